@@ -34,6 +34,7 @@ const (
 	HostApplicationConfigKey       = "host-application-config"
 	CPUNormalizationConfigKey      = "cpu-normalization-config"
 	ResourceAmplificationConfigKey = "resource-amplification-config"
+	CgroupExclusionConfigKey       = "cgroup-exclusion-config"
 )
 
 // +k8s:deepcopy-gen=true
@@ -104,6 +105,18 @@ type HostApplicationCfg struct {
 	NodeConfigs  []NodeHostApplicationCfg          `json:"nodeConfigs,omitempty"`
 }
 
+// +k8s:deepcopy-gen=true
+type NodeCgroupExclusionCfg struct {
+	NodeCfgProfile `json:",inline"`
+	Rules          []slov1alpha1.CgroupExclusionRule `json:"rules,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type CgroupExclusionCfg struct {
+	Rules       []slov1alpha1.CgroupExclusionRule `json:"rules,omitempty"`
+	NodeConfigs []NodeCgroupExclusionCfg          `json:"nodeConfigs,omitempty"`
+}
+
 // +k8s:deepcopy-gen=true
 type ResourceQOSCfg struct {
 	ClusterStrategy *slov1alpha1.ResourceQOSStrategy `json:"clusterStrategy,omitempty"`
@@ -255,6 +268,15 @@ type ColocationStrategy struct {
 	// Allocatable[Mid]' := min(Reclaimable[Mid], NodeAllocatable * thresholdRatio) + Unallocated[Mid] * midUnallocatedRatio.
 	MidUnallocatedPercent *int64 `json:"midUnallocatedPercent,omitempty" validate:"omitempty,min=0,max=100"`
 
+	// ShortLivedPodThresholdSeconds defines the lifetime under which the high-priority pods are regarded as short-lived
+	// in the batch resource calculation. The lifetime of a pod is estimated from the finished pods of the same workload.
+	// The short-lived pods finish before the batch allocatable gets updated, so accounting them fully makes the batch
+	// allocatable oscillate. It is disabled if not set or set to 0.
+	ShortLivedPodThresholdSeconds *int64 `json:"shortLivedPodThresholdSeconds,omitempty" validate:"omitempty,min=0"`
+	// ShortLivedPodAccountingPercent defines the percentage of the requests and usages of the short-lived pods counted
+	// in the batch resource calculation. 0 (default) means excluding them, while 100 means no dampening.
+	ShortLivedPodAccountingPercent *int64 `json:"shortLivedPodAccountingPercent,omitempty" validate:"omitempty,min=0,max=100"`
+
 	ColocationStrategyExtender `json:",inline"` // for third-party extension
 }
 
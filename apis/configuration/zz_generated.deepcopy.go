@@ -108,6 +108,35 @@ func (in *CPUNormalizationStrategy) DeepCopy() *CPUNormalizationStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CgroupExclusionCfg) DeepCopyInto(out *CgroupExclusionCfg) {
+	*out = *in
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]v1alpha1.CgroupExclusionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeConfigs != nil {
+		in, out := &in.NodeConfigs, &out.NodeConfigs
+		*out = make([]NodeCgroupExclusionCfg, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CgroupExclusionCfg.
+func (in *CgroupExclusionCfg) DeepCopy() *CgroupExclusionCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(CgroupExclusionCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ColocationCfg) DeepCopyInto(out *ColocationCfg) {
 	*out = *in
@@ -209,6 +238,16 @@ func (in *ColocationStrategy) DeepCopyInto(out *ColocationStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.ShortLivedPodThresholdSeconds != nil {
+		in, out := &in.ShortLivedPodThresholdSeconds, &out.ShortLivedPodThresholdSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ShortLivedPodAccountingPercent != nil {
+		in, out := &in.ShortLivedPodAccountingPercent, &out.ShortLivedPodAccountingPercent
+		*out = new(int64)
+		**out = **in
+	}
 	in.ColocationStrategyExtender.DeepCopyInto(&out.ColocationStrategyExtender)
 }
 
@@ -382,6 +421,29 @@ func (in *NodeCfgProfile) DeepCopy() *NodeCfgProfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCgroupExclusionCfg) DeepCopyInto(out *NodeCgroupExclusionCfg) {
+	*out = *in
+	in.NodeCfgProfile.DeepCopyInto(&out.NodeCfgProfile)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]v1alpha1.CgroupExclusionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCgroupExclusionCfg.
+func (in *NodeCgroupExclusionCfg) DeepCopy() *NodeCgroupExclusionCfg {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCgroupExclusionCfg)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeColocationCfg) DeepCopyInto(out *NodeColocationCfg) {
 	*out = *in
@@ -22,6 +22,9 @@ import (
 
 const (
 	AnnotationSkipUpdateResource = "config.koordinator.sh/skip-update-resources"
+	// AnnotationApplyCPUAllocationHint indicates the LS pods matched the profile are mutated by the CPU allocation
+	// hints of their workloads when they are created.
+	AnnotationApplyCPUAllocationHint = "config.koordinator.sh/apply-cpu-allocation-hint"
 )
 
 func ShouldSkipUpdateResource(profile *configv1alpha1.ClusterColocationProfile) bool {
@@ -31,3 +34,10 @@ func ShouldSkipUpdateResource(profile *configv1alpha1.ClusterColocationProfile)
 	_, ok := profile.Annotations[AnnotationSkipUpdateResource]
 	return ok
 }
+
+func ShouldApplyCPUAllocationHint(profile *configv1alpha1.ClusterColocationProfile) bool {
+	if profile == nil || profile.Annotations == nil {
+		return false
+	}
+	return profile.Annotations[AnnotationApplyCPUAllocationHint] == "true"
+}
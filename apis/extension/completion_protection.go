@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// AnnotationExpectedCompletionTime indicates the time in RFC3339 format when the pod is expected to run to completion.
+	AnnotationExpectedCompletionTime = DomainPrefix + "expected-completion-time"
+	// AnnotationCompletionProtectionWindow indicates the duration (e.g. "10m") before the expected completion time
+	// during which the pod is protected. It overrides the node-level default window in the NodeSLO.
+	AnnotationCompletionProtectionWindow = DomainPrefix + "completion-protection-window"
+
+	// PodConditionNearCompletion indicates the workload reports the pod is close to completion (e.g. progress > 90%).
+	// A pod with the condition status True is protected regardless of the protection window.
+	PodConditionNearCompletion corev1.PodConditionType = DomainPrefix + "NearCompletion"
+)
+
+// GetExpectedCompletionTime parses the expected completion time of the pod. It returns nil if not set.
+func GetExpectedCompletionTime(annotations map[string]string) (*time.Time, error) {
+	value, ok := annotations[AnnotationExpectedCompletionTime]
+	if !ok {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expected completion time %q, err: %w", value, err)
+	}
+	return &t, nil
+}
+
+// GetCompletionProtectionWindow parses the completion protection window of the pod. It returns nil if not set.
+func GetCompletionProtectionWindow(annotations map[string]string) (*time.Duration, error) {
+	value, ok := annotations[AnnotationCompletionProtectionWindow]
+	if !ok {
+		return nil, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid completion protection window %q, err: %w", value, err)
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("invalid completion protection window %q, must be non-negative", value)
+	}
+	return &d, nil
+}
+
+// IsPodCompletionProtected checks if the pod is inside its run-to-completion protection window, which means
+// the pod reports the NearCompletion condition, or the current time is within the window before the expected
+// completion time. The protection also lasts for one more window after the expected completion time to tolerate
+// a slight overrun, and then expires. The defaultWindow is used when the pod does not declare a window of its own.
+func IsPodCompletionProtected(pod *corev1.Pod, defaultWindow time.Duration, now time.Time) bool {
+	if pod == nil {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == PodConditionNearCompletion && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	completionTime, err := GetExpectedCompletionTime(pod.Annotations)
+	if err != nil || completionTime == nil {
+		return false
+	}
+	window := defaultWindow
+	if w, err := GetCompletionProtectionWindow(pod.Annotations); err == nil && w != nil {
+		window = *w
+	}
+	if window <= 0 {
+		return false
+	}
+	return !now.Before(completionTime.Add(-window)) && now.Before(completionTime.Add(window))
+}
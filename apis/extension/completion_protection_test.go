@@ -0,0 +1,164 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsPodCompletionProtected(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	completionIn := func(d time.Duration) string {
+		return now.Add(d).Format(time.RFC3339)
+	}
+	tests := []struct {
+		name          string
+		pod           *corev1.Pod
+		defaultWindow time.Duration
+		want          bool
+	}{
+		{
+			name: "nil pod",
+			pod:  nil,
+			want: false,
+		},
+		{
+			name:          "no annotation",
+			pod:           &corev1.Pod{},
+			defaultWindow: 10 * time.Minute,
+			want:          false,
+		},
+		{
+			name: "near completion condition",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: PodConditionNearCompletion, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "near completion condition is false",
+			pod: &corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: PodConditionNearCompletion, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "inside default window",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime: completionIn(5 * time.Minute),
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          true,
+		},
+		{
+			name: "default window disabled",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime: completionIn(5 * time.Minute),
+					},
+				},
+			},
+			want: false,
+		},
+		{
+			name: "outside pod window",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime:     completionIn(5 * time.Minute),
+						AnnotationCompletionProtectionWindow: "1m",
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          false,
+		},
+		{
+			name: "slightly overrun",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime: completionIn(-5 * time.Minute),
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          true,
+		},
+		{
+			name: "protection expired",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime: completionIn(-time.Hour),
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          false,
+		},
+		{
+			name: "invalid completion time",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime: "invalid",
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          false,
+		},
+		{
+			name: "invalid window falls back to default",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						AnnotationExpectedCompletionTime:     completionIn(5 * time.Minute),
+						AnnotationCompletionProtectionWindow: "-1m",
+					},
+				},
+			},
+			defaultWindow: 10 * time.Minute,
+			want:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IsPodCompletionProtected(tt.pod, tt.defaultWindow, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationCPUAllocationHint represents the CPU allocation policy recommended from the historical usages of the
+	// workload, which is annotated on the workload (e.g. Deployment) by koord-manager.
+	AnnotationCPUAllocationHint = SchedulingDomainPrefix + "/cpu-allocation-hint"
+	// AnnotationCPUAllocationHintOriginalCPURequest represents the cpu request of the pod before it is mutated by the
+	// CPU allocation hint, so the usages of the mutated pods are still learned against the request of the workload.
+	AnnotationCPUAllocationHintOriginalCPURequest = SchedulingDomainPrefix + "/cpu-allocation-hint-original-cpu-request"
+)
+
+// CPUAllocationHint is the recommended CPU allocation policy of a workload.
+type CPUAllocationHint struct {
+	// QoSClass is the recommended QoS class, which is LSR or LS.
+	QoSClass QoSClass `json:"qosClass"`
+	// CPUs is the recommended number of the exclusive cpus of each pod when the QoSClass is LSR.
+	CPUs int64 `json:"cpus,omitempty"`
+}
+
+func SetCPUAllocationHint(obj metav1.Object, hint *CPUAllocationHint) error {
+	if hint == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(hint)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationCPUAllocationHint] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// GetCPUAllocationHintOriginalCPURequest returns the cpu request of the pod before the mutation by the hint.
+// It returns false if the pod is not mutated or the value is invalid.
+func GetCPUAllocationHintOriginalCPURequest(annotations map[string]string) (resource.Quantity, bool) {
+	val, ok := annotations[AnnotationCPUAllocationHintOriginalCPURequest]
+	if !ok {
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(val)
+	if err != nil {
+		return resource.Quantity{}, false
+	}
+	return q, true
+}
+
+func GetCPUAllocationHint(annotations map[string]string) (*CPUAllocationHint, error) {
+	val, ok := annotations[AnnotationCPUAllocationHint]
+	if !ok {
+		return nil, nil
+	}
+	var hint CPUAllocationHint
+	err := json.Unmarshal([]byte(val), &hint)
+	if err != nil {
+		return nil, err
+	}
+	return &hint, nil
+}
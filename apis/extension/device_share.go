@@ -53,6 +53,25 @@ const (
 	ResourceGPUMemoryRatio corev1.ResourceName = DomainPrefix + "gpu-memory-ratio"
 )
 
+// The GPU profiling metrics collected from the DCGM and reported in the device usages of the NodeMetric.
+const (
+	// ResourceGPUSMActive is the percentage of the time that at least one warp is active on the SMs.
+	ResourceGPUSMActive corev1.ResourceName = DomainPrefix + "gpu-sm-active"
+	// ResourceGPUMemoryBandwidthRatio is the percentage of the time that the device memory is sending or receiving data.
+	ResourceGPUMemoryBandwidthRatio corev1.ResourceName = DomainPrefix + "gpu-memory-bandwidth-ratio"
+	// ResourceGPUNVLinkTransmit is the bytes per second transmitted through the NVLink.
+	ResourceGPUNVLinkTransmit corev1.ResourceName = DomainPrefix + "gpu-nvlink-transmit"
+	// ResourceGPUNVLinkReceive is the bytes per second received through the NVLink.
+	ResourceGPUNVLinkReceive corev1.ResourceName = DomainPrefix + "gpu-nvlink-receive"
+	// ResourceRDMATransmit is the bytes per second transmitted through the RDMA device.
+	ResourceRDMATransmit corev1.ResourceName = DomainPrefix + "rdma-transmit"
+	// ResourceRDMAReceive is the bytes per second received through the RDMA device.
+	ResourceRDMAReceive corev1.ResourceName = DomainPrefix + "rdma-receive"
+
+	// LabelGPUXidError is the last Xid error code of the GPU, which is labeled on the device usage if any.
+	LabelGPUXidError = DomainPrefix + "gpu-xid-error"
+)
+
 const (
 	LabelGPUPartitionPolicy         string = NodeDomainPrefix + "/gpu-partition-policy"
 	LabelGPUModel                   string = NodeDomainPrefix + "/gpu-model"
@@ -148,6 +167,11 @@ type DeviceHint struct {
 	RequiredTopologyScope DeviceTopologyScope `json:"requiredTopologyScope,omitempty"`
 	// ExclusivePolicy indicates the exclusive policy.
 	ExclusivePolicy DeviceExclusivePolicy `json:"exclusivePolicy,omitempty"`
+	// Minors pins the allocation to the devices with the specified minor numbers.
+	// It is usually used by Reservations to reserve the exact devices.
+	Minors []int32 `json:"minors,omitempty"`
+	// NUMANodes pins the allocation to the devices located on the specified NUMA nodes.
+	NUMANodes []int32 `json:"numaNodes,omitempty"`
 }
 
 type DeviceAllocateStrategy string
@@ -226,6 +250,8 @@ type GPUIsolationProvider string
 
 const (
 	GPUIsolationProviderHAMICore GPUIsolationProvider = "HAMi-core"
+	// GPUIsolationProviderMPS indicates the gpu-core share of the pod is enforced by the NVIDIA MPS.
+	GPUIsolationProviderMPS GPUIsolationProvider = "MPS"
 )
 
 func GetDeviceAllocations(podAnnotations map[string]string) (DeviceAllocations, error) {
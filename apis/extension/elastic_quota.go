@@ -19,8 +19,10 @@ package extension
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	v1 "k8s.io/apiserver/pkg/quota/v1"
 
 	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
@@ -56,8 +58,24 @@ const (
 	AnnotationNonPreemptibleUsed         = QuotaKoordinatorPrefix + "/non-preemptible-used"
 	AnnotationAdmission                  = QuotaKoordinatorPrefix + "/admission"
 	AnnotationMaxStrictCheckResourceKeys = QuotaKoordinatorPrefix + "/max-strict-check-resource-keys"
+	AnnotationWaitStatus                 = QuotaKoordinatorPrefix + "/wait-status"
+	AnnotationPreemptionImmuneUntil      = QuotaKoordinatorPrefix + "/preemption-immune-until"
 )
 
+// QuotaWaitStatus describes the pending pods of the quota observed by the scheduler.
+// The gated pods are rejected due to the quota limits, e.g. the quota is misconfigured, while the
+// unschedulable pods are admitted by the quota but fail to find a node, e.g. the cluster is full.
+type QuotaWaitStatus struct {
+	// GatedPods is the number of the pods rejected due to the quota limits.
+	GatedPods int `json:"gatedPods,omitempty"`
+	// OldestGatedTime is the time when the longest gated pod was firstly rejected due to the quota limits.
+	OldestGatedTime *metav1.Time `json:"oldestGatedTime,omitempty"`
+	// UnschedulablePods is the number of the pods admitted by the quota but failing to be scheduled.
+	UnschedulablePods int `json:"unschedulablePods,omitempty"`
+	// OldestUnschedulableTime is the time when the longest unschedulable pod firstly failed to be scheduled.
+	OldestUnschedulableTime *metav1.Time `json:"oldestUnschedulableTime,omitempty"`
+}
+
 func GetParentQuotaName(quota *v1alpha1.ElasticQuota) string {
 	parentName := quota.Labels[LabelQuotaParent]
 	if parentName == "" && quota.Name != RootQuotaName {
@@ -230,3 +248,41 @@ func GetMaxStrictCheckResourceKeys(quota *v1alpha1.ElasticQuota) ([]corev1.Resou
 	}
 	return resources, nil
 }
+
+func GetQuotaWaitStatus(quota *v1alpha1.ElasticQuota) (*QuotaWaitStatus, error) {
+	waitStatus := &QuotaWaitStatus{}
+	if quota.Annotations[AnnotationWaitStatus] != "" {
+		if err := json.Unmarshal([]byte(quota.Annotations[AnnotationWaitStatus]), waitStatus); err != nil {
+			return nil, err
+		}
+	}
+	return waitStatus, nil
+}
+
+// GetPodPreemptionImmuneUntil returns the time until which the pod admitted with the borrowed quota cannot be
+// preempted or revoked.
+func GetPodPreemptionImmuneUntil(pod *corev1.Pod) (time.Time, bool) {
+	value := pod.Annotations[AnnotationPreemptionImmuneUntil]
+	if value == "" {
+		return time.Time{}, false
+	}
+	until, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+func SetPodPreemptionImmuneUntil(obj metav1.Object, until time.Time) {
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationPreemptionImmuneUntil] = until.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+func IsPodPreemptionImmune(pod *corev1.Pod, now time.Time) bool {
+	until, ok := GetPodPreemptionImmuneUntil(pod)
+	return ok && now.Before(until)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationGPUJobProfile represents the GPU utilization profile predicted from the historical usages of the
+	// owner workload, which is annotated by koord-manager.
+	AnnotationGPUJobProfile = SchedulingDomainPrefix + "/gpu-job-profile"
+)
+
+type GPUJobProfileType string
+
+const (
+	// GPUJobProfileComputeBound indicates the job mainly consumes the GPU compute rather than the GPU memory.
+	GPUJobProfileComputeBound GPUJobProfileType = "ComputeBound"
+	// GPUJobProfileMemoryBound indicates the job mainly consumes the GPU memory rather than the GPU compute.
+	GPUJobProfileMemoryBound GPUJobProfileType = "MemoryBound"
+	// GPUJobProfileBalanced indicates the job consumes the GPU compute and memory evenly.
+	GPUJobProfileBalanced GPUJobProfileType = "Balanced"
+)
+
+// GPUJobProfile is the predicted GPU utilization shape of a job.
+type GPUJobProfile struct {
+	Type GPUJobProfileType `json:"type"`
+	// GPUCore is the predicted GPU core utilization in percentage of the requested GPU core.
+	GPUCore int64 `json:"gpuCore,omitempty"`
+	// GPUMemoryRatio is the predicted GPU memory utilization in percentage of the requested GPU memory.
+	GPUMemoryRatio int64 `json:"gpuMemoryRatio,omitempty"`
+}
+
+// IsComplementaryTo checks if the jobs of the two profiles are complementary to share GPUs, i.e. one is
+// compute-bound and the other is memory-bound.
+func (p *GPUJobProfile) IsComplementaryTo(other *GPUJobProfile) bool {
+	if p == nil || other == nil {
+		return false
+	}
+	return (p.Type == GPUJobProfileComputeBound && other.Type == GPUJobProfileMemoryBound) ||
+		(p.Type == GPUJobProfileMemoryBound && other.Type == GPUJobProfileComputeBound)
+}
+
+func SetGPUJobProfile(obj metav1.Object, profile *GPUJobProfile) error {
+	if profile == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationGPUJobProfile] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+func GetGPUJobProfile(annotations map[string]string) (*GPUJobProfile, error) {
+	val, ok := annotations[AnnotationGPUJobProfile]
+	if !ok {
+		return nil, nil
+	}
+	var profile GPUJobProfile
+	err := json.Unmarshal([]byte(val), &profile)
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGPUJobProfile(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-pod",
+		},
+	}
+	got, err := GetGPUJobProfile(pod.Annotations)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	profile := &GPUJobProfile{
+		Type:           GPUJobProfileComputeBound,
+		GPUCore:        80,
+		GPUMemoryRatio: 30,
+	}
+	assert.NoError(t, SetGPUJobProfile(pod, nil))
+	assert.Nil(t, pod.Annotations)
+	assert.NoError(t, SetGPUJobProfile(pod, profile))
+	assert.Equal(t, `{"type":"ComputeBound","gpuCore":80,"gpuMemoryRatio":30}`, pod.Annotations[AnnotationGPUJobProfile])
+	got, err = GetGPUJobProfile(pod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, profile, got)
+
+	_, err = GetGPUJobProfile(map[string]string{AnnotationGPUJobProfile: "invalid"})
+	assert.Error(t, err)
+
+	memoryBound := &GPUJobProfile{Type: GPUJobProfileMemoryBound}
+	balanced := &GPUJobProfile{Type: GPUJobProfileBalanced}
+	assert.True(t, profile.IsComplementaryTo(memoryBound))
+	assert.True(t, memoryBound.IsComplementaryTo(profile))
+	assert.False(t, profile.IsComplementaryTo(profile))
+	assert.False(t, profile.IsComplementaryTo(balanced))
+	assert.False(t, profile.IsComplementaryTo(nil))
+}
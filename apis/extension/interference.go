@@ -0,0 +1,44 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+const (
+	// AnnotationInterferenceSensitivity indicates how sensitive the pod is to the interference of the colocated
+	// best-effort pods. The value is one of High, Medium and Low. The pods without the annotation are considered as
+	// insensitive.
+	AnnotationInterferenceSensitivity = SchedulingDomainPrefix + "/interference-sensitivity"
+)
+
+type InterferenceSensitivity string
+
+const (
+	InterferenceSensitivityHigh   InterferenceSensitivity = "High"
+	InterferenceSensitivityMedium InterferenceSensitivity = "Medium"
+	InterferenceSensitivityLow    InterferenceSensitivity = "Low"
+	InterferenceSensitivityNone   InterferenceSensitivity = ""
+)
+
+// GetInterferenceSensitivity returns the interference sensitivity of the pod, or InterferenceSensitivityNone if the
+// annotation is missing or invalid.
+func GetInterferenceSensitivity(annotations map[string]string) InterferenceSensitivity {
+	s := InterferenceSensitivity(annotations[AnnotationInterferenceSensitivity])
+	switch s {
+	case InterferenceSensitivityHigh, InterferenceSensitivityMedium, InterferenceSensitivityLow:
+		return s
+	}
+	return InterferenceSensitivityNone
+}
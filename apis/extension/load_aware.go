@@ -18,8 +18,10 @@ package extension
 
 import (
 	"encoding/json"
+	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,6 +29,19 @@ const (
 	// AnnotationCustomUsageThresholds represents the user-defined resource utilization threshold.
 	// For specific value definitions, see CustomUsageThresholds
 	AnnotationCustomUsageThresholds = SchedulingDomainPrefix + "/usage-thresholds"
+
+	// AnnotationMemoryBandwidthRequest represents the memory bandwidth the pod is expected to consume in bytes per
+	// second, e.g. "2Gi". The LoadAwareScheduling avoids placing the pod onto the nodes near the bandwidth saturation.
+	AnnotationMemoryBandwidthRequest = SchedulingDomainPrefix + "/memory-bandwidth-request"
+
+	// NodeConditionCPUThermalThrottled indicates whether the cpus of the node are thermally throttled.
+	// It is reported by the koordlet when the CPUThermalCollector is enabled.
+	NodeConditionCPUThermalThrottled corev1.NodeConditionType = "CPUThermalThrottled"
+
+	// NodeConditionColocationReady indicates whether the QoS enforcement of the colocation works on the node, i.e. the
+	// cgroup driver, the resctrl, the runtime hooks and the metric collection of the koordlet are all healthy.
+	// It is reported by the koordlet when the ColocationReadyCondition is enabled.
+	NodeConditionColocationReady corev1.NodeConditionType = "ColocationReady"
 )
 
 // CustomUsageThresholds supports user-defined node resource utilization thresholds.
@@ -60,3 +75,50 @@ func GetCustomUsageThresholds(node *corev1.Node) (*CustomUsageThresholds, error)
 	}
 	return usageThresholds, nil
 }
+
+// GetPodMemoryBandwidthRequest returns the memory bandwidth request of the pod in bytes per second, and it returns 0 if
+// the pod does not declare it.
+func GetPodMemoryBandwidthRequest(pod *corev1.Pod) (int64, error) {
+	if pod == nil {
+		return 0, nil
+	}
+	data, ok := pod.Annotations[AnnotationMemoryBandwidthRequest]
+	if !ok {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(data)
+	if err != nil {
+		return 0, err
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("negative memory bandwidth request %s", data)
+	}
+	return q.Value(), nil
+}
+
+// IsNodeCPUThermalThrottled returns whether the node reports the cpus are thermally throttled.
+func IsNodeCPUThermalThrottled(node *corev1.Node) bool {
+	if node == nil {
+		return false
+	}
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == NodeConditionCPUThermalThrottled {
+			return node.Status.Conditions[i].Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// IsNodeColocationNotReady returns whether the koordlet reports the QoS enforcement of the colocation is broken.
+// The node without the condition is considered as ready.
+func IsNodeColocationNotReady(node *corev1.Node) bool {
+	if node == nil {
+		return false
+	}
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == NodeConditionColocationReady {
+			return node.Status.Conditions[i].Status == corev1.ConditionFalse
+		}
+	}
+	return false
+}
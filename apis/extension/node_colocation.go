@@ -30,4 +30,24 @@ const (
 	// It takes precedence to the MemoryReclaimThresholdPercent in the slo-controller-config and the node annotations.
 	// The illegal value will be ignored.
 	LabelMemoryReclaimRatio = NodeDomainPrefix + "/memory-reclaim-ratio"
+
+	// LabelNodeColocationPool denotes the colocation pool of a node. The nodes of the same value are aggregated into
+	// the ColocationStatus of the pool besides the one of the cluster.
+	LabelNodeColocationPool = NodeDomainPrefix + "/colocation-pool"
+
+	// LabelNodeWorkloadMix denotes the workload mix of a node classified by the observed prod and batch workloads.
+	// The value is the NodeWorkloadMix. The NodeSLO templates and the scheduler pools can select the nodes by it.
+	LabelNodeWorkloadMix = NodeDomainPrefix + "/workload-mix"
+)
+
+// NodeWorkloadMix is the colocation profile of a node classified by the workload mix.
+type NodeWorkloadMix string
+
+const (
+	// NodeWorkloadMixProdOnly denotes the node mostly runs the prod workloads.
+	NodeWorkloadMixProdOnly NodeWorkloadMix = "prod-only"
+	// NodeWorkloadMixBalanced denotes the node runs both the prod and the batch workloads.
+	NodeWorkloadMixBalanced NodeWorkloadMix = "balanced"
+	// NodeWorkloadMixBatchHeavy denotes the node mostly runs the batch workloads.
+	NodeWorkloadMixBatchHeavy NodeWorkloadMix = "batch-heavy"
 )
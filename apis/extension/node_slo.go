@@ -0,0 +1,46 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+const (
+	// LabelNodeSLOSource marks a NodeSLO as a source composed into the NodeSLOs of the nodes by koordlet, instead of
+	// the one managed by slo-controller for a node. The value is one of the NodeSLOSource.
+	LabelNodeSLOSource = NodeDomainPrefix + "/slo-source"
+	// LabelNodeSLOPool specifies the pool of a node, and the pool which a NodeSLO source of NodeSLOSourcePool applies to.
+	LabelNodeSLOPool = NodeDomainPrefix + "/slo-pool"
+	// LabelNodeSLONodeName specifies the node which a NodeSLO source of NodeSLOSourceNode applies to.
+	LabelNodeSLONodeName = NodeDomainPrefix + "/slo-node-name"
+)
+
+// NodeSLOSource is the source type of the NodeSLO. The sources are composed in the order of precedence from low to
+// high: the NodeSLO managed by slo-controller < cluster < pool < node.
+type NodeSLOSource string
+
+const (
+	// NodeSLOSourceCluster applies to all nodes.
+	NodeSLOSourceCluster NodeSLOSource = "cluster"
+	// NodeSLOSourcePool applies to the nodes whose LabelNodeSLOPool equals to the one of the NodeSLO.
+	NodeSLOSourcePool NodeSLOSource = "pool"
+	// NodeSLOSourceNode applies to the node specified by the LabelNodeSLONodeName of the NodeSLO.
+	NodeSLOSourceNode NodeSLOSource = "node"
+)
+
+// IsNodeSLOSource returns whether the NodeSLO is a source composed by koordlet rather than the NodeSLO of a node.
+func IsNodeSLOSource(labels map[string]string) bool {
+	_, ok := labels[LabelNodeSLOSource]
+	return ok
+}
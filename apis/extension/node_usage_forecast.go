@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AnnotationNodeUsageForecast represents the node usage forecasted from the recent NodeMetrics, which is
+	// annotated on the NodeMetric by koord-manager.
+	AnnotationNodeUsageForecast = NodeDomainPrefix + "/usage-forecast"
+)
+
+// NodeUsageForecast is the usage of a node forecasted in a short horizon.
+type NodeUsageForecast struct {
+	// ForecastTime is the time when the usages are forecasted to reach.
+	ForecastTime metav1.Time `json:"forecastTime"`
+	// NodeUsage is the forecasted usage of the node.
+	NodeUsage corev1.ResourceList `json:"nodeUsage,omitempty"`
+	// ProdUsage is the forecasted usage of the Prod pods on the node.
+	ProdUsage corev1.ResourceList `json:"prodUsage,omitempty"`
+}
+
+func SetNodeUsageForecast(obj metav1.Object, forecast *NodeUsageForecast) error {
+	if forecast == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationNodeUsageForecast] = string(data)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+func GetNodeUsageForecast(annotations map[string]string) (*NodeUsageForecast, error) {
+	val, ok := annotations[AnnotationNodeUsageForecast]
+	if !ok {
+		return nil, nil
+	}
+	var forecast NodeUsageForecast
+	err := json.Unmarshal([]byte(val), &forecast)
+	if err != nil {
+		return nil, err
+	}
+	return &forecast, nil
+}
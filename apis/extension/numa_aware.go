@@ -49,6 +49,9 @@ const (
 	// AnnotationNodeBECPUSharedPools describes the CPU Shared Pool defined by Koordinator.
 	// The shared pool is mainly used by Koordinator BE Pods or K8s Besteffort Pods.
 	AnnotationNodeBECPUSharedPools = NodeDomainPrefix + "/be-cpu-shared-pools"
+	// AnnotationNodeCPUSharedSubPools describes the sub-pools partitioned from the CPU Shared Pool of the LS Pods.
+	// The LS Pods are assigned to the sub-pools by the koordinator priority.
+	AnnotationNodeCPUSharedSubPools = NodeDomainPrefix + "/cpu-shared-sub-pools"
 
 	// LabelNodeCPUBindPolicy constrains how to bind CPU logical CPUs when scheduling.
 	LabelNodeCPUBindPolicy = NodeDomainPrefix + "/cpu-bind-policy"
@@ -86,6 +89,9 @@ type ResourceStatus struct {
 	CPUSet string `json:"cpuset,omitempty"`
 	// NUMANodeResources indicates that the Pod is constrained to run on the specified NUMA Node.
 	NUMANodeResources []NUMANodeResource `json:"numaNodeResources,omitempty"`
+	// CPUSharedSubPool is the name of the CPU shared sub-pool which the LS Pod runs in.
+	// When the node partitions the CPU Shared Pool into sub-pools, koord-scheduler will update the field.
+	CPUSharedSubPool string `json:"cpuSharedSubPool,omitempty"`
 }
 
 type NUMANodeResource struct {
@@ -208,6 +214,20 @@ type CPUSharedPool struct {
 	CPUSet string `json:"cpuset,omitempty"`
 }
 
+// CPUSharedSubPool is a partition of the CPU Shared Pool for the LS Pods whose priority values are in the range.
+type CPUSharedSubPool struct {
+	Name        string `json:"name"`
+	MinPriority int32  `json:"minPriority"`
+	MaxPriority int32  `json:"maxPriority"`
+	// Pools are the partitioned CPUs on each NUMA Node.
+	Pools []CPUSharedPool `json:"pools,omitempty"`
+}
+
+// IsPriorityMatched checks if the priority value is in the range of the sub-pool.
+func (p *CPUSharedSubPool) IsPriorityMatched(priority int32) bool {
+	return priority >= p.MinPriority && priority <= p.MaxPriority
+}
+
 type KubeletCPUManagerPolicy struct {
 	Policy       string            `json:"policy,omitempty"`
 	Options      map[string]string `json:"options,omitempty"`
@@ -257,16 +277,11 @@ func SetResourceSpec(obj metav1.Object, spec *ResourceSpec) error {
 
 // GetResourceStatus parses ResourceStatus from annotations
 func GetResourceStatus(annotations map[string]string) (*ResourceStatus, error) {
-	resourceStatus := &ResourceStatus{}
 	data, ok := annotations[AnnotationResourceStatus]
 	if !ok {
-		return resourceStatus, nil
-	}
-	err := json.Unmarshal([]byte(data), resourceStatus)
-	if err != nil {
-		return nil, err
+		return &ResourceStatus{}, nil
 	}
-	return resourceStatus, nil
+	return UnmarshalResourceStatus(data)
 }
 
 func SetResourceStatus(obj metav1.Object, status *ResourceStatus) error {
@@ -325,6 +340,19 @@ func GetNodeCPUSharePools(nodeTopoAnnotations map[string]string) ([]CPUSharedPoo
 	return cpuSharePools, nil
 }
 
+func GetNodeCPUSharedSubPools(nodeTopoAnnotations map[string]string) ([]CPUSharedSubPool, error) {
+	var subPools []CPUSharedSubPool
+	data, ok := nodeTopoAnnotations[AnnotationNodeCPUSharedSubPools]
+	if !ok {
+		return subPools, nil
+	}
+	err := json.Unmarshal([]byte(data), &subPools)
+	if err != nil {
+		return nil, err
+	}
+	return subPools, nil
+}
+
 func GetNodeBECPUSharePools(nodeTopoAnnotations map[string]string) ([]CPUSharedPool, error) {
 	var beCPUSharePools []CPUSharedPool
 	data, ok := nodeTopoAnnotations[AnnotationNodeBECPUSharedPools]
@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The ResourceStatus annotation is JSON formatted by default, whose size grows fast with the scattered CPUs and
+// the NUMA node resources on the large nodes. The compact format is a versioned binary encoding in base64,
+// e.g. `v1:AQ8BAAEBMQ`, which keeps the size steady as the CPUs are encoded into a bitmap.
+// The decoding is backward-compatible with the JSON format.
+const (
+	// ResourceStatusCompactV1Prefix is the prefix of the v1 compact encoded ResourceStatus.
+	ResourceStatusCompactV1Prefix = "v1:"
+)
+
+// the well-known resource names are encoded into the codes to save the space
+var compactResourceNameCodes = map[corev1.ResourceName]uint64{
+	corev1.ResourceCPU:    1,
+	corev1.ResourceMemory: 2,
+}
+
+var compactResourceCodeNames = map[uint64]corev1.ResourceName{
+	1: corev1.ResourceCPU,
+	2: corev1.ResourceMemory,
+}
+
+// MarshalResourceStatusCompact encodes the ResourceStatus in the v1 compact format.
+// The v1 payload is the sequence of:
+// 1. the uvarint length of the CPU bitmap and the bitmap, where the bit i represents the CPU i.
+// 2. the uvarint number of the NUMA nodes, and for each NUMA node, the uvarint node id, the uvarint number of the
+// resources and the resources sorted by name. Each resource is encoded as the uvarint name code, the name if the
+// code is 0, and the quantity string, where the strings are prefixed with the uvarint length.
+// 3. the optional CPU shared sub-pool name prefixed with the uvarint length, which is omitted when it is empty.
+func MarshalResourceStatusCompact(status *ResourceStatus) (string, error) {
+	if status == nil {
+		status = &ResourceStatus{}
+	}
+	cpus, err := parseCPUList(status.CPUSet)
+	if err != nil {
+		return "", err
+	}
+	var bitmap []byte
+	for _, cpu := range cpus {
+		for len(bitmap) <= cpu/8 {
+			bitmap = append(bitmap, 0)
+		}
+		bitmap[cpu/8] |= 1 << (cpu % 8)
+	}
+
+	buf := binary.AppendUvarint(nil, uint64(len(bitmap)))
+	buf = append(buf, bitmap...)
+	buf = binary.AppendUvarint(buf, uint64(len(status.NUMANodeResources)))
+	for _, numaNode := range status.NUMANodeResources {
+		if numaNode.Node < 0 {
+			return "", fmt.Errorf("invalid numa node %d", numaNode.Node)
+		}
+		buf = binary.AppendUvarint(buf, uint64(numaNode.Node))
+		names := make([]string, 0, len(numaNode.Resources))
+		for name := range numaNode.Resources {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		buf = binary.AppendUvarint(buf, uint64(len(names)))
+		for _, name := range names {
+			code := compactResourceNameCodes[corev1.ResourceName(name)]
+			buf = binary.AppendUvarint(buf, code)
+			if code == 0 {
+				buf = appendCompactString(buf, name)
+			}
+			quantity := numaNode.Resources[corev1.ResourceName(name)]
+			buf = appendCompactString(buf, quantity.String())
+		}
+	}
+	if len(status.CPUSharedSubPool) > 0 {
+		buf = appendCompactString(buf, status.CPUSharedSubPool)
+	}
+	return ResourceStatusCompactV1Prefix + base64.RawStdEncoding.EncodeToString(buf), nil
+}
+
+// UnmarshalResourceStatus decodes the ResourceStatus in either the JSON format or the compact format.
+func UnmarshalResourceStatus(data string) (*ResourceStatus, error) {
+	resourceStatus := &ResourceStatus{}
+	if !strings.HasPrefix(data, ResourceStatusCompactV1Prefix) {
+		if err := json.Unmarshal([]byte(data), resourceStatus); err != nil {
+			return nil, err
+		}
+		return resourceStatus, nil
+	}
+
+	buf, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(data, ResourceStatusCompactV1Prefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid compact resource status, err: %w", err)
+	}
+	r := &compactReader{buf: buf}
+	bitmap := r.readBytes()
+	var cpus []int
+	for i, b := range bitmap {
+		for j := 0; j < 8; j++ {
+			if b&(1<<j) != 0 {
+				cpus = append(cpus, i*8+j)
+			}
+		}
+	}
+	resourceStatus.CPUSet = formatCPUList(cpus)
+
+	numaNodes := r.readUvarint()
+	for i := uint64(0); i < numaNodes && r.err == nil; i++ {
+		numaNode := NUMANodeResource{Node: int32(r.readUvarint())}
+		count := r.readUvarint()
+		if count > 0 {
+			numaNode.Resources = corev1.ResourceList{}
+		}
+		for j := uint64(0); j < count && r.err == nil; j++ {
+			code := r.readUvarint()
+			name, ok := compactResourceCodeNames[code]
+			if code == 0 {
+				name = corev1.ResourceName(r.readBytes())
+			} else if !ok {
+				return nil, fmt.Errorf("invalid compact resource status, unknown resource code %d", code)
+			}
+			value := string(r.readBytes())
+			if r.err != nil {
+				break
+			}
+			quantity, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid compact resource status, resource %s, err: %w", name, err)
+			}
+			numaNode.Resources[name] = quantity
+		}
+		resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, numaNode)
+	}
+	if r.err == nil && len(r.buf) > 0 {
+		resourceStatus.CPUSharedSubPool = string(r.readBytes())
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("invalid compact resource status, err: %w", r.err)
+	}
+	return resourceStatus, nil
+}
+
+// SetResourceStatusCompact sets the ResourceStatus annotation in the compact format.
+// NOTE: The components reading the annotation should support the compact format before it is enabled.
+func SetResourceStatusCompact(obj metav1.Object, status *ResourceStatus) error {
+	if obj == nil {
+		return nil
+	}
+	data, err := MarshalResourceStatusCompact(status)
+	if err != nil {
+		return err
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[AnnotationResourceStatus] = data
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// FormatResourceStatus prints the ResourceStatus annotation in any format as the readable JSON, e.g. for debugging.
+func FormatResourceStatus(data string) (string, error) {
+	resourceStatus, err := UnmarshalResourceStatus(data)
+	if err != nil {
+		return "", err
+	}
+	out, err := json.Marshal(resourceStatus)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+func appendCompactString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+type compactReader struct {
+	buf []byte
+	err error
+}
+
+func (r *compactReader) readUvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = fmt.Errorf("malformed uvarint")
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *compactReader) readBytes() []byte {
+	n := r.readUvarint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.err = fmt.Errorf("unexpected end of data")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// parseCPUList parses the Linux CPU list formatted string, e.g. `0-3,8`.
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+	if len(strings.TrimSpace(s)) == 0 {
+		return cpus, nil
+	}
+	for _, r := range strings.Split(s, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(r), "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil || start < 0 {
+			return nil, fmt.Errorf("invalid cpuset %s", s)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid cpuset %s", s)
+			}
+		}
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// formatCPUList formats the sorted CPUs into the Linux CPU list formatted string.
+func formatCPUList(cpus []int) string {
+	var ranges []string
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(cpus[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ",")
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResourceStatusCompactCodec(t *testing.T) {
+	tests := []struct {
+		name   string
+		status *ResourceStatus
+	}{
+		{
+			name:   "empty status",
+			status: &ResourceStatus{},
+		},
+		{
+			name: "cpuset only",
+			status: &ResourceStatus{
+				CPUSet: "0-3,8,10-11",
+			},
+		},
+		{
+			name: "cpuset and numa resources",
+			status: &ResourceStatus{
+				CPUSet: "1,3,64-65",
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 0,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("2"),
+							corev1.ResourceMemory: resource.MustParse("4Gi"),
+						},
+					},
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU:                   resource.MustParse("2"),
+							corev1.ResourceName("hugepages-2Mi"): resource.MustParse("1Gi"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "numa resources and cpu shared sub-pool",
+			status: &ResourceStatus{
+				NUMANodeResources: []NUMANodeResource{
+					{
+						Node: 1,
+						Resources: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+				CPUSharedSubPool: "ls-high",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := MarshalResourceStatusCompact(tt.status)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(data, ResourceStatusCompactV1Prefix))
+
+			pod := &corev1.Pod{}
+			assert.NoError(t, SetResourceStatusCompact(pod, tt.status))
+			assert.Equal(t, data, pod.Annotations[AnnotationResourceStatus])
+			got, err := GetResourceStatus(pod.Annotations)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.status.CPUSet, got.CPUSet)
+			assert.Equal(t, tt.status.CPUSharedSubPool, got.CPUSharedSubPool)
+			assert.Equal(t, len(tt.status.NUMANodeResources), len(got.NUMANodeResources))
+			for i := range tt.status.NUMANodeResources {
+				assert.Equal(t, tt.status.NUMANodeResources[i].Node, got.NUMANodeResources[i].Node)
+				for name, want := range tt.status.NUMANodeResources[i].Resources {
+					gotQuantity := got.NUMANodeResources[i].Resources[name]
+					assert.True(t, want.Equal(gotQuantity), "resource %s, want %s, got %s", name, want.String(), gotQuantity.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGetResourceStatusBackwardCompatible(t *testing.T) {
+	status := &ResourceStatus{
+		CPUSet: "0-3",
+		NUMANodeResources: []NUMANodeResource{
+			{
+				Node:      1,
+				Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{}}
+	assert.NoError(t, SetResourceStatus(pod, status))
+	assert.True(t, strings.HasPrefix(pod.Annotations[AnnotationResourceStatus], "{"))
+	got, err := GetResourceStatus(pod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, status, got)
+
+	got, err = GetResourceStatus(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &ResourceStatus{}, got)
+
+	_, err = GetResourceStatus(map[string]string{AnnotationResourceStatus: ResourceStatusCompactV1Prefix + "!!"})
+	assert.Error(t, err)
+	_, err = GetResourceStatus(map[string]string{AnnotationResourceStatus: ResourceStatusCompactV1Prefix + "Bw"})
+	assert.Error(t, err)
+}
+
+func TestResourceStatusCompactSize(t *testing.T) {
+	var cpus []string
+	for i := 0; i < 256; i += 2 {
+		cpus = append(cpus, strconv.Itoa(i))
+	}
+	status := &ResourceStatus{
+		CPUSet: strings.Join(cpus, ","),
+		NUMANodeResources: []NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("64")}},
+			{Node: 1, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("64")}},
+		},
+	}
+	jsonData, err := json.Marshal(status)
+	assert.NoError(t, err)
+	compactData, err := MarshalResourceStatusCompact(status)
+	assert.NoError(t, err)
+	assert.Less(t, len(compactData)*4, len(jsonData))
+
+	got, err := UnmarshalResourceStatus(compactData)
+	assert.NoError(t, err)
+	assert.Equal(t, status.CPUSet, got.CPUSet)
+}
+
+func TestFormatResourceStatus(t *testing.T) {
+	status := &ResourceStatus{
+		CPUSet: "0-1",
+		NUMANodeResources: []NUMANodeResource{
+			{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+		},
+	}
+	want := `{"cpuset":"0-1","numaNodeResources":[{"node":0,"resources":{"cpu":"2"}}]}`
+	compactData, err := MarshalResourceStatusCompact(status)
+	assert.NoError(t, err)
+	got, err := FormatResourceStatus(compactData)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = FormatResourceStatus(want)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = MarshalResourceStatusCompact(&ResourceStatus{CPUSet: "3-1"})
+	assert.Error(t, err)
+}
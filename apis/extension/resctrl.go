@@ -18,11 +18,17 @@ package extension
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 const (
 	// AnnotationResctrl describes the resctrl config of pod
 	AnnotationResctrl = NodeDomainPrefix + "/resctrl"
+	// AnnotationResctrlMBATier describes the memory bandwidth tier of pod defined in the NodeSLO
+	AnnotationResctrlMBATier = NodeDomainPrefix + "/resctrl-mba-tier"
+	// AnnotationNodeResctrlCapability describes the resctrl capabilities of the node hardware, which is reported
+	// on the NodeResourceTopology by koordlet
+	AnnotationNodeResctrlCapability = NodeDomainPrefix + "/resctrl-capability"
 )
 
 type Resctrl struct {
@@ -67,3 +73,84 @@ func GetResctrlInfo(annotations map[string]string) (*ResctrlConfig, error) {
 	}
 	return res, nil
 }
+
+// GetResctrlMBATier returns the memory bandwidth tier of the pod, or empty if not specified.
+func GetResctrlMBATier(annotations map[string]string) string {
+	return annotations[AnnotationResctrlMBATier]
+}
+
+// ResctrlCapability describes the resctrl capabilities of the node read from the resctrl info, e.g. /sys/fs/resctrl/info.
+// The zero value of a field means the capability is not supported or unknown.
+type ResctrlCapability struct {
+	// NumClosIDs is the number of the control groups (CLOSIDs) that can be created, including the root group.
+	NumClosIDs int64 `json:"numClosIDs,omitempty"`
+	// NumRMIDs is the number of the monitoring groups (RMIDs) that can be created.
+	NumRMIDs int64 `json:"numRMIDs,omitempty"`
+	// L3CacheWays is the number of the L3 cache ways, i.e. the bit length of the cbm_mask.
+	L3CacheWays int64 `json:"l3CacheWays,omitempty"`
+	// L3CDPEnabled indicates if the L3 Code/Data Prioritization is enabled.
+	L3CDPEnabled bool `json:"l3CDPEnabled,omitempty"`
+	// MBAGranularity is the granularity of the memory bandwidth percent, e.g. 10.
+	MBAGranularity int64 `json:"mbaGranularity,omitempty"`
+	// MBAMinBandwidth is the minimum memory bandwidth percent that can be set.
+	MBAMinBandwidth int64 `json:"mbaMinBandwidth,omitempty"`
+}
+
+// GetResctrlCapability returns the resctrl capability on the NodeResourceTopology annotations, or nil if not reported.
+func GetResctrlCapability(annotations map[string]string) (*ResctrlCapability, error) {
+	data, ok := annotations[AnnotationNodeResctrlCapability]
+	if !ok {
+		return nil, nil
+	}
+	capability := &ResctrlCapability{}
+	if err := json.Unmarshal([]byte(data), capability); err != nil {
+		return nil, err
+	}
+	return capability, nil
+}
+
+// ValidateResctrlConfig checks if the resctrl config of a pod can be satisfied by the capability, e.g. the LLC
+// range should allocate at least one cache way, and the MB percent should be no less than the minimum bandwidth.
+func (c *ResctrlCapability) ValidateResctrlConfig(config *ResctrlConfig) error {
+	if c == nil || config == nil {
+		return nil
+	}
+	if err := c.validateLLCSchemata(config.LLC.Schemata); err != nil {
+		return err
+	}
+	for _, s := range config.LLC.SchemataPerCache {
+		if err := c.validateLLCSchemata(s.SchemataConfig); err != nil {
+			return fmt.Errorf("cache %d: %w", s.CacheID, err)
+		}
+	}
+	if err := c.validateMBSchemata(config.MB.Schemata); err != nil {
+		return err
+	}
+	for _, s := range config.MB.SchemataPerCache {
+		if err := c.validateMBSchemata(s.SchemataConfig); err != nil {
+			return fmt.Errorf("cache %d: %w", s.CacheID, err)
+		}
+	}
+	return nil
+}
+
+func (c *ResctrlCapability) validateLLCSchemata(schemata SchemataConfig) error {
+	if c.L3CacheWays <= 0 || len(schemata.Range) != 2 {
+		return nil
+	}
+	percent := int64(schemata.Range[1] - schemata.Range[0])
+	if percent*c.L3CacheWays/100 < 1 {
+		return fmt.Errorf("LLC range %v is less than one cache way of total %d", schemata.Range, c.L3CacheWays)
+	}
+	return nil
+}
+
+func (c *ResctrlCapability) validateMBSchemata(schemata SchemataConfig) error {
+	if c.MBAMinBandwidth <= 0 || schemata.Percent <= 0 {
+		return nil
+	}
+	if int64(schemata.Percent) < c.MBAMinBandwidth {
+		return fmt.Errorf("MB percent %d is less than the minimum bandwidth %d", schemata.Percent, c.MBAMinBandwidth)
+	}
+	return nil
+}
@@ -186,3 +186,142 @@ func TestGetResctrlInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResctrlCapability(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *ResctrlCapability
+		wantErr     bool
+	}{
+		{
+			name:        "not reported",
+			annotations: map[string]string{},
+			want:        nil,
+		},
+		{
+			name: "parse capability",
+			annotations: map[string]string{
+				AnnotationNodeResctrlCapability: `{"numClosIDs":16,"numRMIDs":224,"l3CacheWays":11,"l3CDPEnabled":true,"mbaGranularity":10,"mbaMinBandwidth":10}`,
+			},
+			want: &ResctrlCapability{
+				NumClosIDs:      16,
+				NumRMIDs:        224,
+				L3CacheWays:     11,
+				L3CDPEnabled:    true,
+				MBAGranularity:  10,
+				MBAMinBandwidth: 10,
+			},
+		},
+		{
+			name: "parse failed",
+			annotations: map[string]string{
+				AnnotationNodeResctrlCapability: `invalid`,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetResctrlCapability(tt.annotations)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetResctrlCapability() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetResctrlCapability() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResctrlCapability_ValidateResctrlConfig(t *testing.T) {
+	capability := &ResctrlCapability{
+		NumClosIDs:      16,
+		L3CacheWays:     10,
+		MBAGranularity:  10,
+		MBAMinBandwidth: 10,
+	}
+	tests := []struct {
+		name       string
+		capability *ResctrlCapability
+		config     *ResctrlConfig
+		wantErr    bool
+	}{
+		{
+			name:       "capability not reported",
+			capability: nil,
+			config: &ResctrlConfig{
+				MB: MB{Schemata: SchemataConfig{Percent: 1}},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "valid config",
+			capability: capability,
+			config: &ResctrlConfig{
+				LLC: LLC{
+					Schemata: SchemataConfig{Range: []int{0, 30}},
+					SchemataPerCache: []SchemataPerCacheConfig{
+						{CacheID: 1, SchemataConfig: SchemataConfig{Range: []int{50, 60}}},
+					},
+				},
+				MB: MB{
+					Schemata: SchemataConfig{Percent: 20},
+					SchemataPerCache: []SchemataPerCacheConfig{
+						{CacheID: 1, SchemataConfig: SchemataConfig{Percent: 10}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:       "LLC range less than one way",
+			capability: capability,
+			config: &ResctrlConfig{
+				LLC: LLC{Schemata: SchemataConfig{Range: []int{0, 5}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "LLC range per cache less than one way",
+			capability: capability,
+			config: &ResctrlConfig{
+				LLC: LLC{
+					SchemataPerCache: []SchemataPerCacheConfig{
+						{CacheID: 1, SchemataConfig: SchemataConfig{Range: []int{50, 55}}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "MB percent less than min bandwidth",
+			capability: capability,
+			config: &ResctrlConfig{
+				MB: MB{Schemata: SchemataConfig{Percent: 5}},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "MB percent per cache less than min bandwidth",
+			capability: capability,
+			config: &ResctrlConfig{
+				MB: MB{
+					SchemataPerCache: []SchemataPerCacheConfig{
+						{CacheID: 0, SchemataConfig: SchemataConfig{Percent: 5}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.capability.ValidateResctrlConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateResctrlConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
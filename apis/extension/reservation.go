@@ -48,6 +48,10 @@ const (
 
 	// AnnotationReservationRestrictedOptions represent the Reservation Restricted options
 	AnnotationReservationRestrictedOptions = SchedulingDomainPrefix + "/reservation-restricted-options"
+
+	// AnnotationReservationImagePrePull indicates whether to pre-pull the images of the reservation template on the
+	// reserved node once the reservation is available, so that the pods allocating the reservation can start quickly.
+	AnnotationReservationImagePrePull = SchedulingDomainPrefix + "/reservation-image-pre-pull"
 )
 
 type ReservationAllocated struct {
@@ -125,6 +129,10 @@ func IsReservationAllocateOnce(r *schedulingv1alpha1.Reservation) bool {
 	return pointer.BoolDeref(r.Spec.AllocateOnce, true)
 }
 
+func IsReservationImagePrePullEnabled(r *schedulingv1alpha1.Reservation) bool {
+	return r != nil && r.Annotations != nil && r.Annotations[AnnotationReservationImagePrePull] == "true"
+}
+
 func GetReservationAffinity(annotations map[string]string) (*ReservationAffinity, error) {
 	s, ok := annotations[AnnotationReservationAffinity]
 	if !ok {
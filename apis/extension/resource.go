@@ -29,6 +29,20 @@ const (
 	MidMemory   corev1.ResourceName = ResourceDomainPrefix + "mid-memory"
 )
 
+const (
+	// ResourceNetworkIngress is the bytes per second received by the pod, which is reported in the NodeMetric.
+	ResourceNetworkIngress corev1.ResourceName = DomainPrefix + "network-ingress"
+	// ResourceNetworkEgress is the bytes per second transmitted by the pod, which is reported in the NodeMetric.
+	ResourceNetworkEgress corev1.ResourceName = DomainPrefix + "network-egress"
+	// ResourceNetworkDrops is the packets per second dropped on the network interfaces of the pod.
+	ResourceNetworkDrops corev1.ResourceName = DomainPrefix + "network-drops"
+	// ResourceNetworkTCPRetransmits is the tcp segments per second retransmitted by the pod.
+	ResourceNetworkTCPRetransmits corev1.ResourceName = DomainPrefix + "network-tcp-retransmits"
+	// ResourceNetworkLatency is the average tcp connect round-trip time in seconds from the pod to the probe endpoint
+	// of its QoS class.
+	ResourceNetworkLatency corev1.ResourceName = DomainPrefix + "network-latency"
+)
+
 const (
 	// AnnotationExtendedResourceSpec specifies the resource requirements of extended resources for internal usage.
 	// It annotates the requests/limits of extended resources and can be used by runtime proxy and koordlet that
@@ -59,6 +73,26 @@ func TranslateResourceNameByPriorityClass(priorityClass PriorityClass, defaultRe
 
 type ExtendedResourceSpec struct {
 	Containers map[string]ExtendedResourceContainerSpec `json:"containers,omitempty"`
+	// InitContainers are the extended resource requirements of the init containers, which run before the containers
+	// one by one, so the pod-level requirement is the max of the sum of containers and any init container.
+	InitContainers map[string]ExtendedResourceContainerSpec `json:"initContainers,omitempty"`
+}
+
+// IsEmpty returns whether the spec specifies no container requirement.
+func (s *ExtendedResourceSpec) IsEmpty() bool {
+	return s == nil || (s.Containers == nil && s.InitContainers == nil)
+}
+
+// GetContainerSpec gets the requirement of the container or the init container with the given name.
+func (s *ExtendedResourceSpec) GetContainerSpec(name string) (ExtendedResourceContainerSpec, bool) {
+	if s == nil {
+		return ExtendedResourceContainerSpec{}, false
+	}
+	if containerSpec, ok := s.Containers[name]; ok {
+		return containerSpec, true
+	}
+	containerSpec, ok := s.InitContainers[name]
+	return containerSpec, ok
 }
 
 type ExtendedResourceContainerSpec struct {
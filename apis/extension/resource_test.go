@@ -174,3 +174,31 @@ func TestSetExtendedResourceSpec(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, testSpec, gotSpec)
 }
+
+func TestExtendedResourceSpecGetContainerSpec(t *testing.T) {
+	var nilSpec *ExtendedResourceSpec
+	assert.True(t, nilSpec.IsEmpty())
+	_, ok := nilSpec.GetContainerSpec("test-container-1")
+	assert.False(t, ok)
+	assert.True(t, (&ExtendedResourceSpec{}).IsEmpty())
+
+	containerSpec := ExtendedResourceContainerSpec{
+		Limits: corev1.ResourceList{BatchCPU: resource.MustParse("500")},
+	}
+	initContainerSpec := ExtendedResourceContainerSpec{
+		Limits: corev1.ResourceList{BatchCPU: resource.MustParse("1000")},
+	}
+	spec := &ExtendedResourceSpec{
+		Containers:     map[string]ExtendedResourceContainerSpec{"test-container-1": containerSpec},
+		InitContainers: map[string]ExtendedResourceContainerSpec{"test-init-container-1": initContainerSpec},
+	}
+	assert.False(t, spec.IsEmpty())
+	got, ok := spec.GetContainerSpec("test-container-1")
+	assert.True(t, ok)
+	assert.Equal(t, containerSpec, got)
+	got, ok = spec.GetContainerSpec("test-init-container-1")
+	assert.True(t, ok)
+	assert.Equal(t, initContainerSpec, got)
+	_, ok = spec.GetContainerSpec("test-container-2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import "encoding/json"
+
+const (
+	// AnnotationNodeRuntimeHookMode denotes the modes of the koordlet runtime hooks on the node, which is reported by
+	// the koordlet. The value is the RuntimeHookModeStatus in json.
+	AnnotationNodeRuntimeHookMode = NodeDomainPrefix + "/runtime-hook-mode"
+)
+
+// RuntimeHookMode is the way the koordlet runtime hooks are invoked.
+type RuntimeHookMode string
+
+const (
+	// RuntimeHookModeNRI denotes the hooks are invoked by the container runtime via the NRI (Node Resource Interface).
+	RuntimeHookModeNRI RuntimeHookMode = "NRI"
+	// RuntimeHookModeProxy denotes the hooks are invoked by the koord-runtime-proxy intercepting the CRI requests.
+	RuntimeHookModeProxy RuntimeHookMode = "Proxy"
+	// RuntimeHookModeStandalone denotes the hooks are only applied by the koordlet reconciler after the containers are
+	// started, which is always available.
+	RuntimeHookModeStandalone RuntimeHookMode = "Standalone"
+)
+
+// RuntimeHookModePrecedence is the precedence chain of the runtime hook modes. The first available mode is active,
+// and the next one takes over when it fails, e.g. the NRI socket is unavailable.
+var RuntimeHookModePrecedence = []RuntimeHookMode{
+	RuntimeHookModeNRI,
+	RuntimeHookModeProxy,
+	RuntimeHookModeStandalone,
+}
+
+// RuntimeHookModeStatus is the status of the runtime hook modes on the node.
+type RuntimeHookModeStatus struct {
+	// Mode is the active mode, which is the first available one in the RuntimeHookModePrecedence.
+	Mode RuntimeHookMode `json:"mode"`
+	// AvailableModes is the available modes in the order of the precedence.
+	AvailableModes []RuntimeHookMode `json:"availableModes,omitempty"`
+}
+
+// GetRuntimeHookModeStatus parses the RuntimeHookModeStatus from the node annotations. It returns nil if it is not
+// reported.
+func GetRuntimeHookModeStatus(annotations map[string]string) (*RuntimeHookModeStatus, error) {
+	data, ok := annotations[AnnotationNodeRuntimeHookMode]
+	if !ok {
+		return nil, nil
+	}
+	status := &RuntimeHookModeStatus{}
+	if err := json.Unmarshal([]byte(data), status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	// AnnotationShmSize specifies the size of the /dev/shm mounted into the containers of the pod, e.g. "16Gi".
+	// It takes precedence over the size derived from the GPU allocation.
+	AnnotationShmSize = DomainPrefix + "shm-size"
+)
+
+// GetShmSize parses the /dev/shm size from the pod annotations. It returns nil if the annotation is not set.
+func GetShmSize(annotations map[string]string) (*resource.Quantity, error) {
+	s, ok := annotations[AnnotationShmSize]
+	if !ok {
+		return nil, nil
+	}
+	size, err := resource.ParseQuantity(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse annotation %s, err: %w", AnnotationShmSize, err)
+	}
+	if size.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid annotation %s, shm size must be positive, got %s", AnnotationShmSize, s)
+	}
+	return &size, nil
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package extension
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// LabelNodeCacheDomain is the node label of the cache domain, which groups the nodes with the same L3 cache
+	// topology, e.g. the cpu model and the number of cpus sharing an L3 cache.
+	// It is published by the koord-manager according to the AnnotationNodeTopologyDomains.
+	LabelNodeCacheDomain = NodeDomainPrefix + "/cache-domain"
+	// LabelNodeNUMADomain is the node label of the NUMA domain, which groups the nodes with the same NUMA topology.
+	// It is published by the koord-manager according to the AnnotationNodeTopologyDomains.
+	LabelNodeNUMADomain = NodeDomainPrefix + "/numa-domain"
+	// AnnotationNodeTopologyDomains is the NodeResourceTopology annotation of the topology domains of the node, which
+	// is reported by the koordlet.
+	AnnotationNodeTopologyDomains = NodeDomainPrefix + "/topology-domains"
+
+	// AnnotationCacheDomainSpread indicates the pod opts in to be spread over the cache domains.
+	// The value is the CacheDomainSpreadSpec in json, and an empty object means using the default spec.
+	AnnotationCacheDomainSpread = SchedulingDomainPrefix + "/cache-domain-spread"
+)
+
+// NodeTopologyDomains is the topology domains of the node. The values are valid label values.
+type NodeTopologyDomains struct {
+	CacheDomain string `json:"cacheDomain,omitempty"`
+	NUMADomain  string `json:"numaDomain,omitempty"`
+}
+
+// GetNodeTopologyDomains returns the topology domains from the annotations, or nil if the annotation is missing.
+func GetNodeTopologyDomains(annotations map[string]string) (*NodeTopologyDomains, error) {
+	data, ok := annotations[AnnotationNodeTopologyDomains]
+	if !ok {
+		return nil, nil
+	}
+	domains := &NodeTopologyDomains{}
+	if err := json.Unmarshal([]byte(data), domains); err != nil {
+		return nil, err
+	}
+	return domains, nil
+}
+
+// CacheDomainSpreadSpec describes the topology spread constraints injected for the pod over the cache domains.
+type CacheDomainSpreadSpec struct {
+	// TopologyKeys are the node labels to spread over. Default is the cache domain.
+	TopologyKeys []string `json:"topologyKeys,omitempty"`
+	// MaxSkew is the max skew of the constraints. Default is 1.
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+	// WhenUnsatisfiable indicates how to deal with the pod if it doesn't satisfy the constraints.
+	// Default is ScheduleAnyway.
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// GetCacheDomainSpreadSpec returns the cache domain spread spec of the pod, or nil if the pod does not opt in.
+func GetCacheDomainSpreadSpec(annotations map[string]string) (*CacheDomainSpreadSpec, error) {
+	data, ok := annotations[AnnotationCacheDomainSpread]
+	if !ok {
+		return nil, nil
+	}
+	spec := &CacheDomainSpreadSpec{}
+	if data != "" {
+		if err := json.Unmarshal([]byte(data), spec); err != nil {
+			return nil, err
+		}
+	}
+	if len(spec.TopologyKeys) == 0 {
+		spec.TopologyKeys = []string{LabelNodeCacheDomain}
+	}
+	if spec.MaxSkew <= 0 {
+		spec.MaxSkew = 1
+	}
+	if spec.WhenUnsatisfiable == "" {
+		spec.WhenUnsatisfiable = corev1.ScheduleAnyway
+	}
+	return spec, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelImagePrePullNodeName is the label of the node which the ImagePrePull targets, used by koordlet to watch
+	// the ImagePrePulls of its own node.
+	LabelImagePrePullNodeName = "scheduling.koordinator.sh/image-pre-pull-node"
+)
+
+type ImagePrePullSpec struct {
+	// NodeName is the node to pull the images.
+	// +kubebuilder:validation:Required
+	NodeName string `json:"nodeName"`
+	// Images are the images to pull.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Images []string `json:"images"`
+	// ReservationRef is the reservation triggering the pre-pull.
+	// +optional
+	ReservationRef *corev1.ObjectReference `json:"reservationRef,omitempty"`
+}
+
+type ImagePrePullPhase string
+
+const (
+	ImagePrePullPending   ImagePrePullPhase = "Pending"
+	ImagePrePullSucceeded ImagePrePullPhase = "Succeeded"
+	ImagePrePullFailed    ImagePrePullPhase = "Failed"
+)
+
+type ImagePrePullStatus struct {
+	// Phase is the phase of the ImagePrePull. It is Succeeded if all images are pulled.
+	// +optional
+	Phase ImagePrePullPhase `json:"phase,omitempty"`
+	// Images are the pulling results of the images.
+	// +optional
+	Images []ImagePullStatus `json:"images,omitempty"`
+	// CompletionTime is the time when the ImagePrePull turns into Succeeded or Failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type ImagePullStatus struct {
+	Image string            `json:"image"`
+	Phase ImagePrePullPhase `json:"phase,omitempty"`
+	// ImageRef is the reference of the pulled image returned by the container runtime.
+	// +optional
+	ImageRef string `json:"imageRef,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of ImagePrePull"
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Reservation",type="string",JSONPath=".spec.reservationRef.name"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ImagePrePull requests koordlet to pull the images on the node in advance, e.g. for the pods to be bound to the
+// reservation, so that the pods can start immediately.
+type ImagePrePull struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ImagePrePullSpec   `json:"spec,omitempty"`
+	Status ImagePrePullStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ImagePrePullList contains a list of ImagePrePull
+type ImagePrePullList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ImagePrePull `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImagePrePull{}, &ImagePrePullList{})
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LabelNodeMaintenanceName is the label of the PodMigrationJobs created by the NodeMaintenance.
+	LabelNodeMaintenanceName = "scheduling.koordinator.sh/node-maintenance"
+)
+
+type NodeMaintenanceSpec struct {
+	// NodeName is the node to maintain. The node is cordoned and all pods except the DaemonSet pods and
+	// the static pods are migrated to other nodes.
+	// +kubebuilder:validation:Required
+	NodeName string `json:"nodeName"`
+
+	// Paused indicates whether to stop creating new PodMigrationJobs for the node.
+	// Default is false
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// MaxConcurrentMigrations is the max number of the pods migrating at the same time.
+	// Default is 1
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentMigrations *int32 `json:"maxConcurrentMigrations,omitempty"`
+
+	// Mode represents the operating mode of the PodMigrationJobs.
+	// Default is PodMigrationJobModeReservationFirst, which reserves the resources on other nodes before evicting.
+	// +optional
+	Mode PodMigrationJobMode `json:"mode,omitempty"`
+
+	// JobTTL controls the timeout duration of each PodMigrationJob.
+	// +optional
+	JobTTL *metav1.Duration `json:"jobTTL,omitempty"`
+
+	// DeleteOptions defines the deleting options for the migrated Pods.
+	// +optional
+	DeleteOptions *metav1.DeleteOptions `json:"deleteOptions,omitempty"`
+}
+
+type NodeMaintenancePhase string
+
+const (
+	// NodeMaintenancePending represents the NodeMaintenance has not been processed.
+	NodeMaintenancePending NodeMaintenancePhase = "Pending"
+	// NodeMaintenanceRunning represents the node is cordoned and the pods are migrating.
+	NodeMaintenanceRunning NodeMaintenancePhase = "Running"
+	// NodeMaintenanceSucceeded represents all the pods are migrated.
+	NodeMaintenanceSucceeded NodeMaintenancePhase = "Succeeded"
+	// NodeMaintenanceFailed represents some pods fail to migrate or the node is not found.
+	NodeMaintenanceFailed NodeMaintenancePhase = "Failed"
+)
+
+type NodeMaintenancePodPhase string
+
+const (
+	// NodeMaintenancePodPending represents the pod is waiting to migrate.
+	NodeMaintenancePodPending NodeMaintenancePodPhase = "Pending"
+	// NodeMaintenancePodBlocked represents the pod cannot migrate now since the PodDisruptionBudget disallows.
+	NodeMaintenancePodBlocked NodeMaintenancePodPhase = "Blocked"
+	// NodeMaintenancePodMigrating represents the PodMigrationJob of the pod is in progress.
+	NodeMaintenancePodMigrating NodeMaintenancePodPhase = "Migrating"
+	// NodeMaintenancePodFailed represents the PodMigrationJob of the pod is failed or aborted.
+	NodeMaintenancePodFailed NodeMaintenancePodPhase = "Failed"
+)
+
+type NodeMaintenanceStatus struct {
+	// Phase is the phase of the NodeMaintenance.
+	// +optional
+	Phase NodeMaintenancePhase `json:"phase,omitempty"`
+	// Message represents a human-readable message indicating details about why the NodeMaintenance is in this state.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// TotalPods is the number of the pods to migrate, including the migrated ones.
+	// +optional
+	TotalPods int32 `json:"totalPods,omitempty"`
+	// MigratedPods is the number of the pods migrated successfully.
+	// +optional
+	MigratedPods int32 `json:"migratedPods,omitempty"`
+	// MigratingPods is the number of the pods migrating.
+	// +optional
+	MigratingPods int32 `json:"migratingPods,omitempty"`
+	// FailedPods is the number of the pods failed to migrate.
+	// +optional
+	FailedPods int32 `json:"failedPods,omitempty"`
+	// Pods are the pods remaining on the node in the migration order.
+	// +optional
+	Pods []NodeMaintenancePodStatus `json:"pods,omitempty"`
+	// StartTime is the time when the node is cordoned.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// CompletionTime is the time when the NodeMaintenance turns into Succeeded or Failed.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type NodeMaintenancePodStatus struct {
+	PodRef *corev1.ObjectReference `json:"podRef"`
+	// +optional
+	Phase NodeMaintenancePodPhase `json:"phase,omitempty"`
+	// JobName is the name of the PodMigrationJob of the pod.
+	// +optional
+	JobName string `json:"jobName,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="The phase of NodeMaintenance"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.totalPods"
+// +kubebuilder:printcolumn:name="Migrated",type="integer",JSONPath=".status.migratedPods"
+// +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failedPods"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NodeMaintenance drains the node for the maintenance. It cordons the node and migrates the pods to other nodes
+// with the PodMigrationJobs in the priority order, respecting the PodDisruptionBudgets.
+type NodeMaintenance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeMaintenanceSpec   `json:"spec,omitempty"`
+	Status NodeMaintenanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NodeMaintenanceList contains a list of NodeMaintenance
+type NodeMaintenanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeMaintenance `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NodeMaintenance{}, &NodeMaintenanceList{})
+}
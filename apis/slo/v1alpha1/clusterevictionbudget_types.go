@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// EvictionBudgetEvictorKoordlet is the evictor name of the evictions initiated by koordlet.
+	EvictionBudgetEvictorKoordlet = "koordlet"
+	// EvictionBudgetEvictorDescheduler is the evictor name of the evictions initiated by koord-descheduler.
+	EvictionBudgetEvictorDescheduler = "koord-descheduler"
+)
+
+// ClusterEvictionBudgetSpec defines the pool and the limit of the ClusterEvictionBudget
+type ClusterEvictionBudgetSpec struct {
+	// NodeSelector selects the nodes of the pool which the budget applies to.
+	// Nil or empty selector selects all nodes in the cluster.
+	NodeSelector *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	// MaxEvictions is the maximum number of the pods evicted by the koordinator components from the nodes of the pool
+	// in the window. Zero means no eviction is allowed.
+	// +kubebuilder:validation:Minimum=0
+	MaxEvictions int32 `json:"maxEvictions"`
+	// Window is the duration of the sliding window in which the evictions are counted.
+	Window metav1.Duration `json:"window"`
+}
+
+// EvictionRecord records an eviction admitted by the ClusterEvictionBudget
+type EvictionRecord struct {
+	// Namespace is the namespace of the evicted pod.
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the evicted pod.
+	Name string `json:"name,omitempty"`
+	// UID is the UID of the evicted pod.
+	UID types.UID `json:"uid,omitempty"`
+	// NodeName is the node where the pod is evicted from.
+	NodeName string `json:"nodeName,omitempty"`
+	// Evictor is the component initiating the eviction, e.g. `koordlet`, `koord-descheduler`.
+	Evictor string `json:"evictor,omitempty"`
+	// Time is the time when the eviction is admitted.
+	Time metav1.Time `json:"time"`
+}
+
+// ClusterEvictionBudgetStatus defines the observed state of ClusterEvictionBudget
+type ClusterEvictionBudgetStatus struct {
+	// Evictions are the evictions admitted in the current window sorted by the time, the oldest first.
+	Evictions []EvictionRecord `json:"evictions,omitempty"`
+	// CurrentEvictions is the number of the evictions admitted in the current window.
+	CurrentEvictions int32 `json:"currentEvictions,omitempty"`
+	// RemainingEvictions is the number of the evictions which can be admitted in the current window.
+	RemainingEvictions int32 `json:"remainingEvictions,omitempty"`
+	// UpdateTime is the last time this ClusterEvictionBudget was updated.
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ceb
+// +kubebuilder:printcolumn:name="Max",type="integer",JSONPath=".spec.maxEvictions"
+// +kubebuilder:printcolumn:name="Window",type="string",JSONPath=".spec.window"
+// +kubebuilder:printcolumn:name="Current",type="integer",JSONPath=".status.currentEvictions"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterEvictionBudget is the Schema for the clusterevictionbudgets API, which caps the total number of the pods
+// evicted by the koordinator components, i.e. koordlet and koord-descheduler, from a node pool in a time window.
+// An eviction is admitted only if all budgets selecting the node have the remaining evictions, and it is recorded
+// in the status of the budgets before the pod is evicted.
+type ClusterEvictionBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterEvictionBudgetSpec   `json:"spec,omitempty"`
+	Status ClusterEvictionBudgetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterEvictionBudgetList contains a list of ClusterEvictionBudget
+type ClusterEvictionBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterEvictionBudget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterEvictionBudget{}, &ClusterEvictionBudgetList{})
+}
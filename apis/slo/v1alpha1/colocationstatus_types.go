@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ColocationStatusClusterName is the name of the ColocationStatus aggregated for the whole cluster.
+	ColocationStatusClusterName = "cluster"
+	// ColocationStatusPoolNamePrefix is the name prefix of the ColocationStatus aggregated for a node pool.
+	ColocationStatusPoolNamePrefix = "pool-"
+)
+
+// ColocationStatusSpec defines the scope of the ColocationStatus
+type ColocationStatusSpec struct {
+	// Pool is the node pool the status is aggregated for, which is the value of the node pool label.
+	// Empty means the whole cluster.
+	Pool string `json:"pool,omitempty"`
+}
+
+// ColocationSummary summarizes the colocation of the nodes in a period
+type ColocationSummary struct {
+	// NodeCount is the number of the nodes reporting the NodeMetric.
+	NodeCount int64 `json:"nodeCount,omitempty"`
+	// BatchCapacity is the total batch allocatable of the nodes, e.g. `kubernetes.io/batch-cpu`.
+	BatchCapacity corev1.ResourceList `json:"batchCapacity,omitempty"`
+	// BatchAllocated is the total batch requests of the pods on the nodes.
+	BatchAllocated corev1.ResourceList `json:"batchAllocated,omitempty"`
+	// BatchUsage is the total resource usage of the BE pods on the nodes.
+	BatchUsage corev1.ResourceList `json:"batchUsage,omitempty"`
+	// SuppressionPercent is the percent of the batch cpu allocated but not allowed for the BE pods by the cpu
+	// suppression, i.e. 100 * (1 - suppressed cpu / batch allocated cpu).
+	SuppressionPercent int64 `json:"suppressionPercent,omitempty"`
+	// PodEvictionCount is the number of the pods evicted by koordlet in the period.
+	PodEvictionCount int64 `json:"podEvictionCount,omitempty"`
+	// InterferenceCount is the number of the interference incidents in the period. An incident is counted when the
+	// suppression percent of a node rises to the interference threshold.
+	InterferenceCount int64 `json:"interferenceCount,omitempty"`
+}
+
+// ColocationSummaryRecord is the summary of a past period
+type ColocationSummaryRecord struct {
+	// StartTime is the start of the period.
+	StartTime metav1.Time `json:"startTime,omitempty"`
+	// EndTime is the end of the period.
+	EndTime metav1.Time `json:"endTime,omitempty"`
+
+	ColocationSummary `json:",inline"`
+}
+
+// ColocationStatusStatus defines the observed state of ColocationStatus
+type ColocationStatusStatus struct {
+	// UpdateTime is the last time this ColocationStatus was updated.
+	UpdateTime *metav1.Time `json:"updateTime,omitempty"`
+	// PeriodStartTime is the start of the current period.
+	PeriodStartTime *metav1.Time `json:"periodStartTime,omitempty"`
+	// Current is the summary of the current period. The capacity, allocation, usage and suppression are the
+	// latest values, and the counts are accumulated since the PeriodStartTime.
+	Current ColocationSummary `json:"current,omitempty"`
+	// History is the summaries of the past periods sorted by the time, the oldest first.
+	History []ColocationSummaryRecord `json:"history,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Pool",type="string",JSONPath=".spec.pool"
+// +kubebuilder:printcolumn:name="Nodes",type="integer",JSONPath=".status.current.nodeCount"
+// +kubebuilder:printcolumn:name="Suppression",type="integer",JSONPath=".status.current.suppressionPercent"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ColocationStatus is the Schema for the colocationstatuses API, which summarizes the colocation of the cluster or
+// a node pool. It is maintained by koord-manager.
+type ColocationStatus struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ColocationStatusSpec   `json:"spec,omitempty"`
+	Status ColocationStatusStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ColocationStatusList contains a list of ColocationStatus
+type ColocationStatusList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ColocationStatus `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ColocationStatus{}, &ColocationStatusList{})
+}
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,6 +46,55 @@ type NodeMetricInfo struct {
 	// AggregatedSystemUsages will report only if there are enough samples
 	// Deleted pods will be excluded during aggregation
 	AggregatedSystemUsages []AggregatedUsage `json:"aggregatedSystemUsages,omitempty"`
+	// ZoneMemory is the actual memory state of each NUMA zone on the node
+	ZoneMemory []ZoneMemoryInfo `json:"zoneMemory,omitempty"`
+	// NetworkLatency is the network latency SLO status of each QoS class probed on the node
+	NetworkLatency []QoSNetworkLatencyInfo `json:"networkLatency,omitempty"`
+	// MemoryBandwidth is the memory bandwidth state of the node measured by the resctrl
+	MemoryBandwidth *NodeMemoryBandwidthInfo `json:"memoryBandwidth,omitempty"`
+}
+
+// NodeMemoryBandwidthInfo describes the memory bandwidth state of the node, where the bandwidths are in bytes per
+// second.
+type NodeMemoryBandwidthInfo struct {
+	// Capacity is the total memory bandwidth of the node, which is missing if it is not configured on the koordlet
+	Capacity *resource.Quantity `json:"capacity,omitempty"`
+	// Used is the measured memory bandwidth of all QoS classes
+	Used resource.Quantity `json:"used,omitempty"`
+	// Classes is the memory bandwidth state of each QoS class with a resctrl group
+	Classes []QoSMemoryBandwidthInfo `json:"classes,omitempty"`
+}
+
+// QoSMemoryBandwidthInfo describes the memory bandwidth committed to and used by a QoS class.
+type QoSMemoryBandwidthInfo struct {
+	// QoS is the QoS class of the resctrl group
+	QoS apiext.QoSClass `json:"qos"`
+	// Committed is the memory bandwidth committed to the class by the MBA percent of its resctrl group, which is
+	// missing if the capacity is unknown
+	Committed *resource.Quantity `json:"committed,omitempty"`
+	// Used is the measured memory bandwidth of the class
+	Used resource.Quantity `json:"used,omitempty"`
+}
+
+// QoSNetworkLatencyInfo describes the network latency from the pods of a QoS class to the probe endpoint of the class.
+type QoSNetworkLatencyInfo struct {
+	// QoS is the QoS class of the probed pods
+	QoS apiext.QoSClass `json:"qos"`
+	// RTT is the average tcp connect round-trip time of the successful probes
+	RTT *metav1.Duration `json:"rtt,omitempty"`
+	// SLOViolationPercent is the percentage of the probes exceeding the latency SLO threshold or failed
+	SLOViolationPercent int64 `json:"sloViolationPercent"`
+}
+
+// ZoneMemoryInfo describes the actual memory state of a NUMA zone, which is different from the allocatable
+// and requested resources since the memory can be occupied by pods exceeding their requests or the page cache.
+type ZoneMemoryInfo struct {
+	// Name is the zone name, which is the same as the zone name in the NodeResourceTopology, e.g. "node-0"
+	Name string `json:"name"`
+	// Free is the free memory of the zone
+	Free resource.Quantity `json:"free,omitempty"`
+	// Cold is the reclaimable memory of the zone, i.e. the inactive file page cache
+	Cold resource.Quantity `json:"cold,omitempty"`
 }
 
 type AggregatedUsage struct {
@@ -62,6 +112,15 @@ type PodMetricInfo struct {
 	QoS apiext.QoSClass `json:"qos,omitempty"`
 	// Third party extensions for PodMetric
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
+	// ContainersMetric is the resource usage of the containers of the pod
+	ContainersMetric []ContainerMetricInfo `json:"containersMetric,omitempty"`
+}
+
+type ContainerMetricInfo struct {
+	// Name of the container
+	Name string `json:"name,omitempty"`
+	// Resource usage of the container
+	Usage ResourceMap `json:"usage,omitempty"`
 }
 
 type HostApplicationMetricInfo struct {
@@ -103,6 +162,14 @@ type ReclaimableMetric struct {
 	Resource ResourceMap `json:"resource,omitempty"`
 }
 
+// ColocationMetricInfo defines the statistics of the colocation QoS actions taken by koordlet on the node
+type ColocationMetricInfo struct {
+	// PodEvictionCount is the number of the pods evicted by koordlet since it started
+	PodEvictionCount int64 `json:"podEvictionCount,omitempty"`
+	// BESuppressCPU is the cpu allowed for the BE pods by the latest cpu suppression, nil if not suppressed
+	BESuppressCPU *resource.Quantity `json:"beSuppressCPU,omitempty"`
+}
+
 // NodeMetricStatus defines the observed state of NodeMetric
 type NodeMetricStatus struct {
 	// UpdateTime is the last time this NodeMetric was updated.
@@ -119,6 +186,9 @@ type NodeMetricStatus struct {
 
 	// ProdReclaimableMetric is the indicator statistics of Prod type resources reclaimable
 	ProdReclaimableMetric *ReclaimableMetric `json:"prodReclaimableMetric,omitempty"`
+
+	// ColocationMetric contains the statistics of the colocation QoS actions on this node.
+	ColocationMetric *ColocationMetricInfo `json:"colocationMetric,omitempty"`
 }
 
 // +genclient
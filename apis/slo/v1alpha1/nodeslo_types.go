@@ -39,6 +39,11 @@ type CPUQOS struct {
 	// If set to true, pods of this QoS will use a dedicated core sched group for noise clean with the SchedIdle pods.
 	// NOTE: It takes effect if cpuPolicy = "coreSched".
 	CoreExpeller *bool `json:"coreExpeller,omitempty"`
+	// whether pods of the QoS class use the core sched groups isolated from the other QoS classes. default = false
+	// If set to true, pods of this QoS never run on the same SMT core with the pods of the other QoS classes, which
+	// defends against the SMT-level interference and side channels.
+	// NOTE: It takes effect if cpuPolicy = "coreSched".
+	CoreSchedIsolated *bool `json:"coreSchedIsolated,omitempty"`
 }
 
 type CPUQOSPolicy string
@@ -57,6 +62,8 @@ const (
 	NETQOSPolicyTC NETQOSPolicy = "tc"
 	// NETQOSPolicyTerwayQos indicates implement netqos by terway-qos.
 	NETQOSPolicyTerwayQos NETQOSPolicy = "terway-qos"
+	// NETQOSPolicyEBPF indicates implement the egress netqos by the tc htb classes and the eBPF classifier of koordlet.
+	NETQOSPolicyEBPF NETQOSPolicy = "ebpf"
 )
 
 // MemoryQOS enables memory qos features.
@@ -116,6 +123,29 @@ type MemoryQOS struct {
 	// +kubebuilder:validation:Minimum=-25
 	WmarkMinAdj *int64 `json:"wmarkMinAdj,omitempty" validate:"omitempty,min=-25,max=50"`
 
+	// numa_balancing (per-memcg numa balancing required)
+	// NumaBalancing specifies `memory.numa_balancing` which toggles the kernel automatic NUMA balancing for the pod.
+	// Disabling it avoids the jitter of page migrations for the pods whose memory is pinned on NUMA nodes, so it is
+	// only applied to the pods with the NUMA-level memory allocated, while the others keep the kernel default.
+	// Close: 1. Recommended: [LSR:0, LS:1, BE:1].
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	NumaBalancing *int64 `json:"numaBalancing,omitempty" validate:"omitempty,min=0,max=1"`
+
+	// zswap (cgroups-v2 and kernel zswap required)
+	// ZswapEnable specifies whether the pod can use the compressed swap cache, which sets `memory.zswap.max` to
+	// "max" if enabled or 0 if disabled. Disabling it makes the pod bypass zswap, e.g. for the latency-sensitive pods.
+	// Close: 1. Recommended: [LSR:0, LS:0, BE:1].
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	ZswapEnable *int64 `json:"zswapEnable,omitempty" validate:"omitempty,min=0,max=1"`
+	// ZswapWriteback specifies `memory.zswap.writeback` which toggles whether the pages in zswap can be written back
+	// to the backing swap device. It is ignored if the kernel does not support the zswap writeback control.
+	// Close: 1. Recommended: [LSR:0, LS:0, BE:1].
+	// +kubebuilder:validation:Maximum=1
+	// +kubebuilder:validation:Minimum=0
+	ZswapWriteback *int64 `json:"zswapWriteback,omitempty" validate:"omitempty,min=0,max=1"`
+
 	// TODO: enhance the usages of oom priority and oom kill group
 	PriorityEnable *int64 `json:"priorityEnable,omitempty" validate:"omitempty,min=0,max=1"`
 	Priority       *int64 `json:"priority,omitempty" validate:"omitempty,min=0,max=12"`
@@ -276,6 +306,12 @@ type NetworkQOS struct {
 	// string: a specific network bandwidth value, eg: 50M.
 	// +kubebuilder:default=100
 	EgressLimit *intstr.IntOrString `json:"egressLimit,omitempty"`
+
+	// DSCP is the Differentiated Services Code Point marked on the egress packets of the pods, so that the underlay
+	// network can prioritize the traffic beyond the node. The packets are not marked if it is not specified.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=63
+	DSCP *int64 `json:"dscp,omitempty"`
 }
 
 type ResourceQOSPolicies struct {
@@ -304,6 +340,106 @@ type ResourceQOSStrategy struct {
 
 	// ResourceQOS for root cgroup.
 	CgroupRoot *ResourceQOS `json:"cgroupRoot,omitempty"`
+
+	// ResctrlMBATiers are the memory bandwidth tiers on the node. Each tier is created as a separate resctrl group,
+	// and the pods with the annotation `node.koordinator.sh/resctrl-mba-tier` are assigned to the corresponding tier
+	// instead of the resctrl group of their QoS class.
+	ResctrlMBATiers []ResctrlMBATier `json:"resctrlMBATiers,omitempty" validate:"omitempty,dive"`
+
+	// ResctrlMBAAdaptive tunes the MBA percent of the BE resctrl group dynamically according to the memory bandwidth
+	// of the LS resctrl group, instead of applying the MBA percent of the BE class statically.
+	ResctrlMBAAdaptive *ResctrlMBAAdaptiveStrategy `json:"resctrlMBAAdaptive,omitempty"`
+
+	// ResctrlInterferenceControl tightens the L3 cache ways and the MBA percent of the BE resctrl group dynamically
+	// according to the CPI degradation of the LS pods.
+	ResctrlInterferenceControl *ResctrlInterferenceControlStrategy `json:"resctrlInterferenceControl,omitempty"`
+
+	// CPUSharedSubPools partitions the CPU shared pool of the LS pods into sub-pools. The LS pods are assigned to the
+	// first sub-pool whose priority range contains their koordinator priority, and the other LS pods run in the rest of
+	// the shared pool. The last physical core of each NUMA node is kept in the shared pool.
+	CPUSharedSubPools []CPUSharedSubPool `json:"cpuSharedSubPools,omitempty" validate:"omitempty,dive"`
+}
+
+// CPUSharedSubPool is a partition of the CPU shared pool for the LS pods in the priority range.
+type CPUSharedSubPool struct {
+	// Name is the name of the sub-pool.
+	Name string `json:"name" validate:"required"`
+	// MinPriority is the minimum koordinator priority of the pods in the sub-pool.
+	MinPriority int32 `json:"minPriority,omitempty"`
+	// MaxPriority is the maximum koordinator priority of the pods in the sub-pool.
+	MaxPriority int32 `json:"maxPriority,omitempty"`
+	// CPUPercent is the percent of the CPU shared pool on each NUMA node partitioned to the sub-pool.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	CPUPercent int64 `json:"cpuPercent" validate:"min=1,max=100"`
+}
+
+// ResctrlMBATier is a memory bandwidth tier which limits the MBA of its pods on every NUMA node.
+type ResctrlMBATier struct {
+	// Name is the name of the tier referred by the pod annotation.
+	Name string `json:"name" validate:"required"`
+	// MBA percent of the tier
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MBAPercent *int64 `json:"mbaPercent,omitempty" validate:"omitempty,min=0,max=100"`
+}
+
+// ResctrlMBAAdaptiveStrategy is the closed-loop tuning of the BE MBA percent, which decreases the BE MBA percent
+// when the LS memory bandwidth exceeds the target, and increases it when the LS memory bandwidth falls below the
+// target minus the hysteresis.
+type ResctrlMBAAdaptiveStrategy struct {
+	// Enable indicates whether the adaptive MBA tuning is enabled.
+	Enable *bool `json:"enable,omitempty"`
+	// LSMemoryBandwidthTargetMBps is the target of the total memory bandwidth of the LS resctrl group in MB/s.
+	// +kubebuilder:validation:Minimum=0
+	LSMemoryBandwidthTargetMBps *int64 `json:"lsMemoryBandwidthTargetMBps,omitempty" validate:"omitempty,min=0"`
+	// HysteresisPercent is the percentage of the target under which the BE MBA percent starts to increase,
+	// default = 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	HysteresisPercent *int64 `json:"hysteresisPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// StepPercent is the MBA percent changed in each tuning, default = 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercent *int64 `json:"stepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// MinMBAPercent is the lower bound of the BE MBA percent, default = 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MinMBAPercent *int64 `json:"minMBAPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// MaxMBAPercent is the upper bound of the BE MBA percent, default = 100.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MaxMBAPercent *int64 `json:"maxMBAPercent,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ResctrlInterferenceControlStrategy is the closed-loop interference control of the BE resctrl group by the CPI of
+// the LS pods. The L3 cache ways and the MBA percent of the BE group are tightened by a step when the LS CPI degrades
+// beyond the baseline, and relaxed by a step when the LS CPI recovers. The baseline is learned from the LS CPI when
+// the BE group is not tightened. The MBA percent is not tightened when the ResctrlMBAAdaptive is enabled.
+type ResctrlInterferenceControlStrategy struct {
+	// Enable indicates whether the interference control is enabled.
+	Enable *bool `json:"enable,omitempty"`
+	// CPIDegradationPercent is the increase of the LS CPI in percentage of the baseline to tighten the BE group,
+	// default = 20.
+	// +kubebuilder:validation:Minimum=1
+	CPIDegradationPercent *int64 `json:"cpiDegradationPercent,omitempty" validate:"omitempty,min=1"`
+	// HysteresisPercent is the percentage under the CPIDegradationPercent below which the BE group starts to be
+	// relaxed, default = 10.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	HysteresisPercent *int64 `json:"hysteresisPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// StepPercent is the percentage of the L3 cache ways and the MBA changed in each tuning, default = 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	StepPercent *int64 `json:"stepPercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// MinCATRangePercent is the lower bound of the L3 cache ways percentage of the BE group, default = 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MinCATRangePercent *int64 `json:"minCATRangePercent,omitempty" validate:"omitempty,min=1,max=100"`
+	// MinMBAPercent is the lower bound of the BE MBA percent, default = 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	MinMBAPercent *int64 `json:"minMBAPercent,omitempty" validate:"omitempty,min=1,max=100"`
 }
 
 type CPUSuppressPolicy string
@@ -311,6 +447,10 @@ type CPUSuppressPolicy string
 const (
 	CPUSetPolicy      CPUSuppressPolicy = "cpuset"
 	CPUCfsQuotaPolicy CPUSuppressPolicy = "cfsQuota"
+	// CPUIdlePolicy marks the BE cgroup as idle (cpu.idle=1) instead of shrinking the cpuset or the cfs quota, so the
+	// BE tasks are scheduled like SCHED_IDLE and yield to the LS tasks. It requires the cgroup v2 with the cpu.idle
+	// support, otherwise the cpuset policy is used. It should not be used with the SchedIdle of the CPUQOS.
+	CPUIdlePolicy CPUSuppressPolicy = "cpuIdle"
 )
 
 type CPUEvictPolicy string
@@ -334,6 +474,10 @@ type ResourceThresholdStrategy struct {
 	CPUSuppressMinPercent *int64 `json:"cpuSuppressMinPercent,omitempty" validate:"omitempty,min=0,max=100"`
 	// CPUSuppressPolicy
 	CPUSuppressPolicy CPUSuppressPolicy `json:"cpuSuppressPolicy,omitempty"`
+	// CPUSuppressNUMAAware indicates whether to calculate the suppressed cpus of the BE pods for each NUMA node by the
+	// non-BE usage on it, so the BE pods are squeezed only on the NUMA nodes where the non-BE pods are busy.
+	// It only works with the cpuset policy. Default = false.
+	CPUSuppressNUMAAware *bool `json:"cpuSuppressNUMAAware,omitempty"`
 
 	// upper: memory evict threshold percentage (0,100), default = 70
 	// +kubebuilder:validation:Maximum=100
@@ -343,6 +487,17 @@ type ResourceThresholdStrategy struct {
 	// +kubebuilder:validation:Maximum=100
 	// +kubebuilder:validation:Minimum=0
 	MemoryEvictLowerPercent *int64 `json:"memoryEvictLowerPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=MemoryEvictThresholdPercent"`
+	// MemoryEvictNUMAAware indicates whether to check the memory usage of each NUMA node by its free memory, and evict
+	// the BE pods bound to the pressured NUMA nodes first. The NUMA node is pressured when its usage exceeds the
+	// MemoryEvictThresholdPercent, and released until the usage is under the MemoryEvictLowerPercent. Default = false.
+	MemoryEvictNUMAAware *bool `json:"memoryEvictNUMAAware,omitempty"`
+	// MemoryThrottleThresholdPercent is the node memory usage percentage (0,100) to start throttling the memory of the
+	// BE pods by the memory.high, which should be less than the MemoryEvictThresholdPercent. The memory.high of the BE
+	// pods shrinks as the node memory pressure grows, so the kernel reclaims and throttles the allocation of the BE
+	// pods before the memory eviction happens. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	MemoryThrottleThresholdPercent *int64 `json:"memoryThrottleThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
 
 	// be.satisfactionRate = be.CPURealLimit/be.CPURequest
 	// if be.satisfactionRate > CPUEvictBESatisfactionUpperPercent/100, then stop to evict.
@@ -359,6 +514,29 @@ type ResourceThresholdStrategy struct {
 	// CPUEvictPolicy defines the policy for the BECPUEvict feature.
 	// Default: `evictByRealLimit`.
 	CPUEvictPolicy CPUEvictPolicy `json:"cpuEvictPolicy,omitempty"`
+	// CompletionProtectionWindowSeconds is the default run-to-completion protection window for the pods which declare
+	// an expected completion time but no window of their own. Pods inside the window are deprioritized as eviction
+	// victims. Disabled if not set.
+	CompletionProtectionWindowSeconds *int64 `json:"completionProtectionWindowSeconds,omitempty" validate:"omitempty,min=0"`
+
+	// PSIEvictCPUThresholdPercent is the threshold of the cpu some avg10 pressure percentage (0,100) of the node or
+	// any LS pod to evict the BE pods. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	PSIEvictCPUThresholdPercent *int64 `json:"psiEvictCPUThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// PSIEvictMemoryThresholdPercent is the threshold of the memory some avg10 pressure percentage (0,100) of the node
+	// or any LS pod to evict the BE pods. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	PSIEvictMemoryThresholdPercent *int64 `json:"psiEvictMemoryThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// PSIEvictIOThresholdPercent is the threshold of the io some avg10 pressure percentage (0,100) of the node or any
+	// LS pod to evict the BE pods. Disabled if not set.
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:validation:Minimum=0
+	PSIEvictIOThresholdPercent *int64 `json:"psiEvictIOThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// PSIEvictTimeWindowSeconds is the duration the pressure keeps exceeding the threshold before the BE pods are
+	// evicted, default = 60.
+	PSIEvictTimeWindowSeconds *int64 `json:"psiEvictTimeWindowSeconds,omitempty" validate:"omitempty,gt=0"`
 }
 
 // ResctrlQOSCfg stores node-level config of resctrl qos
@@ -366,6 +544,18 @@ type ResctrlQOSCfg struct {
 	// Enable indicates whether the resctrl qos is enabled.
 	Enable     *bool `json:"enable,omitempty"`
 	ResctrlQOS `json:",inline"`
+	// Sockets overrides the resctrl qos on the specified sockets of the multi-socket machines, where the schemata of
+	// the l3 cache domains on the sockets are generated with the overridden values instead of the uniform ones.
+	// The values not specified in the override inherit the values of the class.
+	Sockets []ResctrlSocketQOS `json:"sockets,omitempty" validate:"omitempty,dive"`
+}
+
+// ResctrlSocketQOS is the resctrl qos of the class on the specified sockets.
+type ResctrlSocketQOS struct {
+	// SocketIDs are the ids of the cpu sockets to override
+	// +kubebuilder:validation:MinItems=1
+	SocketIDs  []int32 `json:"socketIDs" validate:"min=1"`
+	ResctrlQOS `json:",inline"`
 }
 
 type ResctrlQOS struct {
@@ -377,6 +567,16 @@ type ResctrlQOS struct {
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
 	CATRangeEndPercent *int64 `json:"catRangeEndPercent,omitempty" validate:"omitempty,min=0,max=100,gtfield=CATRangeStartPercent"`
+	// LLC available range start of the code for pods by percentage when the L3 CDP is enabled.
+	// The code shares the range of the data, i.e. CATRangeStartPercent, if not specified.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CATCodeRangeStartPercent *int64 `json:"catCodeRangeStartPercent,omitempty" validate:"omitempty,min=0,max=100,ltfield=CATCodeRangeEndPercent"`
+	// LLC available range end of the code for pods by percentage when the L3 CDP is enabled.
+	// The code shares the range of the data, i.e. CATRangeEndPercent, if not specified.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	CATCodeRangeEndPercent *int64 `json:"catCodeRangeEndPercent,omitempty" validate:"omitempty,min=0,max=100,gtfield=CATCodeRangeStartPercent"`
 	// MBA percent
 	// +kubebuilder:validation:Minimum=0
 	// +kubebuilder:validation:Maximum=100
@@ -412,6 +612,22 @@ type CPUBurstStrategy struct {
 	CPUBurstConfig `json:",inline"`
 	// scale down cfs quota if node cpu overload, default = 50
 	SharePoolThresholdPercent *int64 `json:"sharePoolThresholdPercent,omitempty" validate:"omitempty,min=0,max=100"`
+	// allow or deny cpu burst by the owner workloads of pods, all pods are allowed if not specified
+	WorkloadPolicy *CPUBurstWorkloadPolicy `json:"workloadPolicy,omitempty"`
+}
+
+// CPUBurstWorkloadPolicy allows or denies cpu burst of pods according to the kinds of their owner workloads and
+// the pod labels. A pod can burst only if it is allowed and not denied.
+type CPUBurstWorkloadPolicy struct {
+	// workload kinds whose pods are allowed to burst, e.g. Deployment, StatefulSet; all kinds are allowed if empty.
+	// The pods without any controller are regarded as the kind Pod.
+	AllowedKinds []string `json:"allowedKinds,omitempty"`
+	// workload kinds whose pods are denied to burst, e.g. CronJob
+	DeniedKinds []string `json:"deniedKinds,omitempty"`
+	// pods matching the selector are allowed to burst, all pods are allowed if nil
+	AllowedSelector *metav1.LabelSelector `json:"allowedSelector,omitempty"`
+	// pods matching the selector are denied to burst
+	DeniedSelector *metav1.LabelSelector `json:"deniedSelector,omitempty"`
 }
 
 type SystemStrategy struct {
@@ -440,6 +656,23 @@ type NodeSLOSpec struct {
 	Extensions *ExtensionsMap `json:"extensions,omitempty"`
 	// QoS management for out-of-band applications
 	HostApplications []HostApplicationSpec `json:"hostApplications,omitempty"`
+	// Rules to exclude the pods from the cgroup reconciliation of koordlet
+	CgroupExclusionRules []CgroupExclusionRule `json:"cgroupExclusionRules,omitempty"`
+}
+
+// CgroupExclusionRule excludes the matched pods from the cgroup reconciliation of koordlet, e.g. the pods whose
+// resources are managed by another agent. The conditions of a rule are ANDed, and a rule without any condition
+// matches nothing.
+type CgroupExclusionRule struct {
+	// Name is the name of the rule.
+	Name string `json:"name,omitempty"`
+	// Namespaces are the namespaces of the excluded pods.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// PodSelector selects the excluded pods by the labels.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// CgroupPathRegex is the unanchored regular expression matching the cgroup dir relative to the cgroup root,
+	// e.g. "kubepods.slice/.*pod1234". The cgroups under the excluded pod dir are also excluded.
+	CgroupPathRegex string `json:"cgroupPathRegex,omitempty"`
 }
 
 // NodeSLOStatus defines the observed state of NodeSLO
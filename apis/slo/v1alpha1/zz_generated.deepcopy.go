@@ -174,6 +174,11 @@ func (in *CPUBurstStrategy) DeepCopyInto(out *CPUBurstStrategy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.WorkloadPolicy != nil {
+		in, out := &in.WorkloadPolicy, &out.WorkloadPolicy
+		*out = new(CPUBurstWorkloadPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUBurstStrategy.
@@ -186,6 +191,41 @@ func (in *CPUBurstStrategy) DeepCopy() *CPUBurstStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUBurstWorkloadPolicy) DeepCopyInto(out *CPUBurstWorkloadPolicy) {
+	*out = *in
+	if in.AllowedKinds != nil {
+		in, out := &in.AllowedKinds, &out.AllowedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeniedKinds != nil {
+		in, out := &in.DeniedKinds, &out.DeniedKinds
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedSelector != nil {
+		in, out := &in.AllowedSelector, &out.AllowedSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeniedSelector != nil {
+		in, out := &in.DeniedSelector, &out.DeniedSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUBurstWorkloadPolicy.
+func (in *CPUBurstWorkloadPolicy) DeepCopy() *CPUBurstWorkloadPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUBurstWorkloadPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CPUQOS) DeepCopyInto(out *CPUQOS) {
 	*out = *in
@@ -204,6 +244,11 @@ func (in *CPUQOS) DeepCopyInto(out *CPUQOS) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CoreSchedIsolated != nil {
+		in, out := &in.CoreSchedIsolated, &out.CoreSchedIsolated
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUQOS.
@@ -237,6 +282,46 @@ func (in *CPUQOSCfg) DeepCopy() *CPUQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CPUSharedSubPool) DeepCopyInto(out *CPUSharedSubPool) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CPUSharedSubPool.
+func (in *CPUSharedSubPool) DeepCopy() *CPUSharedSubPool {
+	if in == nil {
+		return nil
+	}
+	out := new(CPUSharedSubPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CgroupExclusionRule) DeepCopyInto(out *CgroupExclusionRule) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CgroupExclusionRule.
+func (in *CgroupExclusionRule) DeepCopy() *CgroupExclusionRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CgroupExclusionRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CgroupPath) DeepCopyInto(out *CgroupPath) {
 	*out = *in
@@ -252,6 +337,323 @@ func (in *CgroupPath) DeepCopy() *CgroupPath {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterEvictionBudget) DeepCopyInto(out *ClusterEvictionBudget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterEvictionBudget.
+func (in *ClusterEvictionBudget) DeepCopy() *ClusterEvictionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterEvictionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterEvictionBudget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterEvictionBudgetList) DeepCopyInto(out *ClusterEvictionBudgetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterEvictionBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterEvictionBudgetList.
+func (in *ClusterEvictionBudgetList) DeepCopy() *ClusterEvictionBudgetList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterEvictionBudgetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterEvictionBudgetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterEvictionBudgetSpec) DeepCopyInto(out *ClusterEvictionBudgetSpec) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Window = in.Window
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterEvictionBudgetSpec.
+func (in *ClusterEvictionBudgetSpec) DeepCopy() *ClusterEvictionBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterEvictionBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterEvictionBudgetStatus) DeepCopyInto(out *ClusterEvictionBudgetStatus) {
+	*out = *in
+	if in.Evictions != nil {
+		in, out := &in.Evictions, &out.Evictions
+		*out = make([]EvictionRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterEvictionBudgetStatus.
+func (in *ClusterEvictionBudgetStatus) DeepCopy() *ClusterEvictionBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterEvictionBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationMetricInfo) DeepCopyInto(out *ColocationMetricInfo) {
+	*out = *in
+	if in.BESuppressCPU != nil {
+		in, out := &in.BESuppressCPU, &out.BESuppressCPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationMetricInfo.
+func (in *ColocationMetricInfo) DeepCopy() *ColocationMetricInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationMetricInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationStatus) DeepCopyInto(out *ColocationStatus) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationStatus.
+func (in *ColocationStatus) DeepCopy() *ColocationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ColocationStatus) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationStatusList) DeepCopyInto(out *ColocationStatusList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ColocationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationStatusList.
+func (in *ColocationStatusList) DeepCopy() *ColocationStatusList {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationStatusList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ColocationStatusList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationStatusSpec) DeepCopyInto(out *ColocationStatusSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationStatusSpec.
+func (in *ColocationStatusSpec) DeepCopy() *ColocationStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationStatusStatus) DeepCopyInto(out *ColocationStatusStatus) {
+	*out = *in
+	if in.UpdateTime != nil {
+		in, out := &in.UpdateTime, &out.UpdateTime
+		*out = (*in).DeepCopy()
+	}
+	if in.PeriodStartTime != nil {
+		in, out := &in.PeriodStartTime, &out.PeriodStartTime
+		*out = (*in).DeepCopy()
+	}
+	in.Current.DeepCopyInto(&out.Current)
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ColocationSummaryRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationStatusStatus.
+func (in *ColocationStatusStatus) DeepCopy() *ColocationStatusStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationStatusStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationSummary) DeepCopyInto(out *ColocationSummary) {
+	*out = *in
+	if in.BatchCapacity != nil {
+		in, out := &in.BatchCapacity, &out.BatchCapacity
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.BatchAllocated != nil {
+		in, out := &in.BatchAllocated, &out.BatchAllocated
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.BatchUsage != nil {
+		in, out := &in.BatchUsage, &out.BatchUsage
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationSummary.
+func (in *ColocationSummary) DeepCopy() *ColocationSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ColocationSummaryRecord) DeepCopyInto(out *ColocationSummaryRecord) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	in.ColocationSummary.DeepCopyInto(&out.ColocationSummary)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ColocationSummaryRecord.
+func (in *ColocationSummaryRecord) DeepCopy() *ColocationSummaryRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(ColocationSummaryRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerMetricInfo) DeepCopyInto(out *ContainerMetricInfo) {
+	*out = *in
+	in.Usage.DeepCopyInto(&out.Usage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerMetricInfo.
+func (in *ContainerMetricInfo) DeepCopy() *ContainerMetricInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerMetricInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvictionRecord) DeepCopyInto(out *EvictionRecord) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvictionRecord.
+func (in *EvictionRecord) DeepCopy() *EvictionRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(EvictionRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostApplicationMetricInfo) DeepCopyInto(out *HostApplicationMetricInfo) {
 	*out = *in
@@ -436,6 +838,21 @@ func (in *MemoryQOS) DeepCopyInto(out *MemoryQOS) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.NumaBalancing != nil {
+		in, out := &in.NumaBalancing, &out.NumaBalancing
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ZswapEnable != nil {
+		in, out := &in.ZswapEnable, &out.ZswapEnable
+		*out = new(int64)
+		**out = **in
+	}
+	if in.ZswapWriteback != nil {
+		in, out := &in.ZswapWriteback, &out.ZswapWriteback
+		*out = new(int64)
+		**out = **in
+	}
 	if in.PriorityEnable != nil {
 		in, out := &in.PriorityEnable, &out.PriorityEnable
 		*out = new(int64)
@@ -507,6 +924,11 @@ func (in *NetworkQOS) DeepCopyInto(out *NetworkQOS) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.DSCP != nil {
+		in, out := &in.DSCP, &out.DSCP
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkQOS.
@@ -540,6 +962,34 @@ func (in *NetworkQOSCfg) DeepCopy() *NetworkQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMemoryBandwidthInfo) DeepCopyInto(out *NodeMemoryBandwidthInfo) {
+	*out = *in
+	if in.Capacity != nil {
+		in, out := &in.Capacity, &out.Capacity
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.Used = in.Used.DeepCopy()
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]QoSMemoryBandwidthInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMemoryBandwidthInfo.
+func (in *NodeMemoryBandwidthInfo) DeepCopy() *NodeMemoryBandwidthInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMemoryBandwidthInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMetric) DeepCopyInto(out *NodeMetric) {
 	*out = *in
@@ -621,6 +1071,25 @@ func (in *NodeMetricInfo) DeepCopyInto(out *NodeMetricInfo) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ZoneMemory != nil {
+		in, out := &in.ZoneMemory, &out.ZoneMemory
+		*out = make([]ZoneMemoryInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkLatency != nil {
+		in, out := &in.NetworkLatency, &out.NetworkLatency
+		*out = make([]QoSNetworkLatencyInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MemoryBandwidth != nil {
+		in, out := &in.MemoryBandwidth, &out.MemoryBandwidth
+		*out = new(NodeMemoryBandwidthInfo)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricInfo.
@@ -724,6 +1193,11 @@ func (in *NodeMetricStatus) DeepCopyInto(out *NodeMetricStatus) {
 		*out = new(ReclaimableMetric)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ColocationMetric != nil {
+		in, out := &in.ColocationMetric, &out.ColocationMetric
+		*out = new(ColocationMetricInfo)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricStatus.
@@ -829,6 +1303,13 @@ func (in *NodeSLOSpec) DeepCopyInto(out *NodeSLOSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CgroupExclusionRules != nil {
+		in, out := &in.CgroupExclusionRules, &out.CgroupExclusionRules
+		*out = make([]CgroupExclusionRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeSLOSpec.
@@ -902,6 +1383,13 @@ func (in *PodMetricInfo) DeepCopyInto(out *PodMetricInfo) {
 		in, out := &in.Extensions, &out.Extensions
 		*out = (*in).DeepCopy()
 	}
+	if in.ContainersMetric != nil {
+		in, out := &in.ContainersMetric, &out.ContainersMetric
+		*out = make([]ContainerMetricInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodMetricInfo.
@@ -914,6 +1402,47 @@ func (in *PodMetricInfo) DeepCopy() *PodMetricInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSMemoryBandwidthInfo) DeepCopyInto(out *QoSMemoryBandwidthInfo) {
+	*out = *in
+	if in.Committed != nil {
+		in, out := &in.Committed, &out.Committed
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	out.Used = in.Used.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSMemoryBandwidthInfo.
+func (in *QoSMemoryBandwidthInfo) DeepCopy() *QoSMemoryBandwidthInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSMemoryBandwidthInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QoSNetworkLatencyInfo) DeepCopyInto(out *QoSNetworkLatencyInfo) {
+	*out = *in
+	if in.RTT != nil {
+		in, out := &in.RTT, &out.RTT
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QoSNetworkLatencyInfo.
+func (in *QoSNetworkLatencyInfo) DeepCopy() *QoSNetworkLatencyInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(QoSNetworkLatencyInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReclaimableMetric) DeepCopyInto(out *ReclaimableMetric) {
 	*out = *in
@@ -943,6 +1472,16 @@ func (in *ResctrlQOS) DeepCopyInto(out *ResctrlQOS) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CATCodeRangeStartPercent != nil {
+		in, out := &in.CATCodeRangeStartPercent, &out.CATCodeRangeStartPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CATCodeRangeEndPercent != nil {
+		in, out := &in.CATCodeRangeEndPercent, &out.CATCodeRangeEndPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.MBAPercent != nil {
 		in, out := &in.MBAPercent, &out.MBAPercent
 		*out = new(int64)
@@ -969,6 +1508,13 @@ func (in *ResctrlQOSCfg) DeepCopyInto(out *ResctrlQOSCfg) {
 		**out = **in
 	}
 	in.ResctrlQOS.DeepCopyInto(&out.ResctrlQOS)
+	if in.Sockets != nil {
+		in, out := &in.Sockets, &out.Sockets
+		*out = make([]ResctrlSocketQOS, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlQOSCfg.
@@ -981,6 +1527,137 @@ func (in *ResctrlQOSCfg) DeepCopy() *ResctrlQOSCfg {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResctrlSocketQOS) DeepCopyInto(out *ResctrlSocketQOS) {
+	*out = *in
+	if in.SocketIDs != nil {
+		in, out := &in.SocketIDs, &out.SocketIDs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	in.ResctrlQOS.DeepCopyInto(&out.ResctrlQOS)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlSocketQOS.
+func (in *ResctrlSocketQOS) DeepCopy() *ResctrlSocketQOS {
+	if in == nil {
+		return nil
+	}
+	out := new(ResctrlSocketQOS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResctrlInterferenceControlStrategy) DeepCopyInto(out *ResctrlInterferenceControlStrategy) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CPIDegradationPercent != nil {
+		in, out := &in.CPIDegradationPercent, &out.CPIDegradationPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HysteresisPercent != nil {
+		in, out := &in.HysteresisPercent, &out.HysteresisPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinCATRangePercent != nil {
+		in, out := &in.MinCATRangePercent, &out.MinCATRangePercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinMBAPercent != nil {
+		in, out := &in.MinMBAPercent, &out.MinMBAPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlInterferenceControlStrategy.
+func (in *ResctrlInterferenceControlStrategy) DeepCopy() *ResctrlInterferenceControlStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResctrlInterferenceControlStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResctrlMBAAdaptiveStrategy) DeepCopyInto(out *ResctrlMBAAdaptiveStrategy) {
+	*out = *in
+	if in.Enable != nil {
+		in, out := &in.Enable, &out.Enable
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LSMemoryBandwidthTargetMBps != nil {
+		in, out := &in.LSMemoryBandwidthTargetMBps, &out.LSMemoryBandwidthTargetMBps
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HysteresisPercent != nil {
+		in, out := &in.HysteresisPercent, &out.HysteresisPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.StepPercent != nil {
+		in, out := &in.StepPercent, &out.StepPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MinMBAPercent != nil {
+		in, out := &in.MinMBAPercent, &out.MinMBAPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MaxMBAPercent != nil {
+		in, out := &in.MaxMBAPercent, &out.MaxMBAPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlMBAAdaptiveStrategy.
+func (in *ResctrlMBAAdaptiveStrategy) DeepCopy() *ResctrlMBAAdaptiveStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(ResctrlMBAAdaptiveStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResctrlMBATier) DeepCopyInto(out *ResctrlMBATier) {
+	*out = *in
+	if in.MBAPercent != nil {
+		in, out := &in.MBAPercent, &out.MBAPercent
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResctrlMBATier.
+func (in *ResctrlMBATier) DeepCopy() *ResctrlMBATier {
+	if in == nil {
+		return nil
+	}
+	out := new(ResctrlMBATier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceMap) DeepCopyInto(out *ResourceMap) {
 	*out = *in
@@ -1108,6 +1785,28 @@ func (in *ResourceQOSStrategy) DeepCopyInto(out *ResourceQOSStrategy) {
 		*out = new(ResourceQOS)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResctrlMBATiers != nil {
+		in, out := &in.ResctrlMBATiers, &out.ResctrlMBATiers
+		*out = make([]ResctrlMBATier, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ResctrlMBAAdaptive != nil {
+		in, out := &in.ResctrlMBAAdaptive, &out.ResctrlMBAAdaptive
+		*out = new(ResctrlMBAAdaptiveStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResctrlInterferenceControl != nil {
+		in, out := &in.ResctrlInterferenceControl, &out.ResctrlInterferenceControl
+		*out = new(ResctrlInterferenceControlStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CPUSharedSubPools != nil {
+		in, out := &in.CPUSharedSubPools, &out.CPUSharedSubPools
+		*out = make([]CPUSharedSubPool, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQOSStrategy.
@@ -1138,6 +1837,11 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CPUSuppressNUMAAware != nil {
+		in, out := &in.CPUSuppressNUMAAware, &out.CPUSuppressNUMAAware
+		*out = new(bool)
+		**out = **in
+	}
 	if in.MemoryEvictThresholdPercent != nil {
 		in, out := &in.MemoryEvictThresholdPercent, &out.MemoryEvictThresholdPercent
 		*out = new(int64)
@@ -1148,6 +1852,16 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.MemoryEvictNUMAAware != nil {
+		in, out := &in.MemoryEvictNUMAAware, &out.MemoryEvictNUMAAware
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MemoryThrottleThresholdPercent != nil {
+		in, out := &in.MemoryThrottleThresholdPercent, &out.MemoryThrottleThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
 	if in.CPUEvictBESatisfactionUpperPercent != nil {
 		in, out := &in.CPUEvictBESatisfactionUpperPercent, &out.CPUEvictBESatisfactionUpperPercent
 		*out = new(int64)
@@ -1168,6 +1882,31 @@ func (in *ResourceThresholdStrategy) DeepCopyInto(out *ResourceThresholdStrategy
 		*out = new(int64)
 		**out = **in
 	}
+	if in.CompletionProtectionWindowSeconds != nil {
+		in, out := &in.CompletionProtectionWindowSeconds, &out.CompletionProtectionWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSIEvictCPUThresholdPercent != nil {
+		in, out := &in.PSIEvictCPUThresholdPercent, &out.PSIEvictCPUThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSIEvictMemoryThresholdPercent != nil {
+		in, out := &in.PSIEvictMemoryThresholdPercent, &out.PSIEvictMemoryThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSIEvictIOThresholdPercent != nil {
+		in, out := &in.PSIEvictIOThresholdPercent, &out.PSIEvictIOThresholdPercent
+		*out = new(int64)
+		**out = **in
+	}
+	if in.PSIEvictTimeWindowSeconds != nil {
+		in, out := &in.PSIEvictTimeWindowSeconds, &out.PSIEvictTimeWindowSeconds
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceThresholdStrategy.
@@ -1254,3 +1993,20 @@ func (in *ThirdPartyAllocations) DeepCopy() *ThirdPartyAllocations {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZoneMemoryInfo) DeepCopyInto(out *ZoneMemoryInfo) {
+	*out = *in
+	out.Free = in.Free.DeepCopy()
+	out.Cold = in.Cold.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZoneMemoryInfo.
+func (in *ZoneMemoryInfo) DeepCopy() *ZoneMemoryInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ZoneMemoryInfo)
+	in.DeepCopyInto(out)
+	return out
+}
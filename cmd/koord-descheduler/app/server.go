@@ -64,6 +64,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/fieldindex"
 	frameworkruntime "github.com/koordinator-sh/koordinator/pkg/descheduler/framework/runtime"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 	"github.com/koordinator-sh/koordinator/pkg/util/transformer"
 )
 
@@ -281,6 +282,8 @@ func Setup(ctx context.Context, opts *options.Options, outOfTreeRegistryOptions
 
 	transformer.InstallPodTransformer(cc.InformerFactory.Core().V1().Pods().Informer())
 	transformer.InstallNodeTransformer(cc.InformerFactory.Core().V1().Nodes().Informer())
+	// order the victims by the remaining disruptions allowed of their PodDisruptionBudgets
+	sorter.SetDisruptionBudgetGetter(sorter.NewPDBDisruptionBudgetGetter(cc.InformerFactory.Policy().V1().PodDisruptionBudgets().Lister()))
 
 	deschedulercontrollersoptions.Manager = cc.Manager
 	ctrl.SetLogger(klogr.New())
@@ -22,18 +22,44 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/koordinator-sh/koordinator/pkg/quota-controller/profile"
+	"github.com/koordinator-sh/koordinator/pkg/quota-controller/usage"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/colocationstatus"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/cpuallocationhint"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/evictionbudget"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/gpuprofile"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metricsprovider"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetricreport"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodeslo"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodeusageforecast"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodeworkloadmix"
 )
 
 var controllerInitFlags = map[string]func(*flag.FlagSet){
-	noderesource.Name: noderesource.InitFlags,
+	colocationstatus.Name:  colocationstatus.InitFlags,
+	cpuallocationhint.Name: cpuallocationhint.InitFlags,
+	gpuprofile.Name:        gpuprofile.InitFlags,
+	metricsprovider.Name:   metricsprovider.InitFlags,
+	nodemetricreport.Name:  nodemetricreport.InitFlags,
+	noderesource.Name:      noderesource.InitFlags,
+	nodeusageforecast.Name: nodeusageforecast.InitFlags,
+	nodeworkloadmix.Name:   nodeworkloadmix.InitFlags,
+	usage.Name:             usage.InitFlags,
 }
 
 var controllerAddFuncs = map[string]func(manager.Manager) error{
-	nodemetric.Name:   nodemetric.Add,
-	noderesource.Name: noderesource.Add,
-	nodeslo.Name:      nodeslo.Add,
-	profile.Name:      profile.Add,
+	colocationstatus.Name:  colocationstatus.Add,
+	cpuallocationhint.Name: cpuallocationhint.Add,
+	evictionbudget.Name:    evictionbudget.Add,
+	gpuprofile.Name:        gpuprofile.Add,
+	metricsprovider.Name:   metricsprovider.Add,
+	nodemetric.Name:        nodemetric.Add,
+	nodemetricreport.Name:  nodemetricreport.Add,
+	noderesource.Name:      noderesource.Add,
+	nodeslo.Name:           nodeslo.Add,
+	nodeusageforecast.Name: nodeusageforecast.Add,
+	nodeworkloadmix.Name:   nodeworkloadmix.Add,
+	profile.Name:           profile.Add,
+	usage.Name:             usage.Add,
 }
@@ -33,6 +33,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system/emulator"
 	metricsutil "github.com/koordinator-sh/koordinator/pkg/util/metrics"
 )
 
@@ -60,6 +61,16 @@ func main() {
 
 	stopCtx := signals.SetupSignalHandler()
 
+	// setup the emulated system before the system support configs are initialized
+	if len(*options.EmulatedSystemSpec) > 0 {
+		klog.Warningf("Setting up the emulated system in %s, which is only for testing", *options.EmulatedSystemRoot)
+		e, err := setupEmulatedSystem(*options.EmulatedSystemSpec, *options.EmulatedSystemRoot)
+		if err != nil {
+			klog.Fatalf("Unable to setup the emulated system: %v", err)
+		}
+		go e.Run(stopCtx.Done())
+	}
+
 	// setup the default auditor
 	if features.DefaultKoordletFeatureGate.Enabled(features.AuditEvents) {
 		audit.SetupDefaultAuditor(cfg.AuditConf, stopCtx.Done())
@@ -85,14 +96,30 @@ func main() {
 	d.Run(stopCtx.Done())
 }
 
+func setupEmulatedSystem(specPath, rootDir string) (*emulator.Emulator, error) {
+	spec, err := emulator.LoadSpec(specPath)
+	if err != nil {
+		return nil, err
+	}
+	e, err := emulator.New(rootDir, spec)
+	if err != nil {
+		return nil, err
+	}
+	if err = e.Setup(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 func installHTTPHandler() {
 	klog.Infof("Starting prometheus server on %v", *options.ServerAddr)
 	mux := http.NewServeMux()
-	mux.Handle(metrics.ExternalHTTPPath, promhttp.HandlerFor(metrics.ExternalRegistry, promhttp.HandlerOpts{}))
-	mux.Handle(metrics.InternalHTTPPath, promhttp.HandlerFor(metrics.InternalRegistry, promhttp.HandlerOpts{}))
+	// enable the OpenMetrics format so that the exemplars linking the metrics to the audit events can be exposed
+	mux.Handle(metrics.ExternalHTTPPath, promhttp.HandlerFor(metrics.ExternalRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
+	mux.Handle(metrics.InternalHTTPPath, promhttp.HandlerFor(metrics.InternalRegistry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
 	// merge internal and external
 	mux.Handle(metrics.DefaultHTTPPath, promhttp.HandlerFor(
-		metricsutil.MergedGatherFunc(metrics.InternalRegistry, metrics.ExternalRegistry), promhttp.HandlerOpts{}))
+		metricsutil.MergedGatherFunc(metrics.InternalRegistry, metrics.ExternalRegistry), promhttp.HandlerOpts{EnableOpenMetrics: true}))
 	if features.DefaultKoordletFeatureGate.Enabled(features.AuditEventsHTTPHandler) {
 		mux.HandleFunc("/events", audit.HttpHandler())
 	}
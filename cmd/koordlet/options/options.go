@@ -29,6 +29,10 @@ var (
 	PprofAddr    = flag.String("pprof-addr", ":9317", "The address the pprof binds to.")
 	KubeAPIQPS   = flag.Float64("kube-api-qps", 20.0, "QPS to use while talking with kube-apiserver.")
 	KubeAPIBurst = flag.Int("kube-api-burst", 30, "Burst to use while talking with kube-apiserver.")
+
+	EmulatedSystemSpec = flag.String("emulated-system-spec", "", "The spec file of the emulated cgroup, resctrl and proc "+
+		"filesystems. If specified, koordlet runs on the emulated system instead of the host one, which is only for testing.")
+	EmulatedSystemRoot = flag.String("emulated-system-root", "/tmp/koordlet-emulated-system", "The root dir of the emulated system.")
 )
 
 // ExtendedHTTPHandlerRegistry is the registry of extended HTTP handlers.
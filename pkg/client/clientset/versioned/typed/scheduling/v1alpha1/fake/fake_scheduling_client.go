@@ -32,6 +32,14 @@ func (c *FakeSchedulingV1alpha1) Devices() v1alpha1.DeviceInterface {
 	return &FakeDevices{c}
 }
 
+func (c *FakeSchedulingV1alpha1) ImagePrePulls() v1alpha1.ImagePrePullInterface {
+	return &FakeImagePrePulls{c}
+}
+
+func (c *FakeSchedulingV1alpha1) NodeMaintenances() v1alpha1.NodeMaintenanceInterface {
+	return &FakeNodeMaintenances{c}
+}
+
 func (c *FakeSchedulingV1alpha1) PodMigrationJobs() v1alpha1.PodMigrationJobInterface {
 	return &FakePodMigrationJobs{c}
 }
@@ -20,6 +20,10 @@ package v1alpha1
 
 type DeviceExpansion interface{}
 
+type ImagePrePullExpansion interface{}
+
+type NodeMaintenanceExpansion interface{}
+
 type PodMigrationJobExpansion interface{}
 
 type ReservationExpansion interface{}
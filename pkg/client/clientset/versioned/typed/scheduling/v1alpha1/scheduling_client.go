@@ -29,6 +29,8 @@ import (
 type SchedulingV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	DevicesGetter
+	ImagePrePullsGetter
+	NodeMaintenancesGetter
 	PodMigrationJobsGetter
 	ReservationsGetter
 }
@@ -42,6 +44,14 @@ func (c *SchedulingV1alpha1Client) Devices() DeviceInterface {
 	return newDevices(c)
 }
 
+func (c *SchedulingV1alpha1Client) ImagePrePulls() ImagePrePullInterface {
+	return newImagePrePulls(c)
+}
+
+func (c *SchedulingV1alpha1Client) NodeMaintenances() NodeMaintenanceInterface {
+	return newNodeMaintenances(c)
+}
+
 func (c *SchedulingV1alpha1Client) PodMigrationJobs() PodMigrationJobInterface {
 	return newPodMigrationJobs(c)
 }
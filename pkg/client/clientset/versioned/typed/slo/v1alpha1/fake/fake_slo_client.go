@@ -28,6 +28,14 @@ type FakeSloV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeSloV1alpha1) ClusterEvictionBudgets() v1alpha1.ClusterEvictionBudgetInterface {
+	return &FakeClusterEvictionBudgets{c}
+}
+
+func (c *FakeSloV1alpha1) ColocationStatuses() v1alpha1.ColocationStatusInterface {
+	return &FakeColocationStatuses{c}
+}
+
 func (c *FakeSloV1alpha1) NodeMetrics() v1alpha1.NodeMetricInterface {
 	return &FakeNodeMetrics{c}
 }
@@ -18,6 +18,10 @@ limitations under the License.
 
 package v1alpha1
 
+type ClusterEvictionBudgetExpansion interface{}
+
+type ColocationStatusExpansion interface{}
+
 type NodeMetricExpansion interface{}
 
 type NodeSLOExpansion interface{}
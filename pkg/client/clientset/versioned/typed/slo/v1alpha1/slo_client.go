@@ -28,6 +28,8 @@ import (
 
 type SloV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	ClusterEvictionBudgetsGetter
+	ColocationStatusesGetter
 	NodeMetricsGetter
 	NodeSLOsGetter
 }
@@ -37,6 +39,14 @@ type SloV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *SloV1alpha1Client) ClusterEvictionBudgets() ClusterEvictionBudgetInterface {
+	return newClusterEvictionBudgets(c)
+}
+
+func (c *SloV1alpha1Client) ColocationStatuses() ColocationStatusInterface {
+	return newColocationStatuses(c)
+}
+
 func (c *SloV1alpha1Client) NodeMetrics() NodeMetricInterface {
 	return newNodeMetrics(c)
 }
@@ -71,12 +71,20 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		// Group=scheduling, Version=v1alpha1
 	case schedulingv1alpha1.SchemeGroupVersion.WithResource("devices"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().Devices().Informer()}, nil
+	case schedulingv1alpha1.SchemeGroupVersion.WithResource("imageprepulls"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().ImagePrePulls().Informer()}, nil
+	case schedulingv1alpha1.SchemeGroupVersion.WithResource("nodemaintenances"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().NodeMaintenances().Informer()}, nil
 	case schedulingv1alpha1.SchemeGroupVersion.WithResource("podmigrationjobs"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().PodMigrationJobs().Informer()}, nil
 	case schedulingv1alpha1.SchemeGroupVersion.WithResource("reservations"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Scheduling().V1alpha1().Reservations().Informer()}, nil
 
 		// Group=slo, Version=v1alpha1
+	case slov1alpha1.SchemeGroupVersion.WithResource("clusterevictionbudgets"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Slo().V1alpha1().ClusterEvictionBudgets().Informer()}, nil
+	case slov1alpha1.SchemeGroupVersion.WithResource("colocationstatuses"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Slo().V1alpha1().ColocationStatuses().Informer()}, nil
 	case slov1alpha1.SchemeGroupVersion.WithResource("nodemetrics"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Slo().V1alpha1().NodeMetrics().Informer()}, nil
 	case slov1alpha1.SchemeGroupVersion.WithResource("nodeslos"):
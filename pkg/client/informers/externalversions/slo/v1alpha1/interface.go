@@ -24,6 +24,10 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterEvictionBudgets returns a ClusterEvictionBudgetInformer.
+	ClusterEvictionBudgets() ClusterEvictionBudgetInformer
+	// ColocationStatuses returns a ColocationStatusInformer.
+	ColocationStatuses() ColocationStatusInformer
 	// NodeMetrics returns a NodeMetricInformer.
 	NodeMetrics() NodeMetricInformer
 	// NodeSLOs returns a NodeSLOInformer.
@@ -41,6 +45,16 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterEvictionBudgets returns a ClusterEvictionBudgetInformer.
+func (v *version) ClusterEvictionBudgets() ClusterEvictionBudgetInformer {
+	return &clusterEvictionBudgetInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// ColocationStatuses returns a ColocationStatusInformer.
+func (v *version) ColocationStatuses() ColocationStatusInformer {
+	return &colocationStatusInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // NodeMetrics returns a NodeMetricInformer.
 func (v *version) NodeMetrics() NodeMetricInformer {
 	return &nodeMetricInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -22,6 +22,14 @@ package v1alpha1
 // DeviceLister.
 type DeviceListerExpansion interface{}
 
+// ImagePrePullListerExpansion allows custom methods to be added to
+// ImagePrePullLister.
+type ImagePrePullListerExpansion interface{}
+
+// NodeMaintenanceListerExpansion allows custom methods to be added to
+// NodeMaintenanceLister.
+type NodeMaintenanceListerExpansion interface{}
+
 // PodMigrationJobListerExpansion allows custom methods to be added to
 // PodMigrationJobLister.
 type PodMigrationJobListerExpansion interface{}
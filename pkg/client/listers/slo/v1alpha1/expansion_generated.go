@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// ClusterEvictionBudgetListerExpansion allows custom methods to be added to
+// ClusterEvictionBudgetLister.
+type ClusterEvictionBudgetListerExpansion interface{}
+
+// ColocationStatusListerExpansion allows custom methods to be added to
+// ColocationStatusLister.
+type ColocationStatusListerExpansion interface{}
+
 // NodeMetricListerExpansion allows custom methods to be added to
 // NodeMetricLister.
 type NodeMetricListerExpansion interface{}
@@ -92,6 +92,11 @@ type LowNodeLoadArgs struct {
 
 	// NodePools supports multiple different types of batch nodes to configure different strategies
 	NodePools []LowNodeLoadNodePool
+
+	// EnableUtilizationForecast skips the overutilized nodes whose usages forecasted by koord-manager are under
+	// HighThresholds, since their load spikes are expected to self-resolve. The forecasts are annotated on the
+	// NodeMetrics when the NodeUsageForecast feature of koord-manager is enabled.
+	EnableUtilizationForecast bool
 }
 
 type LowNodeLoadNodePool struct {
@@ -91,6 +91,12 @@ type LowNodeLoadArgs struct {
 
 	// NodePools supports multiple different types of batch nodes to configure different strategies
 	NodePools []LowNodeLoadNodePool `json:"nodePools,omitempty"`
+
+	// EnableUtilizationForecast skips the overutilized nodes whose usages forecasted by koord-manager are under
+	// HighThresholds, since their load spikes are expected to self-resolve. The forecasts are annotated on the
+	// NodeMetrics when the NodeUsageForecast feature of koord-manager is enabled.
+	// Default is false
+	EnableUtilizationForecast *bool `json:"enableUtilizationForecast,omitempty"`
 }
 
 type LowNodeLoadNodePool struct {
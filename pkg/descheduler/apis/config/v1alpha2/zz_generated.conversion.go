@@ -405,6 +405,9 @@ func autoConvert_v1alpha2_LowNodeLoadArgs_To_config_LowNodeLoadArgs(in *LowNodeL
 	} else {
 		out.NodePools = nil
 	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableUtilizationForecast, &out.EnableUtilizationForecast, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -454,6 +457,9 @@ func autoConvert_config_LowNodeLoadArgs_To_v1alpha2_LowNodeLoadArgs(in *config.L
 	} else {
 		out.NodePools = nil
 	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableUtilizationForecast, &out.EnableUtilizationForecast, s); err != nil {
+		return err
+	}
 	return nil
 }
 
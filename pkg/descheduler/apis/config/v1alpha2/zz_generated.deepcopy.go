@@ -268,6 +268,11 @@ func (in *LowNodeLoadArgs) DeepCopyInto(out *LowNodeLoadArgs) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.EnableUtilizationForecast != nil {
+		in, out := &in.EnableUtilizationForecast, &out.EnableUtilizationForecast
+		*out = new(bool)
+		**out = **in
+	}
 	return
 }
 
@@ -33,7 +33,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
-	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/sorter"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 const (
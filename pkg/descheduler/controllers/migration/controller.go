@@ -46,6 +46,9 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/arbitrator"
@@ -54,10 +57,14 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/reservation"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/util"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/nodemaintenance"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/options"
 	evictionsutil "github.com/koordinator-sh/koordinator/pkg/descheduler/evictions"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	"github.com/koordinator-sh/koordinator/pkg/util/evictionbudget"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 const (
@@ -87,6 +94,9 @@ type Reconciler struct {
 	limiterMap      map[deschedulerconfig.MigrationLimitObjectType]map[string]*rate.Limiter
 	limiterCacheMap map[deschedulerconfig.MigrationLimitObjectType]*gocache.Cache
 	limiterLock     sync.Mutex
+
+	// evictionBudgetAdmitter admits the evictions by the ClusterEvictionBudgets if it is set
+	evictionBudgetAdmitter evictionbudget.Admitter
 }
 
 func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
@@ -136,6 +146,12 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 	if err = c.Watch(source.Kind(options.Manager.GetCache(), r.reservationInterpreter.GetReservationType()), &handler.Funcs{}); err != nil {
 		return nil, err
 	}
+
+	if utilfeature.DefaultFeatureGate.Enabled(features.NodeMaintenance) {
+		if err = nodemaintenance.Add(options.Manager, controllerArgs, r.eventRecorder); err != nil {
+			return nil, err
+		}
+	}
 	return r, nil
 }
 
@@ -162,6 +178,23 @@ func newReconciler(args *deschedulerconfig.MigrationControllerArgs, handle frame
 		clock:                  clock.RealClock{},
 	}
 	r.initObjectLimiters()
+	if utilfeature.DefaultFeatureGate.Enabled(features.ClusterEvictionBudget) {
+		koordClientSet, ok := handle.(koordclientset.Interface)
+		if !ok {
+			kubeConfig := *handle.KubeConfig()
+			kubeConfig.ContentType = runtime.ContentTypeJSON
+			kubeConfig.AcceptContentTypes = runtime.ContentTypeJSON
+			koordClientSet, err = koordclientset.NewForConfig(&kubeConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
+		koordSharedInformerFactory := koordinformers.NewSharedInformerFactory(koordClientSet, 0)
+		budgetInformer := koordSharedInformerFactory.Slo().V1alpha1().ClusterEvictionBudgets()
+		r.evictionBudgetAdmitter = evictionbudget.NewAdmitter(koordClientSet, budgetInformer)
+		koordSharedInformerFactory.Start(context.TODO().Done())
+		koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+	}
 	if err := manager.Add(r); err != nil {
 		return nil, err
 	}
@@ -804,6 +837,10 @@ func (r *Reconciler) evictPod(ctx context.Context, job *sev1alpha1.PodMigrationJ
 		return false, reconcile.Result{}, err
 	}
 
+	if admitted, err := r.admitEvictionBudget(ctx, job, pod); !admitted {
+		return false, reconcile.Result{RequeueAfter: defaultRequeueAfter}, err
+	}
+
 	if job.Spec.DeleteOptions == nil {
 		job.Spec.DeleteOptions = r.args.DefaultDeleteOptions
 	}
@@ -828,6 +865,31 @@ func (r *Reconciler) evictPod(ctx context.Context, job *sev1alpha1.PodMigrationJ
 	return false, reconcile.Result{RequeueAfter: defaultRequeueAfter}, err
 }
 
+// admitEvictionBudget checks the eviction against the ClusterEvictionBudgets. The job waits until the budgets selecting
+// the node of the pod are available.
+func (r *Reconciler) admitEvictionBudget(ctx context.Context, job *sev1alpha1.PodMigrationJob, pod *corev1.Pod) (bool, error) {
+	if r.evictionBudgetAdmitter == nil {
+		return true, nil
+	}
+	var node *corev1.Node
+	if pod.Spec.NodeName != "" {
+		node = &corev1.Node{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			klog.Errorf("Failed to get node %q of Pod %q, MigrationJob: %s, err: %v", pod.Spec.NodeName, klog.KObj(pod), job.Name, err)
+			return false, err
+		}
+	}
+	admitted, reason, err := r.evictionBudgetAdmitter.Admit(ctx, pod, node, slov1alpha1.EvictionBudgetEvictorDescheduler)
+	if err != nil {
+		klog.Errorf("Failed to admit the eviction of Pod %q by the cluster eviction budgets, MigrationJob: %s, err: %v", klog.KObj(pod), job.Name, err)
+		return false, err
+	}
+	if !admitted {
+		klog.V(4).Infof("MigrationJob %s waits to evict Pod %q since %s", job.Name, klog.KObj(pod), reason)
+	}
+	return admitted, nil
+}
+
 func (r *Reconciler) prepareJobWithReservationScheduleSuccess(ctx context.Context, job *sev1alpha1.PodMigrationJob, reservationObj reservation.Object) error {
 	scheduledNodeName := reservationObj.GetScheduledNodeName()
 	if scheduledNodeName == "" || job.Status.NodeName != "" {
@@ -42,6 +42,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/v1alpha2"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/controllerfinder"
@@ -579,6 +580,65 @@ func TestEvictPod(t *testing.T) {
 	assert.Equal(t, expectCond, cond)
 }
 
+type fakeEvictionBudgetAdmitter struct {
+	admitted bool
+	evictor  string
+}
+
+func (f *fakeEvictionBudgetAdmitter) Admit(ctx context.Context, pod *corev1.Pod, node *corev1.Node, evictor string) (bool, string, error) {
+	f.evictor = evictor
+	if !f.admitted {
+		return false, "budget is exhausted", nil
+	}
+	return true, "", nil
+}
+
+func TestEvictPodWithEvictionBudget(t *testing.T) {
+	reconciler := newTestReconciler()
+	reconciler.evictorInterpreter = fakeEvictionInterpreter{}
+	admitter := &fakeEvictionBudgetAdmitter{}
+	reconciler.evictionBudgetAdmitter = admitter
+
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test",
+			CreationTimestamp: metav1.Time{Time: time.Now()},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: "default",
+				Name:      "test-pod",
+			},
+		},
+	}
+	assert.Nil(t, reconciler.Create(context.TODO(), job))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+		},
+	}
+	assert.Nil(t, reconciler.Client.Create(context.TODO(), pod))
+
+	// the job waits for the budget
+	evicted, result, err := reconciler.evictPod(context.TODO(), job)
+	assert.False(t, evicted)
+	assert.Equal(t, reconcile.Result{RequeueAfter: defaultRequeueAfter}, result)
+	assert.Nil(t, err)
+	assert.Equal(t, slov1alpha1.EvictionBudgetEvictorDescheduler, admitter.evictor)
+	_, cond := util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+	assert.Nil(t, cond)
+
+	admitter.admitted = true
+	evicted, result, err = reconciler.evictPod(context.TODO(), job)
+	assert.False(t, evicted)
+	assert.Equal(t, reconcile.Result{RequeueAfter: defaultRequeueAfter}, result)
+	assert.Nil(t, err)
+	_, cond = util.GetCondition(&job.Status, sev1alpha1.PodMigrationJobConditionEviction)
+	assert.NotNil(t, cond)
+	assert.Equal(t, sev1alpha1.PodMigrationJobReasonEvicting, cond.Reason)
+}
+
 func TestDeleteReservation(t *testing.T) {
 	reconciler := newTestReconciler()
 	assert.Nil(t, reconciler.deleteReservation(context.TODO(), &sev1alpha1.PodMigrationJob{}))
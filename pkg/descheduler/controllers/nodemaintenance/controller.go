@@ -0,0 +1,430 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/migration/evictor"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/controllers/names"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/fieldindex"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils"
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+)
+
+const (
+	Name                           = names.NodeMaintenanceController
+	defaultRequeueAfter            = 10 * time.Second
+	defaultMaxConcurrentMigrations = 1
+
+	evictReason = "node maintenance"
+)
+
+type Reconciler struct {
+	client.Client
+	args          *deschedulerconfig.MigrationControllerArgs
+	eventRecorder events.EventRecorder
+	clock         clock.Clock
+}
+
+// Add creates the NodeMaintenance controller with the manager. The controller drains the node by creating the
+// PodMigrationJobs, so the pods are migrated by the MigrationController, e.g. reserving the resources on the other
+// nodes before evicting since the maintained node is cordoned.
+func Add(mgr manager.Manager, args *deschedulerconfig.MigrationControllerArgs, eventRecorder events.EventRecorder) error {
+	r := &Reconciler{
+		Client:        mgr.GetClient(),
+		args:          args,
+		eventRecorder: eventRecorder,
+		clock:         clock.RealClock{},
+	}
+	c, err := controller.New(Name, mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: 1})
+	if err != nil {
+		return err
+	}
+	if err = c.Watch(source.Kind(mgr.GetCache(), &sev1alpha1.NodeMaintenance{}), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	return c.Watch(source.Kind(mgr.GetCache(), &sev1alpha1.PodMigrationJob{}),
+		handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &sev1alpha1.NodeMaintenance{}, handler.OnlyControllerOwner()))
+}
+
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodemaintenances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=nodemaintenances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+
+// Reconcile cordons the node of the NodeMaintenance and migrates the pods on the node in the priority order.
+func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	nm := &sev1alpha1.NodeMaintenance{}
+	err := r.Client.Get(ctx, request.NamespacedName, nm)
+	if errors.IsNotFound(err) {
+		return reconcile.Result{}, nil
+	}
+	if err != nil {
+		klog.Errorf("Failed to Get NodeMaintenance from %v, err: %v", request, err)
+		return reconcile.Result{}, err
+	}
+	if nm.DeletionTimestamp != nil || isMaintenanceFinished(nm) {
+		return reconcile.Result{}, nil
+	}
+
+	result, err := r.doMaintain(ctx, nm)
+	if err != nil {
+		klog.Errorf("Failed to reconcile NodeMaintenance %v, err: %v", request.Name, err)
+	}
+	return result, err
+}
+
+func (r *Reconciler) doMaintain(ctx context.Context, nm *sev1alpha1.NodeMaintenance) (reconcile.Result, error) {
+	klog.V(4).Infof("begin process NodeMaintenance %s, node %s", nm.Name, nm.Spec.NodeName)
+	newStatus := nm.Status.DeepCopy()
+
+	node := &corev1.Node{}
+	err := r.Client.Get(ctx, types.NamespacedName{Name: nm.Spec.NodeName}, node)
+	if errors.IsNotFound(err) {
+		newStatus.Phase = sev1alpha1.NodeMaintenanceFailed
+		newStatus.Message = fmt.Sprintf("node %s is not found", nm.Spec.NodeName)
+		newStatus.CompletionTime = &metav1.Time{Time: r.clock.Now()}
+		return reconcile.Result{}, r.updateStatus(ctx, nm, newStatus)
+	}
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err = r.cordonNode(ctx, nm, node); err != nil {
+		return reconcile.Result{}, err
+	}
+	if newStatus.StartTime == nil {
+		newStatus.StartTime = &metav1.Time{Time: r.clock.Now()}
+	}
+
+	pods, err := r.getPodsToMigrate(ctx, node.Name)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	jobList := &sev1alpha1.PodMigrationJobList{}
+	if err = r.Client.List(ctx, jobList, client.MatchingLabels{sev1alpha1.LabelNodeMaintenanceName: nm.Name}, utilclient.DisableDeepCopy); err != nil {
+		return reconcile.Result{}, err
+	}
+	jobs := map[types.UID]*sev1alpha1.PodMigrationJob{}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if job.Spec.PodRef == nil {
+			continue
+		}
+		if old, ok := jobs[job.Spec.PodRef.UID]; ok && isJobFinished(job) && !isJobFinished(old) {
+			continue
+		}
+		jobs[job.Spec.PodRef.UID] = job
+	}
+
+	podStatuses, err := r.syncMigrations(ctx, nm, pods, jobs)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// the pods gone from the node since the last reconciliation are migrated
+	remaining := map[types.UID]bool{}
+	for _, pod := range pods {
+		remaining[pod.UID] = true
+	}
+	for _, podStatus := range nm.Status.Pods {
+		if podStatus.PodRef != nil && !remaining[podStatus.PodRef.UID] {
+			newStatus.MigratedPods++
+		}
+	}
+	newStatus.Pods = podStatuses
+	newStatus.TotalPods = newStatus.MigratedPods + int32(len(pods))
+	newStatus.MigratingPods, newStatus.FailedPods = 0, 0
+	for _, podStatus := range podStatuses {
+		switch podStatus.Phase {
+		case sev1alpha1.NodeMaintenancePodMigrating:
+			newStatus.MigratingPods++
+		case sev1alpha1.NodeMaintenancePodFailed:
+			newStatus.FailedPods++
+		}
+	}
+
+	result := reconcile.Result{RequeueAfter: defaultRequeueAfter}
+	switch {
+	case len(podStatuses) == 0:
+		newStatus.Phase = sev1alpha1.NodeMaintenanceSucceeded
+		newStatus.Message = fmt.Sprintf("all %d pods are migrated", newStatus.MigratedPods)
+		newStatus.CompletionTime = &metav1.Time{Time: r.clock.Now()}
+		result = reconcile.Result{}
+	case int(newStatus.FailedPods) == len(podStatuses):
+		newStatus.Phase = sev1alpha1.NodeMaintenanceFailed
+		newStatus.Message = fmt.Sprintf("%d pods failed to migrate", newStatus.FailedPods)
+		newStatus.CompletionTime = &metav1.Time{Time: r.clock.Now()}
+		result = reconcile.Result{}
+	default:
+		newStatus.Phase = sev1alpha1.NodeMaintenanceRunning
+		newStatus.Message = fmt.Sprintf("%d pods are migrating, %d pods are remaining", newStatus.MigratingPods, len(podStatuses))
+	}
+	if err = r.updateStatus(ctx, nm, newStatus); err != nil {
+		return reconcile.Result{}, err
+	}
+	if isMaintenanceFinished(nm) {
+		r.eventRecorder.Eventf(nm, nil, corev1.EventTypeNormal, string(nm.Status.Phase), "Maintaining", nm.Status.Message)
+	}
+	return result, nil
+}
+
+func (r *Reconciler) cordonNode(ctx context.Context, nm *sev1alpha1.NodeMaintenance, node *corev1.Node) error {
+	if node.Spec.Unschedulable {
+		return nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = true
+	if err := r.Client.Patch(ctx, node, patch); err != nil {
+		klog.Errorf("Failed to cordon node %s for NodeMaintenance %s, err: %v", node.Name, nm.Name, err)
+		return err
+	}
+	r.eventRecorder.Eventf(nm, nil, corev1.EventTypeNormal, "Cordoned", "Maintaining", "node %s is cordoned", node.Name)
+	return nil
+}
+
+// getPodsToMigrate returns the pods on the node except the DaemonSet pods, the static pods and the terminated pods.
+// The pods are ordered from high priority to low priority, so that the important pods take the resources first.
+func (r *Reconciler) getPodsToMigrate(ctx context.Context, nodeName string) ([]*corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.MatchingFields{fieldindex.IndexPodByNodeName: nodeName}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	var pods []*corev1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if utils.IsMirrorPod(pod) || utils.IsStaticPod(pod) || utils.IsDaemonsetPod(pod.OwnerReferences) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		pi, pj := getPodPriority(pods[i]), getPodPriority(pods[j])
+		if pi != pj {
+			return pi > pj
+		}
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// syncMigrations returns the migration statuses of the pods and creates the PodMigrationJobs for the pending pods
+// if the concurrency and the PodDisruptionBudgets allow.
+func (r *Reconciler) syncMigrations(ctx context.Context, nm *sev1alpha1.NodeMaintenance, pods []*corev1.Pod, jobs map[types.UID]*sev1alpha1.PodMigrationJob) ([]sev1alpha1.NodeMaintenancePodStatus, error) {
+	podStatuses := make([]sev1alpha1.NodeMaintenancePodStatus, 0, len(pods))
+	var migratingPods []*corev1.Pod
+	for _, pod := range pods {
+		podStatus := sev1alpha1.NodeMaintenancePodStatus{
+			PodRef: &corev1.ObjectReference{Namespace: pod.Namespace, Name: pod.Name, UID: pod.UID},
+			Phase:  sev1alpha1.NodeMaintenancePodPending,
+		}
+		job := jobs[pod.UID]
+		if job != nil {
+			podStatus.JobName = job.Name
+			podStatus.Phase = sev1alpha1.NodeMaintenancePodMigrating
+			podStatus.Message = job.Status.Message
+			if job.Status.Phase == sev1alpha1.PodMigrationJobFailed || job.Status.Phase == sev1alpha1.PodMigrationJobAborted {
+				podStatus.Phase = sev1alpha1.NodeMaintenancePodFailed
+			}
+		} else if utils.IsPodTerminating(pod) {
+			podStatus.Phase = sev1alpha1.NodeMaintenancePodMigrating
+			podStatus.Message = "pod is terminating"
+		}
+		if podStatus.Phase == sev1alpha1.NodeMaintenancePodMigrating {
+			migratingPods = append(migratingPods, pod)
+		}
+		podStatuses = append(podStatuses, podStatus)
+	}
+	if nm.Spec.Paused {
+		return podStatuses, nil
+	}
+
+	maxConcurrent := defaultMaxConcurrentMigrations
+	if nm.Spec.MaxConcurrentMigrations != nil && *nm.Spec.MaxConcurrentMigrations > 0 {
+		maxConcurrent = int(*nm.Spec.MaxConcurrentMigrations)
+	}
+	budget := maxConcurrent - len(migratingPods)
+	pdbs := map[string][]*policyv1.PodDisruptionBudget{}
+	for i, pod := range pods {
+		if budget <= 0 {
+			break
+		}
+		podStatus := &podStatuses[i]
+		if podStatus.Phase != sev1alpha1.NodeMaintenancePodPending {
+			continue
+		}
+		if _, ok := pdbs[pod.Namespace]; !ok {
+			pdbList := &policyv1.PodDisruptionBudgetList{}
+			if err := r.Client.List(ctx, pdbList, client.InNamespace(pod.Namespace)); err != nil {
+				return nil, err
+			}
+			pdbs[pod.Namespace] = make([]*policyv1.PodDisruptionBudget, 0, len(pdbList.Items))
+			for j := range pdbList.Items {
+				pdbs[pod.Namespace] = append(pdbs[pod.Namespace], &pdbList.Items[j])
+			}
+		}
+		if pdb := getBlockingPDB(pod, pdbs[pod.Namespace], migratingPods); pdb != nil {
+			podStatus.Phase = sev1alpha1.NodeMaintenancePodBlocked
+			podStatus.Message = fmt.Sprintf("blocked by PodDisruptionBudget %s", pdb.Name)
+			continue
+		}
+
+		job, err := r.createMigrationJob(ctx, nm, pod)
+		if err != nil {
+			return nil, err
+		}
+		podStatus.Phase = sev1alpha1.NodeMaintenancePodMigrating
+		podStatus.JobName = job.Name
+		podStatus.Message = ""
+		migratingPods = append(migratingPods, pod)
+		budget--
+	}
+	return podStatuses, nil
+}
+
+func (r *Reconciler) createMigrationJob(ctx context.Context, nm *sev1alpha1.NodeMaintenance, pod *corev1.Pod) (*sev1alpha1.PodMigrationJob, error) {
+	mode := nm.Spec.Mode
+	if mode == "" {
+		mode = sev1alpha1.PodMigrationJobModeReservationFirst
+	}
+	ttl := nm.Spec.JobTTL
+	if ttl == nil {
+		ttl = &r.args.DefaultJobTTL
+	}
+	deleteOptions := nm.Spec.DeleteOptions
+	if deleteOptions == nil {
+		deleteOptions = r.args.DefaultDeleteOptions
+	}
+	job := &sev1alpha1.PodMigrationJob{
+		ObjectMeta: metav1.ObjectMeta{
+			// the name is generated by the pod uid to avoid migrating the same pod repeatedly
+			Name: fmt.Sprintf("%s-%s", nm.Name, pod.UID),
+			Labels: map[string]string{
+				sev1alpha1.LabelNodeMaintenanceName: nm.Name,
+			},
+			Annotations: map[string]string{
+				evictor.AnnotationEvictReason:  evictReason,
+				evictor.AnnotationEvictTrigger: Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(nm, sev1alpha1.SchemeGroupVersion.WithKind("NodeMaintenance")),
+			},
+		},
+		Spec: sev1alpha1.PodMigrationJobSpec{
+			PodRef: &corev1.ObjectReference{
+				Namespace: pod.Namespace,
+				Name:      pod.Name,
+				UID:       pod.UID,
+			},
+			Mode:          mode,
+			TTL:           ttl.DeepCopy(),
+			DeleteOptions: deleteOptions.DeepCopy(),
+		},
+		Status: sev1alpha1.PodMigrationJobStatus{
+			Phase: sev1alpha1.PodMigrationJobPending,
+		},
+	}
+	err := r.Client.Create(ctx, job)
+	if err != nil && !errors.IsAlreadyExists(err) {
+		klog.Errorf("Failed to create PodMigrationJob for Pod %s/%s, NodeMaintenance %s, err: %v", pod.Namespace, pod.Name, nm.Name, err)
+		return nil, err
+	}
+	klog.V(4).Infof("NodeMaintenance %s creates PodMigrationJob %s for Pod %s/%s", nm.Name, job.Name, pod.Namespace, pod.Name)
+	return job, nil
+}
+
+func (r *Reconciler) updateStatus(ctx context.Context, nm *sev1alpha1.NodeMaintenance, newStatus *sev1alpha1.NodeMaintenanceStatus) error {
+	if apiequality.Semantic.DeepEqual(&nm.Status, newStatus) {
+		return nil
+	}
+	nm.Status = *newStatus
+	if err := r.Client.Status().Update(ctx, nm); err != nil {
+		klog.Errorf("Failed to update NodeMaintenance %s status, err: %v", nm.Name, err)
+		return err
+	}
+	return nil
+}
+
+// getBlockingPDB returns the PodDisruptionBudget which disallows the pod to migrate. The migrating pods are
+// subtracted from the allowed disruptions since they may be still available before evicted.
+func getBlockingPDB(pod *corev1.Pod, pdbs []*policyv1.PodDisruptionBudget, migratingPods []*corev1.Pod) *policyv1.PodDisruptionBudget {
+	for _, pdb := range pdbs {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		allowed := pdb.Status.DisruptionsAllowed
+		for _, migratingPod := range migratingPods {
+			if migratingPod.Namespace == pdb.Namespace && selector.Matches(labels.Set(migratingPod.Labels)) {
+				allowed--
+			}
+		}
+		if allowed <= 0 {
+			return pdb
+		}
+	}
+	return nil
+}
+
+func getPodPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
+}
+
+func isMaintenanceFinished(nm *sev1alpha1.NodeMaintenance) bool {
+	return nm.Status.Phase == sev1alpha1.NodeMaintenanceSucceeded || nm.Status.Phase == sev1alpha1.NodeMaintenanceFailed
+}
+
+func isJobFinished(job *sev1alpha1.PodMigrationJob) bool {
+	return job.Status.Phase == sev1alpha1.PodMigrationJobSucceeded ||
+		job.Status.Phase == sev1alpha1.PodMigrationJobFailed ||
+		job.Status.Phase == sev1alpha1.PodMigrationJobAborted
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemaintenance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	sev1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	deschedulerconfig "github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/apis/config/v1alpha2"
+	"github.com/koordinator-sh/koordinator/pkg/descheduler/fieldindex"
+)
+
+func newTestReconciler(objs ...client.Object) *Reconciler {
+	scheme := runtime.NewScheme()
+	_ = sev1alpha1.AddToScheme(scheme)
+	_ = clientgoscheme.AddToScheme(scheme)
+
+	var v1beta2args v1alpha2.MigrationControllerArgs
+	v1alpha2.SetDefaults_MigrationControllerArgs(&v1beta2args)
+	var args deschedulerconfig.MigrationControllerArgs
+	err := v1alpha2.Convert_v1alpha2_MigrationControllerArgs_To_config_MigrationControllerArgs(&v1beta2args, &args, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	runtimeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&sev1alpha1.NodeMaintenance{}, &sev1alpha1.PodMigrationJob{}).
+		WithIndex(&corev1.Pod{}, fieldindex.IndexPodByNodeName, func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithObjects(objs...).Build()
+	eventBroadcaster := record.NewBroadcaster()
+	recorder := eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: Name})
+	return &Reconciler{
+		Client:        runtimeClient,
+		args:          &args,
+		eventRecorder: record.NewEventRecorderAdapter(recorder),
+		clock:         clock.RealClock{},
+	}
+}
+
+func newTestPod(name string, priority int32, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels:    labels,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+			Priority: pointer.Int32(priority),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	nm := &sev1alpha1.NodeMaintenance{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", UID: "test-uid"},
+		Spec: sev1alpha1.NodeMaintenanceSpec{
+			NodeName:                "test-node",
+			MaxConcurrentMigrations: pointer.Int32(2),
+		},
+	}
+	daemonSetPod := newTestPod("daemonset-pod", 9999, nil)
+	daemonSetPod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", UID: "ds"}}
+	completedPod := newTestPod("completed-pod", 9999, nil)
+	completedPod.Status.Phase = corev1.PodSucceeded
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pdb"},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+	}
+	r := newTestReconciler(node, nm, pdb, daemonSetPod, completedPod,
+		newTestPod("low-pod", 0, nil),
+		newTestPod("web-pod-1", 1000, map[string]string{"app": "web"}),
+		newTestPod("web-pod-2", 100, map[string]string{"app": "web"}))
+	ctx := context.TODO()
+
+	result, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: nm.Name}})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultRequeueAfter, result.RequeueAfter)
+
+	gotNode := &corev1.Node{}
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: node.Name}, gotNode))
+	assert.True(t, gotNode.Spec.Unschedulable)
+
+	// web-pod-2 is blocked by the pdb, and the low-pod migrates after it as the concurrency allows
+	got := &sev1alpha1.NodeMaintenance{}
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: nm.Name}, got))
+	assert.Equal(t, sev1alpha1.NodeMaintenanceRunning, got.Status.Phase)
+	assert.NotNil(t, got.Status.StartTime)
+	assert.Equal(t, int32(3), got.Status.TotalPods)
+	assert.Equal(t, int32(2), got.Status.MigratingPods)
+	assert.Len(t, got.Status.Pods, 3)
+	assert.Equal(t, "web-pod-1", got.Status.Pods[0].PodRef.Name)
+	assert.Equal(t, sev1alpha1.NodeMaintenancePodMigrating, got.Status.Pods[0].Phase)
+	assert.Equal(t, "test-web-pod-1", got.Status.Pods[0].JobName)
+	assert.Equal(t, "web-pod-2", got.Status.Pods[1].PodRef.Name)
+	assert.Equal(t, sev1alpha1.NodeMaintenancePodBlocked, got.Status.Pods[1].Phase)
+	assert.Equal(t, "low-pod", got.Status.Pods[2].PodRef.Name)
+	assert.Equal(t, sev1alpha1.NodeMaintenancePodMigrating, got.Status.Pods[2].Phase)
+
+	job := &sev1alpha1.PodMigrationJob{}
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: "test-web-pod-1"}, job))
+	assert.Equal(t, sev1alpha1.PodMigrationJobModeReservationFirst, job.Spec.Mode)
+	assert.Equal(t, "web-pod-1", job.Spec.PodRef.Name)
+	assert.Equal(t, nm.Name, job.Labels[sev1alpha1.LabelNodeMaintenanceName])
+	assert.Equal(t, nm.UID, job.OwnerReferences[0].UID)
+
+	// web-pod-1 is migrated and the job of low-pod fails
+	assert.NoError(t, r.Delete(ctx, newTestPod("web-pod-1", 1000, nil)))
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: "test-low-pod"}, job))
+	job.Status.Phase = sev1alpha1.PodMigrationJobFailed
+	job.Status.Message = "failed to schedule reservation"
+	assert.NoError(t, r.Status().Update(ctx, job))
+
+	_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: nm.Name}})
+	assert.NoError(t, err)
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: nm.Name}, got))
+	assert.Equal(t, sev1alpha1.NodeMaintenanceRunning, got.Status.Phase)
+	assert.Equal(t, int32(3), got.Status.TotalPods)
+	assert.Equal(t, int32(1), got.Status.MigratedPods)
+	assert.Equal(t, int32(1), got.Status.MigratingPods)
+	assert.Equal(t, int32(1), got.Status.FailedPods)
+	assert.Len(t, got.Status.Pods, 2)
+	assert.Equal(t, sev1alpha1.NodeMaintenancePodMigrating, got.Status.Pods[0].Phase)
+	assert.Equal(t, sev1alpha1.NodeMaintenancePodFailed, got.Status.Pods[1].Phase)
+	assert.Equal(t, "failed to schedule reservation", got.Status.Pods[1].Message)
+
+	// all pods leave the node
+	assert.NoError(t, r.Delete(ctx, newTestPod("web-pod-2", 100, nil)))
+	assert.NoError(t, r.Delete(ctx, newTestPod("low-pod", 0, nil)))
+	result, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: nm.Name}})
+	assert.NoError(t, err)
+	assert.True(t, result.IsZero())
+	assert.NoError(t, r.Get(ctx, types.NamespacedName{Name: nm.Name}, got))
+	assert.Equal(t, sev1alpha1.NodeMaintenanceSucceeded, got.Status.Phase)
+	assert.Equal(t, int32(3), got.Status.MigratedPods)
+	assert.NotNil(t, got.Status.CompletionTime)
+}
+
+func TestReconcilePausedOrFailed(t *testing.T) {
+	t.Run("paused", func(t *testing.T) {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+		nm := &sev1alpha1.NodeMaintenance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       sev1alpha1.NodeMaintenanceSpec{NodeName: "test-node", Paused: true},
+		}
+		r := newTestReconciler(node, nm, newTestPod("test-pod", 0, nil))
+		_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: nm.Name}})
+		assert.NoError(t, err)
+
+		got := &sev1alpha1.NodeMaintenance{}
+		assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: nm.Name}, got))
+		assert.Equal(t, sev1alpha1.NodeMaintenanceRunning, got.Status.Phase)
+		assert.Equal(t, sev1alpha1.NodeMaintenancePodPending, got.Status.Pods[0].Phase)
+		jobList := &sev1alpha1.PodMigrationJobList{}
+		assert.NoError(t, r.List(context.TODO(), jobList))
+		assert.Len(t, jobList.Items, 0)
+	})
+
+	t.Run("node not found", func(t *testing.T) {
+		nm := &sev1alpha1.NodeMaintenance{
+			ObjectMeta: metav1.ObjectMeta{Name: "test"},
+			Spec:       sev1alpha1.NodeMaintenanceSpec{NodeName: "test-node"},
+		}
+		r := newTestReconciler(nm)
+		result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: nm.Name}})
+		assert.NoError(t, err)
+		assert.True(t, result.IsZero())
+
+		got := &sev1alpha1.NodeMaintenance{}
+		assert.NoError(t, r.Get(context.TODO(), types.NamespacedName{Name: nm.Name}, got))
+		assert.Equal(t, sev1alpha1.NodeMaintenanceFailed, got.Status.Phase)
+		assert.NotNil(t, got.Status.CompletionTime)
+	})
+}
@@ -39,6 +39,7 @@ import (
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 const (
@@ -333,6 +334,12 @@ func NewEvictorFilter(
 
 // Filter decides when a pod is evictable
 func (ef *EvictorFilter) Filter(pod *corev1.Pod) bool {
+	// the registered victim rules cannot be overridden by the evict annotation
+	if err := sorter.CheckVictim(pod); err != nil {
+		klog.V(4).InfoS("Pod is refused by the victim rules", "pod", klog.KObj(pod), "reason", err.Error())
+		return false
+	}
+
 	var checkErrs []error
 
 	ownerRefList := podutil.OwnerRef(pod)
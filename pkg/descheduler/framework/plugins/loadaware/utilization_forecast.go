@@ -0,0 +1,74 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// getForecastedUsage returns the usage forecasted by koord-manager, where the resources not forecasted are the
+// current usage. It returns false if the forecast is not published or outdated.
+func getForecastedUsage(nodeUsage *NodeUsage, now time.Time, prod bool) (map[corev1.ResourceName]*resource.Quantity, bool) {
+	forecast := nodeUsage.forecast
+	if forecast == nil || !forecast.ForecastTime.After(now) {
+		return nil, false
+	}
+	forecastedList, usage := forecast.NodeUsage, nodeUsage.usage
+	if prod {
+		forecastedList, usage = forecast.ProdUsage, nodeUsage.prodUsage
+	}
+	if len(forecastedList) <= 0 {
+		return nil, false
+	}
+	forecasted := make(map[corev1.ResourceName]*resource.Quantity, len(usage))
+	for resourceName, quantity := range usage {
+		if q, ok := forecastedList[resourceName]; ok {
+			forecasted[resourceName] = &q
+		} else {
+			forecasted[resourceName] = quantity
+		}
+	}
+	return forecasted, true
+}
+
+// filterForecastedOverutilizedNodes drops the overutilized nodes whose forecasted usage is under the high thresholds,
+// since their load spikes are expected to self-resolve within the horizon. The nodes without the forecasts are kept.
+func filterForecastedOverutilizedNodes(sourceNodes []NodeInfo, now time.Time, prod bool) []NodeInfo {
+	var nodes []NodeInfo
+	for _, v := range sourceNodes {
+		forecasted, ok := getForecastedUsage(v.NodeUsage, now, prod)
+		if !ok {
+			nodes = append(nodes, v)
+			continue
+		}
+		thresholds := v.thresholds.highResourceThreshold
+		if prod {
+			thresholds = v.thresholds.prodHighResourceThreshold
+		}
+		if _, overutilized := isNodeOverutilized(forecasted, thresholds); overutilized {
+			nodes = append(nodes, v)
+			continue
+		}
+		klog.V(4).InfoS("Node is overutilized but its forecasted usage is under the high thresholds, skip it",
+			"node", klog.KObj(v.node), "prod", prod, "forecastedUsage", forecasted, "highThresholds", thresholds)
+	}
+	return nodes
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func cpuUsage(milliCPU int64) map[corev1.ResourceName]*resource.Quantity {
+	return map[corev1.ResourceName]*resource.Quantity{
+		corev1.ResourceCPU:  resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+		corev1.ResourcePods: resource.NewQuantity(10, resource.DecimalSI),
+	}
+}
+
+func cpuList(milliCPU int64) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+	}
+}
+
+func TestFilterForecastedOverutilizedNodes(t *testing.T) {
+	now := time.Now()
+	thresholds := NodeThresholds{
+		highResourceThreshold:     cpuUsage(8000),
+		prodHighResourceThreshold: cpuUsage(5000),
+	}
+	newNodeInfo := func(name string, forecast *extension.NodeUsageForecast) NodeInfo {
+		return NodeInfo{
+			NodeUsage: &NodeUsage{
+				node:      &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}},
+				usage:     cpuUsage(9000),
+				prodUsage: cpuUsage(6000),
+				forecast:  forecast,
+			},
+			thresholds: thresholds,
+		}
+	}
+	forecastTime := metav1.NewTime(now.Add(5 * time.Minute))
+
+	sourceNodes := []NodeInfo{
+		// rising
+		newNodeInfo("node-1", &extension.NodeUsageForecast{ForecastTime: forecastTime, NodeUsage: cpuList(11000), ProdUsage: cpuList(7000)}),
+		// transient spike of the batch pods
+		newNodeInfo("node-2", &extension.NodeUsageForecast{ForecastTime: forecastTime, NodeUsage: cpuList(7000), ProdUsage: cpuList(5500)}),
+		// falling
+		newNodeInfo("node-3", &extension.NodeUsageForecast{ForecastTime: forecastTime, NodeUsage: cpuList(6000), ProdUsage: cpuList(3000)}),
+		// outdated forecast
+		newNodeInfo("node-4", &extension.NodeUsageForecast{ForecastTime: metav1.NewTime(now.Add(-time.Minute)), NodeUsage: cpuList(6000)}),
+		// no forecast
+		newNodeInfo("node-5", nil),
+	}
+	var got []string
+	for _, v := range filterForecastedOverutilizedNodes(sourceNodes, now, false) {
+		got = append(got, v.node.Name)
+	}
+	assert.Equal(t, []string{"node-1", "node-4", "node-5"}, got)
+
+	// node-2 is still above the prod high thresholds, and node-4 has no prod forecast
+	got = nil
+	for _, v := range filterForecastedOverutilizedNodes(sourceNodes, now, true) {
+		got = append(got, v.node.Name)
+	}
+	assert.Equal(t, []string{"node-1", "node-2", "node-4", "node-5"}, got)
+}
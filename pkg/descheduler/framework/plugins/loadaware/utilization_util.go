@@ -36,7 +36,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/descheduler/framework"
 	nodeutil "github.com/koordinator-sh/koordinator/pkg/descheduler/node"
 	podutil "github.com/koordinator-sh/koordinator/pkg/descheduler/pod"
-	"github.com/koordinator-sh/koordinator/pkg/descheduler/utils/sorter"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 type Percentage = deschedulerconfig.Percentage
@@ -49,6 +49,8 @@ type NodeUsage struct {
 	usage      map[corev1.ResourceName]*resource.Quantity
 	prodUsage  map[corev1.ResourceName]*resource.Quantity
 	podMetrics map[types.NamespacedName]*slov1alpha1.ResourceMap
+	// forecast is the usage forecasted by koord-manager, which is nil if not published
+	forecast *extension.NodeUsageForecast
 }
 
 type NodeThresholds struct {
@@ -206,6 +208,11 @@ func getNodeUsage(nodes []*corev1.Node, resourceNames []corev1.ResourceName, nod
 			podMetrics[types.NamespacedName{Namespace: podMetric.Namespace, Name: podMetric.Name}] = podMetric.PodUsage.DeepCopy()
 		}
 
+		forecast, err := extension.GetNodeUsageForecast(nodeMetric.Annotations)
+		if err != nil {
+			klog.V(4).ErrorS(err, "Failed to parse the usage forecast of NodeMetric", "node", klog.KObj(v))
+		}
+
 		nodeUsages[v.Name] = &NodeUsage{
 			node:       v,
 			allPods:    pods,
@@ -213,6 +220,7 @@ func getNodeUsage(nodes []*corev1.Node, resourceNames []corev1.ResourceName, nod
 			prodUsage:  prodUsage,
 			prodPods:   prodPods,
 			podMetrics: podMetrics,
+			forecast:   forecast,
 		}
 	}
 
@@ -45,6 +45,12 @@ const (
 	// ConfigMapValidatingWebhook enables validating webhook for configmap Creation or updates
 	ConfigMapValidatingWebhook featuregate.Feature = "ConfigMapValidatingWebhook"
 
+	// NodeSLOValidatingWebhook enables validating webhook for NodeSLO Creation or updates
+	NodeSLOValidatingWebhook featuregate.Feature = "NodeSLOValidatingWebhook"
+
+	// DeviceValidatingWebhook enables validating webhook for Device Creation or updates
+	DeviceValidatingWebhook featuregate.Feature = "DeviceValidatingWebhook"
+
 	// ColocationProfileSkipMutatingResources config whether to update resourceName according to priority by default
 	ColocationProfileSkipMutatingResources featuregate.Feature = "ColocationProfileSkipMutatingResources"
 
@@ -84,6 +90,43 @@ const (
 
 	// Enable sync GPU shared resource from Device CRD
 	EnableSyncGPUSharedResource featuregate.Feature = "EnableSyncGPUSharedResource"
+
+	// NodeMetricsAPIProvider enables serving the metrics.k8s.io API with the usages from NodeMetric.
+	NodeMetricsAPIProvider featuregate.Feature = "NodeMetricsAPIProvider"
+
+	// QuotaUsageAggregation enables serving the real usages of the quota trees aggregated from NodeMetric.
+	QuotaUsageAggregation featuregate.Feature = "QuotaUsageAggregation"
+
+	// GPUJobProfile enables learning the GPU utilization profiles of the workloads from NodeMetric, and annotating
+	// the new pods with the predicted profiles.
+	GPUJobProfile featuregate.Feature = "GPUJobProfile"
+
+	// ColocationStatus enables aggregating the colocation statuses of the cluster and the node pools from NodeMetric.
+	ColocationStatus featuregate.Feature = "ColocationStatus"
+
+	// NodeMetricReportServer enables receiving the node metric reports streamed from the koordlets, and writing the
+	// aggregated summaries into NodeMetric.
+	NodeMetricReportServer featuregate.Feature = "NodeMetricReportServer"
+
+	// CacheDomainTopologySpread enables injecting the topology spread constraints over the cache domains for the pods
+	// opting in by the annotation.
+	CacheDomainTopologySpread featuregate.Feature = "CacheDomainTopologySpread"
+
+	// ClusterEvictionBudget enables maintaining the ClusterEvictionBudgets in koord-manager, and admitting the
+	// evictions of koord-descheduler by the budgets.
+	ClusterEvictionBudget featuregate.Feature = "ClusterEvictionBudget"
+
+	// CPUAllocationHint enables recommending the CPU allocation policies (LSR with exclusive cpus or LS) of the LS
+	// workloads from the usages in NodeMetric, and annotating the workloads with the hints.
+	CPUAllocationHint featuregate.Feature = "CPUAllocationHint"
+
+	// NodeWorkloadMixClassifier enables classifying the nodes into the colocation profiles by the observed workload
+	// mix, and labeling the nodes, so the NodeSLO templates and the scheduler pools are applied automatically.
+	NodeWorkloadMixClassifier featuregate.Feature = "NodeWorkloadMixClassifier"
+
+	// NodeUsageForecast enables forecasting the node usages in a short horizon from the recent NodeMetrics, and
+	// annotating the NodeMetrics with the forecasts for the koord-descheduler.
+	NodeUsageForecast featuregate.Feature = "NodeUsageForecast"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -94,6 +137,8 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	NodeMutatingWebhook:                    {Default: false, PreRelease: featuregate.Alpha},
 	NodeValidatingWebhook:                  {Default: false, PreRelease: featuregate.Alpha},
 	ConfigMapValidatingWebhook:             {Default: false, PreRelease: featuregate.Alpha},
+	NodeSLOValidatingWebhook:               {Default: false, PreRelease: featuregate.Alpha},
+	DeviceValidatingWebhook:                {Default: false, PreRelease: featuregate.Alpha},
 	WebhookFramework:                       {Default: true, PreRelease: featuregate.Beta},
 	ColocationProfileSkipMutatingResources: {Default: false, PreRelease: featuregate.Alpha},
 	MultiQuotaTree:                         {Default: false, PreRelease: featuregate.Alpha},
@@ -104,14 +149,28 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	SupportParentQuotaSubmitPod:            {Default: false, PreRelease: featuregate.Alpha},
 	EnableQuotaAdmission:                   {Default: false, PreRelease: featuregate.Alpha},
 	EnableSyncGPUSharedResource:            {Default: true, PreRelease: featuregate.Alpha},
+	NodeMetricsAPIProvider:                 {Default: false, PreRelease: featuregate.Alpha},
+	QuotaUsageAggregation:                  {Default: false, PreRelease: featuregate.Alpha},
+	GPUJobProfile:                          {Default: false, PreRelease: featuregate.Alpha},
+	ColocationStatus:                       {Default: false, PreRelease: featuregate.Alpha},
+	NodeMetricReportServer:                 {Default: false, PreRelease: featuregate.Alpha},
+	CacheDomainTopologySpread:              {Default: false, PreRelease: featuregate.Alpha},
+	ClusterEvictionBudget:                  {Default: false, PreRelease: featuregate.Alpha},
+	CPUAllocationHint:                      {Default: false, PreRelease: featuregate.Alpha},
+	NodeWorkloadMixClassifier:              {Default: false, PreRelease: featuregate.Alpha},
+	NodeUsageForecast:                      {Default: false, PreRelease: featuregate.Alpha},
 }
 
 const (
 	DisablePVCReservation featuregate.Feature = "DisablePVCReservation"
+
+	// NodeMaintenance enables the NodeMaintenance controller to drain the nodes with the PodMigrationJobs.
+	NodeMaintenance featuregate.Feature = "NodeMaintenance"
 )
 
 var defaultDeschedulerFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	DisablePVCReservation: {Default: false, PreRelease: featuregate.Beta},
+	NodeMaintenance:       {Default: false, PreRelease: featuregate.Alpha},
 }
 
 const (
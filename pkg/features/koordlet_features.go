@@ -64,6 +64,10 @@ const (
 	// BEMemoryEvict evict best-effort pod based on node memory usage.
 	BEMemoryEvict featuregate.Feature = "BEMemoryEvict"
 
+	// BEPSIEvict evicts best-effort pods when the pressure stall information of the node or the LS pods exceeds the
+	// thresholds for a sustained window.
+	BEPSIEvict featuregate.Feature = "BEPSIEvict"
+
 	// owner: @saintube @zwzhang0107
 	// alpha: v0.2
 	// beta: v1.1
@@ -140,12 +144,40 @@ const (
 	// BlkIOReconcile enables block I/O QoS feature of koordlet.
 	BlkIOReconcile featuregate.Feature = "BlkIOReconcile"
 
+	// BlkIOCollector enables koordlet to collect the read/write IOPS, bandwidth and latency of the pods and containers
+	// on each block device from the blkio cgroup and /proc/diskstats.
+	BlkIOCollector featuregate.Feature = "BlkIOCollector"
+
+	// PodNetworkCollector enables koordlet to collect the ingress/egress bandwidth and the tcp retransmissions of the
+	// pods with the eBPF programs attached to the pod cgroups, which requires cgroups-v2.
+	PodNetworkCollector featuregate.Feature = "PodNetworkCollector"
+
+	// PowerCollector enables koordlet to collect the node and per-socket power consumption from the RAPL energy
+	// counters of the powercap, the AMD energy counters and the hwmon power sensors.
+	PowerCollector featuregate.Feature = "PowerCollector"
+
+	// CPUThermalCollector enables koordlet to collect the cpu frequencies, thermal throttling counts and turbo ratios,
+	// and to report the thermal throttling of the node as the node condition.
+	CPUThermalCollector featuregate.Feature = "CPUThermalCollector"
+
+	// NetworkLatencyProber enables koordlet to probe the TCP round-trip time from the network namespaces of the pods
+	// to the configured endpoints of their QoS classes, and to report the network latency SLO in the NodeMetric.
+	NetworkLatencyProber featuregate.Feature = "NetworkLatencyProber"
+
 	// owner: @BUPT-wxq
 	// alpha v1.4
 	//
 	// ColdPageCollector enables coldPageCollector feature of koordlet.
 	ColdPageCollector featuregate.Feature = "ColdPageCollector"
 
+	// DCGMCollector enables koordlet to collect the profiling metrics and the Xid errors of the NVIDIA GPUs from the
+	// dcgm-exporter, e.g. the SM activity, the memory bandwidth utilization and the NVLink traffic.
+	DCGMCollector featuregate.Feature = "DCGMCollector"
+
+	// SchedLatencyCollector enables koordlet to collect the run queue latencies of the pods and containers
+	// with the eBPF programs attached to the sched tracepoints.
+	SchedLatencyCollector featuregate.Feature = "SchedLatencyCollector"
+
 	// HugePageReport enables hugepage collector feature of koordlet.
 	// This feature supports reporting of hugepages.
 	// The koord-scheduler will allocate hugepage information based on the user's hugepage request and add it to the Pod's annotations.
@@ -160,6 +192,59 @@ const (
 	// PodResourcesProxy enabled hooked podResources of kubelet provided by koordlet.
 	// It provides a grpc service to enable discovery of pod resources allocated by koordinator system.
 	PodResourcesProxy featuregate.Feature = "PodResourcesProxy"
+
+	// GPUMPS enables koordlet to manage the NVIDIA MPS control daemon of each GPU shared by the MPS pods,
+	// and limits the active thread percentage of the pods according to their gpu-core allocations.
+	GPUMPS featuregate.Feature = "GPUMPS"
+
+	// ImagePrePull enables koordlet to pull the images requested by the ImagePrePulls of the node in advance,
+	// e.g. the images of the reservations which are available on the node.
+	ImagePrePull featuregate.Feature = "ImagePrePull"
+
+	// CPUSetConflictGuard enables koordlet to detect the pods bound by other schedulers whose cpusets conflict
+	// with the allocations of koord-scheduler, and reconcile them into the shared pool.
+	CPUSetConflictGuard featuregate.Feature = "CPUSetConflictGuard"
+
+	// BEDiskQuota enables koordlet to limit the ephemeral storage of the best-effort pods with the project quotas
+	// of their pod dirs, and evict the pods exhausting the quotas before the kubelet's ephemeral-storage eviction.
+	BEDiskQuota featuregate.Feature = "BEDiskQuota"
+
+	// NodeSLOMultiSources enables koordlet to compose the NodeSLO of the node with the NodeSLO sources of the
+	// cluster, the node pool and the node, so that different teams can manage different sections independently.
+	NodeSLOMultiSources featuregate.Feature = "NodeSLOMultiSources"
+
+	// NetQoSReconcile enables koordlet to limit the egress bandwidth of the pods by QoS classes with the tc htb
+	// classes and an eBPF classifier, when the NodeSLO network QoS policy is "ebpf".
+	NetQoSReconcile featuregate.Feature = "NetQoSReconcile"
+
+	// NetQoSDSCPMarking enables koordlet to mark the DSCP of the egress packets of the pods by QoS classes, so that
+	// the underlay network can prioritize the traffic of the prod pods.
+	NetQoSDSCPMarking featuregate.Feature = "NetQoSDSCPMarking"
+
+	// IOCostReconcile enables koordlet to configure the blk-iocost of the disks and the io weights of the LS and BE
+	// classes, and to lower the weights of the BE class on the disks whose latency exceeds the targets.
+	// It is mutually exclusive with BlkIOReconcile, which takes precedence.
+	IOCostReconcile featuregate.Feature = "IOCostReconcile"
+
+	// MemoryTiering enables koordlet to turn on the kernel memory tiering (numa_balancing=2 and demotion), so the cold
+	// pages are demoted to the slow memory tiers (e.g. CXL memory) and the BE pods are not promoted to the fast DRAM.
+	MemoryTiering featuregate.Feature = "MemoryTiering"
+
+	// EvictionBudgetAdmission enables koordlet to admit the pod evictions by the ClusterEvictionBudgets, so the
+	// evictions of the nodes in a pool are capped in a time window.
+	EvictionBudgetAdmission featuregate.Feature = "EvictionBudgetAdmission"
+
+	// BEMemoryThrottle throttles the memory of best-effort pods by memory.high based on node memory usage before the
+	// memory eviction happens.
+	BEMemoryThrottle featuregate.Feature = "BEMemoryThrottle"
+
+	// ColocationReadyCondition enables koordlet to report the ColocationReady node condition, which summarizes whether
+	// the cgroup driver, resctrl, runtime hooks and metric collection are healthy.
+	ColocationReadyCondition featuregate.Feature = "ColocationReadyCondition"
+
+	// EvictionSortByPDB enables koordlet to watch the PodDisruptionBudgets, so the BE pods to evict are ordered by the
+	// remaining disruptions allowed of their owners.
+	EvictionSortByPDB featuregate.Feature = "EvictionSortByPDB"
 )
 
 func init() {
@@ -171,27 +256,48 @@ var (
 	DefaultKoordletFeatureGate        featuregate.FeatureGate        = DefaultMutableKoordletFeatureGate
 
 	defaultKoordletFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
-		AuditEvents:            {Default: false, PreRelease: featuregate.Alpha},
-		AuditEventsHTTPHandler: {Default: false, PreRelease: featuregate.Alpha},
-		BECPUSuppress:          {Default: true, PreRelease: featuregate.Beta},
-		BECPUManager:           {Default: false, PreRelease: featuregate.Alpha},
-		BECPUEvict:             {Default: false, PreRelease: featuregate.Alpha},
-		BEMemoryEvict:          {Default: false, PreRelease: featuregate.Alpha},
-		CPUBurst:               {Default: true, PreRelease: featuregate.Beta},
-		SystemConfig:           {Default: false, PreRelease: featuregate.Alpha},
-		RdtResctrl:             {Default: true, PreRelease: featuregate.Beta},
-		ResctrlCollector:       {Default: false, PreRelease: featuregate.Alpha},
-		CgroupReconcile:        {Default: false, PreRelease: featuregate.Alpha},
-		NodeTopologyReport:     {Default: true, PreRelease: featuregate.Beta},
-		Accelerators:           {Default: false, PreRelease: featuregate.Alpha},
-		RDMADevices:            {Default: false, PreRelease: featuregate.Alpha},
-		CPICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		Libpfm4:                {Default: false, PreRelease: featuregate.Alpha},
-		PSICollector:           {Default: false, PreRelease: featuregate.Alpha},
-		BlkIOReconcile:         {Default: false, PreRelease: featuregate.Alpha},
-		ColdPageCollector:      {Default: false, PreRelease: featuregate.Alpha},
-		HugePageReport:         {Default: false, PreRelease: featuregate.Alpha},
-		PodResourcesProxy:      {Default: false, PreRelease: featuregate.Alpha},
+		AuditEvents:              {Default: false, PreRelease: featuregate.Alpha},
+		AuditEventsHTTPHandler:   {Default: false, PreRelease: featuregate.Alpha},
+		BECPUSuppress:            {Default: true, PreRelease: featuregate.Beta},
+		BECPUManager:             {Default: false, PreRelease: featuregate.Alpha},
+		BECPUEvict:               {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryEvict:            {Default: false, PreRelease: featuregate.Alpha},
+		BEPSIEvict:               {Default: false, PreRelease: featuregate.Alpha},
+		BEMemoryThrottle:         {Default: false, PreRelease: featuregate.Alpha},
+		CPUBurst:                 {Default: true, PreRelease: featuregate.Beta},
+		SystemConfig:             {Default: false, PreRelease: featuregate.Alpha},
+		RdtResctrl:               {Default: true, PreRelease: featuregate.Beta},
+		ResctrlCollector:         {Default: false, PreRelease: featuregate.Alpha},
+		CgroupReconcile:          {Default: false, PreRelease: featuregate.Alpha},
+		NodeTopologyReport:       {Default: true, PreRelease: featuregate.Beta},
+		Accelerators:             {Default: false, PreRelease: featuregate.Alpha},
+		RDMADevices:              {Default: false, PreRelease: featuregate.Alpha},
+		CPICollector:             {Default: false, PreRelease: featuregate.Alpha},
+		Libpfm4:                  {Default: false, PreRelease: featuregate.Alpha},
+		PSICollector:             {Default: false, PreRelease: featuregate.Alpha},
+		BlkIOReconcile:           {Default: false, PreRelease: featuregate.Alpha},
+		BlkIOCollector:           {Default: false, PreRelease: featuregate.Alpha},
+		PodNetworkCollector:      {Default: false, PreRelease: featuregate.Alpha},
+		PowerCollector:           {Default: false, PreRelease: featuregate.Alpha},
+		CPUThermalCollector:      {Default: false, PreRelease: featuregate.Alpha},
+		NetworkLatencyProber:     {Default: false, PreRelease: featuregate.Alpha},
+		ColdPageCollector:        {Default: false, PreRelease: featuregate.Alpha},
+		SchedLatencyCollector:    {Default: false, PreRelease: featuregate.Alpha},
+		DCGMCollector:            {Default: false, PreRelease: featuregate.Alpha},
+		HugePageReport:           {Default: false, PreRelease: featuregate.Alpha},
+		PodResourcesProxy:        {Default: false, PreRelease: featuregate.Alpha},
+		GPUMPS:                   {Default: false, PreRelease: featuregate.Alpha},
+		ImagePrePull:             {Default: false, PreRelease: featuregate.Alpha},
+		CPUSetConflictGuard:      {Default: false, PreRelease: featuregate.Alpha},
+		BEDiskQuota:              {Default: false, PreRelease: featuregate.Alpha},
+		NodeSLOMultiSources:      {Default: false, PreRelease: featuregate.Alpha},
+		NetQoSReconcile:          {Default: false, PreRelease: featuregate.Alpha},
+		NetQoSDSCPMarking:        {Default: false, PreRelease: featuregate.Alpha},
+		IOCostReconcile:          {Default: false, PreRelease: featuregate.Alpha},
+		MemoryTiering:            {Default: false, PreRelease: featuregate.Alpha},
+		EvictionBudgetAdmission:  {Default: false, PreRelease: featuregate.Alpha},
+		ColocationReadyCondition: {Default: false, PreRelease: featuregate.Alpha},
+		EvictionSortByPDB:        {Default: false, PreRelease: featuregate.Alpha},
 	}
 )
 
@@ -203,7 +309,7 @@ func IsFeatureDisabled(nodeSLO *slov1alpha1.NodeSLO, feature featuregate.Feature
 
 	spec := nodeSLO.Spec
 	switch feature {
-	case BECPUSuppress, BEMemoryEvict, BECPUEvict:
+	case BECPUSuppress, BEMemoryEvict, BEMemoryThrottle, BECPUEvict, BEPSIEvict:
 		if spec.ResourceUsedThresholdWithBE == nil || spec.ResourceUsedThresholdWithBE.Enable == nil {
 			return true, fmt.Errorf("cannot parse feature config for invalid nodeSLO %v", nodeSLO)
 		}
@@ -22,6 +22,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEventLogger(t *testing.T) {
@@ -124,3 +126,12 @@ func TestReverseEventReaderMultiFile(t *testing.T) {
 		t.Errorf("failed to read multi files, actual got %d events", count)
 	}
 }
+
+func TestEventHelperActionID(t *testing.T) {
+	actionID := NewActionID()
+	assert.Len(t, actionID, 32)
+	assert.NotEqual(t, actionID, NewActionID())
+
+	e := (&EventHelper{}).Node().Reason("evict").ActionID(actionID)
+	assert.Equal(t, actionID, e.Event.ActionID)
+}
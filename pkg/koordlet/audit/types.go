@@ -17,6 +17,8 @@ limitations under the License.
 package audit
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 )
@@ -44,6 +46,9 @@ type Event struct {
 	Container string    `json:"container,omitempty"`
 	Reason    string    `json:"reason,omitempty"`
 	Message   string    `json:"message,omitempty"`
+	// ActionID identifies the action recorded by the event, e.g. an eviction or a suppression.
+	// It is attached to the related metrics as the exemplar to link the metrics to the event.
+	ActionID string `json:"actionID,omitempty"`
 }
 
 // EventHelper is a helper struct use to support fluent APIs
@@ -134,6 +139,22 @@ func (e *EventHelper) Message(format string, args ...interface{}) *EventHelper {
 	return e
 }
 
+// ActionID set the event action id to id
+func (e *EventHelper) ActionID(id string) *EventHelper {
+	e.Event.ActionID = id
+	return e
+}
+
+// NewActionID generates a random action id, which has the same format as the W3C trace id
+// so that it can be used as the trace id of the metric exemplars.
+func NewActionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
 // Do write the event to the writer
 func (e *EventHelper) Do() error {
 	e.Event.CreatedAt = time.Now().Local()
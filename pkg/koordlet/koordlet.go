@@ -17,30 +17,38 @@ limitations under the License.
 package agent
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
 	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientset "k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	clientsetbeta1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/queryservice"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/readiness"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	statesinformerimpl "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/impl"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -61,10 +69,13 @@ type daemon struct {
 	metricAdvisor  metricsadvisor.MetricAdvisor
 	statesInformer statesinformer.StatesInformer
 	metricCache    metriccache.MetricCache
+	queryServer    *queryservice.Server
 	qosManager     qosmanager.QOSManager
 	runtimeHook    runtimehooks.RuntimeHook
 	predictServer  prediction.PredictServer
+	readiness      readiness.Reporter
 	executor       resourceexecutor.ResourceUpdateExecutor
+	shutdownTracer tracing.ShutdownFunc
 }
 
 func NewDaemon(config *config.Configuration) (Daemon, error) {
@@ -76,6 +87,11 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	klog.Infof("NODE_NAME is %v, start time %v", nodeName, float64(time.Now().Unix()))
 	metrics.RecordKoordletStartTime(nodeName, float64(time.Now().Unix()))
 
+	shutdownTracer, err := tracing.Setup(context.Background(), config.TracingConf, nodeName)
+	if err != nil {
+		return nil, err
+	}
+
 	system.InitSupportConfigs()
 	klog.Infof("sysconf: %+v, agentMode: %v", system.Conf, system.AgentMode)
 	klog.Infof("kernel version INFO: %+v", system.HostSystemInfo)
@@ -93,11 +109,18 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 	predictorFactory := prediction.NewPredictorFactory(predictServer, config.PredictionConf.ColdStartDuration, config.PredictionConf.SafetyMarginPercent)
 
 	statesInformer := statesinformerimpl.NewStatesInformer(config.StatesInformerConf, kubeClient, crdClient, topologyClient, metricCache, nodeName, schedulingClient, predictorFactory)
+	if updater, ok := metricCache.(metriccache.PodLabelsUpdater); ok {
+		statesInformer.RegisterCallbacks(statesinformer.RegisterTypeAllPods, "metric-cache-pod-labels",
+			"update the workload and quota labels of pods for the metric cache", newPodLabelsCallback(updater))
+	}
 
 	cgroupDriver := system.GetCgroupDriver()
 	system.SetupCgroupPathFormatter(cgroupDriver)
 
-	collectorService := metricsadvisor.NewMetricAdvisor(config.CollectorConf, statesInformer, metricCache)
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&clientcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	collectorRecorder := eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "koordlet-metricAdvisor", Host: nodeName})
+	collectorService := metricsadvisor.NewMetricAdvisor(config.CollectorConf, statesInformer, metricCache, kubeClient, collectorRecorder)
 
 	evictVersion, err := util.FindSupportedEvictVersion(kubeClient)
 	if err != nil {
@@ -115,10 +138,19 @@ func NewDaemon(config *config.Configuration) (Daemon, error) {
 		metricAdvisor:  collectorService,
 		statesInformer: statesInformer,
 		metricCache:    metricCache,
+		queryServer:    queryservice.NewServer(config.MetricCacheConf, metricCache),
 		qosManager:     qosManager,
 		runtimeHook:    runtimeHook,
 		predictServer:  predictServer,
 		executor:       resourceexecutor.NewResourceUpdateExecutor(),
+		shutdownTracer: shutdownTracer,
+	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.ColocationReadyCondition) {
+		d.readiness = readiness.NewReporter(kubeClient, statesInformer,
+			readiness.NewCgroupDriverCheck(),
+			readiness.NewResctrlCheck(),
+			readiness.NewRuntimeHooksCheck(runtimeHook.HasSynced),
+			readiness.NewMetricCollectionCheck(metricCache, config.CollectorConf.CollectResUsedInterval))
 	}
 
 	return d, nil
@@ -137,6 +169,12 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 		}
 	}()
 
+	go func() {
+		if err := d.queryServer.Run(stopCh); err != nil {
+			klog.Fatal("Unable to run the metric query server: ", err)
+		}
+	}()
+
 	// start states informer
 	go func() {
 		if err := d.statesInformer.Run(stopCh); err != nil {
@@ -182,7 +220,36 @@ func (d *daemon) Run(stopCh <-chan struct{}) {
 		}
 	}()
 
+	// start colocation readiness reporter
+	if d.readiness != nil {
+		go d.readiness.Run(stopCh)
+	}
+
 	klog.Info("Start daemon successfully")
 	<-stopCh
 	klog.Info("Shutting down daemon")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := d.shutdownTracer(ctx); err != nil {
+		klog.Warningf("failed to shutdown the tracer provider, err: %v", err)
+	}
+}
+
+// newPodLabelsCallback returns the callback which resolves the workload and quota labels of all pods for the metric
+// cache, so the exported samples of the pods can be aggregated by the owner workload and the elastic quota.
+func newPodLabelsCallback(updater metriccache.PodLabelsUpdater) statesinformer.UpdateCbFn {
+	return func(t statesinformer.RegisterType, obj interface{}, target *statesinformer.CallbackTarget) {
+		if target == nil {
+			return
+		}
+		podLabels := make(map[string]map[string]string, len(target.Pods))
+		for _, podMeta := range target.Pods {
+			if podMeta == nil || podMeta.Pod == nil {
+				continue
+			}
+			podLabels[string(podMeta.Pod.UID)] = metrics.GetPodWorkloadLabels(podMeta.Pod)
+		}
+		updater.UpdatePodLabels(podLabels)
+	}
 }
@@ -20,8 +20,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
+	"github.com/koordinator-sh/koordinator/apis/extension"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/config"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 )
 
 func TestNewDaemon(t *testing.T) {
@@ -32,3 +37,40 @@ func TestNewDaemon(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+type fakePodLabelsUpdater struct {
+	podLabels map[string]map[string]string
+}
+
+func (f *fakePodLabelsUpdater) UpdatePodLabels(podLabels map[string]map[string]string) {
+	f.podLabels = podLabels
+}
+
+func Test_newPodLabelsCallback(t *testing.T) {
+	updater := &fakePodLabelsUpdater{}
+	fn := newPodLabelsCallback(updater)
+	fn(statesinformer.RegisterTypeAllPods, nil, &statesinformer.CallbackTarget{
+		Pods: []*statesinformer.PodMeta{
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "test-job-xxx",
+						UID:    "test-pod-uid",
+						Labels: map[string]string{extension.LabelQuotaName: "team-a"},
+						OwnerReferences: []metav1.OwnerReference{
+							{APIVersion: "batch/v1", Kind: "Job", Name: "test-job", Controller: pointer.Bool(true)},
+						},
+					},
+				},
+			},
+			nil,
+		},
+	})
+	assert.Equal(t, map[string]map[string]string{
+		"test-pod-uid": {
+			"pod_owner_kind": "Job",
+			"pod_owner_name": "test-job",
+			"pod_quota_name": "team-a",
+		},
+	}, updater.podLabels)
+}
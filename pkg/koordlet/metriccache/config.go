@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/prometheus/prometheus/tsdb"
+	cliflag "k8s.io/component-base/cli/flag"
 )
 
 type Config struct {
@@ -33,12 +34,37 @@ type Config struct {
 	TSDBStripeSize        int
 	TSDBMaxBytes          int64
 
-	// not necessary now since it is in-memory empty dir now
+	// not necessary now since it is in-memory empty dir by default
 	TSDBWALSegmentSize            int
 	TSDBMaxBlockChunkSegmentSize  int64
 	TSDBMinBlockDuration          time.Duration
 	TSDBMaxBlockDuration          time.Duration
 	TSDBHeadChunksWriteBufferSize int
+	// TSDBDownsampleRules downsamples the persisted samples as they age. It is disabled if empty.
+	TSDBDownsampleRules    DownsampleRules
+	TSDBDownsampleInterval time.Duration
+	// TSDBPersistEnabled recovers the metric data under the TSDBPath on startup, which keeps the recent samples across
+	// restarts when the TSDBPath is mounted from a host path. The data is discarded if it is corrupted or its size
+	// exceeds the TSDBPersistMaxBytes.
+	TSDBPersistEnabled  bool
+	TSDBPersistMaxBytes int64
+
+	// RemoteWriteURL is the prometheus remote write endpoint where the samples appended to the TSDB are also
+	// streamed to. The remote write is disabled if it is empty.
+	RemoteWriteURL            string
+	RemoteWriteExternalLabels map[string]string
+	RemoteWriteBatchSize      int
+	RemoteWriteFlushInterval  time.Duration
+	RemoteWriteQueueCapacity  int
+	RemoteWriteTimeout        time.Duration
+	RemoteWriteMaxRetries     int
+
+	// QuerySocketPath is the unix domain socket of the read-only gRPC query service of the metric data, which is only
+	// accessible to the owner of the socket file. The service is disabled if it is empty.
+	QuerySocketPath string
+	// QueryHTTPSocketPath serves the same query API in HTTP/JSON on another unix domain socket, disabled if it is empty.
+	QueryHTTPSocketPath string
+	QueryMaxPoints      int
 }
 
 func NewDefaultConfig() *Config {
@@ -57,6 +83,16 @@ func NewDefaultConfig() *Config {
 		TSDBMinBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBMaxBlockDuration:          10 * time.Minute, // 10 minutes
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,      // 1 MB
+		TSDBDownsampleInterval:        5 * time.Minute,
+		TSDBPersistMaxBytes:           200 * 1024 * 1024, // 200 MB
+
+		RemoteWriteBatchSize:     500,
+		RemoteWriteFlushInterval: 10 * time.Second,
+		RemoteWriteQueueCapacity: 10000,
+		RemoteWriteTimeout:       10 * time.Second,
+		RemoteWriteMaxRetries:    3,
+
+		QueryMaxPoints: 100000,
 	}
 }
 
@@ -75,5 +111,21 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.TSDBMinBlockDuration, "tsdb-min-block-duration", c.TSDBMinBlockDuration, "The timestamp range of head blocks after which they get persisted, recommend >= 1h or this will cause chunks_head leak")
 	fs.DurationVar(&c.TSDBMaxBlockDuration, "tsdb-max-block-duration", c.TSDBMaxBlockDuration, "The maximum timestamp range of compacted blocks, recommend >= 1h or this will cause chunks_head leak.")
 	fs.IntVar(&c.TSDBHeadChunksWriteBufferSize, "tsdb-head-chunks-write-buffer-size", c.TSDBHeadChunksWriteBufferSize, "Write buffer size used by the head chunks mapper.")
-
+	fs.Var(&c.TSDBDownsampleRules, "tsdb-downsample-rules", "Comma-separated resolution:keepFor rules to downsample the metric data as they age, e.g. 5s:10m,1m:24h keeps 5s resolution for 10 minutes, then 1m resolution for 24 hours. "+
+		"The samples are averaged in each resolution interval once they are persisted from the head into blocks. Disabled if empty.")
+	fs.DurationVar(&c.TSDBDownsampleInterval, "tsdb-downsample-interval", c.TSDBDownsampleInterval, "The interval to check and downsample the persisted metric data blocks.")
+	fs.BoolVar(&c.TSDBPersistEnabled, "tsdb-persist-enabled", c.TSDBPersistEnabled, "Recover the metric data persisted in tsdb-path on startup. The tsdb-path should be mounted from a host path to keep the data across restarts.")
+	fs.Int64Var(&c.TSDBPersistMaxBytes, "tsdb-persist-max-bytes", c.TSDBPersistMaxBytes, "Maximum number of bytes of the persisted metric data to recover, the data is discarded on startup if it exceeds the limit.")
+
+	fs.StringVar(&c.RemoteWriteURL, "metric-remote-write-url", c.RemoteWriteURL, "The prometheus remote write endpoint to stream the metric samples to, e.g. http://victoria-metrics:8428/api/v1/write. Disabled if empty.")
+	fs.Var(cliflag.NewMapStringString(&c.RemoteWriteExternalLabels), "metric-remote-write-external-labels", "A set of key=value pairs of labels attached to all samples sent by the remote write, e.g. cluster=prod,region=cn.")
+	fs.IntVar(&c.RemoteWriteBatchSize, "metric-remote-write-batch-size", c.RemoteWriteBatchSize, "The max number of samples sent in a remote write request.")
+	fs.DurationVar(&c.RemoteWriteFlushInterval, "metric-remote-write-flush-interval", c.RemoteWriteFlushInterval, "The max interval to flush the pending samples of the remote write.")
+	fs.IntVar(&c.RemoteWriteQueueCapacity, "metric-remote-write-queue-capacity", c.RemoteWriteQueueCapacity, "The max number of samples pending in the remote write queue. The new samples are dropped when the queue is full.")
+	fs.DurationVar(&c.RemoteWriteTimeout, "metric-remote-write-timeout", c.RemoteWriteTimeout, "The timeout of a remote write request.")
+	fs.IntVar(&c.RemoteWriteMaxRetries, "metric-remote-write-max-retries", c.RemoteWriteMaxRetries, "The max retries of a failed remote write request before the batch is dropped.")
+
+	fs.StringVar(&c.QuerySocketPath, "metric-query-socket-path", c.QuerySocketPath, "The unix domain socket path of the read-only gRPC service to query the metric data, e.g. /var/run/koordlet/metric-query.sock. Disabled if empty.")
+	fs.StringVar(&c.QueryHTTPSocketPath, "metric-query-http-socket-path", c.QueryHTTPSocketPath, "The unix domain socket path of the read-only HTTP/JSON service to query the metric data. Disabled if empty.")
+	fs.IntVar(&c.QueryMaxPoints, "metric-query-max-points", c.QueryMaxPoints, "The max number of points returned by a metric query, the query exceeding the limit is rejected.")
 }
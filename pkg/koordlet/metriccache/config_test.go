@@ -41,6 +41,16 @@ func Test_NewDefaultConfig(t *testing.T) {
 		TSDBMinBlockDuration:          10 * time.Minute,
 		TSDBMaxBlockDuration:          10 * time.Minute,
 		TSDBHeadChunksWriteBufferSize: 1024 * 1024,
+		TSDBDownsampleInterval:        5 * time.Minute,
+		TSDBPersistMaxBytes:           200 * 1024 * 1024,
+
+		RemoteWriteBatchSize:     500,
+		RemoteWriteFlushInterval: 10 * time.Second,
+		RemoteWriteQueueCapacity: 10000,
+		RemoteWriteTimeout:       10 * time.Second,
+		RemoteWriteMaxRetries:    3,
+
+		QueryMaxPoints: 100000,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -63,6 +73,22 @@ func Test_InitFlags(t *testing.T) {
 		"--tsdb-min-block-duration=10m",
 		"--tsdb-max-block-duration=20m",
 		"--tsdb-head-chunks-write-buffer-size=512",
+		"--tsdb-downsample-rules=5s:10m,1m:24h",
+		"--tsdb-downsample-interval=1m",
+		"--tsdb-persist-enabled=true",
+		"--tsdb-persist-max-bytes=131072",
+
+		"--metric-remote-write-url=http://victoria-metrics:8428/api/v1/write",
+		"--metric-remote-write-external-labels=cluster=test,region=cn",
+		"--metric-remote-write-batch-size=100",
+		"--metric-remote-write-flush-interval=5s",
+		"--metric-remote-write-queue-capacity=1000",
+		"--metric-remote-write-timeout=3s",
+		"--metric-remote-write-max-retries=5",
+
+		"--metric-query-socket-path=/var/run/koordlet/metric-query.sock",
+		"--metric-query-http-socket-path=/var/run/koordlet/metric-query-http.sock",
+		"--metric-query-max-points=1000",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -81,6 +107,22 @@ func Test_InitFlags(t *testing.T) {
 		TSDBMinBlockDuration          time.Duration
 		TSDBMaxBlockDuration          time.Duration
 		TSDBHeadChunksWriteBufferSize int
+		TSDBDownsampleRules           DownsampleRules
+		TSDBDownsampleInterval        time.Duration
+		TSDBPersistEnabled            bool
+		TSDBPersistMaxBytes           int64
+
+		RemoteWriteURL            string
+		RemoteWriteExternalLabels map[string]string
+		RemoteWriteBatchSize      int
+		RemoteWriteFlushInterval  time.Duration
+		RemoteWriteQueueCapacity  int
+		RemoteWriteTimeout        time.Duration
+		RemoteWriteMaxRetries     int
+
+		QuerySocketPath     string
+		QueryHTTPSocketPath string
+		QueryMaxPoints      int
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -105,6 +147,26 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          10 * time.Minute,
 				TSDBMaxBlockDuration:          20 * time.Minute,
 				TSDBHeadChunksWriteBufferSize: 512,
+				TSDBDownsampleRules: DownsampleRules{
+					{Resolution: 5 * time.Second, KeepFor: 10 * time.Minute},
+					{Resolution: time.Minute, KeepFor: 24 * time.Hour},
+				},
+				TSDBDownsampleInterval: time.Minute,
+				TSDBPersistEnabled:     true,
+				TSDBPersistMaxBytes:    131072,
+				RemoteWriteURL:         "http://victoria-metrics:8428/api/v1/write",
+				RemoteWriteExternalLabels: map[string]string{
+					"cluster": "test",
+					"region":  "cn",
+				},
+				RemoteWriteBatchSize:     100,
+				RemoteWriteFlushInterval: 5 * time.Second,
+				RemoteWriteQueueCapacity: 1000,
+				RemoteWriteTimeout:       3 * time.Second,
+				RemoteWriteMaxRetries:    5,
+				QuerySocketPath:          "/var/run/koordlet/metric-query.sock",
+				QueryHTTPSocketPath:      "/var/run/koordlet/metric-query-http.sock",
+				QueryMaxPoints:           1000,
 			},
 			args: args{fs: fs},
 		},
@@ -126,6 +188,22 @@ func Test_InitFlags(t *testing.T) {
 				TSDBMinBlockDuration:          tt.fields.TSDBMinBlockDuration,
 				TSDBMaxBlockDuration:          tt.fields.TSDBMaxBlockDuration,
 				TSDBHeadChunksWriteBufferSize: tt.fields.TSDBHeadChunksWriteBufferSize,
+				TSDBDownsampleRules:           tt.fields.TSDBDownsampleRules,
+				TSDBDownsampleInterval:        tt.fields.TSDBDownsampleInterval,
+				TSDBPersistEnabled:            tt.fields.TSDBPersistEnabled,
+				TSDBPersistMaxBytes:           tt.fields.TSDBPersistMaxBytes,
+
+				RemoteWriteURL:            tt.fields.RemoteWriteURL,
+				RemoteWriteExternalLabels: tt.fields.RemoteWriteExternalLabels,
+				RemoteWriteBatchSize:      tt.fields.RemoteWriteBatchSize,
+				RemoteWriteFlushInterval:  tt.fields.RemoteWriteFlushInterval,
+				RemoteWriteQueueCapacity:  tt.fields.RemoteWriteQueueCapacity,
+				RemoteWriteTimeout:        tt.fields.RemoteWriteTimeout,
+				RemoteWriteMaxRetries:     tt.fields.RemoteWriteMaxRetries,
+
+				QuerySocketPath:     tt.fields.QuerySocketPath,
+				QueryHTTPSocketPath: tt.fields.QueryHTTPSocketPath,
+				QueryMaxPoints:      tt.fields.QueryMaxPoints,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
@@ -18,6 +18,8 @@ package metriccache
 
 import (
 	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
 )
 
 type InterferenceMetricName string
@@ -59,10 +61,19 @@ type MetricCache interface {
 	KVStorage
 }
 
+// PodLabelsUpdater updates the extra labels of the pods by the pod uid, e.g. the workload owner and the elastic quota.
+// The labels are attached to the samples of the pods exported by the remote write.
+type PodLabelsUpdater interface {
+	UpdatePodLabels(podLabels map[string]map[string]string)
+}
+
+var _ PodLabelsUpdater = &metricCache{}
+
 type metricCache struct {
 	config *Config
 	TSDBStorage
 	KVStorage
+	remoteWriter *remoteWriter
 }
 
 func NewMetricCache(cfg *Config) (MetricCache, error) {
@@ -71,14 +82,42 @@ func NewMetricCache(cfg *Config) (MetricCache, error) {
 		return nil, err
 	}
 	kvdb := NewMemoryStorage()
-	return &metricCache{
+	m := &metricCache{
 		config:      cfg,
 		TSDBStorage: tsdb,
 		KVStorage:   kvdb,
-	}, nil
+	}
+	if cfg.RemoteWriteURL != "" {
+		m.remoteWriter = newRemoteWriter(cfg)
+	}
+	return m, nil
+}
+
+func (m *metricCache) Appender() Appender {
+	appender := m.TSDBStorage.Appender()
+	if m.remoteWriter == nil {
+		return appender
+	}
+	return &remoteWriteAppender{
+		Appender: appender,
+		writer:   m.remoteWriter,
+	}
+}
+
+func (m *metricCache) UpdatePodLabels(podLabels map[string]map[string]string) {
+	if m.remoteWriter == nil {
+		return
+	}
+	m.remoteWriter.UpdatePodLabels(podLabels)
 }
 
 func (m *metricCache) Run(stopCh <-chan struct{}) error {
+	if m.remoteWriter != nil {
+		go m.remoteWriter.Run(stopCh)
+	}
+	if s, ok := m.TSDBStorage.(*tsdbStorage); ok && s.downsampler != nil {
+		go wait.Until(s.downsampler.downsample, m.config.TSDBDownsampleInterval, stopCh)
+	}
 	<-stopCh
 	m.Close()
 	return nil
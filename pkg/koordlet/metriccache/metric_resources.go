@@ -46,6 +46,23 @@ var (
 	ContainerGPUCoreUsageMetric             = defaultMetricFactory.New(ContainerMetricGPUCoreUsage).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	ContainerGPUMemUsageMetric              = defaultMetricFactory.New(ContainerMetricGPUMemUsage).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
 	ContainerCPUThrottledMetric             = defaultMetricFactory.New(ContainerMetricCPUThrottled).withPropertySchema(MetricPropertyContainerID)
+
+	// DCGM
+	NodeGPUSMActiveMetric        = defaultMetricFactory.New(NodeMetricGPUSMActive).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	NodeGPUDRAMActiveMetric      = defaultMetricFactory.New(NodeMetricGPUDRAMActive).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	NodeGPUNVLinkTxBytesMetric   = defaultMetricFactory.New(NodeMetricGPUNVLinkTxBytes).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	NodeGPUNVLinkRxBytesMetric   = defaultMetricFactory.New(NodeMetricGPUNVLinkRxBytes).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	NodeGPUXidErrorMetric        = defaultMetricFactory.New(NodeMetricGPUXidError).withPropertySchema(MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	PodGPUSMActiveMetric         = defaultMetricFactory.New(PodMetricGPUSMActive).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	PodGPUDRAMActiveMetric       = defaultMetricFactory.New(PodMetricGPUDRAMActive).withPropertySchema(MetricPropertyPodUID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	ContainerGPUSMActiveMetric   = defaultMetricFactory.New(ContainerMetricGPUSMActive).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+	ContainerGPUDRAMActiveMetric = defaultMetricFactory.New(ContainerMetricGPUDRAMActive).withPropertySchema(MetricPropertyContainerID, MetricPropertyGPUMinor, MetricPropertyGPUDeviceUUID)
+
+	// RDMA
+	NodeRDMATxBandwidthMetric = defaultMetricFactory.New(NodeMetricRDMATxBandwidth).withPropertySchema(MetricPropertyRDMADevice, MetricPropertyRDMAPort)
+	NodeRDMARxBandwidthMetric = defaultMetricFactory.New(NodeMetricRDMARxBandwidth).withPropertySchema(MetricPropertyRDMADevice, MetricPropertyRDMAPort)
+	PodRDMATxBandwidthMetric  = defaultMetricFactory.New(PodMetricRDMATxBandwidth).withPropertySchema(MetricPropertyPodUID, MetricPropertyRDMADevice)
+	PodRDMARxBandwidthMetric  = defaultMetricFactory.New(PodMetricRDMARxBandwidth).withPropertySchema(MetricPropertyPodUID, MetricPropertyRDMADevice)
 	// cold memory metrics
 	NodeMemoryWithHotPageUsageMetric      = defaultMetricFactory.New(NodeMemoryWithHotPageUsage)
 	PodMemoryWithHotPageUsageMetric       = defaultMetricFactory.New(PodMemoryWithHotPageUsage).withPropertySchema(MetricPropertyPodUID)
@@ -60,11 +77,40 @@ var (
 	ContainerCPI = defaultMetricFactory.New(ContainerMetricCPI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyCPIResource)
 
 	// PSI
+	NodePSIMetric                      = defaultMetricFactory.New(NodeMetricPSI).withPropertySchema(MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
+	NodePSITotalMetric                 = defaultMetricFactory.New(NodeMetricPSITotal).withPropertySchema(MetricPropertyPSIResource, MetricPropertyPSIDegree)
 	ContainerPSIMetric                 = defaultMetricFactory.New(ContainerMetricPSI).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
+	ContainerPSITotalMetric            = defaultMetricFactory.New(ContainerMetricPSITotal).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyPSIResource, MetricPropertyPSIDegree)
 	ContainerPSICPUFullSupportedMetric = defaultMetricFactory.New(ContainerMetricPSICPUFullSupported).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID)
 	PodPSIMetric                       = defaultMetricFactory.New(PodMetricPSI).withPropertySchema(MetricPropertyPodUID, MetricPropertyPSIResource, MetricPropertyPSIPrecision, MetricPropertyPSIDegree)
+	PodPSITotalMetric                  = defaultMetricFactory.New(PodMetricPSITotal).withPropertySchema(MetricPropertyPodUID, MetricPropertyPSIResource, MetricPropertyPSIDegree)
 	PodPSICPUFullSupportedMetric       = defaultMetricFactory.New(PodMetricPSICPUFullSupported).withPropertySchema(MetricPropertyPodUID)
 
+	// Sched Latency
+	ContainerSchedLatencyMetric = defaultMetricFactory.New(ContainerMetricSchedLatency).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertySchedLatencyQuantile)
+	PodSchedLatencyMetric       = defaultMetricFactory.New(PodMetricSchedLatency).withPropertySchema(MetricPropertyPodUID, MetricPropertySchedLatencyQuantile)
+
+	// Block IO
+	ContainerBlkIOMetric = defaultMetricFactory.New(ContainerMetricBlkIO).withPropertySchema(MetricPropertyPodUID, MetricPropertyContainerID, MetricPropertyBlkIODevice, MetricPropertyBlkIOType)
+	PodBlkIOMetric       = defaultMetricFactory.New(PodMetricBlkIO).withPropertySchema(MetricPropertyPodUID, MetricPropertyBlkIODevice, MetricPropertyBlkIOType)
+
+	// Network
+	PodNetworkMetric = defaultMetricFactory.New(PodMetricNetwork).withPropertySchema(MetricPropertyPodUID, MetricPropertyNetworkType)
+
+	// Network Latency
+	PodNetworkLatencyMetric                 = defaultMetricFactory.New(PodMetricNetworkLatency).withPropertySchema(MetricPropertyPodUID)
+	NodeQoSNetworkLatencyMetric             = defaultMetricFactory.New(NodeMetricQoSNetworkLatency).withPropertySchema(MetricPropertyQos)
+	NodeQoSNetworkLatencySLOViolationMetric = defaultMetricFactory.New(NodeMetricQoSNetworkLatencySLOViolation).withPropertySchema(MetricPropertyQos)
+
+	// Power
+	NodePowerMetric       = defaultMetricFactory.New(NodeMetricPower)
+	NodeSocketPowerMetric = defaultMetricFactory.New(NodeMetricSocketPower).withPropertySchema(MetricPropertySocketID, MetricPropertyPowerDomain)
+
+	// CPU Thermal
+	NodeCPUFrequencyMetric             = defaultMetricFactory.New(NodeMetricCPUFrequency)
+	NodeCPUTurboRatioMetric            = defaultMetricFactory.New(NodeMetricCPUTurboRatio)
+	NodeCPUThermalThrottledRatioMetric = defaultMetricFactory.New(NodeMetricCPUThermalThrottledRatio)
+
 	// BE
 	NodeBEMetric = defaultMetricFactory.New(NodeMetricBE).withPropertySchema(MetricPropertyBEResource, MetricPropertyBEAllocation)
 
@@ -76,4 +122,6 @@ var (
 	// Resctrl
 	ResctrlLLCMetric = defaultMetricFactory.New(ResctrlLLC).withPropertySchema(MetricPropertyQos, MetricPropertyResctrlCacheId)
 	ResctrlMBMetric  = defaultMetricFactory.New(ResctrlMB).withPropertySchema(MetricPropertyQos, MetricPropertyResctrlCacheId, MetricPropertyResctrlMbType)
+
+	ResctrlMemoryBandwidthMetric = defaultMetricFactory.New(ResctrlMemoryBandwidth).withPropertySchema(MetricPropertyQos)
 )
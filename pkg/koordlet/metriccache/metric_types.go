@@ -44,6 +44,22 @@ const (
 	NodeMetricGPUMemUsage        MetricKind = "node_gpu_memory_usage"
 	NodeMetricGPUMemTotal        MetricKind = "node_gpu_memory_total"
 
+	// DCGM
+	NodeMetricGPUSMActive        MetricKind = "node_gpu_sm_active"
+	NodeMetricGPUDRAMActive      MetricKind = "node_gpu_dram_active"
+	NodeMetricGPUNVLinkTxBytes   MetricKind = "node_gpu_nvlink_tx_bytes"
+	NodeMetricGPUNVLinkRxBytes   MetricKind = "node_gpu_nvlink_rx_bytes"
+	NodeMetricGPUXidError        MetricKind = "node_gpu_xid_error"
+	PodMetricGPUSMActive         MetricKind = "pod_gpu_sm_active"
+	PodMetricGPUDRAMActive       MetricKind = "pod_gpu_dram_active"
+	ContainerMetricGPUSMActive   MetricKind = "container_gpu_sm_active"
+	ContainerMetricGPUDRAMActive MetricKind = "container_gpu_dram_active"
+
+	NodeMetricRDMATxBandwidth MetricKind = "node_rdma_tx_bandwidth"
+	NodeMetricRDMARxBandwidth MetricKind = "node_rdma_rx_bandwidth"
+	PodMetricRDMATxBandwidth  MetricKind = "pod_rdma_tx_bandwidth"
+	PodMetricRDMARxBandwidth  MetricKind = "pod_rdma_rx_bandwidth"
+
 	SysMetricCPUUsage    MetricKind = "sys_cpu_usage"
 	SysMetricMemoryUsage MetricKind = "sys_memory_usage"
 
@@ -77,13 +93,44 @@ const (
 	// Resctrl
 	ResctrlLLC MetricKind = "resctrl_resource_llc"
 	ResctrlMB  MetricKind = "resctrl_resource_mb"
+	// ResctrlMemoryBandwidth is the memory bandwidth of the resctrl group in bytes per second
+	ResctrlMemoryBandwidth MetricKind = "resctrl_memory_bandwidth"
 
 	// PSI
+	NodeMetricPSI                      MetricKind = "node_psi"
+	NodeMetricPSITotal                 MetricKind = "node_psi_total"
 	ContainerMetricPSI                 MetricKind = "container_psi"
+	ContainerMetricPSITotal            MetricKind = "container_psi_total"
 	ContainerMetricPSICPUFullSupported MetricKind = "container_psi_cpu_full_supported"
 	PodMetricPSI                       MetricKind = "pod_psi"
+	PodMetricPSITotal                  MetricKind = "pod_psi_total"
 	PodMetricPSICPUFullSupported       MetricKind = "pod_psi_cpu_full_supported"
 
+	// Sched Latency
+	ContainerMetricSchedLatency MetricKind = "container_sched_latency"
+	PodMetricSchedLatency       MetricKind = "pod_sched_latency"
+
+	// Block IO
+	ContainerMetricBlkIO MetricKind = "container_blkio"
+	PodMetricBlkIO       MetricKind = "pod_blkio"
+
+	// Network
+	PodMetricNetwork MetricKind = "pod_network"
+
+	// Network Latency
+	PodMetricNetworkLatency                 MetricKind = "pod_network_latency"
+	NodeMetricQoSNetworkLatency             MetricKind = "node_qos_network_latency"
+	NodeMetricQoSNetworkLatencySLOViolation MetricKind = "node_qos_network_latency_slo_violation"
+
+	// Power
+	NodeMetricPower       MetricKind = "node_power"
+	NodeMetricSocketPower MetricKind = "node_socket_power"
+
+	// CPU Thermal
+	NodeMetricCPUFrequency             MetricKind = "node_cpu_frequency"
+	NodeMetricCPUTurboRatio            MetricKind = "node_cpu_turbo_ratio"
+	NodeMetricCPUThermalThrottledRatio MetricKind = "node_cpu_thermal_throttled_ratio"
+
 	//cold memory metrics
 	NodeMemoryWithHotPageUsage      MetricKind = "node_memory_with_hot_page_usage"
 	PodMemoryWithHotPageUsage       MetricKind = "pod_memory_with_hot_page_usage"
@@ -104,6 +151,8 @@ const (
 	MetricPropertyPriorityClass MetricProperty = "priority_class"
 	MetricPropertyGPUMinor      MetricProperty = "gpu_minor"
 	MetricPropertyGPUDeviceUUID MetricProperty = "gpu_device_uuid"
+	MetricPropertyRDMADevice    MetricProperty = "rdma_device"
+	MetricPropertyRDMAPort      MetricProperty = "rdma_port"
 
 	MetricPropertyCPIResource MetricProperty = "cpi_resource"
 
@@ -117,6 +166,16 @@ const (
 	MetricPropertyPSIPrecision MetricProperty = "psi_precision"
 	MetricPropertyPSIDegree    MetricProperty = "psi_degree"
 
+	MetricPropertySchedLatencyQuantile MetricProperty = "sched_latency_quantile"
+
+	MetricPropertyBlkIODevice MetricProperty = "blkio_device"
+	MetricPropertyBlkIOType   MetricProperty = "blkio_type"
+
+	MetricPropertyNetworkType MetricProperty = "network_type"
+
+	MetricPropertySocketID    MetricProperty = "socket_id"
+	MetricPropertyPowerDomain MetricProperty = "power_domain"
+
 	MetricPropertyBEResource   MetricProperty = "be_resource"
 	MetricPropertyBEAllocation MetricProperty = "be_allocation"
 
@@ -139,6 +198,22 @@ const (
 	PSIDegreeFull   MetricPropertyValue = "full"
 	PSIDegreeSome   MetricPropertyValue = "some"
 
+	SchedLatencyQuantileP50 MetricPropertyValue = "p50"
+	SchedLatencyQuantileP95 MetricPropertyValue = "p95"
+	SchedLatencyQuantileP99 MetricPropertyValue = "p99"
+
+	BlkIOTypeReadIOPS     MetricPropertyValue = "read_iops"
+	BlkIOTypeWriteIOPS    MetricPropertyValue = "write_iops"
+	BlkIOTypeReadBPS      MetricPropertyValue = "read_bps"
+	BlkIOTypeWriteBPS     MetricPropertyValue = "write_bps"
+	BlkIOTypeReadLatency  MetricPropertyValue = "read_latency"
+	BlkIOTypeWriteLatency MetricPropertyValue = "write_latency"
+
+	NetworkTypeIngressBPS     MetricPropertyValue = "ingress_bps"
+	NetworkTypeEgressBPS      MetricPropertyValue = "egress_bps"
+	NetworkTypeDrops          MetricPropertyValue = "drops"
+	NetworkTypeTCPRetransmits MetricPropertyValue = "tcp_retransmits"
+
 	ResctrlTypeLLC MetricPropertyValue = "llc"
 	ResctrlTypeMB  MetricPropertyValue = "mb"
 
@@ -150,19 +225,32 @@ const (
 
 // MetricPropertiesFunc is a collection of functions generating metric property k-v, for metric sample generation and query
 var MetricPropertiesFunc = struct {
-	Pod                 func(string) map[MetricProperty]string
-	Container           func(string) map[MetricProperty]string
-	GPU                 func(string, string) map[MetricProperty]string
-	PSICPUFullSupported func(string, string) map[MetricProperty]string
-	ContainerCPI        func(string, string, string) map[MetricProperty]string
-	ResctrlLLC          func(string, int) map[MetricProperty]string
-	ResctrlMB           func(string, int, string) map[MetricProperty]string
-	PodPSI              func(string, string, string, string) map[MetricProperty]string
-	ContainerPSI        func(string, string, string, string, string) map[MetricProperty]string
-	PodGPU              func(string, string, string) map[MetricProperty]string
-	ContainerGPU        func(string, string, string) map[MetricProperty]string
-	NodeBE              func(string, string) map[MetricProperty]string
-	HostApplication     func(string) map[MetricProperty]string
+	Pod                   func(string) map[MetricProperty]string
+	Container             func(string) map[MetricProperty]string
+	GPU                   func(string, string) map[MetricProperty]string
+	PSICPUFullSupported   func(string, string) map[MetricProperty]string
+	ContainerCPI          func(string, string, string) map[MetricProperty]string
+	ResctrlLLC            func(string, int) map[MetricProperty]string
+	ResctrlMB             func(string, int, string) map[MetricProperty]string
+	NodePSI               func(string, string, string) map[MetricProperty]string
+	NodePSITotal          func(string, string) map[MetricProperty]string
+	PodPSI                func(string, string, string, string) map[MetricProperty]string
+	PodPSITotal           func(string, string, string) map[MetricProperty]string
+	ContainerPSI          func(string, string, string, string, string) map[MetricProperty]string
+	ContainerPSITotal     func(string, string, string, string) map[MetricProperty]string
+	PodSchedLatency       func(string, string) map[MetricProperty]string
+	ContainerSchedLatency func(string, string, string) map[MetricProperty]string
+	PodGPU                func(string, string, string) map[MetricProperty]string
+	ContainerGPU          func(string, string, string) map[MetricProperty]string
+	NodeBE                func(string, string) map[MetricProperty]string
+	HostApplication       func(string) map[MetricProperty]string
+	RDMAPort              func(string, string) map[MetricProperty]string
+	PodRDMA               func(string, string) map[MetricProperty]string
+	PodBlkIO              func(string, string, string) map[MetricProperty]string
+	ContainerBlkIO        func(string, string, string, string) map[MetricProperty]string
+	PodNetwork            func(string, string) map[MetricProperty]string
+	SocketPower           func(string, string) map[MetricProperty]string
+	QoS                   func(string) map[MetricProperty]string
 }{
 	Pod: func(podUID string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID}
@@ -194,12 +282,30 @@ var MetricPropertiesFunc = struct {
 	ContainerCPI: func(podUID, containerID, cpiResource string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertyCPIResource: cpiResource}
 	},
+	NodePSI: func(psiResource, psiPrecision, psiDegree string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPSIResource: psiResource, MetricPropertyPSIPrecision: psiPrecision, MetricPropertyPSIDegree: psiDegree}
+	},
+	NodePSITotal: func(psiResource, psiDegree string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPSIResource: psiResource, MetricPropertyPSIDegree: psiDegree}
+	},
 	PodPSI: func(podUID, psiResource, psiPrecision, psiDegree string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyPSIResource: psiResource, MetricPropertyPSIPrecision: psiPrecision, MetricPropertyPSIDegree: psiDegree}
 	},
+	PodPSITotal: func(podUID, psiResource, psiDegree string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyPSIResource: psiResource, MetricPropertyPSIDegree: psiDegree}
+	},
 	ContainerPSI: func(podUID, containerID, psiResource, psiPrecision, psiDegree string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertyPSIResource: psiResource, MetricPropertyPSIPrecision: psiPrecision, MetricPropertyPSIDegree: psiDegree}
 	},
+	ContainerPSITotal: func(podUID, containerID, psiResource, psiDegree string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertyPSIResource: psiResource, MetricPropertyPSIDegree: psiDegree}
+	},
+	PodSchedLatency: func(podUID, quantile string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertySchedLatencyQuantile: quantile}
+	},
+	ContainerSchedLatency: func(podUID, containerID, quantile string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertySchedLatencyQuantile: quantile}
+	},
 	PodGPU: func(podUID, minor, uuid string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyGPUMinor: minor, MetricPropertyGPUDeviceUUID: uuid}
 	},
@@ -212,6 +318,27 @@ var MetricPropertiesFunc = struct {
 	HostApplication: func(appName string) map[MetricProperty]string {
 		return map[MetricProperty]string{MetricPropertyHostAppName: appName}
 	},
+	RDMAPort: func(device, port string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyRDMADevice: device, MetricPropertyRDMAPort: port}
+	},
+	PodRDMA: func(podUID, device string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyRDMADevice: device}
+	},
+	PodBlkIO: func(podUID, device, ioType string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyBlkIODevice: device, MetricPropertyBlkIOType: ioType}
+	},
+	ContainerBlkIO: func(podUID, containerID, device, ioType string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyContainerID: containerID, MetricPropertyBlkIODevice: device, MetricPropertyBlkIOType: ioType}
+	},
+	PodNetwork: func(podUID, networkType string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyPodUID: podUID, MetricPropertyNetworkType: networkType}
+	},
+	SocketPower: func(socketID, domain string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertySocketID: socketID, MetricPropertyPowerDomain: domain}
+	},
+	QoS: func(qos string) map[MetricProperty]string {
+		return map[MetricProperty]string{MetricPropertyQos: qos}
+	},
 }
 
 // point is the struct to describe metric
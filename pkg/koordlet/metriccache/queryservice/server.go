@@ -0,0 +1,266 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryservice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	promstorage "github.com/prometheus/prometheus/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+const (
+	// DefaultQueryWindow is the time range of the query when the start time is not specified.
+	DefaultQueryWindow = 5 * time.Minute
+
+	// HTTPQueryPath is the path of the HTTP/JSON query API, which accepts a QueryRequest in the POST body.
+	HTTPQueryPath = "/v1alpha1/query"
+
+	// socketFileMode restricts the access of the query sockets to the owner, i.e. the user running the koordlet.
+	socketFileMode os.FileMode = 0600
+
+	metricNameLabel = "__name__"
+)
+
+var errTooManyPoints = errors.New("too many points")
+
+var _ MetricQueryServiceServer = &Server{}
+
+// Server serves the read-only queries of the metric cache for the external agents and debugging tools.
+type Server struct {
+	UnimplementedMetricQueryServiceServer
+	config  *metriccache.Config
+	storage metriccache.Queryable
+}
+
+func NewServer(config *metriccache.Config, storage metriccache.Queryable) *Server {
+	return &Server{
+		config:  config,
+		storage: storage,
+	}
+}
+
+// Run serves the gRPC and HTTP/JSON query services on the configured unix domain sockets until the stopCh is closed.
+func (s *Server) Run(stopCh <-chan struct{}) error {
+	if s.config.QuerySocketPath == "" && s.config.QueryHTTPSocketPath == "" {
+		klog.V(4).Infof("metric query service is disabled")
+		return nil
+	}
+
+	errCh := make(chan error, 2)
+	var grpcServer *grpc.Server
+	if s.config.QuerySocketPath != "" {
+		lis, err := listenUnix(s.config.QuerySocketPath)
+		if err != nil {
+			return err
+		}
+		grpcServer = grpc.NewServer(metricreport.ForceServerCodec())
+		RegisterMetricQueryServiceServer(grpcServer, s)
+		go func() {
+			errCh <- grpcServer.Serve(lis)
+		}()
+		defer grpcServer.Stop()
+		klog.Infof("start metric query gRPC service on %s", s.config.QuerySocketPath)
+	}
+	if s.config.QueryHTTPSocketPath != "" {
+		lis, err := listenUnix(s.config.QueryHTTPSocketPath)
+		if err != nil {
+			return err
+		}
+		httpServer := &http.Server{Handler: s.HTTPHandler()}
+		go func() {
+			if err := httpServer.Serve(lis); err != http.ErrServerClosed {
+				errCh <- err
+			}
+		}()
+		defer httpServer.Close()
+		klog.Infof("start metric query HTTP service on %s", s.config.QueryHTTPSocketPath)
+	}
+
+	select {
+	case <-stopCh:
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("metric query service exited, err: %w", err)
+	}
+}
+
+func (s *Server) Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error) {
+	if req.MetricKind == "" {
+		return nil, status.Error(codes.InvalidArgument, "metricKind is required")
+	}
+	end := time.Now()
+	if req.EndTime != nil {
+		end = *req.EndTime
+	}
+	start := end.Add(-DefaultQueryWindow)
+	if req.StartTime != nil {
+		start = *req.StartTime
+	}
+	if start.After(end) {
+		return nil, status.Errorf(codes.InvalidArgument, "startTime %v is after endTime %v", start, end)
+	}
+
+	properties := map[string]string{}
+	for k, v := range req.Properties {
+		properties[k] = v
+	}
+	if req.PodUID != "" {
+		properties[string(metriccache.MetricPropertyPodUID)] = req.PodUID
+	}
+	if req.ContainerID != "" {
+		properties[string(metriccache.MetricPropertyContainerID)] = req.ContainerID
+	}
+
+	querier, err := s.storage.Querier(start, end)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create querier, err: %v", err)
+	}
+	result := &seriesResult{kind: req.MetricKind, properties: properties, maxPoints: s.config.QueryMaxPoints}
+	if err = querier.QueryAndClose(result, nil, result); errors.Is(err, errTooManyPoints) {
+		return nil, status.Errorf(codes.ResourceExhausted, "query returns more than %d points, please narrow the time range", s.config.QueryMaxPoints)
+	} else if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to query metric %s, err: %v", req.MetricKind, err)
+	}
+	return &QueryResponse{Series: result.sortedSeries()}, nil
+}
+
+// HTTPHandler returns the handler of the HTTP/JSON query API.
+func (s *Server) HTTPHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(HTTPQueryPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		req := &QueryRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid query request, err: %v", err), http.StatusBadRequest)
+			return
+		}
+		resp, err := s.Query(r.Context(), req)
+		if err != nil {
+			code := http.StatusInternalServerError
+			switch status.Code(err) {
+			case codes.InvalidArgument, codes.ResourceExhausted:
+				code = http.StatusBadRequest
+			}
+			http.Error(w, status.Convert(err).Message(), code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err = json.NewEncoder(w).Encode(resp); err != nil {
+			klog.V(4).Infof("failed to write metric query response, err: %v", err)
+		}
+	})
+	return mux
+}
+
+// listenUnix listens on the unix domain socket and restricts the access of the socket file.
+func listenUnix(path string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create dir of socket %s, err: %w", path, err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket %s, err: %w", path, err)
+	}
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on socket %s, err: %w", path, err)
+	}
+	if err = os.Chmod(path, socketFileMode); err != nil {
+		_ = lis.Close()
+		return nil, fmt.Errorf("failed to chmod socket %s, err: %w", path, err)
+	}
+	return lis, nil
+}
+
+var _ metriccache.MetricResult = &seriesResult{}
+
+// seriesResult collects the raw series of the query, it is also the MetricMeta of the query.
+type seriesResult struct {
+	kind       string
+	properties map[string]string
+	maxPoints  int
+	points     int
+	series     []Series
+	// seriesKeys are the label strings of the series, which are used to sort the series
+	seriesKeys []string
+}
+
+func (r *seriesResult) GetKind() string {
+	return r.kind
+}
+
+func (r *seriesResult) GetProperties() map[string]string {
+	return r.properties
+}
+
+func (r *seriesResult) AddSeries(series promstorage.Series) error {
+	properties := series.Labels().Map()
+	delete(properties, metricNameLabel)
+	out := Series{Properties: properties, Points: []Point{}}
+	it := series.Iterator()
+	for it.Next() {
+		if r.maxPoints > 0 && r.points >= r.maxPoints {
+			return errTooManyPoints
+		}
+		t, v := it.At()
+		out.Points = append(out.Points, Point{Timestamp: t, Value: v})
+		r.points++
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	r.series = append(r.series, out)
+	r.seriesKeys = append(r.seriesKeys, series.Labels().String())
+	return nil
+}
+
+// sortedSeries returns the series sorted by the labels, since the storage does not sort them.
+func (r *seriesResult) sortedSeries() []Series {
+	sort.Sort(r)
+	return r.series
+}
+
+func (r *seriesResult) Len() int {
+	return len(r.series)
+}
+
+func (r *seriesResult) Less(i, j int) bool {
+	return r.seriesKeys[i] < r.seriesKeys[j]
+}
+
+func (r *seriesResult) Swap(i, j int) {
+	r.series[i], r.series[j] = r.series[j], r.series[i]
+	r.seriesKeys[i], r.seriesKeys[j] = r.seriesKeys[j], r.seriesKeys[i]
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryservice
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+)
+
+func newTestStorage(t *testing.T, now time.Time) metriccache.TSDBStorage {
+	conf := metriccache.NewDefaultConfig()
+	conf.TSDBPath = t.TempDir()
+	conf.TSDBEnablePromMetrics = false
+	storage, err := metriccache.NewTSDBStorage(conf)
+	assert.NoError(t, err)
+
+	var samples []metriccache.MetricSample
+	for _, s := range []struct {
+		podUID string
+		ts     time.Time
+		value  float64
+	}{
+		{podUID: "pod-2", ts: now.Add(-10 * time.Minute), value: 4},
+		{podUID: "pod-1", ts: now.Add(-3 * time.Second), value: 1},
+		{podUID: "pod-1", ts: now.Add(-2 * time.Second), value: 2},
+		{podUID: "pod-2", ts: now.Add(-2 * time.Second), value: 3},
+	} {
+		sample, err := metriccache.PodCPUUsageMetric.GenerateSample(map[metriccache.MetricProperty]string{
+			metriccache.MetricPropertyPodUID: s.podUID,
+		}, s.ts, s.value)
+		assert.NoError(t, err)
+		samples = append(samples, sample)
+	}
+	appender := storage.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+	return storage
+}
+
+func TestServer_Query(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	storage := newTestStorage(t, now)
+	defer storage.Close()
+	start := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		maxPoints int
+		req       *QueryRequest
+		want      *QueryResponse
+		wantCode  codes.Code
+	}{
+		{
+			name: "query pod series",
+			req:  &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), PodUID: "pod-1", EndTime: &now},
+			want: &QueryResponse{Series: []Series{
+				{
+					Properties: map[string]string{"pod_uid": "pod-1"},
+					Points: []Point{
+						{Timestamp: now.Add(-3 * time.Second).UnixMilli(), Value: 1},
+						{Timestamp: now.Add(-2 * time.Second).UnixMilli(), Value: 2},
+					},
+				},
+			}},
+		},
+		{
+			name: "query all pods in the default window",
+			req:  &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), EndTime: &now},
+			want: &QueryResponse{Series: []Series{
+				{
+					Properties: map[string]string{"pod_uid": "pod-1"},
+					Points: []Point{
+						{Timestamp: now.Add(-3 * time.Second).UnixMilli(), Value: 1},
+						{Timestamp: now.Add(-2 * time.Second).UnixMilli(), Value: 2},
+					},
+				},
+				{
+					Properties: map[string]string{"pod_uid": "pod-2"},
+					Points: []Point{
+						{Timestamp: now.Add(-2 * time.Second).UnixMilli(), Value: 3},
+					},
+				},
+			}},
+		},
+		{
+			name: "query no series",
+			req:  &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), ContainerID: "container-1", StartTime: &start, EndTime: &now},
+			want: &QueryResponse{},
+		},
+		{
+			name:     "missing metric kind",
+			req:      &QueryRequest{PodUID: "pod-1"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid time range",
+			req:      &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), StartTime: &now, EndTime: &start},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:      "too many points",
+			maxPoints: 3,
+			req:       &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), StartTime: &start, EndTime: &now},
+			wantCode:  codes.ResourceExhausted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := metriccache.NewDefaultConfig()
+			if tt.maxPoints > 0 {
+				conf.QueryMaxPoints = tt.maxPoints
+			}
+			s := NewServer(conf, storage)
+			got, err := s.Query(context.TODO(), tt.req)
+			assert.Equal(t, tt.wantCode, status.Code(err), err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServer_Run(t *testing.T) {
+	now := time.UnixMilli(time.Now().UnixMilli())
+	storage := newTestStorage(t, now)
+	defer storage.Close()
+
+	dir := t.TempDir()
+	conf := metriccache.NewDefaultConfig()
+	conf.QuerySocketPath = filepath.Join(dir, "query.sock")
+	conf.QueryHTTPSocketPath = filepath.Join(dir, "query-http.sock")
+	s := NewServer(conf, storage)
+	stopCh := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.Run(stopCh)
+	}()
+	assert.Eventually(t, func() bool {
+		_, err1 := os.Stat(conf.QuerySocketPath)
+		_, err2 := os.Stat(conf.QueryHTTPSocketPath)
+		return err1 == nil && err2 == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	info, err := os.Stat(conf.QuerySocketPath)
+	assert.NoError(t, err)
+	assert.Equal(t, socketFileMode, info.Mode().Perm())
+
+	req := &QueryRequest{MetricKind: string(metriccache.PodMetricCPUUsage), PodUID: "pod-2", EndTime: &now}
+	wantSeries := []Series{
+		{
+			Properties: map[string]string{"pod_uid": "pod-2"},
+			Points:     []Point{{Timestamp: now.Add(-2 * time.Second).UnixMilli(), Value: 3}},
+		},
+	}
+
+	// query via gRPC
+	conn, err := grpc.DialContext(context.TODO(), "unix://"+conf.QuerySocketPath,
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	resp, err := NewMetricQueryServiceClient(conn).Query(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, wantSeries, resp.Series)
+
+	// query via HTTP
+	httpClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", conf.QueryHTTPSocketPath)
+		},
+	}}
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+	httpResp, err := httpClient.Post("http://unix"+HTTPQueryPath, "application/json", bytes.NewReader(body))
+	assert.NoError(t, err)
+	defer httpResp.Body.Close()
+	assert.Equal(t, http.StatusOK, httpResp.StatusCode)
+	gotResp := &QueryResponse{}
+	assert.NoError(t, json.NewDecoder(httpResp.Body).Decode(gotResp))
+	assert.Equal(t, wantSeries, gotResp.Series)
+
+	badResp, err := httpClient.Post("http://unix"+HTTPQueryPath, "application/json", bytes.NewReader([]byte(`{}`)))
+	assert.NoError(t, err)
+	badResp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, badResp.StatusCode)
+
+	close(stopCh)
+	assert.NoError(t, <-errCh)
+}
+
+func TestServer_RunDisabled(t *testing.T) {
+	s := NewServer(metriccache.NewDefaultConfig(), nil)
+	assert.NoError(t, s.Run(make(chan struct{})))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package queryservice
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+const (
+	ServiceName     = "koordinator.koordlet.v1alpha1.MetricQueryService"
+	QueryMethodName = "Query"
+	QueryMethod     = "/" + ServiceName + "/" + QueryMethodName
+
+	// CodecName is the content-subtype of the query calls, the messages are encoded in JSON with the codec
+	// of the metricreport package.
+	CodecName = metricreport.CodecName
+)
+
+// QueryRequest queries the time series of a metric kind in the time range.
+type QueryRequest struct {
+	// MetricKind is the kind of the metric, e.g. pod_cpu_usage, container_memory_usage.
+	MetricKind string `json:"metricKind"`
+	// PodUID filters the series by the pod uid if specified.
+	PodUID string `json:"podUID,omitempty"`
+	// ContainerID filters the series by the container id if specified.
+	ContainerID string `json:"containerID,omitempty"`
+	// Properties filters the series by the other properties, e.g. gpu_minor.
+	Properties map[string]string `json:"properties,omitempty"`
+	// StartTime is the start of the time range, defaults to DefaultQueryWindow before the EndTime.
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// EndTime is the end of the time range, defaults to now.
+	EndTime *time.Time `json:"endTime,omitempty"`
+}
+
+// QueryResponse contains the series matching the query.
+type QueryResponse struct {
+	Series []Series `json:"series"`
+}
+
+// Series is a time series of the metric identified by its properties.
+type Series struct {
+	Properties map[string]string `json:"properties,omitempty"`
+	Points     []Point           `json:"points"`
+}
+
+type Point struct {
+	// Timestamp is the unix timestamp in milliseconds.
+	Timestamp int64   `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// MetricQueryServiceClient is the client API for the MetricQueryService.
+type MetricQueryServiceClient interface {
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+}
+
+type metricQueryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMetricQueryServiceClient(cc grpc.ClientConnInterface) MetricQueryServiceClient {
+	return &metricQueryServiceClient{cc}
+}
+
+func (c *metricQueryServiceClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	opts = append([]grpc.CallOption{metricreport.ForceCodec()}, opts...)
+	out := new(QueryResponse)
+	if err := c.cc.Invoke(ctx, QueryMethod, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MetricQueryServiceServer is the server API for the MetricQueryService.
+type MetricQueryServiceServer interface {
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+}
+
+// UnimplementedMetricQueryServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedMetricQueryServiceServer struct{}
+
+func (UnimplementedMetricQueryServiceServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+
+func RegisterMetricQueryServiceServer(s grpc.ServiceRegistrar, srv MetricQueryServiceServer) {
+	s.RegisterService(&MetricQueryService_ServiceDesc, srv)
+}
+
+func _MetricQueryService_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MetricQueryServiceServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QueryMethod,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MetricQueryServiceServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MetricQueryService_ServiceDesc is the grpc.ServiceDesc for the MetricQueryService.
+var MetricQueryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*MetricQueryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: QueryMethodName,
+			Handler:    _MetricQueryService_Query_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/koordlet/metriccache/queryservice/service.go",
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+const (
+	remoteWriteMinBackoff = 500 * time.Millisecond
+	remoteWriteMaxBackoff = 10 * time.Second
+)
+
+// remoteWriter streams the samples appended to the metric cache to a prometheus compatible remote write endpoint,
+// e.g. Prometheus, VictoriaMetrics. The samples are buffered in a bounded queue and sent in batches. When the
+// endpoint cannot keep up, the queue fills up and the new samples are dropped instead of blocking the appenders.
+type remoteWriter struct {
+	url            string
+	client         *http.Client
+	externalLabels []prompb.Label
+	batchSize      int
+	flushInterval  time.Duration
+	maxRetries     int
+	queue          chan prompb.TimeSeries
+
+	podLabelsLock sync.RWMutex
+	// podLabels is the extra labels of the pods by the pod uid
+	podLabels map[string]map[string]string
+}
+
+func newRemoteWriter(conf *Config) *remoteWriter {
+	externalLabels := make([]prompb.Label, 0, len(conf.RemoteWriteExternalLabels))
+	for name, value := range conf.RemoteWriteExternalLabels {
+		externalLabels = append(externalLabels, prompb.Label{Name: name, Value: value})
+	}
+	batchSize := conf.RemoteWriteBatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	queueCapacity := conf.RemoteWriteQueueCapacity
+	if queueCapacity < batchSize {
+		queueCapacity = batchSize
+	}
+	return &remoteWriter{
+		url:            conf.RemoteWriteURL,
+		client:         &http.Client{Timeout: conf.RemoteWriteTimeout},
+		externalLabels: externalLabels,
+		batchSize:      batchSize,
+		flushInterval:  conf.RemoteWriteFlushInterval,
+		maxRetries:     conf.RemoteWriteMaxRetries,
+		queue:          make(chan prompb.TimeSeries, queueCapacity),
+	}
+}
+
+// UpdatePodLabels replaces the extra labels attached to the samples of the pods.
+func (w *remoteWriter) UpdatePodLabels(podLabels map[string]map[string]string) {
+	w.podLabelsLock.Lock()
+	defer w.podLabelsLock.Unlock()
+	w.podLabels = podLabels
+}
+
+func (w *remoteWriter) getPodLabels(podUID string) map[string]string {
+	w.podLabelsLock.RLock()
+	defer w.podLabelsLock.RUnlock()
+	return w.podLabels[podUID]
+}
+
+// Enqueue adds the samples into the sending queue without blocking. The samples are dropped if the queue is full.
+func (w *remoteWriter) Enqueue(samples []MetricSample) {
+	dropped := 0
+	for _, s := range samples {
+		select {
+		case w.queue <- w.toTimeSeries(s):
+		default:
+			dropped++
+		}
+	}
+	if dropped > 0 {
+		klog.V(4).Infof("remote write queue is full, drop %d samples", dropped)
+		metrics.RecordMetricCacheRemoteWriteSamples(metrics.RemoteWriteStatusDropped, dropped)
+	}
+	metrics.RecordMetricCacheRemoteWritePendingSamples(len(w.queue))
+}
+
+func (w *remoteWriter) Run(stopCh <-chan struct{}) {
+	klog.V(4).Infof("start metric cache remote write to %s", w.url)
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+	batch := make([]prompb.TimeSeries, 0, w.batchSize)
+	for {
+		select {
+		case ts := <-w.queue:
+			batch = append(batch, ts)
+			if len(batch) >= w.batchSize {
+				w.flush(batch, stopCh)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch, stopCh)
+				batch = batch[:0]
+			}
+		case <-stopCh:
+			klog.V(4).Infof("stop metric cache remote write, %d samples pending", len(batch)+len(w.queue))
+			return
+		}
+	}
+}
+
+// flush sends the batch with retries, and drops the batch if all retries failed.
+func (w *remoteWriter) flush(batch []prompb.TimeSeries, stopCh <-chan struct{}) {
+	defer metrics.RecordMetricCacheRemoteWritePendingSamples(len(w.queue))
+	data, err := encodeWriteRequest(batch)
+	if err != nil {
+		klog.Warningf("failed to encode remote write request, drop %d samples, err: %v", len(batch), err)
+		metrics.RecordMetricCacheRemoteWriteSamples(metrics.RemoteWriteStatusFailed, len(batch))
+		return
+	}
+	backoff := remoteWriteMinBackoff
+	for i := 0; ; i++ {
+		retryable, err := w.send(data)
+		if err == nil {
+			metrics.RecordMetricCacheRemoteWriteSamples(metrics.RemoteWriteStatusSucceeded, len(batch))
+			return
+		}
+		if !retryable || i >= w.maxRetries {
+			klog.Warningf("failed to remote write %d samples after %d retries, err: %v", len(batch), i, err)
+			metrics.RecordMetricCacheRemoteWriteSamples(metrics.RemoteWriteStatusFailed, len(batch))
+			return
+		}
+		klog.V(5).Infof("failed to remote write %d samples, retry after %v, err: %v", len(batch), backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-stopCh:
+			metrics.RecordMetricCacheRemoteWriteSamples(metrics.RemoteWriteStatusFailed, len(batch))
+			return
+		}
+		backoff *= 2
+		if backoff > remoteWriteMaxBackoff {
+			backoff = remoteWriteMaxBackoff
+		}
+	}
+}
+
+// send posts the encoded write request, and returns whether the request can be retried if it failed.
+func (w *remoteWriter) send(data []byte) (bool, error) {
+	start := time.Now()
+	defer func() {
+		metrics.RecordMetricCacheRemoteWriteDuration(time.Since(start).Seconds())
+	}()
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, w.url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return true, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	err = fmt.Errorf("server returned HTTP status %s: %s", resp.Status, string(body))
+	// the client errors are not retryable except the rate limiting
+	return resp.StatusCode/100 == 5 || resp.StatusCode == http.StatusTooManyRequests, err
+}
+
+func (w *remoteWriter) toTimeSeries(s MetricSample) prompb.TimeSeries {
+	properties := s.GetProperties()
+	lbs := make([]prompb.Label, 0, len(properties)+len(w.externalLabels)+1)
+	lbs = append(lbs, prompb.Label{Name: metricLabelName, Value: s.GetKind()})
+	for name, value := range properties {
+		if name == metricLabelName {
+			continue
+		}
+		lbs = append(lbs, prompb.Label{Name: name, Value: value})
+	}
+	var podLabels map[string]string
+	if podUID, ok := properties[string(MetricPropertyPodUID)]; ok {
+		podLabels = w.getPodLabels(podUID)
+		for name, value := range podLabels {
+			if _, exist := properties[name]; !exist && name != metricLabelName {
+				lbs = append(lbs, prompb.Label{Name: name, Value: value})
+			}
+		}
+	}
+	// the labels of the sample take precedence over the pod labels and the external labels
+	for _, l := range w.externalLabels {
+		if _, ok := properties[l.Name]; ok || l.Name == metricLabelName {
+			continue
+		}
+		if _, ok := podLabels[l.Name]; !ok {
+			lbs = append(lbs, l)
+		}
+	}
+	sort.Slice(lbs, func(i, j int) bool {
+		return lbs[i].Name < lbs[j].Name
+	})
+	return prompb.TimeSeries{
+		Labels:  lbs,
+		Samples: []prompb.Sample{{Timestamp: s.timestamp(), Value: s.value()}},
+	}
+}
+
+func encodeWriteRequest(series []prompb.TimeSeries) ([]byte, error) {
+	req := &prompb.WriteRequest{Timeseries: series}
+	data, err := req.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return snappy.Encode(nil, data), nil
+}
+
+var _ Appender = &remoteWriteAppender{}
+
+// remoteWriteAppender enqueues the samples to the remote writer after they are committed to the local storage.
+type remoteWriteAppender struct {
+	Appender
+	writer  *remoteWriter
+	samples []MetricSample
+}
+
+func (a *remoteWriteAppender) Append(samples []MetricSample) error {
+	if err := a.Appender.Append(samples); err != nil {
+		return err
+	}
+	a.samples = append(a.samples, samples...)
+	return nil
+}
+
+func (a *remoteWriteAppender) Commit() error {
+	if err := a.Appender.Commit(); err != nil {
+		return err
+	}
+	a.writer.Enqueue(a.samples)
+	a.samples = nil
+	return nil
+}
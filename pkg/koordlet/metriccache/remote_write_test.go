@@ -0,0 +1,209 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeRemoteWriteServer struct {
+	lock       sync.Mutex
+	series     []prompb.TimeSeries
+	failed     int
+	statusCode int
+}
+
+func (f *fakeRemoteWriteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.failed > 0 {
+		f.failed--
+		w.WriteHeader(f.statusCode)
+		return
+	}
+	compressed, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	req := &prompb.WriteRequest{}
+	if err = req.Unmarshal(data); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.series = append(f.series, req.Timeseries...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (f *fakeRemoteWriteServer) getSeries() []prompb.TimeSeries {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.series
+}
+
+func Test_remoteWriter_toTimeSeries(t *testing.T) {
+	w := newRemoteWriter(&Config{
+		RemoteWriteURL: "http://localhost:8428/api/v1/write",
+		RemoteWriteExternalLabels: map[string]string{
+			"cluster": "test",
+			"pod_uid": "external",
+		},
+		RemoteWriteBatchSize: 10,
+	})
+	now := time.Now()
+	s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod("test-pod"), now, 1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: string(PodMetricCPUUsage)},
+			{Name: "cluster", Value: "test"},
+			{Name: "pod_uid", Value: "test-pod"},
+		},
+		Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 1.5}},
+	}, w.toTimeSeries(s))
+
+	// the pod labels are attached to the samples of the pod
+	w.UpdatePodLabels(map[string]map[string]string{
+		"test-pod": {
+			"pod_owner_kind": "Deployment",
+			"pod_owner_name": "test-deploy",
+			"pod_quota_name": "team-a",
+			"cluster":        "pod",
+			"pod_uid":        "ignored",
+		},
+	})
+	assert.Equal(t, prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: string(PodMetricCPUUsage)},
+			{Name: "cluster", Value: "pod"},
+			{Name: "pod_owner_kind", Value: "Deployment"},
+			{Name: "pod_owner_name", Value: "test-deploy"},
+			{Name: "pod_quota_name", Value: "team-a"},
+			{Name: "pod_uid", Value: "test-pod"},
+		},
+		Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 1.5}},
+	}, w.toTimeSeries(s))
+	nodeSample, err := NodeCPUUsageMetric.GenerateSample(nil, now, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, prompb.TimeSeries{
+		Labels: []prompb.Label{
+			{Name: "__name__", Value: string(NodeMetricCPUUsage)},
+			{Name: "cluster", Value: "test"},
+			{Name: "pod_uid", Value: "external"},
+		},
+		Samples: []prompb.Sample{{Timestamp: now.UnixMilli(), Value: 2}},
+	}, w.toTimeSeries(nodeSample))
+}
+
+func Test_metricCache_RemoteWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		failed     int
+		statusCode int
+		wantSent   bool
+	}{
+		{
+			name:     "send samples",
+			wantSent: true,
+		},
+		{
+			name:       "retry on server error",
+			failed:     1,
+			statusCode: http.StatusServiceUnavailable,
+			wantSent:   true,
+		},
+		{
+			name:       "drop samples on client error",
+			failed:     1,
+			statusCode: http.StatusBadRequest,
+			wantSent:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeServer := &fakeRemoteWriteServer{failed: tt.failed, statusCode: tt.statusCode}
+			server := httptest.NewServer(fakeServer)
+			defer server.Close()
+
+			conf := NewDefaultConfig()
+			conf.TSDBPath = t.TempDir()
+			conf.TSDBEnablePromMetrics = false
+			conf.RemoteWriteURL = server.URL
+			conf.RemoteWriteBatchSize = 2
+			conf.RemoteWriteFlushInterval = 50 * time.Millisecond
+			m, err := NewMetricCache(conf)
+			assert.NoError(t, err)
+			defer m.Close()
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go m.(*metricCache).remoteWriter.Run(stopCh)
+
+			now := time.Now()
+			var samples []MetricSample
+			for _, uid := range []string{"pod-1", "pod-2", "pod-3"} {
+				s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod(uid), now, 1)
+				assert.NoError(t, err)
+				samples = append(samples, s)
+			}
+			appender := m.Appender()
+			assert.NoError(t, appender.Append(samples))
+			assert.NoError(t, appender.Commit())
+
+			if !tt.wantSent {
+				time.Sleep(500 * time.Millisecond)
+				// the first batch is dropped
+				assert.Equal(t, 1, len(fakeServer.getSeries()))
+				return
+			}
+			assert.Eventually(t, func() bool {
+				return len(fakeServer.getSeries()) == len(samples)
+			}, 5*time.Second, 50*time.Millisecond)
+		})
+	}
+}
+
+func Test_remoteWriter_Enqueue(t *testing.T) {
+	w := newRemoteWriter(&Config{
+		RemoteWriteURL:           "http://localhost:8428/api/v1/write",
+		RemoteWriteBatchSize:     1,
+		RemoteWriteQueueCapacity: 2,
+	})
+	now := time.Now()
+	var samples []MetricSample
+	for _, uid := range []string{"pod-1", "pod-2", "pod-3"} {
+		s, err := PodCPUUsageMetric.GenerateSample(MetricPropertiesFunc.Pod(uid), now, 1)
+		assert.NoError(t, err)
+		samples = append(samples, s)
+	}
+	// the samples exceeding the queue capacity are dropped
+	w.Enqueue(samples)
+	assert.Equal(t, 2, len(w.queue))
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/oklog/ulid"
+	"github.com/prometheus/prometheus/model/labels"
+	promstorage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// downsampledHintPrefix is the compaction hint of the block meta recording the resolution of a downsampled block.
+	downsampledHintPrefix = "koordlet-downsampled-resolution-ms="
+	// downsampleTmpDir is the directory under the TSDB path to build the downsampled blocks. It is not a block dir so
+	// the TSDB never loads it.
+	downsampleTmpDir = "downsample.tmp"
+	metaFilename     = "meta.json"
+)
+
+var timeNow = time.Now
+
+// DownsampleRule keeps the samples at the Resolution until they are older than KeepFor.
+type DownsampleRule struct {
+	Resolution time.Duration
+	KeepFor    time.Duration
+}
+
+// DownsampleRules are the downsample rules sorted by KeepFor, e.g. "5s:10m,1m:24h" keeps 5s resolution for
+// 10 minutes, then 1m resolution for 24 hours. The samples older than all rules keep the resolution of the last rule.
+// It implements the flag.Value.
+type DownsampleRules []DownsampleRule
+
+func (r *DownsampleRules) String() string {
+	if r == nil {
+		return ""
+	}
+	ss := make([]string, 0, len(*r))
+	for _, rule := range *r {
+		ss = append(ss, rule.Resolution.String()+":"+rule.KeepFor.String())
+	}
+	return strings.Join(ss, ",")
+}
+
+func (r *DownsampleRules) Set(value string) error {
+	var rules DownsampleRules
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		parts := strings.Split(s, ":")
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid downsample rule %q, expect resolution:keepFor", s)
+		}
+		resolution, err := time.ParseDuration(parts[0])
+		if err != nil {
+			return fmt.Errorf("invalid resolution of downsample rule %q, err: %w", s, err)
+		}
+		keepFor, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid keepFor of downsample rule %q, err: %w", s, err)
+		}
+		rules = append(rules, DownsampleRule{Resolution: resolution, KeepFor: keepFor})
+	}
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	*r = rules
+	return nil
+}
+
+// Validate checks if the rules are sorted by KeepFor, and the resolution does not get finer as the samples age.
+func (r DownsampleRules) Validate() error {
+	for i, rule := range r {
+		if rule.Resolution < time.Millisecond || rule.KeepFor <= 0 {
+			return fmt.Errorf("invalid downsample rule %s:%s, resolution and keepFor must be positive", rule.Resolution, rule.KeepFor)
+		}
+		if i > 0 && (rule.KeepFor <= r[i-1].KeepFor || rule.Resolution < r[i-1].Resolution) {
+			return fmt.Errorf("invalid downsample rule %s:%s, keepFor must be increasing and resolution must not decrease",
+				rule.Resolution, rule.KeepFor)
+		}
+	}
+	return nil
+}
+
+// resolutionFor returns the resolution of the samples at the age.
+func (r DownsampleRules) resolutionFor(age time.Duration) time.Duration {
+	if len(r) <= 0 {
+		return 0
+	}
+	for _, rule := range r {
+		if age < rule.KeepFor {
+			return rule.Resolution
+		}
+	}
+	return r[len(r)-1].Resolution
+}
+
+// tsdbDownsampler downsamples the persisted blocks of the TSDB according to the rules.
+// A block is rewritten with the average of the samples in each resolution interval, and the new block lists the
+// origin block as its parent, so the TSDB replaces the origin block on the next reload.
+// The samples in the head are not downsampled until they are persisted into blocks.
+type tsdbDownsampler struct {
+	db     *tsdb.DB
+	rules  DownsampleRules
+	logger log.Logger
+	// pending records the blocks which are downsampled but not replaced yet
+	pending map[ulid.ULID]struct{}
+}
+
+func newTSDBDownsampler(db *tsdb.DB, rules DownsampleRules, logger log.Logger) *tsdbDownsampler {
+	return &tsdbDownsampler{
+		db:      db,
+		rules:   rules,
+		logger:  logger,
+		pending: map[ulid.ULID]struct{}{},
+	}
+}
+
+func (d *tsdbDownsampler) downsample() {
+	now := timeNow()
+	blocks := d.db.Blocks()
+	loaded := make(map[ulid.ULID]struct{}, len(blocks))
+	for _, b := range blocks {
+		meta := b.Meta()
+		loaded[meta.ULID] = struct{}{}
+		if _, ok := d.pending[meta.ULID]; ok {
+			continue
+		}
+		resolution := d.rules.resolutionFor(now.Sub(time.UnixMilli(meta.MaxTime)))
+		if resolution <= 0 || getDownsampledResolution(&meta) >= resolution {
+			continue
+		}
+		start := time.Now()
+		if err := d.downsampleBlock(b, resolution); err != nil {
+			klog.Warningf("failed to downsample tsdb block %s to resolution %v, err: %v", meta.ULID, resolution, err)
+			continue
+		}
+		d.pending[meta.ULID] = struct{}{}
+		klog.V(4).Infof("downsample tsdb block %s to resolution %v finished, elapsed %v", meta.ULID, resolution, time.Since(start))
+	}
+	for id := range d.pending {
+		if _, ok := loaded[id]; !ok {
+			delete(d.pending, id)
+		}
+	}
+}
+
+func (d *tsdbDownsampler) downsampleBlock(b *tsdb.Block, resolution time.Duration) error {
+	meta := b.Meta()
+	series, err := downsampleBlockSeries(b, resolution.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("read block series failed, err: %w", err)
+	}
+	if len(series) <= 0 {
+		return nil
+	}
+
+	tmpDir := filepath.Join(d.db.Dir(), downsampleTmpDir)
+	if err = os.RemoveAll(tmpDir); err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err = os.MkdirAll(tmpDir, 0755); err != nil {
+		return err
+	}
+	blockDir, err := tsdb.CreateBlock(series, tmpDir, meta.MaxTime-meta.MinTime, d.logger)
+	if err != nil {
+		return fmt.Errorf("create block failed, err: %w", err)
+	}
+
+	// mark the origin block as the parent, and the TSDB deletes it once the new block is loaded
+	newMeta := &tsdb.BlockMeta{}
+	metaPath := filepath.Join(blockDir, metaFilename)
+	data, err := os.ReadFile(metaPath)
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, newMeta); err != nil {
+		return err
+	}
+	newMeta.Compaction.Level = meta.Compaction.Level
+	newMeta.Compaction.Parents = []tsdb.BlockDesc{{ULID: meta.ULID, MinTime: meta.MinTime, MaxTime: meta.MaxTime}}
+	newMeta.Compaction.Hints = append(newMeta.Compaction.Hints, downsampledHintPrefix+strconv.FormatInt(resolution.Milliseconds(), 10))
+	if data, err = json.MarshalIndent(newMeta, "", "\t"); err != nil {
+		return err
+	}
+	if err = os.WriteFile(metaPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(blockDir, filepath.Join(d.db.Dir(), newMeta.ULID.String()))
+}
+
+// downsampleBlockSeries returns the series of the block averaged in each resolution interval.
+// The averaged sample takes the timestamp of the last sample in the interval.
+func downsampleBlockSeries(b *tsdb.Block, resolution int64) ([]promstorage.Series, error) {
+	meta := b.Meta()
+	q, err := tsdb.NewBlockQuerier(b, meta.MinTime, meta.MaxTime)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	var series []promstorage.Series
+	ss := q.Select(false, nil, labels.MustNewMatcher(labels.MatchRegexp, metricLabelName, ".+"))
+	for ss.Next() {
+		s := ss.At()
+		var samples []tsdbutil.Sample
+		var bucket, lastT int64
+		var sum float64
+		var count int
+		it := s.Iterator()
+		for it.Next() {
+			t, v := it.At()
+			if count > 0 && t/resolution != bucket {
+				samples = append(samples, downsampledSample{t: lastT, v: sum / float64(count)})
+				sum, count = 0, 0
+			}
+			bucket, lastT = t/resolution, t
+			sum += v
+			count++
+		}
+		if it.Err() != nil {
+			return nil, it.Err()
+		}
+		if count > 0 {
+			samples = append(samples, downsampledSample{t: lastT, v: sum / float64(count)})
+		}
+		series = append(series, promstorage.NewListSeries(s.Labels(), samples))
+	}
+	if ss.Err() != nil {
+		return nil, ss.Err()
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return labels.Compare(series[i].Labels(), series[j].Labels()) < 0
+	})
+	return series, nil
+}
+
+func getDownsampledResolution(meta *tsdb.BlockMeta) time.Duration {
+	for _, hint := range meta.Compaction.Hints {
+		if !strings.HasPrefix(hint, downsampledHintPrefix) {
+			continue
+		}
+		ms, err := strconv.ParseInt(strings.TrimPrefix(hint, downsampledHintPrefix), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(ms) * time.Millisecond
+	}
+	return 0
+}
+
+type downsampledSample struct {
+	t int64
+	v float64
+}
+
+func (s downsampledSample) T() int64 {
+	return s.t
+}
+
+func (s downsampledSample) V() float64 {
+	return s.v
+}
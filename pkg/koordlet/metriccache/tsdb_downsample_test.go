@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	promstorage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsampleRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    DownsampleRules
+		wantErr bool
+	}{
+		{
+			name: "parse rules",
+			arg:  "5s:10m, 1m:24h",
+			want: DownsampleRules{
+				{Resolution: 5 * time.Second, KeepFor: 10 * time.Minute},
+				{Resolution: time.Minute, KeepFor: 24 * time.Hour},
+			},
+		},
+		{
+			name: "empty rules",
+			arg:  "",
+		},
+		{
+			name:    "invalid format",
+			arg:     "5s-10m",
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			arg:     "5x:10m",
+			wantErr: true,
+		},
+		{
+			name:    "keepFor not increasing",
+			arg:     "5s:10m,1m:5m",
+			wantErr: true,
+		},
+		{
+			name:    "resolution decreasing",
+			arg:     "1m:10m,5s:24h",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules DownsampleRules
+			err := rules.Set(tt.arg)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, rules)
+		})
+	}
+
+	rules := DownsampleRules{
+		{Resolution: 5 * time.Second, KeepFor: 10 * time.Minute},
+		{Resolution: time.Minute, KeepFor: 24 * time.Hour},
+	}
+	assert.Equal(t, "5s:10m0s,1m0s:24h0m0s", rules.String())
+	assert.Equal(t, 5*time.Second, rules.resolutionFor(time.Minute))
+	assert.Equal(t, time.Minute, rules.resolutionFor(time.Hour))
+	assert.Equal(t, time.Minute, rules.resolutionFor(48*time.Hour))
+	assert.Equal(t, time.Duration(0), DownsampleRules{}.resolutionFor(time.Hour))
+}
+
+func Test_tsdbDownsampler_downsample(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Minute)
+	base := now.Add(-2 * time.Hour).UnixMilli()
+	lset := labels.FromStrings(metricLabelName, string(NodeMetricCPUUsage))
+	var samples []tsdbutil.Sample
+	for i := 0; i < 600; i++ {
+		samples = append(samples, downsampledSample{t: base + int64(i)*1000, v: float64(i)})
+	}
+	_, err := tsdb.CreateBlock([]promstorage.Series{promstorage.NewListSeries(lset, samples)}, dir,
+		(2 * time.Hour).Milliseconds(), log.NewNopLogger())
+	assert.NoError(t, err)
+
+	oldTimeNow := timeNow
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = oldTimeNow
+	}()
+	conf := NewDefaultConfig()
+	conf.TSDBPath = dir
+	conf.TSDBEnablePromMetrics = false
+	assert.NoError(t, conf.TSDBDownsampleRules.Set("5s:10m,1m:24h"))
+	storage, err := NewTSDBStorage(conf)
+	assert.NoError(t, err)
+	s := storage.(*tsdbStorage)
+	assert.Equal(t, 1, len(s.db.Blocks()))
+	originID := s.db.Blocks()[0].Meta().ULID
+	s.downsampler.downsample()
+	assert.Equal(t, 1, len(s.downsampler.pending))
+	// the pending block is not downsampled again
+	s.downsampler.downsample()
+	assert.Equal(t, 1, len(s.downsampler.pending))
+	_, err = os.Stat(dir + "/" + downsampleTmpDir)
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, s.Close())
+
+	// the origin block is replaced after reloading
+	storage, err = NewTSDBStorage(conf)
+	assert.NoError(t, err)
+	s = storage.(*tsdbStorage)
+	defer s.Close()
+	blocks := s.db.Blocks()
+	assert.Equal(t, 1, len(blocks))
+	meta := blocks[0].Meta()
+	assert.NotEqual(t, originID, meta.ULID)
+	assert.Equal(t, time.Minute, getDownsampledResolution(&meta))
+
+	q, err := s.db.Querier(context.TODO(), base, base+int64(600*1000))
+	assert.NoError(t, err)
+	defer q.Close()
+	ss := q.Select(false, nil, labels.MustNewMatcher(labels.MatchEqual, metricLabelName, string(NodeMetricCPUUsage)))
+	var got []float64
+	for ss.Next() {
+		it := ss.At().Iterator()
+		for it.Next() {
+			_, v := it.At()
+			got = append(got, v)
+		}
+	}
+	assert.NoError(t, ss.Err())
+	// average of each minute
+	assert.Equal(t, []float64{29.5, 89.5, 149.5, 209.5, 269.5, 329.5, 389.5, 449.5, 509.5, 569.5}, got)
+
+	// the downsampled block is skipped
+	s.downsampler.downsample()
+	assert.Equal(t, 0, len(s.downsampler.pending))
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/tsdb"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+// openTSDB opens the tsdb under the conf.TSDBPath. If the persistence is enabled, the existing data is verified before
+// recovered: it is discarded if its size exceeds the limit or the tsdb fails to open it, so that a corrupted data dir
+// never blocks the koordlet from starting.
+func openTSDB(conf *Config, logger log.Logger, reg prometheus.Registerer, opts *tsdb.Options) (*tsdb.DB, error) {
+	if !conf.TSDBPersistEnabled {
+		return tsdb.Open(conf.TSDBPath, logger, reg, opts, nil)
+	}
+
+	size, err := dirSize(conf.TSDBPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get size of tsdb path %s, err: %w", conf.TSDBPath, err)
+	}
+	if conf.TSDBPersistMaxBytes > 0 && size > conf.TSDBPersistMaxBytes {
+		klog.Warningf("persisted tsdb data size %d exceeds the limit %d, discard it", size, conf.TSDBPersistMaxBytes)
+		metrics.RecordMetricCacheTSDBRecovery(metrics.TSDBRecoveryStatusOversized)
+		if err = resetDir(conf.TSDBPath); err != nil {
+			return nil, fmt.Errorf("failed to reset tsdb path %s, err: %w", conf.TSDBPath, err)
+		}
+	}
+
+	// verify the persisted data with a throwaway registry, since the collectors registered by a failed open cannot be
+	// registered again by the retry
+	db, err := tsdb.Open(conf.TSDBPath, logger, prometheus.NewRegistry(), opts, nil)
+	if err == nil {
+		blocks := len(db.Blocks())
+		if err = db.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close the verified tsdb, err: %w", err)
+		}
+		klog.V(4).Infof("recovered persisted tsdb data with %d blocks, size %d", blocks, size)
+		metrics.RecordMetricCacheTSDBRecovery(metrics.TSDBRecoveryStatusRecovered)
+	} else {
+		klog.Warningf("failed to open persisted tsdb data, discard it since it may be corrupted, err: %v", err)
+		metrics.RecordMetricCacheTSDBRecovery(metrics.TSDBRecoveryStatusCorrupted)
+		if err = resetDir(conf.TSDBPath); err != nil {
+			return nil, fmt.Errorf("failed to reset tsdb path %s, err: %w", conf.TSDBPath, err)
+		}
+	}
+	return tsdb.Open(conf.TSDBPath, logger, reg, opts, nil)
+}
+
+// dirSize returns the total size of the regular files under the dir, it returns zero if the dir does not exist.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// resetDir removes all the contents under the dir but keeps the dir itself, since it can be a mount point.
+func resetDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err = os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
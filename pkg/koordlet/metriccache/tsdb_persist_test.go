@@ -0,0 +1,132 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metriccache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	promstorage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+func Test_openTSDB(t *testing.T) {
+	nodeMeta, _ := NodeCPUUsageMetric.BuildQueryMeta(nil)
+	now := time.UnixMilli(time.Now().UnixMilli())
+	tests := []struct {
+		name              string
+		persist           bool
+		enablePromMetrics bool
+		prepareFn         func(t *testing.T, conf *Config)
+		wantRecovery      bool
+	}{
+		{
+			name:         "recover persisted samples",
+			persist:      true,
+			wantRecovery: true,
+		},
+		{
+			name:    "discard corrupted data",
+			persist: true,
+			prepareFn: func(t *testing.T, conf *Config) {
+				lset := labels.FromStrings(metricLabelName, string(NodeMetricCPUUsage))
+				series := promstorage.NewListSeries(lset, []tsdbutil.Sample{downsampledSample{t: 1000, v: 1}})
+				blockDir, err := tsdb.CreateBlock([]promstorage.Series{series}, conf.TSDBPath, 0, log.NewNopLogger())
+				assert.NoError(t, err)
+				// truncate the index of the block
+				assert.NoError(t, os.WriteFile(filepath.Join(blockDir, "index"), []byte("corrupted"), 0644))
+			},
+		},
+		{
+			name:              "discard corrupted data with prometheus metrics enabled",
+			persist:           true,
+			enablePromMetrics: true,
+			prepareFn: func(t *testing.T, conf *Config) {
+				lset := labels.FromStrings(metricLabelName, string(NodeMetricCPUUsage))
+				series := promstorage.NewListSeries(lset, []tsdbutil.Sample{downsampledSample{t: 1000, v: 1}})
+				blockDir, err := tsdb.CreateBlock([]promstorage.Series{series}, conf.TSDBPath, 0, log.NewNopLogger())
+				assert.NoError(t, err)
+				assert.NoError(t, os.WriteFile(filepath.Join(blockDir, "index"), []byte("corrupted"), 0644))
+			},
+		},
+		{
+			name:    "discard oversized data",
+			persist: true,
+			prepareFn: func(t *testing.T, conf *Config) {
+				assert.NoError(t, os.WriteFile(filepath.Join(conf.TSDBPath, "garbage"), make([]byte, 2048), 0644))
+				conf.TSDBPersistMaxBytes = 1024
+			},
+		},
+		{
+			name:         "open as usual when persistence disabled",
+			persist:      false,
+			wantRecovery: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			conf := NewDefaultConfig()
+			conf.TSDBPath = dir
+			conf.TSDBEnablePromMetrics = false
+			conf.TSDBPersistEnabled = tt.persist
+			conf.TSDBPersistMaxBytes = 1024 * 1024
+
+			storage, err := NewTSDBStorage(conf)
+			assert.NoError(t, err)
+			s, err := NodeCPUUsageMetric.GenerateSample(nil, now, 1)
+			assert.NoError(t, err)
+			appender := storage.Appender()
+			assert.NoError(t, appender.Append([]MetricSample{s}))
+			assert.NoError(t, appender.Commit())
+			assert.NoError(t, storage.Close())
+
+			if tt.prepareFn != nil {
+				tt.prepareFn(t, conf)
+			}
+			conf.TSDBEnablePromMetrics = tt.enablePromMetrics
+			if tt.enablePromMetrics {
+				// the collectors of the tsdb can only be registered once in a registry
+				oldRegistry := metrics.ExternalRegistry
+				metrics.ExternalRegistry = prometheus.NewRegistry()
+				defer func() { metrics.ExternalRegistry = oldRegistry }()
+			}
+
+			storage, err = NewTSDBStorage(conf)
+			assert.NoError(t, err)
+			defer storage.Close()
+			querier, err := storage.Querier(now.Add(-time.Minute), now.Add(time.Minute))
+			assert.NoError(t, err)
+			result := &aggregateResult{}
+			assert.NoError(t, querier.QueryAndClose(nodeMeta, nil, result))
+			if tt.wantRecovery {
+				assert.Equal(t, 1, result.Count())
+			} else {
+				assert.Equal(t, 0, result.Count())
+			}
+		})
+	}
+}
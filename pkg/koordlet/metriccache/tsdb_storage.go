@@ -88,7 +88,8 @@ var _ TSDBStorage = &tsdbStorage{}
 
 // tsdbStorage implements TSDBStorage
 type tsdbStorage struct {
-	db *tsdb.DB
+	db          *tsdb.DB
+	downsampler *tsdbDownsampler
 }
 
 func (t *tsdbStorage) Appender() Appender {
@@ -131,17 +132,24 @@ func NewTSDBStorage(conf *Config) (TSDBStorage, error) {
 	klog.V(5).Infof("ready to start tsdb with option %+v", tsdbOpt)
 
 	var promReg prometheus.Registerer
+	if err := conf.TSDBDownsampleRules.Validate(); err != nil {
+		return nil, err
+	}
 	if conf.TSDBEnablePromMetrics {
 		promReg = metrics.ExternalRegistry
 	}
 	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stderr))
-	db, err := tsdb.Open(conf.TSDBPath, log.With(logger, "component", "tsdb"), promReg, tsdbOpt, nil)
+	db, err := openTSDB(conf, log.With(logger, "component", "tsdb"), promReg, tsdbOpt)
 	if err != nil {
 		return nil, err
 	}
-	return &tsdbStorage{
+	s := &tsdbStorage{
 		db: db,
-	}, nil
+	}
+	if len(conf.TSDBDownsampleRules) > 0 {
+		s.downsampler = newTSDBDownsampler(db, conf.TSDBDownsampleRules, log.With(logger, "component", "tsdb-downsampler"))
+	}
+	return s, nil
 }
 
 var _ Appender = &tsdbAppender{}
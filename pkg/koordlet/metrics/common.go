@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"sync/atomic"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/koordinator-sh/koordinator/pkg/util/metrics"
@@ -127,12 +129,26 @@ func RecordCollectNodeLocalStorageInfoStatus(err error) {
 }
 
 func RecordPodEviction(namespace, podName, reasonType string) {
+	RecordPodEvictionWithActionID(namespace, podName, reasonType, "")
+}
+
+// podEvictionCount is the number of the pods evicted since koordlet started, which is reported in the NodeMetric.
+var podEvictionCount atomic.Int64
+
+// GetPodEvictionCount returns the number of the pods evicted since koordlet started.
+func GetPodEvictionCount() int64 {
+	return podEvictionCount.Load()
+}
+
+// RecordPodEvictionWithActionID records the pod eviction with the action id of the audit event as the exemplar.
+func RecordPodEvictionWithActionID(namespace, podName, reasonType, actionID string) {
+	podEvictionCount.Add(1)
 	labels := genNodeLabels()
 	if labels == nil {
 		return
 	}
 	labels[EvictionReasonKey] = reasonType
-	PodEviction.With(labels).Inc()
+	addCounterWithActionID(PodEviction.With(labels), actionID)
 
 	detailLabels := labelsClone(labels)
 	detailLabels[PodNamespace] = namespace
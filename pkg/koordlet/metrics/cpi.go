@@ -33,7 +33,7 @@ var (
 		Subsystem: KoordletSubsystem,
 		Name:      "container_cpi",
 		Help:      "Container cpi collected by koordlet",
-	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, CPIField})
+	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, CPIField})
 
 	CPICollectors = []prometheus.Collector{
 		ContainerCPI,
@@ -54,6 +54,7 @@ func RecordContainerCPI(status *corev1.ContainerStatus, pod *corev1.Pod, cycles,
 	labels[PodUID] = string(pod.UID)
 	labels[PodName] = pod.Name
 	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
 	labels[CPIField] = Cycles
 	ContainerCPI.With(labels).Set(cycles)
 
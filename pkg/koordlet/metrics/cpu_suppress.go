@@ -16,7 +16,11 @@ limitations under the License.
 
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
 
 var (
 	BESuppressCPU = prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -37,14 +41,39 @@ var (
 		Help:      "Number of cpu cores used by BE.",
 	}, []string{NodeKey})
 
+	BESuppressAction = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_suppress_action",
+		Help:      "Number of BE suppress actions taken by koordlet",
+	}, []string{NodeKey, BESuppressTypeKey})
+
 	CPUSuppressCollector = []prometheus.Collector{
 		BESuppressCPU,
+		BESuppressAction,
 		BESuppressLSUsedCPU,
 		BESuppressBEUsedCPU,
 	}
 )
 
+// lastBESuppressCores keeps the cores allowed for BE by the latest suppression, which is reported in the NodeMetric.
+var lastBESuppressCores = struct {
+	sync.RWMutex
+	value    float64
+	recorded bool
+}{}
+
+// GetLastBESuppressCores returns the cores allowed for BE by the latest suppression and whether any is recorded.
+func GetLastBESuppressCores() (float64, bool) {
+	lastBESuppressCores.RLock()
+	defer lastBESuppressCores.RUnlock()
+	return lastBESuppressCores.value, lastBESuppressCores.recorded
+}
+
 func RecordBESuppressCores(suppressType string, value float64) {
+	lastBESuppressCores.Lock()
+	lastBESuppressCores.value, lastBESuppressCores.recorded = value, true
+	lastBESuppressCores.Unlock()
+
 	labels := genNodeLabels()
 	if labels == nil {
 		return
@@ -68,3 +97,13 @@ func RecordBESuppressBEUsedCPU(value float64) {
 	}
 	BESuppressBEUsedCPU.With(labels).Set(value)
 }
+
+// RecordBESuppressAction records the BE suppress action with the action id of the audit event as the exemplar.
+func RecordBESuppressAction(suppressType string, actionID string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[BESuppressTypeKey] = suppressType
+	addCounterWithActionID(BESuppressAction.With(labels), actionID)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	CPUKey = "cpu"
+
+	ThermalThrottleTypeKey     = "type"
+	ThermalThrottleTypeCore    = "core"
+	ThermalThrottleTypePackage = "package"
+)
+
+var (
+	NodeCPUCoreFrequency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_core_frequency_mhz",
+		Help:      "Current frequency in MHz of the cpu collected by koordlet",
+	}, []string{NodeKey, CPUKey})
+
+	NodeCPUCoreTurboRatio = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_core_turbo_ratio",
+		Help:      "Ratio of the average frequency to the base frequency of the cpu in the last interval collected by koordlet, which is larger than 1 when the cpu runs in the turbo range",
+	}, []string{NodeKey, CPUKey})
+
+	NodeCPUThermalThrottleCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_thermal_throttle_count",
+		Help:      "Cumulative count of the thermal throttling events(core, package) of the cpu collected by koordlet",
+	}, []string{NodeKey, CPUKey, ThermalThrottleTypeKey})
+
+	NodeCPUThermalThrottled = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_cpu_thermal_throttled",
+		Help:      "Whether the cpus of the node are thermally throttled in the last interval, 1 for throttled and 0 for not",
+	}, []string{NodeKey})
+
+	CPUThermalCollectors = []prometheus.Collector{
+		NodeCPUCoreFrequency,
+		NodeCPUCoreTurboRatio,
+		NodeCPUThermalThrottleCount,
+		NodeCPUThermalThrottled,
+	}
+)
+
+func RecordNodeCPUCoreFrequency(cpu int32, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CPUKey] = strconv.Itoa(int(cpu))
+	NodeCPUCoreFrequency.With(labels).Set(value)
+}
+
+func RecordNodeCPUCoreTurboRatio(cpu int32, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CPUKey] = strconv.Itoa(int(cpu))
+	NodeCPUCoreTurboRatio.With(labels).Set(value)
+}
+
+func RecordNodeCPUThermalThrottleCount(cpu int32, throttleType string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[CPUKey] = strconv.Itoa(int(cpu))
+	labels[ThermalThrottleTypeKey] = throttleType
+	NodeCPUThermalThrottleCount.With(labels).Set(value)
+}
+
+func RecordNodeCPUThermalThrottled(throttled bool) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	value := 0.0
+	if throttled {
+		value = 1
+	}
+	NodeCPUThermalThrottled.With(labels).Set(value)
+}
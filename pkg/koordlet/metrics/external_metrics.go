@@ -36,4 +36,7 @@ func init() {
 	ExternalMustRegister(CPICollectors...)
 	ExternalMustRegister(PSICollectors...)
 	ExternalMustRegister(ResctrlCollectors...)
+	ExternalMustRegister(PowerCollectors...)
+	ExternalMustRegister(CPUThermalCollectors...)
+	ExternalMustRegister(NetworkLatencyCollectors...)
 }
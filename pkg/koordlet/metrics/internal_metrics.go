@@ -38,6 +38,7 @@ func internalMustRegister(metrics ...prometheus.Collector) {
 func init() {
 	internalMustRegister(CommonCollectors...)
 	internalMustRegister(CPUSuppressCollector...)
+	internalMustRegister(MemoryThrottleCollector...)
 	internalMustRegister(CPUBurstCollector...)
 	internalMustRegister(PredictionCollectors...)
 	internalMustRegister(CoreSchedCollector...)
@@ -45,4 +46,5 @@ func init() {
 	internalMustRegister(KubeletStubCollector...)
 	internalMustRegister(RuntimeHookCollectors...)
 	internalMustRegister(HostApplicationCollectors...)
+	internalMustRegister(MetricCacheCollectors...)
 }
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	BEMemoryThrottleTypeKey = "type"
+
+	// BEMemoryThrottleTypeThrottle means the memory.high of the BE pods is lowered by the node memory pressure.
+	BEMemoryThrottleTypeThrottle = "throttle"
+	// BEMemoryThrottleTypeRelease means the memory.high of the BE pods is reset since the node memory pressure is gone.
+	BEMemoryThrottleTypeRelease = "release"
+)
+
+var (
+	BEMemoryThrottleEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_throttle_events",
+		Help:      "Number of BE memory throttle events taken by koordlet",
+	}, []string{NodeKey, BEMemoryThrottleTypeKey})
+
+	BEMemoryThrottleLimit = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "be_memory_throttle_limit_bytes",
+		Help:      "Total memory.high in bytes set on the throttled BE pods by koordlet, zero if not throttled",
+	}, []string{NodeKey})
+
+	MemoryThrottleCollector = []prometheus.Collector{
+		BEMemoryThrottleEvents,
+		BEMemoryThrottleLimit,
+	}
+)
+
+func RecordBEMemoryThrottleEvent(throttleType string) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[BEMemoryThrottleTypeKey] = throttleType
+	BEMemoryThrottleEvents.With(labels).Inc()
+}
+
+func RecordBEMemoryThrottleLimit(value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	BEMemoryThrottleLimit.With(labels).Set(value)
+}
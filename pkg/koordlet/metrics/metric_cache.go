@@ -0,0 +1,89 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RemoteWriteStatusKey represents the status of the samples sent by the metric cache remote write
+	RemoteWriteStatusKey = "status"
+	// TSDBRecoveryStatusKey represents the result of recovering the persisted metric cache tsdb on startup
+	TSDBRecoveryStatusKey = "status"
+)
+
+const (
+	RemoteWriteStatusSucceeded = "succeeded"
+	RemoteWriteStatusFailed    = "failed"
+	RemoteWriteStatusDropped   = "dropped"
+
+	TSDBRecoveryStatusRecovered = "recovered"
+	TSDBRecoveryStatusCorrupted = "corrupted"
+	TSDBRecoveryStatusOversized = "oversized"
+)
+
+var (
+	metricCacheRemoteWriteSamples = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metric_cache_remote_write_samples_total",
+		Help:      "the count of samples streamed from the metric cache to the remote write endpoint",
+	}, []string{RemoteWriteStatusKey})
+
+	metricCacheRemoteWritePendingSamples = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metric_cache_remote_write_pending_samples",
+		Help:      "the number of samples pending in the metric cache remote write queue",
+	})
+
+	metricCacheRemoteWriteDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metric_cache_remote_write_duration_seconds",
+		Help:      "time duration of the remote write requests of the metric cache",
+		// 10ms ~ 10.24s
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 11),
+	})
+
+	metricCacheTSDBRecovery = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "metric_cache_tsdb_recovery_total",
+		Help:      "the count of recovering the persisted metric cache tsdb on startup, the data is discarded if corrupted or oversized",
+	}, []string{TSDBRecoveryStatusKey})
+
+	MetricCacheCollectors = []prometheus.Collector{
+		metricCacheRemoteWriteSamples,
+		metricCacheRemoteWritePendingSamples,
+		metricCacheRemoteWriteDurationSeconds,
+		metricCacheTSDBRecovery,
+	}
+)
+
+func RecordMetricCacheRemoteWriteSamples(status string, count int) {
+	metricCacheRemoteWriteSamples.WithLabelValues(status).Add(float64(count))
+}
+
+func RecordMetricCacheRemoteWritePendingSamples(count int) {
+	metricCacheRemoteWritePendingSamples.Set(float64(count))
+}
+
+func RecordMetricCacheRemoteWriteDuration(seconds float64) {
+	metricCacheRemoteWriteDurationSeconds.Observe(seconds)
+}
+
+func RecordMetricCacheTSDBRecovery(status string) {
+	metricCacheTSDBRecovery.WithLabelValues(status).Inc()
+}
@@ -22,6 +22,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 const (
@@ -39,6 +42,9 @@ const (
 	EvictionReasonKey = "reason"
 	BESuppressTypeKey = "type"
 
+	// TraceIDKey is the exemplar label of the action id, which links the metric to the audit event.
+	TraceIDKey = "trace_id"
+
 	ContainerID   = "container_id"
 	ContainerName = "container_name"
 
@@ -46,6 +52,12 @@ const (
 	PodName      = "pod_name"
 	PodNamespace = "pod_namespace"
 
+	// PodOwnerKind, PodOwnerName and PodQuotaName are the workload labels of the pod metrics, so the metrics can be
+	// aggregated by the workloads (e.g. Deployments, Jobs) and the elastic quotas.
+	PodOwnerKind = "pod_owner_kind"
+	PodOwnerName = "pod_owner_name"
+	PodQuotaName = "pod_quota_name"
+
 	ResourceKey = "resource"
 
 	UnitKey     = "unit"
@@ -90,3 +102,28 @@ func genNodeLabels() prometheus.Labels {
 		NodeKey: NodeName,
 	}
 }
+
+// GetPodWorkloadLabels returns the labels of the top-level workload and the elastic quota resolved from the pod.
+func GetPodWorkloadLabels(pod *corev1.Pod) map[string]string {
+	return map[string]string{
+		PodOwnerKind: util.GetPodWorkloadKind(pod),
+		PodOwnerName: util.GetPodWorkloadName(pod),
+		PodQuotaName: extension.GetQuotaName(pod),
+	}
+}
+
+func addPodWorkloadLabels(labels prometheus.Labels, pod *corev1.Pod) {
+	for k, v := range GetPodWorkloadLabels(pod) {
+		labels[k] = v
+	}
+}
+
+// addCounterWithActionID increases the counter with the exemplar of the action id if it is not empty.
+// The exemplars are only exposed in the OpenMetrics format.
+func addCounterWithActionID(counter prometheus.Counter, actionID string) {
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && len(actionID) > 0 {
+		adder.AddWithExemplar(1, prometheus.Labels{TraceIDKey: actionID})
+		return
+	}
+	counter.Inc()
+}
@@ -21,6 +21,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -144,15 +146,41 @@ func TestCommonCollectors(t *testing.T) {
 		RecordContainerScaledCFSBurstUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
 		RecordContainerScaledCFSQuotaUS(testingPod.Namespace, testingPod.Name, testingContainer.ContainerID, testingContainer.Name, 1000000)
 		RecordPodEviction(testingPod.Namespace, testingPod.Name, "evictByCPU")
+		RecordPodEvictionWithActionID(testingPod.Namespace, testingPod.Name, "evictByCPU", "4bf92f3577b34da6a3ce929d0e0e4736")
+		RecordBESuppressAction("cfsQuota", "4bf92f3577b34da6a3ce929d0e0e4736")
+		RecordBEMemoryThrottleEvent(BEMemoryThrottleTypeThrottle)
+		RecordBEMemoryThrottleLimit(float64(1024))
 		ResetContainerCPI()
 		RecordContainerCPI(testingContainer, testingPod, 1, 1)
 		ResetContainerPSI()
 		RecordContainerPSI(testingContainer, testingPod, testingPSI)
 		ResetPodPSI()
 		RecordPodPSI(testingPod, testingPSI)
+		ResetNodePSI()
+		RecordNodePSI(testingPSI)
+		RecordResctrlRMIDStatus(128, 128, 1)
+		RecordResctrlMonGroupRecycled(1)
 	})
 }
 
+func TestAddCounterWithActionID(t *testing.T) {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "test_counter"})
+	addCounterWithActionID(counter, "")
+	m := &dto.Metric{}
+	assert.NoError(t, counter.Write(m))
+	assert.Equal(t, float64(1), m.GetCounter().GetValue())
+	assert.Nil(t, m.GetCounter().GetExemplar())
+
+	addCounterWithActionID(counter, "4bf92f3577b34da6a3ce929d0e0e4736")
+	m = &dto.Metric{}
+	assert.NoError(t, counter.Write(m))
+	assert.Equal(t, float64(2), m.GetCounter().GetValue())
+	exemplar := m.GetCounter().GetExemplar()
+	assert.NotNil(t, exemplar)
+	assert.Equal(t, TraceIDKey, exemplar.GetLabel()[0].GetName())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", exemplar.GetLabel()[0].GetValue())
+}
+
 func TestResourceSummaryCollectors(t *testing.T) {
 	testingNode := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -446,3 +474,61 @@ func TestHostApplicationCollectors(t *testing.T) {
 		ResetHostApplicationResourceUsage()
 	})
 }
+
+func TestColocationStatistics(t *testing.T) {
+	evictionCount := GetPodEvictionCount()
+	RecordPodEviction("default", "test-pod", "evictByCPU")
+	RecordPodEvictionWithActionID("default", "test-pod", "evictByMemory", "")
+	assert.Equal(t, evictionCount+2, GetPodEvictionCount())
+
+	RecordBESuppressCores("cfsQuota", 4.5)
+	cores, ok := GetLastBESuppressCores()
+	assert.True(t, ok)
+	assert.Equal(t, 4.5, cores)
+	RecordBESuppressCores("cpuset", 2)
+	cores, ok = GetLastBESuppressCores()
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), cores)
+}
+
+func TestResctrlCollectors(t *testing.T) {
+	testingNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{},
+		},
+	}
+	// no panic when the node is not registered
+	RecordResctrlLLC(0, "BE", 1024)
+	RecordResctrlMB(0, "BE", system.ResctrlMBMLocalName, 2048)
+	RecordResctrlL2(0, "BE", 512)
+
+	Register(testingNode)
+	defer Register(nil)
+	ResetResctrlLLCQos()
+	ResetResctrlMBQos()
+
+	RecordResctrlLLC(1, "BE", 1024)
+	RecordResctrlMB(1, "BE", system.ResctrlMBMLocalName, 2048)
+	RecordResctrlMB(1, "BE", system.ResctrlMBMTotalName, 4096)
+	RecordResctrlL2(1, "BE", 512)
+	labels := prometheus.Labels{NodeKey: testingNode.Name, ResctrlCacheId: "1", ResctrlQos: "BE"}
+	for _, tt := range []struct {
+		gauge *prometheus.GaugeVec
+		want  float64
+	}{
+		{gauge: ResctrlLLCOccupancyBytes, want: 1024},
+		{gauge: ResctrlMBLocalBytes, want: 2048},
+		{gauge: ResctrlMBTotalBytes, want: 4096},
+		{gauge: ResctrlL2, want: 512},
+		{gauge: ResctrlLLC, want: 1024},
+	} {
+		m := &dto.Metric{}
+		assert.NoError(t, tt.gauge.With(labels).Write(m))
+		assert.Equal(t, tt.want, m.GetGauge().GetValue())
+	}
+	m := &dto.Metric{}
+	mbLabels := prometheus.Labels{NodeKey: testingNode.Name, ResctrlCacheId: "1", ResctrlQos: "BE", ResctrlMbType: system.ResctrlMBMTotalName}
+	assert.NoError(t, ResctrlMB.With(mbLabels).Write(m))
+	assert.Equal(t, float64(4096), m.GetGauge().GetValue())
+}
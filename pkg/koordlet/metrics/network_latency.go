@@ -0,0 +1,73 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	QoSKey      = "qos"
+	EndpointKey = "endpoint"
+)
+
+var (
+	PodNetworkLatency = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_network_latency_seconds",
+		Help:      "TCP connect round-trip time in seconds from the network namespace of the pod to the probe endpoint of its QoS class",
+	}, []string{NodeKey, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, QoSKey, EndpointKey})
+
+	NodeQoSNetworkLatencySLOViolation = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_qos_network_latency_slo_violation_ratio",
+		Help:      "Ratio of the network latency probes of the QoS class violating the latency SLO in the last round, including the failed probes",
+	}, []string{NodeKey, QoSKey})
+
+	NetworkLatencyCollectors = []prometheus.Collector{
+		PodNetworkLatency,
+		NodeQoSNetworkLatencySLOViolation,
+	}
+)
+
+func ResetPodNetworkLatency() {
+	PodNetworkLatency.Reset()
+}
+
+func RecordPodNetworkLatency(pod *corev1.Pod, qos, endpoint string, seconds float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
+	labels[QoSKey] = qos
+	labels[EndpointKey] = endpoint
+	PodNetworkLatency.With(labels).Set(seconds)
+}
+
+func RecordNodeQoSNetworkLatencySLOViolation(qos string, ratio float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[QoSKey] = qos
+	NodeQoSNetworkLatencySLOViolation.With(labels).Set(ratio)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	PowerSourceKey = "source"
+	PowerSocketKey = "socket"
+	PowerDomainKey = "domain"
+)
+
+var (
+	NodePower = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_power_watts",
+		Help:      "Power consumption in watts of the node collected by koordlet, the source can be rapl, amd_energy or hwmon",
+	}, []string{NodeKey, PowerSourceKey})
+
+	NodeSocketPower = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_socket_power_watts",
+		Help:      "Power consumption in watts of the power domain(package, dram, core, psys, ...) on the cpu socket collected by koordlet",
+	}, []string{NodeKey, PowerSocketKey, PowerDomainKey})
+
+	PowerCollectors = []prometheus.Collector{
+		NodePower,
+		NodeSocketPower,
+	}
+)
+
+func RecordNodePower(source string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PowerSourceKey] = source
+	NodePower.With(labels).Set(value)
+}
+
+func RecordNodeSocketPower(socketID int, domain string, value float64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[PowerSocketKey] = strconv.Itoa(socketID)
+	labels[PowerDomainKey] = domain
+	NodeSocketPower.With(labels).Set(value)
+}
+
+func ResetNodePower() {
+	NodePower.Reset()
+}
@@ -47,19 +47,26 @@ const (
 )
 
 var (
+	NodePSI = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "node_psi",
+		Help:      "Node psi collected by koordlet",
+	}, []string{NodeKey, PSIResourceType, PSIPrecision, PSIDegree, CPUFullSupported})
+
 	ContainerPSI = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
 		Name:      "container_psi",
 		Help:      "Container psi collected by koordlet",
-	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, PSIResourceType, PSIPrecision, PSIDegree, CPUFullSupported})
+	}, []string{NodeKey, ContainerID, ContainerName, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, PSIResourceType, PSIPrecision, PSIDegree, CPUFullSupported})
 
 	PodPSI = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
 		Name:      "pod_psi",
 		Help:      "Pod psi collected by koordlet",
-	}, []string{NodeKey, PodUID, PodName, PodNamespace, PSIResourceType, PSIPrecision, PSIDegree, CPUFullSupported})
+	}, []string{NodeKey, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, PSIResourceType, PSIPrecision, PSIDegree, CPUFullSupported})
 
 	PSICollectors = []prometheus.Collector{
+		NodePSI,
 		ContainerPSI,
 		PodPSI,
 	}
@@ -134,6 +141,21 @@ func makePSIRecordSlice(resourceType string, psiStats system.PSIStats) []PSIReco
 	return records
 }
 
+func RecordNodePSI(psi *system.PSIByResource) {
+	psiRecords := getPSIRecords(psi)
+	for _, record := range psiRecords {
+		labels := genNodeLabels()
+		if labels == nil {
+			return
+		}
+		labels[PSIResourceType] = record.ResourceType
+		labels[PSIPrecision] = record.Precision
+		labels[PSIDegree] = record.Degree
+		labels[CPUFullSupported] = strconv.FormatBool(record.CPUFullSupported)
+		NodePSI.With(labels).Set(record.Value)
+	}
+}
+
 func RecordContainerPSI(status *corev1.ContainerStatus, pod *corev1.Pod, psi *system.PSIByResource) {
 	psiRecords := getPSIRecords(psi)
 	for _, record := range psiRecords {
@@ -146,6 +168,7 @@ func RecordContainerPSI(status *corev1.ContainerStatus, pod *corev1.Pod, psi *sy
 		labels[PodUID] = string(pod.UID)
 		labels[PodName] = pod.Name
 		labels[PodNamespace] = pod.Namespace
+		addPodWorkloadLabels(labels, pod)
 
 		labels[PSIResourceType] = record.ResourceType
 		labels[PSIPrecision] = record.Precision
@@ -165,6 +188,7 @@ func RecordPodPSI(pod *corev1.Pod, psi *system.PSIByResource) {
 		labels[PodUID] = string(pod.UID)
 		labels[PodName] = pod.Name
 		labels[PodNamespace] = pod.Namespace
+		addPodWorkloadLabels(labels, pod)
 
 		labels[PSIResourceType] = record.ResourceType
 		labels[PSIPrecision] = record.Precision
@@ -174,6 +198,10 @@ func RecordPodPSI(pod *corev1.Pod, psi *system.PSIByResource) {
 	}
 }
 
+func ResetNodePSI() {
+	NodePSI.Reset()
+}
+
 func ResetContainerPSI() {
 	ContainerPSI.Reset()
 }
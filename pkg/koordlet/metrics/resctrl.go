@@ -20,6 +20,9 @@ import (
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 const (
@@ -36,39 +39,189 @@ var (
 	ResctrlLLC = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
 		Name:      "resctrl_llc_occupancy",
-		Help:      "resctrl default qos(LSR, LS, BE) llc occupancy collected by koordlet",
+		Help:      "resctrl default qos(LSR, LS, BE) llc occupancy collected by koordlet, deprecated and will be removed in the next minor release, use resctrl_llc_occupancy_bytes instead",
 	}, []string{NodeKey, ResctrlCacheId, ResctrlQos})
 	ResctrlMB = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
 		Name:      "resctrl_memory_bandwidth",
-		Help:      "resctrl default qos(LSR, LS, BE) memory bandwidth collected by koordlet",
+		Help:      "resctrl default qos(LSR, LS, BE) memory bandwidth collected by koordlet, deprecated and will be removed in the next minor release, use resctrl_mb_local_bytes and resctrl_mb_total_bytes instead",
 	}, []string{NodeKey, ResctrlCacheId, ResctrlQos, ResctrlMbType})
+	ResctrlLLCOccupancyBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_llc_occupancy_bytes",
+		Help:      "resctrl llc occupancy in bytes of the qos class(LSR, LS, BE) on the cache id collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, ResctrlQos})
+	ResctrlMBLocalBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_mb_local_bytes",
+		Help:      "resctrl local memory bandwidth counter in bytes of the qos class(LSR, LS, BE) on the cache id collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, ResctrlQos})
+	ResctrlMBTotalBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_mb_total_bytes",
+		Help:      "resctrl total memory bandwidth counter in bytes of the qos class(LSR, LS, BE) on the cache id collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, ResctrlQos})
+	ResctrlL2 = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_l2_occupancy",
+		Help:      "resctrl default qos(LSR, LS, BE) l2 cache occupancy collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, ResctrlQos})
+
+	PodResctrlLLC = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_resctrl_llc_occupancy",
+		Help:      "resctrl llc occupancy of the pod mon group collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName})
+	PodResctrlMB = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_resctrl_memory_bandwidth",
+		Help:      "resctrl memory bandwidth of the pod mon group collected by koordlet",
+	}, []string{NodeKey, ResctrlCacheId, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, ResctrlMbType})
+
+	ResctrlRMIDCapacity = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_rmid_capacity",
+		Help:      "the number of the resctrl RMIDs supported by the node",
+	}, []string{NodeKey})
+	ResctrlRMIDUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_rmid_used",
+		Help:      "the number of the resctrl RMIDs taken by the control groups and the mon groups",
+	}, []string{NodeKey})
+	ResctrlRMIDExhausted = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_rmid_exhausted",
+		Help:      "whether the resctrl RMIDs run out so some pods have no mon groups, 1 for exhausted",
+	}, []string{NodeKey})
+	PodResctrlMonGroupPending = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "pod_resctrl_mon_group_pending",
+		Help:      "the number of the pods without resctrl mon groups since the RMIDs run out",
+	}, []string{NodeKey})
+	ResctrlMonGroupRecycled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_mon_group_recycled",
+		Help:      "the number of the pod mon groups recycled for the pods without mon groups when the RMIDs run out",
+	}, []string{NodeKey})
 
 	ResctrlCollectors = []prometheus.Collector{
 		ResctrlLLC,
 		ResctrlMB,
+		ResctrlL2,
+		ResctrlLLCOccupancyBytes,
+		ResctrlMBLocalBytes,
+		ResctrlMBTotalBytes,
+		PodResctrlLLC,
+		PodResctrlMB,
+		ResctrlRMIDCapacity,
+		ResctrlRMIDUsed,
+		ResctrlRMIDExhausted,
+		PodResctrlMonGroupPending,
+		ResctrlMonGroupRecycled,
 	}
 )
 
 func ResetResctrlLLCQos() {
 	ResctrlLLC.Reset()
+	ResctrlLLCOccupancyBytes.Reset()
 }
 
 func ResetResctrlMBQos() {
 	ResctrlMB.Reset()
+	ResctrlMBLocalBytes.Reset()
+	ResctrlMBTotalBytes.Reset()
 }
 
 func RecordResctrlLLC(cacheId int, qos string, value uint64) {
 	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
 	labels[ResctrlCacheId] = strconv.Itoa(cacheId)
 	labels[ResctrlQos] = qos
 	ResctrlLLC.With(labels).Set(float64(value))
+	ResctrlLLCOccupancyBytes.With(labels).Set(float64(value))
 }
 
 func RecordResctrlMB(cacheId int, qos, mbType string, value uint64) {
 	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
 	labels[ResctrlCacheId] = strconv.Itoa(cacheId)
 	labels[ResctrlQos] = qos
+	switch mbType {
+	case system.ResctrlMBMLocalName:
+		ResctrlMBLocalBytes.With(labels).Set(float64(value))
+	case system.ResctrlMBMTotalName:
+		ResctrlMBTotalBytes.With(labels).Set(float64(value))
+	}
 	labels[ResctrlMbType] = mbType
 	ResctrlMB.With(labels).Set(float64(value))
 }
+
+func RecordResctrlL2(cacheId int, qos string, value uint64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlCacheId] = strconv.Itoa(cacheId)
+	labels[ResctrlQos] = qos
+	ResctrlL2.With(labels).Set(float64(value))
+}
+
+func ResetPodResctrl() {
+	PodResctrlLLC.Reset()
+	PodResctrlMB.Reset()
+}
+
+func RecordPodResctrlLLC(cacheId int, pod *corev1.Pod, value uint64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlCacheId] = strconv.Itoa(cacheId)
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
+	PodResctrlLLC.With(labels).Set(float64(value))
+}
+
+func RecordPodResctrlMB(cacheId int, pod *corev1.Pod, mbType string, value uint64) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	labels[ResctrlCacheId] = strconv.Itoa(cacheId)
+	labels[PodUID] = string(pod.UID)
+	labels[PodName] = pod.Name
+	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
+	labels[ResctrlMbType] = mbType
+	PodResctrlMB.With(labels).Set(float64(value))
+}
+
+// RecordResctrlRMIDStatus records the RMID usage and the pods without mon groups due to the RMID exhaustion.
+func RecordResctrlRMIDStatus(capacity, used int64, pendingPods int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	ResctrlRMIDCapacity.With(labels).Set(float64(capacity))
+	ResctrlRMIDUsed.With(labels).Set(float64(used))
+	exhausted := 0.0
+	if pendingPods > 0 {
+		exhausted = 1.0
+	}
+	ResctrlRMIDExhausted.With(labels).Set(exhausted)
+	PodResctrlMonGroupPending.With(labels).Set(float64(pendingPods))
+}
+
+func RecordResctrlMonGroupRecycled(count int) {
+	labels := genNodeLabels()
+	if labels == nil {
+		return
+	}
+	ResctrlMonGroupRecycled.With(labels).Add(float64(count))
+}
@@ -25,6 +25,12 @@ const (
 	ResourceUpdaterType = "type"
 	// ResourceUpdateStatusKey represents the status of resource update
 	ResourceUpdateStatusKey = "status"
+	// CgroupReconcileStageKey represents the stage of cgroup reconcile, including calculate, update
+	CgroupReconcileStageKey = "stage"
+	// ResctrlGroupGCActionKey represents the action of resctrl group gc, including removing groups, re-syncing tasks
+	ResctrlGroupGCActionKey = "action"
+	// ResourceUpdateSubsystemKey represents the subsystem of the batched resource update, e.g. cpu, memory, resctrl
+	ResourceUpdateSubsystemKey = "subsystem"
 )
 
 const (
@@ -32,6 +38,17 @@ const (
 	ResourceUpdateStatusFailed  = "failed"
 )
 
+const (
+	CgroupReconcileStageCalculate = "calculate"
+	CgroupReconcileStageUpdate    = "update"
+)
+
+const (
+	ResctrlGroupGCActionRemoveCtrlGroup = "remove_ctrl_group"
+	ResctrlGroupGCActionRemoveMonGroup  = "remove_mon_group"
+	ResctrlGroupGCActionResyncTasks     = "resync_tasks"
+)
+
 var (
 	resourceUpdateDurationMilliSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Subsystem: KoordletSubsystem,
@@ -41,11 +58,70 @@ var (
 		Buckets: prometheus.ExponentialBuckets(0.01, 4, 8),
 	}, []string{ResourceUpdaterType, ResourceUpdateStatusKey})
 
+	cgroupReconcileDurationMilliSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "cgroup_reconcile_duration_milliseconds",
+		Help:      "time duration of each stage of a cgroup reconcile round",
+		// 0.1ms ~ 1.6s
+		Buckets: prometheus.ExponentialBuckets(0.1, 4, 8),
+	}, []string{CgroupReconcileStageKey})
+
+	resctrlGroupGCActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resctrl_group_gc_actions",
+		Help:      "the count of actions taken by the resctrl group gc, e.g. removing the orphan groups",
+	}, []string{ResctrlGroupGCActionKey, ResourceUpdateStatusKey})
+
+	resourceUpdateQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resource_update_queue_depth",
+		Help:      "the number of the batched resource updates waiting in the queue",
+	})
+
+	resourceUpdateQueueLatencyMilliSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resource_update_queue_latency_milliseconds",
+		Help:      "time duration of the batched resource update waiting in the queue before it is written",
+		// 1ms ~ 16.4s
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8),
+	}, []string{ResourceUpdateSubsystemKey})
+
+	resourceUpdateCoalesced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: KoordletSubsystem,
+		Name:      "resource_update_coalesced",
+		Help:      "the count of the batched resource updates coalesced by a later update of the same file",
+	}, []string{ResourceUpdateSubsystemKey})
+
 	ResourceExecutorCollector = []prometheus.Collector{
 		resourceUpdateDurationMilliSeconds,
+		cgroupReconcileDurationMilliSeconds,
+		resctrlGroupGCActions,
+		resourceUpdateQueueDepth,
+		resourceUpdateQueueLatencyMilliSeconds,
+		resourceUpdateCoalesced,
 	}
 )
 
 func RecordResourceUpdateDuration(updaterType, status string, seconds float64) {
 	resourceUpdateDurationMilliSeconds.WithLabelValues(updaterType, status).Observe(seconds * 1000)
 }
+
+func RecordCgroupReconcileDuration(stage string, seconds float64) {
+	cgroupReconcileDurationMilliSeconds.WithLabelValues(stage).Observe(seconds * 1000)
+}
+
+func RecordResctrlGroupGCAction(action, status string) {
+	resctrlGroupGCActions.WithLabelValues(action, status).Inc()
+}
+
+func RecordResourceUpdateQueueDepth(depth int) {
+	resourceUpdateQueueDepth.Set(float64(depth))
+}
+
+func RecordResourceUpdateQueueLatency(subsystem string, seconds float64) {
+	resourceUpdateQueueLatencyMilliSeconds.WithLabelValues(subsystem).Observe(seconds * 1000)
+}
+
+func RecordResourceUpdateCoalesced(subsystem string) {
+	resourceUpdateCoalesced.WithLabelValues(subsystem).Inc()
+}
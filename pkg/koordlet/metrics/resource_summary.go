@@ -45,13 +45,13 @@ var (
 		Subsystem: KoordletSubsystem,
 		Name:      "container_resource_requests",
 		Help:      "the container requests of resources updated by koordinator",
-	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, ContainerID, ContainerName})
+	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, ContainerID, ContainerName})
 
 	ContainerResourceLimits = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Subsystem: KoordletSubsystem,
 		Name:      "container_resource_limits",
 		Help:      "the container limits of resources updated by koordinator",
-	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, ContainerID, ContainerName})
+	}, []string{NodeKey, ResourceKey, UnitKey, PodUID, PodName, PodNamespace, PodOwnerKind, PodOwnerName, PodQuotaName, ContainerID, ContainerName})
 
 	ResourceSummaryCollectors = []prometheus.Collector{
 		NodeResourceAllocatable,
@@ -102,6 +102,7 @@ func RecordContainerResourceRequests(resourceName string, unit string, status *c
 	labels[PodUID] = string(pod.UID)
 	labels[PodName] = pod.Name
 	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
 	labels[ContainerID] = status.ContainerID
 	labels[ContainerName] = status.Name
 	ContainerResourceRequests.With(labels).Set(value)
@@ -121,6 +122,7 @@ func RecordContainerResourceLimits(resourceName string, unit string, status *cor
 	labels[PodUID] = string(pod.UID)
 	labels[PodName] = pod.Name
 	labels[PodNamespace] = pod.Namespace
+	addPodWorkloadLabels(labels, pod)
 	labels[ContainerID] = status.ContainerID
 	labels[ContainerName] = status.Name
 	ContainerResourceLimits.With(labels).Set(value)
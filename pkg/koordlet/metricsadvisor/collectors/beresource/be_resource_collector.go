@@ -31,6 +31,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -72,7 +73,7 @@ func (b *beResourceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	go wait.Until(b.collectBECPUResourceMetric, b.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, b.collectBECPUResourceMetric), b.collectInterval, stopCh)
 }
 
 func (b *beResourceCollector) Started() bool {
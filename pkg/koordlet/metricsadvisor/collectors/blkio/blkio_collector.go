@@ -0,0 +1,316 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"sort"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "BlkIOCollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// ioStatSnapshot is the io counters of a cgroup read at the timestamp.
+type ioStatSnapshot struct {
+	stat      system.IOStatRaw
+	timestamp time.Time
+}
+
+// deviceLatency is the average latency of the IOs completed on a block device in the last interval, in microseconds.
+type deviceLatency struct {
+	read  float64
+	write float64
+}
+
+type blkIOValue struct {
+	ioType metriccache.MetricPropertyValue
+	value  float64
+}
+
+// blkIOSampleFunc generates the sample of an io metric of the device, e.g. the read iops on `8:0`.
+type blkIOSampleFunc func(device, ioType string, value float64) (metriccache.MetricSample, error)
+
+// blkIOCollector collects the read/write iops and bandwidth of the pods and containers on each block device from
+// the blkio cgroup, i.e. blkio.throttle.io_serviced/io_service_bytes on cgroups-v1 and io.stat on cgroups-v2.
+// Since neither of them accounts the io latency, the latency of a pod or container is estimated with the average
+// latency of the block device calculated from /proc/diskstats, which is only recorded when the pod or the container
+// has issued IOs on the device in the interval.
+type blkIOCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	podFilter       framework.PodFilter
+
+	lastDiskStats       map[string]*system.DiskStat
+	lastPodIOStat       *gocache.Cache
+	lastContainerIOStat *gocache.Cache
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &blkIOCollector{
+		collectInterval:     collectInterval,
+		started:             atomic.NewBool(false),
+		appendableDB:        opt.MetricCache,
+		statesInformer:      opt.StatesInformer,
+		cgroupReader:        opt.CgroupReader,
+		podFilter:           podFilter,
+		lastPodIOStat:       gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+		lastContainerIOStat: gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &blkIOCollector{}
+
+func (b *blkIOCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BlkIOCollector)
+}
+
+func (b *blkIOCollector) Setup(c *framework.Context) {}
+
+func (b *blkIOCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, b.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, b.collectBlkIO), b.collectInterval, stopCh)
+}
+
+func (b *blkIOCollector) Started() bool {
+	return b.started.Load()
+}
+
+func (b *blkIOCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return b.podFilter.FilterPod(meta)
+}
+
+func (b *blkIOCollector) collectBlkIO() {
+	klog.V(6).Info("start collectBlkIO")
+	latencies := b.collectDeviceLatencies()
+
+	podMetas := b.statesInformer.GetAllPods()
+	podAndContainerMetrics := make([]metriccache.MetricSample, 0)
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		uid := string(pod.UID)
+		if filtered, msg := b.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+			continue
+		}
+
+		collectTime := timeNow()
+		currentStat, err := b.cgroupReader.ReadIOStat(meta.CgroupDir)
+		if err != nil {
+			if pod.Status.Phase == corev1.PodRunning {
+				// print running pod collection error
+				klog.V(4).Infof("collect pod %s/%s, uid %v io stat failed, err %v", pod.Namespace, pod.Name, uid, err)
+			}
+			continue
+		}
+		current := &ioStatSnapshot{stat: currentStat, timestamp: collectTime}
+		lastValue, ok := b.lastPodIOStat.Get(uid)
+		b.lastPodIOStat.Set(uid, current, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(6).Infof("collect pod %s/%s, uid %s io stat first point", pod.Namespace, pod.Name, uid)
+		} else {
+			podMetrics := generateBlkIOSamples(current, lastValue.(*ioStatSnapshot), latencies,
+				func(device, ioType string, value float64) (metriccache.MetricSample, error) {
+					return metriccache.PodBlkIOMetric.GenerateSample(
+						metriccache.MetricPropertiesFunc.PodBlkIO(uid, device, ioType), collectTime, value)
+				})
+			podAndContainerMetrics = append(podAndContainerMetrics, podMetrics...)
+		}
+
+		// collect container-level metrics
+		metrics := b.collectContainerBlkIO(meta, latencies)
+		podAndContainerMetrics = append(podAndContainerMetrics, metrics...)
+	} // end for podMeta
+
+	appender := b.appendableDB.Appender()
+	if err := appender.Append(podAndContainerMetrics); err != nil {
+		klog.Warningf("append pods blkio metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit pods blkio metrics failed, reason: %v", err)
+		return
+	}
+	b.started.Store(true)
+	klog.V(5).Infof("collectBlkIO finished, pod num %d, metric num %d", len(podMetas), len(podAndContainerMetrics))
+}
+
+func (b *blkIOCollector) collectContainerBlkIO(podMeta *statesinformer.PodMeta, latencies map[string]*deviceLatency) []metriccache.MetricSample {
+	pod := podMeta.Pod
+	podUID := string(pod.UID)
+	containersMetric := make([]metriccache.MetricSample, 0)
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		if len(containerStat.ContainerID) == 0 {
+			klog.V(5).Infof("container %s/%s/%s id is empty, maybe not ready, skip this round",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
+
+		containerCgroupDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(4).Infof("collect container %s/%s/%s io stat failed, cannot get container cgroup, err: %s",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+
+		collectTime := timeNow()
+		currentStat, err := b.cgroupReader.ReadIOStat(containerCgroupDir)
+		if err != nil {
+			// higher verbosity for probably non-running pods
+			if containerStat.State.Running == nil {
+				klog.V(6).Infof("collect non-running container %s/%s/%s io stat failed, err: %s",
+					pod.Namespace, pod.Name, containerStat.Name, err)
+			} else {
+				klog.V(4).Infof("collect container %s/%s/%s io stat failed, err: %s",
+					pod.Namespace, pod.Name, containerStat.Name, err)
+			}
+			continue
+		}
+		current := &ioStatSnapshot{stat: currentStat, timestamp: collectTime}
+		lastValue, ok := b.lastContainerIOStat.Get(containerStat.ContainerID)
+		b.lastContainerIOStat.Set(containerStat.ContainerID, current, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(6).Infof("collect container %s/%s/%s io stat first point",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
+
+		containerID := containerStat.ContainerID
+		metrics := generateBlkIOSamples(current, lastValue.(*ioStatSnapshot), latencies,
+			func(device, ioType string, value float64) (metriccache.MetricSample, error) {
+				return metriccache.ContainerBlkIOMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.ContainerBlkIO(podUID, containerID, device, ioType), collectTime, value)
+			})
+		containersMetric = append(containersMetric, metrics...)
+	} // end for container status
+	klog.V(6).Infof("collect container io stat for pod %s finished, metric num %d", util.GetPodKey(pod), len(containersMetric))
+	return containersMetric
+}
+
+// collectDeviceLatencies calculates the average read/write latency of each block device since the last round.
+func (b *blkIOCollector) collectDeviceLatencies() map[string]*deviceLatency {
+	diskStats, err := system.GetDiskStats()
+	if err != nil {
+		klog.V(4).Infof("failed to get disk stats, err: %s", err)
+		return nil
+	}
+	lastDiskStats := b.lastDiskStats
+	b.lastDiskStats = diskStats
+	if lastDiskStats == nil {
+		return nil
+	}
+	return calculateDeviceLatencies(diskStats, lastDiskStats)
+}
+
+func calculateDeviceLatencies(current, last map[string]*system.DiskStat) map[string]*deviceLatency {
+	latencies := map[string]*deviceLatency{}
+	for device, cur := range current {
+		prev, ok := last[device]
+		if !ok {
+			continue
+		}
+		latency := &deviceLatency{}
+		if cur.ReadIOs > prev.ReadIOs && cur.ReadTicks >= prev.ReadTicks {
+			latency.read = float64(cur.ReadTicks-prev.ReadTicks) * 1000 / float64(cur.ReadIOs-prev.ReadIOs)
+		}
+		if cur.WriteIOs > prev.WriteIOs && cur.WriteTicks >= prev.WriteTicks {
+			latency.write = float64(cur.WriteTicks-prev.WriteTicks) * 1000 / float64(cur.WriteIOs-prev.WriteIOs)
+		}
+		latencies[device] = latency
+	}
+	return latencies
+}
+
+// generateBlkIOSamples generates the iops, bandwidth (bytes per second) and latency (microseconds) samples
+// for each device with the io counters of two rounds.
+func generateBlkIOSamples(current, last *ioStatSnapshot, latencies map[string]*deviceLatency, sampleFunc blkIOSampleFunc) []metriccache.MetricSample {
+	duration := current.timestamp.Sub(last.timestamp).Seconds()
+	if duration <= 0 {
+		return nil
+	}
+	devices := make([]string, 0, len(current.stat))
+	for device := range current.stat {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	var samples []metriccache.MetricSample
+	for _, device := range devices {
+		cur := current.stat[device]
+		prev, ok := last.stat[device]
+		if !ok || cur.ReadIOs < prev.ReadIOs || cur.WriteIOs < prev.WriteIOs ||
+			cur.ReadBytes < prev.ReadBytes || cur.WriteBytes < prev.WriteBytes { // counters are reset
+			continue
+		}
+		values := []blkIOValue{
+			{ioType: metriccache.BlkIOTypeReadIOPS, value: float64(cur.ReadIOs-prev.ReadIOs) / duration},
+			{ioType: metriccache.BlkIOTypeWriteIOPS, value: float64(cur.WriteIOs-prev.WriteIOs) / duration},
+			{ioType: metriccache.BlkIOTypeReadBPS, value: float64(cur.ReadBytes-prev.ReadBytes) / duration},
+			{ioType: metriccache.BlkIOTypeWriteBPS, value: float64(cur.WriteBytes-prev.WriteBytes) / duration},
+		}
+		if latency, ok := latencies[device]; ok {
+			if cur.ReadIOs > prev.ReadIOs {
+				values = append(values, blkIOValue{ioType: metriccache.BlkIOTypeReadLatency, value: latency.read})
+			}
+			if cur.WriteIOs > prev.WriteIOs {
+				values = append(values, blkIOValue{ioType: metriccache.BlkIOTypeWriteLatency, value: latency.write})
+			}
+		}
+		for _, v := range values {
+			sample, err := sampleFunc(device, string(v.ioType), v.value)
+			if err != nil {
+				klog.V(4).Infof("failed to generate blkio sample for device %s, type %s, err: %s", device, v.ioType, err)
+				continue
+			}
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_blkIOCollector_collectBlkIO(t *testing.T) {
+	testContainerID := "containerd://testContainerUID"
+	testPodMetaDir := "kubepods.slice/kubepods-podtest-pod-uid.slice"
+	testPodParentDir := "/kubepods.slice/kubepods-podtest-pod-uid.slice"
+	testContainerParentDir := "/kubepods.slice/kubepods-podtest-pod-uid.slice/cri-containerd-testContainerUID.scope"
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:        "test-container",
+					ContainerID: testContainerID,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetResourcesSupported(true, system.BlkioIOServiced, system.BlkioIOServiceBytes)
+	helper.WriteProcSubFileContents(system.ProcDiskStatsName, "   8       0 sda 1000 10 20000 500 2000 20 40000 1000 0 1200 1500 0 0 0 0\n")
+	helper.WriteCgroupFileContents(testPodParentDir, system.BlkioIOServiced, "8:0 Read 100\n8:0 Write 200\nTotal 300\n")
+	helper.WriteCgroupFileContents(testPodParentDir, system.BlkioIOServiceBytes, "8:0 Read 409600\n8:0 Write 819200\nTotal 1228800\n")
+	helper.WriteCgroupFileContents(testContainerParentDir, system.BlkioIOServiced, "8:0 Read 100\n8:0 Write 200\nTotal 300\n")
+	helper.WriteCgroupFileContents(testContainerParentDir, system.BlkioIOServiceBytes, "8:0 Read 409600\n8:0 Write 819200\nTotal 1228800\n")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{
+			CgroupDir: testPodMetaDir,
+			Pod:       testPod,
+		},
+	}).Times(1)
+
+	collector := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: time.Second,
+		},
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*blkIOCollector)
+	lastTime := time.Now().Add(-10 * time.Second)
+	c.lastDiskStats = map[string]*system.DiskStat{
+		"8:0": {Device: "8:0", Name: "sda", ReadIOs: 900, ReadTicks: 400, WriteIOs: 1900, WriteTicks: 900},
+	}
+	c.lastPodIOStat.Set(string(testPod.UID), &ioStatSnapshot{
+		stat:      system.IOStatRaw{"8:0": {}},
+		timestamp: lastTime,
+	}, gocache.DefaultExpiration)
+	c.lastContainerIOStat.Set(testContainerID, &ioStatSnapshot{
+		stat:      system.IOStatRaw{"8:0": {}},
+		timestamp: lastTime,
+	}, gocache.DefaultExpiration)
+	assert.NotPanics(t, func() {
+		c.collectBlkIO()
+	})
+	assert.True(t, c.Started())
+	assert.Equal(t, map[string]*system.DiskStat{
+		"8:0": {Device: "8:0", Name: "sda", ReadIOs: 1000, ReadTicks: 500, WriteIOs: 2000, WriteTicks: 1000},
+	}, c.lastDiskStats)
+
+	querier, err := metricCache.Querier(lastTime, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	queryMeta, err := metriccache.ContainerBlkIOMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.ContainerBlkIO(string(testPod.UID), testContainerID, "8:0", string(metriccache.BlkIOTypeReadLatency)))
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	latency, err := result.Value(metriccache.AggregationTypeLast)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1000), latency)
+}
+
+func Test_calculateDeviceLatencies(t *testing.T) {
+	last := map[string]*system.DiskStat{
+		"8:0":  {Device: "8:0", ReadIOs: 100, ReadTicks: 100, WriteIOs: 100, WriteTicks: 100},
+		"8:16": {Device: "8:16", ReadIOs: 100, ReadTicks: 100, WriteIOs: 100, WriteTicks: 100},
+	}
+	current := map[string]*system.DiskStat{
+		"8:0":  {Device: "8:0", ReadIOs: 200, ReadTicks: 150, WriteIOs: 300, WriteTicks: 500},
+		"8:16": {Device: "8:16", ReadIOs: 100, ReadTicks: 100, WriteIOs: 100, WriteTicks: 100},
+		"8:32": {Device: "8:32", ReadIOs: 100, ReadTicks: 100, WriteIOs: 100, WriteTicks: 100},
+	}
+	assert.Equal(t, map[string]*deviceLatency{
+		"8:0":  {read: 500, write: 2000},
+		"8:16": {},
+	}, calculateDeviceLatencies(current, last))
+}
+
+func Test_generateBlkIOSamples(t *testing.T) {
+	now := time.Now()
+	last := &ioStatSnapshot{
+		stat: system.IOStatRaw{
+			"8:0":  {ReadBytes: 4096, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+			"8:16": {ReadBytes: 4096, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+			"8:32": {ReadBytes: 4096, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+		},
+		timestamp: now.Add(-2 * time.Second),
+	}
+	current := &ioStatSnapshot{
+		stat: system.IOStatRaw{
+			"8:0":  {ReadBytes: 4096 * 21, WriteBytes: 4096, ReadIOs: 21, WriteIOs: 1},
+			"8:16": {ReadBytes: 0, WriteBytes: 0, ReadIOs: 0, WriteIOs: 0}, // counters are reset
+			"8:48": {ReadBytes: 4096, WriteBytes: 4096, ReadIOs: 1, WriteIOs: 1},
+		},
+		timestamp: now,
+	}
+	latencies := map[string]*deviceLatency{
+		"8:0": {read: 500, write: 2000},
+	}
+	sampleFunc := func(device, ioType string, value float64) (metriccache.MetricSample, error) {
+		return metriccache.PodBlkIOMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodBlkIO("test-pod", device, ioType), now, value)
+	}
+	buildSample := func(ioType metriccache.MetricPropertyValue, value float64) metriccache.MetricSample {
+		s, err := sampleFunc("8:0", string(ioType), value)
+		assert.NoError(t, err)
+		return s
+	}
+	assert.Equal(t, []metriccache.MetricSample{
+		buildSample(metriccache.BlkIOTypeReadIOPS, 10),
+		buildSample(metriccache.BlkIOTypeWriteIOPS, 0),
+		buildSample(metriccache.BlkIOTypeReadBPS, 40960),
+		buildSample(metriccache.BlkIOTypeWriteBPS, 0),
+		buildSample(metriccache.BlkIOTypeReadLatency, 500),
+	}, generateBlkIOSamples(current, last, latencies, sampleFunc))
+
+	assert.Nil(t, generateBlkIOSamples(current, current, latencies, sampleFunc))
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cputhermal
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	nodeutil "k8s.io/component-helpers/node/util"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CollectorName = "CPUThermalCollector"
+
+	// thermalThrottledCPURatioThreshold is the minimum ratio of the cpus having new thermal throttling events in an
+	// interval to consider the node is thermally throttled.
+	thermalThrottledCPURatioThreshold = 0.1
+
+	ReasonCPUThermalThrottled = "CPUThermalThrottled"
+	ReasonCPUThermalNormal    = "CPUThermalNormal"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// cpuThermalCollector collects the frequencies, the thermal throttling counts and the turbo ratios of the cpus.
+// The node is considered thermally throttled if enough cpus have new throttling events in the last interval, which
+// is reported as the node condition so that the scheduler can avoid the degraded nodes.
+type cpuThermalCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	statesInformer  statesinformer.StatesInformer
+	metricCache     metriccache.MetricCache
+	kubeClient      clientset.Interface
+
+	lastStats           map[int32]system.CPUFrequencyStat
+	lastConditionStatus corev1.ConditionStatus
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &cpuThermalCollector{
+		collectInterval: opt.Config.CPUThermalCollectorInterval,
+		started:         atomic.NewBool(false),
+		statesInformer:  opt.StatesInformer,
+		metricCache:     opt.MetricCache,
+		kubeClient:      opt.KubeClient,
+	}
+}
+
+func (c *cpuThermalCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPUThermalCollector)
+}
+
+func (c *cpuThermalCollector) Setup(ctx *framework.Context) {}
+
+func (c *cpuThermalCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, c.collectCPUThermal), c.collectInterval, stopCh)
+}
+
+func (c *cpuThermalCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *cpuThermalCollector) collectCPUThermal() {
+	klog.V(6).Infof("start collectCPUThermal")
+	stats, err := system.GetCPUFrequencyStats()
+	collectTime := timeNow()
+	if err != nil {
+		klog.Errorf("failed to read cpu frequency stats, err: %v", err)
+		return
+	}
+	if len(stats) == 0 {
+		klog.V(5).Infof("skip collecting cpu thermal since the cpufreq is not supported")
+		c.started.Store(true)
+		return
+	}
+
+	var totalFreqMHz, totalTurboRatio float64
+	throttledCPUs, turboCPUs := 0, 0
+	curStats := make(map[int32]system.CPUFrequencyStat, len(stats))
+	for _, stat := range stats {
+		curStats[stat.CPU] = stat
+		freqMHz := float64(stat.CurFreqKHz) / 1000
+		totalFreqMHz += freqMHz
+		metrics.RecordNodeCPUCoreFrequency(stat.CPU, freqMHz)
+		if stat.HasThrottleCount {
+			metrics.RecordNodeCPUThermalThrottleCount(stat.CPU, metrics.ThermalThrottleTypeCore, float64(stat.CoreThrottleCount))
+			metrics.RecordNodeCPUThermalThrottleCount(stat.CPU, metrics.ThermalThrottleTypePackage, float64(stat.PackageThrottleCount))
+		}
+
+		last, ok := c.lastStats[stat.CPU]
+		if !ok {
+			continue
+		}
+		if isThermalThrottled(last, stat) {
+			throttledCPUs++
+		}
+		if turboRatio, ok := getTurboRatio(last, stat); ok {
+			totalTurboRatio += turboRatio
+			turboCPUs++
+			metrics.RecordNodeCPUCoreTurboRatio(stat.CPU, turboRatio)
+		}
+	}
+	isFirstCollection := c.lastStats == nil
+	c.lastStats = curStats
+
+	samples := []metriccache.MetricSample{}
+	if sample, err := metriccache.NodeCPUFrequencyMetric.GenerateSample(nil, collectTime, totalFreqMHz/float64(len(stats))); err == nil {
+		samples = append(samples, sample)
+	} else {
+		klog.Warningf("generate node cpu frequency sample failed, err: %v", err)
+	}
+	if turboCPUs > 0 {
+		if sample, err := metriccache.NodeCPUTurboRatioMetric.GenerateSample(nil, collectTime, totalTurboRatio/float64(turboCPUs)); err == nil {
+			samples = append(samples, sample)
+		} else {
+			klog.Warningf("generate node cpu turbo ratio sample failed, err: %v", err)
+		}
+	}
+	if !isFirstCollection {
+		throttledRatio := float64(throttledCPUs) / float64(len(stats))
+		if sample, err := metriccache.NodeCPUThermalThrottledRatioMetric.GenerateSample(nil, collectTime, throttledRatio); err == nil {
+			samples = append(samples, sample)
+		} else {
+			klog.Warningf("generate node cpu thermal throttled ratio sample failed, err: %v", err)
+		}
+		throttled := throttledRatio >= thermalThrottledCPURatioThreshold
+		metrics.RecordNodeCPUThermalThrottled(throttled)
+		c.updateNodeCondition(throttled, fmt.Sprintf("%d of %d cpus are thermally throttled in the last %v",
+			throttledCPUs, len(stats), c.collectInterval))
+	}
+
+	c.saveMetric(samples)
+	c.started.Store(true)
+	klog.V(5).Infof("collectCPUThermal finished at %s, cpu num %d, throttled cpu num %d", timeNow(), len(stats), throttledCPUs)
+}
+
+// isThermalThrottled returns whether the cpu has new thermal throttling events since the last collection.
+func isThermalThrottled(last, cur system.CPUFrequencyStat) bool {
+	if !last.HasThrottleCount || !cur.HasThrottleCount {
+		return false
+	}
+	return cur.CoreThrottleCount > last.CoreThrottleCount || cur.PackageThrottleCount > last.PackageThrottleCount
+}
+
+// getTurboRatio returns the ratio of the average frequency to the base frequency since the last collection.
+func getTurboRatio(last, cur system.CPUFrequencyStat) (float64, bool) {
+	if !last.HasPerfCounters || !cur.HasPerfCounters {
+		return 0, false
+	}
+	// the counters are reset, e.g. the cpu is offline and online again
+	if cur.APERF < last.APERF || cur.MPERF <= last.MPERF {
+		return 0, false
+	}
+	return float64(cur.APERF-last.APERF) / float64(cur.MPERF-last.MPERF), true
+}
+
+// updateNodeCondition patches the thermal throttled condition of the node if the status changes.
+func (c *cpuThermalCollector) updateNodeCondition(throttled bool, message string) {
+	if c.kubeClient == nil {
+		return
+	}
+	node := c.statesInformer.GetNode()
+	if node == nil {
+		klog.V(5).Infof("skip updating cpu thermal condition since the node is not synced")
+		return
+	}
+	status, reason := corev1.ConditionFalse, ReasonCPUThermalNormal
+	if throttled {
+		status, reason = corev1.ConditionTrue, ReasonCPUThermalThrottled
+	}
+	if c.lastConditionStatus == status {
+		return
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == apiext.NodeConditionCPUThermalThrottled && condition.Status == status {
+			c.lastConditionStatus = status
+			return
+		}
+	}
+
+	condition := corev1.NodeCondition{
+		Type:               apiext.NodeConditionCPUThermalThrottled,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(timeNow()),
+	}
+	if err := nodeutil.SetNodeCondition(c.kubeClient, types.NodeName(node.Name), condition); err != nil {
+		klog.Warningf("failed to update cpu thermal condition of node %s, err: %v", node.Name, err)
+		return
+	}
+	c.lastConditionStatus = status
+	klog.V(4).Infof("update cpu thermal condition of node %s to %s, %s", node.Name, status, message)
+}
+
+func (c *cpuThermalCollector) saveMetric(samples []metriccache.MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	appender := c.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append cpu thermal metrics error")
+		return err
+	}
+
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit cpu thermal metrics failed")
+		return err
+	}
+
+	return nil
+}
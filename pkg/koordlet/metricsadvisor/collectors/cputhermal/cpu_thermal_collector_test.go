@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cputhermal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestNewCPUThermalCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config: framework.NewDefaultConfig(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.CPUThermalCollector), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func Test_getTurboRatio(t *testing.T) {
+	tests := []struct {
+		name      string
+		last      system.CPUFrequencyStat
+		cur       system.CPUFrequencyStat
+		wantRatio float64
+		wantOK    bool
+	}{
+		{
+			name:      "turbo",
+			last:      system.CPUFrequencyStat{APERF: 1000, MPERF: 1000, HasPerfCounters: true},
+			cur:       system.CPUFrequencyStat{APERF: 2500, MPERF: 2000, HasPerfCounters: true},
+			wantRatio: 1.5,
+			wantOK:    true,
+		},
+		{
+			name:   "counters reset",
+			last:   system.CPUFrequencyStat{APERF: 1000, MPERF: 1000, HasPerfCounters: true},
+			cur:    system.CPUFrequencyStat{APERF: 100, MPERF: 100, HasPerfCounters: true},
+			wantOK: false,
+		},
+		{
+			name:   "counters not supported",
+			last:   system.CPUFrequencyStat{},
+			cur:    system.CPUFrequencyStat{},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRatio, gotOK := getTurboRatio(tt.last, tt.cur)
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantRatio, gotRatio)
+		})
+	}
+}
+
+func TestCPUThermalCollector_collectCPUThermal(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldMSRDevDir := system.MSRDevDir
+	system.MSRDevDir = t.TempDir()
+	defer func() {
+		system.MSRDevDir = oldMSRDevDir
+	}()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(node)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetNode().Return(node).AnyTimes()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	writeCPU := func(cpu int, freqKHz uint64, coreThrottleCount uint64) {
+		cpuDir := fmt.Sprintf("devices/system/cpu/cpu%d", cpu)
+		helper.WriteFileContents(cpuDir+"/cpufreq/scaling_cur_freq", fmt.Sprintf("%d\n", freqKHz))
+		helper.WriteFileContents(cpuDir+"/cpufreq/cpuinfo_max_freq", "3500000\n")
+		helper.WriteFileContents(cpuDir+"/thermal_throttle/core_throttle_count", fmt.Sprintf("%d\n", coreThrottleCount))
+		helper.WriteFileContents(cpuDir+"/thermal_throttle/package_throttle_count", "0\n")
+	}
+	writeCPU(0, 3000000, 0)
+	writeCPU(1, 2000000, 0)
+
+	c := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		MetricCache:    metricCache,
+		KubeClient:     kubeClient,
+	}).(*cpuThermalCollector)
+	c.collectCPUThermal()
+	assert.True(t, c.Started())
+	gotNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, apiext.IsNodeCPUThermalThrottled(gotNode))
+	assert.Len(t, gotNode.Status.Conditions, 0)
+
+	// cpu 1 is throttled
+	testNow = testNow.Add(10 * time.Second)
+	writeCPU(0, 3000000, 0)
+	writeCPU(1, 1000000, 2)
+	c.collectCPUThermal()
+	gotNode, err = kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.True(t, apiext.IsNodeCPUThermalThrottled(gotNode))
+	assert.Equal(t, ReasonCPUThermalThrottled, gotNode.Status.Conditions[0].Reason)
+
+	querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+	assert.NoError(t, err)
+	for _, tt := range []struct {
+		resource  metriccache.MetricResource
+		wantValue float64
+	}{
+		{resource: metriccache.NodeCPUFrequencyMetric, wantValue: 2000},
+		{resource: metriccache.NodeCPUThermalThrottledRatioMetric, wantValue: 0.5},
+	} {
+		queryMeta, err := tt.resource.BuildQueryMeta(nil)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantValue, got)
+	}
+
+	// the throttling stops
+	testNow = testNow.Add(10 * time.Second)
+	c.collectCPUThermal()
+	gotNode, err = kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, apiext.IsNodeCPUThermalThrottled(gotNode))
+	assert.Equal(t, ReasonCPUThermalNormal, gotNode.Status.Conditions[0].Reason)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 )
 
@@ -80,7 +81,7 @@ func (h *hostAppCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	go wait.Until(h.collectHostAppResUsed, h.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, h.collectHostAppResUsed), h.collectInterval, stopCh)
 }
 
 func (h *hostAppCollector) Started() bool {
@@ -0,0 +1,247 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netlatency
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CollectorName = "NetworkLatencyCollector"
+
+	// maxProbePodsPerQoS is the maximum number of the pods probed for each QoS class in a round.
+	// The pods are probed in turns if there are more.
+	maxProbePodsPerQoS = 5
+)
+
+var (
+	timeNow = time.Now
+
+	// probeTCP measures the tcp connect round-trip time from the network namespace of the process.
+	// It can be overwritten for testing.
+	probeTCP = func(pid uint32, addr *net.TCPAddr, timeout time.Duration) (time.Duration, error) {
+		var rtt time.Duration
+		err := system.RunInNetNS(pid, func() error {
+			start := time.Now()
+			conn, err := net.DialTimeout("tcp", addr.String(), timeout)
+			if err != nil {
+				return err
+			}
+			rtt = time.Since(start)
+			return conn.Close()
+		})
+		return rtt, err
+	}
+)
+
+// networkLatencyCollector probes the network latency from the pods to the configured endpoint of their QoS classes.
+// The probes are tcp connections dialed in the network namespaces of the pods, so the latency includes the overhead
+// of the pod network datapath. A probe violates the latency SLO if it fails or its round-trip time exceeds the
+// threshold.
+type networkLatencyCollector struct {
+	collectInterval time.Duration
+	probeTimeout    time.Duration
+	sloThreshold    time.Duration
+	endpoints       map[string]string
+
+	started        *atomic.Bool
+	appendableDB   metriccache.Appendable
+	statesInformer statesinformer.StatesInformer
+	cgroupReader   resourceexecutor.CgroupReader
+	podFilter      framework.PodFilter
+
+	// probeOffsets records the index of the first pod to probe for each QoS class in the next round
+	probeOffsets map[string]int
+}
+
+func New(opt *framework.Options) framework.Collector {
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &networkLatencyCollector{
+		collectInterval: opt.Config.NetworkLatencyProberInterval,
+		probeTimeout:    opt.Config.NetworkLatencyProbeTimeout,
+		sloThreshold:    opt.Config.NetworkLatencySLOThreshold,
+		endpoints:       opt.Config.NetworkLatencyProbeEndpoints,
+		started:         atomic.NewBool(false),
+		appendableDB:    opt.MetricCache,
+		statesInformer:  opt.StatesInformer,
+		cgroupReader:    opt.CgroupReader,
+		podFilter:       podFilter,
+		probeOffsets:    map[string]int{},
+	}
+}
+
+var _ framework.PodCollector = &networkLatencyCollector{}
+
+func (c *networkLatencyCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NetworkLatencyProber)
+}
+
+func (c *networkLatencyCollector) Setup(ctx *framework.Context) {}
+
+func (c *networkLatencyCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, c.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	if len(c.endpoints) == 0 {
+		klog.Warningf("skip probing network latency since no probe endpoint is configured")
+		c.started.Store(true)
+		return
+	}
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, c.collectNetworkLatency), c.collectInterval, stopCh)
+}
+
+func (c *networkLatencyCollector) Started() bool {
+	return c.started.Load()
+}
+
+func (c *networkLatencyCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return c.podFilter.FilterPod(meta)
+}
+
+func (c *networkLatencyCollector) collectNetworkLatency() {
+	klog.V(6).Info("start collectNetworkLatency")
+	podsByQoS := map[string][]*statesinformer.PodMeta{}
+	for _, meta := range c.statesInformer.GetAllPods() {
+		pod := meta.Pod
+		if filtered, msg := c.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip probe pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+			continue
+		}
+		// the host network pods share the latency of the node
+		if pod.Spec.HostNetwork || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		qos := string(apiext.GetPodQoSClassWithDefault(pod))
+		if _, ok := c.endpoints[qos]; ok {
+			podsByQoS[qos] = append(podsByQoS[qos], meta)
+		}
+	}
+
+	var samples []metriccache.MetricSample
+	metrics.ResetPodNetworkLatency()
+	for qos, endpoint := range c.endpoints {
+		addr, err := net.ResolveTCPAddr("tcp", endpoint)
+		if err != nil {
+			klog.Warningf("failed to resolve network latency probe endpoint %s of QoS %s, err: %v", endpoint, qos, err)
+			continue
+		}
+		var probed, violated, succeeded int
+		var totalRTT time.Duration
+		for _, meta := range c.selectPods(qos, podsByQoS[qos]) {
+			pod := meta.Pod
+			pids, err := c.cgroupReader.ReadCPUProcs(meta.CgroupDir)
+			if err != nil || len(pids) == 0 {
+				klog.V(5).Infof("skip probe pod %s/%s since no process found, err: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+			probed++
+			rtt, err := probeTCP(pids[0], addr, c.probeTimeout)
+			collectTime := timeNow()
+			if err != nil {
+				klog.V(4).Infof("failed to probe network latency from pod %s/%s to %s, err: %v", pod.Namespace, pod.Name, endpoint, err)
+				violated++
+				continue
+			}
+			succeeded++
+			totalRTT += rtt
+			if rtt > c.sloThreshold {
+				violated++
+			}
+			metrics.RecordPodNetworkLatency(pod, qos, endpoint, rtt.Seconds())
+			sample, err := metriccache.PodNetworkLatencyMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.Pod(string(pod.UID)), collectTime, rtt.Seconds())
+			if err != nil {
+				klog.V(4).Infof("failed to generate network latency sample of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+				continue
+			}
+			samples = append(samples, sample)
+		}
+		if probed <= 0 {
+			continue
+		}
+
+		collectTime := timeNow()
+		violationRatio := float64(violated) / float64(probed)
+		metrics.RecordNodeQoSNetworkLatencySLOViolation(qos, violationRatio)
+		properties := metriccache.MetricPropertiesFunc.QoS(qos)
+		if sample, err := metriccache.NodeQoSNetworkLatencySLOViolationMetric.GenerateSample(properties, collectTime, violationRatio); err == nil {
+			samples = append(samples, sample)
+		} else {
+			klog.V(4).Infof("failed to generate network latency slo violation sample of QoS %s, err: %v", qos, err)
+		}
+		if succeeded <= 0 {
+			continue
+		}
+		avgRTT := totalRTT.Seconds() / float64(succeeded)
+		if sample, err := metriccache.NodeQoSNetworkLatencyMetric.GenerateSample(properties, collectTime, avgRTT); err == nil {
+			samples = append(samples, sample)
+		} else {
+			klog.V(4).Infof("failed to generate network latency sample of QoS %s, err: %v", qos, err)
+		}
+	}
+
+	appender := c.appendableDB.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append network latency metrics error")
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit network latency metrics failed")
+		return
+	}
+	c.started.Store(true)
+	klog.V(5).Infof("collectNetworkLatency finished, samples %d", len(samples))
+}
+
+// selectPods returns at most maxProbePodsPerQoS pods of the QoS class in turns.
+func (c *networkLatencyCollector) selectPods(qos string, pods []*statesinformer.PodMeta) []*statesinformer.PodMeta {
+	if len(pods) <= maxProbePodsPerQoS {
+		return pods
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Pod.UID < pods[j].Pod.UID
+	})
+	offset := c.probeOffsets[qos] % len(pods)
+	c.probeOffsets[qos] = offset + maxProbePodsPerQoS
+	selected := make([]*statesinformer.PodMeta, 0, maxProbePodsPerQoS)
+	for i := 0; i < maxProbePodsPerQoS; i++ {
+		selected = append(selected, pods[(offset+i)%len(pods)])
+	}
+	return selected
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netlatency
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestNewNetworkLatencyCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.NetworkLatencyProber), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func newTestPodMeta(name string, qos apiext.QoSClass, hostNetwork bool) *statesinformer.PodMeta {
+	return &statesinformer.PodMeta{
+		CgroupDir: "kubepods.slice/kubepods-pod" + name + ".slice",
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				UID:       types.UID(name),
+				Labels: map[string]string{
+					apiext.LabelPodQoS: string(qos),
+				},
+			},
+			Spec: corev1.PodSpec{
+				HostNetwork: hostNetwork,
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+	}
+}
+
+func Test_networkLatencyCollector_collectNetworkLatency(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+
+	lsPod := newTestPodMeta("ls-pod", apiext.QoSLS, false)
+	slowLSPod := newTestPodMeta("slow-ls-pod", apiext.QoSLS, false)
+	failedLSPod := newTestPodMeta("failed-ls-pod", apiext.QoSLS, false)
+	hostNetworkPod := newTestPodMeta("host-network-pod", apiext.QoSLS, true)
+	bePod := newTestPodMeta("be-pod", apiext.QoSBE, false)
+	pidOfPods := map[*statesinformer.PodMeta]string{
+		lsPod:          "100",
+		slowLSPod:      "200",
+		failedLSPod:    "300",
+		hostNetworkPod: "400",
+		bePod:          "500",
+	}
+	for meta, pid := range pidOfPods {
+		helper.WriteCgroupFileContents("/"+meta.CgroupDir, system.CPUProcsV2, pid+"\n")
+	}
+
+	oldProbeTCP := probeTCP
+	defer func() {
+		probeTCP = oldProbeTCP
+	}()
+	var probedPIDs []uint32
+	probeTCP = func(pid uint32, addr *net.TCPAddr, timeout time.Duration) (time.Duration, error) {
+		probedPIDs = append(probedPIDs, pid)
+		switch pid {
+		case 100:
+			return 2 * time.Millisecond, nil
+		case 200:
+			return 20 * time.Millisecond, nil
+		}
+		return 0, fmt.Errorf("connection refused")
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		lsPod, slowLSPod, failedLSPod, hostNetworkPod, bePod,
+	}).Times(1)
+
+	collector := New(&framework.Options{
+		Config: &framework.Config{
+			NetworkLatencyProberInterval: time.Second,
+			NetworkLatencyProbeTimeout:   time.Second,
+			NetworkLatencySLOThreshold:   10 * time.Millisecond,
+			NetworkLatencyProbeEndpoints: map[string]string{
+				string(apiext.QoSLS): "127.0.0.1:80",
+			},
+		},
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*networkLatencyCollector)
+	start := time.Now().Add(-time.Second)
+	assert.NotPanics(t, func() {
+		c.collectNetworkLatency()
+	})
+	assert.True(t, c.Started())
+	assert.ElementsMatch(t, []uint32{100, 200, 300}, probedPIDs)
+
+	querier, err := metricCache.Querier(start, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	queryLast := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string) (float64, int) {
+		queryMeta, err := resource.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		if result.Count() == 0 {
+			return 0, 0
+		}
+		value, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		return value, result.Count()
+	}
+
+	value, count := queryLast(metriccache.PodNetworkLatencyMetric, metriccache.MetricPropertiesFunc.Pod(string(lsPod.Pod.UID)))
+	assert.Equal(t, 1, count)
+	assert.InDelta(t, 0.002, value, 0.0001)
+	_, count = queryLast(metriccache.PodNetworkLatencyMetric, metriccache.MetricPropertiesFunc.Pod(string(failedLSPod.Pod.UID)))
+	assert.Equal(t, 0, count)
+	_, count = queryLast(metriccache.PodNetworkLatencyMetric, metriccache.MetricPropertiesFunc.Pod(string(bePod.Pod.UID)))
+	assert.Equal(t, 0, count)
+
+	value, count = queryLast(metriccache.NodeQoSNetworkLatencyMetric, metriccache.MetricPropertiesFunc.QoS(string(apiext.QoSLS)))
+	assert.Equal(t, 1, count)
+	assert.InDelta(t, 0.011, value, 0.0001)
+	value, count = queryLast(metriccache.NodeQoSNetworkLatencySLOViolationMetric, metriccache.MetricPropertiesFunc.QoS(string(apiext.QoSLS)))
+	assert.Equal(t, 1, count)
+	assert.InDelta(t, 2.0/3, value, 0.0001)
+	_, count = queryLast(metriccache.NodeQoSNetworkLatencySLOViolationMetric, metriccache.MetricPropertiesFunc.QoS(string(apiext.QoSBE)))
+	assert.Equal(t, 0, count)
+}
+
+func Test_networkLatencyCollector_selectPods(t *testing.T) {
+	c := &networkLatencyCollector{
+		probeOffsets: map[string]int{},
+	}
+	var pods []*statesinformer.PodMeta
+	for i := 0; i < 7; i++ {
+		pods = append(pods, newTestPodMeta(fmt.Sprintf("pod-%d", i), apiext.QoSLS, false))
+	}
+	getNames := func(metas []*statesinformer.PodMeta) []string {
+		var names []string
+		for _, meta := range metas {
+			names = append(names, meta.Pod.Name)
+		}
+		return names
+	}
+	qos := string(apiext.QoSLS)
+	assert.Equal(t, []string{"pod-0", "pod-1"}, getNames(c.selectPods(qos, pods[:2])))
+	assert.Equal(t, []string{"pod-0", "pod-1", "pod-2", "pod-3", "pod-4"}, getNames(c.selectPods(qos, pods)))
+	assert.Equal(t, []string{"pod-5", "pod-6", "pod-0", "pod-1", "pod-2"}, getNames(c.selectPods(qos, pods)))
+	assert.Equal(t, []string{"pod-3", "pod-4", "pod-5", "pod-6", "pod-0"}, getNames(c.selectPods(qos, pods)))
+}
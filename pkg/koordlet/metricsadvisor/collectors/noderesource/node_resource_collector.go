@@ -27,6 +27,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -78,7 +79,7 @@ func (n *nodeResourceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for devices to sync")
 	}
-	go wait.Until(n.collectNodeResUsed, n.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, n.collectNodeResUsed), n.collectInterval, stopCh)
 }
 
 func (n *nodeResourceCollector) Started() bool {
@@ -27,6 +27,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 )
 
@@ -55,7 +56,7 @@ func (n *nodeInfoCollector) Enabled() bool {
 func (n *nodeInfoCollector) Setup(s *framework.Context) {}
 
 func (n *nodeInfoCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(n.collectNodeLocalStorageInfo, n.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, n.collectNodeLocalStorageInfo), n.collectInterval, stopCh)
 }
 
 func (n *nodeInfoCollector) Started() bool {
@@ -29,6 +29,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -76,7 +77,7 @@ func (p *pageCacheCollector) Enabled() bool {
 func (p *pageCacheCollector) Setup(c *framework.Context) {}
 
 func (p *pageCacheCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(p.collectPageCache, p.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, p.collectPageCache), p.collectInterval, stopCh)
 }
 
 func (p *pageCacheCollector) Started() bool {
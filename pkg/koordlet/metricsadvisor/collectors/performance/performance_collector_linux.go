@@ -38,13 +38,10 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/perf"
 	perfgroup "github.com/koordinator-sh/koordinator/pkg/koordlet/util/perf_group"
-	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
-	tools "github.com/koordinator-sh/koordinator/pkg/util"
 )
 
 type performanceCollector struct {
 	cpiCollectInterval        time.Duration
-	psiCollectInterval        time.Duration
 	collectTimeWindowDuration time.Duration
 
 	started        *atomic.Bool
@@ -56,7 +53,6 @@ type performanceCollector struct {
 func New(opt *framework.Options) framework.Collector {
 	return &performanceCollector{
 		cpiCollectInterval:        opt.Config.CPICollectorInterval,
-		psiCollectInterval:        opt.Config.PSICollectorInterval,
 		collectTimeWindowDuration: opt.Config.CPICollectorTimeWindow,
 
 		started:        atomic.NewBool(false),
@@ -68,7 +64,7 @@ func New(opt *framework.Options) framework.Collector {
 
 func (p *performanceCollector) Enabled() bool {
 	// TODO: add tma analyze feature gate
-	return features.DefaultKoordletFeatureGate.Enabled(features.CPICollector) || features.DefaultKoordletFeatureGate.Enabled(features.PSICollector)
+	return features.DefaultKoordletFeatureGate.Enabled(features.CPICollector)
 }
 
 func (p *performanceCollector) EnabledPerf() bool {
@@ -82,9 +78,6 @@ func (p *performanceCollector) Run(stopCh <-chan struct{}) {
 		// Koordlet exit because of statesInformer sync failed.
 		klog.Fatalf("timed out waiting for states informer caches to sync")
 	}
-	if features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) {
-		p.collectPSI(stopCh)
-	}
 	if p.EnabledPerf() {
 		if features.DefaultKoordletFeatureGate.Enabled(features.Libpfm4) {
 			perfgroup.LibInit()
@@ -232,180 +225,6 @@ func (p *performanceCollector) profileCPIOnSingleContainer(status *corev1.Contai
 	return cpiMetrics
 }
 
-func (p *performanceCollector) collectContainerPSI() {
-	klog.V(6).Infof("start collectContainerPSI")
-	timeWindow := time.Now()
-	containerStatusesMap := map[*corev1.ContainerStatus]*statesinformer.PodMeta{}
-	podMetas := p.statesInformer.GetAllPods()
-	for _, meta := range podMetas {
-		pod := meta.Pod
-		for i := range pod.Status.ContainerStatuses {
-			containerStat := &pod.Status.ContainerStatuses[i]
-			containerStatusesMap[containerStat] = meta
-		}
-	}
-
-	metrics.ResetContainerPSI()
-
-	var wg sync.WaitGroup
-	var mutex sync.Mutex
-	wg.Add(len(containerStatusesMap))
-	psiMetrics := make([]metriccache.MetricSample, 0)
-	for containerStatus, podMeta := range containerStatusesMap {
-		pod := podMeta.Pod
-		cgroupDir := podMeta.CgroupDir
-		go func(parentDir string, status *corev1.ContainerStatus, pod *corev1.Pod) {
-			defer wg.Done()
-			metrics := p.collectSingleContainerPSI(parentDir, status, pod)
-			mutex.Lock()
-			psiMetrics = append(psiMetrics, metrics...)
-			mutex.Unlock()
-		}(cgroupDir, containerStatus, pod)
-	}
-	wg.Wait()
-
-	// save container's psi metrics to tsdb
-	p.saveMetric(psiMetrics)
-
-	p.started.Store(true)
-	klog.V(5).Infof("collectContainerPSI for time window %s finished at %s, container num %d",
-		timeWindow, time.Now(), len(containerStatusesMap))
-}
-
-func (p *performanceCollector) collectSingleContainerPSI(podParentCgroupDir string, containerStatus *corev1.ContainerStatus, pod *corev1.Pod) []metriccache.MetricSample {
-	psiMetrics := make([]metriccache.MetricSample, 0)
-	collectTime := time.Now()
-	containerPath, err := util.GetContainerCgroupParentDir(podParentCgroupDir, containerStatus)
-	if err != nil {
-		klog.Errorf("failed to get container path for container %v/%v/%v cgroup path failed, error: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
-		return psiMetrics
-	}
-	containerPSI, err := p.cgroupReader.ReadPSI(containerPath)
-	if err != nil {
-		klog.Errorf("collect container %s psi err: %v", containerStatus.Name, err)
-		return psiMetrics
-	}
-
-	cpuSomeAvg10, err01 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, containerPSI.CPU.Some.Avg10)
-	memSomeAvg10, err02 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, containerPSI.Mem.Some.Avg10)
-	ioSomeAvg10, err03 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, containerPSI.IO.Some.Avg10)
-
-	cpuFullAvg10, err04 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, containerPSI.CPU.Full.Avg10)
-	memFullAvg10, err05 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, containerPSI.Mem.Full.Avg10)
-	ioFullAvg10, err06 := metriccache.ContainerPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.ContainerPSI(string(pod.GetUID()), containerStatus.ContainerID, string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, containerPSI.IO.Full.Avg10)
-
-	cpuFullSupported, err07 := metriccache.ContainerPSICPUFullSupportedMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PSICPUFullSupported(string(pod.GetUID()), containerStatus.ContainerID), collectTime, tools.BoolToFloat64(containerPSI.CPU.FullSupported))
-
-	if err01 != nil || err02 != nil || err03 != nil || err04 != nil || err05 != nil ||
-		err06 != nil || err07 != nil {
-		klog.Warningf(
-			"failed to collect Container %s/%s/%s PSI failed, cpuSomeAvg10 err: %s, memSomeAvg10 err: %s, ioSomeAvg10 err: %s, cpuFullAvg10 err: %s, memFullAvg10 err: %s, ioFullAvg10 err: %s, cpuFullSupported err: %s",
-			pod.GetNamespace(), pod.GetName(), containerStatus.Name, err02, err03, err04, err05, err06, err07)
-		return psiMetrics
-	}
-	psiMetrics = append(psiMetrics, cpuSomeAvg10, memSomeAvg10, ioSomeAvg10, cpuFullAvg10, memFullAvg10, ioFullAvg10, cpuFullSupported)
-
-	metrics.RecordContainerPSI(containerStatus, pod, containerPSI)
-
-	return psiMetrics
-}
-
-func (p *performanceCollector) collectPodPSI() {
-	klog.V(6).Infof("start collectPodPSI")
-	timeWindow := time.Now()
-	podMetas := p.statesInformer.GetAllPods()
-	metrics.ResetPodPSI()
-
-	var wg sync.WaitGroup
-	var mutex sync.Mutex
-	wg.Add(len(podMetas))
-	psiMetrics := make([]metriccache.MetricSample, 0)
-	for _, meta := range podMetas {
-		pod := meta.Pod
-		podCgroupDir := meta.CgroupDir
-		go func(pod *corev1.Pod, podCgroupDir string) {
-			defer wg.Done()
-			metrics := p.collectSinglePodPSI(pod, podCgroupDir)
-			mutex.Lock()
-			psiMetrics = append(psiMetrics, metrics...)
-			mutex.Unlock()
-		}(pod, podCgroupDir)
-	}
-	wg.Wait()
-
-	// save pod psi metrics to tsdb
-	p.saveMetric(psiMetrics)
-
-	p.started.Store(true)
-	klog.V(5).Infof("collectPodPSI for time window %s finished at %s, pod num %d",
-		timeWindow, time.Now(), len(podMetas))
-}
-
-func (p *performanceCollector) collectSinglePodPSI(pod *corev1.Pod, podCgroupDir string) []metriccache.MetricSample {
-	psiMetrics := make([]metriccache.MetricSample, 0)
-	podPSI, err := p.cgroupReader.ReadPSI(podCgroupDir)
-	collectTime := time.Now()
-	if err != nil {
-		klog.Errorf("collect pod %v/%v psi err: %v", pod.Namespace, pod.Name, err)
-		return psiMetrics
-	}
-
-	cpuSomeAvg10, err01 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, podPSI.CPU.Some.Avg10)
-	memSomeAvg10, err02 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, podPSI.Mem.Some.Avg10)
-	ioSomeAvg10, err03 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), collectTime, podPSI.IO.Some.Avg10)
-
-	cpuFullAvg10, err04 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, podPSI.CPU.Full.Avg10)
-	memFullAvg10, err05 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, podPSI.Mem.Full.Avg10)
-	ioFullAvg10, err06 := metriccache.PodPSIMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.PodPSI(string(pod.GetUID()), string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)), collectTime, podPSI.IO.Full.Avg10)
-	cpuFullSupported, err07 := metriccache.PodPSICPUFullSupportedMetric.GenerateSample(
-		metriccache.MetricPropertiesFunc.Pod(string(pod.GetUID())), collectTime, tools.BoolToFloat64(podPSI.CPU.FullSupported))
-
-	if err01 != nil || err02 != nil || err03 != nil || err04 != nil || err05 != nil ||
-		err06 != nil || err07 != nil {
-		klog.Warningf(
-			"failed to collect pod %s/%s PSI, cpuSomeAvg10 err: %s, memSomeAvg10 err: %s, ioSomeAvg10 err: %s, cpuFullAvg10 err: %s, memFullAvg10 err: %s, ioFullAvg10 err: %s, cpuFullSupported err: %s",
-			pod.GetNamespace(), pod.GetName(), err02, err03, err04, err05, err06, err07)
-		return psiMetrics
-	}
-	psiMetrics = append(psiMetrics, cpuSomeAvg10, memSomeAvg10, ioSomeAvg10, cpuFullAvg10, memFullAvg10, ioFullAvg10, cpuFullSupported)
-
-	metrics.RecordPodPSI(pod, podPSI)
-
-	return psiMetrics
-}
-
-func (p *performanceCollector) collectPSI(stopCh <-chan struct{}) {
-	// CgroupV1 psi collector support only on anolis os currently
-	if system.GetCurrentCgroupVersion() == system.CgroupVersionV1 {
-		cpuPressureCheck, _ := system.CPUAcctCPUPressure.IsSupported("")
-		memPressureCheck, _ := system.CPUAcctMemoryPressure.IsSupported("")
-		ioPressureCheck, _ := system.CPUAcctIOPressure.IsSupported("")
-		if !(cpuPressureCheck && memPressureCheck && ioPressureCheck) {
-			klog.V(4).Infof("Collect psi failed, system now not support psi feature in CgroupV1, please check pressure file exist and readable in cpuacct directory.")
-			//skip collect psi when system not support
-			p.started.Store(true)
-			return
-		}
-	}
-	go wait.Until(func() {
-		p.collectContainerPSI()
-		p.collectPodPSI()
-	}, p.psiCollectInterval, stopCh)
-}
-
 func (p *performanceCollector) saveMetric(samples []metriccache.MetricSample) error {
 	if len(samples) == 0 {
 		return nil
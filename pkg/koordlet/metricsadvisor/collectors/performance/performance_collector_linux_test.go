@@ -18,7 +18,6 @@ package performance
 
 import (
 	"os"
-	"path"
 	"syscall"
 	"testing"
 
@@ -38,7 +37,6 @@ import (
 	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	perfgroup "github.com/koordinator-sh/koordinator/pkg/koordlet/util/perf_group"
-	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func TestNewPerformanceCollector(t *testing.T) {
@@ -248,116 +246,6 @@ func Test_profilePerfOnSingleContainer(t *testing.T) {
 	})
 }
 
-func mockInterferencePodMeta(cgroupDir string) *statesinformer.PodMeta {
-	return &statesinformer.PodMeta{
-		Pod: &corev1.Pod{
-			Status: corev1.PodStatus{
-				ContainerStatuses: []corev1.ContainerStatus{
-					{
-						ContainerID: "containerd://test01",
-					},
-				},
-			},
-		},
-		CgroupDir: cgroupDir,
-	}
-}
-
-const (
-	FullCorrectPSIContents = "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0"
-)
-
-func Test_collectContainerPSI(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	dir := t.TempDir()
-	system.Conf.CgroupRootDir = dir
-
-	cgroupDir := t.TempDir()
-	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
-	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
-	testPodMeta := mockInterferencePodMeta(t.TempDir())
-	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodMeta}).AnyTimes()
-
-	paths := getPodCgroupCPUAcctPSIPath(cgroupDir)
-	errCreateCPU := createTestPSIFile(paths.CPU, FullCorrectPSIContents)
-	if errCreateCPU != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateCPU)
-	}
-	errCreateMem := createTestPSIFile(paths.Mem, FullCorrectPSIContents)
-	if errCreateMem != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateMem)
-	}
-	errCreateIO := createTestPSIFile(paths.IO, FullCorrectPSIContents)
-	if errCreateIO != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateIO)
-	}
-
-	collector := New(&framework.Options{
-		Config:         framework.NewDefaultConfig(),
-		StatesInformer: mockStatesInformer,
-		MetricCache:    mockMetricCache,
-		CgroupReader:   resourceexecutor.NewCgroupReader(),
-	})
-	c := collector.(*performanceCollector)
-	assert.NotPanics(t, func() {
-		c.collectContainerPSI()
-	})
-}
-
-func Test_collectPodPSI(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-	dir := t.TempDir()
-	system.Conf.CgroupRootDir = dir
-
-	cgroupDir := t.TempDir()
-	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
-	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
-	testPodMeta := mockInterferencePodMeta(t.TempDir())
-	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodMeta}).AnyTimes()
-
-	paths := getPodCgroupCPUAcctPSIPath(cgroupDir)
-	errCreateCPU := createTestPSIFile(paths.CPU, FullCorrectPSIContents)
-	if errCreateCPU != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateCPU)
-	}
-	errCreateMem := createTestPSIFile(paths.Mem, FullCorrectPSIContents)
-	if errCreateMem != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateMem)
-	}
-	errCreateIO := createTestPSIFile(paths.IO, FullCorrectPSIContents)
-	if errCreateIO != nil {
-		t.Fatalf("got error when create psi files: %v", errCreateIO)
-	}
-
-	collector := New(&framework.Options{
-		Config:         framework.NewDefaultConfig(),
-		StatesInformer: mockStatesInformer,
-		MetricCache:    mockMetricCache,
-		CgroupReader:   resourceexecutor.NewCgroupReader(),
-	})
-	c := collector.(*performanceCollector)
-	assert.NotPanics(t, func() {
-		c.collectPodPSI()
-	})
-}
-
-func createTestPSIFile(filePath, contents string) error {
-	dir, _ := path.Split(filePath)
-	if err := os.MkdirAll(dir, 0777); err != nil {
-		return err
-	}
-	if _, err := os.Create(filePath); err != nil {
-		return err
-	}
-	err := os.WriteFile(filePath, []byte(contents), 0644)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func mockLSPod() *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
@@ -396,18 +284,3 @@ func mockLSPod() *corev1.Pod {
 		},
 	}
 }
-
-// @podParentDir kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/
-//
-//	@return {
-//	   CPU: /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/cpu.pressure
-//	   Mem: /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/memory.pressure
-//	   IO:  /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/io.pressure
-//	 }
-func getPodCgroupCPUAcctPSIPath(podParentDir string) system.PSIPath {
-	return system.PSIPath{
-		CPU: system.GetCgroupFilePath(podParentDir, system.CPUAcctCPUPressure),
-		Mem: system.GetCgroupFilePath(podParentDir, system.CPUAcctMemoryPressure),
-		IO:  system.GetCgroupFilePath(podParentDir, system.CPUAcctIOPressure),
-	}
-}
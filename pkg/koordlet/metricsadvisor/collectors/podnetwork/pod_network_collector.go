@@ -0,0 +1,282 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podnetwork
+
+import (
+	"path/filepath"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netprobe"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CollectorName = "PodNetworkCollector"
+
+	loopbackInterface = "lo"
+)
+
+var (
+	timeNow = time.Now
+
+	// newProbe creates the eBPF probe, which can be overwritten for testing.
+	newProbe = func() (networkProbe, error) {
+		probe, err := netprobe.NewProbe()
+		if err != nil {
+			return nil, err
+		}
+		return probe, nil
+	}
+)
+
+// networkProbe counts the network traffic of the cgroups.
+type networkProbe interface {
+	Attach(cgroupPath string) error
+	Detach(cgroupPath string) error
+	AttachedCgroups() []string
+	Read(cgroupPath string) (*netprobe.Stats, error)
+	Close() error
+}
+
+// networkSnapshot is the network counters of a pod read at the timestamp.
+type networkSnapshot struct {
+	stats netprobe.Stats
+	// drops is the packets dropped on the interfaces of the pod network namespace, which is only collected for the
+	// pods not using the host network.
+	drops          uint64
+	dropsCollected bool
+	timestamp      time.Time
+}
+
+type networkValue struct {
+	networkType metriccache.MetricPropertyValue
+	value       float64
+}
+
+// podNetworkCollector collects the ingress/egress bandwidth and the tcp retransmissions of the pods with the eBPF
+// programs attached to the pod cgroups, which does not rely on the counters of any specific CNI and also works for
+// the pods using the host network. Since the packet drops cannot be attributed to the cgroups in the datapath, they
+// are read from the interfaces in the network namespace of the pods.
+type podNetworkCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	appendableDB    metriccache.Appendable
+	statesInformer  statesinformer.StatesInformer
+	cgroupReader    resourceexecutor.CgroupReader
+	podFilter       framework.PodFilter
+
+	probe       networkProbe
+	lastPodStat *gocache.Cache
+}
+
+func New(opt *framework.Options) framework.Collector {
+	collectInterval := opt.Config.CollectResUsedInterval
+	podFilter := framework.DefaultPodFilter
+	if filter, ok := opt.PodFilters[CollectorName]; ok {
+		podFilter = filter
+	}
+	return &podNetworkCollector{
+		collectInterval: collectInterval,
+		started:         atomic.NewBool(false),
+		appendableDB:    opt.MetricCache,
+		statesInformer:  opt.StatesInformer,
+		cgroupReader:    opt.CgroupReader,
+		podFilter:       podFilter,
+		lastPodStat:     gocache.New(collectInterval*framework.ContextExpiredRatio, framework.CleanupInterval),
+	}
+}
+
+var _ framework.PodCollector = &podNetworkCollector{}
+
+func (p *podNetworkCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.PodNetworkCollector)
+}
+
+func (p *podNetworkCollector) Setup(c *framework.Context) {}
+
+func (p *podNetworkCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, p.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	if system.GetCurrentCgroupVersion() != system.CgroupVersionV2 {
+		klog.Warningf("skip collecting pod network since the eBPF cgroup programs require cgroups-v2")
+		p.started.Store(true)
+		return
+	}
+	probe, err := newProbe()
+	if err != nil {
+		klog.Warningf("skip collecting pod network since the eBPF probe is unavailable, err: %v", err)
+		p.started.Store(true)
+		return
+	}
+	p.probe = probe
+	go func() {
+		wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, p.collectPodNetwork), p.collectInterval, stopCh)
+		if err := p.probe.Close(); err != nil {
+			klog.Warningf("failed to close pod network probe, err: %v", err)
+		}
+	}()
+}
+
+func (p *podNetworkCollector) Started() bool {
+	return p.started.Load()
+}
+
+func (p *podNetworkCollector) FilterPod(meta *statesinformer.PodMeta) (bool, string) {
+	return p.podFilter.FilterPod(meta)
+}
+
+func (p *podNetworkCollector) collectPodNetwork() {
+	klog.V(6).Info("start collectPodNetwork")
+	podMetas := p.statesInformer.GetAllPods()
+	podMetrics := make([]metriccache.MetricSample, 0)
+	activeCgroups := sets.NewString()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		uid := string(pod.UID)
+		if filtered, msg := p.FilterPod(meta); filtered {
+			klog.V(5).Infof("skip collect pod %s/%s, reason: %s", pod.Namespace, pod.Name, msg)
+			continue
+		}
+
+		// the programs are attached to the pod cgroup on the cgroups-v2 unified hierarchy
+		cgroupPath := filepath.Join(system.Conf.CgroupRootDir, meta.CgroupDir)
+		activeCgroups.Insert(cgroupPath)
+		if err := p.probe.Attach(cgroupPath); err != nil {
+			if pod.Status.Phase == corev1.PodRunning {
+				klog.V(4).Infof("attach network probe for pod %s/%s failed, err: %v", pod.Namespace, pod.Name, err)
+			}
+			continue
+		}
+		collectTime := timeNow()
+		stats, err := p.probe.Read(cgroupPath)
+		if err != nil {
+			klog.V(4).Infof("collect pod %s/%s, uid %s network stats failed, err: %v", pod.Namespace, pod.Name, uid, err)
+			continue
+		}
+		current := &networkSnapshot{stats: *stats, timestamp: collectTime}
+		if !pod.Spec.HostNetwork {
+			current.drops, current.dropsCollected = p.collectPodDrops(meta)
+		}
+		lastValue, ok := p.lastPodStat.Get(uid)
+		p.lastPodStat.Set(uid, current, gocache.DefaultExpiration)
+		if !ok {
+			klog.V(6).Infof("collect pod %s/%s, uid %s network stats first point", pod.Namespace, pod.Name, uid)
+			continue
+		}
+		metrics := generateNetworkSamples(current, lastValue.(*networkSnapshot),
+			func(networkType string, value float64) (metriccache.MetricSample, error) {
+				return metriccache.PodNetworkMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.PodNetwork(uid, networkType), collectTime, value)
+			})
+		podMetrics = append(podMetrics, metrics...)
+	} // end for podMeta
+
+	// release the programs of the pods deleted
+	for _, cgroupPath := range p.probe.AttachedCgroups() {
+		if activeCgroups.Has(cgroupPath) {
+			continue
+		}
+		if err := p.probe.Detach(cgroupPath); err != nil {
+			klog.V(4).Infof("detach network probe for cgroup %s failed, err: %v", cgroupPath, err)
+		}
+	}
+
+	appender := p.appendableDB.Appender()
+	if err := appender.Append(podMetrics); err != nil {
+		klog.Warningf("append pods network metrics failed, reason: %v", err)
+		return
+	}
+	if err := appender.Commit(); err != nil {
+		klog.Warningf("commit pods network metrics failed, reason: %v", err)
+		return
+	}
+	p.started.Store(true)
+	klog.V(5).Infof("collectPodNetwork finished, pod num %d, metric num %d", len(podMetas), len(podMetrics))
+}
+
+// collectPodDrops sums up the rx and tx drops of the interfaces in the network namespace of the pod, which is
+// entered by any process of the pod.
+func (p *podNetworkCollector) collectPodDrops(meta *statesinformer.PodMeta) (uint64, bool) {
+	pod := meta.Pod
+	pids, err := p.cgroupReader.ReadCPUProcs(meta.CgroupDir)
+	if err != nil || len(pids) == 0 {
+		klog.V(5).Infof("failed to get processes of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+		return 0, false
+	}
+	netDevStats, err := system.GetPIDNetDevStats(pids[0])
+	if err != nil {
+		klog.V(5).Infof("failed to get net dev stats of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+		return 0, false
+	}
+	var drops uint64
+	for name, stat := range netDevStats {
+		if name == loopbackInterface {
+			continue
+		}
+		drops += stat.RxDrops + stat.TxDrops
+	}
+	return drops, true
+}
+
+// generateNetworkSamples generates the ingress/egress bandwidth (bytes per second), the drops (packets per second)
+// and the tcp retransmissions (segments per second) samples with the network counters of two rounds.
+func generateNetworkSamples(current, last *networkSnapshot, sampleFunc func(networkType string, value float64) (metriccache.MetricSample, error)) []metriccache.MetricSample {
+	duration := current.timestamp.Sub(last.timestamp).Seconds()
+	if duration <= 0 {
+		return nil
+	}
+	cur, prev := current.stats, last.stats
+	if cur.IngressBytes < prev.IngressBytes || cur.EgressBytes < prev.EgressBytes ||
+		cur.TCPRetransmits < prev.TCPRetransmits { // counters are reset
+		return nil
+	}
+	values := []networkValue{
+		{networkType: metriccache.NetworkTypeIngressBPS, value: float64(cur.IngressBytes-prev.IngressBytes) / duration},
+		{networkType: metriccache.NetworkTypeEgressBPS, value: float64(cur.EgressBytes-prev.EgressBytes) / duration},
+		{networkType: metriccache.NetworkTypeTCPRetransmits, value: float64(cur.TCPRetransmits-prev.TCPRetransmits) / duration},
+	}
+	if current.dropsCollected && last.dropsCollected && current.drops >= last.drops {
+		values = append(values, networkValue{networkType: metriccache.NetworkTypeDrops, value: float64(current.drops-last.drops) / duration})
+	}
+
+	samples := make([]metriccache.MetricSample, 0, len(values))
+	for _, v := range values {
+		sample, err := sampleFunc(string(v.networkType), v.value)
+		if err != nil {
+			klog.V(4).Infof("failed to generate network sample, type %s, err: %s", v.networkType, err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podnetwork
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	gocache "github.com/patrickmn/go-cache"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netprobe"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+type fakeProbe struct {
+	stats    map[string]*netprobe.Stats
+	attached map[string]bool
+	closed   bool
+}
+
+func newFakeProbe(stats map[string]*netprobe.Stats) *fakeProbe {
+	return &fakeProbe{
+		stats:    stats,
+		attached: map[string]bool{},
+	}
+}
+
+func (f *fakeProbe) Attach(cgroupPath string) error {
+	if _, ok := f.stats[cgroupPath]; !ok {
+		return fmt.Errorf("cgroup %s not exist", cgroupPath)
+	}
+	f.attached[cgroupPath] = true
+	return nil
+}
+
+func (f *fakeProbe) Detach(cgroupPath string) error {
+	delete(f.attached, cgroupPath)
+	return nil
+}
+
+func (f *fakeProbe) AttachedCgroups() []string {
+	var cgroups []string
+	for cgroupPath := range f.attached {
+		cgroups = append(cgroups, cgroupPath)
+	}
+	return cgroups
+}
+
+func (f *fakeProbe) Read(cgroupPath string) (*netprobe.Stats, error) {
+	if !f.attached[cgroupPath] {
+		return nil, fmt.Errorf("cgroup %s is not attached", cgroupPath)
+	}
+	return f.stats[cgroupPath], nil
+}
+
+func (f *fakeProbe) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestNewPodNetworkCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.PodNetworkCollector), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func Test_podNetworkCollector_collectPodNetwork(t *testing.T) {
+	testPodMetaDir := "kubepods.slice/kubepods-podtest-pod-uid.slice"
+	testPodParentDir := "/kubepods.slice/kubepods-podtest-pod-uid.slice"
+	testPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "test",
+			UID:       "test-pod-uid",
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+		},
+	}
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	helper.WriteCgroupFileContents(testPodParentDir, system.CPUProcsV2, "100\n")
+	helper.WriteProcSubFileContents("100/"+system.ProcNetDevName, `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:     200       2    0    5    0     0          0         0      200       2    0    5    0     0       0          0
+  eth0: 1000 10 0 15 0 0 0 0 2000 20 0 15 0 0 0 0
+`)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              helper.TempDir,
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer func() {
+		metricCache.Close()
+	}()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		{
+			CgroupDir: testPodMetaDir,
+			Pod:       testPod,
+		},
+	}).Times(1)
+
+	collector := New(&framework.Options{
+		Config: &framework.Config{
+			CollectResUsedInterval: time.Second,
+		},
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*podNetworkCollector)
+	podCgroupPath := filepath.Join(system.Conf.CgroupRootDir, testPodMetaDir)
+	staleCgroupPath := filepath.Join(system.Conf.CgroupRootDir, "kubepods.slice/kubepods-podstale-pod-uid.slice")
+	probe := newFakeProbe(map[string]*netprobe.Stats{
+		podCgroupPath: {
+			IngressBytes:   10240,
+			EgressBytes:    20480,
+			TCPRetransmits: 10,
+		},
+		staleCgroupPath: {},
+	})
+	assert.NoError(t, probe.Attach(staleCgroupPath))
+	c.probe = probe
+	lastTime := time.Now().Add(-10 * time.Second)
+	c.lastPodStat.Set(string(testPod.UID), &networkSnapshot{
+		drops:          10,
+		dropsCollected: true,
+		timestamp:      lastTime,
+	}, gocache.DefaultExpiration)
+	assert.NotPanics(t, func() {
+		c.collectPodNetwork()
+	})
+	assert.True(t, c.Started())
+	assert.Equal(t, []string{podCgroupPath}, probe.AttachedCgroups())
+
+	querier, err := metricCache.Querier(lastTime, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	for networkType, expected := range map[metriccache.MetricPropertyValue]float64{
+		metriccache.NetworkTypeIngressBPS:     1024,
+		metriccache.NetworkTypeEgressBPS:      2048,
+		metriccache.NetworkTypeTCPRetransmits: 1,
+		metriccache.NetworkTypeDrops:          2,
+	} {
+		queryMeta, err := metriccache.PodNetworkMetric.BuildQueryMeta(
+			metriccache.MetricPropertiesFunc.PodNetwork(string(testPod.UID), string(networkType)))
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		value, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.InDelta(t, expected, value, 0.1, string(networkType))
+	}
+}
+
+func Test_generateNetworkSamples(t *testing.T) {
+	now := time.Now()
+	last := &networkSnapshot{
+		stats: netprobe.Stats{
+			IngressBytes:   1024,
+			EgressBytes:    1024,
+			TCPRetransmits: 1,
+		},
+		timestamp: now.Add(-2 * time.Second),
+	}
+	current := &networkSnapshot{
+		stats: netprobe.Stats{
+			IngressBytes:   1024 * 3,
+			EgressBytes:    1024 * 5,
+			TCPRetransmits: 5,
+		},
+		drops:          4,
+		dropsCollected: true,
+		timestamp:      now,
+	}
+	sampleFunc := func(networkType string, value float64) (metriccache.MetricSample, error) {
+		return metriccache.PodNetworkMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodNetwork("test-pod", networkType), now, value)
+	}
+	buildSample := func(networkType metriccache.MetricPropertyValue, value float64) metriccache.MetricSample {
+		s, err := sampleFunc(string(networkType), value)
+		assert.NoError(t, err)
+		return s
+	}
+	// the drops are not collected in the last round
+	assert.Equal(t, []metriccache.MetricSample{
+		buildSample(metriccache.NetworkTypeIngressBPS, 1024),
+		buildSample(metriccache.NetworkTypeEgressBPS, 2048),
+		buildSample(metriccache.NetworkTypeTCPRetransmits, 2),
+	}, generateNetworkSamples(current, last, sampleFunc))
+
+	last.dropsCollected = true
+	assert.Equal(t, []metriccache.MetricSample{
+		buildSample(metriccache.NetworkTypeIngressBPS, 1024),
+		buildSample(metriccache.NetworkTypeEgressBPS, 2048),
+		buildSample(metriccache.NetworkTypeTCPRetransmits, 2),
+		buildSample(metriccache.NetworkTypeDrops, 2),
+	}, generateNetworkSamples(current, last, sampleFunc))
+
+	// counters are reset
+	reset := &networkSnapshot{
+		stats:     netprobe.Stats{},
+		timestamp: now.Add(2 * time.Second),
+	}
+	assert.Nil(t, generateNetworkSamples(reset, current, sampleFunc))
+	assert.Nil(t, generateNetworkSamples(current, current, sampleFunc))
+}
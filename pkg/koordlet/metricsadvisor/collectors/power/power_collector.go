@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package power
+
+import (
+	"strconv"
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CollectorName = "PowerCollector"
+
+	PowerSourceRAPL      = "rapl"
+	PowerSourceAMDEnergy = "amd_energy"
+	PowerSourceHwmon     = "hwmon"
+
+	microUnitsPerUnit = 1e6
+)
+
+var (
+	timeNow = time.Now
+)
+
+// energySnapshot is the energy counters read at a time.
+type energySnapshot struct {
+	source   string
+	counters map[string]system.EnergyCounter
+	time     time.Time
+}
+
+// powerCollector collects the power consumption of the node and the cpu sockets. The power is calculated by the
+// delta of the cumulative energy counters of the RAPL or the amd_energy between two collections. If no energy
+// counter is available, the node power falls back to the sum of the hwmon power sensors.
+type powerCollector struct {
+	collectInterval time.Duration
+	started         *atomic.Bool
+	metricCache     metriccache.MetricCache
+
+	lastSnapshot *energySnapshot
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &powerCollector{
+		collectInterval: opt.Config.PowerCollectorInterval,
+		started:         atomic.NewBool(false),
+		metricCache:     opt.MetricCache,
+	}
+}
+
+func (p *powerCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.PowerCollector)
+}
+
+func (p *powerCollector) Setup(c *framework.Context) {}
+
+func (p *powerCollector) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, p.collectPower), p.collectInterval, stopCh)
+}
+
+func (p *powerCollector) Started() bool {
+	return p.started.Load()
+}
+
+func (p *powerCollector) collectPower() {
+	klog.V(6).Infof("start collectPower")
+	snapshot, err := readEnergySnapshot()
+	if err != nil {
+		klog.Errorf("failed to read energy counters, err: %v", err)
+		return
+	}
+
+	var samples []metriccache.MetricSample
+	if snapshot != nil {
+		samples = p.generateEnergySamples(snapshot)
+		p.lastSnapshot = snapshot
+	} else {
+		samples, err = generateHwmonSamples()
+		if err != nil {
+			klog.Errorf("failed to read hwmon power sensors, err: %v", err)
+			return
+		}
+	}
+
+	p.saveMetric(samples)
+	p.started.Store(true)
+	klog.V(5).Infof("collectPower finished at %s, sample num %d", timeNow(), len(samples))
+}
+
+// readEnergySnapshot reads the energy counters from the RAPL, or the amd_energy if the RAPL is not supported.
+// It returns nil if there is no energy counter on the node.
+func readEnergySnapshot() (*energySnapshot, error) {
+	source := PowerSourceRAPL
+	counters, err := system.GetRAPLEnergyCounters()
+	if err != nil {
+		return nil, err
+	}
+	if len(counters) == 0 {
+		source = PowerSourceAMDEnergy
+		if counters, err = system.GetAMDEnergyCounters(); err != nil {
+			return nil, err
+		}
+	}
+	if len(counters) == 0 {
+		return nil, nil
+	}
+	snapshot := &energySnapshot{
+		source:   source,
+		counters: make(map[string]system.EnergyCounter, len(counters)),
+		time:     timeNow(),
+	}
+	for _, counter := range counters {
+		snapshot.counters[counter.Key()] = counter
+	}
+	return snapshot, nil
+}
+
+func (p *powerCollector) generateEnergySamples(snapshot *energySnapshot) []metriccache.MetricSample {
+	last := p.lastSnapshot
+	if last == nil || last.source != snapshot.source {
+		klog.V(5).Infof("skip calculating power for the first collection of source %s", snapshot.source)
+		return nil
+	}
+	elapsed := snapshot.time.Sub(last.time).Seconds()
+	if elapsed <= 0 {
+		return nil
+	}
+
+	var samples []metriccache.MetricSample
+	var psysPower, socketPower float64
+	hasPSys := false
+	for key, counter := range snapshot.counters {
+		lastCounter, ok := last.counters[key]
+		if !ok {
+			continue
+		}
+		delta, ok := energyDelta(lastCounter, counter)
+		if !ok {
+			klog.V(5).Infof("skip power domain %s since the energy counter wraps around with unknown range", key)
+			continue
+		}
+		power := float64(delta) / microUnitsPerUnit / elapsed
+		switch {
+		case counter.Domain == system.PowerDomainPSys:
+			hasPSys = true
+			psysPower += power
+		case counter.Domain == system.PowerDomainPackage, counter.Domain == system.PowerDomainDRAM:
+			// other subzones like core and uncore are parts of the package
+			socketPower += power
+		}
+
+		socketID := strconv.Itoa(counter.SocketID)
+		sample, err := metriccache.NodeSocketPowerMetric.GenerateSample(
+			metriccache.MetricPropertiesFunc.SocketPower(socketID, counter.Domain), snapshot.time, power)
+		if err != nil {
+			klog.Warningf("generate socket %s power %s sample failed, err: %v", socketID, counter.Domain, err)
+			continue
+		}
+		samples = append(samples, sample)
+		metrics.RecordNodeSocketPower(counter.SocketID, counter.Domain, power)
+	}
+
+	// the psys domain covers the whole platform (SoC), which is preferred when supported
+	nodePower := socketPower
+	if hasPSys {
+		nodePower = psysPower
+	}
+	return append(samples, generateNodePowerSample(snapshot.source, snapshot.time, nodePower)...)
+}
+
+// energyDelta returns the energy consumed between two counters, and false if it wraps around with unknown range.
+func energyDelta(last, cur system.EnergyCounter) (uint64, bool) {
+	if cur.EnergyUJ >= last.EnergyUJ {
+		return cur.EnergyUJ - last.EnergyUJ, true
+	}
+	if cur.MaxEnergyRangeUJ <= 0 || last.EnergyUJ > cur.MaxEnergyRangeUJ {
+		return 0, false
+	}
+	return cur.MaxEnergyRangeUJ - last.EnergyUJ + cur.EnergyUJ, true
+}
+
+func generateHwmonSamples() ([]metriccache.MetricSample, error) {
+	sensors, err := system.GetHwmonPowerSensors()
+	if err != nil {
+		return nil, err
+	}
+	if len(sensors) == 0 {
+		klog.V(5).Infof("skip collecting power since no energy counter or power sensor is found")
+		return nil, nil
+	}
+	var nodePower float64
+	for _, sensor := range sensors {
+		nodePower += float64(sensor.PowerUW) / microUnitsPerUnit
+	}
+	return generateNodePowerSample(PowerSourceHwmon, timeNow(), nodePower), nil
+}
+
+func generateNodePowerSample(source string, collectTime time.Time, nodePower float64) []metriccache.MetricSample {
+	sample, err := metriccache.NodePowerMetric.GenerateSample(nil, collectTime, nodePower)
+	if err != nil {
+		klog.Warningf("generate node power sample failed, err: %v", err)
+		return nil
+	}
+	metrics.ResetNodePower()
+	metrics.RecordNodePower(source, nodePower)
+	return []metriccache.MetricSample{sample}
+}
+
+func (p *powerCollector) saveMetric(samples []metriccache.MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	appender := p.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append power metrics error")
+		return err
+	}
+
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit power metrics failed")
+		return err
+	}
+
+	return nil
+}
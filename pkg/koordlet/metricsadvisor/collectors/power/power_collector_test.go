@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package power
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestNewPowerCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config: framework.NewDefaultConfig(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.PowerCollector), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func Test_energyDelta(t *testing.T) {
+	tests := []struct {
+		name      string
+		last      system.EnergyCounter
+		cur       system.EnergyCounter
+		wantDelta uint64
+		wantOK    bool
+	}{
+		{
+			name:      "counter increases",
+			last:      system.EnergyCounter{EnergyUJ: 100},
+			cur:       system.EnergyCounter{EnergyUJ: 300},
+			wantDelta: 200,
+			wantOK:    true,
+		},
+		{
+			name:      "counter wraps around",
+			last:      system.EnergyCounter{EnergyUJ: 900, MaxEnergyRangeUJ: 1000},
+			cur:       system.EnergyCounter{EnergyUJ: 100, MaxEnergyRangeUJ: 1000},
+			wantDelta: 200,
+			wantOK:    true,
+		},
+		{
+			name:   "counter wraps around with unknown range",
+			last:   system.EnergyCounter{EnergyUJ: 900},
+			cur:    system.EnergyCounter{EnergyUJ: 100},
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotDelta, gotOK := energyDelta(tt.last, tt.cur)
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantDelta, gotDelta)
+		})
+	}
+}
+
+func TestPowerCollector_collectPower(t *testing.T) {
+	writeRAPLZone := func(helper *system.FileTestUtil, zone, name, energy string) {
+		helper.WriteFileContents("class/powercap/"+zone+"/name", name)
+		helper.WriteFileContents("class/powercap/"+zone+"/energy_uj", energy)
+		helper.WriteFileContents("class/powercap/"+zone+"/max_energy_range_uj", "262143328850")
+	}
+	tests := []struct {
+		name          string
+		prepareFirst  func(helper *system.FileTestUtil)
+		prepareSecond func(helper *system.FileTestUtil)
+		wantNodePower float64
+		wantSockets   map[[2]string]float64
+	}{
+		{
+			name: "rapl packages and dram",
+			prepareFirst: func(helper *system.FileTestUtil) {
+				writeRAPLZone(helper, "intel-rapl:0", "package-0", "1000000000")
+				writeRAPLZone(helper, "intel-rapl:0:0", "dram", "100000000")
+				writeRAPLZone(helper, "intel-rapl:1", "package-1", "262000000000")
+			},
+			prepareSecond: func(helper *system.FileTestUtil) {
+				// the counter of package-1 wraps around
+				writeRAPLZone(helper, "intel-rapl:0", "package-0", "2000000000")
+				writeRAPLZone(helper, "intel-rapl:0:0", "dram", "200000000")
+				writeRAPLZone(helper, "intel-rapl:1", "package-1", "856671150")
+			},
+			wantNodePower: 210,
+			wantSockets: map[[2]string]float64{
+				{"0", system.PowerDomainPackage}: 100,
+				{"0", system.PowerDomainDRAM}:    10,
+				{"1", system.PowerDomainPackage}: 100,
+			},
+		},
+		{
+			name: "rapl psys is preferred",
+			prepareFirst: func(helper *system.FileTestUtil) {
+				writeRAPLZone(helper, "intel-rapl:0", "package-0", "1000000000")
+				writeRAPLZone(helper, "intel-rapl:1", "psys", "1000000000")
+			},
+			prepareSecond: func(helper *system.FileTestUtil) {
+				writeRAPLZone(helper, "intel-rapl:0", "package-0", "2000000000")
+				writeRAPLZone(helper, "intel-rapl:1", "psys", "4000000000")
+			},
+			wantNodePower: 300,
+			wantSockets: map[[2]string]float64{
+				{"0", system.PowerDomainPackage}: 100,
+				{"-1", system.PowerDomainPSys}:   300,
+			},
+		},
+		{
+			name: "amd energy",
+			prepareFirst: func(helper *system.FileTestUtil) {
+				helper.WriteFileContents("class/hwmon/hwmon0/name", "amd_energy")
+				helper.WriteFileContents("class/hwmon/hwmon0/energy1_label", "Esocket0")
+				helper.WriteFileContents("class/hwmon/hwmon0/energy1_input", "1000000000")
+			},
+			prepareSecond: func(helper *system.FileTestUtil) {
+				helper.WriteFileContents("class/hwmon/hwmon0/energy1_input", "3000000000")
+			},
+			wantNodePower: 200,
+			wantSockets: map[[2]string]float64{
+				{"0", system.PowerDomainPackage}: 200,
+			},
+		},
+		{
+			name: "hwmon power sensors",
+			prepareFirst: func(helper *system.FileTestUtil) {
+				helper.WriteFileContents("class/hwmon/hwmon0/name", "power_meter")
+				helper.WriteFileContents("class/hwmon/hwmon0/power1_input", "100000000")
+			},
+			prepareSecond: func(helper *system.FileTestUtil) {
+				helper.WriteFileContents("class/hwmon/hwmon0/power1_input", "250000000")
+			},
+			wantNodePower: 250,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+				TSDBPath:              t.TempDir(),
+				TSDBEnablePromMetrics: false,
+			})
+			assert.NoError(t, err)
+			defer metricCache.Close()
+
+			testNow := time.Now()
+			timeNow = func() time.Time {
+				return testNow
+			}
+			defer func() {
+				timeNow = time.Now
+			}()
+
+			c := New(&framework.Options{
+				Config:      framework.NewDefaultConfig(),
+				MetricCache: metricCache,
+			}).(*powerCollector)
+			tt.prepareFirst(helper)
+			c.collectPower()
+			assert.True(t, c.Started())
+
+			testNow = testNow.Add(10 * time.Second)
+			tt.prepareSecond(helper)
+			c.collectPower()
+
+			querier, err := metricCache.Querier(testNow.Add(-time.Second), testNow.Add(time.Second))
+			assert.NoError(t, err)
+			queryMeta, err := metriccache.NodePowerMetric.BuildQueryMeta(nil)
+			assert.NoError(t, err)
+			result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+			assert.NoError(t, querier.Query(queryMeta, nil, result))
+			got, err := result.Value(metriccache.AggregationTypeLast)
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.wantNodePower, got, 1e-6)
+
+			for socket, wantPower := range tt.wantSockets {
+				queryMeta, err := metriccache.NodeSocketPowerMetric.BuildQueryMeta(
+					metriccache.MetricPropertiesFunc.SocketPower(socket[0], socket[1]))
+				assert.NoError(t, err)
+				result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+				assert.NoError(t, querier.Query(queryMeta, nil, result))
+				got, err := result.Value(metriccache.AggregationTypeLast)
+				assert.NoError(t, err)
+				assert.InDelta(t, wantPower, got, 1e-6, socket)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psi
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	tools "github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	CollectorName = "PSICollector"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// avgSampleFunc generates the sample of a PSI average value, e.g. avg10 of the cpu some pressure.
+type avgSampleFunc func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error)
+
+// totalSampleFunc generates the sample of a PSI total stall time in microseconds.
+type totalSampleFunc func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error)
+
+type psiCollector struct {
+	collectInterval time.Duration
+
+	started        *atomic.Bool
+	statesInformer statesinformer.StatesInformer
+	metricCache    metriccache.MetricCache
+	cgroupReader   resourceexecutor.CgroupReader
+
+	nodePSISupported   bool
+	cgroupPSISupported bool
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &psiCollector{
+		collectInterval: opt.Config.PSICollectorInterval,
+		started:         atomic.NewBool(false),
+		statesInformer:  opt.StatesInformer,
+		metricCache:     opt.MetricCache,
+		cgroupReader:    opt.CgroupReader,
+	}
+}
+
+func (p *psiCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.PSICollector)
+}
+
+func (p *psiCollector) Setup(c *framework.Context) {}
+
+func (p *psiCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, p.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	p.nodePSISupported = system.IsNodePSISupported()
+	p.cgroupPSISupported = isCgroupPSISupported()
+	if !p.nodePSISupported && !p.cgroupPSISupported {
+		klog.V(4).Infof("skip collecting psi since the system does not support psi, please check the pressure files exist and are readable")
+		p.started.Store(true)
+		return
+	}
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, p.collectPSI), p.collectInterval, stopCh)
+}
+
+func (p *psiCollector) Started() bool {
+	return p.started.Load()
+}
+
+func (p *psiCollector) collectPSI() {
+	if p.nodePSISupported {
+		p.collectNodePSI()
+	}
+	if p.cgroupPSISupported {
+		p.collectContainerPSI()
+		p.collectPodPSI()
+	}
+	p.started.Store(true)
+}
+
+func (p *psiCollector) collectNodePSI() {
+	klog.V(6).Infof("start collectNodePSI")
+	nodePSI, err := system.GetPSIByResource(system.GetNodePSIPath())
+	collectTime := timeNow()
+	if err != nil {
+		klog.Errorf("collect node psi err: %v", err)
+		return
+	}
+
+	psiMetrics, err := generatePSISamples(nodePSI,
+		func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.NodePSIMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSI(psiResource, psiPrecision, psiDegree), collectTime, value)
+		},
+		func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.NodePSITotalMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSITotal(psiResource, psiDegree), collectTime, value)
+		})
+	if err != nil {
+		klog.Warningf("failed to collect node PSI, err: %s", err)
+		return
+	}
+
+	metrics.ResetNodePSI()
+	metrics.RecordNodePSI(nodePSI)
+
+	// save node psi metrics to tsdb
+	p.saveMetric(psiMetrics)
+	klog.V(5).Infof("collectNodePSI finished at %s", timeNow())
+}
+
+func (p *psiCollector) collectContainerPSI() {
+	klog.V(6).Infof("start collectContainerPSI")
+	timeWindow := time.Now()
+	containerStatusesMap := map[*corev1.ContainerStatus]*statesinformer.PodMeta{}
+	podMetas := p.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		for i := range pod.Status.ContainerStatuses {
+			containerStat := &pod.Status.ContainerStatuses[i]
+			containerStatusesMap[containerStat] = meta
+		}
+	}
+
+	metrics.ResetContainerPSI()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	wg.Add(len(containerStatusesMap))
+	psiMetrics := make([]metriccache.MetricSample, 0)
+	for containerStatus, podMeta := range containerStatusesMap {
+		pod := podMeta.Pod
+		cgroupDir := podMeta.CgroupDir
+		go func(parentDir string, status *corev1.ContainerStatus, pod *corev1.Pod) {
+			defer wg.Done()
+			metrics := p.collectSingleContainerPSI(parentDir, status, pod)
+			mutex.Lock()
+			psiMetrics = append(psiMetrics, metrics...)
+			mutex.Unlock()
+		}(cgroupDir, containerStatus, pod)
+	}
+	wg.Wait()
+
+	// save container's psi metrics to tsdb
+	p.saveMetric(psiMetrics)
+
+	klog.V(5).Infof("collectContainerPSI for time window %s finished at %s, container num %d",
+		timeWindow, time.Now(), len(containerStatusesMap))
+}
+
+func (p *psiCollector) collectSingleContainerPSI(podParentCgroupDir string, containerStatus *corev1.ContainerStatus, pod *corev1.Pod) []metriccache.MetricSample {
+	psiMetrics := make([]metriccache.MetricSample, 0)
+	collectTime := timeNow()
+	containerPath, err := util.GetContainerCgroupParentDir(podParentCgroupDir, containerStatus)
+	if err != nil {
+		klog.Errorf("failed to get container path for container %v/%v/%v cgroup path failed, error: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+		return psiMetrics
+	}
+	containerPSI, err := p.cgroupReader.ReadPSI(containerPath)
+	if err != nil {
+		klog.Errorf("collect container %s psi err: %v", containerStatus.Name, err)
+		return psiMetrics
+	}
+
+	podUID := string(pod.GetUID())
+	samples, err := generatePSISamples(containerPSI,
+		func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.ContainerPSIMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.ContainerPSI(podUID, containerStatus.ContainerID, psiResource, psiPrecision, psiDegree), collectTime, value)
+		},
+		func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.ContainerPSITotalMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.ContainerPSITotal(podUID, containerStatus.ContainerID, psiResource, psiDegree), collectTime, value)
+		})
+	if err != nil {
+		klog.Warningf("failed to collect Container %s/%s/%s PSI, err: %s",
+			pod.GetNamespace(), pod.GetName(), containerStatus.Name, err)
+		return psiMetrics
+	}
+	cpuFullSupported, err := metriccache.ContainerPSICPUFullSupportedMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.PSICPUFullSupported(podUID, containerStatus.ContainerID), collectTime, tools.BoolToFloat64(containerPSI.CPU.FullSupported))
+	if err != nil {
+		klog.Warningf("failed to collect Container %s/%s/%s PSI, cpuFullSupported err: %s",
+			pod.GetNamespace(), pod.GetName(), containerStatus.Name, err)
+		return psiMetrics
+	}
+	psiMetrics = append(psiMetrics, samples...)
+	psiMetrics = append(psiMetrics, cpuFullSupported)
+
+	metrics.RecordContainerPSI(containerStatus, pod, containerPSI)
+
+	return psiMetrics
+}
+
+func (p *psiCollector) collectPodPSI() {
+	klog.V(6).Infof("start collectPodPSI")
+	timeWindow := time.Now()
+	podMetas := p.statesInformer.GetAllPods()
+	metrics.ResetPodPSI()
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	wg.Add(len(podMetas))
+	psiMetrics := make([]metriccache.MetricSample, 0)
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		podCgroupDir := meta.CgroupDir
+		go func(pod *corev1.Pod, podCgroupDir string) {
+			defer wg.Done()
+			metrics := p.collectSinglePodPSI(pod, podCgroupDir)
+			mutex.Lock()
+			psiMetrics = append(psiMetrics, metrics...)
+			mutex.Unlock()
+		}(pod, podCgroupDir)
+	}
+	wg.Wait()
+
+	// save pod psi metrics to tsdb
+	p.saveMetric(psiMetrics)
+
+	klog.V(5).Infof("collectPodPSI for time window %s finished at %s, pod num %d",
+		timeWindow, time.Now(), len(podMetas))
+}
+
+func (p *psiCollector) collectSinglePodPSI(pod *corev1.Pod, podCgroupDir string) []metriccache.MetricSample {
+	psiMetrics := make([]metriccache.MetricSample, 0)
+	podPSI, err := p.cgroupReader.ReadPSI(podCgroupDir)
+	collectTime := timeNow()
+	if err != nil {
+		klog.Errorf("collect pod %v/%v psi err: %v", pod.Namespace, pod.Name, err)
+		return psiMetrics
+	}
+
+	podUID := string(pod.GetUID())
+	samples, err := generatePSISamples(podPSI,
+		func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.PodPSIMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.PodPSI(podUID, psiResource, psiPrecision, psiDegree), collectTime, value)
+		},
+		func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.PodPSITotalMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.PodPSITotal(podUID, psiResource, psiDegree), collectTime, value)
+		})
+	if err != nil {
+		klog.Warningf("failed to collect pod %s/%s PSI, err: %s", pod.GetNamespace(), pod.GetName(), err)
+		return psiMetrics
+	}
+	cpuFullSupported, err := metriccache.PodPSICPUFullSupportedMetric.GenerateSample(
+		metriccache.MetricPropertiesFunc.Pod(podUID), collectTime, tools.BoolToFloat64(podPSI.CPU.FullSupported))
+	if err != nil {
+		klog.Warningf("failed to collect pod %s/%s PSI, cpuFullSupported err: %s", pod.GetNamespace(), pod.GetName(), err)
+		return psiMetrics
+	}
+	psiMetrics = append(psiMetrics, samples...)
+	psiMetrics = append(psiMetrics, cpuFullSupported)
+
+	metrics.RecordPodPSI(pod, podPSI)
+
+	return psiMetrics
+}
+
+func (p *psiCollector) saveMetric(samples []metriccache.MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	appender := p.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append psi metrics error")
+		return err
+	}
+
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit psi metrics failed")
+		return err
+	}
+
+	return nil
+}
+
+// generatePSISamples generates the avg10, avg60 and total samples of the cpu, memory and io pressure.
+func generatePSISamples(psi *system.PSIByResource, avgFn avgSampleFunc, totalFn totalSampleFunc) ([]metriccache.MetricSample, error) {
+	resources := []struct {
+		name  metriccache.MetricPropertyValue
+		stats system.PSIStats
+	}{
+		{name: metriccache.PSIResourceCPU, stats: psi.CPU},
+		{name: metriccache.PSIResourceMem, stats: psi.Mem},
+		{name: metriccache.PSIResourceIO, stats: psi.IO},
+	}
+	samples := make([]metriccache.MetricSample, 0, len(resources)*6)
+	for _, r := range resources {
+		degrees := []struct {
+			name metriccache.MetricPropertyValue
+			line *system.PSILine
+		}{
+			{name: metriccache.PSIDegreeSome, line: r.stats.Some},
+			{name: metriccache.PSIDegreeFull, line: r.stats.Full},
+		}
+		for _, d := range degrees {
+			if d.line == nil {
+				continue
+			}
+			avg10, err := avgFn(string(r.name), string(metriccache.PSIPrecision10), string(d.name), d.line.Avg10)
+			if err != nil {
+				return nil, err
+			}
+			avg60, err := avgFn(string(r.name), string(metriccache.PSIPrecision60), string(d.name), d.line.Avg60)
+			if err != nil {
+				return nil, err
+			}
+			total, err := totalFn(string(r.name), string(d.name), float64(d.line.Total))
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, avg10, avg60, total)
+		}
+	}
+	return samples, nil
+}
+
+func isCgroupPSISupported() bool {
+	// CgroupV1 psi collector support only on anolis os currently
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV1 {
+		cpuPressureCheck, _ := system.CPUAcctCPUPressure.IsSupported("")
+		memPressureCheck, _ := system.CPUAcctMemoryPressure.IsSupported("")
+		ioPressureCheck, _ := system.CPUAcctIOPressure.IsSupported("")
+		return cpuPressureCheck && memPressureCheck && ioPressureCheck
+	}
+	return true
+}
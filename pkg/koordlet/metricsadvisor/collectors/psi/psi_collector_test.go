@@ -0,0 +1,296 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psi
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestNewPSICollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.PSICollector), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func Test_collectNodePSI(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents(system.ProcPressureCPUName, "some avg10=1.00 avg60=2.00 avg300=3.00 total=100")
+	helper.WriteProcSubFileContents(system.ProcPressureMemoryName, "some avg10=4.00 avg60=5.00 avg300=6.00 total=200\nfull avg10=7.00 avg60=8.00 avg300=9.00 total=300")
+	helper.WriteProcSubFileContents(system.ProcPressureIOName, FullCorrectPSIContents)
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	c := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		MetricCache:  metricCache,
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	}).(*psiCollector)
+	c.nodePSISupported = system.IsNodePSISupported()
+	assert.True(t, c.nodePSISupported)
+	c.collectPSI()
+	assert.True(t, c.Started())
+
+	tests := []struct {
+		resource  metriccache.MetricResource
+		property  map[metriccache.MetricProperty]string
+		wantValue float64
+	}{
+		{
+			resource:  metriccache.NodePSIMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSI(string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)),
+			wantValue: 1,
+		},
+		{
+			resource:  metriccache.NodePSIMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSI(string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision60), string(metriccache.PSIDegreeSome)),
+			wantValue: 2,
+		},
+		{
+			resource:  metriccache.NodePSITotalMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSITotal(string(metriccache.PSIResourceCPU), string(metriccache.PSIDegreeSome)),
+			wantValue: 100,
+		},
+		{
+			resource:  metriccache.NodePSIMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSI(string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)),
+			wantValue: 7,
+		},
+		{
+			resource:  metriccache.NodePSIMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSI(string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision60), string(metriccache.PSIDegreeFull)),
+			wantValue: 8,
+		},
+		{
+			resource:  metriccache.NodePSITotalMetric,
+			property:  metriccache.MetricPropertiesFunc.NodePSITotal(string(metriccache.PSIResourceMem), string(metriccache.PSIDegreeFull)),
+			wantValue: 300,
+		},
+	}
+	start, end := testNow.Add(-5*time.Second), testNow.Add(5*time.Second)
+	querier, err := metricCache.Querier(start, end)
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		queryMeta, err := tt.resource.BuildQueryMeta(tt.property)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantValue, got, tt.property)
+	}
+}
+
+func Test_generatePSISamples(t *testing.T) {
+	psi := &system.PSIByResource{
+		CPU: system.PSIStats{
+			Some: &system.PSILine{Avg10: 1, Avg60: 2, Total: 10},
+			Full: &system.PSILine{},
+		},
+		Mem: system.PSIStats{
+			Some:          &system.PSILine{Avg10: 3, Avg60: 4, Total: 20},
+			Full:          &system.PSILine{Avg10: 5, Avg60: 6, Total: 30},
+			FullSupported: true,
+		},
+		IO: system.PSIStats{
+			Some: &system.PSILine{},
+		},
+	}
+	now := time.Now()
+	samples, err := generatePSISamples(psi,
+		func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.NodePSIMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSI(psiResource, psiPrecision, psiDegree), now, value)
+		},
+		func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.NodePSITotalMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSITotal(psiResource, psiDegree), now, value)
+		})
+	assert.NoError(t, err)
+	// cpu some/full, mem some/full, io some
+	assert.Equal(t, 15, len(samples))
+
+	_, err = generatePSISamples(psi,
+		func(psiResource, psiPrecision, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			// missing the pod uid
+			return metriccache.PodPSIMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSI(psiResource, psiPrecision, psiDegree), now, value)
+		},
+		func(psiResource, psiDegree string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.NodePSITotalMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.NodePSITotal(psiResource, psiDegree), now, value)
+		})
+	assert.Error(t, err)
+}
+
+func mockInterferencePodMeta(cgroupDir string) *statesinformer.PodMeta {
+	return &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						ContainerID: "containerd://test01",
+					},
+				},
+			},
+		},
+		CgroupDir: cgroupDir,
+	}
+}
+
+const (
+	FullCorrectPSIContents = "some avg10=0.00 avg60=0.00 avg300=0.00 total=0\nfull avg10=0.00 avg60=0.00 avg300=0.00 total=0"
+)
+
+func Test_collectContainerPSI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dir := t.TempDir()
+	system.Conf.CgroupRootDir = dir
+
+	cgroupDir := t.TempDir()
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	testPodMeta := mockInterferencePodMeta(t.TempDir())
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodMeta}).AnyTimes()
+
+	paths := getPodCgroupCPUAcctPSIPath(cgroupDir)
+	errCreateCPU := createTestPSIFile(paths.CPU, FullCorrectPSIContents)
+	if errCreateCPU != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateCPU)
+	}
+	errCreateMem := createTestPSIFile(paths.Mem, FullCorrectPSIContents)
+	if errCreateMem != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateMem)
+	}
+	errCreateIO := createTestPSIFile(paths.IO, FullCorrectPSIContents)
+	if errCreateIO != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateIO)
+	}
+
+	collector := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		MetricCache:    mockMetricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*psiCollector)
+	assert.NotPanics(t, func() {
+		c.collectContainerPSI()
+	})
+}
+
+func Test_collectPodPSI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	dir := t.TempDir()
+	system.Conf.CgroupRootDir = dir
+
+	cgroupDir := t.TempDir()
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	testPodMeta := mockInterferencePodMeta(t.TempDir())
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testPodMeta}).AnyTimes()
+
+	paths := getPodCgroupCPUAcctPSIPath(cgroupDir)
+	errCreateCPU := createTestPSIFile(paths.CPU, FullCorrectPSIContents)
+	if errCreateCPU != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateCPU)
+	}
+	errCreateMem := createTestPSIFile(paths.Mem, FullCorrectPSIContents)
+	if errCreateMem != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateMem)
+	}
+	errCreateIO := createTestPSIFile(paths.IO, FullCorrectPSIContents)
+	if errCreateIO != nil {
+		t.Fatalf("got error when create psi files: %v", errCreateIO)
+	}
+
+	collector := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		MetricCache:    mockMetricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	})
+	c := collector.(*psiCollector)
+	assert.NotPanics(t, func() {
+		c.collectPodPSI()
+	})
+}
+
+func createTestPSIFile(filePath, contents string) error {
+	dir, _ := path.Split(filePath)
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+	if _, err := os.Create(filePath); err != nil {
+		return err
+	}
+	err := os.WriteFile(filePath, []byte(contents), 0644)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// @podParentDir kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/
+//
+//	@return {
+//	   CPU: /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/cpu.pressure
+//	   Mem: /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/memory.pressure
+//	   IO:  /sys/fs/cgroup/cpu/kubepods.slice/kubepods-burstable.slice/kubepods-pod7712555c_ce62_454a_9e18_9ff0217b8941.slice/io.pressure
+//	 }
+func getPodCgroupCPUAcctPSIPath(podParentDir string) system.PSIPath {
+	return system.PSIPath{
+		CPU: system.GetCgroupFilePath(podParentDir, system.CPUAcctCPUPressure),
+		Mem: system.GetCgroupFilePath(podParentDir, system.CPUAcctMemoryPressure),
+		IO:  system.GetCgroupFilePath(podParentDir, system.CPUAcctIOPressure),
+	}
+}
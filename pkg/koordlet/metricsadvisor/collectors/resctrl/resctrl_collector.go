@@ -20,7 +20,9 @@ import (
 	"time"
 
 	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
@@ -30,11 +32,16 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 const (
 	CollectorName = "resctrlCollector"
+
+	// ReasonResctrlRMIDExhausted is the reason of the node event when the resctrl RMIDs run out.
+	ReasonResctrlRMIDExhausted = "ResctrlRMIDExhausted"
 )
 
 type resctrlCollector struct {
@@ -44,6 +51,18 @@ type resctrlCollector struct {
 	statesInformer       statesinformer.StatesInformer
 	resctrlReader        resourceexecutor.ResctrlReader
 	resctrlCollectorGate bool
+
+	enablePodMonGroup bool
+	monGroupManager   resourceexecutor.ResctrlMonGroupManager
+	cgroupReader      resourceexecutor.CgroupReader
+	eventRecorder     record.EventRecorder
+	// rmidExhausted is whether the RMIDs were exhausted in the last collection
+	rmidExhausted bool
+
+	// lastMBMTotalBytes is the MBM total bytes of each QoS group in the last collection, which is used to calculate
+	// the memory bandwidth
+	lastMBMTotalBytes map[string]uint64
+	lastCollectTime   time.Time
 }
 
 func New(opt *framework.Options) framework.Collector {
@@ -54,19 +73,23 @@ func New(opt *framework.Options) framework.Collector {
 		resctrlReader:        resourceexecutor.NewResctrlReader(),
 		resctrlCollectorGate: opt.Config.EnableResctrlCollector,
 		started:              atomic.NewBool(false),
+		enablePodMonGroup:    opt.Config.EnablePodResctrlMonGroup,
+		monGroupManager:      resourceexecutor.NewResctrlMonGroupManager(),
+		cgroupReader:         resourceexecutor.NewCgroupReader(),
+		eventRecorder:        opt.EventRecorder,
 	}
 }
 
 // 1. config enable resctrl collector
 // 2. cmdline, os, cpuid enable resctrl collector
-// 3. check CPU vendor(Intel&AMD)
+// 3. check CPU vendor(Intel&AMD&ARM MPAM, the registered resctrl reader or the probed resctrl monitor)
 // 4. check resctrl collector feature gate
 func (r *resctrlCollector) Enabled() bool {
 	isResctrlEnabled, _ := system.IsSupportResctrl()
 	isResctrlCollectorEnabled, _ := system.IsSupportResctrlCollector()
 	return r.resctrlCollectorGate &&
 		isResctrlEnabled && isResctrlCollectorEnabled &&
-		system.IsVendorSupportResctrl() &&
+		resourceexecutor.IsResctrlReaderAvailable() &&
 		features.DefaultKoordletFeatureGate.Enabled(features.ResctrlCollector)
 }
 
@@ -77,7 +100,7 @@ func (r *resctrlCollector) Started() bool {
 }
 
 func (r *resctrlCollector) Run(stopCh <-chan struct{}) {
-	go wait.Until(r.collectQoSResctrlStat, r.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, r.collectQoSResctrlStat), r.collectInterval, stopCh)
 }
 
 func (r *resctrlCollector) collectQoSResctrlStat() {
@@ -90,6 +113,8 @@ func (r *resctrlCollector) collectQoSResctrlStat() {
 	klog.V(6).Info("start collect QoS resctrl Stat")
 	resctrlMetrics := make([]metriccache.MetricSample, 0)
 	collectTime := time.Now()
+	isL2MonAvailable, _ := system.IsResctrlL2MonAvailableByResctrlInfo()
+	mbmTotalBytes := map[string]uint64{}
 	for _, qos := range []string{
 		resctrl.LSRResctrlGroup,
 		resctrl.LSResctrlGroup,
@@ -112,7 +137,9 @@ func (r *resctrlCollector) collectQoSResctrlStat() {
 			klog.V(4).Infof("collect QoS %s resctrl mb data error: %v", qos, err)
 			continue
 		}
+		var totalBytes uint64
 		for cacheId, value := range mbMap {
+			totalBytes += value[system.ResctrlMBMTotalName]
 			for mbType, mbValue := range value {
 				metrics.RecordResctrlMB(int(cacheId), qos, mbType, mbValue)
 				mbSample, err := metriccache.ResctrlMBMetric.GenerateSample(metriccache.MetricPropertiesFunc.ResctrlMB(qos, int(cacheId), mbType), collectTime, float64(mbValue))
@@ -122,15 +149,159 @@ func (r *resctrlCollector) collectQoSResctrlStat() {
 				resctrlMetrics = append(resctrlMetrics, mbSample)
 			}
 		}
+		mbmTotalBytes[qos] = totalBytes
+		if !isL2MonAvailable {
+			continue
+		}
+		l2Map, err := r.resctrlReader.ReadResctrlL2Stat(qos)
+		if err != nil {
+			klog.V(4).Infof("collect QoS %s resctrl l2 data error: %v", qos, err)
+			continue
+		}
+		for cacheId, value := range l2Map {
+			metrics.RecordResctrlL2(int(cacheId), qos, value)
+		}
+	}
+
+	for qos, bandwidth := range r.calculateMemoryBandwidth(mbmTotalBytes, collectTime) {
+		bandwidthSample, err := metriccache.ResctrlMemoryBandwidthMetric.GenerateSample(metriccache.MetricPropertiesFunc.QoS(qos), collectTime, bandwidth)
+		if err != nil {
+			klog.V(4).Infof("generate QoS %s resctrl memory bandwidth sample error: %v", qos, err)
+			continue
+		}
+		resctrlMetrics = append(resctrlMetrics, bandwidthSample)
 	}
 
 	// save QoS resctrl data to tsdb
 	r.saveMetric(resctrlMetrics)
 
+	if r.enablePodMonGroup {
+		r.collectPodResctrlStat()
+	}
+
 	r.started.Store(true)
 	klog.V(6).Infof("collect resctrl data at %s", time.Now())
 }
 
+// collectPodResctrlStat syncs the mon groups of the running pods and collects the pod-level resctrl stats.
+// The mon groups of the deleted pods are removed during the sync.
+func (r *resctrlCollector) collectPodResctrlStat() {
+	pods := map[string]*corev1.Pod{}
+	podTasks := map[string][]int32{}
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if pod == nil || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		taskIds := r.getPodTaskIds(podMeta)
+		if len(taskIds) <= 0 {
+			continue
+		}
+		pods[string(pod.UID)] = pod
+		podTasks[string(pod.UID)] = taskIds
+	}
+
+	podMonGroups := r.monGroupManager.SyncPodMonGroups(podTasks)
+	r.recordRMIDStatus(r.monGroupManager.GetRMIDStatus())
+	metrics.ResetPodResctrl()
+	for podUID, monGroup := range podMonGroups {
+		pod, ok := pods[podUID]
+		if !ok {
+			continue
+		}
+		l3Map, err := r.resctrlReader.ReadResctrlL3Stat(monGroup)
+		if err != nil {
+			klog.V(4).Infof("collect pod %s/%s resctrl llc data error: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for cacheId, value := range l3Map {
+			metrics.RecordPodResctrlLLC(int(cacheId), pod, value)
+		}
+		mbMap, err := r.resctrlReader.ReadResctrlMBStat(monGroup)
+		if err != nil {
+			klog.V(4).Infof("collect pod %s/%s resctrl mb data error: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for cacheId, value := range mbMap {
+			for mbType, mbValue := range value {
+				metrics.RecordPodResctrlMB(int(cacheId), pod, mbType, mbValue)
+			}
+		}
+	}
+	klog.V(6).Infof("collect resctrl data of %d pod mon groups", len(podMonGroups))
+}
+
+// recordRMIDStatus records the RMID usage, and reports a node event when the RMIDs become exhausted, so the
+// operators can see why the pod-level resctrl stats are missing.
+func (r *resctrlCollector) recordRMIDStatus(status resourceexecutor.ResctrlRMIDStatus) {
+	metrics.RecordResctrlRMIDStatus(status.Capacity, status.Used, status.PendingPods)
+	if status.RecycledMonGroups > 0 {
+		metrics.RecordResctrlMonGroupRecycled(status.RecycledMonGroups)
+	}
+
+	exhausted := status.IsExhausted()
+	defer func() {
+		r.rmidExhausted = exhausted
+	}()
+	if !exhausted || r.rmidExhausted {
+		return
+	}
+	klog.Warningf("resctrl RMIDs are exhausted, used %d, capacity %d, %d pods have no mon groups",
+		status.Used, status.Capacity, status.PendingPods)
+	if r.eventRecorder == nil {
+		return
+	}
+	node := r.statesInformer.GetNode()
+	if node == nil {
+		return
+	}
+	r.eventRecorder.Eventf(node, corev1.EventTypeWarning, ReasonResctrlRMIDExhausted,
+		"resctrl RMIDs are exhausted (used %d, capacity %d), %d pods are missing the pod-level resctrl stats",
+		status.Used, status.Capacity, status.PendingPods)
+}
+
+func (r *resctrlCollector) getPodTaskIds(podMeta *statesinformer.PodMeta) []int32 {
+	var taskIds []int32
+	pod := podMeta.Pod
+	for i := range pod.Status.ContainerStatuses {
+		containerStat := &pod.Status.ContainerStatuses[i]
+		containerDir, err := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
+		if err != nil {
+			klog.V(5).Infof("failed to get container cgroup path for %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		ids, err := r.cgroupReader.ReadCPUTasks(containerDir)
+		if err != nil {
+			klog.V(5).Infof("failed to read container task ids for %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStat.Name, err)
+			continue
+		}
+		taskIds = append(taskIds, ids...)
+	}
+	return taskIds
+}
+
+// calculateMemoryBandwidth returns the memory bandwidth in bytes per second of each QoS group since the last
+// collection. The groups collected for the first time or whose counters reset are skipped.
+func (r *resctrlCollector) calculateMemoryBandwidth(mbmTotalBytes map[string]uint64, now time.Time) map[string]float64 {
+	lastTotalBytes, lastTime := r.lastMBMTotalBytes, r.lastCollectTime
+	r.lastMBMTotalBytes, r.lastCollectTime = mbmTotalBytes, now
+	if lastTime.IsZero() || !now.After(lastTime) {
+		return nil
+	}
+	bandwidths := map[string]float64{}
+	duration := now.Sub(lastTime).Seconds()
+	for qos, totalBytes := range mbmTotalBytes {
+		last, ok := lastTotalBytes[qos]
+		if !ok || totalBytes < last {
+			continue
+		}
+		bandwidths[qos] = float64(totalBytes-last) / duration
+	}
+	return bandwidths
+}
+
 func (r *resctrlCollector) saveMetric(samples []metriccache.MetricSample) error {
 	if len(samples) == 0 {
 		return nil
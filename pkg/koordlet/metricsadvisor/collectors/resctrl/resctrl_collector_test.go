@@ -18,9 +18,13 @@ package resctrl
 
 import (
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
@@ -165,3 +169,51 @@ func Test_collectQosResctrlStatNoErr(t *testing.T) {
 		})
 	}
 }
+
+func Test_recordRMIDStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetNode().Return(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}).AnyTimes()
+	recorder := record.NewFakeRecorder(10)
+	r := &resctrlCollector{
+		statesInformer: statesInformer,
+		eventRecorder:  recorder,
+	}
+
+	r.recordRMIDStatus(resourceexecutor.ResctrlRMIDStatus{Capacity: 8, Used: 6})
+	assert.False(t, r.rmidExhausted)
+	assert.Len(t, recorder.Events, 0)
+
+	// report the event once the RMIDs become exhausted
+	r.recordRMIDStatus(resourceexecutor.ResctrlRMIDStatus{Capacity: 8, Used: 8, PendingPods: 2})
+	assert.True(t, r.rmidExhausted)
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, ReasonResctrlRMIDExhausted)
+	r.recordRMIDStatus(resourceexecutor.ResctrlRMIDStatus{Capacity: 8, Used: 8, PendingPods: 1, RecycledMonGroups: 1})
+	assert.Len(t, recorder.Events, 0)
+
+	// report again after recovered
+	r.recordRMIDStatus(resourceexecutor.ResctrlRMIDStatus{Capacity: 8, Used: 7})
+	assert.False(t, r.rmidExhausted)
+	r.recordRMIDStatus(resourceexecutor.ResctrlRMIDStatus{Capacity: 8, Used: 8, PendingPods: 1})
+	assert.Len(t, recorder.Events, 1)
+}
+
+func Test_calculateMemoryBandwidth(t *testing.T) {
+	r := &resctrlCollector{}
+	now := time.Now()
+	// skip the first collection
+	got := r.calculateMemoryBandwidth(map[string]uint64{"LS": 1000, "BE": 2000}, now)
+	assert.Nil(t, got)
+
+	got = r.calculateMemoryBandwidth(map[string]uint64{"LS": 3000, "BE": 1000, "LSR": 500}, now.Add(2*time.Second))
+	// the BE counter resets and the LSR group is collected for the first time
+	assert.Equal(t, map[string]float64{"LS": 1000}, got)
+
+	got = r.calculateMemoryBandwidth(map[string]uint64{"LS": 4000, "BE": 5000, "LSR": 1500}, now.Add(4*time.Second))
+	assert.Equal(t, map[string]float64{"LS": 500, "BE": 2000, "LSR": 500}, got)
+}
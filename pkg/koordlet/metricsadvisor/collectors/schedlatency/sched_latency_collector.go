@@ -0,0 +1,221 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"time"
+
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/schedlatency"
+)
+
+const (
+	CollectorName = "SchedLatencyCollector"
+)
+
+var (
+	timeNow = time.Now
+
+	// newProbe loads and attaches the eBPF probe, which can be overwritten for testing.
+	newProbe = func() (latencyProbe, error) {
+		probe, err := schedlatency.NewProbe()
+		if err != nil {
+			return nil, err
+		}
+		return probe, nil
+	}
+
+	quantiles = []struct {
+		name  metriccache.MetricPropertyValue
+		value float64
+	}{
+		{name: metriccache.SchedLatencyQuantileP50, value: 0.50},
+		{name: metriccache.SchedLatencyQuantileP95, value: 0.95},
+		{name: metriccache.SchedLatencyQuantileP99, value: 0.99},
+	}
+)
+
+// latencyProbe accumulates the run queue latency histograms of the tasks by the pid.
+type latencyProbe interface {
+	Drain() (map[uint32]*schedlatency.Histogram, error)
+	Close() error
+}
+
+// schedLatencyCollector collects the run queue latencies of the pods and containers, that is, the time from a task
+// becomes runnable to it actually runs on a cpu. The latencies are recorded per task by the eBPF programs attached
+// to the sched tracepoints, and aggregated by the cgroup tasks in each interval.
+type schedLatencyCollector struct {
+	collectInterval time.Duration
+
+	started        *atomic.Bool
+	statesInformer statesinformer.StatesInformer
+	metricCache    metriccache.MetricCache
+	cgroupReader   resourceexecutor.CgroupReader
+
+	probe latencyProbe
+}
+
+func New(opt *framework.Options) framework.Collector {
+	return &schedLatencyCollector{
+		collectInterval: opt.Config.SchedLatencyCollectorInterval,
+		started:         atomic.NewBool(false),
+		statesInformer:  opt.StatesInformer,
+		metricCache:     opt.MetricCache,
+		cgroupReader:    opt.CgroupReader,
+	}
+}
+
+func (s *schedLatencyCollector) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.SchedLatencyCollector)
+}
+
+func (s *schedLatencyCollector) Setup(c *framework.Context) {}
+
+func (s *schedLatencyCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, s.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	probe, err := newProbe()
+	if err != nil {
+		klog.Warningf("skip collecting sched latency since the eBPF probe is unavailable, err: %v", err)
+		s.started.Store(true)
+		return
+	}
+	s.probe = probe
+	go func() {
+		wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, s.collectSchedLatency), s.collectInterval, stopCh)
+		if err := s.probe.Close(); err != nil {
+			klog.Warningf("failed to close sched latency probe, err: %v", err)
+		}
+	}()
+}
+
+func (s *schedLatencyCollector) Started() bool {
+	return s.started.Load()
+}
+
+func (s *schedLatencyCollector) collectSchedLatency() {
+	klog.V(6).Infof("start collectSchedLatency")
+	histograms, err := s.probe.Drain()
+	collectTime := timeNow()
+	if err != nil {
+		klog.Errorf("failed to drain sched latency histograms, err: %v", err)
+		return
+	}
+
+	samples := make([]metriccache.MetricSample, 0)
+	podMetas := s.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		pod := meta.Pod
+		podUID := string(pod.GetUID())
+		podHistogram := &schedlatency.Histogram{}
+		for i := range pod.Status.ContainerStatuses {
+			containerStatus := &pod.Status.ContainerStatuses[i]
+			containerDir, err := util.GetContainerCgroupParentDir(meta.CgroupDir, containerStatus)
+			if err != nil {
+				klog.V(5).Infof("failed to get container path for container %s/%s/%s, err: %v",
+					pod.Namespace, pod.Name, containerStatus.Name, err)
+				continue
+			}
+			tasks, err := s.cgroupReader.ReadCPUTasks(containerDir)
+			if err != nil {
+				klog.V(5).Infof("failed to read tasks of container %s/%s/%s, err: %v",
+					pod.Namespace, pod.Name, containerStatus.Name, err)
+				continue
+			}
+			containerHistogram := &schedlatency.Histogram{}
+			for _, tid := range tasks {
+				containerHistogram.Merge(histograms[uint32(tid)])
+			}
+			if containerHistogram.Count() == 0 {
+				continue
+			}
+			podHistogram.Merge(containerHistogram)
+
+			containerSamples, err := generateQuantileSamples(containerHistogram, func(quantile string, value float64) (metriccache.MetricSample, error) {
+				return metriccache.ContainerSchedLatencyMetric.GenerateSample(
+					metriccache.MetricPropertiesFunc.ContainerSchedLatency(podUID, containerStatus.ContainerID, quantile), collectTime, value)
+			})
+			if err != nil {
+				klog.Warningf("failed to generate sched latency samples for container %s/%s/%s, err: %v",
+					pod.Namespace, pod.Name, containerStatus.Name, err)
+				continue
+			}
+			samples = append(samples, containerSamples...)
+		}
+		if podHistogram.Count() == 0 {
+			continue
+		}
+		podSamples, err := generateQuantileSamples(podHistogram, func(quantile string, value float64) (metriccache.MetricSample, error) {
+			return metriccache.PodSchedLatencyMetric.GenerateSample(
+				metriccache.MetricPropertiesFunc.PodSchedLatency(podUID, quantile), collectTime, value)
+		})
+		if err != nil {
+			klog.Warningf("failed to generate sched latency samples for pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		samples = append(samples, podSamples...)
+	}
+
+	s.saveMetric(samples)
+	s.started.Store(true)
+	klog.V(5).Infof("collectSchedLatency finished at %s, pod num %d, task num %d", timeNow(), len(podMetas), len(histograms))
+}
+
+func (s *schedLatencyCollector) saveMetric(samples []metriccache.MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	appender := s.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append sched latency metrics error")
+		return err
+	}
+
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit sched latency metrics failed")
+		return err
+	}
+
+	return nil
+}
+
+// generateQuantileSamples generates the P50, P95 and P99 samples of the histogram in seconds.
+func generateQuantileSamples(h *schedlatency.Histogram, sampleFn func(quantile string, value float64) (metriccache.MetricSample, error)) ([]metriccache.MetricSample, error) {
+	samples := make([]metriccache.MetricSample, 0, len(quantiles))
+	for _, q := range quantiles {
+		sample, err := sampleFn(string(q.name), h.Quantile(q.value).Seconds())
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
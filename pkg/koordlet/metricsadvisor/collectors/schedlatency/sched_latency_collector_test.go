@@ -0,0 +1,235 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/schedlatency"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+type fakeProbe struct {
+	histograms map[uint32]*schedlatency.Histogram
+	err        error
+	closed     bool
+}
+
+func (f *fakeProbe) Drain() (map[uint32]*schedlatency.Histogram, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	h := f.histograms
+	f.histograms = map[uint32]*schedlatency.Histogram{}
+	return h, nil
+}
+
+func (f *fakeProbe) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newHistogram(slots map[uint32]uint64) *schedlatency.Histogram {
+	h := &schedlatency.Histogram{}
+	for slot, count := range slots {
+		h.Add(slot, count)
+	}
+	return h
+}
+
+func TestNewSchedLatencyCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config:       framework.NewDefaultConfig(),
+		CgroupReader: resourceexecutor.NewCgroupReader(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.SchedLatencyCollector), c.Enabled())
+	assert.False(t, c.Started())
+}
+
+func TestSchedLatencyCollector_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().HasSynced().Return(true).AnyTimes()
+
+	oldNewProbe := newProbe
+	defer func() {
+		newProbe = oldNewProbe
+	}()
+	newProbe = func() (latencyProbe, error) {
+		return nil, fmt.Errorf("not supported")
+	}
+
+	c := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	}).(*schedLatencyCollector)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	c.Run(stopCh)
+	assert.True(t, c.Started())
+	assert.Nil(t, c.probe)
+}
+
+func TestSchedLatencyCollector_collectSchedLatency(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	podMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				UID:       "xxxxxx",
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "test-container-1",
+						ContainerID: "containerd://c1",
+					},
+					{
+						Name:        "test-container-2",
+						ContainerID: "containerd://c2",
+					},
+					{
+						Name:        "test-container-3",
+						ContainerID: "containerd://c3",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/kubepods-podxxxxxx.slice",
+	}
+	for i, tasks := range []string{"100\n101\n", "200\n"} {
+		containerDir, err := util.GetContainerCgroupParentDir(podMeta.CgroupDir, &podMeta.Pod.Status.ContainerStatuses[i])
+		assert.NoError(t, err)
+		helper.WriteCgroupFileContents(containerDir, system.CPUTasks, tasks)
+	}
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	probe := &fakeProbe{
+		histograms: map[uint32]*schedlatency.Histogram{
+			// container 1: 90 samples in [8us, 16us), 10 samples in [1ms, 2ms)
+			100: newHistogram(map[uint32]uint64{13: 60}),
+			101: newHistogram(map[uint32]uint64{13: 30, 20: 10}),
+			// container 2: 100 samples in [1us, 2us)
+			200: newHistogram(map[uint32]uint64{10: 100}),
+			// the task out of the pods
+			300: newHistogram(map[uint32]uint64{25: 100}),
+		},
+	}
+	c := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		MetricCache:    metricCache,
+		CgroupReader:   resourceexecutor.NewCgroupReader(),
+	}).(*schedLatencyCollector)
+	c.probe = probe
+	c.collectSchedLatency()
+	assert.True(t, c.Started())
+
+	tests := []struct {
+		resource  metriccache.MetricResource
+		property  map[metriccache.MetricProperty]string
+		wantValue float64
+	}{
+		{
+			resource:  metriccache.ContainerSchedLatencyMetric,
+			property:  metriccache.MetricPropertiesFunc.ContainerSchedLatency("xxxxxx", "containerd://c1", string(metriccache.SchedLatencyQuantileP50)),
+			wantValue: (8192 + 8192*50.0/90) / 1e9,
+		},
+		{
+			resource:  metriccache.ContainerSchedLatencyMetric,
+			property:  metriccache.MetricPropertiesFunc.ContainerSchedLatency("xxxxxx", "containerd://c1", string(metriccache.SchedLatencyQuantileP99)),
+			wantValue: (1048576 + 1048576*9.0/10) / 1e9,
+		},
+		{
+			resource:  metriccache.ContainerSchedLatencyMetric,
+			property:  metriccache.MetricPropertiesFunc.ContainerSchedLatency("xxxxxx", "containerd://c2", string(metriccache.SchedLatencyQuantileP95)),
+			wantValue: (1024 + 1024*95.0/100) / 1e9,
+		},
+		{
+			resource:  metriccache.PodSchedLatencyMetric,
+			property:  metriccache.MetricPropertiesFunc.PodSchedLatency("xxxxxx", string(metriccache.SchedLatencyQuantileP50)),
+			wantValue: 2048.0 / 1e9,
+		},
+		{
+			resource:  metriccache.PodSchedLatencyMetric,
+			property:  metriccache.MetricPropertiesFunc.PodSchedLatency("xxxxxx", string(metriccache.SchedLatencyQuantileP95)),
+			wantValue: 16384.0 / 1e9,
+		},
+	}
+	start, end := testNow.Add(-5*time.Second), testNow.Add(5*time.Second)
+	querier, err := metricCache.Querier(start, end)
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		queryMeta, err := tt.resource.BuildQueryMeta(tt.property)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.InDelta(t, tt.wantValue, got, 1e-9, tt.property)
+	}
+
+	// container 3 has no tasks
+	queryMeta, err := metriccache.ContainerSchedLatencyMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.ContainerSchedLatency("xxxxxx", "containerd://c3", string(metriccache.SchedLatencyQuantileP50)))
+	assert.NoError(t, err)
+	result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+	assert.NoError(t, querier.Query(queryMeta, nil, result))
+	assert.Equal(t, 0, result.Count())
+
+	// drain failed
+	probe.err = fmt.Errorf("expected error")
+	c.collectSchedLatency()
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -76,7 +77,7 @@ func (s *systemResourceCollector) Run(stopCh <-chan struct{}) {
 	if !cache.WaitForCacheSync(stopCh, dependencyStarted) {
 		klog.Fatal("time out waiting for other collector started")
 	}
-	go wait.Until(s.collectSysResUsed, s.collectInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, CollectorName, s.collectSysResUsed), s.collectInterval, stopCh)
 }
 
 func (s *systemResourceCollector) Started() bool {
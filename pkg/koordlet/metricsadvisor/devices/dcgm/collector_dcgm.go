@@ -0,0 +1,223 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+)
+
+const (
+	DeviceCollectorName = "DCGM"
+)
+
+var (
+	timeNow = time.Now
+)
+
+// dcgmCollector collects the profiling metrics and the Xid errors of the NVIDIA GPUs from the dcgm-exporter.
+// The metrics of a GPU are attributed to the pods and containers whose processes open the GPU device, so the
+// pods sharing a GPU get the same device-level values.
+// Since the dcgm-exporter updates the fields every several seconds, the collector appends the samples into the
+// metric cache at its own interval instead of the resource usage collecting.
+type dcgmCollector struct {
+	enabled         bool
+	collectInterval time.Duration
+
+	started        *atomic.Bool
+	statesInformer statesinformer.StatesInformer
+	metricCache    metriccache.MetricCache
+	exporter       exporterClient
+}
+
+func New(opt *framework.Options) framework.DeviceCollector {
+	return &dcgmCollector{
+		enabled:         features.DefaultKoordletFeatureGate.Enabled(features.DCGMCollector),
+		collectInterval: opt.Config.DCGMCollectorInterval,
+		started:         atomic.NewBool(false),
+		statesInformer:  opt.StatesInformer,
+		metricCache:     opt.MetricCache,
+		exporter:        newExporterClient(opt.Config.DCGMExporterEndpoint),
+	}
+}
+
+func (d *dcgmCollector) Shutdown() {}
+
+func (d *dcgmCollector) Enabled() bool {
+	return d.enabled
+}
+
+func (d *dcgmCollector) Setup(fra *framework.Context) {}
+
+func (d *dcgmCollector) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, d.statesInformer.HasSynced) {
+		// Koordlet exit because of statesInformer sync failed.
+		klog.Fatalf("timed out waiting for states informer caches to sync")
+	}
+	go wait.Until(d.collectDCGMMetrics, d.collectInterval, stopCh)
+}
+
+func (d *dcgmCollector) Started() bool {
+	return d.started.Load()
+}
+
+// Infos returns nil since the GPU devices are reported by the GPU collector.
+func (d *dcgmCollector) Infos() metriccache.Devices {
+	return nil
+}
+
+func (d *dcgmCollector) GetNodeMetric() ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (d *dcgmCollector) GetPodMetric(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (d *dcgmCollector) GetContainerMetric(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	return nil, nil
+}
+
+func (d *dcgmCollector) collectDCGMMetrics() {
+	klog.V(6).Infof("start collectDCGMMetrics")
+	gpus, err := d.exporter.scrape()
+	collectTime := timeNow()
+	if err != nil {
+		klog.Warningf("failed to collect dcgm metrics, err: %v", err)
+		return
+	}
+
+	samples := make([]metriccache.MetricSample, 0)
+	for _, gpu := range gpus {
+		properties := metriccache.MetricPropertiesFunc.GPU(fmt.Sprintf("%d", gpu.Minor), gpu.UUID)
+		for name, value := range gpu.Values {
+			if sample := buildMetricSample(dcgmFields[name].node, properties, collectTime, value); sample != nil {
+				samples = append(samples, sample)
+			}
+		}
+	}
+
+	podMetas := d.statesInformer.GetAllPods()
+	for _, meta := range podMetas {
+		samples = append(samples, d.collectPodDCGMMetrics(meta, gpus, collectTime)...)
+	}
+
+	d.saveMetric(samples)
+	d.started.Store(true)
+	klog.V(5).Infof("collectDCGMMetrics finished at %s, gpu num %d, pod num %d", timeNow(), len(gpus), len(podMetas))
+}
+
+func (d *dcgmCollector) collectPodDCGMMetrics(meta *statesinformer.PodMeta, gpus map[int32]*gpuFieldValues, collectTime time.Time) []metriccache.MetricSample {
+	pod := meta.Pod
+	samples := make([]metriccache.MetricSample, 0)
+	podMinors := map[int32]struct{}{}
+	for i := range pod.Status.ContainerStatuses {
+		containerStatus := &pod.Status.ContainerStatuses[i]
+		if containerStatus.State.Running == nil {
+			continue
+		}
+		pids, err := util.GetPIDsInContainer(meta.CgroupDir, containerStatus)
+		if err != nil {
+			klog.V(5).Infof("failed to get pids of container %s/%s/%s, err: %v",
+				pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		containerMinors := map[int32]struct{}{}
+		for _, pid := range pids {
+			minors, err := getProcessGPUMinors(pid)
+			if err != nil {
+				klog.V(6).Infof("failed to get gpus of process %d, err: %v", pid, err)
+				continue
+			}
+			for _, minor := range minors {
+				containerMinors[minor] = struct{}{}
+				podMinors[minor] = struct{}{}
+			}
+		}
+		for minor := range containerMinors {
+			gpu, ok := gpus[minor]
+			if !ok {
+				continue
+			}
+			properties := metriccache.MetricPropertiesFunc.ContainerGPU(containerStatus.ContainerID, fmt.Sprintf("%d", minor), gpu.UUID)
+			for name, value := range gpu.Values {
+				if field := dcgmFields[name]; field.container != nil {
+					if sample := buildMetricSample(field.container, properties, collectTime, value); sample != nil {
+						samples = append(samples, sample)
+					}
+				}
+			}
+		}
+	}
+
+	podUID := string(pod.UID)
+	for minor := range podMinors {
+		gpu, ok := gpus[minor]
+		if !ok {
+			continue
+		}
+		properties := metriccache.MetricPropertiesFunc.PodGPU(podUID, fmt.Sprintf("%d", minor), gpu.UUID)
+		for name, value := range gpu.Values {
+			if field := dcgmFields[name]; field.pod != nil {
+				if sample := buildMetricSample(field.pod, properties, collectTime, value); sample != nil {
+					samples = append(samples, sample)
+				}
+			}
+		}
+	}
+	return samples
+}
+
+func (d *dcgmCollector) saveMetric(samples []metriccache.MetricSample) error {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	appender := d.metricCache.Appender()
+	if err := appender.Append(samples); err != nil {
+		klog.ErrorS(err, "Append dcgm metrics error")
+		return err
+	}
+
+	if err := appender.Commit(); err != nil {
+		klog.ErrorS(err, "Commit dcgm metrics failed")
+		return err
+	}
+
+	return nil
+}
+
+func buildMetricSample(mr metriccache.MetricResource, properties map[metriccache.MetricProperty]string, t time.Time, val float64) metriccache.MetricSample {
+	m, err := mr.GenerateSample(properties, t, val)
+	if err != nil {
+		klog.Errorf("GenerateSample(%v) error: %v", mr, err)
+		return nil
+	}
+	return m
+}
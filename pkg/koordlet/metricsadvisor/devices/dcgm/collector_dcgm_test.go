@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+type fakeExporterClient struct {
+	gpus map[int32]*gpuFieldValues
+	err  error
+}
+
+func (f *fakeExporterClient) scrape() (map[int32]*gpuFieldValues, error) {
+	return f.gpus, f.err
+}
+
+func TestNewDCGMCollector(t *testing.T) {
+	c := New(&framework.Options{
+		Config: framework.NewDefaultConfig(),
+	})
+	assert.NotNil(t, c)
+	assert.Equal(t, features.DefaultKoordletFeatureGate.Enabled(features.DCGMCollector), c.Enabled())
+	assert.False(t, c.Started())
+	assert.Nil(t, c.Infos())
+}
+
+func Test_dcgmCollector_collectDCGMMetrics(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	system.SetupCgroupPathFormatter(system.Systemd)
+	defer system.SetupCgroupPathFormatter(system.Systemd)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	podParentDir := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod6553a60b_2b97_442a_b6da_a5704d81dd98.slice/"
+	helper.WriteFileContents("cpu/"+podParentDir+"docker-c1.scope/cgroup.procs", "122\n222\n")
+	helper.WriteFileContents("cpu/"+podParentDir+"docker-c2.scope/cgroup.procs", "333\n")
+	writeProcessFds(t, "122", "/dev/nvidiactl", "/dev/nvidia0")
+	writeProcessFds(t, "222", "/dev/nvidia1")
+	writeProcessFds(t, "333", "/dev/null")
+
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	podMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-pod",
+				Namespace: "default",
+				UID:       "6553a60b-2b97-442a-b6da-a5704d81dd98",
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "c1", ContainerID: "docker://c1", State: running},
+					{Name: "c2", ContainerID: "docker://c2", State: running},
+				},
+			},
+		},
+		CgroupDir: podParentDir,
+	}
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{podMeta}).AnyTimes()
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	exporter := &fakeExporterClient{
+		gpus: map[int32]*gpuFieldValues{
+			0: {Minor: 0, UUID: "GPU-0000", Values: map[string]float64{
+				"DCGM_FI_PROF_SM_ACTIVE":       0.25,
+				"DCGM_FI_PROF_DRAM_ACTIVE":     0.125,
+				"DCGM_FI_PROF_NVLINK_TX_BYTES": 1024,
+				"DCGM_FI_DEV_XID_ERRORS":       0,
+			}},
+			1: {Minor: 1, UUID: "GPU-1111", Values: map[string]float64{
+				"DCGM_FI_PROF_SM_ACTIVE": 0.5,
+				"DCGM_FI_DEV_XID_ERRORS": 79,
+			}},
+		},
+	}
+	c := New(&framework.Options{
+		Config:         framework.NewDefaultConfig(),
+		StatesInformer: mockStatesInformer,
+		MetricCache:    metricCache,
+	}).(*dcgmCollector)
+	c.exporter = exporter
+	c.collectDCGMMetrics()
+	assert.True(t, c.Started())
+
+	podUID := string(podMeta.Pod.UID)
+	tests := []struct {
+		resource  metriccache.MetricResource
+		property  map[metriccache.MetricProperty]string
+		wantCount int
+		wantValue float64
+	}{
+		{
+			resource:  metriccache.NodeGPUSMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.GPU("0", "GPU-0000"),
+			wantCount: 1,
+			wantValue: 0.25,
+		},
+		{
+			resource:  metriccache.NodeGPUNVLinkTxBytesMetric,
+			property:  metriccache.MetricPropertiesFunc.GPU("0", "GPU-0000"),
+			wantCount: 1,
+			wantValue: 1024,
+		},
+		{
+			resource:  metriccache.NodeGPUXidErrorMetric,
+			property:  metriccache.MetricPropertiesFunc.GPU("1", "GPU-1111"),
+			wantCount: 1,
+			wantValue: 79,
+		},
+		{
+			resource:  metriccache.PodGPUSMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.PodGPU(podUID, "0", "GPU-0000"),
+			wantCount: 1,
+			wantValue: 0.25,
+		},
+		{
+			resource:  metriccache.PodGPUDRAMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.PodGPU(podUID, "0", "GPU-0000"),
+			wantCount: 1,
+			wantValue: 0.125,
+		},
+		{
+			resource:  metriccache.PodGPUSMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.PodGPU(podUID, "1", "GPU-1111"),
+			wantCount: 1,
+			wantValue: 0.5,
+		},
+		{
+			resource:  metriccache.ContainerGPUSMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.ContainerGPU("docker://c1", "1", "GPU-1111"),
+			wantCount: 1,
+			wantValue: 0.5,
+		},
+		{
+			// c2 uses no gpu
+			resource:  metriccache.ContainerGPUSMActiveMetric,
+			property:  metriccache.MetricPropertiesFunc.ContainerGPU("docker://c2", "0", "GPU-0000"),
+			wantCount: 0,
+		},
+	}
+	start, end := testNow.Add(-5*time.Second), testNow.Add(5*time.Second)
+	querier, err := metricCache.Querier(start, end)
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		queryMeta, err := tt.resource.BuildQueryMeta(tt.property)
+		assert.NoError(t, err)
+		result := metriccache.DefaultAggregateResultFactory.New(queryMeta)
+		assert.NoError(t, querier.Query(queryMeta, nil, result))
+		assert.Equal(t, tt.wantCount, result.Count(), tt.property)
+		if tt.wantCount <= 0 {
+			continue
+		}
+		got, err := result.Value(metriccache.AggregationTypeLast)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantValue, got, tt.property)
+	}
+
+	// scrape failed
+	exporter.err = fmt.Errorf("expected error")
+	c.collectDCGMMetrics()
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+)
+
+const (
+	defaultScrapeTimeout = 5 * time.Second
+
+	// the labels of the dcgm-exporter metrics
+	labelGPU     = "gpu"
+	labelUUID    = "UUID"
+	labelDevice  = "device"
+	labelGPUIID  = "GPU_I_ID"
+	devicePrefix = "nvidia"
+)
+
+// dcgmField describes how a field exported by the dcgm-exporter is saved. The field is attributed to the pods and
+// containers using the GPU only if the pod and container resources are set.
+type dcgmField struct {
+	node      metriccache.MetricResource
+	pod       metriccache.MetricResource
+	container metriccache.MetricResource
+}
+
+var dcgmFields = map[string]dcgmField{
+	// ratio of the time at least one warp is active on a SM, averaged over all SMs
+	"DCGM_FI_PROF_SM_ACTIVE": {
+		node:      metriccache.NodeGPUSMActiveMetric,
+		pod:       metriccache.PodGPUSMActiveMetric,
+		container: metriccache.ContainerGPUSMActiveMetric,
+	},
+	// ratio of the cycles the device memory interface is active sending or receiving data
+	"DCGM_FI_PROF_DRAM_ACTIVE": {
+		node:      metriccache.NodeGPUDRAMActiveMetric,
+		pod:       metriccache.PodGPUDRAMActiveMetric,
+		container: metriccache.ContainerGPUDRAMActiveMetric,
+	},
+	// bytes per second transmitted or received through the NVLink
+	"DCGM_FI_PROF_NVLINK_TX_BYTES": {
+		node: metriccache.NodeGPUNVLinkTxBytesMetric,
+	},
+	"DCGM_FI_PROF_NVLINK_RX_BYTES": {
+		node: metriccache.NodeGPUNVLinkRxBytesMetric,
+	},
+	// the value of the last Xid error, 0 if no error occurs
+	"DCGM_FI_DEV_XID_ERRORS": {
+		node: metriccache.NodeGPUXidErrorMetric,
+	},
+}
+
+// gpuFieldValues is the values of the dcgm fields of a GPU.
+type gpuFieldValues struct {
+	Minor  int32
+	UUID   string
+	Values map[string]float64
+}
+
+type exporterClient interface {
+	scrape() (map[int32]*gpuFieldValues, error)
+}
+
+type httpExporterClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+func newExporterClient(endpoint string) exporterClient {
+	return &httpExporterClient{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: defaultScrapeTimeout},
+	}
+}
+
+func (c *httpExporterClient) scrape() (map[int32]*gpuFieldValues, error) {
+	resp, err := c.client.Get(c.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request dcgm-exporter %s, err: %w", c.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to request dcgm-exporter %s, status code %d", c.endpoint, resp.StatusCode)
+	}
+	return parseExporterMetrics(resp.Body)
+}
+
+// parseExporterMetrics parses the dcgm fields of the GPUs from the metrics in the prometheus text format, e.g.
+// `DCGM_FI_PROF_SM_ACTIVE{gpu="0",UUID="GPU-xxx",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB"} 0.25`.
+// The series of the MIG instances are ignored since the fields of the instances cannot be summed up.
+func parseExporterMetrics(r io.Reader) (map[int32]*gpuFieldValues, error) {
+	parser := expfmt.TextParser{}
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse dcgm-exporter metrics, err: %w", err)
+	}
+	gpus := map[int32]*gpuFieldValues{}
+	for name, family := range families {
+		if _, ok := dcgmFields[name]; !ok {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels[labelGPUIID] != "" {
+				continue
+			}
+			minor, err := getGPUMinor(labels)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse gpu of metric %s, err: %w", name, err)
+			}
+			value, ok := getMetricValue(m)
+			if !ok {
+				continue
+			}
+			gpu, ok := gpus[minor]
+			if !ok {
+				gpu = &gpuFieldValues{Minor: minor, UUID: labels[labelUUID], Values: map[string]float64{}}
+				gpus[minor] = gpu
+			}
+			gpu.Values[name] = value
+		}
+	}
+	return gpus, nil
+}
+
+// getGPUMinor returns the minor number of the GPU by the device name, e.g. "nvidia0", or the gpu index if the
+// device name is missing.
+func getGPUMinor(labels map[string]string) (int32, error) {
+	raw := labels[labelGPU]
+	if device := labels[labelDevice]; strings.HasPrefix(device, devicePrefix) {
+		raw = strings.TrimPrefix(device, devicePrefix)
+	}
+	minor, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return int32(minor), nil
+}
+
+func getMetricValue(m *dto.Metric) (float64, bool) {
+	switch {
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue(), true
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue(), true
+	case m.GetUntyped() != nil:
+		return m.GetUntyped().GetValue(), true
+	}
+	return 0, false
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testExporterMetrics = `# HELP DCGM_FI_DEV_SM_CLOCK SM clock frequency (in MHz).
+# TYPE DCGM_FI_DEV_SM_CLOCK gauge
+DCGM_FI_DEV_SM_CLOCK{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 1410
+DCGM_FI_DEV_SM_CLOCK{gpu="1",UUID="GPU-1111",device="nvidia1",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 1410
+# HELP DCGM_FI_DEV_XID_ERRORS Value of the last XID error encountered.
+# TYPE DCGM_FI_DEV_XID_ERRORS gauge
+DCGM_FI_DEV_XID_ERRORS{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 0
+DCGM_FI_DEV_XID_ERRORS{gpu="1",UUID="GPU-1111",device="nvidia1",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 79
+# HELP DCGM_FI_PROF_SM_ACTIVE The ratio of cycles an SM has at least 1 warp assigned.
+# TYPE DCGM_FI_PROF_SM_ACTIVE gauge
+DCGM_FI_PROF_SM_ACTIVE{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 0.25
+DCGM_FI_PROF_SM_ACTIVE{gpu="1",UUID="GPU-1111",device="nvidia1",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 0.5
+DCGM_FI_PROF_SM_ACTIVE{gpu="1",UUID="GPU-1111",device="nvidia1",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0",GPU_I_PROFILE="1g.10gb",GPU_I_ID="1"} 0.1
+# HELP DCGM_FI_PROF_DRAM_ACTIVE The ratio of cycles the device memory interface is active sending or receiving data.
+# TYPE DCGM_FI_PROF_DRAM_ACTIVE gauge
+DCGM_FI_PROF_DRAM_ACTIVE{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 0.125
+# HELP DCGM_FI_PROF_NVLINK_TX_BYTES The number of bytes of active NvLink tx data including both header and payload.
+# TYPE DCGM_FI_PROF_NVLINK_TX_BYTES gauge
+DCGM_FI_PROF_NVLINK_TX_BYTES{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 1024
+# HELP DCGM_FI_PROF_NVLINK_RX_BYTES The number of bytes of active NvLink rx data including both header and payload.
+# TYPE DCGM_FI_PROF_NVLINK_RX_BYTES gauge
+DCGM_FI_PROF_NVLINK_RX_BYTES{gpu="0",UUID="GPU-0000",device="nvidia0",modelName="NVIDIA A100-SXM4-80GB",Hostname="node-0"} 2048
+`
+
+func Test_parseExporterMetrics(t *testing.T) {
+	got, err := parseExporterMetrics(strings.NewReader(testExporterMetrics))
+	assert.NoError(t, err)
+	expected := map[int32]*gpuFieldValues{
+		0: {
+			Minor: 0,
+			UUID:  "GPU-0000",
+			Values: map[string]float64{
+				"DCGM_FI_DEV_XID_ERRORS":       0,
+				"DCGM_FI_PROF_SM_ACTIVE":       0.25,
+				"DCGM_FI_PROF_DRAM_ACTIVE":     0.125,
+				"DCGM_FI_PROF_NVLINK_TX_BYTES": 1024,
+				"DCGM_FI_PROF_NVLINK_RX_BYTES": 2048,
+			},
+		},
+		1: {
+			Minor: 1,
+			UUID:  "GPU-1111",
+			Values: map[string]float64{
+				"DCGM_FI_DEV_XID_ERRORS": 79,
+				"DCGM_FI_PROF_SM_ACTIVE": 0.5,
+			},
+		},
+	}
+	assert.Equal(t, expected, got)
+
+	_, err = parseExporterMetrics(strings.NewReader(`DCGM_FI_PROF_SM_ACTIVE{gpu="x",UUID="GPU-0000"} 0.25`))
+	assert.Error(t, err)
+	_, err = parseExporterMetrics(strings.NewReader(`DCGM_FI_PROF_SM_ACTIVE{gpu="0" 0.25`))
+	assert.Error(t, err)
+}
+
+func Test_getGPUMinor(t *testing.T) {
+	minor, err := getGPUMinor(map[string]string{labelGPU: "0", labelDevice: "nvidia3"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), minor)
+	minor, err = getGPUMinor(map[string]string{labelGPU: "2"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), minor)
+	_, err = getGPUMinor(map[string]string{})
+	assert.Error(t, err)
+}
+
+func Test_httpExporterClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(testExporterMetrics))
+	}))
+	defer server.Close()
+
+	got, err := newExporterClient(server.URL + "/metrics").scrape()
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	_, err = newExporterClient(server.URL + "/notfound").scrape()
+	assert.Error(t, err)
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const nvidiaDevicePathPrefix = "/dev/nvidia"
+
+// getProcessGPUMinors returns the minor numbers of the GPUs opened by the process, by finding the file descriptors
+// linked to the device files `/dev/nvidia<minor>`. The control devices like `/dev/nvidiactl` are skipped.
+func getProcessGPUMinors(pid uint32) ([]int32, error) {
+	fdDir := filepath.Join(system.Conf.ProcRootDir, strconv.FormatUint(uint64(pid), 10), "fd")
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return nil, err
+	}
+	minorSet := map[int32]struct{}{}
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+		if err != nil || !strings.HasPrefix(target, nvidiaDevicePathPrefix) {
+			// the fd may be closed during the iteration
+			continue
+		}
+		minor, err := strconv.ParseInt(strings.TrimPrefix(target, nvidiaDevicePathPrefix), 10, 32)
+		if err != nil {
+			continue
+		}
+		minorSet[int32(minor)] = struct{}{}
+	}
+	minors := make([]int32, 0, len(minorSet))
+	for minor := range minorSet {
+		minors = append(minors, minor)
+	}
+	sort.Slice(minors, func(i, j int) bool {
+		return minors[i] < minors[j]
+	})
+	return minors, nil
+}
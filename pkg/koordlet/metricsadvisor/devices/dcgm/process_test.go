@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dcgm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func writeProcessFds(t *testing.T, pid string, targets ...string) {
+	fdDir := filepath.Join(system.Conf.ProcRootDir, pid, "fd")
+	assert.NoError(t, os.MkdirAll(fdDir, 0755))
+	for i, target := range targets {
+		assert.NoError(t, os.Symlink(target, filepath.Join(fdDir, string(rune('0'+i)))))
+	}
+}
+
+func Test_getProcessGPUMinors(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	writeProcessFds(t, "100", "/dev/null", "/dev/nvidiactl", "/dev/nvidia-uvm", "/dev/nvidia1", "/dev/nvidia0", "/dev/nvidia1")
+	minors, err := getProcessGPUMinors(100)
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0, 1}, minors)
+
+	writeProcessFds(t, "101", "/dev/null", "socket:[12345]")
+	minors, err = getProcessGPUMinors(101)
+	assert.NoError(t, err)
+	assert.Empty(t, minors)
+
+	_, err = getProcessGPUMinors(102)
+	assert.Error(t, err)
+}
@@ -0,0 +1,284 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/helper"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// amdGPUDeviceManager collects the AMD GPUs (e.g. the MI-series accelerators) from the KFD topology and the amdgpu
+// sysfs like the rocm-smi, so it requires no ROCm library on the node.
+type amdGPUDeviceManager struct {
+	sync.RWMutex
+	devices     []*amdDevice
+	collectTime time.Time
+	start       *atomic.Bool
+	// deviceMetrics is the usage of each device, indexed the same as the devices
+	deviceMetrics    []*rawGPUMetric
+	processesMetrics map[uint32][]*rawGPUMetric
+}
+
+type amdDevice struct {
+	Minor        int32 // index starting from 0, ordered by the bus id
+	DeviceUUID   string
+	GPUID        uint64 // the id in the KFD topology
+	ComputeUnits uint64
+	MemoryTotal  uint64
+	NodeID       int32
+	PCIE         string
+	BusID        string
+}
+
+// initAMDGPUDeviceManager returns nil if there is no AMD GPU on the node.
+func initAMDGPUDeviceManager() GPUDeviceManager {
+	devices, err := initAMDGPUData()
+	if err != nil {
+		klog.Warningf("init amd gpu data failed, error %s", err)
+		return nil
+	}
+	if len(devices) == 0 {
+		klog.V(4).Infof("no amd gpu device found")
+		return nil
+	}
+	return &amdGPUDeviceManager{
+		devices: devices,
+		start:   atomic.NewBool(false),
+	}
+}
+
+func initAMDGPUData() ([]*amdDevice, error) {
+	gpuInfos, err := system.GetAMDGPUInfos()
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*amdDevice, 0, len(gpuInfos))
+	for i, info := range gpuInfos {
+		memoryTotal, _, err := system.GetAMDGPUVRAM(info.BusID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get vram of device %s: %w", info.BusID, err)
+		}
+		nodeID, pcie, busID, err := helper.ParsePCIInfo(info.BusID)
+		if err != nil {
+			return nil, err
+		}
+		// use the bus id as the uuid if the unique id is not supported
+		uuid := info.BusID
+		if info.UniqueID != 0 {
+			uuid = fmt.Sprintf("0x%x", info.UniqueID)
+		}
+		devices = append(devices, &amdDevice{
+			Minor:        int32(i),
+			DeviceUUID:   uuid,
+			GPUID:        info.GPUID,
+			ComputeUnits: info.ComputeUnits,
+			MemoryTotal:  memoryTotal,
+			NodeID:       nodeID,
+			PCIE:         pcie,
+			BusID:        busID,
+		})
+	}
+	return devices, nil
+}
+
+func (a *amdGPUDeviceManager) started() bool {
+	return a.start.Load()
+}
+
+func (a *amdGPUDeviceManager) shutdown() error {
+	return nil
+}
+
+func (a *amdGPUDeviceManager) deviceInfos() metriccache.Devices {
+	a.RLock()
+	defer a.RUnlock()
+	gpuDevices := util.GPUDevices{}
+	for _, device := range a.devices {
+		gpuDevices = append(gpuDevices, util.GPUDeviceInfo{
+			UUID:        device.DeviceUUID,
+			Minor:       device.Minor,
+			MemoryTotal: device.MemoryTotal,
+			NodeID:      device.NodeID,
+			PCIE:        device.PCIE,
+			BusID:       device.BusID,
+			Vendor:      util.GPUVendorAMD,
+		})
+	}
+	return gpuDevices
+}
+
+func (a *amdGPUDeviceManager) collectGPUUsage() {
+	deviceMetrics := make([]*rawGPUMetric, len(a.devices))
+	for idx, device := range a.devices {
+		busyPercent, err := system.GetAMDGPUBusyPercent(device.BusID)
+		if err != nil {
+			klog.Warningf("Unable to get busy percent for device %s: %v", device.BusID, err)
+			continue
+		}
+		_, memoryUsed, err := system.GetAMDGPUVRAM(device.BusID)
+		if err != nil {
+			klog.Warningf("Unable to get vram usage for device %s: %v", device.BusID, err)
+			continue
+		}
+		deviceMetrics[idx] = &rawGPUMetric{
+			SMUtil:     uint32(busyPercent),
+			MemoryUsed: memoryUsed,
+		}
+	}
+
+	processUsages, err := system.GetAMDGPUProcessUsages()
+	if err != nil {
+		klog.Warningf("Unable to get process usages of amd gpus: %v", err)
+	}
+	processesGPUUsages := make(map[uint32][]*rawGPUMetric, len(processUsages))
+	for pid, usages := range processUsages {
+		metrics := make([]*rawGPUMetric, len(a.devices))
+		for idx, device := range a.devices {
+			usage, ok := usages[device.GPUID]
+			if !ok {
+				continue
+			}
+			// the share of the occupied compute units is the analog of the sm utilization
+			var cuUtil uint32
+			if device.ComputeUnits > 0 {
+				cuUtil = uint32(usage.CUOccupancy * 100 / device.ComputeUnits)
+				if cuUtil > 100 {
+					cuUtil = 100
+				}
+			}
+			metrics[idx] = &rawGPUMetric{
+				SMUtil:     cuUtil,
+				MemoryUsed: usage.VRAMUsed,
+			}
+		}
+		processesGPUUsages[pid] = metrics
+	}
+	klog.V(5).Infof("Found %d processes on amd gpu devices", len(processesGPUUsages))
+
+	a.Lock()
+	a.deviceMetrics = deviceMetrics
+	a.processesMetrics = processesGPUUsages
+	a.collectTime = time.Now()
+	a.start.Store(true)
+	a.Unlock()
+}
+
+func (a *amdGPUDeviceManager) getNodeGPUUsage() []metriccache.MetricSample {
+	a.RLock()
+	defer a.RUnlock()
+	gpuMetrics := make([]metriccache.MetricSample, 0)
+	for idx, r := range a.deviceMetrics {
+		if r == nil {
+			continue
+		}
+		properties := metriccache.MetricPropertiesFunc.GPU(fmt.Sprintf("%d", a.devices[idx].Minor), a.devices[idx].DeviceUUID)
+		if m := buildMetricSample(metriccache.NodeGPUCoreUsageMetric, properties, a.collectTime, float64(r.SMUtil)); m != nil {
+			gpuMetrics = append(gpuMetrics, m)
+		}
+		if m := buildMetricSample(metriccache.NodeGPUMemUsageMetric, properties, a.collectTime, float64(r.MemoryUsed)); m != nil {
+			gpuMetrics = append(gpuMetrics, m)
+		}
+	}
+	return gpuMetrics
+}
+
+func (a *amdGPUDeviceManager) getPodOrContainerTotalGPUUsageOfPIDs(id string, isPodID bool, pids []uint32) []metriccache.MetricSample {
+	if id == "" {
+		klog.Warning("id is empty")
+		return nil
+	}
+	coreUsageResource, memUsageResource := metriccache.ContainerGPUCoreUsageMetric, metriccache.ContainerGPUMemUsageMetric
+	if isPodID {
+		coreUsageResource, memUsageResource = metriccache.PodGPUCoreUsageMetric, metriccache.PodGPUMemUsageMetric
+	}
+
+	a.RLock()
+	defer a.RUnlock()
+	tmp := make([]*rawGPUMetric, len(a.devices))
+	for _, pid := range pids {
+		for idx, metric := range a.processesMetrics[pid] {
+			if metric == nil {
+				continue
+			}
+			if tmp[idx] == nil {
+				tmp[idx] = &rawGPUMetric{}
+			}
+			tmp[idx].SMUtil += metric.SMUtil
+			tmp[idx].MemoryUsed += metric.MemoryUsed
+		}
+	}
+
+	var rtn []metriccache.MetricSample
+	for idx, value := range tmp {
+		if value == nil {
+			continue
+		}
+		minor, uuid := fmt.Sprintf("%d", a.devices[idx].Minor), a.devices[idx].DeviceUUID
+		properties := metriccache.MetricPropertiesFunc.ContainerGPU(id, minor, uuid)
+		if isPodID {
+			properties = metriccache.MetricPropertiesFunc.PodGPU(id, minor, uuid)
+		}
+		if m := buildMetricSample(coreUsageResource, properties, a.collectTime, float64(value.SMUtil)); m != nil {
+			rtn = append(rtn, m)
+		}
+		if m := buildMetricSample(memUsageResource, properties, a.collectTime, float64(value.MemoryUsed)); m != nil {
+			rtn = append(rtn, m)
+		}
+	}
+	return rtn
+}
+
+func (a *amdGPUDeviceManager) getPodGPUUsage(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	runningContainer := make([]corev1.ContainerStatus, 0)
+	for _, c := range cs {
+		if c.State.Running == nil {
+			klog.V(5).Infof("non-running container %s", c.ContainerID)
+			continue
+		}
+		runningContainer = append(runningContainer, c)
+	}
+	if len(runningContainer) == 0 {
+		return nil, nil
+	}
+	pids, err := util.GetPIDsInPod(podParentDir, runningContainer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pid, error: %v", err)
+	}
+	return a.getPodOrContainerTotalGPUUsageOfPIDs(uid, true, pids), nil
+}
+
+func (a *amdGPUDeviceManager) getContainerGPUUsage(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	if c.State.Running == nil {
+		klog.V(5).Infof("non-running container %s", c.ContainerID)
+		return nil, nil
+	}
+	currentPIDs, err := util.GetPIDsInContainer(podParentDir, c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pid, error: %v", err)
+	}
+	return a.getPodOrContainerTotalGPUUsageOfPIDs(containerID, false, currentPIDs), nil
+}
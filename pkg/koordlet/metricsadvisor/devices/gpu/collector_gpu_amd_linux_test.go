@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func prepareAMDGPUFiles(helper *system.FileTestUtil) {
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/0/gpu_id", "0\n")
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/0/properties", "cpu_cores_count 64\n")
+	// 0000:03:00.0
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/gpu_id", "53902\n")
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/properties",
+		"simd_count 416\nsimd_per_cu 4\nvendor_id 4098\nlocation_id 768\ndomain 0\nunique_id 255\n")
+	// 0000:83:00.0, unique id not supported
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/2/gpu_id", "12345\n")
+	helper.WriteFileContents("class/kfd/kfd/topology/nodes/2/properties",
+		"simd_count 416\nsimd_per_cu 4\nvendor_id 4098\nlocation_id 33536\ndomain 0\nunique_id 0\n")
+
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/numa_node", "0\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/gpu_busy_percent", "60\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/mem_info_vram_total", "68702699520\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/mem_info_vram_used", "3221225472\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/numa_node", "1\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/gpu_busy_percent", "0\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/mem_info_vram_total", "68702699520\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/mem_info_vram_used", "10485760\n")
+
+	helper.WriteFileContents("class/kfd/kfd/proc/122/vram_53902", "1073741824\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/122/stats_53902/cu_occupancy", "52\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/222/vram_53902", "2147483648\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/222/stats_53902/cu_occupancy", "26\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/222/vram_12345", "1048576\n")
+}
+
+func Test_initAMDGPUDeviceManager(t *testing.T) {
+	t.Run("no amd gpu", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		assert.Nil(t, initAMDGPUDeviceManager())
+	})
+	t.Run("init amd gpus", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		prepareAMDGPUFiles(helper)
+		manager := initAMDGPUDeviceManager()
+		assert.NotNil(t, manager)
+		assert.False(t, manager.started())
+		assert.Equal(t, util.GPUDevices{
+			{UUID: "0xff", Minor: 0, MemoryTotal: 68702699520, NodeID: 0, BusID: "0000:03:00.0", Vendor: util.GPUVendorAMD},
+			{UUID: "0000:83:00.0", Minor: 1, MemoryTotal: 68702699520, NodeID: 1, BusID: "0000:83:00.0", Vendor: util.GPUVendorAMD},
+		}, manager.deviceInfos())
+		assert.Equal(t, util.GPUVendorAMD, manager.deviceInfos().(util.GPUDevices).Vendor())
+	})
+}
+
+func Test_amdGPUDeviceManager_collectGPUUsage(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	prepareAMDGPUFiles(helper)
+	manager := initAMDGPUDeviceManager().(*amdGPUDeviceManager)
+	manager.collectGPUUsage()
+	assert.True(t, manager.started())
+
+	collectTime := manager.collectTime
+	gpu0 := metriccache.MetricPropertiesFunc.GPU("0", "0xff")
+	gpu1 := metriccache.MetricPropertiesFunc.GPU("1", "0000:83:00.0")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildMetricSample(metriccache.NodeGPUCoreUsageMetric, gpu0, collectTime, 60),
+		buildMetricSample(metriccache.NodeGPUMemUsageMetric, gpu0, collectTime, 3221225472),
+		buildMetricSample(metriccache.NodeGPUCoreUsageMetric, gpu1, collectTime, 0),
+		buildMetricSample(metriccache.NodeGPUMemUsageMetric, gpu1, collectTime, 10485760),
+	}, manager.getNodeGPUUsage())
+
+	// 104 compute units: pid 122 occupies 50%, pid 222 occupies 25%
+	containerGPU0 := metriccache.MetricPropertiesFunc.ContainerGPU("test-container", "0", "0xff")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildMetricSample(metriccache.ContainerGPUCoreUsageMetric, containerGPU0, collectTime, 50),
+		buildMetricSample(metriccache.ContainerGPUMemUsageMetric, containerGPU0, collectTime, 1073741824),
+	}, manager.getPodOrContainerTotalGPUUsageOfPIDs("test-container", false, []uint32{122}))
+
+	podGPU0 := metriccache.MetricPropertiesFunc.PodGPU("test-pod", "0", "0xff")
+	podGPU1 := metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "0000:83:00.0")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildMetricSample(metriccache.PodGPUCoreUsageMetric, podGPU0, collectTime, 75),
+		buildMetricSample(metriccache.PodGPUMemUsageMetric, podGPU0, collectTime, 3221225472),
+		buildMetricSample(metriccache.PodGPUCoreUsageMetric, podGPU1, collectTime, 0),
+		buildMetricSample(metriccache.PodGPUMemUsageMetric, podGPU1, collectTime, 1048576),
+	}, manager.getPodOrContainerTotalGPUUsageOfPIDs("test-pod", true, []uint32{122, 222, 333}))
+
+	assert.Nil(t, manager.getPodOrContainerTotalGPUUsageOfPIDs("test-pod", true, []uint32{333}))
+	assert.Nil(t, manager.getPodOrContainerTotalGPUUsageOfPIDs("", true, []uint32{122}))
+}
@@ -67,9 +67,17 @@ func initGPUDeviceManager() GPUDeviceManager {
 	if ret := nvml.Init(); ret != nvml.SUCCESS {
 		if ret == nvml.ERROR_LIBRARY_NOT_FOUND {
 			klog.Warning("nvml init failed, library not found")
-			return &dummyDeviceManager{}
+		} else {
+			klog.Warningf("nvml init failed, return %s", nvml.ErrorString(ret))
+		}
+		// fallback to collect the AMD GPUs if no NVIDIA GPU
+		if manager := initAMDGPUDeviceManager(); manager != nil {
+			return manager
+		}
+		// fallback to collect the Ascend NPUs if neither NVIDIA nor AMD GPU
+		if manager := initAscendNPUDeviceManager(); manager != nil {
+			return manager
 		}
-		klog.Warningf("nvml init failed, return %s", nvml.ErrorString(ret))
 		return &dummyDeviceManager{}
 	}
 	manager := &gpuDeviceManager{start: atomic.NewBool(false)}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/helper"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// ascendNPUDeviceManager collects the Huawei Ascend NPU chips as the GPUs, so the DeviceShare can allocate the NPU
+// fractions in the same way. The chips are discovered from the PCI devices and the usages are collected by npu-smi.
+// The per-process usages are not supported yet.
+type ascendNPUDeviceManager struct {
+	sync.RWMutex
+	devices     []*ascendDevice
+	collectTime time.Time
+	start       *atomic.Bool
+	// deviceMetrics is the usage of each device, indexed the same as the devices
+	deviceMetrics []*rawGPUMetric
+}
+
+type ascendDevice struct {
+	Minor       int32 // the npu id starting from 0, ordered by the bus id
+	DeviceUUID  string
+	MemoryTotal uint64
+	NodeID      int32
+	PCIE        string
+	BusID       string
+}
+
+// initAscendNPUDeviceManager returns nil if there is no Ascend NPU on the node.
+func initAscendNPUDeviceManager() GPUDeviceManager {
+	devices, err := initAscendNPUData()
+	if err != nil {
+		klog.Warningf("init ascend npu data failed, error %s", err)
+		return nil
+	}
+	if len(devices) == 0 {
+		klog.V(4).Infof("no ascend npu device found")
+		return nil
+	}
+	return &ascendNPUDeviceManager{
+		devices: devices,
+		start:   atomic.NewBool(false),
+	}
+}
+
+func initAscendNPUData() ([]*ascendDevice, error) {
+	npuInfos, err := system.GetAscendNPUInfos()
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*ascendDevice, 0, len(npuInfos))
+	for i, info := range npuInfos {
+		usage, err := system.GetAscendNPUUsage(i)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get memory of device %s: %w", info.BusID, err)
+		}
+		nodeID, pcie, busID, err := helper.ParsePCIInfo(info.BusID)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, &ascendDevice{
+			Minor: int32(i),
+			// the npu-smi provides no stable serial of the chips, so use the bus id as the uuid
+			DeviceUUID:  info.BusID,
+			MemoryTotal: usage.MemoryTotal,
+			NodeID:      nodeID,
+			PCIE:        pcie,
+			BusID:       busID,
+		})
+	}
+	return devices, nil
+}
+
+func (a *ascendNPUDeviceManager) started() bool {
+	return a.start.Load()
+}
+
+func (a *ascendNPUDeviceManager) shutdown() error {
+	return nil
+}
+
+func (a *ascendNPUDeviceManager) deviceInfos() metriccache.Devices {
+	a.RLock()
+	defer a.RUnlock()
+	gpuDevices := util.GPUDevices{}
+	for _, device := range a.devices {
+		gpuDevices = append(gpuDevices, util.GPUDeviceInfo{
+			UUID:        device.DeviceUUID,
+			Minor:       device.Minor,
+			MemoryTotal: device.MemoryTotal,
+			NodeID:      device.NodeID,
+			PCIE:        device.PCIE,
+			BusID:       device.BusID,
+			Vendor:      util.GPUVendorHuawei,
+		})
+	}
+	return gpuDevices
+}
+
+func (a *ascendNPUDeviceManager) collectGPUUsage() {
+	deviceMetrics := make([]*rawGPUMetric, len(a.devices))
+	for idx, device := range a.devices {
+		usage, err := system.GetAscendNPUUsage(int(device.Minor))
+		if err != nil {
+			klog.Warningf("Unable to get usage for device %s: %v", device.BusID, err)
+			continue
+		}
+		deviceMetrics[idx] = &rawGPUMetric{
+			SMUtil:     uint32(usage.AICoreUtil),
+			MemoryUsed: usage.MemoryUsed,
+		}
+	}
+
+	a.Lock()
+	a.deviceMetrics = deviceMetrics
+	a.collectTime = time.Now()
+	a.start.Store(true)
+	a.Unlock()
+}
+
+func (a *ascendNPUDeviceManager) getNodeGPUUsage() []metriccache.MetricSample {
+	a.RLock()
+	defer a.RUnlock()
+	gpuMetrics := make([]metriccache.MetricSample, 0)
+	for idx, r := range a.deviceMetrics {
+		if r == nil {
+			continue
+		}
+		properties := metriccache.MetricPropertiesFunc.GPU(fmt.Sprintf("%d", a.devices[idx].Minor), a.devices[idx].DeviceUUID)
+		if m := buildMetricSample(metriccache.NodeGPUCoreUsageMetric, properties, a.collectTime, float64(r.SMUtil)); m != nil {
+			gpuMetrics = append(gpuMetrics, m)
+		}
+		if m := buildMetricSample(metriccache.NodeGPUMemUsageMetric, properties, a.collectTime, float64(r.MemoryUsed)); m != nil {
+			gpuMetrics = append(gpuMetrics, m)
+		}
+	}
+	return gpuMetrics
+}
+
+func (a *ascendNPUDeviceManager) getPodGPUUsage(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	klog.V(6).Infof("pod usage of ascend npu is not supported, pod %s", uid)
+	return nil, nil
+}
+
+func (a *ascendNPUDeviceManager) getContainerGPUUsage(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
+	klog.V(6).Infof("container usage of ascend npu is not supported, container %s", containerID)
+	return nil, nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpu
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func prepareAscendNPUFiles(helper *system.FileTestUtil) {
+	for busID, numaNode := range map[string]string{"0000:c1:00.0": "0", "0000:c2:00.0": "1"} {
+		helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/vendor", busID), "0x19e5\n")
+		helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/device", busID), "0xd802\n")
+		helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/class", busID), "0x120000\n")
+		helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/numa_node", busID), numaNode+"\n")
+	}
+}
+
+func fakeNPUSMI(usages map[string]string) func(cmds []string) ([]byte, int, error) {
+	return func(cmds []string) ([]byte, int, error) {
+		if len(cmds) != 6 || cmds[0] != system.AscendNPUSMICmd {
+			return nil, -1, fmt.Errorf("unexpected command %s", strings.Join(cmds, " "))
+		}
+		out, ok := usages[cmds[5]]
+		if !ok {
+			return nil, 1, fmt.Errorf("invalid npu id %s", cmds[5])
+		}
+		return []byte(out), 0, nil
+	}
+}
+
+func Test_initAscendNPUDeviceManager(t *testing.T) {
+	oldExecCmdOnHost := system.ExecCmdOnHost
+	defer func() {
+		system.ExecCmdOnHost = oldExecCmdOnHost
+	}()
+	t.Run("no ascend npu", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		assert.Nil(t, initAscendNPUDeviceManager())
+	})
+	t.Run("npu-smi failed", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		prepareAscendNPUFiles(helper)
+		system.ExecCmdOnHost = fakeNPUSMI(nil)
+		assert.Nil(t, initAscendNPUDeviceManager())
+	})
+	t.Run("init ascend npus", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+		prepareAscendNPUFiles(helper)
+		system.ExecCmdOnHost = fakeNPUSMI(map[string]string{
+			"0": "HBM Capacity(MB) : 65536\nHBM Usage Rate(%) : 0\nAicore Usage Rate(%) : 0\n",
+			"1": "HBM Capacity(MB) : 65536\nHBM Usage Rate(%) : 0\nAicore Usage Rate(%) : 0\n",
+		})
+		manager := initAscendNPUDeviceManager()
+		assert.NotNil(t, manager)
+		assert.False(t, manager.started())
+		assert.Equal(t, util.GPUDevices{
+			{UUID: "0000:c1:00.0", Minor: 0, MemoryTotal: 65536 * 1024 * 1024, NodeID: 0, BusID: "0000:c1:00.0", Vendor: util.GPUVendorHuawei},
+			{UUID: "0000:c2:00.0", Minor: 1, MemoryTotal: 65536 * 1024 * 1024, NodeID: 1, BusID: "0000:c2:00.0", Vendor: util.GPUVendorHuawei},
+		}, manager.deviceInfos())
+		assert.Equal(t, util.GPUVendorHuawei, manager.deviceInfos().(util.GPUDevices).Vendor())
+	})
+}
+
+func Test_ascendNPUDeviceManager_collectGPUUsage(t *testing.T) {
+	oldExecCmdOnHost := system.ExecCmdOnHost
+	defer func() {
+		system.ExecCmdOnHost = oldExecCmdOnHost
+	}()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	prepareAscendNPUFiles(helper)
+	system.ExecCmdOnHost = fakeNPUSMI(map[string]string{
+		"0": "HBM Capacity(MB) : 65536\nHBM Usage Rate(%) : 50\nAicore Usage Rate(%) : 80\n",
+		"1": "HBM Capacity(MB) : 65536\nHBM Usage Rate(%) : 0\nAicore Usage Rate(%) : 0\n",
+	})
+	manager := initAscendNPUDeviceManager().(*ascendNPUDeviceManager)
+	// the chip 1 is lost
+	system.ExecCmdOnHost = fakeNPUSMI(map[string]string{
+		"0": "HBM Capacity(MB) : 65536\nHBM Usage Rate(%) : 50\nAicore Usage Rate(%) : 80\n",
+	})
+	manager.collectGPUUsage()
+	assert.True(t, manager.started())
+
+	npu0 := metriccache.MetricPropertiesFunc.GPU("0", "0000:c1:00.0")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildMetricSample(metriccache.NodeGPUCoreUsageMetric, npu0, manager.collectTime, 80),
+		buildMetricSample(metriccache.NodeGPUMemUsageMetric, npu0, manager.collectTime, 32768*1024*1024),
+	}, manager.getNodeGPUUsage())
+
+	got, err := manager.getPodGPUUsage("test-pod", "", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+}
@@ -17,25 +17,72 @@ limitations under the License.
 package rdma
 
 import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 const (
 	DeviceCollectorName = "RDMA"
 )
 
+var (
+	timeNow = time.Now
+)
+
+// rdmaCollector reports the RDMA net devices, and collects the bandwidth of the RDMA device ports from the traffic
+// counters in the sysfs.
+// Since the RDMA cgroup controller only accounts the HCA handles and objects rather than the traffic, the bandwidth
+// of a device is attributed to the pods in proportion to the HCA objects they charge in the `rdma.current`, which is
+// an estimation for the pods sharing the device.
 type rdmaCollector struct {
-	enabled bool
+	enabled         bool
+	collectInterval time.Duration
+	cgroupReader    resourceexecutor.CgroupReader
+
+	started *atomic.Bool
+
+	lock sync.RWMutex
+	// lastCounters is the last traffic counters of the ports, keyed by the device and the port
+	lastCounters map[rdmaPortKey]system.RDMAPortCounters
+	lastTime     time.Time
+	// portUsages is the bandwidth of the ports calculated in the latest collection
+	portUsages []rdmaPortUsage
+	// podsResources is the RDMA resources charged to all pods of the latest collection, keyed by the device
+	podsResources map[string]system.RDMAResourceCount
+	collectTime   time.Time
+}
+
+type rdmaPortKey struct {
+	device string
+	port   string
+}
+
+type rdmaPortUsage struct {
+	rdmaPortKey
+	// txBandwidth and rxBandwidth are in bytes per second
+	txBandwidth float64
+	rxBandwidth float64
 }
 
 func New(opt *framework.Options) framework.DeviceCollector {
 	return &rdmaCollector{
-		enabled: features.DefaultKoordletFeatureGate.Enabled(features.RDMADevices),
+		enabled:         features.DefaultKoordletFeatureGate.Enabled(features.RDMADevices),
+		collectInterval: opt.Config.CollectResUsedInterval,
+		cgroupReader:    opt.CgroupReader,
+		started:         atomic.NewBool(false),
 	}
 }
 
@@ -50,10 +97,11 @@ func (g *rdmaCollector) Setup(fra *framework.Context) {
 }
 
 func (g *rdmaCollector) Run(stopCh <-chan struct{}) {
+	go wait.Until(g.collectRDMAUsage, g.collectInterval, stopCh)
 }
 
 func (g *rdmaCollector) Started() bool {
-	return true
+	return g.started.Load()
 }
 
 func (g *rdmaCollector) Infos() metriccache.Devices {
@@ -65,13 +113,129 @@ func (g *rdmaCollector) Infos() metriccache.Devices {
 }
 
 func (g *rdmaCollector) GetNodeMetric() ([]metriccache.MetricSample, error) {
-	return nil, nil
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	samples := make([]metriccache.MetricSample, 0, 2*len(g.portUsages))
+	for _, usage := range g.portUsages {
+		properties := metriccache.MetricPropertiesFunc.RDMAPort(usage.device, usage.port)
+		samples = appendMetricSample(samples, metriccache.NodeRDMATxBandwidthMetric, properties, g.collectTime, usage.txBandwidth)
+		samples = appendMetricSample(samples, metriccache.NodeRDMARxBandwidthMetric, properties, g.collectTime, usage.rxBandwidth)
+	}
+	return samples, nil
 }
 
 func (g *rdmaCollector) GetPodMetric(uid, podParentDir string, cs []corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
-	return nil, nil
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if len(g.portUsages) == 0 || len(g.podsResources) == 0 {
+		return nil, nil
+	}
+	podResources, err := g.cgroupReader.ReadRDMACurrent(podParentDir)
+	if err != nil {
+		return nil, err
+	}
+
+	deviceTx, deviceRx := map[string]float64{}, map[string]float64{}
+	for _, usage := range g.portUsages {
+		deviceTx[usage.device] += usage.txBandwidth
+		deviceRx[usage.device] += usage.rxBandwidth
+	}
+	var samples []metriccache.MetricSample
+	for device, count := range podResources {
+		total, ok := g.podsResources[device]
+		if !ok || total.HCAObject <= 0 || count.HCAObject <= 0 {
+			continue
+		}
+		if _, ok = deviceTx[device]; !ok {
+			continue
+		}
+		ratio := float64(count.HCAObject) / float64(total.HCAObject)
+		if ratio > 1 {
+			ratio = 1
+		}
+		properties := metriccache.MetricPropertiesFunc.PodRDMA(uid, device)
+		samples = appendMetricSample(samples, metriccache.PodRDMATxBandwidthMetric, properties, g.collectTime, deviceTx[device]*ratio)
+		samples = appendMetricSample(samples, metriccache.PodRDMARxBandwidthMetric, properties, g.collectTime, deviceRx[device]*ratio)
+	}
+	return samples, nil
 }
 
 func (g *rdmaCollector) GetContainerMetric(containerID, podParentDir string, c *corev1.ContainerStatus) ([]metriccache.MetricSample, error) {
 	return nil, nil
 }
+
+func (g *rdmaCollector) collectRDMAUsage() {
+	devices, err := system.GetRDMADevices()
+	if err != nil {
+		klog.Warningf("failed to get rdma devices, err: %v", err)
+		return
+	}
+	collectTime := timeNow()
+	counters := map[rdmaPortKey]system.RDMAPortCounters{}
+	for _, device := range devices {
+		portCounters, err := system.GetRDMAPortCounters(device)
+		if err != nil {
+			klog.V(4).Infof("failed to get counters of rdma device %s, err: %v", device, err)
+			continue
+		}
+		for _, c := range portCounters {
+			counters[rdmaPortKey{device: c.Device, port: c.Port}] = c
+		}
+	}
+
+	var podsResources map[string]system.RDMAResourceCount
+	if len(counters) > 0 {
+		podsResources, err = g.cgroupReader.ReadRDMACurrent(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+		if err != nil {
+			klog.V(5).Infof("failed to read rdma resources of pods, err: %v", err)
+		}
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.portUsages = calculatePortUsages(g.lastCounters, counters, collectTime.Sub(g.lastTime))
+	g.lastCounters = counters
+	g.lastTime = collectTime
+	g.podsResources = podsResources
+	g.collectTime = collectTime
+	g.started.Store(true)
+	klog.V(6).Infof("collect rdma usage finished, device num %d, port num %d", len(devices), len(counters))
+}
+
+// calculatePortUsages calculates the bandwidth of the ports with the counters of two collections.
+// The ports newly found or whose counters are reset are skipped.
+func calculatePortUsages(lastCounters, counters map[rdmaPortKey]system.RDMAPortCounters, duration time.Duration) []rdmaPortUsage {
+	if duration <= 0 {
+		return nil
+	}
+	seconds := duration.Seconds()
+	usages := make([]rdmaPortUsage, 0, len(counters))
+	for key, c := range counters {
+		last, ok := lastCounters[key]
+		if !ok || c.TxBytes < last.TxBytes || c.RxBytes < last.RxBytes {
+			continue
+		}
+		usages = append(usages, rdmaPortUsage{
+			rdmaPortKey: key,
+			txBandwidth: float64(c.TxBytes-last.TxBytes) / seconds,
+			rxBandwidth: float64(c.RxBytes-last.RxBytes) / seconds,
+		})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].device != usages[j].device {
+			return usages[i].device < usages[j].device
+		}
+		return usages[i].port < usages[j].port
+	})
+	return usages
+}
+
+func appendMetricSample(samples []metriccache.MetricSample, mr metriccache.MetricResource,
+	properties map[metriccache.MetricProperty]string, t time.Time, val float64) []metriccache.MetricSample {
+	m, err := mr.GenerateSample(properties, t, val)
+	if err != nil {
+		klog.Errorf("GenerateSample(%v) error: %v", mr, err)
+		return samples
+	}
+	return append(samples, m)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rdma
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func writePortCounters(helper *system.FileTestUtil, device, port, xmitData, rcvData string) {
+	helper.WriteFileContents("class/infiniband/"+device+"/ports/"+port+"/counters/port_xmit_data", xmitData)
+	helper.WriteFileContents("class/infiniband/"+device+"/ports/"+port+"/counters/port_rcv_data", rcvData)
+}
+
+func buildSample(t *testing.T, mr metriccache.MetricResource, properties map[metriccache.MetricProperty]string, ts time.Time, val float64) metriccache.MetricSample {
+	s, err := mr.GenerateSample(properties, ts, val)
+	assert.NoError(t, err)
+	return s
+}
+
+func Test_rdmaCollector_collectRDMAUsage(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	podDir := filepath.Join(util.GetPodQoSRelativePath(corev1.PodQOSBestEffort), "pod-test")
+	helper.SetResourcesSupported(true, system.RDMACurrent)
+	helper.WriteCgroupFileContents(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed), system.RDMACurrent, "mlx5_0 hca_handle=4 hca_object=400\nmlx5_1 hca_handle=1 hca_object=0\n")
+	helper.WriteCgroupFileContents(podDir, system.RDMACurrent, "mlx5_0 hca_handle=1 hca_object=100\nmlx5_1 hca_handle=1 hca_object=0\n")
+	writePortCounters(helper, "mlx5_0", "1", "0", "0")
+	writePortCounters(helper, "mlx5_0", "2", "1000", "1000")
+	writePortCounters(helper, "mlx5_1", "1", "1000", "1000")
+
+	now := time.Now()
+	timeNow = func() time.Time {
+		return now
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+	c := &rdmaCollector{
+		cgroupReader: resourceexecutor.NewCgroupReader(),
+		started:      atomic.NewBool(false),
+	}
+	assert.False(t, c.Started())
+
+	// no bandwidth at the first collection
+	c.collectRDMAUsage()
+	assert.True(t, c.Started())
+	samples, err := c.GetNodeMetric()
+	assert.NoError(t, err)
+	assert.Empty(t, samples)
+	samples, err = c.GetPodMetric("test-pod", podDir, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, samples)
+
+	// 10 seconds later, mlx5_0 port 1 transmits 40000 bytes and receives 80000 bytes, port 2 transmits 4000 bytes,
+	// and the counters of mlx5_1 are reset
+	now = now.Add(10 * time.Second)
+	writePortCounters(helper, "mlx5_0", "1", "10000", "20000")
+	writePortCounters(helper, "mlx5_0", "2", "2000", "1000")
+	writePortCounters(helper, "mlx5_1", "1", "0", "0")
+	c.collectRDMAUsage()
+
+	samples, err = c.GetNodeMetric()
+	assert.NoError(t, err)
+	port1, port2 := metriccache.MetricPropertiesFunc.RDMAPort("mlx5_0", "1"), metriccache.MetricPropertiesFunc.RDMAPort("mlx5_0", "2")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildSample(t, metriccache.NodeRDMATxBandwidthMetric, port1, now, 4000),
+		buildSample(t, metriccache.NodeRDMARxBandwidthMetric, port1, now, 8000),
+		buildSample(t, metriccache.NodeRDMATxBandwidthMetric, port2, now, 400),
+		buildSample(t, metriccache.NodeRDMARxBandwidthMetric, port2, now, 0),
+	}, samples)
+
+	// the pod charges 1/4 hca objects of mlx5_0
+	samples, err = c.GetPodMetric("test-pod", podDir, nil)
+	assert.NoError(t, err)
+	podProperties := metriccache.MetricPropertiesFunc.PodRDMA("test-pod", "mlx5_0")
+	assert.Equal(t, []metriccache.MetricSample{
+		buildSample(t, metriccache.PodRDMATxBandwidthMetric, podProperties, now, 1100),
+		buildSample(t, metriccache.PodRDMARxBandwidthMetric, podProperties, now, 2000),
+	}, samples)
+
+	_, err = c.GetPodMetric("test-pod-not-exist", filepath.Join(util.GetPodQoSRelativePath(corev1.PodQOSBestEffort), "pod-not-exist"), nil)
+	assert.Error(t, err)
+}
+
+func Test_calculatePortUsages(t *testing.T) {
+	key := rdmaPortKey{device: "mlx5_0", port: "1"}
+	last := map[rdmaPortKey]system.RDMAPortCounters{
+		key: {Device: "mlx5_0", Port: "1", TxBytes: 100, RxBytes: 100},
+	}
+	current := map[rdmaPortKey]system.RDMAPortCounters{
+		key:                           {Device: "mlx5_0", Port: "1", TxBytes: 300, RxBytes: 500},
+		{device: "mlx5_1", port: "1"}: {Device: "mlx5_1", Port: "1", TxBytes: 300, RxBytes: 500},
+	}
+	assert.Nil(t, calculatePortUsages(last, current, 0))
+	assert.Equal(t, []rdmaPortUsage{
+		{rdmaPortKey: key, txBandwidth: 100, rxBandwidth: 200},
+	}, calculatePortUsages(last, current, 2*time.Second))
+}
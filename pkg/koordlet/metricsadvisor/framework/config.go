@@ -19,6 +19,8 @@ package framework
 import (
 	"flag"
 	"time"
+
+	cliflag "k8s.io/component-base/cli/flag"
 )
 
 const (
@@ -36,8 +38,18 @@ type Config struct {
 	CPICollectorTimeWindow           time.Duration
 	ColdPageCollectorInterval        time.Duration
 	ResctrlCollectorInterval         time.Duration
+	SchedLatencyCollectorInterval    time.Duration
+	DCGMCollectorInterval            time.Duration
+	DCGMExporterEndpoint             string
+	PowerCollectorInterval           time.Duration
+	CPUThermalCollectorInterval      time.Duration
+	NetworkLatencyProberInterval     time.Duration
+	NetworkLatencyProbeTimeout       time.Duration
+	NetworkLatencySLOThreshold       time.Duration
+	NetworkLatencyProbeEndpoints     map[string]string
 	EnablePageCacheCollector         bool
 	EnableResctrlCollector           bool
+	EnablePodResctrlMonGroup         bool
 }
 
 func NewDefaultConfig() *Config {
@@ -51,8 +63,17 @@ func NewDefaultConfig() *Config {
 		CPICollectorTimeWindow:           10 * time.Second,
 		ColdPageCollectorInterval:        5 * time.Second,
 		ResctrlCollectorInterval:         10 * time.Second,
+		SchedLatencyCollectorInterval:    10 * time.Second,
+		DCGMCollectorInterval:            10 * time.Second,
+		DCGMExporterEndpoint:             "http://127.0.0.1:9400/metrics",
+		PowerCollectorInterval:           10 * time.Second,
+		CPUThermalCollectorInterval:      10 * time.Second,
+		NetworkLatencyProberInterval:     10 * time.Second,
+		NetworkLatencyProbeTimeout:       1 * time.Second,
+		NetworkLatencySLOThreshold:       10 * time.Millisecond,
 		EnablePageCacheCollector:         false,
 		EnableResctrlCollector:           false,
+		EnablePodResctrlMonGroup:         false,
 	}
 }
 
@@ -67,5 +88,15 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.ColdPageCollectorInterval, "coldpage-collector-interval", c.ColdPageCollectorInterval, "Collect cold page interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 	fs.BoolVar(&c.EnablePageCacheCollector, "enable-pagecache-collector", c.EnablePageCacheCollector, "Enable cache collector of node, pods and containers")
 	fs.BoolVar(&c.EnableResctrlCollector, "enable-resctrl-collector", c.EnableResctrlCollector, "Enable cache collector of node, pods and containers")
+	fs.BoolVar(&c.EnablePodResctrlMonGroup, "enable-pod-resctrl-mon-group", c.EnablePodResctrlMonGroup, "Enable creating resctrl mon_groups for pods to collect the pod-level llc occupancy and memory bandwidth. It consumes the limited RMIDs of the node.")
+	fs.DurationVar(&c.SchedLatencyCollectorInterval, "sched-latency-collector-interval", c.SchedLatencyCollectorInterval, "Collect sched latency interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.DCGMCollectorInterval, "dcgm-collector-interval", c.DCGMCollectorInterval, "Collect dcgm metrics interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.StringVar(&c.DCGMExporterEndpoint, "dcgm-exporter-endpoint", c.DCGMExporterEndpoint, "The metrics endpoint of the dcgm-exporter running on the node.")
+	fs.DurationVar(&c.PowerCollectorInterval, "power-collector-interval", c.PowerCollectorInterval, "Collect node power interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.CPUThermalCollectorInterval, "cpu-thermal-collector-interval", c.CPUThermalCollectorInterval, "Collect cpu frequency and thermal throttling interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.NetworkLatencyProberInterval, "network-latency-prober-interval", c.NetworkLatencyProberInterval, "Probe pod network latency interval. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
+	fs.DurationVar(&c.NetworkLatencyProbeTimeout, "network-latency-probe-timeout", c.NetworkLatencyProbeTimeout, "The timeout of a pod network latency probe, after which the probe is considered failed.")
+	fs.DurationVar(&c.NetworkLatencySLOThreshold, "network-latency-slo-threshold", c.NetworkLatencySLOThreshold, "The round-trip time above which a pod network latency probe is considered violating the latency SLO.")
+	fs.Var(cliflag.NewMapStringString(&c.NetworkLatencyProbeEndpoints), "network-latency-probe-endpoints", "A set of QoS=ip:port pairs that describe the TCP endpoints to probe from the pods of each QoS class, e.g. LS=10.96.0.10:53,BE=10.96.0.10:53.")
 	fs.DurationVar(&c.ResctrlCollectorInterval, "resctrl-collector-interval", c.ResctrlCollectorInterval, "Collect cpi time window. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h).")
 }
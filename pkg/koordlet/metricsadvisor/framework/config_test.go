@@ -35,6 +35,14 @@ func Test_NewDefaultConfig(t *testing.T) {
 		CPICollectorTimeWindow:           10 * time.Second,
 		ColdPageCollectorInterval:        5 * time.Second,
 		ResctrlCollectorInterval:         10 * time.Second,
+		SchedLatencyCollectorInterval:    10 * time.Second,
+		DCGMCollectorInterval:            10 * time.Second,
+		DCGMExporterEndpoint:             "http://127.0.0.1:9400/metrics",
+		PowerCollectorInterval:           10 * time.Second,
+		CPUThermalCollectorInterval:      10 * time.Second,
+		NetworkLatencyProberInterval:     10 * time.Second,
+		NetworkLatencyProbeTimeout:       1 * time.Second,
+		NetworkLatencySLOThreshold:       10 * time.Millisecond,
 		EnablePageCacheCollector:         false,
 	}
 	defaultConfig := NewDefaultConfig()
@@ -53,6 +61,15 @@ func Test_InitFlags(t *testing.T) {
 		"--collect-cpi-timewindow=15s",
 		"--coldpage-collector-interval=15s",
 		"--resctrl-collector-interval=90s",
+		"--sched-latency-collector-interval=20s",
+		"--dcgm-collector-interval=30s",
+		"--dcgm-exporter-endpoint=http://localhost:9401/metrics",
+		"--power-collector-interval=15s",
+		"--cpu-thermal-collector-interval=15s",
+		"--network-latency-prober-interval=20s",
+		"--network-latency-probe-timeout=2s",
+		"--network-latency-slo-threshold=5ms",
+		"--network-latency-probe-endpoints=LS=10.96.0.10:53,BE=10.96.0.11:80",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -66,6 +83,15 @@ func Test_InitFlags(t *testing.T) {
 		CPICollectorTimeWindow           time.Duration
 		ColdPageCollectorInterval        time.Duration
 		ResctrlCollectorInterval         time.Duration
+		SchedLatencyCollectorInterval    time.Duration
+		DCGMCollectorInterval            time.Duration
+		DCGMExporterEndpoint             string
+		PowerCollectorInterval           time.Duration
+		CPUThermalCollectorInterval      time.Duration
+		NetworkLatencyProberInterval     time.Duration
+		NetworkLatencyProbeTimeout       time.Duration
+		NetworkLatencySLOThreshold       time.Duration
+		NetworkLatencyProbeEndpoints     map[string]string
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -87,6 +113,15 @@ func Test_InitFlags(t *testing.T) {
 				CPICollectorTimeWindow:           15 * time.Second,
 				ColdPageCollectorInterval:        15 * time.Second,
 				ResctrlCollectorInterval:         90 * time.Second,
+				SchedLatencyCollectorInterval:    20 * time.Second,
+				DCGMCollectorInterval:            30 * time.Second,
+				DCGMExporterEndpoint:             "http://localhost:9401/metrics",
+				PowerCollectorInterval:           15 * time.Second,
+				CPUThermalCollectorInterval:      15 * time.Second,
+				NetworkLatencyProberInterval:     20 * time.Second,
+				NetworkLatencyProbeTimeout:       2 * time.Second,
+				NetworkLatencySLOThreshold:       5 * time.Millisecond,
+				NetworkLatencyProbeEndpoints:     map[string]string{"LS": "10.96.0.10:53", "BE": "10.96.0.11:80"},
 			},
 			args: args{fs: fs},
 		},
@@ -103,6 +138,15 @@ func Test_InitFlags(t *testing.T) {
 				CPICollectorTimeWindow:           tt.fields.CPICollectorTimeWindow,
 				ColdPageCollectorInterval:        tt.fields.ColdPageCollectorInterval,
 				ResctrlCollectorInterval:         tt.fields.ResctrlCollectorInterval,
+				SchedLatencyCollectorInterval:    tt.fields.SchedLatencyCollectorInterval,
+				DCGMCollectorInterval:            tt.fields.DCGMCollectorInterval,
+				DCGMExporterEndpoint:             tt.fields.DCGMExporterEndpoint,
+				PowerCollectorInterval:           tt.fields.PowerCollectorInterval,
+				CPUThermalCollectorInterval:      tt.fields.CPUThermalCollectorInterval,
+				NetworkLatencyProberInterval:     tt.fields.NetworkLatencyProberInterval,
+				NetworkLatencyProbeTimeout:       tt.fields.NetworkLatencyProbeTimeout,
+				NetworkLatencySLOThreshold:       tt.fields.NetworkLatencySLOThreshold,
+				NetworkLatencyProbeEndpoints:     tt.fields.NetworkLatencyProbeEndpoints,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
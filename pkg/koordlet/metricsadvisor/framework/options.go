@@ -17,6 +17,9 @@ limitations under the License.
 package framework
 
 import (
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
@@ -28,4 +31,6 @@ type Options struct {
 	MetricCache    metriccache.MetricCache
 	CgroupReader   resourceexecutor.CgroupReader
 	PodFilters     map[string]PodFilter
+	EventRecorder  record.EventRecorder
+	KubeClient     clientset.Interface
 }
@@ -20,6 +20,8 @@ import (
 	"time"
 
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
@@ -38,13 +40,16 @@ type metricAdvisor struct {
 	context *framework.Context
 }
 
-func NewMetricAdvisor(cfg *framework.Config, statesInformer statesinformer.StatesInformer, metricCache metriccache.MetricCache) MetricAdvisor {
+func NewMetricAdvisor(cfg *framework.Config, statesInformer statesinformer.StatesInformer, metricCache metriccache.MetricCache,
+	kubeClient clientset.Interface, eventRecorder record.EventRecorder) MetricAdvisor {
 	opt := &framework.Options{
 		Config:         cfg,
 		StatesInformer: statesInformer,
 		MetricCache:    metricCache,
 		CgroupReader:   resourceexecutor.NewCgroupReader(),
 		PodFilters:     podFilters,
+		EventRecorder:  eventRecorder,
+		KubeClient:     kubeClient,
 	}
 	ctx := &framework.Context{
 		DeviceCollectors: make(map[string]framework.DeviceCollector, len(devicePlugins)),
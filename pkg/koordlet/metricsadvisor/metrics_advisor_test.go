@@ -54,7 +54,7 @@ func TestNewCollector(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewMetricAdvisor(tt.args.cfg, tt.args.statesInformer, tt.args.metricCache); got == nil {
+			if got := NewMetricAdvisor(tt.args.cfg, tt.args.statesInformer, tt.args.metricCache, nil, nil); got == nil {
 				t.Errorf("NewMetricAdvisor() = %v", got)
 			}
 		})
@@ -203,7 +203,7 @@ unevictable 0
 			ci := NewMetricAdvisor(&framework.Config{
 				CollectResUsedInterval:     1 * time.Second,
 				CollectNodeCPUInfoInterval: 1 * time.Second,
-			}, statesInformer, metricCache)
+			}, statesInformer, metricCache, nil, nil)
 			c := ci.(*metricAdvisor)
 			c.context.State.UpdateNodeUsage(metriccache.Point{Timestamp: time.Now(), Value: 1},
 				metriccache.Point{Timestamp: time.Now(), Value: 1024})
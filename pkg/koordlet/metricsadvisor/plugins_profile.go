@@ -18,17 +18,25 @@ package metricsadvisor
 
 import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/beresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/blkio"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/coldmemoryresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/cputhermal"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/hostapplication"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/netlatency"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodeinfo"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/noderesource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/nodestorageinfo"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/pagecache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/performance"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podnetwork"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podresource"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/podthrottled"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/power"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/psi"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/resctrl"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/schedlatency"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/collectors/sysresource"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/dcgm"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/devices/rdma"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
@@ -40,6 +48,7 @@ var (
 	devicePlugins = map[string]framework.DeviceFactory{
 		gpu.DeviceCollectorName:  gpu.New,
 		rdma.DeviceCollectorName: rdma.New,
+		dcgm.DeviceCollectorName: dcgm.New,
 	}
 
 	collectorPlugins = map[string]framework.CollectorFactory{
@@ -50,15 +59,25 @@ var (
 		podresource.CollectorName:        podresource.New,
 		podthrottled.CollectorName:       podthrottled.New,
 		performance.CollectorName:        performance.New,
+		psi.CollectorName:                psi.New,
 		sysresource.CollectorName:        sysresource.New,
 		coldmemoryresource.CollectorName: coldmemoryresource.New,
 		pagecache.CollectorName:          pagecache.New,
 		hostapplication.CollectorName:    hostapplication.New,
 		resctrl.CollectorName:            resctrl.New,
+		schedlatency.CollectorName:       schedlatency.New,
+		blkio.CollectorName:              blkio.New,
+		podnetwork.CollectorName:         podnetwork.New,
+		power.CollectorName:              power.New,
+		cputhermal.CollectorName:         cputhermal.New,
+		netlatency.CollectorName:         netlatency.New,
 	}
 
 	podFilters = map[string]framework.PodFilter{
 		podresource.CollectorName:  framework.DefaultPodFilter,
 		podthrottled.CollectorName: framework.DefaultPodFilter,
+		blkio.CollectorName:        framework.DefaultPodFilter,
+		podnetwork.CollectorName:   framework.DefaultPodFilter,
+		netlatency.CollectorName:   framework.DefaultPodFilter,
 	}
 )
@@ -22,11 +22,17 @@ import (
 
 type Config struct {
 	ReconcileIntervalSeconds   int
+	CgroupReconcileWorkers     int
 	CPUSuppressIntervalSeconds int
 	CPUEvictIntervalSeconds    int
 	MemoryEvictIntervalSeconds int
 	MemoryEvictCoolTimeSeconds int
 	CPUEvictCoolTimeSeconds    int
+	DiskQuotaIntervalSeconds   int
+	NetQoSIntervalSeconds      int
+	IOCostIntervalSeconds      int
+	PSIEvictIntervalSeconds    int
+	PSIEvictCoolTimeSeconds    int
 	OnlyEvictByAPI             bool
 	QOSExtensionCfg            *QOSExtensionConfig
 }
@@ -34,11 +40,17 @@ type Config struct {
 func NewDefaultConfig() *Config {
 	return &Config{
 		ReconcileIntervalSeconds:   1,
+		CgroupReconcileWorkers:     1,
 		CPUSuppressIntervalSeconds: 1,
 		CPUEvictIntervalSeconds:    1,
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		DiskQuotaIntervalSeconds:   10,
+		NetQoSIntervalSeconds:      10,
+		IOCostIntervalSeconds:      5,
+		PSIEvictIntervalSeconds:    5,
+		PSIEvictCoolTimeSeconds:    60,
 		OnlyEvictByAPI:             false,
 		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
@@ -46,11 +58,17 @@ func NewDefaultConfig() *Config {
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.ReconcileIntervalSeconds, "reconcile-interval-seconds", c.ReconcileIntervalSeconds, "reconcile be pod cgroup interval by seconds")
+	fs.IntVar(&c.CgroupReconcileWorkers, "cgroup-reconcile-workers", c.CgroupReconcileWorkers, "the number of workers to reconcile pod cgroups in parallel, the pod-level and container-level cgroups of a pod are always reconciled in order by one worker")
 	fs.IntVar(&c.CPUSuppressIntervalSeconds, "cpu-suppress-interval-seconds", c.CPUSuppressIntervalSeconds, "suppress be pod cpu resource interval by seconds")
 	fs.IntVar(&c.CPUEvictIntervalSeconds, "cpu-evict-interval-seconds", c.CPUEvictIntervalSeconds, "evict be pod(cpu) interval by seconds")
 	fs.IntVar(&c.MemoryEvictIntervalSeconds, "memory-evict-interval-seconds", c.MemoryEvictIntervalSeconds, "evict be pod(memory) interval by seconds")
 	fs.IntVar(&c.MemoryEvictCoolTimeSeconds, "memory-evict-cool-time-seconds", c.MemoryEvictCoolTimeSeconds, "cooling time: memory next evict time should after lastEvictTime + MemoryEvictCoolTimeSeconds")
 	fs.IntVar(&c.CPUEvictCoolTimeSeconds, "cpu-evict-cool-time-seconds", c.CPUEvictCoolTimeSeconds, "cooltime: CPU next evict time should after lastEvictTime + CPUEvictCoolTimeSeconds")
+	fs.IntVar(&c.DiskQuotaIntervalSeconds, "disk-quota-interval-seconds", c.DiskQuotaIntervalSeconds, "reconcile be pod disk quota and evict the pods exceeding the quota interval by seconds")
+	fs.IntVar(&c.NetQoSIntervalSeconds, "net-qos-interval-seconds", c.NetQoSIntervalSeconds, "reconcile the egress bandwidth limits of the pod qos classes interval by seconds")
+	fs.IntVar(&c.IOCostIntervalSeconds, "io-cost-interval-seconds", c.IOCostIntervalSeconds, "reconcile the blk-iocost and the io weights of the pod qos classes by the disk latency interval by seconds")
+	fs.IntVar(&c.PSIEvictIntervalSeconds, "psi-evict-interval-seconds", c.PSIEvictIntervalSeconds, "evict be pod by the pressure stall information of the node and the ls pods interval by seconds")
+	fs.IntVar(&c.PSIEvictCoolTimeSeconds, "psi-evict-cool-time-seconds", c.PSIEvictCoolTimeSeconds, "cooling time: psi next evict time should after lastEvictTime + PSIEvictCoolTimeSeconds")
 	fs.BoolVar(&c.OnlyEvictByAPI, "only-evict-by-api", c.OnlyEvictByAPI, "only evict pod if call eviction api successed")
 	c.QOSExtensionCfg.InitFlags(fs)
 }
@@ -26,11 +26,17 @@ import (
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
 		ReconcileIntervalSeconds:   1,
+		CgroupReconcileWorkers:     1,
 		CPUSuppressIntervalSeconds: 1,
 		CPUEvictIntervalSeconds:    1,
 		MemoryEvictIntervalSeconds: 1,
 		MemoryEvictCoolTimeSeconds: 4,
 		CPUEvictCoolTimeSeconds:    20,
+		DiskQuotaIntervalSeconds:   10,
+		NetQoSIntervalSeconds:      10,
+		IOCostIntervalSeconds:      5,
+		PSIEvictIntervalSeconds:    5,
+		PSIEvictCoolTimeSeconds:    60,
 		OnlyEvictByAPI:             false,
 		QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{}},
 	}
@@ -42,11 +48,17 @@ func Test_InitFlags(t *testing.T) {
 	cmdArgs := []string{
 		"",
 		"--reconcile-interval-seconds=2",
+		"--cgroup-reconcile-workers=4",
 		"--cpu-suppress-interval-seconds=2",
 		"--cpu-evict-interval-seconds=2",
 		"--memory-evict-interval-seconds=2",
 		"--memory-evict-cool-time-seconds=8",
 		"--cpu-evict-cool-time-seconds=40",
+		"--disk-quota-interval-seconds=20",
+		"--net-qos-interval-seconds=20",
+		"--io-cost-interval-seconds=10",
+		"--psi-evict-interval-seconds=10",
+		"--psi-evict-cool-time-seconds=120",
 		"--qos-extension-plugins=test-plugin=true",
 		"--only-evict-by-api=false",
 	}
@@ -54,11 +66,17 @@ func Test_InitFlags(t *testing.T) {
 
 	type fields struct {
 		ReconcileIntervalSeconds   int
+		CgroupReconcileWorkers     int
 		CPUSuppressIntervalSeconds int
 		CPUEvictIntervalSeconds    int
 		MemoryEvictIntervalSeconds int
 		MemoryEvictCoolTimeSeconds int
 		CPUEvictCoolTimeSeconds    int
+		DiskQuotaIntervalSeconds   int
+		NetQoSIntervalSeconds      int
+		IOCostIntervalSeconds      int
+		PSIEvictIntervalSeconds    int
+		PSIEvictCoolTimeSeconds    int
 		OnlyEvictByAPI             bool
 		QOSExtensionCfg            *QOSExtensionConfig
 	}
@@ -74,11 +92,17 @@ func Test_InitFlags(t *testing.T) {
 			name: "not default",
 			fields: fields{
 				ReconcileIntervalSeconds:   2,
+				CgroupReconcileWorkers:     4,
 				CPUSuppressIntervalSeconds: 2,
 				CPUEvictIntervalSeconds:    2,
 				MemoryEvictIntervalSeconds: 2,
 				MemoryEvictCoolTimeSeconds: 8,
 				CPUEvictCoolTimeSeconds:    40,
+				DiskQuotaIntervalSeconds:   20,
+				NetQoSIntervalSeconds:      20,
+				IOCostIntervalSeconds:      10,
+				PSIEvictIntervalSeconds:    10,
+				PSIEvictCoolTimeSeconds:    120,
 				OnlyEvictByAPI:             false,
 				QOSExtensionCfg:            &QOSExtensionConfig{FeatureGates: map[string]bool{"test-plugin": true}},
 			},
@@ -89,11 +113,17 @@ func Test_InitFlags(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			raw := &Config{
 				ReconcileIntervalSeconds:   tt.fields.ReconcileIntervalSeconds,
+				CgroupReconcileWorkers:     tt.fields.CgroupReconcileWorkers,
 				CPUSuppressIntervalSeconds: tt.fields.CPUSuppressIntervalSeconds,
 				CPUEvictIntervalSeconds:    tt.fields.CPUEvictIntervalSeconds,
 				MemoryEvictIntervalSeconds: tt.fields.MemoryEvictIntervalSeconds,
 				MemoryEvictCoolTimeSeconds: tt.fields.MemoryEvictCoolTimeSeconds,
 				CPUEvictCoolTimeSeconds:    tt.fields.CPUEvictCoolTimeSeconds,
+				DiskQuotaIntervalSeconds:   tt.fields.DiskQuotaIntervalSeconds,
+				NetQoSIntervalSeconds:      tt.fields.NetQoSIntervalSeconds,
+				IOCostIntervalSeconds:      tt.fields.IOCostIntervalSeconds,
+				PSIEvictIntervalSeconds:    tt.fields.PSIEvictIntervalSeconds,
+				PSIEvictCoolTimeSeconds:    tt.fields.PSIEvictCoolTimeSeconds,
 				OnlyEvictByAPI:             tt.fields.OnlyEvictByAPI,
 				QOSExtensionCfg:            tt.fields.QOSExtensionCfg,
 			}
@@ -27,11 +27,13 @@ import (
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	expireCache "github.com/koordinator-sh/koordinator/pkg/util/cache"
+	"github.com/koordinator-sh/koordinator/pkg/util/evictionbudget"
 )
 
 type Context struct {
@@ -45,6 +47,8 @@ type Evictor struct {
 	podsEvicted   *expireCache.Cache
 	evictVersion  string
 	started       atomic.Bool
+	// budgetAdmitter admits the evictions by the ClusterEvictionBudgets if it is set
+	budgetAdmitter evictionbudget.Admitter
 }
 
 func NewEvictor(kubeClient clientset.Interface, eventRecorder record.EventRecorder, evictVersion string) *Evictor {
@@ -56,6 +60,11 @@ func NewEvictor(kubeClient clientset.Interface, eventRecorder record.EventRecord
 	}
 }
 
+// SetEvictionBudgetAdmitter sets the admitter, so the evictions are skipped if the ClusterEvictionBudgets are exhausted.
+func (r *Evictor) SetEvictionBudgetAdmitter(admitter evictionbudget.Admitter) {
+	r.budgetAdmitter = admitter
+}
+
 func (r *Evictor) Start(stopCh <-chan struct{}) error {
 	return r.podsEvicted.Run(stopCh)
 }
@@ -80,6 +89,9 @@ func (r *Evictor) EvictPodIfNotEvicted(evictPod *corev1.Pod, node *corev1.Node,
 		klog.V(5).Infof("Pod has been evicted! podID: %v, evict reason: %s", evictPod.UID, reason)
 		return true
 	}
+	if !r.admitEviction(evictPod, node, reason) {
+		return false
+	}
 	success := r.evictPod(evictPod, reason, message)
 	if success {
 		_ = r.podsEvicted.SetDefault(string(evictPod.UID), evictPod.UID)
@@ -88,15 +100,33 @@ func (r *Evictor) EvictPodIfNotEvicted(evictPod *corev1.Pod, node *corev1.Node,
 	return success
 }
 
+func (r *Evictor) admitEviction(evictPod *corev1.Pod, node *corev1.Node, reason string) bool {
+	if r.budgetAdmitter == nil {
+		return true
+	}
+	admitted, msg, err := r.budgetAdmitter.Admit(context.TODO(), evictPod, node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	if err != nil {
+		klog.Errorf("failed to admit the eviction of pod %v/%v by the cluster eviction budgets, reason: %v, error: %v",
+			evictPod.Namespace, evictPod.Name, reason, err)
+		return false
+	}
+	if !admitted {
+		klog.V(4).Infof("skip evicting pod %v/%v, reason: %v, %s", evictPod.Namespace, evictPod.Name, reason, msg)
+		return false
+	}
+	return true
+}
+
 func (r *Evictor) evictPod(evictPod *corev1.Pod, reason string, message string) bool {
 	podEvictMessage := fmt.Sprintf("evict Pod:%s/%s, reason: %s, message: %v", evictPod.Namespace, evictPod.Name, reason, message)
-	_ = audit.V(0).Pod(evictPod.Namespace, evictPod.Name).Reason(reason).Message(message).Do()
+	actionID := audit.NewActionID()
+	_ = audit.V(0).Pod(evictPod.Namespace, evictPod.Name).Reason(reason).Message(message).ActionID(actionID).Do()
 
 	if err := util.EvictPodByVersion(context.TODO(), r.kubeClient, evictPod.Namespace, evictPod.Name, metav1.DeleteOptions{
 		GracePeriodSeconds: nil,
 		Preconditions:      metav1.NewUIDPreconditions(string(evictPod.UID))}, r.evictVersion); err == nil {
 		r.eventRecorder.Eventf(evictPod, corev1.EventTypeWarning, helpers.EvictPodSuccess, podEvictMessage)
-		metrics.RecordPodEviction(evictPod.Namespace, evictPod.Name, reason)
+		metrics.RecordPodEvictionWithActionID(evictPod.Namespace, evictPod.Name, reason, actionID)
 		klog.Infof("evict pod %v/%v success, reason: %v", evictPod.Namespace, evictPod.Name, reason)
 		return true
 	} else {
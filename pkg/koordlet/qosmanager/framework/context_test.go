@@ -19,6 +19,7 @@ package framework
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -31,11 +32,15 @@ import (
 	coretesting "k8s.io/client-go/testing"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/evictionbudget"
 )
 
 func Test_EvictPodIfNotEvicted(t *testing.T) {
@@ -163,6 +168,49 @@ func Test_EvictPodsIfNotEvicted(t *testing.T) {
 	assert.Equal(t, "", fakeRecorder.EventReason, "check evict duplication, no event send!")
 }
 
+func Test_EvictPodsIfNotEvicted_EvictionBudget(t *testing.T) {
+	pod := testutil.MockTestPod(apiext.QoSBE, "test_be_pod")
+	pod1 := testutil.MockTestPod(apiext.QoSBE, "test_be_pod_1")
+	pod1.UID = "test_be_pod_1"
+	node := testutil.MockTestNode("80", "120G")
+
+	fakeRecorder := &testutil.FakeRecorder{}
+	client := clientsetfake.NewSimpleClientset()
+	koordClient := koordfake.NewSimpleClientset(&slov1alpha1.ClusterEvictionBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-budget"},
+		Spec: slov1alpha1.ClusterEvictionBudgetSpec{
+			MaxEvictions: 1,
+			Window:       metav1.Duration{Duration: time.Hour},
+		},
+	})
+	r := NewEvictor(client, fakeRecorder, policyv1beta1.SchemeGroupVersion.Version)
+	budgetStop := make(chan struct{})
+	defer close(budgetStop)
+	factory := koordinformers.NewSharedInformerFactory(koordClient, 0)
+	r.SetEvictionBudgetAdmitter(evictionbudget.NewAdmitter(koordClient, factory.Slo().V1alpha1().ClusterEvictionBudgets()))
+	factory.Start(budgetStop)
+	factory.WaitForCacheSync(budgetStop)
+	stop := make(chan struct{})
+	err := r.podsEvicted.Run(stop)
+	assert.NoError(t, err)
+	defer func() { stop <- struct{}{} }()
+
+	_, err = client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = client.CoreV1().Pods(pod1.Namespace).Create(context.TODO(), pod1, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	// the first eviction is admitted
+	assert.True(t, r.EvictPodIfNotEvicted(pod, node, "evict pod first", ""))
+	assert.Equal(t, helpers.EvictPodSuccess, fakeRecorder.EventReason)
+
+	// the budget is exhausted
+	fakeRecorder.EventReason = ""
+	assert.False(t, r.EvictPodIfNotEvicted(pod1, node, "evict pod second", ""))
+	assert.Equal(t, "", fakeRecorder.EventReason)
+	assert.False(t, r.IsPodEvicted(pod1))
+}
+
 func setupFakeDiscoveryWithPolicyResource(fake *coretesting.Fake, groupVersion string) {
 	fake.AddReactor("get", "group", func(action coretesting.Action) (handled bool, ret runtime.Object, err error) {
 		fake.Resources = []*metav1.APIResourceList{
@@ -20,6 +20,7 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	ma "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
@@ -32,6 +33,7 @@ type Options struct {
 	MetricCache         metriccache.MetricCache
 	EventRecorder       record.EventRecorder
 	KubeClient          clientset.Interface
+	KoordClient         koordclientset.Interface
 	EvictVersion        string
 	Config              *Config
 	MetricAdvisorConfig *ma.Config
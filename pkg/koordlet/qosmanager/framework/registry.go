@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"fmt"
+)
+
+// Registry is a collection of all available qos strategies, which maps the names to the factories.
+// The out-of-tree strategies can be compiled into the koordlet by registering into the registry of the qos manager,
+// e.g. `plugins.StrategyPlugins.Register("MyStrategy", mystrategy.New)` in the init() of the vendor's package.
+type Registry map[string]QOSStrategyFactory
+
+// Register adds a new strategy to the registry. It returns an error if the strategy is already registered.
+func (r Registry) Register(name string, factory QOSStrategyFactory) error {
+	if _, ok := r[name]; ok {
+		return fmt.Errorf("a qos strategy named %v already exists", name)
+	}
+	if factory == nil {
+		return fmt.Errorf("the factory of qos strategy %v is nil", name)
+	}
+	r[name] = factory
+	return nil
+}
+
+// Unregister removes an existing strategy from the registry, e.g. to replace an in-tree strategy with a custom one.
+// It returns an error if the strategy does not exist.
+func (r Registry) Unregister(name string) error {
+	if _, ok := r[name]; !ok {
+		return fmt.Errorf("no qos strategy named %v exists", name)
+	}
+	delete(r, name)
+	return nil
+}
+
+// Merge merges the strategies of the given registry into the current one. It returns an error if any strategy is
+// registered in both registries.
+func (r Registry) Merge(in Registry) error {
+	for name, factory := range in {
+		if err := r.Register(name, factory); err != nil {
+			return err
+		}
+	}
+	return nil
+}
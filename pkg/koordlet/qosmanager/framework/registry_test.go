@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package framework
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStrategy struct{}
+
+func (f *fakeStrategy) Enabled() bool { return true }
+
+func (f *fakeStrategy) Setup(*Context) {}
+
+func (f *fakeStrategy) Run(stopCh <-chan struct{}) {}
+
+func newFakeStrategy(opt *Options) QOSStrategy {
+	return &fakeStrategy{}
+}
+
+func TestRegistry(t *testing.T) {
+	r := Registry{}
+	assert.NoError(t, r.Register("a", newFakeStrategy))
+	assert.Error(t, r.Register("a", newFakeStrategy))
+	assert.Error(t, r.Register("b", nil))
+	assert.Len(t, r, 1)
+
+	assert.NoError(t, r.Merge(Registry{"b": newFakeStrategy, "c": newFakeStrategy}))
+	assert.Len(t, r, 3)
+	assert.Error(t, r.Merge(Registry{"c": newFakeStrategy}))
+
+	assert.NoError(t, r.Unregister("c"))
+	assert.Error(t, r.Unregister("c"))
+	assert.Len(t, r, 2)
+	assert.NotNil(t, r["a"](&Options{}))
+}
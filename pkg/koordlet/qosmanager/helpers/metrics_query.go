@@ -165,6 +165,40 @@ func CollectContainerResMetricLast(metricCache metriccache.MetricCache, queryMet
 	return aggregateResult.Value(queryParam.Aggregate)
 }
 
+// CollectNodePSILast returns the latest node-level PSI average of the given resource, precision and degree,
+// e.g. the avg10 of the memory full pressure.
+func CollectNodePSILast(metricCache metriccache.MetricCache, psiResource, psiPrecision, psiDegree metriccache.MetricPropertyValue,
+	metricCollectInterval time.Duration) (float64, error) {
+	queryMeta, err := metriccache.NodePSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.NodePSI(string(psiResource), string(psiPrecision), string(psiDegree)))
+	if err != nil {
+		return 0, err
+	}
+	return CollectorNodeMetricLast(metricCache, queryMeta, metricCollectInterval)
+}
+
+// CollectPodPSILast returns the latest PSI average of the given pod.
+func CollectPodPSILast(metricCache metriccache.MetricCache, podUID string, psiResource, psiPrecision, psiDegree metriccache.MetricPropertyValue,
+	metricCollectInterval time.Duration) (float64, error) {
+	queryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.PodPSI(podUID, string(psiResource), string(psiPrecision), string(psiDegree)))
+	if err != nil {
+		return 0, err
+	}
+	return CollectPodMetricLast(metricCache, queryMeta, metricCollectInterval)
+}
+
+// CollectContainerPSILast returns the latest PSI average of the given container.
+func CollectContainerPSILast(metricCache metriccache.MetricCache, podUID, containerID string, psiResource, psiPrecision, psiDegree metriccache.MetricPropertyValue,
+	metricCollectInterval time.Duration) (float64, error) {
+	queryMeta, err := metriccache.ContainerPSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.ContainerPSI(podUID, containerID, string(psiResource), string(psiPrecision), string(psiDegree)))
+	if err != nil {
+		return 0, err
+	}
+	return CollectContainerResMetricLast(metricCache, queryMeta, metricCollectInterval)
+}
+
 func CollectContainerThrottledMetric(metricCache metriccache.MetricCache, containerID *string,
 	metricCollectInterval time.Duration) (metriccache.AggregateResult, error) {
 	if containerID == nil {
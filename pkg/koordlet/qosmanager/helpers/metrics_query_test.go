@@ -216,3 +216,44 @@ func Test_resmanager_collectorNodeMetricLast(t *testing.T) {
 		})
 	}
 }
+
+func Test_collectPSILast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	nodeQueryMeta, err := metriccache.NodePSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.NodePSI(string(metriccache.PSIResourceMem), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeFull)))
+	assert.NoError(t, err)
+	podQueryMeta, err := metriccache.PodPSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.PodPSI("test-pod-uid", string(metriccache.PSIResourceCPU), string(metriccache.PSIPrecision60), string(metriccache.PSIDegreeSome)))
+	assert.NoError(t, err)
+	containerQueryMeta, err := metriccache.ContainerPSIMetric.BuildQueryMeta(
+		metriccache.MetricPropertiesFunc.ContainerPSI("test-pod-uid", "containerd://test-container", string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)))
+	assert.NoError(t, err)
+
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctrl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mock_metriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	for queryMeta, value := range map[metriccache.MetricMeta]float64{
+		nodeQueryMeta:      1.5,
+		podQueryMeta:       2.5,
+		containerQueryMeta: 3.5,
+	} {
+		result := mock_metriccache.NewMockAggregateResult(ctrl)
+		result.EXPECT().Value(metriccache.AggregationTypeLast).Return(value, nil).AnyTimes()
+		mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+		mockQuerier.EXPECT().QueryAndClose(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+	}
+
+	got, err := CollectNodePSILast(mockMetricCache, metriccache.PSIResourceMem, metriccache.PSIPrecision10, metriccache.PSIDegreeFull, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.5, got)
+	got, err = CollectPodPSILast(mockMetricCache, "test-pod-uid", metriccache.PSIResourceCPU, metriccache.PSIPrecision60, metriccache.PSIDegreeSome, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, got)
+	got, err = CollectContainerPSILast(mockMetricCache, "test-pod-uid", "containerd://test-container", metriccache.PSIResourceIO, metriccache.PSIPrecision10, metriccache.PSIDegreeSome, 10*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 3.5, got)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package helpers
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
@@ -66,3 +68,12 @@ func GetKubeQoSResourceQoSByQoSClass(qosClass corev1.PodQOSClass, strategy *slov
 	}
 	return resourceQoS
 }
+
+// GetCompletionProtectionWindow returns the node-level default run-to-completion protection window.
+// It returns 0 if the window is not configured, which means only the pods declaring a window are protected.
+func GetCompletionProtectionWindow(strategy *slov1alpha1.ResourceThresholdStrategy) time.Duration {
+	if strategy == nil || strategy.CompletionProtectionWindowSeconds == nil || *strategy.CompletionProtectionWindowSeconds <= 0 {
+		return 0
+	}
+	return time.Duration(*strategy.CompletionProtectionWindowSeconds) * time.Second
+}
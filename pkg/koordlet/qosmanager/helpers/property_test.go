@@ -18,9 +18,11 @@ package helpers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
@@ -82,3 +84,42 @@ func Test_getPodResourceQoSByQoSClass(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCompletionProtectionWindow(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *slov1alpha1.ResourceThresholdStrategy
+		want     time.Duration
+	}{
+		{
+			name:     "nil strategy",
+			strategy: nil,
+			want:     0,
+		},
+		{
+			name:     "window not set",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{},
+			want:     0,
+		},
+		{
+			name: "negative window",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				CompletionProtectionWindowSeconds: pointer.Int64(-1),
+			},
+			want: 0,
+		},
+		{
+			name: "window set",
+			strategy: &slov1alpha1.ResourceThresholdStrategy{
+				CompletionProtectionWindowSeconds: pointer.Int64(600),
+			},
+			want: 10 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetCompletionProtectionWindow(tt.strategy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package blkio
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -39,6 +40,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
@@ -77,7 +79,7 @@ func (b *blkIOReconcile) Run(stopCh <-chan struct{}) {
 		klog.Fatal("blkIOReconcile init failed, error %v", err)
 		return
 	}
-	go wait.Until(b.reconcile, b.reconcileInterval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, BlkIOReconcileName, b.reconcile), b.reconcileInterval, stopCh)
 }
 
 type (
@@ -103,7 +105,7 @@ func (b *blkIOReconcile) init(stopCh <-chan struct{}) error {
 	return nil
 }
 
-func (b *blkIOReconcile) reconcile() {
+func (b *blkIOReconcile) reconcile(ctx context.Context) {
 	klog.V(4).Infof("%s: start to reconcile", BlkIOReconcileName)
 	// get node local storage info
 	storageInfoRaw, exist := b.metricCache.Get(metriccache.NodeLocalStorageInfoKey)
@@ -142,7 +144,7 @@ func (b *blkIOReconcile) reconcile() {
 		}
 		beClassRelativeDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
 		beClassPath := util.GetPodCgroupBlkIOAbsoluteDir(corev1.PodQOSBestEffort)
-		err := b.updateBlkIOConfig(
+		err := b.updateBlkIOConfig(ctx,
 			blocks,
 			nil,
 			blkioUpdater{
@@ -168,7 +170,7 @@ func (b *blkIOReconcile) reconcile() {
 		}
 		rootClassRelativePath := ""
 		rootClassPath := util.GetCgroupRootBlkIOAbsoluteDir()
-		err := b.updateBlkIOConfig(
+		err := b.updateBlkIOConfig(ctx,
 			blocks,
 			nil,
 			blkioUpdater{
@@ -210,7 +212,7 @@ func (b *blkIOReconcile) reconcile() {
 			continue
 		}
 		klog.V(4).Infof("%s: start to reconcile pod %s/%s blkio config", BlkIOReconcileName, podMeta.Pod.Namespace, podMeta.Pod.Name)
-		err = b.updateBlkIOConfig(
+		err = b.updateBlkIOConfig(ctx,
 			podBlkIOQoS.Blocks,
 			podMeta,
 			blkioUpdater{
@@ -241,7 +243,7 @@ type blkioUpdater struct {
 // update blkio cgroup files
 // podMeta == nil when BlockType is BlockTypeDevice or BlockTypeVolumeGroup
 // podMeta != nil when BlockType is BlockTypePodVolume
-func (b *blkIOReconcile) updateBlkIOConfig(blocks []*slov1alpha1.BlockCfg, podMeta *statesinformer.PodMeta, blkioUpdater blkioUpdater) error {
+func (b *blkIOReconcile) updateBlkIOConfig(ctx context.Context, blocks []*slov1alpha1.BlockCfg, podMeta *statesinformer.PodMeta, blkioUpdater blkioUpdater) error {
 	if blkioUpdater.getDiskRecorder == nil {
 		return fmt.Errorf("getDiskRecorder can not be nil")
 	}
@@ -266,7 +268,7 @@ func (b *blkIOReconcile) updateBlkIOConfig(blocks []*slov1alpha1.BlockCfg, podMe
 			resources = append(resources, blkioUpdater.getRemoverFunc(diskNumber, blkioUpdater.dynamicPath)...)
 		}
 	}
-	b.executor.UpdateBatch(true, resources...)
+	b.executor.UpdateBatch(ctx, true, resources...)
 	return nil
 }
 
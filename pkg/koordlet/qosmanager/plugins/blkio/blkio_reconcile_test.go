@@ -17,6 +17,7 @@ limitations under the License.
 package blkio
 
 import (
+	"context"
 	"fmt"
 	"path"
 	"path/filepath"
@@ -184,7 +185,7 @@ func TestBlkIOReconcile_reconcile(t *testing.T) {
 		if err := b.init(stop); err != nil {
 			b.executor.Run(stop)
 		}
-		b.reconcile()
+		b.reconcile(context.TODO())
 	})
 }
 
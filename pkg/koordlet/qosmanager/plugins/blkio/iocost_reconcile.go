@@ -0,0 +1,278 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	IOCostReconcileName = "IOCostReconcile"
+
+	// MinIOWeightPercent is the lower bound of the BE weight when the disk latency exceeds the targets.
+	MinIOWeightPercent = 1
+)
+
+var _ framework.QOSStrategy = &ioCostReconcile{}
+
+// getDiskStats reads the disk stats of the node, it is replaced in the tests.
+var getDiskStats = system.GetDiskStats
+
+// ioCostReconcile enables the blk-iocost (io.cost.qos and io.cost.model on cgroups-v2, blkio.cost.qos and
+// blkio.cost.model of Anolis OS on cgroups-v1) on the disks of the CgroupRoot blocks in the NodeSLO, and sets the
+// io weights of the LS (burstable) and BE (besteffort) classes on the disks by the blocks of the classes.
+// Since the proportional control only takes effect when the disk is saturated, it also observes the average io
+// latency of the disks from /proc/diskstats, halves the BE weight on the disk whose read or write latency exceeds the
+// latency target of the CgroupRoot block, and recovers the weight step by step when the latency drops.
+// It configures the same files as the BlkIOReconcile for the CgroupRoot and the BE class, so it is disabled when the
+// BlkIOReconcile is enabled. On cgroups-v1 without the blk-iocost, the BE class can only be limited with the
+// blk-throttle by the BlkIOReconcile.
+type ioCostReconcile struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	metricCache       metriccache.MetricCache
+	executor          resourceexecutor.ResourceUpdateExecutor
+
+	// the states below are only accessed in the reconcile loop
+	lastDiskStats map[string]*system.DiskStat
+	// beWeights records the current BE weights of the configured disks, keyed by the disk number
+	beWeights map[string]int64
+}
+
+func NewIOCostReconcile(opt *framework.Options) framework.QOSStrategy {
+	return &ioCostReconcile{
+		reconcileInterval: time.Duration(opt.Config.IOCostIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		metricCache:       opt.MetricCache,
+		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+		beWeights:         map[string]int64{},
+	}
+}
+
+func (r *ioCostReconcile) Enabled() bool {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.IOCostReconcile) || r.reconcileInterval <= 0 {
+		return false
+	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.BlkIOReconcile) {
+		klog.Warningf("%s is disabled since %s is enabled, which configures the same blk-iocost files",
+			IOCostReconcileName, features.BlkIOReconcile)
+		return false
+	}
+	return true
+}
+
+func (r *ioCostReconcile) Setup(ctx *framework.Context) {
+}
+
+func (r *ioCostReconcile) Run(stopCh <-chan struct{}) {
+	r.executor.Run(stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, IOCostReconcileName, r.reconcile), r.reconcileInterval, stopCh)
+}
+
+func (r *ioCostReconcile) reconcile(ctx context.Context) {
+	storageInfoRaw, exist := r.metricCache.Get(metriccache.NodeLocalStorageInfoKey)
+	if !exist {
+		klog.V(4).Infof("%s: skip reconcile, node local storage info not exist", IOCostReconcileName)
+		return
+	}
+	storageInfo, ok := storageInfoRaw.(*metriccache.NodeLocalStorageInfo)
+	if !ok {
+		klog.Warningf("%s: skip reconcile, type error, expect %T, but got %T", IOCostReconcileName, &metriccache.NodeLocalStorageInfo{}, storageInfoRaw)
+		return
+	}
+	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		klog.V(4).Infof("%s: skip reconcile, nodeSLO or resourceQOSStrategy is nil", IOCostReconcileName)
+		return
+	}
+	strategy := nodeSLO.Spec.ResourceQOSStrategy
+
+	diskStats, err := getDiskStats()
+	if err != nil {
+		klog.Warningf("%s: failed to get disk stats, the latency is not observed, err: %v", IOCostReconcileName, err)
+	}
+	lastDiskStats := r.lastDiskStats
+	r.lastDiskStats = diskStats
+
+	lsWeights := getClassIOWeights(storageInfo, strategy.LSClass)
+	beWeights := getClassIOWeights(storageInfo, strategy.BEClass)
+	lsDir := util.GetPodQoSRelativePath(corev1.PodQOSBurstable)
+	beDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+
+	var resources []resourceexecutor.ResourceUpdater
+	configured := map[string]struct{}{}
+	for _, block := range getEnabledBlocks(strategy.CgroupRoot) {
+		diskNumber := getDiskNumberFromStorage(storageInfo, block)
+		if diskNumber == "" {
+			klog.V(4).Infof("%s: skip block %s, disk number not found", IOCostReconcileName, block.Name)
+			continue
+		}
+		configured[diskNumber] = struct{}{}
+		resources = append(resources, getDiskConfigUpdaterFromBlockCfg(block, diskNumber, "")...)
+
+		lsWeight, ok := lsWeights[diskNumber]
+		if !ok {
+			lsWeight = DefaultIOWeightPercentage
+		}
+		beTarget, ok := beWeights[diskNumber]
+		if !ok {
+			beTarget = DefaultIOWeightPercentage
+		}
+		congested := false
+		if diskStats != nil && lastDiskStats != nil {
+			readLatency, writeLatency := getDiskLatency(lastDiskStats[diskNumber], diskStats[diskNumber])
+			readTarget, writeTarget := getLatencyTargets(block)
+			congested = readLatency > readTarget || writeLatency > writeTarget
+			klog.V(5).Infof("%s: disk %s read latency %dus, write latency %dus, congested %v",
+				IOCostReconcileName, diskNumber, readLatency, writeLatency, congested)
+		}
+		beWeight := adjustIOWeight(r.beWeights[diskNumber], beTarget, congested)
+		if beWeight != r.beWeights[diskNumber] {
+			klog.V(4).Infof("%s: update be weight of disk %s from %d to %d, target %d",
+				IOCostReconcileName, diskNumber, r.beWeights[diskNumber], beWeight, beTarget)
+		}
+		r.beWeights[diskNumber] = beWeight
+
+		resources = appendIOWeightUpdater(resources, lsDir, diskNumber, lsWeight)
+		resources = appendIOWeightUpdater(resources, beDir, diskNumber, beWeight)
+	}
+
+	// restore the disks which are no longer configured
+	for diskNumber := range r.beWeights {
+		if _, ok := configured[diskNumber]; ok {
+			continue
+		}
+		resources = append(resources, getDiskConfigRemoverFromDiskNumber(diskNumber, "")...)
+		resources = appendIOWeightUpdater(resources, lsDir, diskNumber, DefaultIOWeightPercentage)
+		resources = appendIOWeightUpdater(resources, beDir, diskNumber, DefaultIOWeightPercentage)
+		delete(r.beWeights, diskNumber)
+	}
+
+	r.executor.UpdateBatch(ctx, true, resources...)
+}
+
+func getEnabledBlocks(qos *slov1alpha1.ResourceQOS) []*slov1alpha1.BlockCfg {
+	if qos == nil || qos.BlkIOQOS == nil || qos.BlkIOQOS.Enable == nil || !*qos.BlkIOQOS.Enable {
+		return nil
+	}
+	return qos.BlkIOQOS.Blocks
+}
+
+// getDiskNumberFromStorage returns the disk number of the device or volume group block, the pod volume blocks are
+// not supported.
+func getDiskNumberFromStorage(storageInfo *metriccache.NodeLocalStorageInfo, block *slov1alpha1.BlockCfg) string {
+	switch block.BlockType {
+	case slov1alpha1.BlockTypeDevice:
+		return getDiskNumber(storageInfo, getDiskByDevice(storageInfo, block.Name))
+	case slov1alpha1.BlockTypeVolumeGroup:
+		return getDiskNumber(storageInfo, getDiskByVG(storageInfo, block.Name))
+	default:
+		return ""
+	}
+}
+
+// getClassIOWeights returns the io weights of the class keyed by the disk number.
+func getClassIOWeights(storageInfo *metriccache.NodeLocalStorageInfo, qos *slov1alpha1.ResourceQOS) map[string]int64 {
+	weights := map[string]int64{}
+	for _, block := range getEnabledBlocks(qos) {
+		if block.IOCfg.IOWeightPercent == nil {
+			continue
+		}
+		if diskNumber := getDiskNumberFromStorage(storageInfo, block); diskNumber != "" {
+			weights[diskNumber] = *block.IOCfg.IOWeightPercent
+		}
+	}
+	return weights
+}
+
+func getLatencyTargets(block *slov1alpha1.BlockCfg) (int64, int64) {
+	var readTarget, writeTarget int64 = DefaultIOLatency, DefaultIOLatency
+	if value := block.IOCfg.ReadLatency; value != nil && *value > 0 {
+		readTarget = *value
+	}
+	if value := block.IOCfg.WriteLatency; value != nil && *value > 0 {
+		writeTarget = *value
+	}
+	return readTarget, writeTarget
+}
+
+// getDiskLatency returns the average read and write latency in microseconds of the ios completed between the stats.
+func getDiskLatency(last, cur *system.DiskStat) (int64, int64) {
+	if last == nil || cur == nil {
+		return 0, 0
+	}
+	averageLatency := func(lastIOs, curIOs, lastTicks, curTicks uint64) int64 {
+		if curIOs <= lastIOs || curTicks < lastTicks {
+			return 0
+		}
+		return int64((curTicks - lastTicks) * 1000 / (curIOs - lastIOs))
+	}
+	return averageLatency(last.ReadIOs, cur.ReadIOs, last.ReadTicks, cur.ReadTicks),
+		averageLatency(last.WriteIOs, cur.WriteIOs, last.WriteTicks, cur.WriteTicks)
+}
+
+// adjustIOWeight halves the weight when the disk is congested, otherwise increases it by a tenth of the target until
+// it reaches the target.
+func adjustIOWeight(current, target int64, congested bool) int64 {
+	if current <= 0 || current > target {
+		current = target
+	}
+	if congested {
+		current /= 2
+		if current < MinIOWeightPercent {
+			current = MinIOWeightPercent
+		}
+		return current
+	}
+	step := target / 10
+	if step < 1 {
+		step = 1
+	}
+	current += step
+	if current > target {
+		current = target
+	}
+	return current
+}
+
+func appendIOWeightUpdater(resources []resourceexecutor.ResourceUpdater, dir, diskNumber string, weight int64) []resourceexecutor.ResourceUpdater {
+	value := fmt.Sprintf("%s %d", diskNumber, weight)
+	updater, err := resourceexecutor.NewBlkIOResourceUpdater(system.BlkioIOWeightName, dir, value,
+		audit.V(3).Group("blkio").Reason("UpdateIOCost").Message("update %s/%s to %s", dir, system.BlkioIOWeightName, value))
+	if err != nil {
+		klog.V(4).Infof("%s: failed to create io weight updater for %s, err: %v", IOCostReconcileName, dir, err)
+		return resources
+	}
+	return append(resources, updater)
+}
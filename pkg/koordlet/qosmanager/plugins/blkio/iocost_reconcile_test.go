@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blkio
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func newIOCostBlockQOS(name string, ioCfg slov1alpha1.IOCfg) *slov1alpha1.ResourceQOS {
+	return &slov1alpha1.ResourceQOS{
+		BlkIOQOS: &slov1alpha1.BlkIOQOSCfg{
+			Enable: pointer.Bool(true),
+			BlkIOQOS: slov1alpha1.BlkIOQOS{
+				Blocks: []*slov1alpha1.BlockCfg{
+					{
+						Name:      name,
+						BlockType: slov1alpha1.BlockTypeDevice,
+						IOCfg:     ioCfg,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIOCostReconcile_reconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	lsDir := util.GetPodQoSRelativePath(corev1.PodQOSBurstable)
+	beDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	helper.WriteCgroupFileContents("", system.BlkioIOQoSV2, "253:16 enable=0")
+	helper.WriteCgroupFileContents("", system.BlkioIOModelV2, "253:16 ctrl=auto")
+	helper.WriteCgroupFileContents(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed), system.BlkioIOWeightV2, "default 100")
+	helper.WriteCgroupFileContents(lsDir, system.BlkioIOWeightV2, "default 100")
+	helper.WriteCgroupFileContents(beDir, system.BlkioIOWeightV2, "default 100")
+
+	nodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSClass: newIOCostBlockQOS("/dev/vdb", slov1alpha1.IOCfg{IOWeightPercent: pointer.Int64(100)}),
+				BEClass: newIOCostBlockQOS("/dev/vdb", slov1alpha1.IOCfg{IOWeightPercent: pointer.Int64(40)}),
+				CgroupRoot: newIOCostBlockQOS("/dev/vdb", slov1alpha1.IOCfg{
+					ReadLatency:  pointer.Int64(2000),
+					WriteLatency: pointer.Int64(2000),
+				}),
+			},
+		},
+	}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetNodeSLO().Return(nodeSLO).AnyTimes()
+	mc := mock_metriccache.NewMockMetricCache(ctrl)
+	mc.EXPECT().Get(metriccache.NodeLocalStorageInfoKey).Return(&metriccache.NodeLocalStorageInfo{
+		DiskNumberMap: map[string]string{"/dev/vdb": "253:16"},
+		NumberDiskMap: map[string]string{"253:16": "/dev/vdb"},
+	}, true).AnyTimes()
+
+	diskStat := &system.DiskStat{Device: "253:16", Name: "vdb"}
+	oldGetDiskStats := getDiskStats
+	defer func() { getDiskStats = oldGetDiskStats }()
+	getDiskStats = func() (map[string]*system.DiskStat, error) {
+		stat := *diskStat
+		return map[string]*system.DiskStat{stat.Device: &stat}, nil
+	}
+
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.IOCostReconcile, true)()
+	s := NewIOCostReconcile(&framework.Options{StatesInformer: si, MetricCache: mc, Config: framework.NewDefaultConfig()})
+	assert.True(t, s.Enabled())
+	// the BlkIOReconcile configures the same files
+	func() {
+		defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BlkIOReconcile, true)()
+		assert.False(t, s.Enabled())
+	}()
+	r := s.(*ioCostReconcile)
+	stop := make(chan struct{})
+	defer close(stop)
+	r.executor.Run(stop)
+
+	r.reconcile(context.TODO())
+	assert.Equal(t, "253:16 enable=1 ctrl=user rpct=95 rlat=2000 wpct=95 wlat=2000", helper.ReadCgroupFileContents("", system.BlkioIOQoSV2))
+	assert.Equal(t, "253:16 ctrl=auto", helper.ReadCgroupFileContents("", system.BlkioIOModelV2))
+	assert.Equal(t, "253:16 100", helper.ReadCgroupFileContents(lsDir, system.BlkioIOWeightV2))
+	assert.Equal(t, "253:16 40", helper.ReadCgroupFileContents(beDir, system.BlkioIOWeightV2))
+
+	// the average write latency 5ms exceeds the target, the be weight is halved
+	diskStat.WriteIOs, diskStat.WriteTicks = 100, 500
+	r.reconcile(context.TODO())
+	assert.Equal(t, "253:16 20", helper.ReadCgroupFileContents(beDir, system.BlkioIOWeightV2))
+
+	// the latency drops, the be weight recovers
+	diskStat.WriteIOs, diskStat.WriteTicks = 200, 600
+	r.reconcile(context.TODO())
+	assert.Equal(t, "253:16 24", helper.ReadCgroupFileContents(beDir, system.BlkioIOWeightV2))
+
+	// restore the disk when it is no longer configured
+	nodeSLO.Spec.ResourceQOSStrategy.CgroupRoot.BlkIOQOS.Enable = pointer.Bool(false)
+	r.reconcile(context.TODO())
+	assert.Equal(t, "253:16 enable=0", helper.ReadCgroupFileContents("", system.BlkioIOQoSV2))
+	assert.Equal(t, "253:16 100", helper.ReadCgroupFileContents(beDir, system.BlkioIOWeightV2))
+	assert.Empty(t, r.beWeights)
+}
+
+func Test_getDiskLatency(t *testing.T) {
+	last := &system.DiskStat{ReadIOs: 100, ReadTicks: 100, WriteIOs: 100, WriteTicks: 100}
+	cur := &system.DiskStat{ReadIOs: 200, ReadTicks: 300, WriteIOs: 100, WriteTicks: 100}
+	readLatency, writeLatency := getDiskLatency(last, cur)
+	assert.Equal(t, int64(2000), readLatency)
+	assert.Equal(t, int64(0), writeLatency)
+	readLatency, writeLatency = getDiskLatency(nil, cur)
+	assert.Equal(t, int64(0), readLatency)
+	assert.Equal(t, int64(0), writeLatency)
+}
+
+func Test_adjustIOWeight(t *testing.T) {
+	assert.Equal(t, int64(20), adjustIOWeight(0, 40, true))
+	assert.Equal(t, int64(40), adjustIOWeight(0, 40, false))
+	assert.Equal(t, int64(MinIOWeightPercent), adjustIOWeight(1, 40, true))
+	assert.Equal(t, int64(14), adjustIOWeight(10, 40, false))
+	assert.Equal(t, int64(40), adjustIOWeight(38, 40, false))
+	// the target is lowered
+	assert.Equal(t, int64(30), adjustIOWeight(40, 30, false))
+}
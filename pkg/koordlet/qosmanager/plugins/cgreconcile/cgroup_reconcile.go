@@ -17,6 +17,7 @@ limitations under the License.
 package cgreconcile
 
 import (
+	"context"
 	"math"
 	"strconv"
 	"time"
@@ -30,10 +31,12 @@ import (
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -48,6 +51,7 @@ var _ framework.QOSStrategy = &cgroupResourcesReconcile{}
 
 type cgroupResourcesReconcile struct {
 	reconcileInterval time.Duration
+	workers           int
 	statesInformer    statesinformer.StatesInformer
 	executor          resourceexecutor.ResourceUpdateExecutor
 }
@@ -75,6 +79,7 @@ type cgroupResourceUpdaterMeta struct {
 func New(opt *framework.Options) framework.QOSStrategy {
 	return &cgroupResourcesReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		workers:           opt.Config.CgroupReconcileWorkers,
 		statesInformer:    opt.StatesInformer,
 		executor:          resourceexecutor.NewResourceUpdateExecutor(),
 	}
@@ -89,14 +94,14 @@ func (m *cgroupResourcesReconcile) Setup(context *framework.Context) {
 
 func (m *cgroupResourcesReconcile) Run(stopCh <-chan struct{}) {
 	m.init(stopCh)
-	go wait.Until(m.reconcile, m.reconcileInterval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, CgroupReconcileName, m.reconcile), m.reconcileInterval, stopCh)
 }
 
 func (m *cgroupResourcesReconcile) init(stopCh <-chan struct{}) {
 	m.executor.Run(stopCh)
 }
 
-func (m *cgroupResourcesReconcile) reconcile() {
+func (m *cgroupResourcesReconcile) reconcile(ctx context.Context) {
 	nodeSLO := m.statesInformer.GetNodeSLO()
 	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
 		// do nothing if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil
@@ -106,11 +111,11 @@ func (m *cgroupResourcesReconcile) reconcile() {
 
 	// apply CgroupReconcile: calculate resources to update, and then update them by a leveled order to avoid dynamic
 	// resource overcommitment/leak
-	m.calculateAndUpdateResources(nodeSLO)
+	m.calculateAndUpdateResources(ctx, nodeSLO)
 	klog.V(5).Infof("finish reconciling Cgroups!")
 }
 
-func (m *cgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alpha1.NodeSLO) {
+func (m *cgroupResourcesReconcile) calculateAndUpdateResources(ctx context.Context, nodeSLO *slov1alpha1.NodeSLO) {
 	// 1. sort cgroup resources by the owner level (qos, pod, container).
 	//    e.g. for hierarchical resources of memoryMin, when qos-level memoryMin increases, they should be updated from
 	//         the top to bottom; while resources should be updated from the bottom to top when qos-level memoryMin
@@ -128,18 +133,45 @@ func (m *cgroupResourcesReconcile) calculateAndUpdateResources(nodeSLO *slov1alp
 	podMetas := m.statesInformer.GetAllPods()
 
 	// calculate qos-level, pod-level and container-level resources
-	qosResources, podResources, containerResources := m.calculateResources(nodeSLO.Spec.ResourceQOSStrategy, node, podMetas)
+	calculateStart := time.Now()
+	qosResources, podGroups := m.calculateResourcesByPod(nodeSLO.Spec.ResourceQOSStrategy, node, podMetas)
+	metrics.RecordCgroupReconcileDuration(metrics.CgroupReconcileStageCalculate, time.Since(calculateStart).Seconds())
 
 	// to make sure the hierarchical cgroup resources are correctly updated, we simply update the resources by
 	// cgroup-level order.
 	// e.g. /kubepods.slice/memory.min, /kubepods.slice-podxxx/memory.min, /kubepods.slice-podxxx/docker-yyy/memory.min
-	leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources}
-	m.executor.LeveledUpdateBatch(leveledResources)
+	// when multiple workers are configured, the pods are updated in parallel while the pod-level and container-level
+	// resources of each pod are still updated in order.
+	updateStart := time.Now()
+	if m.workers > 1 {
+		m.executor.LeveledUpdateBatchParallel(ctx, qosResources, podGroups, m.workers)
+	} else {
+		var podResources, containerResources []resourceexecutor.ResourceUpdater
+		for _, podGroup := range podGroups {
+			podResources = append(podResources, podGroup[0]...)
+			containerResources = append(containerResources, podGroup[1]...)
+		}
+		leveledResources := [][]resourceexecutor.ResourceUpdater{qosResources, podResources, containerResources}
+		m.executor.LeveledUpdateBatch(ctx, leveledResources)
+	}
+	metrics.RecordCgroupReconcileDuration(metrics.CgroupReconcileStageUpdate, time.Since(updateStart).Seconds())
 }
 
 // calculateResources calculates qos-level, pod-level and container-level resources with nodeCfg and podMetas
 func (m *cgroupResourcesReconcile) calculateResources(nodeCfg *slov1alpha1.ResourceQOSStrategy, node *corev1.Node,
 	podMetas []*statesinformer.PodMeta) (qosLevelResources, podLevelResources, containerLevelResources []resourceexecutor.ResourceUpdater) {
+	qosLevelResources, podGroups := m.calculateResourcesByPod(nodeCfg, node, podMetas)
+	for _, podGroup := range podGroups {
+		podLevelResources = append(podLevelResources, podGroup[0]...)
+		containerLevelResources = append(containerLevelResources, podGroup[1]...)
+	}
+	return
+}
+
+// calculateResourcesByPod calculates qos-level resources and the leveled resources grouped by pod, where each group
+// consists of the pod-level and container-level resources of a pod.
+func (m *cgroupResourcesReconcile) calculateResourcesByPod(nodeCfg *slov1alpha1.ResourceQOSStrategy, node *corev1.Node,
+	podMetas []*statesinformer.PodMeta) (qosLevelResources []resourceexecutor.ResourceUpdater, podGroups [][][]resourceexecutor.ResourceUpdater) {
 	// TODO: check anolis os version
 	qosSummary := map[corev1.PodQOSClass]*cgroupResourceSummary{
 		corev1.PodQOSGuaranteed: {},
@@ -169,8 +201,7 @@ func (m *cgroupResourcesReconcile) calculateResources(nodeCfg *slov1alpha1.Resou
 
 		// calculate pod-level and container-level resources and make resourceUpdaters
 		podResources, containerResources := m.calculatePodAndContainerResources(podMeta, node, mergedPodCfg)
-		podLevelResources = append(podLevelResources, podResources...)
-		containerLevelResources = append(containerLevelResources, containerResources...)
+		podGroups = append(podGroups, [][]resourceexecutor.ResourceUpdater{podResources, containerResources})
 	}
 	// summarize qos-level resources
 	completeCgroupSummaryForQoS(qosSummary)
@@ -17,6 +17,7 @@ limitations under the License.
 package cgreconcile
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -281,6 +282,7 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 		name        string
 		qosStrategy *slov1alpha1.ResourceQOSStrategy
 		podMetas    []*statesinformer.PodMeta
+		workers     int
 		expect      *slov1alpha1.ResourceQOSStrategy
 	}
 	tests := []args{
@@ -427,6 +429,17 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 			},
 			expect: testutil.DefaultQOSStrategy(),
 		},
+		{
+			name:        "calculate qos resources from pods with parallel workers",
+			qosStrategy: testingQOSStrategyBE,
+			podMetas: []*statesinformer.PodMeta{
+				testutil.MockTestPodWithQOS(corev1.PodQOSBestEffort, apiext.QoSBE),
+				createPodWithMemoryQOS(corev1.PodQOSBestEffort, apiext.QoSBE, &slov1alpha1.PodMemoryQOSConfig{Policy: slov1alpha1.PodMemoryQOSPolicyAuto}),
+				testingNonRunningPod,
+			},
+			workers: 2,
+			expect:  mergeWithDefaultQOSStrategy(testingQOSStrategyBE),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -441,6 +454,9 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 			statesInformer.EXPECT().GetNode().Return(testingNode).MaxTimes(1)
 			statesInformer.EXPECT().GetAllPods().Return(tt.podMetas).MaxTimes(1)
 
+			if tt.workers > 0 {
+				opt.Config.CgroupReconcileWorkers = tt.workers
+			}
 			reconciler := newTestCgroupResourcesReconcile(opt)
 			stop := make(chan struct{})
 			assert.NotPanics(t, func() {
@@ -454,7 +470,7 @@ func Test_calculateAndUpdateResources(t *testing.T) {
 			initQOSStrategy := testutil.DefaultQOSStrategy()
 			initQOSCgroupFile(initQOSStrategy, helper)
 
-			reconciler.calculateAndUpdateResources(createNodeSLOWithQOSStrategy(tt.qosStrategy))
+			reconciler.calculateAndUpdateResources(context.TODO(), createNodeSLOWithQOSStrategy(tt.qosStrategy))
 			got := gotQOSStrategyFromFile(helper)
 			assert.Equal(t, tt.expect, got)
 		})
@@ -1185,6 +1201,7 @@ func Test_makeCgroupResources(t *testing.T) {
 func newTestCgroupResourcesReconcile(opt *framework.Options) *cgroupResourcesReconcile {
 	return &cgroupResourcesReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		workers:           opt.Config.CgroupReconcileWorkers,
 		statesInformer:    opt.StatesInformer,
 		executor: &resourceexecutor.ResourceUpdateExecutorImpl{
 			Config:        resourceexecutor.NewDefaultConfig(),
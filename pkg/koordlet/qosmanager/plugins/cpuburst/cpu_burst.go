@@ -17,6 +17,7 @@ limitations under the License.
 package cpuburst
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -38,6 +39,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -173,6 +175,9 @@ type cpuBurst struct {
 	cgroupReader          resourceexecutor.CgroupReader
 	nodeCPUBurstStrategy  *slov1alpha1.CPUBurstStrategy
 	containerLimiter      map[string]*burstLimiter
+	// cpuBurstUnsupportedMsg records the last reason why the kernel does not support the cpu burst to avoid the
+	// repeated logs
+	cpuBurstUnsupportedMsg string
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
@@ -197,14 +202,14 @@ func (b *cpuBurst) Setup(ctx *framework.Context) {
 
 func (b *cpuBurst) Run(stopCh <-chan struct{}) {
 	b.init(stopCh)
-	go wait.Until(b.start, b.reconcileInterval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, CPUBurstName, b.start), b.reconcileInterval, stopCh)
 }
 
 func (b *cpuBurst) init(stopCh <-chan struct{}) {
 	b.executor.Run(stopCh)
 }
 
-func (b *cpuBurst) start() {
+func (b *cpuBurst) start(ctx context.Context) {
 	klog.V(5).Infof("start cpu burst strategy")
 	// at the beginning of appling cpu burst strategy, we should reset all metrics belongs to pods and containers
 	metrics.ResetCPUBurstCollector()
@@ -217,6 +222,13 @@ func (b *cpuBurst) start() {
 	b.nodeCPUBurstStrategy = nodeSLO.Spec.CPUBurstStrategy
 	podsMeta := b.statesInformer.GetAllPods()
 
+	// fallback to scale the cfs quota only when the kernel does not support the cpu burst
+	cpuBurstSupported, msg := system.IsCPUBurstSupported()
+	if !cpuBurstSupported && msg != b.cpuBurstUnsupportedMsg {
+		klog.Warningf("cpu burst is unsupported, only the cfs quota burst takes effect, reason: %s", msg)
+	}
+	b.cpuBurstUnsupportedMsg = msg
+
 	// get node state by node share pool usage
 	nodeState := b.getNodeStateForBurst(*b.nodeCPUBurstStrategy.SharePoolThresholdPercent, podsMeta)
 	klog.V(5).Infof("get node state %v for cpu burst", nodeState)
@@ -245,11 +257,19 @@ func (b *cpuBurst) start() {
 				podMeta.Pod.Namespace, podMeta.Pod.Name, cpuBurstCfg)
 			continue
 		}
+		if !util.IsPodCPUBurstAllowedByWorkload(podMeta.Pod, b.nodeCPUBurstStrategy.WorkloadPolicy) {
+			// reset the burst of the pods denied by the workload policy
+			klog.V(5).Infof("pod %v/%v cpu burst is denied by workload policy", podMeta.Pod.Namespace, podMeta.Pod.Name)
+			cpuBurstCfg = cpuBurstCfg.DeepCopy()
+			cpuBurstCfg.Policy = slov1alpha1.CPUBurstNone
+		}
 		klog.V(5).Infof("get pod %v/%v cpu burst config: %v", podMeta.Pod.Namespace, podMeta.Pod.Name, cpuBurstCfg)
-		// set cpu.cfs_burst_us for pod and containers
-		b.applyCPUBurst(cpuBurstCfg, podMeta)
+		// set cpu.cfs_burst_us (cpu.max.burst on cgroup v2) for pod and containers
+		if cpuBurstSupported {
+			b.applyCPUBurst(ctx, cpuBurstCfg, podMeta)
+		}
 		// scale cpu.cfs_quota_us for pod and containers
-		b.applyCFSQuotaBurst(cpuBurstCfg, podMeta, nodeState)
+		b.applyCFSQuotaBurst(ctx, cpuBurstCfg, podMeta, nodeState)
 	}
 	b.Recycle()
 }
@@ -338,17 +358,12 @@ func (b *cpuBurst) getNodeStateForBurst(sharePoolThresholdPercent int64,
 }
 
 // scale cpu.cfs_quota_us for pod/containers by container throttled state and node state
-func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta,
+func (b *cpuBurst) applyCFSQuotaBurst(ctx context.Context, burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta,
 	nodeState nodeStateForBurst) {
 	pod := podMeta.Pod
-	containerMap := make(map[string]*corev1.Container)
-	for i := range pod.Spec.Containers {
-		container := &pod.Spec.Containers[i]
-		containerMap[container.Name] = container
-	}
+	containerMap, containerStats, _ := getPodContainers(pod)
 
-	for i := range pod.Status.ContainerStatuses {
-		containerStat := &pod.Status.ContainerStatuses[i]
+	for _, containerStat := range containerStats {
 		container, exist := containerMap[containerStat.Name]
 		if !exist || container == nil {
 			klog.Warningf("container %s/%s/%s not found in pod spec", pod.Namespace, pod.Name, containerStat.Name)
@@ -414,7 +429,7 @@ func (b *cpuBurst) applyCFSQuotaBurst(burstCfg *slov1alpha1.CPUBurstConfig, podM
 			continue
 		}
 		deltaContainerCFS := containerTargetCFS - containerCurCFS
-		err = b.applyContainerCFSQuota(podMeta, containerStat, containerCurCFS, deltaContainerCFS)
+		err = b.applyContainerCFSQuota(ctx, podMeta, containerStat, containerCurCFS, deltaContainerCFS)
 		if err != nil {
 			klog.Infof("scale container %v/%v/%v cfs quota failed, operation %v, delta cfs quota %v, reason %v",
 				pod.Namespace, pod.Name, containerStat.Name, finalOperation, deltaContainerCFS, err)
@@ -504,7 +519,7 @@ func (b *cpuBurst) genOperationByContainer(burstCfg *slov1alpha1.CPUBurstConfig,
 	return cfsRemain
 }
 
-func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, containerStat *corev1.ContainerStatus,
+func (b *cpuBurst) applyContainerCFSQuota(ctx context.Context, podMeta *statesinformer.PodMeta, containerStat *corev1.ContainerStatus,
 	curContaienrCFS, deltaContainerCFS int64) error {
 	podDir := podMeta.CgroupDir
 	curPodCFS, podPathErr := b.cgroupReader.ReadCPUQuota(podDir)
@@ -528,7 +543,7 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 		podCFSValStr := strconv.FormatInt(targetPodCFS, 10)
 		eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason("CFSQuotaBurst").Message("update pod CFSQuota: %v", podCFSValStr)
 		updater, _ := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, podDir, podCFSValStr, eventHelper)
-		if _, err := b.executor.Update(true, updater); err != nil {
+		if _, err := b.executor.Update(ctx, true, updater); err != nil {
 			return fmt.Errorf("update pod cgroup %v failed, error %v", podMeta.CgroupDir, err)
 		}
 
@@ -540,7 +555,7 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 		containerCFSValStr := strconv.FormatInt(targetContainerCFS, 10)
 		eventHelper := audit.V(3).Container(containerStat.Name).Reason("CFSQuotaBurst").Message("update container CFSQuota: %v", containerCFSValStr)
 		updater, _ := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUCFSQuotaName, containerDir, containerCFSValStr, eventHelper)
-		if _, err := b.executor.Update(true, updater); err != nil {
+		if _, err := b.executor.Update(ctx, true, updater); err != nil {
 			return fmt.Errorf("update container cgroup %v failed, reason %v", containerDir, err)
 		}
 
@@ -564,17 +579,12 @@ func (b *cpuBurst) applyContainerCFSQuota(podMeta *statesinformer.PodMeta, conta
 }
 
 // set cpu.cfs_burst_us for containers
-func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta) {
+func (b *cpuBurst) applyCPUBurst(ctx context.Context, burstCfg *slov1alpha1.CPUBurstConfig, podMeta *statesinformer.PodMeta) {
 	pod := podMeta.Pod
-	containerMap := make(map[string]*corev1.Container)
-	for i := range pod.Spec.Containers {
-		container := &pod.Spec.Containers[i]
-		containerMap[container.Name] = container
-	}
+	containerMap, containerStats, initContainers := getPodContainers(pod)
 
-	podCFSBurstVal := int64(0)
-	for i := range pod.Status.ContainerStatuses {
-		containerStat := &pod.Status.ContainerStatuses[i]
+	podCFSBurstVal, podInitCFSBurstVal := int64(0), int64(0)
+	for _, containerStat := range containerStats {
 		container, exist := containerMap[containerStat.Name]
 		if !exist || container == nil {
 			klog.Warningf("container %s/%s/%s not found in pod spec", pod.Namespace, pod.Name, containerStat.Name)
@@ -586,6 +596,11 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 				pod.Namespace, pod.Name, containerStat.Name)
 			continue
 		}
+		if _, isInit := initContainers[containerStat.Name]; isInit && containerStat.State.Running == nil {
+			klog.V(6).Infof("skip init container %s/%s/%s, because it is not running",
+				pod.Namespace, pod.Name, containerStat.Name)
+			continue
+		}
 
 		containerCFSBurstVal := calcStaticCPUBurstVal(container, burstCfg)
 		containerDir, burstPathErr := koordletutil.GetContainerCgroupParentDir(podMeta.CgroupDir, containerStat)
@@ -595,7 +610,11 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 			continue
 		}
 
-		podCFSBurstVal += containerCFSBurstVal
+		if _, isInit := initContainers[containerStat.Name]; isInit {
+			podInitCFSBurstVal = util.MaxInt64(podInitCFSBurstVal, containerCFSBurstVal)
+		} else {
+			podCFSBurstVal += containerCFSBurstVal
+		}
 		containerCFSBurstValStr := strconv.FormatInt(containerCFSBurstVal, 10)
 		eventHelper := audit.V(3).Container(containerStat.Name).Reason("CPUBurst").Message("update container CPUBurst: %v", containerCFSBurstValStr)
 		updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUBurstName, containerDir, containerCFSBurstValStr, eventHelper)
@@ -604,7 +623,7 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 				pod.Namespace, pod.Name, containerStat.Name, err)
 			continue
 		}
-		updated, err := b.executor.Update(true, updater)
+		updated, err := b.executor.Update(ctx, true, updater)
 		if err != nil && system.IsResourceUnsupportedErr(err) {
 			klog.V(5).Infof("update container %v/%v/%v cpu burst failed, cfs burst not supported, dir %v, info %v",
 				pod.Namespace, pod.Name, containerStat.Name, containerDir, err)
@@ -618,6 +637,8 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 		}
 	} // end for containers
 
+	// init containers run one by one before the containers, so the pod takes the max of the sum and any init container
+	podCFSBurstVal = util.MaxInt64(podCFSBurstVal, podInitCFSBurstVal)
 	podDir := podMeta.CgroupDir
 	podCFSBurstValStr := strconv.FormatInt(podCFSBurstVal, 10)
 	eventHelper := audit.V(3).Pod(podMeta.Pod.Namespace, podMeta.Pod.Name).Reason("CPUBurst").Message("update pod CFSQuota: %v", podCFSBurstValStr)
@@ -627,7 +648,7 @@ func (b *cpuBurst) applyCPUBurst(burstCfg *slov1alpha1.CPUBurstConfig, podMeta *
 			pod.Namespace, pod.Name, err)
 		return
 	}
-	updated, err := b.executor.Update(true, updater)
+	updated, err := b.executor.Update(ctx, true, updater)
 	if err != nil && system.IsResourceUnsupportedErr(err) {
 		klog.V(5).Infof("update pod %v/%v cpu burst failed, cfs burst not supported, dir %v, info %v",
 			pod.Namespace, pod.Name, podDir, err)
@@ -649,6 +670,31 @@ func (b *cpuBurst) Recycle() {
 	}
 }
 
+// getPodContainers returns the specs and the statuses of both the containers and the init containers of the pod, and
+// the names of the init containers, since the init containers can also be throttled when they are running.
+func getPodContainers(pod *corev1.Pod) (map[string]*corev1.Container, []*corev1.ContainerStatus, map[string]struct{}) {
+	containerMap := make(map[string]*corev1.Container, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	initContainers := make(map[string]struct{}, len(pod.Spec.InitContainers))
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		containerMap[container.Name] = container
+		initContainers[container.Name] = struct{}{}
+	}
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		containerMap[container.Name] = container
+	}
+
+	containerStats := make([]*corev1.ContainerStatus, 0, len(pod.Status.ContainerStatuses)+len(pod.Status.InitContainerStatuses))
+	for i := range pod.Status.InitContainerStatuses {
+		containerStats = append(containerStats, &pod.Status.InitContainerStatuses[i])
+	}
+	for i := range pod.Status.ContainerStatuses {
+		containerStats = append(containerStats, &pod.Status.ContainerStatuses[i])
+	}
+	return containerMap, containerStats, initContainers
+}
+
 // container cpu.cfs_burst_us = container.limit * burstCfg.CPUBurstPercent * cfs_period_us
 func calcStaticCPUBurstVal(container *corev1.Container, burstCfg *slov1alpha1.CPUBurstConfig) int64 {
 	if !cpuBurstEnabled(burstCfg.Policy) {
@@ -663,6 +709,11 @@ func calcStaticCPUBurstVal(container *corev1.Container, burstCfg *slov1alpha1.CP
 
 	cpuCoresBurst := (float64(containerCPUMilliLimit) / 1000) * (float64(*burstCfg.CPUBurstPercent) / 100)
 	containerCFSBurstVal := int64(cpuCoresBurst * float64(system.CFSBasePeriodValue))
+	if system.GetCurrentCgroupVersion() == system.CgroupVersionV2 {
+		// the upstream kernel rejects the cpu.max.burst larger than the quota of the cpu.max
+		containerCFSQuotaVal := containerCPUMilliLimit * system.CFSBasePeriodValue / 1000
+		containerCFSBurstVal = util.MinInt64(containerCFSBurstVal, containerCFSQuotaVal)
+	}
 	return containerCFSBurstVal
 }
 
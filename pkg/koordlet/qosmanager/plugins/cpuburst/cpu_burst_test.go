@@ -17,6 +17,7 @@ limitations under the License.
 package cpuburst
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -683,7 +684,7 @@ func TestCPUBurst_applyCPUBurst(t *testing.T) {
 			initPodCPUBurst(podMeta, 0, testHelper)
 			initContainerCPUBurst(podMeta, 0, testHelper)
 
-			b.applyCPUBurst(&tt.args.burstCfg, podMeta)
+			b.applyCPUBurst(context.TODO(), &tt.args.burstCfg, podMeta)
 
 			for i := range podMeta.Pod.Status.ContainerStatuses {
 				containerStat := &podMeta.Pod.Status.ContainerStatuses[i]
@@ -702,6 +703,85 @@ func TestCPUBurst_applyCPUBurst(t *testing.T) {
 	}
 }
 
+func TestCPUBurst_applyCPUBurstWithInitContainers(t *testing.T) {
+	testHelper := system.NewFileTestUtil(t)
+
+	b := &cpuBurst{
+		executor: newTestExecutor(),
+	}
+	stop := make(chan struct{})
+	b.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	podMeta := createPodMetaByResource("test-pod-1", map[string]corev1.ResourceRequirements{
+		"test-container-1": {
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(5000, resource.DecimalSI),
+			},
+		},
+		"test-container-2": {
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(3000, resource.DecimalSI),
+			},
+		},
+	})
+	podMeta.Pod.Spec.InitContainers = []corev1.Container{
+		{
+			Name: "test-init-container-1",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU: *resource.NewMilliQuantity(20000, resource.DecimalSI),
+				},
+			},
+		},
+		{
+			Name: "test-init-container-2",
+			Resources: corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU: *resource.NewMilliQuantity(10000, resource.DecimalSI),
+				},
+			},
+		},
+	}
+	podMeta.Pod.Status.InitContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name:        "test-init-container-1",
+			ContainerID: genTestContainerIDByName("test-init-container-1"),
+			State:       corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+		},
+		{
+			Name:        "test-init-container-2",
+			ContainerID: genTestContainerIDByName("test-init-container-2"),
+			State:       corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+		},
+	}
+
+	initPodCPUBurst(podMeta, 0, testHelper)
+	initContainerCPUBurst(podMeta, 0, testHelper)
+	for i := range podMeta.Pod.Status.InitContainerStatuses {
+		containerPath, _ := util.GetContainerCgroupParentDir(podMeta.CgroupDir, &podMeta.Pod.Status.InitContainerStatuses[i])
+		testHelper.WriteCgroupFileContents(containerPath, system.CPUBurst, "0")
+	}
+
+	b.applyCPUBurst(context.TODO(), &defaultAutoBurstCfg, podMeta)
+
+	wantBurstVal := map[string]int64{
+		"test-container-1":      5 * 10 * system.CFSBasePeriodValue,
+		"test-container-2":      3 * 10 * system.CFSBasePeriodValue,
+		"test-init-container-1": 0, // terminated
+		"test-init-container-2": 10 * 10 * system.CFSBasePeriodValue,
+	}
+	for _, containerStats := range [][]corev1.ContainerStatus{podMeta.Pod.Status.ContainerStatuses, podMeta.Pod.Status.InitContainerStatuses} {
+		for i := range containerStats {
+			containerStat := &containerStats[i]
+			got := getContainerCPUBurst(podMeta.CgroupDir, containerStat, testHelper)
+			assert.Equal(t, wantBurstVal[containerStat.Name], got, containerStat.Name)
+		}
+	}
+	// max of the sum of the containers and the running init container
+	assert.Equal(t, 10*10*system.CFSBasePeriodValue, getPodCPUBurst(podMeta.CgroupDir, testHelper))
+}
+
 func TestCPUBurst_applyCFSQuotaBurst(t *testing.T) {
 	testPodName1 := "test-pod-1"
 	testContainerName1 := "test-container-1"
@@ -1282,7 +1362,7 @@ func TestCPUBurst_applyCFSQuotaBurst(t *testing.T) {
 				containerLimiter: make(map[string]*burstLimiter),
 			}
 			b.init(stop)
-			b.applyCFSQuotaBurst(&tt.args.burstCfg, podMeta, tt.args.nodeState)
+			b.applyCFSQuotaBurst(context.TODO(), &tt.args.burstCfg, podMeta, tt.args.nodeState)
 
 			gotPod := getPodCFSQuota(podMeta, testHelper)
 			if !reflect.DeepEqual(gotPod, tt.want.podCFSQuotaVal) {
@@ -1551,6 +1631,75 @@ func TestCPUBurst_start(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "reset-for-pod-denied-by-workload-policy",
+			fields: fields{
+				nodeCPUUsed: resource.NewQuantity(10, resource.DecimalSI),
+				podsMetric: map[string]podMetricSample{
+					lsrPodName: {lsrPodName, 7},
+					lsPodName:  {lsPodName, 0.2},
+				},
+				nodeCPUInfo: testNodeInfo,
+				pods: []*corev1.Pod{
+					newTestPodWithQOS(lsrPodName, apiext.QoSLSR, 8000, 8000),
+					newTestPodWithQOS(lsPodName, apiext.QoSLS, 1000, 1000),
+				},
+				nodeSLO: &slov1alpha1.NodeSLO{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test-node-1",
+					},
+					Spec: slov1alpha1.NodeSLOSpec{
+						CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+							CPUBurstConfig:            defaultAutoBurstCfg,
+							SharePoolThresholdPercent: pointer.Int64(50),
+							WorkloadPolicy: &slov1alpha1.CPUBurstWorkloadPolicy{
+								DeniedKinds: []string{"Pod"},
+							},
+						},
+					},
+				},
+				podsCurCFSQuota: map[string]int64{
+					lsrPodName: -1,
+					lsPodName:  2 * system.CFSBasePeriodValue,
+				},
+				containerCurCFSQuota: map[string]int64{
+					lsrContainerName: -1,
+					lsContainerName:  2 * system.CFSBasePeriodValue,
+				},
+				containersThrottled: map[string]testThrottledMetrics{
+					lsrContainerID: {
+						count: 1,
+						aggregateValues: map[metriccache.AggregationType]float64{
+							metriccache.AggregationTypeLast: 0,
+						},
+					},
+					lsContainerID: {
+						count: 1,
+						aggregateValues: map[metriccache.AggregationType]float64{
+							metriccache.AggregationTypeLast: 0.5,
+						},
+					},
+				},
+			},
+			want: want{
+				podBurstVal: map[string]int64{
+					lsrPodName: 0,
+					lsPodName:  0,
+				},
+				podCFSQuotaVal: map[string]int64{
+					lsrPodName: -1,
+					lsPodName:  1 * system.CFSBasePeriodValue,
+				},
+				containerBurstVal: map[string]int64{
+					lsrContainerName: 0,
+					lsContainerName:  0,
+				},
+				containerCFSQuotaVal: map[string]int64{
+					lsrContainerName: -1,
+					lsContainerName:  1 * system.CFSBasePeriodValue,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1606,6 +1755,7 @@ func TestCPUBurst_start(t *testing.T) {
 			}
 
 			testHelper := system.NewFileTestUtil(t)
+			testHelper.WriteCgroupFileContents(system.CgroupPathFormatter.ParentDir, system.CPUBurst, "0")
 
 			b := newTestCPUBurst(opt)
 			stop := make(chan struct{})
@@ -1620,7 +1770,7 @@ func TestCPUBurst_start(t *testing.T) {
 				initContainerCFSQuota(podMeta, tt.fields.containerCurCFSQuota, testHelper)
 			}
 
-			b.start()
+			b.start(context.TODO())
 
 			for _, podMeta := range podMetas {
 				wantPodCPUBurst := tt.want.podBurstVal[podMeta.Pod.Name]
@@ -1794,3 +1944,26 @@ func Test_genPodBurstConfigWithPlugin(t *testing.T) {
 	}
 	framework.UnregisterQOSGreyCtrlPlugin(p.name())
 }
+
+func Test_calcStaticCPUBurstVal(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	container := &corev1.Container{
+		Name: "test-container",
+		Resources: corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+	}
+	burstCfg := &slov1alpha1.CPUBurstConfig{
+		Policy:          slov1alpha1.CPUBurstAuto,
+		CPUBurstPercent: pointer.Int64(1000),
+	}
+	assert.Equal(t, 20*system.CFSBasePeriodValue, calcStaticCPUBurstVal(container, burstCfg))
+
+	// the burst is no more than the quota on cgroup v2
+	helper.SetCgroupsV2(true)
+	assert.Equal(t, 2*system.CFSBasePeriodValue, calcStaticCPUBurstVal(container, burstCfg))
+	burstCfg.CPUBurstPercent = pointer.Int64(50)
+	assert.Equal(t, system.CFSBasePeriodValue, calcStaticCPUBurstVal(container, burstCfg))
+}
@@ -19,7 +19,6 @@ package cpuevict
 import (
 	"fmt"
 	"math"
-	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -35,7 +34,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 const (
@@ -82,7 +83,7 @@ func (c *cpuEvictor) Setup(ctx *framework.Context) {
 }
 
 func (c *cpuEvictor) Run(stopCh <-chan struct{}) {
-	go wait.Until(c.cpuEvict, c.evictInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, CPUEvictName, c.cpuEvict), c.evictInterval, stopCh)
 }
 
 type podEvictCPUInfo struct {
@@ -287,7 +288,7 @@ func (c *cpuEvictor) evictByResourceSatisfaction(node *corev1.Node, thresholdCon
 	}
 	milliRelease := c.calculateMilliRelease(thresholdConfig, windowSeconds)
 	if milliRelease > 0 {
-		bePodInfos := c.getPodEvictInfoAndSort()
+		bePodInfos := c.getPodEvictInfoAndSort(helpers.GetCompletionProtectionWindow(thresholdConfig))
 		c.killAndEvictBEPodsRelease(node, bePodInfos, milliRelease)
 	}
 }
@@ -327,7 +328,7 @@ func (c *cpuEvictor) killAndEvictBEPodsRelease(node *corev1.Node, bePodInfos []*
 		cpuNeedMilliRelease, cpuMilliReleased)
 }
 
-func (c *cpuEvictor) getPodEvictInfoAndSort() []*podEvictCPUInfo {
+func (c *cpuEvictor) getPodEvictInfoAndSort(protectionWindow time.Duration) []*podEvictCPUInfo {
 	var bePodInfos []*podEvictCPUInfo
 
 	for _, podMeta := range c.statesInformer.GetAllPods() {
@@ -360,14 +361,33 @@ func (c *cpuEvictor) getPodEvictInfoAndSort() []*podEvictCPUInfo {
 		}
 	}
 
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		if bePodInfos[i].pod.Spec.Priority == nil || bePodInfos[j].pod.Spec.Priority == nil ||
-			*bePodInfos[i].pod.Spec.Priority == *bePodInfos[j].pod.Spec.Priority {
-			return bePodInfos[i].cpuUsage > bePodInfos[j].cpuUsage
+	podInfoMap := make(map[*corev1.Pod]*podEvictCPUInfo, len(bePodInfos))
+	bePods := make([]*corev1.Pod, 0, len(bePodInfos))
+	for _, info := range bePodInfos {
+		podInfoMap[info.pod] = info
+		bePods = append(bePods, info.pod)
+	}
+	bePods = sorter.FilterVictims(bePods)
+
+	// compare priority > completion protection > cpu usage > custom victim rules > name
+	// the priority is skipped if either pod does not specify it, so these pods are ordered by the cpu usage
+	cpuUsage := func(p1, p2 *corev1.Pod) int {
+		usage1, usage2 := podInfoMap[p1].cpuUsage, podInfoMap[p2].cpuUsage
+		if usage1 == usage2 {
+			return 0
+		}
+		if usage1 > usage2 {
+			return -1
 		}
-		return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
-	})
-	return bePodInfos
+		return 1
+	}
+	sorter.VictimSorter(sorter.SpecifiedPriority, sorter.CompletionProtection(protectionWindow), cpuUsage).Sort(bePods)
+
+	sortedPodInfos := make([]*podEvictCPUInfo, 0, len(bePods))
+	for _, pod := range bePods {
+		sortedPodInfos = append(sortedPodInfos, podInfoMap[pod])
+	}
+	return sortedPodInfos
 }
 
 func (c *cpuEvictor) getBEMilliAllocatable() float64 {
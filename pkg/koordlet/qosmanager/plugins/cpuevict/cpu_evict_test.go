@@ -432,12 +432,26 @@ func Test_getPodEvictInfoAndSort(t *testing.T) {
 		CPURequest   resource.Quantity // sum(extendResources_Cpu:request) by all qos:BE pod
 	}
 
+	nearCompletion := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type:   apiext.PodConditionNearCompletion,
+			Status: corev1.ConditionTrue,
+		})
+		return pod
+	}
+
+	withoutPriority := func(pod *corev1.Pod) *corev1.Pod {
+		pod.Spec.Priority = nil
+		return pod
+	}
+
 	tests := []struct {
-		name       string
-		podMetrics []podMetricSample
-		pods       []*corev1.Pod
-		beMetric   BECPUResourceMetric
-		expect     []*podEvictCPUInfo
+		name             string
+		podMetrics       []podMetricSample
+		pods             []*corev1.Pod
+		beMetric         BECPUResourceMetric
+		protectionWindow time.Duration
+		expect           []*podEvictCPUInfo
 	}{
 		{
 			name: "test_sort",
@@ -480,6 +494,73 @@ func Test_getPodEvictInfoAndSort(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "test_sort_with_completion_protection",
+			podMetrics: []podMetricSample{
+				{UID: "pod_be_1_priority100", CPUUsed: 3},
+				{UID: "pod_be_2_priority100", CPUUsed: 4},
+				{UID: "pod_be_3_priority10", CPUUsed: 4},
+			},
+			pods: []*corev1.Pod{
+				mockBEPodForCPUEvict("pod_be_1_priority100", 16*1000, 100),
+				nearCompletion(mockBEPodForCPUEvict("pod_be_2_priority100", 16*1000, 100)),
+				mockBEPodForCPUEvict("pod_be_3_priority10", 16*1000, 10),
+			},
+			beMetric: BECPUResourceMetric{
+				CPUUsed:    *resource.NewMilliQuantity(11*1000, resource.DecimalSI),
+				CPURequest: *resource.NewMilliQuantity(48*1000, resource.DecimalSI),
+			},
+			protectionWindow: 10 * time.Minute,
+			expect: []*podEvictCPUInfo{
+				{
+					pod:            mockBEPodForCPUEvict("pod_be_3_priority10", 16*1000, 10),
+					milliRequest:   16 * 1000,
+					milliUsedCores: 4 * 1000,
+					cpuUsage:       float64(4*1000) / float64(16*1000),
+				},
+				{
+					pod:            mockBEPodForCPUEvict("pod_be_1_priority100", 16*1000, 100),
+					milliRequest:   16 * 1000,
+					milliUsedCores: 3 * 1000,
+					cpuUsage:       float64(3*1000) / float64(16*1000),
+				},
+				{
+					pod:            mockBEPodForCPUEvict("pod_be_2_priority100", 16*1000, 100),
+					milliRequest:   16 * 1000,
+					milliUsedCores: 4 * 1000,
+					cpuUsage:       float64(4*1000) / float64(16*1000),
+				},
+			},
+		},
+		{
+			name: "test_sort_without_priority",
+			podMetrics: []podMetricSample{
+				{UID: "pod_be_1_priority100", CPUUsed: 8},
+				{UID: "pod_be_2_no_priority", CPUUsed: 4},
+			},
+			pods: []*corev1.Pod{
+				mockBEPodForCPUEvict("pod_be_1_priority100", 16*1000, 100),
+				withoutPriority(mockBEPodForCPUEvict("pod_be_2_no_priority", 16*1000, 0)),
+			},
+			beMetric: BECPUResourceMetric{
+				CPUUsed:    *resource.NewMilliQuantity(12*1000, resource.DecimalSI),
+				CPURequest: *resource.NewMilliQuantity(32*1000, resource.DecimalSI),
+			},
+			expect: []*podEvictCPUInfo{
+				{
+					pod:            mockBEPodForCPUEvict("pod_be_1_priority100", 16*1000, 100),
+					milliRequest:   16 * 1000,
+					milliUsedCores: 8 * 1000,
+					cpuUsage:       float64(8*1000) / float64(16*1000),
+				},
+				{
+					pod:            mockBEPodForCPUEvict("pod_be_2_no_priority", 16*1000, 0),
+					milliRequest:   16 * 1000,
+					milliUsedCores: 4 * 1000,
+					cpuUsage:       float64(4*1000) / float64(16*1000),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -509,7 +590,7 @@ func Test_getPodEvictInfoAndSort(t *testing.T) {
 			}
 			c := New(opt)
 			cpuEvictor := c.(*cpuEvictor)
-			got := cpuEvictor.getPodEvictInfoAndSort()
+			got := cpuEvictor.getPodEvictInfoAndSort(tt.protectionWindow)
 			assert.Equal(t, len(tt.expect), len(got), "checkLen")
 			for i, expectPodInfo := range tt.expect {
 				gotPodInfo := got[i]
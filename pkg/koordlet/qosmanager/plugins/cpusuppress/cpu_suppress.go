@@ -17,6 +17,7 @@ limitations under the License.
 package cpusuppress
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -40,6 +41,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
@@ -97,7 +99,7 @@ func (r *CPUSuppress) Setup(*framework.Context) {
 
 func (r *CPUSuppress) Run(stopCh <-chan struct{}) {
 	r.init(stopCh)
-	go wait.Until(r.suppressBECPU, r.interval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, CPUSuppressName, r.suppressBECPU), r.interval, stopCh)
 }
 
 func (r *CPUSuppress) init(stopCh <-chan struct{}) {
@@ -105,7 +107,7 @@ func (r *CPUSuppress) init(stopCh <-chan struct{}) {
 }
 
 // writeBECgroupsCPUSet writes the be cgroups cpuset by order
-func (r *CPUSuppress) writeBECgroupsCPUSet(paths []string, cpusetStr string, isReversed bool) {
+func (r *CPUSuppress) writeBECgroupsCPUSet(ctx context.Context, paths []string, cpusetStr string, isReversed bool) {
 	var updaters []resourceexecutor.ResourceUpdater
 	eventHelper := audit.V(3).Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cpuset: %v", cpusetStr)
 	if isReversed {
@@ -129,7 +131,7 @@ func (r *CPUSuppress) writeBECgroupsCPUSet(paths []string, cpusetStr string, isR
 			updaters = append(updaters, u)
 		}
 	}
-	r.executor.UpdateBatch(true, updaters...)
+	r.executor.UpdateBatch(ctx, true, updaters...)
 }
 
 // calculateBESuppressCPU calculates the quantity of cpuset cpus for suppressing BE pods.
@@ -169,7 +171,7 @@ func (r *CPUSuppress) calculateBESuppressCPU(node *corev1.Node, nodeMetric float
 	return nodeBESuppress
 }
 
-func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32) error {
+func (r *CPUSuppress) applyBESuppressCPUSet(ctx context.Context, beCPUSet []int32, oldCPUSet []int32) error {
 	nodeTopo := r.statesInformer.GetNodeTopo()
 	if nodeTopo == nil {
 		return errors.New("NodeTopo is nil")
@@ -180,19 +182,23 @@ func (r *CPUSuppress) applyBESuppressCPUSet(beCPUSet []int32, oldCPUSet []int32)
 		return fmt.Errorf("failed to get kubelet cpu manager policy, %w", err)
 	}
 	if kubeletPolicy.Policy == apiext.KubeletCPUManagerPolicyStatic {
-		r.recoverCPUSetIfNeed(koordletutil.PodCgroupPathRelativeDepth)
-		err = r.applyCPUSetWithStaticPolicy(beCPUSet)
+		r.recoverCPUSetIfNeed(ctx, koordletutil.PodCgroupPathRelativeDepth)
+		err = r.applyCPUSetWithStaticPolicy(ctx, beCPUSet)
 	} else {
-		err = r.applyCPUSetWithNonePolicy(beCPUSet, oldCPUSet)
+		err = r.applyCPUSetWithNonePolicy(ctx, beCPUSet, oldCPUSet)
 	}
 	if err != nil {
 		return fmt.Errorf("failed with kubelet policy %v, %w", kubeletPolicy.Policy, err)
 	}
+	actionID := audit.NewActionID()
+	metrics.RecordBESuppressAction(string(slov1alpha1.CPUSetPolicy), actionID)
+	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cpuset: %v",
+		cpuset.GenerateCPUSetStr(beCPUSet)).ActionID(actionID).Do()
 	return nil
 }
 
 // applyCPUSetWithNonePolicy applies the be suppress policy by writing best-effort cgroups
-func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32) error {
+func (r *CPUSuppress) applyCPUSetWithNonePolicy(ctx context.Context, cpus []int32, oldCPUSet []int32) error {
 	// 1. get current be cgroups cpuset
 	// 2. temporarily write with a union of old cpuset and new cpuset from upper to lower, to avoid cgroup conflicts
 	// 3. write with the new cpuset from lower to upper to apply the real policy
@@ -212,18 +218,18 @@ func (r *CPUSuppress) applyCPUSetWithNonePolicy(cpus []int32, oldCPUSet []int32)
 	mergedCPUSetStr := cpuset.GenerateCPUSetStr(mergedCPUSet)
 	klog.V(6).Infof("applyCPUSetWithNonePolicy temporarily writes cpuset from upper cgroup to lower, cpuset %v",
 		mergedCPUSet)
-	r.writeBECgroupsCPUSet(cpusetCgroupPaths, mergedCPUSetStr, false)
+	r.writeBECgroupsCPUSet(ctx, cpusetCgroupPaths, mergedCPUSetStr, false)
 
 	// apply the suppress policy from lower to upper
 	cpusetStr := cpuset.GenerateCPUSetStr(cpus)
 	klog.V(6).Infof("applyCPUSetWithNonePolicy writes suppressed cpuset from lower cgroup to upper, cpuset %v",
 		cpus)
-	r.writeBECgroupsCPUSet(cpusetCgroupPaths, cpusetStr, true)
+	r.writeBECgroupsCPUSet(ctx, cpusetCgroupPaths, cpusetStr, true)
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUSetPolicy), float64(len(cpus)))
 	return nil
 }
 
-func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32) error {
+func (r *CPUSuppress) applyCPUSetWithStaticPolicy(ctx context.Context, cpus []int32) error {
 	if len(cpus) <= 0 {
 		klog.Warningf("applyCPUSetWithStaticPolicy skipped due to the empty cpuset")
 		return nil
@@ -237,13 +243,13 @@ func (r *CPUSuppress) applyCPUSetWithStaticPolicy(cpus []int32) error {
 
 	cpusetStr := cpuset.GenerateCPUSetStr(cpus)
 	klog.V(6).Infof("applyCPUSetWithStaticPolicy writes suppressed cpuset to containers, cpuset %v", cpus)
-	r.writeBECgroupsCPUSet(containerPaths, cpusetStr, false)
+	r.writeBECgroupsCPUSet(ctx, containerPaths, cpusetStr, false)
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUSetPolicy), float64(len(cpus)))
 	return nil
 }
 
 // suppressBECPU adjusts the cpusets of BE pods to suppress BE cpu usage
-func (r *CPUSuppress) suppressBECPU() {
+func (r *CPUSuppress) suppressBECPU(ctx context.Context) {
 	// 1. calculate be suppress threshold and check if the suppress is needed
 	//    1.1. retrieve latest node resource usage from the metricCache
 	//    1.2  calculate the quantity of be suppress cpuset cpus
@@ -259,14 +265,16 @@ func (r *CPUSuppress) suppressBECPU() {
 		return
 	} else if features.DefaultKoordletFeatureGate.Enabled(features.BECPUSuppress) &&
 		features.DefaultKoordletFeatureGate.Enabled(features.BECPUManager) {
-		r.recoverCFSQuotaIfNeed()
-		r.recoverCPUSetForBECPUManager()
+		r.recoverCFSQuotaIfNeed(ctx)
+		r.recoverCPUIdleIfNeed(ctx)
+		r.recoverCPUSetForBECPUManager(ctx)
 		klog.V(5).Infof("suppressBECPU cannot work with BECPUManager together, suppress will be skipped, " +
 			"recover cpuset on all level if be pod does not specified numa node, and let be cpu set hook handle the others")
 		return
 	} else if disabled {
-		r.recoverCFSQuotaIfNeed()
-		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+		r.recoverCFSQuotaIfNeed(ctx)
+		r.recoverCPUIdleIfNeed(ctx)
+		r.recoverCPUSetIfNeed(ctx, koordletutil.ContainerCgroupPathRelativeDepth)
 		klog.V(5).Infof("suppressBECPU skipped, nodeSLO disable the featuregate")
 		return
 	}
@@ -316,18 +324,38 @@ func (r *CPUSuppress) suppressBECPU() {
 	if !ok {
 		klog.Fatalf("type error, expect %T， but got %T", metriccache.NodeCPUInfo{}, nodeCPUInfoRaw)
 	}
-	if nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy == slov1alpha1.CPUCfsQuotaPolicy {
-		r.adjustByCfsQuota(suppressCPUQuantity, node)
+	policy := nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressPolicy
+	if policy == slov1alpha1.CPUIdlePolicy && !isCPUIdleSupported() {
+		klog.V(4).Infof("suppressBECPU: cpu idle is not supported on the node, fallback to the %s policy", slov1alpha1.CPUSetPolicy)
+		policy = slov1alpha1.CPUSetPolicy
+	}
+	switch policy {
+	case slov1alpha1.CPUCfsQuotaPolicy:
+		r.adjustByCfsQuota(ctx, suppressCPUQuantity, node)
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyUsing
-		r.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
-	} else {
-		r.adjustByCPUSet(suppressCPUQuantity, nodeCPUInfo)
+		r.recoverCPUIdleIfNeed(ctx)
+		r.recoverCPUSetIfNeed(ctx, koordletutil.ContainerCgroupPathRelativeDepth)
+	case slov1alpha1.CPUIdlePolicy:
+		r.adjustByCPUIdle(ctx)
+		r.recoverCFSQuotaIfNeed(ctx)
+		r.recoverCPUSetIfNeed(ctx, koordletutil.ContainerCgroupPathRelativeDepth)
+	default:
+		if numaAware := nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressNUMAAware; numaAware != nil && *numaAware {
+			numaSuppressCPUs := r.calculateBESuppressCPUByNUMA(node, nodeCPUUsage, podMetrics, podMetas,
+				nodeSLO.Spec.HostApplications, hostAppMetrics,
+				*nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent,
+				nodeSLO.Spec.ResourceUsedThresholdWithBE.CPUSuppressMinPercent, nodeCPUInfo)
+			r.adjustByCPUSetPerNUMA(ctx, numaSuppressCPUs, nodeCPUInfo)
+		} else {
+			r.adjustByCPUSet(ctx, suppressCPUQuantity, nodeCPUInfo)
+		}
 		r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyUsing
-		r.recoverCFSQuotaIfNeed()
+		r.recoverCFSQuotaIfNeed(ctx)
+		r.recoverCPUIdleIfNeed(ctx)
 	}
 }
 
-func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
+func (r *CPUSuppress) adjustByCPUSet(ctx context.Context, cpusetQuantity *resource.Quantity, nodeCPUInfo *metriccache.NodeCPUInfo) {
 	rootCgroupParentDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
 	oldCPUS, err := r.cgroupReader.ReadCPUSet(rootCgroupParentDir)
 	if err != nil {
@@ -336,6 +364,38 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 	}
 	oldCPUSet := oldCPUS.ToInt32Slice()
 
+	lsrCpus, lsCpus, err := r.getBESuppressCPUCandidates(nodeCPUInfo)
+	if err != nil {
+		klog.Errorf("suppressBECPU failed to get candidate cpus, err: %v", err)
+		return
+	}
+
+	// set the number of cpuset cpus no less than 2
+	cpus := int32(math.Ceil(float64(cpusetQuantity.MilliValue()) / 1000))
+	if cpus < beMinCPUSetCores {
+		cpus = beMinCPUSetCores
+	}
+	beMaxIncreaseCpuNum := int32(math.Ceil(float64(len(nodeCPUInfo.ProcessorInfos)) * beMaxIncreaseCPUPercent))
+	if cpus-int32(len(oldCPUSet)) > beMaxIncreaseCpuNum {
+		cpus = int32(len(oldCPUSet)) + beMaxIncreaseCpuNum
+	}
+	beCPUSet := selectBESuppressCPUs(cpus, lsrCpus, lsCpus)
+
+	// the new be suppress always need to apply since:
+	// - for a reduce of BE cpuset, we should make effort to protecting LS no matter how huge the decrease is;
+	// - for a enlargement of BE cpuset, it is welcome and costless for BE processes.
+	err = r.applyBESuppressCPUSet(ctx, beCPUSet, oldCPUSet)
+	if err != nil {
+		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy, err: %s", err)
+		return
+	}
+	klog.Infof("suppressBECPU finished, suppress be cpu successfully: current cpuset %v", beCPUSet)
+}
+
+// getBESuppressCPUCandidates returns the cpus which can be allocated to BE pods, which are divided into the cpus of the
+// LSR pods and the shared cpus of the LS pods. The cpus reserved by the node and the exclusive cpus of the LSE and
+// system pods are excluded.
+func (r *CPUSuppress) getBESuppressCPUCandidates(nodeCPUInfo *metriccache.NodeCPUInfo) ([]koordletutil.ProcessorInfo, []koordletutil.ProcessorInfo, error) {
 	podMetas := r.statesInformer.GetAllPods()
 	// value: 0 -> lse, 1 -> lsr, not exists -> others
 	cpuIdToPool := map[int32]apiext.QoSClass{}
@@ -359,13 +419,12 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 
 	topo := r.statesInformer.GetNodeTopo()
 	if topo == nil {
-		klog.Errorf("node topo is nil")
-		return
+		return nil, nil, errors.New("node topo is nil")
 	}
 
-	var cpusetReserved cpuset.CPUSet
 	cpusReservedByAnno, _ := apiext.GetReservedCPUs(topo.Annotations)
-	if cpusetReserved, err = cpuset.Parse(cpusReservedByAnno); err != nil {
+	cpusetReserved, err := cpuset.Parse(cpusReservedByAnno)
+	if err != nil {
 		klog.Warningf("failed to parse cpuset from node reservation %v, err: %v", cpusReservedByAnno, err)
 	}
 
@@ -390,15 +449,14 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 			lsCpus = append(lsCpus, processor)
 		}
 	}
+	return lsrCpus, lsCpus, nil
+}
 
-	// set the number of cpuset cpus no less than 2
-	cpus := int32(math.Ceil(float64(cpusetQuantity.MilliValue()) / 1000))
-	if cpus < beMinCPUSetCores {
-		cpus = beMinCPUSetCores
-	}
-	beMaxIncreaseCpuNum := int32(math.Ceil(float64(len(nodeCPUInfo.ProcessorInfos)) * beMaxIncreaseCPUPercent))
-	if cpus-int32(len(oldCPUSet)) > beMaxIncreaseCpuNum {
-		cpus = int32(len(oldCPUSet)) + beMaxIncreaseCpuNum
+// selectBESuppressCPUs selects the cpus for BE pods from the cpus of LSR pods and LS pods in proportion.
+func selectBESuppressCPUs(cpus int32, lsrCpus, lsCpus []koordletutil.ProcessorInfo) []int32 {
+	if len(lsrCpus)+len(lsCpus) <= 0 {
+		klog.Warningf("failed to select cpus for be suppress, no available cpus")
+		return nil
 	}
 	var beCPUSet []int32
 	lsrCpuNums := int32(int(cpus) * len(lsrCpus) / (len(lsrCpus) + len(lsCpus)))
@@ -411,23 +469,14 @@ func (r *CPUSuppress) adjustByCPUSet(cpusetQuantity *resource.Quantity, nodeCPUI
 		beCPUSetFromLS := calculateBESuppressCPUSetPolicy(cpus-lsrCpuNums, lsCpus)
 		beCPUSet = append(beCPUSet, beCPUSetFromLS...)
 	}
-
-	// the new be suppress always need to apply since:
-	// - for a reduce of BE cpuset, we should make effort to protecting LS no matter how huge the decrease is;
-	// - for a enlargement of BE cpuset, it is welcome and costless for BE processes.
-	err = r.applyBESuppressCPUSet(beCPUSet, oldCPUSet)
-	if err != nil {
-		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy, err: %s", err)
-		return
-	}
-	klog.Infof("suppressBECPU finished, suppress be cpu successfully: current cpuset %v", beCPUSet)
+	return beCPUSet
 }
 
 // recover cpuset path as be share pool for the following dirs:
 // - besteffort dir
 // - besteffort/pod dir
 // - besteffort/pod/container if pod does not specify resource status(cpuset/numa node)
-func (r *CPUSuppress) recoverCPUSetForBECPUManager() {
+func (r *CPUSuppress) recoverCPUSetForBECPUManager(ctx context.Context) {
 	beCPUSet, err := r.calcBECPUSet()
 	if err != nil {
 		klog.Warningf("get be cpuset failed during recoverCPUSetForBECPUManager, error %v", err)
@@ -496,11 +545,11 @@ func (r *CPUSuppress) recoverCPUSetForBECPUManager() {
 
 	cpusetStr := beCPUSet.String()
 	klog.V(5).Infof("recover bestEffort cpuset with be cpu manager, cpuset %v", cpusetStr)
-	r.writeBECgroupsCPUSet(cpusetToRecover, cpusetStr, false)
+	r.writeBECgroupsCPUSet(ctx, cpusetToRecover, cpusetStr, false)
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyRecovered
 }
 
-func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
+func (r *CPUSuppress) recoverCPUSetIfNeed(ctx context.Context, maxDepth int) {
 	beCPUSet, err := r.calcBECPUSet()
 	if err != nil {
 		klog.Warningf("get be cpuset failed during recoverCPUSetIfNeed, error %v", err)
@@ -518,7 +567,7 @@ func (r *CPUSuppress) recoverCPUSetIfNeed(maxDepth int) {
 
 	cpusetStr := beCPUSet.String()
 	klog.V(6).Infof("recover bestEffort cpuset, cpuset %v", cpusetStr)
-	r.writeBECgroupsCPUSet(cpusetCgroupPaths, cpusetStr, false)
+	r.writeBECgroupsCPUSet(ctx, cpusetCgroupPaths, cpusetStr, false)
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = policyRecovered
 }
 
@@ -593,7 +642,7 @@ func (r *CPUSuppress) calcBECPUSet() (*cpuset.CPUSet, error) {
 	return &beCPUSet, nil
 }
 
-func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *corev1.Node) {
+func (r *CPUSuppress) adjustByCfsQuota(ctx context.Context, cpuQuantity *resource.Quantity, node *corev1.Node) {
 	newBeQuota := cpuQuantity.MilliValue() * system.DefaultCPUCFSPeriod / 1000
 	newBeQuota = int64(math.Max(float64(newBeQuota), float64(beMinQuota)))
 
@@ -624,17 +673,19 @@ func (r *CPUSuppress) adjustByCfsQuota(cpuQuantity *resource.Quantity, node *cor
 		klog.V(4).Infof("failed to get be cfs quota updater, err: %v", err)
 		return
 	}
-	isUpdated, err := r.executor.Update(false, updater)
+	isUpdated, err := r.executor.Update(ctx, false, updater)
 	if err != nil {
 		klog.Errorf("suppressBECPU: failed to write cfs_quota_us for be pods, error: %v", err)
 		return
 	}
+	actionID := audit.NewActionID()
 	metrics.RecordBESuppressCores(string(slov1alpha1.CPUCfsQuotaPolicy), float64(newBeQuota)/float64(system.DefaultCPUCFSPeriod))
-	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).Do()
+	metrics.RecordBESuppressAction(string(slov1alpha1.CPUCfsQuotaPolicy), actionID)
+	_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cfs_quota: %v", newBeQuota).ActionID(actionID).Do()
 	klog.Infof("suppressBECPU: succeeded to write cfs_quota_us for offline pods, isUpdated %v, new value: %d", isUpdated, newBeQuota)
 }
 
-func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
+func (r *CPUSuppress) recoverCFSQuotaIfNeed(ctx context.Context) {
 	cfsQuotaPolicyStatus, exist := r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)]
 	if exist && cfsQuotaPolicyStatus == policyRecovered {
 		return
@@ -647,7 +698,7 @@ func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
 		klog.V(4).Infof("failed to get be cfs quota updater, err: %v", err)
 		return
 	}
-	isUpdated, err := r.executor.Update(false, updater)
+	isUpdated, err := r.executor.Update(ctx, false, updater)
 	if err != nil {
 		klog.Errorf("recover bestEffort cfsQuota err: %v", err)
 		return
@@ -656,6 +707,66 @@ func (r *CPUSuppress) recoverCFSQuotaIfNeed() {
 	r.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = policyRecovered
 }
 
+// isCPUIdleSupported checks if the BE cgroup can be marked as idle, which requires the cgroup v2 with the cpu.idle.
+func isCPUIdleSupported() bool {
+	if system.GetCurrentCgroupVersion() != system.CgroupVersionV2 {
+		return false
+	}
+	supported, msg := system.CPUIdleV2.IsSupported(koordletutil.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+	if !supported {
+		klog.V(5).Infof("cpu idle is not supported, msg: %s", msg)
+	}
+	return supported
+}
+
+// adjustByCPUIdle marks the BE cgroup as idle, so the BE tasks only use the cpus when the LS tasks are idle, and the
+// number of the cpus to suppress is not required.
+func (r *CPUSuppress) adjustByCPUIdle(ctx context.Context) {
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cpu.idle: 1")
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUIdleName, beCgroupPath, "1", eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get be cpu idle updater, err: %v", err)
+		return
+	}
+	isUpdated, err := r.executor.Update(ctx, false, updater)
+	if err != nil {
+		klog.Errorf("suppressBECPU: failed to write cpu.idle for be pods, error: %v", err)
+		return
+	}
+	if status := r.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)]; status != policyUsing {
+		actionID := audit.NewActionID()
+		metrics.RecordBESuppressAction(string(slov1alpha1.CPUIdlePolicy), actionID)
+		_ = audit.V(1).Node().Reason(resourceexecutor.AdjustBEByNodeCPUUsage).Message("update BE group to cpu.idle: 1").ActionID(actionID).Do()
+		klog.Infof("suppressBECPU: succeeded to write cpu.idle for offline pods, isUpdated %v", isUpdated)
+	}
+	r.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)] = policyUsing
+}
+
+// recoverCPUIdleIfNeed unmarks the BE cgroup as idle only if it is marked by the suppression, since the cpu.idle can
+// also be set by the core scheduling.
+func (r *CPUSuppress) recoverCPUIdleIfNeed(ctx context.Context) {
+	cpuIdlePolicyStatus, exist := r.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)]
+	if !exist || cpuIdlePolicyStatus == policyRecovered {
+		return
+	}
+
+	beCgroupPath := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	eventHelper := audit.V(3).Reason("suppressBECPU").Message("recover bestEffort cpu.idle to %v", "0")
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(system.CPUIdleName, beCgroupPath, "0", eventHelper)
+	if err != nil {
+		klog.V(4).Infof("failed to get be cpu idle updater, err: %v", err)
+		return
+	}
+	isUpdated, err := r.executor.Update(ctx, false, updater)
+	if err != nil {
+		klog.Errorf("recover bestEffort cpu.idle err: %v", err)
+		return
+	}
+	klog.V(5).Infof("successfully recover bestEffort cpu.idle, isUpdated %v", isUpdated)
+	r.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)] = policyRecovered
+}
+
 // calculateBESuppressPolicy calculates the be cpu suppress policy with cpuset cpus number and node cpu info
 func calculateBESuppressCPUSetPolicy(cpus int32, processorInfos []koordletutil.ProcessorInfo) []int32 {
 	var CPUSets []int32
@@ -0,0 +1,222 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// calculateBESuppressCPUByNUMA calculates the milli-cpus for suppressing BE pods on each NUMA node.
+// The usage of the non-BE pods bound to the cpuset or the NUMA nodes is counted on the NUMA nodes they are bound to,
+// and the other non-BE usage (shared pods, host applications and system) is spread by the cpus of each NUMA node.
+func (r *CPUSuppress) calculateBESuppressCPUByNUMA(node *corev1.Node, nodeMetric float64, podMetrics map[string]float64,
+	podMetas []*statesinformer.PodMeta, hostApps []slov1alpha1.HostApplicationSpec, hostAppMetrics map[string]float64,
+	beCPUUsedThreshold int64, beCPUMinThreshold *int64, nodeCPUInfo *metriccache.NodeCPUInfo) map[int32]int64 {
+	if nodeCPUInfo == nil || len(nodeCPUInfo.ProcessorInfos) <= 0 {
+		return nil
+	}
+	cpuToNUMA := map[int32]int32{}
+	numaCPUNums := map[int32]int64{}
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		cpuToNUMA[processor.CPUID] = processor.NodeID
+		numaCPUNums[processor.NodeID]++
+	}
+	totalCPUNums := int64(len(nodeCPUInfo.ProcessorInfos))
+
+	nodeReserved := helpers.GetNodeResourceReserved(node)
+	nodeReservedCPU := float64(nodeReserved.Cpu().MilliValue()) / 1000
+	podNonBEUsedCPU, hostAppNonBEUsedCPU, systemUsedCPU := helpers.CalculateFilterPodsUsed(nodeMetric, nodeReservedCPU,
+		podMetas, podMetrics, hostApps, hostAppMetrics, helpers.NonBEPodFilter, helpers.NonBEHostAppFilter)
+
+	numaUsedCPU := map[int32]float64{}
+	var boundPodUsedCPU float64
+	for _, podMeta := range podMetas {
+		if !helpers.NonBEPodFilter(podMeta.Pod) {
+			continue
+		}
+		podUsed, ok := podMetrics[string(podMeta.Pod.UID)]
+		if !ok {
+			continue
+		}
+		weights := getPodNUMAWeights(podMeta.Pod, cpuToNUMA)
+		if len(weights) <= 0 {
+			continue
+		}
+		for numaID, weight := range weights {
+			numaUsedCPU[numaID] += podUsed * weight
+		}
+		boundPodUsedCPU += podUsed
+	}
+	sharedUsedCPU := podNonBEUsedCPU + hostAppNonBEUsedCPU + systemUsedCPU - boundPodUsedCPU
+	if sharedUsedCPU < 0 {
+		sharedUsedCPU = 0
+	}
+
+	// suppress(BE, NUMA) := node.Capacity * numa.CPUs / node.CPUs * SLOPercent - numa.BoundUsed - shared.Used * numa.CPUs / node.CPUs
+	capacityMilli := node.Status.Capacity.Cpu().MilliValue()
+	numaSuppress := make(map[int32]int64, len(numaCPUNums))
+	for numaID, cpuNums := range numaCPUNums {
+		numaCapacityMilli := capacityMilli * cpuNums / totalCPUNums
+		usedCPU := numaUsedCPU[numaID] + sharedUsedCPU*float64(cpuNums)/float64(totalCPUNums)
+		suppressMilli := numaCapacityMilli*beCPUUsedThreshold/100 - int64(usedCPU*1000)
+		if beCPUMinThreshold != nil {
+			if beMinMilli := numaCapacityMilli * *beCPUMinThreshold / 100; suppressMilli < beMinMilli {
+				suppressMilli = beMinMilli
+			}
+		}
+		numaSuppress[numaID] = suppressMilli
+		klog.V(6).Infof("numaSuppressBE[CPU(Milli)]:%v = numa %v capacity:%v * SLOPercent:%v%% - used:%v",
+			suppressMilli, numaID, numaCapacityMilli, beCPUUsedThreshold, usedCPU)
+	}
+	return numaSuppress
+}
+
+// getPodNUMAWeights returns the proportions of the pod usage on each NUMA node according to its resource status.
+// It returns nil if the pod is not bound to any cpuset or NUMA node.
+func getPodNUMAWeights(pod *corev1.Pod, cpuToNUMA map[int32]int32) map[int32]float64 {
+	status, err := apiext.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return nil
+	}
+	if status.CPUSet != "" {
+		set, err := cpuset.Parse(status.CPUSet)
+		if err != nil {
+			klog.V(5).Infof("failed to parse cpuset info of pod %s, err: %v", pod.Name, err)
+			return nil
+		}
+		numaCPUs := map[int32]int{}
+		total := 0
+		for _, cpuID := range set.ToSliceNoSort() {
+			numaID, ok := cpuToNUMA[int32(cpuID)]
+			if !ok {
+				continue
+			}
+			numaCPUs[numaID]++
+			total++
+		}
+		if total <= 0 {
+			return nil
+		}
+		weights := make(map[int32]float64, len(numaCPUs))
+		for numaID, cpus := range numaCPUs {
+			weights[numaID] = float64(cpus) / float64(total)
+		}
+		return weights
+	}
+	if len(status.NUMANodeResources) > 0 {
+		weights := make(map[int32]float64, len(status.NUMANodeResources))
+		for _, numaResource := range status.NUMANodeResources {
+			weights[numaResource.Node] = 1 / float64(len(status.NUMANodeResources))
+		}
+		return weights
+	}
+	return nil
+}
+
+// adjustByCPUSetPerNUMA adjusts the cpuset of BE pods on each NUMA node by the suppress milli-cpus of it, so the BE
+// pods are squeezed only on the busy NUMA nodes.
+func (r *CPUSuppress) adjustByCPUSetPerNUMA(ctx context.Context, numaSuppress map[int32]int64, nodeCPUInfo *metriccache.NodeCPUInfo) {
+	rootCgroupParentDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	oldCPUS, err := r.cgroupReader.ReadCPUSet(rootCgroupParentDir)
+	if err != nil {
+		klog.Warningf("applyBESuppressPolicy failed to get current best-effort cgroup cpuset, err: %s", err)
+		return
+	}
+	oldCPUSet := oldCPUS.ToInt32Slice()
+
+	lsrCpus, lsCpus, err := r.getBESuppressCPUCandidates(nodeCPUInfo)
+	if err != nil {
+		klog.Errorf("suppressBECPU failed to get candidate cpus, err: %v", err)
+		return
+	}
+
+	cpuToNUMA := map[int32]int32{}
+	numaCPUNums := map[int32]int{}
+	for _, processor := range nodeCPUInfo.ProcessorInfos {
+		cpuToNUMA[processor.CPUID] = processor.NodeID
+		numaCPUNums[processor.NodeID]++
+	}
+	oldNUMACPUNums := map[int32]int32{}
+	for _, cpuID := range oldCPUSet {
+		if numaID, ok := cpuToNUMA[cpuID]; ok {
+			oldNUMACPUNums[numaID]++
+		}
+	}
+	numaLSRCpus := groupProcessorsByNUMA(lsrCpus)
+	numaLSCpus := groupProcessorsByNUMA(lsCpus)
+
+	numaIDs := make([]int32, 0, len(numaSuppress))
+	for numaID := range numaSuppress {
+		numaIDs = append(numaIDs, numaID)
+	}
+	sort.Slice(numaIDs, func(i, j int) bool {
+		return numaIDs[i] < numaIDs[j]
+	})
+	var beCPUSet []int32
+	for _, numaID := range numaIDs {
+		cpus := int32(math.Ceil(float64(numaSuppress[numaID]) / 1000))
+		if cpus <= 0 {
+			continue
+		}
+		// scale up slow on each NUMA node
+		beMaxIncreaseCpuNum := int32(math.Ceil(float64(numaCPUNums[numaID]) * beMaxIncreaseCPUPercent))
+		if cpus-oldNUMACPUNums[numaID] > beMaxIncreaseCpuNum {
+			cpus = oldNUMACPUNums[numaID] + beMaxIncreaseCpuNum
+		}
+		if available := int32(len(numaLSRCpus[numaID]) + len(numaLSCpus[numaID])); cpus > available {
+			cpus = available
+		}
+		if cpus <= 0 {
+			continue
+		}
+		beCPUSet = append(beCPUSet, selectBESuppressCPUs(cpus, numaLSRCpus[numaID], numaLSCpus[numaID])...)
+	}
+	// keep the number of cpuset cpus no less than 2 on the node
+	if len(beCPUSet) < beMinCPUSetCores {
+		klog.V(5).Infof("suppressBECPU got cpuset %v by NUMA nodes less than %v cpus, select cpus on the node instead",
+			beCPUSet, beMinCPUSetCores)
+		beCPUSet = selectBESuppressCPUs(beMinCPUSetCores, lsrCpus, lsCpus)
+	}
+
+	err = r.applyBESuppressCPUSet(ctx, beCPUSet, oldCPUSet)
+	if err != nil {
+		klog.Warningf("suppressBECPU failed to apply be cpu suppress policy by NUMA nodes, err: %s", err)
+		return
+	}
+	klog.Infof("suppressBECPU finished, suppress be cpu by NUMA nodes successfully: current cpuset %v", beCPUSet)
+}
+
+func groupProcessorsByNUMA(processors []koordletutil.ProcessorInfo) map[int32][]koordletutil.ProcessorInfo {
+	numaProcessors := map[int32][]koordletutil.ProcessorInfo{}
+	for _, processor := range processors {
+		numaProcessors[processor.NodeID] = append(numaProcessors[processor.NodeID], processor)
+	}
+	return numaProcessors
+}
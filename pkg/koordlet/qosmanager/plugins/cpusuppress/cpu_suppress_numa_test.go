@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpusuppress
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func testNUMANodeCPUInfo() *metriccache.NodeCPUInfo {
+	return &metriccache.NodeCPUInfo{
+		ProcessorInfos: []koordletutil.ProcessorInfo{
+			{CPUID: 0, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 1, CoreID: 0, SocketID: 0, NodeID: 0},
+			{CPUID: 2, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 3, CoreID: 1, SocketID: 0, NodeID: 0},
+			{CPUID: 4, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 5, CoreID: 2, SocketID: 1, NodeID: 1},
+			{CPUID: 6, CoreID: 3, SocketID: 1, NodeID: 1},
+			{CPUID: 7, CoreID: 3, SocketID: 1, NodeID: 1},
+		},
+	}
+}
+
+func testNUMAPod(name string, qos apiext.QoSClass, kubeQoS corev1.PodQOSClass, resourceStatus string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				apiext.LabelPodQoS: string(qos),
+			},
+		},
+		Status: corev1.PodStatus{
+			QOSClass: kubeQoS,
+		},
+	}
+	if resourceStatus != "" {
+		pod.Annotations = map[string]string{
+			apiext.AnnotationResourceStatus: resourceStatus,
+		}
+	}
+	return pod
+}
+
+func Test_cpuSuppress_calculateBESuppressCPUByNUMA(t *testing.T) {
+	node := &corev1.Node{
+		Status: corev1.NodeStatus{
+			Capacity: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"),
+			},
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("8"),
+			},
+		},
+	}
+	podMetas := []*statesinformer.PodMeta{
+		{Pod: testNUMAPod("lsr-pod", apiext.QoSLSR, corev1.PodQOSGuaranteed, `{"cpuset": "0-1"}`)},
+		{Pod: testNUMAPod("ls-numa-pod", apiext.QoSLS, corev1.PodQOSBurstable, `{"numaNodeResources": [{"node": 1}]}`)},
+		{Pod: testNUMAPod("ls-pod", apiext.QoSLS, corev1.PodQOSBurstable, "")},
+		{Pod: testNUMAPod("be-pod", apiext.QoSBE, corev1.PodQOSBestEffort, `{"cpuset": "4-7"}`)},
+	}
+	podMetrics := map[string]float64{
+		"lsr-pod":     2,
+		"ls-numa-pod": 0.5,
+		"ls-pod":      1,
+		"be-pod":      3,
+	}
+	tests := []struct {
+		name       string
+		nodeMetric float64
+		minPercent *int64
+		want       map[int32]int64
+	}{
+		{
+			// shared used: ls-pod 1 + system 0.5, 0.75 on each NUMA node
+			// numa 0: 4 * 65% - 2 - 0.75 = -0.15
+			// numa 1: 4 * 65% - 0.5 - 0.75 = 1.35
+			name:       "calculate suppress cpus by NUMA nodes",
+			nodeMetric: 7,
+			want: map[int32]int64{
+				0: -150,
+				1: 1350,
+			},
+		},
+		{
+			name:       "calculate suppress cpus by NUMA nodes with min percent",
+			nodeMetric: 7,
+			minPercent: pointer.Int64(10),
+			want: map[int32]int64{
+				0: 400,
+				1: 1350,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &CPUSuppress{}
+			got := r.calculateBESuppressCPUByNUMA(node, tt.nodeMetric, podMetrics, podMetas, nil, nil,
+				65, tt.minPercent, testNUMANodeCPUInfo())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_cpuSuppress_adjustByCPUSetPerNUMA(t *testing.T) {
+	tests := []struct {
+		name         string
+		numaSuppress map[int32]int64
+		oldCPUSets   string
+		wantCPUSet   string
+	}{
+		{
+			name: "only squeeze the busy NUMA node",
+			numaSuppress: map[int32]int64{
+				0: 400,
+				1: 3000,
+			},
+			oldCPUSets: "0-7",
+			wantCPUSet: "1,4-6",
+		},
+		{
+			name: "scale up slow on each NUMA node",
+			numaSuppress: map[int32]int64{
+				0: 3000,
+				1: 3000,
+			},
+			oldCPUSets: "3,4",
+			wantCPUSet: "2-5",
+		},
+		{
+			name: "keep no less than 2 cpus on the node",
+			numaSuppress: map[int32]int64{
+				0: -150,
+				1: 0,
+			},
+			oldCPUSets: "0-7",
+			wantCPUSet: "2-3",
+		},
+	}
+	ctrl := gomock.NewController(t)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	lsrPod := mockLSRPod()
+	lsePod := mockLSEPod()
+	mockStatesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsrPod}, {Pod: lsePod}}).AnyTimes()
+	mockStatesInformer.EXPECT().GetNodeTopo().Return(&topov1alpha1.NodeResourceTopology{}).AnyTimes()
+	opt := &framework.Options{
+		StatesInformer:      mockStatesInformer,
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	cpuSuppress := newTestCPUSuppress(opt)
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		cpuSuppress.init(stop)
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			testingPrepareBECgroupData(helper, []string{"pod1"}, tt.oldCPUSets)
+
+			cpuSuppress.adjustByCPUSetPerNUMA(context.TODO(), tt.numaSuppress, testNUMANodeCPUInfo())
+
+			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
+			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup)
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cpusuppress
 
 import (
+	"context"
 	"encoding/json"
 	"path/filepath"
 	"strconv"
@@ -734,7 +735,7 @@ func Test_cpuSuppress_suppressBECPU(t *testing.T) {
 				cpuSuppress.init(stop)
 			})
 
-			cpuSuppress.suppressBECPU()
+			cpuSuppress.suppressBECPU(context.TODO())
 
 			// checkCFSQuota
 			gotBECFSQuota := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUCFSQuota)
@@ -1386,7 +1387,7 @@ func Test_cpuSuppress_recoverCPUSetIfNeed(t *testing.T) {
 			if tt.args.currentPolicyStatus != nil {
 				cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)] = *tt.args.currentPolicyStatus
 			}
-			cpuSuppress.recoverCPUSetIfNeed(koordletutil.ContainerCgroupPathRelativeDepth)
+			cpuSuppress.recoverCPUSetIfNeed(context.TODO(), koordletutil.ContainerCgroupPathRelativeDepth)
 			gotPolicyStatus := cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUSetPolicy)]
 			assert.Equal(t, *tt.wantPolicyStatus, gotPolicyStatus, "checkStatus")
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
@@ -1451,7 +1452,7 @@ func Test_cpuSuppress_recoverCFSQuotaIfNeed(t *testing.T) {
 				cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)] = *tt.currentPolicyStatus
 			}
 
-			cpuSuppress.recoverCFSQuotaIfNeed()
+			cpuSuppress.recoverCFSQuotaIfNeed(context.TODO())
 			gotPolicyStatus := cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUCfsQuotaPolicy)]
 			assert.Equal(t, *tt.wantPolicyStatus, gotPolicyStatus, "checkStatus")
 			gotBECfsQuota := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
@@ -1460,6 +1461,42 @@ func Test_cpuSuppress_recoverCFSQuotaIfNeed(t *testing.T) {
 	}
 }
 
+func Test_cpuSuppress_adjustByCPUIdle(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	beQosDir := koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+
+	// cpu idle is only supported on cgroup v2
+	helper.SetCgroupsV2(false)
+	assert.False(t, isCPUIdleSupported())
+	helper.SetCgroupsV2(true)
+	helper.WriteFileContents(system.CPUIdleV2.Path(koordletutil.GetPodQoSRelativePath(corev1.PodQOSGuaranteed)), "0")
+	helper.WriteFileContents(system.CPUIdleV2.Path(beQosDir), "0")
+	assert.True(t, isCPUIdleSupported())
+
+	r := framework.Options{
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	}
+	cpuSuppress := newTestCPUSuppress(&r)
+	stop := make(chan struct{})
+	defer close(stop)
+	cpuSuppress.init(stop)
+
+	// not recovered if it is not marked by the suppression
+	cpuSuppress.recoverCPUIdleIfNeed(context.TODO())
+	_, exist := cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)]
+	assert.False(t, exist)
+
+	cpuSuppress.adjustByCPUIdle(context.TODO())
+	assert.Equal(t, policyUsing, cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)])
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(beQosDir, system.CPUIdleV2))
+
+	cpuSuppress.recoverCPUIdleIfNeed(context.TODO())
+	assert.Equal(t, policyRecovered, cpuSuppress.suppressPolicyStatuses[string(slov1alpha1.CPUIdlePolicy)])
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(beQosDir, system.CPUIdleV2))
+}
+
 func Test_calculateBESuppressCPUSetPolicy(t *testing.T) {
 	type args struct {
 		cpus          int32
@@ -1630,7 +1667,7 @@ func Test_cpuSuppress_applyCPUSetWithNonePolicy(t *testing.T) {
 		r.init(stop)
 	})
 
-	err = r.applyCPUSetWithNonePolicy(cpuset, oldCPUSet)
+	err = r.applyCPUSetWithNonePolicy(context.TODO(), cpuset, oldCPUSet)
 	assert.NoError(t, err)
 	gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 	assert.Equal(t, wantCPUSetStr, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1731,7 +1768,7 @@ func Test_cpuSuppress_adjustByCPUSet(t *testing.T) {
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(context.TODO(), tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1843,7 +1880,7 @@ func Test_cpuSuppress_adjustByCPUSet_withCPUsReservedFromNodeAnno(t *testing.T)
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(context.TODO(), tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -1945,7 +1982,7 @@ func Test_cpuSuppress_adjustByCPUSet_withSystemQOSResourceFromNodeAnno(t *testin
 			podDirs := []string{"pod1", "pod2", "pod3"}
 			testingPrepareBECgroupData(helper, podDirs, tt.args.oldCPUSets)
 
-			cpuSuppress.adjustByCPUSet(tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
+			cpuSuppress.adjustByCPUSet(context.TODO(), tt.args.cpusetQuantity, tt.args.nodeCPUInfo)
 
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 			assert.Equal(t, tt.wantCPUSet, gotCPUSetBECgroup, "checkBECPUSet")
@@ -2022,7 +2059,7 @@ func Test_cpuSuppress_adjustByCfsQuota(t *testing.T) {
 			assert.NotPanics(t, func() {
 				r.init(stop)
 			})
-			r.adjustByCfsQuota(tt.cpuQuantity, node)
+			r.adjustByCfsQuota(context.TODO(), tt.cpuQuantity, node)
 			gotBECfsQuota := helper.ReadCgroupFileContents(beQosDir, system.CPUCFSQuota)
 			if gotBECfsQuota != strconv.FormatInt(tt.wantBECfsQuota, 10) {
 				t.Errorf("failed to adjustByCfsQuota, want file %v cfs_quota %v, got %v", system.GetCgroupFilePath(beQosDir, system.CPUCFSQuota), tt.wantBECfsQuota,
@@ -2056,7 +2093,7 @@ func Test_cpuSuppress_writeBECgroupsCPUSet(t *testing.T) {
 	})
 
 	cpuSetStr := "0,1,2"
-	r.writeBECgroupsCPUSet(dirPaths, cpuSetStr, false)
+	r.writeBECgroupsCPUSet(context.TODO(), dirPaths, cpuSetStr, false)
 
 	gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 	assert.Equal(t, cpuSetStr, gotCPUSetBECgroup, "checkBECPUSet_reversed_false")
@@ -2066,7 +2103,7 @@ func Test_cpuSuppress_writeBECgroupsCPUSet(t *testing.T) {
 	}
 
 	cpuSetStr = "0,1"
-	r.writeBECgroupsCPUSet(dirPaths, cpuSetStr, true)
+	r.writeBECgroupsCPUSet(context.TODO(), dirPaths, cpuSetStr, true)
 	gotCPUSetBECgroup = helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
 	assert.Equal(t, cpuSetStr, gotCPUSetBECgroup, "checkBECPUSet_reversed_true")
 	for _, podDir := range podDirs {
@@ -2273,7 +2310,7 @@ func TestCPUSuppress_applyBESuppressCPUSet(t *testing.T) {
 				close(stopCh)
 			}()
 
-			err := r.applyBESuppressCPUSet(tt.args.beCPUSet, tt.args.oldCPUSet)
+			err := r.applyBESuppressCPUSet(context.TODO(), tt.args.beCPUSet, tt.args.oldCPUSet)
 
 			assert.NoError(t, err)
 			gotCPUSetBECgroup := helper.ReadCgroupFileContents(koordletutil.GetPodQoSRelativePath(corev1.PodQOSBestEffort), system.CPUSet)
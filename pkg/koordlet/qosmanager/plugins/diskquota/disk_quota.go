@@ -0,0 +1,169 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskquota
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/projquota"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	DiskQuotaReconcileName = "DiskQuotaReconcile"
+)
+
+var _ framework.QOSStrategy = &diskQuotaReconcile{}
+
+// diskQuotaReconcile limits the ephemeral storage of the BE pods with the project quotas of their pod dirs, which
+// contain the emptyDir volumes, and evicts the pods exhausting the quotas. Since the kubelet evicts the pods
+// exceeding the ephemeral-storage limits by periodically walking the dirs, the hard quota stops the writes
+// immediately, and the eviction here is done before the kubelet notices them.
+// The container writable layers and logs are not covered since they are not in the pod dirs.
+type diskQuotaReconcile struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	evictor           *framework.Evictor
+	controller        projquota.Controller
+	allocator         *projquota.ProjectIDAllocator
+	// appliedLimits records the quota limits applied to the pods, it is only accessed in the reconcile loop.
+	appliedLimits map[types.UID]int64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &diskQuotaReconcile{
+		reconcileInterval: time.Duration(opt.Config.DiskQuotaIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		controller:        projquota.DefaultController(),
+		allocator:         projquota.NewProjectIDAllocator(),
+		appliedLimits:     map[types.UID]int64{},
+	}
+}
+
+func (r *diskQuotaReconcile) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEDiskQuota) && r.reconcileInterval > 0
+}
+
+func (r *diskQuotaReconcile) Setup(ctx *framework.Context) {
+	r.evictor = ctx.Evictor
+}
+
+func (r *diskQuotaReconcile) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, DiskQuotaReconcileName, r.reconcile), r.reconcileInterval, stopCh)
+}
+
+func (r *diskQuotaReconcile) reconcile() {
+	node := r.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip disk quota reconcile, Node is nil")
+		return
+	}
+	fsPath := system.Conf.VarLibKubeletRootDir
+
+	alivePods := map[types.UID]struct{}{}
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		pod := podMeta.Pod
+		if extension.GetPodQoSClassRaw(pod) != extension.QoSBE || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		limit := getPodEphemeralStorageLimit(pod)
+		if limit <= 0 {
+			continue
+		}
+		alivePods[pod.UID] = struct{}{}
+
+		projectID, err := r.allocator.Allocate(pod.UID)
+		if err != nil {
+			klog.Warningf("failed to allocate project id for pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		if r.appliedLimits[pod.UID] != limit {
+			podDir := filepath.Join(system.Conf.VarLibKubeletRootDir, "pods", string(pod.UID))
+			if err = r.controller.SetQuota(fsPath, podDir, projectID, limit); err != nil {
+				klog.Warningf("failed to set disk quota for pod %s, err: %v", util.GetPodKey(pod), err)
+				continue
+			}
+			r.appliedLimits[pod.UID] = limit
+			klog.V(4).Infof("set disk quota for pod %s, project %d, limit %d", util.GetPodKey(pod), projectID, limit)
+		}
+
+		usage, err := r.controller.GetUsage(fsPath, projectID)
+		if err != nil {
+			klog.Warningf("failed to get disk usage of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		if usage < limit {
+			continue
+		}
+		message := fmt.Sprintf("pod ephemeral storage usage %d exhausts the limit %d", usage, limit)
+		if r.evictor.EvictPodIfNotEvicted(pod, node, resourceexecutor.EvictPodByDiskQuota, message) {
+			klog.V(4).Infof("diskQuota pick pod %s to evict, %s", util.GetPodKey(pod), message)
+		}
+	}
+
+	// remove the quotas of the pods which are deleted or no longer limited
+	for podUID := range r.appliedLimits {
+		if _, ok := alivePods[podUID]; ok {
+			continue
+		}
+		projectID, err := r.allocator.Allocate(podUID)
+		if err == nil {
+			err = r.controller.RemoveQuota(fsPath, projectID)
+		}
+		if err != nil {
+			klog.Warningf("failed to remove disk quota of pod %s, err: %v", podUID, err)
+			continue
+		}
+		r.allocator.Release(podUID)
+		delete(r.appliedLimits, podUID)
+	}
+}
+
+// getPodEphemeralStorageLimit returns the ephemeral storage limit of the pod in bytes, the request is used for the
+// containers which have no limit. It returns 0 if any container is unbounded.
+func getPodEphemeralStorageLimit(pod *corev1.Pod) int64 {
+	var total int64
+	for i := range pod.Spec.Containers {
+		resources := pod.Spec.Containers[i].Resources
+		q, ok := resources.Limits[corev1.ResourceEphemeralStorage]
+		if !ok || q.IsZero() {
+			q, ok = resources.Requests[corev1.ResourceEphemeralStorage]
+		}
+		if !ok || q.IsZero() {
+			return 0
+		}
+		total += q.Value()
+	}
+	return total
+}
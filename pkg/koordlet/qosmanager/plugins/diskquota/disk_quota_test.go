@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package diskquota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/projquota"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+var _ projquota.Controller = &fakeQuotaController{}
+
+type fakeQuotaController struct {
+	limits map[uint32]int64
+	dirs   map[uint32]string
+	usages map[uint32]int64
+}
+
+func (f *fakeQuotaController) SetQuota(fsPath, dir string, projectID uint32, hardLimitBytes int64) error {
+	f.limits[projectID] = hardLimitBytes
+	f.dirs[projectID] = dir
+	return nil
+}
+
+func (f *fakeQuotaController) GetUsage(fsPath string, projectID uint32) (int64, error) {
+	return f.usages[projectID], nil
+}
+
+func (f *fakeQuotaController) RemoveQuota(fsPath string, projectID uint32) error {
+	delete(f.limits, projectID)
+	return nil
+}
+
+func newTestPod(name string, qos extension.QoSClass, ephemeralStorage string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels:    map[string]string{extension.LabelPodQoS: string(qos)},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main"}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if ephemeralStorage != "" {
+		pod.Spec.Containers[0].Resources.Limits = corev1.ResourceList{
+			corev1.ResourceEphemeralStorage: resource.MustParse(ephemeralStorage),
+		}
+	}
+	return pod
+}
+
+func Test_diskQuotaReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	bePod := newTestPod("be-pod", extension.QoSBE, "1Gi")
+	exhaustedPod := newTestPod("be-pod-exhausted", extension.QoSBE, "1Gi")
+	unboundedPod := newTestPod("be-pod-unbounded", extension.QoSBE, "")
+	lsPod := newTestPod("ls-pod", extension.QoSLS, "1Gi")
+	pods := []*corev1.Pod{bePod, exhaustedPod, unboundedPod, lsPod}
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetNode().Return(node).AnyTimes()
+	var podMetas []*statesinformer.PodMeta
+	for _, pod := range pods {
+		podMetas = append(podMetas, &statesinformer.PodMeta{Pod: pod})
+	}
+	si.EXPECT().GetAllPods().Return(podMetas).Times(1)
+
+	client := clientsetfake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	evictor := framework.NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEDiskQuota, true)()
+	s := New(&framework.Options{StatesInformer: si, Config: framework.NewDefaultConfig()})
+	assert.True(t, s.Enabled())
+	r := s.(*diskQuotaReconcile)
+	r.Setup(&framework.Context{Evictor: evictor})
+	controller := &fakeQuotaController{limits: map[uint32]int64{}, dirs: map[uint32]string{}, usages: map[uint32]int64{}}
+	r.controller = controller
+
+	beID, err := r.allocator.Allocate(bePod.UID)
+	assert.NoError(t, err)
+	exhaustedID, err := r.allocator.Allocate(exhaustedPod.UID)
+	assert.NoError(t, err)
+	controller.usages[beID] = 512 << 20
+	controller.usages[exhaustedID] = 1 << 30
+
+	r.reconcile()
+	assert.Equal(t, map[uint32]int64{beID: 1 << 30, exhaustedID: 1 << 30}, controller.limits)
+	assert.Equal(t, system.Conf.VarLibKubeletRootDir+"pods/be-pod", controller.dirs[beID])
+	assert.Equal(t, map[types.UID]int64{bePod.UID: 1 << 30, exhaustedPod.UID: 1 << 30}, r.appliedLimits)
+	assert.False(t, r.evictor.IsPodEvicted(bePod))
+	assert.True(t, r.evictor.IsPodEvicted(exhaustedPod))
+	assert.False(t, r.evictor.IsPodEvicted(unboundedPod))
+	assert.False(t, r.evictor.IsPodEvicted(lsPod))
+
+	// the quotas of the deleted pods are removed
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: bePod}}).Times(1)
+	r.reconcile()
+	assert.Equal(t, map[uint32]int64{beID: 1 << 30}, controller.limits)
+	assert.Equal(t, map[types.UID]int64{bePod.UID: 1 << 30}, r.appliedLimits)
+}
+
+func Test_getPodEphemeralStorageLimit(t *testing.T) {
+	pod := newTestPod("test-pod", extension.QoSBE, "1Gi")
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+		Name: "sidecar",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceEphemeralStorage: resource.MustParse("512Mi")},
+		},
+	})
+	assert.Equal(t, int64(1536<<20), getPodEphemeralStorageLimit(pod))
+
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "unbounded"})
+	assert.Equal(t, int64(0), getPodEphemeralStorageLimit(pod))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpumps
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/mps"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	GPUMPSReconcileName = "GPUMPSReconcile"
+)
+
+var _ framework.QOSStrategy = &gpuMPSReconcile{}
+
+// gpuMPSReconcile keeps the MPS control daemons running on the GPUs shared by the MPS pods,
+// and stops the daemons of the GPUs which are no longer used by any MPS pod.
+// The daemons are started on demand when creating the containers, see the gpu runtime hook.
+type gpuMPSReconcile struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	controller        mps.DaemonController
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &gpuMPSReconcile{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+		controller:        mps.DefaultController(),
+	}
+}
+
+func (r *gpuMPSReconcile) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.GPUMPS) && r.reconcileInterval > 0
+}
+
+func (r *gpuMPSReconcile) Setup(context *framework.Context) {
+}
+
+func (r *gpuMPSReconcile) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, GPUMPSReconcileName, r.reconcile), r.reconcileInterval, stopCh)
+}
+
+func (r *gpuMPSReconcile) reconcile() {
+	required := getMPSRequiredGPUs(r.statesInformer.GetAllPods())
+
+	for _, minor := range required.List() {
+		if err := r.controller.Start(minor); err != nil {
+			klog.Warningf("failed to start mps daemon of gpu %d, err: %v", minor, err)
+		}
+	}
+
+	started, err := r.controller.ListStarted()
+	if err != nil {
+		klog.Warningf("failed to list the started mps daemons, err: %v", err)
+		return
+	}
+	for _, minor := range started {
+		if required.Has(minor) {
+			continue
+		}
+		if err = r.controller.Stop(minor); err != nil {
+			klog.Warningf("failed to stop mps daemon of gpu %d, err: %v", minor, err)
+		}
+	}
+	klog.V(5).Infof("finish to reconcile mps daemons, required gpus %v", required.List())
+}
+
+// getMPSRequiredGPUs returns the GPUs allocated to the alive MPS pods.
+func getMPSRequiredGPUs(podMetas []*statesinformer.PodMeta) sets.Int32 {
+	required := sets.NewInt32()
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		pod := podMeta.Pod
+		if pod.Labels[extension.LabelGPUIsolationProvider] != string(extension.GPUIsolationProviderMPS) {
+			continue
+		}
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		alloc, err := extension.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get device allocations of pod %s, err: %v", util.GetPodKey(pod), err)
+			continue
+		}
+		// keep consistent with the runtime hook, only the pods sharing one GPU connect to the MPS daemon
+		if devices := alloc[schedulingv1alpha1.GPU]; len(devices) == 1 {
+			required.Insert(devices[0].Minor)
+		}
+	}
+	return required
+}
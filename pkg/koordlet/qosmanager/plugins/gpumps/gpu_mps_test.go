@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpumps
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+type fakeMPSController struct {
+	started map[int32]bool
+}
+
+func (f *fakeMPSController) Start(minor int32) error {
+	f.started[minor] = true
+	return nil
+}
+
+func (f *fakeMPSController) Stop(minor int32) error {
+	delete(f.started, minor)
+	return nil
+}
+
+func (f *fakeMPSController) IsRunning(minor int32) bool {
+	return f.started[minor]
+}
+
+func (f *fakeMPSController) ListStarted() ([]int32, error) {
+	var minors []int32
+	for minor := range f.started {
+		minors = append(minors, minor)
+	}
+	return minors, nil
+}
+
+func newTestPodMeta(name string, phase corev1.PodPhase, provider extension.GPUIsolationProvider, alloc string) *statesinformer.PodMeta {
+	return &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Labels:      map[string]string{extension.LabelGPUIsolationProvider: string(provider)},
+				Annotations: map[string]string{extension.AnnotationDeviceAllocated: alloc},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		},
+	}
+}
+
+func TestGPUMPSReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		newTestPodMeta("mps-running", corev1.PodRunning, extension.GPUIsolationProviderMPS, `{"gpu":[{"minor":0}]}`),
+		newTestPodMeta("mps-pending", corev1.PodPending, extension.GPUIsolationProviderMPS, `{"gpu":[{"minor":1}]}`),
+		newTestPodMeta("mps-succeeded", corev1.PodSucceeded, extension.GPUIsolationProviderMPS, `{"gpu":[{"minor":2}]}`),
+		newTestPodMeta("mps-full-gpus", corev1.PodRunning, extension.GPUIsolationProviderMPS, `{"gpu":[{"minor":4},{"minor":5}]}`),
+		newTestPodMeta("hami", corev1.PodRunning, extension.GPUIsolationProviderHAMICore, `{"gpu":[{"minor":6}]}`),
+	}).Times(1)
+
+	controller := &fakeMPSController{started: map[int32]bool{3: true, 0: true}}
+	r := &gpuMPSReconcile{
+		statesInformer: statesInformer,
+		controller:     controller,
+	}
+	r.reconcile()
+	assert.Equal(t, map[int32]bool{0: true, 1: true}, controller.started)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepull
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	schedulinglister "github.com/koordinator-sh/koordinator/pkg/client/listers/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
+)
+
+const (
+	ImagePrePullName = "ImagePrePull"
+
+	defaultPullTimeout = 10 * time.Minute
+)
+
+var _ framework.QOSStrategy = &imagePrePull{}
+
+// imagePrePull pulls the images requested by the ImagePrePulls of the node, and reports the results in the status.
+// The ImagePrePulls are created by the scheduler for the available reservations, so the pods allocating the
+// reservations can start without waiting for the images.
+type imagePrePull struct {
+	statesInformer  statesinformer.StatesInformer
+	koordClient     koordclientset.Interface
+	pullTimeout     time.Duration
+	getImageService func() (handler.ImageServiceHandler, error)
+
+	lister schedulinglister.ImagePrePullLister
+	queue  workqueue.RateLimitingInterface
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &imagePrePull{
+		statesInformer:  opt.StatesInformer,
+		koordClient:     opt.KoordClient,
+		pullTimeout:     defaultPullTimeout,
+		getImageService: koordletutil.GetImageServiceHandler,
+		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ImagePrePullName),
+	}
+}
+
+func (p *imagePrePull) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.ImagePrePull) && p.koordClient != nil
+}
+
+func (p *imagePrePull) Setup(context *framework.Context) {
+}
+
+func (p *imagePrePull) Run(stopCh <-chan struct{}) {
+	node := p.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("failed to run %s, node is not ready", ImagePrePullName)
+		return
+	}
+	selector := labels.SelectorFromSet(labels.Set{schedulingv1alpha1.LabelImagePrePullNodeName: node.Name}).String()
+	factory := koordinformers.NewSharedInformerFactoryWithOptions(p.koordClient, 0,
+		koordinformers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.LabelSelector = selector
+		}))
+	informer := factory.Scheduling().V1alpha1().ImagePrePulls()
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: p.enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			p.enqueue(newObj)
+		},
+	})
+	p.lister = informer.Lister()
+
+	factory.Start(stopCh)
+	if !cache.WaitForCacheSync(stopCh, informer.Informer().HasSynced) {
+		klog.Errorf("time out waiting for ImagePrePull caches to sync")
+		return
+	}
+	go func() {
+		<-stopCh
+		p.queue.ShutDown()
+	}()
+	go wait.Until(p.worker, time.Second, stopCh)
+}
+
+func (p *imagePrePull) enqueue(obj interface{}) {
+	imagePrePull, ok := obj.(*schedulingv1alpha1.ImagePrePull)
+	if !ok || isImagePrePullFinished(imagePrePull) {
+		return
+	}
+	p.queue.Add(imagePrePull.Name)
+}
+
+func (p *imagePrePull) worker() {
+	for p.processNextItem() {
+	}
+}
+
+func (p *imagePrePull) processNextItem() bool {
+	key, quit := p.queue.Get()
+	if quit {
+		return false
+	}
+	defer p.queue.Done(key)
+
+	if err := p.sync(key.(string)); err != nil {
+		klog.Warningf("failed to sync ImagePrePull %s, err: %v", key, err)
+		p.queue.AddRateLimited(key)
+		return true
+	}
+	p.queue.Forget(key)
+	return true
+}
+
+func (p *imagePrePull) sync(name string) error {
+	imagePrePull, err := p.lister.Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if isImagePrePullFinished(imagePrePull) {
+		return nil
+	}
+
+	imageService, err := p.getImageService()
+	if err != nil {
+		return fmt.Errorf("failed to get image service, err: %w", err)
+	}
+
+	imagePrePull = imagePrePull.DeepCopy()
+	imagePrePull.Status = p.pullImages(imageService, imagePrePull)
+	_, err = p.koordClient.SchedulingV1alpha1().ImagePrePulls().UpdateStatus(context.TODO(), imagePrePull, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update status, err: %w", err)
+	}
+	klog.V(4).Infof("ImagePrePull %s finished, phase %s, images %v",
+		imagePrePull.Name, imagePrePull.Status.Phase, imagePrePull.Spec.Images)
+	return nil
+}
+
+// pullImages pulls the images which are not succeeded yet, and returns the new status.
+// The ImagePrePull fails if any of the images fails to pull.
+func (p *imagePrePull) pullImages(imageService handler.ImageServiceHandler, imagePrePull *schedulingv1alpha1.ImagePrePull) schedulingv1alpha1.ImagePrePullStatus {
+	pulled := map[string]schedulingv1alpha1.ImagePullStatus{}
+	for _, imageStatus := range imagePrePull.Status.Images {
+		if imageStatus.Phase == schedulingv1alpha1.ImagePrePullSucceeded {
+			pulled[imageStatus.Image] = imageStatus
+		}
+	}
+
+	status := schedulingv1alpha1.ImagePrePullStatus{Phase: schedulingv1alpha1.ImagePrePullSucceeded}
+	for _, image := range imagePrePull.Spec.Images {
+		imageStatus, ok := pulled[image]
+		if !ok {
+			imageStatus = p.pullImage(imageService, image)
+		}
+		if imageStatus.Phase != schedulingv1alpha1.ImagePrePullSucceeded {
+			status.Phase = schedulingv1alpha1.ImagePrePullFailed
+		}
+		status.Images = append(status.Images, imageStatus)
+	}
+	now := metav1.Now()
+	status.CompletionTime = &now
+	return status
+}
+
+func (p *imagePrePull) pullImage(imageService handler.ImageServiceHandler, image string) schedulingv1alpha1.ImagePullStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), p.pullTimeout)
+	defer cancel()
+
+	imageRef, err := imageService.PullImage(ctx, image)
+	if err != nil {
+		klog.V(4).Infof("failed to pull image %s, err: %v", image, err)
+		return schedulingv1alpha1.ImagePullStatus{
+			Image:   image,
+			Phase:   schedulingv1alpha1.ImagePrePullFailed,
+			Message: err.Error(),
+		}
+	}
+	return schedulingv1alpha1.ImagePullStatus{
+		Image:    image,
+		Phase:    schedulingv1alpha1.ImagePrePullSucceeded,
+		ImageRef: imageRef,
+	}
+}
+
+func isImagePrePullFinished(imagePrePull *schedulingv1alpha1.ImagePrePull) bool {
+	return imagePrePull.Status.Phase == schedulingv1alpha1.ImagePrePullSucceeded ||
+		imagePrePull.Status.Phase == schedulingv1alpha1.ImagePrePullFailed
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package imageprepull
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
+)
+
+type fakeImageService struct {
+	pulled map[string]int
+	failed map[string]bool
+}
+
+func (f *fakeImageService) PullImage(ctx context.Context, image string) (string, error) {
+	if f.failed[image] {
+		return "", fmt.Errorf("expected error")
+	}
+	f.pulled[image]++
+	return "sha256:" + image, nil
+}
+
+func newTestImagePrePull(name, nodeName string, images ...string) *schedulingv1alpha1.ImagePrePull {
+	return &schedulingv1alpha1.ImagePrePull{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{schedulingv1alpha1.LabelImagePrePullNodeName: nodeName},
+		},
+		Spec: schedulingv1alpha1.ImagePrePullSpec{
+			NodeName: nodeName,
+			Images:   images,
+		},
+	}
+}
+
+func Test_imagePrePull_pullImages(t *testing.T) {
+	imageService := &fakeImageService{pulled: map[string]int{}, failed: map[string]bool{"busybox": true}}
+	p := &imagePrePull{pullTimeout: time.Second}
+
+	obj := newTestImagePrePull("test", "test-node", "nginx", "busybox")
+	status := p.pullImages(imageService, obj)
+	assert.Equal(t, schedulingv1alpha1.ImagePrePullFailed, status.Phase)
+	assert.NotNil(t, status.CompletionTime)
+	assert.Len(t, status.Images, 2)
+	assert.Equal(t, schedulingv1alpha1.ImagePullStatus{
+		Image:    "nginx",
+		Phase:    schedulingv1alpha1.ImagePrePullSucceeded,
+		ImageRef: "sha256:nginx",
+	}, status.Images[0])
+	assert.Equal(t, schedulingv1alpha1.ImagePrePullFailed, status.Images[1].Phase)
+	assert.Equal(t, "expected error", status.Images[1].Message)
+
+	// the succeeded images are not pulled again
+	obj.Status = status
+	imageService.failed = nil
+	status = p.pullImages(imageService, obj)
+	assert.Equal(t, schedulingv1alpha1.ImagePrePullSucceeded, status.Phase)
+	assert.Equal(t, 1, imageService.pulled["nginx"])
+	assert.Equal(t, 1, imageService.pulled["busybox"])
+}
+
+func Test_imagePrePull_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetNode().Return(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}).AnyTimes()
+
+	koordClient := koordfake.NewSimpleClientset(
+		newTestImagePrePull("test", "test-node", "nginx"),
+		newTestImagePrePull("test-other-node", "other-node", "busybox"),
+	)
+	imageService := &fakeImageService{pulled: map[string]int{}}
+	p := New(&framework.Options{
+		StatesInformer: statesInformer,
+		KoordClient:    koordClient,
+	}).(*imagePrePull)
+	p.getImageService = func() (handler.ImageServiceHandler, error) {
+		return imageService, nil
+	}
+	assert.False(t, p.Enabled())
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	p.Run(stopCh)
+
+	err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, 5*time.Second, true, func(ctx context.Context) (bool, error) {
+		obj, err := koordClient.SchedulingV1alpha1().ImagePrePulls().Get(ctx, "test", metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return obj.Status.Phase == schedulingv1alpha1.ImagePrePullSucceeded, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"nginx": 1}, imageService.pulled)
+
+	other, err := koordClient.SchedulingV1alpha1().ImagePrePulls().Get(context.TODO(), "test-other-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, other.Status.Phase)
+}
@@ -18,7 +18,6 @@ package memoryevict
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -32,7 +31,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 const (
@@ -50,6 +51,7 @@ type memoryEvictor struct {
 	statesInformer        statesinformer.StatesInformer
 	metricCache           metriccache.MetricCache
 	evictor               *framework.Evictor
+	cgroupReader          resourceexecutor.CgroupReader
 	lastEvictTime         time.Time
 	onlyEvictByAPI        bool
 }
@@ -66,6 +68,7 @@ func New(opt *framework.Options) framework.QOSStrategy {
 		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
 		statesInformer:        opt.StatesInformer,
 		metricCache:           opt.MetricCache,
+		cgroupReader:          opt.CgroupReader,
 		onlyEvictByAPI:        opt.Config.OnlyEvictByAPI,
 	}
 }
@@ -79,7 +82,7 @@ func (m *memoryEvictor) Setup(ctx *framework.Context) {
 }
 
 func (m *memoryEvictor) Run(stopCh <-chan struct{}) {
-	go wait.Until(m.memoryEvict, m.evictInterval, stopCh)
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, MemoryEvictName, m.memoryEvict), m.evictInterval, stopCh)
 }
 
 func (m *memoryEvictor) memoryEvict() {
@@ -146,8 +149,17 @@ func (m *memoryEvictor) memoryEvict() {
 		klog.Warningf("skip memory evict, get node metrics error: %v", err)
 		return
 	}
+	protectionWindow := helpers.GetCompletionProtectionWindow(thresholdConfig)
+	var numaPressures []numaMemoryPressure
+	if thresholdConfig.MemoryEvictNUMAAware != nil && *thresholdConfig.MemoryEvictNUMAAware {
+		numaPressures = getNUMAMemoryPressures(*thresholdPercent, lowerPercent)
+	}
 	nodeMemoryUsage := int64(nodeMemoryUsed) * 100 / memoryCapacity
 	if nodeMemoryUsage < *thresholdPercent {
+		if len(numaPressures) > 0 {
+			m.killAndEvictBEPodsByNUMA(node, podMetrics, numaPressures, protectionWindow)
+			return
+		}
 		klog.V(5).Infof("skip memory evict, node memory usage(%v) is below threshold(%v)", nodeMemoryUsage, *thresholdPercent)
 		return
 	}
@@ -160,14 +172,19 @@ func (m *memoryEvictor) memoryEvict() {
 	)
 
 	memoryNeedRelease := memoryCapacity * (nodeMemoryUsage - lowerPercent) / 100
-	m.killAndEvictBEPods(node, podMetrics, memoryNeedRelease)
+	bePodInfos := m.getSortedBEPodInfos(podMetrics, protectionWindow)
+	if len(numaPressures) > 0 {
+		// the pods bound to the pressured NUMA nodes are evicted first
+		bePodInfos = sortBEPodInfosByNUMAPressures(bePodInfos, numaPressures)
+	}
+	message := fmt.Sprintf("killAndEvictBEPods for node, need to release memory: %v", memoryNeedRelease)
+	m.killAndEvictBEPods(node, bePodInfos, memoryNeedRelease, message)
 }
 
-func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[string]float64, memoryNeedRelease int64) {
-	bePodInfos := m.getSortedBEPodInfos(podMetrics)
-	message := fmt.Sprintf("killAndEvictBEPods for node, need to release memory: %v", memoryNeedRelease)
+func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, bePodInfos []*podInfo, memoryNeedRelease int64, message string) []*podInfo {
 	memoryReleased := int64(0)
 	hasKillPods := false
+	var killedPods []*podInfo
 	for _, bePod := range bePodInfos {
 		if memoryReleased >= memoryNeedRelease {
 			break
@@ -176,6 +193,7 @@ func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[str
 		if m.onlyEvictByAPI {
 			if m.evictor.EvictPodIfNotEvicted(bePod.pod, node, resourceexecutor.EvictPodByNodeMemoryUsage, message) {
 				hasKillPods = true
+				killedPods = append(killedPods, bePod)
 				if bePod.memUsed != 0 {
 					memoryReleased += int64(bePod.memUsed)
 				}
@@ -187,6 +205,7 @@ func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[str
 			killMsg := fmt.Sprintf("%v, kill pod: %v", message, bePod.pod.Name)
 			helpers.KillContainers(bePod.pod, resourceexecutor.EvictPodByNodeMemoryUsage, killMsg)
 			hasKillPods = true
+			killedPods = append(killedPods, bePod)
 			if bePod.memUsed != 0 {
 				memoryReleased += int64(bePod.memUsed)
 			}
@@ -198,37 +217,48 @@ func (m *memoryEvictor) killAndEvictBEPods(node *corev1.Node, podMetrics map[str
 	}
 
 	klog.Infof("killAndEvictBEPods completed, memoryNeedRelease(%v) memoryReleased(%v)", memoryNeedRelease, memoryReleased)
+	return killedPods
 }
 
-func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64) []*podInfo {
-
-	var bePodInfos []*podInfo
+func (m *memoryEvictor) getSortedBEPodInfos(podMetricMap map[string]float64, protectionWindow time.Duration) []*podInfo {
+	var bePods []*corev1.Pod
 	for _, podMeta := range m.statesInformer.GetAllPods() {
 		pod := podMeta.Pod
 		if extension.GetPodQoSClassRaw(pod) == extension.QoSBE {
-			info := &podInfo{
-				pod:     pod,
-				memUsed: podMetricMap[string(pod.UID)],
-			}
-			bePodInfos = append(bePodInfos, info)
+			bePods = append(bePods, pod)
 		}
 	}
-
-	sort.Slice(bePodInfos, func(i, j int) bool {
-		// TODO: https://github.com/koordinator-sh/koordinator/pull/65#discussion_r849048467
-		// compare priority > podMetric > name
-		if bePodInfos[i].pod.Spec.Priority != nil && bePodInfos[j].pod.Spec.Priority != nil && *bePodInfos[i].pod.Spec.Priority != *bePodInfos[j].pod.Spec.Priority {
-			return *bePodInfos[i].pod.Spec.Priority < *bePodInfos[j].pod.Spec.Priority
+	bePods = sorter.FilterVictims(bePods)
+
+	// compare priority > completion protection > podMetric > custom victim rules > name
+	memUsed := func(p1, p2 *corev1.Pod) int {
+		used1, used2 := podMetricMap[string(p1.UID)], podMetricMap[string(p2.UID)]
+		if used1 == 0 || used2 == 0 {
+			// pods without metric are placed at the end
+			if used1 == used2 {
+				return 0
+			}
+			if used1 == 0 {
+				return 1
+			}
+			return -1
 		}
-		if bePodInfos[i].memUsed != 0 && bePodInfos[j].memUsed != 0 {
-			//return bePodInfos[i].podMetric.MemoryUsed.MemoryWithoutCache.Value() > bePodInfos[j].podMetric.MemoryUsed.MemoryWithoutCache.Value()
-			return bePodInfos[i].memUsed > bePodInfos[j].memUsed
-		} else if bePodInfos[i].memUsed == 0 && bePodInfos[j].memUsed == 0 {
-
-			return bePodInfos[i].pod.Name > bePodInfos[j].pod.Name
+		if used1 == used2 {
+			return 0
 		}
-		return bePodInfos[j].memUsed == 0
-	})
-
+		if used1 > used2 {
+			return -1
+		}
+		return 1
+	}
+	sorter.VictimSorter(sorter.SpecifiedPriority, sorter.CompletionProtection(protectionWindow), memUsed).Sort(bePods)
+
+	bePodInfos := make([]*podInfo, 0, len(bePods))
+	for _, pod := range bePods {
+		bePodInfos = append(bePodInfos, &podInfo{
+			pod:     pod,
+			memUsed: podMetricMap[string(pod.UID)],
+		})
+	}
 	return bePodInfos
 }
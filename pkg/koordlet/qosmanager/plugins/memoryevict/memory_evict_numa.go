@@ -0,0 +1,177 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// numaStatPageSize is the size of the pages counted in the memory.numa_stat.
+const numaStatPageSize = 4 * 1024
+
+// numaMemoryPressure is the memory usage of a NUMA node exceeding the evict threshold.
+type numaMemoryPressure struct {
+	numaID       int32
+	usagePercent int64
+	needRelease  int64
+}
+
+// getNUMAMemoryPressures returns the pressured NUMA nodes in the descending order of the memory usage.
+func getNUMAMemoryPressures(thresholdPercent, lowerPercent int64) []numaMemoryPressure {
+	numaInfo, err := koordletutil.GetNodeNUMAInfo()
+	if err != nil {
+		klog.V(4).Infof("failed to get node NUMA info for memory evict, err: %v", err)
+		return nil
+	}
+	var pressures []numaMemoryPressure
+	for _, info := range numaInfo.NUMAInfos {
+		if info.MemInfo == nil || info.MemInfo.MemTotal <= 0 {
+			continue
+		}
+		total := int64(info.MemInfo.MemTotalBytes())
+		usagePercent := int64(getNUMAMemoryUsed(info.MemInfo)) * 100 / total
+		if usagePercent < thresholdPercent {
+			continue
+		}
+		klog.Infof("NUMA node %v MemoryUsage: %.2f, evictThresholdUsage: %.2f, evictLowerUsage: %.2f",
+			info.NUMANodeID, float64(usagePercent)/100, float64(thresholdPercent)/100, float64(lowerPercent)/100)
+		pressures = append(pressures, numaMemoryPressure{
+			numaID:       info.NUMANodeID,
+			usagePercent: usagePercent,
+			needRelease:  total * (usagePercent - lowerPercent) / 100,
+		})
+	}
+	sort.SliceStable(pressures, func(i, j int) bool {
+		return pressures[i].usagePercent > pressures[j].usagePercent
+	})
+	return pressures
+}
+
+// getNUMAMemoryUsed returns the used bytes of the NUMA node, since there is no MemAvailable in the meminfo of the
+// NUMA node, the free memory and the inactive page cache are considered as unused.
+func getNUMAMemoryUsed(memInfo *koordletutil.MemInfo) uint64 {
+	unused := memInfo.MemFree + memInfo.InactiveFile
+	if unused >= memInfo.MemTotal {
+		return 0
+	}
+	return (memInfo.MemTotal - unused) * 1024
+}
+
+// getPodBoundNUMANodes returns the NUMA nodes which the memory of the pod is bound to.
+func getPodBoundNUMANodes(pod *corev1.Pod) []int32 {
+	status, err := extension.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return nil
+	}
+	numaIDs := make([]int32, 0, len(status.NUMANodeResources))
+	for _, numaResource := range status.NUMANodeResources {
+		numaIDs = append(numaIDs, numaResource.Node)
+	}
+	return numaIDs
+}
+
+func isPodBoundToNUMA(pod *corev1.Pod, numaID int32) bool {
+	for _, id := range getPodBoundNUMANodes(pod) {
+		if id == numaID {
+			return true
+		}
+	}
+	return false
+}
+
+// sortBEPodInfosByNUMAPressures moves the pods bound to the pressured NUMA nodes to the front, and keeps the order of
+// the pods otherwise.
+func sortBEPodInfosByNUMAPressures(bePodInfos []*podInfo, pressures []numaMemoryPressure) []*podInfo {
+	sorted := make([]*podInfo, 0, len(bePodInfos))
+	var others []*podInfo
+	for _, bePod := range bePodInfos {
+		bound := false
+		for _, pressure := range pressures {
+			if isPodBoundToNUMA(bePod.pod, pressure.numaID) {
+				bound = true
+				break
+			}
+		}
+		if bound {
+			sorted = append(sorted, bePod)
+		} else {
+			others = append(others, bePod)
+		}
+	}
+	return append(sorted, others...)
+}
+
+// getPodNUMAMemoryUsed returns the memory bytes of the pod allocated on the NUMA node according to the memory.numa_stat
+// of the pod cgroup.
+func (m *memoryEvictor) getPodNUMAMemoryUsed(pod *corev1.Pod, numaID int32) (uint64, error) {
+	numaStats, err := m.cgroupReader.ReadMemoryNumaStat(koordletutil.GetPodCgroupParentDir(pod))
+	if err != nil {
+		return 0, err
+	}
+	for _, numaStat := range numaStats {
+		if numaStat.NumaId == int(numaID) {
+			return numaStat.PagesNum * numaStatPageSize, nil
+		}
+	}
+	return 0, nil
+}
+
+// killAndEvictBEPodsByNUMA releases the memory of each pressured NUMA node when the node memory usage is below the
+// threshold. The pods bound to the NUMA node are evicted first, then the pods not bound to any NUMA node, whose
+// released memory is counted by their usage on the NUMA node. The pods bound to the other NUMA nodes are not evicted
+// since they cannot release the memory of the pressured one.
+func (m *memoryEvictor) killAndEvictBEPodsByNUMA(node *corev1.Node, podMetrics map[string]float64,
+	pressures []numaMemoryPressure, protectionWindow time.Duration) {
+	bePodInfos := m.getSortedBEPodInfos(podMetrics, protectionWindow)
+	killed := map[types.UID]bool{}
+	for _, pressure := range pressures {
+		var boundPods, unboundPods []*podInfo
+		for _, bePod := range bePodInfos {
+			if killed[bePod.pod.UID] {
+				continue
+			}
+			if isPodBoundToNUMA(bePod.pod, pressure.numaID) {
+				boundPods = append(boundPods, bePod)
+			} else if len(getPodBoundNUMANodes(bePod.pod)) <= 0 {
+				numaMemUsed, err := m.getPodNUMAMemoryUsed(bePod.pod, pressure.numaID)
+				if err != nil {
+					klog.V(4).Infof("failed to get NUMA memory usage of pod %s, err: %v", util.GetPodKey(bePod.pod), err)
+					continue
+				}
+				if numaMemUsed <= 0 {
+					continue
+				}
+				unboundPods = append(unboundPods, &podInfo{pod: bePod.pod, memUsed: float64(numaMemUsed)})
+			}
+		}
+		message := fmt.Sprintf("killAndEvictBEPods for NUMA node %v, need to release memory: %v", pressure.numaID, pressure.needRelease)
+		killedPods := m.killAndEvictBEPods(node, append(boundPods, unboundPods...), pressure.needRelease, message)
+		for _, bePod := range killedPods {
+			killed[bePod.pod.UID] = true
+		}
+	}
+}
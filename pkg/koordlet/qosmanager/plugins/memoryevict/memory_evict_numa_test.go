@@ -0,0 +1,139 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memoryevict
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func prepareNUMAMemInfo(helper *system.FileTestUtil) {
+	// node0: (100 - 10 - 5) / 100 = 85%, node1: (100 - 50 - 10) / 100 = 40%
+	helper.WriteFileContents(system.GetNUMAMemInfoPath("node0"), `Node 0 MemTotal:       104857600 kB
+Node 0 MemFree:         10485760 kB
+Node 0 Inactive(file):   5242880 kB`)
+	helper.WriteFileContents(system.GetNUMAMemInfoPath("node1"), `Node 1 MemTotal:       104857600 kB
+Node 1 MemFree:         52428800 kB
+Node 1 Inactive(file):  10485760 kB`)
+}
+
+func createNUMABoundTestPod(name string, priority int32, numaIDs ...int32) *corev1.Pod {
+	pod := createMemoryEvictTestPod(name, apiext.QoSBE, priority)
+	if len(numaIDs) > 0 {
+		status := &apiext.ResourceStatus{}
+		for _, numaID := range numaIDs {
+			status.NUMANodeResources = append(status.NUMANodeResources, apiext.NUMANodeResource{Node: numaID})
+		}
+		_ = apiext.SetResourceStatus(pod, status)
+	}
+	return pod
+}
+
+func Test_getNUMAMemoryPressures(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	prepareNUMAMemInfo(helper)
+
+	got := getNUMAMemoryPressures(80, 78)
+	assert.Equal(t, []numaMemoryPressure{
+		{numaID: 0, usagePercent: 85, needRelease: 104857600 * 1024 * 7 / 100},
+	}, got)
+
+	got = getNUMAMemoryPressures(30, 20)
+	assert.Equal(t, []numaMemoryPressure{
+		{numaID: 0, usagePercent: 85, needRelease: 104857600 * 1024 * 65 / 100},
+		{numaID: 1, usagePercent: 40, needRelease: 104857600 * 1024 * 20 / 100},
+	}, got)
+}
+
+func Test_sortBEPodInfosByNUMAPressures(t *testing.T) {
+	unbound := &podInfo{pod: createNUMABoundTestPod("unbound", 100)}
+	numa0 := &podInfo{pod: createNUMABoundTestPod("numa0", 100, 0)}
+	numa1 := &podInfo{pod: createNUMABoundTestPod("numa1", 100, 1)}
+	numa01 := &podInfo{pod: createNUMABoundTestPod("numa01", 100, 0, 1)}
+	got := sortBEPodInfosByNUMAPressures([]*podInfo{unbound, numa0, numa1, numa01}, []numaMemoryPressure{{numaID: 1}})
+	assert.Equal(t, []*podInfo{numa1, numa01, unbound, numa0}, got)
+}
+
+func Test_killAndEvictBEPodsByNUMA(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	pods := []*corev1.Pod{
+		createNUMABoundTestPod("be_numa0_1", 100, 0),
+		createNUMABoundTestPod("be_numa0_2", 100, 0),
+		createNUMABoundTestPod("be_numa1", 100, 1),
+		createNUMABoundTestPod("be_unbound_1", 100),
+		createNUMABoundTestPod("be_unbound_2", 100),
+	}
+	podMetrics := map[string]float64{
+		"be_numa0_1":   3 << 30,
+		"be_numa0_2":   1 << 30,
+		"be_numa1":     10 << 30,
+		"be_unbound_1": 10 << 30,
+		"be_unbound_2": 10 << 30,
+	}
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	// be_unbound_1 uses 1G of NUMA node 0, be_unbound_2 uses none of NUMA node 0
+	helper.WriteCgroupFileContents(koordletutil.GetPodCgroupParentDir(pods[3]), system.MemoryNumaStat,
+		"total=2621440 N0=262144 N1=2359296\n")
+	helper.WriteCgroupFileContents(koordletutil.GetPodCgroupParentDir(pods[4]), system.MemoryNumaStat,
+		"total=2621440 N0=0 N1=2621440\n")
+	mockStatesInformer := mock_statesinformer.NewMockStatesInformer(ctl)
+	mockStatesInformer.EXPECT().GetAllPods().Return(testutil.GetPodMetas(pods)).AnyTimes()
+
+	client := clientsetfake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := client.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	evictor := framework.NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+
+	m := &memoryEvictor{
+		statesInformer: mockStatesInformer,
+		evictor:        evictor,
+		cgroupReader:   resourceexecutor.NewCgroupReader(),
+		onlyEvictByAPI: true,
+	}
+	node := testutil.MockTestNode("80", "120G")
+	// release 4G of NUMA node 0 by the pods bound to it, and 1G more is released by the unbound pod using NUMA node 0
+	m.killAndEvictBEPodsByNUMA(node, podMetrics, []numaMemoryPressure{{numaID: 0, needRelease: 6 << 30}}, time.Duration(0))
+	assert.True(t, evictor.IsPodEvicted(pods[0]))
+	assert.True(t, evictor.IsPodEvicted(pods[1]))
+	assert.False(t, evictor.IsPodEvicted(pods[2]))
+	assert.True(t, evictor.IsPodEvicted(pods[3]))
+	assert.False(t, evictor.IsPodEvicted(pods[4]))
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottle
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	MemoryThrottleName = "memoryThrottle"
+
+	// memoryThrottleBufferPercent keeps the throttled node memory usage below the memory evict threshold
+	memoryThrottleBufferPercent = 2
+	// minMemoryHighBytes is the lower bound of the memory.high of the throttled pod to avoid freezing the pod
+	minMemoryHighBytes int64 = 64 * 1024 * 1024
+	// memoryHighUnlimited is equal to write "max" into the memory.high
+	memoryHighUnlimited int64 = math.MaxInt64
+	// memoryHighUnknown marks the pod which may be throttled by the last koordlet
+	memoryHighUnknown int64 = -1
+)
+
+var _ framework.QOSStrategy = &memoryThrottle{}
+
+// memoryThrottle is the tier before the memory eviction. When the node memory usage exceeds the throttle threshold,
+// it sets the memory.high of the BE pods to share the memory left below the evict threshold in proportion to their
+// usages, so the kernel reclaims and throttles the allocation of the BE pods instead of killing them. The memory.high
+// shrinks as the node memory pressure grows, and it is reset when the node memory usage falls below the threshold.
+// NOTE: It should not be used with the ThrottlingPercent of the MemoryQOS for the BE pods, which also sets memory.high.
+type memoryThrottle struct {
+	interval              time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	executor              resourceexecutor.ResourceUpdateExecutor
+	// throttledPods records the cgroup dir and the memory.high of the throttled pods by the pod uid
+	throttledPods map[string]*throttledPod
+	// recovered indicates whether the pods throttled before the koordlet restarts have been recovered
+	recovered bool
+}
+
+type throttledPod struct {
+	cgroupDir  string
+	memoryHigh int64
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &memoryThrottle{
+		interval:              time.Duration(opt.Config.MemoryEvictIntervalSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.CollectResUsedInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		executor:              resourceexecutor.NewResourceUpdateExecutor(),
+		throttledPods:         map[string]*throttledPod{},
+	}
+}
+
+func (m *memoryThrottle) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEMemoryThrottle) && m.interval > 0
+}
+
+func (m *memoryThrottle) Setup(ctx *framework.Context) {
+}
+
+func (m *memoryThrottle) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, MemoryThrottleName, m.memoryThrottle), m.interval, stopCh)
+}
+
+func (m *memoryThrottle) memoryThrottle(ctx context.Context) {
+	klog.V(5).Infof("starting memory throttle process")
+	defer klog.V(5).Infof("memory throttle process completed")
+
+	m.recoverThrottledPods()
+
+	nodeSLO := m.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEMemoryThrottle); err != nil {
+		klog.Errorf("failed to acquire memory throttle feature-gate, error: %v", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("skip memory throttle, disabled in NodeSLO")
+		m.release(ctx)
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	throttlePercent := thresholdConfig.MemoryThrottleThresholdPercent
+	if throttlePercent == nil || *throttlePercent <= 0 {
+		klog.V(5).Infof("skip memory throttle, threshold percent is not set")
+		m.release(ctx)
+		return
+	}
+	targetPercent := int64(100)
+	if thresholdConfig.MemoryEvictThresholdPercent != nil {
+		targetPercent = *thresholdConfig.MemoryEvictThresholdPercent
+	}
+	targetPercent -= memoryThrottleBufferPercent
+	if targetPercent < *throttlePercent {
+		targetPercent = *throttlePercent
+	}
+
+	node := m.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("skip memory throttle, Node is nil")
+		return
+	}
+	memoryCapacity := node.Status.Capacity.Memory().Value()
+	if memoryCapacity <= 0 {
+		klog.Warningf("skip memory throttle, memory capacity(%v) should greater than 0", memoryCapacity)
+		return
+	}
+
+	queryMeta, err := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	if err != nil {
+		klog.Warningf("skip memory throttle, get node query failed, error: %v", err)
+		return
+	}
+	nodeMemoryUsed, err := helpers.CollectorNodeMetricLast(m.metricCache, queryMeta, m.metricCollectInterval)
+	if err != nil {
+		klog.Warningf("skip memory throttle, get node metrics error: %v", err)
+		return
+	}
+	nodeMemoryUsage := int64(nodeMemoryUsed) * 100 / memoryCapacity
+	if nodeMemoryUsage < *throttlePercent {
+		klog.V(5).Infof("skip memory throttle, node memory usage(%v) is below threshold(%v)", nodeMemoryUsage, *throttlePercent)
+		m.release(ctx)
+		return
+	}
+
+	podMetrics := helpers.CollectAllPodMetricsLast(m.statesInformer, m.metricCache, metriccache.PodMemUsageMetric, m.metricCollectInterval)
+	var beUsages []*bePodUsage
+	beMemoryUsed := int64(0)
+	for _, podMeta := range m.statesInformer.GetAllPods() {
+		if podMeta == nil || podMeta.Pod == nil || extension.GetPodQoSClassRaw(podMeta.Pod) != extension.QoSBE {
+			continue
+		}
+		used := int64(podMetrics[string(podMeta.Pod.UID)])
+		if used <= 0 {
+			continue
+		}
+		beUsages = append(beUsages, &bePodUsage{podMeta: podMeta, used: used})
+		beMemoryUsed += used
+	}
+	if len(beUsages) <= 0 {
+		klog.V(5).Infof("skip memory throttle, no BE pod is using memory")
+		return
+	}
+
+	// the BE pods share the memory left below the target usage after the non-BE usage
+	nonBEMemoryUsed := int64(nodeMemoryUsed) - beMemoryUsed
+	beMemoryBudget := memoryCapacity*targetPercent/100 - nonBEMemoryUsed
+	klog.Infof("node MemoryUsage(%v): %.2f, throttleThresholdUsage: %.2f, throttleTargetUsage: %.2f, BE memory used %v, budget %v",
+		int64(nodeMemoryUsed), float64(nodeMemoryUsage)/100, float64(*throttlePercent)/100, float64(targetPercent)/100,
+		beMemoryUsed, beMemoryBudget)
+	m.throttle(ctx, beUsages, calculateMemoryHigh(beUsages, beMemoryUsed, beMemoryBudget))
+}
+
+type bePodUsage struct {
+	podMeta *statesinformer.PodMeta
+	used    int64
+}
+
+// calculateMemoryHigh shares the memory budget to the BE pods in proportion to their memory usages, and returns the
+// memory.high by the pod uid.
+func calculateMemoryHigh(beUsages []*bePodUsage, beMemoryUsed, beMemoryBudget int64) map[string]int64 {
+	memoryHighs := make(map[string]int64, len(beUsages))
+	for _, u := range beUsages {
+		memoryHigh := int64(float64(u.used) * float64(beMemoryBudget) / float64(beMemoryUsed))
+		memoryHigh = memoryHigh / system.PageSize * system.PageSize
+		if memoryHigh < minMemoryHighBytes {
+			memoryHigh = minMemoryHighBytes
+		}
+		memoryHighs[string(u.podMeta.Pod.UID)] = memoryHigh
+	}
+	return memoryHighs
+}
+
+func (m *memoryThrottle) throttle(ctx context.Context, beUsages []*bePodUsage, memoryHighs map[string]int64) {
+	var updaters []resourceexecutor.ResourceUpdater
+	totalMemoryHigh := int64(0)
+	for _, u := range beUsages {
+		podUID, podKey := string(u.podMeta.Pod.UID), util.GetPodKey(u.podMeta.Pod)
+		memoryHigh := memoryHighs[podUID]
+		totalMemoryHigh += memoryHigh
+		last, ok := m.throttledPods[podUID]
+		if ok && last.memoryHigh == memoryHigh {
+			continue
+		}
+		updater, err := newMemoryHighUpdater(u.podMeta.CgroupDir, memoryHigh, "throttle BE pod %s memory.high to %v", podKey, memoryHigh)
+		if err != nil {
+			klog.V(4).Infof("failed to throttle memory of pod %s, err: %v", podKey, err)
+			continue
+		}
+		updaters = append(updaters, updater)
+		if !ok || last.memoryHigh == memoryHighUnknown || memoryHigh < last.memoryHigh {
+			metrics.RecordBEMemoryThrottleEvent(metrics.BEMemoryThrottleTypeThrottle)
+		}
+		m.throttledPods[podUID] = &throttledPod{cgroupDir: u.podMeta.CgroupDir, memoryHigh: memoryHigh}
+	}
+	// the pods no longer using memory or deleted are not throttled
+	for podUID, p := range m.throttledPods {
+		if _, ok := memoryHighs[podUID]; ok {
+			continue
+		}
+		if updater, err := newMemoryHighUpdater(p.cgroupDir, memoryHighUnlimited, "release BE pod %s memory.high", podUID); err == nil {
+			updaters = append(updaters, updater)
+		}
+		delete(m.throttledPods, podUID)
+	}
+	m.executor.UpdateBatch(ctx, true, updaters...)
+	metrics.RecordBEMemoryThrottleLimit(float64(totalMemoryHigh))
+}
+
+// recoverThrottledPods marks all BE pods as throttled with an unknown memory.high once after the koordlet starts.
+// The throttled pods are only recorded in memory, so the memory.high set by the last koordlet is left in the cgroups.
+// The marked pods are then throttled to the current memory.high or reset like the other throttled pods.
+func (m *memoryThrottle) recoverThrottledPods() {
+	if m.recovered {
+		return
+	}
+	podMetas := m.statesInformer.GetAllPods()
+	if len(podMetas) <= 0 {
+		return
+	}
+	m.recovered = true
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || extension.GetPodQoSClassRaw(podMeta.Pod) != extension.QoSBE {
+			continue
+		}
+		if _, ok := m.throttledPods[string(podMeta.Pod.UID)]; ok {
+			continue
+		}
+		m.throttledPods[string(podMeta.Pod.UID)] = &throttledPod{cgroupDir: podMeta.CgroupDir, memoryHigh: memoryHighUnknown}
+	}
+	klog.V(4).Infof("recover memory throttle of %d BE pods", len(m.throttledPods))
+}
+
+// release resets the memory.high of all throttled pods.
+func (m *memoryThrottle) release(ctx context.Context) {
+	if len(m.throttledPods) <= 0 {
+		return
+	}
+	var updaters []resourceexecutor.ResourceUpdater
+	for podUID, p := range m.throttledPods {
+		updater, err := newMemoryHighUpdater(p.cgroupDir, memoryHighUnlimited, "release BE pod %s memory.high", podUID)
+		if err != nil {
+			klog.V(4).Infof("failed to release memory throttle of pod %s, err: %v", podUID, err)
+			continue
+		}
+		updaters = append(updaters, updater)
+	}
+	m.executor.UpdateBatch(ctx, true, updaters...)
+	klog.V(4).Infof("release memory throttle of %d BE pods", len(m.throttledPods))
+	m.throttledPods = map[string]*throttledPod{}
+	metrics.RecordBEMemoryThrottleEvent(metrics.BEMemoryThrottleTypeRelease)
+	metrics.RecordBEMemoryThrottleLimit(0)
+}
+
+func newMemoryHighUpdater(cgroupDir string, memoryHigh int64, msg string, args ...interface{}) (resourceexecutor.ResourceUpdater, error) {
+	eventHelper := audit.V(3).Reason(MemoryThrottleName).Message(msg, args...)
+	return resourceexecutor.NewCommonCgroupUpdater(system.MemoryHighName, cgroupDir, strconv.FormatInt(memoryHigh, 10), eventHelper)
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorythrottle
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const gb = 1024 * 1024 * 1024
+
+func newMockMetricCache(ctrl *gomock.Controller, nodeUsed float64, podUsed map[string]float64) metriccache.MetricCache {
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mock_metriccache.NewMockAggregateResultFactory(ctrl)
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	mockQuerier := mock_metriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	nodeQueryMeta, _ := metriccache.NodeMemoryUsageMetric.BuildQueryMeta(nil)
+	testutil.BuildMockQueryResult(ctrl, mockQuerier, mockResultFactory, nodeQueryMeta, nodeUsed)
+	for uid, used := range podUsed {
+		podQueryMeta, _ := metriccache.PodMemUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod(uid))
+		testutil.BuildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podQueryMeta, used)
+	}
+	return mockMetricCache
+}
+
+func Test_memoryThrottle(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEMemoryThrottle, true)()
+
+	bePod := testutil.MockTestPod(apiext.QoSBE, "be-pod")
+	bePod1 := testutil.MockTestPod(apiext.QoSBE, "be-pod-1")
+	lsPod := testutil.MockTestPod(apiext.QoSLS, "ls-pod")
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod, bePod1, lsPod})
+	for _, podMeta := range podMetas {
+		helper.WriteCgroupFileContents(podMeta.CgroupDir, system.MemoryHighV2, "max")
+	}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+	si.EXPECT().GetNode().Return(testutil.MockTestNode("80", "10Gi")).AnyTimes()
+	si.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(&slov1alpha1.ResourceThresholdStrategy{
+		Enable:                         pointer.Bool(true),
+		MemoryEvictThresholdPercent:    pointer.Int64(80),
+		MemoryThrottleThresholdPercent: pointer.Int64(60),
+	})).AnyTimes()
+
+	s := New(&framework.Options{
+		StatesInformer:      si,
+		MetricCache:         newMockMetricCache(ctrl, 8*gb, map[string]float64{"be-pod": 2 * gb, "be-pod-1": 1 * gb, "ls-pod": 5 * gb}),
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	})
+	assert.True(t, s.Enabled())
+	m := s.(*memoryThrottle)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.executor.Run(stop)
+
+	// node usage 80% exceeds the throttle threshold, the BE pods share 78%*10Gi-5Gi in proportion to the usages
+	m.memoryThrottle(context.TODO())
+	budget := int64(10*gb*78/100 - 5*gb)
+	expectHigh := (budget * 2 / 3) / system.PageSize * system.PageSize
+	expectHigh1 := (budget / 3) / system.PageSize * system.PageSize
+	assert.Equal(t, strconv.FormatInt(expectHigh, 10), helper.ReadCgroupFileContents(podMetas[0].CgroupDir, system.MemoryHighV2))
+	assert.Equal(t, strconv.FormatInt(expectHigh1, 10), helper.ReadCgroupFileContents(podMetas[1].CgroupDir, system.MemoryHighV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(podMetas[2].CgroupDir, system.MemoryHighV2))
+	assert.Len(t, m.throttledPods, 2)
+
+	// node usage falls below the threshold, release the throttled pods
+	m.metricCache = newMockMetricCache(ctrl, 5*gb, map[string]float64{"be-pod": 1 * gb, "ls-pod": 4 * gb})
+	m.memoryThrottle(context.TODO())
+	assert.Equal(t, strconv.FormatInt(memoryHighUnlimited, 10), helper.ReadCgroupFileContents(podMetas[0].CgroupDir, system.MemoryHighV2))
+	assert.Equal(t, strconv.FormatInt(memoryHighUnlimited, 10), helper.ReadCgroupFileContents(podMetas[1].CgroupDir, system.MemoryHighV2))
+	assert.Len(t, m.throttledPods, 0)
+}
+
+func Test_memoryThrottle_recoverThrottledPods(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.BEMemoryThrottle, true)()
+
+	bePod := testutil.MockTestPod(apiext.QoSBE, "be-pod")
+	lsPod := testutil.MockTestPod(apiext.QoSLS, "ls-pod")
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{bePod, lsPod})
+	// the memory.high is left by the last koordlet
+	helper.WriteCgroupFileContents(podMetas[0].CgroupDir, system.MemoryHighV2, strconv.FormatInt(gb, 10))
+	helper.WriteCgroupFileContents(podMetas[1].CgroupDir, system.MemoryHighV2, "max")
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+	si.EXPECT().GetNode().Return(testutil.MockTestNode("80", "10Gi")).AnyTimes()
+	si.EXPECT().GetNodeSLO().Return(testutil.GetNodeSLOByThreshold(&slov1alpha1.ResourceThresholdStrategy{
+		Enable:                         pointer.Bool(true),
+		MemoryEvictThresholdPercent:    pointer.Int64(80),
+		MemoryThrottleThresholdPercent: pointer.Int64(60),
+	})).AnyTimes()
+
+	s := New(&framework.Options{
+		StatesInformer:      si,
+		MetricCache:         newMockMetricCache(ctrl, 5*gb, map[string]float64{"be-pod": 1 * gb, "ls-pod": 4 * gb}),
+		Config:              framework.NewDefaultConfig(),
+		MetricAdvisorConfig: maframework.NewDefaultConfig(),
+	})
+	m := s.(*memoryThrottle)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.executor.Run(stop)
+
+	// node usage is below the threshold, reset the memory.high of the BE pod throttled before the restart
+	m.memoryThrottle(context.TODO())
+	assert.True(t, m.recovered)
+	assert.Equal(t, strconv.FormatInt(memoryHighUnlimited, 10), helper.ReadCgroupFileContents(podMetas[0].CgroupDir, system.MemoryHighV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(podMetas[1].CgroupDir, system.MemoryHighV2))
+	assert.Len(t, m.throttledPods, 0)
+}
+
+func Test_calculateMemoryHigh(t *testing.T) {
+	podMetas := testutil.GetPodMetas([]*corev1.Pod{
+		testutil.MockTestPod(apiext.QoSBE, "be-pod"),
+		testutil.MockTestPod(apiext.QoSBE, "be-pod-1"),
+	})
+	// the memory.high is no less than the lower bound even if the budget is exhausted
+	assert.Equal(t, map[string]int64{
+		"be-pod":   minMemoryHighBytes,
+		"be-pod-1": minMemoryHighBytes,
+	}, calculateMemoryHigh([]*bePodUsage{
+		{podMeta: podMetas[0], used: 2 * gb},
+		{podMeta: podMetas[1], used: 1 * gb},
+	}, 3*gb, -1*gb))
+}
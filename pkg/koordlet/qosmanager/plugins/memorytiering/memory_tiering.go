@@ -0,0 +1,258 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytiering
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	MemoryTieringName = "MemoryTieringReconcile"
+
+	// numaBalancingMemoryTiering is the NUMA_BALANCING_MEMORY_TIERING mode of the kernel.numa_balancing, which can be
+	// combined with the normal mode (NUMA_BALANCING_NORMAL=1).
+	numaBalancingMemoryTiering int64 = 2
+	numaDemotionEnabled              = "1"
+	numaDemotionDisabled             = "0"
+
+	qosNumaBalancingEnabled  = "1"
+	qosNumaBalancingDisabled = "0"
+)
+
+// qosNumaBalancingPolicy is the memory.numa_balancing placement policy of the QoS cgroups. The numa balancing of the
+// LS cgroups is enabled, so the hot pages of the LS pods are promoted back and kept in the DRAM. The numa balancing of
+// the BE cgroup is disabled, so the demoted pages of the BE pods stay in the slow tiers and do not compete for the
+// DRAM with the LS pods.
+var qosNumaBalancingPolicy = []struct {
+	qosClass corev1.PodQOSClass
+	value    string
+}{
+	{qosClass: corev1.PodQOSGuaranteed, value: qosNumaBalancingEnabled},
+	{qosClass: corev1.PodQOSBurstable, value: qosNumaBalancingEnabled},
+	{qosClass: corev1.PodQOSBestEffort, value: qosNumaBalancingDisabled},
+}
+
+// memoryTiering turns on the kernel memory tiering on the nodes with the slow memory tiers (e.g. CXL memory or the
+// remote numa nodes). The cold pages are demoted to the slower tiers on the reclaim, and the hot pages are promoted
+// by the numa balancing according to the qosNumaBalancingPolicy.
+// When the feature is disabled or the node no longer supports it, the defaults set before are restored.
+// NOTE: The pod-level memory.numa_balancing set by the NUMABalancing runtime hook overrides the QoS cgroups.
+type memoryTiering struct {
+	reconcileInterval time.Duration
+	executor          resourceexecutor.ResourceUpdateExecutor
+	// unsupportedMsg records the last reason why the memory tiering is not supported to avoid the repeated logs
+	unsupportedMsg string
+}
+
+var _ framework.QOSStrategy = &memoryTiering{}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &memoryTiering{
+		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
+		executor:          resourceexecutor.NewResourceUpdateExecutor(),
+	}
+}
+
+// Enabled returns true even if the feature is disabled, so that the defaults can be restored once the memory tiering
+// set by the former koordlet is turned off.
+func (m *memoryTiering) Enabled() bool {
+	return m.reconcileInterval > 0
+}
+
+func (m *memoryTiering) Setup(context *framework.Context) {
+}
+
+func (m *memoryTiering) Run(stopCh <-chan struct{}) {
+	m.executor.Run(stopCh)
+	if !features.DefaultKoordletFeatureGate.Enabled(features.MemoryTiering) {
+		go m.restoreDefaults(context.TODO())
+		return
+	}
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, MemoryTieringName, m.reconcile), m.reconcileInterval, stopCh)
+}
+
+func (m *memoryTiering) reconcile(ctx context.Context) {
+	if supported, msg := isMemoryTieringSupported(); !supported {
+		if msg != m.unsupportedMsg {
+			klog.Warningf("memory tiering is not supported on the node, skip reconcile, reason: %s", msg)
+			m.unsupportedMsg = msg
+		}
+		m.restoreDefaults(ctx)
+		return
+	}
+	m.unsupportedMsg = ""
+
+	updaters, err := m.getNodeUpdaters()
+	if err != nil {
+		klog.Warningf("failed to reconcile memory tiering, err: %v", err)
+		return
+	}
+	updaters = append(updaters, getQoSUpdaters()...)
+	m.executor.UpdateBatch(ctx, true, updaters...)
+	klog.V(5).Infof("finish to reconcile memory tiering")
+}
+
+// restoreDefaults restores the defaults if the memory tiering has been set on the node, which is recognized by the
+// disabled numa balancing of the BE cgroup. The normal numa balancing is kept, the demotion is disabled, and the
+// memory.numa_balancing of the QoS cgroups inherits the root cgroup. The BE cgroup is restored at last, so the
+// restoration is retried if any of the updates fails.
+func (m *memoryTiering) restoreDefaults(ctx context.Context) {
+	if supported, _ := sysutil.IsMemoryNumaBalancingSupported(); !supported {
+		return
+	}
+	beDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	if content, err := sysutil.CommonFileRead(sysutil.MemoryNumaBalancing.Path(beDir)); err != nil || content != qosNumaBalancingDisabled {
+		return
+	}
+	defaultValue, err := sysutil.CommonFileRead(sysutil.MemoryNumaBalancing.Path(""))
+	if err != nil {
+		klog.Warningf("failed to restore memory tiering, read memory.numa_balancing of the root cgroup err: %v", err)
+		return
+	}
+
+	var updaters []resourceexecutor.ResourceUpdater
+	if supported, _ := sysutil.NumaBalancing.IsSupported(""); supported {
+		numaBalancingFile := sysutil.NumaBalancing.Path("")
+		if current, err := readNumaBalancing(); err != nil {
+			klog.Warningf("failed to restore memory tiering, err: %v", err)
+			return
+		} else if current&numaBalancingMemoryTiering != 0 {
+			numaBalancing := strconv.FormatInt(current&^numaBalancingMemoryTiering, 10)
+			updater, err := resourceexecutor.NewCommonDefaultUpdater(numaBalancingFile, numaBalancingFile, numaBalancing,
+				audit.V(3).Node().Reason(MemoryTieringName).Message("restore numa_balancing to %v", numaBalancing))
+			if err != nil {
+				klog.Warningf("failed to restore memory tiering, err: %v", err)
+				return
+			}
+			updaters = append(updaters, updater)
+		}
+	}
+	if supported, _ := sysutil.NumaDemotionEnabled.IsSupported(""); supported {
+		demotionFile := sysutil.NumaDemotionEnabled.Path("")
+		updater, err := resourceexecutor.NewCommonDefaultUpdater(demotionFile, demotionFile, numaDemotionDisabled,
+			audit.V(3).Node().Reason(MemoryTieringName).Message("restore demotion_enabled to %v", numaDemotionDisabled))
+		if err != nil {
+			klog.Warningf("failed to restore memory tiering, err: %v", err)
+			return
+		}
+		updaters = append(updaters, updater)
+	}
+	// the policy ends with the BE cgroup
+	for _, policy := range qosNumaBalancingPolicy {
+		qosDir := util.GetPodQoSRelativePath(policy.qosClass)
+		updater, err := resourceexecutor.NewCommonCgroupUpdater(sysutil.MemoryNumaBalancingName, qosDir, defaultValue,
+			audit.V(3).Group(string(policy.qosClass)).Reason(MemoryTieringName).Message("restore memory.numa_balancing to %v", defaultValue))
+		if err != nil {
+			klog.Warningf("failed to restore memory tiering, err: %v", err)
+			return
+		}
+		updaters = append(updaters, updater)
+	}
+	m.executor.UpdateBatch(ctx, true, updaters...)
+	klog.V(4).Infof("memory tiering restored to the defaults")
+}
+
+func (m *memoryTiering) getNodeUpdaters() ([]resourceexecutor.ResourceUpdater, error) {
+	numaBalancingFile := sysutil.NumaBalancing.Path("")
+	current, err := readNumaBalancing()
+	if err != nil {
+		return nil, err
+	}
+	// keep the normal numa balancing if it is enabled
+	numaBalancing := strconv.FormatInt(current|numaBalancingMemoryTiering, 10)
+	if valid, msg := sysutil.NumaBalancing.IsValid(numaBalancing); !valid {
+		return nil, fmt.Errorf("invalid numa_balancing %s, msg: %s", numaBalancing, msg)
+	}
+	numaBalancingUpdater, err := resourceexecutor.NewCommonDefaultUpdater(numaBalancingFile, numaBalancingFile, numaBalancing,
+		audit.V(3).Node().Reason(MemoryTieringName).Message("update numa_balancing to %v", numaBalancing))
+	if err != nil {
+		return nil, err
+	}
+
+	demotionFile := sysutil.NumaDemotionEnabled.Path("")
+	demotionUpdater, err := resourceexecutor.NewCommonDefaultUpdater(demotionFile, demotionFile, numaDemotionEnabled,
+		audit.V(3).Node().Reason(MemoryTieringName).Message("update demotion_enabled to %v", numaDemotionEnabled))
+	if err != nil {
+		return nil, err
+	}
+	return []resourceexecutor.ResourceUpdater{numaBalancingUpdater, demotionUpdater}, nil
+}
+
+// getQoSUpdaters returns the updaters of the memory.numa_balancing of the QoS cgroups by the qosNumaBalancingPolicy.
+func getQoSUpdaters() []resourceexecutor.ResourceUpdater {
+	var updaters []resourceexecutor.ResourceUpdater
+	for _, policy := range qosNumaBalancingPolicy {
+		qosDir := util.GetPodQoSRelativePath(policy.qosClass)
+		updater, err := resourceexecutor.NewCommonCgroupUpdater(sysutil.MemoryNumaBalancingName, qosDir, policy.value,
+			audit.V(3).Group(string(policy.qosClass)).Reason(MemoryTieringName).Message("update memory.numa_balancing to %v", policy.value))
+		if err != nil {
+			klog.V(4).Infof("failed to get numa balancing updater of %s cgroup, err: %v", policy.qosClass, err)
+			continue
+		}
+		updaters = append(updaters, updater)
+	}
+	return updaters
+}
+
+func readNumaBalancing() (int64, error) {
+	content, err := sysutil.CommonFileRead(sysutil.NumaBalancing.Path(""))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read numa_balancing, err: %w", err)
+	}
+	current, err := strconv.ParseInt(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse numa_balancing %s, err: %w", content, err)
+	}
+	return current, nil
+}
+
+// isMemoryTieringSupported checks if the kernel supports the memory tiering and if there are slow memory tiers.
+// The cgroup-level numa balancing is also required, otherwise the demoted pages of the BE pods are promoted back.
+func isMemoryTieringSupported() (bool, string) {
+	if supported, msg := sysutil.NumaBalancing.IsSupported(""); !supported {
+		return false, fmt.Sprintf("numa_balancing not supported, %s", msg)
+	}
+	if supported, msg := sysutil.NumaDemotionEnabled.IsSupported(""); !supported {
+		return false, fmt.Sprintf("demotion_enabled not supported, %s", msg)
+	}
+	tiers, err := sysutil.GetMemoryTiers()
+	if err != nil {
+		return false, fmt.Sprintf("failed to get memory tiers, err: %v", err)
+	}
+	if len(tiers) <= 1 {
+		return false, fmt.Sprintf("no slow memory tier, tiers %v", tiers)
+	}
+	if supported, msg := sysutil.IsMemoryNumaBalancingSupported(); !supported {
+		return false, fmt.Sprintf("memory.numa_balancing not supported, %s", msg)
+	}
+	return true, ""
+}
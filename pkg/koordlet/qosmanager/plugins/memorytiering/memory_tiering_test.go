@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memorytiering
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func Test_memoryTiering(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.MemoryTiering, true)()
+	s := New(&framework.Options{Config: framework.NewDefaultConfig()})
+	assert.True(t, s.Enabled())
+	m := s.(*memoryTiering)
+	stop := make(chan struct{})
+	defer close(stop)
+	m.executor.Run(stop)
+	guaranteedDir := util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed)
+	burstableDir := util.GetPodQoSRelativePath(corev1.PodQOSBurstable)
+	beDir := util.GetPodQoSRelativePath(corev1.PodQOSBestEffort)
+	helper.WriteCgroupFileContents(guaranteedDir, sysutil.MemoryNumaBalancing, "1")
+	helper.WriteCgroupFileContents("", sysutil.MemoryNumaBalancing, "1")
+	helper.WriteCgroupFileContents(burstableDir, sysutil.MemoryNumaBalancing, "0")
+	helper.WriteCgroupFileContents(beDir, sysutil.MemoryNumaBalancing, "1")
+
+	// the kernel does not support the memory tiering
+	helper.WriteProcSubFileContents(sysutil.ProcSysKernelRelativePath+sysutil.NumaBalancingFileName, "1")
+	m.reconcile(context.TODO())
+	assert.Contains(t, m.unsupportedMsg, "demotion_enabled")
+	helper.WriteFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName, "false")
+	m.reconcile(context.TODO())
+	assert.Contains(t, m.unsupportedMsg, "memory tiers")
+
+	// only the fast memory tier
+	helper.WriteFileContents(sysutil.MemoryTieringRelativePath+"memory_tier4/nodelist", "0-1")
+	m.reconcile(context.TODO())
+	assert.Contains(t, m.unsupportedMsg, "no slow memory tier")
+	assert.Equal(t, "1", helper.ReadProcSubFileContents(sysutil.ProcSysKernelRelativePath+sysutil.NumaBalancingFileName))
+
+	// the memory tiering is supported, the normal numa balancing is kept
+	helper.WriteFileContents(sysutil.MemoryTieringRelativePath+"memory_tier22/nodelist", "2")
+	m.reconcile(context.TODO())
+	assert.Equal(t, "", m.unsupportedMsg)
+	assert.Equal(t, "3", helper.ReadProcSubFileContents(sysutil.ProcSysKernelRelativePath+sysutil.NumaBalancingFileName))
+	assert.Equal(t, "1", helper.ReadFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(guaranteedDir, sysutil.MemoryNumaBalancing))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(burstableDir, sysutil.MemoryNumaBalancing))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(beDir, sysutil.MemoryNumaBalancing))
+
+	// the slow memory tier is gone, restore the defaults
+	assert.NoError(t, os.RemoveAll(filepath.Join(helper.TempDir, sysutil.MemoryTieringRelativePath, "memory_tier22")))
+	m.reconcile(context.TODO())
+	assert.Contains(t, m.unsupportedMsg, "no slow memory tier")
+	assert.Equal(t, "1", helper.ReadProcSubFileContents(sysutil.ProcSysKernelRelativePath+sysutil.NumaBalancingFileName))
+	assert.Equal(t, "0", helper.ReadFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(beDir, sysutil.MemoryNumaBalancing))
+
+	// the feature is disabled, restore the defaults set by the former koordlet
+	helper.WriteFileContents(sysutil.MemoryTieringRelativePath+"memory_tier22/nodelist", "2")
+	m.reconcile(context.TODO())
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(beDir, sysutil.MemoryNumaBalancing))
+	m.restoreDefaults(context.TODO())
+	assert.Equal(t, "1", helper.ReadProcSubFileContents(sysutil.ProcSysKernelRelativePath+sysutil.NumaBalancingFileName))
+	assert.Equal(t, "0", helper.ReadFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(guaranteedDir, sysutil.MemoryNumaBalancing))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(burstableDir, sysutil.MemoryNumaBalancing))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(beDir, sysutil.MemoryNumaBalancing))
+	// the node without the memory tiering set is not touched
+	helper.WriteFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName, "1")
+	m.restoreDefaults(context.TODO())
+	assert.Equal(t, "1", helper.ReadFileContents(sysutil.NumaDemotionRelativePath+sysutil.NumaDemotionEnabledFileName))
+}
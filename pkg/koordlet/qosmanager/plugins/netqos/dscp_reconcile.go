@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+)
+
+const (
+	DSCPReconcileName = "NetQoSDSCPReconcile"
+)
+
+var _ framework.QOSStrategy = &dscpReconcile{}
+
+// newMarker creates the marker of the egress packets, it is replaced in the tests.
+var newMarker = netqos.NewMarker
+
+// dscpReconcile marks the DSCP of the egress packets of the pods by the network QoS of their QoS classes in the
+// NodeSLO, so that the switches and routers can prioritize the traffic of the LS pods over the BE pods beyond the
+// node-local shaping. It works with any network QoS policy since it only marks the packets.
+type dscpReconcile struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	marker            netqos.Marker
+
+	// appliedMarks is only accessed in the reconcile loop
+	appliedMarks map[string]uint8
+}
+
+func NewDSCPReconcile(opt *framework.Options) framework.QOSStrategy {
+	return &dscpReconcile{
+		reconcileInterval: time.Duration(opt.Config.NetQoSIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+	}
+}
+
+func (r *dscpReconcile) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NetQoSDSCPMarking) && r.reconcileInterval > 0
+}
+
+func (r *dscpReconcile) Setup(ctx *framework.Context) {
+}
+
+func (r *dscpReconcile) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, DSCPReconcileName, r.reconcile), r.reconcileInterval, stopCh)
+}
+
+func (r *dscpReconcile) reconcile() {
+	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO == nil {
+		klog.V(5).Infof("skip dscp reconcile, NodeSLO is nil")
+		return
+	}
+
+	marks := buildMarks(r.statesInformer.GetAllPods(), nodeSLO.Spec.ResourceQOSStrategy)
+	if len(marks) == 0 {
+		r.cleanup()
+		return
+	}
+	if reflect.DeepEqual(marks, r.appliedMarks) {
+		return
+	}
+
+	if r.marker == nil {
+		marker, err := newMarker()
+		if err != nil {
+			klog.Warningf("skip dscp reconcile, failed to create marker, err: %v", err)
+			return
+		}
+		r.marker = marker
+	}
+	if err := r.marker.UpdateMarks(marks); err != nil {
+		// the marks are retried in the next round
+		klog.Warningf("failed to update dscp marks, err: %v", err)
+		r.appliedMarks = nil
+		return
+	}
+	r.appliedMarks = marks
+	klog.V(5).Infof("dscp marks updated, count %d", len(marks))
+}
+
+func (r *dscpReconcile) cleanup() {
+	if r.marker == nil || r.appliedMarks == nil {
+		return
+	}
+	if err := r.marker.Cleanup(); err != nil {
+		klog.Warningf("failed to cleanup dscp marks, err: %v", err)
+		return
+	}
+	r.appliedMarks = nil
+	klog.V(4).Infof("dscp marking is disabled, cleanup the marks")
+}
+
+// buildMarks maps the ipv4 addresses of the running pods to the DSCP values of their QoS classes. The host network
+// pods are not marked since they share the node ips.
+func buildMarks(podMetas []*statesinformer.PodMeta, strategy *slov1alpha1.ResourceQOSStrategy) map[string]uint8 {
+	marks := map[string]uint8{}
+	if strategy == nil {
+		return marks
+	}
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		pod := podMeta.Pod
+		if pod.Spec.HostNetwork || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		dscp, ok := getDSCP(helpers.GetPodResourceQoSByQoSClass(pod, strategy))
+		if !ok {
+			continue
+		}
+		for _, podIP := range pod.Status.PodIPs {
+			marks[podIP.IP] = dscp
+		}
+		if pod.Status.PodIP != "" {
+			marks[pod.Status.PodIP] = dscp
+		}
+	}
+	return marks
+}
+
+func getDSCP(qos *slov1alpha1.ResourceQOS) (uint8, bool) {
+	if qos == nil || qos.NetworkQOS == nil || qos.NetworkQOS.Enable == nil || !*qos.NetworkQOS.Enable ||
+		qos.NetworkQOS.DSCP == nil {
+		return 0, false
+	}
+	dscp := *qos.NetworkQOS.DSCP
+	if dscp < 0 || dscp > netqos.MaxDSCP {
+		return 0, false
+	}
+	return uint8(dscp), true
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+var _ netqos.Marker = &fakeMarker{}
+
+type fakeMarker struct {
+	marks       map[string]uint8
+	updateCount int
+	cleaned     bool
+}
+
+func (f *fakeMarker) UpdateMarks(marks map[string]uint8) error {
+	f.marks = marks
+	f.updateCount++
+	return nil
+}
+
+func (f *fakeMarker) Cleanup() error {
+	f.cleaned = true
+	f.marks = nil
+	return nil
+}
+
+func newTestDSCPNodeSLO(lsDSCP, beDSCP *int64) *slov1alpha1.NodeSLO {
+	return &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				LSClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable:     pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{DSCP: lsDSCP},
+					},
+				},
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable:     pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{DSCP: beDSCP},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_dscpReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lsPod := newTestPod("ls-pod", extension.QoSLS, "10.0.0.1")
+	bePod := newTestPod("be-pod", extension.QoSBE, "10.0.0.2")
+	hostNetworkPod := newTestPod("host-pod", extension.QoSBE, "192.168.0.1")
+	hostNetworkPod.Spec.HostNetwork = true
+	podMetas := []*statesinformer.PodMeta{{Pod: lsPod}, {Pod: bePod}, {Pod: hostNetworkPod}}
+
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	marker := &fakeMarker{}
+	oldNewMarker := newMarker
+	defer func() { newMarker = oldNewMarker }()
+	newMarker = func() (netqos.Marker, error) {
+		return marker, nil
+	}
+
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.NetQoSDSCPMarking, true)()
+	s := NewDSCPReconcile(&framework.Options{StatesInformer: si, Config: framework.NewDefaultConfig()})
+	assert.True(t, s.Enabled())
+	r := s.(*dscpReconcile)
+
+	// no dscp configured, nothing to do
+	si.EXPECT().GetNodeSLO().Return(newTestDSCPNodeSLO(nil, nil)).Times(1)
+	r.reconcile()
+	assert.Nil(t, r.marker)
+
+	si.EXPECT().GetNodeSLO().Return(newTestDSCPNodeSLO(pointer.Int64(46), pointer.Int64(10))).Times(2)
+	r.reconcile()
+	assert.Equal(t, map[string]uint8{
+		"10.0.0.1": 46,
+		"10.0.0.2": 10,
+	}, marker.marks)
+	// marks are not updated if unchanged
+	r.reconcile()
+	assert.Equal(t, 1, marker.updateCount)
+
+	// the invalid dscp is ignored
+	si.EXPECT().GetNodeSLO().Return(newTestDSCPNodeSLO(pointer.Int64(46), pointer.Int64(64))).Times(1)
+	r.reconcile()
+	assert.Equal(t, map[string]uint8{
+		"10.0.0.1": 46,
+	}, marker.marks)
+
+	// cleanup when the dscp is removed
+	si.EXPECT().GetNodeSLO().Return(newTestDSCPNodeSLO(nil, nil)).Times(1)
+	r.reconcile()
+	assert.True(t, marker.cleaned)
+	assert.Nil(t, r.appliedMarks)
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/terwayqos"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+)
+
+const (
+	NetQoSReconcileName = "NetQoSReconcile"
+)
+
+var _ framework.QOSStrategy = &netQoSReconcile{}
+
+// newShaper creates the shaper of the egress traffic, it is replaced in the tests.
+var newShaper = netqos.NewShaper
+
+// netQoSReconcile limits the egress bandwidth of the pods by their QoS classes on the NIC of the default route,
+// so that the BE pods cannot starve the LS pods. The LS and BE classes are guaranteed the egress requests and
+// limited by the egress limits in the NodeSLO, and the remaining bandwidth is guaranteed for the system class
+// which serves the host processes and the system pods. The classes borrow the spare bandwidth in the order of
+// system, LS and BE.
+// It takes effect when the network QoS policy of the NodeSLO is "ebpf", which is exclusive to the tc runtime hook.
+type netQoSReconcile struct {
+	reconcileInterval time.Duration
+	statesInformer    statesinformer.StatesInformer
+	shaper            netqos.Shaper
+
+	// the applied states are only accessed in the reconcile loop
+	appliedTotal     uint64
+	appliedClasses   []netqos.Class
+	appliedEndpoints map[string]uint16
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &netQoSReconcile{
+		reconcileInterval: time.Duration(opt.Config.NetQoSIntervalSeconds) * time.Second,
+		statesInformer:    opt.StatesInformer,
+	}
+}
+
+func (r *netQoSReconcile) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.NetQoSReconcile) && r.reconcileInterval > 0
+}
+
+func (r *netQoSReconcile) Setup(ctx *framework.Context) {
+}
+
+func (r *netQoSReconcile) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, NetQoSReconcileName, r.reconcile), r.reconcileInterval, stopCh)
+}
+
+func (r *netQoSReconcile) reconcile() {
+	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || !isNetQoSPolicyEBPF(&nodeSLO.Spec) {
+		r.cleanup()
+		return
+	}
+	if nodeSLO.Spec.SystemStrategy == nil || nodeSLO.Spec.SystemStrategy.TotalNetworkBandwidth.Value() <= 0 {
+		klog.Warningf("skip net qos reconcile, total network bandwidth is not set in NodeSLO")
+		return
+	}
+	totalBits := uint64(nodeSLO.Spec.SystemStrategy.TotalNetworkBandwidth.Value())
+	// to Byte/s
+	total := terwayqos.BitsToBytes(totalBits)
+
+	if r.shaper == nil {
+		shaper, err := newShaper()
+		if err != nil {
+			klog.Warningf("skip net qos reconcile, failed to create shaper, err: %v", err)
+			return
+		}
+		r.shaper = shaper
+	}
+
+	classes := buildClasses(totalBits, nodeSLO.Spec.ResourceQOSStrategy)
+	if total != r.appliedTotal || !reflect.DeepEqual(classes, r.appliedClasses) {
+		if err := r.shaper.Ensure(total, classes); err != nil {
+			klog.Warningf("failed to ensure net qos classes, err: %v", err)
+			return
+		}
+		r.appliedTotal = total
+		r.appliedClasses = classes
+		klog.V(4).Infof("net qos classes updated, total %d, classes %+v", total, classes)
+	}
+
+	endpoints := buildEndpoints(r.statesInformer.GetAllPods())
+	if reflect.DeepEqual(endpoints, r.appliedEndpoints) {
+		return
+	}
+	if err := r.shaper.UpdateEndpoints(endpoints); err != nil {
+		klog.Warningf("failed to update net qos endpoints, err: %v", err)
+		return
+	}
+	r.appliedEndpoints = endpoints
+	klog.V(5).Infof("net qos endpoints updated, count %d", len(endpoints))
+}
+
+func (r *netQoSReconcile) cleanup() {
+	if r.shaper == nil || r.appliedClasses == nil {
+		return
+	}
+	if err := r.shaper.Cleanup(); err != nil {
+		klog.Warningf("failed to cleanup net qos, err: %v", err)
+		return
+	}
+	r.appliedTotal = 0
+	r.appliedClasses = nil
+	r.appliedEndpoints = nil
+	klog.V(4).Infof("net qos is disabled, cleanup the classes")
+}
+
+func isNetQoSPolicyEBPF(spec *slov1alpha1.NodeSLOSpec) bool {
+	strategy := spec.ResourceQOSStrategy
+	return strategy != nil && strategy.Policies != nil && strategy.Policies.NETQOSPolicy != nil &&
+		*strategy.Policies.NETQOSPolicy == slov1alpha1.NETQOSPolicyEBPF
+}
+
+// buildClasses returns the classes of system, LS and BE in Byte/s, where the total and the network QoS are in bits
+// per second. The class whose network QoS is disabled is guaranteed the minimal rate and can borrow up to the total.
+func buildClasses(totalBits uint64, strategy *slov1alpha1.ResourceQOSStrategy) []netqos.Class {
+	total := terwayqos.BitsToBytes(totalBits)
+	ls := netqos.Class{Minor: netqos.LSClassMinor, Prio: netqos.LSClassPrio, Rate: netqos.MinClassRate, Ceil: total}
+	be := netqos.Class{Minor: netqos.BEClassMinor, Prio: netqos.BEClassPrio, Rate: netqos.MinClassRate, Ceil: total}
+	if strategy != nil {
+		setClassBandwidth(&ls, totalBits, strategy.LSClass)
+		setClassBandwidth(&be, totalBits, strategy.BEClass)
+	}
+
+	system := netqos.Class{Minor: netqos.SystemClassMinor, Prio: netqos.SystemClassPrio, Rate: netqos.MinClassRate, Ceil: total}
+	if reserved := ls.Rate + be.Rate; total > reserved+netqos.MinClassRate {
+		system.Rate = total - reserved
+	}
+	return []netqos.Class{system, ls, be}
+}
+
+func setClassBandwidth(class *netqos.Class, totalBits uint64, qos *slov1alpha1.ResourceQOS) {
+	if qos == nil || qos.NetworkQOS == nil || qos.NetworkQOS.Enable == nil || !*qos.NetworkQOS.Enable {
+		return
+	}
+	total := terwayqos.BitsToBytes(totalBits)
+	if rate := terwayqos.BitsToBytes(netqos.GetBandwidth(totalBits, qos.NetworkQOS.EgressRequest)); rate > netqos.MinClassRate {
+		class.Rate = rate
+	}
+	if ceil := terwayqos.BitsToBytes(netqos.GetBandwidth(totalBits, qos.NetworkQOS.EgressLimit)); ceil > 0 && ceil < total {
+		class.Ceil = ceil
+	}
+	if class.Rate > class.Ceil {
+		class.Rate = class.Ceil
+	}
+}
+
+// buildEndpoints maps the ipv4 addresses of the running pods to the classes of their QoS classes. The host network
+// pods are not classified since they share the node ips.
+func buildEndpoints(podMetas []*statesinformer.PodMeta) map[string]uint16 {
+	endpoints := map[string]uint16{}
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil {
+			continue
+		}
+		pod := podMeta.Pod
+		if pod.Spec.HostNetwork || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		minor := getClassMinor(extension.GetPodQoSClassWithDefault(pod))
+		for _, podIP := range pod.Status.PodIPs {
+			endpoints[podIP.IP] = minor
+		}
+		if pod.Status.PodIP != "" {
+			endpoints[pod.Status.PodIP] = minor
+		}
+	}
+	return endpoints
+}
+
+func getClassMinor(qosClass extension.QoSClass) uint16 {
+	switch qosClass {
+	case extension.QoSSystem:
+		return netqos.SystemClassMinor
+	case extension.QoSBE:
+		return netqos.BEClassMinor
+	default:
+		return netqos.LSClassMinor
+	}
+}
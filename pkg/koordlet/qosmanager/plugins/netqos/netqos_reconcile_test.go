@@ -0,0 +1,175 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+var _ netqos.Shaper = &fakeShaper{}
+
+type fakeShaper struct {
+	total       uint64
+	classes     []netqos.Class
+	endpoints   map[string]uint16
+	ensureCount int
+	cleaned     bool
+}
+
+func (f *fakeShaper) Ensure(total uint64, classes []netqos.Class) error {
+	f.total = total
+	f.classes = classes
+	f.ensureCount++
+	return nil
+}
+
+func (f *fakeShaper) UpdateEndpoints(endpoints map[string]uint16) error {
+	f.endpoints = endpoints
+	return nil
+}
+
+func (f *fakeShaper) Cleanup() error {
+	f.cleaned = true
+	f.endpoints = nil
+	return nil
+}
+
+func newTestPod(name string, qos extension.QoSClass, ip string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{extension.LabelPodQoS: string(qos)},
+		},
+		Status: corev1.PodStatus{
+			Phase:  corev1.PodRunning,
+			PodIP:  ip,
+			PodIPs: []corev1.PodIP{{IP: ip}},
+		},
+	}
+}
+
+func newTestNodeSLO(policy slov1alpha1.NETQOSPolicy) *slov1alpha1.NodeSLO {
+	return &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			SystemStrategy: &slov1alpha1.SystemStrategy{
+				TotalNetworkBandwidth: resource.MustParse("1000M"),
+			},
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				Policies: &slov1alpha1.ResourceQOSPolicies{NETQOSPolicy: &policy},
+				LSClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{
+							EgressRequest: &intstr.IntOrString{Type: intstr.Int, IntVal: 50},
+						},
+					},
+				},
+				BEClass: &slov1alpha1.ResourceQOS{
+					NetworkQOS: &slov1alpha1.NetworkQOSCfg{
+						Enable: pointer.Bool(true),
+						NetworkQOS: slov1alpha1.NetworkQOS{
+							EgressRequest: &intstr.IntOrString{Type: intstr.String, StrVal: "100M"},
+							EgressLimit:   &intstr.IntOrString{Type: intstr.Int, IntVal: 30},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_netQoSReconcile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lsPod := newTestPod("ls-pod", extension.QoSLS, "10.0.0.1")
+	bePod := newTestPod("be-pod", extension.QoSBE, "10.0.0.2")
+	hostNetworkPod := newTestPod("host-pod", extension.QoSBE, "192.168.0.1")
+	hostNetworkPod.Spec.HostNetwork = true
+	podMetas := []*statesinformer.PodMeta{{Pod: lsPod}, {Pod: bePod}, {Pod: hostNetworkPod}}
+
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	shaper := &fakeShaper{}
+	oldNewShaper := newShaper
+	defer func() { newShaper = oldNewShaper }()
+	newShaper = func() (netqos.Shaper, error) {
+		return shaper, nil
+	}
+
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultMutableKoordletFeatureGate, features.NetQoSReconcile, true)()
+	s := New(&framework.Options{StatesInformer: si, Config: framework.NewDefaultConfig()})
+	assert.True(t, s.Enabled())
+	r := s.(*netQoSReconcile)
+
+	// the other policy takes effect, nothing to do
+	si.EXPECT().GetNodeSLO().Return(newTestNodeSLO(slov1alpha1.NETQOSPolicyTC)).Times(1)
+	r.reconcile()
+	assert.Nil(t, r.shaper)
+
+	si.EXPECT().GetNodeSLO().Return(newTestNodeSLO(slov1alpha1.NETQOSPolicyEBPF)).Times(2)
+	r.reconcile()
+	// 1000M bits per second is 125M bytes per second
+	assert.Equal(t, uint64(125000000), shaper.total)
+	assert.Equal(t, []netqos.Class{
+		{Minor: netqos.SystemClassMinor, Prio: netqos.SystemClassPrio, Rate: 50000000, Ceil: 125000000},
+		{Minor: netqos.LSClassMinor, Prio: netqos.LSClassPrio, Rate: 62500000, Ceil: 125000000},
+		{Minor: netqos.BEClassMinor, Prio: netqos.BEClassPrio, Rate: 12500000, Ceil: 37500000},
+	}, shaper.classes)
+	assert.Equal(t, map[string]uint16{
+		"10.0.0.1": netqos.LSClassMinor,
+		"10.0.0.2": netqos.BEClassMinor,
+	}, shaper.endpoints)
+	// classes are not updated if unchanged
+	r.reconcile()
+	assert.Equal(t, 1, shaper.ensureCount)
+
+	// cleanup when the policy is changed
+	si.EXPECT().GetNodeSLO().Return(newTestNodeSLO(slov1alpha1.NETQOSPolicyTC)).Times(1)
+	r.reconcile()
+	assert.True(t, shaper.cleaned)
+	assert.Nil(t, r.appliedClasses)
+}
+
+func Test_buildClasses(t *testing.T) {
+	total := uint64(16000)
+	// no strategy, all classes can borrow to the total
+	assert.Equal(t, []netqos.Class{
+		{Minor: netqos.SystemClassMinor, Prio: netqos.SystemClassPrio, Rate: netqos.MinClassRate, Ceil: 2000},
+		{Minor: netqos.LSClassMinor, Prio: netqos.LSClassPrio, Rate: netqos.MinClassRate, Ceil: 2000},
+		{Minor: netqos.BEClassMinor, Prio: netqos.BEClassPrio, Rate: netqos.MinClassRate, Ceil: 2000},
+	}, buildClasses(total, nil))
+}
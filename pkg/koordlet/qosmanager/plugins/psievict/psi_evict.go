@@ -0,0 +1,297 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psievict
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
+)
+
+const (
+	PSIEvictName = "PSIEvict"
+
+	defaultPSIEvictTimeWindowSeconds = 60
+)
+
+var _ framework.QOSStrategy = &psiEvictor{}
+
+var (
+	timeNow = time.Now
+)
+
+// pressure is the PSI of a resource exceeding the threshold.
+type pressure struct {
+	resource metriccache.MetricPropertyValue
+	// source is the node or the LS pod whose PSI exceeds the threshold
+	source string
+	value  float64
+}
+
+// psiEvictor evicts the BE pods when the some avg10 PSI of the node or any LS pod exceeds the threshold of the
+// resource for a sustained window. Unlike the utilization-based evictors, it reacts to the contention actually
+// stalling the tasks, e.g. the memory reclaim or the io congestion caused by the BE pods.
+// It evicts one BE pod each time and waits for the cooling time, since the PSI does not tell how many resources
+// should be released.
+type psiEvictor struct {
+	evictInterval         time.Duration
+	evictCoolingInterval  time.Duration
+	metricCollectInterval time.Duration
+	statesInformer        statesinformer.StatesInformer
+	metricCache           metriccache.MetricCache
+	evictor               *framework.Evictor
+	lastEvictTime         time.Time
+	onlyEvictByAPI        bool
+
+	// pressureSince records the start time of the pressure of each resource which keeps exceeding the threshold
+	pressureSince map[metriccache.MetricPropertyValue]time.Time
+}
+
+func New(opt *framework.Options) framework.QOSStrategy {
+	return &psiEvictor{
+		evictInterval:         time.Duration(opt.Config.PSIEvictIntervalSeconds) * time.Second,
+		evictCoolingInterval:  time.Duration(opt.Config.PSIEvictCoolTimeSeconds) * time.Second,
+		metricCollectInterval: opt.MetricAdvisorConfig.PSICollectorInterval,
+		statesInformer:        opt.StatesInformer,
+		metricCache:           opt.MetricCache,
+		lastEvictTime:         timeNow(),
+		onlyEvictByAPI:        opt.Config.OnlyEvictByAPI,
+		pressureSince:         map[metriccache.MetricPropertyValue]time.Time{},
+	}
+}
+
+func (p *psiEvictor) Enabled() bool {
+	return features.DefaultKoordletFeatureGate.Enabled(features.BEPSIEvict) &&
+		features.DefaultKoordletFeatureGate.Enabled(features.PSICollector) && p.evictInterval > 0
+}
+
+func (p *psiEvictor) Setup(ctx *framework.Context) {
+	p.evictor = ctx.Evictor
+}
+
+func (p *psiEvictor) Run(stopCh <-chan struct{}) {
+	go wait.Until(tracing.WrapRound(tracing.ModuleQOSManager, PSIEvictName, p.psiEvict), p.evictInterval, stopCh)
+}
+
+func (p *psiEvictor) psiEvict() {
+	klog.V(5).Infof("psi evict process start")
+
+	nodeSLO := p.statesInformer.GetNodeSLO()
+	if disabled, err := features.IsFeatureDisabled(nodeSLO, features.BEPSIEvict); err != nil {
+		klog.Warningf("psiEvict failed, cannot check the feature gate, err: %s", err)
+		return
+	} else if disabled {
+		klog.V(4).Infof("psiEvict skipped, nodeSLO disable the feature gate")
+		p.pressureSince = map[metriccache.MetricPropertyValue]time.Time{}
+		return
+	}
+
+	thresholdConfig := nodeSLO.Spec.ResourceUsedThresholdWithBE
+	thresholds := getPSIThresholds(thresholdConfig)
+	if len(thresholds) <= 0 {
+		klog.V(5).Infof("psiEvict skipped, no psi threshold is configured")
+		p.pressureSince = map[metriccache.MetricPropertyValue]time.Time{}
+		return
+	}
+	window := time.Duration(defaultPSIEvictTimeWindowSeconds) * time.Second
+	if thresholdConfig.PSIEvictTimeWindowSeconds != nil {
+		window = time.Duration(*thresholdConfig.PSIEvictTimeWindowSeconds) * time.Second
+	}
+
+	now := timeNow()
+	sustained := p.updatePressures(p.getPressures(thresholds), now, window)
+	if sustained == nil {
+		return
+	}
+	if now.Sub(p.lastEvictTime) < p.evictCoolingInterval {
+		klog.V(4).Infof("skip psi evict process, still in evict cool time")
+		return
+	}
+
+	node := p.statesInformer.GetNode()
+	if node == nil {
+		klog.Warningf("psiEvict failed, got nil node")
+		return
+	}
+	bePods := p.getBEPodsAndSort(sustained.resource, helpers.GetCompletionProtectionWindow(thresholdConfig))
+	message := fmt.Sprintf("killAndEvictBEPodsByPSI for node(%s), %s pressure of %s is %.2f for %v",
+		node.Name, sustained.resource, sustained.source, sustained.value, now.Sub(p.pressureSince[sustained.resource]))
+	if p.killAndEvictOneBEPod(node, bePods, message) {
+		p.lastEvictTime = now
+		// wait for another sustained window to check if the pressure is relieved
+		delete(p.pressureSince, sustained.resource)
+	}
+	klog.V(5).Info("psi evict process finished.")
+}
+
+// getPSIThresholds returns the configured thresholds of the PSI resources.
+func getPSIThresholds(thresholdConfig *slov1alpha1.ResourceThresholdStrategy) map[metriccache.MetricPropertyValue]float64 {
+	thresholds := map[metriccache.MetricPropertyValue]float64{}
+	if thresholdConfig == nil {
+		return thresholds
+	}
+	for psiResource, threshold := range map[metriccache.MetricPropertyValue]*int64{
+		metriccache.PSIResourceCPU: thresholdConfig.PSIEvictCPUThresholdPercent,
+		metriccache.PSIResourceMem: thresholdConfig.PSIEvictMemoryThresholdPercent,
+		metriccache.PSIResourceIO:  thresholdConfig.PSIEvictIOThresholdPercent,
+	} {
+		if threshold != nil && *threshold > 0 {
+			thresholds[psiResource] = float64(*threshold)
+		}
+	}
+	return thresholds
+}
+
+// getPressures returns the resources whose latest some avg10 PSI of the node or any LS pod exceeds the threshold.
+func (p *psiEvictor) getPressures(thresholds map[metriccache.MetricPropertyValue]float64) map[metriccache.MetricPropertyValue]*pressure {
+	pressures := map[metriccache.MetricPropertyValue]*pressure{}
+	for psiResource, threshold := range thresholds {
+		value, err := helpers.CollectNodePSILast(p.metricCache, psiResource, metriccache.PSIPrecision10,
+			metriccache.PSIDegreeSome, p.metricCollectInterval)
+		if err != nil {
+			klog.V(5).Infof("psiEvict failed to get node %s psi, err: %v", psiResource, err)
+		} else if value > threshold {
+			pressures[psiResource] = &pressure{resource: psiResource, source: "node", value: value}
+		}
+	}
+
+	for _, podMeta := range p.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if !isLSPod(pod) {
+			continue
+		}
+		for psiResource, threshold := range thresholds {
+			if _, ok := pressures[psiResource]; ok {
+				continue
+			}
+			value, err := helpers.CollectPodPSILast(p.metricCache, string(pod.UID), psiResource,
+				metriccache.PSIPrecision10, metriccache.PSIDegreeSome, p.metricCollectInterval)
+			if err != nil {
+				klog.V(6).Infof("psiEvict failed to get pod %s %s psi, err: %v", util.GetPodKey(pod), psiResource, err)
+				continue
+			}
+			if value > threshold {
+				pressures[psiResource] = &pressure{resource: psiResource, source: util.GetPodKey(pod), value: value}
+			}
+		}
+	}
+	return pressures
+}
+
+// updatePressures records the start time of the current pressures, and returns one of the pressures which has
+// lasted for the window. It returns nil if no pressure is sustained.
+func (p *psiEvictor) updatePressures(pressures map[metriccache.MetricPropertyValue]*pressure, now time.Time, window time.Duration) *pressure {
+	for psiResource := range p.pressureSince {
+		if _, ok := pressures[psiResource]; !ok {
+			delete(p.pressureSince, psiResource)
+		}
+	}
+	var sustained *pressure
+	// check the resources in order, so the result is stable
+	for _, psiResource := range []metriccache.MetricPropertyValue{
+		metriccache.PSIResourceMem, metriccache.PSIResourceIO, metriccache.PSIResourceCPU,
+	} {
+		current, ok := pressures[psiResource]
+		if !ok {
+			continue
+		}
+		since, ok := p.pressureSince[psiResource]
+		if !ok {
+			p.pressureSince[psiResource] = now
+			since = now
+		}
+		klog.V(5).Infof("psiEvict found %s pressure of %s is %.2f since %v", psiResource, current.source, current.value, since)
+		if sustained == nil && now.Sub(since) >= window {
+			sustained = current
+		}
+	}
+	return sustained
+}
+
+// getBEPodsAndSort returns the BE pods in the order to evict.
+// compare priority > completion protection > the usage of the pressured resource > custom victim rules
+func (p *psiEvictor) getBEPodsAndSort(psiResource metriccache.MetricPropertyValue, protectionWindow time.Duration) []*corev1.Pod {
+	var bePods []*corev1.Pod
+	for _, podMeta := range p.statesInformer.GetAllPods() {
+		if apiext.GetPodQoSClassRaw(podMeta.Pod) == apiext.QoSBE {
+			bePods = append(bePods, podMeta.Pod)
+		}
+	}
+	bePods = sorter.FilterVictims(bePods)
+
+	var usages map[string]float64
+	switch psiResource {
+	case metriccache.PSIResourceCPU:
+		usages = helpers.CollectAllPodMetricsLast(p.statesInformer, p.metricCache, metriccache.PodCPUUsageMetric, p.metricCollectInterval)
+	case metriccache.PSIResourceMem:
+		usages = helpers.CollectAllPodMetricsLast(p.statesInformer, p.metricCache, metriccache.PodMemUsageMetric, p.metricCollectInterval)
+	}
+	usage := func(p1, p2 *corev1.Pod) int {
+		usage1, usage2 := usages[string(p1.UID)], usages[string(p2.UID)]
+		if usage1 == usage2 {
+			return 0
+		}
+		if usage1 > usage2 {
+			return -1
+		}
+		return 1
+	}
+	sorter.VictimSorter(sorter.Priority, sorter.CompletionProtection(protectionWindow), usage).Sort(bePods)
+	return bePods
+}
+
+// killAndEvictOneBEPod evicts the first BE pod which can be evicted. It returns true if any pod is evicted.
+func (p *psiEvictor) killAndEvictOneBEPod(node *corev1.Node, bePods []*corev1.Pod, message string) bool {
+	for _, pod := range bePods {
+		if p.onlyEvictByAPI {
+			if p.evictor.EvictPodIfNotEvicted(pod, node, resourceexecutor.EvictPodByPSI, message) {
+				klog.V(5).Infof("psiEvict pick pod %s to evict", util.GetPodKey(pod))
+				return true
+			}
+			klog.V(5).Infof("psiEvict pick pod %s to evict, failed", util.GetPodKey(pod))
+			continue
+		}
+		podKillMsg := fmt.Sprintf("%s, kill pod: %s", message, util.GetPodKey(pod))
+		helpers.KillContainers(pod, resourceexecutor.EvictPodByPSI, podKillMsg)
+		klog.V(5).Infof("psiEvict pick pod %s to evict", util.GetPodKey(pod))
+		return true
+	}
+	return false
+}
+
+func isLSPod(pod *corev1.Pod) bool {
+	switch apiext.GetPodQoSClassRaw(pod) {
+	case apiext.QoSLSE, apiext.QoSLSR, apiext.QoSLS:
+		return true
+	}
+	return false
+}
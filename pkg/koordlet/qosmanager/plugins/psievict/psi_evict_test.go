@@ -0,0 +1,168 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package psievict
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientsetfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/testutil"
+)
+
+func newTestPod(name string, qosClass apiext.QoSClass, priority int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test-ns",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels:    map[string]string{apiext.LabelPodQoS: string(qosClass)},
+		},
+		Spec: corev1.PodSpec{
+			Priority: pointer.Int32(priority),
+		},
+	}
+}
+
+func Test_getPSIThresholds(t *testing.T) {
+	assert.Empty(t, getPSIThresholds(nil))
+	assert.Equal(t, map[metriccache.MetricPropertyValue]float64{
+		metriccache.PSIResourceMem: 20,
+		metriccache.PSIResourceIO:  30,
+	}, getPSIThresholds(&slov1alpha1.ResourceThresholdStrategy{
+		PSIEvictCPUThresholdPercent:    pointer.Int64(0),
+		PSIEvictMemoryThresholdPercent: pointer.Int64(20),
+		PSIEvictIOThresholdPercent:     pointer.Int64(30),
+	}))
+}
+
+func Test_psiEvict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	lsPod := newTestPod("ls-pod", apiext.QoSLS, 9000)
+	bePod1 := newTestPod("be-pod-1", apiext.QoSBE, 5500)
+	bePod2 := newTestPod("be-pod-2", apiext.QoSBE, 5000)
+	podMetas := []*statesinformer.PodMeta{{Pod: lsPod}, {Pod: bePod1}, {Pod: bePod2}}
+
+	now := time.Now()
+	var samples []metriccache.MetricSample
+	for _, s := range []struct {
+		resource metriccache.MetricPropertyValue
+		value    float64
+	}{
+		{resource: metriccache.PSIResourceCPU, value: 50},
+		{resource: metriccache.PSIResourceMem, value: 5},
+	} {
+		sample, err := metriccache.NodePSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.NodePSI(
+			string(s.resource), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), now, s.value)
+		assert.NoError(t, err)
+		samples = append(samples, sample)
+	}
+	// the io pressure of the LS pod exceeds the threshold
+	sample, err := metriccache.PodPSIMetric.GenerateSample(metriccache.MetricPropertiesFunc.PodPSI(string(lsPod.UID),
+		string(metriccache.PSIResourceIO), string(metriccache.PSIPrecision10), string(metriccache.PSIDegreeSome)), now, 40)
+	assert.NoError(t, err)
+	samples = append(samples, sample)
+	appender := metricCache.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	nodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                         pointer.Bool(true),
+				PSIEvictMemoryThresholdPercent: pointer.Int64(10),
+				PSIEvictIOThresholdPercent:     pointer.Int64(30),
+				PSIEvictTimeWindowSeconds:      pointer.Int64(30),
+			},
+		},
+	}
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetNodeSLO().Return(nodeSLO).AnyTimes()
+	si.EXPECT().GetNode().Return(testutil.MockTestNode("100", "500G")).AnyTimes()
+	si.EXPECT().GetAllPods().Return(podMetas).AnyTimes()
+
+	client := clientsetfake.NewSimpleClientset()
+	for _, podMeta := range podMetas {
+		_, err = client.CoreV1().Pods(podMeta.Pod.Namespace).Create(context.TODO(), podMeta.Pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	stop := make(chan struct{})
+	evictor := framework.NewEvictor(client, &testutil.FakeRecorder{}, policyv1beta1.SchemeGroupVersion.Version)
+	evictor.Start(stop)
+	defer func() { stop <- struct{}{} }()
+
+	p := &psiEvictor{
+		evictInterval:         time.Second,
+		evictCoolingInterval:  time.Minute,
+		metricCollectInterval: 10 * time.Second,
+		statesInformer:        si,
+		metricCache:           metricCache,
+		evictor:               evictor,
+		lastEvictTime:         now.Add(-5 * time.Minute),
+		onlyEvictByAPI:        true,
+		pressureSince:         map[metriccache.MetricPropertyValue]time.Time{},
+	}
+	defer func() { timeNow = time.Now }()
+
+	// the pressure starts, not sustained for the window
+	timeNow = func() time.Time { return now }
+	p.psiEvict()
+	assert.Equal(t, map[metriccache.MetricPropertyValue]time.Time{metriccache.PSIResourceIO: now}, p.pressureSince)
+	assert.False(t, evictor.IsPodEvicted(bePod1))
+	assert.False(t, evictor.IsPodEvicted(bePod2))
+
+	// the pressure is sustained, evict the BE pod of the lowest priority
+	timeNow = func() time.Time { return now.Add(30 * time.Second) }
+	p.psiEvict()
+	assert.False(t, evictor.IsPodEvicted(bePod1))
+	assert.True(t, evictor.IsPodEvicted(bePod2))
+	assert.Empty(t, p.pressureSince)
+
+	// still in the cooling time
+	timeNow = func() time.Time { return now.Add(70 * time.Second) }
+	p.psiEvict()
+	assert.False(t, evictor.IsPodEvicted(bePod1))
+
+	// the pressure is cleared when the feature is disabled
+	nodeSLO.Spec.ResourceUsedThresholdWithBE.Enable = pointer.Bool(false)
+	p.psiEvict()
+	assert.Empty(t, p.pressureSince)
+}
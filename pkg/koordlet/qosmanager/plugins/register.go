@@ -23,19 +23,40 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuburst"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpuevict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/cpusuppress"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/diskquota"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/gpumps"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/imageprepull"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memoryevict"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memorythrottle"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/memorytiering"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/netqos"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/psievict"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins/sysreconcile"
 )
 
 var (
-	StrategyPlugins = map[string]framework.QOSStrategyFactory{
+	// StrategyPlugins is the registry of the qos strategies run by the qos manager, which includes the in-tree ones.
+	// The out-of-tree strategies can be registered before the qos manager is created, e.g.
+	//   func init() {
+	//     utilruntime.Must(plugins.StrategyPlugins.Register("MyStrategy", mystrategy.New))
+	//   }
+	StrategyPlugins = framework.Registry{
 		blkio.BlkIOReconcileName:               blkio.New,
+		blkio.IOCostReconcileName:              blkio.NewIOCostReconcile,
 		cgreconcile.CgroupReconcileName:        cgreconcile.New,
 		cpuburst.CPUBurstName:                  cpuburst.New,
 		cpuevict.CPUEvictName:                  cpuevict.New,
 		cpusuppress.CPUSuppressName:            cpusuppress.New,
+		diskquota.DiskQuotaReconcileName:       diskquota.New,
+		gpumps.GPUMPSReconcileName:             gpumps.New,
+		imageprepull.ImagePrePullName:          imageprepull.New,
 		memoryevict.MemoryEvictName:            memoryevict.New,
+		memorythrottle.MemoryThrottleName:      memorythrottle.New,
+		memorytiering.MemoryTieringName:        memorytiering.New,
+		netqos.NetQoSReconcileName:             netqos.New,
+		netqos.DSCPReconcileName:               netqos.NewDSCPReconcile,
+		psievict.PSIEvictName:                  psievict.New,
 		resctrl.ResctrlReconcileName:           resctrl.New,
 		sysreconcile.SystemConfigReconcileName: sysreconcile.New,
 	}
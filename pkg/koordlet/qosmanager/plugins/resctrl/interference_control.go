@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+)
+
+const (
+	defaultInterferenceCPIDegradationPercent int64 = 20
+	defaultInterferenceHysteresisPercent     int64 = 10
+	defaultInterferenceStepPercent           int64 = 10
+	defaultInterferenceMinCATRangePercent    int64 = 10
+	defaultInterferenceMinMBAPercent         int64 = 10
+
+	// interferenceBaselineDecay is the weight of the history baseline when the baseline is updated by a new CPI
+	interferenceBaselineDecay = 0.9
+)
+
+// interferenceController tightens the BE resctrl group by the CPI of the LS pods. It learns the CPI baseline when
+// the BE group is not tightened, and changes the tightening level by one step each time a new CPI sample arrives.
+type interferenceController struct {
+	// updateInterval is the minimal interval between two updates, which should be no less than the CPI collecting
+	// interval, so that the same sample is not counted repeatedly
+	updateInterval time.Duration
+	lastUpdateTime time.Time
+	// baseline is the learned LS CPI without the interference, 0 means uninitialized
+	baseline float64
+	// level is the number of steps the BE group is tightened
+	level int64
+}
+
+func newInterferenceController(updateInterval time.Duration) *interferenceController {
+	return &interferenceController{
+		updateInterval: updateInterval,
+	}
+}
+
+func isInterferenceControlEnabled(strategy *slov1alpha1.ResourceQOSStrategy) bool {
+	if strategy == nil || strategy.ResctrlInterferenceControl == nil {
+		return false
+	}
+	cfg := strategy.ResctrlInterferenceControl
+	return cfg.Enable != nil && *cfg.Enable
+}
+
+// getInterferenceControlParams returns the CPI degradation percent, the hysteresis percent, the step percent, the
+// min CAT range percent and the min MBA percent with the defaults.
+func getInterferenceControlParams(cfg *slov1alpha1.ResctrlInterferenceControlStrategy) (int64, int64, int64, int64, int64) {
+	degradation, hysteresis, step := defaultInterferenceCPIDegradationPercent, defaultInterferenceHysteresisPercent,
+		defaultInterferenceStepPercent
+	minCATRange, minMBA := defaultInterferenceMinCATRangePercent, defaultInterferenceMinMBAPercent
+	if cfg.CPIDegradationPercent != nil && *cfg.CPIDegradationPercent > 0 {
+		degradation = *cfg.CPIDegradationPercent
+	}
+	if cfg.HysteresisPercent != nil && *cfg.HysteresisPercent >= 0 && *cfg.HysteresisPercent <= 100 {
+		hysteresis = *cfg.HysteresisPercent
+	}
+	if hysteresis > degradation {
+		hysteresis = degradation
+	}
+	if cfg.StepPercent != nil && *cfg.StepPercent > 0 && *cfg.StepPercent <= 100 {
+		step = *cfg.StepPercent
+	}
+	if cfg.MinCATRangePercent != nil && *cfg.MinCATRangePercent > 0 && *cfg.MinCATRangePercent <= 100 {
+		minCATRange = *cfg.MinCATRangePercent
+	}
+	if cfg.MinMBAPercent != nil && *cfg.MinMBAPercent > 0 && *cfg.MinMBAPercent <= 100 {
+		minMBA = *cfg.MinMBAPercent
+	}
+	return degradation, hysteresis, step, minCATRange, minMBA
+}
+
+// reset drops the baseline and the tightening level, so the BE group is restored to the configured policy.
+func (c *interferenceController) reset() {
+	c.lastUpdateTime = time.Time{}
+	c.baseline = 0
+	c.level = 0
+}
+
+// update changes the tightening level by the LS CPI. The level is increased by one when the CPI exceeds the baseline
+// by the degradation percent, and decreased by one when the CPI is lower than the degradation minus the hysteresis.
+// The baseline follows the lower CPI at once, and it follows the higher CPI slowly only when the BE group is not
+// tightened, so the degradation caused by the BE pods is not learned as the baseline.
+func (c *interferenceController) update(cfg *slov1alpha1.ResctrlInterferenceControlStrategy, cpi float64, now time.Time) {
+	if cpi <= 0 {
+		return
+	}
+	if !c.lastUpdateTime.IsZero() && now.Sub(c.lastUpdateTime) < c.updateInterval {
+		return
+	}
+	c.lastUpdateTime = now
+	if c.baseline <= 0 {
+		c.baseline = cpi
+		return
+	}
+
+	degradation, hysteresis, step, _, _ := getInterferenceControlParams(cfg)
+	highWatermark := c.baseline * float64(100+degradation) / 100
+	lowWatermark := c.baseline * float64(100+degradation-hysteresis) / 100
+	oldLevel := c.level
+	if cpi > highWatermark {
+		// the level is bounded by the steps to reach the lower bounds from the full resource
+		if maxLevel := (100 + step - 1) / step; c.level < maxLevel {
+			c.level++
+		}
+	} else if cpi < lowWatermark && c.level > 0 {
+		c.level--
+	}
+	if cpi < c.baseline {
+		c.baseline = cpi
+	} else if c.level == 0 && cpi <= highWatermark {
+		c.baseline = c.baseline*interferenceBaselineDecay + cpi*(1-interferenceBaselineDecay)
+	}
+	if c.level != oldLevel {
+		klog.V(4).Infof("interference control level for group %s changes from %d to %d, LS CPI %.3f, baseline %.3f",
+			BEResctrlGroup, oldLevel, c.level, cpi, c.baseline)
+	}
+}
+
+// tighten returns the resource qos of the BE group tightened by the current level. The CAT range is shrunk from the
+// end percent and the MBA percent is decreased with the lower bounds. The MBA percent is left to the adaptive MBA
+// when the adaptive MBA is enabled. The original resource qos is not modified.
+func (c *interferenceController) tighten(cfg *slov1alpha1.ResctrlInterferenceControlStrategy, resourceQoS *slov1alpha1.ResourceQOS,
+	isMBAAdaptive bool) *slov1alpha1.ResourceQOS {
+	if c.level <= 0 || resourceQoS == nil || resourceQoS.ResctrlQOS == nil {
+		return resourceQoS
+	}
+	_, _, step, minCATRange, minMBA := getInterferenceControlParams(cfg)
+	delta := c.level * step
+	tightened := resourceQoS.DeepCopy()
+	// the socket overrides are resolved before tightened since their unspecified values inherit the class
+	for i := range tightened.ResctrlQOS.Sockets {
+		socketQoS := mergeSocketResctrlQOS(resourceQoS.ResctrlQOS.ResctrlQOS, tightened.ResctrlQOS.Sockets[i].ResctrlQOS)
+		tightenResctrlQOS(&socketQoS, delta, minCATRange, minMBA, isMBAAdaptive)
+		tightened.ResctrlQOS.Sockets[i].ResctrlQOS = socketQoS
+	}
+	tightenResctrlQOS(&tightened.ResctrlQOS.ResctrlQOS, delta, minCATRange, minMBA, isMBAAdaptive)
+	return tightened
+}
+
+func tightenCATRange(startPercent *int64, endPercent **int64, delta, minCATRange int64) {
+	if startPercent == nil || *endPercent == nil {
+		return
+	}
+	start, end := *startPercent, **endPercent
+	if end-start > minCATRange {
+		newEnd := end - delta
+		if newEnd-start < minCATRange {
+			newEnd = start + minCATRange
+		}
+		*endPercent = &newEnd
+	}
+}
+
+func tightenResctrlQOS(resctrlQoS *slov1alpha1.ResctrlQOS, delta, minCATRange, minMBA int64, isMBAAdaptive bool) {
+	tightenCATRange(resctrlQoS.CATRangeStartPercent, &resctrlQoS.CATRangeEndPercent, delta, minCATRange)
+	// the code range in the CDP mode is tightened in the same way
+	tightenCATRange(resctrlQoS.CATCodeRangeStartPercent, &resctrlQoS.CATCodeRangeEndPercent, delta, minCATRange)
+	if !isMBAAdaptive {
+		mbaPercent := int64(100)
+		if resctrlQoS.MBAPercent != nil && *resctrlQoS.MBAPercent > 0 && *resctrlQoS.MBAPercent <= 100 {
+			mbaPercent = *resctrlQoS.MBAPercent
+		}
+		if mbaPercent > minMBA {
+			newPercent := mbaPercent - delta
+			if newPercent < minMBA {
+				newPercent = minMBA
+			}
+			resctrlQoS.MBAPercent = &newPercent
+		}
+	}
+}
+
+// getLSCPI returns the CPI of the LS pods on the node, which is the sum of the cycles divided by the sum of the
+// instructions of the collected containers. It returns 0 when no CPI is collected.
+func (r *resctrlReconcile) getLSCPI() float64 {
+	var cycles, instructions float64
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		if pod == nil {
+			continue
+		}
+		qosClass := extension.GetPodQoSClassWithDefault(pod)
+		if qosClass != extension.QoSLS && qosClass != extension.QoSLSR && qosClass != extension.QoSLSE {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.ContainerID == "" {
+				continue
+			}
+			cycle, err := r.collectContainerCPIResource(string(pod.UID), status.ContainerID, metriccache.CPIResourceCycle)
+			if err != nil {
+				klog.V(6).Infof("failed to get cpi cycles of container %s/%s/%s, err: %v",
+					pod.Namespace, pod.Name, status.Name, err)
+				continue
+			}
+			instruction, err := r.collectContainerCPIResource(string(pod.UID), status.ContainerID, metriccache.CPIResourceInstruction)
+			if err != nil {
+				klog.V(6).Infof("failed to get cpi instructions of container %s/%s/%s, err: %v",
+					pod.Namespace, pod.Name, status.Name, err)
+				continue
+			}
+			cycles += cycle
+			instructions += instruction
+		}
+	}
+	if instructions <= 0 {
+		return 0
+	}
+	return cycles / instructions
+}
+
+func (r *resctrlReconcile) collectContainerCPIResource(podUID, containerID string, cpiResource metriccache.MetricPropertyValue) (float64, error) {
+	queryMeta, err := metriccache.ContainerCPI.BuildQueryMeta(metriccache.MetricPropertiesFunc.ContainerCPI(podUID, containerID, string(cpiResource)))
+	if err != nil {
+		return 0, err
+	}
+	return helpers.CollectContainerResMetricLast(r.metricCache, queryMeta, r.cpiCollectInterval)
+}
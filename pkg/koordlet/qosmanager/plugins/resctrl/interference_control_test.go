@@ -0,0 +1,299 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+func Test_getInterferenceControlParams(t *testing.T) {
+	degradation, hysteresis, step, minCATRange, minMBA := getInterferenceControlParams(&slov1alpha1.ResctrlInterferenceControlStrategy{})
+	assert.Equal(t, []int64{20, 10, 10, 10, 10}, []int64{degradation, hysteresis, step, minCATRange, minMBA})
+
+	degradation, hysteresis, step, minCATRange, minMBA = getInterferenceControlParams(&slov1alpha1.ResctrlInterferenceControlStrategy{
+		CPIDegradationPercent: pointer.Int64(10),
+		HysteresisPercent:     pointer.Int64(30),
+		StepPercent:           pointer.Int64(0),
+		MinCATRangePercent:    pointer.Int64(20),
+		MinMBAPercent:         pointer.Int64(200),
+	})
+	// the hysteresis is bounded by the degradation, and the invalid values are ignored
+	assert.Equal(t, []int64{10, 10, 10, 20, 10}, []int64{degradation, hysteresis, step, minCATRange, minMBA})
+}
+
+func TestInterferenceController_update(t *testing.T) {
+	cfg := &slov1alpha1.ResctrlInterferenceControlStrategy{
+		Enable:                pointer.Bool(true),
+		CPIDegradationPercent: pointer.Int64(20),
+		HysteresisPercent:     pointer.Int64(10),
+		StepPercent:           pointer.Int64(50),
+	}
+	tests := []struct {
+		name         string
+		cpis         []float64
+		wantLevels   []int64
+		wantBaseline float64
+	}{
+		{
+			name:         "tighten to the max level when LS CPI keeps degraded",
+			cpis:         []float64{1.0, 1.3, 1.3, 1.3},
+			wantLevels:   []int64{0, 1, 2, 2},
+			wantBaseline: 1.0,
+		},
+		{
+			name:       "keep in the hysteresis and relax when LS CPI recovers",
+			cpis:       []float64{1.0, 1.3, 1.15, 1.05, 1.05},
+			wantLevels: []int64{0, 1, 1, 0, 0},
+			// the baseline follows the higher CPI slowly after relaxed
+			wantBaseline: 1.0095,
+		},
+		{
+			name:         "follow the lower CPI as the baseline",
+			cpis:         []float64{1.0, 0.5, 0.65},
+			wantLevels:   []int64{0, 0, 1},
+			wantBaseline: 0.5,
+		},
+		{
+			name:         "ignore the unavailable CPI",
+			cpis:         []float64{0, 1.0, 0, 1.3},
+			wantLevels:   []int64{0, 0, 0, 1},
+			wantBaseline: 1.0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newInterferenceController(time.Minute)
+			now := time.Now()
+			for i, cpi := range tt.cpis {
+				c.update(cfg, cpi, now.Add(time.Duration(i)*time.Minute))
+				assert.Equal(t, tt.wantLevels[i], c.level, "round %d", i)
+			}
+			assert.InDelta(t, tt.wantBaseline, c.baseline, 1e-6)
+
+			// the same sample within the interval is not counted
+			level := c.level
+			c.update(cfg, 100, now.Add(time.Duration(len(tt.cpis)-1)*time.Minute+time.Second))
+			assert.Equal(t, level, c.level)
+
+			c.reset()
+			assert.Equal(t, int64(0), c.level)
+			assert.Equal(t, float64(0), c.baseline)
+		})
+	}
+}
+
+func TestInterferenceController_tighten(t *testing.T) {
+	cfg := &slov1alpha1.ResctrlInterferenceControlStrategy{
+		Enable:             pointer.Bool(true),
+		StepPercent:        pointer.Int64(20),
+		MinCATRangePercent: pointer.Int64(30),
+		MinMBAPercent:      pointer.Int64(30),
+	}
+	beQoS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			Enable: pointer.Bool(true),
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64(0),
+				CATRangeEndPercent:   pointer.Int64(80),
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		level         int64
+		isMBAAdaptive bool
+		wantCATEnd    int64
+		wantMBA       *int64
+	}{
+		{
+			name:       "not tightened",
+			level:      0,
+			wantCATEnd: 80,
+		},
+		{
+			name:       "tighten by one step",
+			level:      1,
+			wantCATEnd: 60,
+			wantMBA:    pointer.Int64(80),
+		},
+		{
+			name:       "tighten to the lower bounds",
+			level:      5,
+			wantCATEnd: 30,
+			wantMBA:    pointer.Int64(30),
+		},
+		{
+			name:          "leave MBA to the adaptive MBA",
+			level:         2,
+			isMBAAdaptive: true,
+			wantCATEnd:    40,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &interferenceController{level: tt.level}
+			got := c.tighten(cfg, beQoS, tt.isMBAAdaptive)
+			assert.Equal(t, tt.wantCATEnd, *got.ResctrlQOS.CATRangeEndPercent)
+			assert.Equal(t, tt.wantMBA, got.ResctrlQOS.MBAPercent)
+			// the config is not modified
+			assert.Equal(t, int64(80), *beQoS.ResctrlQOS.CATRangeEndPercent)
+			assert.Nil(t, beQoS.ResctrlQOS.MBAPercent)
+		})
+	}
+}
+
+func TestInterferenceController_tightenSockets(t *testing.T) {
+	cfg := &slov1alpha1.ResctrlInterferenceControlStrategy{
+		Enable:             pointer.Bool(true),
+		StepPercent:        pointer.Int64(20),
+		MinCATRangePercent: pointer.Int64(30),
+		MinMBAPercent:      pointer.Int64(30),
+	}
+	beQoS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			Enable: pointer.Bool(true),
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64(0),
+				CATRangeEndPercent:   pointer.Int64(60),
+			},
+			Sockets: []slov1alpha1.ResctrlSocketQOS{
+				{
+					SocketIDs: []int32{1},
+					ResctrlQOS: slov1alpha1.ResctrlQOS{
+						CATRangeEndPercent: pointer.Int64(100),
+					},
+				},
+			},
+		},
+	}
+	c := &interferenceController{level: 1}
+	got := c.tighten(cfg, beQoS, false)
+	assert.Equal(t, int64(40), *got.ResctrlQOS.CATRangeEndPercent)
+	assert.Equal(t, int64(80), *got.ResctrlQOS.MBAPercent)
+	socketQoS := got.ResctrlQOS.Sockets[0]
+	assert.Equal(t, []int32{1}, socketQoS.SocketIDs)
+	assert.Equal(t, int64(0), *socketQoS.CATRangeStartPercent)
+	assert.Equal(t, int64(80), *socketQoS.CATRangeEndPercent)
+	assert.Equal(t, int64(80), *socketQoS.MBAPercent)
+	// the config is not modified
+	assert.Equal(t, int64(100), *beQoS.ResctrlQOS.Sockets[0].CATRangeEndPercent)
+	assert.Nil(t, beQoS.ResctrlQOS.Sockets[0].CATRangeStartPercent)
+}
+
+func TestInterferenceController_tightenCodeRange(t *testing.T) {
+	cfg := &slov1alpha1.ResctrlInterferenceControlStrategy{
+		Enable:             pointer.Bool(true),
+		StepPercent:        pointer.Int64(20),
+		MinCATRangePercent: pointer.Int64(30),
+		MinMBAPercent:      pointer.Int64(30),
+	}
+	beQoS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			Enable: pointer.Bool(true),
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent:     pointer.Int64(0),
+				CATRangeEndPercent:       pointer.Int64(60),
+				CATCodeRangeStartPercent: pointer.Int64(50),
+				CATCodeRangeEndPercent:   pointer.Int64(100),
+			},
+		},
+	}
+	c := &interferenceController{level: 1}
+	got := c.tighten(cfg, beQoS, false)
+	assert.Equal(t, int64(40), *got.ResctrlQOS.CATRangeEndPercent)
+	assert.Equal(t, int64(50), *got.ResctrlQOS.CATCodeRangeStartPercent)
+	assert.Equal(t, int64(80), *got.ResctrlQOS.CATCodeRangeEndPercent)
+	// the config is not modified
+	assert.Equal(t, int64(100), *beQoS.ResctrlQOS.CATCodeRangeEndPercent)
+}
+
+func TestResctrlReconcile_getLSCPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	newPod := func(name string, qos extension.QoSClass) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				UID:    types.UID("uid-" + name),
+				Labels: map[string]string{extension.LabelPodQoS: string(qos)},
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: "main", ContainerID: "containerd://" + name},
+				},
+			},
+		}
+	}
+	lsPod := newPod("ls-pod", extension.QoSLS)
+	lsrPod := newPod("lsr-pod", extension.QoSLSR)
+	bePod := newPod("be-pod", extension.QoSBE)
+
+	mc, err := metriccache.NewMetricCache(&metriccache.Config{TSDBPath: t.TempDir(), TSDBEnablePromMetrics: false})
+	assert.NoError(t, err)
+	now := time.Now()
+	var samples []metriccache.MetricSample
+	for _, v := range []struct {
+		pod          *corev1.Pod
+		cycles       float64
+		instructions float64
+	}{
+		{pod: lsPod, cycles: 100, instructions: 100},
+		{pod: lsrPod, cycles: 200, instructions: 100},
+		{pod: bePod, cycles: 1000, instructions: 100},
+	} {
+		containerID := v.pod.Status.ContainerStatuses[0].ContainerID
+		cycle, err := metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(string(v.pod.UID),
+			containerID, string(metriccache.CPIResourceCycle)), now, v.cycles)
+		assert.NoError(t, err)
+		instruction, err := metriccache.ContainerCPI.GenerateSample(metriccache.MetricPropertiesFunc.ContainerCPI(string(v.pod.UID),
+			containerID, string(metriccache.CPIResourceInstruction)), now, v.instructions)
+		assert.NoError(t, err)
+		samples = append(samples, cycle, instruction)
+	}
+	appender := mc.Appender()
+	assert.NoError(t, appender.Append(samples))
+	assert.NoError(t, appender.Commit())
+
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	r := &resctrlReconcile{
+		statesInformer:     si,
+		metricCache:        mc,
+		cpiCollectInterval: time.Minute,
+	}
+
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: lsPod}, {Pod: lsrPod}, {Pod: bePod}}).Times(1)
+	// (100 + 200) / (100 + 100), the BE pod is excluded
+	assert.InDelta(t, 1.5, r.getLSCPI(), 1e-6)
+
+	si.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{{Pod: bePod}}).Times(1)
+	assert.Equal(t, float64(0), r.getLSCPI())
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	defaultMBAAdaptiveHysteresisPercent int64 = 10
+	defaultMBAAdaptiveStepPercent       int64 = 10
+	defaultMBAAdaptiveMinPercent        int64 = 10
+	defaultMBAAdaptiveMaxPercent        int64 = 100
+
+	bytesPerMB = 1024 * 1024
+)
+
+// mbaAdaptiveController tunes the MBA percent of the BE resctrl group by the memory bandwidth of the LS resctrl
+// group, which is calculated from the increment of the MBM total bytes between two reconciliations.
+type mbaAdaptiveController struct {
+	newReader func() resourceexecutor.ResctrlReader
+	reader    resourceexecutor.ResctrlReader
+
+	lastTotalBytes uint64
+	lastTime       time.Time
+	// mbaPercent is the current MBA percent of the BE group, 0 means uninitialized
+	mbaPercent int64
+}
+
+func newMBAAdaptiveController() *mbaAdaptiveController {
+	return &mbaAdaptiveController{
+		newReader: resourceexecutor.NewResctrlReader,
+	}
+}
+
+func isMBAAdaptiveEnabled(strategy *slov1alpha1.ResourceQOSStrategy) bool {
+	if strategy == nil || strategy.ResctrlMBAAdaptive == nil {
+		return false
+	}
+	cfg := strategy.ResctrlMBAAdaptive
+	return cfg.Enable != nil && *cfg.Enable && cfg.LSMemoryBandwidthTargetMBps != nil
+}
+
+// getMBAAdaptiveBounds returns the min percent, max percent, step percent and hysteresis percent with the defaults.
+func getMBAAdaptiveBounds(cfg *slov1alpha1.ResctrlMBAAdaptiveStrategy) (int64, int64, int64, int64) {
+	minPercent, maxPercent := defaultMBAAdaptiveMinPercent, defaultMBAAdaptiveMaxPercent
+	step, hysteresis := defaultMBAAdaptiveStepPercent, defaultMBAAdaptiveHysteresisPercent
+	if cfg.MinMBAPercent != nil && *cfg.MinMBAPercent > 0 && *cfg.MinMBAPercent <= 100 {
+		minPercent = *cfg.MinMBAPercent
+	}
+	if cfg.MaxMBAPercent != nil && *cfg.MaxMBAPercent > 0 && *cfg.MaxMBAPercent <= 100 {
+		maxPercent = *cfg.MaxMBAPercent
+	}
+	if minPercent > maxPercent {
+		klog.V(4).Infof("invalid bounds of adaptive MBA, min %d is larger than max %d, use max as min",
+			minPercent, maxPercent)
+		minPercent = maxPercent
+	}
+	if cfg.StepPercent != nil && *cfg.StepPercent > 0 && *cfg.StepPercent <= 100 {
+		step = *cfg.StepPercent
+	}
+	if cfg.HysteresisPercent != nil && *cfg.HysteresisPercent >= 0 && *cfg.HysteresisPercent <= 100 {
+		hysteresis = *cfg.HysteresisPercent
+	}
+	return minPercent, maxPercent, step, hysteresis
+}
+
+// reset drops the measurement and the tuned MBA percent, so the tuning restarts from the initial percent.
+func (c *mbaAdaptiveController) reset() {
+	c.lastTotalBytes = 0
+	c.lastTime = time.Time{}
+	c.mbaPercent = 0
+}
+
+// calculate returns the MBA percent of the BE group for the current round. The MBA percent starts from the initial
+// percent, and it is decreased by a step when the LS memory bandwidth exceeds the target, and increased by a step
+// when the LS memory bandwidth is lower than the target minus the hysteresis. It keeps unchanged when the memory
+// bandwidth is unavailable, e.g. the first measurement.
+func (c *mbaAdaptiveController) calculate(cfg *slov1alpha1.ResctrlMBAAdaptiveStrategy, initPercent *int64, now time.Time) int64 {
+	minPercent, maxPercent, step, hysteresis := getMBAAdaptiveBounds(cfg)
+	if c.mbaPercent <= 0 {
+		c.mbaPercent = maxPercent
+		if initPercent != nil && *initPercent > 0 && *initPercent <= 100 {
+			c.mbaPercent = *initPercent
+		}
+	}
+	c.mbaPercent = boundMBAPercent(c.mbaPercent, minPercent, maxPercent)
+
+	bandwidth, ok := c.readLSMemoryBandwidth(now)
+	if !ok {
+		return c.mbaPercent
+	}
+	target := float64(*cfg.LSMemoryBandwidthTargetMBps) * bytesPerMB
+	lowWatermark := target * float64(100-hysteresis) / 100
+	oldPercent := c.mbaPercent
+	if bandwidth > target {
+		c.mbaPercent = boundMBAPercent(c.mbaPercent-step, minPercent, maxPercent)
+	} else if bandwidth < lowWatermark {
+		c.mbaPercent = boundMBAPercent(c.mbaPercent+step, minPercent, maxPercent)
+	}
+	if c.mbaPercent != oldPercent {
+		klog.V(4).Infof("adaptive MBA for group %s changes from %d to %d, LS memory bandwidth %.0f bytes/s, "+
+			"target %.0f bytes/s", BEResctrlGroup, oldPercent, c.mbaPercent, bandwidth, target)
+	}
+	return c.mbaPercent
+}
+
+// readLSMemoryBandwidth reads the total MBM bytes of the LS group and returns the memory bandwidth in bytes/s since
+// the last measurement.
+func (c *mbaAdaptiveController) readLSMemoryBandwidth(now time.Time) (float64, bool) {
+	if c.reader == nil {
+		c.reader = c.newReader()
+	}
+	mbStat, err := c.reader.ReadResctrlMBStat(LSResctrlGroup)
+	if err != nil {
+		klog.V(4).Infof("failed to read memory bandwidth of group %s, err: %v", LSResctrlGroup, err)
+		return 0, false
+	}
+	var totalBytes uint64
+	for _, stat := range mbStat {
+		totalBytes += stat[system.ResctrlMBMTotalName]
+	}
+
+	lastTotalBytes, lastTime := c.lastTotalBytes, c.lastTime
+	c.lastTotalBytes, c.lastTime = totalBytes, now
+	// skip the first measurement and the counter reset, e.g. the resctrl group is recreated
+	if lastTime.IsZero() || totalBytes < lastTotalBytes || !now.After(lastTime) {
+		return 0, false
+	}
+	return float64(totalBytes-lastTotalBytes) / now.Sub(lastTime).Seconds(), true
+}
+
+func boundMBAPercent(percent, minPercent, maxPercent int64) int64 {
+	if percent < minPercent {
+		return minPercent
+	}
+	if percent > maxPercent {
+		return maxPercent
+	}
+	return percent
+}
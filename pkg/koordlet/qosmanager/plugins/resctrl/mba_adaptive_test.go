@@ -0,0 +1,250 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// testMBStatReader returns the MBM total bytes of the LS group on two cache domains in turn.
+type testMBStatReader struct {
+	totalBytes []uint64
+	index      int
+}
+
+func (r *testMBStatReader) ReadResctrlL3Stat(parent string) (map[resourceexecutor.CacheId]uint64, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *testMBStatReader) ReadResctrlMBStat(parent string) (map[resourceexecutor.CacheId]system.MBStatData, error) {
+	if parent != LSResctrlGroup {
+		return nil, fmt.Errorf("unexpected group %s", parent)
+	}
+	if r.index >= len(r.totalBytes) {
+		return nil, fmt.Errorf("no more stat")
+	}
+	totalBytes := r.totalBytes[r.index]
+	r.index++
+	return map[resourceexecutor.CacheId]system.MBStatData{
+		0: {system.ResctrlMBMTotalName: totalBytes / 2},
+		1: {system.ResctrlMBMTotalName: totalBytes - totalBytes/2},
+	}, nil
+}
+
+func (r *testMBStatReader) ReadResctrlL2Stat(parent string) (map[resourceexecutor.CacheId]uint64, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func Test_getMBAAdaptiveBounds(t *testing.T) {
+	tests := []struct {
+		name           string
+		arg            *slov1alpha1.ResctrlMBAAdaptiveStrategy
+		wantMin        int64
+		wantMax        int64
+		wantStep       int64
+		wantHysteresis int64
+	}{
+		{
+			name:           "use defaults",
+			arg:            &slov1alpha1.ResctrlMBAAdaptiveStrategy{},
+			wantMin:        10,
+			wantMax:        100,
+			wantStep:       10,
+			wantHysteresis: 10,
+		},
+		{
+			name: "use configured values",
+			arg: &slov1alpha1.ResctrlMBAAdaptiveStrategy{
+				HysteresisPercent: pointer.Int64(20),
+				StepPercent:       pointer.Int64(5),
+				MinMBAPercent:     pointer.Int64(30),
+				MaxMBAPercent:     pointer.Int64(80),
+			},
+			wantMin:        30,
+			wantMax:        80,
+			wantStep:       5,
+			wantHysteresis: 20,
+		},
+		{
+			name: "ignore invalid values",
+			arg: &slov1alpha1.ResctrlMBAAdaptiveStrategy{
+				HysteresisPercent: pointer.Int64(-1),
+				StepPercent:       pointer.Int64(0),
+				MinMBAPercent:     pointer.Int64(0),
+				MaxMBAPercent:     pointer.Int64(200),
+			},
+			wantMin:        10,
+			wantMax:        100,
+			wantStep:       10,
+			wantHysteresis: 10,
+		},
+		{
+			name: "min larger than max",
+			arg: &slov1alpha1.ResctrlMBAAdaptiveStrategy{
+				MinMBAPercent: pointer.Int64(60),
+				MaxMBAPercent: pointer.Int64(40),
+			},
+			wantMin:        40,
+			wantMax:        40,
+			wantStep:       10,
+			wantHysteresis: 10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMin, gotMax, gotStep, gotHysteresis := getMBAAdaptiveBounds(tt.arg)
+			assert.Equal(t, tt.wantMin, gotMin)
+			assert.Equal(t, tt.wantMax, gotMax)
+			assert.Equal(t, tt.wantStep, gotStep)
+			assert.Equal(t, tt.wantHysteresis, gotHysteresis)
+		})
+	}
+}
+
+func TestMBAAdaptiveController_calculate(t *testing.T) {
+	const mb = uint64(bytesPerMB)
+	cfg := &slov1alpha1.ResctrlMBAAdaptiveStrategy{
+		Enable:                      pointer.Bool(true),
+		LSMemoryBandwidthTargetMBps: pointer.Int64(1000),
+		HysteresisPercent:           pointer.Int64(20),
+		StepPercent:                 pointer.Int64(20),
+		MinMBAPercent:               pointer.Int64(20),
+		MaxMBAPercent:               pointer.Int64(90),
+	}
+	tests := []struct {
+		name        string
+		initPercent *int64
+		totalBytes  []uint64
+		want        []int64
+	}{
+		{
+			name:        "start from the initial percent and decrease to the min when LS exceeds the target",
+			initPercent: pointer.Int64(80),
+			// 1200MB/s each second
+			totalBytes: []uint64{0, 1200 * mb, 2400 * mb, 3600 * mb, 4800 * mb},
+			want:       []int64{80, 60, 40, 20, 20},
+		},
+		{
+			name: "start from the max percent and keep in the hysteresis",
+			// 900MB/s, 850MB/s
+			totalBytes: []uint64{0, 900 * mb, 1750 * mb},
+			want:       []int64{90, 90, 90},
+		},
+		{
+			name:        "increase to the max when LS is below the target minus hysteresis",
+			initPercent: pointer.Int64(20),
+			// 1100MB/s, 700MB/s, 500MB/s, 100MB/s, 100MB/s
+			totalBytes: []uint64{0, 1100 * mb, 1800 * mb, 2300 * mb, 2400 * mb, 2500 * mb},
+			want:       []int64{20, 20, 40, 60, 80, 90},
+		},
+		{
+			name:        "keep unchanged when the counter resets or stat is unavailable",
+			initPercent: pointer.Int64(50),
+			totalBytes:  []uint64{2000 * mb, 100 * mb, 1300 * mb},
+			want:        []int64{50, 50, 30, 30},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &testMBStatReader{totalBytes: tt.totalBytes}
+			c := &mbaAdaptiveController{
+				newReader: func() resourceexecutor.ResctrlReader {
+					return reader
+				},
+			}
+			now := time.Now()
+			for i, want := range tt.want {
+				got := c.calculate(cfg, tt.initPercent, now.Add(time.Duration(i)*time.Second))
+				assert.Equal(t, want, got, "round %d", i)
+			}
+
+			c.reset()
+			assert.Equal(t, int64(0), c.mbaPercent)
+			assert.True(t, c.lastTime.IsZero())
+		})
+	}
+}
+
+func TestResctrlReconcile_calculateAndApplyAdaptiveRDTMbPolicyForGroup(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldCacheIdsFunc := system.CacheIdsCacheFunc
+	system.CacheIdsCacheFunc = system.GetCacheIds
+	defer func() {
+		system.CacheIdsCacheFunc = oldCacheIdsFunc
+	}()
+
+	helper.WriteFileContents(filepath.Join(system.GetResctrlSubsystemDirPath(), system.ResctrlSchemataName),
+		"L3:0=ff;1=ff\nMB:0=100;1=100\n")
+	helper.WriteFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup), "L3:0=ff;1=ff\nMB:0=100;1=100\n")
+
+	opt := &framework.Options{
+		Config: framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		r.init(stop)
+	})
+	defer func() { stop <- struct{}{} }()
+
+	reader := &testMBStatReader{totalBytes: []uint64{0, 2000 * bytesPerMB}}
+	r.mbaController.newReader = func() resourceexecutor.ResctrlReader {
+		return reader
+	}
+	cfg := &slov1alpha1.ResctrlMBAAdaptiveStrategy{
+		Enable:                      pointer.Bool(true),
+		LSMemoryBandwidthTargetMBps: pointer.Int64(1000),
+	}
+	resourceQOS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				MBAPercent: pointer.Int64(80),
+			},
+		},
+	}
+	cpuBasicInfo := extension.CPUBasicInfo{VendorID: system.INTEL_VENDOR_ID}
+
+	err := r.calculateAndApplyAdaptiveRDTMbPolicyForGroup(context.TODO(), BEResctrlGroup, 2, cpuBasicInfo, resourceQOS, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=80;1=80;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	// the LS memory bandwidth exceeds the target
+	r.mbaController.lastTime = r.mbaController.lastTime.Add(-time.Second)
+	err = r.calculateAndApplyAdaptiveRDTMbPolicyForGroup(context.TODO(), BEResctrlGroup, 2, cpuBasicInfo, resourceQOS, cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=70;1=70;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	assert.False(t, isMBAAdaptiveEnabled(nil))
+	assert.False(t, isMBAAdaptiveEnabled(&slov1alpha1.ResourceQOSStrategy{
+		ResctrlMBAAdaptive: &slov1alpha1.ResctrlMBAAdaptiveStrategy{Enable: pointer.Bool(true)},
+	}))
+	assert.True(t, isMBAAdaptiveEnabled(&slov1alpha1.ResourceQOSStrategy{ResctrlMBAAdaptive: cfg}))
+}
@@ -17,9 +17,11 @@ limitations under the License.
 package resctrl
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -35,7 +37,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	resctrlutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -51,6 +55,8 @@ const (
 	BEResctrlGroup = "BE"
 	// UnknownResctrlGroup is the resctrl group which is unknown to reconcile
 	UnknownResctrlGroup = "Unknown"
+	// MBATierResctrlGroupPrefix is the name prefix of the resctrl groups of memory bandwidth tiers
+	MBATierResctrlGroupPrefix = "MBA-"
 
 	// Max memory bandwidth for AMD CPU, Gb/s, since the extreme limit is hard to reach, we set a discount by 0.8
 	// TODO The max memory bandwidth varies across SKU, so koordlet should be aware of the maximum automatically,
@@ -77,17 +83,30 @@ type resctrlReconcile struct {
 	metricCache       metriccache.MetricCache
 	cgroupReader      resourceexecutor.CgroupReader
 	eventRecorder     record.EventRecorder
+	mbaController     *mbaAdaptiveController
+	// interferenceController tightens the BE group by the LS CPI
+	interferenceController *interferenceController
+	cpiCollectInterval     time.Duration
+	groupGC                resourceexecutor.ResctrlGroupGC
 }
 
 func New(opt *framework.Options) framework.QOSStrategy {
-	return &resctrlReconcile{
+	r := &resctrlReconcile{
 		reconcileInterval: time.Duration(opt.Config.ReconcileIntervalSeconds) * time.Second,
 		statesInformer:    opt.StatesInformer,
 		metricCache:       opt.MetricCache,
 		executor:          resourceexecutor.NewResourceUpdateExecutor(),
 		cgroupReader:      opt.CgroupReader,
 		eventRecorder:     opt.EventRecorder,
+		mbaController:     newMBAAdaptiveController(),
 	}
+	if opt.MetricAdvisorConfig != nil {
+		r.cpiCollectInterval = opt.MetricAdvisorConfig.CPICollectorInterval
+	}
+	r.interferenceController = newInterferenceController(r.cpiCollectInterval)
+	r.groupGC = resourceexecutor.NewResctrlGroupGC(time.Duration(resourceexecutor.Conf.ResctrlGroupGCIntervalSeconds)*time.Second,
+		resctrlutil.ClosdIdPrefix, r.getResctrlGroupState)
+	return r
 }
 
 func (r *resctrlReconcile) Enabled() bool {
@@ -99,11 +118,14 @@ func (r *resctrlReconcile) Setup(context *framework.Context) {
 
 func (r *resctrlReconcile) Run(stopCh <-chan struct{}) {
 	r.init(stopCh)
-	go wait.Until(r.reconcile, r.reconcileInterval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, ResctrlReconcileName, r.reconcile), r.reconcileInterval, stopCh)
 }
 
 func (r *resctrlReconcile) init(stopCh <-chan struct{}) {
 	r.executor.Run(stopCh)
+	if r.groupGC != nil {
+		r.groupGC.Run(stopCh)
+	}
 }
 
 func getPodResctrlGroup(pod *corev1.Pod) string {
@@ -121,6 +143,42 @@ func getPodResctrlGroup(pod *corev1.Pod) string {
 	return UnknownResctrlGroup
 }
 
+func getMBATierResctrlGroup(tier string) string {
+	return MBATierResctrlGroupPrefix + tier
+}
+
+// getMBATierResctrlGroups returns the resctrl groups of the memory bandwidth tiers in the strategy.
+func getMBATierResctrlGroups(strategy *slov1alpha1.ResourceQOSStrategy) map[string]*slov1alpha1.ResctrlMBATier {
+	if strategy == nil || len(strategy.ResctrlMBATiers) <= 0 {
+		return nil
+	}
+	groups := make(map[string]*slov1alpha1.ResctrlMBATier, len(strategy.ResctrlMBATiers))
+	for i := range strategy.ResctrlMBATiers {
+		tier := &strategy.ResctrlMBATiers[i]
+		if len(tier.Name) <= 0 || strings.Contains(tier.Name, "/") {
+			klog.Warningf("skip the invalid memory bandwidth tier %q", tier.Name)
+			continue
+		}
+		groups[getMBATierResctrlGroup(tier.Name)] = tier
+	}
+	return groups
+}
+
+// getPodMBATierResctrlGroup returns the resctrl group of the memory bandwidth tier specified by the pod annotation,
+// or empty if the pod does not specify a tier or the tier is not defined in the NodeSLO.
+func getPodMBATierResctrlGroup(pod *corev1.Pod, tierGroups map[string]*slov1alpha1.ResctrlMBATier) string {
+	tier := extension.GetResctrlMBATier(pod.Annotations)
+	if len(tier) <= 0 {
+		return ""
+	}
+	group := getMBATierResctrlGroup(tier)
+	if _, ok := tierGroups[group]; !ok {
+		klog.V(5).Infof("memory bandwidth tier %s of pod %s is not defined in NodeSLO", tier, util.GetPodKey(pod))
+		return ""
+	}
+	return group
+}
+
 func getResourceQOSForResctrlGroup(strategy *slov1alpha1.ResourceQOSStrategy, group string) *slov1alpha1.ResourceQOS {
 	if strategy == nil {
 		return nil
@@ -154,6 +212,39 @@ func initCatResctrl() error {
 	return nil
 }
 
+// initMBATierResctrl creates the resctrl groups of the memory bandwidth tiers, and removes the groups of the tiers
+// which are no longer defined. The tasks of a removed group are moved back to the default group by the kernel.
+func initMBATierResctrl(tierGroups map[string]*slov1alpha1.ResctrlMBATier) {
+	for group := range tierGroups {
+		if updated, err := initCatGroupIfNotExist(group); err != nil {
+			klog.Errorf("init resctrl group dir %v failed, error %v", group, err)
+		} else if updated {
+			klog.V(4).Infof("create resctrl dir for memory bandwidth tier group %v successfully", group)
+		}
+	}
+
+	entries, err := os.ReadDir(system.GetResctrlSubsystemDirPath())
+	if err != nil {
+		klog.Warningf("failed to read resctrl root dir, err: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		group := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(group, MBATierResctrlGroupPrefix) {
+			continue
+		}
+		if _, ok := tierGroups[group]; ok {
+			continue
+		}
+		// the control files inside a resctrl group dir cannot be removed, so the dir is removed directly
+		if err = os.Remove(system.GetResctrlGroupRootDirPath(group)); err != nil {
+			klog.Warningf("failed to remove the unused memory bandwidth tier group %s, err: %v", group, err)
+		} else {
+			klog.V(4).Infof("remove the unused memory bandwidth tier group %s successfully", group)
+		}
+	}
+}
+
 func initCatGroupIfNotExist(group string) (bool, error) {
 	path := system.GetResctrlGroupRootDirPath(group)
 	_, err := os.Stat(path)
@@ -290,8 +381,8 @@ func (r *resctrlReconcile) getPodCgroupNewTaskIds(podMeta *statesinformer.PodMet
 	return taskIds
 }
 
-func (r *resctrlReconcile) calculateAndApplyRDTL3PolicyForGroup(group string, cbm uint, l3Num int,
-	resourceQoS *slov1alpha1.ResourceQOS) error {
+func (r *resctrlReconcile) calculateAndApplyRDTL3PolicyForGroup(ctx context.Context, group string, cbm uint, l3Num int,
+	socketCacheIds map[int32][]int, resourceQoS *slov1alpha1.ResourceQOS) error {
 	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil || resourceQoS.ResctrlQOS.CATRangeStartPercent == nil ||
 		resourceQoS.ResctrlQOS.CATRangeEndPercent == nil {
 		klog.Warningf("skipped, since resourceQoS or startPercent or endPercent is nil for group %v, "+
@@ -306,57 +397,146 @@ func (r *resctrlReconcile) calculateAndApplyRDTL3PolicyForGroup(group string, cb
 		klog.Warningf("failed to calculate l3 cat schemata for group %v, err: %v", group, err)
 		return err
 	}
+	// the l3 cache domains on the overridden sockets use their own masks
+	domainMasks, err := calculateSocketL3Masks(group, cbm, resourceQoS.ResctrlQOS, socketCacheIds)
+	if err != nil {
+		klog.Warningf("failed to calculate l3 cat schemata of sockets for group %v, err: %v", group, err)
+		return err
+	}
 
 	// calculate updating resource
-	resource := resourceexecutor.NewResctrlL3SchemataResource(group, l3MaskValue, l3Num)
+	var resource resourceexecutor.ResourceUpdater
+	if system.IsResctrlL3CDPEnabled() {
+		// the code uses its own range if specified, otherwise it shares the cache ways of the data
+		codeStartPercent, codeEndPercent := getCATCodeRange(resourceQoS.ResctrlQOS.ResctrlQOS)
+		var codeMaskValue string
+		codeMaskValue, err = system.CalculateCatL3MaskValue(cbm, *codeStartPercent, *codeEndPercent)
+		if err != nil {
+			klog.Warningf("failed to calculate l3 cat code schemata for group %v, err: %v", group, err)
+			return err
+		}
+		var domainCodeMasks map[int]string
+		domainCodeMasks, err = calculateSocketL3CodeMasks(group, cbm, resourceQoS.ResctrlQOS, socketCacheIds)
+		if err != nil {
+			klog.Warningf("failed to calculate l3 cat code schemata of sockets for group %v, err: %v", group, err)
+			return err
+		}
+		resource, err = resourceexecutor.NewResctrlL3CDPSchemataResource(group, codeMaskValue, l3MaskValue, domainCodeMasks,
+			domainMasks, l3Num, cbm)
+		if err != nil {
+			klog.Warningf("failed to generate l3 cdp schemata for group %v, err: %v", group, err)
+			return err
+		}
+	} else {
+		resource = resourceexecutor.NewResctrlL3SchemataResourceWithDomains(group, l3MaskValue, domainMasks, l3Num)
+	}
 
 	// write policy into resctrl files if need update
-	isUpdated, err := r.executor.Update(true, resource)
+	isUpdated, err := r.executor.Update(ctx, true, resource)
 	if err != nil {
 		klog.Warningf("failed to write l3 cat policy on schemata for group %s, err: %s", group, err)
 		return err
 	} else if isUpdated {
-		klog.V(5).Infof("apply l3 cat policy for group %s finished, schemata %v, l3 number %v, isUpdated %v",
-			group, l3MaskValue, l3Num, isUpdated)
+		klog.V(5).Infof("apply l3 cat policy for group %s finished, schemata %v, domain schemata %v, l3 number %v, isUpdated %v",
+			group, l3MaskValue, domainMasks, l3Num, isUpdated)
+	} else {
+		klog.V(6).Infof("apply l3 cat policy for group %s finished, schemata %v, domain schemata %v, l3 number %v, isUpdated %v",
+			group, l3MaskValue, domainMasks, l3Num, isUpdated)
+	}
+
+	return nil
+}
+
+// calculateAndApplyRDTL2PolicyForGroup applies the l2 cat policy onto the group, where the l2 cache ways are partitioned
+// by the same CAT range of the group as the l3 ones.
+func (r *resctrlReconcile) calculateAndApplyRDTL2PolicyForGroup(ctx context.Context, group string, cbm uint,
+	resourceQoS *slov1alpha1.ResourceQOS) error {
+	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil || resourceQoS.ResctrlQOS.CATRangeStartPercent == nil ||
+		resourceQoS.ResctrlQOS.CATRangeEndPercent == nil {
+		klog.V(5).Infof("skipped, since resourceQoS or startPercent or endPercent is nil for group %v, "+
+			"resourceQoS %v", group, resourceQoS)
+		return nil
+	}
+
+	startPercent, endPercent := *resourceQoS.ResctrlQOS.CATRangeStartPercent, *resourceQoS.ResctrlQOS.CATRangeEndPercent
+	// calculate policy
+	l2MaskValue, err := system.CalculateCatL3MaskValue(cbm, startPercent, endPercent)
+	if err != nil {
+		klog.Warningf("failed to calculate l2 cat schemata for group %v, err: %v", group, err)
+		return err
+	}
+
+	// calculate updating resource
+	resource := resourceexecutor.NewResctrlL2SchemataResource(group, l2MaskValue)
+
+	// write policy into resctrl files if need update
+	isUpdated, err := r.executor.Update(ctx, true, resource)
+	if err != nil {
+		klog.Warningf("failed to write l2 cat policy on schemata for group %s, err: %s", group, err)
+		return err
+	} else if isUpdated {
+		klog.V(5).Infof("apply l2 cat policy for group %s finished, schemata %v, isUpdated %v",
+			group, l2MaskValue, isUpdated)
 	} else {
-		klog.V(6).Infof("apply l3 cat policy for group %s finished, schemata %v, l3 number %v, isUpdated %v",
-			group, l3MaskValue, l3Num, isUpdated)
+		klog.V(6).Infof("apply l2 cat policy for group %s finished, schemata %v, isUpdated %v",
+			group, l2MaskValue, isUpdated)
 	}
 
 	return nil
 }
 
-func (r *resctrlReconcile) calculateAndApplyRDTMbPolicyForGroup(group string, l3Num int, cpuBasicInfo extension.CPUBasicInfo, resourceQoS *slov1alpha1.ResourceQOS) error {
+func (r *resctrlReconcile) calculateAndApplyRDTMbPolicyForGroup(ctx context.Context, group string, l3Num int, cpuBasicInfo extension.CPUBasicInfo,
+	socketCacheIds map[int32][]int, resourceQoS *slov1alpha1.ResourceQOS) error {
 	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil {
 		klog.Warningf("skipped, since resourceQoS or ResctrlQOS is nil for group %v, "+
 			"resourceQoS %v", resourceQoS, group)
 		return nil
 	}
 
-	memBwPercent := calculateMbaPercentForGroup(group, resourceQoS.ResctrlQOS.MBAPercent, cpuBasicInfo)
+	domainPercents := calculateSocketMbaValues(group, cpuBasicInfo, resourceQoS.ResctrlQOS, socketCacheIds)
+	return r.applyRDTMbPolicyForGroup(ctx, group, l3Num, cpuBasicInfo, resourceQoS.ResctrlQOS.MBAPercent, domainPercents)
+}
+
+// calculateAndApplyAdaptiveRDTMbPolicyForGroup applies the MBA percent tuned by the LS memory bandwidth, where the
+// MBA percent of the group config is used as the initial value.
+func (r *resctrlReconcile) calculateAndApplyAdaptiveRDTMbPolicyForGroup(ctx context.Context, group string, l3Num int, cpuBasicInfo extension.CPUBasicInfo,
+	resourceQoS *slov1alpha1.ResourceQOS, adaptiveCfg *slov1alpha1.ResctrlMBAAdaptiveStrategy) error {
+	var initPercent *int64
+	if resourceQoS != nil && resourceQoS.ResctrlQOS != nil {
+		initPercent = resourceQoS.ResctrlQOS.MBAPercent
+	}
+	mbaPercent := r.mbaController.calculate(adaptiveCfg, initPercent, time.Now())
+	return r.applyRDTMbPolicyForGroup(ctx, group, l3Num, cpuBasicInfo, &mbaPercent, nil)
+}
+
+// applyRDTMbPolicyForGroup applies the MBA percent onto the group, where the cache ids in the domainPercents use
+// their own values instead.
+func (r *resctrlReconcile) applyRDTMbPolicyForGroup(ctx context.Context, group string, l3Num int, cpuBasicInfo extension.CPUBasicInfo,
+	mbaPercent *int64, domainPercents map[int]string) error {
+	memBwPercent := calculateMbaPercentForGroup(group, mbaPercent, cpuBasicInfo)
 	if memBwPercent == "" {
 		return nil
 	}
 	// calculate updating resource
-	resource := resourceexecutor.NewResctrlMbSchemataResource(group, memBwPercent, l3Num)
+	resource := resourceexecutor.NewResctrlMbSchemataResourceWithDomains(group, memBwPercent, domainPercents, l3Num)
 
 	// write policy into resctrl files if need update
-	isUpdated, err := r.executor.Update(true, resource)
+	isUpdated, err := r.executor.Update(ctx, true, resource)
 	if err != nil {
 		klog.Warningf("failed to write mba policy on schemata for group %s, err: %s", group, err)
 		return err
 	} else if isUpdated {
-		klog.V(5).Infof("apply mba policy for group %s finished, schemata %v, l3 number %v, isUpdated %v",
-			group, memBwPercent, l3Num, isUpdated)
+		klog.V(5).Infof("apply mba policy for group %s finished, schemata %v, domain schemata %v, l3 number %v, isUpdated %v",
+			group, memBwPercent, domainPercents, l3Num, isUpdated)
 	} else {
-		klog.V(6).Infof("apply mba policy for group %s finished, schemata %v, l3 number %v, isUpdated %v",
-			group, memBwPercent, l3Num, isUpdated)
+		klog.V(6).Infof("apply mba policy for group %s finished, schemata %v, domain schemata %v, l3 number %v, isUpdated %v",
+			group, memBwPercent, domainPercents, l3Num, isUpdated)
 	}
 
 	return nil
 }
 
-func (r *resctrlReconcile) calculateAndApplyRDTL3GroupTasks(group string, taskIds []int32) error {
+func (r *resctrlReconcile) calculateAndApplyRDTL3GroupTasks(ctx context.Context, group string, taskIds []int32) error {
 	if len(taskIds) <= 0 {
 		klog.V(6).Infof("apply l3 cat tasks for group %s skipped, no new task id", group)
 		return nil
@@ -371,7 +551,7 @@ func (r *resctrlReconcile) calculateAndApplyRDTL3GroupTasks(group string, taskId
 	// write policy into resctrl files
 	// NOTE: the operation should not be cacheable, since old tid has chance to be reused by a new task and here the
 	// tasks ids are the realtime diff between cgroup and resctrl
-	updated, err := r.executor.Update(false, resource)
+	updated, err := r.executor.Update(ctx, false, resource)
 	if err != nil {
 		klog.Warningf("failed to write l3 cat policy on tasks for group %s, updated %v, err: %s", group, updated, err)
 		return err
@@ -384,7 +564,7 @@ func (r *resctrlReconcile) calculateAndApplyRDTL3GroupTasks(group string, taskId
 	return nil
 }
 
-func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+func (r *resctrlReconcile) reconcileRDTResctrlPolicy(ctx context.Context, qosStrategy *slov1alpha1.ResourceQOSStrategy) {
 	// 1. retrieve rdt configs from nodeSLOSpec
 	// 2.1 get cbm and l3 numbers, which are general for all resctrl groups
 	// 2.2 calculate applying resctrl policies, like cat policy and so on, with each rdt config
@@ -423,22 +603,80 @@ func (r *resctrlReconcile) reconcileRDTResctrlPolicy(qosStrategy *slov1alpha1.Re
 		klog.Warningf("failed to get the number of l3 caches, invalid value %v", l3Num)
 		return
 	}
+	// the l3 cache ids of each socket, which are the domains of the per-socket overrides
+	socketCacheIds := getSocketCacheIds(nodeCPUInfo)
+
+	// the l2 cat is optional, e.g. on the Atom-based platforms
+	l2Cbm, isL2Cat := getCatL2Cbm()
+
+	// the BE MBA is tuned by the LS memory bandwidth if the adaptive MBA is enabled
+	isMBAAdaptive := isMBAAdaptiveEnabled(qosStrategy)
+	if !isMBAAdaptive {
+		r.mbaController.reset()
+	}
+	// the BE group is tightened by the LS CPI degradation if the interference control is enabled
+	isInterferenceControl := isInterferenceControlEnabled(qosStrategy)
+	if isInterferenceControl {
+		r.interferenceController.update(qosStrategy.ResctrlInterferenceControl, r.getLSCPI(), time.Now())
+	} else {
+		r.interferenceController.reset()
+	}
 
 	// calculate and apply l3 cat policy for each group
 	for _, group := range resctrlGroupList {
 		resQoSStrategy := getResourceQOSForResctrlGroup(qosStrategy, group)
-		err = r.calculateAndApplyRDTL3PolicyForGroup(group, cbm, l3Num, resQoSStrategy)
+		if isInterferenceControl && group == BEResctrlGroup {
+			resQoSStrategy = r.interferenceController.tighten(qosStrategy.ResctrlInterferenceControl, resQoSStrategy, isMBAAdaptive)
+		}
+		err = r.calculateAndApplyRDTL3PolicyForGroup(ctx, group, cbm, l3Num, socketCacheIds, resQoSStrategy)
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat policy for group %v, err: %v", group, err)
 		}
-		err = r.calculateAndApplyRDTMbPolicyForGroup(group, l3Num, nodeCPUInfo.BasicInfo, resQoSStrategy)
+		if isL2Cat {
+			err = r.calculateAndApplyRDTL2PolicyForGroup(ctx, group, l2Cbm, resQoSStrategy)
+			if err != nil {
+				klog.Warningf("failed to apply l2 cat policy for group %v, err: %v", group, err)
+			}
+		}
+		if isMBAAdaptive && group == BEResctrlGroup {
+			err = r.calculateAndApplyAdaptiveRDTMbPolicyForGroup(ctx, group, l3Num, nodeCPUInfo.BasicInfo, resQoSStrategy,
+				qosStrategy.ResctrlMBAAdaptive)
+		} else {
+			err = r.calculateAndApplyRDTMbPolicyForGroup(ctx, group, l3Num, nodeCPUInfo.BasicInfo, socketCacheIds, resQoSStrategy)
+		}
 		if err != nil {
 			klog.Warningf("failed to apply cat MB policy for group %v, err: %v", group, err)
 		}
 	}
+
+	// apply mba policy for each memory bandwidth tier
+	for group, tier := range getMBATierResctrlGroups(qosStrategy) {
+		err = r.applyRDTMbPolicyForGroup(ctx, group, l3Num, nodeCPUInfo.BasicInfo, tier.MBAPercent, nil)
+		if err != nil {
+			klog.Warningf("failed to apply cat MB policy for memory bandwidth tier group %v, err: %v", group, err)
+		}
+	}
 }
 
-func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.ResourceQOSStrategy) {
+// getCatL2Cbm returns the cbm of the l2 cat. It returns false if the l2 cat is not available.
+func getCatL2Cbm() (uint, bool) {
+	if !system.IsResctrlL2CatAvailable() {
+		return 0, false
+	}
+	cbmStr, err := system.ReadCatL2CbmString()
+	if err != nil {
+		klog.Warningf("failed to get cat l2 cbm, err: %v", err)
+		return 0, false
+	}
+	cbmValue, err := strconv.ParseUint(cbmStr, 16, 32)
+	if err != nil {
+		klog.Warningf("failed to parse cat l2 cbm %s, err: %v", cbmStr, err)
+		return 0, false
+	}
+	return uint(cbmValue), true
+}
+
+func (r *resctrlReconcile) reconcileResctrlGroups(ctx context.Context, qosStrategy *slov1alpha1.ResourceQOSStrategy) {
 	// 1. retrieve task ids for each slo by reading cgroup task file of every pod container
 	// 2. add the related task ids in resctrl groups
 
@@ -447,8 +685,15 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	// here we only append the task ids which only appear in cgroup but not in resctrl to reduce resctrl writes
 	var err error
 
+	tierGroups := getMBATierResctrlGroups(qosStrategy)
+	groups := make([]string, 0, len(resctrlGroupList)+len(tierGroups))
+	groups = append(groups, resctrlGroupList...)
+	for group := range tierGroups {
+		groups = append(groups, group)
+	}
+
 	curTaskMaps := map[string]map[int32]struct{}{}
-	for _, group := range resctrlGroupList {
+	for _, group := range groups {
 		curTaskMaps[group], err = system.ReadResctrlTasksMap(group)
 		if err != nil {
 			klog.Warningf("failed to read Cat L3 tasks for resctrl group %s, err: %s", group, err)
@@ -459,25 +704,8 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	podsMeta := r.statesInformer.GetAllPods()
 	for _, podMeta := range podsMeta {
 		pod := podMeta.Pod
-		// only QoS class level pod are considered
-		if _, ok := pod.Annotations[extension.AnnotationResctrl]; ok {
-			continue
-		}
-
-		// only Running and Pending pods are considered
-		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
-			continue
-		}
-
-		// only extension-QoS-specified pod are considered
-		podQoSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy)
-		if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
-			klog.V(5).Infof("pod %v with qos %v disabled resctrl", util.GetPodKey(pod), extension.GetPodQoSClassRaw(pod))
-			continue
-		}
-
-		// TODO https://github.com/koordinator-sh/koordinator/pull/94#discussion_r858779795
-		if group := getPodResctrlGroup(pod); group != UnknownResctrlGroup {
+		group := getPodResctrlGroupForStrategy(pod, qosStrategy, tierGroups)
+		if group != UnknownResctrlGroup {
 			ids := r.getPodCgroupNewTaskIds(podMeta, curTaskMaps[group])
 			taskIds[group] = append(taskIds[group], ids...)
 			klog.V(6).Infof("pod %v apply to group %s with %v tasks", util.GetPodKey(pod), group, len(ids))
@@ -485,15 +713,77 @@ func (r *resctrlReconcile) reconcileResctrlGroups(qosStrategy *slov1alpha1.Resou
 	}
 
 	// write Cat L3 tasks for each resctrl group
-	for _, group := range resctrlGroupList {
-		err = r.calculateAndApplyRDTL3GroupTasks(group, taskIds[group])
+	for _, group := range groups {
+		err = r.calculateAndApplyRDTL3GroupTasks(ctx, group, taskIds[group])
 		if err != nil {
 			klog.Warningf("failed to apply l3 cat tasks for group %s, err %s", group, err)
 		}
 	}
 }
 
-func (r *resctrlReconcile) reconcile() {
+// getPodResctrlGroupForStrategy returns the QoS class level resctrl group of the pod under the strategy.
+// It returns UnknownResctrlGroup if the pod is not considered.
+func getPodResctrlGroupForStrategy(pod *corev1.Pod, qosStrategy *slov1alpha1.ResourceQOSStrategy,
+	tierGroups map[string]*slov1alpha1.ResctrlMBATier) string {
+	// only QoS class level pod are considered
+	if _, ok := pod.Annotations[extension.AnnotationResctrl]; ok {
+		return UnknownResctrlGroup
+	}
+
+	// only Running and Pending pods are considered
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+		return UnknownResctrlGroup
+	}
+
+	// only extension-QoS-specified pod are considered
+	podQoSCfg := helpers.GetPodResourceQoSByQoSClass(pod, qosStrategy)
+	if podQoSCfg.ResctrlQOS.Enable == nil || !(*podQoSCfg.ResctrlQOS.Enable) {
+		klog.V(5).Infof("pod %v with qos %v disabled resctrl", util.GetPodKey(pod), extension.GetPodQoSClassRaw(pod))
+		return UnknownResctrlGroup
+	}
+
+	// the memory bandwidth tier takes precedence over the QoS class
+	group := getPodMBATierResctrlGroup(pod, tierGroups)
+	if len(group) <= 0 {
+		// TODO https://github.com/koordinator-sh/koordinator/pull/94#discussion_r858779795
+		group = getPodResctrlGroup(pod)
+	}
+	return group
+}
+
+// getResctrlGroupState returns the expected state of the resctrl groups for the resctrl group gc.
+// The expected tasks of a QoS class level group are all the tasks of the pods in the group. The gc does nothing
+// if the resctrl is not supported since the resctrl root does not exist.
+func (r *resctrlReconcile) getResctrlGroupState() (*resourceexecutor.ResctrlGroupState, error) {
+	nodeSLO := r.statesInformer.GetNodeSLO()
+	if nodeSLO == nil || nodeSLO.Spec.ResourceQOSStrategy == nil {
+		return nil, nil
+	}
+	qosStrategy := nodeSLO.Spec.ResourceQOSStrategy
+
+	tierGroups := getMBATierResctrlGroups(qosStrategy)
+	state := &resourceexecutor.ResctrlGroupState{
+		CtrlGroupTasks: make(map[string][]int32, len(resctrlGroupList)+len(tierGroups)),
+		PodUIDs:        map[string]struct{}{},
+	}
+	for _, group := range resctrlGroupList {
+		state.CtrlGroupTasks[group] = nil
+	}
+	for group := range tierGroups {
+		state.CtrlGroupTasks[group] = nil
+	}
+	for _, podMeta := range r.statesInformer.GetAllPods() {
+		pod := podMeta.Pod
+		state.PodUIDs[string(pod.UID)] = struct{}{}
+		group := getPodResctrlGroupForStrategy(pod, qosStrategy, tierGroups)
+		if group != UnknownResctrlGroup {
+			state.CtrlGroupTasks[group] = append(state.CtrlGroupTasks[group], r.getPodCgroupNewTaskIds(podMeta, nil)...)
+		}
+	}
+	return state, nil
+}
+
+func (r *resctrlReconcile) reconcile(ctx context.Context) {
 	// Step 0. create and init them if resctrl groups do not exist
 	// Step 1. reconcile rdt policies against `schemata` file
 	// Step 2. reconcile resctrl groups against `tasks` file
@@ -523,6 +813,7 @@ func (r *resctrlReconcile) reconcile() {
 		klog.V(4).Infof("resctrlReconcile failed, cannot initialize cat resctrl group, err: %s", err)
 		return
 	}
-	r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
-	r.reconcileResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy)
+	initMBATierResctrl(getMBATierResctrlGroups(nodeSLO.Spec.ResourceQOSStrategy))
+	r.reconcileRDTResctrlPolicy(ctx, nodeSLO.Spec.ResourceQOSStrategy)
+	r.reconcileResctrlGroups(ctx, nodeSLO.Spec.ResourceQOSStrategy)
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package resctrl
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -52,7 +53,9 @@ func newTestResctrlReconcile(opt *framework.Options) *resctrlReconcile {
 			Config:        resourceexecutor.NewDefaultConfig(),
 			ResourceCache: cache.NewCacheDefault(),
 		},
-		cgroupReader: resourceexecutor.NewCgroupReader(),
+		cgroupReader:           resourceexecutor.NewCgroupReader(),
+		mbaController:          newMBAAdaptiveController(),
+		interferenceController: newInterferenceController(time.Minute),
 	}
 }
 
@@ -755,7 +758,7 @@ func TestResctrlReconcile_calculateAndApplyRDTL3PolicyForGroup(t *testing.T) {
 			}
 
 			// execute function
-			err := r.calculateAndApplyRDTL3PolicyForGroup(tt.args.group, tt.args.cbm, tt.args.l3Num,
+			err := r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), tt.args.group, tt.args.cbm, tt.args.l3Num, nil,
 				getResourceQOSForResctrlGroup(tt.args.qosStrategy, tt.args.group))
 			assert.Equal(t, tt.wantErr, err != nil, err)
 
@@ -766,7 +769,7 @@ func TestResctrlReconcile_calculateAndApplyRDTL3PolicyForGroup(t *testing.T) {
 			if tt.field.noUpdate {
 				// prepare fake record in cache
 				fakeResource := resourceexecutor.NewResctrlL3SchemataResource(tt.args.group, tt.field.cachedMask, tt.args.l3Num)
-				isUpdate, err := r.executor.Update(true, fakeResource)
+				isUpdate, err := r.executor.Update(context.TODO(), true, fakeResource)
 				assert.False(t, isUpdate)
 				assert.NoError(t, err)
 			}
@@ -774,6 +777,121 @@ func TestResctrlReconcile_calculateAndApplyRDTL3PolicyForGroup(t *testing.T) {
 	}
 }
 
+func TestResctrlReconcile_calculateAndApplyRDTL3PolicyForGroupWithCDP(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldCacheIdsFunc := system.CacheIdsCacheFunc
+	system.CacheIdsCacheFunc = system.GetCacheIds
+	defer func() {
+		system.CacheIdsCacheFunc = oldCacheIdsFunc
+	}()
+
+	resctrlDir := system.GetResctrlSubsystemDirPath()
+	for _, dir := range []string{system.L3CodeCatDir, system.L3DataCatDir} {
+		helper.WriteFileContents(filepath.Join(resctrlDir, system.RdtInfoDir, dir, system.ResctrlCbmMaskName), "ff\n")
+	}
+	helper.WriteFileContents(filepath.Join(resctrlDir, system.ResctrlSchemataName),
+		"L3CODE:0=ff;1=ff\nL3DATA:0=ff;1=ff\nMB:0=100;1=100\n")
+	helper.WriteFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup),
+		"L3CODE:0=ff;1=ff\nL3DATA:0=ff;1=ff\nMB:0=100;1=100\n")
+
+	opt := &framework.Options{
+		Config: framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		r.init(stop)
+	})
+	defer func() { stop <- struct{}{} }()
+
+	resourceQOS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64(0),
+				CATRangeEndPercent:   pointer.Int64(50),
+			},
+		},
+	}
+	err := r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), BEResctrlGroup, 0xff, 2, nil, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f;1=f;\nL3DATA:0=f;1=f;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	// the l3 number mismatches the domains
+	err = r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), BEResctrlGroup, 0xff, 1, nil, resourceQOS)
+	assert.Error(t, err)
+
+	// the code uses its own range
+	resourceQOS.ResctrlQOS.CATCodeRangeStartPercent = pointer.Int64(50)
+	resourceQOS.ResctrlQOS.CATCodeRangeEndPercent = pointer.Int64(100)
+	err = r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), BEResctrlGroup, 0xff, 2, nil, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f0;1=f0;\nL3DATA:0=f;1=f;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	// the code range of the socket override
+	resourceQOS.ResctrlQOS.Sockets = []slov1alpha1.ResctrlSocketQOS{
+		{
+			SocketIDs: []int32{1},
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATCodeRangeStartPercent: pointer.Int64(0),
+				CATCodeRangeEndPercent:   pointer.Int64(25),
+			},
+		},
+	}
+	err = r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), BEResctrlGroup, 0xff, 2, map[int32][]int{0: {0}, 1: {1}}, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f0;1=3;\nL3DATA:0=f;1=f;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+}
+
+func TestResctrlReconcile_calculateAndApplyRDTL2PolicyForGroup(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldCacheIdsFunc, oldL2CacheIdsFunc := system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc
+	system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc = system.GetCacheIds, system.GetL2CacheIds
+	defer func() {
+		system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc = oldCacheIdsFunc, oldL2CacheIdsFunc
+	}()
+
+	helper.WriteFileContents(system.GetResctrlL2CbmFilePath(), "ff\n")
+	helper.WriteFileContents(filepath.Join(system.GetResctrlSubsystemDirPath(), system.ResctrlSchemataName),
+		"L3:0=7ff\nMB:0=100\nL2:0=ff;1=ff\n")
+	helper.WriteFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup), "L3:0=7ff\nMB:0=100\nL2:0=ff;1=ff\n")
+	cbm, ok := getCatL2Cbm()
+	assert.True(t, ok)
+	assert.Equal(t, uint(0xff), cbm)
+
+	opt := &framework.Options{
+		Config: framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		r.init(stop)
+	})
+	defer func() { stop <- struct{}{} }()
+
+	// no cat config
+	err := r.calculateAndApplyRDTL2PolicyForGroup(context.TODO(), BEResctrlGroup, cbm, &slov1alpha1.ResourceQOS{})
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=7ff\nMB:0=100\nL2:0=ff;1=ff\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	resourceQOS := &slov1alpha1.ResourceQOS{
+		ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+			ResctrlQOS: slov1alpha1.ResctrlQOS{
+				CATRangeStartPercent: pointer.Int64(0),
+				CATRangeEndPercent:   pointer.Int64(50),
+			},
+		},
+	}
+	err = r.calculateAndApplyRDTL2PolicyForGroup(context.TODO(), BEResctrlGroup, cbm, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "L2:0=f;1=f;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath(BEResctrlGroup)))
+
+	// invalid cbm
+	err = r.calculateAndApplyRDTL2PolicyForGroup(context.TODO(), BEResctrlGroup, 0xf0, resourceQOS)
+	assert.Error(t, err)
+}
+
 func TestResctrlReconcile_calculateAndApplyRDTMbPolicyForGroup(t *testing.T) {
 	type args struct {
 		group        string
@@ -998,7 +1116,7 @@ func TestResctrlReconcile_calculateAndApplyRDTMbPolicyForGroup(t *testing.T) {
 			}
 
 			// execute function
-			err := r.calculateAndApplyRDTMbPolicyForGroup(tt.args.group, tt.args.l3Num, tt.args.basicCPUInfo,
+			err := r.calculateAndApplyRDTMbPolicyForGroup(context.TODO(), tt.args.group, tt.args.l3Num, tt.args.basicCPUInfo, nil,
 				getResourceQOSForResctrlGroup(tt.args.qosStrategy, tt.args.group))
 			assert.Equal(t, tt.wantErr, err != nil)
 
@@ -1009,7 +1127,7 @@ func TestResctrlReconcile_calculateAndApplyRDTMbPolicyForGroup(t *testing.T) {
 			if tt.field.noUpdate {
 				// prepare fake record in cache
 				fakeResource := resourceexecutor.NewResctrlMbSchemataResource(tt.args.group, tt.field.cachedPercent, tt.args.l3Num)
-				isUpdate, err := r.executor.Update(true, fakeResource)
+				isUpdate, err := r.executor.Update(context.TODO(), true, fakeResource)
 				assert.False(t, isUpdate)
 				assert.NoError(t, err)
 			}
@@ -1092,7 +1210,7 @@ func TestResctrlReconcile_calculateAndApplyRDTL3GroupTasks(t *testing.T) {
 			r.init(stop)
 			defer func() { stop <- struct{}{} }()
 
-			err := r.calculateAndApplyRDTL3GroupTasks(tt.args.group, tt.args.taskIds)
+			err := r.calculateAndApplyRDTL3GroupTasks(context.TODO(), tt.args.group, tt.args.taskIds)
 			assert.Equal(t, tt.wantErr, err != nil, err)
 
 			out, err := os.ReadFile(filepath.Join(validSysFSRootDir, system.ResctrlDir, tt.args.group,
@@ -1170,7 +1288,7 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 		defer func() { stop <- struct{}{} }()
 
 		// reconcile and check if the result is correct
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 
 		beSchemataPath := filepath.Join(resctrlDirPath, BEResctrlGroup, system.ResctrlSchemataName)
 		expectBESchemataStr := "L3:0=3f;1=3f;\n"
@@ -1185,11 +1303,11 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 		// log error for invalid be resctrl path
 		err = os.RemoveAll(filepath.Join(resctrlDirPath, BEResctrlGroup))
 		assert.NoError(t, err)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 
 		// log error for invalid root resctrl path
 		system.Conf.SysFSRootDir = "invalidPath"
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 		system.Conf.SysFSRootDir = validSysFSRootDir
 
 		// log error for invalid l3 number
@@ -1197,27 +1315,27 @@ func TestResctrlReconcile_reconcileRDTResctrlPolicy(t *testing.T) {
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "7ff"},
 			TotalInfo: koordletutil.CPUTotalInfo{},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 
 		// log error for invalid l3 cbm
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "invalid"},
 			TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
 			BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: ""},
 			TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
 		}, true).Times(1)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 
 		// log error for invalid nodeCPUInfo
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nil, false)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 
 		// log error for get nodeCPUInfo failed
 		metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(nil, false)
-		r.reconcileRDTResctrlPolicy(nodeSLO.Spec.ResourceQOSStrategy)
+		r.reconcileRDTResctrlPolicy(context.TODO(), nodeSLO.Spec.ResourceQOSStrategy)
 	})
 }
 
@@ -1317,7 +1435,7 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		testingPrepareContainerCgroupCPUTasks(t, helper, testingContainer1ParentDir, testingContainer1TasksStr)
 
 		// run reconcileResctrlGroups for BE & LSE tasks not exist
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(context.TODO(), testQOSStrategy)
 
 		// check if the reconciliation is a success
 		out, err := os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
@@ -1333,7 +1451,7 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 		assert.NoError(t, err)
 
 		// run reconcileResctrlGroups
-		r.reconcileResctrlGroups(testQOSStrategy)
+		r.reconcileResctrlGroups(context.TODO(), testQOSStrategy)
 
 		// check if the reconciliation is a success
 		out, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
@@ -1342,6 +1460,101 @@ func TestResctrlReconcile_reconcileResctrlGroups(t *testing.T) {
 	})
 }
 
+func TestResctrlReconcile_getResctrlGroupState(t *testing.T) {
+	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	testingContainerTasksStr := "122450\n122454"
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod0",
+				UID:  "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "container0",
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/p0",
+	}
+	testingPodMetaResctrl := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod1",
+				UID:  "p1",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSLS),
+				},
+				Annotations: map[string]string{
+					extension.AnnotationResctrl: "{}",
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+			},
+		},
+		CgroupDir: "kubepods.slice/p1",
+	}
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	opt := &framework.Options{
+		StatesInformer: statesInformer,
+		Config:         framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerParentDir, testingContainerTasksStr)
+
+	// nodeSLO is not ready
+	statesInformer.EXPECT().GetNodeSLO().Return(nil)
+	got, err := r.getResctrlGroupState()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	statesInformer.EXPECT().GetNodeSLO().Return(&slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: testQOSStrategy,
+		},
+	})
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{
+		testingPodMeta,
+		testingPodMetaResctrl,
+	})
+	got, err = r.getResctrlGroupState()
+	assert.NoError(t, err)
+	assert.Equal(t, &resourceexecutor.ResctrlGroupState{
+		CtrlGroupTasks: map[string][]int32{
+			LSRResctrlGroup: nil,
+			LSResctrlGroup:  nil,
+			BEResctrlGroup:  {122450, 122454},
+		},
+		PodUIDs: map[string]struct{}{
+			"p0": {},
+			"p1": {},
+		},
+	}, got)
+}
+
 func TestResctrlReconcile_reconcile(t *testing.T) {
 	// preparing
 	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
@@ -1445,19 +1658,19 @@ func TestResctrlReconcile_reconcile(t *testing.T) {
 		cpuInfoContents := "flags		: fpu vme de pse cat_l3 mba"
 		helper.WriteProcSubFileContents("cpuinfo", cpuInfoContents)
 
-		r.reconcile()
+		r.reconcile(context.TODO())
 
 		// test init cat resctrl failed
 		system.Conf.SysFSRootDir = "invalidPath"
-		r.reconcile()
+		r.reconcile(context.TODO())
 		system.Conf.SysFSRootDir = validSysFSRootDir
 
-		r.reconcile()
+		r.reconcile(context.TODO())
 
 		// test strategy parse error
 		testingNodeSLO.Spec.ResourceQOSStrategy = nil
 		statesInformer.EXPECT().GetNodeSLO().Return(testingNodeSLO).AnyTimes()
-		r.reconcile()
+		r.reconcile(context.TODO())
 
 	})
 }
@@ -1543,3 +1756,119 @@ func Test_calculateMbaPercentForGroup(t *testing.T) {
 		})
 	}
 }
+
+func TestResctrlReconcile_reconcileMBATiers(t *testing.T) {
+	testingContainerParentDir := "kubepods.slice/p0/cri-containerd-c0.scope"
+	testingContainerTasksStr := "122450\n122454"
+	testingPodMeta := &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "pod0",
+				UID:  "p0",
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(extension.QoSBE),
+				},
+				Annotations: map[string]string{
+					extension.AnnotationResctrlMBATier: "silver",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Name: "container0",
+					},
+				},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{
+						Name:        "container0",
+						ContainerID: "containerd://c0",
+					},
+				},
+			},
+		},
+		CgroupDir: "kubepods.slice/p0",
+	}
+	testQOSStrategy := sloconfig.DefaultResourceQOSStrategy()
+	testQOSStrategy.BEClass.ResctrlQOS.Enable = pointer.Bool(true)
+	testQOSStrategy.ResctrlMBATiers = []slov1alpha1.ResctrlMBATier{
+		{
+			Name:       "gold",
+			MBAPercent: pointer.Int64(80),
+		},
+		{
+			Name:       "silver",
+			MBAPercent: pointer.Int64(50),
+		},
+		{
+			Name:       "invalid/tier",
+			MBAPercent: pointer.Int64(20),
+		},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	statesInformer := mock_statesinformer.NewMockStatesInformer(ctrl)
+	statesInformer.EXPECT().GetAllPods().Return([]*statesinformer.PodMeta{testingPodMeta}).AnyTimes()
+	metricCache := mock_metriccache.NewMockMetricCache(ctrl)
+	metricCache.EXPECT().Get(metriccache.NodeCPUInfoKey).Return(&metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CatL3CbmMask: "7ff"},
+		TotalInfo: koordletutil.CPUTotalInfo{L3ToCPU: map[int32][]koordletutil.ProcessorInfo{0: {}, 1: {}}},
+	}, true).AnyTimes()
+	opt := &framework.Options{
+		StatesInformer: statesInformer,
+		MetricCache:    metricCache,
+		Config:         framework.NewDefaultConfig(),
+	}
+	r := newTestResctrlReconcile(opt)
+	stop := make(chan struct{})
+	r.init(stop)
+	defer func() { stop <- struct{}{} }()
+
+	testingPrepareResctrlL3CatGroups(t, "7ff", "L3:0=7ff;1=7ff\n")
+	testingPrepareContainerCgroupCPUTasks(t, helper, testingContainerParentDir, testingContainerTasksStr)
+	resctrlDir := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)
+	staleGroupDir := filepath.Join(resctrlDir, getMBATierResctrlGroup("bronze"))
+	assert.NoError(t, os.MkdirAll(staleGroupDir, 0700))
+
+	tierGroups := getMBATierResctrlGroups(testQOSStrategy)
+	assert.Len(t, tierGroups, 2)
+	initMBATierResctrl(tierGroups)
+	_, err := os.Stat(staleGroupDir)
+	assert.True(t, os.IsNotExist(err))
+	for group := range tierGroups {
+		_, err = os.Stat(filepath.Join(resctrlDir, group))
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(system.ResctrlSchemata.Path(group), []byte("    MB:0=100;1=100"), 0666))
+		assert.NoError(t, os.WriteFile(system.ResctrlTasks.Path(group), []byte{}, 0666))
+	}
+
+	r.reconcileRDTResctrlPolicy(context.TODO(), testQOSStrategy)
+	got, err := os.ReadFile(system.ResctrlSchemata.Path(getMBATierResctrlGroup("gold")))
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=80;1=80;\n", string(got))
+	got, err = os.ReadFile(system.ResctrlSchemata.Path(getMBATierResctrlGroup("silver")))
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=50;1=50;\n", string(got))
+
+	// the tasks of the pod are assigned to its tier instead of the BE group
+	r.reconcileResctrlGroups(context.TODO(), testQOSStrategy)
+	got, err = os.ReadFile(system.ResctrlTasks.Path(getMBATierResctrlGroup("silver")))
+	assert.NoError(t, err)
+	assert.Equal(t, "122450122454", string(got))
+	got, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "", string(got))
+
+	// fallback to the QoS class group if the tier is not defined
+	testQOSStrategy.ResctrlMBATiers = testQOSStrategy.ResctrlMBATiers[:1]
+	r.reconcileResctrlGroups(context.TODO(), testQOSStrategy)
+	got, err = os.ReadFile(system.ResctrlTasks.Path(BEResctrlGroup))
+	assert.NoError(t, err)
+	assert.Equal(t, "122450122454", string(got))
+}
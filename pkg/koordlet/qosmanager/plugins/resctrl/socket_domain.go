@@ -0,0 +1,162 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// getSocketCacheIds returns the l3 cache ids of each cpu socket, which are the domains of the resctrl schemata.
+func getSocketCacheIds(nodeCPUInfo *metriccache.NodeCPUInfo) map[int32][]int {
+	if nodeCPUInfo == nil {
+		return nil
+	}
+	socketCacheIds := map[int32][]int{}
+	for l3ID, processors := range nodeCPUInfo.TotalInfo.L3ToCPU {
+		if len(processors) <= 0 {
+			continue
+		}
+		// the processors sharing a l3 cache are on the same socket
+		socketID := processors[0].SocketID
+		socketCacheIds[socketID] = append(socketCacheIds[socketID], int(l3ID))
+	}
+	for _, ids := range socketCacheIds {
+		sort.Ints(ids)
+	}
+	return socketCacheIds
+}
+
+// mergeSocketResctrlQOS returns the resctrl qos of the socket, where the unspecified values inherit the class.
+func mergeSocketResctrlQOS(class, socket slov1alpha1.ResctrlQOS) slov1alpha1.ResctrlQOS {
+	merged := *class.DeepCopy()
+	if socket.CATRangeStartPercent != nil {
+		merged.CATRangeStartPercent = socket.CATRangeStartPercent
+	}
+	if socket.CATRangeEndPercent != nil {
+		merged.CATRangeEndPercent = socket.CATRangeEndPercent
+	}
+	if socket.CATCodeRangeStartPercent != nil {
+		merged.CATCodeRangeStartPercent = socket.CATCodeRangeStartPercent
+	}
+	if socket.CATCodeRangeEndPercent != nil {
+		merged.CATCodeRangeEndPercent = socket.CATCodeRangeEndPercent
+	}
+	if socket.MBAPercent != nil {
+		merged.MBAPercent = socket.MBAPercent
+	}
+	return merged
+}
+
+// getCATRange returns the LLC range of the resctrl qos, which is also the range of the data in the CDP mode.
+func getCATRange(resctrlQoS slov1alpha1.ResctrlQOS) (*int64, *int64) {
+	return resctrlQoS.CATRangeStartPercent, resctrlQoS.CATRangeEndPercent
+}
+
+// getCATCodeRange returns the LLC range of the code in the CDP mode, which falls back to the range of the data.
+func getCATCodeRange(resctrlQoS slov1alpha1.ResctrlQOS) (*int64, *int64) {
+	if resctrlQoS.CATCodeRangeStartPercent != nil && resctrlQoS.CATCodeRangeEndPercent != nil {
+		return resctrlQoS.CATCodeRangeStartPercent, resctrlQoS.CATCodeRangeEndPercent
+	}
+	return getCATRange(resctrlQoS)
+}
+
+// forEachSocketCacheId calls the fn with the merged resctrl qos of each socket override and the cache ids of the
+// sockets. The unknown sockets are skipped.
+func forEachSocketCacheId(group string, resctrlQoS *slov1alpha1.ResctrlQOSCfg, socketCacheIds map[int32][]int,
+	fn func(socketQoS slov1alpha1.ResctrlQOS, cacheIds []int) error) error {
+	if resctrlQoS == nil {
+		return nil
+	}
+	for _, socket := range resctrlQoS.Sockets {
+		var cacheIds []int
+		for _, socketID := range socket.SocketIDs {
+			ids, ok := socketCacheIds[socketID]
+			if !ok {
+				klog.V(5).Infof("skip the resctrl qos of unknown socket %d for group %s", socketID, group)
+				continue
+			}
+			cacheIds = append(cacheIds, ids...)
+		}
+		if len(cacheIds) <= 0 {
+			continue
+		}
+		if err := fn(mergeSocketResctrlQOS(resctrlQoS.ResctrlQOS, socket.ResctrlQOS), cacheIds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// calculateSocketL3Masks returns the l3 masks of the cache ids on the overridden sockets.
+func calculateSocketL3Masks(group string, cbm uint, resctrlQoS *slov1alpha1.ResctrlQOSCfg,
+	socketCacheIds map[int32][]int) (map[int]string, error) {
+	return calculateSocketL3MasksByRange(group, cbm, resctrlQoS, socketCacheIds, getCATRange)
+}
+
+// calculateSocketL3CodeMasks returns the l3 code masks of the cache ids on the overridden sockets in the CDP mode.
+func calculateSocketL3CodeMasks(group string, cbm uint, resctrlQoS *slov1alpha1.ResctrlQOSCfg,
+	socketCacheIds map[int32][]int) (map[int]string, error) {
+	return calculateSocketL3MasksByRange(group, cbm, resctrlQoS, socketCacheIds, getCATCodeRange)
+}
+
+func calculateSocketL3MasksByRange(group string, cbm uint, resctrlQoS *slov1alpha1.ResctrlQOSCfg,
+	socketCacheIds map[int32][]int, rangeFn func(slov1alpha1.ResctrlQOS) (*int64, *int64)) (map[int]string, error) {
+	masks := map[int]string{}
+	err := forEachSocketCacheId(group, resctrlQoS, socketCacheIds, func(socketQoS slov1alpha1.ResctrlQOS, cacheIds []int) error {
+		startPercent, endPercent := rangeFn(socketQoS)
+		if startPercent == nil || endPercent == nil {
+			return nil
+		}
+		mask, err := system.CalculateCatL3MaskValue(cbm, *startPercent, *endPercent)
+		if err != nil {
+			return fmt.Errorf("failed to calculate l3 cat schemata of cache ids %v, err: %w", cacheIds, err)
+		}
+		for _, id := range cacheIds {
+			masks[id] = mask
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return masks, nil
+}
+
+// calculateSocketMbaValues returns the mba values of the cache ids on the overridden sockets.
+func calculateSocketMbaValues(group string, cpuBasicInfo extension.CPUBasicInfo, resctrlQoS *slov1alpha1.ResctrlQOSCfg,
+	socketCacheIds map[int32][]int) map[int]string {
+	values := map[int]string{}
+	_ = forEachSocketCacheId(group, resctrlQoS, socketCacheIds, func(socketQoS slov1alpha1.ResctrlQOS, cacheIds []int) error {
+		value := calculateMbaPercentForGroup(group, socketQoS.MBAPercent, cpuBasicInfo)
+		if value == "" {
+			return nil
+		}
+		for _, id := range cacheIds {
+			values[id] = value
+		}
+		return nil
+	})
+	return values
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resctrl
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func newTestSocketResctrlQOS() *slov1alpha1.ResctrlQOSCfg {
+	return &slov1alpha1.ResctrlQOSCfg{
+		Enable: pointer.Bool(true),
+		ResctrlQOS: slov1alpha1.ResctrlQOS{
+			CATRangeStartPercent: pointer.Int64(0),
+			CATRangeEndPercent:   pointer.Int64(50),
+			MBAPercent:           pointer.Int64(50),
+		},
+		Sockets: []slov1alpha1.ResctrlSocketQOS{
+			{
+				// BE gets more ways on the socket with fewer LS pods
+				SocketIDs: []int32{1},
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					CATRangeEndPercent: pointer.Int64(100),
+				},
+			},
+			{
+				SocketIDs: []int32{2},
+				ResctrlQOS: slov1alpha1.ResctrlQOS{
+					MBAPercent: pointer.Int64(100),
+				},
+			},
+		},
+	}
+}
+
+func Test_getSocketCacheIds(t *testing.T) {
+	assert.Nil(t, getSocketCacheIds(nil))
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		TotalInfo: koordletutil.CPUTotalInfo{
+			L3ToCPU: map[int32][]koordletutil.ProcessorInfo{
+				0: {{CPUID: 0, SocketID: 0, L3: 0}},
+				1: {{CPUID: 1, SocketID: 0, L3: 1}},
+				2: {{CPUID: 2, SocketID: 1, L3: 2}},
+				3: {},
+			},
+		},
+	}
+	assert.Equal(t, map[int32][]int{0: {0, 1}, 1: {2}}, getSocketCacheIds(nodeCPUInfo))
+}
+
+func Test_calculateSocketL3Masks(t *testing.T) {
+	socketCacheIds := map[int32][]int{0: {0}, 1: {1}}
+	masks, err := calculateSocketL3Masks(BEResctrlGroup, 0xff, nil, socketCacheIds)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{}, masks)
+
+	// the unknown socket is skipped and the unspecified values inherit the class
+	masks, err = calculateSocketL3Masks(BEResctrlGroup, 0xff, newTestSocketResctrlQOS(), socketCacheIds)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "ff"}, masks)
+
+	invalid := newTestSocketResctrlQOS()
+	invalid.Sockets[0].CATRangeStartPercent = pointer.Int64(100)
+	_, err = calculateSocketL3Masks(BEResctrlGroup, 0xff, invalid, socketCacheIds)
+	assert.Error(t, err)
+}
+
+func Test_calculateSocketMbaValues(t *testing.T) {
+	socketCacheIds := map[int32][]int{0: {0}, 1: {1}, 2: {2, 3}}
+	values := calculateSocketMbaValues(BEResctrlGroup, extension.CPUBasicInfo{}, newTestSocketResctrlQOS(), socketCacheIds)
+	assert.Equal(t, map[int]string{1: "50", 2: "100", 3: "100"}, values)
+	values = calculateSocketMbaValues(BEResctrlGroup, extension.CPUBasicInfo{VendorID: system.AMD_VENDOR_ID},
+		newTestSocketResctrlQOS(), socketCacheIds)
+	assert.Equal(t, map[int]string{1: "80", 2: AMDCCDUnlimitedMB, 3: AMDCCDUnlimitedMB}, values)
+}
+
+func TestResctrlReconcile_applySocketResctrlPolicy(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldCacheIdsFunc := system.CacheIdsCacheFunc
+	system.CacheIdsCacheFunc = system.GetCacheIds
+	defer func() {
+		system.CacheIdsCacheFunc = oldCacheIdsFunc
+	}()
+	testingPrepareResctrlL3CatGroups(t, "ff", "L3:0=ff;1=ff\nMB:0=100;1=100\n")
+
+	r := newTestResctrlReconcile(&framework.Options{
+		Config: framework.NewDefaultConfig(),
+	})
+	stop := make(chan struct{})
+	assert.NotPanics(t, func() {
+		r.init(stop)
+	})
+	defer func() { stop <- struct{}{} }()
+
+	socketCacheIds := map[int32][]int{0: {0}, 1: {1}}
+	resourceQOS := &slov1alpha1.ResourceQOS{ResctrlQOS: newTestSocketResctrlQOS()}
+	schemataPath := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir, BEResctrlGroup, system.ResctrlSchemataName)
+	err := r.calculateAndApplyRDTL3PolicyForGroup(context.TODO(), BEResctrlGroup, 0xff, 2, socketCacheIds, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=f;1=ff;\n", helper.ReadFileContents(schemataPath))
+	resourceQOS.ResctrlQOS.Sockets[0].MBAPercent = pointer.Int64(80)
+	err = r.calculateAndApplyRDTMbPolicyForGroup(context.TODO(), BEResctrlGroup, 2, extension.CPUBasicInfo{}, socketCacheIds, resourceQOS)
+	assert.NoError(t, err)
+	assert.Equal(t, "MB:0=50;1=80;\n", helper.ReadFileContents(schemataPath))
+}
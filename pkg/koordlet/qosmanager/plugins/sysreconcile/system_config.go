@@ -17,6 +17,7 @@ limitations under the License.
 package sysreconcile
 
 import (
+	"context"
 	"strconv"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -61,14 +63,14 @@ func (r *systemConfig) Setup(context *framework.Context) {
 
 func (r *systemConfig) Run(stopCh <-chan struct{}) {
 	r.init(stopCh)
-	go wait.Until(r.reconcile, r.reconcileInterval, stopCh)
+	go wait.Until(tracing.WrapRoundWithContext(tracing.ModuleQOSManager, SystemConfigReconcileName, r.reconcile), r.reconcileInterval, stopCh)
 }
 
 func (s *systemConfig) init(stopCh <-chan struct{}) {
 	s.executor.Run(stopCh)
 }
 
-func (s *systemConfig) reconcile() {
+func (s *systemConfig) reconcile(ctx context.Context) {
 	nodeSLO := s.statesInformer.GetNodeSLO()
 
 	if nodeSLO == nil || nodeSLO.Spec.SystemStrategy == nil {
@@ -90,7 +92,7 @@ func (s *systemConfig) reconcile() {
 	var resources []resourceexecutor.ResourceUpdater
 	resources = append(resources, caculateMemoryConfig(nodeSLO.Spec.SystemStrategy, memoryCapacity)...)
 
-	s.executor.UpdateBatch(true, resources...)
+	s.executor.UpdateBatch(ctx, true, resources...)
 	klog.V(5).Infof("finish to reconcile system config!")
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package sysreconcile
 
 import (
+	"context"
 	"strconv"
 	"testing"
 
@@ -149,7 +150,7 @@ func Test_systemConfig_reconcile(t *testing.T) {
 			}()
 			reconcile.executor.Run(stopCh)
 
-			reconcile.reconcile()
+			reconcile.reconcile(context.TODO())
 			for file, expectValue := range tt.expect {
 				got := helper.ReadFileContents(file.Path(""))
 				assert.Equal(t, expectValue, got, file.Path(""))
@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/informers"
 	clientset "k8s.io/client-go/kubernetes"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
@@ -30,6 +31,8 @@ import (
 	"k8s.io/klog/v2"
 
 	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	_ "github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	ma "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
@@ -37,6 +40,8 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util/evictionbudget"
+	"github.com/koordinator-sh/koordinator/pkg/util/sorter"
 )
 
 type QOSManager interface {
@@ -46,6 +51,10 @@ type QOSManager interface {
 type qosManager struct {
 	options *framework.Options
 	context *framework.Context
+	// budgetInformerFactory informs the ClusterEvictionBudgets for the eviction budget admission if it is set
+	budgetInformerFactory koordinformers.SharedInformerFactory
+	// pdbInformerFactory informs the PodDisruptionBudgets for ordering the victims if it is set
+	pdbInformerFactory informers.SharedInformerFactory
 }
 
 func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient clientset.Interface, crdClient *koordclientset.Clientset, nodeName string,
@@ -55,6 +64,18 @@ func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient
 	recorder := eventBroadcaster.NewRecorder(schema, corev1.EventSource{Component: "koordlet-qosManager", Host: nodeName})
 	cgroupReader := resourceexecutor.NewCgroupReader()
 	evictor := framework.NewEvictor(kubeClient, recorder, evictVersion)
+	var budgetInformerFactory koordinformers.SharedInformerFactory
+	if features.DefaultKoordletFeatureGate.Enabled(features.EvictionBudgetAdmission) && crdClient != nil {
+		budgetInformerFactory = koordinformers.NewSharedInformerFactory(crdClient, 0)
+		budgetInformer := budgetInformerFactory.Slo().V1alpha1().ClusterEvictionBudgets()
+		evictor.SetEvictionBudgetAdmitter(evictionbudget.NewAdmitter(crdClient, budgetInformer))
+	}
+	var pdbInformerFactory informers.SharedInformerFactory
+	if features.DefaultKoordletFeatureGate.Enabled(features.EvictionSortByPDB) {
+		pdbInformerFactory = informers.NewSharedInformerFactory(kubeClient, 0)
+		pdbLister := pdbInformerFactory.Policy().V1().PodDisruptionBudgets().Lister()
+		sorter.SetDisruptionBudgetGetter(sorter.NewPDBDisruptionBudgetGetter(pdbLister))
+	}
 
 	opt := &framework.Options{
 		CgroupReader:        cgroupReader,
@@ -62,6 +83,7 @@ func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient
 		MetricCache:         metricCache,
 		EventRecorder:       recorder,
 		KubeClient:          kubeClient,
+		KoordClient:         crdClient,
 		EvictVersion:        evictVersion,
 		Config:              cfg,
 		MetricAdvisorConfig: metricAdvisorConfig,
@@ -77,8 +99,10 @@ func NewQOSManager(cfg *framework.Config, schema *apiruntime.Scheme, kubeClient
 	}
 
 	r := &qosManager{
-		options: opt,
-		context: ctx,
+		options:               opt,
+		context:               ctx,
+		budgetInformerFactory: budgetInformerFactory,
+		pdbInformerFactory:    pdbInformerFactory,
 	}
 	return r
 }
@@ -105,6 +129,12 @@ func (r *qosManager) Run(stopCh <-chan struct{}) error {
 	if err != nil {
 		klog.Fatal("start evictor failed %v", err)
 	}
+	if r.budgetInformerFactory != nil {
+		r.budgetInformerFactory.Start(stopCh)
+	}
+	if r.pdbInformerFactory != nil {
+		r.pdbInformerFactory.Start(stopCh)
+	}
 
 	go framework.RunQOSGreyCtrlPlugins(r.options.KubeClient, stopCh)
 
@@ -29,6 +29,7 @@ import (
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
 	maframework "github.com/koordinator-sh/koordinator/pkg/koordlet/metricsadvisor/framework"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/framework"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/plugins"
 	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
 )
 
@@ -48,3 +49,34 @@ func TestNewResManager(t *testing.T) {
 		assert.NotNil(t, r)
 	})
 }
+
+type testOutOfTreeStrategy struct {
+	setup bool
+}
+
+func (s *testOutOfTreeStrategy) Enabled() bool { return true }
+
+func (s *testOutOfTreeStrategy) Setup(*framework.Context) { s.setup = true }
+
+func (s *testOutOfTreeStrategy) Run(stopCh <-chan struct{}) {}
+
+func TestNewQOSManagerWithOutOfTreeStrategy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	strategy := &testOutOfTreeStrategy{}
+	assert.NoError(t, plugins.StrategyPlugins.Register("TestOutOfTreeStrategy", func(opt *framework.Options) framework.QOSStrategy {
+		return strategy
+	}))
+	defer func() {
+		assert.NoError(t, plugins.StrategyPlugins.Unregister("TestOutOfTreeStrategy"))
+	}()
+
+	r := NewQOSManager(framework.NewDefaultConfig(), apiruntime.NewScheme(), &kubernetes.Clientset{}, &clientsetalpha1.Clientset{},
+		"test-node", mock_statesinformer.NewMockStatesInformer(ctrl), mock_metriccache.NewMockMetricCache(ctrl),
+		maframework.NewDefaultConfig(), policyv1beta1.SchemeGroupVersion.String())
+	m := r.(*qosManager)
+	assert.Equal(t, strategy, m.context.Strategies["TestOutOfTreeStrategy"])
+	m.setup()
+	assert.True(t, strategy.setup)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	nodeutil "k8s.io/component-helpers/node/util"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/qosmanager/helpers"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	ReasonColocationReady    = "ColocationReady"
+	ReasonColocationNotReady = "ColocationNotReady"
+
+	defaultReportInterval = 30 * time.Second
+)
+
+var (
+	timeNow = time.Now
+)
+
+// Check is a health check of the colocation capability. It returns false with the message if the QoS enforcement
+// related to the check is broken.
+type Check struct {
+	Name string
+	Fn   func() (bool, string)
+}
+
+// Reporter runs the checks periodically and reports the ColocationReady condition of the node, so that the scheduler
+// can avoid placing the batch pods on the nodes where the QoS enforcement is broken.
+type Reporter interface {
+	Run(stopCh <-chan struct{})
+}
+
+type reporter struct {
+	interval       time.Duration
+	kubeClient     clientset.Interface
+	statesInformer statesinformer.StatesInformer
+	checks         []Check
+
+	lastConditionStatus  corev1.ConditionStatus
+	lastConditionMessage string
+}
+
+func NewReporter(kubeClient clientset.Interface, statesInformer statesinformer.StatesInformer, checks ...Check) Reporter {
+	return &reporter{
+		interval:       defaultReportInterval,
+		kubeClient:     kubeClient,
+		statesInformer: statesInformer,
+		checks:         checks,
+	}
+}
+
+func (r *reporter) Run(stopCh <-chan struct{}) {
+	if !cache.WaitForCacheSync(stopCh, r.statesInformer.HasSynced) {
+		klog.Errorf("timed out waiting for states informer caches to sync, skip reporting colocation readiness")
+		return
+	}
+	klog.V(4).Infof("start reporting colocation readiness, checks num %d", len(r.checks))
+	wait.Until(r.report, r.interval, stopCh)
+}
+
+func (r *reporter) report() {
+	ready, message := r.runChecks()
+	r.updateNodeCondition(ready, message)
+}
+
+// runChecks returns whether all checks pass and the aggregated message of the failed checks.
+func (r *reporter) runChecks() (bool, string) {
+	var failed []string
+	for _, check := range r.checks {
+		ok, msg := check.Fn()
+		if ok {
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s: %s", check.Name, msg))
+	}
+	if len(failed) == 0 {
+		return true, "all colocation checks passed"
+	}
+	sort.Strings(failed)
+	return false, strings.Join(failed, "; ")
+}
+
+// updateNodeCondition patches the colocation ready condition of the node if the status or the message changes.
+func (r *reporter) updateNodeCondition(ready bool, message string) {
+	node := r.statesInformer.GetNode()
+	if node == nil {
+		klog.V(5).Infof("skip updating colocation ready condition since the node is not synced")
+		return
+	}
+	status, reason := corev1.ConditionFalse, ReasonColocationNotReady
+	if ready {
+		status, reason = corev1.ConditionTrue, ReasonColocationReady
+	}
+	if r.lastConditionStatus == status && r.lastConditionMessage == message {
+		return
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == apiext.NodeConditionColocationReady && condition.Status == status && condition.Message == message {
+			r.lastConditionStatus, r.lastConditionMessage = status, message
+			return
+		}
+	}
+
+	condition := corev1.NodeCondition{
+		Type:               apiext.NodeConditionColocationReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		LastTransitionTime: metav1.NewTime(timeNow()),
+	}
+	if err := nodeutil.SetNodeCondition(r.kubeClient, types.NodeName(node.Name), condition); err != nil {
+		klog.Warningf("failed to update colocation ready condition of node %s, err: %v", node.Name, err)
+		return
+	}
+	r.lastConditionStatus, r.lastConditionMessage = status, message
+	klog.V(4).Infof("update colocation ready condition of node %s to %s, %s", node.Name, status, message)
+}
+
+// NewCgroupDriverCheck checks whether the cgroup driver of the kubepods is detected.
+func NewCgroupDriverCheck() Check {
+	return Check{
+		Name: "cgroupDriver",
+		Fn: func() (bool, string) {
+			if !system.GetCgroupDriverFromCgroupName().Validate() {
+				return false, "cgroup driver of kubepods is not detected"
+			}
+			return true, ""
+		},
+	}
+}
+
+// NewResctrlCheck checks whether the resctrl filesystem is mounted when the RdtResctrl is enabled on the supported
+// platform.
+func NewResctrlCheck() Check {
+	return Check{
+		Name: "resctrl",
+		Fn: func() (bool, string) {
+			if !features.DefaultKoordletFeatureGate.Enabled(features.RdtResctrl) {
+				return true, ""
+			}
+			if supported, err := system.IsSupportResctrl(); err != nil || !supported {
+				return true, ""
+			}
+			if !system.FileExists(system.ResctrlSchemata.Path("")) {
+				return false, "resctrl filesystem is not mounted"
+			}
+			return true, ""
+		},
+	}
+}
+
+// NewRuntimeHooksCheck checks whether the runtime hooks have been registered.
+func NewRuntimeHooksCheck(hasSynced func() bool) Check {
+	return Check{
+		Name: "runtimeHooks",
+		Fn: func() (bool, string) {
+			if !hasSynced() {
+				return false, "runtime hooks are not registered"
+			}
+			return true, ""
+		},
+	}
+}
+
+// NewMetricCollectionCheck checks whether the node resource usage has been collected recently.
+func NewMetricCollectionCheck(metricCache metriccache.MetricCache, collectInterval time.Duration) Check {
+	return Check{
+		Name: "metricCollection",
+		Fn: func() (bool, string) {
+			queryMeta, err := metriccache.NodeCPUUsageMetric.BuildQueryMeta(nil)
+			if err != nil {
+				return false, fmt.Sprintf("failed to build query meta, err: %v", err)
+			}
+			end := timeNow()
+			result, err := helpers.CollectNodeMetrics(metricCache, end.Add(-3*collectInterval), end, queryMeta)
+			if err != nil {
+				return false, fmt.Sprintf("failed to query node cpu usage, err: %v", err)
+			}
+			if result.Count() <= 0 {
+				return false, fmt.Sprintf("node cpu usage is not collected in the last %v", 3*collectInterval)
+			}
+			return true, ""
+		},
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readiness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
+	mockstatesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+func TestReporter_report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(node)
+	mockStatesInformer := mockstatesinformer.NewMockStatesInformer(ctrl)
+	mockStatesInformer.EXPECT().GetNode().Return(node).AnyTimes()
+
+	hooksSynced := false
+	driverOK := true
+	r := NewReporter(kubeClient, mockStatesInformer,
+		Check{Name: "fake", Fn: func() (bool, string) {
+			if !driverOK {
+				return false, "broken"
+			}
+			return true, ""
+		}},
+		NewRuntimeHooksCheck(func() bool { return hooksSynced }),
+	).(*reporter)
+
+	getCondition := func() *corev1.NodeCondition {
+		gotNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		for i := range gotNode.Status.Conditions {
+			if gotNode.Status.Conditions[i].Type == apiext.NodeConditionColocationReady {
+				return &gotNode.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// runtime hooks are not registered
+	r.report()
+	condition := getCondition()
+	assert.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonColocationNotReady, condition.Reason)
+	assert.Equal(t, "runtimeHooks: runtime hooks are not registered", condition.Message)
+
+	// the failed checks are aggregated
+	driverOK = false
+	r.report()
+	condition = getCondition()
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, "fake: broken; runtimeHooks: runtime hooks are not registered", condition.Message)
+
+	// all checks pass
+	driverOK, hooksSynced = true, true
+	r.report()
+	condition = getCondition()
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonColocationReady, condition.Reason)
+	gotNode, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, apiext.IsNodeColocationNotReady(gotNode))
+}
+
+func TestNewMetricCollectionCheck(t *testing.T) {
+	metricCache, err := metriccache.NewMetricCache(&metriccache.Config{
+		TSDBPath:              t.TempDir(),
+		TSDBEnablePromMetrics: false,
+	})
+	assert.NoError(t, err)
+	defer metricCache.Close()
+
+	testNow := time.Now()
+	timeNow = func() time.Time {
+		return testNow
+	}
+	defer func() {
+		timeNow = time.Now
+	}()
+
+	check := NewMetricCollectionCheck(metricCache, time.Second)
+	ok, msg := check.Fn()
+	assert.False(t, ok)
+	assert.Equal(t, "node cpu usage is not collected in the last 3s", msg)
+
+	sample, err := metriccache.NodeCPUUsageMetric.GenerateSample(nil, testNow.Add(-time.Second), 1)
+	assert.NoError(t, err)
+	appender := metricCache.Appender()
+	assert.NoError(t, appender.Append([]metriccache.MetricSample{sample}))
+	assert.NoError(t, appender.Commit())
+	ok, _ = check.Fn()
+	assert.True(t, ok)
+}
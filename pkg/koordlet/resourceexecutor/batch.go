@@ -0,0 +1,147 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/util/flowcontrol"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+)
+
+type batchUpdateItem struct {
+	cacheable   bool
+	updater     ResourceUpdater
+	enqueueTime time.Time
+	// spanContext is the span of the latest enqueue, which is the parent span of the flushed write
+	spanContext trace.SpanContext
+	// element is the position of the key in the enqueue order
+	element *list.Element
+}
+
+// updateBatcher queues the resource updates to reduce the write amplification when lots of containers are changing.
+// The updates of the same resource key are coalesced that only the latest one is written, and the writes of each
+// subsystem are limited by an independent token bucket, where the updates exceeding the limit wait for the next flush.
+type updateBatcher struct {
+	lock     sync.Mutex
+	pending  map[string]*batchUpdateItem
+	order    *list.List
+	limiters map[string]flowcontrol.RateLimiter
+	qps      float32
+	burst    int
+}
+
+func newUpdateBatcher(qps, burst int) *updateBatcher {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &updateBatcher{
+		pending:  map[string]*batchUpdateItem{},
+		order:    list.New(),
+		limiters: map[string]flowcontrol.RateLimiter{},
+		qps:      float32(qps),
+		burst:    burst,
+	}
+}
+
+// Add enqueues the updates. The previous pending update of the same key is replaced and moved to the tail of the
+// queue, so the updates are flushed in the order of the latest enqueue, e.g. the parent cgroup before the child when
+// they are enqueued in that order again. The enqueue time of the first pending one is kept for the latency metric.
+func (b *updateBatcher) Add(spanContext trace.SpanContext, cacheable bool, updaters ...ResourceUpdater) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := time.Now()
+	for _, updater := range updaters {
+		key := updater.Key()
+		if item, ok := b.pending[key]; ok {
+			metrics.RecordResourceUpdateCoalesced(getUpdaterSubsystem(updater))
+			item.cacheable = cacheable
+			item.updater = updater
+			item.spanContext = spanContext
+			b.order.MoveToBack(item.element)
+			continue
+		}
+		b.pending[key] = &batchUpdateItem{
+			cacheable:   cacheable,
+			updater:     updater,
+			enqueueTime: now,
+			spanContext: spanContext,
+			element:     b.order.PushBack(key),
+		}
+	}
+	metrics.RecordResourceUpdateQueueDepth(b.order.Len())
+}
+
+// Pop dequeues the pending updates in the enqueue order which are allowed by the rate limiters of their subsystems.
+func (b *updateBatcher) Pop() []*batchUpdateItem {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	var items []*batchUpdateItem
+	now := time.Now()
+	for e := b.order.Front(); e != nil; {
+		next := e.Next()
+		key := e.Value.(string)
+		item := b.pending[key]
+		subsystem := getUpdaterSubsystem(item.updater)
+		if b.tryAccept(subsystem) {
+			b.order.Remove(e)
+			delete(b.pending, key)
+			metrics.RecordResourceUpdateQueueLatency(subsystem, now.Sub(item.enqueueTime).Seconds())
+			items = append(items, item)
+		}
+		e = next
+	}
+	metrics.RecordResourceUpdateQueueDepth(b.order.Len())
+	return items
+}
+
+// Len returns the number of the pending updates.
+func (b *updateBatcher) Len() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.order.Len()
+}
+
+func (b *updateBatcher) tryAccept(subsystem string) bool {
+	if b.qps <= 0 {
+		return true
+	}
+	limiter, ok := b.limiters[subsystem]
+	if !ok {
+		limiter = flowcontrol.NewTokenBucketRateLimiter(b.qps, b.burst)
+		b.limiters[subsystem] = limiter
+	}
+	return limiter.TryAccept()
+}
+
+// getUpdaterSubsystem returns the subsystem of the resource, e.g. cpu for cpu.cfs_quota_us, memory for memory.min,
+// and the updater name for the non-cgroup resources like resctrl schemata.
+func getUpdaterSubsystem(updater ResourceUpdater) string {
+	if _, ok := updater.(*CgroupResourceUpdater); ok {
+		resourceType := string(updater.ResourceType())
+		if idx := strings.Index(resourceType, "."); idx > 0 {
+			return resourceType[:idx]
+		}
+		return resourceType
+	}
+	return updater.Name()
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestUpdateBatcher(t *testing.T) {
+	quotaUpdater, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, "pod-1", "100000", nil)
+	assert.NoError(t, err)
+	quotaUpdater1, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, "pod-1", "200000", nil)
+	assert.NoError(t, err)
+	sharesUpdater, err := NewCommonCgroupUpdater(sysutil.CPUSharesName, "pod-1", "1024", nil)
+	assert.NoError(t, err)
+	memoryUpdater, err := NewCommonCgroupUpdater(sysutil.MemoryLimitName, "pod-1", "1048576", nil)
+	assert.NoError(t, err)
+
+	// the duplicate updates are coalesced into the latest one
+	b := newUpdateBatcher(0, 0)
+	b.Add(trace.SpanContext{}, false, quotaUpdater, sharesUpdater)
+	b.Add(trace.SpanContext{}, true, quotaUpdater1)
+	assert.Equal(t, 2, b.Len())
+	items := b.Pop()
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, 0, b.Len())
+	// the coalesced update is moved to the tail
+	assert.Equal(t, sharesUpdater.Key(), items[0].updater.Key())
+	assert.Equal(t, "1024", items[0].updater.Value())
+	assert.Equal(t, "200000", items[1].updater.Value())
+	assert.True(t, items[1].cacheable)
+
+	// the updates are flushed in the order of the latest enqueue
+	b.Add(trace.SpanContext{}, false, quotaUpdater, sharesUpdater)
+	b.Add(trace.SpanContext{}, false, sharesUpdater, quotaUpdater1)
+	items = b.Pop()
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, sharesUpdater.Key(), items[0].updater.Key())
+	assert.Equal(t, "200000", items[1].updater.Value())
+
+	// the updates exceeding the rate limit of the subsystem are kept for the next flush
+	b = newUpdateBatcher(1, 1)
+	b.Add(trace.SpanContext{}, false, quotaUpdater, sharesUpdater, memoryUpdater)
+	items = b.Pop()
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, quotaUpdater.Key(), items[0].updater.Key())
+	assert.Equal(t, memoryUpdater.Key(), items[1].updater.Key())
+	assert.Equal(t, 1, b.Len())
+}
+
+func Test_getUpdaterSubsystem(t *testing.T) {
+	quotaUpdater, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, "pod-1", "100000", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "cpu", getUpdaterSubsystem(quotaUpdater))
+	cpusetUpdater, err := NewCommonCgroupUpdater(sysutil.CPUSetCPUSName, "pod-1", "0-1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "cpuset", getUpdaterSubsystem(cpusetUpdater))
+	defaultUpdater, err := NewCommonDefaultUpdater("/proc/sys/kernel/sched_group_identity_enabled", "/proc/sys/kernel/sched_group_identity_enabled", "1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "default", getUpdaterSubsystem(defaultUpdater))
+}
+
+func TestResourceUpdateExecutor_BatchUpdate(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	podDir := "kubepods.slice/kubepods-burstable.slice/pod-1"
+	helper.WriteCgroupFileContents(podDir, sysutil.CPUShares, "1024")
+	helper.WriteCgroupFileContents(podDir, sysutil.CPUCFSQuota, "-1")
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+	e.setBatcher(newUpdateBatcher(0, 0))
+
+	sharesUpdater, err := NewCommonCgroupUpdater(sysutil.CPUSharesName, podDir, "2048", nil)
+	assert.NoError(t, err)
+	sharesUpdater1, err := NewCommonCgroupUpdater(sysutil.CPUSharesName, podDir, "4096", nil)
+	assert.NoError(t, err)
+	quotaUpdater, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, podDir, "100000", nil)
+	assert.NoError(t, err)
+
+	// the async updates are not written until the flush
+	e.UpdateBatchAsync(context.TODO(), true, sharesUpdater, quotaUpdater)
+	e.UpdateBatchAsync(context.TODO(), true, sharesUpdater1)
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(podDir, sysutil.CPUShares))
+	assert.Equal(t, "-1", helper.ReadCgroupFileContents(podDir, sysutil.CPUCFSQuota))
+
+	e.flushBatch()
+	assert.Equal(t, "4096", helper.ReadCgroupFileContents(podDir, sysutil.CPUShares))
+	assert.Equal(t, "100000", helper.ReadCgroupFileContents(podDir, sysutil.CPUCFSQuota))
+	assert.Equal(t, 0, e.getBatcher().Len())
+
+	// the sync updates are written on return even if the batching is enabled
+	sharesUpdater2, err := NewCommonCgroupUpdater(sysutil.CPUSharesName, podDir, "8192", nil)
+	assert.NoError(t, err)
+	e.UpdateBatch(context.TODO(), true, sharesUpdater2)
+	assert.Equal(t, "8192", helper.ReadCgroupFileContents(podDir, sysutil.CPUShares))
+	assert.Equal(t, 0, e.getBatcher().Len())
+}
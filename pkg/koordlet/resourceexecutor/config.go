@@ -16,16 +16,27 @@ limitations under the License.
 
 package resourceexecutor
 
-import "flag"
+import (
+	"flag"
+
+	cliflag "k8s.io/component-base/cli/flag"
+)
 
 const (
 	ReasonUpdateCgroups      = "UpdateCgroups"
 	ReasonUpdateSystemConfig = "UpdateSystemConfig"
 	ReasonUpdateResctrl      = "UpdateResctrl" // update resctrl tasks, schemata
 	CreateCATGroup           = "CreateCATGroup"
+	CreateResctrlMonGroup    = "CreateResctrlMonGroup"
+	RemoveResctrlMonGroup    = "RemoveResctrlMonGroup"
+	RemoveResctrlCtrlGroup   = "RemoveResctrlCtrlGroup"
+	RecycleResctrlMonGroup   = "RecycleResctrlMonGroup"
+	DryRunUpdateResource     = "DryRunUpdateResource"
 
 	EvictPodByNodeMemoryUsage   = "EvictPodByNodeMemoryUsage"
 	EvictPodByBECPUSatisfaction = "EvictPodByBECPUSatisfaction"
+	EvictPodByDiskQuota         = "EvictPodByDiskQuota"
+	EvictPodByPSI               = "EvictPodByPSI"
 
 	AdjustBEByNodeCPUUsage = "AdjustBEByNodeCPUUsage"
 )
@@ -34,14 +45,41 @@ var Conf = NewDefaultConfig()
 
 type Config struct {
 	ResourceForceUpdateSeconds int
+	// ResctrlGroupGCIntervalSeconds is the interval of the resctrl group gc. The gc is disabled when it is not positive.
+	ResctrlGroupGCIntervalSeconds int
+	// ResourceUpdateDryRun indicates the executor only logs and audits the resource updates instead of applying them,
+	// so the new policies can be validated on the production nodes before the enforcement. The creations and removals
+	// of the resctrl groups are also skipped.
+	ResourceUpdateDryRun bool
+	// ResourceUpdateDryRunFiles are the file names of the resources updated in the dry-run mode, e.g. cpu.cfs_quota_us
+	// or schemata, while the other resources are updated as usual.
+	ResourceUpdateDryRunFiles []string
+	// ResourceUpdateBatchIntervalMilliseconds is the interval of flushing the batched updates. When it is positive,
+	// the UpdateBatch enqueues the updates and the duplicate updates of the same file are coalesced before the flush.
+	// The batching is disabled when it is not positive.
+	ResourceUpdateBatchIntervalMilliseconds int
+	// ResourceUpdateSubsystemQPS is the rate limit of the batched updates of each subsystem, e.g. cpu, memory and
+	// resctrl. The rate is not limited when it is not positive.
+	ResourceUpdateSubsystemQPS int
+	// ResourceUpdateSubsystemBurst is the burst of the batched updates of each subsystem.
+	ResourceUpdateSubsystemBurst int
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
-		ResourceForceUpdateSeconds: 60,
+		ResourceForceUpdateSeconds:    60,
+		ResctrlGroupGCIntervalSeconds: 300,
+		ResourceUpdateSubsystemQPS:    500,
+		ResourceUpdateSubsystemBurst:  1000,
 	}
 }
 
 func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.IntVar(&c.ResourceForceUpdateSeconds, "resource-force-update-seconds", c.ResourceForceUpdateSeconds, "executor force update resources interval by seconds")
+	fs.IntVar(&c.ResctrlGroupGCIntervalSeconds, "resctrl-group-gc-interval-seconds", c.ResctrlGroupGCIntervalSeconds, "interval by seconds to garbage-collect the orphan resctrl groups and re-sync the resctrl tasks, non-positive value means disabled")
+	fs.BoolVar(&c.ResourceUpdateDryRun, "resource-update-dry-run", c.ResourceUpdateDryRun, "whether the executor only logs and audits the cgroup and resctrl updates without applying them")
+	fs.Var(cliflag.NewStringSlice(&c.ResourceUpdateDryRunFiles), "resource-update-dry-run-files", "file name of the cgroup and resctrl resources only logged and audited without applying the updates, e.g. cpu.cfs_quota_us, which can be specified multiple times")
+	fs.IntVar(&c.ResourceUpdateBatchIntervalMilliseconds, "resource-update-batch-interval-milliseconds", c.ResourceUpdateBatchIntervalMilliseconds, "interval by milliseconds to flush the batched resource updates where the duplicate updates of the same file are coalesced, non-positive value means disabled")
+	fs.IntVar(&c.ResourceUpdateSubsystemQPS, "resource-update-subsystem-qps", c.ResourceUpdateSubsystemQPS, "rate limit of the batched resource updates of each subsystem, non-positive value means unlimited")
+	fs.IntVar(&c.ResourceUpdateSubsystemBurst, "resource-update-subsystem-burst", c.ResourceUpdateSubsystemBurst, "burst of the batched resource updates of each subsystem")
 }
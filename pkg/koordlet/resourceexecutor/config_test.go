@@ -25,7 +25,10 @@ import (
 
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
-		ResourceForceUpdateSeconds: 60,
+		ResourceForceUpdateSeconds:    60,
+		ResctrlGroupGCIntervalSeconds: 300,
+		ResourceUpdateSubsystemQPS:    500,
+		ResourceUpdateSubsystemBurst:  1000,
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -33,7 +36,14 @@ func Test_NewDefaultConfig(t *testing.T) {
 
 func Test_InitFlags(t *testing.T) {
 	type fields struct {
-		ResourceForceUpdateSeconds int
+		ResourceForceUpdateSeconds    int
+		ResctrlGroupGCIntervalSeconds int
+		ResourceUpdateDryRun          bool
+		ResourceUpdateDryRunFiles     []string
+
+		ResourceUpdateBatchIntervalMilliseconds int
+		ResourceUpdateSubsystemQPS              int
+		ResourceUpdateSubsystemBurst            int
 	}
 	type args struct {
 		fs      *flag.FlagSet
@@ -47,7 +57,10 @@ func Test_InitFlags(t *testing.T) {
 		{
 			name: "not default",
 			fields: fields{
-				ResourceForceUpdateSeconds: 120,
+				ResourceForceUpdateSeconds:    120,
+				ResctrlGroupGCIntervalSeconds: 300,
+				ResourceUpdateSubsystemQPS:    500,
+				ResourceUpdateSubsystemBurst:  1000,
 			},
 			args: args{
 				fs: flag.NewFlagSet("", flag.ExitOnError),
@@ -60,13 +73,56 @@ func Test_InitFlags(t *testing.T) {
 		{
 			name: "not default 1",
 			fields: fields{
-				ResourceForceUpdateSeconds: 90,
+				ResourceForceUpdateSeconds:    90,
+				ResctrlGroupGCIntervalSeconds: 0,
+				ResourceUpdateSubsystemQPS:    500,
+				ResourceUpdateSubsystemBurst:  1000,
 			},
 			args: args{
 				fs: flag.NewFlagSet("", flag.ExitOnError),
 				cmdArgs: []string{
 					"",
 					"--resource-force-update-seconds=90",
+					"--resctrl-group-gc-interval-seconds=0",
+				},
+			},
+		},
+		{
+			name: "dry run",
+			fields: fields{
+				ResourceForceUpdateSeconds:    60,
+				ResctrlGroupGCIntervalSeconds: 300,
+				ResourceUpdateDryRun:          true,
+				ResourceUpdateDryRunFiles:     []string{"cpu.cfs_quota_us", "schemata"},
+				ResourceUpdateSubsystemQPS:    500,
+				ResourceUpdateSubsystemBurst:  1000,
+			},
+			args: args{
+				fs: flag.NewFlagSet("", flag.ExitOnError),
+				cmdArgs: []string{
+					"",
+					"--resource-update-dry-run=true",
+					"--resource-update-dry-run-files=cpu.cfs_quota_us",
+					"--resource-update-dry-run-files=schemata",
+				},
+			},
+		},
+		{
+			name: "batch update",
+			fields: fields{
+				ResourceForceUpdateSeconds:              60,
+				ResctrlGroupGCIntervalSeconds:           300,
+				ResourceUpdateBatchIntervalMilliseconds: 100,
+				ResourceUpdateSubsystemQPS:              200,
+				ResourceUpdateSubsystemBurst:            400,
+			},
+			args: args{
+				fs: flag.NewFlagSet("", flag.ExitOnError),
+				cmdArgs: []string{
+					"",
+					"--resource-update-batch-interval-milliseconds=100",
+					"--resource-update-subsystem-qps=200",
+					"--resource-update-subsystem-burst=400",
 				},
 			},
 		},
@@ -74,7 +130,14 @@ func Test_InitFlags(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			want := &Config{
-				ResourceForceUpdateSeconds: tt.fields.ResourceForceUpdateSeconds,
+				ResourceForceUpdateSeconds:    tt.fields.ResourceForceUpdateSeconds,
+				ResctrlGroupGCIntervalSeconds: tt.fields.ResctrlGroupGCIntervalSeconds,
+				ResourceUpdateDryRun:          tt.fields.ResourceUpdateDryRun,
+				ResourceUpdateDryRunFiles:     tt.fields.ResourceUpdateDryRunFiles,
+
+				ResourceUpdateBatchIntervalMilliseconds: tt.fields.ResourceUpdateBatchIntervalMilliseconds,
+				ResourceUpdateSubsystemQPS:              tt.fields.ResourceUpdateSubsystemQPS,
+				ResourceUpdateSubsystemBurst:            tt.fields.ResourceUpdateSubsystemBurst,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
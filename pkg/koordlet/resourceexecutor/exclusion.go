@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// ExclusionPod is a pod to match the cgroup exclusion rules.
+type ExclusionPod struct {
+	Pod *corev1.Pod
+	// CgroupDir is the pod cgroup dir relative to the cgroup root.
+	CgroupDir string
+}
+
+type cgroupExclusionRule struct {
+	name       string
+	namespaces sets.String
+	selector   labels.Selector
+	pathRegex  *regexp.Regexp
+}
+
+func newCgroupExclusionRule(rule *slov1alpha1.CgroupExclusionRule) (*cgroupExclusionRule, error) {
+	r := &cgroupExclusionRule{name: rule.Name}
+	if len(rule.Namespaces) > 0 {
+		r.namespaces = sets.NewString(rule.Namespaces...)
+	}
+	if rule.PodSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rule.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+		r.selector = selector
+	}
+	if len(rule.CgroupPathRegex) > 0 {
+		pathRegex, err := regexp.Compile(rule.CgroupPathRegex)
+		if err != nil {
+			return nil, err
+		}
+		r.pathRegex = pathRegex
+	}
+	return r, nil
+}
+
+func (r *cgroupExclusionRule) hasPodCondition() bool {
+	return r.namespaces != nil || r.selector != nil
+}
+
+// matchPod checks if the pod matches all the conditions of the rule.
+func (r *cgroupExclusionRule) matchPod(namespace string, podLabels map[string]string, cgroupDir string) bool {
+	if !r.hasPodCondition() && r.pathRegex == nil {
+		return false
+	}
+	if r.namespaces != nil && !r.namespaces.Has(namespace) {
+		return false
+	}
+	if r.selector != nil && !r.selector.Matches(labels.Set(podLabels)) {
+		return false
+	}
+	if r.pathRegex != nil && !r.pathRegex.MatchString(cgroupDir) {
+		return false
+	}
+	return true
+}
+
+// cgroupExclusion filters the cgroups which koordlet should not touch. The pods are excluded by the rules specified
+// in the NodeSLO, and the cgroups under the excluded pod dirs are skipped by the executor and the runtime hooks.
+type cgroupExclusion struct {
+	lock  sync.RWMutex
+	rules []*cgroupExclusionRule
+	// podDirs are the cgroup dirs of the excluded pods
+	podDirs sets.String
+}
+
+var defaultCgroupExclusion = &cgroupExclusion{}
+
+// UpdateCgroupExclusion updates the cgroup exclusion rules and the excluded pods.
+func UpdateCgroupExclusion(rules []slov1alpha1.CgroupExclusionRule, pods []ExclusionPod) {
+	defaultCgroupExclusion.update(rules, pods)
+}
+
+// IsPodCgroupExcluded checks if the pod is excluded from the cgroup reconciliation.
+func IsPodCgroupExcluded(namespace string, podLabels map[string]string, cgroupDir string) bool {
+	return defaultCgroupExclusion.isPodExcluded(namespace, podLabels, cgroupDir)
+}
+
+// IsCgroupDirExcluded checks if the cgroup dir is excluded from the cgroup reconciliation.
+func IsCgroupDirExcluded(cgroupDir string) bool {
+	return defaultCgroupExclusion.isDirExcluded(cgroupDir)
+}
+
+func (c *cgroupExclusion) update(rules []slov1alpha1.CgroupExclusionRule, pods []ExclusionPod) {
+	compiledRules := make([]*cgroupExclusionRule, 0, len(rules))
+	for i := range rules {
+		r, err := newCgroupExclusionRule(&rules[i])
+		if err != nil {
+			klog.Warningf("failed to parse cgroup exclusion rule %s, err: %v", rules[i].Name, err)
+			continue
+		}
+		compiledRules = append(compiledRules, r)
+	}
+
+	podDirs := sets.NewString()
+	for _, p := range pods {
+		if p.Pod == nil || len(p.CgroupDir) <= 0 {
+			continue
+		}
+		cgroupDir := normalizeCgroupDir(p.CgroupDir)
+		for _, r := range compiledRules {
+			if r.matchPod(p.Pod.Namespace, p.Pod.Labels, cgroupDir) {
+				klog.V(5).Infof("pod %s/%s is excluded from cgroup reconciliation by rule %s, cgroup dir %s",
+					p.Pod.Namespace, p.Pod.Name, r.name, cgroupDir)
+				podDirs.Insert(cgroupDir)
+				break
+			}
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rules = compiledRules
+	c.podDirs = podDirs
+}
+
+func (c *cgroupExclusion) isPodExcluded(namespace string, podLabels map[string]string, cgroupDir string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	cgroupDir = normalizeCgroupDir(cgroupDir)
+	for _, r := range c.rules {
+		if r.matchPod(namespace, podLabels, cgroupDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *cgroupExclusion) isDirExcluded(cgroupDir string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if len(c.rules) <= 0 {
+		return false
+	}
+	cgroupDir = normalizeCgroupDir(cgroupDir)
+	for dir := cgroupDir; len(dir) > 0; dir = parentCgroupDir(dir) {
+		if c.podDirs.Has(dir) {
+			return true
+		}
+	}
+	// the rules only with the path regex can also exclude the non-pod cgroups
+	for _, r := range c.rules {
+		if !r.hasPodCondition() && r.pathRegex != nil && r.pathRegex.MatchString(cgroupDir) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeCgroupDir(cgroupDir string) string {
+	return strings.Trim(cgroupDir, "/")
+}
+
+func parentCgroupDir(cgroupDir string) string {
+	if idx := strings.LastIndex(cgroupDir, "/"); idx >= 0 {
+		return cgroupDir[:idx]
+	}
+	return ""
+}
+
+func isUpdaterExcluded(updater ResourceUpdater) bool {
+	u, ok := updater.(*CgroupResourceUpdater)
+	if !ok {
+		return false
+	}
+	return IsCgroupDirExcluded(u.parentDir)
+}
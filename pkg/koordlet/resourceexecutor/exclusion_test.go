@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_cgroupExclusion(t *testing.T) {
+	rules := []slov1alpha1.CgroupExclusionRule{
+		{
+			Name:       "exclude-system-agent",
+			Namespaces: []string{"kube-system"},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "agent"},
+			},
+		},
+		{
+			Name:            "exclude-path",
+			CgroupPathRegex: "^system\\.slice/.*",
+		},
+		{
+			Name: "empty rule",
+		},
+		{
+			Name:            "invalid regex",
+			CgroupPathRegex: "[",
+		},
+	}
+	pods := []ExclusionPod{
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "kube-system",
+					Name:      "agent",
+					Labels:    map[string]string{"app": "agent"},
+				},
+			},
+			CgroupDir: "/kubepods.slice/kubepods-pod-agent.slice/",
+		},
+		{
+			Pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "kube-system",
+					Name:      "other",
+					Labels:    map[string]string{"app": "other"},
+				},
+			},
+			CgroupDir: "kubepods.slice/kubepods-pod-other.slice",
+		},
+	}
+	c := &cgroupExclusion{}
+	assert.False(t, c.isDirExcluded("kubepods.slice/kubepods-pod-agent.slice"))
+
+	c.update(rules, pods)
+	assert.Len(t, c.rules, 3)
+	assert.True(t, c.isPodExcluded("kube-system", map[string]string{"app": "agent"}, "kubepods.slice/kubepods-pod-agent.slice"))
+	assert.False(t, c.isPodExcluded("kube-system", map[string]string{"app": "other"}, "kubepods.slice/kubepods-pod-other.slice"))
+	assert.False(t, c.isPodExcluded("default", map[string]string{"app": "agent"}, "kubepods.slice/kubepods-pod-x.slice"))
+	assert.True(t, c.isPodExcluded("default", nil, "/system.slice/foo.service"))
+
+	assert.True(t, c.isDirExcluded("kubepods.slice/kubepods-pod-agent.slice"))
+	assert.True(t, c.isDirExcluded("/kubepods.slice/kubepods-pod-agent.slice/cri-containerd-abc.scope"))
+	assert.False(t, c.isDirExcluded("kubepods.slice/kubepods-pod-other.slice"))
+	assert.False(t, c.isDirExcluded("kubepods.slice"))
+	assert.True(t, c.isDirExcluded("system.slice/foo.service"))
+
+	c.update(nil, pods)
+	assert.False(t, c.isDirExcluded("kubepods.slice/kubepods-pod-agent.slice"))
+}
+
+func Test_isUpdaterExcluded(t *testing.T) {
+	defer UpdateCgroupExclusion(nil, nil)
+	UpdateCgroupExclusion([]slov1alpha1.CgroupExclusionRule{
+		{
+			Name:            "exclude-path",
+			CgroupPathRegex: "^kubepods.slice/excluded.*",
+		},
+	}, nil)
+
+	excluded, err := NewCommonCgroupUpdater(system.CPUSetCPUSName, "/kubepods.slice/excluded-pod.slice", "0-1", nil)
+	assert.NoError(t, err)
+	assert.True(t, isUpdaterExcluded(excluded))
+	included, err := NewCommonCgroupUpdater(system.CPUSetCPUSName, "/kubepods.slice/normal-pod.slice", "0-1", nil)
+	assert.NoError(t, err)
+	assert.False(t, isUpdaterExcluded(included))
+}
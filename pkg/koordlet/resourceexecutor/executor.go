@@ -17,13 +17,21 @@ limitations under the License.
 package resourceexecutor
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util/cache"
 )
@@ -31,15 +39,30 @@ import (
 var _ ResourceUpdateExecutor = &ResourceUpdateExecutorImpl{}
 
 type ResourceUpdateExecutor interface {
-	Update(cacheable bool, updater ResourceUpdater) (updated bool, err error)
-	UpdateBatch(cacheable bool, updaters ...ResourceUpdater)
+	// Update updates the resource, where the write is traced as a child span of the ctx.
+	Update(ctx context.Context, cacheable bool, updater ResourceUpdater) (updated bool, err error)
+	// UpdateBatch updates the resources in order synchronously.
+	UpdateBatch(ctx context.Context, cacheable bool, updaters ...ResourceUpdater)
+	// UpdateBatchAsync enqueues the updates to be written by the flush loop if the batching is enabled, otherwise it
+	// is the same as the UpdateBatch. It is for the callers which do not need the writes done on return, e.g. the
+	// periodic reconciliation of the runtime hooks, so their updates can be batched. The callers who require the
+	// order between the different resources should keep them in one call, since a coalesced update is moved to the
+	// tail of the queue.
+	UpdateBatchAsync(ctx context.Context, cacheable bool, updaters ...ResourceUpdater)
 	// LeveledUpdateBatch is to cacheable update resources by the order of resources' level.
 	// For cgroup interfaces like `cpuset.cpus` and `memory.min`, reconciliation from top to bottom should keep the
 	// upper value larger/broader than the lower. Thus a Leveled updater is implemented as follows:
 	// 1. update batch of cgroup resources group by cgroup interface, i.e. cgroup filename.
 	// 2. update each cgroup resource by the order of layers: firstly update resources from upper to lower by merging
 	//    the new value with old value; then update resources from lower to upper with the new value.
-	LeveledUpdateBatch(updaters [][]ResourceUpdater)
+	LeveledUpdateBatch(ctx context.Context, updaters [][]ResourceUpdater)
+	// LeveledUpdateBatchParallel is to cacheable update resources by the order of resources' level, where the
+	// independent groups of the lower levels are updated in parallel by the workers.
+	LeveledUpdateBatchParallel(ctx context.Context, top []ResourceUpdater, groups [][][]ResourceUpdater, workers int)
+	// UpdateTransaction updates a group of resources in order as a transaction, e.g. `cpuset.cpus` and `cpuset.mems`.
+	// The prior values of the resources are recorded before writing. When a write fails, the applied writes are
+	// rolled back in the reverse order and the error is returned.
+	UpdateTransaction(ctx context.Context, cacheable bool, updaters ...ResourceUpdater) error
 	Run(stopCh <-chan struct{})
 }
 
@@ -50,6 +73,9 @@ type ResourceUpdateExecutorImpl struct {
 
 	onceRun   sync.Once
 	gcStarted bool
+	// batcher queues the async batch updates when the batching is enabled
+	batcher     *updateBatcher
+	batcherLock sync.RWMutex
 }
 
 var singleton = &ResourceUpdateExecutorImpl{
@@ -62,56 +88,107 @@ func NewResourceUpdateExecutor() ResourceUpdateExecutor {
 }
 
 // Update updates the resources with the given cacheable attribute with the cacheable attribute directly.
-func (e *ResourceUpdateExecutorImpl) Update(cacheable bool, resource ResourceUpdater) (bool, error) {
+func (e *ResourceUpdateExecutorImpl) Update(ctx context.Context, cacheable bool, resource ResourceUpdater) (bool, error) {
 	if cacheable {
 		if !e.gcStarted {
 			klog.V(5).Info("failed to cacheable update resources, err: cache GC is not started")
 			return false, fmt.Errorf("cache GC is not started")
 		}
-		return e.updateByCache(resource)
+		return e.updateByCache(ctx, resource)
 	}
-	return true, e.update(resource)
+	return true, e.update(ctx, resource)
 }
 
 // UpdateBatch updates a batch of resources with the given cacheable attribute.
 // TODO: merge and resolve conflicts of batch updates from multiple callers.
-func (e *ResourceUpdateExecutorImpl) UpdateBatch(cacheable bool, updaters ...ResourceUpdater) {
+func (e *ResourceUpdateExecutorImpl) UpdateBatch(ctx context.Context, cacheable bool, updaters ...ResourceUpdater) {
+	if cacheable && !e.gcStarted {
+		klog.Error("failed to cacheable update resources, err: cache GC is not started")
+		return
+	}
+
 	failures := 0
-	if cacheable {
-		if !e.gcStarted {
-			klog.Error("failed to cacheable update resources, err: cache GC is not started")
-			return
+	for _, updater := range updaters {
+		if err := e.updateInBatch(ctx, cacheable, updater); err != nil {
+			failures++
 		}
+	}
+	klog.V(6).Infof("finished batch updating resources, isCacheable %v, total %v, failures %v",
+		cacheable, len(updaters), failures)
+}
 
-		for _, updater := range updaters {
-			isUpdated, err := e.updateByCache(updater)
-			if err != nil {
-				failures++
-				klog.V(4).Infof("failed to cacheable update resource %s to %v, isUpdated %v, err: %v",
-					updater.Key(), updater.Value(), isUpdated, err)
-				continue
-			}
+// UpdateBatchAsync enqueues the updates which are written asynchronously by the flush loop if the batching is
+// enabled, or updates them as the UpdateBatch. The flushed writes are traced as the children of the span in the ctx.
+func (e *ResourceUpdateExecutorImpl) UpdateBatchAsync(ctx context.Context, cacheable bool, updaters ...ResourceUpdater) {
+	batcher := e.getBatcher()
+	if batcher == nil {
+		e.UpdateBatch(ctx, cacheable, updaters...)
+		return
+	}
+	if cacheable && !e.gcStarted {
+		klog.Error("failed to cacheable update resources, err: cache GC is not started")
+		return
+	}
+	batcher.Add(trace.SpanContextFromContext(ctx), cacheable, updaters...)
+	klog.V(6).Infof("enqueued batch updating resources, isCacheable %v, total %v", cacheable, len(updaters))
+}
 
-			klog.V(5).Infof("successfully cacheable update resource %s to %v, isUpdated %v",
-				updater.Key(), updater.Value(), isUpdated)
+func (e *ResourceUpdateExecutorImpl) getBatcher() *updateBatcher {
+	e.batcherLock.RLock()
+	defer e.batcherLock.RUnlock()
+	return e.batcher
+}
+
+func (e *ResourceUpdateExecutorImpl) setBatcher(batcher *updateBatcher) {
+	e.batcherLock.Lock()
+	defer e.batcherLock.Unlock()
+	e.batcher = batcher
+}
+
+func (e *ResourceUpdateExecutorImpl) updateInBatch(ctx context.Context, cacheable bool, updater ResourceUpdater) error {
+	if cacheable {
+		isUpdated, err := e.updateByCache(ctx, updater)
+		if err != nil {
+			klog.V(4).Infof("failed to cacheable update resource %s to %v, isUpdated %v, err: %v",
+				updater.Key(), updater.Value(), isUpdated, err)
+			return err
 		}
-	} else {
-		for _, updater := range updaters {
-			err := e.update(updater)
-			if err != nil {
-				failures++
-				klog.V(4).Infof("failed to update resource %s to %v, err: %v", updater.Key(), updater.Value(), err)
-				continue
-			}
+		klog.V(5).Infof("successfully cacheable update resource %s to %v, isUpdated %v",
+			updater.Key(), updater.Value(), isUpdated)
+		return nil
+	}
+
+	err := e.update(ctx, updater)
+	if err != nil {
+		klog.V(4).Infof("failed to update resource %s to %v, err: %v", updater.Key(), updater.Value(), err)
+		return err
+	}
+	klog.V(5).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
+	return nil
+}
 
-			klog.V(5).Infof("successfully update resource %s to %v", updater.Key(), updater.Value())
+// flushBatch writes the pending batch updates allowed by the rate limiters.
+func (e *ResourceUpdateExecutorImpl) flushBatch() {
+	batcher := e.getBatcher()
+	if batcher == nil {
+		return
+	}
+	items := batcher.Pop()
+	if len(items) <= 0 {
+		return
+	}
+	failures := 0
+	for _, item := range items {
+		ctx := trace.ContextWithSpanContext(context.Background(), item.spanContext)
+		if err := e.updateInBatch(ctx, item.cacheable, item.updater); err != nil {
+			failures++
 		}
 	}
-	klog.V(6).Infof("finished batch updating resources, isCacheable %v, total %v, failures %v",
-		cacheable, len(updaters), failures)
+	klog.V(6).Infof("finished flushing batch updates, total %v, failures %v, remaining %v",
+		len(items), failures, batcher.Len())
 }
 
-func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpdater) {
+func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(ctx context.Context, updaters [][]ResourceUpdater) {
 	e.LeveledUpdateLock.Lock()
 	defer e.LeveledUpdateLock.Unlock()
 	if !e.gcStarted {
@@ -119,15 +196,55 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 		return
 	}
 
-	var err error
 	skipMerge := map[string]bool{}
+	e.leveledMerge(updaters, skipMerge)
+	e.leveledUpdate(ctx, updaters, skipMerge)
+}
+
+// LeveledUpdateBatchParallel is like the LeveledUpdateBatch, while the lower levels are divided into the independent
+// groups which are updated by the parallel workers, e.g. the pod-level and container-level resources of each pod.
+// The resources of a group are updated in the leveled order by one worker, where the top level is merged before all
+// groups and updated after all groups.
+func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatchParallel(ctx context.Context, top []ResourceUpdater, groups [][][]ResourceUpdater, workers int) {
+	e.LeveledUpdateLock.Lock()
+	defer e.LeveledUpdateLock.Unlock()
+	if !e.gcStarted {
+		klog.Error("failed to cacheable level update resources, err: cache GC is not started")
+		return
+	}
+
+	topUpdaters := [][]ResourceUpdater{top}
+	skipMerge := map[string]bool{}
+	e.leveledMerge(topUpdaters, skipMerge)
+	workqueue.ParallelizeUntil(ctx, workers, len(groups), func(i int) {
+		groupSkipMerge := map[string]bool{}
+		e.leveledMerge(groups[i], groupSkipMerge)
+		e.leveledUpdate(ctx, groups[i], groupSkipMerge)
+	})
+	e.leveledUpdate(ctx, topUpdaters, skipMerge)
+}
+
+// leveledMerge merges the new values with the old values of the resources from the upper level to the lower level.
+// The keys of the resources which should not be updated twice are recorded in the skipMerge.
+func (e *ResourceUpdateExecutorImpl) leveledMerge(updaters [][]ResourceUpdater, skipMerge map[string]bool) {
 	for i := 0; i < len(updaters); i++ {
 		for _, updater := range updaters[i] {
+			if isUpdaterExcluded(updater) {
+				klog.V(5).Infof("skip update resource %s since the cgroup is excluded", updater.Key())
+				continue
+			}
 			if !e.needUpdate(updater) {
 				continue
 			}
 
-			mergedUpdater, err := updater.MergeUpdate()
+			var mergedUpdater ResourceUpdater
+			var err error
+			if e.isDryRun(updater) {
+				// record the update once, and it is skipped in the updates from the lower to the upper
+				recordDryRunUpdate(updater)
+			} else {
+				mergedUpdater, err = updater.MergeUpdate()
+			}
 			if err != nil && e.isUpdateErrIgnored(err) {
 				klog.V(5).Infof("failed to merge update resource %s to %v, ignored err: %v",
 					updater.Key(), updater.Value(), err)
@@ -154,9 +271,17 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 			}
 		}
 	}
+}
 
+// leveledUpdate updates the resources with the new values from the lower level to the upper level.
+func (e *ResourceUpdateExecutorImpl) leveledUpdate(ctx context.Context, updaters [][]ResourceUpdater, skipMerge map[string]bool) {
+	var err error
 	for i := len(updaters) - 1; i >= 0; i-- {
 		for _, updater := range updaters[i] {
+			if isUpdaterExcluded(updater) {
+				klog.V(5).Infof("skip update resource %s since the cgroup is excluded", updater.Key())
+				continue
+			}
 			if !e.needUpdate(updater) {
 				continue
 			}
@@ -166,7 +291,12 @@ func (e *ResourceUpdateExecutorImpl) LeveledUpdateBatch(updaters [][]ResourceUpd
 				klog.V(6).Infof("skip update resource %s since it should skip the merge", updater.Key())
 				continue
 			}
-			err = updater.update()
+			if e.isDryRun(updater) {
+				recordDryRunUpdate(updater)
+				err = nil
+			} else {
+				err = tracedUpdate(ctx, updater)
+			}
 			if err != nil && e.isUpdateErrIgnored(err) {
 				klog.V(5).Infof("failed to update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
 				continue
@@ -196,6 +326,13 @@ func (e *ResourceUpdateExecutorImpl) Run(stopCh <-chan struct{}) {
 
 func (e *ResourceUpdateExecutorImpl) run(stopCh <-chan struct{}) {
 	_ = e.ResourceCache.Run(stopCh)
+	if e.Config != nil && e.Config.ResourceUpdateBatchIntervalMilliseconds > 0 {
+		// the batcher is set before the gc started, so the cacheable updates are not enqueued before it is ready
+		e.setBatcher(newUpdateBatcher(e.Config.ResourceUpdateSubsystemQPS, e.Config.ResourceUpdateSubsystemBurst))
+		go wait.Until(e.flushBatch, time.Duration(e.Config.ResourceUpdateBatchIntervalMilliseconds)*time.Millisecond, stopCh)
+		klog.V(4).Infof("resource batch update is enabled, flush interval %vms",
+			e.Config.ResourceUpdateBatchIntervalMilliseconds)
+	}
 	klog.V(4).Info("starting ResourceUpdateExecutor successfully")
 	e.gcStarted = true
 }
@@ -220,9 +357,17 @@ func (e *ResourceUpdateExecutorImpl) needUpdate(updater ResourceUpdater) bool {
 	return false
 }
 
-func (e *ResourceUpdateExecutorImpl) update(updater ResourceUpdater) error {
+func (e *ResourceUpdateExecutorImpl) update(ctx context.Context, updater ResourceUpdater) error {
+	if isUpdaterExcluded(updater) {
+		klog.V(5).Infof("skip update resource %s since the cgroup is excluded", updater.Key())
+		return nil
+	}
+	if e.isDryRun(updater) {
+		recordDryRunUpdate(updater)
+		return nil
+	}
 	start := time.Now()
-	err := updater.update()
+	err := tracedUpdate(ctx, updater)
 	if err != nil && !e.isUpdateErrIgnored(err) {
 		metrics.RecordResourceUpdateDuration(updater.Name(), metrics.ResourceUpdateStatusFailed, metrics.SinceInSeconds(start))
 		klog.V(5).Infof("failed to update resource %s to %v, err: %v", updater.Key(), updater.Value(), err)
@@ -237,10 +382,20 @@ func (e *ResourceUpdateExecutorImpl) update(updater ResourceUpdater) error {
 	return nil
 }
 
-func (e *ResourceUpdateExecutorImpl) updateByCache(updater ResourceUpdater) (bool, error) {
+func (e *ResourceUpdateExecutorImpl) updateByCache(ctx context.Context, updater ResourceUpdater) (bool, error) {
+	if isUpdaterExcluded(updater) {
+		klog.V(5).Infof("skip cacheable update resource %s since the cgroup is excluded", updater.Key())
+		return false, nil
+	}
 	if e.needUpdate(updater) {
 		start := time.Now()
-		err := updater.update()
+		var err error
+		if e.isDryRun(updater) {
+			// the dry-run update is cached as well, so it is not recorded again until the value changes
+			recordDryRunUpdate(updater)
+		} else {
+			err = tracedUpdate(ctx, updater)
+		}
 		if err != nil && e.isUpdateErrIgnored(err) {
 			klog.V(5).Infof("failed to cacheable update resource %s to %v, ignored err: %v", updater.Key(), updater.Value(), err)
 			return false, nil
@@ -263,6 +418,54 @@ func (e *ResourceUpdateExecutorImpl) updateByCache(updater ResourceUpdater) (boo
 	return false, nil
 }
 
+// tracedUpdate updates the resource in a child span of the ctx, so the slow writes can be located in the rounds.
+func tracedUpdate(ctx context.Context, updater ResourceUpdater) error {
+	_, span := tracing.StartSpan(ctx, tracing.ModuleResourceExecutor, "update",
+		attribute.String("updater", updater.Name()),
+		attribute.String("resource", updater.Key()),
+		attribute.String("value", updater.Value()))
+	err := updater.update()
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// isDryRun checks if the update of the resource should be recorded without applying.
+func (e *ResourceUpdateExecutorImpl) isDryRun(updater ResourceUpdater) bool {
+	return isDryRunPath(e.Config, updater.Path())
+}
+
+// isDryRunPath checks if the write or the removal of the path should be recorded without applying.
+func isDryRunPath(config *Config, path string) bool {
+	if config == nil {
+		return false
+	}
+	if config.ResourceUpdateDryRun {
+		return true
+	}
+	if len(config.ResourceUpdateDryRunFiles) <= 0 {
+		return false
+	}
+	fileName := filepath.Base(path)
+	for _, name := range config.ResourceUpdateDryRunFiles {
+		if name == fileName {
+			return true
+		}
+	}
+	return false
+}
+
+// recordDryRunUpdate logs and audits the update which would be performed.
+func recordDryRunUpdate(updater ResourceUpdater) {
+	klog.V(4).Infof("dry-run update resource %s to %v, path %s", updater.Key(), updater.Value(), updater.Path())
+	_ = audit.V(3).Reason(DryRunUpdateResource).Message("dry-run update %v to %v", updater.Path(), updater.Value()).Do()
+}
+
+// recordDryRunGroupChange logs and audits the creation or the removal of the resctrl group which would be performed.
+func recordDryRunGroupChange(action string, path string) {
+	klog.V(4).Infof("dry-run %s resctrl group, path %s", action, path)
+	_ = audit.V(3).Reason(DryRunUpdateResource).Message("dry-run %s resctrl group %v", action, path).Do()
+}
+
 func (e *ResourceUpdateExecutorImpl) isUpdateErrIgnored(err error) bool {
 	if err == nil {
 		return true
@@ -17,6 +17,7 @@ limitations under the License.
 package resourceexecutor
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -162,7 +163,7 @@ func TestResourceUpdateExecutor_Update(t *testing.T) {
 				e.Run(stop)
 			}
 
-			got, gotErr := e.Update(tt.args.isCacheable, tt.args.resource)
+			got, gotErr := e.Update(context.TODO(), tt.args.isCacheable, tt.args.resource)
 			assert.Equal(t, tt.want, got)
 			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
 		})
@@ -242,7 +243,166 @@ func TestResourceUpdateExecutor_UpdateBatch(t *testing.T) {
 				e.Run(stop)
 			}
 
-			e.UpdateBatch(tt.args.isCacheable, tt.args.resources...)
+			e.UpdateBatch(context.TODO(), tt.args.isCacheable, tt.args.resources...)
 		})
 	}
 }
+
+func TestResourceUpdateExecutor_LeveledUpdateBatchParallel(t *testing.T) {
+	type podCgroup struct {
+		podDir         string
+		podValue       string
+		containerDir   string
+		containerValue string
+	}
+	type fields struct {
+		notStarted bool
+		qosDir     string
+		qosValue   string
+		pods       []podCgroup
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		workers int
+		want    map[string]string
+	}{
+		{
+			name: "abort update when GC is not started",
+			fields: fields{
+				notStarted: true,
+				qosDir:     "kubepods.slice/kubepods-burstable.slice",
+				qosValue:   "2048",
+			},
+			workers: 2,
+			want: map[string]string{
+				"kubepods.slice/kubepods-burstable.slice": "1024",
+			},
+		},
+		{
+			name: "update qos-level and pods' resources in parallel",
+			fields: fields{
+				qosDir:   "kubepods.slice/kubepods-burstable.slice",
+				qosValue: "4096",
+				pods: []podCgroup{
+					{
+						podDir:         "kubepods.slice/kubepods-burstable.slice/pod-1",
+						podValue:       "2048",
+						containerDir:   "kubepods.slice/kubepods-burstable.slice/pod-1/container-1",
+						containerValue: "2048",
+					},
+					{
+						podDir:         "kubepods.slice/kubepods-burstable.slice/pod-2",
+						podValue:       "512",
+						containerDir:   "kubepods.slice/kubepods-burstable.slice/pod-2/container-2",
+						containerValue: "256",
+					},
+					{
+						podDir:         "kubepods.slice/kubepods-burstable.slice/pod-3",
+						podValue:       "4",
+						containerDir:   "kubepods.slice/kubepods-burstable.slice/pod-3/container-3",
+						containerValue: "2",
+					},
+				},
+			},
+			workers: 2,
+			want: map[string]string{
+				"kubepods.slice/kubepods-burstable.slice":                   "4096",
+				"kubepods.slice/kubepods-burstable.slice/pod-1":             "2048",
+				"kubepods.slice/kubepods-burstable.slice/pod-1/container-1": "2048",
+				"kubepods.slice/kubepods-burstable.slice/pod-2":             "512",
+				"kubepods.slice/kubepods-burstable.slice/pod-2/container-2": "256",
+				"kubepods.slice/kubepods-burstable.slice/pod-3":             "4",
+				"kubepods.slice/kubepods-burstable.slice/pod-3/container-3": "2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			e := &ResourceUpdateExecutorImpl{
+				ResourceCache: cache.NewCacheDefault(),
+				Config:        NewDefaultConfig(),
+			}
+			if !tt.fields.notStarted {
+				stop := make(chan struct{})
+				defer func() {
+					close(stop)
+				}()
+
+				e.Run(stop)
+			}
+
+			for dir := range tt.want {
+				helper.WriteCgroupFileContents(dir, sysutil.CPUShares, "1024")
+			}
+			qosUpdater, err := NewMergeableCgroupUpdaterIfValueLarger(sysutil.CPUSharesName, tt.fields.qosDir, tt.fields.qosValue, &audit.EventHelper{})
+			assert.NoError(t, err)
+			var groups [][][]ResourceUpdater
+			for _, pod := range tt.fields.pods {
+				podUpdater, err := NewMergeableCgroupUpdaterIfValueLarger(sysutil.CPUSharesName, pod.podDir, pod.podValue, &audit.EventHelper{})
+				assert.NoError(t, err)
+				containerUpdater, err := NewMergeableCgroupUpdaterIfValueLarger(sysutil.CPUSharesName, pod.containerDir, pod.containerValue, &audit.EventHelper{})
+				assert.NoError(t, err)
+				groups = append(groups, [][]ResourceUpdater{{podUpdater}, {containerUpdater}})
+			}
+
+			e.LeveledUpdateBatchParallel(context.TODO(), []ResourceUpdater{qosUpdater}, groups, tt.workers)
+			for dir, want := range tt.want {
+				assert.Equal(t, want, helper.ReadCgroupFileContents(dir, sysutil.CPUShares), dir)
+			}
+		})
+	}
+}
+
+func TestResourceUpdateExecutor_DryRun(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	qosDir := "kubepods.slice/kubepods-burstable.slice"
+	podDir := "kubepods.slice/kubepods-burstable.slice/pod-1"
+	for _, dir := range []string{qosDir, podDir} {
+		helper.WriteCgroupFileContents(dir, sysutil.CPUShares, "1024")
+		helper.WriteCgroupFileContents(dir, sysutil.CPUCFSQuota, "-1")
+	}
+
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	e.Config.ResourceUpdateDryRunFiles = []string{sysutil.CPUSharesName}
+	stop := make(chan struct{})
+	defer close(stop)
+	e.Run(stop)
+
+	// only the resources of the dry-run files are not applied
+	sharesUpdater, err := NewCommonCgroupUpdater(sysutil.CPUSharesName, podDir, "2048", nil)
+	assert.NoError(t, err)
+	updated, err := e.Update(context.TODO(), true, sharesUpdater)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(podDir, sysutil.CPUShares))
+	updated, err = e.Update(context.TODO(), true, sharesUpdater)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	quotaUpdater, err := NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, podDir, "100000", nil)
+	assert.NoError(t, err)
+	_, err = e.Update(context.TODO(), false, quotaUpdater)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000", helper.ReadCgroupFileContents(podDir, sysutil.CPUCFSQuota))
+
+	// all resources are not applied in the global dry-run mode
+	e.Config.ResourceUpdateDryRun = true
+	quotaUpdater, err = NewCommonCgroupUpdater(sysutil.CPUCFSQuotaName, podDir, "200000", nil)
+	assert.NoError(t, err)
+	_, err = e.Update(context.TODO(), false, quotaUpdater)
+	assert.NoError(t, err)
+	assert.Equal(t, "100000", helper.ReadCgroupFileContents(podDir, sysutil.CPUCFSQuota))
+	qosUpdater, err := NewMergeableCgroupUpdaterIfValueLarger(sysutil.CPUSharesName, qosDir, "4096", nil)
+	assert.NoError(t, err)
+	podUpdater, err := NewMergeableCgroupUpdaterIfValueLarger(sysutil.CPUSharesName, podDir, "4096", nil)
+	assert.NoError(t, err)
+	e.LeveledUpdateBatch(context.TODO(), [][]ResourceUpdater{{qosUpdater}, {podUpdater}})
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(qosDir, sysutil.CPUShares))
+	assert.Equal(t, "1024", helper.ReadCgroupFileContents(podDir, sysutil.CPUShares))
+}
@@ -42,6 +42,8 @@ type CgroupReader interface {
 	ReadPSI(parentDir string) (*sysutil.PSIByResource, error)
 	ReadMemoryColdPageUsage(parentDir string) (uint64, error)
 	ReadNetClsId(parentDir string) (uint32, error)
+	ReadRDMACurrent(parentDir string) (map[string]sysutil.RDMAResourceCount, error)
+	ReadIOStat(parentDir string) (sysutil.IOStatRaw, error)
 }
 
 var _ CgroupReader = &CgroupV1Reader{}
@@ -248,6 +250,48 @@ func (r *CgroupV1Reader) ReadNetClsId(parentDir string) (uint32, error) {
 	return readCgroupAndParseUint32(parentDir, resource)
 }
 
+func (r *CgroupV1Reader) ReadRDMACurrent(parentDir string) (map[string]sysutil.RDMAResourceCount, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.RDMACurrentName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return nil, err
+	}
+	// `mlx5_0 hca_handle=2 hca_object=2000`
+	v, err := sysutil.ParseRDMACurrent(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cgroup value %s, err: %v", s, err)
+	}
+	return v, nil
+}
+
+func (r *CgroupV1Reader) ReadIOStat(parentDir string) (sysutil.IOStatRaw, error) {
+	servicedResource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.BlkioIOServicedName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	serviceBytesResource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV1, sysutil.BlkioIOServiceBytesName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	serviced, err := cgroupFileRead(parentDir, servicedResource)
+	if err != nil {
+		return nil, err
+	}
+	serviceBytes, err := cgroupFileRead(parentDir, serviceBytesResource)
+	if err != nil {
+		return nil, err
+	}
+	// `8:0 Read 1000\n8:0 Write 2000\n...\nTotal 3000`
+	v, err := sysutil.ParseBlkioThrottleStat(serviced, serviceBytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cgroup value %s, %s, err: %v", serviced, serviceBytes, err)
+	}
+	return v, nil
+}
+
 var _ CgroupReader = &CgroupV2Reader{}
 
 type CgroupV2Reader struct{}
@@ -470,6 +514,40 @@ func (r *CgroupV2Reader) ReadNetClsId(parentDir string) (uint32, error) {
 	return readCgroupAndParseUint32(parentDir, resource)
 }
 
+func (r *CgroupV2Reader) ReadRDMACurrent(parentDir string) (map[string]sysutil.RDMAResourceCount, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.RDMACurrentName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return nil, err
+	}
+	// `mlx5_0 hca_handle=2 hca_object=2000`
+	v, err := sysutil.ParseRDMACurrent(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cgroup value %s, err: %v", s, err)
+	}
+	return v, nil
+}
+
+func (r *CgroupV2Reader) ReadIOStat(parentDir string) (sysutil.IOStatRaw, error) {
+	resource, ok := sysutil.DefaultRegistry.Get(sysutil.CgroupVersionV2, sysutil.IOStatName)
+	if !ok {
+		return nil, ErrResourceNotRegistered
+	}
+	s, err := cgroupFileRead(parentDir, resource)
+	if err != nil {
+		return nil, err
+	}
+	// `8:0 rbytes=1000 wbytes=2000 rios=10 wios=20 dbytes=0 dios=0`
+	v, err := sysutil.ParseIOStatV2(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse cgroup value %s, err: %v", s, err)
+	}
+	return v, nil
+}
+
 func NewCgroupReader() CgroupReader {
 	if sysutil.GetCurrentCgroupVersion() == sysutil.CgroupVersionV2 {
 		return &CgroupV2Reader{}
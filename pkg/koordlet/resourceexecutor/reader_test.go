@@ -1559,3 +1559,93 @@ func TestCgroupReader_ReadColdPageUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestCgroupReader_ReadIOStat(t *testing.T) {
+	type fields struct {
+		UseCgroupsV2        bool
+		IOServicedValue     string
+		IOServiceBytesValue string
+		IOStatValue         string
+	}
+	type args struct {
+		parentDir string
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		args    args
+		want    sysutil.IOStatRaw
+		wantErr bool
+	}{
+		{
+			name: "parse v1 value successfully",
+			fields: fields{
+				IOServicedValue:     "8:0 Read 100\n8:0 Write 200\n8:0 Sync 300\n8:0 Async 0\n8:0 Discard 0\n8:0 Total 300\nTotal 300\n",
+				IOServiceBytesValue: "8:0 Read 409600\n8:0 Write 819200\n8:0 Sync 1228800\n8:0 Async 0\n8:0 Discard 0\n8:0 Total 1228800\nTotal 1228800\n",
+			},
+			args: args{
+				parentDir: "/kubepods.slice",
+			},
+			want: sysutil.IOStatRaw{
+				"8:0": {ReadBytes: 409600, WriteBytes: 819200, ReadIOs: 100, WriteIOs: 200},
+			},
+			wantErr: false,
+		},
+		{
+			name: "v1 path not exist",
+			fields: fields{
+				IOServicedValue: "8:0 Read 100\n8:0 Write 200\nTotal 300\n",
+			},
+			args: args{
+				parentDir: "/kubepods.slice",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "parse v2 value successfully",
+			fields: fields{
+				UseCgroupsV2: true,
+				IOStatValue:  "8:0 rbytes=409600 wbytes=819200 rios=100 wios=200 dbytes=0 dios=0\n",
+			},
+			args: args{
+				parentDir: "/kubepods.slice",
+			},
+			want: sysutil.IOStatRaw{
+				"8:0": {ReadBytes: 409600, WriteBytes: 819200, ReadIOs: 100, WriteIOs: 200},
+			},
+			wantErr: false,
+		},
+		{
+			name: "parse v2 value failed",
+			fields: fields{
+				UseCgroupsV2: true,
+				IOStatValue:  "8:0 rbytes=abc\n",
+			},
+			args: args{
+				parentDir: "/kubepods.slice",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.fields.UseCgroupsV2)
+			if tt.fields.IOServicedValue != "" {
+				helper.WriteCgroupFileContents(tt.args.parentDir, sysutil.BlkioIOServiced, tt.fields.IOServicedValue)
+			}
+			if tt.fields.IOServiceBytesValue != "" {
+				helper.WriteCgroupFileContents(tt.args.parentDir, sysutil.BlkioIOServiceBytes, tt.fields.IOServiceBytesValue)
+			}
+			if tt.fields.IOStatValue != "" {
+				helper.WriteCgroupFileContents(tt.args.parentDir, sysutil.IOStatV2, tt.fields.IOStatValue)
+			}
+			got, gotErr := NewCgroupReader().ReadIOStat(tt.args.parentDir)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
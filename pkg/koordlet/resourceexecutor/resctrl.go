@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"k8s.io/klog/v2"
 
@@ -32,23 +33,84 @@ import (
 const ErrResctrlDir = "resctrl path or file not exist"
 const CacheIdIndex = 2
 
+// NewResctrlReaderFunc creates a ResctrlReader for a cpu vendor.
+type NewResctrlReaderFunc func() ResctrlReader
+
+var (
+	resctrlReaderRegistryLock sync.RWMutex
+	// resctrlReaderRegistry maps the cpu vendor id to the ResctrlReader generator
+	resctrlReaderRegistry = map[string]NewResctrlReaderFunc{}
+)
+
+func init() {
+	// Support Intel RDT, AMD QoS and ARM MPAM; other platforms can register their implementation of the
+	// resctrl interface via RegisterResctrlReader.
+	RegisterResctrlReader(system.INTEL_VENDOR_ID, NewResctrlRDTReader)
+	RegisterResctrlReader(system.AMD_VENDOR_ID, NewResctrlQoSReader)
+	for vendorID := range system.ARM_VENDOR_ID_MAP {
+		RegisterResctrlReader(vendorID, NewResctrlMPAMReader)
+	}
+}
+
+// RegisterResctrlReader registers the ResctrlReader generator of the cpu vendor, e.g. `HygonGenuine`.
+// It is expected to be called at init time, and the later registration overrides the former one.
+func RegisterResctrlReader(vendorID string, fn NewResctrlReaderFunc) {
+	resctrlReaderRegistryLock.Lock()
+	defer resctrlReaderRegistryLock.Unlock()
+	if _, ok := resctrlReaderRegistry[vendorID]; ok {
+		klog.V(4).Infof("resctrl reader of vendor %s is overridden", vendorID)
+	}
+	resctrlReaderRegistry[vendorID] = fn
+}
+
+func getResctrlReaderFunc(vendorID string) (NewResctrlReaderFunc, bool) {
+	resctrlReaderRegistryLock.RLock()
+	defer resctrlReaderRegistryLock.RUnlock()
+	fn, ok := resctrlReaderRegistry[vendorID]
+	return fn, ok
+}
+
+// IsResctrlReaderAvailable checks if the cpu vendor of the node has a registered ResctrlReader, or the reader of the
+// unknown vendor can be probed by the resctrl info.
+func IsResctrlReaderAvailable() bool {
+	vendorID, err := system.GetVendorIDByCPUInfo(system.GetCPUInfoPath())
+	if err != nil {
+		return false
+	}
+	if _, ok := getResctrlReaderFunc(vendorID); ok {
+		return true
+	}
+	isMonAvailable, err := system.IsResctrlMonAvailableByResctrlInfo()
+	return err == nil && isMonAvailable
+}
+
 // NewResctrlReader: lazy resctrl reader, just check vendor to generate specific reader
 func NewResctrlReader() ResctrlReader {
-	// Support two main platforms; other platforms need to add their implementation of the resctrl interface.
-	if vendorId, err := system.GetVendorIDByCPUInfo(system.GetCPUInfoPath()); err != nil {
+	vendorID, err := system.GetVendorIDByCPUInfo(system.GetCPUInfoPath())
+	if err != nil {
 		klog.V(0).ErrorS(err, "get cpu vendor error, stop start resctrl collector")
 		return &fakeReader{}
-	} else {
-		switch vendorId {
-		case system.INTEL_VENDOR_ID:
-			return NewResctrlRDTReader()
-		case system.AMD_VENDOR_ID:
-			return NewResctrlQoSReader()
-		default:
-			klog.V(0).ErrorS(err, "unsupported cpu vendor")
-		}
 	}
-	return &fakeReader{}
+	if fn, ok := getResctrlReaderFunc(vendorID); ok {
+		return fn()
+	}
+	klog.V(4).Infof("no resctrl reader registered for cpu vendor %s, probe by the resctrl info", vendorID)
+	return probeResctrlReader()
+}
+
+// probeResctrlReader picks a reader by the capabilities in the resctrl info for the unknown vendors.
+// The monitor must support the llc occupancy, e.g. /sys/fs/resctrl/info/L3_MON/mon_features, and the reader of MPAM
+// is picked when the memory bandwidth domains like `mon_MB_00` exist.
+func probeResctrlReader() ResctrlReader {
+	if isMonAvailable, err := system.IsResctrlMonAvailableByResctrlInfo(); err != nil || !isMonAvailable {
+		klog.V(0).Infof("unsupported cpu vendor, resctrl monitor is unavailable, err: %v", err)
+		return &fakeReader{}
+	}
+	mbDomains, err := filepath.Glob(filepath.Join(system.GetResctrlMonDataPath(""), system.ResctrlMonMBDirPrefix+"_*"))
+	if err == nil && len(mbDomains) > 0 {
+		return NewResctrlMPAMReader()
+	}
+	return NewResctrlRDTReader()
 }
 
 type CacheId int
@@ -57,6 +119,8 @@ type CacheId int
 type ResctrlReader interface {
 	ReadResctrlL3Stat(parent string) (map[CacheId]uint64, error)
 	ReadResctrlMBStat(parent string) (map[CacheId]system.MBStatData, error)
+	// ReadResctrlL2Stat reads the l2 occupancy, the key is the l2 cache id.
+	ReadResctrlL2Stat(parent string) (map[CacheId]uint64, error)
 }
 
 type ResctrlBaseReader struct {
@@ -69,6 +133,11 @@ type ResctrlAMDReader struct {
 	ResctrlBaseReader
 }
 
+// ResctrlMPAMReader reads the resctrl-compatible interface of the ARM MPAM.
+type ResctrlMPAMReader struct {
+	ResctrlBaseReader
+}
+
 type fakeReader struct {
 	ResctrlBaseReader
 }
@@ -81,6 +150,10 @@ func (rr *fakeReader) ReadResctrlMBStat(parent string) (map[CacheId]system.MBSta
 	return nil, errors.New("unsupported platform")
 }
 
+func (rr *fakeReader) ReadResctrlL2Stat(parent string) (map[CacheId]uint64, error) {
+	return nil, errors.New("unsupported platform")
+}
+
 func NewResctrlRDTReader() ResctrlReader {
 	return &ResctrlRDTReader{}
 }
@@ -89,83 +162,139 @@ func NewResctrlQoSReader() ResctrlReader {
 	return &ResctrlAMDReader{}
 }
 
+func NewResctrlMPAMReader() ResctrlReader {
+	return &ResctrlMPAMReader{}
+}
+
 // ReadResctrlL3Stat: Reads the resctrl L3 cache statistics based on NUMA domain.
 // For more information about x86 resctrl, refer to: https://docs.kernel.org/arch/x86/resctrl.html
 func (rr *ResctrlBaseReader) ReadResctrlL3Stat(parent string) (map[CacheId]uint64, error) {
-	l3Stat := make(map[CacheId]uint64)
-	monDataPath := system.GetResctrlMonDataPath(parent)
-	fd, err := os.Open(monDataPath)
-	if err != nil {
-		return nil, errors.New(ErrResctrlDir)
-	}
-	defer fd.Close()
-	// read all l3-memory domains
-	domains, err := fd.ReadDir(-1)
-	if err != nil {
-		return nil, fmt.Errorf("%s, cannot find L3 domains, err: %w", ErrResctrlDir, err)
-	}
-	for _, domain := range domains {
-		// Convert the cache ID from the domain name string to an integer.
-		cacheId, err := strconv.Atoi(strings.Split(domain.Name(), "_")[CacheIdIndex])
-		if err != nil {
-			return nil, fmt.Errorf("%s, cannot get cacheid, err: %w", ErrResctrlDir, err)
-		}
-		// Construct the path to the resctrl L3 cache occupancy file.
-		path := system.ResctrlLLCOccupancy.Path(filepath.Join(parent, system.ResctrlMonData, domain.Name()))
-		l3Byte, err := os.ReadFile(path)
-		if err != nil {
-			return nil, fmt.Errorf("%s, cannot read from resctrl file system, err: %w",
-				ErrResctrlDir, err)
-		}
-		// Parse the L3 cache usage data from the file content.
-		l3Usage, err := strconv.ParseUint(string(l3Byte), 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("cannot parse L3 cache usage, err: %w", err)
-		}
-		l3Stat[CacheId(cacheId)] = l3Usage
-	}
-	return l3Stat, nil
+	return readResctrlOccupancy(parent, system.ResctrlMonL3DirPrefix)
 }
 
 // ReadResctrlMBStat: Reads the resctrl memory bandwidth statistics based on NUMA domain.
 // For more information about x86 resctrl, refer to: https://docs.kernel.org/arch/x86/resctrl.html
 func (rr *ResctrlBaseReader) ReadResctrlMBStat(parent string) (map[CacheId]system.MBStatData, error) {
-	mbStat := make(map[CacheId]system.MBStatData)
-	monDataPath := system.GetResctrlMonDataPath(parent)
-	fd, err := os.Open(monDataPath)
+	// read all l3-memory domains, the l2 domains may also exist in the mon_data
+	domains, err := readResctrlMonDomains(parent, system.ResctrlMonL3DirPrefix)
 	if err != nil {
-		return nil, errors.New(ErrResctrlDir)
+		return nil, err
 	}
-	// read all l3-memory domains
-	domains, err := fd.ReadDir(-1)
-	if err != nil {
-		return nil, fmt.Errorf("%s, cannot find L3 domains, err: %w", ErrResctrlDir, err)
-	}
-	for _, domain := range domains {
-		// Parse the L3 cache usage data from the file content.
-		cacheId, err := strconv.Atoi(strings.Split(domain.Name(), "_")[CacheIdIndex])
-		if err != nil {
-			return nil, fmt.Errorf("%s, cannot get cacheid, err: %w", ErrResctrlDir, err)
-		}
-		mbStat[CacheId(cacheId)] = make(system.MBStatData)
+	mbStat := make(map[CacheId]system.MBStatData)
+	for cacheId, domain := range domains {
+		mbStat[cacheId] = make(system.MBStatData)
 		// Read the memory bandwidth statistics for the local and total memory bandwidth.
 		// The local memory bandwidth is the memory bandwidth consumed by the domain itself.
 		// The total memory bandwidth is the memory bandwidth consumed by the domain and accessed by other domains.
 		for _, mbResource := range []system.Resource{
 			system.ResctrlMBLocal, system.ResctrlMBTotal,
 		} {
-			contentName := mbResource.Path(filepath.Join(parent, system.ResctrlMonData, domain.Name()))
-			contentByte, err := os.ReadFile(contentName)
+			mbUsage, err := readResctrlMonValue(mbResource.Path(filepath.Join(parent, system.ResctrlMonData, domain)))
 			if err != nil {
-				return nil, fmt.Errorf("%s, cannot read from resctrl file system, err: %w",
-					ErrResctrlDir, err)
+				return nil, err
 			}
-			mbUsage, err := strconv.ParseUint(string(contentByte), 10, 64)
+			mbStat[cacheId][string(mbResource.ResourceType())] = mbUsage
+		}
+	}
+	return mbStat, nil
+}
+
+// ReadResctrlL2Stat: Reads the resctrl L2 cache occupancy based on the L2 monitoring domains, e.g. `mon_L2_00`.
+// It returns an empty result if the platform does not support the L2 occupancy monitoring.
+func (rr *ResctrlBaseReader) ReadResctrlL2Stat(parent string) (map[CacheId]uint64, error) {
+	return readResctrlOccupancy(parent, system.ResctrlMonL2DirPrefix)
+}
+
+// ReadResctrlL3Stat: Reads the MPAM L3 cache storage usage based on the L3 monitoring domains.
+// Different from x86, the mon_data of MPAM can contain the memory bandwidth domains like `mon_MB_00`.
+func (rr *ResctrlMPAMReader) ReadResctrlL3Stat(parent string) (map[CacheId]uint64, error) {
+	return readResctrlOccupancy(parent, system.ResctrlMonL3DirPrefix)
+}
+
+// ReadResctrlMBStat: Reads the MPAM memory bandwidth usage. The bandwidth monitors are read from the memory bandwidth
+// domains if provided, otherwise from the L3 domains. The MPAM may only support the total memory bandwidth.
+func (rr *ResctrlMPAMReader) ReadResctrlMBStat(parent string) (map[CacheId]system.MBStatData, error) {
+	domains, err := readResctrlMonDomains(parent, system.ResctrlMonMBDirPrefix)
+	if err != nil {
+		return nil, err
+	}
+	if len(domains) <= 0 {
+		domains, err = readResctrlMonDomains(parent, system.ResctrlMonL3DirPrefix)
+		if err != nil {
+			return nil, err
+		}
+	}
+	mbStat := make(map[CacheId]system.MBStatData)
+	for cacheId, domain := range domains {
+		mbStat[cacheId] = make(system.MBStatData)
+		for _, mbResource := range []system.Resource{
+			system.ResctrlMBLocal, system.ResctrlMBTotal,
+		} {
+			path := mbResource.Path(filepath.Join(parent, system.ResctrlMonData, domain))
+			if mbResource == system.ResctrlMBLocal && !system.FileExists(path) {
+				continue
+			}
+			mbUsage, err := readResctrlMonValue(path)
 			if err != nil {
-				return nil, fmt.Errorf("cannot parse result, err: %w", err)
+				return nil, err
 			}
-			mbStat[CacheId(cacheId)][string(mbResource.ResourceType())] = mbUsage
+			mbStat[cacheId][string(mbResource.ResourceType())] = mbUsage
 		}
 	}
 	return mbStat, nil
 }
+
+// readResctrlOccupancy reads the cache occupancy of the monitoring domains with the prefix, e.g. `mon_L3`.
+func readResctrlOccupancy(parent string, prefix string) (map[CacheId]uint64, error) {
+	domains, err := readResctrlMonDomains(parent, prefix)
+	if err != nil {
+		return nil, err
+	}
+	stat := make(map[CacheId]uint64)
+	for cacheId, domain := range domains {
+		path := system.ResctrlLLCOccupancy.Path(filepath.Join(parent, system.ResctrlMonData, domain))
+		usage, err := readResctrlMonValue(path)
+		if err != nil {
+			return nil, err
+		}
+		stat[cacheId] = usage
+	}
+	return stat, nil
+}
+
+// readResctrlMonDomains returns the monitoring domains with the prefix in the mon_data, the key is the domain id.
+func readResctrlMonDomains(parent string, prefix string) (map[CacheId]string, error) {
+	monDataPath := system.GetResctrlMonDataPath(parent)
+	entries, err := os.ReadDir(monDataPath)
+	if err != nil {
+		return nil, errors.New(ErrResctrlDir)
+	}
+	domains := make(map[CacheId]string)
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), prefix+"_") {
+			continue
+		}
+		fields := strings.Split(entry.Name(), "_")
+		if len(fields) <= CacheIdIndex {
+			return nil, fmt.Errorf("%s, invalid domain %s", ErrResctrlDir, entry.Name())
+		}
+		cacheId, err := strconv.Atoi(fields[CacheIdIndex])
+		if err != nil {
+			return nil, fmt.Errorf("%s, cannot get cacheid, err: %w", ErrResctrlDir, err)
+		}
+		domains[CacheId(cacheId)] = entry.Name()
+	}
+	return domains, nil
+}
+
+func readResctrlMonValue(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("%s, cannot read from resctrl file system, err: %w", ErrResctrlDir, err)
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse result, err: %w", err)
+	}
+	return value, nil
+}
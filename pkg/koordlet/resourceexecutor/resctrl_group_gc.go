@@ -0,0 +1,204 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// ResctrlGroupState is the expected state of the resctrl groups owned by koordlet.
+type ResctrlGroupState struct {
+	// CtrlGroupTasks is the expected task ids of the owned control groups, e.g. `BE`.
+	// The missing tasks are re-written into the group, and the unexpected ones are moved back to the root group.
+	CtrlGroupTasks map[string][]int32
+	// PodUIDs is the set of the pods on the node. The pod-level groups of the other pods are orphans.
+	PodUIDs map[string]struct{}
+}
+
+// ResctrlGroupStateFunc returns the expected state of the resctrl groups. The gc round is skipped if it returns nil.
+type ResctrlGroupStateFunc func() (*ResctrlGroupState, error)
+
+// ResctrlGroupGC periodically garbage-collects the orphan control groups and mon groups under the resctrl root and
+// re-syncs the task lists of the owned control groups, since the stale tasks and groups are left when the pods are
+// removed or the QoS classes change.
+type ResctrlGroupGC interface {
+	// Run starts the periodic gc. It does nothing if the interval is not positive.
+	Run(stopCh <-chan struct{})
+	// Reconcile runs a round of the gc.
+	Reconcile()
+}
+
+type resctrlGroupGC struct {
+	interval time.Duration
+	// podCtrlGroupPrefix is the name prefix of the pod-level control groups, e.g. `koordlet-`.
+	podCtrlGroupPrefix string
+	stateFn            ResctrlGroupStateFunc
+}
+
+func NewResctrlGroupGC(interval time.Duration, podCtrlGroupPrefix string, stateFn ResctrlGroupStateFunc) ResctrlGroupGC {
+	return &resctrlGroupGC{
+		interval:           interval,
+		podCtrlGroupPrefix: podCtrlGroupPrefix,
+		stateFn:            stateFn,
+	}
+}
+
+func (g *resctrlGroupGC) Run(stopCh <-chan struct{}) {
+	if g.interval <= 0 {
+		klog.V(4).Infof("resctrl group gc is disabled, interval %v", g.interval)
+		return
+	}
+	go wait.Until(g.Reconcile, g.interval, stopCh)
+}
+
+func (g *resctrlGroupGC) Reconcile() {
+	ctrlGroups, err := listResctrlCtrlGroups()
+	if err != nil {
+		klog.V(5).Infof("skip resctrl group gc, failed to list resctrl control groups, err: %v", err)
+		return
+	}
+	state, err := g.stateFn()
+	if err != nil {
+		klog.V(4).Infof("skip resctrl group gc, failed to get the expected state, err: %v", err)
+		return
+	}
+	if state == nil {
+		klog.V(5).Infof("skip resctrl group gc, the expected state is not ready")
+		return
+	}
+
+	for _, ctrlGroup := range ctrlGroups {
+		if g.isOrphanCtrlGroup(ctrlGroup, state) {
+			ctrlGroupPath := sysutil.GetResctrlGroupRootDirPath(ctrlGroup)
+			if isDryRunPath(Conf, ctrlGroupPath) {
+				recordDryRunGroupChange("remove", ctrlGroupPath)
+				continue
+			}
+			// the tasks of the removed control group are moved back to the root group by the kernel
+			err = os.RemoveAll(ctrlGroupPath)
+			recordResctrlGroupGCAction(metrics.ResctrlGroupGCActionRemoveCtrlGroup, err)
+			if err != nil {
+				klog.V(4).Infof("failed to remove orphan resctrl control group %s, err: %v", ctrlGroup, err)
+			} else {
+				klog.V(4).Infof("remove orphan resctrl control group %s successfully", ctrlGroup)
+				_ = audit.V(3).Reason(RemoveResctrlCtrlGroup).Message("remove orphan resctrl control group %s", ctrlGroup).Do()
+			}
+			continue
+		}
+
+		for podUID, monGroup := range listPodMonGroups(ctrlGroup) {
+			if _, ok := state.PodUIDs[podUID]; ok {
+				continue
+			}
+			err = removeResctrlMonGroup(monGroup)
+			recordResctrlGroupGCAction(metrics.ResctrlGroupGCActionRemoveMonGroup, err)
+			if err != nil {
+				klog.V(4).Infof("failed to remove orphan resctrl mon group %s, err: %v", monGroup, err)
+			}
+		}
+	}
+
+	for ctrlGroup, taskIds := range state.CtrlGroupTasks {
+		g.resyncCtrlGroupTasks(ctrlGroup, taskIds)
+	}
+}
+
+// isOrphanCtrlGroup checks if the control group is a pod-level group whose pod no longer exists.
+func (g *resctrlGroupGC) isOrphanCtrlGroup(ctrlGroup string, state *ResctrlGroupState) bool {
+	if len(ctrlGroup) <= 0 || len(g.podCtrlGroupPrefix) <= 0 || !strings.HasPrefix(ctrlGroup, g.podCtrlGroupPrefix) {
+		return false
+	}
+	if _, ok := state.CtrlGroupTasks[ctrlGroup]; ok {
+		return false
+	}
+	_, ok := state.PodUIDs[strings.TrimPrefix(ctrlGroup, g.podCtrlGroupPrefix)]
+	return !ok
+}
+
+func (g *resctrlGroupGC) resyncCtrlGroupTasks(ctrlGroup string, taskIds []int32) {
+	curTasksMap, err := sysutil.ReadResctrlTasksMap(ctrlGroup)
+	if err != nil {
+		klog.V(5).Infof("failed to read tasks of resctrl group %s, err: %v", ctrlGroup, err)
+		return
+	}
+	expectedTasksMap := make(map[int32]struct{}, len(taskIds))
+	var missingTaskIds []int32
+	for _, id := range taskIds {
+		expectedTasksMap[id] = struct{}{}
+		if _, ok := curTasksMap[id]; !ok {
+			missingTaskIds = append(missingTaskIds, id)
+		}
+	}
+	var staleTaskIds []int32
+	for id := range curTasksMap {
+		if _, ok := expectedTasksMap[id]; !ok {
+			staleTaskIds = append(staleTaskIds, id)
+		}
+	}
+	if len(missingTaskIds) <= 0 && len(staleTaskIds) <= 0 {
+		return
+	}
+
+	if len(staleTaskIds) > 0 {
+		// a task belongs to exactly one control group, so it is removed from the group by writing into the root group
+		err = writeResctrlGroupTasks("", staleTaskIds)
+		recordResctrlGroupGCAction(metrics.ResctrlGroupGCActionResyncTasks, err)
+		if err != nil {
+			klog.V(4).Infof("failed to move %d stale tasks of resctrl group %s to root, err: %v",
+				len(staleTaskIds), ctrlGroup, err)
+		}
+	}
+	if len(missingTaskIds) > 0 {
+		err = writeResctrlGroupTasks(ctrlGroup, missingTaskIds)
+		recordResctrlGroupGCAction(metrics.ResctrlGroupGCActionResyncTasks, err)
+		if err != nil {
+			klog.V(4).Infof("failed to re-sync %d missing tasks of resctrl group %s, err: %v",
+				len(missingTaskIds), ctrlGroup, err)
+		}
+	}
+	klog.V(5).Infof("re-sync tasks of resctrl group %s, stale %d, missing %d",
+		ctrlGroup, len(staleTaskIds), len(missingTaskIds))
+}
+
+func writeResctrlGroupTasks(ctrlGroup string, taskIds []int32) error {
+	updater, err := CalculateResctrlL3TasksResource(ctrlGroup, taskIds)
+	if err != nil {
+		return err
+	}
+	if isDryRunPath(Conf, updater.Path()) {
+		recordDryRunUpdate(updater)
+		return nil
+	}
+	return updater.update()
+}
+
+func recordResctrlGroupGCAction(action string, err error) {
+	if err != nil {
+		metrics.RecordResctrlGroupGCAction(action, metrics.ResourceUpdateStatusFailed)
+		return
+	}
+	metrics.RecordResctrlGroupGCAction(action, metrics.ResourceUpdateStatusSuccess)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestResctrlGroupGC(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	resctrlRoot := system.GetResctrlSubsystemDirPath()
+	helper.WriteFileContents(filepath.Join(resctrlRoot, system.ResctrlTasksName), "100\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName), "1\n2\n3\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "LS", system.ResctrlTasksName), "4\n")
+	helper.MkDirAll(filepath.Join(resctrlRoot, system.RdtInfoDir))
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "koordlet-pod-alive", system.ResctrlTasksName), "5\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "koordlet-pod-deleted", system.ResctrlTasksName), "6\n")
+	aliveMonGroup := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-alive")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, aliveMonGroup, system.ResctrlTasksName), "1\n")
+	orphanMonGroup := system.GetResctrlMonGroupPath("LS", ResctrlMonGroupPodPrefix+"pod-deleted")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, orphanMonGroup, system.ResctrlTasksName), "")
+
+	t.Run("skip when state is not ready", func(t *testing.T) {
+		g := NewResctrlGroupGC(0, "koordlet-", func() (*ResctrlGroupState, error) {
+			return nil, nil
+		})
+		g.Reconcile()
+		g = NewResctrlGroupGC(0, "koordlet-", func() (*ResctrlGroupState, error) {
+			return nil, fmt.Errorf("expected error")
+		})
+		g.Reconcile()
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath("koordlet-pod-deleted")))
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath(orphanMonGroup)))
+	})
+
+	t.Run("only record the changes in the dry-run mode", func(t *testing.T) {
+		oldDryRun := Conf.ResourceUpdateDryRun
+		Conf.ResourceUpdateDryRun = true
+		defer func() { Conf.ResourceUpdateDryRun = oldDryRun }()
+		g := NewResctrlGroupGC(0, "koordlet-", func() (*ResctrlGroupState, error) {
+			return &ResctrlGroupState{
+				CtrlGroupTasks: map[string][]int32{
+					"BE": {1, 2, 7},
+				},
+				PodUIDs: map[string]struct{}{
+					"pod-alive": {},
+				},
+			}, nil
+		})
+		g.Reconcile()
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath("koordlet-pod-deleted")))
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath(orphanMonGroup)))
+		assert.Equal(t, "100\n", helper.ReadFileContents(filepath.Join(resctrlRoot, system.ResctrlTasksName)))
+		assert.Equal(t, "1\n2\n3\n", helper.ReadFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName)))
+	})
+
+	t.Run("remove orphan groups and re-sync tasks", func(t *testing.T) {
+		g := NewResctrlGroupGC(0, "koordlet-", func() (*ResctrlGroupState, error) {
+			return &ResctrlGroupState{
+				CtrlGroupTasks: map[string][]int32{
+					"BE": {1, 2, 7},
+					"LS": {4},
+				},
+				PodUIDs: map[string]struct{}{
+					"pod-alive": {},
+				},
+			}, nil
+		})
+		g.Reconcile()
+
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath("koordlet-pod-alive")))
+		assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath("koordlet-pod-deleted")))
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath(aliveMonGroup)))
+		assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(orphanMonGroup)))
+		assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath("LS")))
+
+		// the test files are appended instead of moving the tasks like the kernel
+		assert.Equal(t, "100\n3", helper.ReadFileContents(filepath.Join(resctrlRoot, system.ResctrlTasksName)))
+		assert.Equal(t, "1\n2\n3\n7", helper.ReadFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName)))
+		assert.Equal(t, "4\n", helper.ReadFileContents(filepath.Join(resctrlRoot, "LS", system.ResctrlTasksName)))
+	})
+}
+
+func TestResctrlGroupGC_isOrphanCtrlGroup(t *testing.T) {
+	state := &ResctrlGroupState{
+		CtrlGroupTasks: map[string][]int32{
+			"BE":               nil,
+			"koordlet-special": nil,
+		},
+		PodUIDs: map[string]struct{}{
+			"pod-alive": {},
+		},
+	}
+	tests := []struct {
+		name      string
+		prefix    string
+		ctrlGroup string
+		want      bool
+	}{
+		{
+			name:      "root group",
+			prefix:    "koordlet-",
+			ctrlGroup: "",
+			want:      false,
+		},
+		{
+			name:      "qos group",
+			prefix:    "koordlet-",
+			ctrlGroup: "LSR",
+			want:      false,
+		},
+		{
+			name:      "alive pod group",
+			prefix:    "koordlet-",
+			ctrlGroup: "koordlet-pod-alive",
+			want:      false,
+		},
+		{
+			name:      "owned group with prefix",
+			prefix:    "koordlet-",
+			ctrlGroup: "koordlet-special",
+			want:      false,
+		},
+		{
+			name:      "deleted pod group",
+			prefix:    "koordlet-",
+			ctrlGroup: "koordlet-pod-deleted",
+			want:      true,
+		},
+		{
+			name:      "no pod group prefix",
+			prefix:    "",
+			ctrlGroup: "koordlet-pod-deleted",
+			want:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &resctrlGroupGC{podCtrlGroupPrefix: tt.prefix}
+			assert.Equal(t, tt.want, g.isOrphanCtrlGroup(tt.ctrlGroup, state))
+		})
+	}
+}
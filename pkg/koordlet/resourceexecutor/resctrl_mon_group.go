@@ -0,0 +1,411 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// ResctrlMonGroupPodPrefix is the name prefix of the pod-level monitoring groups created by koordlet.
+const ResctrlMonGroupPodPrefix = "koordlet-pod-"
+
+// ResctrlMonGroupManager manages the per-pod monitoring groups (mon_groups) under the resctrl control groups,
+// so that the L3 occupancy and the memory bandwidth can be attributed to individual pods.
+type ResctrlMonGroupManager interface {
+	// SyncPodMonGroups creates the mon groups for the pods under the control groups which the pod tasks belong to,
+	// moves the pod tasks into the mon groups, and removes the mon groups of the pods not in the podTasks.
+	// When the RMIDs run out, the least recently active mon groups are recycled for the pods without mon groups.
+	// It returns the pod UID to the mon group path relative to the resctrl root, which can be read by the ResctrlReader.
+	SyncPodMonGroups(podTasks map[string][]int32) map[string]string
+	// RemovePodMonGroup removes the mon group of the pod.
+	RemovePodMonGroup(podUID string) error
+	// GetRMIDStatus returns the RMID usage observed by the last sync.
+	GetRMIDStatus() ResctrlRMIDStatus
+}
+
+// ResctrlRMIDStatus is the usage of the resctrl RMIDs. Each control group and each mon group takes an RMID.
+type ResctrlRMIDStatus struct {
+	// Capacity is the number of the RMIDs supported by the hardware, 0 if unknown.
+	Capacity int64
+	// Used is the number of the RMIDs taken by the control groups and the mon groups.
+	Used int64
+	// PendingPods is the number of the pods without mon groups since the RMIDs run out.
+	PendingPods int
+	// RecycledMonGroups is the number of the mon groups recycled by the last sync.
+	RecycledMonGroups int
+}
+
+// IsExhausted returns whether some pods cannot get the mon groups since the RMIDs run out.
+func (s ResctrlRMIDStatus) IsExhausted() bool {
+	return s.PendingPods > 0
+}
+
+type resctrlMonGroupManager struct {
+	lock        sync.Mutex
+	initialized bool
+	// podMonGroups is the pod UID to the mon group path relative to the resctrl root, e.g. BE/mon_groups/koordlet-pod-xxx
+	podMonGroups map[string]string
+	// podActiveSeqs is the sequence when the mon group of the pod was created or got new tasks, which decides the
+	// LRU order of the recycling
+	podActiveSeqs map[string]uint64
+	activeSeq     uint64
+	// recycledPods are the pods whose mon groups have been recycled. They wait for the free RMIDs instead of
+	// recycling the others, so the mon groups are not recreated back and forth.
+	recycledPods map[string]struct{}
+	rmidCapacity int64
+	rmidStatus   ResctrlRMIDStatus
+
+	ensureMonGroupFn func(monGroup string, taskIds []int32) (bool, error)
+}
+
+func NewResctrlMonGroupManager() ResctrlMonGroupManager {
+	return &resctrlMonGroupManager{
+		podMonGroups:  map[string]string{},
+		podActiveSeqs: map[string]uint64{},
+		recycledPods:  map[string]struct{}{},
+
+		ensureMonGroupFn: ensureResctrlMonGroup,
+	}
+}
+
+func (m *resctrlMonGroupManager) SyncPodMonGroups(podTasks map[string][]int32) map[string]string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	ctrlGroups, err := listResctrlCtrlGroups()
+	if err != nil {
+		klog.V(4).Infof("failed to list resctrl control groups, err: %v", err)
+		return nil
+	}
+	if !m.initialized {
+		// adopt the mon groups created before the restart, so the ones of the deleted pods can be removed
+		for _, ctrlGroup := range ctrlGroups {
+			for podUID, monGroup := range listPodMonGroups(ctrlGroup) {
+				m.podMonGroups[podUID] = monGroup
+				m.podActiveSeqs[podUID] = m.activeSeq
+			}
+		}
+		m.initialized = true
+	}
+
+	taskCtrlGroups := map[int32]string{}
+	for _, ctrlGroup := range ctrlGroups {
+		tasksMap, err := sysutil.ReadResctrlTasksMap(ctrlGroup)
+		if err != nil {
+			klog.V(5).Infof("failed to read tasks of resctrl group %s, err: %v", ctrlGroup, err)
+			continue
+		}
+		for id := range tasksMap {
+			taskCtrlGroups[id] = ctrlGroup
+		}
+	}
+
+	// remove the mon groups of the deleted pods first to release the RMIDs
+	for podUID, monGroup := range m.podMonGroups {
+		if _, ok := podTasks[podUID]; ok {
+			continue
+		}
+		if err := removeResctrlMonGroup(monGroup); err != nil {
+			klog.V(4).Infof("failed to remove mon group %s for pod %s, err: %v", monGroup, podUID, err)
+			continue
+		}
+		delete(m.podMonGroups, podUID)
+		delete(m.podActiveSeqs, podUID)
+	}
+	for podUID := range m.recycledPods {
+		if _, ok := podTasks[podUID]; !ok {
+			delete(m.recycledPods, podUID)
+		}
+	}
+
+	status := ResctrlRMIDStatus{
+		Capacity: m.getRMIDCapacity(),
+		Used:     countResctrlRMIDs(ctrlGroups),
+	}
+	// noFreeRMID indicates the kernel fails to allocate an RMID before reaching the capacity, e.g. some of the freed
+	// ones are still in the limbo state
+	noFreeRMID := false
+	// the mon groups created or refreshed in this round are not recycled
+	roundSeq := m.activeSeq + 1
+	podUIDs := make([]string, 0, len(podTasks))
+	for podUID := range podTasks {
+		podUIDs = append(podUIDs, podUID)
+	}
+	sort.Strings(podUIDs)
+	for _, podUID := range podUIDs {
+		taskIds := podTasks[podUID]
+		ctrlGroup, ok := getTasksCtrlGroup(taskIds, taskCtrlGroups)
+		if !ok {
+			klog.V(5).Infof("skip creating mon group for pod %s since its tasks are not found in resctrl", podUID)
+			continue
+		}
+		monGroup := sysutil.GetResctrlMonGroupPath(ctrlGroup, ResctrlMonGroupPodPrefix+podUID)
+		oldMonGroup, exist := m.podMonGroups[podUID]
+		if exist && oldMonGroup != monGroup {
+			// the pod has been moved to another control group
+			if err := removeResctrlMonGroup(oldMonGroup); err != nil {
+				klog.V(4).Infof("failed to remove outdated mon group %s for pod %s, err: %v", oldMonGroup, podUID, err)
+			} else {
+				status.Used--
+			}
+			delete(m.podMonGroups, podUID)
+			delete(m.podActiveSeqs, podUID)
+			exist = false
+		}
+		if !exist && !m.reserveRMID(podUID, roundSeq, noFreeRMID, &status) {
+			status.PendingPods++
+			continue
+		}
+
+		changed, err := m.ensureMonGroupFn(monGroup, taskIds)
+		if err != nil {
+			if errors.Is(err, syscall.ENOSPC) {
+				klog.V(4).Infof("failed to create mon group %s for pod %s since the RMIDs run out", monGroup, podUID)
+				noFreeRMID = true
+				status.PendingPods++
+				continue
+			}
+			klog.V(4).Infof("failed to ensure mon group %s for pod %s, err: %v", monGroup, podUID, err)
+			continue
+		}
+		if !exist {
+			status.Used++
+		}
+		if changed {
+			m.activeSeq++
+			m.podActiveSeqs[podUID] = m.activeSeq
+		}
+		m.podMonGroups[podUID] = monGroup
+		delete(m.recycledPods, podUID)
+	}
+	if status.PendingPods > 0 {
+		klog.V(4).Infof("resctrl RMIDs are exhausted, used %d, capacity %d, %d pods without mon groups",
+			status.Used, status.Capacity, status.PendingPods)
+	}
+	m.rmidStatus = status
+
+	podMonGroups := make(map[string]string, len(m.podMonGroups))
+	for podUID, monGroup := range m.podMonGroups {
+		podMonGroups[podUID] = monGroup
+	}
+	return podMonGroups
+}
+
+// reserveRMID checks if there is a free RMID for the new mon group of the pod. If the RMIDs run out, it recycles the
+// least recently active mon group unless the mon group of the pod has been recycled.
+func (m *resctrlMonGroupManager) reserveRMID(podUID string, roundSeq uint64, noFreeRMID bool, status *ResctrlRMIDStatus) bool {
+	if !noFreeRMID && (status.Capacity <= 0 || status.Used < status.Capacity) {
+		return true
+	}
+	if _, ok := m.recycledPods[podUID]; ok {
+		return false
+	}
+	victim, ok := m.getLRUPod(roundSeq)
+	if !ok {
+		return false
+	}
+	monGroup := m.podMonGroups[victim]
+	if err := removeResctrlMonGroup(monGroup); err != nil {
+		klog.V(4).Infof("failed to recycle mon group %s of pod %s, err: %v", monGroup, victim, err)
+		return false
+	}
+	klog.V(5).Infof("recycle mon group %s of pod %s for pod %s", monGroup, victim, podUID)
+	_ = audit.V(3).Reason(RecycleResctrlMonGroup).Message("recycle resctrl mon group %s for pod %s", monGroup, podUID).Do()
+	delete(m.podMonGroups, victim)
+	delete(m.podActiveSeqs, victim)
+	m.recycledPods[victim] = struct{}{}
+	status.Used--
+	status.RecycledMonGroups++
+	return true
+}
+
+// getLRUPod returns the pod whose mon group is the least recently active before the round.
+func (m *resctrlMonGroupManager) getLRUPod(roundSeq uint64) (string, bool) {
+	lruPod, lruSeq := "", roundSeq
+	for podUID, seq := range m.podActiveSeqs {
+		if seq < lruSeq || (seq == lruSeq && lruPod != "" && podUID < lruPod) {
+			lruPod, lruSeq = podUID, seq
+		}
+	}
+	return lruPod, lruPod != ""
+}
+
+// getRMIDCapacity returns the number of the RMIDs, 0 if unknown.
+func (m *resctrlMonGroupManager) getRMIDCapacity() int64 {
+	if m.rmidCapacity > 0 {
+		return m.rmidCapacity
+	}
+	numRMIDs, err := sysutil.ReadResctrlInfoInt(sysutil.ResctrlL3MonDir, sysutil.ResctrlNumRMIDsName)
+	if err != nil {
+		klog.V(6).Infof("failed to read the number of resctrl RMIDs, err: %v", err)
+		return 0
+	}
+	m.rmidCapacity = numRMIDs
+	return numRMIDs
+}
+
+func (m *resctrlMonGroupManager) RemovePodMonGroup(podUID string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.recycledPods, podUID)
+	monGroup, ok := m.podMonGroups[podUID]
+	if !ok {
+		return nil
+	}
+	if err := removeResctrlMonGroup(monGroup); err != nil {
+		return err
+	}
+	delete(m.podMonGroups, podUID)
+	delete(m.podActiveSeqs, podUID)
+	return nil
+}
+
+func (m *resctrlMonGroupManager) GetRMIDStatus() ResctrlRMIDStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.rmidStatus
+}
+
+// listResctrlCtrlGroups returns the resctrl control groups including the root group "".
+func listResctrlCtrlGroups() ([]string, error) {
+	entries, err := os.ReadDir(sysutil.GetResctrlSubsystemDirPath())
+	if err != nil {
+		return nil, err
+	}
+	ctrlGroups := []string{""}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		switch entry.Name() {
+		case sysutil.RdtInfoDir, sysutil.ResctrlMonGroupsDir, sysutil.ResctrlMonData:
+			continue
+		}
+		ctrlGroups = append(ctrlGroups, entry.Name())
+	}
+	return ctrlGroups, nil
+}
+
+// listPodMonGroups returns the pod UID to the mon group path of the pod-level mon groups under the control group.
+func listPodMonGroups(ctrlGroup string) map[string]string {
+	entries, err := os.ReadDir(filepath.Join(sysutil.GetResctrlGroupRootDirPath(ctrlGroup), sysutil.ResctrlMonGroupsDir))
+	if err != nil {
+		return nil
+	}
+	podMonGroups := map[string]string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), ResctrlMonGroupPodPrefix) {
+			continue
+		}
+		podUID := strings.TrimPrefix(entry.Name(), ResctrlMonGroupPodPrefix)
+		podMonGroups[podUID] = sysutil.GetResctrlMonGroupPath(ctrlGroup, entry.Name())
+	}
+	return podMonGroups
+}
+
+// countResctrlRMIDs returns the number of the RMIDs taken by the control groups and all their mon groups.
+func countResctrlRMIDs(ctrlGroups []string) int64 {
+	count := int64(len(ctrlGroups))
+	for _, ctrlGroup := range ctrlGroups {
+		entries, err := os.ReadDir(filepath.Join(sysutil.GetResctrlGroupRootDirPath(ctrlGroup), sysutil.ResctrlMonGroupsDir))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// getTasksCtrlGroup returns the control group of the first task found in resctrl.
+func getTasksCtrlGroup(taskIds []int32, taskCtrlGroups map[int32]string) (string, bool) {
+	for _, id := range taskIds {
+		if ctrlGroup, ok := taskCtrlGroups[id]; ok {
+			return ctrlGroup, true
+		}
+	}
+	return "", false
+}
+
+// ensureResctrlMonGroup creates the mon group if not exist and moves the tasks into it. It returns whether the mon
+// group is created or gets new tasks.
+func ensureResctrlMonGroup(monGroup string, taskIds []int32) (bool, error) {
+	if monGroupPath := sysutil.GetResctrlGroupRootDirPath(monGroup); isDryRunPath(Conf, monGroupPath) {
+		recordDryRunGroupChange("create", monGroupPath)
+		return false, nil
+	}
+	created, err := sysutil.InitCatGroupIfNotExist(monGroup)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		klog.V(5).Infof("create resctrl mon group %s successfully", monGroup)
+		_ = audit.V(3).Reason(CreateResctrlMonGroup).Message("create resctrl mon group %s", monGroup).Do()
+	}
+
+	curTasksMap, err := sysutil.ReadResctrlTasksMap(monGroup)
+	if err != nil {
+		klog.V(5).Infof("failed to read tasks of resctrl mon group %s, err: %v", monGroup, err)
+	}
+	newTaskIds := make([]int32, 0, len(taskIds))
+	for _, id := range taskIds {
+		if _, ok := curTasksMap[id]; !ok {
+			newTaskIds = append(newTaskIds, id)
+		}
+	}
+	if len(newTaskIds) <= 0 {
+		return created, nil
+	}
+	updater, err := CalculateResctrlL3TasksResource(monGroup, newTaskIds)
+	if err != nil {
+		return created, err
+	}
+	if isDryRunPath(Conf, updater.Path()) {
+		recordDryRunUpdate(updater)
+		return created, nil
+	}
+	return true, updater.update()
+}
+
+func removeResctrlMonGroup(monGroup string) error {
+	monGroupPath := sysutil.GetResctrlGroupRootDirPath(monGroup)
+	if isDryRunPath(Conf, monGroupPath) {
+		recordDryRunGroupChange("remove", monGroupPath)
+		return nil
+	}
+	// the tasks of the removed mon group are moved back to the parent control group by the kernel
+	if err := os.RemoveAll(monGroupPath); err != nil {
+		return err
+	}
+	klog.V(5).Infof("remove resctrl mon group %s successfully", monGroup)
+	_ = audit.V(3).Reason(RemoveResctrlMonGroup).Message("remove resctrl mon group %s", monGroup).Do()
+	return nil
+}
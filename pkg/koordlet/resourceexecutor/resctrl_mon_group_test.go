@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestResctrlMonGroupManager(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	resctrlRoot := system.GetResctrlSubsystemDirPath()
+	helper.WriteFileContents(filepath.Join(resctrlRoot, system.ResctrlTasksName), "100\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName), "1\n2\n3\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "LS", system.ResctrlTasksName), "4\n")
+	helper.MkDirAll(filepath.Join(resctrlRoot, system.RdtInfoDir))
+	// the tasks files of the mon groups are created by the kernel
+	podBEMonGroup := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-be")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, podBEMonGroup, system.ResctrlTasksName), "1\n")
+	staleMonGroup := system.GetResctrlMonGroupPath("LS", ResctrlMonGroupPodPrefix+"pod-stale")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, staleMonGroup, system.ResctrlTasksName), "")
+
+	m := NewResctrlMonGroupManager()
+	got := m.SyncPodMonGroups(map[string][]int32{
+		"pod-be":      {1, 2},
+		"pod-root":    {100},
+		"pod-unknown": {200},
+	})
+	// the mon_groups dir of the root group does not exist in the test
+	assert.Equal(t, map[string]string{"pod-be": podBEMonGroup}, got)
+	tasksMap, err := system.ReadResctrlTasksMap(podBEMonGroup)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]struct{}{1: {}, 2: {}}, tasksMap)
+	// the stale mon group created before the restart is removed
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(staleMonGroup)))
+
+	// pod moved to the LS group
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "LS", system.ResctrlTasksName), "1\n2\n4\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName), "3\n")
+	podLSMonGroup := system.GetResctrlMonGroupPath("LS", ResctrlMonGroupPodPrefix+"pod-be")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, podLSMonGroup, system.ResctrlTasksName), "")
+	got = m.SyncPodMonGroups(map[string][]int32{
+		"pod-be": {1, 2},
+	})
+	assert.Equal(t, map[string]string{"pod-be": podLSMonGroup}, got)
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(podBEMonGroup)))
+
+	assert.NoError(t, m.RemovePodMonGroup("pod-be"))
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(podLSMonGroup)))
+	assert.NoError(t, m.RemovePodMonGroup("pod-be"))
+	got = m.SyncPodMonGroups(nil)
+	assert.Equal(t, map[string]string{}, got)
+}
+
+func TestResctrlMonGroupManagerRecycle(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	resctrlRoot := system.GetResctrlSubsystemDirPath()
+	helper.WriteFileContents(filepath.Join(resctrlRoot, system.ResctrlTasksName), "100\n")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName), "1\n2\n3\n")
+	helper.MkDirAll(filepath.Join(resctrlRoot, "BE", system.ResctrlMonGroupsDir))
+	// the root and BE control groups take 2 RMIDs
+	helper.WriteFileContents(filepath.Join(resctrlRoot, system.RdtInfoDir, system.ResctrlL3MonDir, system.ResctrlNumRMIDsName), "4\n")
+	monGroupA := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-a")
+	monGroupB := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-b")
+	monGroupC := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-c")
+	podTasks := map[string][]int32{
+		"pod-a": {1},
+		"pod-b": {2},
+		"pod-c": {3},
+	}
+
+	m := NewResctrlMonGroupManager().(*resctrlMonGroupManager)
+	// the tasks file of the mon group is created by the kernel
+	m.ensureMonGroupFn = func(monGroup string, taskIds []int32) (bool, error) {
+		tasksPath := filepath.Join(resctrlRoot, monGroup, system.ResctrlTasksName)
+		if system.FileExists(tasksPath) {
+			return false, nil
+		}
+		helper.WriteFileContents(tasksPath, "")
+		return ensureResctrlMonGroup(monGroup, taskIds)
+	}
+	// no mon group can be recycled since all are created in the round
+	got := m.SyncPodMonGroups(podTasks)
+	assert.Equal(t, map[string]string{"pod-a": monGroupA, "pod-b": monGroupB}, got)
+	status := m.GetRMIDStatus()
+	assert.Equal(t, ResctrlRMIDStatus{Capacity: 4, Used: 4, PendingPods: 1}, status)
+	assert.True(t, status.IsExhausted())
+
+	// the least recently active mon group of pod-a is recycled for pod-c
+	got = m.SyncPodMonGroups(podTasks)
+	assert.Equal(t, map[string]string{"pod-b": monGroupB, "pod-c": monGroupC}, got)
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(monGroupA)))
+	assert.Equal(t, ResctrlRMIDStatus{Capacity: 4, Used: 4, RecycledMonGroups: 1}, m.GetRMIDStatus())
+
+	// the recycled pod waits for the free RMIDs
+	got = m.SyncPodMonGroups(podTasks)
+	assert.Equal(t, map[string]string{"pod-b": monGroupB, "pod-c": monGroupC}, got)
+	assert.Equal(t, ResctrlRMIDStatus{Capacity: 4, Used: 4, PendingPods: 1}, m.GetRMIDStatus())
+
+	// the RMID released by the deleted pod is taken by the recycled pod
+	delete(podTasks, "pod-b")
+	got = m.SyncPodMonGroups(podTasks)
+	assert.Equal(t, map[string]string{"pod-a": monGroupA, "pod-c": monGroupC}, got)
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(monGroupB)))
+	status = m.GetRMIDStatus()
+	assert.Equal(t, ResctrlRMIDStatus{Capacity: 4, Used: 4}, status)
+	assert.False(t, status.IsExhausted())
+}
+
+func TestResctrlMonGroupDryRun(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldDryRun := Conf.ResourceUpdateDryRun
+	Conf.ResourceUpdateDryRun = true
+	defer func() { Conf.ResourceUpdateDryRun = oldDryRun }()
+
+	resctrlRoot := system.GetResctrlSubsystemDirPath()
+	helper.WriteFileContents(filepath.Join(resctrlRoot, "BE", system.ResctrlTasksName), "1\n2\n")
+	newMonGroup := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-new")
+	updated, err := ensureResctrlMonGroup(newMonGroup, []int32{1, 2})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.False(t, system.FileExists(system.GetResctrlGroupRootDirPath(newMonGroup)))
+
+	staleMonGroup := system.GetResctrlMonGroupPath("BE", ResctrlMonGroupPodPrefix+"pod-stale")
+	helper.WriteFileContents(filepath.Join(resctrlRoot, staleMonGroup, system.ResctrlTasksName), "")
+	assert.NoError(t, removeResctrlMonGroup(staleMonGroup))
+	assert.True(t, system.FileExists(system.GetResctrlGroupRootDirPath(staleMonGroup)))
+}
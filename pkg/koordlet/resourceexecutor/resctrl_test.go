@@ -17,6 +17,7 @@ limitations under the License.
 package resourceexecutor
 
 import (
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -59,6 +60,14 @@ func TestNewResctrlReader(t *testing.T) {
 			want:    reflect.TypeOf(&fakeReader{}),
 			wantErr: false,
 		},
+		{
+			name: "test arm mpam",
+			args: args{
+				content: "processor       : 0\nBogoMIPS        : 200.00\nCPU implementer : 0x48\nCPU architecture: 8\n",
+			},
+			want:    reflect.TypeOf(&ResctrlMPAMReader{}),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -71,6 +80,47 @@ func TestNewResctrlReader(t *testing.T) {
 	}
 }
 
+type testHygonReader struct {
+	fakeReader
+}
+
+func TestRegisterResctrlReader(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	helper.WriteProcSubFileContents("cpuinfo", "vendor_id       : HygonGenuine\n")
+	assert.False(t, IsResctrlReaderAvailable())
+
+	RegisterResctrlReader("HygonGenuine", func() ResctrlReader {
+		return &testHygonReader{}
+	})
+	defer func() {
+		resctrlReaderRegistryLock.Lock()
+		delete(resctrlReaderRegistry, "HygonGenuine")
+		resctrlReaderRegistryLock.Unlock()
+	}()
+	assert.True(t, IsResctrlReaderAvailable())
+	assert.Equal(t, reflect.TypeOf(&testHygonReader{}), reflect.TypeOf(NewResctrlReader()))
+}
+
+func TestProbeResctrlReader(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	helper.WriteProcSubFileContents("cpuinfo", "vendor_id       : CentaurHauls\n")
+	// no resctrl monitor
+	assert.False(t, IsResctrlReaderAvailable())
+	assert.Equal(t, reflect.TypeOf(&fakeReader{}), reflect.TypeOf(NewResctrlReader()))
+
+	monFeaturesPath := filepath.Join(system.GetResctrlSubsystemDirPath(), system.RdtInfoDir, system.ResctrlL3MonDir, "mon_features")
+	helper.WriteFileContents(monFeaturesPath, "mbm_total_bytes\n")
+	assert.Equal(t, reflect.TypeOf(&fakeReader{}), reflect.TypeOf(NewResctrlReader()))
+
+	helper.WriteFileContents(monFeaturesPath, "llc_occupancy\nmbm_total_bytes\n")
+	assert.True(t, IsResctrlReaderAvailable())
+	helper.MkDirAll(filepath.Join(system.GetResctrlMonDataPath(""), "mon_L3_00"))
+	assert.Equal(t, reflect.TypeOf(&ResctrlRDTReader{}), reflect.TypeOf(NewResctrlReader()))
+
+	helper.MkDirAll(filepath.Join(system.GetResctrlMonDataPath(""), "mon_MB_00"))
+	assert.Equal(t, reflect.TypeOf(&ResctrlMPAMReader{}), reflect.TypeOf(NewResctrlReader()))
+}
+
 // just for x86 system
 func TestResctrlReader(t *testing.T) {
 	type args struct {
@@ -297,5 +347,92 @@ func TestFakeReader(t *testing.T) {
 		mbmData, err := reader.ReadResctrlMBStat("")
 		assert.Nil(t, mbmData)
 		assert.Error(t, err)
+
+		l2Data, err := reader.ReadResctrlL2Stat("")
+		assert.Nil(t, l2Data)
+		assert.Error(t, err)
 	})
 }
+
+func TestResctrlReaderWithL2Mon(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("cpuinfo", "vendor_id       : GenuineIntel\n")
+
+	system.TestingPrepareResctrlMondata(t, system.Conf.SysFSRootDir, "BE", system.MockMonData{
+		CacheItems: map[int]system.MockCacheItem{
+			0: {"llc_occupancy": 11, "mbm_local_bytes": 21, "mbm_total_bytes": 31},
+		},
+	})
+	// the l2 domains should not be mixed up with the l3 domains
+	for domain, value := range map[string]string{"mon_L2_00": "5", "mon_L2_01": "7"} {
+		helper.WriteFileContents(filepath.Join(system.GetResctrlMonDataPath("BE"), domain, "llc_occupancy"), value)
+	}
+
+	rr := NewResctrlReader()
+	l3Stat, err := rr.ReadResctrlL3Stat("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]uint64{0: 11}, l3Stat)
+	mbStat, err := rr.ReadResctrlMBStat("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]system.MBStatData{
+		0: {"mbm_local_bytes": 21, "mbm_total_bytes": 31},
+	}, mbStat)
+	l2Stat, err := rr.ReadResctrlL2Stat("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]uint64{0: 5, 1: 7}, l2Stat)
+
+	// no l2 domain
+	system.TestingPrepareResctrlMondata(t, system.Conf.SysFSRootDir, "LS", system.MockMonData{
+		CacheItems: map[int]system.MockCacheItem{
+			0: {"llc_occupancy": 1},
+		},
+	})
+	l2Stat, err = rr.ReadResctrlL2Stat("LS")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]uint64{}, l2Stat)
+}
+
+func TestResctrlMPAMReader(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("cpuinfo", "processor       : 0\nCPU implementer : 0x48\n")
+
+	// the bandwidth monitors are located at the memory bandwidth domains
+	system.TestingPrepareResctrlMondata(t, system.Conf.SysFSRootDir, "BE", system.MockMonData{
+		CacheItems: map[int]system.MockCacheItem{
+			0: {"llc_occupancy": 11},
+			1: {"llc_occupancy": 41},
+		},
+	})
+	for domain, value := range map[string]string{"mon_MB_00": "31", "mon_MB_01": "61"} {
+		helper.WriteFileContents(filepath.Join(system.GetResctrlMonDataPath("BE"), domain, "mbm_total_bytes"), value)
+	}
+
+	rr := NewResctrlReader()
+	assert.Equal(t, reflect.TypeOf(&ResctrlMPAMReader{}), reflect.TypeOf(rr))
+	l3Stat, err := rr.ReadResctrlL3Stat("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]uint64{0: 11, 1: 41}, l3Stat)
+	mbStat, err := rr.ReadResctrlMBStat("BE")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]system.MBStatData{
+		0: {"mbm_total_bytes": 31},
+		1: {"mbm_total_bytes": 61},
+	}, mbStat)
+
+	// the bandwidth monitors are located at the L3 domains
+	system.TestingPrepareResctrlMondata(t, system.Conf.SysFSRootDir, "LS", system.MockMonData{
+		CacheItems: map[int]system.MockCacheItem{
+			0: {"llc_occupancy": 1, "mbm_local_bytes": 2, "mbm_total_bytes": 3},
+		},
+	})
+	mbStat, err = rr.ReadResctrlMBStat("LS")
+	assert.NoError(t, err)
+	assert.Equal(t, map[CacheId]system.MBStatData{
+		0: {"mbm_local_bytes": 2, "mbm_total_bytes": 3},
+	}, mbStat)
+
+	_, err = rr.ReadResctrlL3Stat("LSR")
+	assert.Error(t, err)
+}
@@ -77,6 +77,7 @@ func NewResctrlSchemataResource(group, schemata string, e *audit.EventHelper) (R
 	}{
 		{validFunc: schemataRaw.ValidateL3, value: schemataRaw.L3String},
 		{validFunc: schemataRaw.ValidateMB, value: schemataRaw.MBString},
+		{validFunc: schemataRaw.ValidateL2, value: schemataRaw.L2String},
 	} {
 		if valid, _ := item.validFunc(); valid {
 			items = append(items, item.value())
@@ -114,13 +115,19 @@ func NewCatGroupResource(group string, e *audit.EventHelper) (ResourceUpdater, e
 }
 
 func NewResctrlL3SchemataResource(group, schemataDelta string, l3Num int) ResourceUpdater {
+	return NewResctrlL3SchemataResourceWithDomains(group, schemataDelta, nil, l3Num)
+}
+
+// NewResctrlL3SchemataResourceWithDomains generates the updater of the l3 cat schemata, where the masks of the cache
+// ids in the domainDeltas take the place of the uniform mask.
+func NewResctrlL3SchemataResourceWithDomains(group, schemataDelta string, domainDeltas map[int]string, l3Num int) ResourceUpdater {
 	schemataFile := sysutil.ResctrlSchemata.Path(group)
 	l3SchemataKey := sysutil.L3SchemataPrefix + ":" + schemataFile
 	// The current assumption is that the cache ids obtained through
 	// resctrl schemata will not go wrong. TODO: Use the ability of node info
 	// to obtain cache ids to replace the current method.
 	ids, _ := sysutil.CacheIdsCacheFunc()
-	schemata := sysutil.NewResctrlSchemataRaw(ids).WithL3Num(l3Num).WithL3Mask(schemataDelta)
+	schemata := sysutil.NewResctrlSchemataRaw(ids).WithL3Num(l3Num).WithL3Mask(schemataDelta).WithL3DomainMasks(domainDeltas)
 	klog.V(6).Infof("generate new resctrl l3 schemata resource, file %s, key %s, value %s",
 		schemataFile, l3SchemataKey, schemata.L3String())
 
@@ -135,14 +142,54 @@ func NewResctrlL3SchemataResource(group, schemataDelta string, l3Num int) Resour
 	}
 }
 
+// NewResctrlL3CDPSchemataResource generates the updater of the l3 cat schemata when the Code/Data Prioritization is
+// enabled, which writes both the L3CODE and L3DATA lines. The code and data masks of the cache ids in the
+// domainCodeMasks and domainDataMasks are overridden respectively. It returns an error if the masks exceed the cbm.
+func NewResctrlL3CDPSchemataResource(group, codeMask, dataMask string, domainCodeMasks, domainDataMasks map[int]string,
+	l3Num int, cbm uint) (ResourceUpdater, error) {
+	schemataFile := sysutil.ResctrlSchemata.Path(group)
+	l3SchemataKey := sysutil.L3SchemataPrefix + ":" + schemataFile
+	ids, _ := sysutil.CacheIdsCacheFunc()
+	// keep the mba masks empty so that only the code and data masks are compared and written
+	schemata := &sysutil.ResctrlSchemataRaw{
+		L3:    map[int]int64{},
+		L3Num: l3Num,
+	}
+	for _, id := range ids {
+		schemata.L3[id] = 0
+	}
+	schemata.WithL3CDPMask(codeMask, dataMask).WithL3DomainCDPMasks(domainCodeMasks, domainDataMasks)
+	if valid, msg := schemata.ValidateL3MaskLength(cbm); !valid {
+		return nil, fmt.Errorf("invalid l3 cdp schemata %s, msg: %s", schemata.L3String(), msg)
+	}
+	klog.V(6).Infof("generate new resctrl l3 cdp schemata resource, file %s, key %s, value %s",
+		schemataFile, l3SchemataKey, schemata.L3String())
+
+	return &ResctrlSchemataResourceUpdater{
+		DefaultResourceUpdater: DefaultResourceUpdater{
+			key:        l3SchemataKey,
+			file:       schemataFile,
+			value:      schemata.L3String(),
+			updateFunc: UpdateResctrlSchemataFunc,
+		},
+		schemataRaw: schemata,
+	}, nil
+}
+
 func NewResctrlMbSchemataResource(group, schemataDelta string, l3Num int) ResourceUpdater {
+	return NewResctrlMbSchemataResourceWithDomains(group, schemataDelta, nil, l3Num)
+}
+
+// NewResctrlMbSchemataResourceWithDomains generates the updater of the mba schemata, where the values of the cache
+// ids in the domainDeltas take the place of the uniform value.
+func NewResctrlMbSchemataResourceWithDomains(group, schemataDelta string, domainDeltas map[int]string, l3Num int) ResourceUpdater {
 	schemataFile := sysutil.ResctrlSchemata.Path(group)
 	mbSchemataKey := sysutil.MbSchemataPrefix + ":" + schemataFile
 	// The current assumption is that the cache ids obtained through
 	// resctrl schemata will not go wrong. TODO: Use the ability of node info
 	// to obtain cache ids to replace the current method.
 	ids, _ := sysutil.CacheIdsCacheFunc()
-	schemata := sysutil.NewResctrlSchemataRaw(ids).WithL3Num(l3Num).WithMB(schemataDelta)
+	schemata := sysutil.NewResctrlSchemataRaw(ids).WithL3Num(l3Num).WithMB(schemataDelta).WithDomainMB(domainDeltas)
 	klog.V(6).Infof("generate new resctrl mba schemata resource, file %s, key %s, value %s",
 		schemataFile, mbSchemataKey, schemata.MBString())
 
@@ -157,6 +204,33 @@ func NewResctrlMbSchemataResource(group, schemataDelta string, l3Num int) Resour
 	}
 }
 
+// NewResctrlL2SchemataResource generates the updater of the l2 cat schemata, which is only available on the platforms
+// supporting the L2 CAT. The l2 cache ids are independent of the l3 ones.
+func NewResctrlL2SchemataResource(group, schemataDelta string) ResourceUpdater {
+	schemataFile := sysutil.ResctrlSchemata.Path(group)
+	l2SchemataKey := sysutil.L2SchemataPrefix + ":" + schemataFile
+	ids, _ := sysutil.CacheIdsCacheFunc()
+	l2Ids, _ := sysutil.L2CacheIdsCacheFunc()
+	// keep the l3 and mba masks empty so that only the l2 masks are compared and written
+	schemata := &sysutil.ResctrlSchemataRaw{
+		L2:    map[int]int64{},
+		L3Num: len(ids),
+	}
+	schemata.WithL2CacheIds(l2Ids).WithL2Mask(schemataDelta)
+	klog.V(6).Infof("generate new resctrl l2 schemata resource, file %s, key %s, value %s",
+		schemataFile, l2SchemataKey, schemata.L2String())
+
+	return &ResctrlSchemataResourceUpdater{
+		DefaultResourceUpdater: DefaultResourceUpdater{
+			key:        l2SchemataKey,
+			file:       schemataFile,
+			value:      schemata.L2String(),
+			updateFunc: UpdateResctrlSchemataFunc,
+		},
+		schemataRaw: schemata,
+	}
+}
+
 func CalculateResctrlL3TasksResource(group string, taskIds []int32) (ResourceUpdater, error) {
 	// join ids into updater value and make the id updates one by one
 	tasksPath := sysutil.GetResctrlTasksFilePath(group)
@@ -208,14 +282,14 @@ func UpdateResctrlSchemataFunc(u ResourceUpdater) error {
 
 	isEqual, msg := oldR.Equal(r.schemataRaw)
 	if isEqual { // schemata unchanged, no need to update
-		klog.V(6).Infof("skip update resctrl schemata, old l3 %s, mba %s, new %s, l3Num %v",
-			oldR.L3String(), oldR.MBString(), r.Value(), r.schemataRaw.L3Number())
+		klog.V(6).Infof("skip update resctrl schemata, old l3 %s, mba %s, l2 %s, new %s, l3Num %v",
+			oldR.L3String(), oldR.MBString(), oldR.L2String(), r.Value(), r.schemataRaw.L3Number())
 		return nil
 	}
-	klog.V(5).Infof("need to update resctrl schemata, old l3 %s, mba %s, new %s, l3Num %v, msg: %s",
-		oldR.L3String(), oldR.MBString(), r.Value(), r.schemataRaw.L3Number(), msg)
+	klog.V(5).Infof("need to update resctrl schemata, old l3 %s, mba %s, l2 %s, new %s, l3Num %v, msg: %s",
+		oldR.L3String(), oldR.MBString(), oldR.L2String(), r.Value(), r.schemataRaw.L3Number(), msg)
 
-	// NOTE: currently, only l3 and mba schemata are to update, so do not read or compare before the write
+	// NOTE: currently, only l3, mba and l2 schemata are to update, so do not read or compare before the write
 	// eg.
 	// $ cat /sys/fs/resctrl/schemata/BE/schemata
 	// L3:0=7ff;1=7ff
@@ -93,6 +93,11 @@ func TestNewResctrlL3SchemataResource(t *testing.T) {
 		assert.Equal(t, updater.Value(), "L3:0=3c;1=3c;\n")
 		err := updater.update()
 		assert.NoError(t, err)
+
+		updater = NewResctrlL3SchemataResourceWithDomains("BE", "3c", map[int]string{1: "f"}, 2)
+		assert.Equal(t, updater.Value(), "L3:0=3c;1=f;\n")
+		err = updater.update()
+		assert.NoError(t, err)
 	})
 }
 
@@ -110,9 +115,76 @@ func TestNewResctrlMbSchemataResource(t *testing.T) {
 		assert.Equal(t, updater.Value(), "MB:0=90;1=90;\n")
 		err := updater.update()
 		assert.NoError(t, err)
+
+		updater = NewResctrlMbSchemataResourceWithDomains("BE", "90", map[int]string{0: "50"}, 2)
+		assert.Equal(t, updater.Value(), "MB:0=50;1=90;\n")
+		err = updater.update()
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewResctrlL2SchemataResource(t *testing.T) {
+	t.Run("test", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+
+		sysFSRootDirName := "NewResctrlL2SchemataResource"
+		helper.MkDirAll(sysFSRootDirName)
+		system.Conf.SysFSRootDir = filepath.Join(helper.TempDir, sysFSRootDirName)
+		oldCacheIdsFunc, oldL2CacheIdsFunc := system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc
+		system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc = system.GetCacheIds, system.GetL2CacheIds
+		defer func() {
+			system.CacheIdsCacheFunc, system.L2CacheIdsCacheFunc = oldCacheIdsFunc, oldL2CacheIdsFunc
+		}()
+
+		testingPrepareResctrlL3CatGroups(t, "7ff", "    L3:0=ff;1=ff\n    MB:0=100;1=100\n    L2:0=ff;1=ff;2=ff;3=ff")
+		updater := NewResctrlL2SchemataResource("BE", "f0")
+		assert.Equal(t, "L2:0=f0;1=f0;2=f0;3=f0;\n", updater.Value())
+		err := updater.update()
+		assert.NoError(t, err)
 	})
 }
 
+func TestNewResctrlL3CDPSchemataResource(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldCacheIdsFunc := system.CacheIdsCacheFunc
+	system.CacheIdsCacheFunc = system.GetCacheIds
+	defer func() {
+		system.CacheIdsCacheFunc = oldCacheIdsFunc
+	}()
+
+	resctrlDir := system.GetResctrlSubsystemDirPath()
+	helper.WriteFileContents(filepath.Join(resctrlDir, system.RdtInfoDir, system.L3CodeCatDir, system.ResctrlCbmMaskName), "7ff\n")
+	helper.WriteFileContents(filepath.Join(resctrlDir, system.RdtInfoDir, system.L3DataCatDir, system.ResctrlCbmMaskName), "7ff\n")
+	helper.WriteFileContents(filepath.Join(resctrlDir, system.ResctrlSchemataName),
+		"    L3CODE:0=7ff;1=7ff\n    L3DATA:0=7ff;1=7ff\n    MB:0=100;1=100\n")
+	helper.WriteFileContents(system.GetResctrlSchemataFilePath("BE"),
+		"    L3CODE:0=7ff;1=7ff\n    L3DATA:0=7ff;1=7ff\n    MB:0=100;1=100\n")
+	assert.True(t, system.IsResctrlL3CDPEnabled())
+
+	updater, err := NewResctrlL3CDPSchemataResource("BE", "f", "f0", nil, nil, 2, 0x7ff)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f;1=f;\nL3DATA:0=f0;1=f0;\n", updater.Value())
+	err = updater.update()
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f;1=f;\nL3DATA:0=f0;1=f0;\n", helper.ReadFileContents(system.GetResctrlSchemataFilePath("BE")))
+
+	// the masks of the domain are overridden
+	updater, err = NewResctrlL3CDPSchemataResource("BE", "f", "f0", map[int]string{1: "3"}, map[int]string{1: "30"}, 2, 0x7ff)
+	assert.NoError(t, err)
+	assert.Equal(t, "L3CODE:0=f;1=3;\nL3DATA:0=f0;1=30;\n", updater.Value())
+
+	// the mask exceeds the cbm
+	_, err = NewResctrlL3CDPSchemataResource("BE", "fff", "f0", nil, nil, 2, 0x7ff)
+	assert.Error(t, err)
+	_, err = NewResctrlL3CDPSchemataResource("BE", "f", "f0", nil, map[int]string{0: "fff"}, 2, 0x7ff)
+	assert.Error(t, err)
+	// the l3 number mismatches the cache ids
+	_, err = NewResctrlL3CDPSchemataResource("BE", "f", "f0", nil, nil, 1, 0x7ff)
+	assert.Error(t, err)
+}
+
 func TestNewResctrlSchemataResource(t *testing.T) {
 	t.Run("test_all_schemata", func(t *testing.T) {
 		helper := system.NewFileTestUtil(t)
@@ -142,6 +214,20 @@ func TestNewResctrlSchemataResource(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	t.Run("test_L2_resource", func(t *testing.T) {
+		helper := system.NewFileTestUtil(t)
+		defer helper.Cleanup()
+
+		sysFSRootDirName := "NewResctrlSchemataResourceWithL2"
+		helper.MkDirAll(sysFSRootDirName)
+		system.Conf.SysFSRootDir = filepath.Join(helper.TempDir, sysFSRootDirName)
+		testingPrepareResctrlL3CatGroups(t, "7ff", "    L3:0=ff;1=ff\n    L2:0=ff;1=ff")
+		updater, _ := NewResctrlSchemataResource("BE", "L3:0=f;1=f\nL2:0=3;1=3", nil)
+		assert.Equal(t, "L3:0=f;1=f;\nL2:0=3;1=3;\n", updater.Value())
+		err := updater.update()
+		assert.NoError(t, err)
+	})
+
 	t.Run("test_MB_resource", func(t *testing.T) {
 		helper := system.NewFileTestUtil(t)
 		defer helper.Cleanup()
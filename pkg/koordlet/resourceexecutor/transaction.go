@@ -0,0 +1,138 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"context"
+	"fmt"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// updateTransaction records the prior values of the applied updates, so they can be rolled back when a later update
+// in the transaction fails. The prior value is the current content of the file, so the transaction only applies to
+// the resources whose content can be written back, e.g. `cpuset.cpus`, `cpuset.mems` and `memory.min`.
+type updateTransaction struct {
+	executor  *ResourceUpdateExecutorImpl
+	cacheable bool
+	// rollbacks are the updaters to restore the prior values in the order of the applied updates
+	rollbacks []ResourceUpdater
+}
+
+func (e *ResourceUpdateExecutorImpl) UpdateTransaction(ctx context.Context, cacheable bool, updaters ...ResourceUpdater) error {
+	if cacheable && !e.gcStarted {
+		klog.V(5).Info("failed to cacheable update resources in transaction, err: cache GC is not started")
+		return fmt.Errorf("cache GC is not started")
+	}
+	t := &updateTransaction{
+		executor:  e,
+		cacheable: cacheable,
+	}
+	for _, updater := range updaters {
+		err := t.apply(ctx, updater)
+		if err == nil {
+			continue
+		}
+		if rollbackErr := t.rollback(ctx); rollbackErr != nil {
+			klog.Warningf("failed to rollback %v resources in transaction, err: %v", len(t.rollbacks), rollbackErr)
+			return fmt.Errorf("failed to update resource %s, err: %w, rollback err: %v", updater.Key(), err, rollbackErr)
+		}
+		klog.V(4).Infof("rollback %v resources in transaction since failed to update resource %s, err: %v",
+			len(t.rollbacks), updater.Key(), err)
+		return fmt.Errorf("failed to update resource %s, err: %w", updater.Key(), err)
+	}
+	klog.V(6).Infof("finished updating resources in transaction, isCacheable %v, total %v", cacheable, len(updaters))
+	return nil
+}
+
+// apply records the prior value of the resource and then updates it.
+func (t *updateTransaction) apply(ctx context.Context, updater ResourceUpdater) error {
+	rollback, err := newRollbackUpdater(updater)
+	if err != nil && !t.executor.isUpdateErrIgnored(err) {
+		return fmt.Errorf("failed to record the prior value, err: %w", err)
+	} else if err != nil {
+		// the resource is not written if the error is ignored, so there is nothing to rollback
+		rollback = nil
+	}
+
+	updated := true
+	if t.cacheable {
+		updated, err = t.executor.updateByCache(ctx, updater)
+	} else {
+		err = t.executor.update(ctx, updater)
+	}
+	if err != nil {
+		return err
+	}
+	if updated && rollback != nil && !t.executor.isDryRun(updater) {
+		t.rollbacks = append(t.rollbacks, rollback)
+	}
+	return nil
+}
+
+// rollback restores the prior values of the applied updates in the reverse order.
+// It tries to restore all the resources even if some of them fail.
+func (t *updateTransaction) rollback(ctx context.Context) error {
+	var errs []error
+	for i := len(t.rollbacks) - 1; i >= 0; i-- {
+		rollback := t.rollbacks[i]
+		var err error
+		if t.cacheable {
+			// the cache is updated with the prior value, so the next update is not skipped
+			_, err = t.executor.updateByCache(ctx, rollback)
+		} else {
+			err = t.executor.update(ctx, rollback)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to rollback resource %s to %v, err: %w", rollback.Key(), rollback.Value(), err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// newRollbackUpdater returns the updater which writes the current value of the resource.
+func newRollbackUpdater(updater ResourceUpdater) (ResourceUpdater, error) {
+	switch u := updater.(type) {
+	case *CgroupResourceUpdater:
+		value, err := cgroupFileRead(u.parentDir, u.file)
+		if err != nil {
+			return nil, err
+		}
+		return &CgroupResourceUpdater{
+			file:       u.file,
+			parentDir:  u.parentDir,
+			value:      value,
+			updateFunc: u.updateFunc,
+		}, nil
+	case *DefaultResourceUpdater:
+		value, err := sysutil.CommonFileRead(u.file)
+		if err != nil {
+			return nil, err
+		}
+		return &DefaultResourceUpdater{
+			key:        u.key,
+			file:       u.file,
+			value:      value,
+			updateFunc: u.updateFunc,
+		}, nil
+	default:
+		return nil, fmt.Errorf("rollback is unsupported for the updater %s", updater.Name())
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resourceexecutor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/audit"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/cache"
+)
+
+func TestResourceUpdateExecutor_UpdateTransaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		isCacheable bool
+		cpus        string
+		mems        string
+		wantErr     bool
+		wantCPUs    string
+		wantMems    string
+	}{
+		{
+			name:        "non-cacheable update in transaction",
+			isCacheable: false,
+			cpus:        "0-7",
+			mems:        "0-1",
+			wantCPUs:    "0-7",
+			wantMems:    "0-1",
+		},
+		{
+			name:        "cacheable update in transaction",
+			isCacheable: true,
+			cpus:        "0-7",
+			mems:        "0-1",
+			wantCPUs:    "0-7",
+			wantMems:    "0-1",
+		},
+		{
+			name:        "rollback applied updates when a later update fails",
+			isCacheable: false,
+			cpus:        "0-7",
+			mems:        "invalid content",
+			wantErr:     true,
+			wantCPUs:    "0-3",
+			wantMems:    "0",
+		},
+		{
+			name:        "rollback applied cacheable updates when a later update fails",
+			isCacheable: true,
+			cpus:        "0-7",
+			mems:        "invalid content",
+			wantErr:     true,
+			wantCPUs:    "0-3",
+			wantMems:    "0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.WriteCgroupFileContents("test", sysutil.CPUSet, "0-3")
+			helper.WriteCgroupFileContents("test", sysutil.CPUSetMems, "0")
+
+			e := &ResourceUpdateExecutorImpl{
+				ResourceCache: cache.NewCacheDefault(),
+				Config:        NewDefaultConfig(),
+			}
+			stop := make(chan struct{})
+			defer close(stop)
+			e.Run(stop)
+
+			cpusUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, "test", tt.cpus, &audit.EventHelper{})
+			assert.NoError(t, err)
+			memsUpdater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemsName, "test", tt.mems, &audit.EventHelper{})
+			assert.NoError(t, err)
+
+			gotErr := e.UpdateTransaction(context.TODO(), tt.isCacheable, cpusUpdater, memsUpdater)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.wantCPUs, helper.ReadCgroupFileContents("test", sysutil.CPUSet))
+			assert.Equal(t, tt.wantMems, helper.ReadCgroupFileContents("test", sysutil.CPUSetMems))
+		})
+	}
+}
+
+func TestResourceUpdateExecutor_UpdateTransactionNotStarted(t *testing.T) {
+	e := &ResourceUpdateExecutorImpl{
+		ResourceCache: cache.NewCacheDefault(),
+		Config:        NewDefaultConfig(),
+	}
+	updater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, "test", "0-7", &audit.EventHelper{})
+	assert.NoError(t, err)
+	assert.Error(t, e.UpdateTransaction(context.TODO(), true, updater))
+}
+
+func Test_newRollbackUpdater(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteCgroupFileContents("test", sysutil.CPUSet, "0-3")
+
+	updater, err := DefaultCgroupUpdaterFactory.New(sysutil.CPUSetCPUSName, "test", "0-7", &audit.EventHelper{})
+	assert.NoError(t, err)
+	rollback, err := newRollbackUpdater(updater)
+	assert.NoError(t, err)
+	assert.Equal(t, updater.Key(), rollback.Key())
+	assert.Equal(t, "0-3", rollback.Value())
+
+	_, err = newRollbackUpdater(&ResctrlSchemataResourceUpdater{})
+	assert.Error(t, err)
+}
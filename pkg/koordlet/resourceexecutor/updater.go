@@ -42,11 +42,16 @@ func init() {
 	DefaultCgroupUpdaterFactory.Register(NewCgroupUpdaterWithUpdateFunc(CgroupUpdateWithUnlimitedFunc),
 		sysutil.CPUCFSPeriodName,
 		sysutil.MemoryLimitName,
+		sysutil.MemoryZswapMaxName,
+		sysutil.HugetlbLimit2MName,
+		sysutil.HugetlbLimit1GName,
 	)
 	DefaultCgroupUpdaterFactory.Register(NewCommonCgroupUpdater,
 		sysutil.CPUBurstName,
 		sysutil.CPUBVTWarpNsName,
 		sysutil.CPUIdleName,
+		sysutil.CPUUClampMinName,
+		sysutil.CPUUClampMaxName,
 		sysutil.CPUTasksName,
 		sysutil.CPUProcsName,
 		sysutil.MemoryWmarkRatioName,
@@ -55,6 +60,8 @@ func init() {
 		sysutil.MemoryPriorityName,
 		sysutil.MemoryUsePriorityOomName,
 		sysutil.MemoryOomGroupName,
+		sysutil.MemoryNumaBalancingName,
+		sysutil.MemoryZswapWritebackName,
 		sysutil.NetClsClassIdName,
 	)
 	// special cases
@@ -69,6 +76,7 @@ func init() {
 	)
 	DefaultCgroupUpdaterFactory.Register(NewMergeableCgroupUpdaterWithConditionFunc(CommonCgroupUpdateFunc, MergeConditionIfCPUSetIsLooser),
 		sysutil.CPUSetCPUSName,
+		sysutil.CPUSetMemsName,
 	)
 	DefaultCgroupUpdaterFactory.Register(NewBlkIOResourceUpdater,
 		sysutil.BlkioTRIopsName,
@@ -550,18 +558,27 @@ func commonWriteIfDifferentWithLog(c *DefaultResourceUpdater) error {
 
 func BlkIOUpdateFunc(resource ResourceUpdater) error {
 	info := resource.(*CgroupResourceUpdater)
-	return cgroupBlkIOFileWriteIfDifferent(info.parentDir, info.file, info.Value())
+	updated, err := cgroupBlkIOFileWriteIfDifferent(info.parentDir, info.file, info.Value())
+	if err != nil {
+		return err
+	}
+	if updated && info.eventHelper != nil {
+		_ = info.eventHelper.Do()
+	} else if updated {
+		_ = audit.V(3).Reason(ReasonUpdateCgroups).Message("update %v to %v", info.Path(), info.Value()).Do()
+	}
+	return nil
 }
 
 func NewBlkIOResourceUpdater(resourceType sysutil.ResourceType, parentDir string, value string, e *audit.EventHelper) (ResourceUpdater, error) {
 	return NewCgroupUpdaterWithUpdateFunc(BlkIOUpdateFunc)(resourceType, parentDir, value, e)
 }
 
-func cgroupBlkIOFileWriteIfDifferent(cgroupTaskDir string, file sysutil.Resource, value string) error {
+func cgroupBlkIOFileWriteIfDifferent(cgroupTaskDir string, file sysutil.Resource, value string) (bool, error) {
 	var needUpdate bool
 	currentValue, currentErr := cgroupFileRead(cgroupTaskDir, file)
 	if currentErr != nil {
-		return currentErr
+		return false, currentErr
 	}
 
 	switch file.ResourceType() {
@@ -570,16 +587,19 @@ func cgroupBlkIOFileWriteIfDifferent(cgroupTaskDir string, file sysutil.Resource
 	case sysutil.BlkioTRIopsName, sysutil.BlkioTRBpsName, sysutil.BlkioTWIopsName, sysutil.BlkioTWBpsName, sysutil.BlkioIOWeightName:
 		needUpdate = CheckIfBlkQOSNeedUpdate(currentValue, value)
 	default:
-		return fmt.Errorf("unknown blkio resource file %s", file.ResourceType())
+		return false, fmt.Errorf("unknown blkio resource file %s", file.ResourceType())
 	}
 
 	if !needUpdate {
 		klog.V(6).Infof("no need to update blk cgroup file %s/%s: currentValue is %s, value is %s", cgroupTaskDir, file.ResourceType(), currentValue, value)
-		return nil
+		return false, nil
 	}
 
 	klog.V(6).Infof("need to update blk cgroup file %s/%s: currentValue is %s, value is %s", cgroupTaskDir, file.ResourceType(), currentValue, value)
-	return cgroupFileWrite(cgroupTaskDir, file, value)
+	if err := cgroupFileWrite(cgroupTaskDir, file, value); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // https://www.alibabacloud.com/help/en/elastic-compute-service/latest/configure-the-weight-based-throttling-feature-of-blk-iocost
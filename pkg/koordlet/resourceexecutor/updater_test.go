@@ -179,6 +179,106 @@ func TestCgroupResourceUpdater_Update(t *testing.T) {
 	}
 }
 
+func TestCgroupResourceUpdater_UpdateCgroupsV2Only(t *testing.T) {
+	type args struct {
+		resourceType sysutil.ResourceType
+		parentDir    string
+		value        string
+	}
+	tests := []struct {
+		name         string
+		initialValue string
+		args         args
+		wantFile     sysutil.Resource
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "update io.cost.qos in root cgroup",
+			initialValue: "253:16 enable=0 ctrl=auto rpct=0.00 rlat=250000 wpct=0.00 wlat=250000 min=1.00 max=10000.00",
+			args: args{
+				resourceType: sysutil.BlkioIOQoSName,
+				parentDir:    "",
+				value:        "253:16 enable=1 ctrl=user rpct=95 rlat=3000 wpct=95 wlat=4000",
+			},
+			wantFile: sysutil.BlkioIOQoSV2,
+			want:     "253:16 enable=1 ctrl=user rpct=95 rlat=3000 wpct=95 wlat=4000",
+		},
+		{
+			name:         "update io.cost.model in root cgroup",
+			initialValue: "253:16 ctrl=auto model=linear rbps=174019176 rseqiops=41708 rrandiops=370 wbps=178075866 wseqiops=42705 wrandiops=378",
+			args: args{
+				resourceType: sysutil.BlkioIOModelName,
+				parentDir:    "",
+				value:        "253:16 ctrl=user rbps=3324911720 rseqiops=168274 rrandiops=352545 wbps=2765819289 wseqiops=367565 wrandiops=339390",
+			},
+			wantFile: sysutil.BlkioIOModelV2,
+			want:     "253:16 ctrl=user rbps=3324911720 rseqiops=168274 rrandiops=352545 wbps=2765819289 wseqiops=367565 wrandiops=339390",
+		},
+		{
+			name:         "update io.weight",
+			initialValue: "default 100",
+			args: args{
+				resourceType: sysutil.BlkioIOWeightName,
+				parentDir:    "/kubepods.slice",
+				value:        "253:16 1000",
+			},
+			wantFile: sysutil.BlkioIOWeightV2,
+			want:     "253:16 1000",
+		},
+		{
+			name:         "update cpu.uclamp.min",
+			initialValue: "0.00",
+			args: args{
+				resourceType: sysutil.CPUUClampMinName,
+				parentDir:    "/kubepods.slice",
+				value:        "20.5",
+			},
+			wantFile: sysutil.CPUUClampMinV2,
+			want:     "20.5",
+		},
+		{
+			name:         "update cpu.uclamp.max",
+			initialValue: "max",
+			args: args{
+				resourceType: sysutil.CPUUClampMaxName,
+				parentDir:    "/kubepods.slice",
+				value:        "80",
+			},
+			wantFile: sysutil.CPUUClampMaxV2,
+			want:     "80",
+		},
+		{
+			name:         "failed to update cpu.uclamp.max with invalid value",
+			initialValue: "max",
+			args: args{
+				resourceType: sysutil.CPUUClampMaxName,
+				parentDir:    "/kubepods.slice",
+				value:        "120",
+			},
+			wantFile: sysutil.CPUUClampMaxV2,
+			want:     "max",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(true)
+			helper.WriteCgroupFileContents(tt.args.parentDir, tt.wantFile, tt.initialValue)
+
+			u, gotErr := DefaultCgroupUpdaterFactory.New(tt.args.resourceType, tt.args.parentDir, tt.args.value, nil)
+			assert.NoError(t, gotErr)
+			assert.Equal(t, tt.wantFile.Path(tt.args.parentDir), u.Path())
+
+			gotErr = u.update()
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, helper.ReadCgroupFileContents(tt.args.parentDir, tt.wantFile))
+		})
+	}
+}
+
 func TestCgroupResourceUpdater_MergeUpdate(t *testing.T) {
 	type fields struct {
 		UseCgroupsV2 bool
@@ -32,10 +32,16 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/cpuset"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/gpu"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/groupidentity"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/guestqos"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/hugepages"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/netpriority"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/numabalancing"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/rdma"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/resctrl"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/shm"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/tc"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/terwayqos"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/zswap"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
@@ -101,6 +107,28 @@ const (
 	// owner: @kangclzjc @saintube @zwzhang0107
 	// alpha: v1.5
 	Resctrl featuregate.Feature = "Resctrl"
+
+	// NUMABalancing toggles the kernel automatic NUMA balancing for the memory pinned pods according to the memory QoS.
+	NUMABalancing featuregate.Feature = "NUMABalancing"
+
+	// Zswap sets the zswap limit and writeback for pods according to the memory QoS, e.g. directing BE pods to the
+	// compressed swap while LS pods bypass it.
+	Zswap featuregate.Feature = "Zswap"
+
+	// ShmSizeInject mounts a sized /dev/shm into the container according to the pod annotation or the gpu allocation.
+	ShmSizeInject featuregate.Feature = "ShmSizeInject"
+
+	// GuestQoSInject passes the QoS class hint into the guest of the sandboxed runtimes like Kata via the
+	// container annotations, so the in-guest scheduler can mirror the host-side prioritization.
+	GuestQoSInject featuregate.Feature = "GuestQoSInject"
+
+	// HugePages reserves the hugepages on the NUMA nodes allocated to the pod before the sandbox starts, and sets the
+	// hugetlb limit of the pod.
+	HugePages featuregate.Feature = "HugePages"
+
+	// NetworkPriority stamps the cgroups-v2 pod cgroup with the network priority by the qos class before the sandbox
+	// starts, so the tc or ebpf netqos policy classifies the traffic of the hostNetwork containers since they start.
+	NetworkPriority featuregate.Feature = "NetworkPriority"
 )
 
 var (
@@ -115,6 +143,12 @@ var (
 		TerwayQoS:        {Default: false, PreRelease: featuregate.Alpha},
 		TCNetworkQoS:     {Default: false, PreRelease: featuregate.Alpha},
 		Resctrl:          {Default: false, PreRelease: featuregate.Alpha},
+		NUMABalancing:    {Default: false, PreRelease: featuregate.Alpha},
+		Zswap:            {Default: false, PreRelease: featuregate.Alpha},
+		ShmSizeInject:    {Default: false, PreRelease: featuregate.Alpha},
+		GuestQoSInject:   {Default: false, PreRelease: featuregate.Alpha},
+		HugePages:        {Default: false, PreRelease: featuregate.Alpha},
+		NetworkPriority:  {Default: false, PreRelease: featuregate.Alpha},
 	}
 
 	runtimeHookPlugins = map[featuregate.Feature]HookPlugin{
@@ -128,48 +162,60 @@ var (
 		TerwayQoS:        terwayqos.Object(),
 		TCNetworkQoS:     tc.Object(),
 		Resctrl:          resctrl.Object(),
+		NUMABalancing:    numabalancing.Object(),
+		Zswap:            zswap.Object(),
+		ShmSizeInject:    shm.Object(),
+		GuestQoSInject:   guestqos.Object(),
+		HugePages:        hugepages.Object(),
+		NetworkPriority:  netpriority.Object(),
 	}
 )
 
 type Config struct {
-	RuntimeHooksNetwork             string
-	RuntimeHooksAddr                string
-	RuntimeHooksFailurePolicy       string
-	RuntimeHooksPluginFailurePolicy string
-	RuntimeHookConfigFilePath       string
-	RuntimeHookHostEndpoint         string
-	RuntimeHookDisableStages        []string
-	RuntimeHooksNRI                 bool
-	RuntimeHooksNRIConnectTimeout   time.Duration
-	RuntimeHooksNRIBackOffDuration  time.Duration
-	RuntimeHooksNRIBackOffCap       time.Duration
-	RuntimeHooksNRIBackOffFactor    float64
-	RuntimeHooksNRIBackOffSteps     int
-	RuntimeHooksNRISocketPath       string
-	RuntimeHooksNRIPluginName       string
-	RuntimeHooksNRIPluginIndex      string
-	RuntimeHookReconcileInterval    time.Duration
+	RuntimeHooksNetwork               string
+	RuntimeHooksAddr                  string
+	RuntimeHooksFailurePolicy         string
+	RuntimeHooksPluginFailurePolicy   string
+	RuntimeHookConfigFilePath         string
+	RuntimeHookHostEndpoint           string
+	RuntimeHookDisableStages          []string
+	RuntimeHooksNRI                   bool
+	RuntimeHooksNRIConnectTimeout     time.Duration
+	RuntimeHooksNRIBackOffDuration    time.Duration
+	RuntimeHooksNRIBackOffCap         time.Duration
+	RuntimeHooksNRIBackOffFactor      float64
+	RuntimeHooksNRIBackOffSteps       int
+	RuntimeHooksNRISocketPath         string
+	RuntimeHooksNRIPluginName         string
+	RuntimeHooksNRIPluginIndex        string
+	RuntimeHooksModeSyncInterval      time.Duration
+	RuntimeHooksProxyServedExpiration time.Duration
+	RuntimeHookReconcileInterval      time.Duration
+	RuntimeHookKoordSchedulerNames    []string
 }
 
 func NewDefaultConfig() *Config {
 	return &Config{
-		RuntimeHooksNetwork:             "unix",
-		RuntimeHooksAddr:                "/host-var-run-koordlet/koordlet.sock",
-		RuntimeHooksFailurePolicy:       "Ignore",
-		RuntimeHooksPluginFailurePolicy: "Ignore",
-		RuntimeHookConfigFilePath:       system.Conf.RuntimeHooksConfigDir,
-		RuntimeHookHostEndpoint:         "/var/run/koordlet/koordlet.sock",
-		RuntimeHookDisableStages:        []string{},
-		RuntimeHooksNRI:                 true,
-		RuntimeHooksNRIConnectTimeout:   6 * time.Second,
-		RuntimeHooksNRIBackOffDuration:  1 * time.Second,
-		RuntimeHooksNRIBackOffCap:       1<<62 - 1,
-		RuntimeHooksNRIBackOffSteps:     math.MaxInt32,
-		RuntimeHooksNRIBackOffFactor:    2,
-		RuntimeHooksNRISocketPath:       "nri/nri.sock",
-		RuntimeHooksNRIPluginName:       "koordlet_nri",
-		RuntimeHooksNRIPluginIndex:      "00",
-		RuntimeHookReconcileInterval:    10 * time.Second,
+		RuntimeHooksNetwork:               "unix",
+		RuntimeHooksAddr:                  "/host-var-run-koordlet/koordlet.sock",
+		RuntimeHooksFailurePolicy:         "Ignore",
+		RuntimeHooksPluginFailurePolicy:   "Ignore",
+		RuntimeHookConfigFilePath:         system.Conf.RuntimeHooksConfigDir,
+		RuntimeHookHostEndpoint:           "/var/run/koordlet/koordlet.sock",
+		RuntimeHookDisableStages:          []string{},
+		RuntimeHooksNRI:                   true,
+		RuntimeHooksNRIConnectTimeout:     6 * time.Second,
+		RuntimeHooksNRIBackOffDuration:    1 * time.Second,
+		RuntimeHooksNRIBackOffCap:         1<<62 - 1,
+		RuntimeHooksNRIBackOffSteps:       math.MaxInt32,
+		RuntimeHooksNRIBackOffFactor:      2,
+		RuntimeHooksNRISocketPath:         "nri/nri.sock",
+		RuntimeHooksNRIPluginName:         "koordlet_nri",
+		RuntimeHooksNRIPluginIndex:        "00",
+		RuntimeHooksModeSyncInterval:      30 * time.Second,
+		RuntimeHooksProxyServedExpiration: 10 * time.Minute,
+		RuntimeHookReconcileInterval:      10 * time.Second,
+		RuntimeHookKoordSchedulerNames:    []string{"koord-scheduler"},
 	}
 }
 
@@ -188,9 +234,12 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.RuntimeHooksNRISocketPath, "runtime-hooks-nri-socket-path", c.RuntimeHooksNRISocketPath, "nri server socket path")
 	fs.StringVar(&c.RuntimeHooksNRIPluginName, "runtime-hooks-nri-plugin-name", c.RuntimeHooksNRISocketPath, "nri plugin name of the koordlet runtime hooks")
 	fs.StringVar(&c.RuntimeHooksNRIPluginIndex, "runtime-hooks-nri-plugin-index", c.RuntimeHooksNRIPluginIndex, "nri plugin index of the koordlet runtime hooks")
+	fs.DurationVar(&c.RuntimeHooksModeSyncInterval, "runtime-hooks-mode-sync-interval", c.RuntimeHooksModeSyncInterval, "interval to retry the nri mode when it is unavailable and report the runtime hook modes to the node annotation, non-positive value disables it")
+	fs.DurationVar(&c.RuntimeHooksProxyServedExpiration, "runtime-hooks-proxy-served-expiration", c.RuntimeHooksProxyServedExpiration, "the proxy mode is considered unavailable if no hook request has been served by the runtime proxy within the expiration")
 	fs.Var(cliflag.NewStringSlice(&c.RuntimeHookDisableStages), "runtime-hooks-disable-stages", "disable stages for runtime hooks")
 	fs.BoolVar(&c.RuntimeHooksNRI, "enable-nri-runtime-hook", c.RuntimeHooksNRI, "enable/disable runtime hooks nri mode")
 	fs.DurationVar(&c.RuntimeHookReconcileInterval, "runtime-hooks-reconcile-interval", c.RuntimeHookReconcileInterval, "reconcile interval for each plugins")
+	fs.Var(cliflag.NewStringSlice(&c.RuntimeHookKoordSchedulerNames), "runtime-hooks-koord-scheduler-names", "scheduler names of koord-scheduler, the cpusets of pods bound by other schedulers are checked for conflicts")
 }
 
 func init() {
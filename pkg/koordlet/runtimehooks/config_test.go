@@ -29,23 +29,26 @@ import (
 
 func Test_NewDefaultConfig(t *testing.T) {
 	expectConfig := &Config{
-		RuntimeHooksNetwork:             "unix",
-		RuntimeHooksAddr:                "/host-var-run-koordlet/koordlet.sock",
-		RuntimeHooksFailurePolicy:       "Ignore",
-		RuntimeHooksPluginFailurePolicy: "Ignore",
-		RuntimeHookConfigFilePath:       system.Conf.RuntimeHooksConfigDir,
-		RuntimeHookHostEndpoint:         "/var/run/koordlet/koordlet.sock",
-		RuntimeHookDisableStages:        []string{},
-		RuntimeHooksNRI:                 true,
-		RuntimeHooksNRIConnectTimeout:   6 * time.Second,
-		RuntimeHooksNRIBackOffDuration:  1 * time.Second,
-		RuntimeHooksNRIBackOffCap:       1<<62 - 1,
-		RuntimeHooksNRIBackOffSteps:     math.MaxInt32,
-		RuntimeHooksNRIBackOffFactor:    2,
-		RuntimeHooksNRISocketPath:       "nri/nri.sock",
-		RuntimeHooksNRIPluginName:       "koordlet_nri",
-		RuntimeHooksNRIPluginIndex:      "00",
-		RuntimeHookReconcileInterval:    10 * time.Second,
+		RuntimeHooksNetwork:               "unix",
+		RuntimeHooksAddr:                  "/host-var-run-koordlet/koordlet.sock",
+		RuntimeHooksFailurePolicy:         "Ignore",
+		RuntimeHooksPluginFailurePolicy:   "Ignore",
+		RuntimeHookConfigFilePath:         system.Conf.RuntimeHooksConfigDir,
+		RuntimeHookHostEndpoint:           "/var/run/koordlet/koordlet.sock",
+		RuntimeHookDisableStages:          []string{},
+		RuntimeHooksNRI:                   true,
+		RuntimeHooksNRIConnectTimeout:     6 * time.Second,
+		RuntimeHooksNRIBackOffDuration:    1 * time.Second,
+		RuntimeHooksNRIBackOffCap:         1<<62 - 1,
+		RuntimeHooksNRIBackOffSteps:       math.MaxInt32,
+		RuntimeHooksNRIBackOffFactor:      2,
+		RuntimeHooksNRISocketPath:         "nri/nri.sock",
+		RuntimeHooksNRIPluginName:         "koordlet_nri",
+		RuntimeHooksNRIPluginIndex:        "00",
+		RuntimeHooksModeSyncInterval:      30 * time.Second,
+		RuntimeHooksProxyServedExpiration: 10 * time.Minute,
+		RuntimeHookReconcileInterval:      10 * time.Second,
+		RuntimeHookKoordSchedulerNames:    []string{"koord-scheduler"},
 	}
 	defaultConfig := NewDefaultConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 
+	corev1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
@@ -136,7 +137,7 @@ func (p *plugin) SetPodCPUShares(proto protocol.HooksProtocol) error {
 	}
 
 	milliCPURequest := int64(0)
-	// TODO: count init container and pod overhead
+	// TODO: count pod overhead
 	for _, c := range extendedResourceSpec.Containers {
 		if c.Requests == nil {
 			continue
@@ -147,6 +148,13 @@ func (p *plugin) SetPodCPUShares(proto protocol.HooksProtocol) error {
 		}
 		milliCPURequest += containerRequest
 	}
+	// init containers run one by one before the containers, so take the max of the sum and any init container
+	for _, c := range extendedResourceSpec.InitContainers {
+		if c.Requests == nil {
+			continue
+		}
+		milliCPURequest = util.MaxInt64(milliCPURequest, util.GetBatchMilliCPUFromResourceList(c.Requests))
+	}
 
 	cpuShares := sysutil.MilliCPUToShares(milliCPURequest)
 	podCtx.Response.Resources.CPUShares = pointer.Int64(cpuShares)
@@ -179,20 +187,8 @@ func (p *plugin) SetPodCFSQuota(proto protocol.HooksProtocol) error {
 		return nil
 	}
 
-	milliCPULimit := int64(0)
-	// TODO: count init container and pod overhead
-	for _, c := range extendedResourceSpec.Containers {
-		if c.Limits == nil {
-			milliCPULimit = -1
-			break
-		}
-		containerLimit := util.GetBatchMilliCPUFromResourceList(c.Limits)
-		if containerLimit <= 0 { // pod unlimited once a container is unlimited
-			milliCPULimit = -1
-			break
-		}
-		milliCPULimit += containerLimit
-	}
+	// TODO: count pod overhead
+	milliCPULimit := getPodLimit(extendedResourceSpec, util.GetBatchMilliCPUFromResourceList)
 
 	cfsQuota := sysutil.MilliCPUToQuota(milliCPULimit)
 	if cfsQuota > 0 && scaleRatio > 1.0 { // no support ratio in (0, 1) yet
@@ -222,20 +218,8 @@ func (p *plugin) SetPodMemoryLimit(proto protocol.HooksProtocol) error {
 		return nil
 	}
 
-	memoryLimit := int64(0)
-	// TODO: count init container and pod overhead
-	for _, c := range extendedResourceSpec.Containers {
-		if c.Limits == nil {
-			memoryLimit = -1
-			break
-		}
-		containerLimit := util.GetBatchMemoryFromResourceList(c.Limits)
-		if containerLimit <= 0 { // pod unlimited once a container is unlimited
-			memoryLimit = -1
-			break
-		}
-		memoryLimit += containerLimit
-	}
+	// TODO: count pod overhead
+	memoryLimit := getPodLimit(extendedResourceSpec, util.GetBatchMemoryFromResourceList)
 
 	podCtx.Response.Resources.MemoryLimit = pointer.Int64(memoryLimit)
 	return nil
@@ -379,6 +363,33 @@ func (p *plugin) SetContainerMemoryLimit(proto protocol.HooksProtocol) error {
 	return nil
 }
 
+// getPodLimit returns the pod-level limit which is the max of the sum of the containers and any init container,
+// or -1 if any of them is unlimited.
+func getPodLimit(spec *apiext.ExtendedResourceSpec, getFn func(corev1.ResourceList) int64) int64 {
+	podLimit := int64(0)
+	for _, c := range spec.Containers {
+		if c.Limits == nil {
+			return -1
+		}
+		containerLimit := getFn(c.Limits)
+		if containerLimit <= 0 { // pod unlimited once a container is unlimited
+			return -1
+		}
+		podLimit += containerLimit
+	}
+	for _, c := range spec.InitContainers {
+		if c.Limits == nil {
+			return -1
+		}
+		containerLimit := getFn(c.Limits)
+		if containerLimit <= 0 {
+			return -1
+		}
+		podLimit = util.MaxInt64(podLimit, containerLimit)
+	}
+	return podLimit
+}
+
 func isPodQoSBEByAttr(labels map[string]string, annotations map[string]string) bool {
 	return apiext.GetQoSClassByAttrs(labels, annotations) == apiext.QoSBE
 }
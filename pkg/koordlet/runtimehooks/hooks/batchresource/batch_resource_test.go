@@ -122,6 +122,23 @@ func Test_plugin_SetPodResources(t *testing.T) {
 	}
 	testSpecBytes3, err := json.Marshal(testSpec3)
 	assert.NoError(t, err)
+	testSpec4 := &apiext.ExtendedResourceSpec{
+		Containers: testSpec.Containers,
+		InitContainers: map[string]apiext.ExtendedResourceContainerSpec{
+			"init-container-0": {
+				Requests: corev1.ResourceList{
+					apiext.BatchCPU:    resource.MustParse("1000"),
+					apiext.BatchMemory: resource.MustParse("1Gi"),
+				},
+				Limits: corev1.ResourceList{
+					apiext.BatchCPU:    resource.MustParse("1000"),
+					apiext.BatchMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
+	}
+	testSpecBytes4, err := json.Marshal(testSpec4)
+	assert.NoError(t, err)
 	type fields struct {
 		rule *Rule
 	}
@@ -135,6 +152,47 @@ func Test_plugin_SetPodResources(t *testing.T) {
 		want    protocol.HooksProtocol
 		wantErr bool
 	}{
+		{
+			name: "a Batch pod with init containers",
+			fields: fields{
+				rule: &Rule{
+					enableCFSQuota:        pointer.Bool(true),
+					cpuNormalizationRatio: pointer.Float64(-1),
+				},
+			},
+			args: args{
+				proto: &protocol.PodContext{
+					Request: protocol.PodRequest{
+						Labels: map[string]string{
+							apiext.LabelPodQoS: string(apiext.QoSBE),
+						},
+						Annotations: map[string]string{
+							apiext.AnnotationExtendedResourceSpec: string(testSpecBytes4),
+						},
+						ExtendedResources: testSpec4,
+					},
+				},
+			},
+			want: &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels: map[string]string{
+						apiext.LabelPodQoS: string(apiext.QoSBE),
+					},
+					Annotations: map[string]string{
+						apiext.AnnotationExtendedResourceSpec: string(testSpecBytes4),
+					},
+					ExtendedResources: testSpec4,
+				},
+				Response: protocol.PodResponse{
+					Resources: protocol.Resources{
+						// cpu takes the init container, memory takes the sum of the containers
+						CPUShares:   pointer.Int64(1024 * 1000 / 1000),
+						CFSQuota:    pointer.Int64(100000 * 1000 / 1000),
+						MemoryLimit: pointer.Int64(2 * 1024 * 1024 * 1024),
+					},
+				},
+			},
+		},
 		{
 			name: "nil proto",
 			args: args{
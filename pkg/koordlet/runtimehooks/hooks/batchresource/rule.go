@@ -17,6 +17,7 @@ limitations under the License.
 package batchresource
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
@@ -228,7 +229,7 @@ func (p *plugin) ruleUpdateCbForNodeMeta(target *statesinformer.CallbackTarget)
 
 	// NOTE: Update cgroups by the level since some resources like cfs quota requires the upper level value is no less
 	//       than the lower.
-	p.executor.LeveledUpdateBatch([][]resourceexecutor.ResourceUpdater{
+	p.executor.LeveledUpdateBatch(context.TODO(), [][]resourceexecutor.ResourceUpdater{
 		podUpdaters,
 		containerUpdaters,
 	})
@@ -50,6 +50,8 @@ const (
 
 	// ExpellerGroupSuffix is the default suffix of the expeller core sched group.
 	ExpellerGroupSuffix = "-expeller"
+	// IsolatedGroupSuffix is the prefix of the suffix of the core sched group isolated by the QoS class, e.g. `-qos-be`.
+	IsolatedGroupSuffix = "-qos-"
 	// NoneGroupID is the special ID denoting none core sched group.
 	NoneGroupID = "__0__"
 )
@@ -18,6 +18,7 @@ package coresched
 
 import (
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
@@ -247,6 +248,9 @@ func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]stri
 		groupID = podUID
 	} else if policy == slov1alpha1.CoreSchedPolicyNone {
 		isEnabled = false
+	} else if p.rule.IsPodIsolated(podQOS, podKubeQOS) {
+		// the exclusive group is isolated already
+		groupID += getIsolatedGroupSuffix(podQOS, podKubeQOS)
 	}
 	if isExpeller {
 		groupID += ExpellerGroupSuffix
@@ -255,6 +259,15 @@ func (p *Plugin) getPodEnabledAndGroup(podAnnotations, podLabels map[string]stri
 	return isEnabled, groupID
 }
 
+// getIsolatedGroupSuffix returns the group suffix of the QoS class, so the pods of different QoS classes do not
+// share the core sched groups even if they have the same group ID.
+func getIsolatedGroupSuffix(podQOS extension.QoSClass, podKubeQOS corev1.PodQOSClass) string {
+	if podQOS != extension.QoSNone {
+		return IsolatedGroupSuffix + strings.ToLower(string(podQOS))
+	}
+	return IsolatedGroupSuffix + strings.ToLower(string(podKubeQOS))
+}
+
 func (p *Plugin) getContainerUID(podUID string, containerID string) string {
 	return podUID + "/" + containerID
 }
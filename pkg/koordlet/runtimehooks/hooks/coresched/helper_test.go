@@ -965,6 +965,68 @@ func Test_isPodEnabled(t *testing.T) {
 			want:  false,
 			want1: "",
 		},
+		{
+			name: "LS pod enabled and isolated",
+			field: field{
+				rule: testGetIsolatedRule(),
+			},
+			args: args{
+				podAnnotations: map[string]string{},
+				podLabels: map[string]string{
+					extension.LabelPodQoS:             string(extension.QoSLS),
+					slov1alpha1.LabelCoreSchedGroupID: "group-xxx",
+				},
+				podUID: "xxx",
+			},
+			want:  true,
+			want1: "group-xxx-qos-ls-expeller",
+		},
+		{
+			name: "BE pod enabled and isolated",
+			field: field{
+				rule: testGetIsolatedRule(),
+			},
+			args: args{
+				podAnnotations: map[string]string{},
+				podLabels: map[string]string{
+					extension.LabelPodQoS:             string(extension.QoSBE),
+					slov1alpha1.LabelCoreSchedGroupID: "group-xxx",
+				},
+				podUID: "xxx",
+			},
+			want:  true,
+			want1: "group-xxx-qos-be",
+		},
+		{
+			name: "besteffort pod enabled and isolated",
+			field: field{
+				rule: testGetIsolatedRule(),
+			},
+			args: args{
+				podAnnotations: map[string]string{},
+				podLabels:      map[string]string{},
+				podKubeQOS:     corev1.PodQOSBestEffort,
+				podUID:         "xxx",
+			},
+			want:  true,
+			want1: "-qos-besteffort",
+		},
+		{
+			name: "exclusive pod enabled and not suffixed by QoS",
+			field: field{
+				rule: testGetIsolatedRule(),
+			},
+			args: args{
+				podAnnotations: map[string]string{},
+				podLabels: map[string]string{
+					extension.LabelPodQoS:            string(extension.QoSBE),
+					slov1alpha1.LabelCoreSchedPolicy: string(slov1alpha1.CoreSchedPolicyExclusive),
+				},
+				podUID: "xxx",
+			},
+			want:  true,
+			want1: "xxx",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
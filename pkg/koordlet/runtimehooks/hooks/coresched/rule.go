@@ -36,6 +36,7 @@ type Param struct {
 	IsPodEnabled bool
 	IsExpeller   bool
 	IsCPUIdle    bool
+	IsIsolated   bool
 }
 
 func newParam(qosCfg *slov1alpha1.CPUQOSCfg, policy slov1alpha1.CPUQOSPolicy) Param {
@@ -44,6 +45,7 @@ func newParam(qosCfg *slov1alpha1.CPUQOSCfg, policy slov1alpha1.CPUQOSPolicy) Pa
 		IsPodEnabled: isPolicyCoreSched && *qosCfg.Enable,
 		IsExpeller:   isPolicyCoreSched && *qosCfg.CoreExpeller,
 		IsCPUIdle:    isPolicyCoreSched && *qosCfg.SchedIdle == 1,
+		IsIsolated:   isPolicyCoreSched && qosCfg.CoreSchedIsolated != nil && *qosCfg.CoreSchedIsolated,
 	}
 }
 
@@ -91,6 +93,22 @@ func (r *Rule) IsPodEnabled(podQoSClass extension.QoSClass, podKubeQOS corev1.Po
 	return false, false
 }
 
+// IsPodIsolated returns if the pod's core sched groups are isolated from the other QoS classes.
+func (r *Rule) IsPodIsolated(podQoSClass extension.QoSClass, podKubeQOS corev1.PodQOSClass) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if !r.enable {
+		return false
+	}
+	if val, exist := r.podQOSParams[podQoSClass]; exist {
+		return val.IsIsolated
+	}
+	if val, exist := r.kubeQOSPodParams[podKubeQOS]; exist {
+		return val.IsIsolated
+	}
+	return false
+}
+
 func (r *Rule) IsKubeQOSCPUIdle(KubeQOS corev1.PodQOSClass) bool {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -1823,3 +1823,16 @@ func testGetContainerCgroupParentDir(t *testing.T, podParentDir string, containe
 	assert.NoError(t, err)
 	return dir
 }
+
+func testGetIsolatedRule() *Rule {
+	r := testGetEnabledRule()
+	for qos, param := range r.podQOSParams {
+		param.IsIsolated = true
+		r.podQOSParams[qos] = param
+	}
+	for kubeQOS, param := range r.kubeQOSPodParams {
+		param.IsIsolated = true
+		r.kubeQOSPodParams[kubeQOS] = param
+	}
+	return r
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cpunormalization
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"sync"
@@ -147,7 +148,7 @@ func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 
 	// NOTE: Update cgroups by the level since some resources like cfs quota requires the upper level value is no less
 	//       than the lower.
-	p.executor.LeveledUpdateBatch([][]resourceexecutor.ResourceUpdater{
+	p.executor.LeveledUpdateBatch(context.TODO(), [][]resourceexecutor.ResourceUpdater{
 		podUpdaters,
 		containerUpdaters,
 	})
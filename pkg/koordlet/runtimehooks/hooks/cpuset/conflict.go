@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	ruleNameForAllPods = name + " (allPods)"
+
+	eventReasonCPUSetConflict = "CPUSetConflict"
+)
+
+// parseForAllPods detects the pods bound by other schedulers whose cpusets conflict with the cpusets allocated by
+// koord-scheduler. The conflicted pods are not aware of the allocations of koord-scheduler, so they are reconciled
+// into the shared pool instead of the cpusets they carry.
+func (p *cpusetPlugin) parseForAllPods(e interface{}) (bool, error) {
+	if _, ok := e.(*struct{}); !ok {
+		return false, fmt.Errorf("invalid rule type %T", e)
+	}
+	if p.statesInformer == nil {
+		return false, nil
+	}
+	conflictPods := p.getConflictPods(p.statesInformer.GetAllPods())
+	return p.updateConflictPods(conflictPods), nil
+}
+
+func (p *cpusetPlugin) getConflictPods(podMetas []*statesinformer.PodMeta) map[types.UID]struct{} {
+	koordCPUs := cpuset.NewCPUSetBuilder()
+	foreignPods := map[*corev1.Pod]cpuset.CPUSet{}
+	for _, podMeta := range podMetas {
+		pod := podMeta.Pod
+		if pod == nil || util.IsPodTerminated(pod) {
+			continue
+		}
+		cpusetVal, err := util.GetCPUSetFromPod(pod.Annotations)
+		if err != nil || cpusetVal == "" {
+			continue
+		}
+		cpus, err := cpuset.Parse(cpusetVal)
+		if err != nil {
+			klog.V(5).Infof("failed to parse cpuset %s of pod %s, err: %v", cpusetVal, klog.KObj(pod), err)
+			continue
+		}
+		if p.koordSchedulerNames.Has(pod.Spec.SchedulerName) {
+			koordCPUs.Add(cpus.ToSliceNoSort()...)
+		} else {
+			foreignPods[pod] = cpus
+		}
+	}
+
+	allocated := koordCPUs.Result()
+	conflictPods := map[types.UID]struct{}{}
+	for pod, cpus := range foreignPods {
+		conflictCPUs := cpus.Intersection(allocated)
+		if conflictCPUs.IsEmpty() {
+			continue
+		}
+		conflictPods[pod.UID] = struct{}{}
+		if p.isConflictPod(string(pod.UID)) || p.eventRecorder == nil {
+			continue
+		}
+		klog.Warningf("pod %s bound by scheduler %s has cpuset conflicts %s with koord-scheduler, reconcile it into the shared pool",
+			klog.KObj(pod), pod.Spec.SchedulerName, conflictCPUs.String())
+		p.eventRecorder.Eventf(pod, corev1.EventTypeWarning, eventReasonCPUSetConflict,
+			"cpuset %s conflicts with the cpus allocated by koord-scheduler, run in the shared pool instead", conflictCPUs.String())
+	}
+	return conflictPods
+}
+
+func (p *cpusetPlugin) updateConflictPods(conflictPods map[types.UID]struct{}) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if (len(conflictPods) == 0 && len(p.conflictPods) == 0) || reflect.DeepEqual(conflictPods, p.conflictPods) {
+		return false
+	}
+	p.conflictPods = conflictPods
+	return true
+}
+
+func (p *cpusetPlugin) isConflictPod(podUID string) bool {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	_, ok := p.conflictPods[types.UID(podUID)]
+	return ok
+}
+
+func (r *cpusetRule) getAllSharePoolCPUSet() string {
+	allSharePoolCPUs := make([]string, 0, len(r.sharePools))
+	for _, nodeSharePool := range r.sharePools {
+		allSharePoolCPUs = append(allSharePoolCPUs, nodeSharePool.CPUSet)
+	}
+	return strings.Join(allSharePoolCPUs, ",")
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuset
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+)
+
+func newConflictTestPod(name, schedulerName, cpus string) *statesinformer.PodMeta {
+	return &statesinformer.PodMeta{
+		Pod: &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+				UID:       types.UID(name),
+				Annotations: map[string]string{
+					ext.AnnotationResourceStatus: `{"cpuset":"` + cpus + `"}`,
+				},
+			},
+			Spec: corev1.PodSpec{
+				SchedulerName: schedulerName,
+			},
+		},
+	}
+}
+
+func Test_cpusetPlugin_parseForAllPods(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	recorder := record.NewFakeRecorder(10)
+	p := &cpusetPlugin{
+		rule: &cpusetRule{
+			sharePools: []ext.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "8-11"},
+				{Socket: 1, Node: 1, CPUSet: "20-23"},
+			},
+		},
+		statesInformer:      si,
+		eventRecorder:       recorder,
+		koordSchedulerNames: sets.NewString("koord-scheduler"),
+	}
+
+	_, err := p.parseForAllPods(nil)
+	assert.Error(t, err)
+
+	pods := []*statesinformer.PodMeta{
+		newConflictTestPod("koord-pod", "koord-scheduler", "0-3"),
+		newConflictTestPod("conflict-pod", "default-scheduler", "2-5"),
+		newConflictTestPod("non-conflict-pod", "default-scheduler", "6-7"),
+	}
+	si.EXPECT().GetAllPods().Return(pods).Times(2)
+	updated, err := p.parseForAllPods(&struct{}{})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.True(t, p.isConflictPod("conflict-pod"))
+	assert.False(t, p.isConflictPod("non-conflict-pod"))
+	assert.False(t, p.isConflictPod("koord-pod"))
+	assert.Equal(t, 1, len(recorder.Events))
+
+	// not updated and flagged again
+	updated, err = p.parseForAllPods(&struct{}{})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	assert.Equal(t, 1, len(recorder.Events))
+
+	// the conflicted pod runs in the shared pool and keeps the cfs quota
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodMeta: protocol.PodMeta{
+				Namespace: "default",
+				Name:      "conflict-pod",
+				UID:       "conflict-pod",
+			},
+			PodAnnotations: pods[1].Pod.Annotations,
+			CgroupParent:   "kubepods/pod-conflict-pod/test-container/",
+		},
+	}
+	assert.NoError(t, p.SetContainerCPUSetAndUnsetCFS(containerCtx))
+	assert.Equal(t, "8-11,20-23", *containerCtx.Response.Resources.CPUSet)
+	assert.Nil(t, containerCtx.Response.Resources.CFSQuota)
+
+	// the conflict is resolved when the koord pod terminated
+	pods[0].Pod.Status.Phase = corev1.PodSucceeded
+	si.EXPECT().GetAllPods().Return(pods).Times(1)
+	updated, err = p.parseForAllPods(&struct{}{})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.False(t, p.isConflictPod("conflict-pod"))
+	containerCtx.Response = protocol.ContainerResponse{}
+	assert.NoError(t, p.SetContainerCPUSet(containerCtx))
+	assert.Equal(t, "2-5", *containerCtx.Response.Resources.CPUSet)
+}
@@ -20,10 +20,14 @@ import (
 	"fmt"
 	"sync"
 
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
@@ -44,6 +48,12 @@ type cpusetPlugin struct {
 	rule        *cpusetRule
 	ruleRWMutex sync.RWMutex
 	executor    resourceexecutor.ResourceUpdateExecutor
+
+	statesInformer      statesinformer.StatesInformer
+	eventRecorder       record.EventRecorder
+	koordSchedulerNames sets.String
+	// conflictPods are the pods bound by other schedulers whose cpusets conflict with koord-scheduler
+	conflictPods map[types.UID]struct{}
 }
 
 var (
@@ -59,6 +69,11 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	rule.Register(name, description,
 		rule.WithParseFunc(statesinformer.RegisterTypeNodeTopology, p.parseRule),
 		rule.WithUpdateCallback(p.ruleUpdateCb))
+	if features.DefaultKoordletFeatureGate.Enabled(features.CPUSetConflictGuard) {
+		rule.Register(ruleNameForAllPods, description,
+			rule.WithParseFunc(statesinformer.RegisterTypeAllPods, p.parseForAllPods),
+			rule.WithUpdateCallback(p.ruleUpdateCb))
+	}
 
 	reconciler.RegisterCgroupReconciler(reconciler.ContainerLevel, sysutil.CPUSet,
 		"set container cpuset and unset container cpu quota if needed for cpuset pod",
@@ -80,6 +95,9 @@ func (p *cpusetPlugin) Register(op hooks.Options) {
 	reconciler.RegisterHostAppReconciler(sysutil.CPUSet, "set host application cpuset",
 		p.SetHostAppCPUSet, &reconciler.ReconcilerOption{})
 	p.executor = op.Executor
+	p.statesInformer = op.StatesInformer
+	p.eventRecorder = op.EventRecorder
+	p.koordSchedulerNames = sets.NewString(op.KoordSchedulerNames...)
 }
 
 var singleton *cpusetPlugin
@@ -98,6 +116,11 @@ func (p *cpusetPlugin) SetContainerCPUSetAndUnsetCFS(proto protocol.HooksProtoco
 		return err
 	}
 
+	// keep the cfs quota of the conflicted pod since it runs in the shared pool
+	if containerCtx, _ := proto.(*protocol.ContainerContext); containerCtx != nil && p.isConflictPod(containerCtx.Request.PodMeta.UID) {
+		return nil
+	}
+
 	// unset container-level cpu.cfs_quota_us if needed
 	return UnsetContainerCPUQuota(proto)
 }
@@ -113,10 +136,32 @@ func (p *cpusetPlugin) SetContainerCPUSet(proto protocol.HooksProtocol) error {
 	// cpuset from pod annotation (LSE, LSR)
 	if cpusetVal, err := util.GetCPUSetFromPod(containerReq.PodAnnotations); err != nil {
 		return err
+	} else if cpusetVal != "" && p.isConflictPod(containerReq.PodMeta.UID) {
+		// the pod bound by other schedulers conflicts with the allocations of koord-scheduler, use all share pool
+		r := p.getRule()
+		if r == nil {
+			klog.V(5).Infof("hook plugin rule is nil, nothing to do for conflicted container %v/%v",
+				containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+			return nil
+		}
+		containerCtx.Response.Resources.CPUSet = pointer.String(r.getAllSharePoolCPUSet())
+		klog.V(5).Infof("get cpuset %v for conflicted container %v/%v from all share pool", *containerCtx.Response.Resources.CPUSet,
+			containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+		return nil
 	} else if cpusetVal != "" {
 		containerCtx.Response.Resources.CPUSet = pointer.String(cpusetVal)
 		klog.V(5).Infof("get cpuset %v for container %v/%v from pod annotation", cpusetVal,
 			containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+		// bind the memory nodes to the NUMA nodes allocated with the cpus, which are updated together
+		memsVal, err := util.GetCPUSetMemsFromPod(containerReq.PodAnnotations)
+		if err != nil {
+			return err
+		}
+		if memsVal != "" {
+			containerCtx.Response.Resources.CPUSetMems = pointer.String(memsVal)
+			klog.V(5).Infof("get cpuset mems %v for container %v/%v from pod annotation", memsVal,
+				containerCtx.Request.PodMeta.String(), containerCtx.Request.ContainerMeta.Name)
+		}
 		return nil
 	}
 
@@ -41,6 +41,14 @@ func getCPUSet(dirWithKube string, helper *system.FileTestUtil) string {
 	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSet)
 }
 
+func initCPUSetMems(dirWithKube string, value string, helper *system.FileTestUtil) {
+	helper.WriteCgroupFileContents(dirWithKube, system.CPUSetMems, value)
+}
+
+func getCPUSetMems(dirWithKube string, helper *system.FileTestUtil) string {
+	return helper.ReadCgroupFileContents(dirWithKube, system.CPUSetMems)
+}
+
 func initCPUQuota(dirWithKube string, value string, helper *system.FileTestUtil) {
 	helper.WriteCgroupFileContents(dirWithKube, system.CPUCFSQuota, value)
 }
@@ -132,6 +140,7 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 		args       args
 		wantErr    bool
 		wantCPUSet *string
+		wantMems   *string
 	}{
 		{
 			name: "set cpu with nil protocol",
@@ -180,6 +189,30 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			wantErr:    false,
 			wantCPUSet: pointer.StringPtr("2-4"),
 		},
+		{
+			name: "set cpu and mems by pod allocated",
+			fields: fields{
+				rule: nil,
+			},
+			args: args{
+				podAlloc: &ext.ResourceStatus{
+					CPUSet: "2-4",
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 0,
+						},
+					},
+				},
+				proto: &protocol.ContainerContext{
+					Request: protocol.ContainerRequest{
+						CgroupParent: "kubepods/test-pod/test-container/",
+					},
+				},
+			},
+			wantErr:    false,
+			wantCPUSet: pointer.String("2-4"),
+			wantMems:   pointer.String("0"),
+		},
 		{
 			name: "set cpu by pod allocated share pool with nil rule",
 			fields: fields{
@@ -345,6 +378,7 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 			if tt.args.proto != nil {
 				containerCtx = tt.args.proto.(*protocol.ContainerContext)
 				initCPUSet(containerCtx.Request.CgroupParent, "", testHelper)
+				initCPUSetMems(containerCtx.Request.CgroupParent, "", testHelper)
 				if tt.args.podAlloc != nil {
 					podAllocJson := util.DumpJSON(tt.args.podAlloc)
 					containerCtx.Request.PodAnnotations = map[string]string{
@@ -374,6 +408,13 @@ func Test_cpusetPlugin_SetContainerCPUSet(t *testing.T) {
 				gotCPUSet := getCPUSet(containerCtx.Request.CgroupParent, testHelper)
 				assert.Equal(t, *tt.wantCPUSet, gotCPUSet, "container cpuset should be equal")
 			}
+			if tt.wantMems == nil {
+				assert.Nil(t, containerCtx.Response.Resources.CPUSetMems, "cpuset mems value should be nil")
+			} else {
+				assert.Equal(t, *tt.wantMems, *containerCtx.Response.Resources.CPUSetMems, "container cpuset mems should be equal")
+				gotMems := getCPUSetMems(containerCtx.Request.CgroupParent, testHelper)
+				assert.Equal(t, *tt.wantMems, gotMems, "container cpuset mems should be equal")
+			}
 		})
 	}
 }
@@ -39,6 +39,7 @@ type cpusetRule struct {
 	kubeletPolicy   extension.KubeletCPUManagerPolicy
 	sharePools      []extension.CPUSharedPool
 	beSharePools    []extension.CPUSharedPool
+	sharedSubPools  []extension.CPUSharedSubPool
 	systemQOSCPUSet string
 	// TODO: support per-node disable
 }
@@ -47,6 +48,7 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	// pod specifies QoS=BE and share pool id in annotations, use part be cpu share pool if BECPUManager enabled
 	// pod specifies share pool id in annotations, use part cpu share pool
 	// pod specifies QoS=SYSTEM in labels, use system qos resource if rule exist
+	// pod is assigned to a cpu shared sub-pool in annotations, use the sub-pool
 	// pod specifies QoS=LS in labels, use all share pool
 	// besteffort pod(including QoS=BE) will be managed by cpu suppress policy, inject empty string
 	// guaranteed/bustable pod without QoS label, if kubelet use none policy, use all share pool, and if kubelet use
@@ -62,6 +64,10 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 
 	podQOSClass := extension.GetQoSClassByAttrs(podLabels, podAnnotations)
+	var sharedSubPool *extension.CPUSharedSubPool
+	if podQOSClass != extension.QoSBE {
+		sharedSubPool = r.getSharedSubPool(podAlloc.CPUSharedSubPool)
+	}
 
 	// check if numa-aware
 	isNUMAAware := false
@@ -94,6 +100,12 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 			klog.V(6).Infof("get cpuset from specified be cpushare pool for container %v/%v",
 				containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
 			return pointer.String(cpuSetStr), nil
+		} else if sharedSubPool != nil {
+			// LS pods which have specified cpu shared sub-pool
+			cpuSetStr := getCPUFromSharePoolByAllocFn(sharedSubPool.Pools, podAlloc)
+			klog.V(6).Infof("get cpuset from specified cpu shared sub-pool %s for container %v/%v",
+				sharedSubPool.Name, containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+			return pointer.String(cpuSetStr), nil
 		} else if podQOSClass != extension.QoSBE {
 			// LS pods which have specified cpu share pool
 			cpuSetStr := getCPUFromSharePoolByAllocFn(r.sharePools, podAlloc)
@@ -111,6 +123,16 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 		return pointer.String(r.systemQOSCPUSet), nil
 	}
 
+	if sharedSubPool != nil {
+		subPoolCPUs := make([]string, 0, len(sharedSubPool.Pools))
+		for _, nodeSubPool := range sharedSubPool.Pools {
+			subPoolCPUs = append(subPoolCPUs, nodeSubPool.CPUSet)
+		}
+		klog.V(6).Infof("get cpuset from cpu shared sub-pool %s for container %v/%v",
+			sharedSubPool.Name, containerReq.PodMeta.String(), containerReq.ContainerMeta.Name)
+		return pointer.String(strings.Join(subPoolCPUs, ",")), nil
+	}
+
 	allSharePoolCPUs := make([]string, 0, len(r.sharePools))
 	for _, nodeSharePool := range r.sharePools {
 		allSharePoolCPUs = append(allSharePoolCPUs, nodeSharePool.CPUSet)
@@ -145,6 +167,20 @@ func (r *cpusetRule) getContainerCPUSet(containerReq *protocol.ContainerRequest)
 	}
 }
 
+// getSharedSubPool returns the cpu shared sub-pool of the name. It returns nil if the sub-pool is not found or
+// has no cpu, so the pod falls back to the share pool.
+func (r *cpusetRule) getSharedSubPool(name string) *extension.CPUSharedSubPool {
+	if len(name) <= 0 {
+		return nil
+	}
+	for i := range r.sharedSubPools {
+		if r.sharedSubPools[i].Name == name && len(r.sharedSubPools[i].Pools) > 0 {
+			return &r.sharedSubPools[i]
+		}
+	}
+	return nil
+}
+
 func (r *cpusetRule) getHostAppCpuset(hostAppReq *protocol.HostAppRequest) (*string, error) {
 	if hostAppReq == nil {
 		return nil, nil
@@ -174,6 +210,10 @@ func (p *cpusetPlugin) parseRule(nodeTopoIf interface{}) (bool, error) {
 	if err != nil {
 		return false, err
 	}
+	cpuSharedSubPools, err := extension.GetNodeCPUSharedSubPools(nodeTopo.Annotations)
+	if err != nil {
+		return false, err
+	}
 	cpuManagerPolicy, err := extension.GetKubeletCPUManagerPolicy(nodeTopo.Annotations)
 	if err != nil {
 		return false, err
@@ -196,6 +236,7 @@ func (p *cpusetPlugin) parseRule(nodeTopoIf interface{}) (bool, error) {
 		kubeletPolicy:   *cpuManagerPolicy,
 		sharePools:      cpuSharePools,
 		beSharePools:    beCPUSharePools,
+		sharedSubPools:  cpuSharedSubPools,
 		systemQOSCPUSet: systemQOSCPUSet,
 	}
 	updated := p.updateRule(newRule)
@@ -44,6 +44,7 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 		kubeletPolicy   string
 		sharePools      []ext.CPUSharedPool
 		beSharePools    []ext.CPUSharedPool
+		sharedSubPools  []ext.CPUSharedSubPool
 		systemQOSCPUSet string
 	}
 	type args struct {
@@ -488,6 +489,111 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 			want:    pointer.String("0-3"),
 			wantErr: false,
 		},
+		{
+			name: "get cpuset from cpu shared sub-pool",
+			fields: fields{
+				sharePools: []ext.CPUSharedPool{
+					{Socket: 0, Node: 0, CPUSet: "0-7"},
+					{Socket: 1, Node: 1, CPUSet: "8-15"},
+				},
+				sharedSubPools: []ext.CPUSharedSubPool{
+					{
+						Name:        "ls-high",
+						MinPriority: 9500,
+						MaxPriority: 9999,
+						Pools: []ext.CPUSharedPool{
+							{Socket: 0, Node: 0, CPUSet: "0-3"},
+							{Socket: 1, Node: 1, CPUSet: "8-11"},
+						},
+					},
+				},
+			},
+			args: args{
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+				podAlloc: &ext.ResourceStatus{
+					CPUSharedSubPool: "ls-high",
+				},
+			},
+			want:    pointer.String("0-3,8-11"),
+			wantErr: false,
+		},
+		{
+			name: "get cpuset from cpu shared sub-pool with numa-aware allocation",
+			fields: fields{
+				sharePools: []ext.CPUSharedPool{
+					{Socket: 0, Node: 0, CPUSet: "0-7"},
+					{Socket: 1, Node: 1, CPUSet: "8-15"},
+				},
+				sharedSubPools: []ext.CPUSharedSubPool{
+					{
+						Name:        "ls-high",
+						MinPriority: 9500,
+						MaxPriority: 9999,
+						Pools: []ext.CPUSharedPool{
+							{Socket: 0, Node: 0, CPUSet: "0-3"},
+							{Socket: 1, Node: 1, CPUSet: "8-11"},
+						},
+					},
+				},
+			},
+			args: args{
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+				podAlloc: &ext.ResourceStatus{
+					NUMANodeResources: []ext.NUMANodeResource{
+						{
+							Node: 1,
+							Resources: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceCPU: *resource.NewQuantity(2, resource.DecimalSI),
+							},
+						},
+					},
+					CPUSharedSubPool: "ls-high",
+				},
+			},
+			want:    pointer.String("8-11"),
+			wantErr: false,
+		},
+		{
+			name: "get cpuset from all share pool when the sub-pool is unknown",
+			fields: fields{
+				sharePools: []ext.CPUSharedPool{
+					{Socket: 0, Node: 0, CPUSet: "0-7"},
+					{Socket: 1, Node: 1, CPUSet: "8-15"},
+				},
+			},
+			args: args{
+				containerReq: &protocol.ContainerRequest{
+					PodMeta:       protocol.PodMeta{},
+					ContainerMeta: protocol.ContainerMeta{},
+					PodLabels: map[string]string{
+						ext.LabelPodQoS: string(ext.QoSLS),
+					},
+					PodAnnotations: map[string]string{},
+					CgroupParent:   "burstable/test-pod/test-container",
+				},
+				podAlloc: &ext.ResourceStatus{
+					CPUSharedSubPool: "ls-high",
+				},
+			},
+			want:    pointer.String("0-7,8-15"),
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -497,6 +603,7 @@ func Test_cpusetRule_getContainerCPUSet(t *testing.T) {
 				},
 				sharePools:      tt.fields.sharePools,
 				beSharePools:    tt.fields.beSharePools,
+				sharedSubPools:  tt.fields.sharedSubPools,
 				systemQOSCPUSet: tt.fields.systemQOSCPUSet,
 			}
 			if tt.args.podAlloc != nil {
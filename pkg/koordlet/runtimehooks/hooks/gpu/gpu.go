@@ -24,15 +24,19 @@ import (
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/mps"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
 )
 
 const GpuAllocEnv = "NVIDIA_VISIBLE_DEVICES"
 
-type gpuPlugin struct{}
+type gpuPlugin struct {
+	mpsController mps.DaemonController
+}
 
 func (p *gpuPlugin) Register(op hooks.Options) {
 	klog.V(5).Infof("register hook %v", "gpu env inject")
@@ -43,7 +47,7 @@ var singleton *gpuPlugin
 
 func Object() *gpuPlugin {
 	if singleton == nil {
-		singleton = &gpuPlugin{}
+		singleton = &gpuPlugin{mpsController: mps.DefaultController()}
 	}
 	return singleton
 }
@@ -105,6 +109,49 @@ func (p *gpuPlugin) InjectContainerGPUEnv(proto protocol.HooksProtocol) error {
 			)
 		}
 	}
+	if containerReq.PodLabels[ext.LabelGPUIsolationProvider] == string(ext.GPUIsolationProviderMPS) {
+		return p.injectContainerMPSEnv(containerCtx, devices)
+	}
+
+	return nil
+}
 
+// injectContainerMPSEnv connects the container to the MPS control daemon of its GPU, and limits the active thread
+// percentage and the device memory of the container according to the gpu allocation.
+func (p *gpuPlugin) injectContainerMPSEnv(containerCtx *protocol.ContainerContext, devices []*ext.DeviceAllocation) error {
+	if !features.DefaultKoordletFeatureGate.Enabled(features.GPUMPS) {
+		klog.V(5).Infof("feature %s is disabled, skip injecting mps env for pod %s", features.GPUMPS, containerCtx.Request.PodMeta.Name)
+		return nil
+	}
+	// the MPS client can only connect to one control daemon, and the full GPUs do not need to share
+	if len(devices) != 1 {
+		klog.V(5).Infof("skip injecting mps env for pod %s, since it is allocated %d gpus", containerCtx.Request.PodMeta.Name, len(devices))
+		return nil
+	}
+	minor := devices[0].Minor
+	if err := p.mpsController.Start(minor); err != nil {
+		return err
+	}
+
+	envs := containerCtx.Response.AddContainerEnvs
+	envs[mps.EnvPipeDirectory] = mps.ContainerPipeDir
+	gpuResources := devices[0].Resources
+	if gpuCore, ok := gpuResources[ext.ResourceGPUCore]; ok && gpuCore.Value() > 0 && gpuCore.Value() < 100 {
+		envs[mps.EnvActiveThreadPercentage] = fmt.Sprintf("%d", gpuCore.Value())
+	}
+	if gpuMemoryRatio, ok := gpuResources[ext.ResourceGPUMemoryRatio]; ok && gpuMemoryRatio.Value() < 100 {
+		if gpuMemory, ok := gpuResources[ext.ResourceGPUMemory]; ok {
+			// the only visible device in the container is indexed 0
+			envs[mps.EnvPinnedDeviceMemLimit] = fmt.Sprintf("0=%dM", gpuMemory.Value()/1024/1024)
+		}
+	}
+	containerCtx.Response.AddContainerMounts = append(containerCtx.Response.AddContainerMounts,
+		&protocol.Mount{
+			Destination: mps.ContainerPipeDir,
+			Type:        "bind",
+			Source:      mps.GetPipeDir(minor),
+			Options:     []string{"rbind"},
+		},
+	)
 	return nil
 }
@@ -22,7 +22,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/mps"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func Test_InjectContainerGPUEnv(t *testing.T) {
@@ -119,3 +122,81 @@ func Test_InjectContainerGPUEnv(t *testing.T) {
 		})
 	}
 }
+
+type fakeMPSController struct {
+	started map[int32]bool
+}
+
+func (f *fakeMPSController) Start(minor int32) error {
+	f.started[minor] = true
+	return nil
+}
+
+func (f *fakeMPSController) Stop(minor int32) error {
+	delete(f.started, minor)
+	return nil
+}
+
+func (f *fakeMPSController) IsRunning(minor int32) bool {
+	return f.started[minor]
+}
+
+func (f *fakeMPSController) ListStarted() ([]int32, error) {
+	var minors []int32
+	for minor := range f.started {
+		minors = append(minors, minor)
+	}
+	return minors, nil
+}
+
+func Test_InjectContainerMPSEnv(t *testing.T) {
+	assert.NoError(t, features.DefaultMutableKoordletFeatureGate.Set("GPUMPS=true"))
+	defer func() {
+		assert.NoError(t, features.DefaultMutableKoordletFeatureGate.Set("GPUMPS=false"))
+	}()
+
+	controller := &fakeMPSController{started: map[int32]bool{}}
+	plugin := gpuPlugin{mpsController: controller}
+
+	containerCtx := &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodLabels: map[string]string{
+				ext.LabelGPUIsolationProvider: string(ext.GPUIsolationProviderMPS),
+			},
+			PodAnnotations: map[string]string{
+				ext.AnnotationDeviceAllocated: `{"gpu":[{"minor":1,"resources":{"koordinator.sh/gpu-core":"30","koordinator.sh/gpu-memory":"8Gi","koordinator.sh/gpu-memory-ratio":"50"}}]}`,
+			},
+		},
+	}
+	assert.NoError(t, plugin.InjectContainerGPUEnv(containerCtx))
+	assert.True(t, controller.IsRunning(1))
+	assert.Equal(t, map[string]string{
+		GpuAllocEnv:                   "1",
+		mps.EnvPipeDirectory:          mps.ContainerPipeDir,
+		mps.EnvActiveThreadPercentage: "30",
+		mps.EnvPinnedDeviceMemLimit:   "0=8192M",
+	}, containerCtx.Response.AddContainerEnvs)
+	assert.Equal(t, []*protocol.Mount{
+		{
+			Destination: mps.ContainerPipeDir,
+			Type:        "bind",
+			Source:      system.Conf.MPSRootDir + "/1/pipe",
+			Options:     []string{"rbind"},
+		},
+	}, containerCtx.Response.AddContainerMounts)
+
+	// the full gpus do not connect to the mps daemons
+	containerCtx = &protocol.ContainerContext{
+		Request: protocol.ContainerRequest{
+			PodLabels: map[string]string{
+				ext.LabelGPUIsolationProvider: string(ext.GPUIsolationProviderMPS),
+			},
+			PodAnnotations: map[string]string{
+				ext.AnnotationDeviceAllocated: `{"gpu":[{"minor":2},{"minor":3}]}`,
+			},
+		},
+	}
+	assert.NoError(t, plugin.InjectContainerGPUEnv(containerCtx))
+	assert.False(t, controller.IsRunning(2))
+	assert.Equal(t, map[string]string{GpuAllocEnv: "2,3"}, containerCtx.Response.AddContainerEnvs)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package groupidentity
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -190,7 +191,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 			klog.Infof("bvt updater create failed, dir %v, error %v", kubeQOSCgroupPath, err)
 			continue
 		}
-		if _, err = b.executor.Update(true, bvtUpdater); err != nil {
+		if _, err = b.executor.Update(context.TODO(), true, bvtUpdater); err != nil {
 			klog.Infof("update kube qos %v cpu bvt failed, dir %v, error %v", kubeQOS, kubeQOSCgroupPath, err)
 		}
 		qosCgroupMap[kubeQOSCgroupPath] = struct{}{}
@@ -233,7 +234,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 			klog.Infof("bvt updater create failed, dir %v, error %v", podCgroupPath, err)
 			continue
 		}
-		if _, err = b.executor.Update(true, bvtUpdater); err != nil {
+		if _, err = b.executor.Update(context.TODO(), true, bvtUpdater); err != nil {
 			klog.Infof("update pod %s cpu bvt failed, dir %v, error %v",
 				util.GetPodKey(podMeta.Pod), podCgroupPath, err)
 		}
@@ -253,7 +254,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 				klog.Infof("bvt updater create failed, dir %v, error %v", cgroupDir, err)
 				continue
 			}
-			if _, err = b.executor.Update(true, bvtUpdater); err != nil {
+			if _, err = b.executor.Update(context.TODO(), true, bvtUpdater); err != nil {
 				klog.Infof("update container cpu bvt failed, dir %v, error %v", cgroupDir, err)
 			}
 		}
@@ -275,7 +276,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 			klog.Infof("bvt updater create failed, dir %v, error %v", podCgroupDir, err)
 			continue
 		}
-		if _, err = b.executor.Update(true, bvtUpdater); err != nil {
+		if _, err = b.executor.Update(context.TODO(), true, bvtUpdater); err != nil {
 			klog.Infof("update remaining pod cpu bvt failed, dir %v, error %v", podCgroupDir, err)
 		}
 
@@ -291,7 +292,7 @@ func (b *bvtPlugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
 				klog.Infof("bvt updater create failed, dir %v, error %v", cgroupDir, err)
 				continue
 			}
-			if _, err = b.executor.Update(true, bvtUpdater); err != nil {
+			if _, err = b.executor.Update(context.TODO(), true, bvtUpdater); err != nil {
 				klog.Infof("update remaining container cpu bvt failed, dir %v, error %v", cgroupDir, err)
 			}
 		}
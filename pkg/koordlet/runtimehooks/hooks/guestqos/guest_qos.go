@@ -0,0 +1,93 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guestqos
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "guest qos inject"
+	description = "pass the QoS class hint into the guest of the sandboxed runtimes via the container annotations"
+)
+
+type guestQoSPlugin struct{}
+
+func (p *guestQoSPlugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreCreateContainer, name, description, p.InjectGuestQoS)
+}
+
+var singleton *guestQoSPlugin
+
+func Object() *guestQoSPlugin {
+	if singleton == nil {
+		singleton = &guestQoSPlugin{}
+	}
+	return singleton
+}
+
+// InjectGuestQoS adds the QoS class hint into the annotations of the containers running in the sandboxed runtimes
+// like Kata, where the host cgroups do not reach the in-guest processes. The guest agent consumes the annotations so
+// the in-guest scheduler can mirror the host-side prioritization.
+// NOTE: The host cpuset is not passed since the guest vCPUs are not identical to the host cpus.
+func (p *guestQoSPlugin) InjectGuestQoS(proto protocol.HooksProtocol) error {
+	containerCtx, _ := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin guest qos")
+	}
+	containerReq := containerCtx.Request
+	if !isSandboxedRuntimeHandler(containerReq.RuntimeHandler) {
+		klog.V(6).Infof("runtime handler %q of container %s/%s/%s is not sandboxed, skip", containerReq.RuntimeHandler,
+			containerReq.PodMeta.Namespace, containerReq.PodMeta.Name, containerReq.ContainerMeta.Name)
+		return nil
+	}
+
+	qosClass := ext.GetQoSClassByAttrs(containerReq.PodLabels, containerReq.PodAnnotations)
+	if qosClass == ext.QoSNone {
+		return nil
+	}
+
+	if containerCtx.Response.AddContainerAnnotations == nil {
+		containerCtx.Response.AddContainerAnnotations = map[string]string{}
+	}
+	containerCtx.Response.AddContainerAnnotations[ext.AnnotationGuestQoSClass] = string(qosClass)
+	klog.V(5).Infof("inject guest qos class %s for container %s/%s/%s", qosClass,
+		containerReq.PodMeta.Namespace, containerReq.PodMeta.Name, containerReq.ContainerMeta.Name)
+	return nil
+}
+
+func isSandboxedRuntimeHandler(runtimeHandler string) bool {
+	if runtimeHandler == "" {
+		return false
+	}
+	for _, handler := range strings.Split(system.Conf.SandboxedRuntimeHandlers, ",") {
+		if strings.TrimSpace(handler) == runtimeHandler {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package guestqos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_InjectGuestQoS(t *testing.T) {
+	tests := []struct {
+		name                string
+		runtimeHandlers     string
+		proto               protocol.HooksProtocol
+		expectedError       bool
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:          "test empty proto",
+			proto:         nil,
+			expectedError: true,
+		},
+		{
+			name: "not sandboxed runtime",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+				},
+			},
+		},
+		{
+			name: "inject qos class without the host cpuset",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodLabels: map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+					PodAnnotations: map[string]string{
+						ext.AnnotationResourceStatus: `{"cpuset":"0-3"}`,
+					},
+					RuntimeHandler: "kata",
+				},
+			},
+			expectedAnnotations: map[string]string{
+				ext.AnnotationGuestQoSClass: string(ext.QoSLSR),
+			},
+		},
+		{
+			name:            "inject qos class for the configured runtime handler",
+			runtimeHandlers: "my-vm, kata",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodLabels:      map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+					RuntimeHandler: "my-vm",
+				},
+			},
+			expectedAnnotations: map[string]string{
+				ext.AnnotationGuestQoSClass: string(ext.QoSBE),
+			},
+		},
+		{
+			name: "no qos hint",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					RuntimeHandler: "kata-qemu",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldHandlers := system.Conf.SandboxedRuntimeHandlers
+			defer func() {
+				system.Conf.SandboxedRuntimeHandlers = oldHandlers
+			}()
+			if tt.runtimeHandlers != "" {
+				system.Conf.SandboxedRuntimeHandlers = tt.runtimeHandlers
+			}
+
+			p := Object()
+			err := p.InjectGuestQoS(tt.proto)
+			assert.Equal(t, tt.expectedError, err != nil, err)
+			if containerCtx, ok := tt.proto.(*protocol.ContainerContext); ok {
+				assert.Equal(t, tt.expectedAnnotations, containerCtx.Response.AddContainerAnnotations)
+			}
+		})
+	}
+}
@@ -42,6 +42,8 @@ type Options struct {
 	Executor       resourceexecutor.ResourceUpdateExecutor
 	StatesInformer statesinformer.StatesInformer
 	EventRecorder  record.EventRecorder
+	// KoordSchedulerNames are the scheduler names of koord-scheduler.
+	KoordSchedulerNames []string
 }
 
 type HookFn func(protocol.HooksProtocol) error
@@ -83,13 +85,17 @@ func getHooksByStage(stage rmconfig.RuntimeHookType) []*Hook {
 	}
 }
 
-func RunHooks(failPolicy rmconfig.FailurePolicyType, stage rmconfig.RuntimeHookType, protocol protocol.HooksProtocol) error {
+func RunHooks(failPolicy rmconfig.FailurePolicyType, stage rmconfig.RuntimeHookType, hooksProtocol protocol.HooksProtocol) error {
+	if protocol.IsCgroupExcluded(hooksProtocol) {
+		klog.V(5).Infof("skip running hooks at %s since the cgroup is excluded", stage)
+		return nil
+	}
 	hooks := getHooksByStage(stage)
 	klog.V(5).Infof("start run %v hooks at %s", len(hooks), stage)
 	for _, hook := range hooks {
 		start := time.Now()
 		klog.V(5).Infof("call hook %v with description %v", hook.name, hook.description)
-		err := hook.fn(protocol)
+		err := hook.fn(hooksProtocol)
 		metrics.RecordRuntimeHookInvokedDurationMilliSeconds(hook.name, string(stage), err, metrics.SinceInSeconds(start))
 		if err != nil {
 			klog.Errorf("failed to run hook %s in stage %s, reason: %v", hook.name, stage, err)
@@ -0,0 +1,409 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hugepages
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	name        = "HugePages"
+	description = "reserve the per-NUMA hugepages and set the hugetlb limit for the pod"
+
+	hugePageSize2M int64 = 2 * 1024 * 1024
+	hugePageSize1G int64 = 1024 * 1024 * 1024
+)
+
+type Plugin struct {
+	// allocateLock serializes the read-and-grow of the nr_hugepages among the starting and stopping pods
+	allocateLock sync.Mutex
+	// grownPages are the hugepages grown for the pods, which are released when the pod sandboxes stop
+	grownPages map[string][]numaHugePage
+
+	supportedLock sync.Mutex
+	// sysSupported records if the hugetlb cgroup of each page size is enabled
+	sysSupported map[int64]bool
+
+	executor       resourceexecutor.ResourceUpdateExecutor
+	statesInformer statesinformer.StatesInformer
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = &Plugin{}
+	}
+	return singleton
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description, p.SetPodHugePages)
+	hooks.Register(rmconfig.PostStopPodSandbox, name, description+" (release)", p.ReleasePodHugePages)
+	hooks.Register(rmconfig.PreRemoveRunPodSandbox, name, description+" (release)", p.ReleasePodHugePages)
+	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.HugetlbLimit2M, "reconcile pod level hugetlb limit",
+		p.SetPodHugetlbLimit, reconciler.NoneFilter())
+	p.executor = op.Executor
+	p.statesInformer = op.StatesInformer
+}
+
+// isPageSizeSupported checks if the hugetlb cgroup of the page size is enabled. The page sizes are probed separately
+// since the 1G hugepages can be unsupported by the kernel while the 2M ones are.
+func (p *Plugin) isPageSizeSupported(pageSize int64) bool {
+	p.supportedLock.Lock()
+	defer p.supportedLock.Unlock()
+	if p.sysSupported == nil {
+		p.sysSupported = map[int64]bool{}
+	}
+	if isSupported, ok := p.sysSupported[pageSize]; ok {
+		return isSupported
+	}
+	resourceType := sysutil.ResourceType(sysutil.HugetlbLimit2MName)
+	if pageSize == hugePageSize1G {
+		resourceType = sysutil.ResourceType(sysutil.HugetlbLimit1GName)
+	}
+	isSupported, msg := false, "resource not found"
+	r, err := sysutil.GetCgroupResource(resourceType)
+	if err == nil {
+		isSupported, msg = r.IsSupported(koordletutil.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+	}
+	klog.Infof("update system supported info of %s to %v for plugin %v, supported msg %s",
+		resourceType, isSupported, name, msg)
+	p.sysSupported[pageSize] = isSupported
+	return isSupported
+}
+
+// SetPodHugePages makes sure the hugepages on the NUMA nodes allocated by the scheduler are available for the pod
+// before the sandbox starts, and then sets the hugetlb limit of the pod. If the available hugepages are insufficient,
+// it compacts the memory of the NUMA node and grows the nr_hugepages up to the capacity advertised in the
+// NodeResourceTopology, so the pod does not fail at the page faults. The hook fails if the pool cannot hold the pod
+// within the capacity, since the memory beyond it is scheduled as the normal memory.
+func (p *Plugin) SetPodHugePages(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	numaHugePages, err := getPodNUMAHugePages(podCtx.Request.Annotations)
+	if err != nil {
+		return err
+	}
+	if len(numaHugePages) <= 0 {
+		return nil
+	}
+
+	if err = p.allocateNUMAHugePages(podCtx.Request.PodMeta.UID, numaHugePages); err != nil {
+		return fmt.Errorf("failed to allocate hugepages for pod %s/%s, err: %w",
+			podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name, err)
+	}
+	setHugetlbLimit(podCtx, numaHugePages, p.isPageSizeSupported)
+	return nil
+}
+
+// SetPodHugetlbLimit only sets the hugetlb limit of the pod, since the hugepages of the running pods have been
+// allocated before.
+func (p *Plugin) SetPodHugetlbLimit(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	numaHugePages, err := getPodNUMAHugePages(podCtx.Request.Annotations)
+	if err != nil {
+		return err
+	}
+	if len(numaHugePages) <= 0 {
+		return nil
+	}
+	setHugetlbLimit(podCtx, numaHugePages, p.isPageSizeSupported)
+	return nil
+}
+
+// ReleasePodHugePages shrinks the nr_hugepages by the pages grown for the pod when its sandbox stops.
+// NOTE: The grown pages are recorded in memory, so the ones grown before the koordlet restarts are not released.
+func (p *Plugin) ReleasePodHugePages(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	podUID := podCtx.Request.PodMeta.UID
+	p.allocateLock.Lock()
+	defer p.allocateLock.Unlock()
+	grown, ok := p.grownPages[podUID]
+	if !ok {
+		return nil
+	}
+	delete(p.grownPages, podUID)
+	releaseNUMAHugePages(grown)
+	klog.V(4).Infof("release the hugepages grown for pod %s/%s", podCtx.Request.PodMeta.Namespace, podCtx.Request.PodMeta.Name)
+	return nil
+}
+
+func (p *Plugin) allocateNUMAHugePages(podUID string, numaHugePages []numaHugePage) error {
+	p.allocateLock.Lock()
+	defer p.allocateLock.Unlock()
+	var topo *topologyInfo
+	allocated := map[numaHugePageKey]int64{}
+	if p.statesInformer != nil {
+		topo = newTopologyInfo(p.statesInformer)
+		allocated = getRunningPodsNUMAHugePages(p.statesInformer.GetAllPods(), podUID)
+	}
+
+	var grown []numaHugePage
+	for _, h := range numaHugePages {
+		capacity := topo.getCapacityPages(h)
+		grownPages, err := ensureNUMAHugePages(h, capacity, allocated[h.key()])
+		if err != nil {
+			// release the pages grown on the other NUMA nodes since the pod cannot start
+			releaseNUMAHugePages(grown)
+			return err
+		}
+		if grownPages > 0 {
+			grown = append(grown, numaHugePage{node: h.node, pageSize: h.pageSize, pages: grownPages})
+		}
+	}
+	if len(grown) > 0 {
+		if p.grownPages == nil {
+			p.grownPages = map[string][]numaHugePage{}
+		}
+		p.grownPages[podUID] = append(p.grownPages[podUID], grown...)
+	}
+	return nil
+}
+
+// numaHugePage is the number of the hugepages in a page size allocated on a NUMA node.
+type numaHugePage struct {
+	node     int32
+	pageSize int64 // in bytes
+	pages    int64
+}
+
+func (h numaHugePage) nodeDir() string {
+	return fmt.Sprintf("node%d", h.node)
+}
+
+func (h numaHugePage) pageDir() string {
+	return fmt.Sprintf("hugepages-%dkB", h.pageSize/1024)
+}
+
+// getPodNUMAHugePages parses the hugepages allocated on each NUMA node from the resource status of the pod.
+func getPodNUMAHugePages(annotations map[string]string) ([]numaHugePage, error) {
+	resourceStatus, err := ext.GetResourceStatus(annotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource status, err: %w", err)
+	}
+	var numaHugePages []numaHugePage
+	for _, numaResource := range resourceStatus.NUMANodeResources {
+		for _, pageSize := range []int64{hugePageSize2M, hugePageSize1G} {
+			q, ok := numaResource.Resources[hugePageResourceName(pageSize)]
+			if !ok || q.Value() <= 0 {
+				continue
+			}
+			numaHugePages = append(numaHugePages, numaHugePage{
+				node:     numaResource.Node,
+				pageSize: pageSize,
+				// round up the pages in case of the unaligned requests
+				pages: (q.Value() + pageSize - 1) / pageSize,
+			})
+		}
+	}
+	return numaHugePages, nil
+}
+
+func hugePageResourceName(pageSize int64) corev1.ResourceName {
+	return corev1.ResourceName(corev1.ResourceHugePagesPrefix + resource.NewQuantity(pageSize, resource.BinarySI).String())
+}
+
+type numaHugePageKey struct {
+	node     int32
+	pageSize int64
+}
+
+func (h numaHugePage) key() numaHugePageKey {
+	return numaHugePageKey{node: h.node, pageSize: h.pageSize}
+}
+
+// topologyInfo is the hugepages capacity of the NUMA nodes advertised in the NodeResourceTopology.
+type topologyInfo struct {
+	zoneResources map[string]corev1.ResourceList
+}
+
+func newTopologyInfo(si statesinformer.StatesInformer) *topologyInfo {
+	topo := si.GetNodeTopo()
+	if topo == nil {
+		return nil
+	}
+	return &topologyInfo{zoneResources: util.ZoneListToZoneResourceList(topo.Zones)}
+}
+
+// getCapacityPages returns the hugepages capacity of the NUMA node, which is 0 if it is not advertised.
+func (t *topologyInfo) getCapacityPages(h numaHugePage) int64 {
+	if t == nil {
+		return 0
+	}
+	q, ok := t.zoneResources[util.GenNodeZoneName(int(h.node))][hugePageResourceName(h.pageSize)]
+	if !ok {
+		return 0
+	}
+	return q.Value() / h.pageSize
+}
+
+// getRunningPodsNUMAHugePages sums the hugepages allocated to the running pods except the given one on each NUMA node.
+func getRunningPodsNUMAHugePages(podMetas []*statesinformer.PodMeta, excludedUID string) map[numaHugePageKey]int64 {
+	allocated := map[numaHugePageKey]int64{}
+	for _, podMeta := range podMetas {
+		if podMeta == nil || podMeta.Pod == nil || string(podMeta.Pod.UID) == excludedUID {
+			continue
+		}
+		if phase := podMeta.Pod.Status.Phase; phase == corev1.PodSucceeded || phase == corev1.PodFailed {
+			continue
+		}
+		numaHugePages, err := getPodNUMAHugePages(podMeta.Pod.Annotations)
+		if err != nil {
+			klog.V(5).Infof("failed to get hugepages of pod %s, err: %v", podMeta.Key(), err)
+			continue
+		}
+		for _, h := range numaHugePages {
+			allocated[h.key()] += h.pages
+		}
+	}
+	return allocated
+}
+
+// ensureNUMAHugePages grows the nr_hugepages of the NUMA node when the available hugepages are not enough, and
+// returns the number of the grown pages. The free hugepages include the ones reserved but not faulted by the running
+// pods, so the pages allocated to the running pods are not counted as available even if they are free.
+// The pool is not grown beyond the capacity, and an error is returned if it cannot hold the pages.
+func ensureNUMAHugePages(h numaHugePage, capacity, allocated int64) (int64, error) {
+	nodeDir, pageDir := h.nodeDir(), h.pageDir()
+	free, err := readHugePagesFile(sysutil.GetNUMAHugepagesFreePath(nodeDir, pageDir))
+	if err != nil {
+		return 0, err
+	}
+	nrPath := sysutil.GetNUMAHugepagesNrPath(nodeDir, pageDir)
+	nr, err := readHugePagesFile(nrPath)
+	if err != nil {
+		return 0, err
+	}
+	available := free
+	if unallocated := nr - allocated; unallocated < available {
+		available = unallocated
+	}
+	if available < 0 {
+		available = 0
+	}
+	if available >= h.pages {
+		klog.V(6).Infof("available hugepages %s on %s are enough, free %d, allocated %d, need %d",
+			pageDir, nodeDir, free, allocated, h.pages)
+		return 0, nil
+	}
+
+	expected := nr + h.pages - available
+	if expected > capacity {
+		return 0, fmt.Errorf("insufficient hugepages %s on %s, need %d, available %d, the pool %d cannot grow to %d beyond the capacity %d",
+			pageDir, nodeDir, h.pages, available, nr, expected, capacity)
+	}
+
+	// compact the memory before growing the pool, so the contiguous pages are more likely to be available
+	compactPath := sysutil.GetNUMACompactPath(nodeDir)
+	if err = sysutil.CommonFileWrite(compactPath, "1"); err != nil {
+		klog.V(4).Infof("failed to compact memory of %s, err: %v", nodeDir, err)
+	}
+
+	if err = sysutil.CommonFileWrite(nrPath, strconv.FormatInt(expected, 10)); err != nil {
+		return 0, fmt.Errorf("failed to set hugepages %s on %s to %d, err: %w", pageDir, nodeDir, expected, err)
+	}
+	// the kernel allocates as many pages as possible, so check the result
+	actual, err := readHugePagesFile(nrPath)
+	if err != nil {
+		return 0, err
+	}
+	if actual < expected {
+		// restore the pool since the pod cannot start
+		if err = sysutil.CommonFileWrite(nrPath, strconv.FormatInt(nr, 10)); err != nil {
+			klog.V(4).Infof("failed to restore hugepages %s on %s to %d, err: %v", pageDir, nodeDir, nr, err)
+		}
+		return 0, fmt.Errorf("insufficient hugepages %s on %s, expected %d, actual %d", pageDir, nodeDir, expected, actual)
+	}
+	klog.V(4).Infof("grow hugepages %s on %s from %d to %d", pageDir, nodeDir, nr, actual)
+	return actual - nr, nil
+}
+
+// releaseNUMAHugePages shrinks the nr_hugepages of the NUMA nodes by the grown pages.
+func releaseNUMAHugePages(grown []numaHugePage) {
+	for _, h := range grown {
+		nodeDir, pageDir := h.nodeDir(), h.pageDir()
+		nrPath := sysutil.GetNUMAHugepagesNrPath(nodeDir, pageDir)
+		nr, err := readHugePagesFile(nrPath)
+		if err != nil {
+			klog.V(4).Infof("failed to release hugepages %s on %s, err: %v", pageDir, nodeDir, err)
+			continue
+		}
+		expected := nr - h.pages
+		if expected < 0 {
+			expected = 0
+		}
+		if err = sysutil.CommonFileWrite(nrPath, strconv.FormatInt(expected, 10)); err != nil {
+			klog.V(4).Infof("failed to release hugepages %s on %s to %d, err: %v", pageDir, nodeDir, expected, err)
+			continue
+		}
+		klog.V(4).Infof("shrink hugepages %s on %s from %d to %d", pageDir, nodeDir, nr, expected)
+	}
+}
+
+func readHugePagesFile(path string) (int64, error) {
+	content, err := sysutil.CommonFileRead(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s, err: %w", path, err)
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(content), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s, content %q, err: %w", path, content, err)
+	}
+	return v, nil
+}
+
+func setHugetlbLimit(podCtx *protocol.PodContext, numaHugePages []numaHugePage, isSupported func(pageSize int64) bool) {
+	limits := map[int64]int64{}
+	for _, h := range numaHugePages {
+		limits[h.pageSize] += h.pages * h.pageSize
+	}
+	if limit, ok := limits[hugePageSize2M]; ok && isSupported(hugePageSize2M) {
+		podCtx.Response.Resources.HugetlbLimit2M = pointer.Int64(limit)
+	}
+	if limit, ok := limits[hugePageSize1G]; ok && isSupported(hugePageSize1G) {
+		podCtx.Response.Resources.HugetlbLimit1G = pointer.Int64(limit)
+	}
+}
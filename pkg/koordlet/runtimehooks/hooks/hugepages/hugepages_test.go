@@ -0,0 +1,342 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hugepages
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	mock_statesinformer "github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer/mockstatesinformer"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+func newTestAnnotations(t *testing.T, numaResources ...ext.NUMANodeResource) map[string]string {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{}}
+	err := ext.SetResourceStatus(pod, &ext.ResourceStatus{NUMANodeResources: numaResources})
+	assert.NoError(t, err)
+	return pod.Annotations
+}
+
+func newTestNodeTopo(zoneResources map[string]corev1.ResourceList) *topov1alpha1.NodeResourceTopology {
+	if zoneResources == nil {
+		return nil
+	}
+	return &topov1alpha1.NodeResourceTopology{Zones: util.ZoneResourceListToZoneList(zoneResources)}
+}
+
+func newTestSupported(isSupported bool) map[int64]bool {
+	return map[int64]bool{hugePageSize2M: isSupported, hugePageSize1G: isSupported}
+}
+
+func writeHugePages(helper *sysutil.FileTestUtil, node, page string, nr, free string) {
+	helper.WriteFileContents(sysutil.GetNUMAHugepagesNrPath(node, page), nr)
+	helper.WriteFileContents(sysutil.GetNUMAHugepagesFreePath(node, page), free)
+}
+
+func TestPlugin_SetPodHugePages(t *testing.T) {
+	tests := []struct {
+		name            string
+		systemSupported map[int64]bool
+		zoneResources   map[string]corev1.ResourceList
+		runningPods     []*statesinformer.PodMeta
+		numaResources   []ext.NUMANodeResource
+		prepareFn       func(helper *sysutil.FileTestUtil)
+		wantErr         bool
+		wantLimit2M     *int64
+		wantLimit1G     *int64
+		wantNr          map[string]string
+		wantCompact     map[string]bool
+	}{
+		{
+			name:            "skip pod without hugepages",
+			systemSupported: newTestSupported(true),
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+			},
+		},
+		{
+			name:            "free hugepages are enough",
+			systemSupported: newTestSupported(true),
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "10", "4")
+			},
+			wantLimit2M: pointer.Int64(8 * 1024 * 1024),
+			wantNr:      map[string]string{"node0/hugepages-2048kB": "10"},
+			wantCompact: map[string]bool{"node0": false},
+		},
+		{
+			name:            "grow hugepages on the allocated numa nodes",
+			systemSupported: newTestSupported(true),
+			zoneResources: map[string]corev1.ResourceList{
+				"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("32Mi")},
+				"node-1": {
+					corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("4Mi"),
+					corev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("2Gi"),
+				},
+			},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+				{Node: 1, Resources: corev1.ResourceList{
+					corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("4Mi"),
+					corev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("2Gi"),
+				}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "10", "1")
+				writeHugePages(helper, "node1", "hugepages-2048kB", "0", "0")
+				writeHugePages(helper, "node1", "hugepages-1048576kB", "1", "1")
+			},
+			wantLimit2M: pointer.Int64(12 * 1024 * 1024),
+			wantLimit1G: pointer.Int64(2 * 1024 * 1024 * 1024),
+			wantNr: map[string]string{
+				"node0/hugepages-2048kB":    "13",
+				"node1/hugepages-2048kB":    "2",
+				"node1/hugepages-1048576kB": "2",
+			},
+			wantCompact: map[string]bool{"node0": true, "node1": true},
+		},
+		{
+			name:            "free hugepages reserved by the running pods are not available",
+			systemSupported: newTestSupported(true),
+			zoneResources: map[string]corev1.ResourceList{
+				"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("32Mi")},
+			},
+			runningPods: []*statesinformer.PodMeta{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+					UID: "running-pod",
+					Annotations: newTestAnnotations(t, ext.NUMANodeResource{
+						Node:      0,
+						Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("16Mi")},
+					}),
+				}}},
+			},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				// the running pod has faulted 2 of its 8 pages
+				writeHugePages(helper, "node0", "hugepages-2048kB", "10", "8")
+			},
+			wantLimit2M: pointer.Int64(8 * 1024 * 1024),
+			wantNr:      map[string]string{"node0/hugepages-2048kB": "12"},
+			wantCompact: map[string]bool{"node0": true},
+		},
+		{
+			name:            "fail instead of growing beyond the capacity",
+			systemSupported: newTestSupported(true),
+			zoneResources: map[string]corev1.ResourceList{
+				"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("20Mi")},
+			},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "10", "1")
+			},
+			wantErr:     true,
+			wantNr:      map[string]string{"node0/hugepages-2048kB": "10"},
+			wantCompact: map[string]bool{"node0": false},
+		},
+		{
+			name:            "fail instead of growing without the capacity",
+			systemSupported: newTestSupported(true),
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "0", "0")
+			},
+			wantErr: true,
+			wantNr:  map[string]string{"node0/hugepages-2048kB": "0"},
+		},
+		{
+			name:            "release the grown pages on the other numa nodes when failed",
+			systemSupported: newTestSupported(true),
+			zoneResources: map[string]corev1.ResourceList{
+				"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")},
+			},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+				{Node: 1, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "0", "0")
+				writeHugePages(helper, "node1", "hugepages-2048kB", "0", "0")
+			},
+			wantErr: true,
+			wantNr: map[string]string{
+				"node0/hugepages-2048kB": "0",
+				"node1/hugepages-2048kB": "0",
+			},
+		},
+		{
+			name:            "skip hugetlb limit when system not supported",
+			systemSupported: newTestSupported(false),
+			zoneResources: map[string]corev1.ResourceList{
+				"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")},
+			},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "0", "0")
+			},
+			wantNr: map[string]string{"node0/hugepages-2048kB": "4"},
+		},
+		{
+			name:            "skip hugetlb limit of the unsupported page size",
+			systemSupported: map[int64]bool{hugePageSize2M: true, hugePageSize1G: false},
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{
+					corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("4Mi"),
+					corev1.ResourceHugePagesPrefix + "1Gi": resource.MustParse("1Gi"),
+				}},
+			},
+			prepareFn: func(helper *sysutil.FileTestUtil) {
+				writeHugePages(helper, "node0", "hugepages-2048kB", "2", "2")
+				writeHugePages(helper, "node0", "hugepages-1048576kB", "1", "1")
+			},
+			wantLimit2M: pointer.Int64(4 * 1024 * 1024),
+		},
+		{
+			name:            "failed to read hugepages",
+			systemSupported: newTestSupported(true),
+			numaResources: []ext.NUMANodeResource{
+				{Node: 0, Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.prepareFn != nil {
+				tt.prepareFn(helper)
+			}
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			si := mock_statesinformer.NewMockStatesInformer(ctrl)
+			si.EXPECT().GetNodeTopo().Return(newTestNodeTopo(tt.zoneResources)).AnyTimes()
+			si.EXPECT().GetAllPods().Return(tt.runningPods).AnyTimes()
+			p := &Plugin{
+				sysSupported:   tt.systemSupported,
+				statesInformer: si,
+			}
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					PodMeta:      protocol.PodMeta{Namespace: "default", Name: "test-pod", UID: "test-uid"},
+					Annotations:  newTestAnnotations(t, tt.numaResources...),
+					CgroupParent: "kubepods/pod-test-uid/",
+				},
+			}
+			err := p.SetPodHugePages(podCtx)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantLimit2M, podCtx.Response.Resources.HugetlbLimit2M)
+			assert.Equal(t, tt.wantLimit1G, podCtx.Response.Resources.HugetlbLimit1G)
+			for path, want := range tt.wantNr {
+				node, page, _ := strings.Cut(path, "/")
+				assert.Equal(t, want, helper.ReadFileContents(sysutil.GetNUMAHugepagesNrPath(node, page)), path)
+			}
+			for node, want := range tt.wantCompact {
+				assert.Equal(t, want, sysutil.FileExists(sysutil.GetNUMACompactPath(node)), node)
+			}
+		})
+	}
+}
+
+func TestPlugin_SetPodHugetlbLimit(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	// the hugepages of the running pod are not allocated again
+	writeHugePages(helper, "node0", "hugepages-2048kB", "4", "0")
+
+	p := &Plugin{
+		sysSupported: newTestSupported(true),
+	}
+	podCtx := &protocol.PodContext{
+		Request: protocol.PodRequest{
+			Annotations: newTestAnnotations(t, ext.NUMANodeResource{
+				Node:      0,
+				Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")},
+			}),
+			CgroupParent: "kubepods/pod-test-uid/",
+		},
+	}
+	err := p.SetPodHugetlbLimit(podCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, pointer.Int64(8*1024*1024), podCtx.Response.Resources.HugetlbLimit2M)
+	assert.Nil(t, podCtx.Response.Resources.HugetlbLimit1G)
+	assert.Equal(t, "4", helper.ReadFileContents(sysutil.GetNUMAHugepagesNrPath("node0", "hugepages-2048kB")))
+}
+
+func TestPlugin_ReleasePodHugePages(t *testing.T) {
+	helper := sysutil.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	writeHugePages(helper, "node0", "hugepages-2048kB", "10", "1")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	si := mock_statesinformer.NewMockStatesInformer(ctrl)
+	si.EXPECT().GetNodeTopo().Return(newTestNodeTopo(map[string]corev1.ResourceList{
+		"node-0": {corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("32Mi")},
+	})).AnyTimes()
+	si.EXPECT().GetAllPods().Return(nil).AnyTimes()
+	p := &Plugin{
+		sysSupported:   newTestSupported(true),
+		statesInformer: si,
+	}
+	podCtx := &protocol.PodContext{
+		Request: protocol.PodRequest{
+			PodMeta: protocol.PodMeta{Namespace: "default", Name: "test-pod", UID: "test-uid"},
+			Annotations: newTestAnnotations(t, ext.NUMANodeResource{
+				Node:      0,
+				Resources: corev1.ResourceList{corev1.ResourceHugePagesPrefix + "2Mi": resource.MustParse("8Mi")},
+			}),
+		},
+	}
+	assert.NoError(t, p.SetPodHugePages(podCtx))
+	assert.Equal(t, "13", helper.ReadFileContents(sysutil.GetNUMAHugepagesNrPath("node0", "hugepages-2048kB")))
+
+	// the pages grown for the pod are released when the sandbox stops
+	stopCtx := &protocol.PodContext{
+		Request: protocol.PodRequest{
+			PodMeta: protocol.PodMeta{Namespace: "default", Name: "test-pod", UID: "test-uid"},
+		},
+	}
+	assert.NoError(t, p.ReleasePodHugePages(stopCtx))
+	assert.Equal(t, "10", helper.ReadFileContents(sysutil.GetNUMAHugepagesNrPath("node0", "hugepages-2048kB")))
+	// and they are not released again
+	assert.NoError(t, p.ReleasePodHugePages(stopCtx))
+	assert.Equal(t, "10", helper.ReadFileContents(sysutil.GetNUMAHugepagesNrPath("node0", "hugepages-2048kB")))
+	assert.Error(t, p.ReleasePodHugePages(nil))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpriority
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/tc"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "NetworkPriority"
+	description = "stamp the network priority of the pod cgroup by qos class at creation"
+)
+
+// the minors of the htb classes of the ebpf policy
+var netQoSClassMinors = map[tc.NetQoSClass]uint16{
+	tc.NETQoSSystem: netqos.SystemClassMinor,
+	tc.NETQoSLS:     netqos.LSClassMinor,
+	tc.NETQoSBE:     netqos.BEClassMinor,
+}
+
+var attachCgroupPriority = netqos.AttachCgroupPriority
+
+type Plugin struct {
+	rule        *netPriorityRule
+	ruleRWMutex sync.RWMutex
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = &Plugin{}
+	}
+	return singleton
+}
+
+// Register only registers the hook of the pod creation. The net_cls of the pods on cgroups-v1 is set by the
+// TCNetworkQoS plugin, and the egress of the running pods is classified by their ips under the ebpf policy.
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description, p.SetPodNetPriority)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule))
+}
+
+// SetPodNetPriority attaches a cgroup skb program on the cgroups-v2 pod cgroup before the sandbox starts, which sets
+// the skb priority to the class id of the qos class of the pod, so the containers are classified by the tc or ebpf
+// netqos policy since they start. There is no net_cls on cgroups-v2, while the net_cls.classid on cgroups-v1 is set by
+// the TCNetworkQoS plugin at the same stage.
+// NOTE: The skb priority only takes effect for the hostNetwork pods. The packets of the other pods are forwarded from
+// their network namespaces by the host, and the forwarding resets the skb priority by the tos, so they are classified
+// by their ips instead.
+func (p *Plugin) SetPodNetPriority(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	r := p.getRule()
+	if r == nil || !r.enable {
+		klog.V(5).Infof("hook plugin rule is nil or disabled, nothing to do for plugin %v", name)
+		return nil
+	}
+
+	if sysutil.GetCurrentCgroupVersion() != sysutil.CgroupVersionV2 {
+		return nil
+	}
+
+	req := podCtx.Request
+	// the pods of the bandwidth limit are allocated the dedicated classes by the TCNetworkQoS plugin
+	if r.policy == slov1alpha1.NETQOSPolicyTC && req.Annotations[ext.AnnotationNetworkQOS] != "" {
+		return nil
+	}
+	classID, ok := r.getClassID(tc.GetNetQoSClassByAttrs(req.Labels, req.Annotations))
+	if !ok {
+		return nil
+	}
+
+	cgroupDir := filepath.Join(sysutil.GetRootCgroupSubfsDir(""), req.CgroupParent)
+	if err := attachCgroupPriority(cgroupDir, classID); err != nil {
+		return fmt.Errorf("failed to set network priority %x for pod %s/%s, err: %w",
+			classID, req.PodMeta.Namespace, req.PodMeta.Name, err)
+	}
+	klog.V(5).Infof("set pod %s/%s network priority %x on cgroup %s",
+		req.PodMeta.Namespace, req.PodMeta.Name, classID, cgroupDir)
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpriority
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestPlugin_parseRule(t *testing.T) {
+	ebpfPolicy := slov1alpha1.NETQOSPolicyEBPF
+	terwayPolicy := slov1alpha1.NETQOSPolicyTerwayQos
+	tests := []struct {
+		name     string
+		nodeSLO  *slov1alpha1.NodeSLOSpec
+		wantRule *netPriorityRule
+	}{
+		{
+			name:     "tc policy by default",
+			nodeSLO:  &slov1alpha1.NodeSLOSpec{},
+			wantRule: &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyTC},
+		},
+		{
+			name: "ebpf policy",
+			nodeSLO: &slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					Policies: &slov1alpha1.ResourceQOSPolicies{NETQOSPolicy: &ebpfPolicy},
+				},
+			},
+			wantRule: &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyEBPF},
+		},
+		{
+			name: "disabled for terway-qos policy",
+			nodeSLO: &slov1alpha1.NodeSLOSpec{
+				ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+					Policies: &slov1alpha1.ResourceQOSPolicies{NETQOSPolicy: &terwayPolicy},
+				},
+			},
+			wantRule: &netPriorityRule{enable: false, policy: slov1alpha1.NETQOSPolicyTerwayQos},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{}
+			updated, err := p.parseRule(tt.nodeSLO)
+			assert.NoError(t, err)
+			assert.True(t, updated)
+			assert.Equal(t, tt.wantRule, p.getRule())
+			// not updated if unchanged
+			updated, err = p.parseRule(tt.nodeSLO)
+			assert.NoError(t, err)
+			assert.False(t, updated)
+		})
+	}
+}
+
+func TestPlugin_SetPodNetPriority(t *testing.T) {
+	type attached struct {
+		cgroupDir string
+		classID   uint32
+	}
+	tests := []struct {
+		name         string
+		rule         *netPriorityRule
+		useCgroupV2  bool
+		attachErr    error
+		labels       map[string]string
+		annotations  map[string]string
+		wantAttached *attached
+		wantErr      bool
+	}{
+		{
+			name:   "skip when rule is nil",
+			labels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+		},
+		{
+			name:   "skip when rule is disabled",
+			rule:   &netPriorityRule{enable: false, policy: slov1alpha1.NETQOSPolicyTerwayQos},
+			labels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+		},
+		{
+			name:        "skip pod without qos class",
+			rule:        &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyTC},
+			useCgroupV2: true,
+		},
+		{
+			name:   "skip on cgroups-v1 whose net_cls is set by the tc plugin",
+			rule:   &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyTC},
+			labels: map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+		},
+		{
+			name:         "attach priority program of tc class for be pod",
+			rule:         &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyTC},
+			useCgroupV2:  true,
+			labels:       map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			wantAttached: &attached{cgroupDir: "kubepods/pod-test-uid", classID: 0x10004},
+		},
+		{
+			name:        "skip pod of bandwidth limit for tc policy",
+			rule:        &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyTC},
+			useCgroupV2: true,
+			labels:      map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			annotations: map[string]string{ext.AnnotationNetworkQOS: `{"egressLimit":"10M"}`},
+		},
+		{
+			name:         "attach priority program of ebpf class for ls pod",
+			rule:         &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyEBPF},
+			useCgroupV2:  true,
+			labels:       map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			wantAttached: &attached{cgroupDir: "kubepods/pod-test-uid", classID: 0x20003},
+		},
+		{
+			name:         "attach priority program of ebpf class for system pod",
+			rule:         &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyEBPF},
+			useCgroupV2:  true,
+			labels:       map[string]string{ext.LabelPodQoS: string(ext.QoSSystem)},
+			wantAttached: &attached{cgroupDir: "kubepods/pod-test-uid", classID: 0x20002},
+		},
+		{
+			name:        "failed to attach priority program",
+			rule:        &netPriorityRule{enable: true, policy: slov1alpha1.NETQOSPolicyEBPF},
+			useCgroupV2: true,
+			attachErr:   fmt.Errorf("expected error"),
+			labels:      map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			helper.SetCgroupsV2(tt.useCgroupV2)
+
+			var gotAttached *attached
+			oldAttach := attachCgroupPriority
+			defer func() { attachCgroupPriority = oldAttach }()
+			attachCgroupPriority = func(cgroupDir string, classID uint32) error {
+				if tt.attachErr != nil {
+					return tt.attachErr
+				}
+				rel, err := filepath.Rel(sysutil.GetRootCgroupSubfsDir(""), cgroupDir)
+				assert.NoError(t, err)
+				gotAttached = &attached{cgroupDir: rel, classID: classID}
+				return nil
+			}
+
+			p := &Plugin{rule: tt.rule}
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels:       tt.labels,
+					Annotations:  tt.annotations,
+					CgroupParent: "kubepods/pod-test-uid/",
+				},
+			}
+			err := p.SetPodNetPriority(podCtx)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Nil(t, podCtx.Response.Resources.NetClsClassId)
+			assert.Equal(t, tt.wantAttached, gotAttached)
+		})
+	}
+}
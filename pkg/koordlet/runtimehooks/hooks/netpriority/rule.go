@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netpriority
+
+import (
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks/tc"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/netqos"
+)
+
+type netPriorityRule struct {
+	enable bool
+	policy slov1alpha1.NETQOSPolicy
+}
+
+// getClassID returns the tc handle of the htb class of the net qos class under the policy.
+func (r *netPriorityRule) getClassID(netQoS tc.NetQoSClass) (uint32, bool) {
+	if netQoS == tc.NETQoSNone {
+		return 0, false
+	}
+	switch r.policy {
+	case slov1alpha1.NETQOSPolicyTC:
+		return tc.GetNetQoSClassHandle(netQoS), true
+	case slov1alpha1.NETQOSPolicyEBPF:
+		minor, ok := netQoSClassMinors[netQoS]
+		if !ok {
+			return 0, false
+		}
+		return netqos.ClassID(minor), true
+	default:
+		return 0, false
+	}
+}
+
+func (p *Plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	if mergedNodeSLO == nil {
+		return false, nil
+	}
+	qosStrategy := mergedNodeSLO.ResourceQOSStrategy
+
+	// the tc policy is the default as the TCNetworkQoS plugin
+	policy := slov1alpha1.NETQOSPolicyTC
+	if qosStrategy != nil && qosStrategy.Policies != nil && qosStrategy.Policies.NETQOSPolicy != nil {
+		policy = *qosStrategy.Policies.NETQOSPolicy
+	}
+	newRule := &netPriorityRule{
+		// the classes of the terway-qos are not managed by koordlet
+		enable: policy == slov1alpha1.NETQOSPolicyTC || policy == slov1alpha1.NETQOSPolicyEBPF,
+		policy: policy,
+	}
+
+	updated := p.updateRule(newRule)
+	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule)
+	return updated, nil
+}
+
+func (p *Plugin) getRule() *netPriorityRule {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.rule == nil {
+		return nil
+	}
+	rule := *p.rule
+	return &rule
+}
+
+func (p *Plugin) updateRule(newRule *netPriorityRule) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, p.rule) {
+		p.rule = newRule
+		return true
+	}
+	return false
+}
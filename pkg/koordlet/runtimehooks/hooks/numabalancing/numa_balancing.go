@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numabalancing
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "NUMABalancing"
+	description = "toggle the automatic numa balancing for the memory pinned pod by qos class"
+)
+
+type Plugin struct {
+	rule        *numaBalancingRule
+	ruleRWMutex sync.RWMutex
+
+	sysSupported *bool
+
+	executor resourceexecutor.ResourceUpdateExecutor
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = &Plugin{}
+	}
+	return singleton
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description, p.SetPodNumaBalancing)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule),
+		rule.WithUpdateCallback(p.ruleUpdateCb),
+		rule.WithSystemSupported(p.SystemSupported))
+	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.MemoryNumaBalancing, "reconcile pod level memory numa balancing",
+		p.SetPodNumaBalancing, reconciler.NoneFilter())
+	p.executor = op.Executor
+}
+
+func (p *Plugin) SystemSupported() bool {
+	if p.sysSupported == nil {
+		isSupported, msg := false, "resource not found"
+		numaBalancingResource, err := sysutil.GetCgroupResource(sysutil.MemoryNumaBalancingName)
+		if err == nil {
+			isSupported, msg = numaBalancingResource.IsSupported(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+		}
+		p.sysSupported = pointer.Bool(isSupported)
+		klog.Infof("update system supported info to %v for plugin %v, supported msg %s",
+			*p.sysSupported, name, msg)
+	}
+	return *p.sysSupported
+}
+
+// SetPodNumaBalancing sets the numa balancing of the pod whose memory is pinned on NUMA nodes.
+// The other pods are skipped since they rely on the balancing for the memory locality.
+func (p *Plugin) SetPodNumaBalancing(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	r := p.getRule()
+	if r == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+
+	req := podCtx.Request
+	isPinned, err := isPodMemoryPinned(req.Annotations)
+	if err != nil {
+		return fmt.Errorf("failed to get resource status, err: %w", err)
+	}
+	if !isPinned {
+		return nil
+	}
+	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
+	podCtx.Response.Resources.MemoryNumaBalancing = pointer.Int64(r.getPodNumaBalancing(podQOS))
+	return nil
+}
+
+// isPodMemoryPinned checks if the memory of the pod is allocated on the NUMA nodes.
+func isPodMemoryPinned(annotations map[string]string) (bool, error) {
+	resourceStatus, err := ext.GetResourceStatus(annotations)
+	if err != nil {
+		return false, err
+	}
+	for _, numaNode := range resourceStatus.NUMANodeResources {
+		if numaNode.Resources != nil && !numaNode.Resources.Memory().IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
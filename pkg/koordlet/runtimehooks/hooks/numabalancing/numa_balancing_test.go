@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numabalancing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+)
+
+func TestPlugin_SetPodNumaBalancing(t *testing.T) {
+	testRule := &numaBalancingRule{
+		podQOSParams: map[ext.QoSClass]int64{
+			ext.QoSLSE: 0,
+			ext.QoSLSR: 0,
+			ext.QoSLS:  1,
+			ext.QoSBE:  1,
+		},
+	}
+	memoryPinnedStatus := `{"numaNodeResources":[{"node":0,"resources":{"cpu":"4","memory":"8Gi"}}]}`
+	tests := []struct {
+		name            string
+		rule            *numaBalancingRule
+		systemSupported bool
+		labels          map[string]string
+		annotations     map[string]string
+		want            *int64
+		wantErr         bool
+	}{
+		{
+			name:            "disable for memory pinned lsr pod",
+			rule:            testRule,
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			annotations:     map[string]string{ext.AnnotationResourceStatus: memoryPinnedStatus},
+			want:            pointer.Int64(0),
+		},
+		{
+			name:            "enable for memory pinned ls pod",
+			rule:            testRule,
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			annotations:     map[string]string{ext.AnnotationResourceStatus: memoryPinnedStatus},
+			want:            pointer.Int64(1),
+		},
+		{
+			name:            "skip lsr pod whose memory is not pinned",
+			rule:            testRule,
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			annotations: map[string]string{
+				ext.AnnotationResourceStatus: `{"cpuset":"0-3","numaNodeResources":[{"node":0,"resources":{"cpu":"4"}}]}`,
+			},
+		},
+		{
+			name:            "skip be pod without resource status",
+			rule:            testRule,
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+		},
+		{
+			name:            "skip when system not supported",
+			rule:            testRule,
+			systemSupported: false,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			annotations:     map[string]string{ext.AnnotationResourceStatus: memoryPinnedStatus},
+		},
+		{
+			name:            "skip when rule is nil",
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			annotations:     map[string]string{ext.AnnotationResourceStatus: memoryPinnedStatus},
+		},
+		{
+			name:            "invalid resource status",
+			rule:            testRule,
+			systemSupported: true,
+			labels:          map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+			annotations:     map[string]string{ext.AnnotationResourceStatus: "invalid"},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				rule:         tt.rule,
+				sysSupported: pointer.Bool(tt.systemSupported),
+			}
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels:       tt.labels,
+					Annotations:  tt.annotations,
+					CgroupParent: "kubepods/pod-test-uid/",
+				},
+			}
+			err := p.SetPodNumaBalancing(podCtx)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, podCtx.Response.Resources.MemoryNumaBalancing)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numabalancing
+
+import (
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+// defaultNumaBalancing is the kernel default which enables the numa balancing.
+const defaultNumaBalancing int64 = 1
+
+type numaBalancingRule struct {
+	podQOSParams map[ext.QoSClass]int64
+}
+
+func (r *numaBalancingRule) getPodNumaBalancing(podQoSClass ext.QoSClass) int64 {
+	if val, exist := r.podQOSParams[podQoSClass]; exist {
+		return val
+	}
+	return defaultNumaBalancing
+}
+
+func getNumaBalancing(resourceQOS *slov1alpha1.ResourceQOS) int64 {
+	// the memory qos is merged as the none config in states informer if it is disabled
+	if resourceQOS == nil || resourceQOS.MemoryQOS == nil || resourceQOS.MemoryQOS.NumaBalancing == nil {
+		return defaultNumaBalancing
+	}
+	return *resourceQOS.MemoryQOS.NumaBalancing
+}
+
+func (p *Plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	qosStrategy := mergedNodeSLO.ResourceQOSStrategy
+	if qosStrategy == nil {
+		qosStrategy = &slov1alpha1.ResourceQOSStrategy{}
+	}
+
+	lsrValue := getNumaBalancing(qosStrategy.LSRClass)
+	newRule := &numaBalancingRule{
+		podQOSParams: map[ext.QoSClass]int64{
+			ext.QoSLSE: lsrValue,
+			ext.QoSLSR: lsrValue,
+			ext.QoSLS:  getNumaBalancing(qosStrategy.LSClass),
+			ext.QoSBE:  getNumaBalancing(qosStrategy.BEClass),
+		},
+	}
+
+	updated := p.updateRule(newRule)
+	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule)
+	return updated, nil
+}
+
+func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+
+	for _, podMeta := range target.Pods {
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		if err := p.SetPodNumaBalancing(podCtx); err != nil {
+			klog.V(4).Infof("failed to set pod numa balancing during callback %s, pod %s, err: %s",
+				name, podMeta.Key(), err)
+			continue
+		}
+		podCtx.ReconcilerDone(p.executor)
+	}
+	return nil
+}
+
+func (p *Plugin) getRule() *numaBalancingRule {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.rule == nil {
+		return nil
+	}
+	rule := *p.rule
+	return &rule
+}
+
+func (p *Plugin) updateRule(newRule *numaBalancingRule) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, p.rule) {
+		p.rule = newRule
+		return true
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package numabalancing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestPlugin_parseRule(t *testing.T) {
+	p := &Plugin{}
+	nodeSLO := &slov1alpha1.NodeSLOSpec{
+		ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+			LSRClass: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					Enable:    pointer.Bool(true),
+					MemoryQOS: slov1alpha1.MemoryQOS{NumaBalancing: pointer.Int64(0)},
+				},
+			},
+			LSClass: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					Enable:    pointer.Bool(false),
+					MemoryQOS: *sloconfig.NoneMemoryQOS(),
+				},
+			},
+		},
+	}
+	updated, err := p.parseRule(nodeSLO)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, &numaBalancingRule{
+		podQOSParams: map[ext.QoSClass]int64{
+			ext.QoSLSE: 0,
+			ext.QoSLSR: 0,
+			ext.QoSLS:  1,
+			ext.QoSBE:  1,
+		},
+	}, p.getRule())
+
+	updated, err = p.parseRule(nodeSLO)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestPlugin_ruleUpdateCb(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	pinnedPodDir := "kubepods/podpinned"
+	unpinnedPodDir := "kubepods/podunpinned"
+	helper.SetResourcesSupported(true, system.MemoryNumaBalancing)
+	helper.WriteCgroupFileContents(pinnedPodDir, system.MemoryNumaBalancing, "1")
+	helper.WriteCgroupFileContents(unpinnedPodDir, system.MemoryNumaBalancing, "1")
+
+	p := &Plugin{
+		rule: &numaBalancingRule{
+			podQOSParams: map[ext.QoSClass]int64{
+				ext.QoSLSR: 0,
+			},
+		},
+		sysSupported: pointer.Bool(true),
+		executor:     resourceexecutor.NewTestResourceExecutor(),
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	p.executor.Run(stop)
+
+	target := &statesinformer.CallbackTarget{
+		Pods: []*statesinformer.PodMeta{
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "pod-pinned",
+						Labels: map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+						Annotations: map[string]string{
+							ext.AnnotationResourceStatus: `{"numaNodeResources":[{"node":0,"resources":{"memory":"8Gi"}}]}`,
+						},
+					},
+				},
+				CgroupDir: pinnedPodDir,
+			},
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "pod-unpinned",
+						Labels: map[string]string{ext.LabelPodQoS: string(ext.QoSLSR)},
+					},
+				},
+				CgroupDir: unpinnedPodDir,
+			},
+		},
+	}
+	assert.NoError(t, p.ruleUpdateCb(target))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(pinnedPodDir, system.MemoryNumaBalancing))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(unpinnedPodDir, system.MemoryNumaBalancing))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shm
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "shm size inject"
+	description = "mount a sized tmpfs on /dev/shm of the container according to the annotation or gpu allocation"
+
+	ShmMountPath = "/dev/shm"
+)
+
+type shmPlugin struct{}
+
+func (p *shmPlugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreCreateContainer, name, description, p.InjectContainerShm)
+}
+
+var singleton *shmPlugin
+
+func Object() *shmPlugin {
+	if singleton == nil {
+		singleton = &shmPlugin{}
+	}
+	return singleton
+}
+
+// InjectContainerShm mounts a tmpfs with the expected size on the /dev/shm of the container. The size is taken from
+// the pod annotation, or else computed by the allocated GPUs and the shm size per GPU of the node.
+// Since the tmpfs pages are charged to the memory cgroup of the container, the size is capped by the container memory
+// limit, so the shm usage stays within the memory QoS of the pod instead of triggering the OOM beyond the limit.
+// NOTE: currently only the NRI mode supports adding mounts.
+func (p *shmPlugin) InjectContainerShm(proto protocol.HooksProtocol) error {
+	containerCtx, _ := proto.(*protocol.ContainerContext)
+	if containerCtx == nil {
+		return fmt.Errorf("container protocol is nil for plugin shm")
+	}
+	containerReq := containerCtx.Request
+	size, err := getShmSize(containerReq.PodAnnotations)
+	if err != nil {
+		return err
+	}
+	if size <= 0 {
+		klog.V(5).Infof("no shm size required for container %s/%s/%s, skip",
+			containerReq.PodMeta.Namespace, containerReq.PodMeta.Name, containerReq.ContainerMeta.Name)
+		return nil
+	}
+	if memoryLimit := getContainerMemoryLimit(containerCtx); memoryLimit > 0 && size > memoryLimit {
+		klog.V(4).Infof("shm size %d exceeds the memory limit %d of container %s/%s/%s, use the memory limit",
+			size, memoryLimit, containerReq.PodMeta.Namespace, containerReq.PodMeta.Name, containerReq.ContainerMeta.Name)
+		size = memoryLimit
+	}
+
+	containerCtx.Response.AddContainerMounts = append(containerCtx.Response.AddContainerMounts,
+		&protocol.Mount{
+			Destination: ShmMountPath,
+			Type:        "tmpfs",
+			Source:      "shm",
+			Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", fmt.Sprintf("size=%d", size)},
+		},
+	)
+	klog.V(5).Infof("inject shm size %d for container %s/%s/%s",
+		size, containerReq.PodMeta.Namespace, containerReq.PodMeta.Name, containerReq.ContainerMeta.Name)
+	return nil
+}
+
+func getShmSize(podAnnotations map[string]string) (int64, error) {
+	size, err := ext.GetShmSize(podAnnotations)
+	if err != nil {
+		return 0, err
+	}
+	if size != nil {
+		return size.Value(), nil
+	}
+
+	if system.Conf.ShmSizePerGPU == "" {
+		return 0, nil
+	}
+	sizePerGPU, err := resource.ParseQuantity(system.Conf.ShmSizePerGPU)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse shm size per gpu %s, err: %w", system.Conf.ShmSizePerGPU, err)
+	}
+	alloc, err := ext.GetDeviceAllocations(podAnnotations)
+	if err != nil {
+		return 0, err
+	}
+	devices := alloc[schedulingv1alpha1.GPU]
+	return sizePerGPU.Value() * int64(len(devices)), nil
+}
+
+// getContainerMemoryLimit returns the memory limit of the container in bytes, where the response one injected by the
+// previous hooks is preferred. It returns 0 if the limit is unknown or unlimited.
+func getContainerMemoryLimit(containerCtx *protocol.ContainerContext) int64 {
+	if containerCtx.Response.Resources.MemoryLimit != nil {
+		return *containerCtx.Response.Resources.MemoryLimit
+	}
+	if containerCtx.Request.Resources != nil && containerCtx.Request.Resources.MemoryLimit != nil {
+		return *containerCtx.Request.Resources.MemoryLimit
+	}
+	return 0
+}
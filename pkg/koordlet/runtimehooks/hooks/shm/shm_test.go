@@ -0,0 +1,190 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_InjectContainerShm(t *testing.T) {
+	shmMount := func(size string) []*protocol.Mount {
+		return []*protocol.Mount{
+			{
+				Destination: ShmMountPath,
+				Type:        "tmpfs",
+				Source:      "shm",
+				Options:     []string{"nosuid", "noexec", "nodev", "mode=1777", "size=" + size},
+			},
+		}
+	}
+	tests := []struct {
+		name           string
+		sizePerGPU     string
+		proto          protocol.HooksProtocol
+		expectedError  bool
+		expectedMounts []*protocol.Mount
+	}{
+		{
+			name:          "test empty proto",
+			proto:         nil,
+			expectedError: true,
+		},
+		{
+			name:  "no shm size required",
+			proto: &protocol.ContainerContext{},
+		},
+		{
+			name: "shm size from annotation",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize: "1Gi",
+					},
+				},
+			},
+			expectedMounts: shmMount("1073741824"),
+		},
+		{
+			name: "invalid annotation",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize: "-1Gi",
+					},
+				},
+			},
+			expectedError: true,
+		},
+		{
+			name:       "annotation takes precedence over gpu allocation",
+			sizePerGPU: "2Gi",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize:         "1Gi",
+						ext.AnnotationDeviceAllocated: `{"gpu": [{"minor": 0},{"minor": 1}]}`,
+					},
+				},
+			},
+			expectedMounts: shmMount("1073741824"),
+		},
+		{
+			name:       "shm size from gpu allocation",
+			sizePerGPU: "2Gi",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"gpu": [{"minor": 0},{"minor": 1}]}`,
+					},
+				},
+			},
+			expectedMounts: shmMount("4294967296"),
+		},
+		{
+			name: "gpu allocation without shm size per gpu",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"gpu": [{"minor": 0},{"minor": 1}]}`,
+					},
+				},
+			},
+		},
+		{
+			name:       "no gpu allocated",
+			sizePerGPU: "2Gi",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationDeviceAllocated: `{"fpga": [{"minor": 0}]}`,
+					},
+				},
+			},
+		},
+		{
+			name: "shm size capped by the memory limit",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize: "1Gi",
+					},
+				},
+				Response: protocol.ContainerResponse{
+					Resources: protocol.Resources{
+						MemoryLimit: pointer.Int64(512 * 1024 * 1024),
+					},
+				},
+			},
+			expectedMounts: shmMount("536870912"),
+		},
+		{
+			name: "shm size capped by the request memory limit",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize: "1Gi",
+					},
+					Resources: &protocol.Resources{
+						MemoryLimit: pointer.Int64(256 * 1024 * 1024),
+					},
+				},
+			},
+			expectedMounts: shmMount("268435456"),
+		},
+		{
+			name: "memory limit larger than shm size",
+			proto: &protocol.ContainerContext{
+				Request: protocol.ContainerRequest{
+					PodAnnotations: map[string]string{
+						ext.AnnotationShmSize: "1Gi",
+					},
+					Resources: &protocol.Resources{
+						MemoryLimit: pointer.Int64(4 * 1024 * 1024 * 1024),
+					},
+				},
+			},
+			expectedMounts: shmMount("1073741824"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldSizePerGPU := system.Conf.ShmSizePerGPU
+			system.Conf.ShmSizePerGPU = tt.sizePerGPU
+			defer func() {
+				system.Conf.ShmSizePerGPU = oldSizePerGPU
+			}()
+
+			p := Object()
+			var containerCtx *protocol.ContainerContext
+			if tt.proto != nil {
+				containerCtx = tt.proto.(*protocol.ContainerContext)
+			}
+			err := p.InjectContainerShm(containerCtx)
+			assert.Equal(t, tt.expectedError, err != nil, err)
+			if containerCtx != nil {
+				assert.Equal(t, tt.expectedMounts, containerCtx.Response.AddContainerMounts)
+			}
+		})
+	}
+}
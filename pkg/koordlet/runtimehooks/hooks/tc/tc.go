@@ -44,3 +44,7 @@ func (n *tcPlugin) Reconcile() {
 func (n *tcPlugin) Register(op hooks.Options) {
 	klog.V(5).Infof("register hook %v", name)
 }
+
+func GetNetQoSClassHandle(netQos NetQoSClass) uint32 {
+	return 0
+}
@@ -48,6 +48,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
 )
 
 const (
@@ -145,6 +146,8 @@ func (p *tcPlugin) Register(op hooks.Options) {
 		rule.WithUpdateCallback(p.ruleUpdateCbForPod))
 	// TODO register NRI after there is pod ip in NRI request
 
+	// the net_cls.classid is set before the sandbox starts, so the containers are classified since they start
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description+" (pod net class id)", p.SetPodNetCls)
 	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.NetClsClassId, description+" (pod net class id)",
 		p.SetPodNetCls, reconciler.NoneFilter())
 
@@ -180,19 +183,7 @@ func (p *tcPlugin) SetPodNetCls(proto protocol.HooksProtocol) error {
 			handle = handleId
 		}
 	} else {
-		netqosToHandle := func(qos NetQoSClass) uint32 {
-			m := map[NetQoSClass]uint32{
-				NETQoSSystem: systemClass,
-				NETQoSLS:     lsClass,
-				NETQoSBE:     beClass,
-			}
-			if handle, ok := m[qos]; ok {
-				return handle
-			}
-			return lsClass
-		}
-
-		handle = netqosToHandle(netQos)
+		handle = GetNetQoSClassHandle(netQos)
 	}
 
 	podCtx.Response.Resources.NetClsClassId = pointer.Uint32(handle)
@@ -200,6 +191,20 @@ func (p *tcPlugin) SetPodNetCls(proto protocol.HooksProtocol) error {
 	return nil
 }
 
+// GetNetQoSClassHandle returns the handle of the htb class of the net qos class, and the unknown class goes to the
+// ls class.
+func GetNetQoSClassHandle(netQos NetQoSClass) uint32 {
+	m := map[NetQoSClass]uint32{
+		NETQoSSystem: systemClass,
+		NETQoSLS:     lsClass,
+		NETQoSBE:     beClass,
+	}
+	if handle, ok := m[netQos]; ok {
+		return handle
+	}
+	return lsClass
+}
+
 func (p *tcPlugin) createTcRulesForHostPod(rule *tcRule, pod *v1.Pod, egress uint64) error {
 	handle, _ := rule.uidToHandle[pod.UID]
 	netqos := GetNetQoSClassByAttrs(pod.Labels, pod.Annotations)
@@ -17,6 +17,7 @@ limitations under the License.
 package terwayqos
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -219,7 +220,7 @@ func (p *Plugin) syncPodConfig() error {
 		return err
 	}
 
-	p.executor.UpdateBatch(true, pRes)
+	p.executor.UpdateBatch(context.TODO(), true, pRes)
 	return nil
 }
 
@@ -252,7 +253,7 @@ func (p *Plugin) syncNodeConfig() error {
 		return err
 	}
 
-	p.executor.UpdateBatch(true, nRes)
+	p.executor.UpdateBatch(context.TODO(), true, nRes)
 	return nil
 }
 
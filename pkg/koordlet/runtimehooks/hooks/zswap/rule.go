@@ -0,0 +1,141 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zswap
+
+import (
+	"reflect"
+
+	"k8s.io/klog/v2"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+const (
+	// defaultZswapEnable is the kernel default which does not limit the zswap usage.
+	defaultZswapEnable int64 = 1
+	// defaultZswapWriteback is the kernel default which allows the zswap writeback.
+	defaultZswapWriteback int64 = 1
+	// zswapMaxUnlimited is converted to "max" by the cgroup updater.
+	zswapMaxUnlimited int64 = -1
+)
+
+type zswapParam struct {
+	enable    int64
+	writeback int64
+}
+
+// getZswapMax returns the value of `memory.zswap.max`.
+func (z zswapParam) getZswapMax() int64 {
+	if z.enable == 0 {
+		return 0
+	}
+	return zswapMaxUnlimited
+}
+
+type zswapRule struct {
+	podQOSParams map[ext.QoSClass]zswapParam
+}
+
+func (r *zswapRule) getPodZswapParam(podQoSClass ext.QoSClass) zswapParam {
+	if val, exist := r.podQOSParams[podQoSClass]; exist {
+		return val
+	}
+	return zswapParam{enable: defaultZswapEnable, writeback: defaultZswapWriteback}
+}
+
+func getZswapParam(resourceQOS *slov1alpha1.ResourceQOS) zswapParam {
+	param := zswapParam{enable: defaultZswapEnable, writeback: defaultZswapWriteback}
+	// the memory qos is merged as the none config in states informer if it is disabled
+	if resourceQOS == nil || resourceQOS.MemoryQOS == nil {
+		return param
+	}
+	if resourceQOS.MemoryQOS.ZswapEnable != nil {
+		param.enable = *resourceQOS.MemoryQOS.ZswapEnable
+	}
+	if resourceQOS.MemoryQOS.ZswapWriteback != nil {
+		param.writeback = *resourceQOS.MemoryQOS.ZswapWriteback
+	}
+	return param
+}
+
+func (p *Plugin) parseRule(mergedNodeSLOIf interface{}) (bool, error) {
+	mergedNodeSLO := mergedNodeSLOIf.(*slov1alpha1.NodeSLOSpec)
+	qosStrategy := mergedNodeSLO.ResourceQOSStrategy
+	if qosStrategy == nil {
+		qosStrategy = &slov1alpha1.ResourceQOSStrategy{}
+	}
+
+	lsrValue := getZswapParam(qosStrategy.LSRClass)
+	newRule := &zswapRule{
+		podQOSParams: map[ext.QoSClass]zswapParam{
+			ext.QoSLSE: lsrValue,
+			ext.QoSLSR: lsrValue,
+			ext.QoSLS:  getZswapParam(qosStrategy.LSClass),
+			ext.QoSBE:  getZswapParam(qosStrategy.BEClass),
+		},
+	}
+
+	updated := p.updateRule(newRule)
+	klog.Infof("runtime hook plugin %s update rule %v, new rule %v", name, updated, newRule)
+	return updated, nil
+}
+
+func (p *Plugin) ruleUpdateCb(target *statesinformer.CallbackTarget) error {
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	if target == nil {
+		klog.Warningf("callback target is nil")
+		return nil
+	}
+
+	for _, podMeta := range target.Pods {
+		podCtx := &protocol.PodContext{}
+		podCtx.FromReconciler(podMeta)
+		if err := p.SetPodZswap(podCtx); err != nil {
+			klog.V(4).Infof("failed to set pod zswap during callback %s, pod %s, err: %s",
+				name, podMeta.Key(), err)
+			continue
+		}
+		podCtx.ReconcilerDone(p.executor)
+	}
+	return nil
+}
+
+func (p *Plugin) getRule() *zswapRule {
+	p.ruleRWMutex.RLock()
+	defer p.ruleRWMutex.RUnlock()
+	if p.rule == nil {
+		return nil
+	}
+	rule := *p.rule
+	return &rule
+}
+
+func (p *Plugin) updateRule(newRule *zswapRule) bool {
+	p.ruleRWMutex.Lock()
+	defer p.ruleRWMutex.Unlock()
+	if !reflect.DeepEqual(newRule, p.rule) {
+		p.rule = newRule
+		return true
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zswap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestPlugin_parseRule(t *testing.T) {
+	p := &Plugin{}
+	nodeSLO := &slov1alpha1.NodeSLOSpec{
+		ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+			LSClass: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					Enable: pointer.Bool(true),
+					MemoryQOS: slov1alpha1.MemoryQOS{
+						ZswapEnable:    pointer.Int64(0),
+						ZswapWriteback: pointer.Int64(0),
+					},
+				},
+			},
+			BEClass: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					Enable: pointer.Bool(true),
+					MemoryQOS: slov1alpha1.MemoryQOS{
+						ZswapEnable: pointer.Int64(1),
+					},
+				},
+			},
+			LSRClass: &slov1alpha1.ResourceQOS{
+				MemoryQOS: &slov1alpha1.MemoryQOSCfg{
+					Enable:    pointer.Bool(false),
+					MemoryQOS: *sloconfig.NoneMemoryQOS(),
+				},
+			},
+		},
+	}
+	updated, err := p.parseRule(nodeSLO)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, &zswapRule{
+		podQOSParams: map[ext.QoSClass]zswapParam{
+			ext.QoSLSE: {enable: 1, writeback: 1},
+			ext.QoSLSR: {enable: 1, writeback: 1},
+			ext.QoSLS:  {enable: 0, writeback: 0},
+			ext.QoSBE:  {enable: 1, writeback: 1},
+		},
+	}, p.getRule())
+
+	updated, err = p.parseRule(nodeSLO)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestPlugin_ruleUpdateCb(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(true)
+	lsPodDir := "kubepods/podls"
+	bePodDir := "kubepods/besteffort/podbe"
+	helper.WriteCgroupFileContents(lsPodDir, system.MemoryZswapMaxV2, "max")
+	helper.WriteCgroupFileContents(lsPodDir, system.MemoryZswapWritebackV2, "1")
+	helper.WriteCgroupFileContents(bePodDir, system.MemoryZswapMaxV2, "0")
+	helper.WriteCgroupFileContents(bePodDir, system.MemoryZswapWritebackV2, "0")
+
+	p := &Plugin{
+		rule: &zswapRule{
+			podQOSParams: map[ext.QoSClass]zswapParam{
+				ext.QoSLS: {enable: 0, writeback: 0},
+				ext.QoSBE: {enable: 1, writeback: 1},
+			},
+		},
+		sysSupported:       pointer.Bool(true),
+		writebackSupported: pointer.Bool(true),
+		executor:           resourceexecutor.NewTestResourceExecutor(),
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	p.executor.Run(stop)
+
+	target := &statesinformer.CallbackTarget{
+		Pods: []*statesinformer.PodMeta{
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "pod-ls",
+						Labels: map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+					},
+				},
+				CgroupDir: lsPodDir,
+			},
+			{
+				Pod: &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "pod-be",
+						Labels: map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+					},
+				},
+				CgroupDir: bePodDir,
+			},
+		},
+	}
+	assert.NoError(t, p.ruleUpdateCb(target))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(lsPodDir, system.MemoryZswapMaxV2))
+	assert.Equal(t, "0", helper.ReadCgroupFileContents(lsPodDir, system.MemoryZswapWritebackV2))
+	assert.Equal(t, "max", helper.ReadCgroupFileContents(bePodDir, system.MemoryZswapMaxV2))
+	assert.Equal(t, "1", helper.ReadCgroupFileContents(bePodDir, system.MemoryZswapWritebackV2))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zswap
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/reconciler"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/rule"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	rmconfig "github.com/koordinator-sh/koordinator/pkg/runtimeproxy/config"
+)
+
+const (
+	name        = "Zswap"
+	description = "set the zswap limit and writeback of the pod by qos class"
+)
+
+type Plugin struct {
+	rule        *zswapRule
+	ruleRWMutex sync.RWMutex
+
+	sysSupported       *bool
+	writebackSupported *bool
+
+	executor resourceexecutor.ResourceUpdateExecutor
+}
+
+var singleton *Plugin
+
+func Object() *Plugin {
+	if singleton == nil {
+		singleton = &Plugin{}
+	}
+	return singleton
+}
+
+func (p *Plugin) Register(op hooks.Options) {
+	klog.V(5).Infof("register hook %v", name)
+	hooks.Register(rmconfig.PreRunPodSandbox, name, description, p.SetPodZswap)
+	rule.Register(name, description,
+		rule.WithParseFunc(statesinformer.RegisterTypeNodeSLOSpec, p.parseRule),
+		rule.WithUpdateCallback(p.ruleUpdateCb),
+		rule.WithSystemSupported(p.SystemSupported))
+	reconciler.RegisterCgroupReconciler(reconciler.PodLevel, sysutil.MemoryZswapMaxV2, "reconcile pod level memory zswap",
+		p.SetPodZswap, reconciler.NoneFilter())
+	p.executor = op.Executor
+}
+
+// SystemSupported checks if the `memory.zswap.max` is supported, which requires the cgroups-v2 and the kernel zswap.
+func (p *Plugin) SystemSupported() bool {
+	if p.sysSupported == nil {
+		p.sysSupported = pointer.Bool(isResourceSupported(sysutil.MemoryZswapMaxName))
+	}
+	return *p.sysSupported
+}
+
+// WritebackSupported checks if the `memory.zswap.writeback` is supported, which requires a newer kernel than the
+// `memory.zswap.max`.
+func (p *Plugin) WritebackSupported() bool {
+	if p.writebackSupported == nil {
+		p.writebackSupported = pointer.Bool(isResourceSupported(sysutil.MemoryZswapWritebackName))
+	}
+	return *p.writebackSupported
+}
+
+func isResourceSupported(resourceType sysutil.ResourceType) bool {
+	isSupported, msg := false, "resource not found"
+	r, err := sysutil.GetCgroupResource(resourceType)
+	if err == nil {
+		isSupported, msg = r.IsSupported(util.GetPodQoSRelativePath(corev1.PodQOSGuaranteed))
+	}
+	klog.Infof("update system supported info of %s to %v for plugin %v, supported msg %s",
+		resourceType, isSupported, name, msg)
+	return isSupported
+}
+
+// SetPodZswap sets the zswap limit and the zswap writeback of the pod according to its qos class.
+// e.g. The BE pods can be directed to the compressed swap while the LS pods bypass it.
+func (p *Plugin) SetPodZswap(proto protocol.HooksProtocol) error {
+	podCtx, ok := proto.(*protocol.PodContext)
+	if !ok || podCtx == nil {
+		return fmt.Errorf("pod protocol is nil for plugin %s", name)
+	}
+	if !p.SystemSupported() {
+		klog.V(5).Infof("plugin %s is not supported by system", name)
+		return nil
+	}
+	r := p.getRule()
+	if r == nil {
+		klog.V(5).Infof("hook plugin rule is nil, nothing to do for plugin %v", name)
+		return nil
+	}
+
+	req := podCtx.Request
+	podQOS := ext.GetQoSClassByAttrs(req.Labels, req.Annotations)
+	param := r.getPodZswapParam(podQOS)
+	podCtx.Response.Resources.MemoryZswapMax = pointer.Int64(param.getZswapMax())
+	if p.WritebackSupported() {
+		podCtx.Response.Resources.MemoryZswapWriteback = pointer.Int64(param.writeback)
+	}
+	return nil
+}
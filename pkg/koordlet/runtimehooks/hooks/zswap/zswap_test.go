@@ -0,0 +1,114 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package zswap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	ext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/protocol"
+)
+
+func TestPlugin_SetPodZswap(t *testing.T) {
+	testRule := &zswapRule{
+		podQOSParams: map[ext.QoSClass]zswapParam{
+			ext.QoSLSE: {enable: 0, writeback: 0},
+			ext.QoSLSR: {enable: 0, writeback: 0},
+			ext.QoSLS:  {enable: 0, writeback: 0},
+			ext.QoSBE:  {enable: 1, writeback: 1},
+		},
+	}
+	tests := []struct {
+		name               string
+		rule               *zswapRule
+		systemSupported    bool
+		writebackSupported bool
+		labels             map[string]string
+		wantMax            *int64
+		wantWriteback      *int64
+	}{
+		{
+			name:               "bypass zswap for ls pod",
+			rule:               testRule,
+			systemSupported:    true,
+			writebackSupported: true,
+			labels:             map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+			wantMax:            pointer.Int64(0),
+			wantWriteback:      pointer.Int64(0),
+		},
+		{
+			name:               "enable zswap for be pod",
+			rule:               testRule,
+			systemSupported:    true,
+			writebackSupported: true,
+			labels:             map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			wantMax:            pointer.Int64(-1),
+			wantWriteback:      pointer.Int64(1),
+		},
+		{
+			name:               "skip writeback when not supported",
+			rule:               testRule,
+			systemSupported:    true,
+			writebackSupported: false,
+			labels:             map[string]string{ext.LabelPodQoS: string(ext.QoSBE)},
+			wantMax:            pointer.Int64(-1),
+		},
+		{
+			name:               "use default for pod without qos class",
+			rule:               testRule,
+			systemSupported:    true,
+			writebackSupported: true,
+			wantMax:            pointer.Int64(-1),
+			wantWriteback:      pointer.Int64(1),
+		},
+		{
+			name:               "skip when system not supported",
+			rule:               testRule,
+			systemSupported:    false,
+			writebackSupported: true,
+			labels:             map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+		},
+		{
+			name:               "skip when rule is nil",
+			systemSupported:    true,
+			writebackSupported: true,
+			labels:             map[string]string{ext.LabelPodQoS: string(ext.QoSLS)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Plugin{
+				rule:               tt.rule,
+				sysSupported:       pointer.Bool(tt.systemSupported),
+				writebackSupported: pointer.Bool(tt.writebackSupported),
+			}
+			podCtx := &protocol.PodContext{
+				Request: protocol.PodRequest{
+					Labels:       tt.labels,
+					CgroupParent: "kubepods/pod-test-uid/",
+				},
+			}
+			err := p.SetPodZswap(podCtx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantMax, podCtx.Response.Resources.MemoryZswapMax)
+			assert.Equal(t, tt.wantWriteback, podCtx.Response.Resources.MemoryZswapWriteback)
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimehooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/nri"
+)
+
+func (r *runtimeHook) getNRIServer() *nri.NriServer {
+	r.nriLock.RLock()
+	defer r.nriLock.RUnlock()
+	return r.nriServer
+}
+
+// syncHookModes retries the nri mode if it is unavailable, and reports the runtime hook modes to the node annotation.
+func (r *runtimeHook) syncHookModes() {
+	r.ensureNRIServer()
+	status := r.getHookModeStatus()
+	if err := r.reportHookModes(status); err != nil {
+		klog.Warningf("failed to report runtime hook modes %+v to node %s, err: %v", status, r.nodeName, err)
+	}
+}
+
+// ensureNRIServer creates and starts the nri server if the nri socket was unavailable when the koordlet started, so
+// the hooks result in the nri mode once the container runtime enables the NRI.
+// The disconnected nri server retries to connect with a limited backoff, so it is recreated when it is neither
+// connected nor connecting, e.g. the initial start failed or the reconnecting backoff has given up.
+func (r *runtimeHook) ensureNRIServer() {
+	if r.nriOptions == nil {
+		return
+	}
+	oldServer := r.getNRIServer()
+	if oldServer != nil && (oldServer.IsConnected() || oldServer.IsConnecting()) {
+		return
+	}
+	nriServer, err := nri.NewNriServer(*r.nriOptions)
+	if err != nil {
+		klog.V(5).Infof("nri mode runtime hook server is still unavailable, err: %v", err)
+		return
+	}
+	if err = nriServer.Start(); err != nil {
+		klog.Warningf("nri mode runtime hook server start failed: %v", err)
+		return
+	}
+	if oldServer != nil {
+		// stop the replaced server so it no longer retries to connect
+		oldServer.Stop()
+	}
+	r.nriLock.Lock()
+	r.nriServer = nriServer
+	r.nriLock.Unlock()
+	klog.Infof("nri mode runtime hook server has started after the failover")
+}
+
+// getHookModeStatus returns the available modes in the order of the precedence. The standalone mode is always
+// available since the reconciler keeps running.
+func (r *runtimeHook) getHookModeStatus() *apiext.RuntimeHookModeStatus {
+	available := map[apiext.RuntimeHookMode]bool{
+		apiext.RuntimeHookModeStandalone: true,
+	}
+	if nriServer := r.getNRIServer(); nriServer != nil && nriServer.IsConnected() {
+		available[apiext.RuntimeHookModeNRI] = true
+	}
+	if r.server != nil && r.isProxyServing() {
+		available[apiext.RuntimeHookModeProxy] = true
+	}
+
+	status := &apiext.RuntimeHookModeStatus{}
+	for _, mode := range apiext.RuntimeHookModePrecedence {
+		if available[mode] {
+			status.AvailableModes = append(status.AvailableModes, mode)
+		}
+	}
+	status.Mode = status.AvailableModes[0]
+	return status
+}
+
+// isProxyServing returns whether the runtime proxy has served any hook request within the expiration. A non-positive
+// expiration means the served proxy never expires.
+func (r *runtimeHook) isProxyServing() bool {
+	lastServed := r.server.LastServedTime()
+	if lastServed.IsZero() {
+		return false
+	}
+	return r.proxyServedExpiration <= 0 || time.Since(lastServed) <= r.proxyServedExpiration
+}
+
+func (r *runtimeHook) reportHookModes(status *apiext.RuntimeHookModeStatus) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	if string(data) == r.reportedModes {
+		return nil
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, apiext.AnnotationNodeRuntimeHookMode, string(data))
+	_, err = r.kubeClient.CoreV1().Nodes().Patch(context.TODO(), r.nodeName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	klog.Infof("runtime hook modes of node %s changed from %q to %q", r.nodeName, r.reportedModes, string(data))
+	r.reportedModes = string(data)
+	return nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runtimehooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/nri"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/proxyserver"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+var _ proxyserver.Server = &fakeProxyServer{}
+
+type fakeProxyServer struct {
+	lastServed time.Time
+}
+
+func (f *fakeProxyServer) Setup() error { return nil }
+
+func (f *fakeProxyServer) Start() error { return nil }
+
+func (f *fakeProxyServer) Stop() {}
+
+func (f *fakeProxyServer) Register() error { return nil }
+
+func (f *fakeProxyServer) LastServedTime() time.Time { return f.lastServed }
+
+func Test_runtimeHook_syncHookModes(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	kubeClient := fake.NewSimpleClientset(node)
+	proxyServer := &fakeProxyServer{}
+	r := &runtimeHook{
+		server:                proxyServer,
+		kubeClient:            kubeClient,
+		nodeName:              node.Name,
+		proxyServedExpiration: time.Minute,
+		// the nri socket does not exist
+		nriOptions: &nri.Options{
+			NriSocketPath: "nri/nri.sock",
+		},
+	}
+	getStatus := func() *apiext.RuntimeHookModeStatus {
+		got, err := kubeClient.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+		assert.NoError(t, err)
+		status, err := apiext.GetRuntimeHookModeStatus(got.Annotations)
+		assert.NoError(t, err)
+		return status
+	}
+
+	// fail over to the standalone mode
+	r.syncHookModes()
+	assert.Nil(t, r.getNRIServer())
+	assert.Equal(t, &apiext.RuntimeHookModeStatus{
+		Mode:           apiext.RuntimeHookModeStandalone,
+		AvailableModes: []apiext.RuntimeHookMode{apiext.RuntimeHookModeStandalone},
+	}, getStatus())
+
+	// the proxy takes precedence over the standalone
+	proxyServer.lastServed = time.Now()
+	r.syncHookModes()
+	assert.Equal(t, &apiext.RuntimeHookModeStatus{
+		Mode:           apiext.RuntimeHookModeProxy,
+		AvailableModes: []apiext.RuntimeHookMode{apiext.RuntimeHookModeProxy, apiext.RuntimeHookModeStandalone},
+	}, getStatus())
+
+	// the disconnected nri server is not available
+	r.nriServer = &nri.NriServer{}
+	assert.Equal(t, apiext.RuntimeHookModeProxy, r.getHookModeStatus().Mode)
+
+	// no patch if unchanged
+	kubeClient.ClearActions()
+	r.syncHookModes()
+	assert.Len(t, kubeClient.Actions(), 0)
+
+	// the proxy expires if no request has been served recently
+	proxyServer.lastServed = time.Now().Add(-2 * time.Minute)
+	assert.Equal(t, apiext.RuntimeHookModeStandalone, r.getHookModeStatus().Mode)
+	r.proxyServedExpiration = 0
+	assert.Equal(t, apiext.RuntimeHookModeProxy, r.getHookModeStatus().Mode)
+}
@@ -73,6 +73,12 @@ type NriServer struct {
 	options  Options       // server options
 	stubOpts []stub.Option // nri stub options
 	stopped  *atomic.Bool  // if false, the stub will try to reconnect when stub.OnClose is invoked
+	// connected indicates whether the stub has registered to the container runtime, which is false when it is
+	// disconnected and still reconnecting
+	connected atomic.Bool
+	// connecting counts the in-flight starts and reconnecting retries, which is zero once the stub is connected or the
+	// retry has given up
+	connecting atomic.Int32
 }
 
 const (
@@ -119,6 +125,8 @@ func NewNriServer(opt Options) (*NriServer, error) {
 }
 
 func (p *NriServer) Start() error {
+	p.connecting.Inc()
+	defer p.connecting.Dec()
 	err := p.options.Validate()
 	if err != nil {
 		return err
@@ -143,19 +151,43 @@ func (p *NriServer) Start() error {
 
 	select {
 	case <-success:
+		p.connected.Store(true)
 		return nil
 	case <-errorChan:
 		return fmt.Errorf("nri start fail, err: %w", err)
 	}
 }
 
+// StartAsync starts the server in the background and calls the callback with the start result. The server is
+// considered connecting once it returns, so the caller does not recreate the server before the start completes.
+func (p *NriServer) StartAsync(callback func(error)) {
+	p.connecting.Inc()
+	go func() {
+		defer p.connecting.Dec()
+		callback(p.Start())
+	}()
+}
+
 func (p *NriServer) Stop() {
 	p.stopped.Store(true)
+	p.connected.Store(false)
 	p.stub.Stop()
 }
 
+// IsConnected returns whether the NRI server is connected to the container runtime.
+func (p *NriServer) IsConnected() bool {
+	return p.connected.Load()
+}
+
+// IsConnecting returns whether the NRI server is starting or retrying to connect to the container runtime.
+func (p *NriServer) IsConnecting() bool {
+	return p.connecting.Load() > 0
+}
+
 func (p *NriServer) Configure(_ context.Context, config, runtime, version string) (stub.EventMask, error) {
 	klog.V(4).Infof("got configuration data: %q from runtime %s %s", config, runtime, version)
+	// the runtime configures the plugin once it is registered
+	p.connected.Store(true)
 	if config == "" {
 		return p.mask, nil
 	}
@@ -261,6 +293,9 @@ func (p *NriServer) RemovePodSandbox(_ context.Context, pod *api.PodSandbox) err
 }
 
 func (p *NriServer) onClose() {
+	p.connected.Store(false)
+	p.connecting.Inc()
+	defer p.connecting.Dec()
 	//TODO: consider the pod status during restart
 	retryFunc := func() (bool, error) {
 		if p.stopped.Load() { // if set to stopped, no longer reconnect
@@ -25,6 +25,8 @@ import (
 
 	"github.com/containerd/nri/pkg/api"
 	"github.com/containerd/nri/pkg/stub"
+	"go.uber.org/atomic"
+	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/runtimehooks/hooks"
@@ -115,10 +117,56 @@ func TestNriServer_Start(t *testing.T) {
 			if err := s.Start(); (err != nil) != tt.wantErr {
 				t.Errorf("Start() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if s.IsConnecting() {
+				t.Errorf("IsConnecting() = true after Start() returned")
+			}
 		})
 	}
 }
 
+func TestNriServer_StartAsync(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	s := &NriServer{
+		options: Options{
+			NriSocketPath:     "nri/nri.sock",
+			NriConnectTimeout: time.Second,
+		},
+	}
+	errCh := make(chan error)
+	s.StartAsync(func(err error) {
+		errCh <- err
+	})
+	if !s.IsConnecting() {
+		t.Errorf("IsConnecting() = false before the start completes")
+	}
+	if err := <-errCh; err == nil {
+		t.Errorf("StartAsync() error = nil, want the nri socket not found")
+	}
+	if err := wait.PollImmediate(time.Millisecond, time.Second, func() (bool, error) {
+		return !s.IsConnecting(), nil
+	}); err != nil {
+		t.Errorf("IsConnecting() = true after the start completes")
+	}
+}
+
+func TestNriServer_onClose(t *testing.T) {
+	s := &NriServer{
+		stopped: atomic.NewBool(true),
+		options: Options{
+			BackOff: wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 1},
+		},
+	}
+	s.connected.Store(true)
+	s.onClose()
+	if s.IsConnected() {
+		t.Errorf("IsConnected() = true after the stub is closed")
+	}
+	if s.IsConnecting() {
+		t.Errorf("IsConnecting() = true after the reconnecting retry is done")
+	}
+}
+
 func TestNewNriServer(t *testing.T) {
 	type fields struct {
 		isNriSocketExist bool
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -63,6 +64,8 @@ type ContainerRequest struct {
 	ContainerEnvs     map[string]string
 	Resources         *Resources // TODO: support proxy & nri mode
 	ExtendedResources *apiext.ExtendedResourceContainerSpec
+	// RuntimeHandler is the runtime handler of the pod sandbox, e.g. kata.
+	RuntimeHandler string
 }
 
 func splitEnvVar(s string) (string, string) {
@@ -81,6 +84,7 @@ func (c *ContainerRequest) FromNri(pod *api.PodSandbox, container *api.Container
 	c.ContainerMeta.FromNri(container, pod.GetAnnotations())
 	c.PodLabels = pod.GetLabels()
 	c.PodAnnotations = pod.GetAnnotations()
+	c.RuntimeHandler = pod.GetRuntimeHandler()
 	c.CgroupParent, _ = koordletutil.GetContainerCgroupParentDirByID(pod.Linux.CgroupParent, c.ContainerMeta.ID)
 
 	envs := make(map[string]string)
@@ -97,10 +101,8 @@ func (c *ContainerRequest) FromNri(pod *api.PodSandbox, container *api.Container
 		klog.V(4).Infof("failed to get ExtendedResourceSpec from nri via annotation, container %s/%s, name: %s, err: %s",
 			c.PodMeta.Namespace, c.PodMeta.Name, c.ContainerMeta.Name, err)
 	}
-	if spec != nil && spec.Containers != nil {
-		if containerSpec, ok := spec.Containers[c.ContainerMeta.Name]; ok {
-			c.ExtendedResources = &containerSpec
-		}
+	if containerSpec, ok := spec.GetContainerSpec(c.ContainerMeta.Name); ok {
+		c.ExtendedResources = &containerSpec
 	}
 }
 
@@ -111,16 +113,15 @@ func (c *ContainerRequest) FromProxy(req *runtimeapi.ContainerResourceHookReques
 	c.PodAnnotations = req.GetPodAnnotations()
 	c.CgroupParent, _ = koordletutil.GetContainerCgroupParentDirByID(req.GetPodCgroupParent(), c.ContainerMeta.ID)
 	c.ContainerEnvs = req.GetContainerEnvs()
+	c.RuntimeHandler = req.GetRuntimeHandler()
 	// retrieve ExtendedResources from pod annotations
 	spec, err := apiext.GetExtendedResourceSpec(req.GetPodAnnotations())
 	if err != nil {
 		klog.V(4).Infof("failed to get ExtendedResourceSpec from proxy via annotation, container %s/%s, name: %s, err: %s",
 			c.PodMeta.Namespace, c.PodMeta.Name, c.ContainerMeta.Name, err)
 	}
-	if spec != nil && spec.Containers != nil {
-		if containerSpec, ok := spec.Containers[c.ContainerMeta.Name]; ok {
-			c.ExtendedResources = &containerSpec
-		}
+	if containerSpec, ok := spec.GetContainerSpec(c.ContainerMeta.Name); ok {
+		c.ExtendedResources = &containerSpec
 	}
 }
 
@@ -199,10 +200,8 @@ func (c *ContainerRequest) FromReconciler(podMeta *statesinformer.PodMeta, conta
 	}
 	if specFromContainer != nil {
 		c.ExtendedResources = specFromContainer
-	} else if specFromAnnotations != nil && specFromAnnotations.Containers != nil { // specFromContainer == nil
-		if containerSpec, ok := specFromAnnotations.Containers[c.ContainerMeta.Name]; ok {
-			c.ExtendedResources = &containerSpec
-		}
+	} else if containerSpec, ok := specFromAnnotations.GetContainerSpec(c.ContainerMeta.Name); ok { // specFromContainer == nil
+		c.ExtendedResources = &containerSpec
 	}
 }
 
@@ -211,6 +210,9 @@ type ContainerResponse struct {
 	AddContainerEnvs    map[string]string
 	AddContainerMounts  []*Mount
 	AddContainerDevices []*LinuxDevice
+	// AddContainerAnnotations are the annotations added into the container spec, e.g. the hints passed into the guest
+	// of the sandboxed runtimes.
+	AddContainerAnnotations map[string]string
 }
 
 type LinuxDevice struct {
@@ -229,6 +231,9 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 	if c.Resources.CPUSet != nil {
 		resp.ContainerResources.CpusetCpus = *c.Resources.CPUSet
 	}
+	if c.Resources.CPUSetMems != nil {
+		resp.ContainerResources.CpusetMems = *c.Resources.CPUSetMems
+	}
 	if c.Resources.CFSQuota != nil {
 		resp.ContainerResources.CpuQuota = *c.Resources.CFSQuota
 	}
@@ -246,6 +251,14 @@ func (c *ContainerResponse) ProxyDone(resp *runtimeapi.ContainerResourceHookResp
 			resp.ContainerEnvs[k] = v
 		}
 	}
+	if c.AddContainerAnnotations != nil {
+		if resp.ContainerAnnotations == nil {
+			resp.ContainerAnnotations = make(map[string]string)
+		}
+		for k, v := range c.AddContainerAnnotations {
+			resp.ContainerAnnotations[k] = v
+		}
+	}
 }
 
 type ContainerContext struct {
@@ -253,6 +266,8 @@ type ContainerContext struct {
 	Response ContainerResponse
 	executor resourceexecutor.ResourceUpdateExecutor
 	updaters []resourceexecutor.ResourceUpdater
+	// cpusetUpdaters are the updaters of `cpuset.cpus` and `cpuset.mems` which are written in a transaction
+	cpusetUpdaters []resourceexecutor.ResourceUpdater
 }
 
 func (c *ContainerContext) RecordEvent(r record.EventRecorder, pod *corev1.Pod) {
@@ -289,6 +304,11 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		update.SetLinuxCPUSetCPUs(*c.Response.Resources.CPUSet)
 	}
 
+	if c.Response.Resources.CPUSetMems != nil {
+		adjust.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+		update.SetLinuxCPUSetMems(*c.Response.Resources.CPUSetMems)
+	}
+
 	if c.Response.Resources.CFSQuota != nil {
 		adjust.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
 		update.SetLinuxCPUQuota(*c.Response.Resources.CFSQuota)
@@ -315,6 +335,10 @@ func (c *ContainerContext) NriDone(executor resourceexecutor.ResourceUpdateExecu
 		}
 	}
 
+	for k, v := range c.Response.AddContainerAnnotations {
+		adjust.AddAnnotation(k, v)
+	}
+
 	for _, m := range c.Response.AddContainerMounts {
 		adjust.AddMount(&api.Mount{
 			Destination: m.Destination,
@@ -364,18 +388,38 @@ func (c *ContainerContext) ReconcilerProcess(executor resourceexecutor.ResourceU
 
 func (c *ContainerContext) ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor) {
 	c.ReconcilerProcess(executor)
-	c.Update()
+	c.updateCPUSet()
+	c.executor.UpdateBatchAsync(context.TODO(), true, c.updaters...)
+	c.updaters = nil
 }
 
+// GetUpdaters returns the updaters including the cpuset ones, which are updated by the caller.
 func (c *ContainerContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
-	return c.updaters
+	if len(c.cpusetUpdaters) <= 0 {
+		return c.updaters
+	}
+	return append(append([]resourceexecutor.ResourceUpdater{}, c.cpusetUpdaters...), c.updaters...)
 }
 
 func (c *ContainerContext) Update() {
-	c.executor.UpdateBatch(true, c.updaters...)
+	c.updateCPUSet()
+	c.executor.UpdateBatch(context.TODO(), true, c.updaters...)
 	c.updaters = nil
 }
 
+// updateCPUSet updates the `cpuset.cpus` and `cpuset.mems` in a transaction, so the container is not left with the
+// cpus and the memory nodes of the different allocations when one of them fails.
+func (c *ContainerContext) updateCPUSet() {
+	if len(c.cpusetUpdaters) <= 0 {
+		return
+	}
+	if err := c.executor.UpdateTransaction(context.TODO(), true, c.cpusetUpdaters...); err != nil {
+		klog.Warningf("failed to update container %v/%v/%v cpuset in transaction, error %v", c.Request.PodMeta.Namespace,
+			c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, err)
+	}
+	c.cpusetUpdaters = nil
+}
+
 // Inject valid parameters in ContainerContext.Response.Resources,
 // such as CPUShares, CPUSet, CFSQuota, MemoryLimit...
 func (c *ContainerContext) injectForOrigin() {
@@ -401,6 +445,19 @@ func (c *ContainerContext) injectForOrigin() {
 		if err != nil {
 			klog.Infof("set container %v/%v/%v cpuset %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
 				c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSet, c.Request.CgroupParent, err)
+		} else if c.Response.Resources.CPUSetMems != nil && *c.Response.Resources.CPUSetMems != "" {
+			// If CPUSetMems is not nil and is not an empty string, set container cpuset.mems with the cpuset.cpus
+			memsEventHelper := audit.V(3).Container(c.Request.ContainerMeta.ID).Reason("runtime-hooks").Message("set container cpuset mems to %v", *c.Response.Resources.CPUSetMems)
+			memsUpdater, err := injectCPUSetMems(c.Request.CgroupParent, *c.Response.Resources.CPUSetMems, memsEventHelper, c.executor)
+			if err != nil {
+				klog.Infof("set container %v/%v/%v cpuset mems %v on cgroup parent %v failed, error %v", c.Request.PodMeta.Namespace,
+					c.Request.PodMeta.Name, c.Request.ContainerMeta.Name, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent, err)
+			} else {
+				c.cpusetUpdaters = []resourceexecutor.ResourceUpdater{updater, memsUpdater}
+				klog.V(5).Infof("set container %v/%v/%v cpuset %v and mems %v on cgroup parent %v",
+					c.Request.PodMeta.Namespace, c.Request.PodMeta.Name, c.Request.ContainerMeta.Name,
+					*c.Response.Resources.CPUSet, *c.Response.Resources.CPUSetMems, c.Request.CgroupParent)
+			}
 		} else {
 			c.updaters = append(c.updaters, updater)
 			klog.V(5).Infof("set container %v/%v/%v cpuset %v on cgroup parent %v",
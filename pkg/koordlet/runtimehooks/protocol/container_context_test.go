@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/containerd/nri/pkg/api"
+	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
@@ -136,6 +137,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 						CPUShares:   pointer.Int64(1024 * 500 / 1000),
 						CFSQuota:    pointer.Int64(1024 * 500 / 1000),
 						CPUSet:      pointer.String("0,1,2"),
+						CPUSetMems:  pointer.String("0"),
 						MemoryLimit: pointer.Int64(2 * 1024 * 1024 * 1024),
 					},
 					AddContainerEnvs: map[string]string{"test": "test"},
@@ -158,6 +160,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
@@ -178,6 +181,7 @@ func TestContainerContext_NriDone(t *testing.T) {
 								Value: 512,
 							},
 							Cpus: "0,1,2",
+							Mems: "0",
 						},
 					},
 				},
@@ -211,6 +215,62 @@ func TestContainerContext_NriDone(t *testing.T) {
 	}
 }
 
+func TestContainerContext_UpdateCPUSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		cpuset   string
+		mems     string
+		wantCPUs string
+		wantMems string
+	}{
+		{
+			name:     "update cpuset and mems in transaction",
+			cpuset:   "2-4",
+			mems:     "1",
+			wantCPUs: "2-4",
+			wantMems: "1",
+		},
+		{
+			name:     "rollback cpuset when failed to update mems",
+			cpuset:   "2-4",
+			mems:     "invalid content",
+			wantCPUs: "0-7",
+			wantMems: "0-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			cgroupParent := "kubepods/test-pod/test-container/"
+			helper.WriteCgroupFileContents(cgroupParent, system.CPUSet, "0-7")
+			helper.WriteCgroupFileContents(cgroupParent, system.CPUSetMems, "0-1")
+
+			executor := resourceexecutor.NewResourceUpdateExecutor()
+			stop := make(chan struct{})
+			defer close(stop)
+			executor.Run(stop)
+
+			c := &ContainerContext{
+				Request: ContainerRequest{
+					CgroupParent: cgroupParent,
+				},
+				Response: ContainerResponse{
+					Resources: Resources{
+						CPUSet:     pointer.String(tt.cpuset),
+						CPUSetMems: pointer.String(tt.mems),
+					},
+				},
+			}
+			c.ReconcilerProcess(executor)
+			assert.Equal(t, 2, len(c.GetUpdaters()))
+			c.Update()
+			assert.Equal(t, tt.wantCPUs, helper.ReadCgroupFileContents(cgroupParent, system.CPUSet))
+			assert.Equal(t, tt.wantMems, helper.ReadCgroupFileContents(cgroupParent, system.CPUSetMems))
+		})
+	}
+}
+
 func Test_getContainerID(t *testing.T) {
 	type args struct {
 		podAnnotations           map[string]string
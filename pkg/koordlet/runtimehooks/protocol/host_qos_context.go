@@ -17,6 +17,8 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -69,7 +71,8 @@ func (c *HostAppContext) ReconcilerProcess(executor resourceexecutor.ResourceUpd
 
 func (c *HostAppContext) ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor) {
 	c.ReconcilerProcess(executor)
-	c.Update()
+	c.executor.UpdateBatchAsync(context.TODO(), true, c.updaters...)
+	c.updaters = nil
 }
 
 func (c *HostAppContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
@@ -78,7 +81,7 @@ func (c *HostAppContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
 
 func (c *HostAppContext) Update() {
 	klog.V(5).Infof("")
-	c.executor.UpdateBatch(true, c.updaters...)
+	c.executor.UpdateBatch(context.TODO(), true, c.updaters...)
 	c.updaters = nil
 }
 
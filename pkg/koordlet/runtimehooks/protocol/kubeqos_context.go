@@ -17,6 +17,8 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
@@ -66,7 +68,8 @@ func (k *KubeQOSContext) ReconcilerProcess(executor resourceexecutor.ResourceUpd
 
 func (k *KubeQOSContext) ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor) {
 	k.ReconcilerProcess(executor)
-	k.Update()
+	k.executor.UpdateBatchAsync(context.TODO(), true, k.updaters...)
+	k.updaters = nil
 }
 
 func (k *KubeQOSContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
@@ -74,7 +77,7 @@ func (k *KubeQOSContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
 }
 
 func (k *KubeQOSContext) Update() {
-	k.executor.UpdateBatch(true, k.updaters...)
+	k.executor.UpdateBatch(context.TODO(), true, k.updaters...)
 	k.updaters = nil
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package protocol
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -86,7 +87,7 @@ func (p *PodRequest) FromNri(pod *api.PodSandbox) {
 		klog.V(4).Infof("failed to get ExtendedResourceSpec from nri via annotation, pod %s/%s, err: %s",
 			p.PodMeta.Namespace, p.PodMeta.Name, err)
 	}
-	if spec != nil && spec.Containers != nil {
+	if !spec.IsEmpty() {
 		p.ExtendedResources = spec
 	}
 }
@@ -102,7 +103,7 @@ func (p *PodRequest) FromProxy(req *runtimeapi.PodSandboxHookRequest) {
 		klog.V(4).Infof("failed to get ExtendedResourceSpec from proxy via annotation, pod %s/%s, err: %s",
 			p.PodMeta.Namespace, p.PodMeta.Name, err)
 	}
-	if spec != nil && spec.Containers != nil {
+	if !spec.IsEmpty() {
 		p.ExtendedResources = spec
 	}
 }
@@ -124,7 +125,7 @@ func (p *PodRequest) FromReconciler(podMeta *statesinformer.PodMeta) {
 	specFromPod := util.GetPodExtendedResources(podMeta.Pod)
 	if specFromPod != nil {
 		p.ExtendedResources = specFromPod
-	} else if specFromAnnotations != nil && specFromAnnotations.Containers != nil { // specFromPod == nil
+	} else if !specFromAnnotations.IsEmpty() { // specFromPod == nil
 		p.ExtendedResources = specFromAnnotations
 	}
 }
@@ -244,7 +245,8 @@ func (p *PodContext) ReconcilerProcess(executor resourceexecutor.ResourceUpdateE
 
 func (p *PodContext) ReconcilerDone(executor resourceexecutor.ResourceUpdateExecutor) {
 	p.ReconcilerProcess(executor)
-	p.Update()
+	p.executor.UpdateBatchAsync(context.TODO(), true, p.updaters...)
+	p.updaters = nil
 }
 
 func (p *PodContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
@@ -252,7 +254,7 @@ func (p *PodContext) GetUpdaters() []resourceexecutor.ResourceUpdater {
 }
 
 func (p *PodContext) Update() {
-	p.executor.UpdateBatch(true, p.updaters...)
+	p.executor.UpdateBatch(context.TODO(), true, p.updaters...)
 	p.updaters = nil
 }
 
@@ -287,6 +289,71 @@ func (p *PodContext) injectForExt() {
 				p.Request.PodMeta.Name, *p.Response.Resources.CPUIdle, p.Request.CgroupParent)
 		}
 	}
+	if p.Response.Resources.MemoryNumaBalancing != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod numa balancing to %v", *p.Response.Resources.MemoryNumaBalancing)
+		updater, err := injectMemoryNumaBalancing(p.Request.CgroupParent, *p.Response.Resources.MemoryNumaBalancing, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v numa balancing %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryNumaBalancing, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v numa balancing %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryNumaBalancing, p.Request.CgroupParent)
+		}
+	}
+	if p.Response.Resources.MemoryZswapMax != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod zswap max to %v", *p.Response.Resources.MemoryZswapMax)
+		updater, err := injectMemoryZswapMax(p.Request.CgroupParent, *p.Response.Resources.MemoryZswapMax, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v zswap max %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryZswapMax, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v zswap max %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryZswapMax, p.Request.CgroupParent)
+		}
+	}
+	if p.Response.Resources.MemoryZswapWriteback != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod zswap writeback to %v", *p.Response.Resources.MemoryZswapWriteback)
+		updater, err := injectMemoryZswapWriteback(p.Request.CgroupParent, *p.Response.Resources.MemoryZswapWriteback, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v zswap writeback %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryZswapWriteback, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v zswap writeback %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.MemoryZswapWriteback, p.Request.CgroupParent)
+		}
+	}
+	if p.Response.Resources.HugetlbLimit2M != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod hugetlb 2M limit to %v", *p.Response.Resources.HugetlbLimit2M)
+		updater, err := injectHugetlbLimit2M(p.Request.CgroupParent, *p.Response.Resources.HugetlbLimit2M, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v hugetlb 2M limit %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.HugetlbLimit2M, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v hugetlb 2M limit %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.HugetlbLimit2M, p.Request.CgroupParent)
+		}
+	}
+	if p.Response.Resources.HugetlbLimit1G != nil {
+		eventHelper := audit.V(3).Pod(p.Request.PodMeta.Namespace, p.Request.PodMeta.Name).Reason("runtime-hooks").Message(
+			"set pod hugetlb 1G limit to %v", *p.Response.Resources.HugetlbLimit1G)
+		updater, err := injectHugetlbLimit1G(p.Request.CgroupParent, *p.Response.Resources.HugetlbLimit1G, eventHelper, p.executor)
+		if err != nil {
+			klog.Infof("set pod %v/%v hugetlb 1G limit %v on cgroup parent %v failed, error %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.HugetlbLimit1G, p.Request.CgroupParent, err)
+		} else {
+			p.updaters = append(p.updaters, updater)
+			klog.V(5).Infof("set pod %v/%v hugetlb 1G limit %v on cgroup parent %v", p.Request.PodMeta.Namespace,
+				p.Request.PodMeta.Name, *p.Response.Resources.HugetlbLimit1G, p.Request.CgroupParent)
+		}
+	}
 
 	// some of pod-level cgroups are manually updated since pod-stage hooks do not support it;
 	// kubelet may set the cgroups when pod is created or restarted, so we need to update the cgroups repeatedly
@@ -37,6 +37,17 @@ type HooksProtocol interface {
 	RecordEvent(r record.EventRecorder, pod *corev1.Pod)
 }
 
+// IsCgroupExcluded checks if the pod of the hooks protocol is excluded from the cgroup reconciliation.
+func IsCgroupExcluded(p HooksProtocol) bool {
+	switch c := p.(type) {
+	case *PodContext:
+		return resourceexecutor.IsPodCgroupExcluded(c.Request.PodMeta.Namespace, c.Request.Labels, c.Request.CgroupParent)
+	case *ContainerContext:
+		return resourceexecutor.IsPodCgroupExcluded(c.Request.PodMeta.Namespace, c.Request.PodLabels, c.Request.CgroupParent)
+	}
+	return false
+}
+
 type hooksProtocolBuilder struct {
 	KubeQOS   func(kubeQOS corev1.PodQOSClass) HooksProtocol
 	Pod       func(podMeta *statesinformer.PodMeta) HooksProtocol
@@ -84,17 +95,23 @@ type Resources struct {
 	CPUShares     *int64
 	CFSQuota      *int64
 	CPUSet        *string
+	CPUSetMems    *string
 	MemoryLimit   *int64
 	NetClsClassId *uint32
 
 	// extended resources
-	CPUBvt  *int64
-	CPUIdle *int64
-	Resctrl *Resctrl
+	CPUBvt               *int64
+	CPUIdle              *int64
+	Resctrl              *Resctrl
+	MemoryNumaBalancing  *int64
+	MemoryZswapMax       *int64
+	MemoryZswapWriteback *int64
+	HugetlbLimit2M       *int64
+	HugetlbLimit1G       *int64
 }
 
 func (r *Resources) IsOriginResSet() bool {
-	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.MemoryLimit != nil
+	return r.CPUShares != nil || r.CFSQuota != nil || r.CPUSet != nil || r.CPUSetMems != nil || r.MemoryLimit != nil
 }
 
 func (r *Resources) FromPod(pod *corev1.Pod) {
@@ -159,6 +176,14 @@ func injectCPUSet(cgroupParent string, cpuset string, a *audit.EventHelper, e re
 	return updater, nil
 }
 
+func injectCPUSetMems(cgroupParent string, mems string, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUSetMemsName, cgroupParent, mems, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectCPUQuota(cgroupParent string, cpuQuota int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	cpuQuotaStr := strconv.FormatInt(cpuQuota, 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.CPUCFSQuotaName, cgroupParent, cpuQuotaStr, a)
@@ -195,6 +220,51 @@ func injectCPUIdle(cgroupParent string, idleValue int64, a *audit.EventHelper, e
 	return updater, nil
 }
 
+func injectMemoryNumaBalancing(cgroupParent string, numaBalancing int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	numaBalancingStr := strconv.FormatInt(numaBalancing, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.MemoryNumaBalancingName, cgroupParent, numaBalancingStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
+func injectMemoryZswapMax(cgroupParent string, zswapMax int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	zswapMaxStr := strconv.FormatInt(zswapMax, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.MemoryZswapMaxName, cgroupParent, zswapMaxStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
+func injectMemoryZswapWriteback(cgroupParent string, zswapWriteback int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	zswapWritebackStr := strconv.FormatInt(zswapWriteback, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.MemoryZswapWritebackName, cgroupParent, zswapWritebackStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
+func injectHugetlbLimit2M(cgroupParent string, limit int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	limitStr := strconv.FormatInt(limit, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.HugetlbLimit2MName, cgroupParent, limitStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
+func injectHugetlbLimit1G(cgroupParent string, limit int64, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
+	limitStr := strconv.FormatInt(limit, 10)
+	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.HugetlbLimit1GName, cgroupParent, limitStr, a)
+	if err != nil {
+		return nil, err
+	}
+	return updater, nil
+}
+
 func injectNetClsClassId(cgroupParent string, classId uint32, a *audit.EventHelper, e resourceexecutor.ResourceUpdateExecutor) (resourceexecutor.ResourceUpdater, error) {
 	clsIdStr := strconv.FormatUint(uint64(classId), 10)
 	updater, err := resourceexecutor.DefaultCgroupUpdaterFactory.New(sysutil.NetClsClassIdName, cgroupParent, clsIdStr, a)
@@ -17,12 +17,15 @@ limitations under the License.
 package proxyserver
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"go.uber.org/atomic"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 	"k8s.io/client-go/tools/record"
@@ -53,12 +56,16 @@ type Server interface {
 	Start() error
 	Stop()
 	Register() error
+	// LastServedTime returns the time when the last hook request was served, which is zero if none has been served.
+	// A recently served request means the runtime proxy is working.
+	LastServedTime() time.Time
 }
 
 type server struct {
-	listener net.Listener // socket our gRPC server listens on
-	server   *grpc.Server // our gRPC server
-	options  Options      // server options
+	listener   net.Listener // socket our gRPC server listens on
+	server     *grpc.Server // our gRPC server
+	options    Options      // server options
+	lastServed atomic.Int64 // the unix nanoseconds when the last hook request was served
 	runtimeapi.UnimplementedRuntimeHookServiceServer
 }
 
@@ -96,6 +103,19 @@ func (s *server) Register() error {
 	return nil
 }
 
+func (s *server) LastServedTime() time.Time {
+	lastServed := s.lastServed.Load()
+	if lastServed <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, lastServed)
+}
+
+func (s *server) markServed(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.lastServed.Store(time.Now().UnixNano())
+	return handler(ctx, req)
+}
+
 func (s *server) createRPCServer() error {
 	if s.server != nil {
 		return nil
@@ -110,7 +130,7 @@ func (s *server) createRPCServer() error {
 		return fmt.Errorf("failed to create runtime hook server, error: %w", err)
 	}
 	s.listener = l
-	s.server = grpc.NewServer()
+	s.server = grpc.NewServer(grpc.UnaryInterceptor(s.markServed))
 	reflection.Register(s.server)
 	return nil
 }
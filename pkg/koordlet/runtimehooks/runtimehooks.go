@@ -18,7 +18,10 @@ package runtimehooks
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
+	"go.uber.org/atomic"
 	corev1 "k8s.io/api/core/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -44,6 +47,8 @@ type HookPlugin interface {
 
 type RuntimeHook interface {
 	Run(stopCh <-chan struct{}) error
+	// HasSynced returns whether the runtime hook server has been registered.
+	HasSynced() bool
 }
 
 type runtimeHook struct {
@@ -54,6 +59,18 @@ type runtimeHook struct {
 	hostAppReconciler reconciler.Reconciler
 	reader            resourceexecutor.CgroupReader
 	executor          resourceexecutor.ResourceUpdateExecutor
+	registered        *atomic.Bool
+
+	kubeClient clientset.Interface
+	nodeName   string
+	// nriLock protects the nriServer which can be created after the failover
+	nriLock sync.RWMutex
+	// nriOptions is nil if the nri mode is disabled
+	nriOptions       *nri.Options
+	modeSyncInterval time.Duration
+	// proxyServedExpiration is how long the proxy mode is available after the last served hook request
+	proxyServedExpiration time.Duration
+	reportedModes         string
 }
 
 func (r *runtimeHook) Run(stopCh <-chan struct{}) error {
@@ -62,15 +79,15 @@ func (r *runtimeHook) Run(stopCh <-chan struct{}) error {
 	if err := r.server.Start(); err != nil {
 		return err
 	}
-	if r.nriServer != nil {
-		go func() {
-			if err := r.nriServer.Start(); err != nil {
+	if nriServer := r.getNRIServer(); nriServer != nil {
+		nriServer.StartAsync(func(err error) {
+			if err != nil {
 				// if NRI is not enabled or container runtime not support NRI, we just skip NRI server start
 				klog.Warningf("nri mode runtime hook server start failed: %v", err)
 			} else {
 				klog.V(4).Infof("nri mode runtime hook server has started")
 			}
-		}()
+		})
 	}
 	if err := r.reconciler.Run(stopCh); err != nil {
 		return err
@@ -81,12 +98,20 @@ func (r *runtimeHook) Run(stopCh <-chan struct{}) error {
 	if err := r.server.Register(); err != nil {
 		return err
 	}
+	r.registered.Store(true)
+	if r.modeSyncInterval > 0 {
+		go wait.Until(r.syncHookModes, r.modeSyncInterval, stopCh)
+	}
 	klog.V(5).Infof("runtime hook server has started")
 	<-stopCh
 	klog.Infof("runtime hook is stopped")
 	return nil
 }
 
+func (r *runtimeHook) HasSynced() bool {
+	return r.registered.Load()
+}
+
 func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiruntime.Scheme, kubeClient clientset.Interface, nodeName string) (RuntimeHook, error) {
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartRecordingToSink(&clientcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
@@ -121,8 +146,9 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiru
 		Cap:      cfg.RuntimeHooksNRIBackOffCap,
 	}
 	var nriServer *nri.NriServer
+	var nriServerOptions *nri.Options
 	if cfg.RuntimeHooksNRI {
-		nriServerOptions := nri.Options{
+		nriServerOptions = &nri.Options{
 			NriPluginName:       cfg.RuntimeHooksNRIPluginName,
 			NriPluginIdx:        cfg.RuntimeHooksNRIPluginIndex,
 			NriSocketPath:       cfg.RuntimeHooksNRISocketPath,
@@ -133,9 +159,11 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiru
 			BackOff:             backOff,
 			EventRecorder:       recorder,
 		}
-		nriServer, err = nri.NewNriServer(nriServerOptions)
+		nriServer, err = nri.NewNriServer(*nriServerOptions)
 		if err != nil {
+			// fail over to the proxy and standalone modes, and retry in the mode sync
 			klog.Warningf("new nri mode runtimehooks server error: %v", err)
+			nriServer = nil
 		}
 	} else {
 		klog.V(4).Info("nri mode runtimehooks is disabled")
@@ -150,23 +178,30 @@ func NewRuntimeHook(si statesinformer.StatesInformer, cfg *Config, schema *apiru
 	}
 
 	newPluginOptions := hooks.Options{
-		Reader:         cr,
-		Executor:       e,
-		StatesInformer: si,
-		EventRecorder:  recorder,
+		Reader:              cr,
+		Executor:            e,
+		StatesInformer:      si,
+		EventRecorder:       recorder,
+		KoordSchedulerNames: cfg.RuntimeHookKoordSchedulerNames,
 	}
 
 	if err != nil {
 		return nil, err
 	}
 	r := &runtimeHook{
-		statesInformer:    si,
-		server:            s,
-		nriServer:         nriServer,
-		reconciler:        reconciler.NewReconciler(newReconcilerCtx),
-		hostAppReconciler: reconciler.NewHostAppReconciler(newReconcilerCtx),
-		reader:            cr,
-		executor:          e,
+		statesInformer:        si,
+		server:                s,
+		nriServer:             nriServer,
+		reconciler:            reconciler.NewReconciler(newReconcilerCtx),
+		hostAppReconciler:     reconciler.NewHostAppReconciler(newReconcilerCtx),
+		reader:                cr,
+		executor:              e,
+		registered:            atomic.NewBool(false),
+		kubeClient:            kubeClient,
+		nodeName:              nodeName,
+		nriOptions:            nriServerOptions,
+		modeSyncInterval:      cfg.RuntimeHooksModeSyncInterval,
+		proxyServedExpiration: cfg.RuntimeHooksProxyServedExpiration,
 	}
 	registerPlugins(newPluginOptions)
 	si.RegisterCallbacks(statesinformer.RegisterTypeNodeSLOSpec, "runtime-hooks-rule-node-slo",
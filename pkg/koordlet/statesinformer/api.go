@@ -18,6 +18,8 @@ package statesinformer
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 
 	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -85,6 +87,44 @@ const (
 	RegisterTypeNodeMetadata
 )
 
+// registerTypeExtensionBase is the first RegisterType allocated for the extension states.
+const registerTypeExtensionBase RegisterType = 1000
+
+var (
+	extensionTypesLock sync.RWMutex
+	extensionTypes     = map[RegisterType]string{}
+)
+
+// RegisterExtensionType allocates a RegisterType for the states provided by an extension informer plugin, so that
+// other modules can subscribe to the states with RegisterCallbacks. It should be called before the states informer
+// is created, e.g. in the init function of the out-of-tree plugin.
+func RegisterExtensionType(name string) RegisterType {
+	extensionTypesLock.Lock()
+	defer extensionTypesLock.Unlock()
+	for t, n := range extensionTypes {
+		if n == name {
+			return t
+		}
+	}
+	t := registerTypeExtensionBase + RegisterType(len(extensionTypes))
+	extensionTypes[t] = name
+	return t
+}
+
+// ExtensionRegisterTypes returns the registered extension types in order.
+func ExtensionRegisterTypes() []RegisterType {
+	extensionTypesLock.RLock()
+	defer extensionTypesLock.RUnlock()
+	types := make([]RegisterType, 0, len(extensionTypes))
+	for t := range extensionTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
 func (r RegisterType) String() string {
 	switch r {
 	case RegisterTypeNodeSLOSpec:
@@ -96,6 +136,11 @@ func (r RegisterType) String() string {
 	case RegisterTypeNodeMetadata:
 		return "RegisterNodeMetadata"
 	default:
+		extensionTypesLock.RLock()
+		defer extensionTypesLock.RUnlock()
+		if name, ok := extensionTypes[r]; ok {
+			return name
+		}
 		return "RegisterTypeUnknown"
 	}
 }
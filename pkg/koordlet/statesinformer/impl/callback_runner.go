@@ -19,6 +19,8 @@ package impl
 import (
 	"k8s.io/klog/v2"
 
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 )
 
@@ -34,6 +36,8 @@ type callbackRunner struct {
 	callbackChans        map[statesinformer.RegisterType]chan UpdateCbCtx
 	stateUpdateCallbacks map[statesinformer.RegisterType][]updateCallback
 	statesInformer       statesinformer.StatesInformer
+	// stateGetters returns the objects of the extension types for the callbacks
+	stateGetters map[statesinformer.RegisterType]func() interface{}
 }
 
 func NewCallbackRunner() *callbackRunner {
@@ -50,6 +54,11 @@ func NewCallbackRunner() *callbackRunner {
 		statesinformer.RegisterTypeNodeTopology: {},
 		statesinformer.RegisterTypeNodeMetadata: {},
 	}
+	c.stateGetters = map[statesinformer.RegisterType]func() interface{}{}
+	for _, t := range statesinformer.ExtensionRegisterTypes() {
+		c.callbackChans[t] = make(chan UpdateCbCtx, 1)
+		c.stateUpdateCallbacks[t] = []updateCallback{}
+	}
 	return c
 }
 
@@ -76,6 +85,14 @@ func (s *callbackRunner) RegisterCallbacks(rType statesinformer.RegisterType, na
 	klog.V(1).Infof("states informer callback %s has registered for type %v", name, rType.String())
 }
 
+// RegisterStateGetter sets the function which returns the object of the extension type for the callbacks.
+func (s *callbackRunner) RegisterStateGetter(rType statesinformer.RegisterType, getter func() interface{}) {
+	if _, legal := s.callbackChans[rType]; !legal {
+		klog.Fatalf("states informer state getter register with type %v is illegal", rType.String())
+	}
+	s.stateGetters[rType] = getter
+}
+
 func (s *callbackRunner) SendCallback(objType statesinformer.RegisterType) {
 	if _, exist := s.callbackChans[objType]; exist {
 		select {
@@ -98,9 +115,14 @@ func (s *callbackRunner) runCallbacks(objType statesinformer.RegisterType, obj i
 	callbackTarget := &statesinformer.CallbackTarget{
 		Pods: s.statesInformer.GetAllPods(),
 	}
-	if nodeSLO := s.statesInformer.GetNodeSLO(); nodeSLO != nil {
+	nodeSLO := s.statesInformer.GetNodeSLO()
+	if nodeSLO != nil {
 		callbackTarget.HostApplications = nodeSLO.Spec.HostApplications
 	}
+	if objType == statesinformer.RegisterTypeNodeSLOSpec || objType == statesinformer.RegisterTypeAllPods {
+		// refresh the excluded pods before the callbacks reconcile the cgroups
+		updateCgroupExclusion(nodeSLO, callbackTarget.Pods)
+	}
 	for _, c := range callbacks {
 		klog.V(5).Infof("start running callback function %v for type %v, pod num %v, host app num %v",
 			c.name, objType.String(), len(callbackTarget.Pods), len(callbackTarget.HostApplications))
@@ -145,5 +167,22 @@ func (s *callbackRunner) getObjByType(objType statesinformer.RegisterType, cbCtx
 	case statesinformer.RegisterTypeNodeMetadata:
 		return s.statesInformer.GetNode()
 	}
+	if getter, ok := s.stateGetters[objType]; ok {
+		return getter()
+	}
 	return nil
 }
+
+func updateCgroupExclusion(nodeSLO *slov1alpha1.NodeSLO, podMetas []*statesinformer.PodMeta) {
+	var rules []slov1alpha1.CgroupExclusionRule
+	if nodeSLO != nil {
+		rules = nodeSLO.Spec.CgroupExclusionRules
+	}
+	pods := make([]resourceexecutor.ExclusionPod, 0, len(podMetas))
+	if len(rules) > 0 {
+		for _, podMeta := range podMetas {
+			pods = append(pods, resourceexecutor.ExclusionPod{Pod: podMeta.Pod, CgroupDir: podMeta.CgroupDir})
+		}
+	}
+	resourceexecutor.UpdateCgroupExclusion(rules, pods)
+}
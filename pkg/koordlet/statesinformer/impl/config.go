@@ -21,6 +21,8 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
 )
 
 type Config struct {
@@ -34,6 +36,13 @@ type Config struct {
 	EnableNodeMetricReport      bool
 	MetricReportInterval        time.Duration // Deprecated
 	EnablePodTaskIds            bool
+	NodeMetricReportTransport   string
+	NodeMetricReportServerAddr  string
+	NodeMetricReportServerCA    string
+	NodeMetricReportTokenFile   string
+	PodDiscoveryMode            string
+	// MemoryBandwidthCapacityMBps is the total memory bandwidth of the node reported in the NodeMetric, 0 means unknown
+	MemoryBandwidthCapacityMBps int64
 }
 
 func NewDefaultConfig() *Config {
@@ -47,6 +56,12 @@ func NewDefaultConfig() *Config {
 		DisableQueryKubeletConfig:   false,
 		EnableNodeMetricReport:      true,
 		EnablePodTaskIds:            false,
+		NodeMetricReportTransport:   NodeMetricReportTransportCRD,
+		NodeMetricReportServerAddr:  "",
+		NodeMetricReportServerCA:    "",
+		NodeMetricReportTokenFile:   metricreport.ServiceAccountTokenFile,
+		PodDiscoveryMode:            PodDiscoveryModeKubelet,
+		MemoryBandwidthCapacityMBps: 0,
 	}
 }
 
@@ -60,5 +75,11 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&c.DisableQueryKubeletConfig, "disable-query-kubelet-config", c.DisableQueryKubeletConfig, "Disables querying the kubelet configuration from kubelet. Flag must be set to true if kubelet-insecure-tls=true is configured")
 	fs.DurationVar(&c.MetricReportInterval, "report-interval", c.MetricReportInterval, "Deprecated since v1.1, use ColocationStrategy.MetricReportIntervalSeconds in config map of slo-controller")
 	fs.BoolVar(&c.EnableNodeMetricReport, "enable-node-metric-report", c.EnableNodeMetricReport, "Enable status update of node metric crd.")
+	fs.StringVar(&c.NodeMetricReportTransport, "node-metric-report-transport", c.NodeMetricReportTransport, "The transport to report the node metric status. 'crd' updates the status of node metric crd, 'grpc' streams the reports to the koord-manager which writes the summaries.")
+	fs.StringVar(&c.NodeMetricReportServerAddr, "node-metric-report-server-addr", c.NodeMetricReportServerAddr, "The address of the koord-manager node metric report server, used if node-metric-report-transport=grpc.")
+	fs.StringVar(&c.NodeMetricReportServerCA, "node-metric-report-server-ca-file", c.NodeMetricReportServerCA, "The CA file to verify the certificate of the node metric report server. The system roots are used if not specified.")
+	fs.StringVar(&c.NodeMetricReportTokenFile, "node-metric-report-token-file", c.NodeMetricReportTokenFile, "The ServiceAccount token file to authenticate to the node metric report server.")
+	fs.StringVar(&c.PodDiscoveryMode, "pod-discovery-mode", c.PodDiscoveryMode, "The source to discover the pods on the node. 'kubelet' queries the kubelet, 'cri' lists the pod sandboxes and containers from the CRI runtime for the clusters disabling the kubelet endpoints, 'auto' falls back to the CRI runtime when the kubelet is unavailable. The 'cri' mode requires disable-query-kubelet-config=true.")
+	fs.Int64Var(&c.MemoryBandwidthCapacityMBps, "memory-bandwidth-capacity-mbps", c.MemoryBandwidthCapacityMBps, "The total memory bandwidth of the node in MB/s, which is reported with the resctrl memory bandwidth in the NodeMetric for the scheduler to avoid the bandwidth saturation. 0 means unknown.")
 	fs.BoolVar(&c.EnablePodTaskIds, "enable-pod-taskids", c.EnablePodTaskIds, "Enable pod taskids in statesinformer.")
 }
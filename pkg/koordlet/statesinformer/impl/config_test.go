@@ -43,6 +43,12 @@ func TestNewDefaultConfig(t *testing.T) {
 				EnableNodeMetricReport:      true,
 				MetricReportInterval:        0,
 				EnablePodTaskIds:            false,
+				NodeMetricReportTransport:   "crd",
+				NodeMetricReportServerAddr:  "",
+				NodeMetricReportServerCA:    "",
+				NodeMetricReportTokenFile:   "/var/run/secrets/kubernetes.io/serviceaccount/token",
+				PodDiscoveryMode:            "kubelet",
+				MemoryBandwidthCapacityMBps: 0,
 			},
 		},
 	}
@@ -66,6 +72,12 @@ func TestConfig_InitFlags(t *testing.T) {
 		"--disable-query-kubelet-config=true",
 		"--enable-node-metric-report=false",
 		"--enable-pod-taskids=true",
+		"--node-metric-report-transport=grpc",
+		"--node-metric-report-server-addr=koord-manager.koordinator-system:9316",
+		"--node-metric-report-server-ca-file=/etc/koordlet/ca.crt",
+		"--node-metric-report-token-file=/var/run/secrets/tokens/koordlet",
+		"--pod-discovery-mode=cri",
+		"--memory-bandwidth-capacity-mbps=100000",
 	}
 	fs := flag.NewFlagSet(cmdArgs[0], flag.ExitOnError)
 
@@ -79,6 +91,12 @@ func TestConfig_InitFlags(t *testing.T) {
 		DisableQueryKubeletConfig   bool
 		EnableNodeMetricReport      bool
 		EnablePodTaskIds            bool
+		NodeMetricReportTransport   string
+		NodeMetricReportServerAddr  string
+		NodeMetricReportServerCA    string
+		NodeMetricReportTokenFile   string
+		PodDiscoveryMode            string
+		MemoryBandwidthCapacityMBps int64
 	}
 	type args struct {
 		fs *flag.FlagSet
@@ -100,6 +118,12 @@ func TestConfig_InitFlags(t *testing.T) {
 				DisableQueryKubeletConfig:   true,
 				EnableNodeMetricReport:      false,
 				EnablePodTaskIds:            true,
+				NodeMetricReportTransport:   "grpc",
+				NodeMetricReportServerAddr:  "koord-manager.koordinator-system:9316",
+				NodeMetricReportServerCA:    "/etc/koordlet/ca.crt",
+				NodeMetricReportTokenFile:   "/var/run/secrets/tokens/koordlet",
+				PodDiscoveryMode:            "cri",
+				MemoryBandwidthCapacityMBps: 100000,
 			},
 			args: args{fs: fs},
 		},
@@ -116,6 +140,12 @@ func TestConfig_InitFlags(t *testing.T) {
 				DisableQueryKubeletConfig:   tt.fields.DisableQueryKubeletConfig,
 				EnableNodeMetricReport:      tt.fields.EnableNodeMetricReport,
 				EnablePodTaskIds:            tt.fields.EnablePodTaskIds,
+				NodeMetricReportTransport:   tt.fields.NodeMetricReportTransport,
+				NodeMetricReportServerAddr:  tt.fields.NodeMetricReportServerAddr,
+				NodeMetricReportServerCA:    tt.fields.NodeMetricReportServerCA,
+				NodeMetricReportTokenFile:   tt.fields.NodeMetricReportTokenFile,
+				PodDiscoveryMode:            tt.fields.PodDiscoveryMode,
+				MemoryBandwidthCapacityMBps: tt.fields.MemoryBandwidthCapacityMBps,
 			}
 			c := NewDefaultConfig()
 			c.InitFlags(tt.args.fs)
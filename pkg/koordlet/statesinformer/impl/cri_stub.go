@@ -0,0 +1,311 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	"k8s.io/klog/v2"
+	kubeletconfiginternal "k8s.io/kubernetes/pkg/kubelet/apis/config"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// PodDiscoveryModeKubelet discovers the pods from the kubelet /pods endpoint.
+	PodDiscoveryModeKubelet = "kubelet"
+	// PodDiscoveryModeCRI discovers the pods from the pod sandboxes and containers of the CRI runtime, which is
+	// used when the kubelet endpoints are disabled.
+	PodDiscoveryModeCRI = "cri"
+	// PodDiscoveryModeAuto discovers the pods from the kubelet, and falls back to the CRI runtime when the kubelet
+	// is unavailable.
+	PodDiscoveryModeAuto = "auto"
+)
+
+// labels set by the kubelet on the pod sandbox, which are not the labels of the pod
+const (
+	criLabelPodName      = "io.kubernetes.pod.name"
+	criLabelPodNamespace = "io.kubernetes.pod.namespace"
+	criLabelPodUID       = "io.kubernetes.pod.uid"
+)
+
+var podQOSClassesToProbe = []corev1.PodQOSClass{corev1.PodQOSGuaranteed, corev1.PodQOSBurstable, corev1.PodQOSBestEffort}
+
+// criStub implements the KubeletStub by the CRI runtime service. The pods are rebuilt from the pod sandboxes and
+// containers, so only the fields which can be recovered from the runtime are filled, including the metadata, the
+// container statuses, the container cpu and memory limits and the QoS class probed from the pod cgroups.
+type criStub struct {
+	client      runtimeapi.RuntimeServiceClient
+	timeout     time.Duration
+	runtimeName string
+}
+
+func NewCRIStub(client runtimeapi.RuntimeServiceClient, timeout time.Duration) KubeletStub {
+	return &criStub{
+		client:  client,
+		timeout: timeout,
+	}
+}
+
+func (c *criStub) GetAllPods() (corev1.PodList, error) {
+	podList := corev1.PodList{}
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	if c.runtimeName == "" {
+		versionRsp, err := c.client.Version(ctx, &runtimeapi.VersionRequest{})
+		if err != nil {
+			return podList, fmt.Errorf("get cri runtime version failed, err: %w", err)
+		}
+		c.runtimeName = versionRsp.GetRuntimeName()
+	}
+	sandboxRsp, err := c.client.ListPodSandbox(ctx, &runtimeapi.ListPodSandboxRequest{})
+	if err != nil {
+		return podList, fmt.Errorf("list cri pod sandboxes failed, err: %w", err)
+	}
+	containerRsp, err := c.client.ListContainers(ctx, &runtimeapi.ListContainersRequest{})
+	if err != nil {
+		return podList, fmt.Errorf("list cri containers failed, err: %w", err)
+	}
+
+	containersBySandbox := map[string][]*runtimeapi.Container{}
+	for _, container := range containerRsp.GetContainers() {
+		sandboxID := container.GetPodSandboxId()
+		containersBySandbox[sandboxID] = append(containersBySandbox[sandboxID], container)
+	}
+	for _, sandbox := range latestPodSandboxes(sandboxRsp.GetItems()) {
+		pod := c.buildPod(ctx, sandbox, latestContainers(containersBySandbox[sandbox.GetId()]))
+		podList.Items = append(podList.Items, *pod)
+	}
+	return podList, nil
+}
+
+func (c *criStub) GetKubeletConfiguration() (*kubeletconfiginternal.KubeletConfiguration, error) {
+	return nil, fmt.Errorf("kubelet configuration is not supported in the pod discovery mode %s", PodDiscoveryModeCRI)
+}
+
+func (c *criStub) buildPod(ctx context.Context, sandbox *runtimeapi.PodSandbox, containers []*runtimeapi.Container) *corev1.Pod {
+	meta := sandbox.GetMetadata()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              meta.GetName(),
+			Namespace:         meta.GetNamespace(),
+			UID:               types.UID(meta.GetUid()),
+			Labels:            map[string]string{},
+			Annotations:       map[string]string{},
+			CreationTimestamp: metav1.NewTime(time.Unix(0, sandbox.GetCreatedAt())),
+		},
+	}
+	for k, v := range sandbox.GetLabels() {
+		if k == criLabelPodName || k == criLabelPodNamespace || k == criLabelPodUID {
+			continue
+		}
+		pod.Labels[k] = v
+	}
+	for k, v := range sandbox.GetAnnotations() {
+		pod.Annotations[k] = v
+	}
+
+	for _, container := range containers {
+		containerSpec := corev1.Container{
+			Name:  container.GetMetadata().GetName(),
+			Image: container.GetImage().GetImage(),
+		}
+		containerStatus := corev1.ContainerStatus{
+			Name:        containerSpec.Name,
+			ContainerID: fmt.Sprintf("%s://%s", c.runtimeName, container.GetId()),
+			Image:       containerSpec.Image,
+			ImageID:     container.GetImageRef(),
+		}
+		statusRsp, err := c.client.ContainerStatus(ctx, &runtimeapi.ContainerStatusRequest{ContainerId: container.GetId()})
+		if err != nil {
+			klog.V(4).Infof("get cri container status failed, pod %s/%s, container %s, err: %v",
+				pod.Namespace, pod.Name, containerSpec.Name, err)
+			setContainerState(&containerStatus, container.GetState(), nil)
+		} else {
+			setContainerState(&containerStatus, container.GetState(), statusRsp.GetStatus())
+			containerSpec.Resources = containerResourcesFromCRI(statusRsp.GetStatus().GetResources())
+		}
+		pod.Spec.Containers = append(pod.Spec.Containers, containerSpec)
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, containerStatus)
+	}
+
+	pod.Status.Phase = podPhaseFromCRI(sandbox, pod.Status.ContainerStatuses)
+	pod.Status.QOSClass = probePodQOSClass(pod.UID)
+	return pod
+}
+
+// latestPodSandboxes returns one sandbox for each pod, where the ready and the latest created one is preferred.
+func latestPodSandboxes(sandboxes []*runtimeapi.PodSandbox) []*runtimeapi.PodSandbox {
+	sandboxByUID := map[string]*runtimeapi.PodSandbox{}
+	for _, sandbox := range sandboxes {
+		uid := sandbox.GetMetadata().GetUid()
+		if uid == "" {
+			continue
+		}
+		old, ok := sandboxByUID[uid]
+		if !ok {
+			sandboxByUID[uid] = sandbox
+			continue
+		}
+		oldReady := old.GetState() == runtimeapi.PodSandboxState_SANDBOX_READY
+		newReady := sandbox.GetState() == runtimeapi.PodSandboxState_SANDBOX_READY
+		if (newReady && !oldReady) || (newReady == oldReady && sandbox.GetCreatedAt() > old.GetCreatedAt()) {
+			sandboxByUID[uid] = sandbox
+		}
+	}
+	result := make([]*runtimeapi.PodSandbox, 0, len(sandboxByUID))
+	for _, sandbox := range sandboxByUID {
+		result = append(result, sandbox)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetMetadata().GetUid() < result[j].GetMetadata().GetUid()
+	})
+	return result
+}
+
+// latestContainers returns the container of the latest attempt for each container name.
+func latestContainers(containers []*runtimeapi.Container) []*runtimeapi.Container {
+	containerByName := map[string]*runtimeapi.Container{}
+	for _, container := range containers {
+		name := container.GetMetadata().GetName()
+		old, ok := containerByName[name]
+		if !ok || container.GetMetadata().GetAttempt() > old.GetMetadata().GetAttempt() {
+			containerByName[name] = container
+		}
+	}
+	result := make([]*runtimeapi.Container, 0, len(containerByName))
+	for _, container := range containerByName {
+		result = append(result, container)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].GetMetadata().GetName() < result[j].GetMetadata().GetName()
+	})
+	return result
+}
+
+func setContainerState(containerStatus *corev1.ContainerStatus, state runtimeapi.ContainerState, status *runtimeapi.ContainerStatus) {
+	switch state {
+	case runtimeapi.ContainerState_CONTAINER_RUNNING:
+		containerStatus.Ready = true
+		containerStatus.State.Running = &corev1.ContainerStateRunning{
+			StartedAt: metav1.NewTime(time.Unix(0, status.GetStartedAt())),
+		}
+	case runtimeapi.ContainerState_CONTAINER_EXITED:
+		containerStatus.State.Terminated = &corev1.ContainerStateTerminated{
+			ExitCode:   status.GetExitCode(),
+			Reason:     status.GetReason(),
+			Message:    status.GetMessage(),
+			StartedAt:  metav1.NewTime(time.Unix(0, status.GetStartedAt())),
+			FinishedAt: metav1.NewTime(time.Unix(0, status.GetFinishedAt())),
+		}
+	default:
+		containerStatus.State.Waiting = &corev1.ContainerStateWaiting{
+			Reason: "ContainerCreating",
+		}
+	}
+}
+
+// containerResourcesFromCRI recovers the cpu and memory resources of the container from the linux resources of the
+// CRI container status. The cpu request is converted from the cpu shares, and the memory request is unknown.
+func containerResourcesFromCRI(resources *runtimeapi.ContainerResources) corev1.ResourceRequirements {
+	requirements := corev1.ResourceRequirements{}
+	linux := resources.GetLinux()
+	if linux == nil {
+		return requirements
+	}
+	if linux.GetCpuShares() > system.CPUSharesMinValue {
+		requirements.Requests = corev1.ResourceList{
+			corev1.ResourceCPU: *resource.NewMilliQuantity(linux.GetCpuShares()*1000/system.CPUShareUnitValue, resource.DecimalSI),
+		}
+	}
+	limits := corev1.ResourceList{}
+	if linux.GetCpuQuota() > 0 && linux.GetCpuPeriod() > 0 {
+		limits[corev1.ResourceCPU] = *resource.NewMilliQuantity(linux.GetCpuQuota()*1000/linux.GetCpuPeriod(), resource.DecimalSI)
+	}
+	if linux.GetMemoryLimitInBytes() > 0 {
+		limits[corev1.ResourceMemory] = *resource.NewQuantity(linux.GetMemoryLimitInBytes(), resource.BinarySI)
+	}
+	if len(limits) > 0 {
+		requirements.Limits = limits
+	}
+	return requirements
+}
+
+func podPhaseFromCRI(sandbox *runtimeapi.PodSandbox, containerStatuses []corev1.ContainerStatus) corev1.PodPhase {
+	if sandbox.GetState() == runtimeapi.PodSandboxState_SANDBOX_READY {
+		for i := range containerStatuses {
+			if containerStatuses[i].State.Running != nil {
+				return corev1.PodRunning
+			}
+		}
+		return corev1.PodPending
+	}
+	if len(containerStatuses) == 0 {
+		return corev1.PodFailed
+	}
+	for i := range containerStatuses {
+		terminated := containerStatuses[i].State.Terminated
+		if terminated == nil || terminated.ExitCode != 0 {
+			return corev1.PodFailed
+		}
+	}
+	return corev1.PodSucceeded
+}
+
+// probePodQOSClass finds the QoS class of the pod by its cgroup dir, since the CRI runtime does not know the pod QoS.
+// It returns empty if the pod cgroup is not found, and the QoS class is then calculated from the container resources.
+func probePodQOSClass(podUID types.UID) corev1.PodQOSClass {
+	for _, qosClass := range podQOSClassesToProbe {
+		podDir := filepath.Join(system.GetRootCgroupSubfsDir(system.CgroupCPUDir),
+			system.CgroupPathFormatter.ParentDir,
+			system.CgroupPathFormatter.QOSDirFn(qosClass),
+			system.CgroupPathFormatter.PodDirFn(qosClass, string(podUID)))
+		if system.FileExists(podDir) {
+			return qosClass
+		}
+	}
+	return ""
+}
+
+// fallbackKubeletStub queries the pods from the kubelet, and falls back to the CRI runtime once the kubelet fails.
+type fallbackKubeletStub struct {
+	kubelet KubeletStub
+	cri     KubeletStub
+}
+
+func (f *fallbackKubeletStub) GetAllPods() (corev1.PodList, error) {
+	podList, err := f.kubelet.GetAllPods()
+	if err == nil {
+		return podList, nil
+	}
+	klog.V(4).Infof("get pods from kubelet failed, fall back to the cri runtime, err: %v", err)
+	return f.cri.GetAllPods()
+}
+
+func (f *fallbackKubeletStub) GetKubeletConfiguration() (*kubeletconfiginternal.KubeletConfiguration, error) {
+	return f.kubelet.GetKubeletConfiguration()
+}
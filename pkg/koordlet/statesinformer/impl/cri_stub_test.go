@@ -0,0 +1,228 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubeletconfiginternal "k8s.io/kubernetes/pkg/kubelet/apis/config"
+
+	mockclient "github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler/mockclient"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func Test_criStub_GetAllPods(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.SetCgroupsV2(false)
+	helper.MkDirAll(filepath.Join(system.CgroupCPUDir, system.CgroupPathFormatter.ParentDir,
+		system.CgroupPathFormatter.QOSDirFn(corev1.PodQOSBurstable),
+		system.CgroupPathFormatter.PodDirFn(corev1.PodQOSBurstable, "uid-a")))
+
+	sandboxes := []*runtimeapi.PodSandbox{
+		{
+			Id:        "sandbox-a-0",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "pod-a", Namespace: "default", Uid: "uid-a", Attempt: 0},
+			State:     runtimeapi.PodSandboxState_SANDBOX_NOTREADY,
+			CreatedAt: 100,
+		},
+		{
+			Id:        "sandbox-a-1",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "pod-a", Namespace: "default", Uid: "uid-a", Attempt: 1},
+			State:     runtimeapi.PodSandboxState_SANDBOX_READY,
+			CreatedAt: 200,
+			Labels: map[string]string{
+				criLabelPodName:      "pod-a",
+				criLabelPodNamespace: "default",
+				criLabelPodUID:       "uid-a",
+				"app":                "test",
+			},
+			Annotations: map[string]string{"test-annotation": "true"},
+		},
+		{
+			Id:        "sandbox-b",
+			Metadata:  &runtimeapi.PodSandboxMetadata{Name: "pod-b", Namespace: "default", Uid: "uid-b"},
+			State:     runtimeapi.PodSandboxState_SANDBOX_NOTREADY,
+			CreatedAt: 300,
+		},
+	}
+	containers := []*runtimeapi.Container{
+		{
+			Id:           "container-a-0",
+			PodSandboxId: "sandbox-a-1",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "main", Attempt: 0},
+			Image:        &runtimeapi.ImageSpec{Image: "test-image"},
+			State:        runtimeapi.ContainerState_CONTAINER_EXITED,
+		},
+		{
+			Id:           "container-a-1",
+			PodSandboxId: "sandbox-a-1",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "main", Attempt: 1},
+			Image:        &runtimeapi.ImageSpec{Image: "test-image"},
+			ImageRef:     "sha256:test",
+			State:        runtimeapi.ContainerState_CONTAINER_RUNNING,
+		},
+		{
+			Id:           "container-b",
+			PodSandboxId: "sandbox-b",
+			Metadata:     &runtimeapi.ContainerMetadata{Name: "job"},
+			State:        runtimeapi.ContainerState_CONTAINER_EXITED,
+		},
+	}
+
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	client := mockclient.NewMockRuntimeServiceClient(ctl)
+	client.EXPECT().Version(gomock.Any(), gomock.Any()).Return(&runtimeapi.VersionResponse{RuntimeName: "containerd"}, nil).Times(1)
+	client.EXPECT().ListPodSandbox(gomock.Any(), gomock.Any()).Return(&runtimeapi.ListPodSandboxResponse{Items: sandboxes}, nil).Times(2)
+	client.EXPECT().ListContainers(gomock.Any(), gomock.Any()).Return(&runtimeapi.ListContainersResponse{Containers: containers}, nil).Times(2)
+	client.EXPECT().ContainerStatus(gomock.Any(), &runtimeapi.ContainerStatusRequest{ContainerId: "container-a-1"}).Return(&runtimeapi.ContainerStatusResponse{
+		Status: &runtimeapi.ContainerStatus{
+			StartedAt: 1000,
+			Resources: &runtimeapi.ContainerResources{
+				Linux: &runtimeapi.LinuxContainerResources{
+					CpuShares:          1024,
+					CpuQuota:           200000,
+					CpuPeriod:          100000,
+					MemoryLimitInBytes: 1 << 30,
+				},
+			},
+		},
+	}, nil).Times(2)
+	client.EXPECT().ContainerStatus(gomock.Any(), &runtimeapi.ContainerStatusRequest{ContainerId: "container-b"}).Return(&runtimeapi.ContainerStatusResponse{
+		Status: &runtimeapi.ContainerStatus{ExitCode: 0, Reason: "Completed"},
+	}, nil).Times(2)
+
+	stub := NewCRIStub(client, 3*time.Second)
+	for i := 0; i < 2; i++ { // the runtime version is queried only once
+		podList, err := stub.GetAllPods()
+		assert.NoError(t, err)
+		assert.Len(t, podList.Items, 2)
+
+		podA := podList.Items[0]
+		assert.Equal(t, "pod-a", podA.Name)
+		assert.Equal(t, "default", podA.Namespace)
+		assert.Equal(t, map[string]string{"app": "test"}, podA.Labels)
+		assert.Equal(t, map[string]string{"test-annotation": "true"}, podA.Annotations)
+		assert.Equal(t, corev1.PodRunning, podA.Status.Phase)
+		assert.Equal(t, corev1.PodQOSBurstable, podA.Status.QOSClass)
+		assert.Len(t, podA.Spec.Containers, 1)
+		assert.Equal(t, corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU: *resource.NewMilliQuantity(1000, resource.DecimalSI),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    *resource.NewMilliQuantity(2000, resource.DecimalSI),
+				corev1.ResourceMemory: *resource.NewQuantity(1<<30, resource.BinarySI),
+			},
+		}, podA.Spec.Containers[0].Resources)
+		assert.Len(t, podA.Status.ContainerStatuses, 1)
+		assert.Equal(t, "containerd://container-a-1", podA.Status.ContainerStatuses[0].ContainerID)
+		assert.Equal(t, "sha256:test", podA.Status.ContainerStatuses[0].ImageID)
+		assert.True(t, podA.Status.ContainerStatuses[0].Ready)
+		assert.NotNil(t, podA.Status.ContainerStatuses[0].State.Running)
+
+		podB := podList.Items[1]
+		assert.Equal(t, "pod-b", podB.Name)
+		assert.Equal(t, corev1.PodSucceeded, podB.Status.Phase)
+		assert.Equal(t, corev1.PodQOSClass(""), podB.Status.QOSClass)
+		assert.NotNil(t, podB.Status.ContainerStatuses[0].State.Terminated)
+		assert.Equal(t, "Completed", podB.Status.ContainerStatuses[0].State.Terminated.Reason)
+	}
+
+	_, err := stub.GetKubeletConfiguration()
+	assert.Error(t, err)
+}
+
+func Test_criStub_GetAllPodsFailed(t *testing.T) {
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	client := mockclient.NewMockRuntimeServiceClient(ctl)
+	client.EXPECT().Version(gomock.Any(), gomock.Any()).Return(&runtimeapi.VersionResponse{RuntimeName: "containerd"}, nil)
+	client.EXPECT().ListPodSandbox(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("expected error"))
+
+	stub := NewCRIStub(client, 3*time.Second)
+	_, err := stub.GetAllPods()
+	assert.Error(t, err)
+}
+
+func Test_podPhaseFromCRI(t *testing.T) {
+	running := corev1.ContainerStatus{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}
+	waiting := corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{}}}
+	succeeded := corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}}
+	failed := corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}}}
+	ready := &runtimeapi.PodSandbox{State: runtimeapi.PodSandboxState_SANDBOX_READY}
+	notReady := &runtimeapi.PodSandbox{State: runtimeapi.PodSandboxState_SANDBOX_NOTREADY}
+	tests := []struct {
+		name       string
+		sandbox    *runtimeapi.PodSandbox
+		containers []corev1.ContainerStatus
+		want       corev1.PodPhase
+	}{
+		{name: "running", sandbox: ready, containers: []corev1.ContainerStatus{waiting, running}, want: corev1.PodRunning},
+		{name: "pending", sandbox: ready, containers: []corev1.ContainerStatus{waiting}, want: corev1.PodPending},
+		{name: "succeeded", sandbox: notReady, containers: []corev1.ContainerStatus{succeeded}, want: corev1.PodSucceeded},
+		{name: "failed", sandbox: notReady, containers: []corev1.ContainerStatus{succeeded, failed}, want: corev1.PodFailed},
+		{name: "failed without containers", sandbox: notReady, want: corev1.PodFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, podPhaseFromCRI(tt.sandbox, tt.containers))
+		})
+	}
+}
+
+type fakeKubeletStub struct {
+	pods corev1.PodList
+	err  error
+}
+
+func (f *fakeKubeletStub) GetAllPods() (corev1.PodList, error) {
+	return f.pods, f.err
+}
+
+func (f *fakeKubeletStub) GetKubeletConfiguration() (*kubeletconfiginternal.KubeletConfiguration, error) {
+	return &kubeletconfiginternal.KubeletConfiguration{}, f.err
+}
+
+func Test_fallbackKubeletStub(t *testing.T) {
+	kubeletPods := corev1.PodList{Items: []corev1.Pod{{}}}
+	criPods := corev1.PodList{Items: []corev1.Pod{{}, {}}}
+
+	stub := &fallbackKubeletStub{
+		kubelet: &fakeKubeletStub{pods: kubeletPods},
+		cri:     &fakeKubeletStub{pods: criPods},
+	}
+	got, err := stub.GetAllPods()
+	assert.NoError(t, err)
+	assert.Equal(t, kubeletPods, got)
+
+	stub.kubelet = &fakeKubeletStub{err: fmt.Errorf("expected error")}
+	got, err = stub.GetAllPods()
+	assert.NoError(t, err)
+	assert.Equal(t, criPods, got)
+	_, err = stub.GetKubeletConfiguration()
+	assert.Error(t, err)
+}
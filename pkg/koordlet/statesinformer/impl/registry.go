@@ -16,6 +16,13 @@ limitations under the License.
 
 package impl
 
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/klog/v2"
+)
+
 // NOTE: variables in this file can be overwritten for extension
 
 var DefaultPluginRegistry = map[PluginName]informerPlugin{
@@ -27,3 +34,70 @@ var DefaultPluginRegistry = map[PluginName]informerPlugin{
 	podResourcesInformerName: newPodResourcesInformer(),
 	nodeMetricInformerName:   NewNodeMetricInformer(),
 }
+
+// PluginDependencies are the plugins which have to be synced before the depending plugin starts.
+var PluginDependencies = map[PluginName][]PluginName{}
+
+// InformerPlugin is the interface of the informer plugins, which out-of-tree plugins can implement to provide
+// additional states, e.g. custom CRDs or external config sources.
+type InformerPlugin = informerPlugin
+
+// RegisterInformerPlugin registers an out-of-tree informer plugin into the states informer without modifying the
+// built-in list. The plugin starts after all of its dependencies have synced. It should be called before the states
+// informer is created, e.g. in the init function of the plugin.
+func RegisterInformerPlugin(name PluginName, plugin InformerPlugin, dependencies ...PluginName) {
+	if _, ok := DefaultPluginRegistry[name]; ok {
+		klog.Fatalf("states informer plugin %v already registered", name)
+	}
+	DefaultPluginRegistry[name] = plugin
+	if len(dependencies) > 0 {
+		PluginDependencies[name] = dependencies
+	}
+	klog.V(1).Infof("states informer plugin %v has registered, dependencies %v", name, dependencies)
+}
+
+// sortPluginsByDependencies returns the plugin names in the order that each plugin is after its dependencies.
+// The plugins without dependency relations are sorted by names.
+func sortPluginsByDependencies(plugins map[PluginName]informerPlugin, dependencies map[PluginName][]PluginName) ([]PluginName, error) {
+	names := make([]PluginName, 0, len(plugins))
+	for name := range plugins {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := map[PluginName]int{}
+	sorted := make([]PluginName, 0, len(names))
+	var visit func(name PluginName, path []PluginName) error
+	visit = func(name PluginName, path []PluginName) error {
+		switch states[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("states informer plugins have circular dependencies %v", append(path, name))
+		}
+		states[name] = visiting
+		for _, dep := range dependencies[name] {
+			if _, ok := plugins[dep]; !ok {
+				return fmt.Errorf("states informer plugin %v depends on %v which is not registered", name, dep)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		states[name] = visited
+		sorted = append(sorted, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+type fakeExtensionPlugin struct {
+	synced  *atomic.Bool
+	started chan struct{}
+	state   *PluginState
+}
+
+func newFakeExtensionPlugin() *fakeExtensionPlugin {
+	return &fakeExtensionPlugin{
+		synced:  atomic.NewBool(false),
+		started: make(chan struct{}),
+	}
+}
+
+func (f *fakeExtensionPlugin) Setup(ctx *PluginOption, state *PluginState) {
+	f.state = state
+}
+
+func (f *fakeExtensionPlugin) Start(stopCh <-chan struct{}) {
+	close(f.started)
+}
+
+func (f *fakeExtensionPlugin) HasSynced() bool {
+	return f.synced.Load()
+}
+
+func Test_sortPluginsByDependencies(t *testing.T) {
+	plugins := map[PluginName]informerPlugin{
+		"a": newFakeExtensionPlugin(),
+		"b": newFakeExtensionPlugin(),
+		"c": newFakeExtensionPlugin(),
+		"d": newFakeExtensionPlugin(),
+	}
+	tests := []struct {
+		name         string
+		dependencies map[PluginName][]PluginName
+		want         []PluginName
+		wantErr      bool
+	}{
+		{
+			name: "no dependencies",
+			want: []PluginName{"a", "b", "c", "d"},
+		},
+		{
+			name: "sort by dependencies",
+			dependencies: map[PluginName][]PluginName{
+				"a": {"c"},
+				"c": {"d", "b"},
+			},
+			want: []PluginName{"d", "b", "c", "a"},
+		},
+		{
+			name: "dependency not registered",
+			dependencies: map[PluginName][]PluginName{
+				"a": {"e"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "circular dependencies",
+			dependencies: map[PluginName][]PluginName{
+				"a": {"b"},
+				"b": {"c"},
+				"c": {"a"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sortPluginsByDependencies(plugins, tt.dependencies)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRegisterInformerPlugin(t *testing.T) {
+	oldRegistry, oldDependencies := DefaultPluginRegistry, PluginDependencies
+	defer func() {
+		DefaultPluginRegistry, PluginDependencies = oldRegistry, oldDependencies
+	}()
+	base, extension := newFakeExtensionPlugin(), newFakeExtensionPlugin()
+	DefaultPluginRegistry = map[PluginName]informerPlugin{"base": base}
+	PluginDependencies = map[PluginName][]PluginName{}
+	RegisterInformerPlugin("extension", extension, "base")
+	assert.Equal(t, []PluginName{"base"}, PluginDependencies["extension"])
+
+	extensionType := statesinformer.RegisterExtensionType("RegisterTypeTestExtension")
+	assert.Equal(t, extensionType, statesinformer.RegisterExtensionType("RegisterTypeTestExtension"))
+	assert.Equal(t, "RegisterTypeTestExtension", extensionType.String())
+
+	s := &statesInformer{
+		option: &PluginOption{},
+		states: &PluginState{callbackRunner: NewCallbackRunner()},
+	}
+	s.initInformerPlugins()
+	s.setupPlugins()
+	assert.Equal(t, []PluginName{"base", "extension"}, s.pluginOrder)
+	got, ok := extension.state.GetInformerPlugin("base")
+	assert.True(t, ok)
+	assert.Equal(t, base, got)
+
+	// the extension states fan out to the subscribers
+	output := make(chan interface{}, 1)
+	s.states.callbackRunner.Setup(s)
+	s.RegisterCallbacks(extensionType, "test-extension", "receive extension states",
+		func(t statesinformer.RegisterType, obj interface{}, target *statesinformer.CallbackTarget) {
+			output <- obj
+		})
+	extension.state.RegisterStateGetter(extensionType, func() interface{} {
+		return "extension-state"
+	})
+	assert.Equal(t, "extension-state", s.states.callbackRunner.getObjByType(extensionType, UpdateCbCtx{}))
+
+	// the extension plugin starts after its dependencies synced
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	s.startPlugins(stopCh)
+	<-base.started
+	select {
+	case <-extension.started:
+		t.Fatal("extension plugin started before its dependencies synced")
+	case <-time.After(200 * time.Millisecond):
+	}
+	base.synced.Store(true)
+	<-extension.started
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	koordletuti "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
 
@@ -45,11 +46,20 @@ func (s *statesInformer) reportDevice() {
 	}
 	device := s.buildBasicDevice(node)
 	func() {
-		gpuDevices := s.buildGPUDevice()
+		gpus := s.getGPUDevices()
+		gpuDevices := s.buildGPUDevice(gpus)
 		if len(gpuDevices) == 0 {
 			return
 		}
-		gpuModel, gpuDriverVer := s.getGPUDriverAndModelFunc()
+		var gpuModel, gpuDriverVer string
+		switch gpus.Vendor() {
+		case koordletuti.GPUVendorAMD:
+			gpuModel, gpuDriverVer = getAMDGPUDriverAndModel(gpus)
+		case koordletuti.GPUVendorHuawei:
+			gpuModel, gpuDriverVer = getAscendNPUDriverAndModel(gpus)
+		default:
+			gpuModel, gpuDriverVer = s.getGPUDriverAndModelFunc()
+		}
 		s.fillGPUDevice(device, gpuDevices, gpuModel, gpuDriverVer)
 	}()
 	func() {
@@ -150,8 +160,7 @@ func (s *statesInformer) updateDevice(device *schedulingv1alpha1.Device) error {
 	})
 }
 
-func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
-	//queryParam := generateQueryParam()
+func (s *statesInformer) getGPUDevices() koordletuti.GPUDevices {
 	gpuDeviceInfo, exist := s.metricsCache.Get(koordletuti.GPUDeviceType)
 	if !exist {
 		klog.V(4).Infof("gpu device not exist")
@@ -162,7 +171,10 @@ func (s *statesInformer) buildGPUDevice() []schedulingv1alpha1.DeviceInfo {
 		klog.Errorf("value type error, expect: %T, got %T", koordletuti.GPUDevices{}, gpuDeviceInfo)
 		return nil
 	}
+	return gpus
+}
 
+func (s *statesInformer) buildGPUDevice(gpus koordletuti.GPUDevices) []schedulingv1alpha1.DeviceInfo {
 	var deviceInfos []schedulingv1alpha1.DeviceInfo
 	for idx := range gpus {
 		gpu := gpus[idx]
@@ -322,6 +334,56 @@ func (s *statesInformer) getGPUDriverAndModel() (string, string) {
 	return transModel, driverVersion
 }
 
+// getAMDGPUDriverAndModel returns the model and the driver version of the AMD GPUs from the sysfs.
+func getAMDGPUDriverAndModel(gpus koordletuti.GPUDevices) (string, string) {
+	model := ""
+	for i, gpu := range gpus {
+		productName, err := system.GetAMDGPUProductName(gpu.BusID)
+		if err != nil || productName == "" {
+			klog.Errorf("unable to get product name of device %s: %v", gpu.BusID, err)
+			return "", ""
+		} else if i == 0 {
+			model = productName
+		} else if model != productName {
+			klog.Errorf("device model invalid: %s, %s", model, productName)
+			return "", ""
+		}
+	}
+
+	// AMD Instinct MI210 -> Instinct-MI210
+	transModel := strings.ReplaceAll(strings.TrimPrefix(model, "AMD "), " ", "-")
+
+	driverVersion, err := system.GetAMDGPUDriverVersion()
+	if err != nil {
+		klog.Errorf("unable to get amdgpu driver version: %v", err)
+		return "", ""
+	}
+	return transModel, driverVersion
+}
+
+func getAscendNPUDriverAndModel(gpus koordletuti.GPUDevices) (string, string) {
+	model := ""
+	for i, gpu := range gpus {
+		npuModel, err := system.GetAscendNPUModel(gpu.BusID)
+		if err != nil {
+			klog.Errorf("unable to get model of device %s: %v", gpu.BusID, err)
+			return "", ""
+		} else if i == 0 {
+			model = npuModel
+		} else if model != npuModel {
+			klog.Errorf("device model invalid: %s, %s", model, npuModel)
+			return "", ""
+		}
+	}
+
+	driverVersion, err := system.GetAscendNPUDriverVersion()
+	if err != nil {
+		klog.Errorf("unable to get ascend driver version: %v", err)
+		return "", ""
+	}
+	return model, driverVersion
+}
+
 func (s *statesInformer) gpuHealCheck(stopCh <-chan struct{}) {
 	count, ret := nvml.DeviceGetCount()
 	if ret != nvml.SUCCESS {
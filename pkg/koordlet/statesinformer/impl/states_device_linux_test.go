@@ -33,6 +33,7 @@ import (
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	schedulingfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	mock_metriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func Test_reportGPUDevice(t *testing.T) {
@@ -164,3 +165,111 @@ func Test_reportGPUDevice(t *testing.T) {
 	assert.Equal(t, device.Labels[extension.LabelGPUModel], "A100")
 	assert.Equal(t, device.Labels[extension.LabelGPUDriverVersion], "470")
 }
+
+func Test_reportAMDGPUDevice(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/product_name", "AMD Instinct MI210\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/product_name", "AMD Instinct MI210\n")
+	helper.WriteFileContents("module/amdgpu/version", "6.3.6\n")
+
+	testNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test",
+		},
+	}
+	fakeClient := schedulingfake.NewSimpleClientset().SchedulingV1alpha1().Devices()
+	ctl := gomock.NewController(t)
+	defer ctl.Finish()
+	mockMetricCache := mock_metriccache.NewMockMetricCache(ctl)
+	gpuDeviceInfo := koordletutil.GPUDevices{
+		{UUID: "0xff", Minor: 0, MemoryTotal: 68702699520, BusID: "0000:03:00.0", Vendor: koordletutil.GPUVendorAMD},
+		{UUID: "0000:83:00.0", Minor: 1, MemoryTotal: 68702699520, BusID: "0000:83:00.0", Vendor: koordletutil.GPUVendorAMD},
+	}
+	mockMetricCache.EXPECT().Get(koordletutil.GPUDeviceType).Return(gpuDeviceInfo, true)
+	mockMetricCache.EXPECT().Get(koordletutil.RDMADeviceType).Return(nil, false)
+	r := &statesInformer{
+		deviceClient: fakeClient,
+		metricsCache: mockMetricCache,
+		states: &PluginState{
+			informerPlugins: map[PluginName]informerPlugin{
+				nodeInformerName: &nodeInformer{
+					node: testNode,
+				},
+			},
+		},
+		getGPUDriverAndModelFunc: func() (string, string) {
+			t.Error("nvml is not expected to be called for the amd gpus")
+			return "", ""
+		},
+	}
+	r.reportDevice()
+
+	device, err := fakeClient.Get(context.TODO(), "test", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, device.Spec.Devices, 2)
+	assert.Equal(t, "0xff", device.Spec.Devices[0].UUID)
+	assert.Equal(t, schedulingv1alpha1.GPU, device.Spec.Devices[0].Type)
+	gotMemory := device.Spec.Devices[0].Resources[extension.ResourceGPUMemory]
+	assert.Equal(t, int64(68702699520), gotMemory.Value())
+	assert.Equal(t, "Instinct-MI210", device.Labels[extension.LabelGPUModel])
+	assert.Equal(t, "6.3.6", device.Labels[extension.LabelGPUDriverVersion])
+}
+
+func Test_getAMDGPUDriverAndModel(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	gpus := koordletutil.GPUDevices{
+		{UUID: "0xff", Minor: 0, BusID: "0000:03:00.0", Vendor: koordletutil.GPUVendorAMD},
+		{UUID: "0xfe", Minor: 1, BusID: "0000:83:00.0", Vendor: koordletutil.GPUVendorAMD},
+	}
+	// product name not found
+	model, driverVersion := getAMDGPUDriverAndModel(gpus)
+	assert.Equal(t, "", model)
+	assert.Equal(t, "", driverVersion)
+
+	// mixed models
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/product_name", "AMD Instinct MI210\n")
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/product_name", "AMD Instinct MI250X\n")
+	helper.WriteFileContents("module/amdgpu/version", "6.3.6\n")
+	model, driverVersion = getAMDGPUDriverAndModel(gpus)
+	assert.Equal(t, "", model)
+	assert.Equal(t, "", driverVersion)
+
+	helper.WriteFileContents("bus/pci/devices/0000:83:00.0/product_name", "AMD Instinct MI210\n")
+	model, driverVersion = getAMDGPUDriverAndModel(gpus)
+	assert.Equal(t, "Instinct-MI210", model)
+	assert.Equal(t, "6.3.6", driverVersion)
+}
+
+func Test_getAscendNPUDriverAndModel(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldExecCmdOnHost := system.ExecCmdOnHost
+	defer func() {
+		system.ExecCmdOnHost = oldExecCmdOnHost
+	}()
+	system.ExecCmdOnHost = func(cmds []string) ([]byte, int, error) {
+		return []byte("Version=23.0.rc3\n"), 0, nil
+	}
+	gpus := koordletutil.GPUDevices{
+		{UUID: "0000:c1:00.0", Minor: 0, BusID: "0000:c1:00.0", Vendor: koordletutil.GPUVendorHuawei},
+		{UUID: "0000:c2:00.0", Minor: 1, BusID: "0000:c2:00.0", Vendor: koordletutil.GPUVendorHuawei},
+	}
+	// device id not found
+	model, driverVersion := getAscendNPUDriverAndModel(gpus)
+	assert.Equal(t, "", model)
+	assert.Equal(t, "", driverVersion)
+
+	// mixed models
+	helper.WriteFileContents("bus/pci/devices/0000:c1:00.0/device", "0xd802\n")
+	helper.WriteFileContents("bus/pci/devices/0000:c2:00.0/device", "0xd801\n")
+	model, driverVersion = getAscendNPUDriverAndModel(gpus)
+	assert.Equal(t, "", model)
+	assert.Equal(t, "", driverVersion)
+
+	helper.WriteFileContents("bus/pci/devices/0000:c2:00.0/device", "0xd802\n")
+	model, driverVersion = getAscendNPUDriverAndModel(gpus)
+	assert.Equal(t, "Ascend910B", model)
+	assert.Equal(t, "23.0.rc3", driverVersion)
+}
@@ -62,6 +62,26 @@ type PluginState struct {
 	predictorFactory prediction.PredictorFactory
 }
 
+// GetInformerPlugin returns the informer plugin by name, which allows the plugins to read the states of their dependencies.
+func (s *PluginState) GetInformerPlugin(name PluginName) (InformerPlugin, bool) {
+	plugin, ok := s.informerPlugins[name]
+	return plugin, ok
+}
+
+func (s *PluginState) GetMetricCache() metriccache.MetricCache {
+	return s.metricCache
+}
+
+// RegisterStateGetter sets the function which returns the states of the extension type for the callbacks.
+func (s *PluginState) RegisterStateGetter(rType statesinformer.RegisterType, getter func() interface{}) {
+	s.callbackRunner.RegisterStateGetter(rType, getter)
+}
+
+// SendCallback notifies the callbacks subscribed to the type that the states are updated.
+func (s *PluginState) SendCallback(rType statesinformer.RegisterType) {
+	s.callbackRunner.SendCallback(rType)
+}
+
 type GetGPUDriverAndModelFunc func() (string, string)
 
 type statesInformer struct {
@@ -75,6 +95,8 @@ type statesInformer struct {
 	option  *PluginOption
 	states  *PluginState
 	started *atomic.Bool
+	// pluginOrder is the order of the plugins to setup and start, where each plugin is after its dependencies
+	pluginOrder []PluginName
 
 	getGPUDriverAndModelFunc GetGPUDriverAndModelFunc
 }
@@ -123,8 +145,13 @@ func (s *statesInformer) initInformerPlugins() {
 }
 
 func (s *statesInformer) setupPlugins() {
-	for name, plugin := range s.states.informerPlugins {
-		plugin.Setup(s.option, s.states)
+	pluginOrder, err := sortPluginsByDependencies(s.states.informerPlugins, PluginDependencies)
+	if err != nil {
+		klog.Fatalf("failed to setup states informer plugins, err: %v", err)
+	}
+	s.pluginOrder = pluginOrder
+	for _, name := range s.pluginOrder {
+		s.states.informerPlugins[name].Setup(s.option, s.states)
 		klog.V(2).Infof("plugin %v has been setup", name)
 	}
 }
@@ -176,9 +203,20 @@ func (s *statesInformer) waitForSyncFunc() []cache.InformerSynced {
 }
 
 func (s *statesInformer) startPlugins(stopCh <-chan struct{}) {
-	for name, p := range s.states.informerPlugins {
-		klog.V(4).Infof("starting informer plugin %v", name)
-		go p.Start(stopCh)
+	for _, name := range s.pluginOrder {
+		pluginName, p := name, s.states.informerPlugins[name]
+		var depsSynced []cache.InformerSynced
+		for _, dep := range PluginDependencies[pluginName] {
+			depsSynced = append(depsSynced, s.states.informerPlugins[dep].HasSynced)
+		}
+		go func() {
+			if len(depsSynced) > 0 && !cache.WaitForCacheSync(stopCh, depsSynced...) {
+				klog.Errorf("timed out waiting for the dependencies of informer plugin %v to sync", pluginName)
+				return
+			}
+			klog.V(4).Infof("starting informer plugin %v", pluginName)
+			p.Start(stopCh)
+		}()
 	}
 }
 
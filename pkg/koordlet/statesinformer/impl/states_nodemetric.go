@@ -18,13 +18,18 @@ package impl
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -45,6 +50,7 @@ import (
 	clientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	listerv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
@@ -52,6 +58,7 @@ import (
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
 )
 
 const (
@@ -95,12 +102,16 @@ type nodeMetricInformer struct {
 	nodeMetricLister   listerv1alpha1.NodeMetricLister
 	eventRecorder      record.EventRecorder
 	statusUpdater      *statusUpdater
+	// statusReporter reports the status instead of the statusUpdater if the grpc transport is configured
+	statusReporter nodeMetricStatusReporter
 
 	podsInformer     *podsInformer
 	nodeInformer     *nodeInformer
 	nodeSLOInformer  *nodeSLOInformer
 	metricCache      metriccache.MetricCache
 	predictorFactory prediction.PredictorFactory
+	// memoryBandwidthCapacity is the total memory bandwidth of the node in bytes per second, 0 means unknown
+	memoryBandwidthCapacity int64
 
 	rwMutex    sync.RWMutex
 	nodeMetric *slov1alpha1.NodeMetric
@@ -133,7 +144,12 @@ func (r *nodeMetricInformer) Setup(ctx *PluginOption, state *PluginState) {
 	r.eventRecorder = eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "koordlet-NodeMetric", Host: ctx.NodeName})
 
 	r.statusUpdater = newStatusUpdater(ctx.KoordClient.SloV1alpha1().NodeMetrics())
+	if ctx.config.NodeMetricReportTransport == NodeMetricReportTransportGRPC {
+		r.setupStatusReporter(ctx.config)
+	}
 
+	// the capacity is configured in MB/s, where 1 MB is 10^6 bytes
+	r.memoryBandwidthCapacity = ctx.config.MemoryBandwidthCapacityMBps * 1000 * 1000
 	r.metricCache = state.metricCache
 	podsInformerIf := state.informerPlugins[podsInformerName]
 	if podsInformer, ok := podsInformerIf.(*podsInformer); !ok {
@@ -268,8 +284,24 @@ func (r *nodeMetricInformer) sync() {
 		PodsMetric:            podMetricInfo,
 		HostApplicationMetric: hostAppMetricInfo,
 		ProdReclaimableMetric: prodReclaimableMetric,
+		ColocationMetric:      collectColocationMetric(),
+	}
+	var retErr error
+	if r.statusReporter != nil {
+		retErr = r.statusReporter.report(newStatus)
+	} else {
+		retErr = r.updateNodeMetricStatus(newStatus)
 	}
-	retErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+
+	if retErr != nil {
+		klog.Warningf("update node metric status failed, status %v, err %v", util.DumpJSON(newStatus), retErr)
+	} else {
+		klog.V(4).Infof("update node metric status success, detail: %v", util.DumpJSON(newStatus))
+	}
+}
+
+func (r *nodeMetricInformer) updateNodeMetricStatus(newStatus *slov1alpha1.NodeMetricStatus) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		nodeMetric, err := r.nodeMetricLister.Get(r.nodeName)
 		if errors.IsNotFound(err) {
 			klog.Warningf("nodeMetric %v not found, skip", r.nodeName)
@@ -281,12 +313,53 @@ func (r *nodeMetricInformer) sync() {
 		err = r.statusUpdater.updateStatus(nodeMetric, newStatus)
 		return err
 	})
+}
 
-	if retErr != nil {
-		klog.Warningf("update node metric status failed, status %v, err %v", util.DumpJSON(newStatus), retErr)
-	} else {
-		klog.V(4).Infof("update node metric status success, detail: %v", util.DumpJSON(newStatus))
+// setupStatusReporter connects to the node metric report server of the koord-manager with TLS, and authenticates
+// with the ServiceAccount token of the koordlet. It falls back to update the NodeMetric CR if the server is not
+// configured.
+func (r *nodeMetricInformer) setupStatusReporter(config *Config) {
+	serverAddr := config.NodeMetricReportServerAddr
+	if serverAddr == "" {
+		klog.Errorf("node metric report server address is not specified, fallback to the %s transport",
+			NodeMetricReportTransportCRD)
+		return
+	}
+	creds, err := newReportTransportCredentials(config.NodeMetricReportServerCA)
+	if err != nil {
+		klog.Errorf("failed to load the credentials of node metric report server %s, fallback to the %s transport, err: %v",
+			serverAddr, NodeMetricReportTransportCRD, err)
+		return
+	}
+	// the connection is established lazily, so the dial only fails on the invalid arguments
+	conn, err := grpc.Dial(serverAddr, grpc.WithTransportCredentials(creds),
+		grpc.WithPerRPCCredentials(metricreport.NewTokenFileCredentials(config.NodeMetricReportTokenFile)))
+	if err != nil {
+		klog.Errorf("failed to dial node metric report server %s, fallback to the %s transport, err: %v",
+			serverAddr, NodeMetricReportTransportCRD, err)
+		return
+	}
+	r.statusReporter = newGRPCStatusReporter(r.nodeName, conn)
+	klog.V(4).Infof("node metric status is reported to the server %s", serverAddr)
+}
+
+// newReportTransportCredentials verifies the server certificate with the CA file, or the system roots if not set.
+func newReportTransportCredentials(caFile string) (credentials.TransportCredentials, error) {
+	if caFile == "" {
+		return credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12}), nil
+	}
+	return credentials.NewClientTLSFromFile(caFile, "")
+}
+
+// collectColocationMetric collects the statistics of the colocation QoS actions taken by koordlet.
+func collectColocationMetric() *slov1alpha1.ColocationMetricInfo {
+	info := &slov1alpha1.ColocationMetricInfo{
+		PodEvictionCount: metrics.GetPodEvictionCount(),
+	}
+	if cores, ok := metrics.GetLastBESuppressCores(); ok {
+		info.BESuppressCPU = resource.NewMilliQuantity(int64(cores*1000), resource.DecimalSI)
 	}
+	return info
 }
 
 func newNodeMetricInformer(client clientset.Interface, nodeName string) cache.SharedIndexInformer {
@@ -349,6 +422,7 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		AggregatedNodeUsages:   r.collectNodeAggregateMetric(endTime, spec.CollectPolicy.NodeAggregatePolicy),
 		SystemUsage:            r.querySystemMetric(startTime, endTime, metriccache.AggregationTypeAVG, false),
 		AggregatedSystemUsages: r.collectSystemAggregateMetric(endTime, spec.CollectPolicy.NodeAggregatePolicy),
+		ZoneMemory:             r.collectZoneMemory(),
 	}
 
 	var gpus koordletutil.GPUDevices
@@ -360,6 +434,8 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		}
 	}
 
+	rdmas := r.getRDMADevices()
+
 	podsMeta := r.podsInformer.GetAllPods()
 	podsMetricInfo := make([]*slov1alpha1.PodMetricInfo, 0, len(podsMeta))
 	nodeSLO := r.nodeSLOInformer.GetNodeSLO()
@@ -369,6 +445,12 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		Start:     &startTime,
 		End:       &endTime,
 	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.NetworkLatencyProber) {
+		nodeMetricInfo.NetworkLatency = r.collectNetworkLatency(queryParam)
+	}
+	if features.DefaultKoordletFeatureGate.Enabled(features.ResctrlCollector) {
+		nodeMetricInfo.MemoryBandwidth = r.collectMemoryBandwidth(queryParam, nodeSLO)
+	}
 	node := r.nodeInformer.GetNode()
 	prodPredictor := r.predictorFactory.New(prediction.ProdReclaimablePredictor, prediction.PredictorContext{Node: node})
 	for _, podMeta := range podsMeta {
@@ -387,6 +469,15 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 		if len(gpus) > 0 {
 			r.fillGPUMetrics(queryParam, podMetric, string(podMeta.Pod.UID), gpus)
 		}
+		if len(rdmas) > 0 {
+			r.fillRDMAMetrics(queryParam, podMetric, string(podMeta.Pod.UID), rdmas)
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.PodNetworkCollector) {
+			r.fillNetworkMetrics(queryParam, podMetric, string(podMeta.Pod.UID))
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.NetworkLatencyProber) {
+			r.fillNetworkLatencyMetrics(queryParam, podMetric, string(podMeta.Pod.UID))
+		}
 		podsMetricInfo = append(podsMetricInfo, podMetric)
 	}
 	for _, hostApp := range nodeSLO.Spec.HostApplications {
@@ -415,6 +506,34 @@ func (r *nodeMetricInformer) collectMetric() (*slov1alpha1.NodeMetricInfo, []*sl
 	return nodeMetricInfo, podsMetricInfo, hostAppMetricInfo, prodReclaimable
 }
 
+// collectZoneMemory reports the actual free and cold memory of each NUMA zone, so the scheduler can tell
+// which zones are really free rather than only unallocated.
+func (r *nodeMetricInformer) collectZoneMemory() []slov1alpha1.ZoneMemoryInfo {
+	value, ok := r.metricCache.Get(metriccache.NodeNUMAInfoKey)
+	if !ok {
+		klog.V(5).Infof("node NUMA info not exist, skip collecting zone memory")
+		return nil
+	}
+	nodeNUMAInfo, ok := value.(*koordletutil.NodeNUMAInfo)
+	if !ok || nodeNUMAInfo == nil {
+		klog.Errorf("value type error, expect: %T, got %T", &koordletutil.NodeNUMAInfo{}, value)
+		return nil
+	}
+
+	var zoneMemory []slov1alpha1.ZoneMemoryInfo
+	for _, numaInfo := range nodeNUMAInfo.NUMAInfos {
+		if numaInfo.MemInfo == nil {
+			continue
+		}
+		zoneMemory = append(zoneMemory, slov1alpha1.ZoneMemoryInfo{
+			Name: util.GenNodeZoneName(int(numaInfo.NUMANodeID)),
+			Free: *resource.NewQuantity(int64(numaInfo.MemInfo.MemFree*1024), resource.BinarySI),
+			Cold: *resource.NewQuantity(int64(numaInfo.MemInfo.InactiveFile*1024), resource.BinarySI),
+		})
+	}
+	return zoneMemory
+}
+
 func (r *nodeMetricInformer) queryNodeMetric(start time.Time, end time.Time, aggregateType metriccache.AggregationType,
 	coldStartFilter bool) slov1alpha1.ResourceMap {
 	rm := slov1alpha1.ResourceMap{}
@@ -437,24 +556,151 @@ func (r *nodeMetricInformer) queryNodeMetric(start time.Time, end time.Time, agg
 	}
 
 	rm.ResourceList = cpuAndMem
+	rm.Devices = r.queryNodeGPUDevices(queryParam)
+
+	if rdmas := r.getRDMADevices(); len(rdmas) > 0 {
+		rdmaDevices, err := r.collectNodeRDMAMetric(queryParam, rdmas)
+		if err != nil {
+			klog.Errorf("query node rdma metric failed, error: %v", err)
+		} else {
+			rm.Devices = append(rm.Devices, rdmaDevices...)
+		}
+	}
+	return rm
+}
 
+func (r *nodeMetricInformer) queryNodeGPUDevices(queryParam metriccache.QueryParam) []schedulingv1alpha1.DeviceInfo {
 	value, exist := r.metricCache.Get(koordletutil.GPUDeviceType)
 	if !exist {
 		klog.V(5).Infof("got no device info on node, skip node gpu metric collection")
-		return rm
+		return nil
 	}
 	gpus, ok := value.(koordletutil.GPUDevices)
 	if !ok {
 		klog.Errorf("value type error, expect: %T, got %T", koordletutil.GPUDevices{}, value)
-		return rm
+		return nil
 	}
 	devices, err := r.collectNodeGPUMetric(queryParam, gpus)
 	if err != nil {
 		klog.Errorf("query node gpu metric failed, error: %v", err)
-		return rm
+		return nil
 	}
-	rm.Devices = devices
-	return rm
+	return devices
+}
+
+func (r *nodeMetricInformer) getRDMADevices() koordletutil.RDMADevices {
+	value, exist := r.metricCache.Get(koordletutil.RDMADeviceType)
+	if !exist {
+		return nil
+	}
+	rdmas, ok := value.(koordletutil.RDMADevices)
+	if !ok {
+		klog.Errorf("value type error, expect: %T, got %T", koordletutil.RDMADevices{}, value)
+		return nil
+	}
+	return rdmas
+}
+
+// collectNodeRDMAMetric reports the bandwidth of the RDMA net devices, which sums up the ports of the RDMA devices
+// on the net device.
+func (r *nodeMetricInformer) collectNodeRDMAMetric(queryparam metriccache.QueryParam, rdmas koordletutil.RDMADevices) ([]schedulingv1alpha1.DeviceInfo, error) {
+	var result []schedulingv1alpha1.DeviceInfo
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get node rdma metric querier failed, error %v", err)
+		return nil, err
+	}
+	defer querier.Close()
+	for _, rdma := range rdmas {
+		var txBandwidth, rxBandwidth float64
+		collected := false
+		for _, rdmaDevice := range rdma.RDMAResources {
+			ports, err := system.GetRDMADevicePorts(rdmaDevice)
+			if err != nil {
+				klog.V(5).Infof("failed to get ports of rdma device %s, err: %v", rdmaDevice, err)
+				continue
+			}
+			for _, port := range ports {
+				properties := metriccache.MetricPropertiesFunc.RDMAPort(rdmaDevice, port)
+				tx, txCollected, err := queryAggregateValue(querier, metriccache.NodeRDMATxBandwidthMetric, properties, queryparam.Aggregate)
+				if err != nil {
+					return result, err
+				}
+				rx, rxCollected, err := queryAggregateValue(querier, metriccache.NodeRDMARxBandwidthMetric, properties, queryparam.Aggregate)
+				if err != nil {
+					return result, err
+				}
+				txBandwidth, rxBandwidth = txBandwidth+tx, rxBandwidth+rx
+				collected = collected || txCollected || rxCollected
+			}
+		}
+		if !collected {
+			continue
+		}
+		result = append(result, buildRDMADeviceUsage(rdma, txBandwidth, rxBandwidth))
+	}
+	return result, nil
+}
+
+func (r *nodeMetricInformer) collectPodRDMAMetric(queryparam metriccache.QueryParam, uid string, rdmas koordletutil.RDMADevices) ([]schedulingv1alpha1.DeviceInfo, error) {
+	var result []schedulingv1alpha1.DeviceInfo
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get pod rdma metric querier failed, error %v", err)
+		return nil, err
+	}
+	defer querier.Close()
+	for _, rdma := range rdmas {
+		var txBandwidth, rxBandwidth float64
+		collected := false
+		for _, rdmaDevice := range rdma.RDMAResources {
+			properties := metriccache.MetricPropertiesFunc.PodRDMA(uid, rdmaDevice)
+			tx, txCollected, err := queryAggregateValue(querier, metriccache.PodRDMATxBandwidthMetric, properties, queryparam.Aggregate)
+			if err != nil {
+				return result, err
+			}
+			rx, rxCollected, err := queryAggregateValue(querier, metriccache.PodRDMARxBandwidthMetric, properties, queryparam.Aggregate)
+			if err != nil {
+				return result, err
+			}
+			txBandwidth, rxBandwidth = txBandwidth+tx, rxBandwidth+rx
+			collected = collected || txCollected || rxCollected
+		}
+		if !collected {
+			continue
+		}
+		result = append(result, buildRDMADeviceUsage(rdma, txBandwidth, rxBandwidth))
+	}
+	return result, nil
+}
+
+func buildRDMADeviceUsage(rdma koordletutil.RDMADeviceInfo, txBandwidth, rxBandwidth float64) schedulingv1alpha1.DeviceInfo {
+	return schedulingv1alpha1.DeviceInfo{
+		UUID:  rdma.ID,
+		Minor: pointer.Int32(rdma.Minor),
+		Type:  schedulingv1alpha1.RDMA,
+		Resources: map[corev1.ResourceName]resource.Quantity{
+			apiext.ResourceRDMATransmit: *resource.NewQuantity(int64(txBandwidth), resource.DecimalSI),
+			apiext.ResourceRDMAReceive:  *resource.NewQuantity(int64(rxBandwidth), resource.DecimalSI),
+		},
+	}
+}
+
+// queryAggregateValue returns the aggregated value of the metric, and whether any sample is collected.
+func queryAggregateValue(querier metriccache.Querier, resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string,
+	aggregate metriccache.AggregationType) (float64, bool, error) {
+	aggregateResult, err := doQuery(querier, resource, properties)
+	if err != nil {
+		return 0, false, err
+	}
+	if aggregateResult.Count() == 0 {
+		return 0, false, nil
+	}
+	value, err := aggregateResult.Value(aggregate)
+	if err != nil {
+		return 0, false, err
+	}
+	return value, true, nil
 }
 
 func metricsInColdStart(queryStart, queryEnd time.Time, duration time.Duration) bool {
@@ -551,7 +797,7 @@ func (r *nodeMetricInformer) collectNodeGPUMetric(queryparam metriccache.QueryPa
 		}
 		memoryRatioRaw := 100 * memUsage / float64(gpu.MemoryTotal)
 		minor := gpu.Minor
-		result = append(result, schedulingv1alpha1.DeviceInfo{
+		device := schedulingv1alpha1.DeviceInfo{
 			UUID:  gpu.UUID,
 			Minor: pointer.Int32(minor),
 			Type:  schedulingv1alpha1.GPU,
@@ -561,7 +807,17 @@ func (r *nodeMetricInformer) collectNodeGPUMetric(queryparam metriccache.QueryPa
 				apiext.ResourceGPUMemory:      *resource.NewQuantity(int64(memUsage), resource.BinarySI),
 				apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(int64(memoryRatioRaw), resource.DecimalSI),
 			},
-		})
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.DCGMCollector) {
+			properties := metriccache.MetricPropertiesFunc.GPU(fmt.Sprintf("%d", gpu.Minor), gpu.UUID)
+			if err = fillGPUProfilingMetrics(querier, queryparam.Aggregate, &device, nodeGPUProfilingResources, properties); err != nil {
+				return result, err
+			}
+			if err = fillGPUXidError(querier, &device, properties); err != nil {
+				return result, err
+			}
+		}
+		result = append(result, device)
 	}
 
 	return result, nil
@@ -691,23 +947,16 @@ func (r *nodeMetricInformer) collectPodMetric(podMeta *statesinformer.PodMeta, q
 		return nil, err
 	}
 
-	var memAggregateResult metriccache.AggregateResult
+	podMemMetric, containerMemMetric := metriccache.PodMemUsageMetric, metriccache.ContainerMemUsageMetric
 	nodeMemoryCollectPolicy := *r.getNodeMetricSpec().CollectPolicy.NodeMemoryCollectPolicy
 	if nodeMemoryCollectPolicy == slov1alpha1.UsageWithHotPageCache && system.GetIsStartColdMemory() {
-		memAggregateResult, err = doQuery(querier, metriccache.PodMemoryWithHotPageUsageMetric, metriccache.MetricPropertiesFunc.Pod(podUID))
-		if err != nil {
-			return nil, err
-		}
+		podMemMetric, containerMemMetric = metriccache.PodMemoryWithHotPageUsageMetric, metriccache.ContainerMemoryWithHotPageUsageMetric
 	} else if nodeMemoryCollectPolicy == slov1alpha1.UsageWithPageCache {
-		memAggregateResult, err = doQuery(querier, metriccache.PodMemoryUsageWithPageCacheMetric, metriccache.MetricPropertiesFunc.Pod(podUID))
-		if err != nil {
-			return nil, err
-		}
-	} else { // slov1alpha1.UsageWithoutPageCache
-		memAggregateResult, err = doQuery(querier, metriccache.PodMemUsageMetric, metriccache.MetricPropertiesFunc.Pod(podUID))
-		if err != nil {
-			return nil, err
-		}
+		podMemMetric, containerMemMetric = metriccache.PodMemoryUsageWithPageCacheMetric, metriccache.ContainerMemoryUsageWithPageCacheMetric
+	} // else slov1alpha1.UsageWithoutPageCache
+	memAggregateResult, err := doQuery(querier, podMemMetric, metriccache.MetricPropertiesFunc.Pod(podUID))
+	if err != nil {
+		return nil, err
 	}
 	memUsed, err := memAggregateResult.Value(queryParam.Aggregate)
 	if err != nil {
@@ -725,11 +974,55 @@ func (r *nodeMetricInformer) collectPodMetric(podMeta *statesinformer.PodMeta, q
 				corev1.ResourceMemory: *resource.NewQuantity(int64(memUsed), resource.BinarySI),
 			},
 		},
+		ContainersMetric: collectContainerMetrics(querier, pod, containerMemMetric, queryParam.Aggregate),
 	}
 
 	return podMetric, nil
 }
 
+// collectContainerMetrics returns the cpu and memory usage of the running containers of the pod.
+// The containers without the usage in the metric cache are skipped.
+func collectContainerMetrics(querier metriccache.Querier, pod *corev1.Pod, memMetric metriccache.MetricResource,
+	aggregateType metriccache.AggregationType) []slov1alpha1.ContainerMetricInfo {
+	var containerMetrics []slov1alpha1.ContainerMetricInfo
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.ContainerID == "" {
+			continue
+		}
+		properties := metriccache.MetricPropertiesFunc.Container(containerStatus.ContainerID)
+		cpuAggregateResult, err := doQuery(querier, metriccache.ContainerCPUUsageMetric, properties)
+		if err != nil {
+			klog.V(5).Infof("failed to query cpu usage of container %s/%s/%s, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		cpuUsed, err := cpuAggregateResult.Value(aggregateType)
+		if err != nil {
+			klog.V(5).Infof("failed to get cpu usage of container %s/%s/%s, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		memAggregateResult, err := doQuery(querier, memMetric, properties)
+		if err != nil {
+			klog.V(5).Infof("failed to query memory usage of container %s/%s/%s, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		memUsed, err := memAggregateResult.Value(aggregateType)
+		if err != nil {
+			klog.V(5).Infof("failed to get memory usage of container %s/%s/%s, err: %v", pod.Namespace, pod.Name, containerStatus.Name, err)
+			continue
+		}
+		containerMetrics = append(containerMetrics, slov1alpha1.ContainerMetricInfo{
+			Name: containerStatus.Name,
+			Usage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(int64(cpuUsed*1000), resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(int64(memUsed), resource.BinarySI),
+				},
+			},
+		})
+	}
+	return containerMetrics
+}
+
 func (r *nodeMetricInformer) collectHostAppMetric(hostApp *slov1alpha1.HostApplicationSpec, queryParam metriccache.QueryParam) (*slov1alpha1.HostApplicationMetricInfo, error) {
 	if hostApp == nil {
 		return nil, fmt.Errorf("invalid nil host application")
@@ -817,7 +1110,7 @@ func (r *nodeMetricInformer) collectPodGPUMetric(queryparam metriccache.QueryPar
 		}
 		memoryRatioRaw := 100 * memUsage / float64(gpu.MemoryTotal)
 		minor := gpu.Minor
-		result = append(result, schedulingv1alpha1.DeviceInfo{
+		device := schedulingv1alpha1.DeviceInfo{
 			UUID:  gpu.UUID,
 			Minor: pointer.Int32(minor),
 			Type:  schedulingv1alpha1.GPU,
@@ -827,12 +1120,81 @@ func (r *nodeMetricInformer) collectPodGPUMetric(queryparam metriccache.QueryPar
 				apiext.ResourceGPUMemory:      *resource.NewQuantity(int64(memUsage), resource.BinarySI),
 				apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(int64(memoryRatioRaw), resource.DecimalSI),
 			},
-		})
+		}
+		if features.DefaultKoordletFeatureGate.Enabled(features.DCGMCollector) {
+			if err = fillGPUProfilingMetrics(querier, queryparam.Aggregate, &device, podGPUProfilingResources, properties); err != nil {
+				return result, err
+			}
+		}
+		result = append(result, device)
 	}
 
 	return result, nil
 }
 
+type gpuProfilingResource struct {
+	name   corev1.ResourceName
+	metric metriccache.MetricResource
+	// scale converts the metric value to the resource quantity, e.g. the ratio to the percentage
+	scale float64
+}
+
+var (
+	nodeGPUProfilingResources = []gpuProfilingResource{
+		{name: apiext.ResourceGPUSMActive, metric: metriccache.NodeGPUSMActiveMetric, scale: 100},
+		{name: apiext.ResourceGPUMemoryBandwidthRatio, metric: metriccache.NodeGPUDRAMActiveMetric, scale: 100},
+		{name: apiext.ResourceGPUNVLinkTransmit, metric: metriccache.NodeGPUNVLinkTxBytesMetric, scale: 1},
+		{name: apiext.ResourceGPUNVLinkReceive, metric: metriccache.NodeGPUNVLinkRxBytesMetric, scale: 1},
+	}
+	podGPUProfilingResources = []gpuProfilingResource{
+		{name: apiext.ResourceGPUSMActive, metric: metriccache.PodGPUSMActiveMetric, scale: 100},
+		{name: apiext.ResourceGPUMemoryBandwidthRatio, metric: metriccache.PodGPUDRAMActiveMetric, scale: 100},
+	}
+)
+
+// fillGPUProfilingMetrics fills the profiling metrics collected from the DCGM into the device usage, and skips the
+// metrics not collected.
+func fillGPUProfilingMetrics(querier metriccache.Querier, aggregate metriccache.AggregationType, device *schedulingv1alpha1.DeviceInfo,
+	profilingResources []gpuProfilingResource, properties map[metriccache.MetricProperty]string) error {
+	for _, r := range profilingResources {
+		aggregateResult, err := doQuery(querier, r.metric, properties)
+		if err != nil {
+			return err
+		}
+		if aggregateResult.Count() == 0 {
+			continue
+		}
+		value, err := aggregateResult.Value(aggregate)
+		if err != nil {
+			return err
+		}
+		device.Resources[r.name] = *resource.NewQuantity(int64(value*r.scale), resource.DecimalSI)
+	}
+	return nil
+}
+
+// fillGPUXidError labels the last Xid error of the GPU in the query window on the device usage if any.
+func fillGPUXidError(querier metriccache.Querier, device *schedulingv1alpha1.DeviceInfo, properties map[metriccache.MetricProperty]string) error {
+	aggregateResult, err := doQuery(querier, metriccache.NodeGPUXidErrorMetric, properties)
+	if err != nil {
+		return err
+	}
+	if aggregateResult.Count() == 0 {
+		return nil
+	}
+	xid, err := aggregateResult.Value(metriccache.AggregationTypeLast)
+	if err != nil {
+		return err
+	}
+	if xid > 0 {
+		if device.Labels == nil {
+			device.Labels = map[string]string{}
+		}
+		device.Labels[apiext.LabelGPUXidError] = strconv.FormatInt(int64(xid), 10)
+	}
+	return nil
+}
+
 func (r *nodeMetricInformer) fillGPUMetrics(queryparam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string, gpus koordletutil.GPUDevices) {
 	podGPUMetrics, err := r.collectPodGPUMetric(queryparam, uid, gpus)
 	if err != nil {
@@ -843,6 +1205,188 @@ func (r *nodeMetricInformer) fillGPUMetrics(queryparam metriccache.QueryParam, i
 	info.PodUsage.Devices = podGPUMetrics
 }
 
+func (r *nodeMetricInformer) fillRDMAMetrics(queryparam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string, rdmas koordletutil.RDMADevices) {
+	podRDMAMetrics, err := r.collectPodRDMAMetric(queryparam, uid, rdmas)
+	if err != nil {
+		klog.Warningf("collect pod UID(%s) rdma metric failed, error: %v", uid, err)
+		return
+	}
+
+	info.PodUsage.Devices = append(info.PodUsage.Devices, podRDMAMetrics...)
+}
+
+// fillNetworkMetrics reports the network bandwidth, drops and tcp retransmissions of the pod in the pod usage, so
+// the network-aware scheduling and suppression can consume them.
+func (r *nodeMetricInformer) fillNetworkMetrics(queryparam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string) {
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get pod network metric querier failed, error %v", err)
+		return
+	}
+	defer querier.Close()
+	for _, m := range []struct {
+		networkType  metriccache.MetricPropertyValue
+		resourceName corev1.ResourceName
+		milli        bool
+	}{
+		{networkType: metriccache.NetworkTypeIngressBPS, resourceName: apiext.ResourceNetworkIngress},
+		{networkType: metriccache.NetworkTypeEgressBPS, resourceName: apiext.ResourceNetworkEgress},
+		{networkType: metriccache.NetworkTypeDrops, resourceName: apiext.ResourceNetworkDrops, milli: true},
+		{networkType: metriccache.NetworkTypeTCPRetransmits, resourceName: apiext.ResourceNetworkTCPRetransmits, milli: true},
+	} {
+		value, collected, err := queryAggregateValue(querier, metriccache.PodNetworkMetric,
+			metriccache.MetricPropertiesFunc.PodNetwork(uid, string(m.networkType)), queryparam.Aggregate)
+		if err != nil {
+			klog.Warningf("collect pod UID(%s) network metric %s failed, error: %v", uid, m.networkType, err)
+			continue
+		}
+		if !collected {
+			continue
+		}
+		if info.PodUsage.ResourceList == nil {
+			info.PodUsage.ResourceList = corev1.ResourceList{}
+		}
+		if m.milli {
+			info.PodUsage.ResourceList[m.resourceName] = *resource.NewMilliQuantity(int64(value*1000), resource.DecimalSI)
+		} else {
+			info.PodUsage.ResourceList[m.resourceName] = *resource.NewQuantity(int64(value), resource.DecimalSI)
+		}
+	}
+}
+
+// collectNetworkLatency reports the average network round-trip time and the latency SLO violation percent of
+// each QoS class probed by the network latency prober.
+func (r *nodeMetricInformer) collectNetworkLatency(queryparam metriccache.QueryParam) []slov1alpha1.QoSNetworkLatencyInfo {
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get node network latency querier failed, error %v", err)
+		return nil
+	}
+	defer querier.Close()
+
+	var networkLatency []slov1alpha1.QoSNetworkLatencyInfo
+	for _, qos := range []apiext.QoSClass{apiext.QoSLSE, apiext.QoSLSR, apiext.QoSLS, apiext.QoSBE, apiext.QoSSystem} {
+		properties := metriccache.MetricPropertiesFunc.QoS(string(qos))
+		violationRatio, collected, err := queryAggregateValue(querier, metriccache.NodeQoSNetworkLatencySLOViolationMetric,
+			properties, queryparam.Aggregate)
+		if err != nil {
+			klog.Warningf("collect network latency slo violation of QoS %s failed, error: %v", qos, err)
+			continue
+		}
+		if !collected {
+			continue
+		}
+		info := slov1alpha1.QoSNetworkLatencyInfo{
+			QoS:                 qos,
+			SLOViolationPercent: int64(math.Round(violationRatio * 100)),
+		}
+		// the rtt is missing if all probes of the QoS class failed
+		rtt, collected, err := queryAggregateValue(querier, metriccache.NodeQoSNetworkLatencyMetric, properties, queryparam.Aggregate)
+		if err != nil {
+			klog.Warningf("collect network latency of QoS %s failed, error: %v", qos, err)
+		} else if collected {
+			info.RTT = &metav1.Duration{Duration: time.Duration(rtt * float64(time.Second))}
+		}
+		networkLatency = append(networkLatency, info)
+	}
+	return networkLatency
+}
+
+// fillNetworkLatencyMetrics reports the probed network round-trip time of the pod in the pod usage.
+func (r *nodeMetricInformer) fillNetworkLatencyMetrics(queryparam metriccache.QueryParam, info *slov1alpha1.PodMetricInfo, uid string) {
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get pod network latency querier failed, error %v", err)
+		return
+	}
+	defer querier.Close()
+	value, collected, err := queryAggregateValue(querier, metriccache.PodNetworkLatencyMetric,
+		metriccache.MetricPropertiesFunc.Pod(uid), queryparam.Aggregate)
+	if err != nil {
+		klog.Warningf("collect pod UID(%s) network latency failed, error: %v", uid, err)
+		return
+	}
+	if !collected {
+		return
+	}
+	if info.PodUsage.ResourceList == nil {
+		info.PodUsage.ResourceList = corev1.ResourceList{}
+	}
+	info.PodUsage.ResourceList[apiext.ResourceNetworkLatency] = *resource.NewScaledQuantity(int64(value*1e6), resource.Micro)
+}
+
+// collectMemoryBandwidth reports the memory bandwidth measured in the resctrl group of each QoS class. The committed
+// bandwidth of a class is the share of the node capacity limited by the MBA percent of its resctrl group.
+func (r *nodeMetricInformer) collectMemoryBandwidth(queryparam metriccache.QueryParam, nodeSLO *slov1alpha1.NodeSLO) *slov1alpha1.NodeMemoryBandwidthInfo {
+	querier, err := r.metricCache.Querier(*queryparam.Start, *queryparam.End)
+	if err != nil {
+		klog.V(5).Infof("get node memory bandwidth querier failed, error %v", err)
+		return nil
+	}
+	defer querier.Close()
+
+	var strategy *slov1alpha1.ResourceQOSStrategy
+	if nodeSLO != nil {
+		strategy = nodeSLO.Spec.ResourceQOSStrategy
+	}
+	info := &slov1alpha1.NodeMemoryBandwidthInfo{}
+	var totalUsed int64
+	for _, qos := range []apiext.QoSClass{apiext.QoSLSR, apiext.QoSLS, apiext.QoSBE} {
+		used, collected, err := queryAggregateValue(querier, metriccache.ResctrlMemoryBandwidthMetric,
+			metriccache.MetricPropertiesFunc.QoS(string(qos)), queryparam.Aggregate)
+		if err != nil {
+			klog.Warningf("collect memory bandwidth of QoS %s failed, error: %v", qos, err)
+			continue
+		}
+		if !collected {
+			continue
+		}
+		classInfo := slov1alpha1.QoSMemoryBandwidthInfo{
+			QoS:  qos,
+			Used: *resource.NewQuantity(int64(used), resource.BinarySI),
+		}
+		if r.memoryBandwidthCapacity > 0 {
+			committed := r.memoryBandwidthCapacity * getResctrlMBAPercent(strategy, qos) / 100
+			classInfo.Committed = resource.NewQuantity(committed, resource.BinarySI)
+		}
+		info.Classes = append(info.Classes, classInfo)
+		totalUsed += int64(used)
+	}
+	if len(info.Classes) <= 0 {
+		return nil
+	}
+	info.Used = *resource.NewQuantity(totalUsed, resource.BinarySI)
+	if r.memoryBandwidthCapacity > 0 {
+		info.Capacity = resource.NewQuantity(r.memoryBandwidthCapacity, resource.BinarySI)
+	}
+	return info
+}
+
+// getResctrlMBAPercent returns the MBA percent of the QoS class, which is 100 if the resctrl qos is disabled.
+func getResctrlMBAPercent(strategy *slov1alpha1.ResourceQOSStrategy, qos apiext.QoSClass) int64 {
+	if strategy == nil {
+		return 100
+	}
+	var resourceQoS *slov1alpha1.ResourceQOS
+	switch qos {
+	case apiext.QoSLSR:
+		resourceQoS = strategy.LSRClass
+	case apiext.QoSLS:
+		resourceQoS = strategy.LSClass
+	case apiext.QoSBE:
+		resourceQoS = strategy.BEClass
+	}
+	if resourceQoS == nil || resourceQoS.ResctrlQOS == nil || resourceQoS.ResctrlQOS.Enable == nil ||
+		!*resourceQoS.ResctrlQOS.Enable {
+		return 100
+	}
+	mbaPercent := resourceQoS.ResctrlQOS.MBAPercent
+	if mbaPercent == nil || *mbaPercent <= 0 || *mbaPercent > 100 {
+		return 100
+	}
+	return *mbaPercent
+}
+
 const (
 	statusUpdateQPS   = 0.1
 	statusUpdateBurst = 2
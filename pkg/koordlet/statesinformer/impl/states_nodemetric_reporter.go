@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+const (
+	// NodeMetricReportTransportCRD updates the node metric status into the NodeMetric CR directly.
+	NodeMetricReportTransportCRD = "crd"
+	// NodeMetricReportTransportGRPC streams the node metric status to the koord-manager, which aggregates the reports
+	// and writes the summaries into the NodeMetric CR, reducing the updates to the apiserver in large clusters.
+	NodeMetricReportTransportGRPC = "grpc"
+)
+
+// nodeMetricStatusReporter reports the node metric status via the transport other than the NodeMetric CR.
+type nodeMetricStatusReporter interface {
+	report(newStatus *slov1alpha1.NodeMetricStatus) error
+}
+
+var _ nodeMetricStatusReporter = &grpcStatusReporter{}
+
+type grpcStatusReporter struct {
+	nodeName string
+	client   metricreport.NodeMetricReportServiceClient
+	stream   metricreport.NodeMetricReportService_ReportClient
+	cancel   context.CancelFunc
+}
+
+func newGRPCStatusReporter(nodeName string, conn grpc.ClientConnInterface) *grpcStatusReporter {
+	return &grpcStatusReporter{
+		nodeName: nodeName,
+		client:   metricreport.NewNodeMetricReportServiceClient(conn),
+	}
+}
+
+// report sends the status in the long-lived report stream, and the stream is reopened at the next report if it broke.
+func (g *grpcStatusReporter) report(newStatus *slov1alpha1.NodeMetricStatus) error {
+	if g.stream == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := g.client.Report(ctx)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("failed to open node metric report stream, err: %w", err)
+		}
+		g.stream, g.cancel = stream, cancel
+	}
+
+	err := g.stream.Send(&metricreport.NodeMetricReport{
+		NodeName: g.nodeName,
+		Status:   newStatus,
+	})
+	if err != nil {
+		g.cancel()
+		g.stream, g.cancel = nil, nil
+		return fmt.Errorf("failed to send node metric report, err: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+type fakeReportServer struct {
+	metricreport.UnimplementedNodeMetricReportServiceServer
+	reports chan *metricreport.NodeMetricReport
+}
+
+func (s *fakeReportServer) Report(stream metricreport.NodeMetricReportService_ReportServer) error {
+	for {
+		report, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&metricreport.NodeMetricReportResponse{})
+		}
+		if err != nil {
+			return err
+		}
+		s.reports <- report
+	}
+}
+
+type fakeReportClient struct {
+	opened int
+	stream *fakeReportStream
+}
+
+func (c *fakeReportClient) Report(ctx context.Context, opts ...grpc.CallOption) (metricreport.NodeMetricReportService_ReportClient, error) {
+	c.opened++
+	return c.stream, nil
+}
+
+type fakeReportStream struct {
+	metricreport.NodeMetricReportService_ReportClient
+	sendErr error
+}
+
+func (s *fakeReportStream) Send(*metricreport.NodeMetricReport) error {
+	return s.sendErr
+}
+
+func Test_grpcStatusReporter_report(t *testing.T) {
+	t.Run("report to server", func(t *testing.T) {
+		listener := bufconn.Listen(1024 * 1024)
+		server := grpc.NewServer(metricreport.ForceServerCodec())
+		fakeSrv := &fakeReportServer{reports: make(chan *metricreport.NodeMetricReport, 2)}
+		metricreport.RegisterNodeMetricReportServiceServer(server, fakeSrv)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		defer server.Stop()
+		conn, err := grpc.DialContext(context.TODO(), "bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()))
+		assert.NoError(t, err)
+		defer conn.Close()
+
+		r := newGRPCStatusReporter("test-node", conn)
+		updateTime := metav1.NewTime(time.Unix(1700000000, 0))
+		assert.NoError(t, r.report(&slov1alpha1.NodeMetricStatus{UpdateTime: &updateTime}))
+		assert.NoError(t, r.report(&slov1alpha1.NodeMetricStatus{UpdateTime: &updateTime}))
+		for i := 0; i < 2; i++ {
+			select {
+			case got := <-fakeSrv.reports:
+				assert.Equal(t, "test-node", got.NodeName)
+				assert.True(t, updateTime.Equal(got.Status.UpdateTime))
+			case <-time.After(5 * time.Second):
+				t.Fatal("wait for report timeout")
+			}
+		}
+	})
+	t.Run("reopen stream after send failed", func(t *testing.T) {
+		fakeClient := &fakeReportClient{stream: &fakeReportStream{sendErr: fmt.Errorf("expected error")}}
+		r := &grpcStatusReporter{nodeName: "test-node", client: fakeClient}
+		assert.Error(t, r.report(&slov1alpha1.NodeMetricStatus{}))
+		assert.Nil(t, r.stream)
+
+		fakeClient.stream.sendErr = nil
+		assert.NoError(t, r.report(&slov1alpha1.NodeMetricStatus{}))
+		assert.NoError(t, r.report(&slov1alpha1.NodeMetricStatus{}))
+		assert.Equal(t, 2, fakeClient.opened)
+	})
+}
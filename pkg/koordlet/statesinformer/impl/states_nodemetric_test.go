@@ -42,12 +42,15 @@ import (
 	clientsetv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1"
 	fakeclientslov1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/typed/slo/v1alpha1/fake"
 	listerv1alpha1 "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	mockmetriccache "github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache/mockmetriccache"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/prediction"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 var _ listerv1alpha1.NodeMetricLister = &fakeNodeMetricLister{}
@@ -867,6 +870,215 @@ func Test_nodeMetricInformer_collectNodeGPUMetric(t *testing.T) {
 	}
 }
 
+func Test_nodeMetricInformer_collectGPUMetricWithDCGM(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, features.DefaultKoordletFeatureGate, features.DCGMCollector, true)()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	duration := now.Sub(startTime)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		End:       &now,
+		Start:     &startTime,
+	}
+	gpus := util.GPUDevices{
+		{Minor: 0, UUID: "1", MemoryTotal: 8000},
+		{Minor: 1, UUID: "2", MemoryTotal: 10000},
+	}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	buildResult := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string, value float64, count int) {
+		queryMeta, err := resource.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		if count > 0 {
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, queryMeta, value, duration)
+			return
+		}
+		result := mockmetriccache.NewMockAggregateResult(ctrl)
+		result.EXPECT().Count().Return(0).AnyTimes()
+		mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+		mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+	}
+
+	// gpu 0 has all the dcgm metrics, and gpu 1 has only the sm active and the xid error
+	gpu0, gpu1 := metriccache.MetricPropertiesFunc.GPU("0", "1"), metriccache.MetricPropertiesFunc.GPU("1", "2")
+	buildResult(metriccache.NodeGPUCoreUsageMetric, gpu0, 80, 1)
+	buildResult(metriccache.NodeGPUMemUsageMetric, gpu0, 800, 1)
+	buildResult(metriccache.NodeGPUSMActiveMetric, gpu0, 0.6, 1)
+	buildResult(metriccache.NodeGPUDRAMActiveMetric, gpu0, 0.3, 1)
+	buildResult(metriccache.NodeGPUNVLinkTxBytesMetric, gpu0, 1024, 1)
+	buildResult(metriccache.NodeGPUNVLinkRxBytesMetric, gpu0, 2048, 1)
+	buildResult(metriccache.NodeGPUXidErrorMetric, gpu0, 0, 1)
+	buildResult(metriccache.NodeGPUCoreUsageMetric, gpu1, 10, 1)
+	buildResult(metriccache.NodeGPUMemUsageMetric, gpu1, 100, 1)
+	buildResult(metriccache.NodeGPUSMActiveMetric, gpu1, 0.05, 1)
+	buildResult(metriccache.NodeGPUDRAMActiveMetric, gpu1, 0, 0)
+	buildResult(metriccache.NodeGPUNVLinkTxBytesMetric, gpu1, 0, 0)
+	buildResult(metriccache.NodeGPUNVLinkRxBytesMetric, gpu1, 0, 0)
+	buildResult(metriccache.NodeGPUXidErrorMetric, gpu1, 79, 1)
+
+	podGPU0 := metriccache.MetricPropertiesFunc.PodGPU("test-pod", "0", "1")
+	buildResult(metriccache.PodGPUCoreUsageMetric, podGPU0, 80, 1)
+	buildResult(metriccache.PodGPUMemUsageMetric, podGPU0, 800, 1)
+	buildResult(metriccache.PodGPUSMActiveMetric, podGPU0, 0.6, 1)
+	buildResult(metriccache.PodGPUDRAMActiveMetric, podGPU0, 0.3, 1)
+	buildResult(metriccache.PodGPUCoreUsageMetric, metriccache.MetricPropertiesFunc.PodGPU("test-pod", "1", "2"), 0, 0)
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+	got, err := r.collectNodeGPUMetric(queryParam, gpus)
+	assert.NoError(t, err)
+	want := []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:  "1",
+			Minor: pointer.Int32(0),
+			Type:  schedulingv1alpha1.GPU,
+			Resources: map[v1.ResourceName]resource.Quantity{
+				apiext.ResourceGPUCore:                 *resource.NewQuantity(80, resource.DecimalSI),
+				apiext.ResourceGPUMemory:               *resource.NewQuantity(800, resource.BinarySI),
+				apiext.ResourceGPUMemoryRatio:          *resource.NewQuantity(10, resource.DecimalSI),
+				apiext.ResourceGPUSMActive:             *resource.NewQuantity(60, resource.DecimalSI),
+				apiext.ResourceGPUMemoryBandwidthRatio: *resource.NewQuantity(30, resource.DecimalSI),
+				apiext.ResourceGPUNVLinkTransmit:       *resource.NewQuantity(1024, resource.DecimalSI),
+				apiext.ResourceGPUNVLinkReceive:        *resource.NewQuantity(2048, resource.DecimalSI),
+			},
+		},
+		{
+			UUID:   "2",
+			Minor:  pointer.Int32(1),
+			Type:   schedulingv1alpha1.GPU,
+			Labels: map[string]string{apiext.LabelGPUXidError: "79"},
+			Resources: map[v1.ResourceName]resource.Quantity{
+				apiext.ResourceGPUCore:        *resource.NewQuantity(10, resource.DecimalSI),
+				apiext.ResourceGPUMemory:      *resource.NewQuantity(100, resource.BinarySI),
+				apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(1, resource.DecimalSI),
+				apiext.ResourceGPUSMActive:    *resource.NewQuantity(5, resource.DecimalSI),
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	gotPod, err := r.collectPodGPUMetric(queryParam, "test-pod", gpus)
+	assert.NoError(t, err)
+	wantPod := []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:  "1",
+			Minor: pointer.Int32(0),
+			Type:  schedulingv1alpha1.GPU,
+			Resources: map[v1.ResourceName]resource.Quantity{
+				apiext.ResourceGPUCore:                 *resource.NewQuantity(80, resource.DecimalSI),
+				apiext.ResourceGPUMemory:               *resource.NewQuantity(800, resource.BinarySI),
+				apiext.ResourceGPUMemoryRatio:          *resource.NewQuantity(10, resource.DecimalSI),
+				apiext.ResourceGPUSMActive:             *resource.NewQuantity(60, resource.DecimalSI),
+				apiext.ResourceGPUMemoryBandwidthRatio: *resource.NewQuantity(30, resource.DecimalSI),
+			},
+		},
+	}
+	assert.Equal(t, wantPod, gotPod)
+}
+
+func Test_nodeMetricInformer_collectRDMAMetric(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/1/counters/port_xmit_data", "0")
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/2/counters/port_xmit_data", "0")
+	helper.WriteFileContents("class/infiniband/mlx5_1/ports/1/counters/port_xmit_data", "0")
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	duration := now.Sub(startTime)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		End:       &now,
+		Start:     &startTime,
+	}
+	rdmas := util.RDMADevices{
+		{ID: "0000:1f:00.0", RDMAResources: []string{"mlx5_0"}},
+		{ID: "0000:90:00.0", RDMAResources: []string{"mlx5_1"}},
+	}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	buildResult := func(resource metriccache.MetricResource, properties map[metriccache.MetricProperty]string, value float64, count int) {
+		queryMeta, err := resource.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		if count > 0 {
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, queryMeta, value, duration)
+			return
+		}
+		result := mockmetriccache.NewMockAggregateResult(ctrl)
+		result.EXPECT().Count().Return(0).AnyTimes()
+		mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+		mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+	}
+
+	// mlx5_0 has two ports, and mlx5_1 has no metric collected
+	port1, port2 := metriccache.MetricPropertiesFunc.RDMAPort("mlx5_0", "1"), metriccache.MetricPropertiesFunc.RDMAPort("mlx5_0", "2")
+	buildResult(metriccache.NodeRDMATxBandwidthMetric, port1, 1000, 1)
+	buildResult(metriccache.NodeRDMARxBandwidthMetric, port1, 2000, 1)
+	buildResult(metriccache.NodeRDMATxBandwidthMetric, port2, 500, 1)
+	buildResult(metriccache.NodeRDMARxBandwidthMetric, port2, 0, 1)
+	port3 := metriccache.MetricPropertiesFunc.RDMAPort("mlx5_1", "1")
+	buildResult(metriccache.NodeRDMATxBandwidthMetric, port3, 0, 0)
+	buildResult(metriccache.NodeRDMARxBandwidthMetric, port3, 0, 0)
+
+	podRDMA0, podRDMA1 := metriccache.MetricPropertiesFunc.PodRDMA("test-pod", "mlx5_0"), metriccache.MetricPropertiesFunc.PodRDMA("test-pod", "mlx5_1")
+	buildResult(metriccache.PodRDMATxBandwidthMetric, podRDMA0, 300, 1)
+	buildResult(metriccache.PodRDMARxBandwidthMetric, podRDMA0, 600, 1)
+	buildResult(metriccache.PodRDMATxBandwidthMetric, podRDMA1, 0, 0)
+	buildResult(metriccache.PodRDMARxBandwidthMetric, podRDMA1, 0, 0)
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+	got, err := r.collectNodeRDMAMetric(queryParam, rdmas)
+	assert.NoError(t, err)
+	assert.Equal(t, []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:  "0000:1f:00.0",
+			Minor: pointer.Int32(0),
+			Type:  schedulingv1alpha1.RDMA,
+			Resources: map[v1.ResourceName]resource.Quantity{
+				apiext.ResourceRDMATransmit: *resource.NewQuantity(1500, resource.DecimalSI),
+				apiext.ResourceRDMAReceive:  *resource.NewQuantity(2000, resource.DecimalSI),
+			},
+		},
+	}, got)
+
+	gotPod, err := r.collectPodRDMAMetric(queryParam, "test-pod", rdmas)
+	assert.NoError(t, err)
+	assert.Equal(t, []schedulingv1alpha1.DeviceInfo{
+		{
+			UUID:  "0000:1f:00.0",
+			Minor: pointer.Int32(0),
+			Type:  schedulingv1alpha1.RDMA,
+			Resources: map[v1.ResourceName]resource.Quantity{
+				apiext.ResourceRDMATransmit: *resource.NewQuantity(300, resource.DecimalSI),
+				apiext.ResourceRDMAReceive:  *resource.NewQuantity(600, resource.DecimalSI),
+			},
+		},
+	}, gotPod)
+}
+
 func Test_nodeMetricInformer_collectNodeMetric(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -983,8 +1195,10 @@ func Test_nodeMetricInformer_collectPodMetric(t *testing.T) {
 		pod                 *statesinformer.PodMeta
 	}
 	type samples struct {
-		CPUUsed float64
-		MemUsed float64
+		CPUUsed          float64
+		MemUsed          float64
+		ContainerCPUUsed float64
+		ContainerMemUsed float64
 	}
 	tests := []struct {
 		name    string
@@ -1101,6 +1315,62 @@ func Test_nodeMetricInformer_collectPodMetric(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "report container usage",
+			args: args{
+				queryparam:          metriccache.QueryParam{Start: &startTime, End: &now, Aggregate: metriccache.AggregationTypeAVG},
+				memoryCollectPolicy: slov1alpha1.UsageWithoutPageCache,
+				pod: &statesinformer.PodMeta{
+					Pod: &v1.Pod{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      "test-pod",
+							Namespace: "default",
+							UID:       "test-pod",
+						},
+						Status: v1.PodStatus{
+							ContainerStatuses: []v1.ContainerStatus{
+								{
+									Name:        "test-container",
+									ContainerID: "containerd://test-container",
+								},
+								{
+									Name: "test-container-not-started",
+								},
+							},
+						},
+					},
+				},
+			},
+			samples: samples{
+				CPUUsed:          2,
+				MemUsed:          10 * 1024 * 1024 * 1024,
+				ContainerCPUUsed: 1.5,
+				ContainerMemUsed: 8 * 1024 * 1024 * 1024,
+			},
+			want: &slov1alpha1.PodMetricInfo{
+				Name:      "test-pod",
+				Namespace: "default",
+				Priority:  apiext.PriorityBatch,
+				QoS:       apiext.QoSBE,
+				PodUsage: slov1alpha1.ResourceMap{
+					ResourceList: v1.ResourceList{
+						v1.ResourceCPU:    *resource.NewMilliQuantity(2000, resource.DecimalSI),
+						v1.ResourceMemory: *resource.NewQuantity(10*1024*1024*1024, resource.BinarySI),
+					},
+				},
+				ContainersMetric: []slov1alpha1.ContainerMetricInfo{
+					{
+						Name: "test-container",
+						Usage: slov1alpha1.ResourceMap{
+							ResourceList: v1.ResourceList{
+								v1.ResourceCPU:    *resource.NewMilliQuantity(1500, resource.DecimalSI),
+								v1.ResourceMemory: *resource.NewQuantity(8*1024*1024*1024, resource.BinarySI),
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1130,6 +1400,18 @@ func Test_nodeMetricInformer_collectPodMetric(t *testing.T) {
 			assert.NoError(t, err)
 			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, memQueryMeta, tt.samples.MemUsed, duration)
 
+			for _, containerStatus := range tt.args.pod.Pod.Status.ContainerStatuses {
+				if containerStatus.ContainerID == "" {
+					continue
+				}
+				containerCPUQueryMeta, err := metriccache.ContainerCPUUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Container(containerStatus.ContainerID))
+				assert.NoError(t, err)
+				buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, containerCPUQueryMeta, tt.samples.ContainerCPUUsed, duration)
+				containerMemQueryMeta, err := metriccache.ContainerMemUsageMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Container(containerStatus.ContainerID))
+				assert.NoError(t, err)
+				buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, containerMemQueryMeta, tt.samples.ContainerMemUsed, duration)
+			}
+
 			r := &nodeMetricInformer{
 				metricCache: mockMetricCache,
 				nodeMetric: &slov1alpha1.NodeMetric{
@@ -1147,6 +1429,216 @@ func Test_nodeMetricInformer_collectPodMetric(t *testing.T) {
 	}
 }
 
+func Test_nodeMetricInformer_fillNetworkMetrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	duration := now.Sub(startTime)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		End:       &now,
+		Start:     &startTime,
+	}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().Close().AnyTimes()
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	for networkType, value := range map[metriccache.MetricPropertyValue]float64{
+		metriccache.NetworkTypeIngressBPS:     1000,
+		metriccache.NetworkTypeEgressBPS:      2000,
+		metriccache.NetworkTypeTCPRetransmits: 0.5,
+	} {
+		queryMeta, err := metriccache.PodNetworkMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodNetwork("test-pod", string(networkType)))
+		assert.NoError(t, err)
+		buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, queryMeta, value, duration)
+	}
+	// drops are not collected for the host network pods
+	queryMeta, err := metriccache.PodNetworkMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.PodNetwork("test-pod", string(metriccache.NetworkTypeDrops)))
+	assert.NoError(t, err)
+	result := mockmetriccache.NewMockAggregateResult(ctrl)
+	result.EXPECT().Count().Return(0).AnyTimes()
+	mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+	mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+	info := &slov1alpha1.PodMetricInfo{
+		PodUsage: slov1alpha1.ResourceMap{
+			ResourceList: v1.ResourceList{
+				v1.ResourceCPU: resource.MustParse("1"),
+			},
+		},
+	}
+	r.fillNetworkMetrics(queryParam, info, "test-pod")
+	assert.Equal(t, v1.ResourceList{
+		v1.ResourceCPU:                       resource.MustParse("1"),
+		apiext.ResourceNetworkIngress:        *resource.NewQuantity(1000, resource.DecimalSI),
+		apiext.ResourceNetworkEgress:         *resource.NewQuantity(2000, resource.DecimalSI),
+		apiext.ResourceNetworkTCPRetransmits: *resource.NewMilliQuantity(500, resource.DecimalSI),
+	}, info.PodUsage.ResourceList)
+}
+
+func Test_nodeMetricInformer_collectNetworkLatency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	duration := now.Sub(startTime)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		End:       &now,
+		Start:     &startTime,
+	}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().Close().AnyTimes()
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	buildEmptyQueryResult := func(queryMeta metriccache.MetricMeta) {
+		result := mockmetriccache.NewMockAggregateResult(ctrl)
+		result.EXPECT().Count().Return(0).AnyTimes()
+		mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+		mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+	}
+	for _, qos := range []apiext.QoSClass{apiext.QoSLSE, apiext.QoSLSR, apiext.QoSLS, apiext.QoSBE, apiext.QoSSystem} {
+		properties := metriccache.MetricPropertiesFunc.QoS(string(qos))
+		violationQueryMeta, err := metriccache.NodeQoSNetworkLatencySLOViolationMetric.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		rttQueryMeta, err := metriccache.NodeQoSNetworkLatencyMetric.BuildQueryMeta(properties)
+		assert.NoError(t, err)
+		switch qos {
+		case apiext.QoSLS:
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, violationQueryMeta, 0.25, duration)
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, rttQueryMeta, 0.0015, duration)
+		case apiext.QoSBE:
+			// all probes failed
+			buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, violationQueryMeta, 1, duration)
+			buildEmptyQueryResult(rttQueryMeta)
+		default:
+			buildEmptyQueryResult(violationQueryMeta)
+		}
+	}
+	podQueryMeta, err := metriccache.PodNetworkLatencyMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.Pod("test-pod"))
+	assert.NoError(t, err)
+	buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, podQueryMeta, 0.002, duration)
+
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+	assert.Equal(t, []slov1alpha1.QoSNetworkLatencyInfo{
+		{
+			QoS:                 apiext.QoSLS,
+			RTT:                 &metav1.Duration{Duration: 1500 * time.Microsecond},
+			SLOViolationPercent: 25,
+		},
+		{
+			QoS:                 apiext.QoSBE,
+			SLOViolationPercent: 100,
+		},
+	}, r.collectNetworkLatency(queryParam))
+
+	info := &slov1alpha1.PodMetricInfo{}
+	r.fillNetworkLatencyMetrics(queryParam, info, "test-pod")
+	assert.Equal(t, v1.ResourceList{
+		apiext.ResourceNetworkLatency: *resource.NewScaledQuantity(2000, resource.Micro),
+	}, info.PodUsage.ResourceList)
+}
+
+func Test_nodeMetricInformer_collectMemoryBandwidth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	startTime := now.Add(-time.Second * 120)
+	duration := now.Sub(startTime)
+	queryParam := metriccache.QueryParam{
+		Aggregate: metriccache.AggregationTypeAVG,
+		End:       &now,
+		Start:     &startTime,
+	}
+
+	mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+	mockResultFactory := mockmetriccache.NewMockAggregateResultFactory(ctrl)
+	oldFactory := metriccache.DefaultAggregateResultFactory
+	metriccache.DefaultAggregateResultFactory = mockResultFactory
+	defer func() {
+		metriccache.DefaultAggregateResultFactory = oldFactory
+	}()
+	mockQuerier := mockmetriccache.NewMockQuerier(ctrl)
+	mockQuerier.EXPECT().Close().AnyTimes()
+	mockMetricCache.EXPECT().Querier(gomock.Any(), gomock.Any()).Return(mockQuerier, nil).AnyTimes()
+	for qos, value := range map[apiext.QoSClass]float64{apiext.QoSLSR: 0, apiext.QoSLS: 4 << 30, apiext.QoSBE: 2 << 30} {
+		queryMeta, err := metriccache.ResctrlMemoryBandwidthMetric.BuildQueryMeta(metriccache.MetricPropertiesFunc.QoS(string(qos)))
+		assert.NoError(t, err)
+		if qos == apiext.QoSLSR {
+			result := mockmetriccache.NewMockAggregateResult(ctrl)
+			result.EXPECT().Count().Return(0).AnyTimes()
+			mockResultFactory.EXPECT().New(queryMeta).Return(result).AnyTimes()
+			mockQuerier.EXPECT().Query(queryMeta, gomock.Any(), result).Return(nil).AnyTimes()
+			continue
+		}
+		buildMockQueryResult(ctrl, mockQuerier, mockResultFactory, queryMeta, value, duration)
+	}
+	nodeSLO := &slov1alpha1.NodeSLO{
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+				BEClass: &slov1alpha1.ResourceQOS{
+					ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+						Enable: pointer.Bool(true),
+						ResctrlQOS: slov1alpha1.ResctrlQOS{
+							MBAPercent: pointer.Int64(25),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// the capacity is unknown
+	r := &nodeMetricInformer{
+		metricCache: mockMetricCache,
+	}
+	assert.Equal(t, &slov1alpha1.NodeMemoryBandwidthInfo{
+		Used: *resource.NewQuantity(6<<30, resource.BinarySI),
+		Classes: []slov1alpha1.QoSMemoryBandwidthInfo{
+			{QoS: apiext.QoSLS, Used: *resource.NewQuantity(4<<30, resource.BinarySI)},
+			{QoS: apiext.QoSBE, Used: *resource.NewQuantity(2<<30, resource.BinarySI)},
+		},
+	}, r.collectMemoryBandwidth(queryParam, nodeSLO))
+
+	r.memoryBandwidthCapacity = 16 << 30
+	assert.Equal(t, &slov1alpha1.NodeMemoryBandwidthInfo{
+		Capacity: resource.NewQuantity(16<<30, resource.BinarySI),
+		Used:     *resource.NewQuantity(6<<30, resource.BinarySI),
+		Classes: []slov1alpha1.QoSMemoryBandwidthInfo{
+			{
+				QoS:       apiext.QoSLS,
+				Committed: resource.NewQuantity(16<<30, resource.BinarySI),
+				Used:      *resource.NewQuantity(4<<30, resource.BinarySI),
+			},
+			{
+				QoS:       apiext.QoSBE,
+				Committed: resource.NewQuantity(4<<30, resource.BinarySI),
+				Used:      *resource.NewQuantity(2<<30, resource.BinarySI),
+			},
+		},
+	}, r.collectMemoryBandwidth(queryParam, nodeSLO))
+}
+
 func buildMockQueryResult(ctrl *gomock.Controller, querier *mockmetriccache.MockQuerier, factory *mockmetriccache.MockAggregateResultFactory,
 	queryMeta metriccache.MetricMeta, value float64, duration time.Duration) {
 	result := mockmetriccache.NewMockAggregateResult(ctrl)
@@ -1493,3 +1985,92 @@ func Test_nodeMetricInformer_collectHostAppMetric(t *testing.T) {
 		})
 	}
 }
+
+func Test_collectColocationMetric(t *testing.T) {
+	evictionCount := metrics.GetPodEvictionCount()
+	metrics.RecordPodEviction("default", "test-pod", "evictByCPU")
+	metrics.RecordBESuppressCores("cfsQuota", 2.5)
+
+	got := collectColocationMetric()
+	assert.Equal(t, evictionCount+1, got.PodEvictionCount)
+	assert.NotNil(t, got.BESuppressCPU)
+	assert.Equal(t, int64(2500), got.BESuppressCPU.MilliValue())
+}
+
+func Test_collectZoneMemory(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodeNUMAInfo interface{}
+		exist        bool
+		want         []slov1alpha1.ZoneMemoryInfo
+	}{
+		{
+			name:  "node NUMA info not exist",
+			exist: false,
+			want:  nil,
+		},
+		{
+			name:         "invalid node NUMA info",
+			nodeNUMAInfo: util.GPUDevices{},
+			exist:        true,
+			want:         nil,
+		},
+		{
+			name: "collect zone memory",
+			nodeNUMAInfo: &util.NodeNUMAInfo{
+				NUMAInfos: []util.NUMAInfo{
+					{
+						NUMANodeID: 0,
+						MemInfo: &util.MemInfo{
+							MemTotal:     16 * 1024 * 1024,
+							MemFree:      4 * 1024 * 1024,
+							InactiveFile: 2 * 1024 * 1024,
+						},
+					},
+					{
+						NUMANodeID: 1,
+						MemInfo: &util.MemInfo{
+							MemTotal:     16 * 1024 * 1024,
+							MemFree:      1024 * 1024,
+							InactiveFile: 512 * 1024,
+						},
+					},
+					{
+						NUMANodeID: 2,
+					},
+				},
+			},
+			exist: true,
+			want: []slov1alpha1.ZoneMemoryInfo{
+				{
+					Name: "node-0",
+					Free: resource.MustParse("4Gi"),
+					Cold: resource.MustParse("2Gi"),
+				},
+				{
+					Name: "node-1",
+					Free: resource.MustParse("1Gi"),
+					Cold: resource.MustParse("512Mi"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			mockMetricCache := mockmetriccache.NewMockMetricCache(ctrl)
+			mockMetricCache.EXPECT().Get(metriccache.NodeNUMAInfoKey).Return(tt.nodeNUMAInfo, tt.exist).Times(1)
+			r := &nodeMetricInformer{
+				metricCache: mockMetricCache,
+			}
+			got := r.collectZoneMemory()
+			assert.Equal(t, len(tt.want), len(got))
+			for i := range tt.want {
+				assert.Equal(t, tt.want[i].Name, got[i].Name)
+				assert.Equal(t, tt.want[i].Free.Value(), got[i].Free.Value())
+				assert.Equal(t, tt.want[i].Cold.Value(), got[i].Cold.Value())
+			}
+		})
+	}
+}
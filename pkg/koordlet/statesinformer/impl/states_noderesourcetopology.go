@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	rawerrors "errors"
 	"fmt"
+	"hash/fnv"
 	"os"
 	"reflect"
 	"sort"
@@ -46,11 +47,13 @@ import (
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/topology"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/kubelet"
+	resctrlutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/resctrl"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
@@ -96,6 +99,10 @@ func (n *nodeTopologyStatus) updateNRT(nrt *v1alpha1.NodeResourceTopology) {
 	for k, v := range n.Annotations {
 		nrt.Annotations[k] = v
 	}
+	// remove the sub-pools when they are no longer configured
+	if _, ok := n.Annotations[extension.AnnotationNodeCPUSharedSubPools]; !ok {
+		delete(nrt.Annotations, extension.AnnotationNodeCPUSharedSubPools)
+	}
 
 	nrt.TopologyPolicies = []string{string(n.TopologyPolicy)}
 
@@ -115,9 +122,10 @@ type nodeTopoInformer struct {
 	nodeResourceTopologyInformer cache.SharedIndexInformer
 	nodeResourceTopologyLister   topologylister.NodeResourceTopologyLister
 
-	kubelet      KubeletStub
-	nodeInformer *nodeInformer
-	podsInformer *podsInformer
+	kubelet         KubeletStub
+	nodeInformer    *nodeInformer
+	podsInformer    *podsInformer
+	nodeSLOInformer *nodeSLOInformer
 }
 
 func NewNodeTopoInformer() *nodeTopoInformer {
@@ -152,6 +160,13 @@ func (s *nodeTopoInformer) Setup(ctx *PluginOption, state *PluginState) {
 		klog.Fatalf("pods informer format error")
 	}
 	s.podsInformer = podsInformer
+
+	nodeSLOInformerIf := state.informerPlugins[nodeSLOInformerName]
+	nodeSLOInformer, ok := nodeSLOInformerIf.(*nodeSLOInformer)
+	if !ok {
+		klog.Fatalf("node slo informer format error")
+	}
+	s.nodeSLOInformer = nodeSLOInformer
 }
 
 func (s *nodeTopoInformer) Start(stopCh <-chan struct{}) {
@@ -281,7 +296,9 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 	//    that are exclusive to the remaining cpuset cpus managed by the kubelet static cpu manager. The users should
 	//    no longer use the kubelet static cpu manager anymore and should set the policy to "none". After the last pod
 	//    of the static cpu manager policy is terminated, the cpuset cpus will be fully managed by the koordlet.
-	if s.config != nil && !s.config.DisableQueryKubeletConfig {
+	// The kubelet configuration cannot be queried when the pods are discovered from the CRI runtime only, so the cpu
+	// manager policy is considered as none.
+	if s.config != nil && !s.config.DisableQueryKubeletConfig && s.config.PodDiscoveryMode != PodDiscoveryModeCRI {
 		kubeletConfiguration, err := s.kubelet.GetKubeletConfiguration()
 		if err != nil {
 			return nil, fmt.Errorf("failed to GetKubeletConfiguration, err: %v", err)
@@ -362,6 +379,14 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 	// remove cpus that exclusive for system qos from annotation
 	lsSharePools = removeSystemQOSCPUs(lsSharePools, systemQOSRes)
 	beSharePools = removeSystemQOSCPUs(beSharePools, systemQOSRes)
+
+	// remove cpus that partitioned to the cpu shared sub-pools, so the LS pods out of the sub-pools do not share them
+	var resourceQOSStrategy *slov1alpha1.ResourceQOSStrategy
+	if nodeSLO := s.nodeSLOInformer.GetNodeSLO(); nodeSLO != nil {
+		resourceQOSStrategy = nodeSLO.Spec.ResourceQOSStrategy
+	}
+	subPools := calCPUSharedSubPools(lsSharePools, cpuTopology, resourceQOSStrategy)
+	lsSharePools = removeCPUSharedSubPoolCPUs(lsSharePools, subPools)
 	lsCPUSharePoolsJSON, err := json.Marshal(lsSharePools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal cpushare pools of node, err: %v", err)
@@ -387,10 +412,120 @@ func (s *nodeTopoInformer) calcNodeTopo() (*nodeTopologyStatus, error) {
 		nodeTopoStatus.Annotations[extension.AnnotationNodeSystemQOSResource] = string(systemQOSJson)
 	}
 
+	if len(subPools) > 0 {
+		subPoolsJSON, err := json.Marshal(subPools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal cpu shared sub-pools, err: %v", err)
+		}
+		nodeTopoStatus.Annotations[extension.AnnotationNodeCPUSharedSubPools] = string(subPoolsJSON)
+	}
+
+	if resctrlCapability := resctrlutil.GetResctrlCapability(); resctrlCapability != nil {
+		resctrlCapabilityJSON, err := json.Marshal(resctrlCapability)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resctrl capability, err: %v", err)
+		}
+		nodeTopoStatus.Annotations[extension.AnnotationNodeResctrlCapability] = string(resctrlCapabilityJSON)
+	}
+
+	if topologyDomains := calTopologyDomains(nodeCPUInfo); topologyDomains != nil {
+		topologyDomainsJSON, err := json.Marshal(topologyDomains)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal topology domains, err: %w", err)
+		}
+		nodeTopoStatus.Annotations[extension.AnnotationNodeTopologyDomains] = string(topologyDomainsJSON)
+	}
+
 	klog.V(6).Infof("calculate node topology status: %+v", nodeTopoStatus)
 	return nodeTopoStatus, nil
 }
 
+// calTopologyDomains calculates the topology domains of the node, which are published as the node labels to spread
+// the pods over. The cache domain is the hash of the cpu model with the number of cpus sharing an L3 cache, and the
+// NUMA domain is the number of the NUMA nodes with the number of cpus of each NUMA node.
+// It returns nil if neither of the domains is known.
+func calTopologyDomains(nodeCPUInfo *metriccache.NodeCPUInfo) *extension.NodeTopologyDomains {
+	domains := &extension.NodeTopologyDomains{}
+	if cpusPerL3 := getMaxCPUs(nodeCPUInfo.TotalInfo.L3ToCPU); cpusPerL3 > 0 && nodeCPUInfo.BasicInfo.CPUModel != "" {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(nodeCPUInfo.BasicInfo.CPUModel))
+		domains.CacheDomain = fmt.Sprintf("%08x-l3-%d", h.Sum32(), cpusPerL3)
+	}
+	if cpusPerNUMA := getMaxCPUs(nodeCPUInfo.TotalInfo.NodeToCPU); cpusPerNUMA > 0 {
+		domains.NUMADomain = fmt.Sprintf("%dnuma-%dcpus", len(nodeCPUInfo.TotalInfo.NodeToCPU), cpusPerNUMA)
+	}
+	if domains.CacheDomain == "" && domains.NUMADomain == "" {
+		return nil
+	}
+	return domains
+}
+
+func getMaxCPUs(cpus map[int32][]koordletutil.ProcessorInfo) int {
+	maxCPUs := 0
+	for _, processors := range cpus {
+		if len(processors) > maxCPUs {
+			maxCPUs = len(processors)
+		}
+	}
+	return maxCPUs
+}
+
+// calCPUSharedSubPools partitions the LS share pools into the sub-pools configured in the NodeSLO.
+// The sub-pools are carved in order from the CPUs of each NUMA node, and the CPUs of a physical core are kept together.
+// The last physical core of each NUMA node is never partitioned, so the share pool keeps CPUs for the other LS pods.
+func calCPUSharedSubPools(lsSharePools []extension.CPUSharedPool, cpuTopology *extension.CPUTopology,
+	strategy *slov1alpha1.ResourceQOSStrategy) []extension.CPUSharedSubPool {
+	if strategy == nil || len(strategy.CPUSharedSubPools) <= 0 {
+		return nil
+	}
+	cpuCores := map[int]int32{}
+	for _, info := range cpuTopology.Detail {
+		cpuCores[int(info.ID)] = info.Core
+	}
+
+	subPools := make([]extension.CPUSharedSubPool, len(strategy.CPUSharedSubPools))
+	for i, subPool := range strategy.CPUSharedSubPools {
+		subPools[i] = extension.CPUSharedSubPool{
+			Name:        subPool.Name,
+			MinPriority: subPool.MinPriority,
+			MaxPriority: subPool.MaxPriority,
+		}
+	}
+	for _, pool := range lsSharePools {
+		cpus, err := cpuset.Parse(pool.CPUSet)
+		if err != nil {
+			klog.V(4).Infof("failed to parse cpuset of the share pool on node %d, err: %v", pool.Node, err)
+			continue
+		}
+		remaining := cpus.ToSlice()
+		sort.SliceStable(remaining, func(i, j int) bool {
+			return cpuCores[remaining[i]] < cpuCores[remaining[j]]
+		})
+		total := len(remaining)
+		// keep the cpus of the last physical core in the share pool
+		numKept := 0
+		for i := len(remaining) - 1; i >= 0 && cpuCores[remaining[i]] == cpuCores[remaining[len(remaining)-1]]; i-- {
+			numKept++
+		}
+		for i, subPool := range strategy.CPUSharedSubPools {
+			count := total * int(subPool.CPUPercent) / 100
+			if count > len(remaining)-numKept {
+				count = len(remaining) - numKept
+			}
+			if count <= 0 {
+				continue
+			}
+			subPools[i].Pools = append(subPools[i].Pools, extension.CPUSharedPool{
+				Socket: pool.Socket,
+				Node:   pool.Node,
+				CPUSet: cpuset.NewCPUSet(remaining[:count]...).String(),
+			})
+			remaining = remaining[count:]
+		}
+	}
+	return subPools
+}
+
 // removeNodeReservedCPUs filter out cpus that reserved by annotation of node.
 func removeNodeReservedCPUs(cpuSharePools []extension.CPUSharedPool, reservedCPUs cpuset.CPUSet) []extension.CPUSharedPool {
 	newCPUSharePools := make([]extension.CPUSharedPool, len(cpuSharePools))
@@ -410,6 +545,34 @@ func removeNodeReservedCPUs(cpuSharePools []extension.CPUSharedPool, reservedCPU
 	return newCPUSharePools
 }
 
+// removeCPUSharedSubPoolCPUs removes the cpus of the sub-pools from the share pools on the same NUMA node.
+func removeCPUSharedSubPoolCPUs(cpuSharePools []extension.CPUSharedPool, subPools []extension.CPUSharedSubPool) []extension.CPUSharedPool {
+	if len(subPools) <= 0 {
+		return cpuSharePools
+	}
+	subPoolCPUs := map[int32]cpuset.CPUSet{}
+	for _, subPool := range subPools {
+		for _, pool := range subPool.Pools {
+			cpus, err := cpuset.Parse(pool.CPUSet)
+			if err != nil {
+				continue
+			}
+			subPoolCPUs[pool.Node] = subPoolCPUs[pool.Node].Union(cpus)
+		}
+	}
+
+	newCPUSharePools := make([]extension.CPUSharedPool, len(cpuSharePools))
+	for idx, pool := range cpuSharePools {
+		newCPUSharePools[idx] = pool
+		originCPUs, err := cpuset.Parse(pool.CPUSet)
+		if err != nil {
+			continue
+		}
+		newCPUSharePools[idx].CPUSet = originCPUs.Difference(subPoolCPUs[pool.Node]).String()
+	}
+	return newCPUSharePools
+}
+
 // removeSystemQOSCPUs filter out cpus that for system qos.
 func removeSystemQOSCPUs(cpuSharePools []extension.CPUSharedPool, sysQOSRes *extension.SystemQOSResource) []extension.CPUSharedPool {
 	if sysQOSRes == nil || len(sysQOSRes.CPUSet) == 0 || !sysQOSRes.IsCPUSetExclusive() {
@@ -636,6 +799,8 @@ func isEqualNRTAnnotations(oldAnno, newAnno map[string]string) (bool, string) {
 		extension.AnnotationNodeCPUAllocs,
 		extension.AnnotationNodeReservation,
 		extension.AnnotationNodeSystemQOSResource,
+		extension.AnnotationNodeResctrlCapability,
+		extension.AnnotationNodeCPUSharedSubPools,
 	}
 	for _, key := range keys {
 		oldValue, oldExist := oldAnno[key]
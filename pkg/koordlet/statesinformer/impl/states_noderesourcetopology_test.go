@@ -34,12 +34,14 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	fakeclientset "k8s.io/client-go/kubernetes/fake"
 	kubeletconfiginternal "k8s.io/kubernetes/pkg/kubelet/apis/config"
 	"k8s.io/kubernetes/pkg/kubelet/cm/cpumanager/topology"
 	"k8s.io/utils/pointer"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	fakekoordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/metriccache"
@@ -119,8 +121,9 @@ func Test_nodeResourceTopology_NewAndSetup(t *testing.T) {
 				state: &PluginState{
 					metricCache: mock_metriccache.NewMockMetricCache(ctrl),
 					informerPlugins: map[PluginName]informerPlugin{
-						podsInformerName: NewPodsInformer(),
-						nodeInformerName: NewNodeInformer(),
+						podsInformerName:    NewPodsInformer(),
+						nodeInformerName:    NewNodeInformer(),
+						nodeSLOInformerName: NewNodeSLOInformer(),
 					},
 				},
 			},
@@ -1158,6 +1161,25 @@ func Test_reportNodeTopology(t *testing.T) {
 			expectedTopologyPolicies: expectedTopologyPolices,
 			expectedZones:            expectedZones,
 		},
+		{
+			name: "skip query kubelet config when pods discovered by cri",
+			config: &Config{
+				PodDiscoveryMode: PodDiscoveryModeCRI,
+			},
+			kubeletStub: &criStub{},
+			expectedKubeletCPUManagerPolicy: extension.KubeletCPUManagerPolicy{
+				Policy:       "",
+				ReservedCPUs: "",
+			},
+			expectedCPUBasicInfo:     string(expectedCPUBasicInfoBytes),
+			expectedCPUSharedPool:    expectedCPUSharedPool,
+			expectedCPUTopology:      expectedCPUTopology,
+			expectedNodeCPUAllocs:    "null",
+			expectedNodeReservation:  "{}",
+			expectedSystemQOS:        "{}",
+			expectedTopologyPolicies: expectedTopologyPolices,
+			expectedZones:            expectedZones,
+		},
 		{
 			name:                     "disable report topology",
 			disableCreateTopologyCRD: true,
@@ -1315,7 +1337,8 @@ func Test_reportNodeTopology(t *testing.T) {
 				nodeInformer: &nodeInformer{
 					node: testNode,
 				},
-				callbackRunner: NewCallbackRunner(),
+				nodeSLOInformer: &nodeSLOInformer{},
+				callbackRunner:  NewCallbackRunner(),
 			}
 
 			topologyName := testNode.Name
@@ -2888,3 +2911,115 @@ func Test_getTopologyPolicy(t *testing.T) {
 		})
 	}
 }
+
+func Test_calCPUSharedSubPools(t *testing.T) {
+	cpuTopology := &extension.CPUTopology{
+		Detail: []extension.CPUInfo{
+			{ID: 0, Core: 0, Socket: 0, Node: 0},
+			{ID: 1, Core: 1, Socket: 0, Node: 0},
+			{ID: 2, Core: 2, Socket: 0, Node: 0},
+			{ID: 3, Core: 3, Socket: 0, Node: 0},
+			{ID: 4, Core: 0, Socket: 0, Node: 0},
+			{ID: 5, Core: 1, Socket: 0, Node: 0},
+			{ID: 6, Core: 2, Socket: 0, Node: 0},
+			{ID: 7, Core: 3, Socket: 0, Node: 0},
+			{ID: 8, Core: 4, Socket: 1, Node: 1},
+			{ID: 9, Core: 4, Socket: 1, Node: 1},
+		},
+	}
+	lsSharePools := []extension.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-7"},
+		{Socket: 1, Node: 1, CPUSet: "8-9"},
+	}
+	tests := []struct {
+		name     string
+		strategy *slov1alpha1.ResourceQOSStrategy
+		want     []extension.CPUSharedSubPool
+	}{
+		{
+			name:     "no sub-pool configured",
+			strategy: &slov1alpha1.ResourceQOSStrategy{},
+			want:     nil,
+		},
+		{
+			name: "partition the share pools and keep the last core for the share pools",
+			strategy: &slov1alpha1.ResourceQOSStrategy{
+				CPUSharedSubPools: []slov1alpha1.CPUSharedSubPool{
+					{Name: "ls-high", MinPriority: 9500, MaxPriority: 9999, CPUPercent: 50},
+					{Name: "ls-low", MinPriority: 9000, MaxPriority: 9499, CPUPercent: 25},
+				},
+			},
+			want: []extension.CPUSharedSubPool{
+				{
+					Name:        "ls-high",
+					MinPriority: 9500,
+					MaxPriority: 9999,
+					Pools: []extension.CPUSharedPool{
+						{Socket: 0, Node: 0, CPUSet: "0-1,4-5"},
+					},
+				},
+				{
+					Name:        "ls-low",
+					MinPriority: 9000,
+					MaxPriority: 9499,
+					Pools: []extension.CPUSharedPool{
+						{Socket: 0, Node: 0, CPUSet: "2,6"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calCPUSharedSubPools(lsSharePools, cpuTopology, tt.strategy)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_removeCPUSharedSubPoolCPUs(t *testing.T) {
+	lsSharePools := []extension.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-7"},
+		{Socket: 1, Node: 1, CPUSet: "8-9"},
+	}
+	subPools := []extension.CPUSharedSubPool{
+		{
+			Name:  "ls-high",
+			Pools: []extension.CPUSharedPool{{Socket: 0, Node: 0, CPUSet: "0-1,4-5"}},
+		},
+		{
+			Name:  "ls-low",
+			Pools: []extension.CPUSharedPool{{Socket: 0, Node: 0, CPUSet: "2,6"}},
+		},
+	}
+	assert.Equal(t, lsSharePools, removeCPUSharedSubPoolCPUs(lsSharePools, nil))
+	assert.Equal(t, []extension.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "3,7"},
+		{Socket: 1, Node: 1, CPUSet: "8-9"},
+	}, removeCPUSharedSubPoolCPUs(lsSharePools, subPools))
+}
+
+func Test_calTopologyDomains(t *testing.T) {
+	processors := []koordletutil.ProcessorInfo{{CPUID: 0}, {CPUID: 1}, {CPUID: 2}, {CPUID: 3}}
+	nodeCPUInfo := &metriccache.NodeCPUInfo{
+		BasicInfo: extension.CPUBasicInfo{CPUModel: "Intel(R) Xeon(R) Platinum 8369B CPU @ 2.70GHz"},
+		TotalInfo: koordletutil.CPUTotalInfo{
+			NodeToCPU: map[int32][]koordletutil.ProcessorInfo{0: processors, 1: processors},
+			L3ToCPU:   map[int32][]koordletutil.ProcessorInfo{0: processors[:2], 1: processors[2:], 2: processors[:2], 3: processors[2:]},
+		},
+	}
+	got := calTopologyDomains(nodeCPUInfo)
+	assert.NotNil(t, got)
+	assert.Equal(t, "2numa-4cpus", got.NUMADomain)
+	assert.Regexp(t, "^[0-9a-f]{8}-l3-2$", got.CacheDomain)
+	assert.Empty(t, validation.IsValidLabelValue(got.CacheDomain))
+
+	// the nodes of the same cpu model share the cache domain
+	other := calTopologyDomains(nodeCPUInfo)
+	assert.Equal(t, got.CacheDomain, other.CacheDomain)
+	nodeCPUInfo.BasicInfo.CPUModel = "AMD EPYC 7T83 64-Core Processor"
+	other = calTopologyDomains(nodeCPUInfo)
+	assert.NotEqual(t, got.CacheDomain, other.CacheDomain)
+
+	assert.Nil(t, calTopologyDomains(&metriccache.NodeCPUInfo{}))
+}
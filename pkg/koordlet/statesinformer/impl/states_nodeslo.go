@@ -23,6 +23,7 @@ import (
 	"sync"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	apiruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -31,6 +32,7 @@ import (
 
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
@@ -46,6 +48,13 @@ type nodeSLOInformer struct {
 	nodeSLO         *slov1alpha1.NodeSLO
 
 	callbackRunner *callbackRunner
+
+	// fields below are used to compose the NodeSLO with the sources when NodeSLOMultiSources is enabled
+	sourceInformer cache.SharedIndexInformer
+	sourceMutex    sync.Mutex
+	managedNodeSLO *slov1alpha1.NodeSLO
+	composedSpec   *slov1alpha1.NodeSLOSpec
+	getNode        func() *corev1.Node
 }
 
 func NewNodeSLOInformer() *nodeSLOInformer {
@@ -86,11 +95,17 @@ func (s *nodeSLOInformer) Setup(ctx *PluginOption, state *PluginState) {
 		},
 	})
 	s.callbackRunner = state.callbackRunner
+	if features.DefaultKoordletFeatureGate.Enabled(features.NodeSLOMultiSources) {
+		s.setupSources(ctx, state)
+	}
 }
 
 func (s *nodeSLOInformer) Start(stopCh <-chan struct{}) {
 	klog.V(2).Infof("starting node slo informer")
 	go s.nodeSLOInformer.Run(stopCh)
+	if s.sourceInformer != nil {
+		go s.sourceInformer.Run(stopCh)
+	}
 	klog.V(2).Infof("node slo informer started")
 }
 
@@ -98,12 +113,19 @@ func (s *nodeSLOInformer) HasSynced() bool {
 	if s.nodeSLOInformer == nil {
 		return false
 	}
-	synced := s.nodeSLOInformer.HasSynced()
+	synced := s.nodeSLOInformer.HasSynced() && (s.sourceInformer == nil || s.sourceInformer.HasSynced())
 	klog.V(5).Infof("node slo informer has synced %v", synced)
 	return synced
 }
 
 func (s *nodeSLOInformer) updateNodeSLOSpec(nodeSLO *slov1alpha1.NodeSLO) {
+	if s.sourceInformer != nil {
+		s.sourceMutex.Lock()
+		s.managedNodeSLO = nodeSLO.DeepCopy()
+		s.sourceMutex.Unlock()
+		s.refreshNodeSLOSpec()
+		return
+	}
 	s.setNodeSLOSpec(nodeSLO)
 	s.callbackRunner.SendCallback(statesinformer.RegisterTypeNodeSLOSpec)
 }
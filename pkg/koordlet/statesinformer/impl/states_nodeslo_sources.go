@@ -0,0 +1,160 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// nodeSLOSourcePrecedence is the order of the NodeSLO sources to compose from low to high. The NodeSLO managed by
+// slo-controller has the lowest precedence, and the node's source has the highest.
+var nodeSLOSourcePrecedence = []apiext.NodeSLOSource{
+	apiext.NodeSLOSourceCluster,
+	apiext.NodeSLOSourcePool,
+	apiext.NodeSLOSourceNode,
+}
+
+func (s *nodeSLOInformer) setupSources(ctx *PluginOption, state *PluginState) {
+	s.sourceInformer = newNodeSLOSourceInformer(ctx.KoordClient)
+	s.sourceInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			s.refreshNodeSLOSpec()
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			s.refreshNodeSLOSpec()
+		},
+		DeleteFunc: func(obj interface{}) {
+			s.refreshNodeSLOSpec()
+		},
+	})
+	if n, ok := state.informerPlugins[nodeInformerName].(*nodeInformer); ok {
+		s.getNode = n.GetNode
+	}
+	// the pool of the node is specified in the labels
+	state.callbackRunner.RegisterCallbacks(statesinformer.RegisterTypeNodeMetadata, "compose-nodeslo-sources",
+		"compose the NodeSLO sources when the node pool changes",
+		func(t statesinformer.RegisterType, obj interface{}, target *statesinformer.CallbackTarget) {
+			s.refreshNodeSLOSpec()
+		})
+}
+
+// refreshNodeSLOSpec composes the NodeSLO with the sources, and notifies the callbacks if the composed spec changes.
+func (s *nodeSLOInformer) refreshNodeSLOSpec() {
+	s.sourceMutex.Lock()
+	defer s.sourceMutex.Unlock()
+	if s.managedNodeSLO == nil {
+		klog.V(5).Infof("node slo has not been created, skip composing the sources")
+		return
+	}
+
+	var node *corev1.Node
+	if s.getNode != nil {
+		node = s.getNode()
+	}
+	var sources []*slov1alpha1.NodeSLO
+	for _, obj := range s.sourceInformer.GetStore().List() {
+		if source, ok := obj.(*slov1alpha1.NodeSLO); ok {
+			sources = append(sources, source)
+		}
+	}
+	composed := composeNodeSLO(s.managedNodeSLO, sources, node)
+	if s.composedSpec != nil && reflect.DeepEqual(*s.composedSpec, composed.Spec) {
+		klog.V(5).Infof("composed node slo spec has not changed")
+		return
+	}
+	s.composedSpec = composed.Spec.DeepCopy()
+	klog.V(4).Infof("node slo spec is composed with the sources, spec %v", util.DumpJSON(composed.Spec))
+	s.setNodeSLOSpec(composed)
+	s.callbackRunner.SendCallback(statesinformer.RegisterTypeNodeSLOSpec)
+}
+
+// composeNodeSLO overlays the sources applied to the node onto the managed NodeSLO in the order of precedence.
+// The fields set in a source with higher precedence override the ones from the lower, while the unset fields are
+// inherited, so that each source can manage the sections independently. The sources of the same precedence are
+// overlaid in the order of names.
+func composeNodeSLO(managed *slov1alpha1.NodeSLO, sources []*slov1alpha1.NodeSLO, node *corev1.Node) *slov1alpha1.NodeSLO {
+	out := managed.DeepCopy()
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Name < sources[j].Name
+	})
+	for _, sourceType := range nodeSLOSourcePrecedence {
+		for _, source := range sources {
+			if !isNodeSLOSourceApplied(source, sourceType, node) {
+				continue
+			}
+			// ignore err for serializing/deserializing the same struct type
+			data, _ := json.Marshal(source.Spec)
+			_ = json.Unmarshal(data, &out.Spec)
+			klog.V(5).Infof("node slo source %s of type %s is composed", source.Name, sourceType)
+		}
+	}
+	return out
+}
+
+func isNodeSLOSourceApplied(source *slov1alpha1.NodeSLO, sourceType apiext.NodeSLOSource, node *corev1.Node) bool {
+	if apiext.NodeSLOSource(source.Labels[apiext.LabelNodeSLOSource]) != sourceType {
+		return false
+	}
+	switch sourceType {
+	case apiext.NodeSLOSourceCluster:
+		return true
+	case apiext.NodeSLOSourcePool:
+		pool := source.Labels[apiext.LabelNodeSLOPool]
+		return node != nil && pool != "" && node.Labels[apiext.LabelNodeSLOPool] == pool
+	case apiext.NodeSLOSourceNode:
+		return node != nil && source.Labels[apiext.LabelNodeSLONodeName] == node.Name
+	}
+	return false
+}
+
+func newNodeSLOSourceInformer(client koordclientset.Interface) cache.SharedIndexInformer {
+	tweakListOptionFunc := func(opt *metav1.ListOptions) {
+		opt.LabelSelector = apiext.LabelNodeSLOSource
+	}
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (apiruntime.Object, error) {
+				tweakListOptionFunc(&options)
+				return client.SloV1alpha1().NodeSLOs().List(context.TODO(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				tweakListOptionFunc(&options)
+				return client.SloV1alpha1().NodeSLOs().Watch(context.TODO(), options)
+			},
+		},
+		&slov1alpha1.NodeSLO{},
+		time.Hour*12,
+		cache.Indexers{},
+	)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	fakekoordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+)
+
+func newTestNodeSLOSource(name string, labels map[string]string, spec slov1alpha1.NodeSLOSpec) *slov1alpha1.NodeSLO {
+	return &slov1alpha1.NodeSLO{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       spec,
+	}
+}
+
+func Test_composeNodeSLO(t *testing.T) {
+	managed := &slov1alpha1.NodeSLO{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Spec: slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      pointer.Bool(false),
+				CPUSuppressThresholdPercent: pointer.Int64(65),
+				MemoryEvictThresholdPercent: pointer.Int64(70),
+			},
+			CPUBurstStrategy: &slov1alpha1.CPUBurstStrategy{
+				CPUBurstConfig: slov1alpha1.CPUBurstConfig{
+					CPUBurstPercent: pointer.Int64(1000),
+				},
+			},
+		},
+	}
+	sources := []*slov1alpha1.NodeSLO{
+		newTestNodeSLOSource("node-override", map[string]string{
+			apiext.LabelNodeSLOSource:   string(apiext.NodeSLOSourceNode),
+			apiext.LabelNodeSLONodeName: "test-node",
+		}, slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				CPUSuppressThresholdPercent: pointer.Int64(50),
+			},
+		}),
+		newTestNodeSLOSource("other-node-override", map[string]string{
+			apiext.LabelNodeSLOSource:   string(apiext.NodeSLOSourceNode),
+			apiext.LabelNodeSLONodeName: "other-node",
+		}, slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				CPUSuppressThresholdPercent: pointer.Int64(10),
+			},
+		}),
+		newTestNodeSLOSource("pool-gpu", map[string]string{
+			apiext.LabelNodeSLOSource: string(apiext.NodeSLOSourcePool),
+			apiext.LabelNodeSLOPool:   "gpu",
+		}, slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				CPUSuppressThresholdPercent: pointer.Int64(55),
+				MemoryEvictThresholdPercent: pointer.Int64(80),
+			},
+			HostApplications: []slov1alpha1.HostApplicationSpec{{Name: "gpu-agent"}},
+		}),
+		newTestNodeSLOSource("cluster-default", map[string]string{
+			apiext.LabelNodeSLOSource: string(apiext.NodeSLOSourceCluster),
+		}, slov1alpha1.NodeSLOSpec{
+			ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+				Enable:                      pointer.Bool(true),
+				CPUSuppressThresholdPercent: pointer.Int64(60),
+			},
+			HostApplications: []slov1alpha1.HostApplicationSpec{{Name: "cluster-agent"}},
+		}),
+	}
+
+	tests := []struct {
+		name string
+		node *corev1.Node
+		want slov1alpha1.NodeSLOSpec
+	}{
+		{
+			name: "node not synced",
+			want: slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					Enable:                      pointer.Bool(true),
+					CPUSuppressThresholdPercent: pointer.Int64(60),
+					MemoryEvictThresholdPercent: pointer.Int64(70),
+				},
+				CPUBurstStrategy: managed.Spec.CPUBurstStrategy,
+				HostApplications: []slov1alpha1.HostApplicationSpec{{Name: "cluster-agent"}},
+			},
+		},
+		{
+			name: "node not in pool",
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}},
+			want: slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					Enable:                      pointer.Bool(true),
+					CPUSuppressThresholdPercent: pointer.Int64(50),
+					MemoryEvictThresholdPercent: pointer.Int64(70),
+				},
+				CPUBurstStrategy: managed.Spec.CPUBurstStrategy,
+				HostApplications: []slov1alpha1.HostApplicationSpec{{Name: "cluster-agent"}},
+			},
+		},
+		{
+			name: "node in pool",
+			node: &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-node",
+				Labels: map[string]string{apiext.LabelNodeSLOPool: "gpu"},
+			}},
+			want: slov1alpha1.NodeSLOSpec{
+				ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+					Enable:                      pointer.Bool(true),
+					CPUSuppressThresholdPercent: pointer.Int64(50),
+					MemoryEvictThresholdPercent: pointer.Int64(80),
+				},
+				CPUBurstStrategy: managed.Spec.CPUBurstStrategy,
+				HostApplications: []slov1alpha1.HostApplicationSpec{{Name: "gpu-agent"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := composeNodeSLO(managed, sources, tt.node)
+			assert.Equal(t, tt.want, got.Spec)
+			assert.Equal(t, managed.Name, got.Name)
+		})
+	}
+	// the managed NodeSLO is not modified
+	assert.Equal(t, pointer.Int64(65), managed.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+}
+
+func Test_nodeSLOInformer_refreshNodeSLOSpec(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+	cr := NewCallbackRunner()
+	s := &nodeSLOInformer{
+		callbackRunner: cr,
+		sourceInformer: newNodeSLOSourceInformer(fakekoordclientset.NewSimpleClientset()),
+		getNode: func() *corev1.Node {
+			return node
+		},
+	}
+
+	// skip until the managed NodeSLO is created
+	s.refreshNodeSLOSpec()
+	assert.Nil(t, s.GetNodeSLO())
+
+	err := s.sourceInformer.GetStore().Add(newTestNodeSLOSource("cluster-default", map[string]string{
+		apiext.LabelNodeSLOSource: string(apiext.NodeSLOSourceCluster),
+	}, slov1alpha1.NodeSLOSpec{
+		ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+			CPUSuppressThresholdPercent: pointer.Int64(60),
+		},
+	}))
+	assert.NoError(t, err)
+	s.updateNodeSLOSpec(&slov1alpha1.NodeSLO{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
+	got := s.GetNodeSLO()
+	assert.NotNil(t, got)
+	assert.Equal(t, pointer.Int64(60), got.Spec.ResourceUsedThresholdWithBE.CPUSuppressThresholdPercent)
+	assert.Len(t, cr.callbackChans[statesinformer.RegisterTypeNodeSLOSpec], 1)
+	<-cr.callbackChans[statesinformer.RegisterTypeNodeSLOSpec]
+
+	// no callback if the composed spec is unchanged
+	s.refreshNodeSLOSpec()
+	assert.Len(t, cr.callbackChans[statesinformer.RegisterTypeNodeSLOSpec], 0)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package impl
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -33,7 +35,9 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/pleg"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/resourceexecutor"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/statesinformer"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/tracing"
 	koordletutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util"
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime"
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 )
@@ -209,7 +213,12 @@ func (s *podsInformer) getTaskIds(podMeta *statesinformer.PodMeta) {
 	}
 }
 
-func (s *podsInformer) syncPods() error {
+func (s *podsInformer) syncPods() (err error) {
+	_, span := tracing.StartSpan(context.Background(), tracing.ModuleStatesInformer, "syncPods")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	podList, err := s.kubelet.GetAllPods()
 
 	// when kubelet recovers from crash, podList may be empty.
@@ -278,6 +287,40 @@ func (s *podsInformer) syncKubeletLoop(duration time.Duration, stopCh <-chan str
 }
 
 func newKubeletStubFromConfig(node *corev1.Node, cfg *Config) (KubeletStub, error) {
+	switch cfg.PodDiscoveryMode {
+	case "", PodDiscoveryModeKubelet:
+		return newHTTPKubeletStubFromConfig(node, cfg)
+	case PodDiscoveryModeCRI:
+		return newCRIStubFromConfig(cfg)
+	case PodDiscoveryModeAuto:
+		kubeletStub, kubeletErr := newHTTPKubeletStubFromConfig(node, cfg)
+		criStub, criErr := newCRIStubFromConfig(cfg)
+		if kubeletErr != nil && criErr != nil {
+			return nil, fmt.Errorf("create kubelet stub failed, err: %v, create cri stub failed, err: %v", kubeletErr, criErr)
+		}
+		if criErr != nil {
+			klog.Warningf("create cri stub failed, use the kubelet only to discover pods, err: %v", criErr)
+			return kubeletStub, nil
+		}
+		if kubeletErr != nil {
+			klog.Warningf("create kubelet stub failed, use the cri runtime to discover pods, err: %v", kubeletErr)
+			return criStub, nil
+		}
+		return &fallbackKubeletStub{kubelet: kubeletStub, cri: criStub}, nil
+	default:
+		return nil, fmt.Errorf("unsupported pod discovery mode %s", cfg.PodDiscoveryMode)
+	}
+}
+
+func newCRIStubFromConfig(cfg *Config) (KubeletStub, error) {
+	client, err := runtime.GetRuntimeServiceClient()
+	if err != nil {
+		return nil, err
+	}
+	return NewCRIStub(client, cfg.KubeletSyncTimeout), nil
+}
+
+func newHTTPKubeletStubFromConfig(node *corev1.Node, cfg *Config) (KubeletStub, error) {
 	var port int
 	var scheme string
 	var restConfig *rest.Config
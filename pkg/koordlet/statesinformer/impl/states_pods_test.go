@@ -321,14 +321,34 @@ func Test_newKubeletStub(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "auto mode without cri runtime",
+			args: args{
+				node: testingNode,
+				cfg: &Config{
+					KubeletPreferredAddressType: string(corev1.NodeInternalIP),
+					KubeletSyncTimeout:          10 * time.Second,
+					InsecureKubeletTLS:          true,
+					KubeletReadOnlyPort:         10250,
+					PodDiscoveryMode:            PodDiscoveryModeAuto,
+				},
+			},
+			want:    kubeStub,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			defer helper.Cleanup()
 			got, err := newKubeletStubFromConfig(tt.args.node, tt.args.cfg)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("newKubeletStub() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.args.cfg.PodDiscoveryMode == PodDiscoveryModeAuto {
+				assert.IsType(t, tt.want, got)
+			}
 			if tt.wantErr && got != nil {
 				t.Errorf("newKubeletStub() = %v, want %v", got, tt.want)
 			}
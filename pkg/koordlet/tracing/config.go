@@ -0,0 +1,43 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"flag"
+)
+
+type Config struct {
+	// OTLPEndpoint is the address of the OTLP gRPC receiver which the spans are exported to.
+	// The tracing is disabled if it is empty.
+	OTLPEndpoint  string
+	OTLPInsecure  bool
+	SamplingRatio float64
+}
+
+func NewDefaultConfig() *Config {
+	return &Config{
+		OTLPEndpoint:  "",
+		OTLPInsecure:  false,
+		SamplingRatio: 0.1,
+	}
+}
+
+func (c *Config) InitFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.OTLPEndpoint, "tracing-otlp-endpoint", c.OTLPEndpoint, "The address of the OTLP gRPC endpoint which the tracing spans are exported to, e.g. localhost:4317. Tracing is disabled if it is empty.")
+	fs.BoolVar(&c.OTLPInsecure, "tracing-otlp-insecure", c.OTLPInsecure, "Whether to disable the client transport security for the OTLP gRPC connection.")
+	fs.Float64Var(&c.SamplingRatio, "tracing-sampling-ratio", c.SamplingRatio, "The ratio in [0, 1] of the reconcile rounds to be traced.")
+}
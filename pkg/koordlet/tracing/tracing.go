@@ -0,0 +1,113 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/klog/v2"
+)
+
+const (
+	instrumentationName = "github.com/koordinator-sh/koordinator/pkg/koordlet"
+	serviceName         = "koordlet"
+)
+
+// modules of the koordlet pipeline, which are used as the prefix of the span names.
+const (
+	ModuleStatesInformer   = "statesinformer"
+	ModuleMetricsAdvisor   = "metricsadvisor"
+	ModuleQOSManager       = "qosmanager"
+	ModuleResourceExecutor = "resourceexecutor"
+)
+
+// ShutdownFunc flushes the pending spans and stops the exporter.
+type ShutdownFunc func(ctx context.Context) error
+
+// Setup installs the global tracer provider which exports the spans to the OTLP endpoint.
+// It does nothing if the endpoint is not configured, and the spans are dropped by the default no-op provider.
+func Setup(ctx context.Context, cfg *Config, nodeName string) (ShutdownFunc, error) {
+	if cfg == nil || cfg.OTLPEndpoint == "" {
+		klog.V(4).Infof("tracing otlp endpoint is not configured, tracing is disabled")
+		return func(ctx context.Context) error { return nil }, nil
+	}
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		return nil, fmt.Errorf("invalid tracing sampling ratio %v, must be in [0, 1]", cfg.SamplingRatio)
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint)}
+	if cfg.OTLPInsecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp trace exporter, err: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.HostNameKey.String(nodeName),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	klog.V(4).Infof("tracing is enabled, exporting to %s, sampling ratio %v", cfg.OTLPEndpoint, cfg.SamplingRatio)
+	return provider.Shutdown, nil
+}
+
+// StartSpan starts a span named as "<module>/<name>" from the global tracer provider.
+func StartSpan(ctx context.Context, module, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, module+"/"+name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan records the error if it is not nil and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// WrapRound wraps a reconcile round of the module so that each round is traced as a root span,
+// e.g. `go wait.Until(tracing.WrapRound(tracing.ModuleMetricsAdvisor, "psi", p.collectPSI), p.interval, stopCh)`.
+func WrapRound(module, name string, round func()) func() {
+	return WrapRoundWithContext(module, name, func(context.Context) {
+		round()
+	})
+}
+
+// WrapRoundWithContext is like the WrapRound, while the round is called with the context of the root span, so the
+// spans started from the context in the round, e.g. the resource updates, are traced as its children.
+func WrapRoundWithContext(module, name string, round func(ctx context.Context)) func() {
+	return func() {
+		ctx, span := StartSpan(context.Background(), module, name)
+		defer span.End()
+		round(ctx)
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTestRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	origin := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() {
+		otel.SetTracerProvider(origin)
+	})
+	return recorder
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		shutdown, err := Setup(context.TODO(), NewDefaultConfig(), "test-node")
+		assert.NoError(t, err)
+		assert.NoError(t, shutdown(context.TODO()))
+	})
+	t.Run("invalid sampling ratio", func(t *testing.T) {
+		cfg := NewDefaultConfig()
+		cfg.OTLPEndpoint = "localhost:4317"
+		cfg.SamplingRatio = 2
+		_, err := Setup(context.TODO(), cfg, "test-node")
+		assert.Error(t, err)
+	})
+	t.Run("enabled", func(t *testing.T) {
+		origin := otel.GetTracerProvider()
+		defer otel.SetTracerProvider(origin)
+		cfg := NewDefaultConfig()
+		cfg.OTLPEndpoint = "localhost:4317"
+		cfg.OTLPInsecure = true
+		shutdown, err := Setup(context.TODO(), cfg, "test-node")
+		assert.NoError(t, err)
+		_, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+		assert.True(t, ok)
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		_ = shutdown(ctx)
+	})
+}
+
+func TestSpans(t *testing.T) {
+	recorder := setupTestRecorder(t)
+
+	WrapRound(ModuleQOSManager, "testRound", func() {})()
+	_, span := StartSpan(context.TODO(), ModuleResourceExecutor, "update", attribute.String("resource", "cpu.cfs_quota_us"))
+	EndSpan(span, fmt.Errorf("expected error"))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "qosmanager/testRound", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "resourceexecutor/update", spans[1].Name())
+	assert.Equal(t, []attribute.KeyValue{attribute.String("resource", "cpu.cfs_quota_us")}, spans[1].Attributes())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Len(t, spans[1].Events(), 1)
+}
+
+func TestWrapRoundWithContext(t *testing.T) {
+	recorder := setupTestRecorder(t)
+
+	WrapRoundWithContext(ModuleQOSManager, "testRound", func(ctx context.Context) {
+		_, span := StartSpan(ctx, ModuleResourceExecutor, "update")
+		EndSpan(span, nil)
+	})()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "resourceexecutor/update", spans[0].Name())
+	assert.Equal(t, "qosmanager/testRound", spans[1].Name())
+	// the update is the child span of the round
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, spans[1].SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+}
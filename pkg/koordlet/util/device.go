@@ -27,8 +27,23 @@ type Devices interface {
 	Type() DeviceType
 }
 
+const (
+	GPUVendorNVIDIA = "nvidia"
+	GPUVendorAMD    = "amd"
+	// GPUVendorHuawei is the vendor of the Ascend NPUs, which are reported as the GPUs for the DeviceShare.
+	GPUVendorHuawei = "huawei"
+)
+
 type GPUDevices []GPUDeviceInfo
 
+// Vendor returns the vendor of the GPUs, which are supposed to be the same on a node.
+func (g GPUDevices) Vendor() string {
+	if len(g) <= 0 || g[0].Vendor == "" {
+		return GPUVendorNVIDIA
+	}
+	return g[0].Vendor
+}
+
 func (g GPUDevices) Type() DeviceType {
 	return GPUDeviceType
 }
@@ -42,6 +57,8 @@ type GPUDeviceInfo struct {
 	NodeID      int32  `json:"nodeID"`
 	PCIE        string `json:"pcie,omitempty"`
 	BusID       string `json:"busID,omitempty"`
+	// Vendor represents the vendor of the GPU, and the empty vendor is NVIDIA for the compatibility
+	Vendor string `json:"vendor,omitempty"`
 }
 
 type RDMADevices []RDMADeviceInfo
@@ -0,0 +1,159 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mps
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// ContainerPipeDir is the dir in the container which the pipe dir of the MPS control daemon is mounted to.
+	ContainerPipeDir = "/tmp/nvidia-mps"
+
+	EnvVisibleDevices         = "CUDA_VISIBLE_DEVICES"
+	EnvPipeDirectory          = "CUDA_MPS_PIPE_DIRECTORY"
+	EnvLogDirectory           = "CUDA_MPS_LOG_DIRECTORY"
+	EnvActiveThreadPercentage = "CUDA_MPS_ACTIVE_THREAD_PERCENTAGE"
+	EnvPinnedDeviceMemLimit   = "CUDA_MPS_PINNED_DEVICE_MEM_LIMIT"
+
+	pipeDirName = "pipe"
+	logDirName  = "log"
+	// controlPipeName is the named pipe created by the running MPS control daemon in the pipe dir
+	controlPipeName = "control"
+)
+
+// runCommand runs the MPS control command, it is replaced in the tests.
+var runCommand = func(cmd *exec.Cmd) error {
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w, output: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DaemonController manages the MPS control daemons of the GPUs, one daemon per GPU.
+type DaemonController interface {
+	// Start starts the MPS control daemon of the GPU if it is not running.
+	Start(minor int32) error
+	// Stop stops the MPS control daemon of the GPU and cleans up its dirs.
+	Stop(minor int32) error
+	// IsRunning returns whether the MPS control daemon of the GPU is running.
+	IsRunning(minor int32) bool
+	// ListStarted returns the GPUs whose MPS control daemons have been started.
+	ListStarted() ([]int32, error)
+}
+
+var defaultController DaemonController = &daemonController{}
+
+func DefaultController() DaemonController {
+	return defaultController
+}
+
+// GetPipeDir returns the host pipe dir of the MPS control daemon of the GPU.
+func GetPipeDir(minor int32) string {
+	return filepath.Join(system.Conf.MPSRootDir, strconv.Itoa(int(minor)), pipeDirName)
+}
+
+// GetLogDir returns the host log dir of the MPS control daemon of the GPU.
+func GetLogDir(minor int32) string {
+	return filepath.Join(system.Conf.MPSRootDir, strconv.Itoa(int(minor)), logDirName)
+}
+
+type daemonController struct {
+	lock sync.Mutex
+}
+
+func (d *daemonController) Start(minor int32) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.IsRunning(minor) {
+		return nil
+	}
+
+	pipeDir, logDir := GetPipeDir(minor), GetLogDir(minor)
+	for _, dir := range []string{pipeDir, logDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create dir %s for mps daemon of gpu %d, err: %w", dir, minor, err)
+		}
+	}
+	// the daemon forks itself into background with `-d`
+	cmd := exec.Command(system.Conf.MPSControlBinaryPath, "-d")
+	cmd.Env = append(os.Environ(),
+		EnvVisibleDevices+"="+strconv.Itoa(int(minor)),
+		EnvPipeDirectory+"="+pipeDir,
+		EnvLogDirectory+"="+logDir,
+	)
+	if err := runCommand(cmd); err != nil {
+		return fmt.Errorf("failed to start mps daemon of gpu %d, err: %w", minor, err)
+	}
+	klog.V(4).Infof("start mps daemon of gpu %d successfully, pipe dir %s", minor, pipeDir)
+	return nil
+}
+
+func (d *daemonController) Stop(minor int32) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if d.IsRunning(minor) {
+		cmd := exec.Command(system.Conf.MPSControlBinaryPath)
+		cmd.Env = append(os.Environ(), EnvPipeDirectory+"="+GetPipeDir(minor))
+		cmd.Stdin = strings.NewReader("quit\n")
+		if err := runCommand(cmd); err != nil {
+			return fmt.Errorf("failed to stop mps daemon of gpu %d, err: %w", minor, err)
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(system.Conf.MPSRootDir, strconv.Itoa(int(minor)))); err != nil {
+		return fmt.Errorf("failed to clean up dirs of mps daemon of gpu %d, err: %w", minor, err)
+	}
+	klog.V(4).Infof("stop mps daemon of gpu %d successfully", minor)
+	return nil
+}
+
+func (d *daemonController) IsRunning(minor int32) bool {
+	_, err := os.Stat(filepath.Join(GetPipeDir(minor), controlPipeName))
+	return err == nil
+}
+
+func (d *daemonController) ListStarted() ([]int32, error) {
+	entries, err := os.ReadDir(system.Conf.MPSRootDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var minors []int32
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		minor, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		minors = append(minors, int32(minor))
+	}
+	return minors, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mps
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func TestDaemonController(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+	system.Conf.MPSRootDir = filepath.Join(helper.TempDir, "nvidia-mps")
+
+	// simulate the mps control daemon which creates the control pipe when started
+	var commands []*exec.Cmd
+	oldRunCommand := runCommand
+	defer func() { runCommand = oldRunCommand }()
+	runCommand = func(cmd *exec.Cmd) error {
+		commands = append(commands, cmd)
+		if len(cmd.Args) > 1 && cmd.Args[1] == "-d" {
+			return os.WriteFile(filepath.Join(GetPipeDir(0), controlPipeName), nil, 0644)
+		}
+		return nil
+	}
+
+	d := &daemonController{}
+	started, err := d.ListStarted()
+	assert.NoError(t, err)
+	assert.Empty(t, started)
+	assert.False(t, d.IsRunning(0))
+
+	assert.NoError(t, d.Start(0))
+	assert.True(t, d.IsRunning(0))
+	assert.Len(t, commands, 1)
+	assert.Contains(t, commands[0].Env, EnvVisibleDevices+"=0")
+	assert.Contains(t, commands[0].Env, EnvPipeDirectory+"="+GetPipeDir(0))
+	_, err = os.Stat(GetLogDir(0))
+	assert.NoError(t, err)
+
+	// start again is a no-op
+	assert.NoError(t, d.Start(0))
+	assert.Len(t, commands, 1)
+
+	started, err = d.ListStarted()
+	assert.NoError(t, err)
+	assert.Equal(t, []int32{0}, started)
+
+	assert.NoError(t, d.Stop(0))
+	assert.Len(t, commands, 2)
+	assert.Len(t, commands[1].Args, 1)
+	assert.False(t, d.IsRunning(0))
+	started, err = d.ListStarted()
+	assert.NoError(t, err)
+	assert.Empty(t, started)
+}
@@ -0,0 +1,328 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netprobe
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	statsMapMaxEntries = 4096
+
+	// the offsets of the fields in struct __sk_buff and struct bpf_sock_ops
+	skbLenOffset         = 0
+	sockOpsOpOffset      = 0
+	sockOpsArg1Offset    = 8
+	sockOpsArg2Offset    = 12
+	sockOpsCbFlagsOffset = 84
+
+	sockOpsActiveEstablished  = 4 // BPF_SOCK_OPS_ACTIVE_ESTABLISHED_CB
+	sockOpsPassiveEstablished = 5 // BPF_SOCK_OPS_PASSIVE_ESTABLISHED_CB
+	sockOpsRetrans            = 9 // BPF_SOCK_OPS_RETRANS_CB
+	sockOpsRetransCbFlag      = 2 // BPF_SOCK_OPS_RETRANS_CB_FLAG
+
+	cgroupSKBAccept = 1
+
+	programLicense = "Dual BSD/GPL"
+)
+
+// cgroupProbe is the programs attached to a cgroup, and the key of the cgroup in the stats map.
+type cgroupProbe struct {
+	key      uint64
+	programs []*ebpf.Program
+	links    []link.Link
+}
+
+// Probe counts the network traffic of the cgroups with the eBPF programs attached to the cgroups-v2 directories.
+// The cgroup_skb programs count the bytes and packets passing through the sockets on ingress and egress, and the
+// sock_ops program counts the tcp segments retransmitted. Since the retransmission callback is enabled when a tcp
+// connection is established, the connections established before the attachment are not counted.
+type Probe struct {
+	lock     sync.Mutex
+	statsMap *ebpf.Map
+	cgroups  map[string]*cgroupProbe
+}
+
+// NewProbe creates the stats map shared by the cgroups. It requires the CAP_BPF (or CAP_SYS_ADMIN).
+func NewProbe() (*Probe, error) {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, fmt.Errorf("failed to remove memlock limit, err: %w", err)
+	}
+	statsMap, err := ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "net_stats",
+		Type:       ebpf.Hash,
+		KeySize:    8,
+		ValueSize:  statsSize,
+		MaxEntries: statsMapMaxEntries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stats map, err: %w", err)
+	}
+	return &Probe{
+		statsMap: statsMap,
+		cgroups:  map[string]*cgroupProbe{},
+	}, nil
+}
+
+// Attach attaches the programs to the cgroups-v2 directory if not attached yet. The counters of the cgroup are
+// reset when attached.
+func (p *Probe) Attach(cgroupPath string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if _, ok := p.cgroups[cgroupPath]; ok {
+		return nil
+	}
+	key, err := getCgroupKey(cgroupPath)
+	if err != nil {
+		return err
+	}
+	if err = p.statsMap.Update(key, Stats{}, ebpf.UpdateAny); err != nil {
+		return fmt.Errorf("failed to init stats of cgroup %s, err: %w", cgroupPath, err)
+	}
+
+	c := &cgroupProbe{key: key}
+	attachments := []struct {
+		program    string
+		attachType ebpf.AttachType
+		progType   ebpf.ProgramType
+		insns      asm.Instructions
+	}{
+		{
+			program:    "net_ingress",
+			attachType: ebpf.AttachCGroupInetIngress,
+			progType:   ebpf.CGroupSKB,
+			insns:      buildSKBInstructions(p.statsMap.FD(), key, offsetIngressBytes, offsetIngressPackets),
+		},
+		{
+			program:    "net_egress",
+			attachType: ebpf.AttachCGroupInetEgress,
+			progType:   ebpf.CGroupSKB,
+			insns:      buildSKBInstructions(p.statsMap.FD(), key, offsetEgressBytes, offsetEgressPackets),
+		},
+		{
+			program:    "net_sockops",
+			attachType: ebpf.AttachCGroupSockOps,
+			progType:   ebpf.SockOps,
+			insns:      buildSockOpsInstructions(p.statsMap.FD(), key),
+		},
+	}
+	for _, a := range attachments {
+		prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+			Name:         a.program,
+			Type:         a.progType,
+			Instructions: a.insns,
+			License:      programLicense,
+		})
+		if err != nil {
+			_ = c.close()
+			p.deleteStats(key)
+			return fmt.Errorf("failed to load program %s, err: %w", a.program, err)
+		}
+		c.programs = append(c.programs, prog)
+		l, err := link.AttachCgroup(link.CgroupOptions{
+			Path:    cgroupPath,
+			Attach:  a.attachType,
+			Program: prog,
+		})
+		if err != nil {
+			_ = c.close()
+			p.deleteStats(key)
+			return fmt.Errorf("failed to attach program %s to cgroup %s, err: %w", a.program, cgroupPath, err)
+		}
+		c.links = append(c.links, l)
+	}
+	p.cgroups[cgroupPath] = c
+	return nil
+}
+
+// Detach detaches the programs from the cgroup and releases its counters.
+func (p *Probe) Detach(cgroupPath string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	c, ok := p.cgroups[cgroupPath]
+	if !ok {
+		return nil
+	}
+	delete(p.cgroups, cgroupPath)
+	p.deleteStats(c.key)
+	return c.close()
+}
+
+// AttachedCgroups returns the cgroups to which the programs are attached.
+func (p *Probe) AttachedCgroups() []string {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	cgroups := make([]string, 0, len(p.cgroups))
+	for cgroupPath := range p.cgroups {
+		cgroups = append(cgroups, cgroupPath)
+	}
+	return cgroups
+}
+
+// Read returns the counters of the attached cgroup.
+func (p *Probe) Read(cgroupPath string) (*Stats, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	c, ok := p.cgroups[cgroupPath]
+	if !ok {
+		return nil, fmt.Errorf("cgroup %s is not attached", cgroupPath)
+	}
+	stats := &Stats{}
+	if err := p.statsMap.Lookup(c.key, stats); err != nil {
+		return nil, fmt.Errorf("failed to lookup stats of cgroup %s, err: %w", cgroupPath, err)
+	}
+	return stats, nil
+}
+
+// Close detaches the programs from all cgroups and releases the map.
+func (p *Probe) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var errs []error
+	for cgroupPath, c := range p.cgroups {
+		if err := c.close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(p.cgroups, cgroupPath)
+	}
+	if p.statsMap != nil {
+		if err := p.statsMap.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		p.statsMap = nil
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (p *Probe) deleteStats(key uint64) {
+	if err := p.statsMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		klog.V(5).Infof("failed to delete stats of cgroup key %d, err: %v", key, err)
+	}
+}
+
+func (c *cgroupProbe) close() error {
+	var errs []error
+	for _, l := range c.links {
+		if err := l.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.links = nil
+	for _, prog := range c.programs {
+		if err := prog.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	c.programs = nil
+	return utilerrors.NewAggregate(errs)
+}
+
+// getCgroupKey returns the inode number of the cgroups-v2 directory, which is the cgroup id in the kernel.
+func getCgroupKey(cgroupPath string) (uint64, error) {
+	info, err := os.Stat(cgroupPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat cgroup %s, err: %w", cgroupPath, err)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("failed to get inode of cgroup %s", cgroupPath)
+	}
+	return stat.Ino, nil
+}
+
+// buildSKBInstructions builds the cgroup_skb program which accumulates the length of the skb and the packet count
+// into the stats of the cgroup. The key of the cgroup is built into the program, so a program is loaded for each
+// cgroup. The packet is always accepted.
+func buildSKBInstructions(statsMapFD int, key uint64, bytesOffset, packetsOffset int16) asm.Instructions {
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1),
+	}
+	insns = append(insns, lookupStatsInstructions(statsMapFD, key)...)
+	insns = append(insns,
+		asm.JEq.Imm(asm.R0, 0, "exit"),
+		asm.LoadMem(asm.R1, asm.R6, skbLenOffset, asm.Word),
+		storeXAdd(asm.R0, bytesOffset, asm.R1),
+		asm.Mov.Imm(asm.R1, 1),
+		storeXAdd(asm.R0, packetsOffset, asm.R1),
+		asm.Mov.Imm(asm.R0, cgroupSKBAccept).WithSymbol("exit"),
+		asm.Return(),
+	)
+	return insns
+}
+
+// buildSockOpsInstructions builds the sock_ops program which enables the retransmission callback on the tcp
+// connections established, and accumulates the segments retransmitted successfully into the stats of the cgroup.
+func buildSockOpsInstructions(statsMapFD int, key uint64) asm.Instructions {
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1),
+		asm.LoadMem(asm.R7, asm.R6, sockOpsOpOffset, asm.Word),
+		asm.JEq.Imm(asm.R7, sockOpsActiveEstablished, "established"),
+		asm.JEq.Imm(asm.R7, sockOpsPassiveEstablished, "established"),
+		asm.JNE.Imm(asm.R7, sockOpsRetrans, "exit"),
+		// args[2] is the error of the retransmission, and args[1] is the number of segments
+		asm.LoadMem(asm.R8, asm.R6, sockOpsArg2Offset, asm.Word),
+		asm.JNE.Imm(asm.R8, 0, "exit"),
+		asm.LoadMem(asm.R8, asm.R6, sockOpsArg1Offset, asm.Word),
+	}
+	insns = append(insns, lookupStatsInstructions(statsMapFD, key)...)
+	insns = append(insns,
+		asm.JEq.Imm(asm.R0, 0, "exit"),
+		storeXAdd(asm.R0, offsetTCPRetransmits, asm.R8),
+		asm.Ja.Label("exit"),
+		// bpf_sock_ops_cb_flags_set(skops, skops->bpf_sock_ops_cb_flags | BPF_SOCK_OPS_RETRANS_CB_FLAG)
+		asm.LoadMem(asm.R2, asm.R6, sockOpsCbFlagsOffset, asm.Word).WithSymbol("established"),
+		asm.Or.Imm(asm.R2, sockOpsRetransCbFlag),
+		asm.Mov.Reg(asm.R1, asm.R6),
+		asm.FnSockOpsCbFlagsSet.Call(),
+		asm.Mov.Imm(asm.R0, 1).WithSymbol("exit"),
+		asm.Return(),
+	)
+	return insns
+}
+
+// lookupStatsInstructions looks up the stats of the key, and returns the pointer of the value in r0.
+func lookupStatsInstructions(statsMapFD int, key uint64) asm.Instructions {
+	return asm.Instructions{
+		asm.LoadImm(asm.R1, int64(key), asm.DWord),
+		asm.StoreMem(asm.RFP, -8, asm.R1, asm.DWord),
+		asm.LoadMapPtr(asm.R1, statsMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -8),
+		asm.FnMapLookupElem.Call(),
+	}
+}
+
+// storeXAdd atomically adds the src to the u64 at dst + offset.
+func storeXAdd(dst asm.Register, offset int16, src asm.Register) asm.Instruction {
+	insn := asm.StoreXAdd(dst, src, asm.DWord)
+	insn.Offset = offset
+	return insn
+}
@@ -0,0 +1,64 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netprobe
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/ebpf/asm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInstructions(t *testing.T) {
+	for name, insns := range map[string]asm.Instructions{
+		"ingress": buildSKBInstructions(3, 12345, offsetIngressBytes, offsetIngressPackets),
+		"egress":  buildSKBInstructions(3, 12345, offsetEgressBytes, offsetEgressPackets),
+		"sockops": buildSockOpsInstructions(3, 12345),
+	} {
+		symbols, err := insns.SymbolOffsets()
+		assert.NoError(t, err, name)
+		for ref := range insns.ReferenceOffsets() {
+			_, ok := symbols[ref]
+			assert.True(t, ok, "%s: undefined symbol %s", name, ref)
+		}
+		assert.Equal(t, asm.Return().OpCode, insns[len(insns)-1].OpCode, name)
+	}
+}
+
+func TestStatsLayout(t *testing.T) {
+	s := Stats{}
+	assert.Equal(t, uintptr(statsSize), unsafe.Sizeof(s))
+	assert.Equal(t, uintptr(offsetIngressBytes), unsafe.Offsetof(s.IngressBytes))
+	assert.Equal(t, uintptr(offsetIngressPackets), unsafe.Offsetof(s.IngressPackets))
+	assert.Equal(t, uintptr(offsetEgressBytes), unsafe.Offsetof(s.EgressBytes))
+	assert.Equal(t, uintptr(offsetEgressPackets), unsafe.Offsetof(s.EgressPackets))
+	assert.Equal(t, uintptr(offsetTCPRetransmits), unsafe.Offsetof(s.TCPRetransmits))
+}
+
+func TestGetCgroupKey(t *testing.T) {
+	dir := t.TempDir()
+	key, err := getCgroupKey(dir)
+	assert.NoError(t, err)
+	assert.NotZero(t, key)
+
+	_, err = getCgroupKey(dir + "/not-exist")
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netprobe
+
+import "fmt"
+
+// Probe counts the network traffic of the cgroups, which is only supported on linux.
+type Probe struct{}
+
+func NewProbe() (*Probe, error) {
+	return nil, fmt.Errorf("network probe is only supported on linux")
+}
+
+func (p *Probe) Attach(cgroupPath string) error {
+	return fmt.Errorf("network probe is only supported on linux")
+}
+
+func (p *Probe) Detach(cgroupPath string) error {
+	return nil
+}
+
+func (p *Probe) AttachedCgroups() []string {
+	return nil
+}
+
+func (p *Probe) Read(cgroupPath string) (*Stats, error) {
+	return nil, fmt.Errorf("network probe is only supported on linux")
+}
+
+func (p *Probe) Close() error {
+	return nil
+}
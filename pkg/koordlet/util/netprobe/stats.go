@@ -0,0 +1,38 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netprobe
+
+// Stats is the cumulative network counters of a cgroup since the probe attached. The layout must be consistent
+// with the value of the stats map accessed by the bpf programs.
+type Stats struct {
+	IngressBytes   uint64
+	IngressPackets uint64
+	EgressBytes    uint64
+	EgressPackets  uint64
+	TCPRetransmits uint64
+}
+
+const (
+	// the offsets of the fields in Stats
+	offsetIngressBytes   = 0
+	offsetIngressPackets = 8
+	offsetEgressBytes    = 16
+	offsetEgressPackets  = 24
+	offsetTCPRetransmits = 32
+
+	statsSize = 40
+)
@@ -0,0 +1,149 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/coreos/go-iptables/iptables"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	markTable = "mangle"
+	// markChain is jumped from the POSTROUTING of the mangle table, which is traversed before the masquerade rules of
+	// the nat table, so the packets of the pods still have the pod ips as the source addresses.
+	markChain  = "KOORD-NETQOS-DSCP"
+	markParent = "POSTROUTING"
+)
+
+// iptablesHandler is the subset of the iptables operations used by the marker.
+type iptablesHandler interface {
+	ChainExists(table, chain string) (bool, error)
+	ClearChain(table, chain string) error
+	DeleteChain(table, chain string) error
+	AppendUnique(table, chain string, rulespec ...string) error
+	DeleteIfExists(table, chain string, rulespec ...string) error
+}
+
+// iptablesMarker sets the DSCP of the egress packets by their source ipv4 addresses with the iptables DSCP target in
+// the host network namespace, which covers the packets forwarded from the pod network namespaces and requires no
+// change inside the pods.
+type iptablesMarker struct {
+	lock    sync.Mutex
+	handler iptablesHandler
+	// marks is nil until the chain is initialized
+	marks map[string]uint8
+}
+
+// NewMarker creates the marker with the iptables of ipv4. It requires the CAP_NET_ADMIN.
+func NewMarker() (Marker, error) {
+	ipt, err := iptables.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create iptables handler, err: %w", err)
+	}
+	return newIPTablesMarker(ipt), nil
+}
+
+func newIPTablesMarker(handler iptablesHandler) *iptablesMarker {
+	return &iptablesMarker{handler: handler}
+}
+
+func (m *iptablesMarker) UpdateMarks(marks map[string]uint8) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.marks == nil {
+		if err := m.initChain(); err != nil {
+			return err
+		}
+		m.marks = map[string]uint8{}
+	}
+
+	var errs []error
+	for ip, dscp := range m.marks {
+		if newDSCP, ok := marks[ip]; ok && newDSCP == dscp {
+			continue
+		}
+		if err := m.handler.DeleteIfExists(markTable, markChain, markRuleSpec(ip, dscp)...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete dscp mark of %s, err: %w", ip, err))
+			continue
+		}
+		delete(m.marks, ip)
+	}
+	for ip, dscp := range marks {
+		if oldDSCP, ok := m.marks[ip]; ok && oldDSCP == dscp {
+			continue
+		}
+		if net.ParseIP(ip).To4() == nil || dscp > MaxDSCP {
+			klog.V(5).Infof("skip dscp mark of %s, dscp %d, not a valid ipv4 mark", ip, dscp)
+			continue
+		}
+		if err := m.handler.AppendUnique(markTable, markChain, markRuleSpec(ip, dscp)...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to add dscp mark of %s, err: %w", ip, err))
+			continue
+		}
+		m.marks[ip] = dscp
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (m *iptablesMarker) Cleanup() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	existed, err := m.handler.ChainExists(markTable, markChain)
+	if err != nil {
+		return fmt.Errorf("failed to check chain %s, err: %w", markChain, err)
+	}
+	if existed {
+		if err = m.handler.DeleteIfExists(markTable, markParent, "-j", markChain); err != nil {
+			return fmt.Errorf("failed to delete jump to chain %s, err: %w", markChain, err)
+		}
+		if err = m.handler.ClearChain(markTable, markChain); err != nil {
+			return fmt.Errorf("failed to clear chain %s, err: %w", markChain, err)
+		}
+		if err = m.handler.DeleteChain(markTable, markChain); err != nil {
+			return fmt.Errorf("failed to delete chain %s, err: %w", markChain, err)
+		}
+	}
+	m.marks = nil
+	return nil
+}
+
+// initChain creates the chain or flushes the remaining rules of the last run, and jumps to it from POSTROUTING.
+func (m *iptablesMarker) initChain() error {
+	// ClearChain creates the chain if it does not exist
+	if err := m.handler.ClearChain(markTable, markChain); err != nil {
+		return fmt.Errorf("failed to init chain %s, err: %w", markChain, err)
+	}
+	if err := m.handler.AppendUnique(markTable, markParent, "-j", markChain); err != nil {
+		return fmt.Errorf("failed to jump to chain %s, err: %w", markChain, err)
+	}
+	return nil
+}
+
+func markRuleSpec(ip string, dscp uint8) []string {
+	return []string{"-s", ip + "/32", "-j", "DSCP", "--set-dscp", strconv.Itoa(int(dscp))}
+}
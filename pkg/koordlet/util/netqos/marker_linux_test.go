@@ -0,0 +1,109 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeIPTables struct {
+	chains map[string][]string
+}
+
+func newFakeIPTables() *fakeIPTables {
+	return &fakeIPTables{chains: map[string][]string{markParent: nil}}
+}
+
+func (f *fakeIPTables) ChainExists(table, chain string) (bool, error) {
+	_, ok := f.chains[chain]
+	return ok, nil
+}
+
+func (f *fakeIPTables) ClearChain(table, chain string) error {
+	f.chains[chain] = []string{}
+	return nil
+}
+
+func (f *fakeIPTables) DeleteChain(table, chain string) error {
+	delete(f.chains, chain)
+	return nil
+}
+
+func (f *fakeIPTables) AppendUnique(table, chain string, rulespec ...string) error {
+	rule := strings.Join(rulespec, " ")
+	for _, r := range f.chains[chain] {
+		if r == rule {
+			return nil
+		}
+	}
+	f.chains[chain] = append(f.chains[chain], rule)
+	return nil
+}
+
+func (f *fakeIPTables) DeleteIfExists(table, chain string, rulespec ...string) error {
+	rule := strings.Join(rulespec, " ")
+	rules := f.chains[chain][:0]
+	for _, r := range f.chains[chain] {
+		if r != rule {
+			rules = append(rules, r)
+		}
+	}
+	f.chains[chain] = rules
+	return nil
+}
+
+func TestIPTablesMarker(t *testing.T) {
+	ipt := newFakeIPTables()
+	// remaining rule of the last run
+	ipt.chains[markChain] = []string{"-s 10.0.0.9/32 -j DSCP --set-dscp 10"}
+	m := newIPTablesMarker(ipt)
+
+	err := m.UpdateMarks(map[string]uint8{
+		"10.0.0.1": 46,
+		"10.0.0.2": 10,
+		"fd00::1":  46,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-j " + markChain}, ipt.chains[markParent])
+	assert.ElementsMatch(t, []string{
+		"-s 10.0.0.1/32 -j DSCP --set-dscp 46",
+		"-s 10.0.0.2/32 -j DSCP --set-dscp 10",
+	}, ipt.chains[markChain])
+
+	err = m.UpdateMarks(map[string]uint8{
+		"10.0.0.1": 34,
+		"10.0.0.3": 10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-j " + markChain}, ipt.chains[markParent])
+	assert.ElementsMatch(t, []string{
+		"-s 10.0.0.1/32 -j DSCP --set-dscp 34",
+		"-s 10.0.0.3/32 -j DSCP --set-dscp 10",
+	}, ipt.chains[markChain])
+
+	assert.NoError(t, m.Cleanup())
+	assert.Empty(t, ipt.chains[markParent])
+	_, ok := ipt.chains[markChain]
+	assert.False(t, ok)
+	assert.Nil(t, m.marks)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// HandleMajor is the major handle of the htb qdisc and classes managed by the shaper.
+	HandleMajor uint16 = 2
+
+	RootClassMinor   uint16 = 1
+	SystemClassMinor uint16 = 2
+	LSClassMinor     uint16 = 3
+	BEClassMinor     uint16 = 4
+
+	// the classes of the lower prio value are offered the spare bandwidth first
+	SystemClassPrio uint32 = 1
+	LSClassPrio     uint32 = 2
+	BEClassPrio     uint32 = 3
+
+	// MinClassRate is the minimal guaranteed rate of a class in bytes per second, since htb requires a positive rate.
+	MinClassRate uint64 = 1000
+
+	// MaxDSCP is the maximum value of the 6-bit DSCP field.
+	MaxDSCP = 63
+)
+
+// Class is an htb class under the root class of the NIC.
+type Class struct {
+	Minor uint16
+	Prio  uint32
+	// Rate is the guaranteed bandwidth in bytes per second.
+	Rate uint64
+	// Ceil is the maximum bandwidth in bytes per second when borrowing from the root class.
+	Ceil uint64
+}
+
+// Shaper limits the egress bandwidth of the pods on the NIC of the default route. The traffic is scheduled by the
+// htb classes with the fq leaves, and the packets are classified into the classes by their source ips.
+type Shaper interface {
+	// Ensure creates or updates the htb qdisc, the root class of the total bandwidth and the classes.
+	// The traffic which is not from the endpoints goes to the system class.
+	Ensure(total uint64, classes []Class) error
+	// UpdateEndpoints replaces the endpoints to classify, which maps the ipv4 addresses to the minor of the class.
+	UpdateEndpoints(endpoints map[string]uint16) error
+	// Cleanup removes the qdisc and the endpoints.
+	Cleanup() error
+}
+
+// Marker marks the DSCP of the egress packets of the pods, so that the underlay network can prioritize the traffic
+// of the QoS classes end-to-end.
+type Marker interface {
+	// UpdateMarks replaces the marks, which maps the ipv4 addresses to the DSCP values.
+	UpdateMarks(marks map[string]uint8) error
+	// Cleanup removes the marks.
+	Cleanup() error
+}
+
+// GetBandwidth returns the bandwidth in the unit of the total, which is either a quantity or a percentage of the total.
+// It returns 0 if the value is invalid.
+func GetBandwidth(total uint64, intOrPercent *intstr.IntOrString) uint64 {
+	if intOrPercent == nil {
+		return 0
+	}
+	switch intOrPercent.Type {
+	case intstr.String:
+		q, err := resource.ParseQuantity(intOrPercent.StrVal)
+		if err != nil || q.Sign() < 0 {
+			return 0
+		}
+		return uint64(q.Value())
+	case intstr.Int:
+		percent := intOrPercent.IntValue()
+		if percent < 0 || percent > 100 {
+			return 0
+		}
+		return total * uint64(percent) / 100
+	default:
+		return 0
+	}
+}
+
+// ClassID returns the tc handle of the class.
+func ClassID(minor uint16) uint32 {
+	return uint32(HandleMajor)<<16 | uint32(minor)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestGetBandwidth(t *testing.T) {
+	quantity := intstr.FromString("50M")
+	percent := intstr.FromInt(30)
+	invalidPercent := intstr.FromInt(120)
+	invalidQuantity := intstr.FromString("xxx")
+	tests := []struct {
+		name  string
+		total uint64
+		arg   *intstr.IntOrString
+		want  uint64
+	}{
+		{name: "nil", total: 1000, arg: nil, want: 0},
+		{name: "quantity", total: 1000, arg: &quantity, want: 50000000},
+		{name: "percent", total: 1000, arg: &percent, want: 300},
+		{name: "invalid percent", total: 1000, arg: &invalidPercent, want: 0},
+		{name: "invalid quantity", total: 1000, arg: &invalidQuantity, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, GetBandwidth(tt.total, tt.arg))
+		})
+	}
+}
+
+func TestClassID(t *testing.T) {
+	assert.Equal(t, uint32(0x20003), ClassID(LSClassMinor))
+}
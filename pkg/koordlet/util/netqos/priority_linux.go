@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+)
+
+const (
+	// the offset of the priority in struct __sk_buff
+	skbPriorityOffset = 32
+
+	priorityProgramName = "koord_netprio"
+)
+
+// AttachCgroupPriority attaches a cgroup skb program on the egress of the cgroups-v2 directory, which sets the skb
+// priority of the packets sent by the sockets in the cgroup to the class id. The htb qdisc classifies the packets of
+// a matched priority into the class directly before running the filters.
+// The program attached without the flags replaces the one attached before, so it is safe to attach repeatedly.
+func AttachCgroupPriority(cgroupDir string, classID uint32) error {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return fmt.Errorf("failed to remove memlock limit, err: %w", err)
+	}
+	program, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         priorityProgramName,
+		Type:         ebpf.CGroupSKB,
+		AttachType:   ebpf.AttachCGroupInetEgress,
+		Instructions: buildPriorityInstructions(classID),
+		License:      programLicense,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load priority program, err: %w", err)
+	}
+	// the attached program is held by the cgroup, and it is released when the cgroup is removed
+	defer program.Close()
+
+	f, err := os.Open(cgroupDir)
+	if err != nil {
+		return fmt.Errorf("failed to open cgroup %s, err: %w", cgroupDir, err)
+	}
+	defer f.Close()
+	if err = link.RawAttachProgram(link.RawAttachProgramOptions{
+		Target:  int(f.Fd()),
+		Program: program,
+		Attach:  ebpf.AttachCGroupInetEgress,
+	}); err != nil {
+		return fmt.Errorf("failed to attach priority program to cgroup %s, err: %w", cgroupDir, err)
+	}
+	return nil
+}
+
+// buildPriorityInstructions builds the cgroup skb program, which sets the skb priority and allows the packet.
+func buildPriorityInstructions(classID uint32) asm.Instructions {
+	return asm.Instructions{
+		// the verifier rejects the immediate stores into the context, so the value is stored from a register
+		asm.Mov.Imm32(asm.R2, int32(classID)),
+		asm.StoreMem(asm.R1, skbPriorityOffset, asm.R2, asm.Word),
+		asm.Mov.Imm(asm.R0, 1),
+		asm.Return(),
+	}
+}
@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf/asm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildPriorityInstructions(t *testing.T) {
+	insns := buildPriorityInstructions(ClassID(BEClassMinor))
+	assert.Equal(t, int64(ClassID(BEClassMinor)), insns[0].Constant)
+	assert.Equal(t, int16(skbPriorityOffset), insns[1].Offset)
+	assert.Equal(t, asm.R1, insns[1].Dst)
+	assert.Equal(t, asm.Return().OpCode, insns[len(insns)-1].OpCode)
+}
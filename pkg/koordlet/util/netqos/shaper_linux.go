@@ -0,0 +1,334 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"unsafe"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/rlimit"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	endpointMapMaxEntries = 65536
+
+	// the offset of the protocol in struct __sk_buff
+	skbProtocolOffset = 16
+	// the offset of the source address in the ipv4 packet with the ethernet header
+	ipv4SrcAddrOffset = 14 + 12
+
+	filterName     = "koord_netqos"
+	filterPrio     = 1
+	programLicense = "Dual BSD/GPL"
+
+	// the mtu to calculate the burst of the htb class, which is the same as netlink.NewHtbClass
+	htbMTU = 1600
+
+	// the major handle of the fq leaf qdisc is the base plus the minor of its class
+	leafQdiscMajorBase uint16 = 0x100
+)
+
+// ebpfShaper classifies the egress packets with a cls_bpf program, which looks up the source ipv4 address in the
+// endpoint map and returns the class id. The unmatched packets go to the default class of the htb qdisc.
+// NOTE: The packets of the pods masqueraded to the node ip are no longer recognized at the egress of the NIC.
+type ebpfShaper struct {
+	lock        sync.Mutex
+	endpointMap *ebpf.Map
+	program     *ebpf.Program
+	link        netlink.Link
+	endpoints   map[[4]byte]uint32
+}
+
+// NewShaper loads the classifier program. It requires the CAP_BPF and CAP_NET_ADMIN (or CAP_SYS_ADMIN).
+func NewShaper() (Shaper, error) {
+	if err := rlimit.RemoveMemlock(); err != nil {
+		return nil, fmt.Errorf("failed to remove memlock limit, err: %w", err)
+	}
+	endpointMap, err := ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "netqos_endpoints",
+		Type:       ebpf.Hash,
+		KeySize:    4,
+		ValueSize:  4,
+		MaxEntries: endpointMapMaxEntries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create endpoint map, err: %w", err)
+	}
+	program, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+		Name:         filterName,
+		Type:         ebpf.SchedCLS,
+		Instructions: buildClassifierInstructions(endpointMap.FD()),
+		License:      programLicense,
+	})
+	if err != nil {
+		_ = endpointMap.Close()
+		return nil, fmt.Errorf("failed to load classifier program, err: %w", err)
+	}
+	return &ebpfShaper{
+		endpointMap: endpointMap,
+		program:     program,
+		endpoints:   map[[4]byte]uint32{},
+	}, nil
+}
+
+func (s *ebpfShaper) Ensure(total uint64, classes []Class) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	link, err := system.GetLinkInfoByDefaultRoute()
+	if err != nil {
+		return fmt.Errorf("failed to get link of the default route, err: %w", err)
+	}
+	if link == nil || link.Attrs() == nil {
+		return fmt.Errorf("link of the default route is nil")
+	}
+	if s.link != nil && s.link.Attrs().Index != link.Attrs().Index {
+		if err = deleteQdisc(s.link); err != nil {
+			klog.V(4).Infof("failed to delete qdisc on the previous link %s, err: %v", s.link.Attrs().Name, err)
+		}
+	}
+	s.link = link
+	index := link.Attrs().Index
+
+	if err = ensureRootQdisc(link); err != nil {
+		return err
+	}
+	rootClass := newHtbClass(index, netlink.MakeHandle(HandleMajor, 0), ClassID(RootClassMinor), total, total, 0)
+	if err = netlink.ClassReplace(rootClass); err != nil {
+		return fmt.Errorf("failed to replace root class, err: %w", err)
+	}
+	for _, c := range classes {
+		class := newHtbClass(index, ClassID(RootClassMinor), ClassID(c.Minor), c.Rate, c.Ceil, c.Prio)
+		if err = netlink.ClassReplace(class); err != nil {
+			return fmt.Errorf("failed to replace class %d, err: %w", c.Minor, err)
+		}
+		if err = ensureLeafQdisc(link, c.Minor); err != nil {
+			return err
+		}
+	}
+
+	filter := &netlink.BpfFilter{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: index,
+			Parent:    netlink.MakeHandle(HandleMajor, 0),
+			Handle:    1,
+			Priority:  filterPrio,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Fd:   s.program.FD(),
+		Name: filterName,
+	}
+	if err = netlink.FilterReplace(filter); err != nil {
+		return fmt.Errorf("failed to replace bpf filter, err: %w", err)
+	}
+	return nil
+}
+
+func (s *ebpfShaper) UpdateEndpoints(endpoints map[string]uint16) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	expected := map[[4]byte]uint32{}
+	for ip, minor := range endpoints {
+		key, ok := ipv4Key(ip)
+		if !ok {
+			continue
+		}
+		expected[key] = ClassID(minor)
+	}
+
+	var errs []error
+	for key, classID := range expected {
+		if old, ok := s.endpoints[key]; ok && old == classID {
+			continue
+		}
+		if err := s.endpointMap.Update(key, classID, ebpf.UpdateAny); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update endpoint %s, err: %w", net.IP(key[:]), err))
+			continue
+		}
+		s.endpoints[key] = classID
+	}
+	for key := range s.endpoints {
+		if _, ok := expected[key]; ok {
+			continue
+		}
+		if err := s.endpointMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			errs = append(errs, fmt.Errorf("failed to delete endpoint %s, err: %w", net.IP(key[:]), err))
+			continue
+		}
+		delete(s.endpoints, key)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (s *ebpfShaper) Cleanup() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var errs []error
+	for key := range s.endpoints {
+		if err := s.endpointMap.Delete(key); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		delete(s.endpoints, key)
+	}
+	if s.link != nil {
+		if err := deleteQdisc(s.link); err != nil {
+			errs = append(errs, err)
+		} else {
+			s.link = nil
+		}
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func ensureRootQdisc(link netlink.Link) error {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to list qdisc on %s, err: %w", link.Attrs().Name, err)
+	}
+	handle := netlink.MakeHandle(HandleMajor, 0)
+	for _, qdisc := range qdiscs {
+		if qdisc.Type() == "htb" && qdisc.Attrs().Parent == netlink.HANDLE_ROOT && qdisc.Attrs().Handle == handle {
+			return nil
+		}
+	}
+	htb := netlink.NewHtb(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    handle,
+		Parent:    netlink.HANDLE_ROOT,
+	})
+	htb.Defcls = uint32(SystemClassMinor)
+	if err = netlink.QdiscReplace(htb); err != nil {
+		return fmt.Errorf("failed to replace root qdisc on %s, err: %w", link.Attrs().Name, err)
+	}
+	return nil
+}
+
+func ensureLeafQdisc(link netlink.Link, minor uint16) error {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to list qdisc on %s, err: %w", link.Attrs().Name, err)
+	}
+	parent := ClassID(minor)
+	for _, qdisc := range qdiscs {
+		if qdisc.Type() == "fq" && qdisc.Attrs().Parent == parent {
+			return nil
+		}
+	}
+	fq := netlink.NewFq(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(leafQdiscMajorBase+minor, 0),
+		Parent:    parent,
+	})
+	if err = netlink.QdiscReplace(fq); err != nil {
+		return fmt.Errorf("failed to replace fq qdisc of class %d, err: %w", minor, err)
+	}
+	return nil
+}
+
+func deleteQdisc(link netlink.Link) error {
+	qdiscs, err := netlink.QdiscList(link)
+	if err != nil {
+		return fmt.Errorf("failed to list qdisc on %s, err: %w", link.Attrs().Name, err)
+	}
+	handle := netlink.MakeHandle(HandleMajor, 0)
+	for _, qdisc := range qdiscs {
+		if qdisc.Attrs().Parent == netlink.HANDLE_ROOT && qdisc.Attrs().Handle == handle {
+			return netlink.QdiscDel(qdisc)
+		}
+	}
+	return nil
+}
+
+// newHtbClass builds the htb class with the rate and ceil in bytes per second. The class is not built by
+// netlink.NewHtbClass, which takes the rates in bits per second.
+func newHtbClass(linkIndex int, parent, handle uint32, rate, ceil uint64, prio uint32) *netlink.HtbClass {
+	if ceil < rate {
+		ceil = rate
+	}
+	return &netlink.HtbClass{
+		ClassAttrs: netlink.ClassAttrs{
+			LinkIndex: linkIndex,
+			Parent:    parent,
+			Handle:    handle,
+		},
+		Rate:    rate,
+		Ceil:    ceil,
+		Buffer:  netlink.Xmittime(rate, uint32(float64(rate)/netlink.Hz()+htbMTU)),
+		Cbuffer: netlink.Xmittime(ceil, uint32(float64(ceil)/netlink.Hz()+htbMTU)),
+		Prio:    prio,
+	}
+}
+
+// buildClassifierInstructions builds the cls_bpf program, which returns the class id of the source ipv4 address in
+// the endpoint map. It returns 0 for the other packets, so they are not classified by the filter.
+func buildClassifierInstructions(endpointMapFD int) asm.Instructions {
+	return asm.Instructions{
+		asm.Mov.Reg(asm.R6, asm.R1),
+		asm.LoadMem(asm.R2, asm.R6, skbProtocolOffset, asm.Word),
+		asm.JNE.Imm(asm.R2, int32(htons(unix.ETH_P_IP)), "miss"),
+		// bpf_skb_load_bytes(skb, offset of saddr, fp - 4, 4)
+		asm.Mov.Reg(asm.R1, asm.R6),
+		asm.Mov.Imm(asm.R2, ipv4SrcAddrOffset),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -4),
+		asm.Mov.Imm(asm.R4, 4),
+		asm.FnSkbLoadBytes.Call(),
+		asm.JNE.Imm(asm.R0, 0, "miss"),
+		asm.LoadMapPtr(asm.R1, endpointMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -4),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "miss"),
+		asm.LoadMem(asm.R0, asm.R0, 0, asm.Word),
+		asm.Return(),
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("miss"),
+		asm.Return(),
+	}
+}
+
+// htons returns the value of the network byte order as it is read by the program on the host.
+func htons(v uint16) uint16 {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, v)
+	return *(*uint16)(unsafe.Pointer(&b[0]))
+}
+
+// ipv4Key returns the address bytes in the network byte order, which is the same as the packet.
+func ipv4Key(ip string) ([4]byte, bool) {
+	var key [4]byte
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil {
+		return key, false
+	}
+	copy(key[:], parsed)
+	return key, true
+}
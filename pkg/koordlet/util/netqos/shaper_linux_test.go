@@ -0,0 +1,65 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package netqos
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/cilium/ebpf/asm"
+	"github.com/stretchr/testify/assert"
+	"github.com/vishvananda/netlink"
+)
+
+func TestBuildClassifierInstructions(t *testing.T) {
+	insns := buildClassifierInstructions(3)
+	symbols, err := insns.SymbolOffsets()
+	assert.NoError(t, err)
+	for ref := range insns.ReferenceOffsets() {
+		_, ok := symbols[ref]
+		assert.True(t, ok, "undefined symbol %s", ref)
+	}
+	assert.Equal(t, asm.Return().OpCode, insns[len(insns)-1].OpCode)
+}
+
+func TestIPv4Key(t *testing.T) {
+	key, ok := ipv4Key("10.0.1.2")
+	assert.True(t, ok)
+	assert.Equal(t, [4]byte{10, 0, 1, 2}, key)
+	_, ok = ipv4Key("fd00::1")
+	assert.False(t, ok)
+	_, ok = ipv4Key("invalid")
+	assert.False(t, ok)
+}
+
+func TestHtons(t *testing.T) {
+	v := htons(0x0800)
+	b := (*[2]byte)(unsafe.Pointer(&v))
+	assert.Equal(t, [2]byte{0x08, 0x00}, *b)
+}
+
+func TestNewHtbClass(t *testing.T) {
+	class := newHtbClass(1, ClassID(RootClassMinor), ClassID(LSClassMinor), 1000000, 500000, LSClassPrio)
+	assert.Equal(t, netlink.MakeHandle(HandleMajor, LSClassMinor), class.Handle)
+	assert.Equal(t, uint64(1000000), class.Rate)
+	// ceil is no less than the rate
+	assert.Equal(t, uint64(1000000), class.Ceil)
+	assert.Equal(t, LSClassPrio, class.Prio)
+}
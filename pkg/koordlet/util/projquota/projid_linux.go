@@ -0,0 +1,90 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projquota
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// the ioctl numbers of FS_IOC_FSGETXATTR and FS_IOC_FSSETXATTR, which are supported by XFS and ext4
+	fsIOCFSGetXAttr = 0x801c581f
+	fsIOCFSSetXAttr = 0x401c5820
+	// fsXFlagProjInherit makes the new files and dirs under the dir inherit its project id
+	fsXFlagProjInherit = 0x00000200
+)
+
+// fsXAttr is the struct fsxattr of linux/fs.h.
+type fsXAttr struct {
+	XFlags     uint32
+	ExtSize    uint32
+	NExtents   uint32
+	ProjID     uint32
+	CowExtSize uint32
+	Pad        [8]byte
+}
+
+func getFsXAttr(fd int) (*fsXAttr, error) {
+	attr := &fsXAttr{}
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIOCFSGetXAttr, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return nil, errno
+	}
+	return attr, nil
+}
+
+func openNoFollow(path string) (int, error) {
+	return unix.Open(path, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+}
+
+func getProjectIDByIoctl(path string) (uint32, error) {
+	fd, err := openNoFollow(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s, err: %w", path, err)
+	}
+	defer unix.Close(fd)
+	attr, err := getFsXAttr(fd)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get project id of %s, err: %w", path, err)
+	}
+	return attr.ProjID, nil
+}
+
+func setProjectIDByIoctl(path string, projectID uint32, isDir bool) error {
+	fd, err := openNoFollow(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s, err: %w", path, err)
+	}
+	defer unix.Close(fd)
+	attr, err := getFsXAttr(fd)
+	if err != nil {
+		return fmt.Errorf("failed to get project id of %s, err: %w", path, err)
+	}
+	attr.ProjID = projectID
+	if isDir {
+		attr.XFlags |= fsXFlagProjInherit
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), fsIOCFSSetXAttr, uintptr(unsafe.Pointer(attr))); errno != 0 {
+		return fmt.Errorf("failed to set project id of %s, err: %w", path, errno)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projquota
+
+import "fmt"
+
+func getProjectIDByIoctl(path string) (uint32, error) {
+	return 0, fmt.Errorf("project quota is only supported on linux")
+}
+
+func setProjectIDByIoctl(path string, projectID uint32, isDir bool) error {
+	return fmt.Errorf("project quota is only supported on linux")
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projquota
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	// ProjectIDBase is the first project id managed by koordlet. The ids from 1048577 are allocated by the kubelet
+	// for the emptyDir volumes with the LocalStorageCapacityIsolationFSQuotaMonitoring, and the lower ids are left to
+	// the users, so koordlet manages the range [524288, 1048576) below the ones of the kubelet.
+	ProjectIDBase uint32 = 1 << 19
+	// projectIDRange is the number of project ids managed by koordlet.
+	projectIDRange uint32 = 1 << 19
+
+	// blockSize is the unit of the block numbers reported by xfs_quota.
+	blockSize int64 = 1024
+)
+
+// getProjectID and setProjectID get and set the project id of a file or dir with the ioctls, they are replaced in the
+// tests.
+var (
+	getProjectID = getProjectIDByIoctl
+	setProjectID = setProjectIDByIoctl
+)
+
+// runCommand runs the xfs_quota command and returns its output, it is replaced in the tests.
+var runCommand = func(cmd *exec.Cmd) (string, error) {
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%w, output: %s", err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// Controller manages the project quotas of the dirs on a filesystem which supports project quota, e.g. XFS and
+// ext4 mounted with `prjquota`.
+type Controller interface {
+	// SetQuota assigns the project id to the dir recursively and limits the disk usage of the project with the
+	// hard limit. The sub dirs which already have the other project ids, e.g. the emptyDir volumes limited by the
+	// kubelet, are skipped.
+	SetQuota(fsPath, dir string, projectID uint32, hardLimitBytes int64) error
+	// GetUsage returns the disk usage of the project in bytes.
+	GetUsage(fsPath string, projectID uint32) (int64, error)
+	// RemoveQuota removes the limit of the project.
+	RemoveQuota(fsPath string, projectID uint32) error
+}
+
+var defaultController Controller = &xfsQuotaController{}
+
+func DefaultController() Controller {
+	return defaultController
+}
+
+// ProjectIDAllocator allocates the project ids for the pods.
+// The id is derived from the pod uid so that it keeps stable across restarts in most cases,
+// and the conflicted ones are probed linearly.
+type ProjectIDAllocator struct {
+	lock      sync.Mutex
+	podToID   map[types.UID]uint32
+	allocated map[uint32]types.UID
+}
+
+func NewProjectIDAllocator() *ProjectIDAllocator {
+	return &ProjectIDAllocator{
+		podToID:   map[types.UID]uint32{},
+		allocated: map[uint32]types.UID{},
+	}
+}
+
+// Allocate returns the project id of the pod, a new one is allocated if the pod has none.
+func (a *ProjectIDAllocator) Allocate(podUID types.UID) (uint32, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if id, ok := a.podToID[podUID]; ok {
+		return id, nil
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(podUID))
+	offset := h.Sum32() % projectIDRange
+	for i := uint32(0); i < projectIDRange; i++ {
+		id := ProjectIDBase + (offset+i)%projectIDRange
+		if _, ok := a.allocated[id]; ok {
+			continue
+		}
+		a.podToID[podUID] = id
+		a.allocated[id] = podUID
+		return id, nil
+	}
+	return 0, fmt.Errorf("no project id available for pod %s", podUID)
+}
+
+// Release releases the project id of the pod.
+func (a *ProjectIDAllocator) Release(podUID types.UID) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	if id, ok := a.podToID[podUID]; ok {
+		delete(a.allocated, id)
+		delete(a.podToID, podUID)
+	}
+}
+
+// xfsQuotaController manages the project quotas with the xfs_quota command.
+// The foreign filesystem mode `-f` is always enabled so that it works on both XFS and ext4.
+type xfsQuotaController struct{}
+
+func (c *xfsQuotaController) SetQuota(fsPath, dir string, projectID uint32, hardLimitBytes int64) error {
+	if err := setDirProjectID(dir, projectID); err != nil {
+		return fmt.Errorf("failed to set project %d of dir %s, err: %w", projectID, dir, err)
+	}
+	if _, err := c.run(fsPath, fmt.Sprintf("limit -p bhard=%d %d", hardLimitBytes, projectID)); err != nil {
+		return fmt.Errorf("failed to limit project %d to %d bytes, err: %w", projectID, hardLimitBytes, err)
+	}
+	return nil
+}
+
+func (c *xfsQuotaController) GetUsage(fsPath string, projectID uint32) (int64, error) {
+	out, err := c.run(fsPath, fmt.Sprintf("quota -p -N -n -b %d", projectID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get usage of project %d, err: %w", projectID, err)
+	}
+	return parseQuotaUsage(out)
+}
+
+func (c *xfsQuotaController) RemoveQuota(fsPath string, projectID uint32) error {
+	if _, err := c.run(fsPath, fmt.Sprintf("limit -p bhard=0 %d", projectID)); err != nil {
+		return fmt.Errorf("failed to remove limit of project %d, err: %w", projectID, err)
+	}
+	return nil
+}
+
+// setDirProjectID assigns the project id to the dir tree like `xfs_quota project -s`, while the sub dirs of the other
+// non-zero project ids are not overwritten. The symlinks and the special files are skipped.
+func setDirProjectID(dir string, projectID uint32) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		// the dir itself is owned by koordlet
+		if path != dir {
+			id, err := getProjectID(path)
+			if err != nil {
+				return err
+			}
+			if id == projectID {
+				return nil
+			}
+			if id != 0 {
+				klog.V(5).Infof("skip %s of project %d when setting project %d", path, id, projectID)
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+		return setProjectID(path, projectID, d.IsDir())
+	})
+}
+
+func (c *xfsQuotaController) run(fsPath, command string) (string, error) {
+	return runCommand(exec.Command(system.Conf.XFSQuotaBinaryPath, "-f", "-x", "-c", command, fsPath))
+}
+
+// parseQuotaUsage parses the output of `quota -p -N -n -b`, e.g.
+// `/dev/sdb1  1024  0  2048  00 [--------]`, the second field is the used blocks in KiB.
+// An empty output means the project has no usage.
+func parseQuotaUsage(out string) (int64, error) {
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("invalid quota output %q", line)
+		}
+		blocks, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid quota output %q, err: %w", line, err)
+		}
+		return blocks * blockSize, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package projquota
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestXFSQuotaController(t *testing.T) {
+	var commands []string
+	oldRunCommand := runCommand
+	defer func() { runCommand = oldRunCommand }()
+	podDir := t.TempDir()
+	projectIDs := map[string]uint32{}
+	oldGetProjectID, oldSetProjectID := getProjectID, setProjectID
+	defer func() { getProjectID, setProjectID = oldGetProjectID, oldSetProjectID }()
+	getProjectID = func(path string) (uint32, error) {
+		return projectIDs[path], nil
+	}
+	setProjectID = func(path string, projectID uint32, isDir bool) error {
+		projectIDs[path] = projectID
+		return nil
+	}
+	runCommand = func(cmd *exec.Cmd) (string, error) {
+		assert.Equal(t, []string{"-f", "-x", "-c"}, cmd.Args[1:4])
+		assert.Equal(t, "/var/lib/kubelet/", cmd.Args[5])
+		commands = append(commands, cmd.Args[4])
+		if cmd.Args[4] == fmt.Sprintf("quota -p -N -n -b %d", 524289) {
+			return "/dev/sdb1  2048  0  4096  00 [--------]\n", nil
+		}
+		return "", nil
+	}
+
+	c := DefaultController()
+	err := c.SetQuota("/var/lib/kubelet/", podDir, 524289, 4194304)
+	assert.NoError(t, err)
+	usage, err := c.GetUsage("/var/lib/kubelet/", 524289)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2097152), usage)
+	usage, err = c.GetUsage("/var/lib/kubelet/", 524290)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), usage)
+	err = c.RemoveQuota("/var/lib/kubelet/", 524289)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"limit -p bhard=4194304 524289",
+		"quota -p -N -n -b 524289",
+		"quota -p -N -n -b 524290",
+		"limit -p bhard=0 524289",
+	}, commands)
+	assert.Equal(t, map[string]uint32{podDir: 524289}, projectIDs)
+
+	runCommand = func(cmd *exec.Cmd) (string, error) {
+		return "", fmt.Errorf("expected error")
+	}
+	assert.Error(t, c.SetQuota("/var/lib/kubelet/", podDir, 524289, 4194304))
+	_, err = c.GetUsage("/var/lib/kubelet/", 524289)
+	assert.Error(t, err)
+	assert.Error(t, c.RemoveQuota("/var/lib/kubelet/", 524289))
+}
+
+func Test_setDirProjectID(t *testing.T) {
+	podDir := t.TempDir()
+	volumeDir := filepath.Join(podDir, "volumes", "kubernetes.io~empty-dir", "cache")
+	assert.NoError(t, os.MkdirAll(volumeDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(volumeDir, "data"), []byte("x"), 0644))
+	pluginDir := filepath.Join(podDir, "plugins")
+	assert.NoError(t, os.MkdirAll(pluginDir, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(pluginDir, "file"), []byte("x"), 0644))
+	assert.NoError(t, os.Symlink("/etc/hosts", filepath.Join(podDir, "link")))
+
+	// the emptyDir volume is limited by the kubelet with its own project id
+	projectIDs := map[string]uint32{volumeDir: 1048577}
+	oldGetProjectID, oldSetProjectID := getProjectID, setProjectID
+	defer func() { getProjectID, setProjectID = oldGetProjectID, oldSetProjectID }()
+	getProjectID = func(path string) (uint32, error) {
+		return projectIDs[path], nil
+	}
+	setProjectID = func(path string, projectID uint32, isDir bool) error {
+		projectIDs[path] = projectID
+		return nil
+	}
+
+	assert.NoError(t, setDirProjectID(podDir, 524289))
+	assert.Equal(t, map[string]uint32{
+		podDir:                           524289,
+		filepath.Join(podDir, "volumes"): 524289,
+		filepath.Dir(volumeDir):          524289,
+		volumeDir:                        1048577,
+		pluginDir:                        524289,
+		filepath.Join(pluginDir, "file"): 524289,
+	}, projectIDs)
+}
+
+func Test_parseQuotaUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		out     string
+		want    int64
+		wantErr bool
+	}{
+		{name: "empty output", out: "", want: 0},
+		{name: "parse usage", out: "\n/dev/sdb1  1024  0  2048  00 [--------]\n", want: 1048576},
+		{name: "missing fields", out: "/dev/sdb1\n", wantErr: true},
+		{name: "invalid blocks", out: "/dev/sdb1 xxx 0 2048\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseQuotaUsage(tt.out)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProjectIDAllocator(t *testing.T) {
+	a := NewProjectIDAllocator()
+	id1, err := a.Allocate("pod-1")
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, id1, ProjectIDBase)
+	assert.Less(t, id1, ProjectIDBase+projectIDRange)
+	got, err := a.Allocate("pod-1")
+	assert.NoError(t, err)
+	assert.Equal(t, id1, got)
+
+	// the conflicted id is probed to the next one
+	a.Release("pod-1")
+	a.allocated[id1] = types.UID("pod-x")
+	id2, err := a.Allocate("pod-1")
+	assert.NoError(t, err)
+	assert.NotEqual(t, id1, id2)
+	a.Release("pod-1")
+	_, ok := a.podToID["pod-1"]
+	assert.False(t, ok)
+}
@@ -19,6 +19,8 @@ package resctrl
 import (
 	"encoding/json"
 	"fmt"
+	"math/bits"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -90,6 +92,7 @@ func NewRDTEngine(vendor string) (ResctrlEngine, error) {
 		CBM:        cbm,
 		Cgm:        NewControlGroupManager(),
 		Vendor:     vendor,
+		Capability: GetResctrlCapability(),
 	}, nil
 }
 
@@ -100,6 +103,8 @@ type RDTEngine struct {
 	l          sync.RWMutex
 	CBM        uint
 	Vendor     string
+	// Capability is the resctrl capability of the node to validate the resctrl configs of the pods.
+	Capability *apiext.ResctrlCapability
 }
 
 func (R *RDTEngine) GetApps() map[string]App {
@@ -144,6 +149,10 @@ func (R *RDTEngine) RegisterApp(podid, annotation string, fromNRI bool, updater
 		klog.Errorf("error is %v", err)
 		return err
 	}
+	if err = R.Capability.ValidateResctrlConfig(&res); err != nil {
+		klog.Warningf("invalid resctrl config of pod %s, err: %v", podid, err)
+		return fmt.Errorf("invalid resctrl config, err: %w", err)
+	}
 
 	schemata := R.ParseSchemata(res, R.CBM)
 	app := App{
@@ -313,3 +322,46 @@ func GetNewTaskIds(ids []int32, tasksMap map[int32]struct{}) ([]int32, error) {
 	}
 	return taskIDs, nil
 }
+
+// GetResctrlCapability reads the resctrl capability of the node from the resctrl info.
+// It returns nil if the resctrl is not mounted, and the capabilities not provided by the platform are left zero.
+func GetResctrlCapability() *apiext.ResctrlCapability {
+	if exist, _ := sysutil.PathExists(filepath.Join(sysutil.GetResctrlSubsystemDirPath(), sysutil.RdtInfoDir)); !exist {
+		return nil
+	}
+	capability := &apiext.ResctrlCapability{
+		L3CDPEnabled: sysutil.IsResctrlL3CDPEnabled(),
+	}
+	l3CatDir := sysutil.L3CatDir
+	if capability.L3CDPEnabled {
+		l3CatDir = sysutil.L3CodeCatDir
+	}
+	// the CLOSIDs are shared by all the resources, so only the minimum number of them are available
+	for _, infoDir := range []string{l3CatDir, sysutil.L2CatDir, sysutil.ResctrlMBDir} {
+		numClosIDs, err := sysutil.ReadResctrlInfoInt(infoDir, sysutil.ResctrlNumClosIDsName)
+		if err != nil {
+			klog.V(6).Infof("failed to read resctrl %s num_closids, err: %v", infoDir, err)
+			continue
+		}
+		if capability.NumClosIDs <= 0 || numClosIDs < capability.NumClosIDs {
+			capability.NumClosIDs = numClosIDs
+		}
+	}
+	if cbm, err := sysutil.ReadCatL3CbmString(); err == nil {
+		if mask, err := strconv.ParseUint(cbm, 16, 64); err == nil {
+			capability.L3CacheWays = int64(bits.OnesCount64(mask))
+		} else {
+			klog.V(5).Infof("failed to parse resctrl l3 cbm %s, err: %v", cbm, err)
+		}
+	}
+	if numRMIDs, err := sysutil.ReadResctrlInfoInt(sysutil.ResctrlL3MonDir, sysutil.ResctrlNumRMIDsName); err == nil {
+		capability.NumRMIDs = numRMIDs
+	}
+	if gran, err := sysutil.ReadResctrlInfoInt(sysutil.ResctrlMBDir, sysutil.ResctrlBandwidthGranName); err == nil {
+		capability.MBAGranularity = gran
+	}
+	if minBandwidth, err := sysutil.ReadResctrlInfoInt(sysutil.ResctrlMBDir, sysutil.ResctrlMinBandwidthName); err == nil {
+		capability.MBAMinBandwidth = minBandwidth
+	}
+	return capability
+}
@@ -18,9 +18,13 @@ package resctrl
 
 import (
 	"fmt"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	sysutil "github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
 )
 
 func TestGetNewTaskIds(t *testing.T) {
@@ -65,3 +69,100 @@ func TestGetNewTaskIds(t *testing.T) {
 		})
 	}
 }
+
+func TestRDTEngine_RegisterAppWithCapability(t *testing.T) {
+	engine := &RDTEngine{
+		Apps:       make(map[string]App),
+		CtrlGroups: make(map[string]apiext.Resctrl),
+		CBM:        0xf,
+		Cgm:        NewControlGroupManager(),
+		Capability: &apiext.ResctrlCapability{
+			L3CacheWays:     4,
+			MBAMinBandwidth: 10,
+		},
+	}
+	// the LLC range is less than one cache way
+	err := engine.RegisterApp("pod-1", `{"llc":{"schemata":{"range":[0,20]}}}`, false, nil)
+	assert.Error(t, err)
+	// the MB percent is less than the minimum bandwidth
+	err = engine.RegisterApp("pod-1", `{"mb":{"schemata":{"percent":5}}}`, false, nil)
+	assert.Error(t, err)
+	_, ok := engine.GetApp("pod-1")
+	assert.False(t, ok)
+}
+
+func TestGetResctrlCapability(t *testing.T) {
+	type fields struct {
+		prepareFn func(helper *sysutil.FileTestUtil)
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   *apiext.ResctrlCapability
+	}{
+		{
+			name: "resctrl not mounted",
+			want: nil,
+		},
+		{
+			name: "intel rdt with cat, mba and monitoring",
+			fields: fields{
+				prepareFn: func(helper *sysutil.FileTestUtil) {
+					infoDir := filepath.Join(sysutil.GetResctrlSubsystemDirPath(), sysutil.RdtInfoDir)
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CatDir, sysutil.ResctrlCbmMaskName), "7ff\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CatDir, sysutil.ResctrlNumClosIDsName), "15\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.ResctrlMBDir, sysutil.ResctrlNumClosIDsName), "8\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.ResctrlMBDir, sysutil.ResctrlBandwidthGranName), "10\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.ResctrlMBDir, sysutil.ResctrlMinBandwidthName), "10\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.ResctrlL3MonDir, sysutil.ResctrlNumRMIDsName), "224\n")
+				},
+			},
+			want: &apiext.ResctrlCapability{
+				NumClosIDs:      8,
+				NumRMIDs:        224,
+				L3CacheWays:     11,
+				MBAGranularity:  10,
+				MBAMinBandwidth: 10,
+			},
+		},
+		{
+			name: "cdp enabled",
+			fields: fields{
+				prepareFn: func(helper *sysutil.FileTestUtil) {
+					infoDir := filepath.Join(sysutil.GetResctrlSubsystemDirPath(), sysutil.RdtInfoDir)
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CodeCatDir, sysutil.ResctrlCbmMaskName), "fff\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CodeCatDir, sysutil.ResctrlNumClosIDsName), "8\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3DataCatDir, sysutil.ResctrlCbmMaskName), "fff\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3DataCatDir, sysutil.ResctrlNumClosIDsName), "8\n")
+				},
+			},
+			want: &apiext.ResctrlCapability{
+				NumClosIDs:   8,
+				L3CacheWays:  12,
+				L3CDPEnabled: true,
+			},
+		},
+		{
+			name: "invalid info ignored",
+			fields: fields{
+				prepareFn: func(helper *sysutil.FileTestUtil) {
+					infoDir := filepath.Join(sysutil.GetResctrlSubsystemDirPath(), sysutil.RdtInfoDir)
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CatDir, sysutil.ResctrlCbmMaskName), "invalid\n")
+					helper.WriteFileContents(filepath.Join(infoDir, sysutil.L3CatDir, sysutil.ResctrlNumClosIDsName), "invalid\n")
+				},
+			},
+			want: &apiext.ResctrlCapability{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := sysutil.NewFileTestUtil(t)
+			defer helper.Cleanup()
+			if tt.fields.prepareFn != nil {
+				tt.fields.prepareFn(helper)
+			}
+			got := GetResctrlCapability()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
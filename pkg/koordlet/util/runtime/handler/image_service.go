@@ -0,0 +1,76 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// ImageServiceHandler manages the images via the CRI image service of the container runtime.
+type ImageServiceHandler interface {
+	// PullImage pulls the image if it is not present on the node, and returns the image reference.
+	// The image must be accessible without the registry credentials.
+	PullImage(ctx context.Context, image string) (string, error)
+}
+
+type CRIImageServiceHandler struct {
+	imageServiceClient runtimeapi.ImageServiceClient
+	endpoint           string
+}
+
+func NewCRIImageServiceHandler(endpoint string) (ImageServiceHandler, error) {
+	ep := strings.TrimPrefix(endpoint, "unix://")
+	if _, err := os.Stat(ep); err != nil {
+		return nil, err
+	}
+
+	conn, err := getClientConnection(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %v", err)
+	}
+
+	return &CRIImageServiceHandler{
+		imageServiceClient: runtimeapi.NewImageServiceClient(conn),
+		endpoint:           endpoint,
+	}, nil
+}
+
+func (c *CRIImageServiceHandler) PullImage(ctx context.Context, image string) (string, error) {
+	if image == "" {
+		return "", fmt.Errorf("image cannot be empty")
+	}
+	imageSpec := &runtimeapi.ImageSpec{Image: image}
+
+	statusResp, err := c.imageServiceClient.ImageStatus(ctx, &runtimeapi.ImageStatusRequest{Image: imageSpec})
+	if err != nil {
+		return "", fmt.Errorf("failed to get image status, err: %w", err)
+	}
+	if statusResp.GetImage() != nil && statusResp.GetImage().GetId() != "" {
+		return statusResp.GetImage().GetId(), nil
+	}
+
+	pullResp, err := c.imageServiceClient.PullImage(ctx, &runtimeapi.PullImageRequest{Image: imageSpec})
+	if err != nil {
+		return "", err
+	}
+	return pullResp.GetImageRef(), nil
+}
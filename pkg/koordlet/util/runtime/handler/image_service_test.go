@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	mockclient "github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler/mockclient"
+)
+
+func Test_CRIImageService_PullImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      string
+		statusResp *runtimeapi.ImageStatusResponse
+		statusErr  error
+		pullResp   *runtimeapi.PullImageResponse
+		pullErr    error
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:    "empty image",
+			wantErr: true,
+		},
+		{
+			name:      "failed to get image status",
+			image:     "nginx:1.25",
+			statusErr: fmt.Errorf("expected error"),
+			wantErr:   true,
+		},
+		{
+			name:       "image already present",
+			image:      "nginx:1.25",
+			statusResp: &runtimeapi.ImageStatusResponse{Image: &runtimeapi.Image{Id: "sha256:aaa"}},
+			want:       "sha256:aaa",
+		},
+		{
+			name:       "pull image successfully",
+			image:      "nginx:1.25",
+			statusResp: &runtimeapi.ImageStatusResponse{},
+			pullResp:   &runtimeapi.PullImageResponse{ImageRef: "sha256:bbb"},
+			want:       "sha256:bbb",
+		},
+		{
+			name:       "failed to pull image",
+			image:      "nginx:1.25",
+			statusResp: &runtimeapi.ImageStatusResponse{},
+			pullErr:    fmt.Errorf("expected error"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctl := gomock.NewController(t)
+			defer ctl.Finish()
+			mockImageClient := mockclient.NewMockImageServiceClient(ctl)
+			mockImageClient.EXPECT().ImageStatus(gomock.Any(), gomock.Any()).Return(tt.statusResp, tt.statusErr).AnyTimes()
+			mockImageClient.EXPECT().PullImage(gomock.Any(), gomock.Any()).Return(tt.pullResp, tt.pullErr).AnyTimes()
+
+			imageHandler := CRIImageServiceHandler{imageServiceClient: mockImageClient, endpoint: GetContainerdEndpoint()}
+			got, gotErr := imageHandler.PullImage(context.TODO(), tt.image)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
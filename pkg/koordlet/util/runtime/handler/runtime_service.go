@@ -0,0 +1,34 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handler
+
+import (
+	"os"
+	"strings"
+
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// NewCRIRuntimeServiceClient returns the client of the CRI runtime service on the endpoint, which is used to list the
+// pod sandboxes and containers without the kubelet.
+func NewCRIRuntimeServiceClient(endpoint string) (runtimeapi.RuntimeServiceClient, error) {
+	ep := strings.TrimPrefix(endpoint, "unix://")
+	if _, err := os.Stat(ep); err != nil {
+		return nil, err
+	}
+	return getRuntimeClient(endpoint)
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/runtime/handler"
 
+	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
@@ -33,6 +34,8 @@ var (
 	ContainerdHandler handler.ContainerRuntimeHandler
 	PouchHandler      handler.ContainerRuntimeHandler
 	CrioHandler       handler.ContainerRuntimeHandler
+	ImageHandler      handler.ImageServiceHandler
+	RuntimeClient     runtimeapi.RuntimeServiceClient
 	mutex             = &sync.Mutex{}
 )
 
@@ -192,6 +195,60 @@ func getCrioEndpoint() (string, error) {
 	return "", fmt.Errorf("cri-o endpoint does not exist")
 }
 
+// GetImageServiceHandler returns the CRI image service handler of containerd or cri-o.
+func GetImageServiceHandler() (handler.ImageServiceHandler, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if ImageHandler != nil {
+		return ImageHandler, nil
+	}
+
+	unixEndpoint, err := getContainerdEndpoint()
+	if err != nil {
+		unixEndpoint, err = getCrioEndpoint()
+	}
+	if err != nil {
+		klog.Errorf("failed to get the cri endpoint of image service, error: %v", err)
+		return nil, err
+	}
+
+	ImageHandler, err = handler.NewCRIImageServiceHandler(unixEndpoint)
+	if err != nil {
+		klog.Errorf("failed to create image service handler, error: %v", err)
+		return nil, err
+	}
+
+	return ImageHandler, nil
+}
+
+// GetRuntimeServiceClient returns the CRI runtime service client of containerd or cri-o.
+func GetRuntimeServiceClient() (runtimeapi.RuntimeServiceClient, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if RuntimeClient != nil {
+		return RuntimeClient, nil
+	}
+
+	unixEndpoint, err := getContainerdEndpoint()
+	if err != nil {
+		unixEndpoint, err = getCrioEndpoint()
+	}
+	if err != nil {
+		klog.Errorf("failed to get the cri endpoint of runtime service, error: %v", err)
+		return nil, err
+	}
+
+	RuntimeClient, err = handler.NewCRIRuntimeServiceClient(unixEndpoint)
+	if err != nil {
+		klog.Errorf("failed to create runtime service client, error: %v", err)
+		return nil, err
+	}
+
+	return RuntimeClient, nil
+}
+
 func isFile(path string) bool {
 	s, err := os.Stat(path)
 	if err != nil || s == nil {
@@ -185,6 +185,48 @@ func Test_GetRuntimeHandler(t *testing.T) {
 	}
 }
 
+func Test_GetImageServiceHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		endPoint  string
+		expectErr bool
+	}{
+		{
+			name:      "no cri endpoint",
+			endPoint:  "/var/run/docker.sock",
+			expectErr: true,
+		},
+		{
+			name:     "containerd endpoint",
+			endPoint: "/var/run/containerd/containerd.sock",
+		},
+		{
+			name:     "cri-o endpoint",
+			endPoint: "/var/run/crio/crio.sock",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helper := system.NewFileTestUtil(t)
+			ImageHandler = nil
+			defer func() { ImageHandler = nil }()
+			system.Conf.VarRunRootDir = filepath.Join(helper.TempDir, "/var/run")
+			helper.WriteFileContents(tt.endPoint, "test")
+
+			stubs := gostub.Stub(&handler.GrpcDial, func(context context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+				return &grpc.ClientConn{}, nil
+			})
+			defer stubs.Reset()
+
+			gotHandler, gotErr := GetImageServiceHandler()
+			assert.Equal(t, tt.expectErr, gotErr != nil, gotErr)
+			if !tt.expectErr {
+				assert.NotNil(t, gotHandler)
+			}
+		})
+	}
+}
+
 func dockerStub() *gostub.Stubs {
 	return gostub.Stub(&handler.GetDockerClient, func(httpClient *http.Client, endPoint string) (*dclient.Client, error) {
 		info := func(req *http.Request) (*http.Response, error) {
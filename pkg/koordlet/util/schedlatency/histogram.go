@@ -0,0 +1,91 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"time"
+)
+
+// NumSlots is the number of log2 slots of the latency histogram. The slot i counts the latencies in
+// [2^i, 2^(i+1)) nanoseconds, except that the slot 0 counts [0, 2) and the last slot counts all the larger ones.
+const NumSlots = 32
+
+// Histogram is the log2 histogram of the run queue latencies in nanoseconds.
+type Histogram struct {
+	Buckets [NumSlots]uint64
+}
+
+// Add adds the count to the slot.
+func (h *Histogram) Add(slot uint32, count uint64) {
+	if slot >= NumSlots {
+		slot = NumSlots - 1
+	}
+	h.Buckets[slot] += count
+}
+
+// Merge adds the counts of another histogram.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil {
+		return
+	}
+	for i, c := range other.Buckets {
+		h.Buckets[i] += c
+	}
+}
+
+// Count returns the total count of the histogram.
+func (h *Histogram) Count() uint64 {
+	var total uint64
+	for _, c := range h.Buckets {
+		total += c
+	}
+	return total
+}
+
+// Quantile estimates the latency at the quantile q in (0, 1] by interpolating linearly inside the slot.
+// It returns 0 if the histogram is empty.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	total := h.Count()
+	if total == 0 {
+		return 0
+	}
+	if q > 1 {
+		q = 1
+	}
+	rank := q * float64(total)
+	var cumulative float64
+	for i, c := range h.Buckets {
+		if c == 0 {
+			continue
+		}
+		if cumulative+float64(c) >= rank {
+			lower, upper := slotBounds(i)
+			fraction := (rank - cumulative) / float64(c)
+			return time.Duration(lower + (upper-lower)*fraction)
+		}
+		cumulative += float64(c)
+	}
+	_, upper := slotBounds(NumSlots - 1)
+	return time.Duration(upper)
+}
+
+func slotBounds(slot int) (float64, float64) {
+	if slot == 0 {
+		return 0, 2
+	}
+	return float64(uint64(1) << slot), float64(uint64(1) << (slot + 1))
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	h := &Histogram{}
+	assert.Equal(t, time.Duration(0), h.Quantile(0.5))
+
+	// 50 samples in [1us, 2us), 45 samples in [8us, 16us), 5 samples in [1ms, 2ms)
+	h.Add(10, 50)
+	h.Add(13, 45)
+	other := &Histogram{}
+	other.Add(20, 5)
+	h.Merge(other)
+	h.Merge(nil)
+	assert.Equal(t, uint64(100), h.Count())
+
+	assert.Equal(t, time.Duration(2048), h.Quantile(0.5))
+	assert.Equal(t, time.Duration(16384), h.Quantile(0.95))
+	assert.Equal(t, time.Duration(1048576+1048576*4/5), h.Quantile(0.99))
+	assert.Equal(t, time.Duration(1048576*2), h.Quantile(1.5))
+
+	// the slots out of range are counted in the last slot
+	h = &Histogram{}
+	h.Add(NumSlots+1, 1)
+	assert.Equal(t, uint64(1), h.Buckets[NumSlots-1])
+	h = &Histogram{}
+	h.Add(0, 2)
+	assert.Equal(t, time.Duration(1), h.Quantile(0.5))
+}
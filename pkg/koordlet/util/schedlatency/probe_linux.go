@@ -0,0 +1,350 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cilium/ebpf"
+	"github.com/cilium/ebpf/asm"
+	"github.com/cilium/ebpf/link"
+	"github.com/cilium/ebpf/rlimit"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+)
+
+const (
+	startMapMaxEntries = 10240
+	histMapMaxEntries  = 65536
+
+	bpfAny     = 0
+	bpfNoExist = 1
+
+	programLicense = "Dual BSD/GPL"
+)
+
+// histKey is the key of the histogram map, whose layout must be consistent with the bpf program.
+type histKey struct {
+	Pid  uint32
+	Slot uint32
+}
+
+// Probe records the run queue latencies of the tasks with the eBPF programs attached to the sched tracepoints.
+// A task enqueues when it is woken up or preempted, and dequeues when it is switched in. The latencies are
+// accumulated into the per-pid log2 histograms in the kernel until drained.
+type Probe struct {
+	startMap *ebpf.Map
+	histMap  *ebpf.Map
+	programs []*ebpf.Program
+	links    []link.Link
+}
+
+// NewProbe loads the eBPF programs and attaches them to the sched_wakeup, sched_wakeup_new and sched_switch
+// tracepoints. It requires the CAP_BPF (or CAP_SYS_ADMIN) and the tracefs mounted on the host.
+func NewProbe() (*Probe, error) {
+	wakeupFields, err := getTracepointFields("sched", "sched_wakeup")
+	if err != nil {
+		return nil, err
+	}
+	wakeupNewFields, err := getTracepointFields("sched", "sched_wakeup_new")
+	if err != nil {
+		return nil, err
+	}
+	switchFields, err := getTracepointFields("sched", "sched_switch")
+	if err != nil {
+		return nil, err
+	}
+	if err = rlimit.RemoveMemlock(); err != nil {
+		return nil, fmt.Errorf("failed to remove memlock limit, err: %w", err)
+	}
+
+	p := &Probe{}
+	p.startMap, err = ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "rq_start",
+		Type:       ebpf.LRUHash,
+		KeySize:    4,
+		ValueSize:  8,
+		MaxEntries: startMapMaxEntries,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create start map, err: %w", err)
+	}
+	p.histMap, err = ebpf.NewMap(&ebpf.MapSpec{
+		Name:       "rq_hist",
+		Type:       ebpf.Hash,
+		KeySize:    8,
+		ValueSize:  8,
+		MaxEntries: histMapMaxEntries,
+	})
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("failed to create histogram map, err: %w", err)
+	}
+
+	wakeupInsns, err := buildWakeupInstructions(p.startMap.FD(), wakeupFields)
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("failed to build sched_wakeup program, err: %w", err)
+	}
+	wakeupNewInsns, err := buildWakeupInstructions(p.startMap.FD(), wakeupNewFields)
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("failed to build sched_wakeup_new program, err: %w", err)
+	}
+	switchInsns, err := buildSwitchInstructions(p.startMap.FD(), p.histMap.FD(), switchFields)
+	if err != nil {
+		_ = p.Close()
+		return nil, fmt.Errorf("failed to build sched_switch program, err: %w", err)
+	}
+
+	tracepoints := []struct {
+		name    string
+		program string
+		insns   asm.Instructions
+	}{
+		{name: "sched_wakeup", program: "rq_wakeup", insns: wakeupInsns},
+		{name: "sched_wakeup_new", program: "rq_wakeup_new", insns: wakeupNewInsns},
+		{name: "sched_switch", program: "rq_switch", insns: switchInsns},
+	}
+	for _, tp := range tracepoints {
+		prog, err := ebpf.NewProgram(&ebpf.ProgramSpec{
+			Name:         tp.program,
+			Type:         ebpf.TracePoint,
+			Instructions: tp.insns,
+			License:      programLicense,
+		})
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("failed to load program for tracepoint %s, err: %w", tp.name, err)
+		}
+		p.programs = append(p.programs, prog)
+		l, err := link.Tracepoint("sched", tp.name, prog, nil)
+		if err != nil {
+			_ = p.Close()
+			return nil, fmt.Errorf("failed to attach tracepoint %s, err: %w", tp.name, err)
+		}
+		p.links = append(p.links, l)
+	}
+	return p, nil
+}
+
+// Drain returns the latency histograms of the pids recorded since the last drain, and resets them.
+// The latencies recorded during the draining may be dropped.
+func (p *Probe) Drain() (map[uint32]*Histogram, error) {
+	result := map[uint32]*Histogram{}
+	var keys []histKey
+	var key histKey
+	var count uint64
+	iter := p.histMap.Iterate()
+	for iter.Next(&key, &count) {
+		h, ok := result[key.Pid]
+		if !ok {
+			h = &Histogram{}
+			result[key.Pid] = h
+		}
+		h.Add(key.Slot, count)
+		keys = append(keys, key)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate histogram map, err: %w", err)
+	}
+	for _, k := range keys {
+		if err := p.histMap.Delete(k); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+			klog.V(5).Infof("failed to delete histogram of pid %d slot %d, err: %v", k.Pid, k.Slot, err)
+		}
+	}
+	return result, nil
+}
+
+// Close detaches the programs and releases the maps.
+func (p *Probe) Close() error {
+	var errs []error
+	for _, l := range p.links {
+		if err := l.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.links = nil
+	for _, prog := range p.programs {
+		if err := prog.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.programs = nil
+	for _, m := range []*ebpf.Map{p.startMap, p.histMap} {
+		if m == nil {
+			continue
+		}
+		if err := m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	p.startMap, p.histMap = nil, nil
+	return utilerrors.NewAggregate(errs)
+}
+
+// buildWakeupInstructions builds the program recording the time when the woken task enqueues.
+func buildWakeupInstructions(startMapFD int, fields map[string]tracepointField) (asm.Instructions, error) {
+	pid, pidSize, err := getLoadField(fields, "pid")
+	if err != nil {
+		return nil, err
+	}
+	insns := asm.Instructions{
+		asm.LoadMem(asm.R6, asm.R1, pid, pidSize),
+		asm.JEq.Imm(asm.R6, 0, "exit"),
+	}
+	insns = append(insns, recordStartInstructions(startMapFD, asm.R6)...)
+	insns = append(insns,
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("exit"),
+		asm.Return(),
+	)
+	return insns, nil
+}
+
+// buildSwitchInstructions builds the program which records the enqueue time of the preempted task, and
+// accumulates the run queue latency of the next task into the histogram.
+func buildSwitchInstructions(startMapFD, histMapFD int, fields map[string]tracepointField) (asm.Instructions, error) {
+	prevPid, prevPidSize, err := getLoadField(fields, "prev_pid")
+	if err != nil {
+		return nil, err
+	}
+	prevState, prevStateSize, err := getLoadField(fields, "prev_state")
+	if err != nil {
+		return nil, err
+	}
+	nextPid, nextPidSize, err := getLoadField(fields, "next_pid")
+	if err != nil {
+		return nil, err
+	}
+
+	insns := asm.Instructions{
+		asm.Mov.Reg(asm.R9, asm.R1),
+		// the preempted task is still runnable and enqueues again
+		asm.LoadMem(asm.R7, asm.R9, prevState, prevStateSize),
+		asm.JNE.Imm(asm.R7, 0, "next"),
+		asm.LoadMem(asm.R6, asm.R9, prevPid, prevPidSize),
+		asm.JEq.Imm(asm.R6, 0, "next"),
+	}
+	insns = append(insns, recordStartInstructions(startMapFD, asm.R6)...)
+	insns = append(insns,
+		// the next task dequeues, r7 = now - start
+		asm.LoadMem(asm.R6, asm.R9, nextPid, nextPidSize).WithSymbol("next"),
+		asm.JEq.Imm(asm.R6, 0, "exit"),
+		asm.StoreMem(asm.RFP, -4, asm.R6, asm.Word),
+		asm.LoadMapPtr(asm.R1, startMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -4),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "exit"),
+		asm.LoadMem(asm.R7, asm.R0, 0, asm.DWord),
+		asm.FnKtimeGetNs.Call(),
+		asm.Sub.Reg(asm.R0, asm.R7),
+		asm.Mov.Reg(asm.R7, asm.R0),
+		asm.LoadMapPtr(asm.R1, startMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -4),
+		asm.FnMapDeleteElem.Call(),
+		// r8 = log2(r7), unrolled since the older verifiers reject the loops
+		asm.Mov.Imm(asm.R8, 0),
+	)
+	label := ""
+	for _, shift := range []int32{32, 16, 8, 4, 2, 1} {
+		first := asm.Mov.Reg(asm.R1, asm.R7)
+		if label != "" {
+			first = first.WithSymbol(label)
+		}
+		label = fmt.Sprintf("log2_%d", shift)
+		insns = append(insns,
+			first,
+			asm.RSh.Imm(asm.R1, shift),
+			asm.JEq.Imm(asm.R1, 0, label),
+			asm.Mov.Reg(asm.R7, asm.R1),
+			asm.Add.Imm(asm.R8, shift),
+		)
+	}
+	insns = append(insns,
+		asm.JLT.Imm(asm.R8, NumSlots, "slot").WithSymbol(label),
+		asm.Mov.Imm(asm.R8, NumSlots-1),
+		// histogram[{pid, slot}] += 1
+		asm.StoreMem(asm.RFP, -24, asm.R6, asm.Word).WithSymbol("slot"),
+		asm.StoreMem(asm.RFP, -20, asm.R8, asm.Word),
+		asm.LoadMapPtr(asm.R1, histMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -24),
+		asm.FnMapLookupElem.Call(),
+		asm.JEq.Imm(asm.R0, 0, "init"),
+		asm.Mov.Imm(asm.R1, 1),
+		asm.StoreXAdd(asm.R0, asm.R1, asm.DWord),
+		asm.Ja.Label("exit"),
+		asm.StoreImm(asm.RFP, -32, 1, asm.DWord).WithSymbol("init"),
+		asm.LoadMapPtr(asm.R1, histMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -24),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -32),
+		asm.Mov.Imm(asm.R4, bpfNoExist),
+		asm.FnMapUpdateElem.Call(),
+		asm.Mov.Imm(asm.R0, 0).WithSymbol("exit"),
+		asm.Return(),
+	)
+	return insns, nil
+}
+
+// recordStartInstructions records the current time of the pid in pidReg into the start map.
+// The pidReg must be a callee saved register.
+func recordStartInstructions(startMapFD int, pidReg asm.Register) asm.Instructions {
+	return asm.Instructions{
+		asm.StoreMem(asm.RFP, -4, pidReg, asm.Word),
+		asm.FnKtimeGetNs.Call(),
+		asm.StoreMem(asm.RFP, -16, asm.R0, asm.DWord),
+		asm.LoadMapPtr(asm.R1, startMapFD),
+		asm.Mov.Reg(asm.R2, asm.RFP),
+		asm.Add.Imm(asm.R2, -4),
+		asm.Mov.Reg(asm.R3, asm.RFP),
+		asm.Add.Imm(asm.R3, -16),
+		asm.Mov.Imm(asm.R4, bpfAny),
+		asm.FnMapUpdateElem.Call(),
+	}
+}
+
+func getLoadField(fields map[string]tracepointField, name string) (int16, asm.Size, error) {
+	field, ok := fields[name]
+	if !ok {
+		return 0, 0, fmt.Errorf("field %s not found in tracepoint format", name)
+	}
+	var size asm.Size
+	switch field.Size {
+	case 1:
+		size = asm.Byte
+	case 2:
+		size = asm.Half
+	case 4:
+		size = asm.Word
+	case 8:
+		size = asm.DWord
+	default:
+		return 0, 0, fmt.Errorf("unsupported size %d of field %s", field.Size, name)
+	}
+	if field.Offset > 1<<15-1 {
+		return 0, 0, fmt.Errorf("unsupported offset %d of field %s", field.Offset, name)
+	}
+	return int16(field.Offset), size, nil
+}
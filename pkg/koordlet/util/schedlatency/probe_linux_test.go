@@ -0,0 +1,64 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"testing"
+
+	"github.com/cilium/ebpf/asm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildInstructions(t *testing.T) {
+	wakeupFields := map[string]tracepointField{
+		"comm": {Offset: 8, Size: 16},
+		"pid":  {Offset: 24, Size: 4},
+	}
+	switchFields := map[string]tracepointField{
+		"prev_pid":   {Offset: 24, Size: 4},
+		"prev_state": {Offset: 32, Size: 8},
+		"next_pid":   {Offset: 56, Size: 4},
+	}
+	wakeupInsns, err := buildWakeupInstructions(3, wakeupFields)
+	assert.NoError(t, err)
+	switchInsns, err := buildSwitchInstructions(3, 4, switchFields)
+	assert.NoError(t, err)
+	for name, insns := range map[string]asm.Instructions{
+		"wakeup": wakeupInsns,
+		"switch": switchInsns,
+	} {
+		symbols, err := insns.SymbolOffsets()
+		assert.NoError(t, err, name)
+		for ref := range insns.ReferenceOffsets() {
+			_, ok := symbols[ref]
+			assert.True(t, ok, "%s: undefined symbol %s", name, ref)
+		}
+		assert.Equal(t, asm.Return().OpCode, insns[len(insns)-1].OpCode, name)
+	}
+
+	_, err = buildWakeupInstructions(3, map[string]tracepointField{})
+	assert.Error(t, err)
+	_, err = buildSwitchInstructions(3, 4, map[string]tracepointField{
+		"prev_pid":   {Offset: 24, Size: 4},
+		"prev_state": {Offset: 32, Size: 3},
+		"next_pid":   {Offset: 56, Size: 4},
+	})
+	assert.Error(t, err)
+}
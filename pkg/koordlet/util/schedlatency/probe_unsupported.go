@@ -0,0 +1,37 @@
+//go:build !linux
+// +build !linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import "fmt"
+
+// Probe records the run queue latencies of the tasks, which is only supported on linux.
+type Probe struct{}
+
+func NewProbe() (*Probe, error) {
+	return nil, fmt.Errorf("sched latency probe is only supported on linux")
+}
+
+func (p *Probe) Drain() (map[uint32]*Histogram, error) {
+	return nil, fmt.Errorf("sched latency probe is only supported on linux")
+}
+
+func (p *Probe) Close() error {
+	return nil
+}
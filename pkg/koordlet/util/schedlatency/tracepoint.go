@@ -0,0 +1,97 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+// tracingDirs are the candidate mount points of the tracefs relative to the sys root dir.
+var tracingDirs = []string{"kernel/tracing", "kernel/debug/tracing"}
+
+// tracepointField is the layout of a field in the raw tracepoint context.
+type tracepointField struct {
+	Offset int
+	Size   int
+}
+
+// getTracepointFields returns the field layouts of the tracepoint from its format file in the tracefs,
+// so that the probe does not depend on the kernel version or the BTF.
+func getTracepointFields(group, name string) (map[string]tracepointField, error) {
+	var errs []string
+	for _, dir := range tracingDirs {
+		formatPath := filepath.Join(system.Conf.SysRootDir, dir, "events", group, name, "format")
+		content, err := os.ReadFile(formatPath)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return parseTracepointFormat(string(content))
+	}
+	return nil, fmt.Errorf("failed to read format of tracepoint %s/%s, err: %s", group, name, strings.Join(errs, "; "))
+}
+
+// parseTracepointFormat parses the fields of the tracepoint format, e.g.
+// "	field:pid_t pid;	offset:24;	size:4;	signed:1;".
+func parseTracepointFormat(content string) (map[string]tracepointField, error) {
+	fields := map[string]tracepointField{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "field:") {
+			continue
+		}
+		var name string
+		field := tracepointField{Offset: -1, Size: -1}
+		for _, part := range strings.Split(line, ";") {
+			part = strings.TrimSpace(part)
+			key, value, found := strings.Cut(part, ":")
+			if !found {
+				continue
+			}
+			var err error
+			switch key {
+			case "field":
+				decl := strings.Fields(value)
+				if len(decl) == 0 {
+					return nil, fmt.Errorf("invalid field line %q", line)
+				}
+				name = decl[len(decl)-1]
+				if i := strings.Index(name, "["); i >= 0 {
+					name = name[:i]
+				}
+			case "offset":
+				field.Offset, err = strconv.Atoi(value)
+			case "size":
+				field.Size, err = strconv.Atoi(value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid field line %q, err: %w", line, err)
+			}
+		}
+		if name == "" || field.Offset < 0 || field.Size <= 0 {
+			return nil, fmt.Errorf("invalid field line %q", line)
+		}
+		fields[name] = field
+	}
+	return fields, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedlatency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const testSchedSwitchFormat = `name: sched_switch
+ID: 316
+format:
+	field:unsigned short common_type;	offset:0;	size:2;	signed:0;
+	field:unsigned char common_flags;	offset:2;	size:1;	signed:0;
+	field:unsigned char common_preempt_count;	offset:3;	size:1;	signed:0;
+	field:int common_pid;	offset:4;	size:4;	signed:1;
+
+	field:char prev_comm[16];	offset:8;	size:16;	signed:1;
+	field:pid_t prev_pid;	offset:24;	size:4;	signed:1;
+	field:int prev_prio;	offset:28;	size:4;	signed:1;
+	field:long prev_state;	offset:32;	size:8;	signed:1;
+	field:char next_comm[16];	offset:40;	size:16;	signed:1;
+	field:pid_t next_pid;	offset:56;	size:4;	signed:1;
+	field:int next_prio;	offset:60;	size:4;	signed:1;
+
+print fmt: "prev_comm=%s prev_pid=%d prev_prio=%d prev_state=%s%s ==> next_comm=%s next_pid=%d next_prio=%d"
+`
+
+func TestGetTracepointFields(t *testing.T) {
+	helper := system.NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := getTracepointFields("sched", "sched_switch")
+	assert.Error(t, err)
+
+	helper.WriteFileContents("kernel/tracing/events/sched/sched_switch/format", testSchedSwitchFormat)
+	fields, err := getTracepointFields("sched", "sched_switch")
+	assert.NoError(t, err)
+	assert.Equal(t, tracepointField{Offset: 8, Size: 16}, fields["prev_comm"])
+	assert.Equal(t, tracepointField{Offset: 24, Size: 4}, fields["prev_pid"])
+	assert.Equal(t, tracepointField{Offset: 32, Size: 8}, fields["prev_state"])
+	assert.Equal(t, tracepointField{Offset: 56, Size: 4}, fields["next_pid"])
+	assert.Len(t, fields, 11)
+
+	_, err = parseTracepointFormat("format:\n\tfield:pid_t pid;\toffset:x;\tsize:4;\n")
+	assert.Error(t, err)
+	_, err = parseTracepointFormat("format:\n\tfield:pid_t pid;\tsize:4;\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The AMD GPUs are discovered from the KFD (Kernel Fusion Driver) topology and the amdgpu sysfs attributes of the
+// PCI devices, which are also the sources of the rocm-smi.
+const (
+	SysKFDTopologyNodesSubDir = "class/kfd/kfd/topology/nodes"
+	SysKFDProcSubDir          = "class/kfd/kfd/proc"
+	SysAMDGPUVersionSubPath   = "module/amdgpu/version"
+
+	kfdNodeGPUIDFile      = "gpu_id"
+	kfdNodeNameFile       = "name"
+	kfdNodePropertiesFile = "properties"
+
+	amdGPUBusyPercentFile = "gpu_busy_percent"
+	amdGPUVRAMTotalFile   = "mem_info_vram_total"
+	amdGPUVRAMUsedFile    = "mem_info_vram_used"
+	amdGPUProductNameFile = "product_name"
+
+	// AMDGPUVendorID is the PCI vendor id of the AMD (0x1002).
+	AMDGPUVendorID = 4098
+)
+
+// AMDGPUInfo is the static information of an AMD GPU.
+type AMDGPUInfo struct {
+	// GPUID is the id of the GPU in the KFD topology, which is used by the KFD process stats.
+	GPUID uint64
+	// BusID is the PCI address of the GPU, e.g. 0000:03:00.0.
+	BusID string
+	// UniqueID is the unique id (serial) of the GPU, which can be zero for the GPUs not supporting it.
+	UniqueID uint64
+	// ComputeUnits is the number of compute units of the GPU.
+	ComputeUnits uint64
+	// Name is the gfx target name of the GPU, e.g. gfx90a.
+	Name string
+}
+
+func GetKFDTopologyNodesDir() string {
+	return filepath.Join(Conf.SysRootDir, SysKFDTopologyNodesSubDir)
+}
+
+func GetKFDProcDir() string {
+	return filepath.Join(Conf.SysRootDir, SysKFDProcSubDir)
+}
+
+func GetAMDGPUVersionPath() string {
+	return filepath.Join(Conf.SysRootDir, SysAMDGPUVersionSubPath)
+}
+
+// GetAMDGPUInfos returns the AMD GPUs in the KFD topology ordered by the PCI address.
+// It returns an empty list without error if the KFD is not loaded.
+func GetAMDGPUInfos() ([]AMDGPUInfo, error) {
+	nodeDirs, err := os.ReadDir(GetKFDTopologyNodesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read kfd topology nodes, err: %w", err)
+	}
+
+	var gpus []AMDGPUInfo
+	for _, nodeDir := range nodeDirs {
+		nodePath := filepath.Join(GetKFDTopologyNodesDir(), nodeDir.Name())
+		gpuID, err := readUint64File(filepath.Join(nodePath, kfdNodeGPUIDFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read gpu id of kfd node %s, err: %w", nodeDir.Name(), err)
+		}
+		if gpuID == 0 { // the cpu node
+			continue
+		}
+		properties, err := readKFDNodeProperties(filepath.Join(nodePath, kfdNodePropertiesFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read properties of kfd node %s, err: %w", nodeDir.Name(), err)
+		}
+		if properties["vendor_id"] != AMDGPUVendorID {
+			continue
+		}
+		var computeUnits uint64
+		if simdPerCU := properties["simd_per_cu"]; simdPerCU > 0 {
+			computeUnits = properties["simd_count"] / simdPerCU
+		}
+		name, _ := os.ReadFile(filepath.Join(nodePath, kfdNodeNameFile))
+		locationID := properties["location_id"]
+		gpus = append(gpus, AMDGPUInfo{
+			GPUID: gpuID,
+			// location_id is the (bus << 8 | devfn) of the device
+			BusID: fmt.Sprintf("%04x:%02x:%02x.%x", properties["domain"],
+				locationID>>8, (locationID>>3)&0x1f, locationID&0x7),
+			UniqueID:     properties["unique_id"],
+			ComputeUnits: computeUnits,
+			Name:         string(bytes.TrimSpace(name)),
+		})
+	}
+	sort.Slice(gpus, func(i, j int) bool {
+		return gpus[i].BusID < gpus[j].BusID
+	})
+	return gpus, nil
+}
+
+func readKFDNodeProperties(path string) (map[string]uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	properties := map[string]uint64{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		properties[fields[0]] = value
+	}
+	return properties, nil
+}
+
+// GetAMDGPUBusyPercent returns the percentage of the time that the GPU is busy.
+func GetAMDGPUBusyPercent(busID string) (uint64, error) {
+	return readUint64File(filepath.Join(GetPCIDeviceDir(), busID, amdGPUBusyPercentFile))
+}
+
+// GetAMDGPUVRAM returns the total and the used bytes of the VRAM of the GPU.
+func GetAMDGPUVRAM(busID string) (uint64, uint64, error) {
+	total, err := readUint64File(filepath.Join(GetPCIDeviceDir(), busID, amdGPUVRAMTotalFile))
+	if err != nil {
+		return 0, 0, err
+	}
+	used, err := readUint64File(filepath.Join(GetPCIDeviceDir(), busID, amdGPUVRAMUsedFile))
+	if err != nil {
+		return 0, 0, err
+	}
+	return total, used, nil
+}
+
+// GetAMDGPUProductName returns the product name of the GPU, e.g. "AMD Instinct MI210".
+// The attribute is only available on the newer kernels.
+func GetAMDGPUProductName(busID string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(GetPCIDeviceDir(), busID, amdGPUProductNameFile))
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(content)), nil
+}
+
+// GetAMDGPUDriverVersion returns the version of the amdgpu kernel module.
+func GetAMDGPUDriverVersion() (string, error) {
+	content, err := os.ReadFile(GetAMDGPUVersionPath())
+	if err != nil {
+		return "", err
+	}
+	return string(bytes.TrimSpace(content)), nil
+}
+
+// AMDGPUProcessUsage is the usage of a process on an AMD GPU.
+type AMDGPUProcessUsage struct {
+	// VRAMUsed is the used bytes of the VRAM.
+	VRAMUsed uint64
+	// CUOccupancy is the number of the compute units occupied by the process.
+	CUOccupancy uint64
+}
+
+// GetAMDGPUProcessUsages returns the usages of the processes running on the AMD GPUs, keyed by the pid and the gpu id.
+func GetAMDGPUProcessUsages() (map[uint32]map[uint64]*AMDGPUProcessUsage, error) {
+	procDirs, err := os.ReadDir(GetKFDProcDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read kfd proc dir, err: %w", err)
+	}
+
+	usages := map[uint32]map[uint64]*AMDGPUProcessUsage{}
+	for _, procDir := range procDirs {
+		pid, err := strconv.ParseUint(procDir.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		procPath := filepath.Join(GetKFDProcDir(), procDir.Name())
+		vramFiles, err := filepath.Glob(filepath.Join(procPath, "vram_*"))
+		if err != nil {
+			return nil, err
+		}
+		for _, vramFile := range vramFiles {
+			gpuID, err := strconv.ParseUint(strings.TrimPrefix(filepath.Base(vramFile), "vram_"), 10, 64)
+			if err != nil {
+				continue
+			}
+			vramUsed, err := readUint64File(vramFile)
+			if err != nil {
+				// the process may exit during the collection
+				continue
+			}
+			// cu_occupancy is missing on the old kernels
+			cuOccupancy, _ := readUint64File(filepath.Join(procPath, fmt.Sprintf("stats_%d", gpuID), "cu_occupancy"))
+			if _, ok := usages[uint32(pid)]; !ok {
+				usages[uint32(pid)] = map[uint64]*AMDGPUProcessUsage{}
+			}
+			usages[uint32(pid)][gpuID] = &AMDGPUProcessUsage{
+				VRAMUsed:    vramUsed,
+				CUOccupancy: cuOccupancy,
+			}
+		}
+	}
+	return usages, nil
+}
+
+func readUint64File(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(bytes.TrimSpace(content)), 10, 64)
+}
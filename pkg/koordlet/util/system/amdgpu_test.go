@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKFDGPUProperties = `cpu_cores_count 0
+simd_count 416
+simd_per_cu 4
+vendor_id 4098
+device_id 29711
+location_id 768
+domain 0
+drm_render_minor 128
+unique_id 12345678901234567890
+`
+
+func TestGetAMDGPUInfos(t *testing.T) {
+	t.Run("kfd not loaded", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		got, err := GetAMDGPUInfos()
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("parse gpu nodes", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		// cpu node
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/0/gpu_id", "0\n")
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/0/properties", "cpu_cores_count 64\n")
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/gpu_id", "53902\n")
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/name", "gfx90a\n")
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/properties", testKFDGPUProperties)
+		// the gpu of the other vendor
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/2/gpu_id", "1234\n")
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/2/properties", "vendor_id 1000\n")
+		got, err := GetAMDGPUInfos()
+		assert.NoError(t, err)
+		assert.Equal(t, []AMDGPUInfo{
+			{
+				GPUID:        53902,
+				BusID:        "0000:03:00.0",
+				UniqueID:     12345678901234567890,
+				ComputeUnits: 104,
+				Name:         "gfx90a",
+			},
+		}, got)
+	})
+	t.Run("invalid gpu id", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		helper.WriteFileContents("class/kfd/kfd/topology/nodes/1/gpu_id", "invalid\n")
+		_, err := GetAMDGPUInfos()
+		assert.Error(t, err)
+	})
+}
+
+func TestGetAMDGPUAttributes(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	busID := "0000:03:00.0"
+	_, err := GetAMDGPUBusyPercent(busID)
+	assert.Error(t, err)
+
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/gpu_busy_percent", "35\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/mem_info_vram_total", "68702699520\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/mem_info_vram_used", "10737418240\n")
+	helper.WriteFileContents("bus/pci/devices/0000:03:00.0/product_name", "AMD Instinct MI210\n")
+	helper.WriteFileContents("module/amdgpu/version", "6.3.6\n")
+
+	busy, err := GetAMDGPUBusyPercent(busID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(35), busy)
+	total, used, err := GetAMDGPUVRAM(busID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(68702699520), total)
+	assert.Equal(t, uint64(10737418240), used)
+	name, err := GetAMDGPUProductName(busID)
+	assert.NoError(t, err)
+	assert.Equal(t, "AMD Instinct MI210", name)
+	version, err := GetAMDGPUDriverVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "6.3.6", version)
+}
+
+func TestGetAMDGPUProcessUsages(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	got, err := GetAMDGPUProcessUsages()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	helper.WriteFileContents("class/kfd/kfd/proc/1001/vram_53902", "1073741824\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/1001/stats_53902/cu_occupancy", "52\n")
+	// cu_occupancy is missing
+	helper.WriteFileContents("class/kfd/kfd/proc/1002/vram_53902", "2048\n")
+	helper.WriteFileContents("class/kfd/kfd/proc/invalid/vram_53902", "2048\n")
+	got, err = GetAMDGPUProcessUsages()
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint32]map[uint64]*AMDGPUProcessUsage{
+		1001: {53902: {VRAMUsed: 1073741824, CUOccupancy: 52}},
+		1002: {53902: {VRAMUsed: 2048}},
+	}, got)
+}
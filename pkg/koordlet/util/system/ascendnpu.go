@@ -0,0 +1,200 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The Huawei Ascend NPUs are discovered from the PCI devices, while the usages are collected by the npu-smi since the
+// Ascend driver exposes no sysfs attribute of the usages.
+const (
+	AscendDriverVersionFilePath = "/usr/local/Ascend/driver/version.info"
+	AscendNPUSMICmd             = "npu-smi"
+
+	pciVendorFile = "vendor"
+	pciDeviceFile = "device"
+	pciClassFile  = "class"
+
+	// AscendNPUVendorID is the PCI vendor id of the Huawei (0x19e5).
+	AscendNPUVendorID = 0x19e5
+	// pciClassProcessingAccelerator is the PCI base class of the processing accelerators.
+	pciClassProcessingAccelerator = 0x12
+)
+
+// ascendNPUModels are the models of the Ascend NPUs keyed by the PCI device id.
+var ascendNPUModels = map[uint64]string{
+	0xd100: "Ascend310",
+	0xd500: "Ascend310P",
+	0xd801: "Ascend910",
+	0xd802: "Ascend910B",
+}
+
+// AscendNPUInfo is the static information of an Ascend NPU chip.
+type AscendNPUInfo struct {
+	// BusID is the PCI address of the NPU chip, e.g. 0000:c1:00.0.
+	BusID string
+	// DeviceID is the PCI device id of the NPU chip.
+	DeviceID uint64
+	// Model is the model of the NPU chip, e.g. Ascend910B. It is empty for the unknown device ids.
+	Model string
+}
+
+// GetAscendNPUInfos returns the Ascend NPU chips ordered by the PCI address, which is the same as the order of the
+// npu ids in npu-smi and the minors of the /dev/davinciN.
+func GetAscendNPUInfos() ([]AscendNPUInfo, error) {
+	deviceDirs, err := os.ReadDir(GetPCIDeviceDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read pci devices, err: %w", err)
+	}
+
+	var npus []AscendNPUInfo
+	for _, deviceDir := range deviceDirs {
+		devicePath := filepath.Join(GetPCIDeviceDir(), deviceDir.Name())
+		vendorID, err := readHexUint64File(filepath.Join(devicePath, pciVendorFile))
+		if err != nil || vendorID != AscendNPUVendorID {
+			continue
+		}
+		class, err := readHexUint64File(filepath.Join(devicePath, pciClassFile))
+		if err != nil || class>>16 != pciClassProcessingAccelerator {
+			continue
+		}
+		deviceID, err := readHexUint64File(filepath.Join(devicePath, pciDeviceFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read device id of pci device %s, err: %w", deviceDir.Name(), err)
+		}
+		npus = append(npus, AscendNPUInfo{
+			BusID:    deviceDir.Name(),
+			DeviceID: deviceID,
+			Model:    ascendNPUModels[deviceID],
+		})
+	}
+	sort.Slice(npus, func(i, j int) bool {
+		return npus[i].BusID < npus[j].BusID
+	})
+	return npus, nil
+}
+
+// GetAscendNPUModel returns the model of the NPU chip, e.g. Ascend910B.
+func GetAscendNPUModel(busID string) (string, error) {
+	deviceID, err := readHexUint64File(filepath.Join(GetPCIDeviceDir(), busID, pciDeviceFile))
+	if err != nil {
+		return "", err
+	}
+	model, ok := ascendNPUModels[deviceID]
+	if !ok {
+		return "", fmt.Errorf("unknown device id 0x%x", deviceID)
+	}
+	return model, nil
+}
+
+// AscendNPUUsage is the usage of an Ascend NPU chip.
+type AscendNPUUsage struct {
+	// AICoreUtil is the utilization percentage of the AI cores.
+	AICoreUtil uint64
+	// MemoryTotal is the total bytes of the device memory (HBM if equipped).
+	MemoryTotal uint64
+	// MemoryUsed is the used bytes of the device memory.
+	MemoryUsed uint64
+}
+
+// GetAscendNPUUsage returns the usage of the NPU chip by `npu-smi info -t usages -i <npuID>`.
+func GetAscendNPUUsage(npuID int) (*AscendNPUUsage, error) {
+	out, _, err := ExecCmdOnHost([]string{AscendNPUSMICmd, "info", "-t", "usages", "-i", strconv.Itoa(npuID)})
+	if err != nil {
+		return nil, err
+	}
+	return parseAscendNPUUsage(out)
+}
+
+// parseAscendNPUUsage parses the output of the npu-smi usages like:
+//
+//	Memory Capacity(MB)            : 15079
+//	Memory Usage Rate(%)           : 6
+//	HBM Capacity(MB)               : 32768
+//	HBM Usage Rate(%)              : 10
+//	Aicore Usage Rate(%)           : 25
+func parseAscendNPUUsage(content []byte) (*AscendNPUUsage, error) {
+	values := map[string]uint64{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), ":", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64)
+		if err != nil {
+			continue
+		}
+		values[strings.TrimSpace(fields[0])] = value
+	}
+	aicoreUtil, ok := values["Aicore Usage Rate(%)"]
+	if !ok {
+		return nil, fmt.Errorf("aicore usage rate not found")
+	}
+	usage := &AscendNPUUsage{AICoreUtil: aicoreUtil}
+	// the HBM is the device memory if equipped, e.g. Ascend910, while the Memory is the DDR of the device control CPU
+	if capacity := values["HBM Capacity(MB)"]; capacity > 0 {
+		usage.MemoryTotal = capacity * 1024 * 1024
+		usage.MemoryUsed = usage.MemoryTotal * values["HBM Usage Rate(%)"] / 100
+	} else {
+		usage.MemoryTotal = values["Memory Capacity(MB)"] * 1024 * 1024
+		usage.MemoryUsed = usage.MemoryTotal * values["Memory Usage Rate(%)"] / 100
+	}
+	return usage, nil
+}
+
+// GetAscendNPUDriverVersion returns the version of the Ascend driver installed on the host.
+func GetAscendNPUDriverVersion() (string, error) {
+	out, _, err := ExecCmdOnHost([]string{"cat", AscendDriverVersionFilePath})
+	if err != nil {
+		return "", err
+	}
+	return parseAscendDriverVersion(out)
+}
+
+// parseAscendDriverVersion parses the version.info of the Ascend driver like:
+//
+//	Version=23.0.rc3
+//	ascendhal_version=7.35.19
+func parseAscendDriverVersion(content []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if found && key == "Version" && value != "" {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("version not found")
+}
+
+func readHexUint64File(path string) (uint64, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimPrefix(string(bytes.TrimSpace(content)), "0x"), 16, 64)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAscendNPUInfos(t *testing.T) {
+	t.Run("no pci device", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		got, err := GetAscendNPUInfos()
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("parse npu chips", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		writePCIDevice := func(busID, vendor, device, class string) {
+			helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/vendor", busID), vendor+"\n")
+			helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/device", busID), device+"\n")
+			helper.WriteFileContents(fmt.Sprintf("bus/pci/devices/%s/class", busID), class+"\n")
+		}
+		writePCIDevice("0000:c1:00.0", "0x19e5", "0xd802", "0x120000")
+		writePCIDevice("0000:01:00.0", "0x19e5", "0xd801", "0x120000")
+		// the network controller of the huawei
+		writePCIDevice("0000:02:00.0", "0x19e5", "0x0200", "0x020000")
+		// the accelerator of the other vendor
+		writePCIDevice("0000:03:00.0", "0x10de", "0x20b0", "0x120000")
+		got, err := GetAscendNPUInfos()
+		assert.NoError(t, err)
+		assert.Equal(t, []AscendNPUInfo{
+			{
+				BusID:    "0000:01:00.0",
+				DeviceID: 0xd801,
+				Model:    "Ascend910",
+			},
+			{
+				BusID:    "0000:c1:00.0",
+				DeviceID: 0xd802,
+				Model:    "Ascend910B",
+			},
+		}, got)
+	})
+}
+
+func Test_parseAscendNPUUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *AscendNPUUsage
+		wantErr bool
+	}{
+		{
+			name: "npu with hbm",
+			content: `        Memory Capacity(MB)            : 15079
+        Memory Usage Rate(%)           : 6
+        HBM Capacity(MB)               : 32768
+        HBM Usage Rate(%)              : 10
+        Aicore Usage Rate(%)           : 25
+        Aicpu Usage Rate(%)            : 0
+        Ctrlcpu Usage Rate(%)          : 3
+`,
+			want: &AscendNPUUsage{
+				AICoreUtil:  25,
+				MemoryTotal: 32768 * 1024 * 1024,
+				MemoryUsed:  32768 * 1024 * 1024 / 10,
+			},
+		},
+		{
+			name: "npu without hbm",
+			content: `        Memory Capacity(MB)            : 21527
+        Memory Usage Rate(%)           : 50
+        Aicore Usage Rate(%)           : 3
+`,
+			want: &AscendNPUUsage{
+				AICoreUtil:  3,
+				MemoryTotal: 21527 * 1024 * 1024,
+				MemoryUsed:  21527 * 1024 * 1024 / 2,
+			},
+		},
+		{
+			name:    "invalid output",
+			content: "Error: the npu id is invalid\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAscendNPUUsage([]byte(tt.content))
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_parseAscendDriverVersion(t *testing.T) {
+	got, err := parseAscendDriverVersion([]byte("Version=23.0.rc3\nascendhal_version=7.35.19\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "23.0.rc3", got)
+
+	_, err = parseAscendDriverVersion([]byte("ascendhal_version=7.35.19\n"))
+	assert.Error(t, err)
+}
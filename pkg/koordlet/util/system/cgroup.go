@@ -60,6 +60,17 @@ type MemoryStatRaw struct {
 	// add more fields
 }
 
+// BlkIOStatRaw is the accumulated IO counters of a cgroup on one block device.
+type BlkIOStatRaw struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadIOs    uint64
+	WriteIOs   uint64
+}
+
+// IOStatRaw is the IO counters of a cgroup, keyed by the block device number `major:minor`.
+type IOStatRaw map[string]*BlkIOStatRaw
+
 type NumaMemoryPages struct {
 	NumaId   int
 	PagesNum uint64
@@ -216,6 +227,59 @@ func ParseCgroupProcs(content string) ([]uint32, error) {
 	return pids, nil
 }
 
+// ParseBlkioThrottleStat parses the contents in blkio.throttle.io_serviced and blkio.throttle.io_service_bytes.
+// pattern: `8:0 Read 1000\n8:0 Write 2000\n8:0 Sync 3000\n8:0 Async 0\n8:0 Discard 0\n8:0 Total 3000\nTotal 3000`
+func ParseBlkioThrottleStat(servicedContent, serviceBytesContent string) (IOStatRaw, error) {
+	stat := IOStatRaw{}
+	for _, t := range []struct {
+		content string
+		read    func(s *BlkIOStatRaw) *uint64
+		write   func(s *BlkIOStatRaw) *uint64
+	}{
+		{
+			content: servicedContent,
+			read:    func(s *BlkIOStatRaw) *uint64 { return &s.ReadIOs },
+			write:   func(s *BlkIOStatRaw) *uint64 { return &s.WriteIOs },
+		},
+		{
+			content: serviceBytesContent,
+			read:    func(s *BlkIOStatRaw) *uint64 { return &s.ReadBytes },
+			write:   func(s *BlkIOStatRaw) *uint64 { return &s.WriteBytes },
+		},
+	} {
+		for _, line := range strings.Split(t.content, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 3 { // skip the summary line like `Total 3000`
+				continue
+			}
+			var value *uint64
+			switch fields[1] {
+			case "Read":
+				value = t.read(getOrCreateBlkIOStat(stat, fields[0]))
+			case "Write":
+				value = t.write(getOrCreateBlkIOStat(stat, fields[0]))
+			default:
+				continue
+			}
+			v, err := strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse row %s of blkio stat, err: %w", line, err)
+			}
+			*value = v
+		}
+	}
+	return stat, nil
+}
+
+func getOrCreateBlkIOStat(stat IOStatRaw, device string) *BlkIOStatRaw {
+	s, ok := stat[device]
+	if !ok {
+		s = &BlkIOStatRaw{}
+		stat[device] = s
+	}
+	return s
+}
+
 func CalcCPUThrottledRatio(curPoint, prePoint *CPUStatRaw) float64 {
 	deltaPeriod := curPoint.NrPeriods - prePoint.NrPeriods
 	deltaThrottled := curPoint.NrThrottled - prePoint.NrThrottled
@@ -33,7 +33,21 @@ type CPUStatV2Raw struct {
 	ThrottledUSec int64
 }
 
+// cgroupsV2Override is the cgroup version specified instead of detected, which is nil by default.
+var cgroupsV2Override *bool
+
+// OverrideCgroupsVersion specifies the cgroup version rather than detecting it with the filesystem type of the cgroup
+// root, e.g. when the cgroup root is an emulated tree on a regular filesystem. A nil value resets the override.
+func OverrideCgroupsVersion(useCgroupsV2 *bool) {
+	cgroupsV2Override = useCgroupsV2
+	initCgroupsVersion()
+}
+
 func initCgroupsVersion() {
+	if cgroupsV2Override != nil {
+		UseCgroupsV2.Store(*cgroupsV2Override)
+		return
+	}
 	UseCgroupsV2.Store(IsUsingCgroupsV2())
 }
 
@@ -280,6 +294,45 @@ func ParseMemoryNumaStatV2(content string) ([]NumaMemoryPages, error) {
 	return stat, nil
 }
 
+// ParseIOStatV2 parses the content in io.stat.
+// pattern: `8:0 rbytes=1000 wbytes=2000 rios=10 wios=20 dbytes=0 dios=0\n253:0 rbytes=...`
+func ParseIOStatV2(content string) (IOStatRaw, error) {
+	stat := IOStatRaw{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		s := &BlkIOStatRaw{}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("failed to parse row %s of io.stat, err: invalid field %s", line, field)
+			}
+			var value *uint64
+			switch kv[0] {
+			case "rbytes":
+				value = &s.ReadBytes
+			case "wbytes":
+				value = &s.WriteBytes
+			case "rios":
+				value = &s.ReadIOs
+			case "wios":
+				value = &s.WriteIOs
+			default: // ignore other fields like dbytes, dios and the iocost stats
+				continue
+			}
+			v, err := strconv.ParseUint(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse row %s of io.stat, err: %w", line, err)
+			}
+			*value = v
+		}
+		stat[fields[0]] = s
+	}
+	return stat, nil
+}
+
 // ConvertCPUWeightToShares converts the value of `cpu.weight` (cgroups-v2) into the value of `cpu.shares` (cgroups-v1)
 func ConvertCPUWeightToShares(v int64) (int64, error) {
 	isValid, msg := CPUWeightValidator.Validate(strconv.FormatInt(v, 10))
@@ -18,6 +18,8 @@ package system
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseCPUCFSQuotaV2(t *testing.T) {
@@ -445,3 +447,45 @@ func TestConvertCPUSharesToWeight(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIOStatV2(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    IOStatRaw
+		wantErr bool
+	}{
+		{
+			name:    "parse empty",
+			content: "",
+			want:    IOStatRaw{},
+		},
+		{
+			name:    "parse correctly",
+			content: "8:0 rbytes=409600 wbytes=819200 rios=100 wios=200 dbytes=0 dios=0\n253:0 rbytes=40960 wbytes=0 rios=10 wios=0 dbytes=0 dios=0 cost.vrate=100.00\n",
+			want: IOStatRaw{
+				"8:0":   {ReadBytes: 409600, WriteBytes: 819200, ReadIOs: 100, WriteIOs: 200},
+				"253:0": {ReadBytes: 40960, ReadIOs: 10},
+			},
+		},
+		{
+			name:    "parse invalid field",
+			content: "8:0 rbytes\n",
+			wantErr: true,
+		},
+		{
+			name:    "parse invalid value",
+			content: "8:0 rbytes=abc wbytes=0 rios=0 wios=0\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := ParseIOStatV2(tt.content)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
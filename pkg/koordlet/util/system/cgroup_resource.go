@@ -112,6 +112,8 @@ const ( // subsystems
 	CgroupMemDir     string = "memory/"
 	CgroupBlkioDir   string = "blkio/"
 	CgroupNetClsDir  string = "net_cls/"
+	CgroupRDMADir    string = "rdma/"
+	CgroupHugetlbDir string = "hugetlb/"
 
 	CgroupV2Dir = ""
 )
@@ -137,9 +139,12 @@ const (
 	CPUMaxBurstName  = "cpu.max.burst"
 	CPUWeightName    = "cpu.weight"
 	CPUIdleName      = "cpu.idle"
+	CPUUClampMinName = "cpu.uclamp.min" // cgroups-v2
+	CPUUClampMaxName = "cpu.uclamp.max" // cgroups-v2
 
 	CPUSetCPUSName          = "cpuset.cpus"
 	CPUSetCPUSEffectiveName = "cpuset.cpus.effective"
+	CPUSetMemsName          = "cpuset.mems"
 
 	CPUAcctStatName           = "cpuacct.stat"
 	CPUAcctUsageName          = "cpuacct.usage"
@@ -162,6 +167,9 @@ const (
 	MemoryPriorityName         = "memory.priority"
 	MemoryUsePriorityOomName   = "memory.use_priority_oom"
 	MemoryOomGroupName         = "memory.oom.group"
+	MemoryNumaBalancingName    = "memory.numa_balancing"
+	MemoryZswapMaxName         = "memory.zswap.max"
+	MemoryZswapWritebackName   = "memory.zswap.writeback"
 	MemoryIdlePageStatsName    = "memory.idle_page_stats"
 
 	BlkioTRIopsName   = "blkio.throttle.read_iops_device"
@@ -172,7 +180,21 @@ const (
 	BlkioIOQoSName    = "blkio.cost.qos"
 	BlkioIOModelName  = "blkio.cost.model"
 
+	BlkioIOServicedName     = "blkio.throttle.io_serviced"
+	BlkioIOServiceBytesName = "blkio.throttle.io_service_bytes"
+	IOStatName              = "io.stat"
+	IOWeightName            = "io.weight"     // cgroups-v2
+	IOCostQoSName           = "io.cost.qos"   // cgroups-v2, root cgroup only
+	IOCostModelName         = "io.cost.model" // cgroups-v2, root cgroup only
+
 	NetClsClassIdName = "net_cls.classid"
+
+	RDMACurrentName = "rdma.current"
+
+	HugetlbLimit2MName = "hugetlb.2MB.limit_in_bytes"
+	HugetlbLimit1GName = "hugetlb.1GB.limit_in_bytes"
+	HugetlbMax2MName   = "hugetlb.2MB.max" // cgroups-v2
+	HugetlbMax1GName   = "hugetlb.1GB.max" // cgroups-v2
 )
 
 var (
@@ -188,6 +210,8 @@ var (
 	MemoryPriorityValidator                 = &RangeValidator{min: 0, max: 12}
 	MemoryOomGroupValidator                 = &RangeValidator{min: 0, max: 1}
 	MemoryUsePriorityOomValidator           = &RangeValidator{min: 0, max: 1}
+	MemoryNumaBalancingValidator            = &RangeValidator{min: 0, max: 1}
+	MemoryZswapWritebackValidator           = &RangeValidator{min: 0, max: 1}
 	MemoryWmarkMinAdjValidator              = &RangeValidator{min: -25, max: 50}
 	MemoryWmarkScaleFactorFileNameValidator = &RangeValidator{min: 1, max: 1000}
 	BlkioTRIopsValidator                    = &BlkIORangeValidator{min: 0, max: math.MaxInt64, resource: BlkioTRIopsName}
@@ -197,6 +221,9 @@ var (
 	BlkioIOWeightValidator                  = &BlkIORangeValidator{min: 1, max: 100, resource: BlkioIOWeightName}
 	BlkioIOQoSValidator                     = &BlkIORangeValidator{min: 0, max: math.MaxInt64, resource: BlkioIOQoSName}
 	BlkioIOModelValidator                   = &BlkIORangeValidator{min: 1, max: math.MaxInt64, resource: BlkioIOModelName}
+	IOWeightValidator                       = &BlkIORangeValidator{min: 1, max: 10000, resource: BlkioIOWeightName}
+
+	CPUUClampValidator = &CPUUClampRangeValidator{}
 
 	NetClsClassIdValidator = &NetClsRangeValidator{resource: NetClsClassIdName}
 
@@ -217,7 +244,8 @@ var (
 	CPUTasks     = DefaultFactory.New(CPUTasksName, CgroupCPUDir)
 	CPUProcs     = DefaultFactory.New(CPUProcsName, CgroupCPUDir)
 
-	CPUSet = DefaultFactory.New(CPUSetCPUSName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
+	CPUSet     = DefaultFactory.New(CPUSetCPUSName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
+	CPUSetMems = DefaultFactory.New(CPUSetMemsName, CgroupCPUSetDir).WithValidator(CPUSetCPUSValidator)
 
 	CPUAcctStat           = DefaultFactory.New(CPUAcctStatName, CgroupCPUAcctDir)
 	CPUAcctUsage          = DefaultFactory.New(CPUAcctUsageName, CgroupCPUAcctDir)
@@ -238,6 +266,7 @@ var (
 	MemoryPriority         = DefaultFactory.New(MemoryPriorityName, CgroupMemDir).WithValidator(MemoryPriorityValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	MemoryUsePriorityOom   = DefaultFactory.New(MemoryUsePriorityOomName, CgroupMemDir).WithValidator(MemoryUsePriorityOomValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	MemoryOomGroup         = DefaultFactory.New(MemoryOomGroupName, CgroupMemDir).WithValidator(MemoryOomGroupValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	MemoryNumaBalancing    = DefaultFactory.New(MemoryNumaBalancingName, CgroupMemDir).WithValidator(MemoryNumaBalancingValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	MemoryIdlePageStats    = DefaultFactory.New(MemoryIdlePageStatsName, CgroupMemDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
 	BlkioReadIops  = DefaultFactory.New(BlkioTRIopsName, CgroupBlkioDir).WithValidator(BlkioTRIopsValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
@@ -248,8 +277,16 @@ var (
 	BlkioIOQoS     = DefaultFactory.New(BlkioIOQoSName, CgroupBlkioDir).WithValidator(BlkioIOQoSValidator).WithSupported(SupportedIfFileExistsInRootCgroup(BlkioIOQoSName, CgroupBlkioDir))
 	BlkioIOModel   = DefaultFactory.New(BlkioIOModelName, CgroupBlkioDir).WithValidator(BlkioIOModelValidator).WithSupported(SupportedIfFileExistsInRootCgroup(BlkioIOModelName, CgroupBlkioDir))
 
+	BlkioIOServiced     = DefaultFactory.New(BlkioIOServicedName, CgroupBlkioDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	BlkioIOServiceBytes = DefaultFactory.New(BlkioIOServiceBytesName, CgroupBlkioDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
 	NetClsClassId = DefaultFactory.New(NetClsClassIdName, CgroupNetClsDir).WithValidator(NetClsClassIdValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
+	RDMACurrent = DefaultFactory.New(RDMACurrentName, CgroupRDMADir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
+	HugetlbLimit2M = DefaultFactory.New(HugetlbLimit2MName, CgroupHugetlbDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	HugetlbLimit1G = DefaultFactory.New(HugetlbLimit1GName, CgroupHugetlbDir).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
 	knownCgroupResources = []Resource{
 		CPUStat,
 		CPUShares,
@@ -260,6 +297,7 @@ var (
 		CPUBVTWarpNs,
 		CPUIdle,
 		CPUSet,
+		CPUSetMems,
 		CPUAcctStat,
 		CPUAcctUsage,
 		CPUAcctCPUPressure,
@@ -279,6 +317,7 @@ var (
 		MemoryPriority,
 		MemoryUsePriorityOom,
 		MemoryOomGroup,
+		MemoryNumaBalancing,
 		MemoryIdlePageStats,
 		BlkioReadIops,
 		BlkioReadBps,
@@ -287,7 +326,12 @@ var (
 		BlkioIOWeight,
 		BlkioIOQoS,
 		BlkioIOModel,
+		BlkioIOServiced,
+		BlkioIOServiceBytes,
 		NetClsClassId,
+		RDMACurrent,
+		HugetlbLimit2M,
+		HugetlbLimit1G,
 	}
 
 	CPUCFSQuotaV2  = DefaultFactory.NewV2(CPUCFSQuotaName, CPUMaxName)
@@ -299,6 +343,8 @@ var (
 	CPUBurstV2     = DefaultFactory.NewV2(CPUBurstName, CPUMaxBurstName).WithValidator(CPUMaxBurstValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	CPUBVTWarpNsV2 = DefaultFactory.NewV2(CPUBVTWarpNsName, CPUBVTWarpNsName).WithValidator(CPUBvtWarpNsValidator).WithCheckSupported(SupportedIfFileExists)
 	CPUIdleV2      = DefaultFactory.NewV2(CPUIdleName, CPUIdleName).WithValidator(CPUIdleValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	CPUUClampMinV2 = DefaultFactory.NewV2(CPUUClampMinName, CPUUClampMinName).WithValidator(CPUUClampValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	CPUUClampMaxV2 = DefaultFactory.NewV2(CPUUClampMaxName, CPUUClampMaxName).WithValidator(CPUUClampValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
 	CPUAcctCPUPressureV2    = DefaultFactory.NewV2(CPUAcctCPUPressureName, CPUAcctCPUPressureName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 	CPUAcctMemoryPressureV2 = DefaultFactory.NewV2(CPUAcctMemoryPressureName, CPUAcctMemoryPressureName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
@@ -306,6 +352,7 @@ var (
 
 	CPUSetV2                 = DefaultFactory.NewV2(CPUSetCPUSName, CPUSetCPUSName).WithValidator(CPUSetCPUSValidator)
 	CPUSetEffectiveV2        = DefaultFactory.NewV2(CPUSetCPUSEffectiveName, CPUSetCPUSEffectiveName) // TODO: unify the R/W
+	CPUSetMemsV2             = DefaultFactory.NewV2(CPUSetMemsName, CPUSetMemsName).WithValidator(CPUSetCPUSValidator)
 	CPUTasksV2               = DefaultFactory.NewV2(CPUTasksName, CPUThreadsName)
 	CPUProcsV2               = DefaultFactory.NewV2(CPUProcsName, CPUProcsName)
 	MemoryLimitV2            = DefaultFactory.NewV2(MemoryLimitName, MemoryMaxName)
@@ -321,6 +368,20 @@ var (
 	MemoryPriorityV2         = DefaultFactory.NewV2(MemoryPriorityName, MemoryPriorityName).WithValidator(MemoryPriorityValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryUsePriorityOomV2   = DefaultFactory.NewV2(MemoryUsePriorityOomName, MemoryUsePriorityOomName).WithValidator(MemoryUsePriorityOomValidator).WithCheckSupported(SupportedIfFileExists)
 	MemoryOomGroupV2         = DefaultFactory.NewV2(MemoryOomGroupName, MemoryOomGroupName).WithValidator(MemoryOomGroupValidator).WithCheckSupported(SupportedIfFileExists)
+	MemoryNumaBalancingV2    = DefaultFactory.NewV2(MemoryNumaBalancingName, MemoryNumaBalancingName).WithValidator(MemoryNumaBalancingValidator).WithCheckSupported(SupportedIfFileExists)
+	MemoryZswapMaxV2         = DefaultFactory.NewV2(MemoryZswapMaxName, MemoryZswapMaxName).WithCheckSupported(SupportedIfFileExists)
+	MemoryZswapWritebackV2   = DefaultFactory.NewV2(MemoryZswapWritebackName, MemoryZswapWritebackName).WithValidator(MemoryZswapWritebackValidator).WithCheckSupported(SupportedIfFileExists)
+
+	IOStatV2 = DefaultFactory.NewV2(IOStatName, IOStatName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	// io.cost.qos and io.cost.model only exist in the root cgroup, so the support is checked on the given path.
+	BlkioIOWeightV2 = DefaultFactory.NewV2(BlkioIOWeightName, IOWeightName).WithValidator(IOWeightValidator).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	BlkioIOQoSV2    = DefaultFactory.NewV2(BlkioIOQoSName, IOCostQoSName).WithValidator(BlkioIOQoSValidator).WithCheckSupported(SupportedIfFileExists)
+	BlkioIOModelV2  = DefaultFactory.NewV2(BlkioIOModelName, IOCostModelName).WithValidator(BlkioIOModelValidator).WithCheckSupported(SupportedIfFileExists)
+
+	RDMACurrentV2 = DefaultFactory.NewV2(RDMACurrentName, RDMACurrentName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+
+	HugetlbLimit2MV2 = DefaultFactory.NewV2(HugetlbLimit2MName, HugetlbMax2MName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
+	HugetlbLimit1GV2 = DefaultFactory.NewV2(HugetlbLimit1GName, HugetlbMax1GName).WithCheckSupported(SupportedIfFileExistsInKubepods).WithCheckOnce(true)
 
 	knownCgroupV2Resources = []Resource{
 		CPUCFSQuotaV2,
@@ -332,11 +393,14 @@ var (
 		CPUBurstV2,
 		CPUBVTWarpNsV2,
 		CPUIdleV2,
+		CPUUClampMinV2,
+		CPUUClampMaxV2,
 		CPUAcctCPUPressureV2,
 		CPUAcctMemoryPressureV2,
 		CPUAcctIOPressureV2,
 		CPUSetV2,
 		CPUSetEffectiveV2,
+		CPUSetMemsV2,
 		CPUTasksV2,
 		CPUProcsV2,
 		MemoryLimitV2,
@@ -352,7 +416,16 @@ var (
 		MemoryPriorityV2,
 		MemoryUsePriorityOomV2,
 		MemoryOomGroupV2,
-		// TODO: register BlkioIOWeight, BlkioIOQoS and BlkioIOModel
+		MemoryNumaBalancingV2,
+		MemoryZswapMaxV2,
+		MemoryZswapWritebackV2,
+		IOStatV2,
+		BlkioIOWeightV2,
+		BlkioIOQoSV2,
+		BlkioIOModelV2,
+		RDMACurrentV2,
+		HugetlbLimit2MV2,
+		HugetlbLimit1GV2,
 
 		NetClsClassId,
 	}
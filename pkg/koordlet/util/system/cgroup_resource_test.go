@@ -47,6 +47,20 @@ func TestCgroupResourceRegistry(t *testing.T) {
 		cgroup, ok = r.(*CgroupResource)
 		assert.True(t, ok)
 		assert.Equal(t, cgroup.FileName, CPUMaxBurstName)
+
+		for resourceType, fileName := range map[ResourceType]string{
+			BlkioIOWeightName: IOWeightName,
+			BlkioIOQoSName:    IOCostQoSName,
+			BlkioIOModelName:  IOCostModelName,
+			CPUUClampMinName:  CPUUClampMinName,
+			CPUUClampMaxName:  CPUUClampMaxName,
+		} {
+			r, ok = DefaultRegistry.Get(CgroupVersionV2, resourceType)
+			assert.True(t, ok, resourceType)
+			cgroup, ok = r.(*CgroupResource)
+			assert.True(t, ok)
+			assert.Equal(t, fileName, cgroup.FileName)
+		}
 	})
 }
 
@@ -18,6 +18,8 @@ package system
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestParseCPUStatRaw(t *testing.T) {
@@ -264,3 +266,44 @@ func TestCalcCPUThrottledRatio(t *testing.T) {
 		})
 	}
 }
+
+func TestParseBlkioThrottleStat(t *testing.T) {
+	tests := []struct {
+		name                string
+		servicedContent     string
+		serviceBytesContent string
+		want                IOStatRaw
+		wantErr             bool
+	}{
+		{
+			name:                "parse empty",
+			servicedContent:     "Total 0\n",
+			serviceBytesContent: "Total 0\n",
+			want:                IOStatRaw{},
+		},
+		{
+			name:                "parse correctly",
+			servicedContent:     "8:0 Read 100\n8:0 Write 200\n8:0 Sync 300\n8:0 Async 0\n8:0 Discard 0\n8:0 Total 300\n253:0 Read 10\n253:0 Write 0\nTotal 310\n",
+			serviceBytesContent: "8:0 Read 409600\n8:0 Write 819200\n8:0 Sync 1228800\n8:0 Async 0\n8:0 Discard 0\n8:0 Total 1228800\nTotal 1228800\n",
+			want: IOStatRaw{
+				"8:0":   {ReadBytes: 409600, WriteBytes: 819200, ReadIOs: 100, WriteIOs: 200},
+				"253:0": {ReadIOs: 10},
+			},
+		},
+		{
+			name:                "parse invalid value",
+			servicedContent:     "8:0 Read abc\n",
+			serviceBytesContent: "Total 0\n",
+			wantErr:             true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotErr := ParseBlkioThrottleStat(tt.servicedContent, tt.serviceBytesContent)
+			assert.Equal(t, tt.wantErr, gotErr != nil, gotErr)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -29,6 +29,9 @@ const (
 	HOST_MODE = "hostMode"
 )
 
+// DefaultSandboxedRuntimeHandlers are the runtime handlers of the Kata containers by default.
+const DefaultSandboxedRuntimeHandlers = "kata,kata-qemu,kata-clh,kata-fc,kata-dragonball"
+
 var Conf = NewDsModeConfig()
 var AgentMode = DS_MODE
 
@@ -51,6 +54,13 @@ type Config struct {
 	DefaultRuntimeType           string
 	HAMICoreLibraryDirectoryPath string
 	PodResourcesProxyPath        string
+	MPSRootDir                   string
+	MPSControlBinaryPath         string
+	XFSQuotaBinaryPath           string
+	ShmSizePerGPU                string
+	// SandboxedRuntimeHandlers are the comma-separated runtime handlers of the sandboxed runtimes, e.g. kata, whose
+	// pods run in the guests.
+	SandboxedRuntimeHandlers string
 }
 
 func init() {
@@ -95,6 +105,10 @@ func NewHostModeConfig() *Config {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		MPSRootDir:                   "/var/run/koordlet/nvidia-mps",
+		MPSControlBinaryPath:         "nvidia-cuda-mps-control",
+		XFSQuotaBinaryPath:           "xfs_quota",
+		SandboxedRuntimeHandlers:     DefaultSandboxedRuntimeHandlers,
 	}
 }
 
@@ -112,6 +126,10 @@ func NewDsModeConfig() *Config {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		MPSRootDir:                   "/var/run/koordlet/nvidia-mps",
+		MPSControlBinaryPath:         "nvidia-cuda-mps-control",
+		XFSQuotaBinaryPath:           "xfs_quota",
+		SandboxedRuntimeHandlers:     DefaultSandboxedRuntimeHandlers,
 	}
 }
 
@@ -136,4 +154,9 @@ func (c *Config) InitFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.HAMICoreLibraryDirectoryPath, "hami-core-library-directory-path", c.HAMICoreLibraryDirectoryPath, "path of hami core library")
 
 	fs.StringVar(&c.PodResourcesProxyPath, "pod-resources-proxy-path", c.PodResourcesProxyPath, "The path of the socket file for the pod resource proxy")
+	fs.StringVar(&c.MPSRootDir, "mps-root-dir", c.MPSRootDir, "The host dir of the pipe and log dirs of the NVIDIA MPS control daemons")
+	fs.StringVar(&c.MPSControlBinaryPath, "mps-control-binary-path", c.MPSControlBinaryPath, "The path of the NVIDIA MPS control binary")
+	fs.StringVar(&c.XFSQuotaBinaryPath, "xfs-quota-binary-path", c.XFSQuotaBinaryPath, "The path of the xfs_quota binary, used to manage the project quotas of the pod dirs")
+	fs.StringVar(&c.ShmSizePerGPU, "shm-size-per-gpu", c.ShmSizePerGPU, "The /dev/shm size per allocated GPU of the containers which do not specify the shm size, e.g. 8Gi. Empty means disabled")
+	fs.StringVar(&c.SandboxedRuntimeHandlers, "sandboxed-runtime-handlers", c.SandboxedRuntimeHandlers, "The comma-separated runtime handlers of the sandboxed runtimes, e.g. kata, whose containers are passed the QoS hints into the guests")
 }
@@ -35,6 +35,10 @@ func Test_NewDsModeConfig(t *testing.T) {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		MPSRootDir:                   "/var/run/koordlet/nvidia-mps",
+		MPSControlBinaryPath:         "nvidia-cuda-mps-control",
+		XFSQuotaBinaryPath:           "xfs_quota",
+		SandboxedRuntimeHandlers:     DefaultSandboxedRuntimeHandlers,
 	}
 	defaultConfig := NewDsModeConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -53,6 +57,10 @@ func Test_NewHostModeConfig(t *testing.T) {
 		DefaultRuntimeType:           "containerd",
 		HAMICoreLibraryDirectoryPath: "/usr/local/vgpu/libvgpu.so",
 		PodResourcesProxyPath:        "/var/run/koordlet/pod-resources",
+		MPSRootDir:                   "/var/run/koordlet/nvidia-mps",
+		MPSControlBinaryPath:         "nvidia-cuda-mps-control",
+		XFSQuotaBinaryPath:           "xfs_quota",
+		SandboxedRuntimeHandlers:     DefaultSandboxedRuntimeHandlers,
 	}
 	defaultConfig := NewHostModeConfig()
 	assert.Equal(t, expectConfig, defaultConfig)
@@ -0,0 +1,129 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	cpufreqCurFreqFile = "cpufreq/scaling_cur_freq"
+	cpufreqMaxFreqFile = "cpufreq/cpuinfo_max_freq"
+
+	thermalThrottleCoreCountFile    = "thermal_throttle/core_throttle_count"
+	thermalThrottlePackageCountFile = "thermal_throttle/package_throttle_count"
+
+	// MSRMPERF is the MSR of the maximum performance frequency clock count, which increments at the base frequency.
+	MSRMPERF int64 = 0xe7
+	// MSRAPERF is the MSR of the actual performance frequency clock count, which increments at the actual frequency.
+	MSRAPERF int64 = 0xe8
+)
+
+var (
+	// MSRDevDir is the dir of the msr devices, e.g. /dev/cpu/0/msr, which requires the msr kernel module.
+	MSRDevDir = "/dev/cpu"
+)
+
+// CPUFrequencyStat is the frequency and thermal throttling stat of a logical cpu.
+type CPUFrequencyStat struct {
+	CPU int32
+	// CurFreqKHz is the current frequency in kHz.
+	CurFreqKHz uint64
+	// MaxFreqKHz is the maximum frequency in kHz.
+	MaxFreqKHz uint64
+	// CoreThrottleCount is the cumulative count of the core thermal throttling events, which is also counted by the
+	// other cpus of the core.
+	CoreThrottleCount uint64
+	// PackageThrottleCount is the cumulative count of the package thermal throttling events, which is also counted
+	// by the other cpus of the package.
+	PackageThrottleCount uint64
+	// HasThrottleCount indicates whether the thermal throttling counts are supported.
+	HasThrottleCount bool
+	// APERF and MPERF are the cumulative clock counts read from the MSRs, whose delta ratio indicates the average
+	// frequency relative to the base frequency, e.g. a ratio larger than 1 means the cpu runs in the turbo range.
+	APERF uint64
+	MPERF uint64
+	// HasPerfCounters indicates whether the APERF and MPERF are readable.
+	HasPerfCounters bool
+}
+
+// GetCPUFrequencyStats returns the frequency stats of the online cpus which support the cpufreq, ordered by the cpu.
+// The thermal throttling counts and the perf counters are optional since they are not supported on all platforms.
+func GetCPUFrequencyStats() ([]CPUFrequencyStat, error) {
+	cpuDirs, err := filepath.Glob(filepath.Join(Conf.SysRootDir, SysCPUSubDir, "cpu[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	var stats []CPUFrequencyStat
+	for _, cpuDir := range cpuDirs {
+		cpu, err := strconv.ParseInt(strings.TrimPrefix(filepath.Base(cpuDir), "cpu"), 10, 32)
+		if err != nil {
+			continue
+		}
+		curFreq, err := readUint64File(filepath.Join(cpuDir, cpufreqCurFreqFile))
+		if os.IsNotExist(err) {
+			// the cpu is offline or the cpufreq is not supported
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read current frequency of cpu %d, err: %w", cpu, err)
+		}
+		maxFreq, err := readUint64File(filepath.Join(cpuDir, cpufreqMaxFreqFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read max frequency of cpu %d, err: %w", cpu, err)
+		}
+		stat := CPUFrequencyStat{
+			CPU:        int32(cpu),
+			CurFreqKHz: curFreq,
+			MaxFreqKHz: maxFreq,
+		}
+		coreCount, coreErr := readUint64File(filepath.Join(cpuDir, thermalThrottleCoreCountFile))
+		packageCount, packageErr := readUint64File(filepath.Join(cpuDir, thermalThrottlePackageCountFile))
+		if coreErr == nil && packageErr == nil {
+			stat.CoreThrottleCount, stat.PackageThrottleCount, stat.HasThrottleCount = coreCount, packageCount, true
+		}
+		aperf, aperfErr := ReadMSR(stat.CPU, MSRAPERF)
+		mperf, mperfErr := ReadMSR(stat.CPU, MSRMPERF)
+		if aperfErr == nil && mperfErr == nil {
+			stat.APERF, stat.MPERF, stat.HasPerfCounters = aperf, mperf, true
+		}
+		stats = append(stats, stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].CPU < stats[j].CPU
+	})
+	return stats, nil
+}
+
+// ReadMSR reads the 64-bit model specific register of the cpu from the msr device.
+func ReadMSR(cpu int32, msr int64) (uint64, error) {
+	f, err := os.Open(filepath.Join(MSRDevDir, strconv.Itoa(int(cpu)), "msr"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	buf := make([]byte, 8)
+	if _, err = f.ReadAt(buf, msr); err != nil {
+		return 0, fmt.Errorf("failed to read msr %#x of cpu %d, err: %w", msr, cpu, err)
+	}
+	return binary.LittleEndian.Uint64(buf), nil
+}
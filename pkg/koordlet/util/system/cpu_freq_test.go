@@ -0,0 +1,87 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestMSRs writes a fake msr device and returns the values to read. Since the msr device is addressed by the
+// register while a regular file is addressed by the byte, the adjacent registers overlap in the fake device.
+func writeTestMSRs(t *testing.T, dir string, cpu string, aperf, mperf uint64) (uint64, uint64) {
+	buf := make([]byte, MSRAPERF+8)
+	binary.LittleEndian.PutUint64(buf[MSRMPERF:], mperf)
+	binary.LittleEndian.PutUint64(buf[MSRAPERF:], aperf)
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, cpu), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, cpu, "msr"), buf, 0644))
+	return binary.LittleEndian.Uint64(buf[MSRAPERF:]), binary.LittleEndian.Uint64(buf[MSRMPERF:])
+}
+
+func TestGetCPUFrequencyStats(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	oldMSRDevDir := MSRDevDir
+	MSRDevDir = t.TempDir()
+	defer func() {
+		MSRDevDir = oldMSRDevDir
+	}()
+
+	got, err := GetCPUFrequencyStats()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	helper.WriteFileContents("devices/system/cpu/cpu0/cpufreq/scaling_cur_freq", "2400000\n")
+	helper.WriteFileContents("devices/system/cpu/cpu0/cpufreq/cpuinfo_max_freq", "3500000\n")
+	helper.WriteFileContents("devices/system/cpu/cpu0/thermal_throttle/core_throttle_count", "3\n")
+	helper.WriteFileContents("devices/system/cpu/cpu0/thermal_throttle/package_throttle_count", "5\n")
+	aperf, mperf := writeTestMSRs(t, MSRDevDir, "0", 3000, 2000)
+	helper.WriteFileContents("devices/system/cpu/cpu10/cpufreq/scaling_cur_freq", "1200000\n")
+	helper.WriteFileContents("devices/system/cpu/cpu10/cpufreq/cpuinfo_max_freq", "3500000\n")
+	// offline cpu
+	helper.WriteFileContents("devices/system/cpu/cpu11/online", "0\n")
+	helper.WriteFileContents("devices/system/cpu/cpufreq/boost", "1\n")
+	got, err = GetCPUFrequencyStats()
+	assert.NoError(t, err)
+	assert.Equal(t, []CPUFrequencyStat{
+		{
+			CPU:                  0,
+			CurFreqKHz:           2400000,
+			MaxFreqKHz:           3500000,
+			CoreThrottleCount:    3,
+			PackageThrottleCount: 5,
+			HasThrottleCount:     true,
+			APERF:                aperf,
+			MPERF:                mperf,
+			HasPerfCounters:      true,
+		},
+		{
+			CPU:        10,
+			CurFreqKHz: 1200000,
+			MaxFreqKHz: 3500000,
+		},
+	}, got)
+
+	helper.WriteFileContents("devices/system/cpu/cpu10/cpufreq/cpuinfo_max_freq", "invalid\n")
+	got, err = GetCPUFrequencyStats()
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
@@ -0,0 +1,476 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+const (
+	CgroupVersionV1 = "v1"
+	CgroupVersionV2 = "v2"
+
+	defaultCPUs          = 8
+	defaultMemoryBytes   = 16 << 30
+	defaultStepInterval  = time.Second
+	defaultCBMMask       = "fff"
+	defaultMinBandwidth  = 10
+	defaultBandwidthGran = 10
+
+	// the sub directories of the emulated root
+	cgroupDir = "cgroup"
+	procDir   = "proc"
+	sysDir    = "sys"
+	sysFSDir  = "sys/fs"
+)
+
+// Spec describes the emulated host, which is usually loaded from a yaml file.
+type Spec struct {
+	// CgroupVersion is the version of the emulated cgroup filesystem, "v1" or "v2". Default is "v1".
+	CgroupVersion string `json:"cgroupVersion,omitempty"`
+	// CgroupDriver is the cgroup driver of the emulated kubelet, "systemd" or "cgroupfs". Default is "systemd".
+	CgroupDriver system.CgroupDriverType `json:"cgroupDriver,omitempty"`
+	// CPUs is the number of the emulated cpus. Default is 8.
+	CPUs int `json:"cpus,omitempty"`
+	// MemoryBytes is the emulated memory capacity. Default is 16Gi.
+	MemoryBytes int64 `json:"memoryBytes,omitempty"`
+	// Resctrl emulates the resctrl filesystem if specified.
+	Resctrl *ResctrlSpec `json:"resctrl,omitempty"`
+	// Cgroups are the extra cgroups to create besides the root, kubepods and qos cgroups, e.g. the pod cgroups.
+	Cgroups []CgroupSpec `json:"cgroups,omitempty"`
+	// Files are the extra files to write, whose paths are relative to the emulated root, e.g. `proc/loadavg`.
+	Files map[string]string `json:"files,omitempty"`
+	// Dynamics are the scripted changes of the files applied on each step.
+	Dynamics []Dynamic `json:"dynamics,omitempty"`
+	// StepSeconds is the interval of the steps when running. Default is 1.
+	StepSeconds int64 `json:"stepSeconds,omitempty"`
+}
+
+// ResctrlSpec describes the emulated resctrl filesystem.
+type ResctrlSpec struct {
+	// CacheIDs are the ids of the L3 cache domains. Default is [0].
+	CacheIDs []int `json:"cacheIDs,omitempty"`
+	// CBMMask is the cbm_mask of the L3 cache. Default is "fff".
+	CBMMask string `json:"cbmMask,omitempty"`
+	// MinBandwidth is the minimum memory bandwidth percent. Default is 10.
+	MinBandwidth int `json:"minBandwidth,omitempty"`
+	// BandwidthGran is the granularity of the memory bandwidth percent. Default is 10.
+	BandwidthGran int `json:"bandwidthGran,omitempty"`
+}
+
+// CgroupSpec describes an emulated cgroup.
+type CgroupSpec struct {
+	// Path is the cgroup parent dir, e.g. `kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod123.slice`.
+	Path string `json:"path"`
+	// Files overwrites the contents of the cgroup files, whose keys are the file names, e.g. `cpu.shares`.
+	Files map[string]string `json:"files,omitempty"`
+}
+
+// Dynamic changes the content of an emulated file on each step.
+type Dynamic struct {
+	// Path is the path relative to the emulated root, e.g. `cgroup/cpuacct/kubepods.slice/cpuacct.usage`.
+	Path string `json:"path"`
+	// Rate makes the file a counter increasing by the rate per second, e.g. the cpu usage in nanoseconds.
+	Rate *int64 `json:"rate,omitempty"`
+	// Values makes the file cycle through the values on each step, e.g. the memory usage in bytes.
+	Values []string `json:"values,omitempty"`
+}
+
+// Emulator maintains an emulated tree of the cgroup, resctrl and proc filesystems in a regular directory, so the
+// koordlet strategies can be exercised on the machines without the required kernel features or hardware.
+// The koordlet writes to the emulated files as usual, and the emulator applies the scripted dynamics and the
+// kernel behaviors needed, e.g. populating the files of a resctrl group created.
+type Emulator struct {
+	rootDir string
+	spec    *Spec
+
+	lock        sync.Mutex
+	valueIndex  map[int]int
+	lastStep    time.Time
+	timeNowFunc func() time.Time
+}
+
+// LoadSpec loads the spec from a yaml or json file.
+func LoadSpec(path string) (*Spec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read emulator spec %s, err: %w", path, err)
+	}
+	spec := &Spec{}
+	if err = yaml.UnmarshalStrict(content, spec); err != nil {
+		return nil, fmt.Errorf("failed to parse emulator spec %s, err: %w", path, err)
+	}
+	return spec, nil
+}
+
+func New(rootDir string, spec *Spec) (*Emulator, error) {
+	if spec == nil {
+		spec = &Spec{}
+	}
+	spec = spec.withDefaults()
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+	return &Emulator{
+		rootDir:     rootDir,
+		spec:        spec,
+		valueIndex:  map[int]int{},
+		timeNowFunc: time.Now,
+	}, nil
+}
+
+// Setup creates the emulated tree and points the system config to it. It must be called before the koordlet
+// initializes the system support configs.
+func (e *Emulator) Setup() error {
+	system.Conf.CgroupRootDir = filepath.Join(e.rootDir, cgroupDir)
+	system.Conf.ProcRootDir = filepath.Join(e.rootDir, procDir)
+	system.Conf.SysRootDir = filepath.Join(e.rootDir, sysDir)
+	system.Conf.SysFSRootDir = filepath.Join(e.rootDir, sysFSDir)
+	useCgroupsV2 := e.spec.CgroupVersion == CgroupVersionV2
+	system.OverrideCgroupsVersion(&useCgroupsV2)
+	system.SetupCgroupPathFormatter(e.spec.CgroupDriver)
+
+	if err := e.setupProc(); err != nil {
+		return err
+	}
+	if err := e.setupCgroups(); err != nil {
+		return err
+	}
+	if err := e.setupResctrl(); err != nil {
+		return err
+	}
+	for path, content := range e.spec.Files {
+		if err := writeFile(filepath.Join(e.rootDir, path), content); err != nil {
+			return err
+		}
+	}
+	e.lastStep = e.timeNowFunc()
+	klog.V(4).Infof("emulated system is set up in %s, cgroup version %s, driver %s",
+		e.rootDir, e.spec.CgroupVersion, e.spec.CgroupDriver)
+	return nil
+}
+
+// Run applies the dynamics periodically until stopped.
+func (e *Emulator) Run(stopCh <-chan struct{}) {
+	wait.Until(func() {
+		if err := e.Step(); err != nil {
+			klog.Warningf("failed to step the emulated system, err: %v", err)
+		}
+	}, time.Duration(e.spec.StepSeconds)*time.Second, stopCh)
+}
+
+// Step applies the dynamics with the time elapsed since the last step.
+func (e *Emulator) Step() error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	now := e.timeNowFunc()
+	elapsed := now.Sub(e.lastStep)
+	e.lastStep = now
+
+	var errs []string
+	for i, d := range e.spec.Dynamics {
+		if err := e.applyDynamic(i, &d, elapsed); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := e.populateResctrlGroups(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func (e *Emulator) applyDynamic(idx int, d *Dynamic, elapsed time.Duration) error {
+	path := filepath.Join(e.rootDir, d.Path)
+	if d.Rate != nil {
+		content, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s, err: %w", d.Path, err)
+		}
+		var current int64
+		if s := strings.TrimSpace(string(content)); len(s) > 0 {
+			if current, err = strconv.ParseInt(s, 10, 64); err != nil {
+				return fmt.Errorf("failed to parse counter %s, err: %w", d.Path, err)
+			}
+		}
+		current += int64(float64(*d.Rate) * elapsed.Seconds())
+		return writeFile(path, strconv.FormatInt(current, 10))
+	}
+	i := e.valueIndex[idx]
+	e.valueIndex[idx] = (i + 1) % len(d.Values)
+	return writeFile(path, d.Values[i])
+}
+
+func (e *Emulator) setupProc() error {
+	var cpuInfo, stat strings.Builder
+	flags := "fpu vme de pse tsc msr pae"
+	if e.spec.Resctrl != nil {
+		flags += " cat_l3 mba cqm_llc cqm_occup_llc cqm_mbm_total cqm_mbm_local"
+	}
+	stat.WriteString("cpu  0 0 0 0 0 0 0 0 0 0\n")
+	for i := 0; i < e.spec.CPUs; i++ {
+		fmt.Fprintf(&cpuInfo, "processor\t: %d\nvendor_id\t: %s\nphysical id\t: 0\ncore id\t\t: %d\nflags\t\t: %s\n\n",
+			i, system.INTEL_VENDOR_ID, i, flags)
+		fmt.Fprintf(&stat, "cpu%d 0 0 0 0 0 0 0 0 0 0\n", i)
+	}
+	memKB := e.spec.MemoryBytes >> 10
+	files := map[string]string{
+		system.ProcCPUInfoName:       cpuInfo.String(),
+		system.ProcStatName:          stat.String(),
+		system.ProcMemInfoName:       fmt.Sprintf("MemTotal:       %d kB\nMemFree:        %d kB\nMemAvailable:   %d kB\nBuffers:               0 kB\nCached:                0 kB\nActive(file):          0 kB\nInactive(file):        0 kB\n", memKB, memKB, memKB),
+		system.KernelCmdlineFileName: "BOOT_IMAGE=/vmlinuz root=/dev/vda1 rdt=cmt,l3cat,mba\n",
+	}
+	for name, content := range files {
+		if err := writeFile(filepath.Join(system.Conf.ProcRootDir, name), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) setupCgroups() error {
+	formatter := system.GetCgroupPathFormatter(e.spec.CgroupDriver)
+	dirs := []string{
+		"",
+		formatter.ParentDir,
+		filepath.Join(formatter.ParentDir, formatter.QOSDirFn(corev1.PodQOSBurstable)),
+		filepath.Join(formatter.ParentDir, formatter.QOSDirFn(corev1.PodQOSBestEffort)),
+	}
+	for _, dir := range dirs {
+		if err := e.setupCgroup(dir, nil); err != nil {
+			return err
+		}
+	}
+	for _, c := range e.spec.Cgroups {
+		if err := e.setupCgroup(c.Path, c.Files); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) setupCgroup(dir string, files map[string]string) error {
+	cpus := fmt.Sprintf("0-%d", e.spec.CPUs-1)
+	var defaultFiles []struct {
+		subfs   string
+		name    string
+		content string
+	}
+	add := func(subfs, name, content string) {
+		defaultFiles = append(defaultFiles, struct {
+			subfs   string
+			name    string
+			content string
+		}{subfs: subfs, name: name, content: content})
+	}
+	if e.spec.CgroupVersion == CgroupVersionV2 {
+		add("", "cgroup.controllers", "cpuset cpu io memory pids")
+		add("", "cgroup.subtree_control", "cpuset cpu io memory pids")
+		add("", system.CPUProcsName, "")
+		add("", system.CPUThreadsName, "")
+		add("", system.CPUMaxName, "max 100000")
+		add("", system.CPUWeightName, "100")
+		add("", system.CPUStatName, "usage_usec 0\nuser_usec 0\nsystem_usec 0\nnr_periods 0\nnr_throttled 0\nthrottled_usec 0\n")
+		add("", system.CPUSetCPUSName, cpus)
+		add("", system.CPUSetCPUSEffectiveName, cpus)
+		add("", "cpuset.mems", "0")
+		add("", system.MemoryMaxName, "max")
+		add("", system.MemoryCurrentName, "0")
+		add("", system.MemoryStatName, "anon 0\nfile 0\nactive_anon 0\ninactive_anon 0\nactive_file 0\ninactive_file 0\n")
+	} else {
+		add(system.CgroupCPUDir, system.CPUTasksName, "")
+		add(system.CgroupCPUDir, system.CPUProcsName, "")
+		add(system.CgroupCPUDir, system.CPUCFSQuotaName, "-1")
+		add(system.CgroupCPUDir, system.CPUCFSPeriodName, "100000")
+		add(system.CgroupCPUDir, system.CPUSharesName, "1024")
+		add(system.CgroupCPUDir, system.CPUStatName, "nr_periods 0\nnr_throttled 0\nthrottled_time 0\n")
+		add(system.CgroupCPUAcctDir, system.CPUAcctUsageName, "0")
+		add(system.CgroupCPUAcctDir, system.CPUAcctStatName, "user 0\nsystem 0\n")
+		add(system.CgroupCPUSetDir, system.CPUSetCPUSName, cpus)
+		add(system.CgroupCPUSetDir, "cpuset.mems", "0")
+		add(system.CgroupMemDir, system.MemoryLimitName, "9223372036854771712")
+		add(system.CgroupMemDir, system.MemoryUsageName, "0")
+		add(system.CgroupMemDir, system.MemoryStatName, "total_cache 0\ntotal_rss 0\ntotal_inactive_file 0\ntotal_active_file 0\ntotal_inactive_anon 0\ntotal_active_anon 0\n")
+	}
+	for _, f := range defaultFiles {
+		content := f.content
+		if c, ok := files[f.name]; ok {
+			content = c
+		}
+		if err := writeFile(filepath.Join(system.Conf.CgroupRootDir, f.subfs, dir, f.name), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Emulator) setupResctrl() error {
+	r := e.spec.Resctrl
+	if r == nil {
+		return nil
+	}
+	resctrlRoot := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)
+	files := map[string]string{
+		filepath.Join(system.RdtInfoDir, system.L3CatDir, system.ResctrlCbmMaskName):           r.CBMMask,
+		filepath.Join(system.RdtInfoDir, system.L3CatDir, "min_cbm_bits"):                      "1",
+		filepath.Join(system.RdtInfoDir, system.L3CatDir, system.ResctrlNumClosIDsName):        "16",
+		filepath.Join(system.RdtInfoDir, system.ResctrlMBDir, system.ResctrlMinBandwidthName):  strconv.Itoa(r.MinBandwidth),
+		filepath.Join(system.RdtInfoDir, system.ResctrlMBDir, system.ResctrlBandwidthGranName): strconv.Itoa(r.BandwidthGran),
+		filepath.Join(system.RdtInfoDir, system.ResctrlMBDir, system.ResctrlNumClosIDsName):    "8",
+		filepath.Join(system.RdtInfoDir, system.ResctrlL3MonDir, system.ResctrlNumRMIDsName):   "128",
+	}
+	for path, content := range files {
+		if err := writeFile(filepath.Join(resctrlRoot, path), content); err != nil {
+			return err
+		}
+	}
+	return e.setupResctrlGroup(resctrlRoot)
+}
+
+// setupResctrlGroup populates the files of a resctrl group like the kernel does when the group is created.
+func (e *Emulator) setupResctrlGroup(groupDir string) error {
+	r := e.spec.Resctrl
+	var l3, mb []string
+	for _, id := range r.CacheIDs {
+		l3 = append(l3, fmt.Sprintf("%d=%s", id, r.CBMMask))
+		mb = append(mb, fmt.Sprintf("%d=100", id))
+	}
+	schemata := fmt.Sprintf("%s:%s\n%s:%s\n", system.L3SchemataPrefix, strings.Join(l3, ";"),
+		system.MbSchemataPrefix, strings.Join(mb, ";"))
+	files := map[string]string{
+		system.ResctrlSchemataName: schemata,
+		system.ResctrlTasksName:    "",
+	}
+	for _, id := range r.CacheIDs {
+		monDir := filepath.Join(system.ResctrlMonData, fmt.Sprintf("%s_%02d", system.ResctrlMonL3DirPrefix, id))
+		files[filepath.Join(monDir, system.ResctrlLLCOccupancyName)] = "0"
+		files[filepath.Join(monDir, system.ResctrlMBMLocalName)] = "0"
+		files[filepath.Join(monDir, system.ResctrlMBMTotalName)] = "0"
+	}
+	for path, content := range files {
+		if err := writeFile(filepath.Join(groupDir, path), content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// populateResctrlGroups populates the files of the resctrl groups created since the last step.
+func (e *Emulator) populateResctrlGroups() error {
+	if e.spec.Resctrl == nil {
+		return nil
+	}
+	resctrlRoot := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)
+	entries, err := os.ReadDir(resctrlRoot)
+	if err != nil {
+		return fmt.Errorf("failed to read resctrl root, err: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == system.RdtInfoDir || entry.Name() == system.ResctrlMonData ||
+			entry.Name() == system.ResctrlMonGroupsDir {
+			continue
+		}
+		groupDir := filepath.Join(resctrlRoot, entry.Name())
+		if system.FileExists(filepath.Join(groupDir, system.ResctrlSchemataName)) {
+			continue
+		}
+		if err = e.setupResctrlGroup(groupDir); err != nil {
+			return err
+		}
+		klog.V(5).Infof("emulated resctrl group %s is populated", entry.Name())
+	}
+	return nil
+}
+
+func (s *Spec) withDefaults() *Spec {
+	out := *s
+	if len(out.CgroupVersion) == 0 {
+		out.CgroupVersion = CgroupVersionV1
+	}
+	if len(out.CgroupDriver) == 0 {
+		out.CgroupDriver = system.Systemd
+	}
+	if out.CPUs <= 0 {
+		out.CPUs = defaultCPUs
+	}
+	if out.MemoryBytes <= 0 {
+		out.MemoryBytes = defaultMemoryBytes
+	}
+	if out.StepSeconds <= 0 {
+		out.StepSeconds = int64(defaultStepInterval / time.Second)
+	}
+	if out.Resctrl != nil {
+		r := *out.Resctrl
+		if len(r.CacheIDs) == 0 {
+			r.CacheIDs = []int{0}
+		}
+		if len(r.CBMMask) == 0 {
+			r.CBMMask = defaultCBMMask
+		}
+		if r.MinBandwidth <= 0 {
+			r.MinBandwidth = defaultMinBandwidth
+		}
+		if r.BandwidthGran <= 0 {
+			r.BandwidthGran = defaultBandwidthGran
+		}
+		out.Resctrl = &r
+	}
+	return &out
+}
+
+func (s *Spec) validate() error {
+	if s.CgroupVersion != CgroupVersionV1 && s.CgroupVersion != CgroupVersionV2 {
+		return fmt.Errorf("unsupported cgroup version %s", s.CgroupVersion)
+	}
+	if !s.CgroupDriver.Validate() {
+		return fmt.Errorf("unsupported cgroup driver %s", s.CgroupDriver)
+	}
+	for i, d := range s.Dynamics {
+		if len(d.Path) == 0 {
+			return fmt.Errorf("path of dynamic %d is empty", i)
+		}
+		if (d.Rate == nil) == (len(d.Values) == 0) {
+			return fmt.Errorf("dynamic %s must specify exactly one of rate and values", d.Path)
+		}
+	}
+	return nil
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create dir for %s, err: %w", path, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s, err: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package emulator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/pkg/koordlet/util/system"
+)
+
+func setupTestConf(t *testing.T) string {
+	// the temp dir is removed after the config is restored
+	root := t.TempDir()
+	oldConf := *system.Conf
+	t.Cleanup(func() {
+		system.OverrideCgroupsVersion(nil)
+		*system.Conf = oldConf
+		system.SetupCgroupPathFormatter(system.Systemd)
+	})
+	return root
+}
+
+func readCgroupFile(t *testing.T, parentDir string, r system.Resource) string {
+	content, err := os.ReadFile(system.GetCgroupFilePath(parentDir, r))
+	assert.NoError(t, err)
+	return string(content)
+}
+
+func TestLoadSpec(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`cgroupVersion: v2
+cgroupDriver: cgroupfs
+cpus: 4
+resctrl:
+  cacheIDs: [0, 1]
+dynamics:
+- path: cgroup/kubepods/cpu.stat
+  values: ["usage_usec 100", "usage_usec 200"]
+`), 0644))
+	spec, err := LoadSpec(path)
+	assert.NoError(t, err)
+	assert.Equal(t, &Spec{
+		CgroupVersion: CgroupVersionV2,
+		CgroupDriver:  system.Cgroupfs,
+		CPUs:          4,
+		Resctrl:       &ResctrlSpec{CacheIDs: []int{0, 1}},
+		Dynamics: []Dynamic{
+			{Path: "cgroup/kubepods/cpu.stat", Values: []string{"usage_usec 100", "usage_usec 200"}},
+		},
+	}, spec)
+
+	assert.NoError(t, os.WriteFile(path, []byte("unknownField: 1\n"), 0644))
+	_, err = LoadSpec(path)
+	assert.Error(t, err)
+
+	_, err = LoadSpec(filepath.Join(dir, "not-exist.yaml"))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *Spec
+		wantErr bool
+	}{
+		{name: "nil spec uses defaults", spec: nil},
+		{name: "invalid cgroup version", spec: &Spec{CgroupVersion: "v3"}, wantErr: true},
+		{name: "invalid cgroup driver", spec: &Spec{CgroupDriver: "unknown"}, wantErr: true},
+		{name: "dynamic without path", spec: &Spec{Dynamics: []Dynamic{{Values: []string{"1"}}}}, wantErr: true},
+		{name: "dynamic with both rate and values", spec: &Spec{Dynamics: []Dynamic{{Path: "a", Rate: pointer.Int64(1), Values: []string{"1"}}}}, wantErr: true},
+		{name: "dynamic with neither rate nor values", spec: &Spec{Dynamics: []Dynamic{{Path: "a"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(t.TempDir(), tt.spec)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, CgroupVersionV1, e.spec.CgroupVersion)
+				assert.Equal(t, system.Systemd, e.spec.CgroupDriver)
+				assert.Equal(t, defaultCPUs, e.spec.CPUs)
+			}
+		})
+	}
+}
+
+func TestEmulatorSetupCgroupsV1(t *testing.T) {
+	root := setupTestConf(t)
+	e, err := New(root, &Spec{
+		Cgroups: []CgroupSpec{
+			{
+				Path:  "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod123.slice",
+				Files: map[string]string{system.CPUSharesName: "2"},
+			},
+		},
+		Files: map[string]string{"proc/loadavg": "0.00 0.00 0.00 1/100 1000\n"},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, e.Setup())
+
+	assert.False(t, system.GetCurrentCgroupVersion() == system.CgroupVersionV2)
+	assert.Equal(t, system.Systemd, system.GetCgroupDriver())
+	assert.Equal(t, filepath.Join(root, procDir), system.Conf.ProcRootDir)
+
+	assert.Equal(t, "2", readCgroupFile(t, "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod123.slice", system.CPUShares))
+	assert.Equal(t, "-1", readCgroupFile(t, "kubepods.slice", system.CPUCFSQuota))
+	assert.Equal(t, "0-7", readCgroupFile(t, "kubepods.slice/kubepods-burstable.slice", system.CPUSet))
+
+	content, err := os.ReadFile(filepath.Join(root, "proc/loadavg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0.00 0.00 0.00 1/100 1000\n", string(content))
+	assert.False(t, system.FileExists(filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir)))
+}
+
+func TestEmulatorSetupCgroupsV2(t *testing.T) {
+	root := setupTestConf(t)
+	e, err := New(root, &Spec{CgroupVersion: CgroupVersionV2, CgroupDriver: system.Cgroupfs, CPUs: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, e.Setup())
+
+	assert.Equal(t, system.CgroupVersionV2, system.GetCurrentCgroupVersion())
+	assert.Equal(t, system.Cgroupfs, system.GetCgroupDriver())
+	assert.Equal(t, "0-3", readCgroupFile(t, "kubepods/besteffort", system.CPUSetV2))
+	assert.Equal(t, "max", readCgroupFile(t, "kubepods", system.MemoryLimitV2))
+}
+
+func TestEmulatorResctrl(t *testing.T) {
+	root := setupTestConf(t)
+	e, err := New(root, &Spec{Resctrl: &ResctrlSpec{CacheIDs: []int{0, 1}}})
+	assert.NoError(t, err)
+	assert.NoError(t, e.Setup())
+
+	isSupported, err := system.IsSupportResctrl()
+	assert.NoError(t, err)
+	assert.True(t, isSupported)
+	ids, err := system.CacheIdsCacheFunc()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{0, 1}, ids)
+
+	groupDir := filepath.Join(system.Conf.SysFSRootDir, system.ResctrlDir, "LS")
+	assert.NoError(t, os.Mkdir(groupDir, 0755))
+	assert.NoError(t, e.Step())
+	content, err := os.ReadFile(filepath.Join(groupDir, system.ResctrlSchemataName))
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=fff;1=fff\nMB:0=100;1=100\n", string(content))
+
+	// the group written by the koordlet is not overwritten
+	assert.NoError(t, os.WriteFile(filepath.Join(groupDir, system.ResctrlSchemataName), []byte("L3:0=f;1=f\n"), 0644))
+	assert.NoError(t, e.Step())
+	content, err = os.ReadFile(filepath.Join(groupDir, system.ResctrlSchemataName))
+	assert.NoError(t, err)
+	assert.Equal(t, "L3:0=f;1=f\n", string(content))
+}
+
+func TestEmulatorStep(t *testing.T) {
+	root := setupTestConf(t)
+	usagePath := "cgroup/cpuacct/kubepods.slice/cpuacct.usage"
+	memPath := "cgroup/memory/kubepods.slice/memory.usage_in_bytes"
+	e, err := New(root, &Spec{
+		Dynamics: []Dynamic{
+			{Path: usagePath, Rate: pointer.Int64(2000000000)},
+			{Path: memPath, Values: []string{"100", "200"}},
+		},
+	})
+	assert.NoError(t, err)
+	now := time.Now()
+	e.timeNowFunc = func() time.Time { return now }
+	assert.NoError(t, e.Setup())
+
+	read := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(root, path))
+		assert.NoError(t, err)
+		return string(content)
+	}
+	wantUsages := []string{"1000000000", "2000000000", "3000000000"}
+	wantMems := []string{"100", "200", "100"}
+	for i := range wantUsages {
+		now = now.Add(500 * time.Millisecond)
+		assert.NoError(t, e.Step())
+		assert.Equal(t, wantUsages[i], read(usagePath))
+		assert.Equal(t, wantMems[i], read(memPath))
+	}
+
+	stopCh := make(chan struct{})
+	close(stopCh)
+	e.Run(stopCh)
+}
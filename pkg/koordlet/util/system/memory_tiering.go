@@ -0,0 +1,65 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	MemoryTieringRelativePath = "devices/virtual/memory_tiering/"
+	memoryTierPrefix          = "memory_tier"
+	memoryTierNodesFileName   = "nodelist"
+)
+
+// MemoryTier is a memory tier of the kernel, e.g. /sys/devices/virtual/memory_tiering/memory_tier4.
+// The numa nodes in the tier with a smaller id are faster.
+type MemoryTier struct {
+	Name     string
+	NodeList string
+}
+
+func GetMemoryTieringDir() string {
+	return filepath.Join(Conf.SysRootDir, MemoryTieringRelativePath)
+}
+
+// GetMemoryTiers returns the memory tiers of the node. It returns an error if the kernel does not expose the memory
+// tiers, which are supported since the kernel 6.1.
+func GetMemoryTiers() ([]MemoryTier, error) {
+	dir := GetMemoryTieringDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var tiers []MemoryTier
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), memoryTierPrefix) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name(), memoryTierNodesFileName))
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, MemoryTier{
+			Name:     entry.Name(),
+			NodeList: strings.TrimSpace(string(content)),
+		})
+	}
+	return tiers, nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMemoryTiers(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := GetMemoryTiers()
+	assert.Error(t, err)
+
+	helper.WriteFileContents(MemoryTieringRelativePath+"memory_tier4/nodelist", "0-1\n")
+	helper.WriteFileContents(MemoryTieringRelativePath+"memory_tier22/nodelist", "2\n")
+	helper.MkDirAll(MemoryTieringRelativePath + "power")
+	tiers, err := GetMemoryTiers()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []MemoryTier{
+		{Name: "memory_tier4", NodeList: "0-1"},
+		{Name: "memory_tier22", NodeList: "2"},
+	}, tiers)
+}
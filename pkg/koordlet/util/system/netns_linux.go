@@ -0,0 +1,72 @@
+//go:build linux
+// +build linux
+
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+// RunInNetNS runs the function in the network namespace of the given process.
+// The function is executed in a dedicated goroutine locked to its OS thread. If the thread fails to switch back to
+// the original network namespace, it is left locked and terminated by the go runtime after the goroutine exits.
+func RunInNetNS(pid uint32, fn func() error) error {
+	targetPath := filepath.Join(Conf.ProcRootDir, strconv.FormatUint(uint64(pid), 10), "ns", "net")
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+
+		originNS, err := os.Open(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()))
+		if err != nil {
+			runtime.UnlockOSThread()
+			errCh <- fmt.Errorf("failed to open current netns, err: %w", err)
+			return
+		}
+		defer originNS.Close()
+		targetNS, err := os.Open(targetPath)
+		if err != nil {
+			runtime.UnlockOSThread()
+			errCh <- fmt.Errorf("failed to open netns %s, err: %w", targetPath, err)
+			return
+		}
+		defer targetNS.Close()
+
+		if err = unix.Setns(int(targetNS.Fd()), unix.CLONE_NEWNET); err != nil {
+			runtime.UnlockOSThread()
+			errCh <- fmt.Errorf("failed to enter netns %s, err: %w", targetPath, err)
+			return
+		}
+		fnErr := fn()
+		if err = unix.Setns(int(originNS.Fd()), unix.CLONE_NEWNET); err != nil {
+			// keep the thread locked, so it will not be reused by other goroutines
+			klog.Errorf("failed to restore netns from %s, err: %v", targetPath, err)
+		} else {
+			runtime.UnlockOSThread()
+		}
+		errCh <- fnErr
+	}()
+	return <-errCh
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The energy counters are read from the RAPL (Running Average Power Limit) zones of the powercap, which are also
+// provided by the AMD cpus since linux 5.8, or from the amd_energy hwmon driver. The hwmon power sensors, e.g. the
+// ACPI power meter, report the instant power of the platform.
+const (
+	SysPowercapSubDir = "class/powercap"
+	SysHwmonSubDir    = "class/hwmon"
+
+	raplZonePrefix         = "intel-rapl:"
+	raplNameFile           = "name"
+	raplEnergyFile         = "energy_uj"
+	raplMaxEnergyRangeFile = "max_energy_range_uj"
+	raplPackageNamePrefix  = "package-"
+
+	hwmonNameFile = "name"
+	// AMDEnergyHwmonName is the hwmon name of the amd_energy driver.
+	AMDEnergyHwmonName    = "amd_energy"
+	amdEnergySocketPrefix = "Esocket"
+
+	PowerDomainPackage = "package"
+	PowerDomainDRAM    = "dram"
+	PowerDomainPSys    = "psys"
+
+	// PlatformSocketID is the socket id of the platform-wide counters, e.g. the psys domain.
+	PlatformSocketID = -1
+)
+
+// EnergyCounter is a cumulative energy counter of a power domain.
+type EnergyCounter struct {
+	// SocketID is the id of the cpu socket (package), or PlatformSocketID for the platform-wide domain.
+	SocketID int
+	// Domain is the power domain, e.g. package, dram, core, uncore and psys.
+	Domain string
+	// EnergyUJ is the cumulative energy in microjoules.
+	EnergyUJ uint64
+	// MaxEnergyRangeUJ is the range in microjoules after which the counter wraps around, or zero if unknown.
+	MaxEnergyRangeUJ uint64
+}
+
+// Key returns the unique key of the counter on the node.
+func (e *EnergyCounter) Key() string {
+	return fmt.Sprintf("%d/%s", e.SocketID, e.Domain)
+}
+
+// PowerSensor is an instant power sensor of the hwmon.
+type PowerSensor struct {
+	// Name is the hwmon name and the sensor label, e.g. power_meter/power1.
+	Name string
+	// PowerUW is the power in microwatts.
+	PowerUW uint64
+}
+
+func GetPowercapDir() string {
+	return filepath.Join(Conf.SysRootDir, SysPowercapSubDir)
+}
+
+func GetHwmonDir() string {
+	return filepath.Join(Conf.SysRootDir, SysHwmonSubDir)
+}
+
+// GetRAPLEnergyCounters returns the energy counters of the RAPL zones and subzones ordered by the key.
+// It returns an empty list without error if the RAPL is not supported.
+func GetRAPLEnergyCounters() ([]EnergyCounter, error) {
+	entries, err := os.ReadDir(GetPowercapDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read powercap dir, err: %w", err)
+	}
+
+	// the top-level zones are named as intel-rapl:<zone>, and the subzones as intel-rapl:<zone>:<subzone>
+	zoneSockets := map[string]int{}
+	var subzones []string
+	var counters []EnergyCounter
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), raplZonePrefix) {
+			continue
+		}
+		ids := strings.Split(strings.TrimPrefix(entry.Name(), raplZonePrefix), ":")
+		if len(ids) > 1 {
+			subzones = append(subzones, entry.Name())
+			continue
+		}
+		counter, err := readRAPLZone(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(counter.Domain, raplPackageNamePrefix) {
+			socketID, err := strconv.Atoi(strings.TrimPrefix(counter.Domain, raplPackageNamePrefix))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse socket of rapl zone %s, err: %w", entry.Name(), err)
+			}
+			counter.SocketID = socketID
+			counter.Domain = PowerDomainPackage
+		}
+		zoneSockets[ids[0]] = counter.SocketID
+		counters = append(counters, *counter)
+	}
+	for _, subzone := range subzones {
+		counter, err := readRAPLZone(subzone)
+		if err != nil {
+			return nil, err
+		}
+		ids := strings.Split(strings.TrimPrefix(subzone, raplZonePrefix), ":")
+		socketID, ok := zoneSockets[ids[0]]
+		if !ok {
+			continue
+		}
+		counter.SocketID = socketID
+		counters = append(counters, *counter)
+	}
+	sortEnergyCounters(counters)
+	return counters, nil
+}
+
+func readRAPLZone(zone string) (*EnergyCounter, error) {
+	zoneDir := filepath.Join(GetPowercapDir(), zone)
+	name, err := os.ReadFile(filepath.Join(zoneDir, raplNameFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read name of rapl zone %s, err: %w", zone, err)
+	}
+	energy, err := readUint64File(filepath.Join(zoneDir, raplEnergyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read energy of rapl zone %s, err: %w", zone, err)
+	}
+	// the max range can be missing on some platforms, so only the counter is required
+	maxRange, _ := readUint64File(filepath.Join(zoneDir, raplMaxEnergyRangeFile))
+	return &EnergyCounter{
+		SocketID:         PlatformSocketID,
+		Domain:           strings.TrimSpace(string(name)),
+		EnergyUJ:         energy,
+		MaxEnergyRangeUJ: maxRange,
+	}, nil
+}
+
+// GetAMDEnergyCounters returns the socket energy counters of the amd_energy hwmon ordered by the key.
+// It returns an empty list without error if the driver is not loaded.
+func GetAMDEnergyCounters() ([]EnergyCounter, error) {
+	hwmonDirs, err := getHwmonDirsByName()
+	if err != nil {
+		return nil, err
+	}
+	var counters []EnergyCounter
+	for _, hwmonDir := range hwmonDirs[AMDEnergyHwmonName] {
+		labelPaths, err := filepath.Glob(filepath.Join(hwmonDir, "energy*_label"))
+		if err != nil {
+			return nil, err
+		}
+		for _, labelPath := range labelPaths {
+			label, err := os.ReadFile(labelPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read amd energy label %s, err: %w", labelPath, err)
+			}
+			// only the socket counters are collected, e.g. Esocket0, while the core counters are like Ecore000
+			socket := strings.TrimSpace(string(label))
+			if !strings.HasPrefix(socket, amdEnergySocketPrefix) {
+				continue
+			}
+			socketID, err := strconv.Atoi(strings.TrimPrefix(socket, amdEnergySocketPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse amd energy label %s, err: %w", socket, err)
+			}
+			energy, err := readUint64File(strings.TrimSuffix(labelPath, "_label") + "_input")
+			if err != nil {
+				return nil, fmt.Errorf("failed to read amd energy of %s, err: %w", socket, err)
+			}
+			// the driver accumulates the 32-bit hardware counters into 64-bit ones
+			counters = append(counters, EnergyCounter{
+				SocketID: socketID,
+				Domain:   PowerDomainPackage,
+				EnergyUJ: energy,
+			})
+		}
+	}
+	sortEnergyCounters(counters)
+	return counters, nil
+}
+
+// GetHwmonPowerSensors returns the power sensors of the hwmon ordered by the name.
+// It returns an empty list without error if there is no power sensor.
+func GetHwmonPowerSensors() ([]PowerSensor, error) {
+	hwmonDirs, err := getHwmonDirsByName()
+	if err != nil {
+		return nil, err
+	}
+	var sensors []PowerSensor
+	for name, dirs := range hwmonDirs {
+		for _, hwmonDir := range dirs {
+			inputPaths, err := filepath.Glob(filepath.Join(hwmonDir, "power*_input"))
+			if err != nil {
+				return nil, err
+			}
+			for _, inputPath := range inputPaths {
+				power, err := readUint64File(inputPath)
+				if err != nil {
+					return nil, fmt.Errorf("failed to read hwmon power %s, err: %w", inputPath, err)
+				}
+				sensor := strings.TrimSuffix(filepath.Base(inputPath), "_input")
+				if label, err := os.ReadFile(strings.TrimSuffix(inputPath, "_input") + "_label"); err == nil {
+					sensor = strings.TrimSpace(string(label))
+				}
+				sensors = append(sensors, PowerSensor{
+					Name:    name + "/" + sensor,
+					PowerUW: power,
+				})
+			}
+		}
+	}
+	sort.Slice(sensors, func(i, j int) bool {
+		return sensors[i].Name < sensors[j].Name
+	})
+	return sensors, nil
+}
+
+// getHwmonDirsByName returns the hwmon dirs grouped by the hwmon names.
+func getHwmonDirsByName() (map[string][]string, error) {
+	entries, err := os.ReadDir(GetHwmonDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read hwmon dir, err: %w", err)
+	}
+	hwmonDirs := map[string][]string{}
+	for _, entry := range entries {
+		// the hwmon entries are usually the symlinks to the devices
+		hwmonDir := filepath.Join(GetHwmonDir(), entry.Name())
+		name, err := os.ReadFile(filepath.Join(hwmonDir, hwmonNameFile))
+		if err != nil {
+			continue
+		}
+		hwmonName := strings.TrimSpace(string(name))
+		hwmonDirs[hwmonName] = append(hwmonDirs[hwmonName], hwmonDir)
+	}
+	return hwmonDirs, nil
+}
+
+func sortEnergyCounters(counters []EnergyCounter) {
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].SocketID != counters[j].SocketID {
+			return counters[i].SocketID < counters[j].SocketID
+		}
+		return counters[i].Domain < counters[j].Domain
+	})
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRAPLEnergyCounters(t *testing.T) {
+	t.Run("rapl not supported", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		got, err := GetRAPLEnergyCounters()
+		assert.NoError(t, err)
+		assert.Nil(t, got)
+	})
+	t.Run("read rapl zones", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		helper.WriteFileContents("class/powercap/intel-rapl/enabled", "1\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0/name", "package-0\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0/energy_uj", "1000\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0/max_energy_range_uj", "262143328850\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0:0/name", "dram\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0:0/energy_uj", "200\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:1/name", "package-1\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:1/energy_uj", "3000\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:2/name", "psys\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:2/energy_uj", "5000\n")
+		got, err := GetRAPLEnergyCounters()
+		assert.NoError(t, err)
+		assert.Equal(t, []EnergyCounter{
+			{SocketID: PlatformSocketID, Domain: PowerDomainPSys, EnergyUJ: 5000},
+			{SocketID: 0, Domain: PowerDomainDRAM, EnergyUJ: 200},
+			{SocketID: 0, Domain: PowerDomainPackage, EnergyUJ: 1000, MaxEnergyRangeUJ: 262143328850},
+			{SocketID: 1, Domain: PowerDomainPackage, EnergyUJ: 3000},
+		}, got)
+	})
+	t.Run("invalid energy", func(t *testing.T) {
+		helper := NewFileTestUtil(t)
+		defer helper.Cleanup()
+		helper.WriteFileContents("class/powercap/intel-rapl:0/name", "package-0\n")
+		helper.WriteFileContents("class/powercap/intel-rapl:0/energy_uj", "invalid\n")
+		got, err := GetRAPLEnergyCounters()
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestGetAMDEnergyCounters(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	got, err := GetAMDEnergyCounters()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	helper.WriteFileContents("class/hwmon/hwmon0/name", "k10temp\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/name", "amd_energy\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy1_label", "Ecore000\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy1_input", "100\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy65_label", "Esocket0\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy65_input", "2000\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy66_label", "Esocket1\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/energy66_input", "3000\n")
+	got, err = GetAMDEnergyCounters()
+	assert.NoError(t, err)
+	assert.Equal(t, []EnergyCounter{
+		{SocketID: 0, Domain: PowerDomainPackage, EnergyUJ: 2000},
+		{SocketID: 1, Domain: PowerDomainPackage, EnergyUJ: 3000},
+	}, got)
+}
+
+func TestGetHwmonPowerSensors(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	got, err := GetHwmonPowerSensors()
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	helper.WriteFileContents("class/hwmon/hwmon0/name", "power_meter\n")
+	helper.WriteFileContents("class/hwmon/hwmon0/power1_input", "350000000\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/name", "psu\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/power1_label", "pin\n")
+	helper.WriteFileContents("class/hwmon/hwmon1/power1_input", "400000000\n")
+	got, err = GetHwmonPowerSensors()
+	assert.NoError(t, err)
+	assert.Equal(t, []PowerSensor{
+		{Name: "power_meter/power1", PowerUW: 350000000},
+		{Name: "psu/pin", PowerUW: 400000000},
+	}, got)
+}
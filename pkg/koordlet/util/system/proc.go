@@ -31,6 +31,9 @@ const (
 	ProcStatName    = "stat"
 	ProcMemInfoName = "meminfo"
 	ProcCPUInfoName = "cpuinfo"
+
+	ProcDiskStatsName = "diskstats"
+	ProcNetDevName    = "net/dev"
 )
 
 func GetProcFilePath(procRelativePath string) string {
@@ -52,6 +55,128 @@ type ProcStat struct {
 	// TODO: add more fields if needed
 }
 
+// DiskStat is the IO statistics of a block device in /proc/diskstats.
+// https://www.kernel.org/doc/Documentation/ABI/testing/procfs-diskstats
+type DiskStat struct {
+	// Device is the block device number `major:minor`.
+	Device string
+	Name   string
+	// ReadIOs is the number of reads completed.
+	ReadIOs uint64
+	// ReadTicks is the time spent reading in milliseconds.
+	ReadTicks uint64
+	// WriteIOs is the number of writes completed.
+	WriteIOs uint64
+	// WriteTicks is the time spent writing in milliseconds.
+	WriteTicks uint64
+}
+
+// NetDevStat is the statistics of a network interface in /proc/<pid>/net/dev, which is the view of the network
+// namespace of the process.
+type NetDevStat struct {
+	Name      string
+	RxBytes   uint64
+	RxPackets uint64
+	RxDrops   uint64
+	TxBytes   uint64
+	TxPackets uint64
+	TxDrops   uint64
+}
+
+// ParseProcDiskStats parses the content of /proc/diskstats into the stats keyed by the device number.
+// pattern: `   8       0 sda 1000 10 20000 500 2000 20 40000 1000 0 1200 1500 0 0 0 0`
+func ParseProcDiskStats(content string) (map[string]*DiskStat, error) {
+	stats := map[string]*DiskStat{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 11 {
+			return nil, fmt.Errorf("failed to parse diskstats, err: fields not enough %s", line)
+		}
+		stat := &DiskStat{
+			Device: fields[0] + ":" + fields[1],
+			Name:   fields[2],
+		}
+		for _, t := range []struct {
+			idx   int
+			value *uint64
+		}{
+			{idx: 3, value: &stat.ReadIOs},
+			{idx: 6, value: &stat.ReadTicks},
+			{idx: 7, value: &stat.WriteIOs},
+			{idx: 10, value: &stat.WriteTicks},
+		} {
+			v, err := strconv.ParseUint(fields[t.idx], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse diskstats, err: invalid field %s of %s", fields[t.idx], line)
+			}
+			*t.value = v
+		}
+		stats[stat.Device] = stat
+	}
+	return stats, nil
+}
+
+func GetDiskStats() (map[string]*DiskStat, error) {
+	content, err := os.ReadFile(GetProcFilePath(ProcDiskStatsName))
+	if err != nil {
+		return nil, err
+	}
+	return ParseProcDiskStats(string(content))
+}
+
+// ParseProcNetDev parses the content of /proc/<pid>/net/dev into the stats keyed by the interface name.
+// pattern:
+// `Inter-|   Receive                                                |  Transmit`
+// ` face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed`
+// `  eth0: 1000 10 0 1 0 0 0 0 2000 20 0 2 0 0 0 0`
+func ParseProcNetDev(content string) (map[string]*NetDevStat, error) {
+	stats := map[string]*NetDevStat{}
+	for _, line := range strings.Split(content, "\n") {
+		name, counters, ok := strings.Cut(line, ":")
+		if !ok { // the header lines
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			return nil, fmt.Errorf("failed to parse net dev, err: fields not enough %s", line)
+		}
+		stat := &NetDevStat{
+			Name: strings.TrimSpace(name),
+		}
+		for _, t := range []struct {
+			idx   int
+			value *uint64
+		}{
+			{idx: 0, value: &stat.RxBytes},
+			{idx: 1, value: &stat.RxPackets},
+			{idx: 3, value: &stat.RxDrops},
+			{idx: 8, value: &stat.TxBytes},
+			{idx: 9, value: &stat.TxPackets},
+			{idx: 11, value: &stat.TxDrops},
+		} {
+			v, err := strconv.ParseUint(fields[t.idx], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse net dev, err: invalid field %s of %s", fields[t.idx], line)
+			}
+			*t.value = v
+		}
+		stats[stat.Name] = stat
+	}
+	return stats, nil
+}
+
+// GetPIDNetDevStats returns the network interface stats in the network namespace of the process.
+func GetPIDNetDevStats(pid uint32) (map[string]*NetDevStat, error) {
+	content, err := os.ReadFile(filepath.Join(Conf.ProcRootDir, strconv.FormatUint(uint64(pid), 10), ProcNetDevName))
+	if err != nil {
+		return nil, err
+	}
+	return ParseProcNetDev(string(content))
+}
+
 func GetProcPIDStatPath(pid uint32) string {
 	return filepath.Join(Conf.ProcRootDir, strconv.FormatUint(uint64(pid), 10), ProcStatName)
 }
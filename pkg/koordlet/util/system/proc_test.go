@@ -295,3 +295,50 @@ func TestGetContainerPGIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDiskStats(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	_, err := GetDiskStats()
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents(ProcDiskStatsName, `   8       0 sda 1000 10 20000 500 2000 20 40000 1000 0 1200 1500 0 0 0 0
+   8       1 sda1 900 10 18000 450 1800 20 36000 900 0 1100 1350 0 0 0 0
+ 253       0 dm-0 0 0 0 0 0 0 0 0 0 0 0
+`)
+	got, err := GetDiskStats()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*DiskStat{
+		"8:0":   {Device: "8:0", Name: "sda", ReadIOs: 1000, ReadTicks: 500, WriteIOs: 2000, WriteTicks: 1000},
+		"8:1":   {Device: "8:1", Name: "sda1", ReadIOs: 900, ReadTicks: 450, WriteIOs: 1800, WriteTicks: 900},
+		"253:0": {Device: "253:0", Name: "dm-0"},
+	}, got)
+
+	helper.WriteProcSubFileContents(ProcDiskStatsName, "   8       0 sda 1000 10\n")
+	_, err = GetDiskStats()
+	assert.Error(t, err)
+}
+
+func TestGetPIDNetDevStats(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("100/"+ProcNetDevName, `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:     200       2    0    0    0     0          0         0      200       2    0    0    0     0       0          0
+  eth0: 1000 10 0 1 0 0 0 0 2000 20 0 2 0 0 0 0
+`)
+	got, err := GetPIDNetDevStats(100)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*NetDevStat{
+		"lo":   {Name: "lo", RxBytes: 200, RxPackets: 2, TxBytes: 200, TxPackets: 2},
+		"eth0": {Name: "eth0", RxBytes: 1000, RxPackets: 10, RxDrops: 1, TxBytes: 2000, TxPackets: 20, TxDrops: 2},
+	}, got)
+
+	_, err = GetPIDNetDevStats(101)
+	assert.Error(t, err)
+
+	helper.WriteProcSubFileContents("100/"+ProcNetDevName, "  eth0: 1000 10 0 1\n")
+	_, err = GetPIDNetDevStats(100)
+	assert.Error(t, err)
+}
@@ -29,6 +29,12 @@ import (
 
 const psiLineFormat = "avg10=%f avg60=%f avg300=%f total=%d"
 
+const (
+	ProcPressureCPUName    = "pressure/cpu"
+	ProcPressureMemoryName = "pressure/memory"
+	ProcPressureIOName     = "pressure/io"
+)
+
 type PSIPath struct {
 	CPU string
 	Mem string
@@ -93,6 +99,26 @@ func ParsePSIStats(r io.Reader) (PSIStats, error) {
 	return psiStats, nil
 }
 
+// GetNodePSIPath returns the node-level PSI paths in the procfs, e.g. /proc/pressure/cpu.
+func GetNodePSIPath() PSIPath {
+	return PSIPath{
+		CPU: GetProcFilePath(ProcPressureCPUName),
+		Mem: GetProcFilePath(ProcPressureMemoryName),
+		IO:  GetProcFilePath(ProcPressureIOName),
+	}
+}
+
+// IsNodePSISupported checks if the kernel exposes the node-level PSI files.
+func IsNodePSISupported() bool {
+	paths := GetNodePSIPath()
+	for _, p := range []string{paths.CPU, paths.Mem, paths.IO} {
+		if !FileExists(p) {
+			return false
+		}
+	}
+	return true
+}
+
 func GetPSIByResource(paths PSIPath) (*PSIByResource, error) {
 	cpuStats, err := readPSI(paths.CPU)
 	if err != nil {
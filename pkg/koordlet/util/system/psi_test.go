@@ -148,3 +148,21 @@ func TestGetPSIRecords_FullNotSupported(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, false, psi.FullSupported)
 }
+
+func TestNodePSI(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	assert.False(t, IsNodePSISupported())
+
+	helper.WriteProcSubFileContents(ProcPressureCPUName, "some avg10=1.00 avg60=2.00 avg300=3.00 total=100")
+	helper.WriteProcSubFileContents(ProcPressureMemoryName, FullCorrectPSIContents)
+	assert.False(t, IsNodePSISupported())
+	helper.WriteProcSubFileContents(ProcPressureIOName, FullCorrectPSIContents)
+	assert.True(t, IsNodePSISupported())
+
+	psi, err := GetPSIByResource(GetNodePSIPath())
+	assert.NoError(t, err)
+	assert.Equal(t, &PSILine{Avg10: 1, Avg60: 2, Avg300: 3, Total: 100}, psi.CPU.Some)
+	assert.False(t, psi.CPU.FullSupported)
+	assert.True(t, psi.Mem.FullSupported)
+}
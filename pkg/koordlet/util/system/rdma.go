@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The RDMA devices (e.g. mlx5_0) are discovered from the infiniband class of the sysfs, where each port exposes the
+// traffic counters defined by the InfiniBand spec.
+const (
+	SysInfinibandSubDir = "class/infiniband"
+
+	rdmaPortsDir          = "ports"
+	rdmaPortCountersDir   = "counters"
+	rdmaPortXmitDataFile  = "port_xmit_data"
+	rdmaPortRcvDataFile   = "port_rcv_data"
+	rdmaPortDataLaneBytes = 4
+)
+
+// RDMAPortCounters is the traffic counters of a port of the RDMA device.
+type RDMAPortCounters struct {
+	Device string
+	Port   string
+	// TxBytes is the total bytes transmitted on the port.
+	TxBytes uint64
+	// RxBytes is the total bytes received on the port.
+	RxBytes uint64
+}
+
+// RDMAResourceCount is the count of the RDMA resources charged to a cgroup, which comes from the `rdma.current`.
+type RDMAResourceCount struct {
+	HCAHandle int64
+	HCAObject int64
+}
+
+func GetInfinibandDir() string {
+	return filepath.Join(Conf.SysRootDir, SysInfinibandSubDir)
+}
+
+// GetRDMADevices returns the names of the RDMA devices in order, e.g. [mlx5_0, mlx5_1].
+// It returns an empty list without error if no RDMA device is found.
+func GetRDMADevices() ([]string, error) {
+	entries, err := os.ReadDir(GetInfinibandDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read infiniband dir, err: %w", err)
+	}
+	devices := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		devices = append(devices, entry.Name())
+	}
+	sort.Strings(devices)
+	return devices, nil
+}
+
+// GetRDMADevicePorts returns the port numbers of the RDMA device in order, e.g. [1, 2].
+func GetRDMADevicePorts(device string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(GetInfinibandDir(), device, rdmaPortsDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ports of rdma device %s, err: %w", device, err)
+	}
+	ports := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ports = append(ports, entry.Name())
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		pi, _ := strconv.Atoi(ports[i])
+		pj, _ := strconv.Atoi(ports[j])
+		return pi < pj
+	})
+	return ports, nil
+}
+
+// GetRDMAPortCounters returns the traffic counters of all ports of the RDMA device.
+// NOTE: The `port_xmit_data` and `port_rcv_data` are counted in the units of 4 bytes (octets divided by 4).
+func GetRDMAPortCounters(device string) ([]RDMAPortCounters, error) {
+	ports, err := GetRDMADevicePorts(device)
+	if err != nil {
+		return nil, err
+	}
+	counters := make([]RDMAPortCounters, 0, len(ports))
+	for _, port := range ports {
+		countersDir := filepath.Join(GetInfinibandDir(), device, rdmaPortsDir, port, rdmaPortCountersDir)
+		xmitData, err := readUint64File(filepath.Join(countersDir, rdmaPortXmitDataFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of rdma device %s port %s, err: %w", rdmaPortXmitDataFile, device, port, err)
+		}
+		rcvData, err := readUint64File(filepath.Join(countersDir, rdmaPortRcvDataFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of rdma device %s port %s, err: %w", rdmaPortRcvDataFile, device, port, err)
+		}
+		counters = append(counters, RDMAPortCounters{
+			Device:  device,
+			Port:    port,
+			TxBytes: xmitData * rdmaPortDataLaneBytes,
+			RxBytes: rcvData * rdmaPortDataLaneBytes,
+		})
+	}
+	return counters, nil
+}
+
+// ParseRDMACurrent parses the content of the `rdma.current`, which is like:
+// `mlx5_0 hca_handle=2 hca_object=2000\nmlx5_1 hca_handle=1 hca_object=20`
+func ParseRDMACurrent(content string) (map[string]RDMAResourceCount, error) {
+	result := map[string]RDMAResourceCount{}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		count := RDMAResourceCount{}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return nil, fmt.Errorf("invalid rdma resource %s of device %s", field, fields[0])
+			}
+			v, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid rdma resource %s of device %s, err: %w", field, fields[0], err)
+			}
+			switch kv[0] {
+			case "hca_handle":
+				count.HCAHandle = v
+			case "hca_object":
+				count.HCAObject = v
+			}
+		}
+		result[fields[0]] = count
+	}
+	return result, nil
+}
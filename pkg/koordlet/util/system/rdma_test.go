@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRDMAPortCounters(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	devices, err := GetRDMADevices()
+	assert.NoError(t, err)
+	assert.Nil(t, devices)
+
+	helper.WriteFileContents("class/infiniband/mlx5_1/ports/1/counters/port_xmit_data", "0\n")
+	helper.WriteFileContents("class/infiniband/mlx5_1/ports/1/counters/port_rcv_data", "0\n")
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/2/counters/port_xmit_data", "200\n")
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/2/counters/port_rcv_data", "400\n")
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/1/counters/port_xmit_data", "1024\n")
+	helper.WriteFileContents("class/infiniband/mlx5_0/ports/1/counters/port_rcv_data", "2048\n")
+	helper.WriteFileContents("class/infiniband/mlx5_2/ports/1/counters/port_xmit_data", "invalid\n")
+
+	devices, err = GetRDMADevices()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mlx5_0", "mlx5_1", "mlx5_2"}, devices)
+
+	counters, err := GetRDMAPortCounters("mlx5_0")
+	assert.NoError(t, err)
+	assert.Equal(t, []RDMAPortCounters{
+		{Device: "mlx5_0", Port: "1", TxBytes: 4096, RxBytes: 8192},
+		{Device: "mlx5_0", Port: "2", TxBytes: 800, RxBytes: 1600},
+	}, counters)
+
+	_, err = GetRDMAPortCounters("mlx5_2")
+	assert.Error(t, err)
+	_, err = GetRDMAPortCounters("mlx5_3")
+	assert.Error(t, err)
+}
+
+func TestParseRDMACurrent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]RDMAResourceCount
+		wantErr bool
+	}{
+		{
+			name:    "parse multiple devices",
+			content: "mlx5_0 hca_handle=2 hca_object=2000\nmlx5_1 hca_handle=1 hca_object=20\n",
+			want: map[string]RDMAResourceCount{
+				"mlx5_0": {HCAHandle: 2, HCAObject: 2000},
+				"mlx5_1": {HCAHandle: 1, HCAObject: 20},
+			},
+		},
+		{
+			name:    "parse empty content",
+			content: "",
+			want:    map[string]RDMAResourceCount{},
+		},
+		{
+			name:    "invalid resource",
+			content: "mlx5_0 hca_handle",
+			wantErr: true,
+		},
+		{
+			name:    "invalid value",
+			content: "mlx5_0 hca_handle=max",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRDMACurrent(tt.content)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -41,13 +41,32 @@ const (
 	ResctrlDir string = "resctrl/"
 	RdtInfoDir string = "info"
 	L3CatDir   string = "L3"
+	L2CatDir   string = "L2"
+	// L3CodeCatDir and L3DataCatDir are the l3 cat info dirs when the Code/Data Prioritization (CDP) is enabled,
+	// e.g. mounted with `-o cdp`, where the L3CatDir is not provided.
+	L3CodeCatDir string = "L3CODE"
+	L3DataCatDir string = "L3DATA"
 
 	ResctrlSchemataName string = "schemata"
 	ResctrlCbmMaskName  string = "cbm_mask"
 	ResctrlTasksName    string = "tasks"
+	// ResctrlNumClosIDsName is the info file of the number of the CLOSIDs, e.g. /sys/fs/resctrl/info/L3/num_closids
+	ResctrlNumClosIDsName string = "num_closids"
+	// ResctrlNumRMIDsName is the info file of the number of the RMIDs, e.g. /sys/fs/resctrl/info/L3_MON/num_rmids
+	ResctrlNumRMIDsName string = "num_rmids"
+	// ResctrlBandwidthGranName is the info file of the mba granularity, e.g. /sys/fs/resctrl/info/MB/bandwidth_gran
+	ResctrlBandwidthGranName string = "bandwidth_gran"
+	// ResctrlMinBandwidthName is the info file of the minimum mba percent, e.g. /sys/fs/resctrl/info/MB/min_bandwidth
+	ResctrlMinBandwidthName string = "min_bandwidth"
 
 	// L3SchemataPrefix is the prefix of l3 cat schemata
 	L3SchemataPrefix = "L3"
+	// L3CodeSchemataPrefix is the prefix of l3 cat schemata for the code when the CDP is enabled
+	L3CodeSchemataPrefix = "L3CODE"
+	// L3DataSchemataPrefix is the prefix of l3 cat schemata for the data when the CDP is enabled
+	L3DataSchemataPrefix = "L3DATA"
+	// L2SchemataPrefix is the prefix of l2 cat schemata
+	L2SchemataPrefix = "L2"
 	// MbSchemataPrefix is the prefix of mba schemata
 	MbSchemataPrefix = "MB"
 
@@ -56,9 +75,27 @@ const (
 	ResctrlMBMLocalName     = "mbm_local_bytes"
 	ResctrlMBMTotalName     = "mbm_total_bytes"
 
+	// ResctrlMonL3DirPrefix is the prefix of the l3 monitoring domains in the mon_data, e.g. mon_L3_00
+	ResctrlMonL3DirPrefix = "mon_L3"
+	// ResctrlMonL2DirPrefix is the prefix of the l2 monitoring domains in the mon_data, e.g. mon_L2_00,
+	// which is provided by the platforms supporting the l2 occupancy monitoring.
+	ResctrlMonL2DirPrefix = "mon_L2"
+	// ResctrlMonMBDirPrefix is the prefix of the memory bandwidth monitoring domains in the mon_data, e.g. mon_MB_00,
+	// which is provided by the ARM MPAM when the bandwidth monitors are not located at the L3 cache.
+	ResctrlMonMBDirPrefix = "mon_MB"
+	ResctrlL3MonDir       = "L3_MON"
+	ResctrlL2MonDir       = "L2_MON"
+	ResctrlMBDir          = "MB"
+	// ResctrlMonGroupsDir is the dir of the monitoring groups under a control group, e.g. /sys/fs/resctrl/BE/mon_groups
+	ResctrlMonGroupsDir = "mon_groups"
+
 	// other cpu vendor like "GenuineIntel"
 	AMD_VENDOR_ID   = "AuthenticAMD"
 	INTEL_VENDOR_ID = "GenuineIntel"
+	// ARM cpu vendors which are recognized by the "CPU implementer" in the cpu info
+	ARM_VENDOR_ID       = "ARM"
+	HISILICON_VENDOR_ID = "HiSilicon"
+	AMPERE_VENDOR_ID    = "Ampere"
 )
 
 var (
@@ -68,14 +105,32 @@ var (
 	isSupportResctrlCollector bool
 	collectorOnceFunc         sync.Once
 	CacheIdsCacheFunc         func() ([]int, error)
-	ARM_VENDOR_ID_MAP         = map[string]struct{}{} // support MPAM ARM vendor ids
+	L2CacheIdsCacheFunc       func() ([]int, error)
+	ARM_VENDOR_ID_MAP         = map[string]struct{}{ // support MPAM ARM vendor ids
+		ARM_VENDOR_ID:       {},
+		HISILICON_VENDOR_ID: {},
+		AMPERE_VENDOR_ID:    {},
+	}
+	// armCPUImplementers maps the ARM "CPU implementer" codes to the vendor ids.
+	armCPUImplementers = map[string]string{
+		"0x41": ARM_VENDOR_ID,
+		"0x48": HISILICON_VENDOR_ID,
+		"0xc0": AMPERE_VENDOR_ID,
+	}
 )
 
 func init() {
 	CacheIdsCacheFunc = util.OnceValues(GetCacheIds)
+	L2CacheIdsCacheFunc = util.OnceValues(GetL2CacheIds)
 }
 
 func isCPUSupportResctrl() (bool, error) {
+	if IsMPAMVendor() {
+		// MPAM features are not reported in the cpu flags, check the resctrl info instead
+		isCatSet, isMbaSet := isMPAMAvailableByResctrlInfo()
+		klog.V(4).Infof("isMPAMAvailableByResctrlInfo result, isCatSet: %v, isMbaSet: %v", isCatSet, isMbaSet)
+		return isCatSet && isMbaSet, nil
+	}
 	isCatFlagSet, isMbaFlagSet, err := isResctrlAvailableByCpuInfo(GetCPUInfoPath())
 	if err != nil {
 		klog.Errorf("isResctrlAvailableByCpuInfo error: %v", err)
@@ -90,6 +145,10 @@ func isKernelSupportResctrl() (bool, error) {
 		// AMD CPU support resctrl by default
 		klog.V(4).Infof("isKernelSupportResctrl true, since the cpu vendor is %v, no need to check kernel command line", vendorID)
 		return true, nil
+	} else if _, ok := ARM_VENDOR_ID_MAP[vendorID]; ok {
+		// MPAM is not configured by the rdt kernel command line
+		klog.V(4).Infof("isKernelSupportResctrl true, since the cpu vendor is %v, no need to check kernel command line", vendorID)
+		return true, nil
 	}
 	isCatFlagSet, isMbaFlagSet, err := isResctrlAvailableByKernelCmd(filepath.Join(Conf.ProcRootDir, KernelCmdlineFileName))
 	if err != nil {
@@ -100,6 +159,77 @@ func isKernelSupportResctrl() (bool, error) {
 	return isCatFlagSet && isMbaFlagSet, nil
 }
 
+// IsMPAMVendor returns if the cpu vendor is an ARM vendor supporting MPAM.
+func IsMPAMVendor() bool {
+	vendorID, err := GetVendorIDByCPUInfo(GetCPUInfoPath())
+	if err != nil {
+		return false
+	}
+	_, ok := ARM_VENDOR_ID_MAP[vendorID]
+	return ok
+}
+
+// isMPAMAvailableByResctrlInfo checks if the MPAM cache portion and memory bandwidth controls are exposed
+// by the resctrl, e.g. /sys/fs/resctrl/info/L3 and /sys/fs/resctrl/info/MB.
+func isMPAMAvailableByResctrlInfo() (bool, bool) {
+	infoDir := filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir)
+	isCatSet, _ := PathExists(filepath.Join(infoDir, L3CatDir))
+	isMbaSet, _ := PathExists(filepath.Join(infoDir, ResctrlMBDir))
+	return isCatSet, isMbaSet
+}
+
+// IsResctrlMonAvailableByResctrlInfo checks if the resctrl monitors support the llc occupancy,
+// e.g. /sys/fs/resctrl/info/L3_MON/mon_features.
+func IsResctrlMonAvailableByResctrlInfo() (bool, error) {
+	content, err := os.ReadFile(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, ResctrlL3MonDir, "mon_features"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, feature := range strings.Fields(string(content)) {
+		if feature == ResctrlLLCOccupancyName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// IsResctrlL2CatAvailable checks if the l2 cache allocation is exposed by the resctrl, e.g. /sys/fs/resctrl/info/L2.
+// The L2 CAT is optional and independent of the L3 CAT, e.g. on the Atom-based platforms.
+func IsResctrlL2CatAvailable() bool {
+	isSet, _ := PathExists(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L2CatDir))
+	return isSet
+}
+
+// IsResctrlL3CDPEnabled checks if the l3 Code/Data Prioritization is enabled by the resctrl,
+// e.g. /sys/fs/resctrl/info/L3CODE and /sys/fs/resctrl/info/L3DATA.
+func IsResctrlL3CDPEnabled() bool {
+	infoDir := filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir)
+	isCodeSet, _ := PathExists(filepath.Join(infoDir, L3CodeCatDir))
+	isDataSet, _ := PathExists(filepath.Join(infoDir, L3DataCatDir))
+	return isCodeSet && isDataSet
+}
+
+// IsResctrlL2MonAvailableByResctrlInfo checks if the resctrl monitors support the l2 occupancy,
+// e.g. /sys/fs/resctrl/info/L2_MON/mon_features.
+func IsResctrlL2MonAvailableByResctrlInfo() (bool, error) {
+	content, err := os.ReadFile(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, ResctrlL2MonDir, "mon_features"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, feature := range strings.Fields(string(content)) {
+		if feature == ResctrlLLCOccupancyName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func IsVendorSupportResctrl() bool {
 	vendorID, err := GetVendorIDByCPUInfo(GetCPUInfoPath())
 	if err != nil {
@@ -136,6 +266,10 @@ func IsSupportResctrl() (bool, error) {
 func IsSupportResctrlCollector() (bool, error) {
 	var err error
 	collectorOnceFunc.Do(func() {
+		if IsMPAMVendor() {
+			isSupportResctrlCollector, err = IsResctrlMonAvailableByResctrlInfo()
+			return
+		}
 		path := GetCPUInfoPath()
 		mbm, err1 := isResctrlMBMAvailableByCpuInfo(path)
 		cqm, err2 := isResctrlCQMAvailableByCpuInfo(path)
@@ -146,13 +280,16 @@ func IsSupportResctrlCollector() (bool, error) {
 }
 
 var (
-	ResctrlRoot         = NewCommonResctrlResource("", "")
-	ResctrlSchemata     = NewCommonResctrlResource(ResctrlSchemataName, "")
-	ResctrlTasks        = NewCommonResctrlResource(ResctrlTasksName, "")
-	ResctrlL3CbmMask    = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L3CatDir))
-	ResctrlLLCOccupancy = NewCommonResctrlResource(ResctrlLLCOccupancyName, "")
-	ResctrlMBLocal      = NewCommonResctrlResource(ResctrlMBMLocalName, "")
-	ResctrlMBTotal      = NewCommonResctrlResource(ResctrlMBMTotalName, "")
+	ResctrlRoot      = NewCommonResctrlResource("", "")
+	ResctrlSchemata  = NewCommonResctrlResource(ResctrlSchemataName, "")
+	ResctrlTasks     = NewCommonResctrlResource(ResctrlTasksName, "")
+	ResctrlL3CbmMask = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L3CatDir))
+	ResctrlL2CbmMask = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L2CatDir))
+	// ResctrlL3CodeCbmMask is the l3 cbm when the CDP is enabled, which is the same as the data one.
+	ResctrlL3CodeCbmMask = NewCommonResctrlResource(ResctrlCbmMaskName, filepath.Join(RdtInfoDir, L3CodeCatDir))
+	ResctrlLLCOccupancy  = NewCommonResctrlResource(ResctrlLLCOccupancyName, "")
+	ResctrlMBLocal       = NewCommonResctrlResource(ResctrlMBMLocalName, "")
+	ResctrlMBTotal       = NewCommonResctrlResource(ResctrlMBMTotalName, "")
 )
 
 var _ Resource = &ResctrlResource{}
@@ -222,12 +359,20 @@ type ResctrlSchemataRaw struct {
 	L3    map[int]int64
 	MB    map[int]int64
 	L3Num int
+	// L2 is the l2 cat masks, whose cache ids are different from the l3 ones, e.g. one id per core cluster.
+	// It is empty unless the l2 cache ids are specified.
+	L2 map[int]int64
+	// L3Code and L3Data are the l3 cat masks of the code and the data when the CDP is enabled, which take the place
+	// of the L3 masks. They are empty unless the CDP is enabled.
+	L3Code map[int]int64
+	L3Data map[int]int64
 }
 
 func NewResctrlSchemataRaw(cacheids []int) *ResctrlSchemataRaw {
 	r := &ResctrlSchemataRaw{
 		L3:    make(map[int]int64),
 		MB:    make(map[int]int64),
+		L2:    make(map[int]int64),
 		L3Num: 1,
 	}
 	for _, id := range cacheids {
@@ -255,6 +400,105 @@ func (r *ResctrlSchemataRaw) WithL3Mask(mask string) *ResctrlSchemataRaw {
 	return r
 }
 
+// WithL3CDPMask sets the code and data masks of the l3 cache ids for the CDP mode, and the L3 masks are cleared.
+// e.g. codeMask=`ff`, dataMask=`f0`, cache ids [0, 1] -> `L3CODE:0=ff;1=ff\nL3DATA:0=f0;1=f0\n`
+func (r *ResctrlSchemataRaw) WithL3CDPMask(codeMask, dataMask string) *ResctrlSchemataRaw {
+	ids := r.CacheIds()
+	r.L3Code, r.L3Data = make(map[int]int64, len(ids)), make(map[int]int64, len(ids))
+	for _, v := range []struct {
+		mask  string
+		masks map[int]int64
+	}{
+		{mask: codeMask, masks: r.L3Code},
+		{mask: dataMask, masks: r.L3Data},
+	} {
+		// l3 mask MUST be a valid hex
+		maskValue, err := strconv.ParseInt(strings.TrimSpace(v.mask), 16, 64)
+		if err != nil {
+			klog.V(5).Infof("failed to parse l3 cdp mask %s, err: %v", v.mask, err)
+		}
+		for _, id := range ids {
+			v.masks[id] = maskValue
+		}
+	}
+	r.L3 = map[int]int64{}
+	return r
+}
+
+// WithL3DomainMasks overrides the l3 masks of the specified cache ids, which allows different masks on the l3 cache
+// domains, e.g. the different sockets. In the CDP mode, both the code and data masks of the cache ids are overridden.
+// The unknown cache ids are ignored.
+// e.g. mask=`ff`, masks={1: `f`}, cache ids [0, 1] -> `L3:0=ff;1=f;\n`
+func (r *ResctrlSchemataRaw) WithL3DomainMasks(masks map[int]string) *ResctrlSchemataRaw {
+	targets := []map[int]int64{r.L3}
+	if r.IsL3CDP() {
+		targets = []map[int]int64{r.L3Code, r.L3Data}
+	}
+	for id, mask := range masks {
+		// l3 mask MUST be a valid hex
+		maskValue, err := strconv.ParseInt(strings.TrimSpace(mask), 16, 64)
+		if err != nil {
+			klog.V(5).Infof("failed to parse l3 mask %s of cache id %d, err: %v", mask, id, err)
+		}
+		for _, target := range targets {
+			if _, ok := target[id]; ok {
+				target[id] = maskValue
+			}
+		}
+	}
+	return r
+}
+
+// WithL3DomainCDPMasks overrides the code and data masks of the specified cache ids in the CDP mode respectively.
+// The unknown cache ids are ignored.
+// e.g. codeMasks={1: `f`}, dataMasks={1: `f0`}, cache ids [0, 1] -> `L3CODE:0=ff;1=f;\nL3DATA:0=ff;1=f0;\n`
+func (r *ResctrlSchemataRaw) WithL3DomainCDPMasks(codeMasks, dataMasks map[int]string) *ResctrlSchemataRaw {
+	for _, v := range []struct {
+		masks  map[int]string
+		target map[int]int64
+	}{
+		{masks: codeMasks, target: r.L3Code},
+		{masks: dataMasks, target: r.L3Data},
+	} {
+		for id, mask := range v.masks {
+			// l3 mask MUST be a valid hex
+			maskValue, err := strconv.ParseInt(strings.TrimSpace(mask), 16, 64)
+			if err != nil {
+				klog.V(5).Infof("failed to parse l3 cdp mask %s of cache id %d, err: %v", mask, id, err)
+			}
+			if _, ok := v.target[id]; ok {
+				v.target[id] = maskValue
+			}
+		}
+	}
+	return r
+}
+
+// IsL3CDP returns if the l3 masks are in the CDP mode, i.e. the code and data masks are specified.
+func (r *ResctrlSchemataRaw) IsL3CDP() bool {
+	return len(r.L3Code) > 0 || len(r.L3Data) > 0
+}
+
+// WithL2CacheIds sets the l2 cache ids of the schemata, e.g. [0, 1, 2, 3] for `L2:0=ff;1=ff;2=ff;3=ff`.
+func (r *ResctrlSchemataRaw) WithL2CacheIds(l2CacheIds []int) *ResctrlSchemataRaw {
+	for _, id := range l2CacheIds {
+		r.L2[id] = 0
+	}
+	return r
+}
+
+func (r *ResctrlSchemataRaw) WithL2Mask(mask string) *ResctrlSchemataRaw {
+	// l2 mask MUST be a valid hex
+	maskValue, err := strconv.ParseInt(strings.TrimSpace(mask), 16, 64)
+	if err != nil {
+		klog.V(5).Infof("failed to parse l2 mask %s, err: %v", mask, err)
+	}
+	for id := range r.L2 {
+		r.L2[id] = maskValue
+	}
+	return r
+}
+
 func (r *ResctrlSchemataRaw) WithMB(valueOrPercent string) *ResctrlSchemataRaw {
 	// mba valueOrPercent MUST be a valid integer
 	// for intel: "MB:0=100;1=100"
@@ -269,12 +513,43 @@ func (r *ResctrlSchemataRaw) WithMB(valueOrPercent string) *ResctrlSchemataRaw {
 	return r
 }
 
+// WithDomainMB overrides the mba values of the specified cache ids, which allows different memory bandwidth limits on
+// the l3 cache domains. The unknown cache ids are ignored.
+func (r *ResctrlSchemataRaw) WithDomainMB(values map[int]string) *ResctrlSchemataRaw {
+	for id, valueOrPercent := range values {
+		if _, ok := r.MB[id]; !ok {
+			continue
+		}
+		// mba valueOrPercent MUST be a valid integer
+		percentValue, err := strconv.ParseInt(strings.TrimSpace(valueOrPercent), 10, 64)
+		if err != nil {
+			klog.V(5).Infof("failed to parse mba %s of cache id %d, err: %v", valueOrPercent, id, err)
+		}
+		r.MB[id] = percentValue
+	}
+	return r
+}
+
 func (r *ResctrlSchemataRaw) DeepCopy() *ResctrlSchemataRaw {
-	n := NewResctrlSchemataRaw(r.CacheIds()).WithL3Num(r.L3Num)
+	n := NewResctrlSchemataRaw(nil).WithL3Num(r.L3Num)
 	for id := range r.L3 {
 		n.L3[id] = r.L3[id]
+	}
+	for id := range r.MB {
 		n.MB[id] = r.MB[id]
 	}
+	for id := range r.L2 {
+		n.L2[id] = r.L2[id]
+	}
+	if r.IsL3CDP() {
+		n.L3Code, n.L3Data = make(map[int]int64, len(r.L3Code)), make(map[int]int64, len(r.L3Data))
+		for id := range r.L3Code {
+			n.L3Code[id] = r.L3Code[id]
+		}
+		for id := range r.L3Data {
+			n.L3Data[id] = r.L3Data[id]
+		}
+	}
 	return n
 }
 
@@ -283,9 +558,15 @@ func (r *ResctrlSchemataRaw) Prefix() string {
 	if len(r.L3) > 0 {
 		prefix += L3SchemataPrefix + ":"
 	}
+	if r.IsL3CDP() {
+		prefix += L3CodeSchemataPrefix + ":" + L3DataSchemataPrefix + ":"
+	}
 	if len(r.MB) > 0 {
 		prefix += MbSchemataPrefix + ":"
 	}
+	if len(r.L2) > 0 {
+		prefix += L2SchemataPrefix + ":"
+	}
 	return prefix
 }
 
@@ -296,13 +577,31 @@ func (r *ResctrlSchemataRaw) L3Number() int {
 func (r *ResctrlSchemataRaw) CacheIds() []int {
 	// TODO: consider situation that L3 number and the MB number are the same.
 	ids := []int{}
-	for id := range r.L3 {
+	masks := r.L3
+	if len(masks) <= 0 && r.IsL3CDP() { // the l3 masks are replaced with the code and data masks in the CDP mode
+		masks = r.L3Code
+	}
+	for id := range masks {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// L2CacheIds returns the sorted l2 cache ids.
+func (r *ResctrlSchemataRaw) L2CacheIds() []int {
+	ids := []int{}
+	for id := range r.L2 {
 		ids = append(ids, id)
 	}
+	sort.Ints(ids)
 	return ids
 }
 
+// L3String returns the l3 schemata lines. It returns the code and data lines instead in the CDP mode.
 func (r *ResctrlSchemataRaw) L3String() string {
+	if r.IsL3CDP() {
+		return formatSchemataLine(L3CodeSchemataPrefix, r.L3Code) + formatSchemataLine(L3DataSchemataPrefix, r.L3Data)
+	}
 	if len(r.L3) <= 0 {
 		return ""
 	}
@@ -334,11 +633,44 @@ func (r *ResctrlSchemataRaw) MBString() string {
 	return schemata
 }
 
+func (r *ResctrlSchemataRaw) L2String() string {
+	if len(r.L2) <= 0 {
+		return ""
+	}
+	schemata := L2SchemataPrefix + ":"
+	// the last ';' will be auto ignored
+	for _, id := range r.L2CacheIds() {
+		schemata = schemata + strconv.Itoa(id) + "=" + strconv.FormatInt(r.L2[id], 16) + ";"
+	}
+	// the trailing '\n' is necessary to append
+	schemata += "\n"
+	return schemata
+}
+
+// formatSchemataLine formats the hex masks into a schemata line, e.g. `L3CODE:0=ff;1=ff;\n`.
+func formatSchemataLine(prefix string, masks map[int]int64) string {
+	if len(masks) <= 0 {
+		return ""
+	}
+	ids := make([]int, 0, len(masks))
+	for id := range masks {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	schemata := prefix + ":"
+	// the last ';' will be auto ignored
+	for _, id := range ids {
+		schemata = schemata + strconv.Itoa(id) + "=" + strconv.FormatInt(masks[id], 16) + ";"
+	}
+	// the trailing '\n' is necessary to append
+	return schemata + "\n"
+}
+
 func (r *ResctrlSchemataRaw) Equal(a *ResctrlSchemataRaw) (bool, string) {
 	if r.L3Num != a.L3Num {
 		return false, "l3 number not equal"
 	}
-	if a.L3 != nil {
+	if len(a.L3) > 0 {
 		if len(r.L3) != len(a.L3) || r.L3 == nil {
 			return false, "the number of l3 masks not equal"
 		}
@@ -348,7 +680,7 @@ func (r *ResctrlSchemataRaw) Equal(a *ResctrlSchemataRaw) (bool, string) {
 			}
 		}
 	}
-	if a.MB != nil {
+	if len(a.MB) > 0 {
 		if len(r.MB) != len(a.MB) || r.MB == nil {
 			return false, "the number of mba percent not equal"
 		}
@@ -358,6 +690,37 @@ func (r *ResctrlSchemataRaw) Equal(a *ResctrlSchemataRaw) (bool, string) {
 			}
 		}
 	}
+	// the code and data masks are compared only if specified in the CDP mode
+	if a.IsL3CDP() {
+		for _, v := range []struct {
+			name   string
+			masks  map[int]int64
+			aMasks map[int]int64
+		}{
+			{name: "l3 code", masks: r.L3Code, aMasks: a.L3Code},
+			{name: "l3 data", masks: r.L3Data, aMasks: a.L3Data},
+		} {
+			if len(v.masks) != len(v.aMasks) {
+				return false, fmt.Sprintf("the number of %s masks not equal", v.name)
+			}
+			for id, mask := range v.aMasks {
+				if m, ok := v.masks[id]; !ok || m != mask {
+					return false, fmt.Sprintf("the value of %s mask not equal", v.name)
+				}
+			}
+		}
+	}
+	// the l2 masks are compared only if specified since the l2 cat is optional
+	if len(a.L2) > 0 {
+		if len(r.L2) != len(a.L2) {
+			return false, "the number of l2 masks not equal"
+		}
+		for id, mask := range a.L2 {
+			if v, ok := r.L2[id]; !ok || v != mask {
+				return false, "the value of l2 mask not equal"
+			}
+		}
+	}
 	return true, ""
 }
 
@@ -375,6 +738,9 @@ func (r *ResctrlSchemataRaw) ValidateL3() (bool, string) {
 	if r.L3Num <= 0 {
 		return false, "L3 number is zero"
 	}
+	if r.IsL3CDP() {
+		return r.validateL3CDP()
+	}
 	if len(r.L3) <= 0 {
 		return false, "no L3 CAT info"
 	}
@@ -389,6 +755,59 @@ func (r *ResctrlSchemataRaw) ValidateL3() (bool, string) {
 	return true, ""
 }
 
+// validateL3CDP checks if each l3 domain has both the valid code and data masks.
+func (r *ResctrlSchemataRaw) validateL3CDP() (bool, string) {
+	if r.L3Num != len(r.L3Code) || r.L3Num != len(r.L3Data) {
+		return false, "unmatched L3 number and CDP infos"
+	}
+	for id, value := range r.L3Code {
+		if value <= 0 {
+			return false, "wrong value of L3 code mask"
+		}
+		if dataValue, ok := r.L3Data[id]; !ok || dataValue <= 0 {
+			return false, "wrong value of L3 data mask"
+		}
+	}
+	return true, ""
+}
+
+// ValidateL3MaskLength checks if the l3 masks of each domain do not exceed the cbm, i.e. each mask only owns the
+// cache ways of the cbm. Both the code and data masks are checked in the CDP mode.
+func (r *ResctrlSchemataRaw) ValidateL3MaskLength(cbm uint) (bool, string) {
+	if valid, msg := r.ValidateL3(); !valid {
+		return false, msg
+	}
+	type namedMasks struct {
+		name  string
+		masks map[int]int64
+	}
+	maskList := []namedMasks{{name: "L3", masks: r.L3}}
+	if r.IsL3CDP() {
+		maskList = []namedMasks{{name: "L3 code", masks: r.L3Code}, {name: "L3 data", masks: r.L3Data}}
+	}
+	cbmLength := bits.Len(cbm)
+	for _, v := range maskList {
+		for id, mask := range v.masks {
+			if bits.Len64(uint64(mask)) > cbmLength || uint64(mask)&^uint64(cbm) != 0 {
+				return false, fmt.Sprintf("%s mask %x of domain %d exceeds the cbm %x", v.name, mask, id, cbm)
+			}
+		}
+	}
+	return true, ""
+}
+
+func (r *ResctrlSchemataRaw) ValidateL2() (bool, string) {
+	if len(r.L2) <= 0 {
+		return false, "no L2 CAT info"
+	}
+	for _, value := range r.L2 {
+		if value <= 0 {
+			return false, "wrong value of L2 mask"
+		}
+	}
+	return true, ""
+}
+
 func (r *ResctrlSchemataRaw) ValidateMB() (bool, string) {
 	if r.L3Num <= 0 {
 		return false, "L3 number is zero"
@@ -404,28 +823,54 @@ func (r *ResctrlSchemataRaw) ValidateMB() (bool, string) {
 	return true, ""
 }
 
-// ParseResctrlSchemata parses the resctrl schemata of given cgroup, and returns the l3_cat masks and mba masks.
-// Set l3Num=-1 to use the read L3 number from the schemata.
+// ParseResctrlSchemata parses the resctrl schemata of given cgroup, and returns the l3_cat masks, mba masks and
+// l2_cat masks. Set l3Num=-1 to use the read L3 number from the schemata. The l2 masks are not counted by the l3Num.
 // @content `L3:0=fff;1=fff\nMB:0=100;1=100\n` (may have additional lines (e.g. ARM MPAM))
 // @l3Num 2
 // @return {L3: {0: "fff", 1: "fff"}, MB: {0: "100", 1: "100"}}, nil
 func (r *ResctrlSchemataRaw) ParseResctrlSchemata(content string, l3Num int) error {
 	schemataMap := ParseResctrlSchemataMap(content)
+	if r.L2 == nil {
+		r.L2 = make(map[int]int64)
+	}
+	if schemataMap[L3CodeSchemataPrefix] != nil || schemataMap[L3DataSchemataPrefix] != nil {
+		r.L3Code, r.L3Data = make(map[int]int64), make(map[int]int64)
+	}
 
 	for _, t := range []struct {
-		prefix string
-		base   int
-		v      *map[int]int64
+		prefix  string
+		base    int
+		v       *map[int]int64
+		isL3Num bool
 	}{
 		{
-			prefix: L3SchemataPrefix,
-			base:   16,
-			v:      &r.L3,
+			prefix:  L3SchemataPrefix,
+			base:    16,
+			v:       &r.L3,
+			isL3Num: true,
+		},
+		{
+			prefix:  MbSchemataPrefix,
+			base:    10,
+			v:       &r.MB,
+			isL3Num: true,
+		},
+		{
+			prefix:  L3CodeSchemataPrefix,
+			base:    16,
+			v:       &r.L3Code,
+			isL3Num: true,
+		},
+		{
+			prefix:  L3DataSchemataPrefix,
+			base:    16,
+			v:       &r.L3Data,
+			isL3Num: true,
 		},
 		{
-			prefix: MbSchemataPrefix,
-			base:   10,
-			v:      &r.MB,
+			prefix: L2SchemataPrefix,
+			base:   16,
+			v:      &r.L2,
 		},
 	} {
 		maskMap := schemataMap[t.prefix]
@@ -433,10 +878,10 @@ func (r *ResctrlSchemataRaw) ParseResctrlSchemata(content string, l3Num int) err
 			klog.V(5).Infof("read resctrl schemata of %s aborted, mask not found", t.prefix)
 			continue
 		}
-		if l3Num == -1 {
+		if l3Num == -1 && t.isL3Num {
 			l3Num = len(maskMap)
 		}
-		if len(maskMap) != l3Num {
+		if t.isL3Num && len(maskMap) != l3Num {
 			return fmt.Errorf("read resctrl schemata failed, %s masks has invalid count %v, l3Num %v",
 				t.prefix, len(maskMap), l3Num)
 		}
@@ -464,11 +909,19 @@ func GetResctrlGroupRootDirPath(groupPath string) string {
 	return filepath.Join(Conf.SysFSRootDir, ResctrlDir, groupPath)
 }
 
-// @return /sys/fs/resctrl/info/L3/cbm_mask
+// @return /sys/fs/resctrl/info/L3/cbm_mask, or /sys/fs/resctrl/info/L3CODE/cbm_mask if the CDP is enabled
 func GetResctrlL3CbmFilePath() string {
+	if IsResctrlL3CDPEnabled() {
+		return ResctrlL3CodeCbmMask.Path("")
+	}
 	return ResctrlL3CbmMask.Path("")
 }
 
+// @return /sys/fs/resctrl/info/L2/cbm_mask
+func GetResctrlL2CbmFilePath() string {
+	return ResctrlL2CbmMask.Path("")
+}
+
 // @groupPath BE
 // @return /sys/fs/resctrl/BE/schemata
 func GetResctrlSchemataFilePath(groupPath string) string {
@@ -487,6 +940,12 @@ func GetResctrlMonDataPath(parentDir string) string {
 	return filepath.Join(Conf.SysFSRootDir, ResctrlDir, parentDir, ResctrlMonData)
 }
 
+// @ctrlGroup BE, @monGroup pod1
+// @return BE/mon_groups/pod1
+func GetResctrlMonGroupPath(ctrlGroup, monGroup string) string {
+	return filepath.Join(ctrlGroup, ResctrlMonGroupsDir, monGroup)
+}
+
 func ReadResctrlSchemataRaw(schemataFile string, l3Num int) (*ResctrlSchemataRaw, error) {
 	content, err := os.ReadFile(schemataFile)
 	if err != nil {
@@ -499,7 +958,7 @@ func ReadResctrlSchemataRaw(schemataFile string, l3Num int) (*ResctrlSchemataRaw
 		return nil, fmt.Errorf("failed to parse l3 schemata, content %s, err: %v", string(content), err)
 	}
 	if l3Num == -1 {
-		schemataRaw.WithL3Num(len(schemataRaw.L3))
+		schemataRaw.WithL3Num(len(schemataRaw.CacheIds()))
 	}
 
 	return schemataRaw, nil
@@ -515,6 +974,16 @@ func GetCacheIds() ([]int, error) {
 	return r.CacheIds(), nil
 }
 
+// GetL2CacheIds get l2 cache ids from schemata file.
+// e.g. schemata=`L3:0=fff\nMB:0=100\nL2:0=ff;1=ff;2=ff;3=ff\n` -> [0, 1, 2, 3]
+func GetL2CacheIds() ([]int, error) {
+	r, err := ReadResctrlSchemataRaw(filepath.Join(Conf.SysFSRootDir, ResctrlDir, ResctrlSchemataName), -1)
+	if err != nil {
+		return nil, err
+	}
+	return r.L2CacheIds(), nil
+}
+
 // ParseResctrlSchemataMap parses the content of resctrl schemata.
 // e.g. schemata=`L3:0=fff;1=fff\nMB:0=100;1=100\n` -> `{"L3": {0: "fff", 1: "fff"}, "MB": {0: "100", 1: "100"}}`
 func ParseResctrlSchemataMap(content string) map[string]map[int]string {
@@ -563,6 +1032,30 @@ func ReadCatL3CbmString() (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// ReadCatL2CbmString reads and returns the value of cat l2 cbm_mask
+func ReadCatL2CbmString() (string, error) {
+	cbmFile := GetResctrlL2CbmFilePath()
+	out, err := os.ReadFile(cbmFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read l2 cbm, path %s, err: %v", cbmFile, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ReadResctrlInfoInt reads the integer value of the resctrl info file, e.g. /sys/fs/resctrl/info/L3/num_closids.
+func ReadResctrlInfoInt(infoSubDir string, fileName string) (int64, error) {
+	infoFile := filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, infoSubDir, fileName)
+	out, err := os.ReadFile(infoFile)
+	if err != nil {
+		return 0, err
+	}
+	v, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resctrl info, path %s, err: %v", infoFile, err)
+	}
+	return v, nil
+}
+
 // ReadResctrlTasksMap reads and returns the map of given resctrl group's task ids
 func ReadResctrlTasksMap(groupPath string) (map[int32]struct{}, error) {
 	tasksPath := GetResctrlTasksFilePath(groupPath)
@@ -625,7 +1118,7 @@ func InitCatGroupIfNotExist(group string) (bool, error) {
 	}
 	err = os.Mkdir(path, 0755)
 	if err != nil {
-		return false, fmt.Errorf("create dir %v failed for group %s, err: %v", path, group, err)
+		return false, fmt.Errorf("create dir %v failed for group %s, err: %w", path, group, err)
 	}
 	return true, nil
 }
@@ -676,8 +1169,11 @@ func CalculateCatL3MaskValue(cbm uint, startPercent, endPercent int64) (string,
 // GetVendorIDByCPUInfo returns vendor_id like AuthenticAMD from cpu info, e.g.
 // vendor_id       : AuthenticAMD
 // vendor_id       : GenuineIntel
+// For the ARM cpus without the vendor_id, it returns the vendor of the CPU implementer, e.g.
+// CPU implementer : 0x48
 func GetVendorIDByCPUInfo(path string) (string, error) {
 	vendorID := "unknown"
+	implementerVendorID := ""
 	f, err := os.Open(path)
 	if err != nil {
 		return vendorID, err
@@ -696,11 +1192,19 @@ func GetVendorIDByCPUInfo(path string) (string, error) {
 		if strings.Contains(line, "vendor_id") {
 			attrs := strings.Split(line, ":")
 			if len(attrs) >= 2 {
-				vendorID = strings.TrimSpace(attrs[1])
-				break
+				return strings.TrimSpace(attrs[1]), nil
+			}
+		}
+		if implementerVendorID == "" && strings.HasPrefix(line, "CPU implementer") {
+			attrs := strings.Split(line, ":")
+			if len(attrs) >= 2 {
+				implementerVendorID = armCPUImplementers[strings.ToLower(strings.TrimSpace(attrs[1]))]
 			}
 		}
 	}
+	if implementerVendorID != "" {
+		return implementerVendorID, nil
+	}
 	return vendorID, nil
 }
 
@@ -303,6 +303,14 @@ func TestGetVendorIDByCPUInfo(t *testing.T) {
 			want:    "GenuineIntel",
 			wantErr: false,
 		},
+		{
+			name: "test arm implementer",
+			args: args{
+				content: "processor       : 0\nBogoMIPS        : 200.00\nCPU implementer : 0x48\nCPU architecture: 8\n",
+			},
+			want:    HISILICON_VENDOR_ID,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -591,3 +599,172 @@ func TestIsVendorSupportResctrl(t *testing.T) {
 		})
 	}
 }
+
+func TestIsSupportResctrlCollectorForMPAM(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+	helper.WriteProcSubFileContents("cpuinfo", "processor       : 0\nCPU implementer : 0x41\n")
+	assert.True(t, IsMPAMVendor())
+	helper.WriteProcSubFileContents("cpuinfo", "processor       : 0\nCPU implementer : 0xc0\n")
+	assert.True(t, IsMPAMVendor())
+
+	got, err := IsResctrlMonAvailableByResctrlInfo()
+	assert.NoError(t, err)
+	assert.False(t, got)
+	isCatSet, isMbaSet := isMPAMAvailableByResctrlInfo()
+	assert.False(t, isCatSet)
+	assert.False(t, isMbaSet)
+
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, ResctrlL3MonDir, "mon_features"), "llc_occupancy\nmbm_total_bytes\n")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3CatDir, ResctrlCbmMaskName), "ffff")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, ResctrlMBDir, "min_bandwidth"), "1")
+	got, err = IsResctrlMonAvailableByResctrlInfo()
+	assert.NoError(t, err)
+	assert.True(t, got)
+	isCatSet, isMbaSet = isMPAMAvailableByResctrlInfo()
+	assert.True(t, isCatSet)
+	assert.True(t, isMbaSet)
+}
+
+func TestResctrlSchemataRawL2(t *testing.T) {
+	r := NewResctrlSchemataRaw([]int{0}).WithL3Num(1).WithL3Mask("fff")
+	assert.Equal(t, "", r.L2String())
+	valid, _ := r.ValidateL2()
+	assert.False(t, valid)
+
+	r.WithL2CacheIds([]int{2, 0, 1, 3}).WithL2Mask("f")
+	assert.Equal(t, "L2:0=f;1=f;2=f;3=f;\n", r.L2String())
+	assert.Equal(t, []int{0, 1, 2, 3}, r.L2CacheIds())
+	assert.Equal(t, "L3:MB:L2:", r.Prefix())
+	valid, _ = r.ValidateL2()
+	assert.True(t, valid)
+	assert.Equal(t, r.L2, r.DeepCopy().L2)
+
+	parsed := NewResctrlSchemataRaw([]int{})
+	err := parsed.ParseResctrlSchemata("L3:0=fff\nMB:0=100\nL2:0=ff;1=f;2=ff;3=ff\n", -1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{0: 0xfff}, parsed.L3)
+	assert.Equal(t, map[int]int64{0: 0xff, 1: 0xf, 2: 0xff, 3: 0xff}, parsed.L2)
+	parsed.WithL3Num(1)
+	isEqual, _ := parsed.Equal(NewResctrlSchemataRaw([]int{0}).WithL3Num(1).WithL3Mask("fff").WithMB("100"))
+	assert.True(t, isEqual, "l2 not specified")
+	isEqual, msg := parsed.Equal(r.DeepCopy().WithMB("100"))
+	assert.False(t, isEqual)
+	assert.Equal(t, "the value of l2 mask not equal", msg)
+}
+
+func TestResctrlL2Available(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	assert.False(t, IsResctrlL2CatAvailable())
+	got, err := IsResctrlL2MonAvailableByResctrlInfo()
+	assert.NoError(t, err)
+	assert.False(t, got)
+	_, err = ReadCatL2CbmString()
+	assert.Error(t, err)
+
+	helper.WriteFileContents(GetResctrlL2CbmFilePath(), "ff\n")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, ResctrlL2MonDir, "mon_features"), "llc_occupancy\n")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), ResctrlSchemataName), "L3:0=fff;1=fff\nL2:0=ff;1=ff\n")
+	assert.True(t, IsResctrlL2CatAvailable())
+	got, err = IsResctrlL2MonAvailableByResctrlInfo()
+	assert.NoError(t, err)
+	assert.True(t, got)
+	cbm, err := ReadCatL2CbmString()
+	assert.NoError(t, err)
+	assert.Equal(t, "ff", cbm)
+	ids, err := GetL2CacheIds()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1}, ids)
+}
+
+func TestResctrlSchemataRawL3CDP(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	assert.False(t, IsResctrlL3CDPEnabled())
+	assert.Equal(t, ResctrlL3CbmMask.Path(""), GetResctrlL3CbmFilePath())
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3CodeCatDir, ResctrlCbmMaskName), "7ff\n")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), RdtInfoDir, L3DataCatDir, ResctrlCbmMaskName), "7ff\n")
+	helper.WriteFileContents(filepath.Join(GetResctrlSubsystemDirPath(), ResctrlSchemataName),
+		"L3CODE:0=7ff;1=7ff\nL3DATA:0=7ff;1=7ff\nMB:0=100;1=100\n")
+	assert.True(t, IsResctrlL3CDPEnabled())
+	assert.Equal(t, ResctrlL3CodeCbmMask.Path(""), GetResctrlL3CbmFilePath())
+	cbm, err := ReadCatL3CbmString()
+	assert.NoError(t, err)
+	assert.Equal(t, "7ff", cbm)
+
+	// parse the cdp schemata
+	ids, err := GetCacheIds()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{0, 1}, ids)
+	assert.NoError(t, CheckResctrlSchemataValid())
+	parsed, err := ReadResctrlSchemataRaw(filepath.Join(GetResctrlSubsystemDirPath(), ResctrlSchemataName), -1)
+	assert.NoError(t, err)
+	assert.True(t, parsed.IsL3CDP())
+	assert.Equal(t, 2, parsed.L3Number())
+	assert.Equal(t, map[int]int64{0: 0x7ff, 1: 0x7ff}, parsed.L3Code)
+	assert.Equal(t, map[int]int64{0: 0x7ff, 1: 0x7ff}, parsed.L3Data)
+	assert.Equal(t, "L3CODE:0=7ff;1=7ff;\nL3DATA:0=7ff;1=7ff;\n", parsed.L3String())
+
+	// generate the cdp schemata
+	r := NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3CDPMask("f", "f0")
+	assert.True(t, r.IsL3CDP())
+	assert.Equal(t, map[int]int64{}, r.L3)
+	assert.Equal(t, "L3CODE:0=f;1=f;\nL3DATA:0=f0;1=f0;\n", r.L3String())
+	assert.Equal(t, "L3CODE:L3DATA:MB:", r.Prefix())
+	valid, msg := r.ValidateL3()
+	assert.True(t, valid, msg)
+	valid, msg = r.ValidateL3MaskLength(0x7ff)
+	assert.True(t, valid, msg)
+	valid, _ = r.ValidateL3MaskLength(0xf)
+	assert.False(t, valid)
+	rCopy := r.DeepCopy()
+	assert.Equal(t, r.L3Code, rCopy.L3Code)
+	assert.Equal(t, r.L3Data, rCopy.L3Data)
+	assert.Equal(t, r.L3String(), rCopy.L3String())
+
+	isEqual, msg := parsed.Equal(r.DeepCopy().WithMB("100"))
+	assert.False(t, isEqual)
+	assert.Equal(t, "the value of l3 code mask not equal", msg)
+	isEqual, msg = parsed.Equal(NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3CDPMask("7ff", "7ff").WithMB("100"))
+	assert.True(t, isEqual, msg)
+
+	// each domain should have both the code and data masks
+	invalid := NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3CDPMask("f", "f0")
+	delete(invalid.L3Data, 1)
+	valid, _ = invalid.ValidateL3()
+	assert.False(t, valid)
+	invalid.L3Data[2] = 0xf0
+	valid, _ = invalid.ValidateL3()
+	assert.False(t, valid)
+
+	// the non-cdp masks are checked as well
+	r = NewResctrlSchemataRaw([]int{0}).WithL3Num(1).WithL3Mask("fff")
+	assert.False(t, r.IsL3CDP())
+	valid, _ = r.ValidateL3MaskLength(0x7ff)
+	assert.False(t, valid)
+	valid, msg = r.ValidateL3MaskLength(0xfff)
+	assert.True(t, valid, msg)
+}
+
+func TestResctrlSchemataRawWithDomains(t *testing.T) {
+	r := NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3Mask("ff").WithMB("100").
+		WithL3DomainMasks(map[int]string{1: "f", 2: "f0"}).WithDomainMB(map[int]string{0: "50", 3: "10"})
+	assert.Equal(t, "L3:0=ff;1=f;\n", r.L3String())
+	assert.Equal(t, "MB:0=50;1=100;\n", r.MBString())
+	valid, msg := r.Validate()
+	assert.True(t, valid, msg)
+
+	// both the code and data masks are overridden in the cdp mode
+	r = NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3CDPMask("ff", "ff").
+		WithL3DomainMasks(map[int]string{0: "3"})
+	assert.Equal(t, "L3CODE:0=3;1=ff;\nL3DATA:0=3;1=ff;\n", r.L3String())
+	assert.Equal(t, map[int]int64{}, r.L3)
+
+	// the code and data masks are overridden respectively
+	r = NewResctrlSchemataRaw([]int{0, 1}).WithL3Num(2).WithL3CDPMask("ff", "ff").
+		WithL3DomainCDPMasks(map[int]string{0: "3", 2: "f"}, map[int]string{1: "30"})
+	assert.Equal(t, "L3CODE:0=3;1=ff;\nL3DATA:0=ff;1=30;\n", r.L3String())
+}
@@ -35,13 +35,18 @@ const (
 	KernelCmdlineFileName = "cmdline"
 	HugepageDir           = "hugepages"
 	nrPath                = "nr_hugepages"
+	freePath              = "free_hugepages"
+	compactPath           = "compact"
 
 	KernelSchedGroupIdentityEnable = "kernel/sched_group_identity_enabled"
 	KernelSchedCore                = "kernel/sched_core"
+	// KernelSchedCFSBandwidthBurstEnable is the switch of the cfs bandwidth burst on some kernels, e.g. Anolis OS.
+	KernelSchedCFSBandwidthBurstEnable = "kernel/sched_cfs_bw_burst_enabled"
 
 	SysNUMASubDir   = "bus/node/devices"
 	SysPCIDeviceDir = "bus/pci/devices"
 
+	SysCPUSubDir                 = "devices/system/cpu"
 	SysCPUSMTActiveSubPath       = "devices/system/cpu/smt/active"
 	SysIntelPStateNoTurboSubPath = "devices/system/cpu/intel_pstate/no_turbo"
 )
@@ -96,6 +101,16 @@ func GetNUMAHugepagesNrPath(numaNodeSubDir string, page string) string {
 	return filepath.Join(Conf.SysRootDir, SysNUMASubDir, numaNodeSubDir, HugepageDir, page, nrPath)
 }
 
+func GetNUMAHugepagesFreePath(numaNodeSubDir string, page string) string {
+	return filepath.Join(Conf.SysRootDir, SysNUMASubDir, numaNodeSubDir, HugepageDir, page, freePath)
+}
+
+// GetNUMACompactPath returns the path to trigger the memory compaction of the NUMA node,
+// e.g. /sys/bus/node/devices/node0/compact
+func GetNUMACompactPath(numaNodeSubDir string) string {
+	return filepath.Join(Conf.SysRootDir, SysNUMASubDir, numaNodeSubDir, compactPath)
+}
+
 func GetCPUInfoPath() string {
 	return filepath.Join(Conf.ProcRootDir, ProcCPUInfoName)
 }
@@ -144,6 +159,44 @@ func IsGroupIdentitySysctlSupported() bool {
 	return FileExists(GetProcSysFilePath(KernelSchedGroupIdentityEnable))
 }
 
+// IsCPUBurstSupported checks if the kernel supports the cpu burst of the cfs bandwidth control, which is the
+// cpu.cfs_burst_us on cgroup v1 or the cpu.max.burst on cgroup v2 (since linux 5.14) of the kubepods cgroup. The burst
+// is also unsupported if it is disabled by the sysctl kernel.sched_cfs_bw_burst_enabled.
+func IsCPUBurstSupported() (bool, string) {
+	r, err := GetCgroupResource(CPUBurstName)
+	if err != nil {
+		return false, fmt.Sprintf("cannot get cpu burst resource, err: %v", err)
+	}
+	if supported, msg := r.IsSupported(CgroupPathFormatter.ParentDir); !supported {
+		return false, fmt.Sprintf("%s is unsupported, reason: %s", r.Path(CgroupPathFormatter.ParentDir), msg)
+	}
+	if !FileExists(GetProcSysFilePath(KernelSchedCFSBandwidthBurstEnable)) {
+		return true, ""
+	}
+	// 0: disabled; 1: enabled
+	cur, err := NewProcSysctl().GetSysctl(KernelSchedCFSBandwidthBurstEnable)
+	if err != nil {
+		return false, fmt.Sprintf("cannot get sysctl cfs bandwidth burst, err: %v", err)
+	}
+	if cur != 1 {
+		return false, "disabled by sysctl " + KernelSchedCFSBandwidthBurstEnable
+	}
+	return true, ""
+}
+
+// IsMemoryNumaBalancingSupported checks if the kernel supports the cgroup-level numa balancing, which is the
+// memory.numa_balancing of the kubepods cgroup only provided by the Anolis OS kernel.
+func IsMemoryNumaBalancingSupported() (bool, string) {
+	r, err := GetCgroupResource(MemoryNumaBalancingName)
+	if err != nil {
+		return false, fmt.Sprintf("cannot get memory numa balancing resource, err: %v", err)
+	}
+	if supported, msg := r.IsSupported(CgroupPathFormatter.ParentDir); !supported {
+		return false, fmt.Sprintf("%s is unsupported, reason: %s", r.Path(CgroupPathFormatter.ParentDir), msg)
+	}
+	return true, ""
+}
+
 func GetSchedGroupIdentity() (bool, error) {
 	s := NewProcSysctl()
 	// 0: disabled; 1: enabled
@@ -104,3 +104,36 @@ func TestSetSchedCore(t *testing.T) {
 		assert.Equal(t, got, testContent)
 	})
 }
+
+func TestIsCPUBurstSupported(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	// cpu.cfs_burst_us not exist
+	supported, msg := IsCPUBurstSupported()
+	assert.False(t, supported)
+	assert.NotEmpty(t, msg)
+
+	helper.WriteCgroupFileContents(CgroupPathFormatter.ParentDir, CPUBurst, "0")
+	supported, _ = IsCPUBurstSupported()
+	assert.True(t, supported)
+
+	// disabled by sysctl
+	testProcSysFilepath := filepath.Join(SysctlSubDir, KernelSchedCFSBandwidthBurstEnable)
+	helper.WriteProcSubFileContents(testProcSysFilepath, "0")
+	supported, msg = IsCPUBurstSupported()
+	assert.False(t, supported)
+	assert.Contains(t, msg, "sysctl")
+	helper.WriteProcSubFileContents(testProcSysFilepath, "1")
+	supported, _ = IsCPUBurstSupported()
+	assert.True(t, supported)
+}
+
+func TestIsMemoryNumaBalancingSupported(t *testing.T) {
+	helper := NewFileTestUtil(t)
+	defer helper.Cleanup()
+
+	helper.WriteCgroupFileContents(CgroupPathFormatter.ParentDir, MemoryNumaBalancing, "1")
+	supported, msg := IsMemoryNumaBalancingSupported()
+	assert.True(t, supported, msg)
+}
@@ -28,6 +28,8 @@ const (
 	MemcgReaperRelativePath   = "kernel/mm/memcg_reaper/"
 	KidledRelativePath        = "kernel/mm/kidled/"
 	SchedFeaturesRelativePath = "kernel/debug/"
+	ProcSysKernelRelativePath = "sys/kernel/"
+	NumaDemotionRelativePath  = "kernel/mm/numa/"
 
 	MinFreeKbytesFileName             = "min_free_kbytes"
 	WatermarkScaleFactorFileName      = "watermark_scale_factor"
@@ -35,6 +37,8 @@ const (
 	KidledScanPeriodInSecondsFileName = "scan_period_in_seconds"
 	KidledUseHierarchyFileFileName    = "use_hierarchy"
 	SchedFeaturesFileName             = "sched_features"
+	NumaBalancingFileName             = "numa_balancing"
+	NumaDemotionEnabledFileName       = "demotion_enabled"
 )
 
 var (
@@ -43,6 +47,7 @@ var (
 	MemcgReapBackGroundValidator       = &RangeValidator{min: 0, max: 1}
 	KidledScanPeriodInSecondsValidator = &RangeValidator{min: 0, max: math.MaxInt64}
 	KidledUseHierarchyValidator        = &RangeValidator{min: 0, max: 1}
+	NumaBalancingValidator             = &RangeValidator{min: 0, max: 3}
 )
 
 var (
@@ -53,6 +58,11 @@ var (
 	KidledUseHierarchy        = NewCommonSystemResource(KidledRelativePath, KidledUseHierarchyFileFileName, GetSysRootDir).WithValidator(KidledUseHierarchyValidator).WithCheckSupported(SupportedIfFileExists)
 	// SchedFeatures is the system file which shows the enabled features of the kernel scheduling.
 	SchedFeatures = NewCommonSystemResource(SchedFeaturesRelativePath, SchedFeaturesFileName, GetSysRootDir).WithCheckSupported(SupportedIfFileExists)
+	// NumaBalancing is the sysctl kernel.numa_balancing. The mode 2 (NUMA_BALANCING_MEMORY_TIERING) promotes the hot
+	// pages from the slow memory tiers, which is supported since the kernel 5.18.
+	NumaBalancing = NewCommonSystemResource(ProcSysKernelRelativePath, NumaBalancingFileName, GetProcRootDir).WithValidator(NumaBalancingValidator).WithCheckSupported(SupportedIfFileExists)
+	// NumaDemotionEnabled toggles the demotion of the reclaimed pages to the slow memory tiers.
+	NumaDemotionEnabled = NewCommonSystemResource(NumaDemotionRelativePath, NumaDemotionEnabledFileName, GetSysRootDir).WithCheckSupported(SupportedIfFileExists)
 )
 
 var _ Resource = &SystemResource{}
@@ -19,6 +19,7 @@ package system
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -122,6 +123,33 @@ func (r *BlkIORangeValidator) Validate(value string) (bool, string) {
 	return true, ""
 }
 
+// CPUUClampRangeValidator validates the cgroups-v2 `cpu.uclamp.min` and `cpu.uclamp.max`, which accept a percentage
+// in [0, 100] with at most two decimal places, or "max" for 100%.
+// e.g. "0", "20.5", "80.00", "max"
+type CPUUClampRangeValidator struct{}
+
+var cpuUClampValueRegexp = regexp.MustCompile(`^[0-9]+(\.[0-9]{1,2})?$`)
+
+func (c *CPUUClampRangeValidator) Validate(value string) (bool, string) {
+	if value == "" {
+		return false, "value is nil"
+	}
+	if value == CgroupMaxSymbolStr {
+		return true, ""
+	}
+	if !cpuUClampValueRegexp.MatchString(value) {
+		return false, fmt.Sprintf("value %v is not a percentage with at most two decimal places", value)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false, fmt.Sprintf("value %v is not a valid percentage, err: %v", value, err)
+	}
+	if v < 0 || v > 100 {
+		return false, fmt.Sprintf("value %v is not in [min:0, max:100]", value)
+	}
+	return true, ""
+}
+
 type NetClsRangeValidator struct {
 	resource string
 }
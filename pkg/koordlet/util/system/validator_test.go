@@ -71,6 +71,41 @@ func Test_RangeValidate(t *testing.T) {
 	}
 }
 
+func TestCPUUClampRangeValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  bool
+	}{
+		{name: "nil value", value: "", want: false},
+		{name: "max", value: "max", want: true},
+		{name: "integer", value: "20", want: true},
+		{name: "two decimals", value: "80.00", want: true},
+		{name: "one decimal", value: "0.5", want: true},
+		{name: "upper bound", value: "100", want: true},
+		{name: "exceed upper bound", value: "100.01", want: false},
+		{name: "negative", value: "-1", want: false},
+		{name: "too many decimals", value: "20.123", want: false},
+		{name: "not a number", value: "abc", want: false},
+		{name: "exponent", value: "1e1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, msg := CPUUClampValidator.Validate(tt.value)
+			assert.Equal(t, tt.want, got, msg)
+		})
+	}
+}
+
+func TestBlkIORangeValidator_IOWeight(t *testing.T) {
+	got, _ := IOWeightValidator.Validate("253:16 10000")
+	assert.True(t, got)
+	got, _ = IOWeightValidator.Validate("253:16 10001")
+	assert.False(t, got)
+	got, _ = BlkioIOWeightValidator.Validate("253:16 200")
+	assert.False(t, got)
+}
+
 func TestNetClsRangeValidator_Validate(t *testing.T) {
 	type fields struct {
 		resource string
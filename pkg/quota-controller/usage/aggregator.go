@@ -0,0 +1,241 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	schedv1alpha1 "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// QuotaUsage is the aggregated usage of a quota. The usages of a parent quota include its children.
+type QuotaUsage struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Parent    string `json:"parent,omitempty"`
+	IsParent  bool   `json:"isParent,omitempty"`
+
+	Min     corev1.ResourceList `json:"min,omitempty"`
+	Max     corev1.ResourceList `json:"max,omitempty"`
+	Runtime corev1.ResourceList `json:"runtime,omitempty"`
+	Request corev1.ResourceList `json:"request,omitempty"`
+
+	// Used is the real usage of the pods reported in the NodeMetrics.
+	Used corev1.ResourceList `json:"used,omitempty"`
+	// BEUsed is the real usage of the BE pods reported in the NodeMetrics.
+	BEUsed corev1.ResourceList `json:"beUsed,omitempty"`
+	// BERequest is the batch requests of the BE pods. The gap between BERequest and BEUsed
+	// reflects the suppression experienced by the BE pods of the tenant.
+	BERequest corev1.ResourceList `json:"beRequest,omitempty"`
+	// PodCount is the number of running pods which have the usage reported.
+	PodCount int `json:"podCount"`
+}
+
+// QuotaTreeUsage is the usages of the quotas in a quota tree.
+type QuotaTreeUsage struct {
+	TreeID string        `json:"treeID"`
+	Quotas []*QuotaUsage `json:"quotas"`
+}
+
+type Aggregator struct {
+	reader client.Reader
+}
+
+func NewAggregator(reader client.Reader) *Aggregator {
+	return &Aggregator{reader: reader}
+}
+
+// Aggregate returns the usages of all quota trees, or only the specified tree if treeID is not empty.
+func (a *Aggregator) Aggregate(ctx context.Context, treeID string) ([]*QuotaTreeUsage, error) {
+	quotaList := &schedv1alpha1.ElasticQuotaList{}
+	if err := a.reader.List(ctx, quotaList); err != nil {
+		return nil, err
+	}
+	nodeMetricList := &slov1alpha1.NodeMetricList{}
+	if err := a.reader.List(ctx, nodeMetricList); err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := a.reader.List(ctx, podList); err != nil {
+		return nil, err
+	}
+
+	quotas := map[string]*schedv1alpha1.ElasticQuota{}
+	quotaUsages := map[string]*QuotaUsage{}
+	namespaceToQuota := map[string]string{}
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		quotas[quota.Name] = quota
+		quotaUsages[quota.Name] = newQuotaUsage(quota)
+		for _, namespace := range extension.GetAnnotationQuotaNamespaces(quota) {
+			namespaceToQuota[namespace] = quota.Name
+		}
+	}
+
+	podUsages := map[types.NamespacedName]*slov1alpha1.PodMetricInfo{}
+	for i := range nodeMetricList.Items {
+		for _, podMetric := range nodeMetricList.Items[i].Status.PodsMetric {
+			if podMetric == nil {
+				continue
+			}
+			podUsages[types.NamespacedName{Namespace: podMetric.Namespace, Name: podMetric.Name}] = podMetric
+		}
+	}
+
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		podMetric := podUsages[types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}]
+		if podMetric == nil || podMetric.PodUsage.ResourceList == nil {
+			continue
+		}
+		quotaName := getPodQuotaName(pod, quotas, namespaceToQuota)
+		if quotaName == "" {
+			continue
+		}
+		isBE := extension.GetPodQoSClassRaw(pod) == extension.QoSBE
+		var beRequest corev1.ResourceList
+		if isBE {
+			beRequest = util.GetPodRequest(pod, extension.BatchCPU, extension.BatchMemory)
+		}
+		// accumulate the usage to the quota and all its ancestors
+		visited := map[string]bool{}
+		for name := quotaName; name != "" && !visited[name]; name = getParentName(quotas[name]) {
+			visited[name] = true
+			quotaUsage := quotaUsages[name]
+			if quotaUsage == nil {
+				break
+			}
+			quotaUsage.Used = quotav1.Add(quotaUsage.Used, podMetric.PodUsage.ResourceList)
+			if isBE {
+				quotaUsage.BEUsed = quotav1.Add(quotaUsage.BEUsed, podMetric.PodUsage.ResourceList)
+				quotaUsage.BERequest = quotav1.Add(quotaUsage.BERequest, beRequest)
+			}
+			quotaUsage.PodCount++
+		}
+	}
+
+	trees := map[string]*QuotaTreeUsage{}
+	for name, quotaUsage := range quotaUsages {
+		id := extension.GetQuotaTreeID(quotas[name])
+		if treeID != "" && id != treeID {
+			continue
+		}
+		tree := trees[id]
+		if tree == nil {
+			tree = &QuotaTreeUsage{TreeID: id}
+			trees[id] = tree
+		}
+		tree.Quotas = append(tree.Quotas, quotaUsage)
+	}
+
+	result := make([]*QuotaTreeUsage, 0, len(trees))
+	for _, tree := range trees {
+		sort.Slice(tree.Quotas, func(i, j int) bool {
+			return tree.Quotas[i].Name < tree.Quotas[j].Name
+		})
+		result = append(result, tree)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TreeID < result[j].TreeID
+	})
+	return result, nil
+}
+
+func newQuotaUsage(quota *schedv1alpha1.ElasticQuota) *QuotaUsage {
+	runtime, err := extension.GetRuntime(quota)
+	if err != nil {
+		klog.V(4).Infof("failed to get runtime of quota %s, err: %v", quota.Name, err)
+	}
+	request, err := extension.GetRequest(quota)
+	if err != nil {
+		klog.V(4).Infof("failed to get request of quota %s, err: %v", quota.Name, err)
+	}
+	return &QuotaUsage{
+		Name:      quota.Name,
+		Namespace: quota.Namespace,
+		Parent:    getParentName(quota),
+		IsParent:  extension.IsParentQuota(quota),
+		Min:       quota.Spec.Min.DeepCopy(),
+		Max:       quota.Spec.Max.DeepCopy(),
+		Runtime:   runtime,
+		Request:   request,
+		Used:      corev1.ResourceList{},
+		BEUsed:    corev1.ResourceList{},
+		BERequest: corev1.ResourceList{},
+	}
+}
+
+func getParentName(quota *schedv1alpha1.ElasticQuota) string {
+	if quota == nil {
+		return ""
+	}
+	parent := extension.GetParentQuotaName(quota)
+	if parent == extension.RootQuotaName {
+		return ""
+	}
+	return parent
+}
+
+// getPodQuotaName returns the quota of the pod by the quota label, the quota named after the namespace
+// or the quota bound to the namespace, which is consistent with the ElasticQuota plugin.
+func getPodQuotaName(pod *corev1.Pod, quotas map[string]*schedv1alpha1.ElasticQuota, namespaceToQuota map[string]string) string {
+	if name := extension.GetQuotaName(pod); name != "" {
+		if _, ok := quotas[name]; ok {
+			return name
+		}
+	}
+	if quota, ok := quotas[pod.Namespace]; ok && quota.Namespace == pod.Namespace {
+		return quota.Name
+	}
+	if name, ok := namespaceToQuota[pod.Namespace]; ok {
+		return name
+	}
+	if _, ok := quotas[extension.DefaultQuotaName]; ok {
+		return extension.DefaultQuotaName
+	}
+	return ""
+}
+
+// ServeHTTP serves the quota usages in json. The query parameter "tree" filters the quota tree.
+func (a *Aggregator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	usages, err := a.Aggregate(r.Context(), r.URL.Query().Get("tree"))
+	if err != nil {
+		klog.Warningf("failed to aggregate quota usages, err: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(usages); err != nil {
+		klog.Warningf("failed to write quota usages, err: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	schedv1alpha1 "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+)
+
+func newTestAggregator() *Aggregator {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = slov1alpha1.AddToScheme(scheme)
+	_ = schedv1alpha1.AddToScheme(scheme)
+
+	objs := []runtime.Object{
+		&schedv1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "parent",
+				Labels: map[string]string{
+					extension.LabelQuotaIsParent: "true",
+					extension.LabelQuotaTreeID:   "tree-a",
+				},
+			},
+			Spec: schedv1alpha1.ElasticQuotaSpec{
+				Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100")},
+			},
+		},
+		&schedv1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "child",
+				Labels: map[string]string{
+					extension.LabelQuotaParent: "parent",
+					extension.LabelQuotaTreeID: "tree-a",
+				},
+				Annotations: map[string]string{
+					extension.AnnotationRuntime: `{"cpu":"10"}`,
+				},
+			},
+			Spec: schedv1alpha1.ElasticQuotaSpec{
+				Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("5")},
+				Max: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("20")},
+			},
+		},
+		&schedv1alpha1.ElasticQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns-b",
+				Name:      "ns-b",
+				Labels: map[string]string{
+					extension.LabelQuotaTreeID: "tree-b",
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-ls",
+				Labels:    map[string]string{extension.LabelQuotaName: "child"},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "pod-be",
+				Labels: map[string]string{
+					extension.LabelQuotaName: "child",
+					extension.LabelPodQoS:    string(extension.QoSBE),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{extension.BatchCPU: resource.MustParse("4000")},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns-b", Name: "pod-b"},
+		},
+		&slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-0"},
+			Status: slov1alpha1.NodeMetricStatus{
+				PodsMetric: []*slov1alpha1.PodMetricInfo{
+					{
+						Namespace: "default",
+						Name:      "pod-ls",
+						PodUsage:  slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+					},
+					{
+						Namespace: "default",
+						Name:      "pod-be",
+						PodUsage:  slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}},
+					},
+					{
+						Namespace: "ns-b",
+						Name:      "pod-b",
+						PodUsage:  slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("3")}},
+					},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	return NewAggregator(c)
+}
+
+func TestAggregate(t *testing.T) {
+	a := newTestAggregator()
+	trees, err := a.Aggregate(context.TODO(), "")
+	assert.NoError(t, err)
+	assert.Len(t, trees, 2)
+
+	treeA := trees[0]
+	assert.Equal(t, "tree-a", treeA.TreeID)
+	assert.Len(t, treeA.Quotas, 2)
+	child, parent := treeA.Quotas[0], treeA.Quotas[1]
+	assert.Equal(t, "child", child.Name)
+	assert.Equal(t, "parent", child.Parent)
+	assert.Equal(t, 2, child.PodCount)
+	assert.True(t, child.Used.Cpu().Equal(resource.MustParse("3")))
+	assert.True(t, child.BEUsed.Cpu().Equal(resource.MustParse("1")))
+	beRequest := child.BERequest[extension.BatchCPU]
+	assert.Equal(t, int64(4000), beRequest.Value())
+	assert.True(t, child.Runtime.Cpu().Equal(resource.MustParse("10")))
+	assert.Equal(t, "parent", parent.Name)
+	assert.True(t, parent.IsParent)
+	assert.Equal(t, 2, parent.PodCount)
+	assert.True(t, parent.Used.Cpu().Equal(resource.MustParse("3")))
+
+	treeB := trees[1]
+	assert.Equal(t, "tree-b", treeB.TreeID)
+	assert.Len(t, treeB.Quotas, 1)
+	assert.True(t, treeB.Quotas[0].Used.Cpu().Equal(resource.MustParse("3")))
+
+	trees, err = a.Aggregate(context.TODO(), "tree-b")
+	assert.NoError(t, err)
+	assert.Len(t, trees, 1)
+}
+
+func TestAggregatorServeHTTP(t *testing.T) {
+	a := newTestAggregator()
+	w := httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodGet, HTTPPath+"?tree=tree-a", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var trees []*QuotaTreeUsage
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &trees))
+	assert.Len(t, trees, 1)
+	assert.Len(t, trees[0].Quotas, 2)
+
+	w = httptest.NewRecorder()
+	a.ServeHTTP(w, httptest.NewRequest(http.MethodPost, HTTPPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
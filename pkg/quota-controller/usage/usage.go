@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"flag"
+	"net/http"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+	"github.com/koordinator-sh/koordinator/pkg/util/httputil"
+)
+
+const (
+	Name = "quotausage"
+
+	// HTTPPath is the path serving the usages of the quota trees.
+	HTTPPath = "/apis/v1/quota-usages"
+)
+
+// BindAddress is the address the quota usage server binds to.
+var BindAddress = ":9447"
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.StringVar(&BindAddress, "quota-usage-bind-address", BindAddress, "The address the quota usage aggregation server binds to.")
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=scheduling.sigs.k8s.io,resources=elasticquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+
+// Add registers the quota usage aggregation server as a runnable of the manager.
+func Add(mgr manager.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.QuotaUsageAggregation) {
+		klog.V(4).Infof("feature %s is disabled, skip the quota usage server", features.QuotaUsageAggregation)
+		return nil
+	}
+	mux := http.NewServeMux()
+	mux.Handle(HTTPPath, NewAggregator(mgr.GetClient()))
+	return mgr.Add(httputil.NewRunnableServer(Name, BindAddress, "", mux))
+}
@@ -89,6 +89,7 @@ func (c *ContainerResourceExecutor) ParseRequest(req interface{}) (utils.CallHoo
 				ContainerResources:   transferToKoordResources(request.GetConfig().GetLinux().GetResources()),
 				PodCgroupParent:      request.GetSandboxConfig().GetLinux().GetCgroupParent(),
 				ContainerEnvs:        transferToKoordContainerEnvs(request.GetConfig().GetEnvs()),
+				RuntimeHandler:       podCheckPoint.GetRuntimeHandler(),
 			},
 		}
 		klog.Infof("success parse container info %v during container create", c)
@@ -131,6 +132,7 @@ func (c *ContainerResourceExecutor) ParseContainer(container *runtimeapi.Contain
 			PodAnnotations:  podInfo.GetAnnotations(),
 			PodLabels:       podInfo.GetLabels(),
 			PodCgroupParent: podInfo.GetCgroupParent(),
+			RuntimeHandler:  podInfo.GetRuntimeHandler(),
 			// envs info would be loss during failover.
 			// TODO: How to get resource and envs when failOver
 		},
@@ -80,6 +80,7 @@ func (d *RuntimeManagerDockerServer) HandleCreateContainer(ctx context.Context,
 				PodLabels:            podInfo.Labels,
 				PodCgroupParent:      podInfo.CgroupParent,
 				ContainerEnvs:        splitDockerEnv(ContainerConfig.Env),
+				RuntimeHandler:       podInfo.RuntimeHandler,
 			},
 		}
 		runtimeHookPath = config.CreateContainer
@@ -158,6 +158,7 @@ func (d *RuntimeManagerDockerServer) failOver(dockerClient proxyDockerClient) er
 		if podCheckPoint != nil {
 			cInfo.ContainerResourceHookRequest.PodMeta = podCheckPoint.PodMeta
 			cInfo.ContainerResourceHookRequest.PodResources = podCheckPoint.Resources
+			cInfo.ContainerResourceHookRequest.RuntimeHandler = podCheckPoint.RuntimeHandler
 		}
 		if c.Config != nil {
 			cInfo.ContainerResourceHookRequest.ContainerEnvs = splitDockerEnv(c.Config.Env)
@@ -59,6 +59,52 @@ type LoadAwareSchedulingArgs struct {
 	EstimatedScalingFactors map[corev1.ResourceName]int64
 	// Aggregated supports resource utilization filtering and scoring based on percentile statistics
 	Aggregated *LoadAwareSchedulingAggregatedArgs
+	// StaleNodeMetricPolicy indicates how to handle the nodes whose NodeMetrics are stale but not expired.
+	// Not enabled by default
+	StaleNodeMetricPolicy *LoadAwareStaleNodeMetricPolicy
+	// ThermalThrottledPenaltyScorePercent indicates the percentage of the score deducted for the nodes reporting
+	// the CPUThermalThrottled condition, since the cpus of the nodes run below the expected frequency.
+	// Not enabled by default
+	ThermalThrottledPenaltyScorePercent int64
+	// AccountSystemUsage indicates whether to add the resource usage of the daemon processes and OS kernel reported by
+	// koordlet into the estimated usage when it is not covered by the node usage, i.e. the Prod usage and the
+	// request-based usage of the stale NodeMetrics, so the nodes with heavy host daemons are not overloaded.
+	// Not enabled by default
+	AccountSystemUsage bool
+	// FilterColocationNotReadyNodes indicates whether to filter the nodes whose koordlet reports the ColocationReady
+	// condition is False for the Batch pods, since the QoS enforcement of the colocation is broken on the nodes.
+	// Not enabled by default
+	FilterColocationNotReadyNodes bool
+	// MemoryBandwidthUsageThresholdPercent indicates the memory bandwidth utilization threshold of the node for the pods
+	// declaring the memory bandwidth requests by the annotation. The node is filtered if the memory bandwidth measured
+	// by the koordlet plus the requests exceeds the threshold of the capacity reported in the NodeMetric.
+	// Not enabled by default
+	MemoryBandwidthUsageThresholdPercent int64
+	// ScoreAccordingMemoryBandwidth indicates whether to scale the score of the pods declaring the memory bandwidth
+	// requests by the ratio of the free memory bandwidth of the node after placing the pods.
+	// Not enabled by default
+	ScoreAccordingMemoryBandwidth bool
+}
+
+// StaleNodeMetricAction is the action of the LoadAwareScheduling to take on the nodes with the stale NodeMetrics.
+type StaleNodeMetricAction string
+
+const (
+	// StaleNodeMetricActionRequestBased estimates the node usage by the requests of the pods on the node.
+	StaleNodeMetricActionRequestBased StaleNodeMetricAction = "RequestBased"
+	// StaleNodeMetricActionPenalty keeps using the stale NodeMetric but deducts the score of the node.
+	StaleNodeMetricActionPenalty StaleNodeMetricAction = "Penalty"
+	// StaleNodeMetricActionFilter filters out the node.
+	StaleNodeMetricActionFilter StaleNodeMetricAction = "Filter"
+)
+
+type LoadAwareStaleNodeMetricPolicy struct {
+	// StaleReportIntervals indicates the NodeMetric is stale if it is not updated in the number of report intervals.
+	StaleReportIntervals int64
+	// Action indicates the action to take on the nodes with the stale NodeMetrics.
+	Action StaleNodeMetricAction
+	// PenaltyScorePercent indicates the percentage of the score deducted for the stale nodes if the action is Penalty.
+	PenaltyScorePercent int64
 }
 
 type LoadAwareSchedulingAggregatedArgs struct {
@@ -115,6 +161,9 @@ type NodeNUMAResourceArgs struct {
 	ScoringStrategy *ScoringStrategy
 	// NUMAScoringStrategy is used to configure the scoring strategy of the NUMANode-level
 	NUMAScoringStrategy *ScoringStrategy
+	// ZoneMemoryScoringWeight is the weight of the actual free memory of NUMA zones reported in the NodeMetric
+	// when scoring memory-heavy batch pods. The zone memory scoring is disabled if it is zero.
+	ZoneMemoryScoringWeight int64
 }
 
 // CPUBindPolicy defines the CPU binding policy
@@ -213,6 +262,14 @@ type ElasticQuotaArgs struct {
 
 	// EnableRuntimeQuota if true, use max instead of runtime for all checks.
 	EnableRuntimeQuota bool
+
+	// EnableGangQuotaAdmission if true, the quota of a gang is reserved for all the members atomically
+	// in PreFilter, or none of the members is admitted.
+	EnableGangQuotaAdmission bool
+
+	// PreemptionImmunityDuration is the grace period after a pod is admitted with the borrowed quota, i.e. the used
+	// of the quota exceeds its min, during which the pod cannot be preempted or revoked. Zero disables the immunity.
+	PreemptionImmunityDuration metav1.Duration
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -230,8 +287,22 @@ type CoschedulingArgs struct {
 	// Skip check schedule cycle
 	// default is false
 	SkipCheckScheduleCycle bool
+	// QueueSortPolicy indicates how to order the gangs of the same priority in the scheduling queue.
+	QueueSortPolicy GangQueueSortPolicy
 }
 
+// GangQueueSortPolicy defines how to order the gangs of the same priority in the scheduling queue.
+type GangQueueSortPolicy = string
+
+const (
+	// GangQueueSortPolicyDefault orders the gangs by their last schedule time.
+	GangQueueSortPolicyDefault GangQueueSortPolicy = "Default"
+	// GangQueueSortPolicyQuotaFairShare interleaves the gangs of the different quotas by the weighted fair share
+	// of the quotas (the used and the requests of the gangs in the min), so that the waiting gangs of one quota
+	// cannot block the ones of the other quotas when the cluster is temporarily full.
+	GangQueueSortPolicyQuotaFairShare GangQueueSortPolicy = "QuotaFairShare"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // DeviceShareArgs defines the parameters for DeviceShare plugin.
@@ -245,8 +316,21 @@ type DeviceShareArgs struct {
 	ScoringStrategy *ScoringStrategy
 	// DisableDeviceNUMATopologyAlignment indicates device don't need to align with other resources' numa topology
 	DisableDeviceNUMATopologyAlignment bool
+	// PCIeSpreadPolicy indicates how to spread the devices of the pods across the PCIe root complexes.
+	PCIeSpreadPolicy PCIeSpreadPolicy
 }
 
+// PCIeSpreadPolicy defines how to spread the devices allocated to the different pods across the PCIe root complexes.
+type PCIeSpreadPolicy = string
+
+const (
+	// PCIeSpreadPolicyNone allocates the devices without considering the balance of the PCIe root complexes.
+	PCIeSpreadPolicyNone PCIeSpreadPolicy = "None"
+	// PCIeSpreadPolicySpreadByRootComplex prefers the devices under the PCIe root complex with the fewest allocated
+	// devices of the same type, so that the bandwidth-heavy pods don't all share one PCIe root port.
+	PCIeSpreadPolicySpreadByRootComplex PCIeSpreadPolicy = "SpreadByRootComplex"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // ScarceResourceAvoidanceArgs defines the parameters for ScarceResourceAvoidance plugin.
@@ -267,3 +351,39 @@ type ResourcesType struct {
 	Type   config.ScoringStrategyType
 	Weight int64
 }
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SchedulerCoexistenceArgs defines the parameters for SchedulerCoexistence plugin.
+type SchedulerCoexistenceArgs struct {
+	metav1.TypeMeta
+	// KoordSchedulerNames are the scheduler names served by koord-scheduler. The pods bound by other schedulers are
+	// checked whether their cpusets conflict with the fine-grained CPU allocations of koord-scheduler.
+	KoordSchedulerNames []string
+	// ConflictPolicy indicates how to handle the nodes which have cpuset conflicts caused by other schedulers.
+	ConflictPolicy CoexistenceConflictPolicy
+}
+
+type CoexistenceConflictPolicy = string
+
+const (
+	// CoexistenceConflictPolicyFlag only records the conflicts with events.
+	CoexistenceConflictPolicyFlag CoexistenceConflictPolicy = "Flag"
+	// CoexistenceConflictPolicyReject records the conflicts and rejects the pods requiring cpusets to be scheduled
+	// on the conflicted nodes until the conflicts are resolved.
+	CoexistenceConflictPolicyReject CoexistenceConflictPolicy = "Reject"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InterferenceAwareArgs defines the parameters for InterferenceAware plugin.
+type InterferenceAwareArgs struct {
+	metav1.TypeMeta
+	// SensitivityRisks is the interference risk in [0, 100] contributed by each resident pod of the sensitivity
+	// annotated by `scheduling.koordinator.sh/interference-sensitivity`. The risk of the resident pods is capped at 100.
+	SensitivityRisks map[extension.InterferenceSensitivity]int64
+	// ContentionWeight is the weight in [0, 100] of the historical contention of the node in the interference risk,
+	// i.e. the network latency SLO violation percent of the non-BE pods reported in the NodeMetric. The weight of the
+	// resident pods' sensitivity is 100 - ContentionWeight.
+	ContentionWeight *int64
+}
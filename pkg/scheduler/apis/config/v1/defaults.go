@@ -31,6 +31,8 @@ import (
 
 var (
 	defaultNodeMetricExpirationSeconds int64 = 180
+	defaultStaleReportIntervals        int64 = 3
+	defaultStalePenaltyScorePercent    int64 = 50
 
 	defaultResourceWeights = map[corev1.ResourceName]int64{
 		corev1.ResourceCPU:    1,
@@ -49,6 +51,10 @@ var (
 
 	defaultPreferredCPUBindPolicy = CPUBindPolicyFullPCPUs
 
+	defaultPCIeSpreadPolicy = PCIeSpreadPolicyNone
+
+	defaultGangQueueSortPolicy = GangQueueSortPolicyDefault
+
 	defaultEnablePreemption             = pointer.Bool(false)
 	defaultMinCandidateNodesPercentage  = pointer.Int32(10)
 	defaultMinCandidateNodesAbsolute    = pointer.Int32(100)
@@ -69,9 +75,20 @@ var (
 
 	defaultQuotaGroupNamespace = "koordinator-system"
 
-	defaultMonitorAllQuotas       = pointer.Bool(false)
-	defaultEnableCheckParentQuota = pointer.Bool(false)
-	defaultEnableRuntimeQuota     = pointer.Bool(true)
+	defaultMonitorAllQuotas         = pointer.Bool(false)
+	defaultEnableCheckParentQuota   = pointer.Bool(false)
+	defaultEnableRuntimeQuota       = pointer.Bool(true)
+	defaultEnableGangQuotaAdmission = pointer.Bool(false)
+
+	defaultKoordSchedulerNames       = []string{"koord-scheduler"}
+	defaultCoexistenceConflictPolicy = CoexistenceConflictPolicyReject
+
+	defaultSensitivityRisks = map[extension.InterferenceSensitivity]int64{
+		extension.InterferenceSensitivityHigh:   40,
+		extension.InterferenceSensitivityMedium: 15,
+		extension.InterferenceSensitivityLow:    5,
+	}
+	defaultContentionWeight = pointer.Int64(50)
 
 	defaultTimeout           = 600 * time.Second
 	defaultControllerWorkers = 1
@@ -103,6 +120,17 @@ func SetDefaults_LoadAwareSchedulingArgs(obj *LoadAwareSchedulingArgs) {
 			}
 		}
 	}
+	if obj.StaleNodeMetricPolicy != nil {
+		if obj.StaleNodeMetricPolicy.StaleReportIntervals == 0 {
+			obj.StaleNodeMetricPolicy.StaleReportIntervals = defaultStaleReportIntervals
+		}
+		if obj.StaleNodeMetricPolicy.Action == "" {
+			obj.StaleNodeMetricPolicy.Action = StaleNodeMetricActionRequestBased
+		}
+		if obj.StaleNodeMetricPolicy.Action == StaleNodeMetricActionPenalty && obj.StaleNodeMetricPolicy.PenaltyScorePercent == 0 {
+			obj.StaleNodeMetricPolicy.PenaltyScorePercent = defaultStalePenaltyScorePercent
+		}
+	}
 }
 
 // SetDefaults_NodeNUMAResourceArgs sets the default parameters for NodeNUMANodeResource plugin.
@@ -190,6 +218,9 @@ func SetDefaults_ElasticQuotaArgs(obj *ElasticQuotaArgs) {
 	if obj.EnableRuntimeQuota == nil {
 		obj.EnableRuntimeQuota = defaultEnableRuntimeQuota
 	}
+	if obj.EnableGangQuotaAdmission == nil {
+		obj.EnableGangQuotaAdmission = defaultEnableGangQuotaAdmission
+	}
 }
 
 func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
@@ -201,9 +232,17 @@ func SetDefaults_CoschedulingArgs(obj *CoschedulingArgs) {
 	if obj.ControllerWorkers == nil {
 		obj.ControllerWorkers = pointer.Int64(int64(defaultControllerWorkers))
 	}
+	if obj.QueueSortPolicy == nil {
+		policy := defaultGangQueueSortPolicy
+		obj.QueueSortPolicy = &policy
+	}
 }
 
 func SetDefaults_DeviceShareArgs(obj *DeviceShareArgs) {
+	if obj.PCIeSpreadPolicy == nil {
+		policy := defaultPCIeSpreadPolicy
+		obj.PCIeSpreadPolicy = &policy
+	}
 	if obj.ScoringStrategy == nil {
 		obj.ScoringStrategy = &ScoringStrategy{
 			// By default, LeastAllocate is used to ensure high availability of applications
@@ -225,3 +264,24 @@ func SetDefaults_DeviceShareArgs(obj *DeviceShareArgs) {
 		}
 	}
 }
+
+func SetDefaults_SchedulerCoexistenceArgs(obj *SchedulerCoexistenceArgs) {
+	if len(obj.KoordSchedulerNames) == 0 {
+		obj.KoordSchedulerNames = append([]string{}, defaultKoordSchedulerNames...)
+	}
+	if obj.ConflictPolicy == "" {
+		obj.ConflictPolicy = defaultCoexistenceConflictPolicy
+	}
+}
+
+func SetDefaults_InterferenceAwareArgs(obj *InterferenceAwareArgs) {
+	if len(obj.SensitivityRisks) == 0 {
+		obj.SensitivityRisks = make(map[extension.InterferenceSensitivity]int64, len(defaultSensitivityRisks))
+		for k, v := range defaultSensitivityRisks {
+			obj.SensitivityRisks[k] = v
+		}
+	}
+	if obj.ContentionWeight == nil {
+		obj.ContentionWeight = pointer.Int64(*defaultContentionWeight)
+	}
+}
@@ -42,6 +42,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DeviceShareArgs{},
 		&NodeResourcesFitPlusArgs{},
 		&ScarceResourceAvoidanceArgs{},
+		&SchedulerCoexistenceArgs{},
+		&InterferenceAwareArgs{},
 	)
 	return nil
 }
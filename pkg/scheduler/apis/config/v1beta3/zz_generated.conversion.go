@@ -71,6 +71,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*InterferenceAwareArgs)(nil), (*config.InterferenceAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs(a.(*InterferenceAwareArgs), b.(*config.InterferenceAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.InterferenceAwareArgs)(nil), (*InterferenceAwareArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_InterferenceAwareArgs_To_v1beta3_InterferenceAwareArgs(a.(*config.InterferenceAwareArgs), b.(*InterferenceAwareArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*LoadAwareSchedulingAggregatedArgs)(nil), (*config.LoadAwareSchedulingAggregatedArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(a.(*LoadAwareSchedulingAggregatedArgs), b.(*config.LoadAwareSchedulingAggregatedArgs), scope)
 	}); err != nil {
@@ -86,6 +96,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*LoadAwareStaleNodeMetricPolicy)(nil), (*config.LoadAwareStaleNodeMetricPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_LoadAwareStaleNodeMetricPolicy_To_config_LoadAwareStaleNodeMetricPolicy(a.(*LoadAwareStaleNodeMetricPolicy), b.(*config.LoadAwareStaleNodeMetricPolicy), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.LoadAwareStaleNodeMetricPolicy)(nil), (*LoadAwareStaleNodeMetricPolicy)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_LoadAwareStaleNodeMetricPolicy_To_v1beta3_LoadAwareStaleNodeMetricPolicy(a.(*config.LoadAwareStaleNodeMetricPolicy), b.(*LoadAwareStaleNodeMetricPolicy), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeNUMAResourceArgs)(nil), (*config.NodeNUMAResourceArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(a.(*NodeNUMAResourceArgs), b.(*config.NodeNUMAResourceArgs), scope)
 	}); err != nil {
@@ -136,6 +156,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SchedulerCoexistenceArgs)(nil), (*config.SchedulerCoexistenceArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs(a.(*SchedulerCoexistenceArgs), b.(*config.SchedulerCoexistenceArgs), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*config.SchedulerCoexistenceArgs)(nil), (*SchedulerCoexistenceArgs)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_config_SchedulerCoexistenceArgs_To_v1beta3_SchedulerCoexistenceArgs(a.(*config.SchedulerCoexistenceArgs), b.(*SchedulerCoexistenceArgs), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ScoringStrategy)(nil), (*config.ScoringStrategy)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_ScoringStrategy_To_config_ScoringStrategy(a.(*ScoringStrategy), b.(*config.ScoringStrategy), scope)
 	}); err != nil {
@@ -164,6 +194,9 @@ func autoConvert_v1beta3_CoschedulingArgs_To_config_CoschedulingArgs(in *Cosched
 	if err := v1.Convert_Pointer_bool_To_bool(&in.SkipCheckScheduleCycle, &out.SkipCheckScheduleCycle, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_string_To_string(&in.QueueSortPolicy, &out.QueueSortPolicy, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -182,6 +215,9 @@ func autoConvert_config_CoschedulingArgs_To_v1beta3_CoschedulingArgs(in *config.
 	if err := v1.Convert_bool_To_Pointer_bool(&in.SkipCheckScheduleCycle, &out.SkipCheckScheduleCycle, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_string_To_Pointer_string(&in.QueueSortPolicy, &out.QueueSortPolicy, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -194,6 +230,9 @@ func autoConvert_v1beta3_DeviceShareArgs_To_config_DeviceShareArgs(in *DeviceSha
 	out.Allocator = in.Allocator
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.DisableDeviceNUMATopologyAlignment = in.DisableDeviceNUMATopologyAlignment
+	if err := v1.Convert_Pointer_string_To_string(&in.PCIeSpreadPolicy, &out.PCIeSpreadPolicy, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -206,6 +245,9 @@ func autoConvert_config_DeviceShareArgs_To_v1beta3_DeviceShareArgs(in *config.De
 	out.Allocator = in.Allocator
 	out.ScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.DisableDeviceNUMATopologyAlignment = in.DisableDeviceNUMATopologyAlignment
+	if err := v1.Convert_string_To_Pointer_string(&in.PCIeSpreadPolicy, &out.PCIeSpreadPolicy, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -233,6 +275,12 @@ func autoConvert_v1beta3_ElasticQuotaArgs_To_config_ElasticQuotaArgs(in *Elastic
 	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableRuntimeQuota, &out.EnableRuntimeQuota, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_Pointer_bool_To_bool(&in.EnableGangQuotaAdmission, &out.EnableGangQuotaAdmission, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_Pointer_v1_Duration_To_v1_Duration(&in.PreemptionImmunityDuration, &out.PreemptionImmunityDuration, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -260,6 +308,12 @@ func autoConvert_config_ElasticQuotaArgs_To_v1beta3_ElasticQuotaArgs(in *config.
 	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableRuntimeQuota, &out.EnableRuntimeQuota, s); err != nil {
 		return err
 	}
+	if err := v1.Convert_bool_To_Pointer_bool(&in.EnableGangQuotaAdmission, &out.EnableGangQuotaAdmission, s); err != nil {
+		return err
+	}
+	if err := v1.Convert_v1_Duration_To_Pointer_v1_Duration(&in.PreemptionImmunityDuration, &out.PreemptionImmunityDuration, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -268,6 +322,28 @@ func Convert_config_ElasticQuotaArgs_To_v1beta3_ElasticQuotaArgs(in *config.Elas
 	return autoConvert_config_ElasticQuotaArgs_To_v1beta3_ElasticQuotaArgs(in, out, s)
 }
 
+func autoConvert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs(in *InterferenceAwareArgs, out *config.InterferenceAwareArgs, s conversion.Scope) error {
+	out.SensitivityRisks = *(*map[extension.InterferenceSensitivity]int64)(unsafe.Pointer(&in.SensitivityRisks))
+	out.ContentionWeight = (*int64)(unsafe.Pointer(in.ContentionWeight))
+	return nil
+}
+
+// Convert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs is an autogenerated conversion function.
+func Convert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs(in *InterferenceAwareArgs, out *config.InterferenceAwareArgs, s conversion.Scope) error {
+	return autoConvert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs(in, out, s)
+}
+
+func autoConvert_config_InterferenceAwareArgs_To_v1beta3_InterferenceAwareArgs(in *config.InterferenceAwareArgs, out *InterferenceAwareArgs, s conversion.Scope) error {
+	out.SensitivityRisks = *(*map[extension.InterferenceSensitivity]int64)(unsafe.Pointer(&in.SensitivityRisks))
+	out.ContentionWeight = (*int64)(unsafe.Pointer(in.ContentionWeight))
+	return nil
+}
+
+// Convert_config_InterferenceAwareArgs_To_v1beta3_InterferenceAwareArgs is an autogenerated conversion function.
+func Convert_config_InterferenceAwareArgs_To_v1beta3_InterferenceAwareArgs(in *config.InterferenceAwareArgs, out *InterferenceAwareArgs, s conversion.Scope) error {
+	return autoConvert_config_InterferenceAwareArgs_To_v1beta3_InterferenceAwareArgs(in, out, s)
+}
+
 func autoConvert_v1beta3_LoadAwareSchedulingAggregatedArgs_To_config_LoadAwareSchedulingAggregatedArgs(in *LoadAwareSchedulingAggregatedArgs, out *config.LoadAwareSchedulingAggregatedArgs, s conversion.Scope) error {
 	out.UsageThresholds = *(*map[corev1.ResourceName]int64)(unsafe.Pointer(&in.UsageThresholds))
 	out.UsageAggregationType = extension.AggregationType(in.UsageAggregationType)
@@ -325,6 +401,12 @@ func autoConvert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingAr
 	} else {
 		out.Aggregated = nil
 	}
+	out.StaleNodeMetricPolicy = (*config.LoadAwareStaleNodeMetricPolicy)(unsafe.Pointer(in.StaleNodeMetricPolicy))
+	out.ThermalThrottledPenaltyScorePercent = in.ThermalThrottledPenaltyScorePercent
+	out.AccountSystemUsage = in.AccountSystemUsage
+	out.FilterColocationNotReadyNodes = in.FilterColocationNotReadyNodes
+	out.MemoryBandwidthUsageThresholdPercent = in.MemoryBandwidthUsageThresholdPercent
+	out.ScoreAccordingMemoryBandwidth = in.ScoreAccordingMemoryBandwidth
 	return nil
 }
 
@@ -349,6 +431,12 @@ func autoConvert_config_LoadAwareSchedulingArgs_To_v1beta3_LoadAwareSchedulingAr
 	} else {
 		out.Aggregated = nil
 	}
+	out.StaleNodeMetricPolicy = (*LoadAwareStaleNodeMetricPolicy)(unsafe.Pointer(in.StaleNodeMetricPolicy))
+	out.ThermalThrottledPenaltyScorePercent = in.ThermalThrottledPenaltyScorePercent
+	out.AccountSystemUsage = in.AccountSystemUsage
+	out.FilterColocationNotReadyNodes = in.FilterColocationNotReadyNodes
+	out.MemoryBandwidthUsageThresholdPercent = in.MemoryBandwidthUsageThresholdPercent
+	out.ScoreAccordingMemoryBandwidth = in.ScoreAccordingMemoryBandwidth
 	return nil
 }
 
@@ -357,12 +445,39 @@ func Convert_config_LoadAwareSchedulingArgs_To_v1beta3_LoadAwareSchedulingArgs(i
 	return autoConvert_config_LoadAwareSchedulingArgs_To_v1beta3_LoadAwareSchedulingArgs(in, out, s)
 }
 
+func autoConvert_v1beta3_LoadAwareStaleNodeMetricPolicy_To_config_LoadAwareStaleNodeMetricPolicy(in *LoadAwareStaleNodeMetricPolicy, out *config.LoadAwareStaleNodeMetricPolicy, s conversion.Scope) error {
+	out.StaleReportIntervals = in.StaleReportIntervals
+	out.Action = config.StaleNodeMetricAction(in.Action)
+	out.PenaltyScorePercent = in.PenaltyScorePercent
+	return nil
+}
+
+// Convert_v1beta3_LoadAwareStaleNodeMetricPolicy_To_config_LoadAwareStaleNodeMetricPolicy is an autogenerated conversion function.
+func Convert_v1beta3_LoadAwareStaleNodeMetricPolicy_To_config_LoadAwareStaleNodeMetricPolicy(in *LoadAwareStaleNodeMetricPolicy, out *config.LoadAwareStaleNodeMetricPolicy, s conversion.Scope) error {
+	return autoConvert_v1beta3_LoadAwareStaleNodeMetricPolicy_To_config_LoadAwareStaleNodeMetricPolicy(in, out, s)
+}
+
+func autoConvert_config_LoadAwareStaleNodeMetricPolicy_To_v1beta3_LoadAwareStaleNodeMetricPolicy(in *config.LoadAwareStaleNodeMetricPolicy, out *LoadAwareStaleNodeMetricPolicy, s conversion.Scope) error {
+	out.StaleReportIntervals = in.StaleReportIntervals
+	out.Action = StaleNodeMetricAction(in.Action)
+	out.PenaltyScorePercent = in.PenaltyScorePercent
+	return nil
+}
+
+// Convert_config_LoadAwareStaleNodeMetricPolicy_To_v1beta3_LoadAwareStaleNodeMetricPolicy is an autogenerated conversion function.
+func Convert_config_LoadAwareStaleNodeMetricPolicy_To_v1beta3_LoadAwareStaleNodeMetricPolicy(in *config.LoadAwareStaleNodeMetricPolicy, out *LoadAwareStaleNodeMetricPolicy, s conversion.Scope) error {
+	return autoConvert_config_LoadAwareStaleNodeMetricPolicy_To_v1beta3_LoadAwareStaleNodeMetricPolicy(in, out, s)
+}
+
 func autoConvert_v1beta3_NodeNUMAResourceArgs_To_config_NodeNUMAResourceArgs(in *NodeNUMAResourceArgs, out *config.NodeNUMAResourceArgs, s conversion.Scope) error {
 	if err := v1.Convert_Pointer_string_To_string(&in.DefaultCPUBindPolicy, &out.DefaultCPUBindPolicy, s); err != nil {
 		return err
 	}
 	out.ScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.NUMAScoringStrategy = (*config.ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	if err := v1.Convert_Pointer_int64_To_int64(&in.ZoneMemoryScoringWeight, &out.ZoneMemoryScoringWeight, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -377,6 +492,9 @@ func autoConvert_config_NodeNUMAResourceArgs_To_v1beta3_NodeNUMAResourceArgs(in
 	}
 	out.ScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.ScoringStrategy))
 	out.NUMAScoringStrategy = (*ScoringStrategy)(unsafe.Pointer(in.NUMAScoringStrategy))
+	if err := v1.Convert_int64_To_Pointer_int64(&in.ZoneMemoryScoringWeight, &out.ZoneMemoryScoringWeight, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -491,6 +609,28 @@ func Convert_config_ScarceResourceAvoidanceArgs_To_v1beta3_ScarceResourceAvoidan
 	return autoConvert_config_ScarceResourceAvoidanceArgs_To_v1beta3_ScarceResourceAvoidanceArgs(in, out, s)
 }
 
+func autoConvert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs(in *SchedulerCoexistenceArgs, out *config.SchedulerCoexistenceArgs, s conversion.Scope) error {
+	out.KoordSchedulerNames = *(*[]string)(unsafe.Pointer(&in.KoordSchedulerNames))
+	out.ConflictPolicy = in.ConflictPolicy
+	return nil
+}
+
+// Convert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs is an autogenerated conversion function.
+func Convert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs(in *SchedulerCoexistenceArgs, out *config.SchedulerCoexistenceArgs, s conversion.Scope) error {
+	return autoConvert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs(in, out, s)
+}
+
+func autoConvert_config_SchedulerCoexistenceArgs_To_v1beta3_SchedulerCoexistenceArgs(in *config.SchedulerCoexistenceArgs, out *SchedulerCoexistenceArgs, s conversion.Scope) error {
+	out.KoordSchedulerNames = *(*[]string)(unsafe.Pointer(&in.KoordSchedulerNames))
+	out.ConflictPolicy = in.ConflictPolicy
+	return nil
+}
+
+// Convert_config_SchedulerCoexistenceArgs_To_v1beta3_SchedulerCoexistenceArgs is an autogenerated conversion function.
+func Convert_config_SchedulerCoexistenceArgs_To_v1beta3_SchedulerCoexistenceArgs(in *config.SchedulerCoexistenceArgs, out *SchedulerCoexistenceArgs, s conversion.Scope) error {
+	return autoConvert_config_SchedulerCoexistenceArgs_To_v1beta3_SchedulerCoexistenceArgs(in, out, s)
+}
+
 func autoConvert_v1beta3_ScoringStrategy_To_config_ScoringStrategy(in *ScoringStrategy, out *config.ScoringStrategy, s conversion.Scope) error {
 	out.Type = config.ScoringStrategyType(in.Type)
 	out.Resources = *(*[]apisconfig.ResourceSpec)(unsafe.Pointer(&in.Resources))
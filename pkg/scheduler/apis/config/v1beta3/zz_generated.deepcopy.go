@@ -22,6 +22,7 @@ limitations under the License.
 package v1beta3
 
 import (
+	extension "github.com/koordinator-sh/koordinator/apis/extension"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -47,6 +48,11 @@ func (in *CoschedulingArgs) DeepCopyInto(out *CoschedulingArgs) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.QueueSortPolicy != nil {
+		in, out := &in.QueueSortPolicy, &out.QueueSortPolicy
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -77,6 +83,11 @@ func (in *DeviceShareArgs) DeepCopyInto(out *DeviceShareArgs) {
 		*out = new(ScoringStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PCIeSpreadPolicy != nil {
+		in, out := &in.PCIeSpreadPolicy, &out.PCIeSpreadPolicy
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -141,6 +152,16 @@ func (in *ElasticQuotaArgs) DeepCopyInto(out *ElasticQuotaArgs) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.EnableGangQuotaAdmission != nil {
+		in, out := &in.EnableGangQuotaAdmission, &out.EnableGangQuotaAdmission
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PreemptionImmunityDuration != nil {
+		in, out := &in.PreemptionImmunityDuration, &out.PreemptionImmunityDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
@@ -162,6 +183,43 @@ func (in *ElasticQuotaArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InterferenceAwareArgs) DeepCopyInto(out *InterferenceAwareArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.SensitivityRisks != nil {
+		in, out := &in.SensitivityRisks, &out.SensitivityRisks
+		*out = make(map[extension.InterferenceSensitivity]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ContentionWeight != nil {
+		in, out := &in.ContentionWeight, &out.ContentionWeight
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InterferenceAwareArgs.
+func (in *InterferenceAwareArgs) DeepCopy() *InterferenceAwareArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(InterferenceAwareArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InterferenceAwareArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadAwareSchedulingAggregatedArgs) DeepCopyInto(out *LoadAwareSchedulingAggregatedArgs) {
 	*out = *in
@@ -252,6 +310,11 @@ func (in *LoadAwareSchedulingArgs) DeepCopyInto(out *LoadAwareSchedulingArgs) {
 		*out = new(LoadAwareSchedulingAggregatedArgs)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StaleNodeMetricPolicy != nil {
+		in, out := &in.StaleNodeMetricPolicy, &out.StaleNodeMetricPolicy
+		*out = new(LoadAwareStaleNodeMetricPolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -273,6 +336,22 @@ func (in *LoadAwareSchedulingArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LoadAwareStaleNodeMetricPolicy) DeepCopyInto(out *LoadAwareStaleNodeMetricPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LoadAwareStaleNodeMetricPolicy.
+func (in *LoadAwareStaleNodeMetricPolicy) DeepCopy() *LoadAwareStaleNodeMetricPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LoadAwareStaleNodeMetricPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 	*out = *in
@@ -292,6 +371,11 @@ func (in *NodeNUMAResourceArgs) DeepCopyInto(out *NodeNUMAResourceArgs) {
 		*out = new(ScoringStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ZoneMemoryScoringWeight != nil {
+		in, out := &in.ZoneMemoryScoringWeight, &out.ZoneMemoryScoringWeight
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -436,6 +520,36 @@ func (in *ScarceResourceAvoidanceArgs) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchedulerCoexistenceArgs) DeepCopyInto(out *SchedulerCoexistenceArgs) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.KoordSchedulerNames != nil {
+		in, out := &in.KoordSchedulerNames, &out.KoordSchedulerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchedulerCoexistenceArgs.
+func (in *SchedulerCoexistenceArgs) DeepCopy() *SchedulerCoexistenceArgs {
+	if in == nil {
+		return nil
+	}
+	out := new(SchedulerCoexistenceArgs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SchedulerCoexistenceArgs) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScoringStrategy) DeepCopyInto(out *ScoringStrategy) {
 	*out = *in
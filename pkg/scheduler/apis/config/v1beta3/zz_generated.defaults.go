@@ -32,9 +32,11 @@ func RegisterDefaults(scheme *runtime.Scheme) error {
 	scheme.AddTypeDefaultingFunc(&CoschedulingArgs{}, func(obj interface{}) { SetObjectDefaults_CoschedulingArgs(obj.(*CoschedulingArgs)) })
 	scheme.AddTypeDefaultingFunc(&DeviceShareArgs{}, func(obj interface{}) { SetObjectDefaults_DeviceShareArgs(obj.(*DeviceShareArgs)) })
 	scheme.AddTypeDefaultingFunc(&ElasticQuotaArgs{}, func(obj interface{}) { SetObjectDefaults_ElasticQuotaArgs(obj.(*ElasticQuotaArgs)) })
+	scheme.AddTypeDefaultingFunc(&InterferenceAwareArgs{}, func(obj interface{}) { SetObjectDefaults_InterferenceAwareArgs(obj.(*InterferenceAwareArgs)) })
 	scheme.AddTypeDefaultingFunc(&LoadAwareSchedulingArgs{}, func(obj interface{}) { SetObjectDefaults_LoadAwareSchedulingArgs(obj.(*LoadAwareSchedulingArgs)) })
 	scheme.AddTypeDefaultingFunc(&NodeNUMAResourceArgs{}, func(obj interface{}) { SetObjectDefaults_NodeNUMAResourceArgs(obj.(*NodeNUMAResourceArgs)) })
 	scheme.AddTypeDefaultingFunc(&ReservationArgs{}, func(obj interface{}) { SetObjectDefaults_ReservationArgs(obj.(*ReservationArgs)) })
+	scheme.AddTypeDefaultingFunc(&SchedulerCoexistenceArgs{}, func(obj interface{}) { SetObjectDefaults_SchedulerCoexistenceArgs(obj.(*SchedulerCoexistenceArgs)) })
 	return nil
 }
 
@@ -50,6 +52,10 @@ func SetObjectDefaults_ElasticQuotaArgs(in *ElasticQuotaArgs) {
 	SetDefaults_ElasticQuotaArgs(in)
 }
 
+func SetObjectDefaults_InterferenceAwareArgs(in *InterferenceAwareArgs) {
+	SetDefaults_InterferenceAwareArgs(in)
+}
+
 func SetObjectDefaults_LoadAwareSchedulingArgs(in *LoadAwareSchedulingArgs) {
 	SetDefaults_LoadAwareSchedulingArgs(in)
 }
@@ -61,3 +67,7 @@ func SetObjectDefaults_NodeNUMAResourceArgs(in *NodeNUMAResourceArgs) {
 func SetObjectDefaults_ReservationArgs(in *ReservationArgs) {
 	SetDefaults_ReservationArgs(in)
 }
+
+func SetObjectDefaults_SchedulerCoexistenceArgs(in *SchedulerCoexistenceArgs) {
+	SetDefaults_SchedulerCoexistenceArgs(in)
+}
@@ -52,12 +52,39 @@ func ValidateLoadAwareSchedulingArgs(args *config.LoadAwareSchedulingArgs) error
 		}
 	}
 
+	if args.StaleNodeMetricPolicy != nil {
+		allErrs = append(allErrs, validateStaleNodeMetricPolicy(args.StaleNodeMetricPolicy, field.NewPath("staleNodeMetricPolicy"))...)
+	}
+	if args.ThermalThrottledPenaltyScorePercent < 0 || args.ThermalThrottledPenaltyScorePercent > 100 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("thermalThrottledPenaltyScorePercent"), args.ThermalThrottledPenaltyScorePercent, "thermalThrottledPenaltyScorePercent not in valid range [0, 100]"))
+	}
+	if args.MemoryBandwidthUsageThresholdPercent < 0 || args.MemoryBandwidthUsageThresholdPercent > 100 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("memoryBandwidthUsageThresholdPercent"), args.MemoryBandwidthUsageThresholdPercent, "memoryBandwidthUsageThresholdPercent not in valid range [0, 100]"))
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return allErrs.ToAggregate()
 }
 
+func validateStaleNodeMetricPolicy(policy *config.LoadAwareStaleNodeMetricPolicy, p *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if policy.StaleReportIntervals <= 0 {
+		allErrs = append(allErrs, field.Invalid(p.Child("staleReportIntervals"), policy.StaleReportIntervals, "staleReportIntervals should be a positive value"))
+	}
+	switch policy.Action {
+	case config.StaleNodeMetricActionRequestBased, config.StaleNodeMetricActionPenalty, config.StaleNodeMetricActionFilter:
+	default:
+		allErrs = append(allErrs, field.NotSupported(p.Child("action"), policy.Action,
+			[]string{string(config.StaleNodeMetricActionRequestBased), string(config.StaleNodeMetricActionPenalty), string(config.StaleNodeMetricActionFilter)}))
+	}
+	if policy.PenaltyScorePercent < 0 || policy.PenaltyScorePercent > 100 {
+		allErrs = append(allErrs, field.Invalid(p.Child("penaltyScorePercent"), policy.PenaltyScorePercent, "penaltyScorePercent not in valid range [0, 100]"))
+	}
+	return allErrs
+}
+
 func validateResourceWeights(resources map[corev1.ResourceName]int64) error {
 	for resourceName, weight := range resources {
 		if weight <= 0 {
@@ -117,6 +144,10 @@ func ValidateElasticQuotaArgs(elasticArgs *config.ElasticQuotaArgs) error {
 		return fmt.Errorf("elasticQuotaArgs error, RevokePodCycle should be a positive value")
 	}
 
+	if elasticArgs.PreemptionImmunityDuration.Duration < 0 {
+		return fmt.Errorf("elasticQuotaArgs error, PreemptionImmunityDuration should be a positive value")
+	}
+
 	return nil
 }
 
@@ -127,6 +158,11 @@ func ValidateCoschedulingArgs(coeSchedulingArgs *config.CoschedulingArgs) error
 	if coeSchedulingArgs.ControllerWorkers < 1 {
 		return fmt.Errorf("coeSchedulingArgs ControllerWorkers invalid")
 	}
+	switch coeSchedulingArgs.QueueSortPolicy {
+	case "", config.GangQueueSortPolicyDefault, config.GangQueueSortPolicyQuotaFairShare:
+	default:
+		return fmt.Errorf("coeSchedulingArgs QueueSortPolicy %s not supported", coeSchedulingArgs.QueueSortPolicy)
+	}
 	return nil
 }
 
@@ -146,6 +182,12 @@ func ValidateDeviceShareArgs(path *field.Path, args *config.DeviceShareArgs) err
 	if args.ScoringStrategy != nil {
 		allErrs = append(allErrs, validateResources(args.ScoringStrategy.Resources, path.Child("resources"))...)
 	}
+	switch args.PCIeSpreadPolicy {
+	case "", config.PCIeSpreadPolicyNone, config.PCIeSpreadPolicySpreadByRootComplex:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("pcieSpreadPolicy"), args.PCIeSpreadPolicy,
+			[]string{config.PCIeSpreadPolicyNone, config.PCIeSpreadPolicySpreadByRootComplex}))
+	}
 
 	if len(allErrs) == 0 {
 		return nil
@@ -198,6 +240,46 @@ func ValidateNodeNUMAResourceArgs(path *field.Path, args *config.NodeNUMAResourc
 		allErrs = append(allErrs, validateResources(args.ScoringStrategy.Resources, path.Child("resources"))...)
 	}
 
+	if args.ZoneMemoryScoringWeight < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("zoneMemoryScoringWeight"), args.ZoneMemoryScoringWeight, "zoneMemoryScoringWeight should be a non-negative value"))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+func ValidateSchedulerCoexistenceArgs(path *field.Path, args *config.SchedulerCoexistenceArgs) error {
+	var allErrs field.ErrorList
+	if len(args.KoordSchedulerNames) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("koordSchedulerNames"), "at least one scheduler name must be specified"))
+	}
+	switch args.ConflictPolicy {
+	case config.CoexistenceConflictPolicyFlag, config.CoexistenceConflictPolicyReject:
+	default:
+		allErrs = append(allErrs, field.NotSupported(path.Child("conflictPolicy"), args.ConflictPolicy,
+			[]string{config.CoexistenceConflictPolicyFlag, config.CoexistenceConflictPolicyReject}))
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return allErrs.ToAggregate()
+}
+
+// ValidateInterferenceAwareArgs validates that InterferenceAwareArgs are correct.
+func ValidateInterferenceAwareArgs(path *field.Path, args *config.InterferenceAwareArgs) error {
+	var allErrs field.ErrorList
+	for sensitivity, risk := range args.SensitivityRisks {
+		if risk < 0 || risk > 100 {
+			allErrs = append(allErrs, field.Invalid(path.Child("sensitivityRisks").Key(string(sensitivity)), risk, "risk not in valid range [0, 100]"))
+		}
+	}
+	if args.ContentionWeight != nil && (*args.ContentionWeight < 0 || *args.ContentionWeight > 100) {
+		allErrs = append(allErrs, field.Invalid(path.Child("contentionWeight"), *args.ContentionWeight, "contentionWeight not in valid range [0, 100]"))
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -34,6 +34,7 @@ import (
 	koordinatorclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/schedulingphase"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
@@ -278,10 +279,18 @@ func (ext *frameworkExtenderImpl) RunScorePlugins(ctx context.Context, state *fr
 	return pluginToNodeScores, status
 }
 
+// RunPostFilterPlugins records a pod event explaining why the preemption does not help if the PostFilter fails.
 func (ext *frameworkExtenderImpl) RunPostFilterPlugins(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (_ *framework.PostFilterResult, status *framework.Status) {
 	schedulingphase.RecordPhase(state, schedulingphase.PostFilter)
+	preemptionexplanation.Init(state)
 	defer func() { schedulingphase.RecordPhase(state, "") }()
-	return ext.Framework.RunPostFilterPlugins(ctx, state, pod, filteredNodeStatusMap)
+	result, status := ext.Framework.RunPostFilterPlugins(ctx, state, pod, filteredNodeStatusMap)
+	if !status.IsSuccess() && !reservationutil.IsReservePod(pod) && ext.EventRecorder() != nil {
+		if message := preemptionexplanation.Message(state); message != "" {
+			ext.EventRecorder().Eventf(pod, nil, corev1.EventTypeWarning, preemptionexplanation.EventReason, preemptionexplanation.EventAction, message)
+		}
+	}
+	return result, status
 }
 
 // RunPreBindPlugins supports PreBindReservation for Reservation
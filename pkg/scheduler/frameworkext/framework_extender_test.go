@@ -28,7 +28,9 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	schedulerconfig "k8s.io/kubernetes/pkg/scheduler/apis/config"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	frameworkfake "k8s.io/kubernetes/pkg/scheduler/framework/fake"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
@@ -39,6 +41,7 @@ import (
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
 
@@ -1070,3 +1073,82 @@ func TestReservationScorePlugin(t *testing.T) {
 		})
 	}
 }
+
+var _ framework.PostFilterPlugin = &testPostFilterPlugin{}
+
+type testPostFilterPlugin struct {
+	status *framework.Status
+}
+
+func (p *testPostFilterPlugin) Name() string { return "testPostFilterPlugin" }
+
+func (p *testPostFilterPlugin) PostFilter(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, filteredNodeStatusMap framework.NodeToStatusMap) (*framework.PostFilterResult, *framework.Status) {
+	preemptionexplanation.RecordConsidered(state, p.Name())
+	if !p.status.IsSuccess() {
+		preemptionexplanation.RecordFailure(state, p.Name(), preemptionexplanation.ReasonQuotaGuard, p.status.Message())
+		return nil, p.status
+	}
+	return &framework.PostFilterResult{NominatingInfo: &framework.NominatingInfo{NominatedNodeName: "test-node"}}, p.status
+}
+
+func Test_frameworkExtenderImpl_RunPostFilterPlugins(t *testing.T) {
+	tests := []struct {
+		name      string
+		pod       *corev1.Pod
+		status    *framework.Status
+		wantEvent string
+	}{
+		{
+			name:   "preemption succeeded",
+			pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+			status: framework.NewStatus(framework.Success),
+		},
+		{
+			name:   "preemption failed",
+			pod:    &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-pod"}},
+			status: framework.NewStatus(framework.Unschedulable, "no victims"),
+			wantEvent: "Warning PreemptionExplanation preemption considered: true (testPostFilterPlugin); evaluated victims (0); " +
+				"failures: [testPostFilterPlugin/QuotaGuard: no victims]",
+		},
+		{
+			name: "skip reserve pod",
+			pod: reservationutil.NewReservePod(&schedulingv1alpha1.Reservation{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-r", UID: "123"},
+				Spec:       schedulingv1alpha1.ReservationSpec{Template: &corev1.PodTemplateSpec{}},
+			}),
+			status: framework.NewStatus(framework.Unschedulable, "no victims"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extenderFactory, _ := NewFrameworkExtenderFactory()
+			registeredPlugins := []schedulertesting.RegisterPluginFunc{
+				schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+				func(reg *frameworkruntime.Registry, profile *schedulerconfig.KubeSchedulerProfile) {
+					_ = reg.Register("testPostFilterPlugin", func(_ runtime.Object, _ framework.Handle) (framework.Plugin, error) {
+						return &testPostFilterPlugin{status: tt.status}, nil
+					})
+					profile.Plugins.PostFilter.Enabled = append(profile.Plugins.PostFilter.Enabled, schedulerconfig.Plugin{Name: "testPostFilterPlugin"})
+				},
+			}
+			fakeRecorder := record.NewFakeRecorder(1024)
+			fh, err := schedulertesting.NewFramework(
+				context.TODO(),
+				registeredPlugins,
+				"koord-scheduler",
+				frameworkruntime.WithEventRecorder(record.NewEventRecorderAdapter(fakeRecorder)),
+			)
+			assert.NoError(t, err)
+			frameworkExtender := extenderFactory.NewFrameworkExtender(fh)
+			_, status := frameworkExtender.RunPostFilterPlugins(context.TODO(), framework.NewCycleState(), tt.pod, framework.NodeToStatusMap{})
+			assert.Equal(t, tt.status.IsSuccess(), status.IsSuccess())
+			var gotEvent string
+			select {
+			case gotEvent = <-fakeRecorder.Events:
+			default:
+			}
+			assert.Equal(t, tt.wantEvent, gotEvent)
+		})
+	}
+}
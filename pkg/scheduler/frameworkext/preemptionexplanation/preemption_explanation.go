@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionexplanation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+const (
+	stateKey = extension.SchedulingDomainPrefix + "/preemption-explanation"
+
+	// EventReason is the reason of the pod event which explains a failed PostFilter.
+	EventReason = "PreemptionExplanation"
+	// EventAction is the action of the pod event which explains a failed PostFilter.
+	EventAction = "Preempting"
+
+	maxVictimsInMessage = 10
+)
+
+const (
+	// ReasonQuotaGuard means the potential victims are guarded by the quotas, e.g. they belong to other quotas.
+	ReasonQuotaGuard = "QuotaGuard"
+	// ReasonGangProtection means the gang is rejected as a whole rather than preempting for one member.
+	ReasonGangProtection = "GangProtection"
+	// ReasonReservation means the pod is unschedulable due to the reservations.
+	ReasonReservation = "Reservation"
+	// ReasonNonPreemptible means the potential victims are marked as non-preemptible.
+	ReasonNonPreemptible = "NonPreemptible"
+	// ReasonPreemptionFailed means the preemption evaluator finds no candidate.
+	ReasonPreemptionFailed = "PreemptionFailed"
+)
+
+type failureKey struct {
+	plugin string
+	reason string
+}
+
+type failure struct {
+	message string
+	count   int
+}
+
+// Explanation collects why the preemption of the PostFilter plugins does not help the pod.
+// It is shared by the cycle states cloned in the dry-run preemption, so it must be concurrent-safe.
+type Explanation struct {
+	lock        sync.Mutex
+	considered  []string
+	victims     sets.String
+	failureKeys []failureKey
+	failures    map[failureKey]*failure
+}
+
+func (e *Explanation) Clone() framework.StateData {
+	return e
+}
+
+// Init resets the explanation of the cycle state. The explanations are only recorded after Init.
+func Init(cycleState *framework.CycleState) {
+	cycleState.Write(stateKey, &Explanation{
+		victims:  sets.NewString(),
+		failures: map[failureKey]*failure{},
+	})
+}
+
+func getExplanation(cycleState *framework.CycleState) *Explanation {
+	if cycleState == nil {
+		return nil
+	}
+	s, err := cycleState.Read(stateKey)
+	if err != nil || s == nil {
+		return nil
+	}
+	e, _ := s.(*Explanation)
+	return e
+}
+
+// RecordConsidered records the plugin has tried the preemption for the pod.
+func RecordConsidered(cycleState *framework.CycleState, pluginName string) {
+	e := getExplanation(cycleState)
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, name := range e.considered {
+		if name == pluginName {
+			return
+		}
+	}
+	e.considered = append(e.considered, pluginName)
+}
+
+// RecordVictims records the potential victims evaluated for the pod.
+func RecordVictims(cycleState *framework.CycleState, podInfos []*framework.PodInfo) {
+	e := getExplanation(cycleState)
+	if e == nil || len(podInfos) == 0 {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, pi := range podInfos {
+		if pi == nil || pi.Pod == nil {
+			continue
+		}
+		e.victims.Insert(fmt.Sprintf("%s/%s", pi.Pod.Namespace, pi.Pod.Name))
+	}
+}
+
+// RecordFailure records why the preemption of the plugin fails. The failures of the same plugin and reason are
+// aggregated, e.g. the failures per node, and only the first message is kept.
+func RecordFailure(cycleState *framework.CycleState, pluginName, reason, message string) {
+	e := getExplanation(cycleState)
+	if e == nil {
+		return
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	key := failureKey{plugin: pluginName, reason: reason}
+	if f := e.failures[key]; f != nil {
+		f.count++
+		return
+	}
+	e.failureKeys = append(e.failureKeys, key)
+	e.failures[key] = &failure{message: message, count: 1}
+}
+
+// Message returns the explanation in a structured message. It returns empty if nothing is recorded.
+func Message(cycleState *framework.CycleState) string {
+	e := getExplanation(cycleState)
+	if e == nil {
+		return ""
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.considered) == 0 && len(e.failureKeys) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if len(e.considered) > 0 {
+		fmt.Fprintf(&sb, "preemption considered: true (%s)", strings.Join(e.considered, ", "))
+	} else {
+		sb.WriteString("preemption considered: false")
+	}
+
+	victims := e.victims.List()
+	fmt.Fprintf(&sb, "; evaluated victims (%d)", len(victims))
+	if len(victims) > 0 {
+		if len(victims) > maxVictimsInMessage {
+			victims = append(victims[:maxVictimsInMessage], "...")
+		}
+		fmt.Fprintf(&sb, ": %s", strings.Join(victims, ", "))
+	}
+
+	if len(e.failureKeys) > 0 {
+		failures := make([]string, 0, len(e.failureKeys))
+		for _, key := range e.failureKeys {
+			f := e.failures[key]
+			s := fmt.Sprintf("[%s/%s", key.plugin, key.reason)
+			if f.count > 1 {
+				s += fmt.Sprintf(" (x%d)", f.count)
+			}
+			if f.message != "" {
+				s += ": " + f.message
+			}
+			s += "]"
+			failures = append(failures, s)
+		}
+		fmt.Fprintf(&sb, "; failures: %s", strings.Join(failures, " "))
+	}
+	return sb.String()
+}
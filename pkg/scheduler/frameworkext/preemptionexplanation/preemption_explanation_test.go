@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preemptionexplanation
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+)
+
+func TestExplanation(t *testing.T) {
+	cycleState := framework.NewCycleState()
+	// nothing is recorded before Init
+	RecordConsidered(cycleState, "ElasticQuota")
+	assert.Equal(t, "", Message(cycleState))
+
+	Init(cycleState)
+	assert.Equal(t, "", Message(cycleState))
+
+	RecordConsidered(cycleState, "ElasticQuota")
+	RecordConsidered(cycleState, "ElasticQuota")
+	// the cloned states share the explanation, e.g. in the dry-run preemption
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(stateCopy *framework.CycleState) {
+			defer wg.Done()
+			RecordVictims(stateCopy, []*framework.PodInfo{
+				{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "victim"}}},
+			})
+			RecordFailure(stateCopy, "ElasticQuota", ReasonQuotaGuard, "the lower priority pods belong to other quotas")
+		}(cycleState.Clone())
+	}
+	wg.Wait()
+	RecordFailure(cycleState, "Coscheduling", ReasonGangProtection, "Gang \"default/gang\" gets rejected")
+	assert.Equal(t, "preemption considered: true (ElasticQuota); evaluated victims (1): default/victim; "+
+		"failures: [ElasticQuota/QuotaGuard (x3): the lower priority pods belong to other quotas] "+
+		"[Coscheduling/GangProtection: Gang \"default/gang\" gets rejected]", Message(cycleState))
+
+	Init(cycleState)
+	RecordFailure(cycleState, "Reservation", ReasonReservation, "1 Reservation(s) didn't match affinity rules")
+	assert.Equal(t, "preemption considered: false; evaluated victims (0); "+
+		"failures: [Reservation/Reservation: 1 Reservation(s) didn't match affinity rules]", Message(cycleState))
+}
+
+func TestExplanationTruncateVictims(t *testing.T) {
+	cycleState := framework.NewCycleState()
+	Init(cycleState)
+	RecordConsidered(cycleState, "Reservation")
+	var podInfos []*framework.PodInfo
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		podInfos = append(podInfos, &framework.PodInfo{Pod: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: name}}})
+	}
+	RecordVictims(cycleState, podInfos)
+	assert.Equal(t, "preemption considered: true (Reservation); evaluated victims (12): "+
+		"ns/a, ns/b, ns/c, ns/d, ns/e, ns/f, ns/g, ns/h, ns/i, ns/j, ...", Message(cycleState))
+}
@@ -63,10 +63,17 @@ var (
 			Name:      "secondary_device_not_well_planned",
 			Help:      "The number of secondary device not well planned",
 		}, []string{NodeNameKey}))
+	LoadAwareNodeMetricState = metrics.NewGaugeVec(
+		&metrics.GaugeOpts{
+			Subsystem: schedulermetrics.SchedulerSubsystem,
+			Name:      "load_aware_node_metric_state",
+			Help:      "The number of nodes observed by LoadAware in each NodeMetric state (e.g. fresh, stale, expired)",
+		}, []string{"state"})
 
 	metricsList = []metrics.Registerable{
 		SchedulingTimeout,
 		ElasticQuotaProcessLatency,
+		LoadAwareNodeMetricState,
 	}
 
 	gcMetricsList = []prometheus.Collector{
@@ -123,3 +130,7 @@ func RecordElasticQuotaProcessLatency(operation string, latency time.Duration) {
 func RecordSecondaryDeviceNotWellPlanned(nodeName string) {
 	SecondaryDeviceNotWellPlannedNodes.WithSet(prometheus.Labels{NodeNameKey: nodeName}, 1.0)
 }
+
+func RecordLoadAwareNodeMetricState(state string, count int) {
+	LoadAwareNodeMetricState.WithLabelValues(state).Set(float64(count))
+}
@@ -41,6 +41,7 @@ import (
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
@@ -78,6 +79,7 @@ type Manager interface {
 	GetChildScheduleCycle(*corev1.Pod) int
 	GetLastScheduleTime(*corev1.Pod, time.Time) time.Time
 	GetBoundPodNumber(gangId string) int32
+	GetGangQuotaRank(*corev1.Pod) int
 }
 
 // PodGroupManager defines the scheduling operation called
@@ -93,6 +95,8 @@ type PodGroupManager struct {
 	reserveResourcePercentage int32
 	// cache stores gang info
 	cache *GangCache
+	// quotaRanker ranks the gangs in their quotas for the QuotaFairShare queue sort policy
+	quotaRanker *gangQuotaRanker
 	sync.RWMutex
 }
 
@@ -115,18 +119,27 @@ func NewPodGroupManager(
 		cache:     gangCache,
 	}
 
-	podGroupEventHandler := cache.ResourceEventHandlerFuncs{
+	var podGroupEventHandler cache.ResourceEventHandler = cache.ResourceEventHandlerFuncs{
 		AddFunc:    gangCache.onPodGroupAdd,
 		UpdateFunc: gangCache.onPodGroupUpdate,
 		DeleteFunc: gangCache.onPodGroupDelete,
 	}
-	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), pgSharedInformerFactory, pgInformer.Informer(), podGroupEventHandler)
-
-	podEventHandler := cache.ResourceEventHandlerFuncs{
+	var podEventHandler cache.ResourceEventHandler = cache.ResourceEventHandlerFuncs{
 		AddFunc:    gangCache.onPodAdd,
 		UpdateFunc: gangCache.onPodUpdate,
 		DeleteFunc: gangCache.onPodDelete,
 	}
+	if args.QueueSortPolicy == config.GangQueueSortPolicyQuotaFairShare {
+		quotaInformer := pgSharedInformerFactory.Scheduling().V1alpha1().ElasticQuotas()
+		pgMgr.quotaRanker = newGangQuotaRanker(gangCache, quotaInformer.Lister())
+		podGroupEventHandler = newRankRefreshEventHandler(podGroupEventHandler, pgMgr.quotaRanker)
+		podEventHandler = newRankRefreshEventHandler(podEventHandler, pgMgr.quotaRanker)
+		frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), pgSharedInformerFactory, quotaInformer.Informer(),
+			newRankRefreshEventHandler(cache.ResourceEventHandlerFuncs{}, pgMgr.quotaRanker))
+		go pgMgr.quotaRanker.run(context.TODO().Done())
+	}
+
+	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), pgSharedInformerFactory, pgInformer.Informer(), podGroupEventHandler)
 	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), sharedInformerFactory, podInformer.Informer(), podEventHandler)
 	reservationInformer := koordSharedInformerFactory.Scheduling().V1alpha1().Reservations()
 	reservationEventHandler := reservationutil.NewReservationToPodEventHandler(podEventHandler)
@@ -382,6 +395,8 @@ func (pgMgr *PodGroupManager) PostFilter(ctx context.Context, state *framework.C
 		message := fmt.Sprintf("Gang %q gets rejected due to member Pod %q is unschedulable with reason %q", gang.Name, pod.Name, fitErr)
 
 		pgMgr.rejectGangGroupById(handle, preFilterState != nil && preFilterState.skipSetCycleInvalid, pluginName, gang.Name, message)
+		preemptionexplanation.RecordFailure(state, pluginName, preemptionexplanation.ReasonGangProtection,
+			fmt.Sprintf("Gang %q gets rejected as a whole due to member pod is unschedulable", gang.Name))
 		return &framework.PostFilterResult{}, framework.NewStatus(framework.Unschedulable,
 			fmt.Sprintf("Gang %q gets rejected due to pod is unschedulable", gang.Name))
 	}
@@ -606,3 +621,16 @@ func (pgMgr *PodGroupManager) GetBoundPodNumber(gangId string) int32 {
 	}
 	return gang.getBoundPodNum()
 }
+
+// GetGangQuotaRank returns the rank of the gang group of the pod among the unsatisfied gang groups of the same quota.
+// It returns 0 if the pod does not belong to a gang.
+func (pgMgr *PodGroupManager) GetGangQuotaRank(pod *corev1.Pod) int {
+	gang := pgMgr.GetGangByPod(pod)
+	if gang == nil {
+		return 0
+	}
+	if pgMgr.quotaRanker == nil {
+		return 0
+	}
+	return pgMgr.quotaRanker.getRank(gang.GangGroupId)
+}
@@ -0,0 +1,267 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	pglister "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+	quotacore "github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+// quotaRankRefreshInterval is the interval to refresh the ranks besides the refreshing on the events, since the
+// satisfaction of the gangs is changed without any event.
+const quotaRankRefreshInterval = time.Second
+
+// gangQuotaRanker ranks the gang groups which are not yet satisfied by the weighted fair share of their quotas, so
+// that the scheduling queue pops the gangs of the quota which uses the least of its min first. The share of a gang
+// group is the dominant share of its quota after the gang group and the gang groups ahead in the same quota are
+// scheduled, i.e. the max of (used + the requests) / min of the resources in the min. The gang groups of the quota
+// which is not found or has no min are regarded as each taking up the whole min.
+// The quota of a gang is the quota label of its pods, and is the namespace if the label is missing.
+// The ranks are refreshed on the events and periodically rather than in the comparisons of the scheduling queue,
+// and every gang group has a distinct rank ordered by the share, the create time and the id.
+type gangQuotaRanker struct {
+	cache       *GangCache
+	quotaLister pglister.ElasticQuotaLister
+	refreshCh   chan struct{}
+
+	lock  sync.RWMutex
+	ranks map[string]int
+}
+
+func newGangQuotaRanker(cache *GangCache, quotaLister pglister.ElasticQuotaLister) *gangQuotaRanker {
+	return &gangQuotaRanker{
+		cache:       cache,
+		quotaLister: quotaLister,
+		refreshCh:   make(chan struct{}, 1),
+		ranks:       map[string]int{},
+	}
+}
+
+// getRank returns the rank of the gang group, it returns 0 if the gang group is not ranked.
+func (r *gangQuotaRanker) getRank(gangGroupId string) int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.ranks[gangGroupId]
+}
+
+// requestRefresh triggers the refreshing of the ranks asynchronously.
+func (r *gangQuotaRanker) requestRefresh() {
+	select {
+	case r.refreshCh <- struct{}{}:
+	default:
+	}
+}
+
+func (r *gangQuotaRanker) run(stopCh <-chan struct{}) {
+	ticker := time.NewTicker(quotaRankRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-r.refreshCh:
+		case <-ticker.C:
+		}
+		r.refresh()
+	}
+}
+
+func (r *gangQuotaRanker) refresh() {
+	ranks := r.buildRanks()
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.ranks = ranks
+}
+
+// rankRefreshEventHandler requests the refreshing of the ranks after handling the events of the pods, the pod groups
+// and the quotas.
+type rankRefreshEventHandler struct {
+	handler cache.ResourceEventHandler
+	ranker  *gangQuotaRanker
+}
+
+func newRankRefreshEventHandler(handler cache.ResourceEventHandler, ranker *gangQuotaRanker) cache.ResourceEventHandler {
+	return &rankRefreshEventHandler{handler: handler, ranker: ranker}
+}
+
+func (h *rankRefreshEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.handler.OnAdd(obj, isInInitialList)
+	h.ranker.requestRefresh()
+}
+
+func (h *rankRefreshEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.handler.OnUpdate(oldObj, newObj)
+	h.ranker.requestRefresh()
+}
+
+func (h *rankRefreshEventHandler) OnDelete(obj interface{}) {
+	h.handler.OnDelete(obj)
+	h.ranker.requestRefresh()
+}
+
+type rankedGangGroup struct {
+	id         string
+	quota      string
+	createTime time.Time
+	request    corev1.ResourceList
+	share      float64
+}
+
+func (r *gangQuotaRanker) buildRanks() map[string]int {
+	gangGroups := map[string]*rankedGangGroup{}
+	for _, gang := range r.cache.getAllGangsFromCache() {
+		if gang.isGangOnceResourceSatisfied() {
+			continue
+		}
+		quota, ok := gang.getQuotaName()
+		if !ok {
+			continue
+		}
+		createTime := gang.getCreateTime()
+		request := gang.getChildrenRequest()
+		group, ok := gangGroups[gang.GangGroupId]
+		if !ok {
+			gangGroups[gang.GangGroupId] = &rankedGangGroup{id: gang.GangGroupId, quota: quota, createTime: createTime, request: request}
+			continue
+		}
+		group.request = quotav1.Add(group.request, request)
+		// the gang group is ranked in the quota of its earliest gang
+		if createTime.Before(group.createTime) {
+			group.quota = quota
+			group.createTime = createTime
+		}
+	}
+
+	quotaGangGroups := map[string][]*rankedGangGroup{}
+	for _, group := range gangGroups {
+		quotaGangGroups[group.quota] = append(quotaGangGroups[group.quota], group)
+	}
+	quotas := r.getQuotas()
+	sortedGroups := make([]*rankedGangGroup, 0, len(gangGroups))
+	for quotaName, groups := range quotaGangGroups {
+		sort.Slice(groups, func(i, j int) bool {
+			return lessRankedGangGroup(groups[i], groups[j])
+		})
+		quota := quotas[quotaName]
+		used := quota.used
+		for i, group := range groups {
+			used = quotav1.Add(used, group.request)
+			group.share = getDominantShare(used, quota.min, i+1)
+		}
+		sortedGroups = append(sortedGroups, groups...)
+	}
+	sort.Slice(sortedGroups, func(i, j int) bool {
+		if sortedGroups[i].share != sortedGroups[j].share {
+			return sortedGroups[i].share < sortedGroups[j].share
+		}
+		return lessRankedGangGroup(sortedGroups[i], sortedGroups[j])
+	})
+	ranks := make(map[string]int, len(sortedGroups))
+	for i, group := range sortedGroups {
+		ranks[group.id] = i
+	}
+	return ranks
+}
+
+func lessRankedGangGroup(a, b *rankedGangGroup) bool {
+	if !a.createTime.Equal(b.createTime) {
+		return a.createTime.Before(b.createTime)
+	}
+	return a.id < b.id
+}
+
+type quotaShare struct {
+	min  corev1.ResourceList
+	used corev1.ResourceList
+}
+
+func (r *gangQuotaRanker) getQuotas() map[string]quotaShare {
+	quotas := map[string]quotaShare{}
+	if r.quotaLister == nil {
+		return quotas
+	}
+	elasticQuotas, err := r.quotaLister.List(labels.Everything())
+	if err != nil {
+		klog.V(4).ErrorS(err, "failed to list elastic quotas for the gang quota ranks")
+		return quotas
+	}
+	for _, quota := range elasticQuotas {
+		quotas[quota.Name] = quotaShare{min: quota.Spec.Min, used: quota.Status.Used}
+	}
+	return quotas
+}
+
+// getDominantShare returns the max share of the used in the min. It returns the count of the gang groups if there
+// is no positive min.
+func getDominantShare(used, min corev1.ResourceList, count int) float64 {
+	share, found := 0.0, false
+	for resourceName, minQuantity := range min {
+		if minQuantity.MilliValue() <= 0 {
+			continue
+		}
+		usedQuantity := used[resourceName]
+		share = math.Max(share, float64(usedQuantity.MilliValue())/float64(minQuantity.MilliValue()))
+		found = true
+	}
+	if !found {
+		return float64(count)
+	}
+	return share
+}
+
+// getChildrenRequest returns the total requests of the children of the gang which are not yet assigned, since the
+// assigned ones are counted in the used of the quota.
+func (gang *Gang) getChildrenRequest() corev1.ResourceList {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	request := corev1.ResourceList{}
+	for _, pod := range gang.Children {
+		if pod.Spec.NodeName != "" {
+			continue
+		}
+		request = quotav1.Add(request, quotacore.PodRequests(pod))
+	}
+	return request
+}
+
+// getQuotaName returns the quota of the gang by its children, it returns false if the gang has no child.
+func (gang *Gang) getQuotaName() (string, bool) {
+	gang.lock.Lock()
+	defer gang.lock.Unlock()
+
+	namespace := ""
+	for _, pod := range gang.Children {
+		if quotaName := extension.GetQuotaName(pod); quotaName != "" {
+			return quotaName, true
+		}
+		namespace = pod.Namespace
+	}
+	return namespace, namespace != ""
+}
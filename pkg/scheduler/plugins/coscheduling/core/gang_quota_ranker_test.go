@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8scache "k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+	pglister "github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/generated/listers/scheduling/v1alpha1"
+)
+
+func TestGangQuotaRanker(t *testing.T) {
+	now := time.Now()
+	cache := NewGangCache(getTestDefaultCoschedulingArgs(t), nil, nil, nil)
+	addGang := func(name, quota string, createTime time.Time) *Gang {
+		gang := NewGang("default/" + name)
+		gang.CreateTime = createTime
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name + "-pod",
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+						},
+					},
+				},
+			},
+		}
+		if quota != "" {
+			pod.Labels = map[string]string{extension.LabelQuotaName: quota}
+		}
+		gang.setChild(pod)
+		cache.gangItems[gang.Name] = gang
+		return gang
+	}
+	addGang("a1", "quota-a", now.Add(-5*time.Minute))
+	addGang("a2", "quota-a", now.Add(-4*time.Minute))
+	addGang("a3", "quota-a", now.Add(-3*time.Minute))
+	addGang("b1", "quota-b", now.Add(-2*time.Minute))
+	addGang("b2", "quota-b", now.Add(-1*time.Minute))
+	// the gang without quota label is ranked in the quota of its namespace, which is not found
+	addGang("c1", "", now.Add(-1*time.Minute))
+	satisfied := addGang("a0", "quota-a", now.Add(-10*time.Minute))
+	satisfied.GangGroupInfo.setResourceSatisfied()
+
+	indexer := k8scache.NewIndexer(k8scache.MetaNamespaceKeyFunc, k8scache.Indexers{})
+	for _, quota := range []*v1alpha1.ElasticQuota{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quota-a"},
+			Spec:       v1alpha1.ElasticQuotaSpec{Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "quota-b"},
+			Spec:       v1alpha1.ElasticQuotaSpec{Min: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10")}},
+			Status:     v1alpha1.ElasticQuotaStatus{Used: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")}},
+		},
+	} {
+		assert.NoError(t, indexer.Add(quota))
+	}
+
+	ranker := newGangQuotaRanker(cache, pglister.NewElasticQuotaLister(indexer))
+	ranker.refresh()
+	// the shares are a1: 0.2, a2: 0.4, a3: 0.6, b1: 0.8, b2: 1.0 and c1: 1
+	assert.Equal(t, 0, ranker.getRank("default/a1"))
+	assert.Equal(t, 1, ranker.getRank("default/a2"))
+	assert.Equal(t, 2, ranker.getRank("default/a3"))
+	assert.Equal(t, 3, ranker.getRank("default/b1"))
+	assert.Equal(t, 4, ranker.getRank("default/b2"))
+	assert.Equal(t, 5, ranker.getRank("default/c1"))
+	assert.Equal(t, 0, ranker.getRank("default/a0"))
+
+	// the ranks are not refreshed on the lookups
+	cache.gangItems["default/a1"].GangGroupInfo.setResourceSatisfied()
+	assert.Equal(t, 1, ranker.getRank("default/a2"))
+
+	// the ranks are refreshed on the events
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go ranker.run(stopCh)
+	handler := newRankRefreshEventHandler(k8scache.ResourceEventHandlerFuncs{}, ranker)
+	handler.OnUpdate(nil, nil)
+	assert.Eventually(t, func() bool {
+		return ranker.getRank("default/a2") == 0 && ranker.getRank("default/a3") == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func Test_getDominantShare(t *testing.T) {
+	min := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("10"),
+		corev1.ResourceMemory: resource.MustParse("10Gi"),
+	}
+	used := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("2"),
+		corev1.ResourceMemory: resource.MustParse("5Gi"),
+	}
+	assert.Equal(t, 0.5, getDominantShare(used, min, 1))
+	assert.Equal(t, float64(2), getDominantShare(used, nil, 2))
+}
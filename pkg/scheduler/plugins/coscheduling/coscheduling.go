@@ -125,6 +125,7 @@ func (cs *Coscheduling) PreEnqueue(ctx context.Context, pod *v1.Pod) *framework.
 
 // Less is sorting pods in the scheduling queue in the following order.
 // Firstly, compare the priorities of the two pods, the higher priority (if pod's priority is equal,then compare their KoordinatorPriority at labels )is at the front of the queue,
+// If the QueueSortPolicy is QuotaFairShare, then compare the ranks of the gangs by the weighted fair share of their quotas,
 // Secondly, compare Gang group ID of the two pods, pods that NOT belong to a Gang will have higher priority than pods that belongs to a Gang,
 // Thirdly, compare the creationTimestamp of two pods, if pod belongs to a Gang, then we compare creationTimestamp of the Gang, the one created first will be at the front of the queue
 // Finally, compare pod's namespaced name.
@@ -146,6 +147,14 @@ func (cs *Coscheduling) Less(podInfo1, podInfo2 *framework.QueuedPodInfo) bool {
 		return subPrio1 > subPrio2
 	}
 
+	if cs.args.QueueSortPolicy == config.GangQueueSortPolicyQuotaFairShare {
+		rank1 := cs.pgMgr.GetGangQuotaRank(podInfo1.Pod)
+		rank2 := cs.pgMgr.GetGangQuotaRank(podInfo2.Pod)
+		if rank1 != rank2 {
+			return rank1 < rank2
+		}
+	}
+
 	lastScheduleTime1 := cs.pgMgr.GetLastScheduleTime(podInfo1.Pod, podInfo1.Timestamp)
 	lastScheduleTime2 := cs.pgMgr.GetLastScheduleTime(podInfo2.Pod, podInfo2.Timestamp)
 	if !lastScheduleTime1.Equal(lastScheduleTime2) {
@@ -30,6 +30,7 @@ import (
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	schedulerconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/schedulingphase"
 	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
 )
@@ -56,6 +57,7 @@ type requestContext struct {
 	required                  map[schedulingv1alpha1.DeviceType]sets.Int
 	preferred                 map[schedulingv1alpha1.DeviceType]sets.Int
 	allocationScorer          *resourceAllocationScorer
+	pcieSpreadPolicy          schedulerconfig.PCIeSpreadPolicy
 	nodeDevice                *nodeDevice
 }
 
@@ -66,6 +68,7 @@ type AutopilotAllocator struct {
 	node                      *corev1.Node
 	pod                       *corev1.Pod
 	scorer                    *resourceAllocationScorer
+	pcieSpreadPolicy          schedulerconfig.PCIeSpreadPolicy
 	numaNodes                 bitmask.BitMask
 	requestsPerInstance       map[schedulingv1alpha1.DeviceType]corev1.ResourceList
 	desiredCountPerDeviceType map[schedulingv1alpha1.DeviceType]int
@@ -111,6 +114,7 @@ func (a *AutopilotAllocator) Allocate(
 		requestsPerInstance:       a.requestsPerInstance,
 		desiredCountPerDeviceType: a.desiredCountPerDeviceType,
 		allocationScorer:          a.scorer,
+		pcieSpreadPolicy:          a.pcieSpreadPolicy,
 		required:                  required,
 		preferred:                 preferred,
 		nodeDevice:                a.nodeDevice,
@@ -148,6 +152,7 @@ func (a *AutopilotAllocator) filterNodeDevice(
 		deviceInfos := a.nodeDevice.deviceInfos[deviceType]
 		minors := sets.NewInt()
 		selector := a.state.hintSelectors[deviceType][0]
+		hint := a.state.hints[deviceType]
 		for _, deviceInfo := range deviceInfos {
 			// TODO if a.numaNodes == nil && selector == nil return all device of this deviceType
 			if a.numaNodes != nil {
@@ -155,6 +160,9 @@ func (a *AutopilotAllocator) filterNodeDevice(
 					continue
 				}
 			}
+			if !matchPinnedDevices(hint, deviceInfo) {
+				continue
+			}
 			if selector == nil || selector.Matches(labels.Set(deviceInfo.Labels)) {
 				minors.Insert(int(pointer.Int32Deref(deviceInfo.Minor, 0)))
 			}
@@ -372,6 +380,9 @@ func defaultAllocateDevices(
 
 	var allocations []*apiext.DeviceAllocation
 	resourceMinorPairs := scoreDevices(podRequestPerInstance, nodeDeviceTotal, freeDevices, requestCtx.allocationScorer)
+	if requestCtx.pcieSpreadPolicy == schedulerconfig.PCIeSpreadPolicySpreadByRootComplex && requestCtx.nodeDevice != nil {
+		resourceMinorPairs = fillPCIeAllocatedCount(resourceMinorPairs, requestCtx.nodeDevice.deviceUsed[deviceType], requestCtx.nodeDevice.deviceInfos[deviceType])
+	}
 	resourceMinorPairs = sortDeviceResourcesByPreferredPCIe(resourceMinorPairs, preferredPCIEs, deviceInfos)
 	// TODO Device allocation logic hotspots discovered through flame graphs
 	resourceMinorPairs = sortDeviceResourcesByMinor(resourceMinorPairs, requestCtx.preferred[deviceType])
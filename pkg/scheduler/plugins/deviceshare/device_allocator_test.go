@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -2488,3 +2489,195 @@ func Test_allocateRDMA(t *testing.T) {
 	}
 	assert.True(t, equality.Semantic.DeepEqual(expectAllocations, allocateResult[schedulingv1alpha1.RDMA]))
 }
+
+func TestAutopilotAllocatorWithPinnedDevices(t *testing.T) {
+	tests := []struct {
+		name      string
+		gpuWanted int
+		hint      *apiext.DeviceHint
+		want      []int32
+		wantErr   bool
+	}{
+		{
+			name:      "allocate pinned minors",
+			gpuWanted: 2,
+			hint:      &apiext.DeviceHint{Minors: []int32{5, 6}},
+			want:      []int32{5, 6},
+		},
+		{
+			name:      "allocate pinned NUMA node",
+			gpuWanted: 1,
+			hint:      &apiext.DeviceHint{NUMANodes: []int32{1}},
+			want:      []int32{4},
+		},
+		{
+			name:      "allocate pinned minors on NUMA node",
+			gpuWanted: 1,
+			hint:      &apiext.DeviceHint{Minors: []int32{1, 7}, NUMANodes: []int32{1}},
+			want:      []int32{7},
+		},
+		{
+			name:      "insufficient pinned minors",
+			gpuWanted: 2,
+			hint:      &apiext.DeviceHint{Minors: []int32{3}},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceCR := fakeDeviceCR.DeepCopy()
+			deviceCR.ResourceVersion = "1"
+			koordFakeClient := koordfake.NewSimpleClientset()
+			_, err := koordFakeClient.SchedulingV1alpha1().Devices().Create(context.TODO(), deviceCR, metav1.CreateOptions{})
+			assert.NoError(t, err)
+			koordShareInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordFakeClient, 0)
+			kubeFakeClient := kubefake.NewSimpleClientset(&corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+			})
+			sharedInformerFactory := informers.NewSharedInformerFactory(kubeFakeClient, 0)
+
+			deviceCache := newNodeDeviceCache()
+			registerDeviceEventHandler(deviceCache, koordShareInformerFactory)
+			registerPodEventHandler(deviceCache, sharedInformerFactory, koordShareInformerFactory)
+			sharedInformerFactory.Start(nil)
+			sharedInformerFactory.WaitForCacheSync(nil)
+
+			nodeDevice := deviceCache.getNodeDevice("test-node-1", false)
+			assert.NotNil(t, nodeDevice)
+
+			pod := &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									apiext.ResourceNvidiaGPU: *resource.NewQuantity(int64(tt.gpuWanted), resource.DecimalSI),
+								},
+							},
+						},
+					},
+				},
+			}
+			assert.NoError(t, apiext.SetDeviceAllocateHints(pod, apiext.DeviceAllocateHints{schedulingv1alpha1.GPU: tt.hint}))
+			state, status := preparePod(pod)
+			assert.True(t, status.IsSuccess())
+			assert.True(t, state.hasSelectors)
+
+			allocator := &AutopilotAllocator{
+				state:      state,
+				nodeDevice: nodeDevice,
+				node:       &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node-1"}},
+				pod:        pod,
+			}
+			allocations, status := allocator.Allocate(nil, nil, nil, nil)
+			if !status.IsSuccess() != tt.wantErr {
+				t.Errorf("Allocate() error = %v, wantErr %v", status.AsError(), tt.wantErr)
+				return
+			}
+			sortDeviceAllocations(allocations)
+			var minors []int32
+			for _, alloc := range allocations[schedulingv1alpha1.GPU] {
+				minors = append(minors, alloc.Minor)
+			}
+			assert.Equal(t, tt.want, minors)
+		})
+	}
+}
+
+func Test_allocateRDMAWithPCIeSpreadPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		pcieSpreadPolicy schedulerconfig.PCIeSpreadPolicy
+		wantMinor        int32
+	}{
+		{
+			name:             "allocate without pcie spread",
+			pcieSpreadPolicy: schedulerconfig.PCIeSpreadPolicyNone,
+			wantMinor:        2,
+		},
+		{
+			name:             "allocate with spreading by pcie root complex",
+			pcieSpreadPolicy: schedulerconfig.PCIeSpreadPolicySpreadByRootComplex,
+			wantMinor:        3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nd := newNodeDevice()
+			total := deviceResources{}
+			var deviceInfos []*schedulingv1alpha1.DeviceInfo
+			for minor := 1; minor <= 4; minor++ {
+				total[minor] = corev1.ResourceList{
+					apiext.ResourceRDMA: resource.MustParse("100"),
+				}
+				deviceInfos = append(deviceInfos, &schedulingv1alpha1.DeviceInfo{
+					Type:   schedulingv1alpha1.RDMA,
+					Health: true,
+					Minor:  pointer.Int32(int32(minor)),
+					Topology: &schedulingv1alpha1.DeviceTopology{
+						SocketID: 0,
+						NodeID:   0,
+						PCIEID:   strconv.Itoa((minor - 1) / 2),
+					},
+				})
+			}
+			nd.resetDeviceTotal(map[schedulingv1alpha1.DeviceType]deviceResources{
+				schedulingv1alpha1.RDMA: total,
+			})
+			nd.deviceInfos = map[schedulingv1alpha1.DeviceType][]*schedulingv1alpha1.DeviceInfo{
+				schedulingv1alpha1.RDMA: deviceInfos,
+			}
+			// the NIC minor 1 under the PCIe 0 is allocated to another pod
+			allocatedPod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "allocated-pod",
+				},
+			}
+			nd.updateCacheUsed(apiext.DeviceAllocations{
+				schedulingv1alpha1.RDMA: {
+					{
+						Minor: 1,
+						Resources: corev1.ResourceList{
+							apiext.ResourceRDMA: resource.MustParse("100"),
+						},
+					},
+				},
+			}, allocatedPod, true)
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod",
+				},
+			}
+			state := &preFilterState{
+				podRequests: map[schedulingv1alpha1.DeviceType]corev1.ResourceList{
+					schedulingv1alpha1.RDMA: {
+						apiext.ResourceRDMA: resource.MustParse("100"),
+					},
+				},
+			}
+			allocator := &AutopilotAllocator{
+				state:            state,
+				nodeDevice:       nd,
+				node:             &corev1.Node{},
+				pod:              pod,
+				pcieSpreadPolicy: tt.pcieSpreadPolicy,
+			}
+			allocateResult, status := allocator.Allocate(nil, nil, nil, nil)
+			assert.True(t, status.IsSuccess())
+			expectAllocations := []*apiext.DeviceAllocation{
+				{
+					Minor: tt.wantMinor,
+					Resources: corev1.ResourceList{
+						apiext.ResourceRDMA: resource.MustParse("100"),
+					},
+				},
+			}
+			assert.True(t, equality.Semantic.DeepEqual(expectAllocations, allocateResult[schedulingv1alpha1.RDMA]), allocateResult)
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/utils/pointer"
 
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/util"
@@ -166,6 +167,8 @@ type deviceResourceMinorPair struct {
 	minor     int
 	resources corev1.ResourceList
 	score     int64
+	// pcieAllocated is the number of the allocated devices under the same PCIe root complex.
+	pcieAllocated int
 }
 
 func scoreDevices(podRequest corev1.ResourceList, totalResources, freeResources deviceResources, allocationScorer *resourceAllocationScorer) []deviceResourceMinorPair {
@@ -197,6 +200,9 @@ func sortDeviceResourcesByMinor(r []deviceResourceMinorPair, preferred sets.Int)
 		} else if !r[i].preferred && r[j].preferred {
 			return false
 		}
+		if r[i].pcieAllocated != r[j].pcieAllocated {
+			return r[i].pcieAllocated < r[j].pcieAllocated
+		}
 		if r[i].score < r[j].score {
 			return false
 		} else if r[i].score > r[j].score {
@@ -218,3 +224,29 @@ func sortDeviceResourcesByPreferredPCIe(r []deviceResourceMinorPair, preferredPC
 	}
 	return sortDeviceResourcesByMinor(r, nil)
 }
+
+// fillPCIeAllocatedCount counts the allocated devices under the PCIe root complex of each device, so that the devices
+// under the less allocated PCIe root complexes are preferred and the bandwidth of the pods is spread across the root ports.
+// The devices connected to the same PCIe switch are considered to share one root port.
+func fillPCIeAllocatedCount(r []deviceResourceMinorPair, used deviceResources, deviceInfos []*schedulingv1alpha1.DeviceInfo) []deviceResourceMinorPair {
+	minorToPCIe := map[int]string{}
+	for _, deviceInfo := range deviceInfos {
+		if deviceInfo.Topology != nil {
+			minorToPCIe[int(pointer.Int32Deref(deviceInfo.Minor, 0))] = deviceInfo.Topology.PCIEID
+		}
+	}
+	allocatedCount := map[string]int{}
+	for minor, resources := range used {
+		pcie, ok := minorToPCIe[minor]
+		if !ok || quotav1.IsZero(resources) {
+			continue
+		}
+		allocatedCount[pcie]++
+	}
+	for i := range r {
+		if pcie, ok := minorToPCIe[r[i].minor]; ok {
+			r[i].pcieAllocated = allocatedCount[pcie]
+		}
+	}
+	return r
+}
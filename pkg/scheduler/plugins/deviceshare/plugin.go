@@ -75,6 +75,7 @@ type Plugin struct {
 	handle                             frameworkext.ExtendedHandle
 	nodeDeviceCache                    *nodeDeviceCache
 	scorer                             *resourceAllocationScorer
+	pcieSpreadPolicy                   schedulerconfig.PCIeSpreadPolicy
 }
 
 type preFilterState struct {
@@ -86,6 +87,7 @@ type preFilterState struct {
 	jointAllocate      *apiext.DeviceJointAllocate
 	primaryDeviceType  schedulingv1alpha1.DeviceType
 	gpuRequirements    *GPURequirements
+	gpuJobProfile      *apiext.GPUJobProfile
 	allocationResult   apiext.DeviceAllocations
 	preemptibleDevices map[string]map[schedulingv1alpha1.DeviceType]deviceResources
 	preemptibleInRRs   map[string]map[types.UID]map[schedulingv1alpha1.DeviceType]deviceResources
@@ -111,6 +113,7 @@ func (s *preFilterState) Clone() framework.StateData {
 		hints:                  s.hints,
 		hasSelectors:           s.hasSelectors,
 		gpuRequirements:        s.gpuRequirements,
+		gpuJobProfile:          s.gpuJobProfile,
 		hintSelectors:          s.hintSelectors,
 		jointAllocate:          s.jointAllocate,
 		primaryDeviceType:      s.primaryDeviceType,
@@ -451,6 +454,7 @@ func (p *Plugin) Reserve(ctx context.Context, cycleState *framework.CycleState,
 		node:               nodeInfo.Node(),
 		pod:                pod,
 		scorer:             p.scorer,
+		pcieSpreadPolicy:   p.pcieSpreadPolicy,
 		numaNodes:          affinity.NUMANodeAffinity,
 	}
 
@@ -646,5 +650,6 @@ func New(obj runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		nodeDeviceCache:                    deviceCache,
 		scorer:                             scorePlugin(args),
 		disableDeviceNUMATopologyAlignment: args.DisableDeviceNUMATopologyAlignment,
+		pcieSpreadPolicy:                   args.PCIeSpreadPolicy,
 	}, nil
 }
@@ -26,6 +26,8 @@ import (
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	pluginhelper "k8s.io/kubernetes/pkg/scheduler/framework/plugins/helper"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	schedulerconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
@@ -65,12 +67,13 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 	affinity, _ := store.GetAffinity(nodeName)
 
 	allocator := &AutopilotAllocator{
-		state:      state,
-		nodeDevice: nodeDeviceInfo,
-		node:       nodeInfo.Node(),
-		pod:        pod,
-		scorer:     p.scorer,
-		numaNodes:  affinity.NUMANodeAffinity,
+		state:            state,
+		nodeDevice:       nodeDeviceInfo,
+		node:             nodeInfo.Node(),
+		pod:              pod,
+		scorer:           p.scorer,
+		pcieSpreadPolicy: p.pcieSpreadPolicy,
+		numaNodes:        affinity.NUMANodeAffinity,
 	}
 
 	reservationRestoreState := getReservationRestoreState(cycleState)
@@ -84,7 +87,7 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 	if reservationInfo != nil {
 		score, status := p.scoreWithNominatedReservation(allocator, state, restoreState, nodeName, pod, preemptible, reservationInfo)
 		if status.IsSuccess() {
-			return score, nil
+			return scoreGPUJobProfile(state.gpuJobProfile, nodeInfo, score), nil
 		}
 		klog.ErrorS(status.AsError(), "Failed to scoreWithNominatedReservation of DeviceShare",
 			"pod", klog.KObj(pod), "reservation", klog.KObj(reservationInfo), "node", nodeName)
@@ -96,7 +99,37 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 		klog.ErrorS(status.AsError(), "Failed to score of DeviceShare", "pod", klog.KObj(pod), "node", nodeName)
 		return 0, status
 	}
-	return score, nil
+	return scoreGPUJobProfile(state.gpuJobProfile, nodeInfo, score), nil
+}
+
+// scoreGPUJobProfile adjusts the score by the GPU job profiles of the GPU pods on the node. It prefers the nodes
+// running the complementary jobs, e.g. a compute-bound job is colocated with the memory-bound jobs, and avoids the
+// nodes running the jobs of the same bound.
+func scoreGPUJobProfile(profile *apiext.GPUJobProfile, nodeInfo *framework.NodeInfo, score int64) int64 {
+	if profile == nil || profile.Type == apiext.GPUJobProfileBalanced {
+		return score
+	}
+	var complementary, conflicting int64
+	for _, podInfo := range nodeInfo.Pods {
+		allocations, err := apiext.GetDeviceAllocations(podInfo.Pod.Annotations)
+		if err != nil || len(allocations[schedulingv1alpha1.GPU]) <= 0 {
+			continue
+		}
+		other, err := apiext.GetGPUJobProfile(podInfo.Pod.Annotations)
+		if err != nil || other == nil {
+			continue
+		}
+		if profile.IsComplementaryTo(other) {
+			complementary++
+		} else if profile.Type == other.Type {
+			conflicting++
+		}
+	}
+	if complementary+conflicting <= 0 {
+		return score
+	}
+	profileScore := framework.MaxNodeScore * complementary / (complementary + conflicting)
+	return (score + profileScore) / 2
 }
 
 func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
@@ -133,12 +166,13 @@ func (p *Plugin) ScoreReservation(ctx context.Context, cycleState *framework.Cyc
 	affinity, _ := store.GetAffinity(nodeInfo.Node().Name)
 
 	allocator := &AutopilotAllocator{
-		state:      state,
-		nodeDevice: nodeDeviceInfo,
-		node:       nodeInfo.Node(),
-		pod:        pod,
-		scorer:     p.scorer,
-		numaNodes:  affinity.NUMANodeAffinity,
+		state:            state,
+		nodeDevice:       nodeDeviceInfo,
+		node:             nodeInfo.Node(),
+		pod:              pod,
+		scorer:           p.scorer,
+		pcieSpreadPolicy: p.pcieSpreadPolicy,
+		numaNodes:        affinity.NUMANodeAffinity,
 	}
 
 	preemptible := appendAllocated(nil, restoreState.mergedUnmatchedUsed, state.preemptibleDevices[nodeName])
@@ -1165,3 +1165,90 @@ func Test_resourceAllocationScorer_scoreDevice(t *testing.T) {
 		})
 	}
 }
+
+func Test_scoreGPUJobProfile(t *testing.T) {
+	newProfilePod := func(name string, profileType apiext.GPUJobProfileType, allocated bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name,
+			},
+		}
+		if profileType != "" {
+			assert.NoError(t, apiext.SetGPUJobProfile(pod, &apiext.GPUJobProfile{Type: profileType}))
+		}
+		if allocated {
+			assert.NoError(t, apiext.SetDeviceAllocations(pod, apiext.DeviceAllocations{
+				schedulingv1alpha1.GPU: {
+					{Minor: 0},
+				},
+			}))
+		}
+		return pod
+	}
+	computeBound := &apiext.GPUJobProfile{Type: apiext.GPUJobProfileComputeBound}
+	tests := []struct {
+		name    string
+		profile *apiext.GPUJobProfile
+		pods    []*corev1.Pod
+		score   int64
+		want    int64
+	}{
+		{
+			name:    "pod without profile",
+			profile: nil,
+			pods:    []*corev1.Pod{newProfilePod("pod-1", apiext.GPUJobProfileMemoryBound, true)},
+			score:   60,
+			want:    60,
+		},
+		{
+			name:    "balanced pod",
+			profile: &apiext.GPUJobProfile{Type: apiext.GPUJobProfileBalanced},
+			pods:    []*corev1.Pod{newProfilePod("pod-1", apiext.GPUJobProfileMemoryBound, true)},
+			score:   60,
+			want:    60,
+		},
+		{
+			name:    "no profiled gpu pods on node",
+			profile: computeBound,
+			pods: []*corev1.Pod{
+				newProfilePod("pod-1", "", true),
+				newProfilePod("pod-2", apiext.GPUJobProfileMemoryBound, false),
+			},
+			score: 60,
+			want:  60,
+		},
+		{
+			name:    "prefer complementary pods",
+			profile: computeBound,
+			pods:    []*corev1.Pod{newProfilePod("pod-1", apiext.GPUJobProfileMemoryBound, true)},
+			score:   60,
+			want:    80,
+		},
+		{
+			name:    "avoid conflicting pods",
+			profile: computeBound,
+			pods:    []*corev1.Pod{newProfilePod("pod-1", apiext.GPUJobProfileComputeBound, true)},
+			score:   60,
+			want:    30,
+		},
+		{
+			name:    "mixed pods",
+			profile: computeBound,
+			pods: []*corev1.Pod{
+				newProfilePod("pod-1", apiext.GPUJobProfileComputeBound, true),
+				newProfilePod("pod-2", apiext.GPUJobProfileMemoryBound, true),
+				newProfilePod("pod-3", apiext.GPUJobProfileBalanced, true),
+			},
+			score: 60,
+			want:  55,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodeInfo := framework.NewNodeInfo(tt.pods...)
+			got := scoreGPUJobProfile(tt.profile, nodeInfo, tt.score)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
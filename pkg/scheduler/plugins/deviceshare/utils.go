@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
@@ -322,6 +323,13 @@ func preparePod(pod *corev1.Pod) (state *preFilterState, status *framework.Statu
 		if err != nil {
 			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
 		}
+		if state.gpuRequirements != nil {
+			// the profile is only a scoring preference, so the invalid one is ignored
+			state.gpuJobProfile, err = apiext.GetGPUJobProfile(pod.Annotations)
+			if err != nil {
+				klog.V(4).InfoS("ignore invalid GPU job profile", "pod", klog.KObj(pod), "err", err)
+			}
+		}
 		reservationAffinity, err := reservationutil.GetRequiredReservationAffinity(pod)
 		if err != nil {
 			return nil, framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
@@ -393,6 +401,13 @@ func parsePodDeviceShareExtensions(pod *corev1.Pod, podRequests map[schedulingv1
 			break
 		}
 	}
+	for _, hint := range hints {
+		// the pinned devices are filtered as the selectors
+		if hasPinnedDevices(hint) {
+			state.hasSelectors = true
+			break
+		}
+	}
 	state.jointAllocate = jointAllocate
 	return nil
 }
@@ -450,3 +465,43 @@ func parseGPURequirements(pod *corev1.Pod, podRequests map[schedulingv1alpha1.De
 	}
 	return gpuRequirements, nil
 }
+
+func hasPinnedDevices(hint *apiext.DeviceHint) bool {
+	return hint != nil && (len(hint.Minors) > 0 || len(hint.NUMANodes) > 0)
+}
+
+// matchPinnedDevices checks if the device is pinned by the Minors and NUMANodes of the hint.
+func matchPinnedDevices(hint *apiext.DeviceHint, deviceInfo *schedulingv1alpha1.DeviceInfo) bool {
+	if !hasPinnedDevices(hint) {
+		return true
+	}
+	if len(hint.Minors) > 0 {
+		minor := pointer.Int32Deref(deviceInfo.Minor, 0)
+		matched := false
+		for _, v := range hint.Minors {
+			if v == minor {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(hint.NUMANodes) > 0 {
+		if deviceInfo.Topology == nil {
+			return false
+		}
+		matched := false
+		for _, v := range hint.NUMANodes {
+			if v == deviceInfo.Topology.NodeID {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
@@ -82,6 +82,7 @@ func (ctrl *Controller) syncElasticQuotaStatus(eq *v1alpha1.ElasticQuota) {
 		klog.Warningf("failed get quota summary for elasticQuota %v", eq.Name)
 		return
 	}
+	summary.WaitStatus = ctrl.plugin.waitTracker.getWaitStatus(eq.Name)
 
 	newEQ, err := updateElasticQuotaStatusIfChanged(eq, summary, klog.V(5).Enabled())
 	if err != nil {
@@ -190,6 +191,26 @@ func updateElasticQuotaStatusIfChanged(eq *v1alpha1.ElasticQuota, summary *core.
 		newElasticQuota.Status.Used = summary.Used
 	}
 
+	if summary.WaitStatus != nil {
+		diff, err := isElasticQuotaWaitStatusDiff(eq, summary.WaitStatus)
+		if err != nil {
+			return nil, err
+		}
+		if diff {
+			if newElasticQuota == nil {
+				newElasticQuota = eq.DeepCopy()
+				if newElasticQuota.Annotations == nil {
+					newElasticQuota.Annotations = map[string]string{}
+				}
+			}
+			data, err := json.Marshal(summary.WaitStatus)
+			if err != nil {
+				return nil, err
+			}
+			newElasticQuota.Annotations[extension.AnnotationWaitStatus] = string(data)
+		}
+	}
+
 	if logChanges && len(changes) > 0 {
 		sb := strings.Builder{}
 		for i, v := range changes {
@@ -215,6 +236,23 @@ func isElasticQuotaAnnotationDiff(eq *v1alpha1.ElasticQuota, key string, resourc
 	return changed, originalResourceList, nil
 }
 
+func isElasticQuotaWaitStatusDiff(eq *v1alpha1.ElasticQuota, waitStatus *extension.QuotaWaitStatus) (bool, error) {
+	original, err := extension.GetQuotaWaitStatus(eq)
+	if err != nil {
+		return false, err
+	}
+	// compare the serialized values since the time in the annotation is truncated to seconds
+	originalData, err := json.Marshal(original)
+	if err != nil {
+		return false, err
+	}
+	data, err := json.Marshal(waitStatus)
+	if err != nil {
+		return false, err
+	}
+	return string(originalData) != string(data), nil
+}
+
 func updateElasticQuotaAnnotation(eq *v1alpha1.ElasticQuota, key string, resourceList v1.ResourceList) error {
 	data, err := json.Marshal(resourceList)
 	if err != nil {
@@ -18,6 +18,8 @@ package core
 
 import (
 	v1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
 type SimplePodInfo struct {
@@ -51,6 +53,9 @@ type QuotaInfoSummary struct {
 	SelfNonPreemptibleRequest v1.ResourceList `json:"selfNonPreemptibleRequest"`
 
 	PodCache map[string]*SimplePodInfo `json:"podCache,omitempty"`
+
+	// WaitStatus is the wait status of the pending pods of the quota, which is tracked by the plugin.
+	WaitStatus *extension.QuotaWaitStatus `json:"waitStatus,omitempty"`
 }
 
 func NewQuotaInfoSummary() *QuotaInfoSummary {
@@ -0,0 +1,233 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/coscheduling/util"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+// defaultGangQuotaReservationTimeout is the same as the default gang waiting time of the Coscheduling plugin.
+const defaultGangQuotaReservationTimeout = 600 * time.Second
+
+// gangQuotaReservation is the quota held for the members of a gang which are not reserved in the quota yet.
+type gangQuotaReservation struct {
+	quotaName string
+	treeID    string
+	// reserved is the sum of the requests of the members not reserved in the quota yet.
+	reserved corev1.ResourceList
+	// reservedPods records the requests of the members which have been reserved in the quota.
+	reservedPods map[types.UID]corev1.ResourceList
+	expireTime   time.Time
+}
+
+// gangQuotaReserver holds the quota for the gangs, so that the quota is admitted for all the members of a gang
+// atomically. The members reserved in the quota are moved from the gang reservation to the quota used, and the
+// reservation is released when all members are reserved or the gang times out.
+type gangQuotaReserver struct {
+	lock  sync.Mutex
+	gangs map[string]*gangQuotaReservation
+	now   func() time.Time
+}
+
+func newGangQuotaReserver() *gangQuotaReserver {
+	return &gangQuotaReserver{
+		gangs: map[string]*gangQuotaReservation{},
+		now:   time.Now,
+	}
+}
+
+// has checks if the gang holds an unexpired reservation.
+func (r *gangQuotaReserver) has(gangID string) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.getLocked(gangID) != nil
+}
+
+// reserve holds the request for the gang in the quota until the timeout.
+func (r *gangQuotaReserver) reserve(gangID, quotaName, treeID string, request corev1.ResourceList, timeout time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.gangs[gangID] = &gangQuotaReservation{
+		quotaName:    quotaName,
+		treeID:       treeID,
+		reserved:     request.DeepCopy(),
+		reservedPods: map[types.UID]corev1.ResourceList{},
+		expireTime:   r.now().Add(timeout),
+	}
+}
+
+// reservedByOthers returns the quota held by the gangs other than the given one in the quota.
+func (r *gangQuotaReserver) reservedByOthers(quotaName, treeID, gangID string) corev1.ResourceList {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	reserved := corev1.ResourceList{}
+	for id := range r.gangs {
+		if id == gangID {
+			continue
+		}
+		reservation := r.getLocked(id)
+		if reservation == nil || reservation.quotaName != quotaName || reservation.treeID != treeID {
+			continue
+		}
+		reserved = quotav1.Add(reserved, reservation.reserved)
+	}
+	return reserved
+}
+
+// assumePod moves the request of the member from the gang reservation to the quota used.
+// The reservation is released once it is used up.
+func (r *gangQuotaReserver) assumePod(gangID string, pod *corev1.Pod, request corev1.ResourceList) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	reservation := r.getLocked(gangID)
+	if reservation == nil {
+		return
+	}
+	if _, ok := reservation.reservedPods[pod.UID]; ok {
+		return
+	}
+	reservation.reservedPods[pod.UID] = request
+	reservation.reserved = quotav1.SubtractWithNonNegativeResult(reservation.reserved, request)
+	if quotav1.IsZero(reservation.reserved) {
+		klog.V(4).Infof("gang %s quota reservation is used up, quota: %v", gangID, reservation.quotaName)
+		delete(r.gangs, gangID)
+	}
+}
+
+// forgetPod moves the request of the unreserved member back to the gang reservation.
+func (r *gangQuotaReserver) forgetPod(gangID string, pod *corev1.Pod) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	reservation := r.getLocked(gangID)
+	if reservation == nil {
+		return
+	}
+	request, ok := reservation.reservedPods[pod.UID]
+	if !ok {
+		return
+	}
+	delete(reservation.reservedPods, pod.UID)
+	reservation.reserved = quotav1.Add(reservation.reserved, request)
+}
+
+func (r *gangQuotaReserver) getLocked(gangID string) *gangQuotaReservation {
+	reservation := r.gangs[gangID]
+	if reservation == nil {
+		return nil
+	}
+	if !r.now().Before(reservation.expireTime) {
+		klog.V(4).Infof("gang %s quota reservation expired, quota: %v, reserved: %v",
+			gangID, reservation.quotaName, printResourceList(reservation.reserved))
+		delete(r.gangs, gangID)
+		return nil
+	}
+	return reservation
+}
+
+func getPodGangID(pod *corev1.Pod) string {
+	gangName := util.GetGangNameByPod(pod)
+	if gangName == "" {
+		return ""
+	}
+	return util.GetId(pod.Namespace, gangName)
+}
+
+func getGangQuotaReservationTimeout(pod *corev1.Pod) time.Duration {
+	if value := pod.Annotations[extension.AnnotationGangWaitTime]; value != "" {
+		if waitTime, err := time.ParseDuration(value); err == nil && waitTime > 0 {
+			return waitTime
+		}
+	}
+	return defaultGangQuotaReservationTimeout
+}
+
+// admitGang reserves the quota for all the members of the gang not assigned yet if the gang holds no reservation.
+// It rejects the pod if the quota is insufficient for the whole gang, so that none of the members takes the quota.
+func (g *Plugin) admitGang(pod *corev1.Pod, gangID, quotaName, treeID string, podRequest corev1.ResourceList,
+	state *PostFilterState, othersReserved corev1.ResourceList) *framework.Status {
+	if g.gangReserver.has(gangID) {
+		return nil
+	}
+
+	gangRequest, err := g.getGangQuotaRequest(pod, quotaName, treeID, podRequest, state.quotaInfo)
+	if err != nil {
+		return framework.NewStatus(framework.Error, err.Error())
+	}
+	used := quotav1.Add(quotav1.Add(state.used, othersReserved), gangRequest)
+	if isLessEqual, exceedDimensions := quotav1.LessThanOrEqual(used, state.usedLimit); !isLessEqual {
+		return framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient quotas for gang, "+
+			"quotaName: %v, gang: %v, runtime: %v, used: %v, reservedByOtherGangs: %v, gang's request: %v, exceedDimensions: %v",
+			quotaName, gangID, printResourceList(state.usedLimit), printResourceList(state.used),
+			printResourceList(othersReserved), printResourceList(gangRequest), exceedDimensions))
+	}
+
+	g.gangReserver.reserve(gangID, quotaName, treeID, gangRequest, getGangQuotaReservationTimeout(pod))
+	klog.V(4).Infof("reserve quota for gang %s, quota: %v, request: %v", gangID, quotaName, printResourceList(gangRequest))
+	return nil
+}
+
+// getGangQuotaRequest sums up the requests of the pending members of the gang in the quota. The members not created
+// yet are estimated with the request of the given pod, so that the reservation covers the min number of the gang.
+func (g *Plugin) getGangQuotaRequest(pod *corev1.Pod, quotaName, treeID string, podRequest corev1.ResourceList,
+	quotaInfo *core.QuotaInfo) (corev1.ResourceList, error) {
+	pods, err := g.podLister.Pods(pod.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of gang, err: %w", err)
+	}
+
+	gangName := util.GetGangNameByPod(pod)
+	gangRequest := podRequest.DeepCopy()
+	pendingNum, assignedNum := 1, 0
+	for _, p := range pods {
+		if p.UID == pod.UID || p.DeletionTimestamp != nil || util.GetGangNameByPod(p) != gangName ||
+			p.Status.Phase == corev1.PodSucceeded || p.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if name, id := g.getPodAssociateQuotaNameAndTreeID(p); name != quotaName || id != treeID {
+			continue
+		}
+		if p.Spec.NodeName != "" {
+			assignedNum++
+			continue
+		}
+		request := quotav1.Mask(core.PodRequests(p), quotav1.ResourceNames(quotaInfo.CalculateInfo.Max))
+		gangRequest = quotav1.Add(gangRequest, request)
+		pendingNum++
+	}
+
+	minNum, err := util.GetGangMinNumFromPod(pod)
+	if err != nil {
+		return gangRequest, nil
+	}
+	for i := pendingNum + assignedNum; i < minNum; i++ {
+		gangRequest = quotav1.Add(gangRequest, podRequest)
+	}
+	return gangRequest, nil
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestGangQuotaReserver(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	reserver := newGangQuotaReserver()
+	reserver.now = func() time.Time { return now }
+
+	pod1 := MakePod("ns1", "pod1").UID("pod1").Obj()
+	pod2 := MakePod("ns1", "pod2").UID("pod2").Obj()
+
+	reserver.reserve("ns1/gang-a", "test", "", MakeResourceList().CPU(4).Mem(8).Obj(), time.Minute)
+	reserver.reserve("ns1/gang-b", "test", "", MakeResourceList().CPU(2).Mem(4).Obj(), 2*time.Minute)
+	reserver.reserve("ns1/gang-c", "other", "", MakeResourceList().CPU(1).Mem(1).Obj(), time.Minute)
+	assert.True(t, reserver.has("ns1/gang-a"))
+	assert.Equal(t, MakeResourceList().CPU(6).Mem(12).Obj(), reserver.reservedByOthers("test", "", ""))
+	assert.Equal(t, MakeResourceList().CPU(2).Mem(4).Obj(), reserver.reservedByOthers("test", "", "ns1/gang-a"))
+
+	// the reserved member is moved out of the reservation, and is moved back when unreserved
+	reserver.assumePod("ns1/gang-a", pod1, MakeResourceList().CPU(2).Mem(4).Obj())
+	reserver.assumePod("ns1/gang-a", pod1, MakeResourceList().CPU(2).Mem(4).Obj())
+	assert.Equal(t, MakeResourceList().CPU(4).Mem(8).Obj(), reserver.reservedByOthers("test", "", ""))
+	reserver.forgetPod("ns1/gang-a", pod1)
+	assert.Equal(t, MakeResourceList().CPU(6).Mem(12).Obj(), reserver.reservedByOthers("test", "", ""))
+
+	// the reservation is released once used up
+	reserver.assumePod("ns1/gang-a", pod1, MakeResourceList().CPU(2).Mem(4).Obj())
+	reserver.assumePod("ns1/gang-a", pod2, MakeResourceList().CPU(2).Mem(4).Obj())
+	assert.False(t, reserver.has("ns1/gang-a"))
+	assert.Equal(t, MakeResourceList().CPU(2).Mem(4).Obj(), reserver.reservedByOthers("test", "", ""))
+
+	// the reservation is released once expired
+	now = now.Add(90 * time.Second)
+	assert.False(t, reserver.has("ns1/gang-c"))
+	assert.True(t, reserver.has("ns1/gang-b"))
+	now = now.Add(time.Minute)
+	assert.Equal(t, corev1.ResourceList{}, reserver.reservedByOthers("test", "", ""))
+	assert.Len(t, reserver.gangs, 0)
+}
+
+func TestPlugin_PreFilter_GangQuotaAdmission(t *testing.T) {
+	makeGangPod := func(name, gangName, minNum string, cpu int64) *corev1.Pod {
+		pod := MakePod("t1-ns1", name).UID(name).Container(MakeResourceList().CPU(cpu).Mem(2).Obj()).Obj()
+		pod.Annotations = map[string]string{
+			extension.AnnotationGangName:   gangName,
+			extension.AnnotationGangMinNum: minNum,
+		}
+		return pod
+	}
+
+	suit := newPluginTestSuit(t, nil)
+	p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+	assert.Nil(t, err)
+	gp := p.(*Plugin)
+	gp.pluginArgs.EnableGangQuotaAdmission = true
+	now := time.Unix(1700000000, 0)
+	gp.gangReserver.now = func() time.Time { return now }
+	qi := gp.groupQuotaManager.GetQuotaInfoByName(extension.DefaultQuotaName)
+	qi.Lock()
+	qi.CalculateInfo.Runtime = MakeResourceList().CPU(10).Mem(20).Obj()
+	qi.UnLock()
+	ctx := context.TODO()
+
+	// the gang needs more quota than available, none of the members is admitted
+	_, status := gp.PreFilter(ctx, framework.NewCycleState(), makeGangPod("pod-1", "gang-a", "4", 3))
+	assert.True(t, status.IsUnschedulable(), status.Message())
+	assert.False(t, gp.gangReserver.has("t1-ns1/gang-a"))
+
+	// the quota is reserved for the whole gang when the first member is admitted
+	gangPod1 := makeGangPod("pod-2", "gang-b", "2", 3)
+	gangPod2 := makeGangPod("pod-3", "gang-b", "2", 3)
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), gangPod1)
+	assert.True(t, status.IsSuccess(), status.Message())
+	assert.True(t, gp.gangReserver.has("t1-ns1/gang-b"))
+
+	// the pods out of the gang can not take the reserved quota
+	pod := MakePod("t1-ns1", "pod-4").UID("pod-4").Container(MakeResourceList().CPU(5).Mem(2).Obj()).Obj()
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), pod)
+	assert.True(t, status.IsUnschedulable(), status.Message())
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), makeGangPod("pod-5", "gang-c", "2", 3))
+	assert.True(t, status.IsUnschedulable(), status.Message())
+
+	// the members are admitted with the reserved quota
+	gp.OnPodAdd(gangPod1)
+	gp.OnPodAdd(gangPod2)
+	gp.Reserve(ctx, framework.NewCycleState(), gangPod1, "")
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), gangPod2)
+	assert.True(t, status.IsSuccess(), status.Message())
+	gp.Reserve(ctx, framework.NewCycleState(), gangPod2, "")
+	assert.False(t, gp.gangReserver.has("t1-ns1/gang-b"))
+	assert.Equal(t, MakeResourceList().CPU(6).Mem(4).Obj(), qi.GetUsed())
+
+	// the reservation of the gang timed out is released
+	gangPod3 := makeGangPod("pod-6", "gang-d", "2", 2)
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), gangPod3)
+	assert.True(t, status.IsSuccess(), status.Message())
+	pod = MakePod("t1-ns1", "pod-7").UID("pod-7").Container(MakeResourceList().CPU(2).Mem(2).Obj()).Obj()
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), pod)
+	assert.True(t, status.IsUnschedulable(), status.Message())
+	now = now.Add(defaultGangQuotaReservationTimeout)
+	_, status = gp.PreFilter(ctx, framework.NewCycleState(), pod)
+	assert.True(t, status.IsSuccess(), status.Message())
+}
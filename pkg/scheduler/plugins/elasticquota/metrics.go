@@ -51,6 +51,26 @@ var (
 			Buckets:   metrics.ExponentialBuckets(0.001, 2, 15),
 		},
 	)
+
+	ElasticQuotaAdmissionWaitDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem: schedulermetrics.SchedulerSubsystem,
+			Name:      "elastic_quota_admission_wait_duration_seconds",
+			Help:      "Duration in seconds the pods of the ElasticQuota are rejected due to the quota limits before admitted",
+			Buckets:   metrics.ExponentialBuckets(1, 2, 15),
+		},
+		[]string{"name", "tree"},
+	)
+
+	ElasticQuotaSchedulingWaitDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Subsystem: schedulermetrics.SchedulerSubsystem,
+			Name:      "elastic_quota_scheduling_wait_duration_seconds",
+			Help:      "Duration in seconds the pods admitted by the ElasticQuota are unschedulable before reserved",
+			Buckets:   metrics.ExponentialBuckets(1, 2, 15),
+		},
+		[]string{"name", "tree"},
+	)
 )
 
 func init() {
@@ -58,6 +78,8 @@ func init() {
 		ElasticQuotaSpecMetric,
 		ElasticQuotaStatusMetric,
 		UpdateElasticQuotaStatusLatency,
+		ElasticQuotaAdmissionWaitDuration,
+		ElasticQuotaSchedulingWaitDuration,
 	)
 }
 
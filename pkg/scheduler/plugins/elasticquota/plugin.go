@@ -44,6 +44,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
 	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
 	"github.com/koordinator-sh/koordinator/pkg/util/transformer"
 )
@@ -91,6 +92,11 @@ type Plugin struct {
 	// quotaToTreeMap store the relationship of quota and quota tree
 	// the key is the quota name, the value is the tree id
 	quotaToTreeMap map[string]string
+
+	// waitTracker tracks the pods waiting for the quota admission or the scheduling.
+	waitTracker *quotaWaitTracker
+	// gangReserver holds the quota for the gangs admitted when EnableGangQuotaAdmission is true.
+	gangReserver *gangQuotaReserver
 }
 
 var (
@@ -147,6 +153,8 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		nodeLister:                     handle.SharedInformerFactory().Core().V1().Nodes().Lister(),
 		groupQuotaManagersForQuotaTree: make(map[string]*core.GroupQuotaManager),
 		quotaToTreeMap:                 make(map[string]string),
+		waitTracker:                    newQuotaWaitTracker(),
+		gangReserver:                   newGangQuotaReserver(),
 	}
 	elasticQuota.groupQuotaManager = core.NewGroupQuotaManager("", pluginArgs.SystemQuotaGroupMax, pluginArgs.DefaultQuotaGroupMax)
 
@@ -238,7 +246,22 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	podRequest := core.PodRequests(pod)
 	podRequest = quotav1.Mask(podRequest, quotav1.ResourceNames(quotaInfo.CalculateInfo.Max))
 	used := quotav1.Add(podRequest, state.used)
+	if g.pluginArgs.EnableGangQuotaAdmission {
+		// the quota held by the other gangs is unavailable to the pod
+		gangID := getPodGangID(pod)
+		othersReserved := g.gangReserver.reservedByOthers(quotaName, treeID, gangID)
+		if gangID != "" {
+			if status := g.admitGang(pod, gangID, quotaName, treeID, podRequest, state, othersReserved); !status.IsSuccess() {
+				if status.IsUnschedulable() {
+					g.waitTracker.markGated(pod, quotaName, treeID)
+				}
+				return nil, status
+			}
+		}
+		used = quotav1.Add(used, othersReserved)
+	}
 	if isLessEqual, exceedDimensions := quotav1.LessThanOrEqual(used, state.usedLimit); !isLessEqual {
+		g.waitTracker.markGated(pod, quotaName, treeID)
 		return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient quotas, "+
 			"quotaName: %v, runtime: %v, used: %v, pod's request: %v, exceedDimensions: %v",
 			quotaName, printResourceList(state.usedLimit), printResourceList(state.used), printResourceList(podRequest), exceedDimensions))
@@ -249,6 +272,7 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 		nonPreemptibleUsed := state.nonPreemptibleUsed
 		addNonPreemptibleUsed := quotav1.Add(podRequest, nonPreemptibleUsed)
 		if isLessEqual, exceedDimensions := quotav1.LessThanOrEqual(addNonPreemptibleUsed, quotaMin); !isLessEqual {
+			g.waitTracker.markGated(pod, quotaName, treeID)
 			return nil, framework.NewStatus(framework.Unschedulable, fmt.Sprintf("Insufficient non-preemptible quotas, "+
 				"quotaName: %v, min: %v, nonPreemptibleUsed: %v, pod's request: %v, exceedDimensions: %v",
 				quotaName, printResourceList(quotaMin), printResourceList(nonPreemptibleUsed), printResourceList(podRequest), exceedDimensions))
@@ -256,9 +280,16 @@ func (g *Plugin) PreFilter(ctx context.Context, cycleState *framework.CycleState
 	}
 
 	if g.pluginArgs.EnableCheckParentQuota {
-		return nil, g.checkQuotaRecursive(quotaName, []string{quotaName}, podRequest)
+		status := g.checkQuotaRecursive(quotaName, []string{quotaName}, podRequest)
+		if status.IsUnschedulable() {
+			g.waitTracker.markGated(pod, quotaName, treeID)
+			return nil, status
+		} else if !status.IsSuccess() {
+			return nil, status
+		}
 	}
 
+	g.waitTracker.markAdmitted(pod)
 	return nil, framework.NewStatus(framework.Success, "")
 }
 
@@ -323,7 +354,15 @@ func (g *Plugin) PostFilter(ctx context.Context, state *framework.CycleState, po
 		Interface:  g,
 	}
 
+	if quotaName, treeID := g.getPodAssociateQuotaNameAndTreeID(pod); quotaName != "" {
+		g.waitTracker.markUnschedulable(pod, quotaName, treeID)
+	}
+
+	preemptionexplanation.RecordConsidered(state, Name)
 	result, status := pe.Preempt(ctx, pod, filteredNodeStatusMap)
+	if !status.IsSuccess() {
+		preemptionexplanation.RecordFailure(state, Name, preemptionexplanation.ReasonPreemptionFailed, status.Message())
+	}
 	if status.Message() != "" {
 		return result, framework.NewStatus(status.Code(), "preemption: "+status.Message())
 	}
@@ -343,6 +382,16 @@ func (g *Plugin) Reserve(ctx context.Context, state *framework.CycleState, p *co
 	}
 
 	mgr.ReservePod(quotaName, p)
+	g.waitTracker.markReserved(p)
+	if g.pluginArgs.EnableGangQuotaAdmission {
+		if gangID := getPodGangID(p); gangID != "" {
+			quotaInfo := mgr.GetQuotaInfoByName(quotaName)
+			if quotaInfo != nil {
+				podRequest := quotav1.Mask(core.PodRequests(p), quotav1.ResourceNames(quotaInfo.CalculateInfo.Max))
+				g.gangReserver.assumePod(gangID, p, podRequest)
+			}
+		}
+	}
 	return framework.NewStatus(framework.Success, "")
 }
 
@@ -358,6 +407,11 @@ func (g *Plugin) Unreserve(ctx context.Context, state *framework.CycleState, p *
 		return
 	}
 	mgr.UnreservePod(quotaName, p)
+	if g.pluginArgs.EnableGangQuotaAdmission {
+		if gangID := getPodGangID(p); gangID != "" {
+			g.gangReserver.forgetPod(gangID, p)
+		}
+	}
 }
 
 func (g *Plugin) GetQuotaInformer() cache.SharedIndexInformer { // expose for extensions
@@ -111,6 +111,7 @@ func (g *Plugin) OnPodDelete(obj interface{}) {
 		return
 	}
 
+	g.waitTracker.forget(pod)
 	g.handlePodDelete(pod)
 }
 
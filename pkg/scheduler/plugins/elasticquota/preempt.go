@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	policy "k8s.io/api/policy/v1"
@@ -37,6 +38,7 @@ import (
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	koordfeature "github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
 )
 
@@ -136,6 +138,7 @@ func (g *Plugin) SelectVictimsOnNode(
 	}
 	// As the first step, remove all the lower priority pods from the node and
 	// check if the given pod can be scheduled.
+	numQuotaGuarded, numNonPreemptible := 0, 0
 	for _, pi := range nodeInfo.Pods {
 		// TODO only allow same quotaGroup preemption.
 		if g.canPreempt(pod, pi.Pod) {
@@ -143,11 +146,26 @@ func (g *Plugin) SelectVictimsOnNode(
 			if err := removePod(pi); err != nil {
 				return nil, 0, framework.AsStatus(err)
 			}
+		} else if corev1helpers.PodPriority(pi.Pod) < corev1helpers.PodPriority(pod) {
+			if extension.IsPodNonPreemptible(pi.Pod) {
+				numNonPreemptible++
+			} else {
+				numQuotaGuarded++
+			}
 		}
 	}
+	preemptionexplanation.RecordVictims(state, potentialVictims)
 
 	// No potential victims are found, and so we don't need to evaluate the node again since its state didn't change.
 	if len(potentialVictims) == 0 {
+		if numQuotaGuarded > 0 {
+			preemptionexplanation.RecordFailure(state, Name, preemptionexplanation.ReasonQuotaGuard,
+				"the lower priority pods belong to other quotas")
+		}
+		if numNonPreemptible > 0 {
+			preemptionexplanation.RecordFailure(state, Name, preemptionexplanation.ReasonNonPreemptible,
+				"the lower priority pods are non-preemptible")
+		}
 		message := fmt.Sprintf("No victims found on node %v for preemptor pod %v", nodeInfo.Node().Name, pod.Name)
 		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
 	}
@@ -281,7 +299,7 @@ func getPDBLister(handle framework.Handle) policylisters.PodDisruptionBudgetList
 }
 
 func (g *Plugin) canPreempt(pod, victim *corev1.Pod) bool {
-	if extension.IsPodNonPreemptible(victim) {
+	if extension.IsPodNonPreemptible(victim) || extension.IsPodPreemptionImmune(victim, time.Now()) {
 		return false
 	}
 	podPri := corev1helpers.PodPriority(pod)
@@ -0,0 +1,70 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+var _ framework.PreBindPlugin = &Plugin{}
+
+// PreBind grants the preemption immunity to the pod admitted with the borrowed quota, so the pod cannot be preempted
+// or revoked until the PreemptionImmunityDuration expires. It smooths the thrash when the quota used oscillates
+// around the min. The deadline of the immunity is recorded in the pod annotation.
+func (g *Plugin) PreBind(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) *framework.Status {
+	immunityDuration := g.pluginArgs.PreemptionImmunityDuration.Duration
+	if immunityDuration <= 0 {
+		return nil
+	}
+	quotaName, treeID := g.getPodAssociateQuotaNameAndTreeID(pod)
+	if quotaName == "" || quotaName == extension.SystemQuotaName {
+		return nil
+	}
+	mgr := g.GetGroupQuotaManagerForTree(treeID)
+	if mgr == nil {
+		return nil
+	}
+	quotaInfo := mgr.GetQuotaInfoByName(quotaName)
+	if quotaInfo == nil {
+		return nil
+	}
+	if !isQuotaBorrowed(quotaInfo, core.PodRequests(pod)) {
+		return nil
+	}
+	until := time.Now().Add(immunityDuration)
+	extension.SetPodPreemptionImmuneUntil(pod, until)
+	klog.V(4).InfoS("Pod is admitted with the borrowed quota and immune to preemption", "pod", klog.KObj(pod),
+		"quota", quotaName, "until", until)
+	return nil
+}
+
+// isQuotaBorrowed checks whether the used of the quota, including the pod reserved, exceeds the min in any
+// dimension of the pod request.
+func isQuotaBorrowed(quotaInfo *core.QuotaInfo, podRequest corev1.ResourceList) bool {
+	used := quotav1.Mask(quotaInfo.GetUsed(), quotav1.ResourceNames(podRequest))
+	isLessEqual, _ := quotav1.LessThanOrEqual(used, quotaInfo.GetMin())
+	return !isLessEqual
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestPlugin_PreBindPreemptionImmunity(t *testing.T) {
+	tests := []struct {
+		name             string
+		immunityDuration time.Duration
+		podRequest       corev1.ResourceList
+		wantImmune       bool
+	}{
+		{
+			name:             "immunity disabled",
+			immunityDuration: 0,
+			podRequest:       MakeResourceList().CPU(20).Mem(20).Obj(),
+			wantImmune:       false,
+		},
+		{
+			name:             "admitted within min",
+			immunityDuration: time.Minute,
+			podRequest:       MakeResourceList().CPU(5).Mem(5).Obj(),
+			wantImmune:       false,
+		},
+		{
+			name:             "admitted with borrowed quota",
+			immunityDuration: time.Minute,
+			podRequest:       MakeResourceList().CPU(20).Mem(20).Obj(),
+			wantImmune:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suit := newPluginTestSuit(t, nil)
+			suit.elasticQuotaArgs.PreemptionImmunityDuration = metav1.Duration{Duration: tt.immunityDuration}
+			p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+			assert.Nil(t, err)
+			gp := p.(*Plugin)
+			gp.addQuota("test1", extension.RootQuotaName, 100, 100, 10, 10, 100, 100, false, "", "")
+
+			pod := MakePod("t1-ns1", "pod1").Label(extension.LabelQuotaName, "test1").
+				Container(tt.podRequest).UID("pod1").Obj()
+			gp.OnPodAdd(pod)
+			state := framework.NewCycleState()
+			assert.True(t, gp.Reserve(context.TODO(), state, pod, "").IsSuccess())
+			assert.True(t, gp.PreBind(context.TODO(), state, pod, "").IsSuccess())
+
+			_, ok := extension.GetPodPreemptionImmuneUntil(pod)
+			assert.Equal(t, tt.wantImmune, ok)
+			assert.Equal(t, tt.wantImmune, extension.IsPodPreemptionImmune(pod, time.Now()))
+			assert.False(t, extension.IsPodPreemptionImmune(pod, time.Now().Add(tt.immunityDuration+time.Second)))
+		})
+	}
+}
+
+func TestPlugin_canPreemptImmunePod(t *testing.T) {
+	suit := newPluginTestSuit(t, nil)
+	p, err := suit.proxyNew(suit.elasticQuotaArgs, suit.Handle)
+	assert.Nil(t, err)
+	gp := p.(*Plugin)
+
+	highPriority, lowPriority := int32(100), int32(10)
+	preemptor := MakePod("t1-ns1", "preemptor").Label(extension.LabelQuotaName, "test1").Obj()
+	preemptor.Spec.Priority = &highPriority
+	victim := MakePod("t1-ns1", "victim").Label(extension.LabelQuotaName, "test1").Obj()
+	victim.Spec.Priority = &lowPriority
+	assert.True(t, gp.canPreempt(preemptor, victim))
+
+	extension.SetPodPreemptionImmuneUntil(victim, time.Now().Add(time.Minute))
+	assert.False(t, gp.canPreempt(preemptor, victim))
+
+	extension.SetPodPreemptionImmuneUntil(victim, time.Now().Add(-time.Minute))
+	assert.True(t, gp.canPreempt(preemptor, victim))
+}
@@ -106,12 +106,13 @@ func (monitor *QuotaOverUsedGroupMonitor) getToRevokePodList(quotaName string) [
 
 	// first try revoke all until used <= runtime
 	tryAssignBackPodCache := make([]*v1.Pod, 0)
+	now := time.Now()
 
 	for _, pod := range priPodCache {
 		if shouldBreak, _ := quotav1.LessThanOrEqual(used, runtime); shouldBreak {
 			break
 		}
-		if extension.IsPodNonPreemptible(pod) {
+		if extension.IsPodNonPreemptible(pod) || extension.IsPodPreemptionImmune(pod, now) {
 			continue
 		}
 		podReq := core.PodRequests(pod)
@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+// podWaitInfo records since when the pod waits for the quota or the nodes.
+type podWaitInfo struct {
+	quotaName          string
+	treeID             string
+	gatedSince         time.Time
+	unschedulableSince time.Time
+}
+
+// quotaWaitTracker tracks the pending pods of the quotas, so that we can distinguish the pods
+// gated by the quota limits from the pods which are admitted by the quota but unschedulable.
+type quotaWaitTracker struct {
+	lock sync.Mutex
+	pods map[types.UID]*podWaitInfo
+	now  func() time.Time
+}
+
+func newQuotaWaitTracker() *quotaWaitTracker {
+	return &quotaWaitTracker{
+		pods: map[types.UID]*podWaitInfo{},
+		now:  time.Now,
+	}
+}
+
+// markGated marks the pod is rejected due to the quota limits.
+func (t *quotaWaitTracker) markGated(pod *corev1.Pod, quotaName, treeID string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	info := t.getOrCreate(pod, quotaName, treeID)
+	if info.gatedSince.IsZero() {
+		info.gatedSince = t.now()
+	}
+	info.unschedulableSince = time.Time{}
+}
+
+// markAdmitted marks the pod is admitted by the quota and records the admission wait if it was gated.
+func (t *quotaWaitTracker) markAdmitted(pod *corev1.Pod) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	info := t.pods[pod.UID]
+	if info == nil || info.gatedSince.IsZero() {
+		return
+	}
+	ElasticQuotaAdmissionWaitDuration.WithLabelValues(info.quotaName, info.treeID).Observe(t.now().Sub(info.gatedSince).Seconds())
+	info.gatedSince = time.Time{}
+	if info.unschedulableSince.IsZero() {
+		delete(t.pods, pod.UID)
+	}
+}
+
+// markUnschedulable marks the pod admitted by the quota fails to be scheduled.
+func (t *quotaWaitTracker) markUnschedulable(pod *corev1.Pod, quotaName, treeID string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	info := t.getOrCreate(pod, quotaName, treeID)
+	if !info.gatedSince.IsZero() {
+		return
+	}
+	if info.unschedulableSince.IsZero() {
+		info.unschedulableSince = t.now()
+	}
+}
+
+// markReserved records the scheduling wait if the pod was unschedulable and stops tracking the pod.
+func (t *quotaWaitTracker) markReserved(pod *corev1.Pod) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	info := t.pods[pod.UID]
+	if info == nil {
+		return
+	}
+	if !info.unschedulableSince.IsZero() {
+		ElasticQuotaSchedulingWaitDuration.WithLabelValues(info.quotaName, info.treeID).Observe(t.now().Sub(info.unschedulableSince).Seconds())
+	}
+	delete(t.pods, pod.UID)
+}
+
+// forget stops tracking the pod, e.g. the pod is deleted.
+func (t *quotaWaitTracker) forget(pod *corev1.Pod) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.pods, pod.UID)
+}
+
+// getWaitStatus returns the wait status of the pending pods of the quota.
+func (t *quotaWaitTracker) getWaitStatus(quotaName string) *extension.QuotaWaitStatus {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	status := &extension.QuotaWaitStatus{}
+	var oldestGated, oldestUnschedulable time.Time
+	for _, info := range t.pods {
+		if info.quotaName != quotaName {
+			continue
+		}
+		if !info.gatedSince.IsZero() {
+			status.GatedPods++
+			if oldestGated.IsZero() || info.gatedSince.Before(oldestGated) {
+				oldestGated = info.gatedSince
+			}
+		}
+		if !info.unschedulableSince.IsZero() {
+			status.UnschedulablePods++
+			if oldestUnschedulable.IsZero() || info.unschedulableSince.Before(oldestUnschedulable) {
+				oldestUnschedulable = info.unschedulableSince
+			}
+		}
+	}
+	if !oldestGated.IsZero() {
+		status.OldestGatedTime = &metav1.Time{Time: oldestGated}
+	}
+	if !oldestUnschedulable.IsZero() {
+		status.OldestUnschedulableTime = &metav1.Time{Time: oldestUnschedulable}
+	}
+	return status
+}
+
+func (t *quotaWaitTracker) getOrCreate(pod *corev1.Pod, quotaName, treeID string) *podWaitInfo {
+	info := t.pods[pod.UID]
+	if info == nil {
+		info = &podWaitInfo{}
+		t.pods[pod.UID] = info
+	}
+	info.quotaName, info.treeID = quotaName, treeID
+	return info
+}
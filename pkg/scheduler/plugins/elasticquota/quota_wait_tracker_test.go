@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/elasticquota/core"
+)
+
+func TestQuotaWaitTracker(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tracker := newQuotaWaitTracker()
+	tracker.now = func() time.Time { return now }
+
+	pod1 := MakePod("ns1", "pod1").UID("pod1").Obj()
+	pod2 := MakePod("ns1", "pod2").UID("pod2").Obj()
+	pod3 := MakePod("ns1", "pod3").UID("pod3").Obj()
+
+	tracker.markGated(pod1, "test", "")
+	now = now.Add(10 * time.Second)
+	tracker.markGated(pod1, "test", "")
+	tracker.markGated(pod2, "test", "")
+	tracker.markUnschedulable(pod2, "test", "")
+	tracker.markUnschedulable(pod3, "test", "")
+	tracker.markAdmitted(pod3)
+
+	got := tracker.getWaitStatus("test")
+	assert.Equal(t, &extension.QuotaWaitStatus{
+		GatedPods:               2,
+		OldestGatedTime:         &metav1.Time{Time: time.Unix(1700000000, 0)},
+		UnschedulablePods:       1,
+		OldestUnschedulableTime: &metav1.Time{Time: now},
+	}, got)
+	assert.Equal(t, &extension.QuotaWaitStatus{}, tracker.getWaitStatus("other"))
+
+	// pod1 is admitted after waiting 20s and then becomes unschedulable
+	now = now.Add(10 * time.Second)
+	tracker.markAdmitted(pod1)
+	assert.Len(t, tracker.pods, 2)
+	tracker.markUnschedulable(pod1, "test", "")
+	now = now.Add(5 * time.Second)
+	tracker.markReserved(pod1)
+	tracker.markReserved(pod3)
+	tracker.forget(pod2)
+	assert.Len(t, tracker.pods, 0)
+	assert.Equal(t, &extension.QuotaWaitStatus{}, tracker.getWaitStatus("test"))
+}
+
+func Test_updateElasticQuotaWaitStatusIfChanged(t *testing.T) {
+	waitStatus := &extension.QuotaWaitStatus{
+		GatedPods:       1,
+		OldestGatedTime: &metav1.Time{Time: time.Unix(1700000000, 0)},
+	}
+	data, err := json.Marshal(waitStatus)
+	assert.NoError(t, err)
+
+	eq := MakeEQ("ns1", "test").Obj()
+	summary := &core.QuotaInfoSummary{WaitStatus: &extension.QuotaWaitStatus{}}
+	newEQ, err := updateElasticQuotaStatusIfChanged(eq, summary, false)
+	assert.NoError(t, err)
+	assert.Nil(t, newEQ)
+
+	summary.WaitStatus = waitStatus
+	newEQ, err = updateElasticQuotaStatusIfChanged(eq, summary, false)
+	assert.NoError(t, err)
+	assert.NotNil(t, newEQ)
+	assert.Equal(t, string(data), newEQ.Annotations[extension.AnnotationWaitStatus])
+
+	// the sub-second part of the time is dropped by the annotation
+	summary.WaitStatus = &extension.QuotaWaitStatus{
+		GatedPods:       1,
+		OldestGatedTime: &metav1.Time{Time: time.Unix(1700000000, 100)},
+	}
+	newEQ, err = updateElasticQuotaStatusIfChanged(newEQ, summary, false)
+	assert.NoError(t, err)
+	assert.Nil(t, newEQ)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interferenceaware
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+const (
+	Name = "InterferenceAware"
+
+	maxRisk = 100
+)
+
+var (
+	_ framework.PreScorePlugin = &Plugin{}
+	_ framework.ScorePlugin    = &Plugin{}
+)
+
+// Plugin scores the nodes for the pending BE pods by the interference risk of the colocation. The risk is combined
+// from the sensitivity of the resident non-BE pods and the historical contention of the node, so that the BE pods
+// prefer the nodes where they are less likely to hurt the latency-sensitive pods.
+type Plugin struct {
+	handle           framework.Handle
+	args             *config.InterferenceAwareArgs
+	nodeMetricLister slolisters.NodeMetricLister
+}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	pluginArgs, ok := args.(*config.InterferenceAwareArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type InterferenceAwareArgs, got %T", args)
+	}
+	if err := validation.ValidateInterferenceAwareArgs(nil, pluginArgs); err != nil {
+		return nil, err
+	}
+
+	frameworkExtender, ok := handle.(frameworkext.ExtendedHandle)
+	if !ok {
+		return nil, fmt.Errorf("want handle to be of type frameworkext.ExtendedHandle, got %T", handle)
+	}
+
+	return &Plugin{
+		handle:           handle,
+		args:             pluginArgs,
+		nodeMetricLister: frameworkExtender.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Lister(),
+	}, nil
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) PreScore(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodes []*corev1.Node) *framework.Status {
+	if extension.GetPodQoSClassRaw(pod) != extension.QoSBE {
+		return framework.NewStatus(framework.Skip)
+	}
+	return nil
+}
+
+func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *corev1.Pod, nodeName string) (int64, *framework.Status) {
+	nodeInfo, err := p.handle.SnapshotSharedLister().NodeInfos().Get(nodeName)
+	if err != nil {
+		return 0, framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
+	}
+	risk := p.interferenceRisk(nodeInfo)
+	return framework.MaxNodeScore * (maxRisk - risk) / maxRisk, nil
+}
+
+func (p *Plugin) ScoreExtensions() framework.ScoreExtensions {
+	return nil
+}
+
+// interferenceRisk returns the interference risk in [0, 100] of placing a BE pod on the node.
+func (p *Plugin) interferenceRisk(nodeInfo *framework.NodeInfo) int64 {
+	contentionWeight := int64(0)
+	if p.args.ContentionWeight != nil {
+		contentionWeight = *p.args.ContentionWeight
+	}
+	sensitivityRisk := p.sensitivityRisk(nodeInfo)
+	contentionRisk := p.contentionRisk(nodeInfo)
+	risk := (sensitivityRisk*(maxRisk-contentionWeight) + contentionRisk*contentionWeight) / maxRisk
+	klog.V(6).InfoS("interference risk of node", "node", nodeInfo.Node().Name, "risk", risk,
+		"sensitivityRisk", sensitivityRisk, "contentionRisk", contentionRisk)
+	return risk
+}
+
+// sensitivityRisk sums the risks of the resident non-BE pods by their interference sensitivity.
+func (p *Plugin) sensitivityRisk(nodeInfo *framework.NodeInfo) int64 {
+	var risk int64
+	for _, podInfo := range nodeInfo.Pods {
+		pod := podInfo.Pod
+		if qosClass := extension.GetPodQoSClassWithDefault(pod); qosClass == extension.QoSBE || qosClass == extension.QoSSystem {
+			continue
+		}
+		sensitivity := extension.GetInterferenceSensitivity(pod.Annotations)
+		if sensitivity == extension.InterferenceSensitivityNone {
+			continue
+		}
+		risk += p.args.SensitivityRisks[sensitivity]
+		if risk >= maxRisk {
+			return maxRisk
+		}
+	}
+	return risk
+}
+
+// contentionRisk returns the historical contention of the node, which is the max network latency SLO violation
+// percent of the non-BE pods reported in the NodeMetric.
+func (p *Plugin) contentionRisk(nodeInfo *framework.NodeInfo) int64 {
+	nodeMetric, err := p.nodeMetricLister.Get(nodeInfo.Node().Name)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.V(5).InfoS("failed to get NodeMetric", "node", nodeInfo.Node().Name, "err", err)
+		}
+		return 0
+	}
+	return getContentionRisk(nodeMetric)
+}
+
+func getContentionRisk(nodeMetric *slov1alpha1.NodeMetric) int64 {
+	if nodeMetric.Status.NodeMetric == nil {
+		return 0
+	}
+	var risk int64
+	for _, latency := range nodeMetric.Status.NodeMetric.NetworkLatency {
+		if latency.QoS == extension.QoSBE {
+			continue
+		}
+		if latency.SLOViolationPercent > risk {
+			risk = latency.SLOViolationPercent
+		}
+	}
+	if risk > maxRisk {
+		return maxRisk
+	}
+	return risk
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package interferenceaware
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	frameworkruntime "k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	schedulertesting "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1beta3"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+)
+
+var _ framework.SharedLister = &testSharedLister{}
+
+type testSharedLister struct {
+	nodeInfos   []*framework.NodeInfo
+	nodeInfoMap map[string]*framework.NodeInfo
+}
+
+func newTestSharedLister(pods []*corev1.Pod, nodes []*corev1.Node) *testSharedLister {
+	nodeInfoMap := make(map[string]*framework.NodeInfo)
+	nodeInfos := make([]*framework.NodeInfo, 0)
+	for _, pod := range pods {
+		nodeName := pod.Spec.NodeName
+		if _, ok := nodeInfoMap[nodeName]; !ok {
+			nodeInfoMap[nodeName] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[nodeName].AddPod(pod)
+	}
+	for _, node := range nodes {
+		if _, ok := nodeInfoMap[node.Name]; !ok {
+			nodeInfoMap[node.Name] = framework.NewNodeInfo()
+		}
+		nodeInfoMap[node.Name].SetNode(node)
+	}
+	for _, v := range nodeInfoMap {
+		nodeInfos = append(nodeInfos, v)
+	}
+	return &testSharedLister{
+		nodeInfos:   nodeInfos,
+		nodeInfoMap: nodeInfoMap,
+	}
+}
+
+func (f *testSharedLister) StorageInfos() framework.StorageInfoLister {
+	return f
+}
+
+func (f *testSharedLister) IsPVCUsedByPods(key string) bool {
+	return false
+}
+
+func (f *testSharedLister) NodeInfos() framework.NodeInfoLister {
+	return f
+}
+
+func (f *testSharedLister) List() ([]*framework.NodeInfo, error) {
+	return f.nodeInfos, nil
+}
+
+func (f *testSharedLister) HavePodsWithAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) HavePodsWithRequiredAntiAffinityList() ([]*framework.NodeInfo, error) {
+	return nil, nil
+}
+
+func (f *testSharedLister) Get(nodeName string) (*framework.NodeInfo, error) {
+	return f.nodeInfoMap[nodeName], nil
+}
+
+func newTestPod(name, nodeName string, qos extension.QoSClass, sensitivity extension.InterferenceSensitivity) *corev1.Pod {
+	pod := schedulertesting.MakePod().Namespace("default").Name(name).Node(nodeName).Obj()
+	pod.Labels = map[string]string{extension.LabelPodQoS: string(qos)}
+	if sensitivity != extension.InterferenceSensitivityNone {
+		pod.Annotations = map[string]string{extension.AnnotationInterferenceSensitivity: string(sensitivity)}
+	}
+	return pod
+}
+
+func TestPlugin_Score(t *testing.T) {
+	var v1beta3args v1beta3.InterferenceAwareArgs
+	v1beta3.SetDefaults_InterferenceAwareArgs(&v1beta3args)
+	var args config.InterferenceAwareArgs
+	err := v1beta3.Convert_v1beta3_InterferenceAwareArgs_To_config_InterferenceAwareArgs(&v1beta3args, &args, nil)
+	assert.NoError(t, err)
+
+	koordClientSet := koordfake.NewSimpleClientset()
+	koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+	extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+		frameworkext.WithKoordinatorClientSet(koordClientSet),
+		frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+	)
+	proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "idle-node"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sensitive-node"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "contended-node"}},
+	}
+	pods := []*corev1.Pod{
+		newTestPod("ls-high", "sensitive-node", extension.QoSLS, extension.InterferenceSensitivityHigh),
+		newTestPod("lsr-medium", "sensitive-node", extension.QoSLSR, extension.InterferenceSensitivityMedium),
+		// the BE pods and the pods without the annotation are ignored
+		newTestPod("be-high", "idle-node", extension.QoSBE, extension.InterferenceSensitivityHigh),
+		newTestPod("ls-none", "idle-node", extension.QoSLS, extension.InterferenceSensitivityNone),
+		newTestPod("ls-low", "contended-node", extension.QoSLS, extension.InterferenceSensitivityLow),
+	}
+	_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{Name: "contended-node"},
+		Status: slov1alpha1.NodeMetricStatus{
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NetworkLatency: []slov1alpha1.QoSNetworkLatencyInfo{
+					{QoS: extension.QoSLS, SLOViolationPercent: 60},
+					{QoS: extension.QoSBE, SLOViolationPercent: 90},
+				},
+			},
+		},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	cs := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	snapshot := newTestSharedLister(pods, nodes)
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	assert.NoError(t, err)
+	p, err := proxyNew(&args, fh)
+	assert.NoError(t, err)
+	koordSharedInformerFactory.Start(context.TODO().Done())
+	koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+	plugin := p.(*Plugin)
+
+	// the non-BE pods are skipped
+	lsPod := newTestPod("pending-ls", "", extension.QoSLS, extension.InterferenceSensitivityNone)
+	status := plugin.PreScore(context.TODO(), framework.NewCycleState(), lsPod, nodes)
+	assert.True(t, status.IsSkip())
+
+	bePod := newTestPod("pending-be", "", extension.QoSBE, extension.InterferenceSensitivityNone)
+	status = plugin.PreScore(context.TODO(), framework.NewCycleState(), bePod, nodes)
+	assert.True(t, status.IsSuccess())
+	wantScores := map[string]int64{
+		// no risk
+		"idle-node": framework.MaxNodeScore,
+		// sensitivity risk 40 + 15, weighted by 50%
+		"sensitive-node": 73,
+		// sensitivity risk 5 and contention risk 60, weighted by 50%
+		"contended-node": 68,
+	}
+	for nodeName, want := range wantScores {
+		got, status := plugin.Score(context.TODO(), framework.NewCycleState(), bePod, nodeName)
+		assert.True(t, status.IsSuccess())
+		assert.Equal(t, want, got, nodeName)
+	}
+}
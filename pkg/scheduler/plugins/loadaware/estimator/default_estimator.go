@@ -66,9 +66,57 @@ func estimatedPodUsed(pod *corev1.Pod, resourceWeights map[corev1.ResourceName]i
 		realResourceName := extension.TranslateResourceNameByPriorityClass(priorityClass, resourceName)
 		estimatedUsed[resourceName] = estimatedUsedByResource(requests, limits, realResourceName, scalingFactors[resourceName])
 	}
+	if isPodInitializing(pod) {
+		// the init containers run one by one before the containers and are usually busy, e.g. to download the data,
+		// so the pod may use up to the peak of the init containers rather than the scaled requests during initializing
+		for resourceName := range resourceWeights {
+			realResourceName := extension.TranslateResourceNameByPriorityClass(priorityClass, resourceName)
+			if peak := initContainersPeakByResource(pod, realResourceName); peak > estimatedUsed[resourceName] {
+				estimatedUsed[resourceName] = peak
+			}
+		}
+	}
 	return estimatedUsed
 }
 
+// isPodInitializing checks if the pod has any init container not completed yet.
+func isPodInitializing(pod *corev1.Pod) bool {
+	if len(pod.Spec.InitContainers) == 0 {
+		return false
+	}
+	completed := 0
+	for i := range pod.Status.InitContainerStatuses {
+		state := pod.Status.InitContainerStatuses[i].State
+		if state.Terminated != nil && state.Terminated.ExitCode == 0 {
+			completed++
+		}
+	}
+	return completed < len(pod.Spec.InitContainers)
+}
+
+// initContainersPeakByResource returns the max of the init containers' limits, or the requests if unlimited.
+func initContainersPeakByResource(pod *corev1.Pod, resourceName corev1.ResourceName) int64 {
+	var peak int64
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		quantity, ok := container.Resources.Limits[resourceName]
+		if !ok || quantity.IsZero() {
+			quantity = container.Resources.Requests[resourceName]
+		}
+		var value int64
+		switch resourceName {
+		case corev1.ResourceCPU:
+			value = quantity.MilliValue()
+		default:
+			value = quantity.Value()
+		}
+		if value > peak {
+			peak = value
+		}
+	}
+	return peak
+}
+
 // TODO(joseph): Do we need to differentiate scalingFactor according to Koordinator Priority type?
 func estimatedUsedByResource(requests, limits corev1.ResourceList, resourceName corev1.ResourceName, scalingFactor int64) int64 {
 	limitQuantity := limits[resourceName]
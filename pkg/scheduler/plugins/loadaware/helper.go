@@ -185,6 +185,16 @@ func sumPodUsages(podMetrics map[types.NamespacedName]corev1.ResourceList, estim
 	return podUsages, estimatedPodsUsages
 }
 
+// addSystemUsage adds the resource usage of the daemon processes and OS kernel reported in the NodeMetric.
+func addSystemUsage(estimatedUsed map[corev1.ResourceName]int64, nodeMetric *slov1alpha1.NodeMetric) {
+	if nodeMetric == nil || nodeMetric.Status.NodeMetric == nil {
+		return
+	}
+	for resourceName, quantity := range nodeMetric.Status.NodeMetric.SystemUsage.ResourceList {
+		estimatedUsed[resourceName] += getResourceValue(resourceName, quantity)
+	}
+}
+
 // isDaemonSetPod returns true if the pod is a IsDaemonSetPod.
 func isDaemonSetPod(ownerRefList []metav1.OwnerReference) bool {
 	for _, ownerRef := range ownerRefList {
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
@@ -43,8 +44,11 @@ import (
 const (
 	Name                                    = "LoadAwareScheduling"
 	ErrReasonNodeMetricExpired              = "node(s) nodeMetric expired"
+	ErrReasonNodeMetricStale                = "node(s) nodeMetric stale"
+	ErrReasonColocationNotReady             = "node(s) colocation not ready"
 	ErrReasonUsageExceedThreshold           = "node(s) %s usage exceed threshold"
 	ErrReasonAggregatedUsageExceedThreshold = "node(s) %s aggregated usage exceed threshold"
+	ErrReasonMemoryBandwidthExceedThreshold = "node(s) memory bandwidth usage exceed threshold"
 	ErrReasonFailedEstimatePod
 )
 
@@ -98,13 +102,17 @@ func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error)
 		return nil, err
 	}
 
-	return &Plugin{
+	plugin := &Plugin{
 		handle:           handle,
 		args:             pluginArgs,
 		nodeMetricLister: nodeMetricLister,
 		estimator:        estimator,
 		podAssignCache:   assignCache,
-	}, nil
+	}
+	if pluginArgs.StaleNodeMetricPolicy != nil {
+		go wait.Until(plugin.recordNodeMetricStates, nodeMetricStateRecordInterval, context.TODO().Done())
+	}
+	return plugin, nil
 }
 
 func (p *Plugin) Name() string { return Name }
@@ -129,6 +137,11 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 		return nil
 	}
 
+	if p.args.FilterColocationNotReadyNodes && extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityBatch &&
+		extension.IsNodeColocationNotReady(node) {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonColocationNotReady)
+	}
+
 	nodeMetric, err := p.nodeMetricLister.Get(node.Name)
 	if err != nil {
 		// For nodes that lack load information, fall back to the situation where there is no load-aware scheduling.
@@ -147,9 +160,12 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 		}
 		return nil
 	}
-	if nodeMetric.Status.NodeMetric == nil {
-		klog.Warningf("nodeMetrics(%s) should not be nil.", node.Name)
-		return nil
+	staleAction := p.staleNodeMetricAction(nodeMetric)
+	if staleAction == config.StaleNodeMetricActionFilter {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonNodeMetricStale)
+	}
+	if status := p.filterMemoryBandwidth(node.Name, nodeMetric, pod); !status.IsSuccess() {
+		return status
 	}
 
 	allocatable, err := p.estimator.EstimateNode(node)
@@ -160,6 +176,24 @@ func (p *Plugin) Filter(ctx context.Context, state *framework.CycleState, pod *c
 	filterProfile := generateUsageThresholdsFilterProfile(node, p.args)
 	prodPod := len(filterProfile.ProdUsageThresholds) > 0 && extension.GetPodPriorityClassWithDefault(pod) == extension.PriorityProd
 
+	if staleAction == config.StaleNodeMetricActionRequestBased {
+		usageThresholds := filterProfile.UsageThresholds
+		if prodPod {
+			usageThresholds = filterProfile.ProdUsageThresholds
+		}
+		estimatedUsed, err := p.estimateRequestBasedUsed(nodeInfo, nodeMetric, pod)
+		if err != nil {
+			klog.ErrorS(err, "Estimated request based usage failed!", "node", node.Name)
+			return nil
+		}
+		return filterNodeUsage(usageThresholds, estimatedUsed, allocatable, prodPod, &usageThresholdsFilterProfile{})
+	}
+
+	if nodeMetric.Status.NodeMetric == nil {
+		klog.Warningf("nodeMetrics(%s) should not be nil.", node.Name)
+		return nil
+	}
+
 	var nodeUsage *slov1alpha1.ResourceMap
 	var usageThresholds map[corev1.ResourceName]int64
 	if prodPod {
@@ -219,6 +253,23 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 	if p.args.NodeMetricExpirationSeconds != nil && isNodeMetricExpired(nodeMetric, *p.args.NodeMetricExpirationSeconds) {
 		return 0, nil
 	}
+	staleAction := p.staleNodeMetricAction(nodeMetric)
+	switch staleAction {
+	case config.StaleNodeMetricActionFilter:
+		return 0, nil
+	case config.StaleNodeMetricActionRequestBased:
+		estimatedUsed, err := p.estimateRequestBasedUsed(nodeInfo, nodeMetric, pod)
+		if err != nil {
+			klog.ErrorS(err, "Estimated request based usage failed!", "node", node.Name)
+			return 0, nil
+		}
+		allocatable, err := p.estimator.EstimateNode(node)
+		if err != nil {
+			klog.ErrorS(err, "Estimated node allocatable failed!", "node", node.Name)
+			return 0, nil
+		}
+		return loadAwareSchedulingScorer(p.args.ResourceWeights, estimatedUsed, allocatable), nil
+	}
 	if nodeMetric.Status.NodeMetric == nil {
 		klog.Warningf("nodeMetrics(%s) should not be nil.", node.Name)
 		return 0, nil
@@ -245,6 +296,13 @@ func (p *Plugin) Score(ctx context.Context, state *framework.CycleState, pod *co
 		return 0, nil
 	}
 	score := loadAwareSchedulingScorer(p.args.ResourceWeights, estimatedUsed, allocatable)
+	if staleAction == config.StaleNodeMetricActionPenalty {
+		score = score * (100 - p.args.StaleNodeMetricPolicy.PenaltyScorePercent) / 100
+	}
+	if p.args.ThermalThrottledPenaltyScorePercent > 0 && extension.IsNodeCPUThermalThrottled(node) {
+		score = score * (100 - p.args.ThermalThrottledPenaltyScorePercent) / 100
+	}
+	score = p.scoreMemoryBandwidth(score, nodeName, nodeMetric, pod)
 	return score, nil
 }
 
@@ -267,6 +325,9 @@ func (p *Plugin) GetEstimatedUsed(nodeName string, nodeMetric *slov1alpha1.NodeM
 		for resourceName, quantity := range podActualUsages {
 			estimatedUsed[resourceName] += getResourceValue(resourceName, quantity)
 		}
+		if p.args.AccountSystemUsage {
+			addSystemUsage(estimatedUsed, nodeMetric)
+		}
 	} else {
 		if nodeMetric.Status.NodeMetric != nil {
 			if nodeUsage != nil {
@@ -2437,3 +2437,638 @@ func TestScore(t *testing.T) {
 		})
 	}
 }
+
+func TestStaleNodeMetricPolicy(t *testing.T) {
+	staleNodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+		Spec: slov1alpha1.NodeMetricSpec{
+			CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+				ReportIntervalSeconds: pointer.Int64(30),
+			},
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{
+				Time: time.Now().Add(-120 * time.Second),
+			},
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage: slov1alpha1.ResourceMap{
+					ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("30"),
+						corev1.ResourceMemory: resource.MustParse("32Gi"),
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name       string
+		policy     *v1beta3.LoadAwareStaleNodeMetricPolicy
+		wantStatus *framework.Status
+		wantScore  int64
+	}{
+		{
+			name:       "no stale policy",
+			wantStatus: framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)),
+			wantScore:  7,
+		},
+		{
+			name: "not stale within report intervals",
+			policy: &v1beta3.LoadAwareStaleNodeMetricPolicy{
+				StaleReportIntervals: 5,
+				Action:               v1beta3.StaleNodeMetricActionFilter,
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)),
+			wantScore:  7,
+		},
+		{
+			name: "fall back to request based estimation",
+			policy: &v1beta3.LoadAwareStaleNodeMetricPolicy{
+				Action: v1beta3.StaleNodeMetricActionRequestBased,
+			},
+			wantStatus: nil,
+			wantScore:  60,
+		},
+		{
+			name: "apply penalty score",
+			policy: &v1beta3.LoadAwareStaleNodeMetricPolicy{
+				Action: v1beta3.StaleNodeMetricActionPenalty,
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)),
+			wantScore:  3,
+		},
+		{
+			name: "filter stale node",
+			policy: &v1beta3.LoadAwareStaleNodeMetricPolicy{
+				Action: v1beta3.StaleNodeMetricActionFilter,
+			},
+			wantStatus: framework.NewStatus(framework.Unschedulable, ErrReasonNodeMetricStale),
+			wantScore:  0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v1beta3args v1beta3.LoadAwareSchedulingArgs
+			v1beta3args.StaleNodeMetricPolicy = tt.policy
+			v1beta3.SetDefaults_LoadAwareSchedulingArgs(&v1beta3args)
+			var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
+			err := v1beta3.Convert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta3args, &loadAwareSchedulingArgs, nil)
+			assert.NoError(t, err)
+
+			koordClientSet := koordfake.NewSimpleClientset()
+			koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+			extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+				frameworkext.WithKoordinatorClientSet(koordClientSet),
+				frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+			)
+			proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+			cs := kubefake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+
+			nodes := []*corev1.Node{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: staleNodeMetric.Name,
+					},
+					Status: corev1.NodeStatus{
+						Allocatable: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("32"),
+							corev1.ResourceMemory: resource.MustParse("64Gi"),
+						},
+					},
+				},
+			}
+
+			snapshot := newTestSharedLister(nil, nodes)
+			registeredPlugins := []schedulertesting.RegisterPluginFunc{
+				schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithSnapshotSharedLister(snapshot),
+			)
+			assert.Nil(t, err)
+
+			p, err := proxyNew(&loadAwareSchedulingArgs, fh)
+			assert.NotNil(t, p)
+			assert.Nil(t, err)
+
+			_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), staleNodeMetric, metav1.CreateOptions{})
+			assert.NoError(t, err)
+
+			koordSharedInformerFactory.Start(context.TODO().Done())
+			koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "test-pod-1",
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("16"),
+									corev1.ResourceMemory: resource.MustParse("32Gi"),
+								},
+							},
+						},
+					},
+				},
+			}
+
+			nodeInfo, err := snapshot.Get(staleNodeMetric.Name)
+			assert.NoError(t, err)
+			status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), pod, nodeInfo)
+			assert.True(t, tt.wantStatus.Equal(status), "want status: %s, but got %s", tt.wantStatus.Message(), status.Message())
+
+			score, status := p.(*Plugin).Score(context.TODO(), framework.NewCycleState(), pod, staleNodeMetric.Name)
+			assert.Nil(t, status)
+			assert.Equal(t, tt.wantScore, score)
+		})
+	}
+}
+
+func TestThermalThrottledPenalty(t *testing.T) {
+	var v1beta3args v1beta3.LoadAwareSchedulingArgs
+	v1beta3args.ThermalThrottledPenaltyScorePercent = 50
+	v1beta3.SetDefaults_LoadAwareSchedulingArgs(&v1beta3args)
+	var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
+	err := v1beta3.Convert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta3args, &loadAwareSchedulingArgs, nil)
+	assert.NoError(t, err)
+
+	koordClientSet := koordfake.NewSimpleClientset()
+	koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+	extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+		frameworkext.WithKoordinatorClientSet(koordClientSet),
+		frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+	)
+	proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+	cs := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+
+	var nodes []*corev1.Node
+	for i, status := range []corev1.ConditionStatus{corev1.ConditionFalse, corev1.ConditionTrue} {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("test-node-%d", i),
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("32"),
+					corev1.ResourceMemory: resource.MustParse("64Gi"),
+				},
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:   extension.NodeConditionCPUThermalThrottled,
+						Status: status,
+					},
+				},
+			},
+		}
+		nodes = append(nodes, node)
+		_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), &slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: node.Name,
+			},
+			Spec: slov1alpha1.NodeMetricSpec{
+				CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+					ReportIntervalSeconds: pointer.Int64(60),
+				},
+			},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &metav1.Time{
+					Time: time.Now(),
+				},
+				NodeMetric: &slov1alpha1.NodeMetricInfo{
+					NodeUsage: slov1alpha1.ResourceMap{
+						ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("8"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+				},
+			},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	snapshot := newTestSharedLister(nil, nodes)
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	assert.Nil(t, err)
+
+	p, err := proxyNew(&loadAwareSchedulingArgs, fh)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	koordSharedInformerFactory.Start(context.TODO().Done())
+	koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-1",
+		},
+	}
+	normalScore, status := p.(*Plugin).Score(context.TODO(), framework.NewCycleState(), pod, nodes[0].Name)
+	assert.Nil(t, status)
+	assert.True(t, normalScore > 0)
+	throttledScore, status := p.(*Plugin).Score(context.TODO(), framework.NewCycleState(), pod, nodes[1].Name)
+	assert.Nil(t, status)
+	assert.Equal(t, normalScore*50/100, throttledScore)
+}
+
+func TestMemoryBandwidth(t *testing.T) {
+	var v1beta3args v1beta3.LoadAwareSchedulingArgs
+	v1beta3args.MemoryBandwidthUsageThresholdPercent = 80
+	v1beta3args.ScoreAccordingMemoryBandwidth = true
+	v1beta3.SetDefaults_LoadAwareSchedulingArgs(&v1beta3args)
+	var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
+	err := v1beta3.Convert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta3args, &loadAwareSchedulingArgs, nil)
+	assert.NoError(t, err)
+
+	koordClientSet := koordfake.NewSimpleClientset()
+	koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+	extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+		frameworkext.WithKoordinatorClientSet(koordClientSet),
+		frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+	)
+	proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+	cs := kubefake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+
+	var nodes []*corev1.Node
+	for i, bandwidth := range []*slov1alpha1.NodeMemoryBandwidthInfo{
+		// the capacity is unknown
+		{Used: resource.MustParse("50Gi")},
+		{Capacity: resource.NewQuantity(100<<30, resource.BinarySI), Used: resource.MustParse("20Gi")},
+		{Capacity: resource.NewQuantity(100<<30, resource.BinarySI), Used: resource.MustParse("75Gi")},
+	} {
+		node := &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: fmt.Sprintf("test-node-%d", i),
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("32"),
+					corev1.ResourceMemory: resource.MustParse("64Gi"),
+				},
+			},
+		}
+		nodes = append(nodes, node)
+		_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), &slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: node.Name,
+			},
+			Spec: slov1alpha1.NodeMetricSpec{
+				CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+					ReportIntervalSeconds: pointer.Int64(60),
+				},
+			},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &metav1.Time{
+					Time: time.Now(),
+				},
+				NodeMetric: &slov1alpha1.NodeMetricInfo{
+					NodeUsage: slov1alpha1.ResourceMap{
+						ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("8"),
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+					MemoryBandwidth: bandwidth,
+				},
+			},
+		}, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+
+	snapshot := newTestSharedLister(nil, nodes)
+	registeredPlugins := []schedulertesting.RegisterPluginFunc{
+		schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+		schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+	}
+	fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+		frameworkruntime.WithClientSet(cs),
+		frameworkruntime.WithInformerFactory(informerFactory),
+		frameworkruntime.WithSnapshotSharedLister(snapshot),
+	)
+	assert.Nil(t, err)
+
+	p, err := proxyNew(&loadAwareSchedulingArgs, fh)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	koordSharedInformerFactory.Start(context.TODO().Done())
+	koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+	normalPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-1",
+		},
+	}
+	bandwidthPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod-2",
+			UID:       "test-pod-2",
+			Annotations: map[string]string{
+				extension.AnnotationMemoryBandwidthRequest: "10Gi",
+			},
+		},
+	}
+
+	// the pods without the request are not affected
+	var normalScores []int64
+	for _, node := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), normalPod, nodeInfo)
+		assert.True(t, status.IsSuccess())
+		score, status := p.(*Plugin).Score(context.TODO(), framework.NewCycleState(), normalPod, node.Name)
+		assert.Nil(t, status)
+		normalScores = append(normalScores, score)
+	}
+	assert.Equal(t, normalScores[0], normalScores[1])
+	assert.Equal(t, normalScores[0], normalScores[2])
+
+	wantFilterSuccess := []bool{true, true, false}
+	// the score is scaled by the free ratio, i.e. (100 - 20 - 10) / 100 and (100 - 75 - 10) / 100
+	wantScores := []int64{normalScores[0], normalScores[1] * 70 / 100, normalScores[2] * 15 / 100}
+	for i, node := range nodes {
+		nodeInfo := framework.NewNodeInfo()
+		nodeInfo.SetNode(node)
+		status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), bandwidthPod, nodeInfo)
+		assert.Equal(t, wantFilterSuccess[i], status.IsSuccess(), "node %s", node.Name)
+		score, status := p.(*Plugin).Score(context.TODO(), framework.NewCycleState(), bandwidthPod, node.Name)
+		assert.Nil(t, status)
+		assert.Equal(t, wantScores[i], score, "node %s", node.Name)
+	}
+
+	// the assigned pods are counted before measured
+	assignedPod := bandwidthPod.DeepCopy()
+	assignedPod.Name, assignedPod.UID = "test-pod-3", "test-pod-3"
+	assignedPod.Annotations[extension.AnnotationMemoryBandwidthRequest] = "55Gi"
+	p.(*Plugin).podAssignCache.assign(nodes[1].Name, assignedPod)
+	nodeInfo := framework.NewNodeInfo()
+	nodeInfo.SetNode(nodes[1])
+	status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), bandwidthPod, nodeInfo)
+	assert.Equal(t, ErrReasonMemoryBandwidthExceedThreshold, status.Message())
+}
+
+func TestGetNodeMetricState(t *testing.T) {
+	p := &Plugin{
+		args: &config.LoadAwareSchedulingArgs{
+			NodeMetricExpirationSeconds: pointer.Int64(180),
+			StaleNodeMetricPolicy: &config.LoadAwareStaleNodeMetricPolicy{
+				StaleReportIntervals: 3,
+				Action:               config.StaleNodeMetricActionRequestBased,
+			},
+		},
+	}
+	newNodeMetric := func(updateTime time.Time) *slov1alpha1.NodeMetric {
+		return &slov1alpha1.NodeMetric{
+			Spec: slov1alpha1.NodeMetricSpec{
+				CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+					ReportIntervalSeconds: pointer.Int64(30),
+				},
+			},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &metav1.Time{Time: updateTime},
+			},
+		}
+	}
+	assert.Equal(t, nodeMetricStateFresh, p.getNodeMetricState(newNodeMetric(time.Now())))
+	assert.Equal(t, nodeMetricStateStale, p.getNodeMetricState(newNodeMetric(time.Now().Add(-120*time.Second))))
+	assert.Equal(t, nodeMetricStateExpired, p.getNodeMetricState(newNodeMetric(time.Now().Add(-200*time.Second))))
+}
+
+func TestFilterWithSystemUsage(t *testing.T) {
+	tests := []struct {
+		name               string
+		accountSystemUsage bool
+		wantStatus         *framework.Status
+	}{
+		{
+			name:               "system usage not accounted",
+			accountSystemUsage: false,
+			wantStatus:         nil,
+		},
+		{
+			name:               "system usage accounted",
+			accountSystemUsage: true,
+			wantStatus:         framework.NewStatus(framework.Unschedulable, fmt.Sprintf(ErrReasonUsageExceedThreshold, corev1.ResourceCPU)),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v1beta3args v1beta3.LoadAwareSchedulingArgs
+			v1beta3args.ProdUsageThresholds = map[corev1.ResourceName]int64{
+				corev1.ResourceCPU: 50,
+			}
+			v1beta3args.AccountSystemUsage = tt.accountSystemUsage
+			v1beta3.SetDefaults_LoadAwareSchedulingArgs(&v1beta3args)
+			var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
+			err := v1beta3.Convert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta3args, &loadAwareSchedulingArgs, nil)
+			assert.NoError(t, err)
+
+			koordClientSet := koordfake.NewSimpleClientset()
+			koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+			extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+				frameworkext.WithKoordinatorClientSet(koordClientSet),
+				frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+			)
+			proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+			cs := kubefake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("32"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+				},
+			}
+			// the host daemons use 20 cores, which are not counted by the prod usage
+			_, err = koordClientSet.SloV1alpha1().NodeMetrics().Create(context.TODO(), &slov1alpha1.NodeMetric{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: node.Name,
+				},
+				Spec: slov1alpha1.NodeMetricSpec{
+					CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+						ReportIntervalSeconds: pointer.Int64(60),
+					},
+				},
+				Status: slov1alpha1.NodeMetricStatus{
+					UpdateTime: &metav1.Time{
+						Time: time.Now(),
+					},
+					NodeMetric: &slov1alpha1.NodeMetricInfo{
+						NodeUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("24"),
+								corev1.ResourceMemory: resource.MustParse("16Gi"),
+							},
+						},
+						SystemUsage: slov1alpha1.ResourceMap{
+							ResourceList: corev1.ResourceList{
+								corev1.ResourceCPU:    resource.MustParse("20"),
+								corev1.ResourceMemory: resource.MustParse("4Gi"),
+							},
+						},
+					},
+				},
+			}, metav1.CreateOptions{})
+			assert.NoError(t, err)
+
+			snapshot := newTestSharedLister(nil, []*corev1.Node{node})
+			registeredPlugins := []schedulertesting.RegisterPluginFunc{
+				schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithSnapshotSharedLister(snapshot),
+			)
+			assert.Nil(t, err)
+
+			p, err := proxyNew(&loadAwareSchedulingArgs, fh)
+			assert.NotNil(t, p)
+			assert.Nil(t, err)
+
+			koordSharedInformerFactory.Start(context.TODO().Done())
+			koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+			pod := schedulertesting.MakePod().Namespace("default").Name("prod-pod").Priority(extension.PriorityProdValueMax).Obj()
+			nodeInfo, err := snapshot.Get(node.Name)
+			assert.NoError(t, err)
+			status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), pod, nodeInfo)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
+
+func TestFilterColocationNotReady(t *testing.T) {
+	tests := []struct {
+		name            string
+		enabled         bool
+		priority        int32
+		conditionStatus corev1.ConditionStatus
+		wantStatus      *framework.Status
+	}{
+		{
+			name:            "filter disabled",
+			enabled:         false,
+			priority:        extension.PriorityBatchValueMax,
+			conditionStatus: corev1.ConditionFalse,
+			wantStatus:      nil,
+		},
+		{
+			name:            "batch pod on colocation not ready node",
+			enabled:         true,
+			priority:        extension.PriorityBatchValueMax,
+			conditionStatus: corev1.ConditionFalse,
+			wantStatus:      framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrReasonColocationNotReady),
+		},
+		{
+			name:            "batch pod on colocation ready node",
+			enabled:         true,
+			priority:        extension.PriorityBatchValueMax,
+			conditionStatus: corev1.ConditionTrue,
+			wantStatus:      nil,
+		},
+		{
+			name:            "prod pod on colocation not ready node",
+			enabled:         true,
+			priority:        extension.PriorityProdValueMax,
+			conditionStatus: corev1.ConditionFalse,
+			wantStatus:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v1beta3args v1beta3.LoadAwareSchedulingArgs
+			v1beta3args.FilterColocationNotReadyNodes = tt.enabled
+			v1beta3.SetDefaults_LoadAwareSchedulingArgs(&v1beta3args)
+			var loadAwareSchedulingArgs config.LoadAwareSchedulingArgs
+			err := v1beta3.Convert_v1beta3_LoadAwareSchedulingArgs_To_config_LoadAwareSchedulingArgs(&v1beta3args, &loadAwareSchedulingArgs, nil)
+			assert.NoError(t, err)
+
+			koordClientSet := koordfake.NewSimpleClientset()
+			koordSharedInformerFactory := koordinatorinformers.NewSharedInformerFactory(koordClientSet, 0)
+			extenderFactory, _ := frameworkext.NewFrameworkExtenderFactory(
+				frameworkext.WithKoordinatorClientSet(koordClientSet),
+				frameworkext.WithKoordinatorSharedInformerFactory(koordSharedInformerFactory),
+			)
+			proxyNew := frameworkext.PluginFactoryProxy(extenderFactory, New)
+
+			cs := kubefake.NewSimpleClientset()
+			informerFactory := informers.NewSharedInformerFactory(cs, 0)
+
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node-1",
+				},
+				Status: corev1.NodeStatus{
+					Allocatable: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("32"),
+						corev1.ResourceMemory: resource.MustParse("64Gi"),
+					},
+					Conditions: []corev1.NodeCondition{
+						{
+							Type:   extension.NodeConditionColocationReady,
+							Status: tt.conditionStatus,
+						},
+					},
+				},
+			}
+
+			snapshot := newTestSharedLister(nil, []*corev1.Node{node})
+			registeredPlugins := []schedulertesting.RegisterPluginFunc{
+				schedulertesting.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+				schedulertesting.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+			}
+			fh, err := schedulertesting.NewFramework(context.TODO(), registeredPlugins, "koord-scheduler",
+				frameworkruntime.WithClientSet(cs),
+				frameworkruntime.WithInformerFactory(informerFactory),
+				frameworkruntime.WithSnapshotSharedLister(snapshot),
+			)
+			assert.Nil(t, err)
+
+			p, err := proxyNew(&loadAwareSchedulingArgs, fh)
+			assert.NotNil(t, p)
+			assert.Nil(t, err)
+
+			koordSharedInformerFactory.Start(context.TODO().Done())
+			koordSharedInformerFactory.WaitForCacheSync(context.TODO().Done())
+
+			pod := schedulertesting.MakePod().Namespace("default").Name("test-pod").Priority(tt.priority).Obj()
+			nodeInfo, err := snapshot.Get(node.Name)
+			assert.NoError(t, err)
+			status := p.(*Plugin).Filter(context.TODO(), framework.NewCycleState(), pod, nodeInfo)
+			assert.Equal(t, tt.wantStatus, status)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// estimateMemoryBandwidth returns the estimated memory bandwidth usage of the node after placing the pod and the
+// memory bandwidth capacity of the node in bytes per second. The estimated usage is the measured usage in the
+// NodeMetric plus the requests of the pod and the assigned pods not measured yet.
+// It returns false if the pod declares no request or the node reports no capacity.
+func (p *Plugin) estimateMemoryBandwidth(nodeName string, nodeMetric *slov1alpha1.NodeMetric, pod *corev1.Pod) (int64, int64, bool) {
+	request, err := extension.GetPodMemoryBandwidthRequest(pod)
+	if err != nil {
+		klog.V(5).InfoS("failed to get memory bandwidth request of pod", "pod", klog.KObj(pod), "err", err)
+		return 0, 0, false
+	}
+	if request <= 0 || nodeMetric == nil || nodeMetric.Status.NodeMetric == nil {
+		return 0, 0, false
+	}
+	bandwidth := nodeMetric.Status.NodeMetric.MemoryBandwidth
+	if bandwidth == nil || bandwidth.Capacity == nil || bandwidth.Capacity.Value() <= 0 {
+		return 0, 0, false
+	}
+
+	var nodeMetricUpdateTime time.Time
+	if nodeMetric.Status.UpdateTime != nil {
+		nodeMetricUpdateTime = nodeMetric.Status.UpdateTime.Time
+	}
+	nodeMetricReportInterval := getNodeMetricReportInterval(nodeMetric)
+	estimatedUsed := bandwidth.Used.Value() + request
+	for _, assignInfo := range p.podAssignCache.getPodsAssignInfoOnNode(nodeName) {
+		if assignInfo.pod.UID == pod.UID {
+			continue
+		}
+		if !missedLatestUpdateTime(assignInfo.timestamp, nodeMetricUpdateTime) &&
+			!stillInTheReportInterval(assignInfo.timestamp, nodeMetricUpdateTime, nodeMetricReportInterval) {
+			continue
+		}
+		if assignedRequest, err := extension.GetPodMemoryBandwidthRequest(assignInfo.pod); err == nil {
+			estimatedUsed += assignedRequest
+		}
+	}
+	return estimatedUsed, bandwidth.Capacity.Value(), true
+}
+
+func (p *Plugin) filterMemoryBandwidth(nodeName string, nodeMetric *slov1alpha1.NodeMetric, pod *corev1.Pod) *framework.Status {
+	if p.args.MemoryBandwidthUsageThresholdPercent <= 0 {
+		return nil
+	}
+	estimatedUsed, capacity, ok := p.estimateMemoryBandwidth(nodeName, nodeMetric, pod)
+	if !ok {
+		return nil
+	}
+	if estimatedUsed*100 > capacity*p.args.MemoryBandwidthUsageThresholdPercent {
+		return framework.NewStatus(framework.Unschedulable, ErrReasonMemoryBandwidthExceedThreshold)
+	}
+	return nil
+}
+
+// scoreMemoryBandwidth scales the score by the ratio of the free memory bandwidth after placing the pod.
+func (p *Plugin) scoreMemoryBandwidth(score int64, nodeName string, nodeMetric *slov1alpha1.NodeMetric, pod *corev1.Pod) int64 {
+	if !p.args.ScoreAccordingMemoryBandwidth {
+		return score
+	}
+	estimatedUsed, capacity, ok := p.estimateMemoryBandwidth(nodeName, nodeMetric, pod)
+	if !ok {
+		return score
+	}
+	if estimatedUsed >= capacity {
+		return 0
+	}
+	return int64(float64(score) * float64(capacity-estimatedUsed) / float64(capacity))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loadaware
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/metrics"
+)
+
+const (
+	nodeMetricStateFresh   = "fresh"
+	nodeMetricStateStale   = "stale"
+	nodeMetricStateExpired = "expired"
+
+	nodeMetricStateRecordInterval = 30 * time.Second
+)
+
+func isNodeMetricStale(nodeMetric *slov1alpha1.NodeMetric, staleReportIntervals int64) bool {
+	return nodeMetric == nil ||
+		nodeMetric.Status.UpdateTime == nil ||
+		staleReportIntervals > 0 &&
+			time.Since(nodeMetric.Status.UpdateTime.Time) >= time.Duration(staleReportIntervals)*getNodeMetricReportInterval(nodeMetric)
+}
+
+// staleNodeMetricAction returns the action configured for a stale NodeMetric,
+// or an empty action if the policy is disabled or the NodeMetric is not stale.
+func (p *Plugin) staleNodeMetricAction(nodeMetric *slov1alpha1.NodeMetric) config.StaleNodeMetricAction {
+	policy := p.args.StaleNodeMetricPolicy
+	if policy == nil || !isNodeMetricStale(nodeMetric, policy.StaleReportIntervals) {
+		return ""
+	}
+	return policy.Action
+}
+
+func (p *Plugin) getNodeMetricState(nodeMetric *slov1alpha1.NodeMetric) string {
+	if p.args.NodeMetricExpirationSeconds != nil && isNodeMetricExpired(nodeMetric, *p.args.NodeMetricExpirationSeconds) {
+		return nodeMetricStateExpired
+	}
+	if p.staleNodeMetricAction(nodeMetric) != "" {
+		return nodeMetricStateStale
+	}
+	return nodeMetricStateFresh
+}
+
+func (p *Plugin) recordNodeMetricStates() {
+	nodeMetrics, err := p.nodeMetricLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to list NodeMetrics")
+		return
+	}
+	counts := map[string]int{
+		nodeMetricStateFresh:   0,
+		nodeMetricStateStale:   0,
+		nodeMetricStateExpired: 0,
+	}
+	for _, nodeMetric := range nodeMetrics {
+		counts[p.getNodeMetricState(nodeMetric)]++
+	}
+	for state, count := range counts {
+		metrics.RecordLoadAwareNodeMetricState(state, count)
+	}
+}
+
+// estimateRequestBasedUsed estimates the node usage only by the estimated usage of the pods on the node
+// and the pod to be scheduled, which is used when the NodeMetric cannot be trusted.
+// The last reported system usage is added if AccountSystemUsage is enabled, since it is not covered by the requests.
+func (p *Plugin) estimateRequestBasedUsed(nodeInfo *framework.NodeInfo, nodeMetric *slov1alpha1.NodeMetric, pod *corev1.Pod) (map[corev1.ResourceName]int64, error) {
+	estimatedUsed, err := p.estimator.EstimatePod(pod)
+	if err != nil {
+		return nil, err
+	}
+	for _, podInfo := range nodeInfo.Pods {
+		if podInfo.Pod.UID == pod.UID {
+			continue
+		}
+		estimated, err := p.estimator.EstimatePod(podInfo.Pod)
+		if err != nil {
+			continue
+		}
+		for resourceName, value := range estimated {
+			estimatedUsed[resourceName] += value
+		}
+	}
+	if p.args.AccountSystemUsage {
+		addSystemUsage(estimatedUsed, nodeMetric)
+	}
+	return estimatedUsed, nil
+}
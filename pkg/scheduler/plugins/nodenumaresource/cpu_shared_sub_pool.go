@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// matchCPUSharedSubPool returns the first CPU shared sub-pool whose priority range contains the priority of the pod.
+// The BE Pods run in the BE shared pool, so they are never assigned to a sub-pool.
+func matchCPUSharedSubPool(object metav1.Object, subPools []extension.CPUSharedSubPool) *extension.CPUSharedSubPool {
+	pod, ok := object.(*corev1.Pod)
+	if !ok || len(subPools) <= 0 {
+		return nil
+	}
+	if extension.GetPodQoSClassWithDefault(pod) == extension.QoSBE {
+		return nil
+	}
+	priority := *extension.GetPodPriorityValueWithDefault(pod)
+	for i := range subPools {
+		if subPools[i].IsPriorityMatched(priority) {
+			return &subPools[i]
+		}
+	}
+	return nil
+}
+
+// filterCPUSharedSubPool checks if the CPU shared sub-pool of the pod is large enough for the CPU requests of the pod
+// and the pods already running in the sub-pool. The pods bound to the exclusive CPUs are not in the sub-pool.
+func (p *Plugin) filterCPUSharedSubPool(pod *corev1.Pod, podRequestMilliCPU int64, nodeInfo *framework.NodeInfo, subPools []extension.CPUSharedSubPool) *framework.Status {
+	subPool := matchCPUSharedSubPool(pod, subPools)
+	if subPool == nil || podRequestMilliCPU == 0 {
+		return nil
+	}
+	numCPUs := 0
+	for _, pool := range subPool.Pools {
+		cpus, err := cpuset.Parse(pool.CPUSet)
+		if err != nil {
+			return framework.NewStatus(framework.UnschedulableAndUnresolvable, err.Error())
+		}
+		numCPUs += cpus.Size()
+	}
+	if int64(numCPUs)*1000 < podRequestMilliCPU {
+		return framework.NewStatus(framework.UnschedulableAndUnresolvable, ErrInsufficientCPUSharedSubPool)
+	}
+
+	requestedMilliCPU := int64(0)
+	for _, podInfo := range nodeInfo.Pods {
+		assignedPod := podInfo.Pod
+		if assignedPod.UID == pod.UID {
+			continue
+		}
+		if matched := matchCPUSharedSubPool(assignedPod, subPools); matched == nil || matched.Name != subPool.Name {
+			continue
+		}
+		if cpus, ok := p.resourceManager.GetAllocatedCPUSet(nodeInfo.Node().Name, assignedPod.UID); ok && !cpus.IsEmpty() {
+			continue
+		}
+		requests := resourceapi.PodRequests(assignedPod, resourceapi.PodResourcesOptions{})
+		requestedMilliCPU += requests.Cpu().MilliValue()
+	}
+	if requestedMilliCPU+podRequestMilliCPU > int64(numCPUs)*1000 {
+		return framework.NewStatus(framework.Unschedulable, ErrInsufficientCPUSharedSubPool)
+	}
+	return nil
+}
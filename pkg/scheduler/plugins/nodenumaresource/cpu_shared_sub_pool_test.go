@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+func TestFilterCPUSharedSubPool(t *testing.T) {
+	subPools := []extension.CPUSharedSubPool{
+		{
+			Name:        "ls-high",
+			MinPriority: 9500,
+			MaxPriority: 9999,
+			Pools: []extension.CPUSharedPool{
+				{Socket: 0, Node: 0, CPUSet: "0-1"},
+				{Socket: 1, Node: 1, CPUSet: "8-9"},
+			},
+		},
+	}
+	newPod := func(name string, qos extension.QoSClass, priority int32, milliCPU int64) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:  types.UID(name),
+				Name: name,
+				Labels: map[string]string{
+					extension.LabelPodQoS: string(qos),
+				},
+			},
+			Spec: corev1.PodSpec{
+				Priority: pointer.Int32(priority),
+				Containers: []corev1.Container{
+					{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	tests := []struct {
+		name         string
+		qos          extension.QoSClass
+		priority     int32
+		milliCPU     int64
+		assignedPods []*corev1.Pod
+		wantStatus   bool
+	}{
+		{
+			name:       "sub-pool is large enough",
+			qos:        extension.QoSLS,
+			priority:   9600,
+			milliCPU:   4000,
+			wantStatus: true,
+		},
+		{
+			name:       "insufficient cpus in the sub-pool",
+			qos:        extension.QoSLS,
+			priority:   9600,
+			milliCPU:   4500,
+			wantStatus: false,
+		},
+		{
+			name:     "insufficient cpus with the pods in the sub-pool",
+			qos:      extension.QoSLS,
+			priority: 9600,
+			milliCPU: 2000,
+			assignedPods: []*corev1.Pod{
+				newPod("assigned-pod-1", extension.QoSLS, 9500, 1500),
+				newPod("assigned-pod-2", extension.QoSLS, 9999, 1000),
+			},
+			wantStatus: false,
+		},
+		{
+			name:     "pods out of the sub-pool are not accounted",
+			qos:      extension.QoSLS,
+			priority: 9600,
+			milliCPU: 2000,
+			assignedPods: []*corev1.Pod{
+				newPod("assigned-pod-1", extension.QoSLS, 9500, 1500),
+				newPod("assigned-pod-2", extension.QoSLS, 9100, 4000),
+				newPod("assigned-pod-3", extension.QoSBE, 9600, 4000),
+				newPod("cpu-bound-pod", extension.QoSLSR, 9600, 2000),
+			},
+			wantStatus: true,
+		},
+		{
+			name:       "pod out of the priority range",
+			qos:        extension.QoSLS,
+			priority:   9100,
+			milliCPU:   8000,
+			wantStatus: true,
+		},
+		{
+			name:       "BE pod is not assigned to the sub-pool",
+			qos:        extension.QoSBE,
+			priority:   9600,
+			milliCPU:   8000,
+			wantStatus: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}
+			nodeInfo := framework.NewNodeInfo(tt.assignedPods...)
+			nodeInfo.SetNode(node)
+			cpuTopology := buildCPUTopologyForTest(2, 1, 4, 2)
+			nodeAllocation := NewNodeAllocation(node.Name)
+			nodeAllocation.update(&PodAllocation{
+				UID:    "cpu-bound-pod",
+				Name:   "cpu-bound-pod",
+				CPUSet: cpuset.NewCPUSet(2, 3),
+			}, cpuTopology)
+			p := &Plugin{
+				resourceManager: &resourceManager{
+					nodeAllocations: map[string]*NodeAllocation{node.Name: nodeAllocation},
+				},
+			}
+			pod := newPod("test-pod", tt.qos, tt.priority, tt.milliCPU)
+			status := p.filterCPUSharedSubPool(pod, tt.milliCPU, nodeInfo, subPools)
+			assert.Equal(t, tt.wantStatus, status.IsSuccess())
+		})
+	}
+}
@@ -28,12 +28,15 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/klog/v2"
 	resourceapi "k8s.io/kubernetes/pkg/api/v1/resource"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 
 	"github.com/koordinator-sh/koordinator/apis/extension"
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
@@ -55,6 +58,7 @@ const (
 	ErrCPUBindPolicyConflict        = "node(s) cpu bind policy conflicts with pod's required cpu bind policy"
 	ErrInvalidCPUAmplificationRatio = "node(s) invalid CPU amplification ratio"
 	ErrInsufficientAmplifiedCPU     = "Insufficient amplified cpu"
+	ErrInsufficientCPUSharedSubPool = "Insufficient CPUs in the shared sub-pool"
 )
 
 var (
@@ -74,12 +78,13 @@ var (
 )
 
 type Plugin struct {
-	handle          frameworkext.ExtendedHandle
-	pluginArgs      *schedulingconfig.NodeNUMAResourceArgs
-	nrtLister       topologylister.NodeResourceTopologyLister
-	scorer          *resourceAllocationScorer
-	numaScorer      *resourceAllocationScorer
-	resourceManager ResourceManager
+	handle           frameworkext.ExtendedHandle
+	pluginArgs       *schedulingconfig.NodeNUMAResourceArgs
+	nrtLister        topologylister.NodeResourceTopologyLister
+	nodeMetricLister slolisters.NodeMetricLister
+	scorer           *resourceAllocationScorer
+	numaScorer       *resourceAllocationScorer
+	resourceManager  ResourceManager
 
 	topologyOptionsManager TopologyOptionsManager
 }
@@ -153,10 +158,17 @@ func NewWithOptions(args runtime.Object, handle framework.Handle, opts ...Option
 
 	nrtLister := nrtInformerFactory.Topology().V1alpha1().NodeResourceTopologies().Lister()
 
+	extendedHandle := handle.(frameworkext.ExtendedHandle)
+	var nodeMetricLister slolisters.NodeMetricLister
+	if pluginArgs.ZoneMemoryScoringWeight > 0 {
+		nodeMetricLister = extendedHandle.KoordinatorSharedInformerFactory().Slo().V1alpha1().NodeMetrics().Lister()
+	}
+
 	return &Plugin{
-		handle:                 handle.(frameworkext.ExtendedHandle),
+		handle:                 extendedHandle,
 		pluginArgs:             pluginArgs,
 		nrtLister:              nrtLister,
+		nodeMetricLister:       nodeMetricLister,
 		scorer:                 scorer,
 		numaScorer:             numaScorer,
 		resourceManager:        options.resourceManager,
@@ -351,6 +363,12 @@ func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, p
 		return status
 	}
 
+	if !requestCPUBind {
+		if status := p.filterCPUSharedSubPool(pod, state.requests.Cpu().MilliValue(), nodeInfo, topologyOptions.CPUSharedSubPools); !status.IsSuccess() {
+			return status
+		}
+	}
+
 	if requestCPUBind {
 		// It's necessary to force node to have NodeResourceTopology and CPUTopology
 		// We must satisfy the user's CPUSet request. Even if some nodes in the cluster have resources,
@@ -592,7 +610,12 @@ func (p *Plugin) preBindObject(ctx context.Context, cycleState *framework.CycleS
 	if !status.IsSuccess() {
 		return status
 	}
-	if state.skip || state.allocation == nil {
+	if state.skip {
+		return nil
+	}
+	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(nodeName)
+	subPool := matchCPUSharedSubPool(object, topologyOptions.CPUSharedSubPools)
+	if state.allocation == nil && subPool == nil {
 		return nil
 	}
 
@@ -601,7 +624,6 @@ func (p *Plugin) preBindObject(ctx context.Context, cycleState *framework.CycleS
 		return framework.NewStatus(framework.Error, fmt.Sprintf("getting node %q from Snapshot: %v", nodeName, err))
 	}
 	node := nodeInfo.Node()
-	topologyOptions := p.topologyOptionsManager.GetTopologyOptions(node.Name)
 	nodeCPUBindPolicy := extension.GetNodeCPUBindPolicy(node.Labels, topologyOptions.Policy)
 	requestCPUBind, status := requestCPUBind(state, nodeCPUBindPolicy)
 	if !status.IsSuccess() {
@@ -614,16 +636,25 @@ func (p *Plugin) preBindObject(ctx context.Context, cycleState *framework.CycleS
 		}
 	}
 
-	resourceStatus := &extension.ResourceStatus{
-		CPUSet: state.allocation.CPUSet.String(),
+	resourceStatus := &extension.ResourceStatus{}
+	if state.allocation != nil {
+		resourceStatus.CPUSet = state.allocation.CPUSet.String()
+		for _, nodeRes := range state.allocation.NUMANodeResources {
+			resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, extension.NUMANodeResource{
+				Node:      int32(nodeRes.Node),
+				Resources: nodeRes.Resources,
+			})
+		}
 	}
-	for _, nodeRes := range state.allocation.NUMANodeResources {
-		resourceStatus.NUMANodeResources = append(resourceStatus.NUMANodeResources, extension.NUMANodeResource{
-			Node:      int32(nodeRes.Node),
-			Resources: nodeRes.Resources,
-		})
+	// the pods bound to the exclusive cpus do not run in the shared pool
+	if subPool != nil && !requestCPUBind {
+		resourceStatus.CPUSharedSubPool = subPool.Name
+	}
+	setResourceStatus := extension.SetResourceStatus
+	if k8sfeature.DefaultFeatureGate.Enabled(features.CompactResourceStatus) {
+		setResourceStatus = extension.SetResourceStatusCompact
 	}
-	if err := extension.SetResourceStatus(object, resourceStatus); err != nil {
+	if err := setResourceStatus(object, resourceStatus); err != nil {
 		return framework.AsStatus(err)
 	}
 	return nil
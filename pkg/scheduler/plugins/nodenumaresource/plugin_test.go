@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+	k8sfeature "k8s.io/apiserver/pkg/util/feature"
 	"k8s.io/client-go/informers"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	apiresource "k8s.io/kubernetes/pkg/api/v1/resource"
@@ -46,6 +47,7 @@ import (
 	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
 	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
 	koordinatorinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	"github.com/koordinator-sh/koordinator/pkg/features"
 	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	_ "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/scheme"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1beta3"
@@ -54,6 +56,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/topologymanager"
 	"github.com/koordinator-sh/koordinator/pkg/util/bitmask"
 	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
 )
 
 var _ framework.SharedLister = &testSharedLister{}
@@ -1643,6 +1646,55 @@ func TestPlugin_PreBind(t *testing.T) {
 	assert.Equal(t, expectResourceStatus, resourceStatus)
 }
 
+func TestPlugin_PreBindWithCompactResourceStatus(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, k8sfeature.DefaultMutableFeatureGate, features.CompactResourceStatus, true)()
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+	}
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       uuid.NewUUID(),
+			Namespace: "default",
+			Name:      "test-pod-1",
+		},
+	}
+
+	_, status := suit.Handle.ClientSet().CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+	assert.Nil(t, status)
+
+	suit.start()
+
+	plg := p.(*Plugin)
+
+	state := &preFilterState{
+		requestCPUBind: true,
+		numCPUsNeeded:  4,
+		allocation: &PodAllocation{
+			CPUSet: cpuset.NewCPUSet(0, 1, 2, 3),
+		},
+	}
+	cycleState := framework.NewCycleState()
+	cycleState.Write(stateKey, state)
+
+	s := plg.PreBind(context.TODO(), cycleState, pod, node.Name)
+	assert.True(t, s.IsSuccess())
+	assert.Contains(t, pod.Annotations[extension.AnnotationResourceStatus], extension.ResourceStatusCompactV1Prefix)
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	assert.NoError(t, err)
+	expectResourceStatus := &extension.ResourceStatus{
+		CPUSet: "0-3",
+	}
+	assert.Equal(t, expectResourceStatus, resourceStatus)
+}
+
 func TestPlugin_PreBindWithCPUBindPolicyNone(t *testing.T) {
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1698,6 +1750,71 @@ func TestPlugin_PreBindWithCPUBindPolicyNone(t *testing.T) {
 	assert.Equal(t, expectedResourceSpec, resourceSpec)
 }
 
+func TestPlugin_PreBindWithCPUSharedSubPool(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+	}
+	suit := newPluginTestSuit(t, nil, []*corev1.Node{node})
+	p, err := suit.proxyNew(suit.nodeNUMAResourceArgs, suit.Handle)
+	assert.NotNil(t, p)
+	assert.Nil(t, err)
+
+	highPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       uuid.NewUUID(),
+			Namespace: "default",
+			Name:      "test-pod-1",
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+		},
+		Spec: corev1.PodSpec{
+			Priority: pointer.Int32(9600),
+		},
+	}
+	lowPod := highPod.DeepCopy()
+	lowPod.UID = uuid.NewUUID()
+	lowPod.Name = "test-pod-2"
+	lowPod.Spec.Priority = pointer.Int32(9100)
+	for _, pod := range []*corev1.Pod{highPod, lowPod} {
+		_, status := suit.Handle.ClientSet().CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.Nil(t, status)
+	}
+
+	suit.start()
+
+	plg := p.(*Plugin)
+	plg.topologyOptionsManager.UpdateTopologyOptions(node.Name, func(options *TopologyOptions) {
+		options.CPUSharedSubPools = []extension.CPUSharedSubPool{
+			{
+				Name:        "ls-high",
+				MinPriority: 9500,
+				MaxPriority: 9999,
+				Pools:       []extension.CPUSharedPool{{Socket: 0, Node: 0, CPUSet: "0-3"}},
+			},
+		}
+	})
+
+	// the pod without the NUMA allocation is assigned to the sub-pool
+	cycleState := framework.NewCycleState()
+	cycleState.Write(stateKey, &preFilterState{})
+	s := plg.PreBind(context.TODO(), cycleState, highPod, node.Name)
+	assert.True(t, s.IsSuccess())
+	resourceStatus, err := extension.GetResourceStatus(highPod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &extension.ResourceStatus{CPUSharedSubPool: "ls-high"}, resourceStatus)
+
+	// the pod out of the priority range keeps running in the shared pool
+	cycleState = framework.NewCycleState()
+	cycleState.Write(stateKey, &preFilterState{})
+	s = plg.PreBind(context.TODO(), cycleState, lowPod, node.Name)
+	assert.True(t, s.IsSuccess())
+	_, ok := lowPod.Annotations[extension.AnnotationResourceStatus]
+	assert.False(t, ok)
+}
+
 func TestPlugin_PreBindReservation(t *testing.T) {
 	node := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
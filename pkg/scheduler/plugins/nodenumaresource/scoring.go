@@ -99,7 +99,11 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 	}
 
 	if numaTopologyPolicy == extension.NUMATopologyPolicyNone {
-		return p.scoreWithAmplifiedCPUs(state, nodeInfo, resourceOptions)
+		score, status := p.scoreWithAmplifiedCPUs(state, nodeInfo, resourceOptions)
+		if !status.IsSuccess() {
+			return score, status
+		}
+		return p.mixZoneMemoryScore(score, pod, state, node, &topologyOptions, nil), nil
 	}
 
 	reservationRestoreState := getReservationRestoreState(cycleState)
@@ -116,7 +120,11 @@ func (p *Plugin) Score(ctx context.Context, cycleState *framework.CycleState, po
 	}
 
 	allocatable, requested := p.calculateAllocatableAndRequested(node.Name, nodeInfo, podAllocation, resourceOptions)
-	return p.scorer.score(requested, allocatable, framework.NewResource(resourceOptions.requests))
+	score, status := p.scorer.score(requested, allocatable, framework.NewResource(resourceOptions.requests))
+	if !status.IsSuccess() {
+		return score, status
+	}
+	return p.mixZoneMemoryScore(score, pod, state, node, &topologyOptions, podAllocation), nil
 }
 
 func (p *Plugin) scoreWithAmplifiedCPUs(state *preFilterState, nodeInfo *framework.NodeInfo, resourceOptions *ResourceOptions) (int64, *framework.Status) {
@@ -45,6 +45,7 @@ type TopologyOptions struct {
 	NUMATopologyPolicy  extension.NUMATopologyPolicy            `json:"numaTopologyPolicy"`
 	NUMANodeResources   []NUMANodeResource                      `json:"numaNodeResources"`
 	AmplificationRatios map[corev1.ResourceName]extension.Ratio `json:"amplificationRatios,omitempty"`
+	CPUSharedSubPools   []extension.CPUSharedSubPool            `json:"cpuSharedSubPools,omitempty"`
 }
 
 type NUMANodeResource struct {
@@ -152,6 +153,11 @@ func NewTopologyOptions(nrt *nrtv1alpha1.NodeResourceTopology) TopologyOptions {
 		klog.Errorf("Failed to GetNodeResourceAmplificationRatios, name: %s, err: %v", nrt.Name, err)
 	}
 
+	cpuSharedSubPools, err := extension.GetNodeCPUSharedSubPools(nrt.Annotations)
+	if err != nil {
+		klog.Errorf("Failed to GetNodeCPUSharedSubPools, name: %s, err: %v", nrt.Name, err)
+	}
+
 	return TopologyOptions{
 		CPUTopology:         cpuTopology,
 		ReservedCPUs:        reservedCPUs,
@@ -160,6 +166,7 @@ func NewTopologyOptions(nrt *nrtv1alpha1.NodeResourceTopology) TopologyOptions {
 		NUMATopologyPolicy:  policy,
 		NUMANodeResources:   numaNodeResources,
 		AmplificationRatios: amplificationRatios,
+		CPUSharedSubPools:   cpuSharedSubPools,
 	}
 }
 
@@ -0,0 +1,126 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// isMemoryHeavyBatchPod checks if the pod is a batch pod declaring the batch memory requests, whose memory
+// is usually satisfied by the resources reclaimed from the LS pods rather than the unallocated resources.
+func isMemoryHeavyBatchPod(pod *corev1.Pod, requests corev1.ResourceList) bool {
+	if extension.GetPodPriorityClassWithDefault(pod) != extension.PriorityBatch {
+		return false
+	}
+	quantity, ok := requests[extension.BatchMemory]
+	return ok && !quantity.IsZero()
+}
+
+// scoreZoneMemory scores the node according to the actual free memory of the NUMA zones reported in the NodeMetric.
+// If the pod is allocated on some NUMA nodes, the memory of these zones are summed up. Otherwise, the zone with
+// the most free memory is taken since the kernel prefers allocating the memory on the local NUMA node.
+// It returns false if the node has no valid zone memory.
+func scoreZoneMemory(zoneMemory []slov1alpha1.ZoneMemoryInfo, topologyOptions *TopologyOptions, podAllocation *PodAllocation, memoryRequest int64) (int64, bool) {
+	if len(zoneMemory) == 0 || len(topologyOptions.NUMANodeResources) == 0 {
+		return 0, false
+	}
+	zoneAvailable := make(map[string]int64, len(zoneMemory))
+	for _, zone := range zoneMemory {
+		zoneAvailable[zone.Name] = zone.Free.Value() + zone.Cold.Value()
+	}
+
+	if podAllocation != nil && len(podAllocation.NUMANodeResources) > 0 {
+		var available, capacity int64
+		for _, allocated := range podAllocation.NUMANodeResources {
+			zoneAvailableMemory, ok := zoneAvailable[util.GenNodeZoneName(allocated.Node)]
+			if !ok {
+				return 0, false
+			}
+			available += zoneAvailableMemory
+			capacity += getNUMANodeMemoryCapacity(topologyOptions, allocated.Node)
+		}
+		if capacity <= 0 {
+			return 0, false
+		}
+		return zoneMemoryScore(available, memoryRequest, capacity), true
+	}
+
+	var score int64
+	var found bool
+	for _, numaNode := range topologyOptions.NUMANodeResources {
+		zoneAvailableMemory, ok := zoneAvailable[util.GenNodeZoneName(numaNode.Node)]
+		if !ok {
+			continue
+		}
+		capacity := numaNode.Resources.Memory().Value()
+		if capacity <= 0 {
+			continue
+		}
+		if zoneScore := zoneMemoryScore(zoneAvailableMemory, memoryRequest, capacity); !found || zoneScore > score {
+			score = zoneScore
+		}
+		found = true
+	}
+	return score, found
+}
+
+// zoneMemoryScore favors the zones with more free memory left after placing the pod.
+func zoneMemoryScore(available, request, capacity int64) int64 {
+	used := capacity - available
+	if used < 0 {
+		used = 0
+	}
+	return leastRequestedScore(used+request, capacity)
+}
+
+func getNUMANodeMemoryCapacity(topologyOptions *TopologyOptions, node int) int64 {
+	for _, numaNode := range topologyOptions.NUMANodeResources {
+		if numaNode.Node == node {
+			return numaNode.Resources.Memory().Value()
+		}
+	}
+	return 0
+}
+
+// mixZoneMemoryScore mixes the resource score with the zone memory score by the configured weight for the
+// memory-heavy batch pods, so that the pods prefer the nodes whose zones have genuinely free memory.
+func (p *Plugin) mixZoneMemoryScore(score int64, pod *corev1.Pod, state *preFilterState, node *corev1.Node,
+	topologyOptions *TopologyOptions, podAllocation *PodAllocation) int64 {
+	weight := p.pluginArgs.ZoneMemoryScoringWeight
+	if weight <= 0 || p.nodeMetricLister == nil || !isMemoryHeavyBatchPod(pod, state.requests) {
+		return score
+	}
+	nodeMetric, err := p.nodeMetricLister.Get(node.Name)
+	if err != nil {
+		klog.V(5).InfoS("failed to get NodeMetric for zone memory scoring", "node", node.Name, "err", err)
+		return score
+	}
+	if nodeMetric.Status.NodeMetric == nil {
+		return score
+	}
+	memoryRequest := state.requests[extension.BatchMemory]
+	zoneScore, ok := scoreZoneMemory(nodeMetric.Status.NodeMetric.ZoneMemory, topologyOptions, podAllocation, memoryRequest.Value())
+	if !ok {
+		return score
+	}
+	return (score + zoneScore*weight) / (1 + weight)
+}
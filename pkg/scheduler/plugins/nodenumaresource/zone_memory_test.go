@@ -0,0 +1,249 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodenumaresource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+	schedulingconfig "github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+)
+
+func TestScoreZoneMemory(t *testing.T) {
+	topologyOptions := &TopologyOptions{
+		NUMANodeResources: []NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("100Gi"),
+				},
+			},
+			{
+				Node: 1,
+				Resources: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("100Gi"),
+				},
+			},
+		},
+	}
+	zoneMemory := []slov1alpha1.ZoneMemoryInfo{
+		{
+			Name: "node-0",
+			Free: resource.MustParse("20Gi"),
+			Cold: resource.MustParse("10Gi"),
+		},
+		{
+			Name: "node-1",
+			Free: resource.MustParse("50Gi"),
+			Cold: resource.MustParse("10Gi"),
+		},
+	}
+	tests := []struct {
+		name            string
+		zoneMemory      []slov1alpha1.ZoneMemoryInfo
+		topologyOptions *TopologyOptions
+		podAllocation   *PodAllocation
+		request         resource.Quantity
+		wantScore       int64
+		wantOK          bool
+	}{
+		{
+			name:            "no zone memory",
+			topologyOptions: topologyOptions,
+			request:         resource.MustParse("10Gi"),
+			wantOK:          false,
+		},
+		{
+			name:            "no NUMA node resources",
+			zoneMemory:      zoneMemory,
+			topologyOptions: &TopologyOptions{},
+			request:         resource.MustParse("10Gi"),
+			wantOK:          false,
+		},
+		{
+			name:            "pick the zone with the most free memory",
+			zoneMemory:      zoneMemory,
+			topologyOptions: topologyOptions,
+			request:         resource.MustParse("10Gi"),
+			wantScore:       50,
+			wantOK:          true,
+		},
+		{
+			name:            "request exceeds the available memory of all zones",
+			zoneMemory:      zoneMemory,
+			topologyOptions: topologyOptions,
+			request:         resource.MustParse("70Gi"),
+			wantScore:       0,
+			wantOK:          true,
+		},
+		{
+			name:            "sum up the allocated zones",
+			zoneMemory:      zoneMemory,
+			topologyOptions: topologyOptions,
+			podAllocation: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{Node: 0},
+					{Node: 1},
+				},
+			},
+			request:   resource.MustParse("40Gi"),
+			wantScore: 25,
+			wantOK:    true,
+		},
+		{
+			name:            "allocated zone has no zone memory",
+			zoneMemory:      zoneMemory[:1],
+			topologyOptions: topologyOptions,
+			podAllocation: &PodAllocation{
+				NUMANodeResources: []NUMANodeResource{
+					{Node: 1},
+				},
+			},
+			request: resource.MustParse("10Gi"),
+			wantOK:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotScore, gotOK := scoreZoneMemory(tt.zoneMemory, tt.topologyOptions, tt.podAllocation, tt.request.Value())
+			assert.Equal(t, tt.wantOK, gotOK)
+			assert.Equal(t, tt.wantScore, gotScore)
+		})
+	}
+}
+
+func TestMixZoneMemoryScore(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+	}
+	topologyOptions := &TopologyOptions{
+		NUMANodeResources: []NUMANodeResource{
+			{
+				Node: 0,
+				Resources: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("100Gi"),
+				},
+			},
+		},
+	}
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node-1",
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				ZoneMemory: []slov1alpha1.ZoneMemoryInfo{
+					{
+						Name: "node-0",
+						Free: resource.MustParse("80Gi"),
+						Cold: resource.MustParse("10Gi"),
+					},
+				},
+			},
+		},
+	}
+	batchPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				extension.LabelPodPriorityClass: string(extension.PriorityBatch),
+			},
+		},
+	}
+	batchRequests := corev1.ResourceList{
+		extension.BatchCPU:    resource.MustParse("4000"),
+		extension.BatchMemory: resource.MustParse("10Gi"),
+	}
+	tests := []struct {
+		name       string
+		weight     int64
+		pod        *corev1.Pod
+		requests   corev1.ResourceList
+		nodeMetric *slov1alpha1.NodeMetric
+		want       int64
+	}{
+		{
+			name:       "zone memory scoring disabled",
+			weight:     0,
+			pod:        batchPod,
+			requests:   batchRequests,
+			nodeMetric: nodeMetric,
+			want:       40,
+		},
+		{
+			name:   "not a batch pod",
+			weight: 1,
+			pod:    &corev1.Pod{},
+			requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("10Gi"),
+			},
+			nodeMetric: nodeMetric,
+			want:       40,
+		},
+		{
+			name:     "node metric not found",
+			weight:   1,
+			pod:      batchPod,
+			requests: batchRequests,
+			want:     40,
+		},
+		{
+			name:       "mix zone memory score",
+			weight:     1,
+			pod:        batchPod,
+			requests:   batchRequests,
+			nodeMetric: nodeMetric,
+			want:       60,
+		},
+		{
+			name:       "mix zone memory score with a larger weight",
+			weight:     3,
+			pod:        batchPod,
+			requests:   batchRequests,
+			nodeMetric: nodeMetric,
+			want:       70,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tt.nodeMetric != nil {
+				assert.NoError(t, indexer.Add(tt.nodeMetric))
+			}
+			p := &Plugin{
+				pluginArgs: &schedulingconfig.NodeNUMAResourceArgs{
+					ZoneMemoryScoringWeight: tt.weight,
+				},
+				nodeMetricLister: slolisters.NewNodeMetricLister(indexer),
+			}
+			state := &preFilterState{
+				requests: tt.requests,
+			}
+			got := p.mixZoneMemoryScore(40, tt.pod, state, node, topologyOptions, nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
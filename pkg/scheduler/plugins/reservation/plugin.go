@@ -44,6 +44,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/plugins/reservation/controller"
 	"github.com/koordinator-sh/koordinator/pkg/util"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
@@ -707,6 +708,9 @@ func (pl *Plugin) PostFilter(ctx context.Context, cycleState *framework.CycleSta
 
 	state := getStateData(cycleState)
 	postFilterReasons := pl.makePostFilterReasons(state, filteredNodeStatusMap)
+	if len(postFilterReasons) > 0 {
+		preemptionexplanation.RecordFailure(cycleState, Name, preemptionexplanation.ReasonReservation, strings.Join(postFilterReasons, ", "))
+	}
 	reasons = append(reasons, postFilterReasons...)
 	return result, framework.NewStatus(framework.Unschedulable, reasons...)
 }
@@ -1002,5 +1006,24 @@ func (pl *Plugin) Bind(ctx context.Context, cycleState *framework.CycleState, po
 	}
 
 	pl.handle.EventRecorder().Eventf(reservation, nil, corev1.EventTypeNormal, "Scheduled", "Binding", "Successfully assigned %v to %v", rName, nodeName)
+	if apiext.IsReservationImagePrePullEnabled(reservation) {
+		pl.createImagePrePull(reservation)
+	}
 	return nil
 }
+
+// createImagePrePull requests koordlet to pull the images of the available reservation on the node in advance.
+// The failure is tolerable since the images can still be pulled when the pods start.
+func (pl *Plugin) createImagePrePull(reservation *schedulingv1alpha1.Reservation) {
+	imagePrePull := reservationutil.NewImagePrePull(reservation)
+	if imagePrePull == nil {
+		return
+	}
+	_, err := pl.client.ImagePrePulls().Create(context.TODO(), imagePrePull, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		klog.ErrorS(err, "failed to create ImagePrePull for reservation", "reservation", klog.KObj(reservation))
+		return
+	}
+	klog.V(4).InfoS("ImagePrePull created for reservation", "reservation", klog.KObj(reservation),
+		"node", imagePrePull.Spec.NodeName, "images", imagePrePull.Spec.Images)
+}
@@ -3396,6 +3396,50 @@ func TestBind(t *testing.T) {
 	}
 }
 
+func TestBindWithImagePrePull(t *testing.T) {
+	reservation := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:  uuid.NewUUID(),
+			Name: "reserve-pod-0",
+			Annotations: map[string]string{
+				apiext.AnnotationReservationImagePrePull: "true",
+			},
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "test-container",
+							Image: "nginx:1.25",
+						},
+					},
+				},
+			},
+		},
+	}
+	suit := newPluginTestSuit(t)
+	client := suit.extenderFactory.KoordinatorClientSet()
+	_, err := client.SchedulingV1alpha1().Reservations().Create(context.TODO(), reservation, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	p, err := suit.pluginFactory()
+	assert.NoError(t, err)
+	pl := p.(*Plugin)
+	suit.start()
+
+	got := pl.Bind(context.TODO(), nil, reservationutil.NewReservePod(reservation), "test-node-0")
+	assert.True(t, got.IsSuccess())
+	imagePrePull, err := client.SchedulingV1alpha1().ImagePrePulls().Get(context.TODO(), reservation.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "test-node-0", imagePrePull.Spec.NodeName)
+	assert.Equal(t, []string{"nginx:1.25"}, imagePrePull.Spec.Images)
+
+	// an existing ImagePrePull is fine
+	reservation, err = client.SchedulingV1alpha1().Reservations().Get(context.TODO(), reservation.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	pl.createImagePrePull(reservation)
+}
+
 func testGetReservePod(pod *corev1.Pod) *corev1.Pod {
 	if pod.Annotations == nil {
 		pod.Annotations = map[string]string{}
@@ -47,6 +47,7 @@ import (
 	koordfeature "github.com/koordinator-sh/koordinator/pkg/features"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
 	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/preemptionexplanation"
 	reservationutil "github.com/koordinator-sh/koordinator/pkg/util/reservation"
 )
 
@@ -117,7 +118,11 @@ func (pm *PreemptionMgr) PostFilter(ctx context.Context, state *framework.CycleS
 	}
 	klog.V(4).InfoS("Attempt to do reservation preemption in the PostFilter", "pod", klog.KObj(pod))
 
+	preemptionexplanation.RecordConsidered(state, Name)
 	result, status := pe.Preempt(ctx, pod, m)
+	if !status.IsSuccess() {
+		preemptionexplanation.RecordFailure(state, Name, preemptionexplanation.ReasonPreemptionFailed, status.Message())
+	}
 	if status.Message() != "" {
 		return result, framework.NewStatus(status.Code(), "preemption: "+status.Message())
 	}
@@ -158,10 +163,14 @@ func (pm *PreemptionMgr) SelectVictimsOnNode(
 	// As the first step, remove all the lower priority pods from the node and
 	// check if the given pod can be scheduled.
 	podPriority := corev1helpers.PodPriority(pod)
+	numNonPreemptible := 0
 	for _, pi := range nodeInfo.Pods {
+		if corev1helpers.PodPriority(pi.Pod) >= podPriority {
+			continue
+		}
 		// NOTE: Ignore the non-preemptible pod.
-		if !extension.IsPodPreemptible(pi.Pod) ||
-			corev1helpers.PodPriority(pi.Pod) >= podPriority {
+		if !extension.IsPodPreemptible(pi.Pod) {
+			numNonPreemptible++
 			continue
 		}
 
@@ -170,9 +179,14 @@ func (pm *PreemptionMgr) SelectVictimsOnNode(
 			return nil, 0, framework.AsStatus(err)
 		}
 	}
+	preemptionexplanation.RecordVictims(state, potentialVictims)
 
 	// No potential victims are found, and so we don't need to evaluate the node again since its state didn't change.
 	if len(potentialVictims) == 0 {
+		if numNonPreemptible > 0 {
+			preemptionexplanation.RecordFailure(state, Name, preemptionexplanation.ReasonNonPreemptible,
+				"the lower priority pods are non-preemptible")
+		}
 		message := fmt.Sprintf("No preemption victims found for incoming pod")
 		return nil, 0, framework.NewStatus(framework.UnschedulableAndUnresolvable, message)
 	}
@@ -0,0 +1,205 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulercoexistence
+
+import (
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+// podCPUSet is the cpuset of a pod parsed from its resource status.
+type podCPUSet struct {
+	pod  *corev1.Pod
+	cpus cpuset.CPUSet
+}
+
+type nodeCPUSets struct {
+	// koordPods are the cpusets allocated by koord-scheduler
+	koordPods map[types.UID]*podCPUSet
+	// foreignPods are the cpusets carried by the pods bound by other schedulers
+	foreignPods map[types.UID]*podCPUSet
+	// flagged are the foreign pods whose conflicts have been flagged
+	flagged sets.String
+}
+
+// conflictCache records the cpusets of the pods on each node, and detects the cpuset conflicts between the pods
+// bound by koord-scheduler and the pods bound by other schedulers.
+type conflictCache struct {
+	lock                sync.RWMutex
+	koordSchedulerNames sets.String
+	nodes               map[string]*nodeCPUSets
+}
+
+func newConflictCache(koordSchedulerNames []string) *conflictCache {
+	return &conflictCache{
+		koordSchedulerNames: sets.NewString(koordSchedulerNames...),
+		nodes:               map[string]*nodeCPUSets{},
+	}
+}
+
+func (c *conflictCache) isForeignPod(pod *corev1.Pod) bool {
+	return !c.koordSchedulerNames.Has(pod.Spec.SchedulerName)
+}
+
+func (c *conflictCache) updatePod(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	if util.IsPodTerminated(pod) {
+		c.deletePod(pod)
+		return
+	}
+	cpus := getPodCPUSet(pod)
+	if cpus.IsEmpty() {
+		c.deletePod(pod)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	node := c.nodes[pod.Spec.NodeName]
+	if node == nil {
+		node = &nodeCPUSets{
+			koordPods:   map[types.UID]*podCPUSet{},
+			foreignPods: map[types.UID]*podCPUSet{},
+			flagged:     sets.NewString(),
+		}
+		c.nodes[pod.Spec.NodeName] = node
+	}
+	podCPUs := &podCPUSet{
+		pod:  pod,
+		cpus: cpus,
+	}
+	if c.isForeignPod(pod) {
+		node.foreignPods[pod.UID] = podCPUs
+	} else {
+		node.koordPods[pod.UID] = podCPUs
+	}
+}
+
+func (c *conflictCache) deletePod(pod *corev1.Pod) {
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	node := c.nodes[pod.Spec.NodeName]
+	if node == nil {
+		return
+	}
+	delete(node.koordPods, pod.UID)
+	delete(node.foreignPods, pod.UID)
+	node.flagged.Delete(string(pod.UID))
+	if len(node.koordPods) == 0 && len(node.foreignPods) == 0 {
+		delete(c.nodes, pod.Spec.NodeName)
+	}
+}
+
+// getConflicts returns the keys of the pods bound by other schedulers whose cpusets overlap with the allocations
+// of koord-scheduler on the node.
+func (c *conflictCache) getConflicts(nodeName string) []string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	node := c.nodes[nodeName]
+	if node == nil || len(node.foreignPods) == 0 || len(node.koordPods) == 0 {
+		return nil
+	}
+	koordCPUs := node.koordCPUs()
+	var conflicts []string
+	for _, podCPUs := range node.foreignPods {
+		if !podCPUs.cpus.Intersection(koordCPUs).IsEmpty() {
+			conflicts = append(conflicts, podCPUs.pod.Namespace+"/"+podCPUs.pod.Name)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// flagNewConflicts returns the foreign pods which newly conflict with the allocations of koord-scheduler on the
+// node, along with the conflicted cpus. The conflicts are only returned once until they are resolved.
+func (c *conflictCache) flagNewConflicts(nodeName string) map[*corev1.Pod]cpuset.CPUSet {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	node := c.nodes[nodeName]
+	if node == nil {
+		return nil
+	}
+	koordCPUs := node.koordCPUs()
+	var newConflicts map[*corev1.Pod]cpuset.CPUSet
+	for uid, podCPUs := range node.foreignPods {
+		conflictCPUs := podCPUs.cpus.Intersection(koordCPUs)
+		if conflictCPUs.IsEmpty() {
+			node.flagged.Delete(string(uid))
+			continue
+		}
+		if node.flagged.Has(string(uid)) {
+			continue
+		}
+		node.flagged.Insert(string(uid))
+		if newConflicts == nil {
+			newConflicts = map[*corev1.Pod]cpuset.CPUSet{}
+		}
+		newConflicts[podCPUs.pod] = conflictCPUs
+	}
+	return newConflicts
+}
+
+func (n *nodeCPUSets) koordCPUs() cpuset.CPUSet {
+	builder := cpuset.NewCPUSetBuilder()
+	for _, pod := range n.koordPods {
+		builder.Add(pod.cpus.ToSliceNoSort()...)
+	}
+	return builder.Result()
+}
+
+func getPodCPUSet(pod *corev1.Pod) cpuset.CPUSet {
+	resourceStatus, err := extension.GetResourceStatus(pod.Annotations)
+	if err != nil {
+		return cpuset.NewCPUSet()
+	}
+	cpus, err := cpuset.Parse(resourceStatus.CPUSet)
+	if err != nil {
+		return cpuset.NewCPUSet()
+	}
+	return cpus
+}
+
+// requestCPUSet checks if the pod requires the cpuset allocated by koord-scheduler.
+func requestCPUSet(pod *corev1.Pod) bool {
+	qosClass := extension.GetPodQoSClassRaw(pod)
+	if qosClass == extension.QoSLSE || qosClass == extension.QoSLSR {
+		return true
+	}
+	resourceSpec, err := extension.GetResourceSpec(pod.Annotations)
+	if err != nil {
+		return false
+	}
+	for _, policy := range []extension.CPUBindPolicy{resourceSpec.RequiredCPUBindPolicy, resourceSpec.PreferredCPUBindPolicy} {
+		if policy == extension.CPUBindPolicyFullPCPUs || policy == extension.CPUBindPolicySpreadByPCPUs {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulercoexistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/validation"
+	frameworkexthelper "github.com/koordinator-sh/koordinator/pkg/scheduler/frameworkext/helper"
+)
+
+const (
+	Name = "SchedulerCoexistence"
+
+	ErrReasonCPUSetConflict = "node(s) had cpuset conflicts caused by pods bound by other schedulers"
+
+	eventReasonCPUSetConflict  = "CPUSetConflict"
+	eventReasonCPUSetUnmanaged = "CPUSetUnmanaged"
+)
+
+var (
+	_ framework.FilterPlugin = &Plugin{}
+)
+
+// Plugin detects the pods bound by other schedulers onto the nodes whose CPUs are managed by koord-scheduler in
+// fine-grained. The pods bound by other schedulers are not aware of the cpusets allocated by koord-scheduler, so the
+// cpusets they carry can overlap with the allocations of koord-scheduler. The koordlet reconciles these pods into
+// the shared pool, and the plugin flags the conflicts and optionally rejects the cpuset pods on the conflicted nodes.
+type Plugin struct {
+	handle framework.Handle
+	args   *config.SchedulerCoexistenceArgs
+	cache  *conflictCache
+}
+
+func New(args runtime.Object, handle framework.Handle) (framework.Plugin, error) {
+	pluginArgs, ok := args.(*config.SchedulerCoexistenceArgs)
+	if !ok {
+		return nil, fmt.Errorf("want args to be of type SchedulerCoexistenceArgs, got %T", args)
+	}
+	if err := validation.ValidateSchedulerCoexistenceArgs(nil, pluginArgs); err != nil {
+		return nil, err
+	}
+
+	p := &Plugin{
+		handle: handle,
+		args:   pluginArgs,
+		cache:  newConflictCache(pluginArgs.KoordSchedulerNames),
+	}
+	podInformer := handle.SharedInformerFactory().Core().V1().Pods().Informer()
+	frameworkexthelper.ForceSyncFromInformer(context.TODO().Done(), handle.SharedInformerFactory(), podInformer, p)
+	return p, nil
+}
+
+func (p *Plugin) Name() string {
+	return Name
+}
+
+func (p *Plugin) Filter(ctx context.Context, cycleState *framework.CycleState, pod *corev1.Pod, nodeInfo *framework.NodeInfo) *framework.Status {
+	if p.args.ConflictPolicy != config.CoexistenceConflictPolicyReject || !requestCPUSet(pod) {
+		return nil
+	}
+	node := nodeInfo.Node()
+	if node == nil {
+		return framework.NewStatus(framework.Error, "node not found")
+	}
+	if conflicts := p.cache.getConflicts(node.Name); len(conflicts) > 0 {
+		klog.V(5).Infof("node %s has cpuset conflicts with pods %v, reject pod %s", node.Name, conflicts, klog.KObj(pod))
+		return framework.NewStatus(framework.Unschedulable, ErrReasonCPUSetConflict)
+	}
+	return nil
+}
+
+func (p *Plugin) OnAdd(obj interface{}, isInInitialList bool) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	p.updatePod(nil, pod)
+}
+
+func (p *Plugin) OnUpdate(oldObj, newObj interface{}) {
+	oldPod, ok := oldObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok {
+		return
+	}
+	p.updatePod(oldPod, pod)
+}
+
+func (p *Plugin) OnDelete(obj interface{}) {
+	var pod *corev1.Pod
+	switch t := obj.(type) {
+	case *corev1.Pod:
+		pod = t
+	case cache.DeletedFinalStateUnknown:
+		pod, _ = t.Obj.(*corev1.Pod)
+	}
+	if pod == nil {
+		return
+	}
+	p.cache.deletePod(pod)
+}
+
+func (p *Plugin) updatePod(oldPod, pod *corev1.Pod) {
+	p.cache.updatePod(pod)
+	if pod.Spec.NodeName == "" {
+		return
+	}
+	for conflictPod, conflictCPUs := range p.cache.flagNewConflicts(pod.Spec.NodeName) {
+		klog.Warningf("pod %s bound by scheduler %s has cpuset conflicts %s with koord-scheduler on node %s",
+			klog.KObj(conflictPod), conflictPod.Spec.SchedulerName, conflictCPUs.String(), conflictPod.Spec.NodeName)
+		p.handle.EventRecorder().Eventf(conflictPod, nil, corev1.EventTypeWarning, eventReasonCPUSetConflict, "Scheduling",
+			"cpuset %s conflicts with the cpus allocated by koord-scheduler(%s) on node %s",
+			conflictCPUs.String(), strings.Join(p.args.KoordSchedulerNames, ","), conflictPod.Spec.NodeName)
+	}
+
+	// flag the pod requiring cpuset once it is bound by other schedulers
+	if oldPod != nil && oldPod.Spec.NodeName != "" {
+		return
+	}
+	if p.cache.isForeignPod(pod) && requestCPUSet(pod) && getPodCPUSet(pod).IsEmpty() {
+		klog.V(4).Infof("pod %s bound by scheduler %s requires cpuset but not allocated by koord-scheduler on node %s",
+			klog.KObj(pod), pod.Spec.SchedulerName, pod.Spec.NodeName)
+		p.handle.EventRecorder().Eventf(pod, nil, corev1.EventTypeWarning, eventReasonCPUSetUnmanaged, "Scheduling",
+			"cpuset is not allocated by koord-scheduler(%s), the pod runs in the shared pool of node %s",
+			strings.Join(p.args.KoordSchedulerNames, ","), pod.Spec.NodeName)
+	}
+}
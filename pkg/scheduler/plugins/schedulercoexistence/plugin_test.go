@@ -0,0 +1,152 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulercoexistence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/kubernetes/pkg/scheduler/framework"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/defaultbinder"
+	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/queuesort"
+	"k8s.io/kubernetes/pkg/scheduler/framework/runtime"
+	st "k8s.io/kubernetes/pkg/scheduler/testing"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config"
+	"github.com/koordinator-sh/koordinator/pkg/scheduler/apis/config/v1beta3"
+)
+
+func newTestPod(name, schedulerName, nodeName string, qosClass extension.QoSClass, cpus string) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(qosClass),
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: corev1.PodSpec{
+			SchedulerName: schedulerName,
+			NodeName:      nodeName,
+		},
+	}
+	if cpus != "" {
+		pod.Annotations[extension.AnnotationResourceStatus] = `{"cpuset":"` + cpus + `"}`
+	}
+	return pod
+}
+
+func newTestPlugin(t *testing.T, policy config.CoexistenceConflictPolicy, pods ...*corev1.Pod) (*Plugin, *events.FakeRecorder) {
+	var v1beta3args v1beta3.SchedulerCoexistenceArgs
+	v1beta3.SetDefaults_SchedulerCoexistenceArgs(&v1beta3args)
+	var args config.SchedulerCoexistenceArgs
+	assert.NoError(t, v1beta3.Convert_v1beta3_SchedulerCoexistenceArgs_To_config_SchedulerCoexistenceArgs(&v1beta3args, &args, nil))
+	if policy != "" {
+		args.ConflictPolicy = policy
+	}
+
+	cs := kubefake.NewSimpleClientset()
+	for _, pod := range pods {
+		_, err := cs.CoreV1().Pods(pod.Namespace).Create(context.TODO(), pod, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	informerFactory := informers.NewSharedInformerFactory(cs, 0)
+	recorder := events.NewFakeRecorder(10)
+	fh, err := st.NewFramework(
+		context.TODO(),
+		[]st.RegisterPluginFunc{
+			st.RegisterBindPlugin(defaultbinder.Name, defaultbinder.New),
+			st.RegisterQueueSortPlugin(queuesort.Name, queuesort.New),
+		},
+		"koord-scheduler",
+		runtime.WithClientSet(cs),
+		runtime.WithInformerFactory(informerFactory),
+		runtime.WithEventRecorder(recorder),
+	)
+	assert.NoError(t, err)
+	p, err := New(&args, fh)
+	assert.NoError(t, err)
+	assert.Equal(t, Name, p.Name())
+	return p.(*Plugin), recorder
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(&config.SchedulerCoexistenceArgs{ConflictPolicy: config.CoexistenceConflictPolicyFlag}, nil)
+	assert.Error(t, err)
+	_, err = New(&config.SchedulerCoexistenceArgs{KoordSchedulerNames: []string{"koord-scheduler"}, ConflictPolicy: "unknown"}, nil)
+	assert.Error(t, err)
+}
+
+func TestPlugin_Filter(t *testing.T) {
+	koordPod := newTestPod("koord-pod", "koord-scheduler", "node-1", extension.QoSLSR, "0-3")
+	foreignPod := newTestPod("foreign-pod", "default-scheduler", "node-1", extension.QoSLSR, "2-5")
+	nonConflictPod := newTestPod("non-conflict-pod", "default-scheduler", "node-2", extension.QoSLSR, "0-1")
+	unmanagedPod := newTestPod("unmanaged-pod", "default-scheduler", "node-2", extension.QoSLSE, "")
+
+	node1 := framework.NewNodeInfo()
+	node1.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+	node2 := framework.NewNodeInfo()
+	node2.SetNode(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}})
+	lsrPod := newTestPod("lsr-pod", "koord-scheduler", "", extension.QoSLSR, "")
+	lsPod := newTestPod("ls-pod", "koord-scheduler", "", extension.QoSLS, "")
+
+	t.Run("reject cpuset pods on conflicted nodes", func(t *testing.T) {
+		p, recorder := newTestPlugin(t, "", koordPod, foreignPod, nonConflictPod, unmanagedPod)
+		assert.Equal(t, []string{"default/foreign-pod"}, p.cache.getConflicts("node-1"))
+		assert.Nil(t, p.cache.getConflicts("node-2"))
+		assert.Equal(t, 2, len(recorder.Events))
+
+		status := p.Filter(context.TODO(), framework.NewCycleState(), lsrPod, node1)
+		assert.Equal(t, framework.NewStatus(framework.Unschedulable, ErrReasonCPUSetConflict), status)
+		assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), lsrPod, node2).IsSuccess())
+		assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), lsPod, node1).IsSuccess())
+
+		// the conflicts are flagged only once
+		p.OnUpdate(foreignPod, foreignPod)
+		assert.Equal(t, 2, len(recorder.Events))
+
+		p.OnDelete(foreignPod)
+		assert.Nil(t, p.cache.getConflicts("node-1"))
+		assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), lsrPod, node1).IsSuccess())
+	})
+
+	t.Run("only flag conflicts", func(t *testing.T) {
+		p, recorder := newTestPlugin(t, config.CoexistenceConflictPolicyFlag, koordPod, foreignPod)
+		assert.Equal(t, []string{"default/foreign-pod"}, p.cache.getConflicts("node-1"))
+		assert.Equal(t, 1, len(recorder.Events))
+		assert.True(t, p.Filter(context.TODO(), framework.NewCycleState(), lsrPod, node1).IsSuccess())
+	})
+
+	t.Run("resolve conflicts when the foreign pod terminated", func(t *testing.T) {
+		p, _ := newTestPlugin(t, "", koordPod, foreignPod)
+		assert.Equal(t, []string{"default/foreign-pod"}, p.cache.getConflicts("node-1"))
+		terminatedPod := foreignPod.DeepCopy()
+		terminatedPod.Status.Phase = corev1.PodSucceeded
+		p.OnUpdate(foreignPod, terminatedPod)
+		assert.Nil(t, p.cache.getConflicts("node-1"))
+	})
+}
@@ -0,0 +1,276 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package colocationstatus
+
+import (
+	"context"
+	"flag"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "colocationstatus"
+
+var (
+	// SyncInterval is the minimum interval between two aggregations.
+	SyncInterval = time.Minute
+	// PeriodDuration is the duration of a period, after which the current summary is rolled into the history.
+	PeriodDuration = time.Hour
+	// HistoryLimit is the maximum number of the past periods kept in the history.
+	HistoryLimit = 24
+	// InterferenceThresholdPercent is the suppression percent of a node at which an interference incident is counted.
+	InterferenceThresholdPercent = 50
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.DurationVar(&SyncInterval, "colocation-status-sync-interval", SyncInterval, "The minimum interval to aggregate the colocation statuses.")
+	pflag.DurationVar(&PeriodDuration, "colocation-status-period", PeriodDuration, "The duration of a colocation status period, after which the current summary is rolled into the history.")
+	pflag.IntVar(&HistoryLimit, "colocation-status-history-limit", HistoryLimit, "The maximum number of the past periods kept in the colocation status history.")
+	pflag.IntVar(&InterferenceThresholdPercent, "colocation-status-interference-threshold-percent", InterferenceThresholdPercent, "The batch cpu suppression percent of a node at which an interference incident is counted.")
+}
+
+// Reconciler aggregates the NodeMetrics into the ColocationStatuses of the cluster and the node pools.
+type Reconciler struct {
+	client.Client
+
+	lock         sync.Mutex
+	lastSyncTime time.Time
+	// nodeCounters records the counters of the nodes observed by the last sync
+	nodeCounters map[string]*nodeCounter
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=colocationstatuses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=colocationstatuses/status,verbs=get;update;patch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := time.Now()
+	if elapsed := now.Sub(r.lastSyncTime); elapsed < SyncInterval {
+		return ctrl.Result{RequeueAfter: SyncInterval - elapsed}, nil
+	}
+
+	summaries, err := r.aggregate(ctx)
+	if err != nil {
+		klog.Errorf("failed to aggregate colocation status, err: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if err = r.updateStatuses(ctx, summaries, now); err != nil {
+		klog.Errorf("failed to update colocation status, err: %v", err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	r.lastSyncTime = now
+	klog.V(4).Infof("update %d colocation statuses successfully", len(summaries))
+	return ctrl.Result{}, nil
+}
+
+// aggregate builds the latest summaries of the cluster and the node pools, keyed by the ColocationStatus names.
+func (r *Reconciler) aggregate(ctx context.Context) (map[string]*slov1alpha1.ColocationStatus, error) {
+	nodeList := &corev1.NodeList{}
+	if err := r.Client.List(ctx, nodeList); err != nil {
+		return nil, err
+	}
+	nodeMetricList := &slov1alpha1.NodeMetricList{}
+	if err := r.Client.List(ctx, nodeMetricList); err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList); err != nil {
+		return nil, err
+	}
+
+	nodeMetrics := make(map[string]*slov1alpha1.NodeMetric, len(nodeMetricList.Items))
+	for i := range nodeMetricList.Items {
+		nodeMetrics[nodeMetricList.Items[i].Name] = &nodeMetricList.Items[i]
+	}
+	nodePods := map[string][]*corev1.Pod{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		nodePods[pod.Spec.NodeName] = append(nodePods[pod.Spec.NodeName], pod)
+	}
+
+	clusterBuilder := newSummaryBuilder()
+	poolBuilders := map[string]*summaryBuilder{}
+	newNodeCounters := make(map[string]*nodeCounter, len(nodeList.Items))
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		nodeMetric, ok := nodeMetrics[node.Name]
+		if !ok {
+			continue
+		}
+		snapshot := newNodeSnapshot(node, nodePods[node.Name], nodeMetric)
+		counter, observed := r.nodeCounters[node.Name]
+		if !observed {
+			counter = &nodeCounter{}
+		}
+		evictions, interferences := snapshot.observe(counter, observed, int64(InterferenceThresholdPercent))
+		newNodeCounters[node.Name] = counter
+
+		clusterBuilder.add(snapshot, evictions, interferences)
+		if snapshot.pool == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(getPoolStatusName(snapshot.pool)); len(errs) > 0 {
+			klog.V(5).Infof("skip the colocation pool %s of node %s, invalid name: %v", snapshot.pool, node.Name, errs)
+			continue
+		}
+		if poolBuilders[snapshot.pool] == nil {
+			poolBuilders[snapshot.pool] = newSummaryBuilder()
+		}
+		poolBuilders[snapshot.pool].add(snapshot, evictions, interferences)
+	}
+	// drop the counters of the deleted nodes
+	r.nodeCounters = newNodeCounters
+
+	summaries := map[string]*slov1alpha1.ColocationStatus{
+		slov1alpha1.ColocationStatusClusterName: newColocationStatus(slov1alpha1.ColocationStatusClusterName, "", clusterBuilder.build()),
+	}
+	for pool, b := range poolBuilders {
+		name := getPoolStatusName(pool)
+		summaries[name] = newColocationStatus(name, pool, b.build())
+	}
+	return summaries, nil
+}
+
+// updateStatuses creates or updates the ColocationStatuses with the latest summaries and deletes the ones of the
+// node pools which no longer exist.
+func (r *Reconciler) updateStatuses(ctx context.Context, summaries map[string]*slov1alpha1.ColocationStatus, now time.Time) error {
+	statusList := &slov1alpha1.ColocationStatusList{}
+	if err := r.Client.List(ctx, statusList); err != nil {
+		return err
+	}
+	existing := make(map[string]*slov1alpha1.ColocationStatus, len(statusList.Items))
+	for i := range statusList.Items {
+		existing[statusList.Items[i].Name] = &statusList.Items[i]
+	}
+
+	names := make([]string, 0, len(summaries))
+	for name := range summaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		latest := summaries[name]
+		old, ok := existing[name]
+		if !ok {
+			old = newColocationStatus(name, latest.Spec.Pool, slov1alpha1.ColocationSummary{})
+			if err := r.Client.Create(ctx, old); err != nil {
+				return err
+			}
+			klog.V(4).Infof("create colocation status %s", name)
+		}
+		newStatus := old.DeepCopy()
+		newStatus.Status = updatePeriod(old.Status, latest.Status.Current, now, PeriodDuration, HistoryLimit)
+		if err := r.Client.Status().Update(ctx, newStatus); err != nil {
+			return err
+		}
+	}
+
+	for name, old := range existing {
+		if _, ok := summaries[name]; ok || name == slov1alpha1.ColocationStatusClusterName {
+			continue
+		}
+		if err := r.Client.Delete(ctx, old); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		klog.V(4).Infof("delete colocation status %s since the pool has no node", name)
+	}
+	return nil
+}
+
+// updatePeriod returns the status with the latest summary merged into the current period. When the current period
+// is over, it is rolled into the history and a new period starts.
+func updatePeriod(old slov1alpha1.ColocationStatusStatus, latest slov1alpha1.ColocationSummary, now time.Time,
+	period time.Duration, historyLimit int) slov1alpha1.ColocationStatusStatus {
+	status := *old.DeepCopy()
+	nowTime := metav1.NewTime(now)
+	if status.PeriodStartTime == nil {
+		status.PeriodStartTime = &nowTime
+	} else if now.Sub(status.PeriodStartTime.Time) >= period {
+		status.History = append(status.History, slov1alpha1.ColocationSummaryRecord{
+			StartTime:         *status.PeriodStartTime,
+			EndTime:           nowTime,
+			ColocationSummary: status.Current,
+		})
+		if historyLimit >= 0 && len(status.History) > historyLimit {
+			status.History = status.History[len(status.History)-historyLimit:]
+		}
+		status.PeriodStartTime = &nowTime
+		status.Current = slov1alpha1.ColocationSummary{}
+	}
+	status.Current = mergeSummary(status.Current, latest)
+	status.UpdateTime = &nowTime
+	return status
+}
+
+func newColocationStatus(name, pool string, summary slov1alpha1.ColocationSummary) *slov1alpha1.ColocationStatus {
+	return &slov1alpha1.ColocationStatus{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: slov1alpha1.ColocationStatusSpec{
+			Pool: pool,
+		},
+		Status: slov1alpha1.ColocationStatusStatus{
+			Current: summary,
+		},
+	}
+}
+
+// Add creates the controller which aggregates the ColocationStatuses on the NodeMetric updates.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ColocationStatus) {
+		klog.V(4).Infof("feature %s is disabled, skip the colocation status controller", features.ColocationStatus)
+		return nil
+	}
+	reconciler := &Reconciler{
+		Client:       mgr.GetClient(),
+		nodeCounters: map[string]*nodeCounter{},
+	}
+	// all NodeMetric events share the single request to aggregate the whole cluster
+	enqueueCluster := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: slov1alpha1.ColocationStatusClusterName}}}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(Name).
+		Watches(&slov1alpha1.NodeMetric{}, enqueueCluster).
+		Complete(reconciler)
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package colocationstatus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestColocationStatusReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+
+	suppressCPU := resource.MustParse("1")
+	node0 := newTestNode("node-0", "pool-a", 8000, 16<<30)
+	node1 := newTestNode("node-1", "", 8000, 16<<30)
+	// the node without NodeMetric is ignored
+	node2 := newTestNode("node-2", "pool-a", 8000, 16<<30)
+	nodeMetric0 := newTestNodeMetric("node-0", 2, &suppressCPU)
+	nodeMetric1 := newTestNodeMetric("node-1", 0, nil)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&slov1alpha1.ColocationStatus{}).
+		WithObjects(node0, node1, node2, nodeMetric0, nodeMetric1,
+			newTestBatchPod("batch-pod-0", "node-0", 4000, 4<<30),
+			newTestBatchPod("batch-pod-1", "node-1", 4000, 4<<30),
+			newTestBatchPod("pending-pod", "", 4000, 4<<30)).Build()
+	r := &Reconciler{
+		Client:       fakeClient,
+		nodeCounters: map[string]*nodeCounter{},
+	}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: slov1alpha1.ColocationStatusClusterName}}
+
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	clusterStatus := &slov1alpha1.ColocationStatus{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: slov1alpha1.ColocationStatusClusterName}, clusterStatus))
+	assert.Equal(t, "", clusterStatus.Spec.Pool)
+	assert.NotNil(t, clusterStatus.Status.PeriodStartTime)
+	assert.NotNil(t, clusterStatus.Status.UpdateTime)
+	assert.Equal(t, int64(2), clusterStatus.Status.Current.NodeCount)
+	assert.Equal(t, int64(8000), clusterStatus.Status.Current.BatchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(38), clusterStatus.Status.Current.SuppressionPercent)
+	assert.Equal(t, int64(0), clusterStatus.Status.Current.PodEvictionCount)
+	assert.Equal(t, int64(1), clusterStatus.Status.Current.InterferenceCount)
+
+	poolStatus := &slov1alpha1.ColocationStatus{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "pool-pool-a"}, poolStatus))
+	assert.Equal(t, "pool-a", poolStatus.Spec.Pool)
+	assert.Equal(t, int64(1), poolStatus.Status.Current.NodeCount)
+	assert.Equal(t, int64(75), poolStatus.Status.Current.SuppressionPercent)
+
+	// rate limited
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	// count the new evictions and remove node-0 from the pool
+	r.lastSyncTime = time.Time{}
+	nodeMetric0.Status.ColocationMetric.PodEvictionCount = 5
+	assert.NoError(t, fakeClient.Update(ctx, nodeMetric0))
+	node0.Labels = nil
+	assert.NoError(t, fakeClient.Update(ctx, node0))
+
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: slov1alpha1.ColocationStatusClusterName}, clusterStatus))
+	assert.Equal(t, int64(3), clusterStatus.Status.Current.PodEvictionCount)
+	assert.Equal(t, int64(1), clusterStatus.Status.Current.InterferenceCount)
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: "pool-pool-a"}, poolStatus)
+	assert.True(t, errors.IsNotFound(err))
+}
+
+func Test_updatePeriod(t *testing.T) {
+	startTime := time.Now().Add(-2 * time.Hour)
+	periodStartTime := metav1.NewTime(startTime)
+	old := slov1alpha1.ColocationStatusStatus{
+		PeriodStartTime: &periodStartTime,
+		Current: slov1alpha1.ColocationSummary{
+			NodeCount:        2,
+			PodEvictionCount: 3,
+		},
+		History: []slov1alpha1.ColocationSummaryRecord{
+			{ColocationSummary: slov1alpha1.ColocationSummary{NodeCount: 1}},
+			{ColocationSummary: slov1alpha1.ColocationSummary{NodeCount: 2}},
+		},
+	}
+	latest := slov1alpha1.ColocationSummary{
+		NodeCount:        3,
+		PodEvictionCount: 1,
+	}
+
+	// in the current period
+	now := startTime.Add(30 * time.Minute)
+	got := updatePeriod(old, latest, now, time.Hour, 2)
+	assert.Equal(t, periodStartTime, *got.PeriodStartTime)
+	assert.Equal(t, int64(3), got.Current.NodeCount)
+	assert.Equal(t, int64(4), got.Current.PodEvictionCount)
+	assert.Len(t, got.History, 2)
+	assert.Equal(t, now, got.UpdateTime.Time)
+
+	// roll into the history
+	now = startTime.Add(time.Hour)
+	got = updatePeriod(old, latest, now, time.Hour, 2)
+	assert.Equal(t, now, got.PeriodStartTime.Time)
+	assert.Equal(t, int64(3), got.Current.NodeCount)
+	assert.Equal(t, int64(1), got.Current.PodEvictionCount)
+	assert.Len(t, got.History, 2)
+	assert.Equal(t, int64(2), got.History[0].NodeCount)
+	assert.Equal(t, periodStartTime, got.History[1].StartTime)
+	assert.Equal(t, now, got.History[1].EndTime.Time)
+	assert.Equal(t, int64(3), got.History[1].PodEvictionCount)
+
+	// the first period
+	got = updatePeriod(slov1alpha1.ColocationStatusStatus{}, latest, now, time.Hour, 2)
+	assert.Equal(t, now, got.PeriodStartTime.Time)
+	assert.Equal(t, latest, got.Current)
+}
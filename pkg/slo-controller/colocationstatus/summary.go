@@ -0,0 +1,185 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package colocationstatus
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	quotav1 "k8s.io/apiserver/pkg/quota/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+var batchResourceNames = []corev1.ResourceName{apiext.BatchCPU, apiext.BatchMemory}
+
+// nodeSnapshot is the colocation status of a node at the sync.
+type nodeSnapshot struct {
+	pool           string
+	batchCapacity  corev1.ResourceList
+	batchAllocated corev1.ResourceList
+	batchUsage     corev1.ResourceList
+	// allowedBatchMilliCPU is the batch cpu allowed by the cpu suppression, which equals to the allocated if the
+	// node is not suppressed.
+	allowedBatchMilliCPU int64
+	podEvictionCount     int64
+	hasColocationMetric  bool
+}
+
+// nodeCounter records the counters of a node observed by the last sync.
+type nodeCounter struct {
+	podEvictionCount int64
+	interfered       bool
+}
+
+// newNodeSnapshot builds the snapshot of a node with its batch pods and its NodeMetric.
+func newNodeSnapshot(node *corev1.Node, pods []*corev1.Pod, nodeMetric *slov1alpha1.NodeMetric) *nodeSnapshot {
+	s := &nodeSnapshot{
+		pool:           node.Labels[apiext.LabelNodeColocationPool],
+		batchCapacity:  quotav1.Mask(node.Status.Allocatable, batchResourceNames),
+		batchAllocated: corev1.ResourceList{},
+		batchUsage:     corev1.ResourceList{},
+	}
+	for _, pod := range pods {
+		if util.IsPodTerminated(pod) {
+			continue
+		}
+		s.batchAllocated = quotav1.Add(s.batchAllocated, util.GetPodRequest(pod, batchResourceNames...))
+	}
+	s.allowedBatchMilliCPU = s.batchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value()
+
+	if nodeMetric == nil {
+		return s
+	}
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil || !isBatchPodMetric(podMetric) {
+			continue
+		}
+		s.batchUsage = quotav1.Add(s.batchUsage, toBatchResourceList(podMetric.PodUsage.ResourceList))
+	}
+	if colocationMetric := nodeMetric.Status.ColocationMetric; colocationMetric != nil {
+		s.hasColocationMetric = true
+		s.podEvictionCount = colocationMetric.PodEvictionCount
+		if colocationMetric.BESuppressCPU != nil {
+			s.allowedBatchMilliCPU = util.MinInt64(s.allowedBatchMilliCPU, colocationMetric.BESuppressCPU.MilliValue())
+		}
+	}
+	return s
+}
+
+// suppressionPercent returns the percent of the batch cpu allocated but not allowed by the cpu suppression.
+func (s *nodeSnapshot) suppressionPercent() int64 {
+	allocated := s.batchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value()
+	return calculateSuppressionPercent(s.allowedBatchMilliCPU, allocated)
+}
+
+// observe updates the counter of the node and returns the new evictions and interference incidents since the last
+// sync. The evictions before the first observation are not counted since they may belong to the former periods.
+func (s *nodeSnapshot) observe(counter *nodeCounter, observed bool, interferenceThreshold int64) (evictions, interferences int64) {
+	if s.hasColocationMetric {
+		if observed {
+			if s.podEvictionCount >= counter.podEvictionCount {
+				evictions = s.podEvictionCount - counter.podEvictionCount
+			} else { // koordlet restarted
+				evictions = s.podEvictionCount
+			}
+		}
+		counter.podEvictionCount = s.podEvictionCount
+	}
+
+	interfered := s.suppressionPercent() >= interferenceThreshold && interferenceThreshold > 0
+	if interfered && !counter.interfered {
+		interferences = 1
+	}
+	counter.interfered = interfered
+	return evictions, interferences
+}
+
+// summaryBuilder aggregates the node snapshots into a ColocationSummary.
+type summaryBuilder struct {
+	summary              slov1alpha1.ColocationSummary
+	allowedBatchMilliCPU int64
+}
+
+func newSummaryBuilder() *summaryBuilder {
+	return &summaryBuilder{
+		summary: slov1alpha1.ColocationSummary{
+			BatchCapacity:  corev1.ResourceList{},
+			BatchAllocated: corev1.ResourceList{},
+			BatchUsage:     corev1.ResourceList{},
+		},
+	}
+}
+
+func (b *summaryBuilder) add(s *nodeSnapshot, evictions, interferences int64) {
+	b.summary.NodeCount++
+	b.summary.BatchCapacity = quotav1.Add(b.summary.BatchCapacity, s.batchCapacity)
+	b.summary.BatchAllocated = quotav1.Add(b.summary.BatchAllocated, s.batchAllocated)
+	b.summary.BatchUsage = quotav1.Add(b.summary.BatchUsage, s.batchUsage)
+	b.allowedBatchMilliCPU += s.allowedBatchMilliCPU
+	b.summary.PodEvictionCount += evictions
+	b.summary.InterferenceCount += interferences
+}
+
+func (b *summaryBuilder) build() slov1alpha1.ColocationSummary {
+	summary := b.summary
+	allocated := summary.BatchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value()
+	summary.SuppressionPercent = calculateSuppressionPercent(b.allowedBatchMilliCPU, allocated)
+	return summary
+}
+
+// mergeSummary returns the summary of the current period, which takes the latest values of the snapshot and
+// accumulates the counts with the previous ones in the period.
+func mergeSummary(previous, latest slov1alpha1.ColocationSummary) slov1alpha1.ColocationSummary {
+	merged := *latest.DeepCopy()
+	merged.PodEvictionCount += previous.PodEvictionCount
+	merged.InterferenceCount += previous.InterferenceCount
+	return merged
+}
+
+func calculateSuppressionPercent(allowedMilliCPU, allocatedMilliCPU int64) int64 {
+	if allocatedMilliCPU <= 0 || allowedMilliCPU >= allocatedMilliCPU {
+		return 0
+	}
+	if allowedMilliCPU <= 0 {
+		return 100
+	}
+	return 100 - allowedMilliCPU*100/allocatedMilliCPU
+}
+
+func isBatchPodMetric(podMetric *slov1alpha1.PodMetricInfo) bool {
+	return podMetric.Priority == apiext.PriorityBatch || podMetric.QoS == apiext.QoSBE
+}
+
+// toBatchResourceList converts the cpu and memory usages into the batch resources in the units of the batch
+// allocatable, i.e. milli-cores for the batch-cpu.
+func toBatchResourceList(usage corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	if q, ok := usage[corev1.ResourceCPU]; ok {
+		result[apiext.BatchCPU] = *resource.NewQuantity(q.MilliValue(), resource.DecimalSI)
+	}
+	if q, ok := usage[corev1.ResourceMemory]; ok {
+		result[apiext.BatchMemory] = q.DeepCopy()
+	}
+	return result
+}
+
+// getPoolStatusName returns the name of the ColocationStatus of the node pool.
+func getPoolStatusName(pool string) string {
+	return slov1alpha1.ColocationStatusPoolNamePrefix + pool
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package colocationstatus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_calculateSuppressionPercent(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   int64
+		allocated int64
+		want      int64
+	}{
+		{name: "no batch allocated", allowed: 0, allocated: 0, want: 0},
+		{name: "not suppressed", allowed: 4000, allocated: 4000, want: 0},
+		{name: "allowed more than allocated", allowed: 8000, allocated: 4000, want: 0},
+		{name: "partially suppressed", allowed: 1000, allocated: 4000, want: 75},
+		{name: "fully suppressed", allowed: 0, allocated: 4000, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, calculateSuppressionPercent(tt.allowed, tt.allocated))
+		})
+	}
+}
+
+func Test_newNodeSnapshot(t *testing.T) {
+	node := newTestNode("test-node", "pool-a", 8000, 16<<30)
+	pods := []*corev1.Pod{
+		newTestBatchPod("batch-pod-0", "test-node", 2000, 4<<30),
+		newTestBatchPod("batch-pod-1", "test-node", 2000, 4<<30),
+	}
+	terminatedPod := newTestBatchPod("batch-pod-2", "test-node", 2000, 4<<30)
+	terminatedPod.Status.Phase = corev1.PodSucceeded
+	pods = append(pods, terminatedPod)
+	suppressCPU := resource.MustParse("1")
+	nodeMetric := newTestNodeMetric("test-node", 3, &suppressCPU)
+
+	got := newNodeSnapshot(node, pods, nodeMetric)
+	assert.Equal(t, "pool-a", got.pool)
+	assert.Equal(t, int64(8000), got.batchCapacity.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(4000), got.batchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(8<<30), got.batchAllocated.Name(apiext.BatchMemory, resource.BinarySI).Value())
+	assert.Equal(t, int64(1500), got.batchUsage.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(2<<30), got.batchUsage.Name(apiext.BatchMemory, resource.BinarySI).Value())
+	assert.Equal(t, int64(1000), got.allowedBatchMilliCPU)
+	assert.Equal(t, int64(3), got.podEvictionCount)
+	assert.Equal(t, int64(75), got.suppressionPercent())
+
+	got = newNodeSnapshot(node, pods, nil)
+	assert.Equal(t, int64(4000), got.allowedBatchMilliCPU)
+	assert.Equal(t, int64(0), got.suppressionPercent())
+	assert.False(t, got.hasColocationMetric)
+}
+
+func Test_nodeSnapshot_observe(t *testing.T) {
+	node := newTestNode("test-node", "", 8000, 16<<30)
+	pods := []*corev1.Pod{newTestBatchPod("batch-pod-0", "test-node", 4000, 4<<30)}
+	suppressCPU := resource.MustParse("1")
+
+	counter := &nodeCounter{}
+	// the first observation does not count the evictions
+	evictions, interferences := newNodeSnapshot(node, pods, newTestNodeMetric("test-node", 5, &suppressCPU)).observe(counter, false, 50)
+	assert.Equal(t, int64(0), evictions)
+	assert.Equal(t, int64(1), interferences)
+	assert.Equal(t, &nodeCounter{podEvictionCount: 5, interfered: true}, counter)
+
+	// still interfered
+	evictions, interferences = newNodeSnapshot(node, pods, newTestNodeMetric("test-node", 7, &suppressCPU)).observe(counter, true, 50)
+	assert.Equal(t, int64(2), evictions)
+	assert.Equal(t, int64(0), interferences)
+
+	// recovered and koordlet restarted
+	evictions, interferences = newNodeSnapshot(node, pods, newTestNodeMetric("test-node", 1, nil)).observe(counter, true, 50)
+	assert.Equal(t, int64(1), evictions)
+	assert.Equal(t, int64(0), interferences)
+	assert.Equal(t, &nodeCounter{podEvictionCount: 1, interfered: false}, counter)
+
+	// interfered again
+	evictions, interferences = newNodeSnapshot(node, pods, newTestNodeMetric("test-node", 1, &suppressCPU)).observe(counter, true, 50)
+	assert.Equal(t, int64(0), evictions)
+	assert.Equal(t, int64(1), interferences)
+}
+
+func Test_summaryBuilder(t *testing.T) {
+	suppressCPU := resource.MustParse("1")
+	b := newSummaryBuilder()
+	b.add(newNodeSnapshot(newTestNode("node-0", "", 8000, 16<<30),
+		[]*corev1.Pod{newTestBatchPod("batch-pod-0", "node-0", 4000, 4<<30)},
+		newTestNodeMetric("node-0", 0, &suppressCPU)), 2, 1)
+	b.add(newNodeSnapshot(newTestNode("node-1", "", 8000, 16<<30),
+		[]*corev1.Pod{newTestBatchPod("batch-pod-1", "node-1", 4000, 4<<30)},
+		newTestNodeMetric("node-1", 0, nil)), 1, 0)
+
+	got := b.build()
+	assert.Equal(t, int64(2), got.NodeCount)
+	assert.Equal(t, int64(16000), got.BatchCapacity.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(8000), got.BatchAllocated.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	assert.Equal(t, int64(3000), got.BatchUsage.Name(apiext.BatchCPU, resource.DecimalSI).Value())
+	// (1000 + 4000) of 8000 allowed
+	assert.Equal(t, int64(38), got.SuppressionPercent)
+	assert.Equal(t, int64(3), got.PodEvictionCount)
+	assert.Equal(t, int64(1), got.InterferenceCount)
+
+	merged := mergeSummary(slov1alpha1.ColocationSummary{PodEvictionCount: 4, InterferenceCount: 2, NodeCount: 5}, got)
+	assert.Equal(t, int64(2), merged.NodeCount)
+	assert.Equal(t, int64(7), merged.PodEvictionCount)
+	assert.Equal(t, int64(3), merged.InterferenceCount)
+}
+
+func newTestNode(name, pool string, batchMilliCPU, batchMemory int64) *corev1.Node {
+	node := &corev1.Node{}
+	node.Name = name
+	if pool != "" {
+		node.Labels = map[string]string{apiext.LabelNodeColocationPool: pool}
+	}
+	node.Status.Allocatable = corev1.ResourceList{
+		corev1.ResourceCPU:  resource.MustParse("16"),
+		apiext.BatchCPU:     *resource.NewQuantity(batchMilliCPU, resource.DecimalSI),
+		apiext.BatchMemory:  *resource.NewQuantity(batchMemory, resource.BinarySI),
+		corev1.ResourcePods: resource.MustParse("110"),
+	}
+	return node
+}
+
+func newTestBatchPod(name, nodeName string, batchMilliCPU, batchMemory int64) *corev1.Pod {
+	pod := &corev1.Pod{}
+	pod.Namespace = "default"
+	pod.Name = name
+	pod.Labels = map[string]string{apiext.LabelPodQoS: string(apiext.QoSBE)}
+	pod.Spec.NodeName = nodeName
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "main",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					apiext.BatchCPU:    *resource.NewQuantity(batchMilliCPU, resource.DecimalSI),
+					apiext.BatchMemory: *resource.NewQuantity(batchMemory, resource.BinarySI),
+				},
+			},
+		},
+	}
+	pod.Status.Phase = corev1.PodRunning
+	return pod
+}
+
+func newTestNodeMetric(name string, podEvictionCount int64, beSuppressCPU *resource.Quantity) *slov1alpha1.NodeMetric {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	nodeMetric.Name = name
+	nodeMetric.Status.PodsMetric = []*slov1alpha1.PodMetricInfo{
+		{
+			Namespace: "default",
+			Name:      "batch-pod",
+			Priority:  apiext.PriorityBatch,
+			PodUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1500m"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+		},
+		{
+			Namespace: "default",
+			Name:      "ls-pod",
+			Priority:  apiext.PriorityProd,
+			QoS:       apiext.QoSLS,
+			PodUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("4"),
+				},
+			},
+		},
+	}
+	nodeMetric.Status.ColocationMetric = &slov1alpha1.ColocationMetricInfo{
+		PodEvictionCount: podEvictionCount,
+		BESuppressCPU:    beSuppressCPU,
+	}
+	return nodeMetric
+}
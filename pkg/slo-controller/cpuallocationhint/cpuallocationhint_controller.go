@@ -0,0 +1,220 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuallocationhint
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "cpuallocationhint"
+
+var (
+	// MinSamples is the minimum number of the samples for a workload to recommend the hint.
+	MinSamples = 12
+	// MaxSamples is the maximum number of the latest samples kept for each pod of a workload.
+	MaxSamples = 288
+	// HistoryExpiration is the duration after which the samples of a workload without new samples are dropped.
+	HistoryExpiration = 7 * 24 * time.Hour
+	// LSRMinUtilizationPercent is the minimum median cpu usage in percentage of the request for a workload to be
+	// recommended as LSR.
+	LSRMinUtilizationPercent = 70
+	// LSRMaxBurstPercent is the maximum peak cpu usage in percentage of the median usage for a workload to be
+	// recommended as LSR. The bursty workloads benefit more from sharing the cpus.
+	LSRMaxBurstPercent = 150
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.IntVar(&MinSamples, "cpu-allocation-hint-min-samples", MinSamples, "The minimum number of the usage samples to recommend the CPU allocation hint of a workload.")
+	pflag.IntVar(&MaxSamples, "cpu-allocation-hint-max-samples", MaxSamples, "The maximum number of the latest usage samples kept for each pod of a workload.")
+	pflag.DurationVar(&HistoryExpiration, "cpu-allocation-hint-history-expiration", HistoryExpiration, "The duration after which the usage samples of a workload without new samples are dropped.")
+	pflag.IntVar(&LSRMinUtilizationPercent, "cpu-allocation-hint-lsr-min-utilization-percent", LSRMinUtilizationPercent, "The minimum median cpu usage in percentage of the request to recommend a workload as LSR.")
+	pflag.IntVar(&LSRMaxBurstPercent, "cpu-allocation-hint-lsr-max-burst-percent", LSRMaxBurstPercent, "The maximum peak cpu usage in percentage of the median usage to recommend a workload as LSR.")
+}
+
+// NodeMetricReconciler learns the cpu usages of the workloads from NodeMetric, and annotates the workloads with the
+// recommended CPU allocation hints.
+type NodeMetricReconciler struct {
+	client.Client
+	learner *Learner
+
+	lock sync.Mutex
+	// lastUpdateTimes records the update time of the NodeMetrics which have been learned
+	lastUpdateTimes map[string]time.Time
+	// hints records the hints which have been annotated on the workloads
+	hints      map[workloadRef]apiext.CPUAllocationHint
+	lastGCTime time.Time
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;patch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+
+func (r *NodeMetricReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	if err := r.Client.Get(ctx, req.NamespacedName, nodeMetric); err != nil {
+		if errors.IsNotFound(err) {
+			r.lock.Lock()
+			delete(r.lastUpdateTimes, req.Name)
+			r.lock.Unlock()
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get nodeMetric %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if nodeMetric.Status.UpdateTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	updateTime := nodeMetric.Status.UpdateTime.Time
+	r.lock.Lock()
+	lastUpdateTime, ok := r.lastUpdateTimes[req.Name]
+	if ok && !updateTime.After(lastUpdateTime) {
+		r.lock.Unlock()
+		// the samples of the report have been learned
+		return ctrl.Result{}, nil
+	}
+	r.lastUpdateTimes[req.Name] = updateTime
+	if now.Sub(r.lastGCTime) > time.Hour {
+		r.learner.GC(now)
+		for workload := range r.hints {
+			if r.learner.Recommend(workload, now) == nil {
+				delete(r.hints, workload)
+			}
+		}
+		r.lastGCTime = now
+	}
+	r.lock.Unlock()
+
+	learned := map[workloadRef]struct{}{}
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil {
+			continue
+		}
+		pod := &corev1.Pod{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: podMetric.Namespace, Name: podMetric.Name}, pod); err != nil {
+			klog.V(5).Infof("failed to get pod %s/%s of nodeMetric %s, err: %v",
+				podMetric.Namespace, podMetric.Name, req.Name, err)
+			continue
+		}
+		if workload, ok := r.learner.Learn(pod, podMetric, updateTime); ok {
+			learned[workload] = struct{}{}
+		}
+	}
+	klog.V(5).Infof("learned cpu usages of %d workloads from nodeMetric %s", len(learned), req.Name)
+
+	var lastErr error
+	for workload := range learned {
+		if err := r.annotateWorkload(ctx, workload, now); err != nil {
+			klog.Errorf("failed to annotate CPU allocation hint of workload %s, err: %v", workload, err)
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return ctrl.Result{Requeue: true}, lastErr
+	}
+	return ctrl.Result{}, nil
+}
+
+// annotateWorkload patches the recommended hint on the workload if it is changed.
+func (r *NodeMetricReconciler) annotateWorkload(ctx context.Context, workload workloadRef, now time.Time) error {
+	hint := r.learner.Recommend(workload, now)
+	if hint == nil {
+		return nil
+	}
+	r.lock.Lock()
+	lastHint, ok := r.hints[workload]
+	r.lock.Unlock()
+	if ok && lastHint == *hint {
+		return nil
+	}
+
+	obj := newWorkloadMetadata(workload)
+	if err := apiext.SetCPUAllocationHint(obj, hint); err != nil {
+		return err
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": obj.Annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data)); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	r.lock.Lock()
+	r.hints[workload] = *hint
+	r.lock.Unlock()
+	klog.V(4).Infof("annotate workload %s with CPU allocation hint %+v", workload, *hint)
+	return nil
+}
+
+// newWorkloadMetadata returns the metadata-only object of the workload, so the workload is patched without caching
+// the whole objects.
+func newWorkloadMetadata(workload workloadRef) *metav1.PartialObjectMetadata {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       workload.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: workload.Namespace,
+			Name:      workload.Name,
+		},
+	}
+}
+
+// Add creates the controller which recommends the CPU allocation hints of the workloads.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.CPUAllocationHint) {
+		klog.V(4).Infof("feature %s is disabled, skip the cpu allocation hint controller", features.CPUAllocationHint)
+		return nil
+	}
+	r := &NodeMetricReconciler{
+		Client:          mgr.GetClient(),
+		learner:         NewLearner(),
+		lastUpdateTimes: map[string]time.Time{},
+		hints:           map[workloadRef]apiext.CPUAllocationHint{},
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slov1alpha1.NodeMetric{}).
+		Named(Name).
+		Complete(r)
+}
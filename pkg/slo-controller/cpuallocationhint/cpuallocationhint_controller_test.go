@@ -0,0 +1,92 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuallocationhint
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestCPUAllocationHintReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+
+	pod := newTestPod("nginx-5d8f7c-abcde", "nginx", apiext.QoSLS, "2")
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+	}
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			PodsMetric: []*slov1alpha1.PodMetricInfo{
+				newTestPodMetric(pod, "1800m"),
+				// the pod is not found
+				newTestPodMetric(newTestPod("deleted-pod", "nginx", apiext.QoSLS, "2"), "1800m"),
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod, deployment, nodeMetric).WithStatusSubresource(nodeMetric).Build()
+	r := &NodeMetricReconciler{
+		Client:          c,
+		learner:         NewLearner(),
+		lastUpdateTimes: map[string]time.Time{},
+		hints:           map[workloadRef]apiext.CPUAllocationHint{},
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}}
+
+	updateTime := time.Now()
+	for i := 0; i < MinSamples; i++ {
+		updateTime = updateTime.Add(time.Minute)
+		nodeMetric.Status.UpdateTime = &metav1.Time{Time: updateTime}
+		assert.NoError(t, c.Status().Update(context.TODO(), nodeMetric))
+		_, err := r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+		// the report has been learned
+		_, err = r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+	}
+	workload := workloadRef{Namespace: "default", Kind: "Deployment", Name: "nginx"}
+	assert.Len(t, r.learner.workloads[workload].pods[pod.Name].usages, MinSamples)
+
+	got := &appsv1.Deployment{}
+	assert.NoError(t, c.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "nginx"}, got))
+	hint, err := apiext.GetCPUAllocationHint(got.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &apiext.CPUAllocationHint{QoSClass: apiext.QoSLSR, CPUs: 2}, hint)
+
+	// the nodeMetric is deleted
+	assert.NoError(t, c.Delete(context.TODO(), nodeMetric))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.NotContains(t, r.lastUpdateTimes, "test-node")
+}
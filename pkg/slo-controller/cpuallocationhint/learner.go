@@ -0,0 +1,192 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuallocationhint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// workloadRef is the reference of a top-level workload which the hint is annotated on.
+type workloadRef struct {
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+func (w workloadRef) String() string {
+	return fmt.Sprintf("%s/%s/%s", w.Namespace, w.Kind, w.Name)
+}
+
+type workloadSamples struct {
+	// pods are the samples of the pods by the pod name, so the samples of each pod cover the same window no matter
+	// how many replicas the workload has
+	pods map[string]*podSamples
+	// request is the cpu request of the latest learned pod in cores
+	request    float64
+	updateTime time.Time
+}
+
+type podSamples struct {
+	// usages are the latest cpu usages of the pod in cores, at most MaxSamples
+	usages     []float64
+	updateTime time.Time
+}
+
+// Learner learns the cpu usages of the LS and LSR workloads from the pod usages reported in the NodeMetrics, and
+// recommends whether the pods of a workload should take exclusive cpus.
+// The samples are only kept in memory, so the history is learned again after koord-manager restarts or the leader
+// changes. The hints annotated on the workloads are kept meanwhile, and they are updated once the new samples are
+// enough.
+type Learner struct {
+	lock      sync.RWMutex
+	workloads map[workloadRef]*workloadSamples
+}
+
+func NewLearner() *Learner {
+	return &Learner{
+		workloads: map[workloadRef]*workloadSamples{},
+	}
+}
+
+// Learn records a sample of the pod usage reported in the NodeMetric.
+// It returns false if the pod does not belong to a supported workload or is not an LS or LSR pod with cpu request.
+// The pod mutated to LSR by the hint is learned against its original cpu request, since its request is pinned to the
+// exclusive cpus.
+func (l *Learner) Learn(pod *corev1.Pod, podMetric *slov1alpha1.PodMetricInfo, now time.Time) (workloadRef, bool) {
+	workload, ok := getWorkloadRef(pod)
+	if !ok || podMetric == nil {
+		return workload, false
+	}
+	if qosClass := apiext.GetPodQoSClassRaw(pod); qosClass != apiext.QoSLS && qosClass != apiext.QoSLSR {
+		return workload, false
+	}
+	requests := util.GetPodRequest(pod, corev1.ResourceCPU)
+	request := requests.Cpu().MilliValue()
+	if originalRequest, ok := apiext.GetCPUAllocationHintOriginalCPURequest(pod.Annotations); ok {
+		request = originalRequest.MilliValue()
+	}
+	if request <= 0 {
+		return workload, false
+	}
+	usage, ok := podMetric.PodUsage.ResourceList[corev1.ResourceCPU]
+	if !ok {
+		return workload, false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	samples, ok := l.workloads[workload]
+	if !ok {
+		samples = &workloadSamples{pods: map[string]*podSamples{}}
+		l.workloads[workload] = samples
+	}
+	pSamples, ok := samples.pods[pod.Name]
+	if !ok {
+		pSamples = &podSamples{}
+		samples.pods[pod.Name] = pSamples
+	}
+	pSamples.usages = append(pSamples.usages, float64(usage.MilliValue())/1000)
+	if len(pSamples.usages) > MaxSamples {
+		pSamples.usages = pSamples.usages[len(pSamples.usages)-MaxSamples:]
+	}
+	pSamples.updateTime = now
+	samples.request = float64(request) / 1000
+	samples.updateTime = now
+	return workload, true
+}
+
+// Recommend returns the CPU allocation hint of the workload.
+// A workload steadily using most of its request scales with the cores, so it is recommended to be LSR with the
+// exclusive cpus covering its peak usage. The others stay LS to share the cpus.
+// It returns nil if the workload has not enough samples or the samples have expired.
+func (l *Learner) Recommend(workload workloadRef, now time.Time) *apiext.CPUAllocationHint {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	samples, ok := l.workloads[workload]
+	if !ok || now.Sub(samples.updateTime) > HistoryExpiration {
+		return nil
+	}
+	var sorted []float64
+	for _, pSamples := range samples.pods {
+		if now.Sub(pSamples.updateTime) <= HistoryExpiration {
+			sorted = append(sorted, pSamples.usages...)
+		}
+	}
+	if len(sorted) < MinSamples {
+		return nil
+	}
+	sort.Float64s(sorted)
+	median, peak := percentile(sorted, 0.5), percentile(sorted, 0.95)
+	if peak >= 1 && median*100 >= float64(LSRMinUtilizationPercent)*samples.request &&
+		peak*100 <= float64(LSRMaxBurstPercent)*median {
+		return &apiext.CPUAllocationHint{
+			QoSClass: apiext.QoSLSR,
+			CPUs:     int64(math.Ceil(peak)),
+		}
+	}
+	return &apiext.CPUAllocationHint{
+		QoSClass: apiext.QoSLS,
+	}
+}
+
+// GC removes the samples which are not updated during the history expiration, e.g. the pods have been deleted.
+func (l *Learner) GC(now time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for workload, samples := range l.workloads {
+		for name, pSamples := range samples.pods {
+			if now.Sub(pSamples.updateTime) > HistoryExpiration {
+				delete(samples.pods, name)
+			}
+		}
+		if len(samples.pods) <= 0 || now.Sub(samples.updateTime) > HistoryExpiration {
+			delete(l.workloads, workload)
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) <= 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
+
+// getWorkloadRef returns the top-level workload of the pod. Only the Deployments and StatefulSets are supported
+// since the hint is applied to the pods recreated by the workload.
+func getWorkloadRef(pod *corev1.Pod) (workloadRef, bool) {
+	kind := util.GetPodWorkloadKind(pod)
+	if kind != "Deployment" && kind != "StatefulSet" {
+		return workloadRef{}, false
+	}
+	return workloadRef{Namespace: pod.Namespace, Kind: kind, Name: util.GetPodWorkloadName(pod)}, true
+}
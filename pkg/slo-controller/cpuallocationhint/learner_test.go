@@ -0,0 +1,144 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuallocationhint
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func newTestPod(name, deployment string, qosClass apiext.QoSClass, cpuRequest string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels: map[string]string{
+				apiext.LabelPodQoS:                     string(qosClass),
+				appsv1.DefaultDeploymentUniqueLabelKey: "5d8f7c",
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: deployment + "-5d8f7c", Controller: pointer.Bool(true)},
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newTestPodMetric(pod *corev1.Pod, cpuUsage string) *slov1alpha1.PodMetricInfo {
+	return &slov1alpha1.PodMetricInfo{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		PodUsage: slov1alpha1.ResourceMap{
+			ResourceList: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuUsage)},
+		},
+	}
+}
+
+func Test_getWorkloadRef(t *testing.T) {
+	pod := newTestPod("nginx-5d8f7c-abcde", "nginx", apiext.QoSLS, "2")
+	got, ok := getWorkloadRef(pod)
+	assert.True(t, ok)
+	assert.Equal(t, workloadRef{Namespace: "default", Kind: "Deployment", Name: "nginx"}, got)
+	assert.Equal(t, "default/Deployment/nginx", got.String())
+
+	pod.OwnerReferences[0].Kind = "Job"
+	_, ok = getWorkloadRef(pod)
+	assert.False(t, ok)
+	pod.OwnerReferences = nil
+	_, ok = getWorkloadRef(pod)
+	assert.False(t, ok)
+}
+
+func TestLearner(t *testing.T) {
+	now := time.Now()
+	l := NewLearner()
+
+	// BE pods and the pods without cpu request are not learned
+	bePod := newTestPod("be-pod", "be", apiext.QoSBE, "2")
+	_, ok := l.Learn(bePod, newTestPodMetric(bePod, "2"), now)
+	assert.False(t, ok)
+	noRequestPod := newTestPod("no-request-pod", "no-request", apiext.QoSLS, "0")
+	_, ok = l.Learn(noRequestPod, newTestPodMetric(noRequestPod, "1"), now)
+	assert.False(t, ok)
+
+	steadyPod := newTestPod("steady-pod", "steady", apiext.QoSLS, "4")
+	burstyPod := newTestPod("bursty-pod", "bursty", apiext.QoSLS, "4")
+	idlePod := newTestPod("idle-pod", "idle", apiext.QoSLS, "4")
+	var steady, bursty, idle workloadRef
+	for i := 0; i < MinSamples; i++ {
+		steady, ok = l.Learn(steadyPod, newTestPodMetric(steadyPod, "3200m"), now)
+		assert.True(t, ok)
+		burstyUsage := "3"
+		if i%3 == 0 {
+			burstyUsage = "6"
+		}
+		bursty, _ = l.Learn(burstyPod, newTestPodMetric(burstyPod, burstyUsage), now)
+		idle, _ = l.Learn(idlePod, newTestPodMetric(idlePod, "500m"), now)
+		if i == 0 {
+			// not enough samples
+			assert.Nil(t, l.Recommend(steady, now))
+		}
+	}
+
+	assert.Equal(t, &apiext.CPUAllocationHint{QoSClass: apiext.QoSLSR, CPUs: 4}, l.Recommend(steady, now))
+	assert.Equal(t, &apiext.CPUAllocationHint{QoSClass: apiext.QoSLS}, l.Recommend(bursty, now))
+	assert.Equal(t, &apiext.CPUAllocationHint{QoSClass: apiext.QoSLS}, l.Recommend(idle, now))
+
+	// the samples are limited
+	for i := 0; i < MaxSamples+1; i++ {
+		l.Learn(idlePod, newTestPodMetric(idlePod, "500m"), now)
+	}
+	assert.Len(t, l.workloads[idle].pods[idlePod.Name].usages, MaxSamples)
+
+	// the samples of each pod are limited separately
+	idlePod1 := newTestPod("idle-pod-1", "idle", apiext.QoSLS, "4")
+	l.Learn(idlePod1, newTestPodMetric(idlePod1, "500m"), now)
+	assert.Len(t, l.workloads[idle].pods[idlePod.Name].usages, MaxSamples)
+	assert.Len(t, l.workloads[idle].pods[idlePod1.Name].usages, 1)
+
+	// the pod mutated to LSR is learned against the original request
+	lsrPod := newTestPod("steady-pod-1", "steady", apiext.QoSLSR, "3")
+	lsrPod.Annotations = map[string]string{apiext.AnnotationCPUAllocationHintOriginalCPURequest: "4"}
+	_, ok = l.Learn(lsrPod, newTestPodMetric(lsrPod, "2900m"), now)
+	assert.True(t, ok)
+	assert.Equal(t, float64(4), l.workloads[steady].request)
+
+	// expired
+	expired := now.Add(HistoryExpiration + time.Minute)
+	assert.Nil(t, l.Recommend(steady, expired))
+	l.GC(expired)
+	assert.Len(t, l.workloads, 0)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictionbudget
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilevictionbudget "github.com/koordinator-sh/koordinator/pkg/util/evictionbudget"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "evictionbudget"
+
+var timeNow = time.Now
+
+// Reconciler maintains the statuses of the ClusterEvictionBudgets. The evictions are recorded by the evictors of
+// koordlet and koord-descheduler, and the reconciler prunes the ones out of the window, so the counts in the status
+// keep recovering even if no eviction happens.
+type Reconciler struct {
+	client.Client
+}
+
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=clusterevictionbudgets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=clusterevictionbudgets/status,verbs=get;update;patch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	budget := &slov1alpha1.ClusterEvictionBudget{}
+	if err := r.Client.Get(ctx, req.NamespacedName, budget); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get cluster eviction budget %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	now := timeNow()
+	newStatus := utilevictionbudget.NewStatus(budget, now)
+	if !isStatusEqual(&budget.Status, &newStatus) {
+		newBudget := budget.DeepCopy()
+		newBudget.Status = newStatus
+		if err := r.Client.Status().Update(ctx, newBudget); err != nil {
+			klog.Errorf("failed to update cluster eviction budget %s, err: %v", req.Name, err)
+			return ctrl.Result{Requeue: true}, err
+		}
+		klog.V(4).Infof("update cluster eviction budget %s, current evictions %d, remaining evictions %d",
+			req.Name, newStatus.CurrentEvictions, newStatus.RemainingEvictions)
+		budget = newBudget
+	}
+
+	// requeue when the oldest eviction expires
+	if d := utilevictionbudget.GetNextExpireDuration(budget, now); d > 0 {
+		return ctrl.Result{RequeueAfter: d}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// isStatusEqual compares the statuses ignoring the update time.
+func isStatusEqual(old, new *slov1alpha1.ClusterEvictionBudgetStatus) bool {
+	oldCopy, newCopy := old.DeepCopy(), new.DeepCopy()
+	oldCopy.UpdateTime, newCopy.UpdateTime = nil, nil
+	return equality.Semantic.DeepEqual(oldCopy, newCopy)
+}
+
+// Add creates the controller which maintains the statuses of the ClusterEvictionBudgets.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ClusterEvictionBudget) {
+		klog.V(4).Infof("feature %s is disabled, skip the cluster eviction budget controller", features.ClusterEvictionBudget)
+		return nil
+	}
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(Name).
+		For(&slov1alpha1.ClusterEvictionBudget{}).
+		Complete(reconciler)
+}
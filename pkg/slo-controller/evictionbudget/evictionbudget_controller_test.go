@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictionbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestEvictionBudgetReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+
+	// the time is serialized in seconds
+	now := time.Now().Truncate(time.Second)
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return now }
+
+	budget := &slov1alpha1.ClusterEvictionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-budget",
+		},
+		Spec: slov1alpha1.ClusterEvictionBudgetSpec{
+			MaxEvictions: 2,
+			Window:       metav1.Duration{Duration: 10 * time.Minute},
+		},
+		Status: slov1alpha1.ClusterEvictionBudgetStatus{
+			Evictions: []slov1alpha1.EvictionRecord{
+				{Namespace: "default", Name: "pod-0", Evictor: slov1alpha1.EvictionBudgetEvictorKoordlet, Time: metav1.NewTime(now.Add(-15 * time.Minute))},
+				{Namespace: "default", Name: "pod-1", Evictor: slov1alpha1.EvictionBudgetEvictorDescheduler, Time: metav1.NewTime(now.Add(-4 * time.Minute))},
+			},
+			CurrentEvictions: 2,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&slov1alpha1.ClusterEvictionBudget{}).
+		WithObjects(budget).Build()
+	r := &Reconciler{Client: fakeClient}
+	ctx := context.TODO()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-budget"}}
+
+	// the expired eviction is pruned
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: 6 * time.Minute}, result)
+	got := &slov1alpha1.ClusterEvictionBudget{}
+	assert.NoError(t, fakeClient.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, 1, len(got.Status.Evictions))
+	assert.Equal(t, "pod-1", got.Status.Evictions[0].Name)
+	assert.Equal(t, int32(1), got.Status.CurrentEvictions)
+	assert.Equal(t, int32(1), got.Status.RemainingEvictions)
+	assert.NotNil(t, got.Status.UpdateTime)
+
+	// all evictions expire
+	now = now.Add(6 * time.Minute)
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(ctx, req.NamespacedName, got))
+	assert.Equal(t, 0, len(got.Status.Evictions))
+	assert.Equal(t, int32(0), got.Status.CurrentEvictions)
+	assert.Equal(t, int32(2), got.Status.RemainingEvictions)
+
+	// the deleted budget is ignored
+	assert.NoError(t, fakeClient.Delete(ctx, got))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+}
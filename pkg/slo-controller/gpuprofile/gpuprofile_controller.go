@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuprofile
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "gpuprofile"
+
+var (
+	// MinSamples is the minimum number of the samples for a workload to predict the profile.
+	MinSamples = 6
+	// HistoryExpiration is the duration after which the profile of a workload without new samples is dropped.
+	HistoryExpiration = 7 * 24 * time.Hour
+	// BoundMarginPercent is the minimum gap between the GPU core and memory utilizations for a workload to be
+	// considered as compute-bound or memory-bound.
+	BoundMarginPercent = 20
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.IntVar(&MinSamples, "gpu-profile-min-samples", MinSamples, "The minimum number of the usage samples to predict the GPU job profile of a workload.")
+	pflag.DurationVar(&HistoryExpiration, "gpu-profile-history-expiration", HistoryExpiration, "The duration after which the GPU job profile of a workload without new usage samples is dropped.")
+	pflag.IntVar(&BoundMarginPercent, "gpu-profile-bound-margin-percent", BoundMarginPercent, "The minimum gap between GPU core and memory utilizations to consider a workload as compute-bound or memory-bound.")
+}
+
+// NodeMetricReconciler learns the GPU job profiles from the pod usages in NodeMetric.
+type NodeMetricReconciler struct {
+	client.Client
+	learner *Learner
+
+	lock sync.Mutex
+	// lastUpdateTimes records the update time of the NodeMetrics which have been learned
+	lastUpdateTimes map[string]time.Time
+	lastGCTime      time.Time
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+
+func (r *NodeMetricReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	if err := r.Client.Get(ctx, req.NamespacedName, nodeMetric); err != nil {
+		if errors.IsNotFound(err) {
+			r.lock.Lock()
+			delete(r.lastUpdateTimes, req.Name)
+			r.lock.Unlock()
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get nodeMetric %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if nodeMetric.Status.UpdateTime == nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	updateTime := nodeMetric.Status.UpdateTime.Time
+	r.lock.Lock()
+	lastUpdateTime, ok := r.lastUpdateTimes[req.Name]
+	if ok && !updateTime.After(lastUpdateTime) {
+		r.lock.Unlock()
+		// the samples of the report have been learned
+		return ctrl.Result{}, nil
+	}
+	r.lastUpdateTimes[req.Name] = updateTime
+	if now.Sub(r.lastGCTime) > time.Hour {
+		r.learner.GC(now)
+		r.lastGCTime = now
+	}
+	r.lock.Unlock()
+
+	learned := 0
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil || len(podMetric.PodUsage.Devices) <= 0 {
+			continue
+		}
+		pod := &corev1.Pod{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: podMetric.Namespace, Name: podMetric.Name}, pod); err != nil {
+			klog.V(5).Infof("failed to get pod %s/%s of nodeMetric %s, err: %v",
+				podMetric.Namespace, podMetric.Name, req.Name, err)
+			continue
+		}
+		if r.learner.Learn(pod, podMetric, updateTime) {
+			learned++
+		}
+	}
+	klog.V(5).Infof("learned GPU job profiles of %d pods from nodeMetric %s", learned, req.Name)
+	return ctrl.Result{}, nil
+}
+
+// PodReconciler annotates the pending GPU pods with the profiles predicted by the learner.
+type PodReconciler struct {
+	client.Client
+	learner *Learner
+}
+
+func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get pod %s, err: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if !isPodToAnnotate(pod) {
+		return ctrl.Result{}, nil
+	}
+	profile := r.learner.Predict(pod, time.Now())
+	if profile == nil {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if err := apiext.SetGPUJobProfile(pod, profile); err != nil {
+		klog.Errorf("failed to set GPU job profile of pod %s, err: %v", req.NamespacedName, err)
+		return ctrl.Result{}, nil
+	}
+	if err := r.Client.Patch(ctx, pod, patch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to patch GPU job profile of pod %s, err: %v", req.NamespacedName, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	klog.V(4).Infof("annotate pod %s with GPU job profile %+v", req.NamespacedName, *profile)
+	return ctrl.Result{}, nil
+}
+
+// isPodToAnnotate checks if the pod is a pending GPU pod without the profile.
+func isPodToAnnotate(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName != "" || pod.DeletionTimestamp != nil {
+		return false
+	}
+	if _, ok := pod.Annotations[apiext.AnnotationGPUJobProfile]; ok {
+		return false
+	}
+	gpuCore, gpuMemoryRatio := getPodGPURequest(pod)
+	return gpuCore > 0 && gpuMemoryRatio > 0
+}
+
+// Add creates the controllers which learn the GPU job profiles and annotate the new pods.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.GPUJobProfile) {
+		klog.V(4).Infof("feature %s is disabled, skip the gpu profile controller", features.GPUJobProfile)
+		return nil
+	}
+	learner := NewLearner()
+	nodeMetricReconciler := &NodeMetricReconciler{
+		Client:          mgr.GetClient(),
+		learner:         learner,
+		lastUpdateTimes: map[string]time.Time{},
+	}
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&slov1alpha1.NodeMetric{}).
+		Named(Name + "-nodemetric").
+		Complete(nodeMetricReconciler); err != nil {
+		return err
+	}
+
+	podReconciler := &PodReconciler{
+		Client:  mgr.GetClient(),
+		learner: learner,
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&corev1.Pod{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				pod, ok := e.Object.(*corev1.Pod)
+				return ok && isPodToAnnotate(pod)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return false
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Named(Name + "-pod").
+		Complete(podReconciler)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuprofile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestGPUProfileReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+
+	requests := corev1.ResourceList{
+		apiext.ResourceNvidiaGPU: resource.MustParse("1"),
+	}
+	runningPod := newTestGPUPod("running-pod", "Job", "test-job", requests)
+	runningPod.Spec.NodeName = "test-node"
+	pendingPod := newTestGPUPod("pending-pod", "Job", "test-job", requests)
+	otherPod := newTestGPUPod("other-pod", "Job", "other-job", requests)
+	updateTime := metav1.Now()
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &updateTime,
+			PodsMetric: []*slov1alpha1.PodMetricInfo{
+				newTestPodMetric(runningPod, 90, 20),
+				// the pod is not found
+				newTestPodMetric(newTestGPUPod("deleted-pod", "Job", "test-job", requests), 90, 20),
+				{Namespace: "default", Name: "cpu-pod"},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(runningPod, pendingPod, otherPod, nodeMetric).Build()
+
+	learner := NewLearner()
+	nodeMetricReconciler := &NodeMetricReconciler{
+		Client:          fakeClient,
+		learner:         learner,
+		lastUpdateTimes: map[string]time.Time{},
+	}
+	podReconciler := &PodReconciler{
+		Client:  fakeClient,
+		learner: learner,
+	}
+	ctx := context.TODO()
+
+	_, err := nodeMetricReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}})
+	assert.NoError(t, err)
+	// the same report is learned once
+	for i := 0; i < MinSamples; i++ {
+		_, err = nodeMetricReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, learner.profiles["default/Job/test-job"].samples)
+	for i := 1; i < MinSamples; i++ {
+		assert.True(t, learner.Learn(runningPod, newTestPodMetric(runningPod, 90, 20), time.Now()))
+	}
+
+	for _, pod := range []*corev1.Pod{runningPod, pendingPod, otherPod} {
+		_, err = podReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}})
+		assert.NoError(t, err)
+	}
+	_, err = podReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "not-found"}})
+	assert.NoError(t, err)
+
+	gotPod := &corev1.Pod{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "pending-pod"}, gotPod))
+	profile, err := apiext.GetGPUJobProfile(gotPod.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, &apiext.GPUJobProfile{
+		Type:           apiext.GPUJobProfileComputeBound,
+		GPUCore:        90,
+		GPUMemoryRatio: 20,
+	}, profile)
+	// the scheduled pod and the pod without learned profile are not annotated
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "running-pod"}, gotPod))
+	assert.NotContains(t, gotPod.Annotations, apiext.AnnotationGPUJobProfile)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "other-pod"}, gotPod))
+	assert.NotContains(t, gotPod.Annotations, apiext.AnnotationGPUJobProfile)
+
+	// the deleted nodeMetric is forgotten
+	assert.NoError(t, fakeClient.Delete(ctx, nodeMetric))
+	_, err = nodeMetricReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}})
+	assert.NoError(t, err)
+	assert.NotContains(t, nodeMetricReconciler.lastUpdateTimes, "test-node")
+}
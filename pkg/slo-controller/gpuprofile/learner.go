@@ -0,0 +1,193 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuprofile
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// smoothingFactor is the weight of the latest sample in the exponential moving average
+	smoothingFactor = 0.2
+)
+
+type workloadProfile struct {
+	gpuCore        float64
+	gpuMemoryRatio float64
+	samples        int
+	updateTime     time.Time
+}
+
+// Learner learns the GPU utilization profiles of the workloads from the pod usages reported in the NodeMetrics.
+// The utilizations are the exponential moving averages of the usages in percentage of the GPU requests.
+type Learner struct {
+	lock     sync.RWMutex
+	profiles map[string]*workloadProfile
+}
+
+func NewLearner() *Learner {
+	return &Learner{
+		profiles: map[string]*workloadProfile{},
+	}
+}
+
+// Learn records a sample of the pod usage reported in the NodeMetric.
+// It returns false if the pod does not belong to a workload or does not use any GPU.
+func (l *Learner) Learn(pod *corev1.Pod, podMetric *slov1alpha1.PodMetricInfo, now time.Time) bool {
+	workload := getWorkloadKey(pod)
+	if workload == "" || podMetric == nil {
+		return false
+	}
+	gpuCore, gpuMemoryRatio, ok := getPodGPUUtilization(pod, podMetric.PodUsage.Devices)
+	if !ok {
+		return false
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	profile, ok := l.profiles[workload]
+	if !ok {
+		l.profiles[workload] = &workloadProfile{
+			gpuCore:        gpuCore,
+			gpuMemoryRatio: gpuMemoryRatio,
+			samples:        1,
+			updateTime:     now,
+		}
+		return true
+	}
+	profile.gpuCore = smoothingFactor*gpuCore + (1-smoothingFactor)*profile.gpuCore
+	profile.gpuMemoryRatio = smoothingFactor*gpuMemoryRatio + (1-smoothingFactor)*profile.gpuMemoryRatio
+	profile.samples++
+	profile.updateTime = now
+	return true
+}
+
+// Predict returns the GPU job profile of the pod by the learned profile of its workload.
+// It returns nil if the profile has not enough samples or has expired.
+func (l *Learner) Predict(pod *corev1.Pod, now time.Time) *apiext.GPUJobProfile {
+	workload := getWorkloadKey(pod)
+	if workload == "" {
+		return nil
+	}
+
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	profile, ok := l.profiles[workload]
+	if !ok || profile.samples < MinSamples || now.Sub(profile.updateTime) > HistoryExpiration {
+		return nil
+	}
+	return &apiext.GPUJobProfile{
+		Type:           getProfileType(profile.gpuCore, profile.gpuMemoryRatio),
+		GPUCore:        int64(math.Round(profile.gpuCore)),
+		GPUMemoryRatio: int64(math.Round(profile.gpuMemoryRatio)),
+	}
+}
+
+// GC removes the profiles which are not updated during the history expiration.
+func (l *Learner) GC(now time.Time) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for workload, profile := range l.profiles {
+		if now.Sub(profile.updateTime) > HistoryExpiration {
+			delete(l.profiles, workload)
+		}
+	}
+}
+
+func getProfileType(gpuCore, gpuMemoryRatio float64) apiext.GPUJobProfileType {
+	if gpuCore-gpuMemoryRatio >= float64(BoundMarginPercent) {
+		return apiext.GPUJobProfileComputeBound
+	}
+	if gpuMemoryRatio-gpuCore >= float64(BoundMarginPercent) {
+		return apiext.GPUJobProfileMemoryBound
+	}
+	return apiext.GPUJobProfileBalanced
+}
+
+// getWorkloadKey returns the key of the owner workload, e.g. `default/Deployment/nginx`.
+// The pods of a Deployment are keyed by the Deployment, so the profile is kept across the rollouts.
+func getWorkloadKey(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+	kind, name := owner.Kind, owner.Name
+	if hash := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; kind == "ReplicaSet" && hash != "" {
+		kind, name = "Deployment", strings.TrimSuffix(name, "-"+hash)
+	}
+	return fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, name)
+}
+
+// getPodGPURequest returns the requested GPU core and memory ratio of the pod, where a full GPU is 100.
+func getPodGPURequest(pod *corev1.Pod) (int64, int64) {
+	requests := util.GetPodRequest(pod)
+	var fullGPUs int64
+	for _, resourceName := range []corev1.ResourceName{
+		apiext.ResourceNvidiaGPU, apiext.ResourceAMDGPU, apiext.ResourceHygonDCU, apiext.ResourceGPU,
+	} {
+		if q, ok := requests[resourceName]; ok {
+			fullGPUs += q.Value()
+		}
+	}
+	gpuCore, gpuMemoryRatio := fullGPUs*100, fullGPUs*100
+	if q, ok := requests[apiext.ResourceGPUCore]; ok {
+		gpuCore += q.Value()
+	}
+	if q, ok := requests[apiext.ResourceGPUMemoryRatio]; ok {
+		gpuMemoryRatio += q.Value()
+	}
+	return gpuCore, gpuMemoryRatio
+}
+
+// getPodGPUUtilization returns the GPU core and memory utilizations of the pod in percentage of the requests.
+func getPodGPUUtilization(pod *corev1.Pod, devices []schedulingv1alpha1.DeviceInfo) (float64, float64, bool) {
+	requestCore, requestMemoryRatio := getPodGPURequest(pod)
+	if requestCore <= 0 || requestMemoryRatio <= 0 {
+		return 0, 0, false
+	}
+	var usedCore, usedMemoryRatio int64
+	var hasGPU bool
+	for _, device := range devices {
+		if device.Type != schedulingv1alpha1.GPU {
+			continue
+		}
+		hasGPU = true
+		if q, ok := device.Resources[apiext.ResourceGPUCore]; ok {
+			usedCore += q.Value()
+		}
+		if q, ok := device.Resources[apiext.ResourceGPUMemoryRatio]; ok {
+			usedMemoryRatio += q.Value()
+		}
+	}
+	if !hasGPU {
+		return 0, 0, false
+	}
+	return float64(usedCore) * 100 / float64(requestCore), float64(usedMemoryRatio) * 100 / float64(requestMemoryRatio), true
+}
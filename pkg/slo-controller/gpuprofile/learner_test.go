@@ -0,0 +1,187 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gpuprofile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func newTestGPUPod(name, ownerKind, ownerName string, requests corev1.ResourceList) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			Labels:    map[string]string{},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name: "main",
+					Resources: corev1.ResourceRequirements{
+						Requests: requests,
+					},
+				},
+			},
+		},
+	}
+	if ownerKind != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{
+			{
+				APIVersion: "apps/v1",
+				Kind:       ownerKind,
+				Name:       ownerName,
+				Controller: pointer.Bool(true),
+			},
+		}
+	}
+	return pod
+}
+
+func newTestPodMetric(pod *corev1.Pod, gpuCore, gpuMemoryRatio int64) *slov1alpha1.PodMetricInfo {
+	return &slov1alpha1.PodMetricInfo{
+		Namespace: pod.Namespace,
+		Name:      pod.Name,
+		PodUsage: slov1alpha1.ResourceMap{
+			Devices: []schedulingv1alpha1.DeviceInfo{
+				{
+					Type:  schedulingv1alpha1.GPU,
+					Minor: pointer.Int32(0),
+					Resources: corev1.ResourceList{
+						apiext.ResourceGPUCore:        *resource.NewQuantity(gpuCore, resource.DecimalSI),
+						apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(gpuMemoryRatio, resource.DecimalSI),
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_getWorkloadKey(t *testing.T) {
+	pod := newTestGPUPod("test-pod", "ReplicaSet", "test-deploy-5d8f9c", nil)
+	pod.Labels["pod-template-hash"] = "5d8f9c"
+	assert.Equal(t, "default/Deployment/test-deploy", getWorkloadKey(pod))
+
+	pod = newTestGPUPod("test-pod", "ReplicaSet", "test-rs", nil)
+	assert.Equal(t, "default/ReplicaSet/test-rs", getWorkloadKey(pod))
+
+	pod = newTestGPUPod("test-pod", "Job", "test-job", nil)
+	assert.Equal(t, "default/Job/test-job", getWorkloadKey(pod))
+
+	pod = newTestGPUPod("test-pod", "", "", nil)
+	assert.Equal(t, "", getWorkloadKey(pod))
+}
+
+func Test_getPodGPURequest(t *testing.T) {
+	tests := []struct {
+		name               string
+		requests           corev1.ResourceList
+		wantGPUCore        int64
+		wantGPUMemoryRatio int64
+	}{
+		{
+			name: "no gpu",
+			requests: corev1.ResourceList{
+				corev1.ResourceCPU: resource.MustParse("1"),
+			},
+		},
+		{
+			name: "nvidia gpu",
+			requests: corev1.ResourceList{
+				apiext.ResourceNvidiaGPU: resource.MustParse("2"),
+			},
+			wantGPUCore:        200,
+			wantGPUMemoryRatio: 200,
+		},
+		{
+			name: "shared gpu",
+			requests: corev1.ResourceList{
+				apiext.ResourceGPUCore:        resource.MustParse("50"),
+				apiext.ResourceGPUMemoryRatio: resource.MustParse("25"),
+			},
+			wantGPUCore:        50,
+			wantGPUMemoryRatio: 25,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotCore, gotMemoryRatio := getPodGPURequest(newTestGPUPod("test-pod", "Job", "test-job", tt.requests))
+			assert.Equal(t, tt.wantGPUCore, gotCore)
+			assert.Equal(t, tt.wantGPUMemoryRatio, gotMemoryRatio)
+		})
+	}
+}
+
+func TestLearner(t *testing.T) {
+	now := time.Now()
+	learner := NewLearner()
+	requests := corev1.ResourceList{
+		apiext.ResourceGPUCore:        resource.MustParse("50"),
+		apiext.ResourceGPUMemoryRatio: resource.MustParse("50"),
+	}
+	computePod := newTestGPUPod("compute-pod", "Job", "compute-job", requests)
+	memoryPod := newTestGPUPod("memory-pod", "Job", "memory-job", requests)
+	balancedPod := newTestGPUPod("balanced-pod", "Job", "balanced-job", requests)
+
+	// not a gpu pod, or not owned by a workload
+	assert.False(t, learner.Learn(newTestGPUPod("cpu-pod", "Job", "cpu-job", nil), newTestPodMetric(computePod, 40, 10), now))
+	assert.False(t, learner.Learn(newTestGPUPod("bare-pod", "", "", requests), newTestPodMetric(computePod, 40, 10), now))
+	assert.False(t, learner.Learn(computePod, &slov1alpha1.PodMetricInfo{}, now))
+
+	for i := 0; i < MinSamples; i++ {
+		assert.Nil(t, learner.Predict(computePod, now))
+		assert.True(t, learner.Learn(computePod, newTestPodMetric(computePod, 45, 10), now))
+		assert.True(t, learner.Learn(memoryPod, newTestPodMetric(memoryPod, 10, 40), now))
+		assert.True(t, learner.Learn(balancedPod, newTestPodMetric(balancedPod, 30, 25), now))
+	}
+
+	assert.Equal(t, &apiext.GPUJobProfile{
+		Type:           apiext.GPUJobProfileComputeBound,
+		GPUCore:        90,
+		GPUMemoryRatio: 20,
+	}, learner.Predict(computePod, now))
+	assert.Equal(t, &apiext.GPUJobProfile{
+		Type:           apiext.GPUJobProfileMemoryBound,
+		GPUCore:        20,
+		GPUMemoryRatio: 80,
+	}, learner.Predict(memoryPod, now))
+	assert.Equal(t, &apiext.GPUJobProfile{
+		Type:           apiext.GPUJobProfileBalanced,
+		GPUCore:        60,
+		GPUMemoryRatio: 50,
+	}, learner.Predict(balancedPod, now))
+
+	// a new pod of the workload gets the profile
+	newComputePod := newTestGPUPod("compute-pod-1", "Job", "compute-job", requests)
+	assert.Equal(t, apiext.GPUJobProfileComputeBound, learner.Predict(newComputePod, now).Type)
+
+	// the expired profiles are not predicted and are removed
+	expired := now.Add(HistoryExpiration + time.Minute)
+	assert.Nil(t, learner.Predict(computePod, expired))
+	learner.GC(expired)
+	assert.Len(t, learner.profiles, 0)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsprovider
+
+import (
+	"flag"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "metricsprovider"
+
+var (
+	// BindAddress is the address the metrics api server binds to.
+	BindAddress = ":9445"
+	// CertDir is the directory of the serving certificate tls.crt and tls.key,
+	// a self-signed certificate is generated if it is empty or the files do not exist.
+	CertDir = ""
+	// AuthKubeconfig is the kubeconfig to create the TokenReviews and SubjectAccessReviews,
+	// the in-cluster config is used if it is empty.
+	AuthKubeconfig = ""
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.StringVar(&BindAddress, "metrics-provider-bind-address", BindAddress, "The address the metrics.k8s.io api provider binds to.")
+	pflag.StringVar(&CertDir, "metrics-provider-cert-dir", CertDir, "The directory containing tls.crt and tls.key for the metrics.k8s.io api provider.")
+	pflag.StringVar(&AuthKubeconfig, "metrics-provider-auth-kubeconfig", AuthKubeconfig, "The kubeconfig to delegate the authentication and authorization of the metrics.k8s.io api provider, the in-cluster config is used if it is empty.")
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes;pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+
+// Add registers the metrics.k8s.io api provider as a runnable of the manager.
+// It is registered with an APIService so that small clusters can drop the metrics-server.
+func Add(mgr manager.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.NodeMetricsAPIProvider) {
+		klog.V(4).Infof("feature %s is disabled, skip the metrics api provider", features.NodeMetricsAPIProvider)
+		return nil
+	}
+	server, err := newSecureServer(NewProvider(mgr.GetClient()))
+	if err != nil {
+		return err
+	}
+	return mgr.Add(server)
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	defaultWindowSeconds = 300
+
+	apiGroupPath   = "/apis/" + metricsGroupName
+	apiVersionPath = apiGroupPath + "/" + metricsGroupVersion
+
+	metricsGroupName    = "metrics.k8s.io"
+	metricsGroupVersion = "v1beta1"
+)
+
+// Provider serves the metrics.k8s.io API with the usages reported in the NodeMetrics.
+type Provider struct {
+	reader client.Reader
+}
+
+func NewProvider(reader client.Reader) *Provider {
+	return &Provider{reader: reader}
+}
+
+// GetNodeMetrics returns the metrics of the nodes matching the selector.
+func (p *Provider) GetNodeMetrics(ctx context.Context, name string, selector labels.Selector) ([]metricsapi.NodeMetrics, error) {
+	var nodes []corev1.Node
+	if name != "" {
+		node := &corev1.Node{}
+		if err := p.reader.Get(ctx, types.NamespacedName{Name: name}, node); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, *node)
+	} else {
+		nodeList := &corev1.NodeList{}
+		if err := p.reader.List(ctx, nodeList, &client.ListOptions{LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+		nodes = nodeList.Items
+	}
+
+	var results []metricsapi.NodeMetrics
+	for i := range nodes {
+		nodeMetric := &slov1alpha1.NodeMetric{}
+		if err := p.reader.Get(ctx, types.NamespacedName{Name: nodes[i].Name}, nodeMetric); err != nil {
+			if errors.IsNotFound(err) {
+				klog.V(5).Infof("skip node %s for metrics api, nodeMetric not found", nodes[i].Name)
+				continue
+			}
+			return nil, err
+		}
+		m := convertNodeMetrics(&nodes[i], nodeMetric)
+		if m == nil {
+			continue
+		}
+		results = append(results, *m)
+	}
+	if name != "" && len(results) == 0 {
+		return nil, errors.NewNotFound(metricsapi.Resource("nodes"), name)
+	}
+	return results, nil
+}
+
+// GetPodMetrics returns the metrics of the pods in the namespace matching the selector.
+// An empty namespace means all namespaces.
+func (p *Provider) GetPodMetrics(ctx context.Context, namespace, name string, selector labels.Selector) ([]metricsapi.PodMetrics, error) {
+	var pods []corev1.Pod
+	if name != "" {
+		pod := &corev1.Pod{}
+		if err := p.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, pod); err != nil {
+			return nil, err
+		}
+		pods = append(pods, *pod)
+	} else {
+		podList := &corev1.PodList{}
+		if err := p.reader.List(ctx, podList, &client.ListOptions{Namespace: namespace, LabelSelector: selector}); err != nil {
+			return nil, err
+		}
+		pods = podList.Items
+	}
+
+	// group the pods by node to get each NodeMetric only once
+	nodeMetrics := map[string]*slov1alpha1.NodeMetric{}
+	var results []metricsapi.PodMetrics
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+		nodeMetric, ok := nodeMetrics[pod.Spec.NodeName]
+		if !ok {
+			nodeMetric = &slov1alpha1.NodeMetric{}
+			if err := p.reader.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, nodeMetric); err != nil {
+				if !errors.IsNotFound(err) {
+					return nil, err
+				}
+				nodeMetric = nil
+			}
+			nodeMetrics[pod.Spec.NodeName] = nodeMetric
+		}
+		if nodeMetric == nil {
+			continue
+		}
+		m := convertPodMetrics(pod, nodeMetric)
+		if m == nil {
+			continue
+		}
+		results = append(results, *m)
+	}
+	if name != "" && len(results) == 0 {
+		return nil, errors.NewNotFound(metricsapi.Resource("pods"), name)
+	}
+	return results, nil
+}
+
+func convertNodeMetrics(node *corev1.Node, nodeMetric *slov1alpha1.NodeMetric) *metricsapi.NodeMetrics {
+	if nodeMetric.Status.UpdateTime == nil || nodeMetric.Status.NodeMetric == nil ||
+		nodeMetric.Status.NodeMetric.NodeUsage.ResourceList == nil {
+		return nil
+	}
+	return &metricsapi.NodeMetrics{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              node.Name,
+			Labels:            node.Labels,
+			CreationTimestamp: metav1.NewTime(time.Now()),
+		},
+		Timestamp: *nodeMetric.Status.UpdateTime,
+		Window:    getWindow(nodeMetric),
+		Usage:     filterUsage(nodeMetric.Status.NodeMetric.NodeUsage.ResourceList),
+	}
+}
+
+func convertPodMetrics(pod *corev1.Pod, nodeMetric *slov1alpha1.NodeMetric) *metricsapi.PodMetrics {
+	if nodeMetric.Status.UpdateTime == nil {
+		return nil
+	}
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil || podMetric.Namespace != pod.Namespace || podMetric.Name != pod.Name {
+			continue
+		}
+		// the pods reported by the koordlet without the container usages are skipped like the metrics-server
+		// does for the pods whose containers are not collected yet
+		if len(podMetric.ContainersMetric) == 0 {
+			return nil
+		}
+		containers := make([]metricsapi.ContainerMetrics, 0, len(podMetric.ContainersMetric))
+		for _, containerMetric := range podMetric.ContainersMetric {
+			containers = append(containers, metricsapi.ContainerMetrics{
+				Name:  containerMetric.Name,
+				Usage: filterUsage(containerMetric.Usage.ResourceList),
+			})
+		}
+		return &metricsapi.PodMetrics{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         pod.Namespace,
+				Name:              pod.Name,
+				Labels:            pod.Labels,
+				CreationTimestamp: metav1.NewTime(time.Now()),
+			},
+			Timestamp:  *nodeMetric.Status.UpdateTime,
+			Window:     getWindow(nodeMetric),
+			Containers: containers,
+		}
+	}
+	return nil
+}
+
+func getWindow(nodeMetric *slov1alpha1.NodeMetric) metav1.Duration {
+	windowSeconds := int64(defaultWindowSeconds)
+	if nodeMetric.Spec.CollectPolicy != nil && nodeMetric.Spec.CollectPolicy.AggregateDurationSeconds != nil {
+		windowSeconds = *nodeMetric.Spec.CollectPolicy.AggregateDurationSeconds
+	}
+	return metav1.Duration{Duration: time.Duration(windowSeconds) * time.Second}
+}
+
+// filterUsage keeps the resources supported by the metrics api.
+func filterUsage(usage corev1.ResourceList) corev1.ResourceList {
+	result := corev1.ResourceList{}
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if q, ok := usage[resourceName]; ok {
+			result[resourceName] = q.DeepCopy()
+		}
+	}
+	return result
+}
+
+// ServeHTTP serves the discovery and the resource paths of the metrics.k8s.io/v1beta1 API.
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeStatus(w, errors.NewMethodNotSupported(metricsapi.Resource(""), r.Method))
+		return
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch path {
+	case "/apis":
+		writeJSON(w, http.StatusOK, apiGroupList())
+		return
+	case apiGroupPath:
+		writeJSON(w, http.StatusOK, apiGroup())
+		return
+	case apiVersionPath:
+		writeJSON(w, http.StatusOK, apiResourceList())
+		return
+	}
+	if !strings.HasPrefix(path, apiVersionPath+"/") {
+		writeStatus(w, errors.NewNotFound(metricsapi.Resource(""), path))
+		return
+	}
+
+	selector, err := labels.Parse(r.URL.Query().Get("labelSelector"))
+	if err != nil {
+		writeStatus(w, errors.NewBadRequest(fmt.Sprintf("invalid labelSelector, err: %v", err)))
+		return
+	}
+
+	// nodes, nodes/{name}, pods, namespaces/{namespace}/pods, namespaces/{namespace}/pods/{name}
+	parts := strings.Split(strings.TrimPrefix(path, apiVersionPath+"/"), "/")
+	ctx := r.Context()
+	switch {
+	case parts[0] == "nodes" && len(parts) <= 2:
+		name := ""
+		if len(parts) == 2 {
+			name = parts[1]
+		}
+		items, err := p.GetNodeMetrics(ctx, name, selector)
+		if err != nil {
+			writeStatus(w, err)
+			return
+		}
+		if name != "" {
+			writeJSON(w, http.StatusOK, withNodeTypeMeta(&items[0]))
+			return
+		}
+		list := &metricsapi.NodeMetricsList{
+			TypeMeta: metav1.TypeMeta{Kind: "NodeMetricsList", APIVersion: metricsapi.SchemeGroupVersion.String()},
+			Items:    items,
+		}
+		if list.Items == nil {
+			list.Items = []metricsapi.NodeMetrics{}
+		}
+		writeJSON(w, http.StatusOK, list)
+	case parts[0] == "pods" && len(parts) == 1,
+		parts[0] == "namespaces" && len(parts) >= 3 && len(parts) <= 4 && parts[2] == "pods":
+		namespace, name := "", ""
+		if len(parts) >= 3 {
+			namespace = parts[1]
+		}
+		if len(parts) == 4 {
+			name = parts[3]
+		}
+		items, err := p.GetPodMetrics(ctx, namespace, name, selector)
+		if err != nil {
+			writeStatus(w, err)
+			return
+		}
+		if name != "" {
+			writeJSON(w, http.StatusOK, withPodTypeMeta(&items[0]))
+			return
+		}
+		list := &metricsapi.PodMetricsList{
+			TypeMeta: metav1.TypeMeta{Kind: "PodMetricsList", APIVersion: metricsapi.SchemeGroupVersion.String()},
+			Items:    items,
+		}
+		if list.Items == nil {
+			list.Items = []metricsapi.PodMetrics{}
+		}
+		writeJSON(w, http.StatusOK, list)
+	default:
+		writeStatus(w, errors.NewNotFound(metricsapi.Resource(""), path))
+	}
+}
+
+func withNodeTypeMeta(m *metricsapi.NodeMetrics) *metricsapi.NodeMetrics {
+	m.TypeMeta = metav1.TypeMeta{Kind: "NodeMetrics", APIVersion: metricsapi.SchemeGroupVersion.String()}
+	return m
+}
+
+func withPodTypeMeta(m *metricsapi.PodMetrics) *metricsapi.PodMetrics {
+	m.TypeMeta = metav1.TypeMeta{Kind: "PodMetrics", APIVersion: metricsapi.SchemeGroupVersion.String()}
+	return m
+}
+
+func apiGroup() *metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{
+		GroupVersion: metricsapi.SchemeGroupVersion.String(),
+		Version:      metricsGroupVersion,
+	}
+	return &metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             metricsGroupName,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+func apiGroupList() *metav1.APIGroupList {
+	return &metav1.APIGroupList{
+		TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+		Groups:   []metav1.APIGroup{*apiGroup()},
+	}
+}
+
+func apiResourceList() *metav1.APIResourceList {
+	verbs := metav1.Verbs{"get", "list"}
+	return &metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: metricsapi.SchemeGroupVersion.String(),
+		APIResources: []metav1.APIResource{
+			{Name: "nodes", Kind: "NodeMetrics", Namespaced: false, Verbs: verbs},
+			{Name: "pods", Kind: "PodMetrics", Namespaced: true, Verbs: verbs},
+		},
+	}
+}
+
+func writeStatus(w http.ResponseWriter, err error) {
+	status, ok := err.(errors.APIStatus)
+	if !ok {
+		status = errors.NewInternalError(err)
+	}
+	s := status.Status()
+	s.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+	code := int(s.Code)
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+	writeJSON(w, code, &s)
+}
+
+func writeJSON(w http.ResponseWriter, code int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.Warningf("failed to write metrics api response, err: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsprovider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	metricsapi "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func newTestProvider() *Provider {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = slov1alpha1.AddToScheme(scheme)
+
+	updateTime := metav1.NewTime(time.Unix(1700000000, 0))
+	objs := []runtime.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"pool": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "b"}}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0", Labels: map[string]string{"app": "x"}},
+			Spec:       corev1.PodSpec{NodeName: "node-0"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-1"},
+			Spec:       corev1.PodSpec{NodeName: "node-0"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-pending"},
+		},
+		&slov1alpha1.NodeMetric{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-0"},
+			Status: slov1alpha1.NodeMetricStatus{
+				UpdateTime: &updateTime,
+				NodeMetric: &slov1alpha1.NodeMetricInfo{
+					NodeUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU:        resource.MustParse("2"),
+						corev1.ResourceMemory:     resource.MustParse("4Gi"),
+						"kubernetes.io/batch-cpu": resource.MustParse("1000"),
+					}},
+				},
+				PodsMetric: []*slov1alpha1.PodMetricInfo{
+					{
+						Namespace: "default",
+						Name:      "pod-0",
+						PodUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("500m"),
+							corev1.ResourceMemory: resource.MustParse("1Gi"),
+						}},
+						ContainersMetric: []slov1alpha1.ContainerMetricInfo{
+							{
+								Name: "main",
+								Usage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("400m"),
+									corev1.ResourceMemory: resource.MustParse("768Mi"),
+								}},
+							},
+							{
+								Name: "sidecar",
+								Usage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+									corev1.ResourceCPU:    resource.MustParse("100m"),
+									corev1.ResourceMemory: resource.MustParse("256Mi"),
+								}},
+							},
+						},
+					},
+					{
+						// reported without the container usages
+						Namespace: "default",
+						Name:      "pod-1",
+						PodUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("200m"),
+							corev1.ResourceMemory: resource.MustParse("512Mi"),
+						}},
+					},
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build()
+	return NewProvider(c)
+}
+
+func TestProviderServeHTTP(t *testing.T) {
+	p := newTestProvider()
+	tests := []struct {
+		name     string
+		path     string
+		wantCode int
+		check    func(t *testing.T, body []byte)
+	}{
+		{
+			name:     "discovery",
+			path:     "/apis/metrics.k8s.io/v1beta1",
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				list := &metav1.APIResourceList{}
+				assert.NoError(t, json.Unmarshal(body, list))
+				assert.Len(t, list.APIResources, 2)
+			},
+		},
+		{
+			name:     "list nodes",
+			path:     "/apis/metrics.k8s.io/v1beta1/nodes",
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				list := &metricsapi.NodeMetricsList{}
+				assert.NoError(t, json.Unmarshal(body, list))
+				assert.Len(t, list.Items, 1)
+				assert.Equal(t, "node-0", list.Items[0].Name)
+				assert.Equal(t, 300*time.Second, list.Items[0].Window.Duration)
+				assert.Len(t, list.Items[0].Usage, 2)
+				cpu := list.Items[0].Usage[corev1.ResourceCPU]
+				assert.Equal(t, int64(2000), cpu.MilliValue())
+			},
+		},
+		{
+			name:     "list nodes with selector",
+			path:     "/apis/metrics.k8s.io/v1beta1/nodes?labelSelector=pool%3Db",
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				list := &metricsapi.NodeMetricsList{}
+				assert.NoError(t, json.Unmarshal(body, list))
+				assert.Len(t, list.Items, 0)
+			},
+		},
+		{
+			name:     "get node without metric",
+			path:     "/apis/metrics.k8s.io/v1beta1/nodes/node-1",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "get pod",
+			path:     "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods/pod-0",
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				m := &metricsapi.PodMetrics{}
+				assert.NoError(t, json.Unmarshal(body, m))
+				assert.Equal(t, "PodMetrics", m.Kind)
+				assert.Len(t, m.Containers, 2)
+				assert.Equal(t, "main", m.Containers[0].Name)
+				cpu := m.Containers[0].Usage[corev1.ResourceCPU]
+				assert.Equal(t, int64(400), cpu.MilliValue())
+				assert.Equal(t, "sidecar", m.Containers[1].Name)
+				memory := m.Containers[1].Usage[corev1.ResourceMemory]
+				assert.Equal(t, int64(256*1024*1024), memory.Value())
+			},
+		},
+		{
+			name:     "get pod without container metrics",
+			path:     "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods/pod-1",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "list pods of all namespaces",
+			path:     "/apis/metrics.k8s.io/v1beta1/pods",
+			wantCode: http.StatusOK,
+			check: func(t *testing.T, body []byte) {
+				list := &metricsapi.PodMetricsList{}
+				assert.NoError(t, json.Unmarshal(body, list))
+				assert.Len(t, list.Items, 1)
+			},
+		},
+		{
+			name:     "invalid selector",
+			path:     "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods?labelSelector=a%3D%3D%3D",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unknown path",
+			path:     "/apis/metrics.k8s.io/v1beta1/services",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			p.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.check != nil {
+				tt.check(t, w.Body.Bytes())
+			}
+		})
+	}
+}
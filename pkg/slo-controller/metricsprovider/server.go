@@ -0,0 +1,123 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	genericapifilters "k8s.io/apiserver/pkg/endpoints/filters"
+	apirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/server"
+	genericfilters "k8s.io/apiserver/pkg/server/filters"
+	apiserveroptions "k8s.io/apiserver/pkg/server/options"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	netutils "k8s.io/utils/net"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+// secureServer serves the metrics api over https. The requests are authenticated and authorized by delegating to
+// the kube-apiserver, i.e. the front-proxy client certificates of the aggregator, and the TokenReviews and the
+// SubjectAccessReviews for the bearer tokens.
+type secureServer struct {
+	servingInfo *server.SecureServingInfo
+	handler     http.Handler
+}
+
+func newSecureServer(handler http.Handler) (*secureServer, error) {
+	host, port, err := net.SplitHostPort(BindAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bind address %s, err: %w", BindAddress, err)
+	}
+	servingOptions := apiserveroptions.NewSecureServingOptions()
+	if host != "" {
+		servingOptions.BindAddress = netutils.ParseIPSloppy(host)
+		if servingOptions.BindAddress == nil {
+			return nil, fmt.Errorf("invalid bind address %s, host must be an ip", BindAddress)
+		}
+	}
+	if servingOptions.BindPort, err = strconv.Atoi(port); err != nil {
+		return nil, fmt.Errorf("invalid bind address %s, err: %w", BindAddress, err)
+	}
+	servingOptions.ServerCert.CertDirectory = CertDir
+	servingOptions.ServerCert.PairName = "tls"
+	if err = servingOptions.MaybeDefaultWithSelfSignedCerts("localhost", nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to prepare the serving certificate, err: %w", err)
+	}
+
+	authnOptions := apiserveroptions.NewDelegatingAuthenticationOptions()
+	authnOptions.RemoteKubeConfigFile = AuthKubeconfig
+	authzOptions := apiserveroptions.NewDelegatingAuthorizationOptions()
+	authzOptions.RemoteKubeConfigFile = AuthKubeconfig
+
+	var servingInfo *server.SecureServingInfo
+	if err = servingOptions.ApplyTo(&servingInfo); err != nil {
+		return nil, fmt.Errorf("failed to apply the secure serving options, err: %w", err)
+	}
+	authnInfo := &server.AuthenticationInfo{}
+	if err = authnOptions.ApplyTo(authnInfo, servingInfo, nil); err != nil {
+		return nil, fmt.Errorf("failed to apply the delegating authentication options, err: %w", err)
+	}
+	authzInfo := &server.AuthorizationInfo{}
+	if err = authzOptions.ApplyTo(authzInfo); err != nil {
+		return nil, fmt.Errorf("failed to apply the delegating authorization options, err: %w", err)
+	}
+
+	return &secureServer{
+		servingInfo: servingInfo,
+		handler:     buildHandlerChain(handler, authnInfo, authzInfo.Authorizer),
+	}, nil
+}
+
+// NeedLeaderElection returns false since every replica could serve the requests from the cache.
+func (s *secureServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *secureServer) Start(ctx context.Context) error {
+	klog.Infof("starting the %s server on %s", Name, BindAddress)
+	stoppedCh, _, err := s.servingInfo.Serve(s.handler, shutdownTimeout, ctx.Done())
+	if err != nil {
+		return err
+	}
+	<-stoppedCh
+	return nil
+}
+
+// buildHandlerChain wraps the given handler with the authentication and authorization filters.
+func buildHandlerChain(handler http.Handler, authn *server.AuthenticationInfo, authz authorizer.Authorizer) http.Handler {
+	requestInfoResolver := &apirequest.RequestInfoFactory{
+		APIPrefixes:          sets.NewString("apis"),
+		GrouplessAPIPrefixes: sets.NewString(),
+	}
+	failedHandler := genericapifilters.Unauthorized(scheme.Codecs)
+
+	handler = genericapifilters.WithAuthorization(handler, authz, scheme.Codecs)
+	handler = genericapifilters.WithAuthentication(handler, authn.Authenticator, failedHandler, authn.APIAudiences, authn.RequestHeaderConfig)
+	handler = genericapifilters.WithRequestInfo(handler, requestInfoResolver)
+	handler = genericfilters.WithPanicRecovery(handler, requestInfoResolver)
+
+	return handler
+}
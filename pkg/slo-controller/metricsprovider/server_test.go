@@ -0,0 +1,110 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricsprovider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apiserver/pkg/authentication/authenticator"
+	"k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/authorization/authorizer"
+	"k8s.io/apiserver/pkg/server"
+)
+
+func TestBuildHandlerChain(t *testing.T) {
+	authn := &server.AuthenticationInfo{
+		Authenticator: authenticator.RequestFunc(func(req *http.Request) (*authenticator.Response, bool, error) {
+			name := req.Header.Get("X-Test-User")
+			if name == "" {
+				return nil, false, nil
+			}
+			return &authenticator.Response{User: &user.DefaultInfo{Name: name}}, true, nil
+		}),
+	}
+	var gotAttrs authorizer.Attributes
+	authz := authorizer.AuthorizerFunc(func(ctx context.Context, a authorizer.Attributes) (authorizer.Decision, string, error) {
+		gotAttrs = a
+		if a.GetUser().GetName() == "allowed" {
+			return authorizer.DecisionAllow, "", nil
+		}
+		return authorizer.DecisionNoOpinion, "", nil
+	})
+	handler := buildHandlerChain(newTestProvider(), authn, authz)
+
+	tests := []struct {
+		name         string
+		user         string
+		path         string
+		wantCode     int
+		wantResource string
+		wantVerb     string
+	}{
+		{
+			name:     "unauthenticated",
+			path:     "/apis/metrics.k8s.io/v1beta1/nodes",
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "forbidden",
+			user:         "denied",
+			path:         "/apis/metrics.k8s.io/v1beta1/nodes",
+			wantCode:     http.StatusForbidden,
+			wantResource: "nodes",
+			wantVerb:     "list",
+		},
+		{
+			name:         "list pods",
+			user:         "allowed",
+			path:         "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods",
+			wantCode:     http.StatusOK,
+			wantResource: "pods",
+			wantVerb:     "list",
+		},
+		{
+			name:         "get pod",
+			user:         "allowed",
+			path:         "/apis/metrics.k8s.io/v1beta1/namespaces/default/pods/pod-0",
+			wantCode:     http.StatusOK,
+			wantResource: "pods",
+			wantVerb:     "get",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAttrs = nil
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.user != "" {
+				req.Header.Set("X-Test-User", tt.user)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			assert.Equal(t, tt.wantCode, w.Code, w.Body.String())
+			if tt.wantResource == "" {
+				assert.Nil(t, gotAttrs)
+				return
+			}
+			assert.NotNil(t, gotAttrs)
+			assert.Equal(t, metricsGroupName, gotAttrs.GetAPIGroup())
+			assert.Equal(t, tt.wantResource, gotAttrs.GetResource())
+			assert.Equal(t, tt.wantVerb, gotAttrs.GetVerb())
+		})
+	}
+}
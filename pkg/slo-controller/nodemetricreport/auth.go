@@ -0,0 +1,108 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetricreport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+const (
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+	// the extra info of the ServiceAccount tokens bound to the pods
+	podNameExtraKey = "authentication.kubernetes.io/pod-name"
+	podUIDExtraKey  = "authentication.kubernetes.io/pod-uid"
+)
+
+// reportAuthenticator authenticates the koordlet of a report stream and returns the node the koordlet runs on.
+type reportAuthenticator interface {
+	authenticate(ctx context.Context, md map[string][]string) (string, error)
+}
+
+var _ reportAuthenticator = &tokenReviewAuthenticator{}
+
+// tokenReviewAuthenticator reviews the bearer token of the stream, which must be a bound token of the allowed
+// ServiceAccounts. The node is resolved from the pod the token is bound to, so a koordlet can only report the
+// metrics of its own node.
+type tokenReviewAuthenticator struct {
+	client client.Client
+	// allowedServiceAccounts are the usernames of the ServiceAccounts, e.g. system:serviceaccount:ns:name
+	allowedServiceAccounts sets.String
+}
+
+func newTokenReviewAuthenticator(c client.Client, allowedServiceAccounts []string) *tokenReviewAuthenticator {
+	usernames := sets.NewString()
+	for _, sa := range allowedServiceAccounts {
+		usernames.Insert(serviceAccountUsernamePrefix + sa)
+	}
+	return &tokenReviewAuthenticator{client: c, allowedServiceAccounts: usernames}
+}
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+func (a *tokenReviewAuthenticator) authenticate(ctx context.Context, md map[string][]string) (string, error) {
+	token, ok := metricreport.GetBearerToken(md)
+	if !ok {
+		return "", fmt.Errorf("bearer token not found")
+	}
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := a.client.Create(ctx, review); err != nil {
+		return "", fmt.Errorf("failed to review token, err: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return "", fmt.Errorf("token is not authenticated, err: %s", review.Status.Error)
+	}
+	user := review.Status.User
+	if !a.allowedServiceAccounts.Has(user.Username) {
+		return "", fmt.Errorf("user %s is not allowed to report node metrics", user.Username)
+	}
+	// a username of the ServiceAccount is in the format of system:serviceaccount:<namespace>:<name>
+	parts := strings.Split(strings.TrimPrefix(user.Username, serviceAccountUsernamePrefix), ":")
+	podName, podUID := getExtraValue(user.Extra, podNameExtraKey), getExtraValue(user.Extra, podUIDExtraKey)
+	if len(parts) != 2 || podName == "" || podUID == "" {
+		return "", fmt.Errorf("token of user %s is not bound to a pod", user.Username)
+	}
+
+	pod := &corev1.Pod{}
+	if err := a.client.Get(ctx, types.NamespacedName{Namespace: parts[0], Name: podName}, pod); err != nil {
+		return "", fmt.Errorf("failed to get pod %s/%s of the token, err: %w", parts[0], podName, err)
+	}
+	if string(pod.UID) != podUID || pod.Spec.NodeName == "" {
+		return "", fmt.Errorf("pod %s/%s of the token is not running on a node", parts[0], podName)
+	}
+	return pod.Spec.NodeName, nil
+}
+
+func getExtraValue(extra map[string]authenticationv1.ExtraValue, key string) string {
+	if values := extra[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetricreport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func Test_tokenReviewAuthenticator(t *testing.T) {
+	koordletPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "koordinator-system", Name: "koordlet-abc", UID: "uid-0"},
+		Spec:       corev1.PodSpec{NodeName: "node-0"},
+	}
+	reviewed := map[string]authenticationv1.TokenReviewStatus{
+		"koordlet-token": {
+			Authenticated: true,
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:koordinator-system:koordlet",
+				Extra: map[string]authenticationv1.ExtraValue{
+					podNameExtraKey: {"koordlet-abc"},
+					podUIDExtraKey:  {"uid-0"},
+				},
+			},
+		},
+		"stale-pod-token": {
+			Authenticated: true,
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:koordinator-system:koordlet",
+				Extra: map[string]authenticationv1.ExtraValue{
+					podNameExtraKey: {"koordlet-abc"},
+					podUIDExtraKey:  {"uid-old"},
+				},
+			},
+		},
+		"unbound-token": {
+			Authenticated: true,
+			User:          authenticationv1.UserInfo{Username: "system:serviceaccount:koordinator-system:koordlet"},
+		},
+		"other-sa-token": {
+			Authenticated: true,
+			User: authenticationv1.UserInfo{
+				Username: "system:serviceaccount:default:default",
+				Extra: map[string]authenticationv1.ExtraValue{
+					podNameExtraKey: {"attacker"},
+					podUIDExtraKey:  {"uid-1"},
+				},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(koordletPod).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if review, ok := obj.(*authenticationv1.TokenReview); ok {
+					review.Status = reviewed[review.Spec.Token]
+					return nil
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+	a := newTokenReviewAuthenticator(fakeClient, []string{"koordinator-system:koordlet"})
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantNode string
+		wantErr  bool
+	}{
+		{
+			name:    "no token",
+			md:      metadata.New(nil),
+			wantErr: true,
+		},
+		{
+			name:    "unauthenticated token",
+			md:      metadata.Pairs("authorization", "Bearer invalid-token"),
+			wantErr: true,
+		},
+		{
+			name:    "not allowed service account",
+			md:      metadata.Pairs("authorization", "Bearer other-sa-token"),
+			wantErr: true,
+		},
+		{
+			name:    "token not bound to pod",
+			md:      metadata.Pairs("authorization", "Bearer unbound-token"),
+			wantErr: true,
+		},
+		{
+			name:    "token bound to deleted pod",
+			md:      metadata.Pairs("authorization", "Bearer stale-pod-token"),
+			wantErr: true,
+		},
+		{
+			name:     "koordlet token",
+			md:       metadata.Pairs("authorization", "Bearer koordlet-token"),
+			wantNode: "node-0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.authenticate(context.TODO(), tt.md)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantNode, got)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetricreport
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+const Name = "nodemetricreport"
+
+var (
+	// BindAddress is the address the node metric report server listens on.
+	BindAddress = ":9316"
+	// SyncInterval is the interval to write the summaries of the received reports into NodeMetrics.
+	SyncInterval = 30 * time.Second
+	// MaxStaleness is the maximum duration a NodeMetric keeps the stale summary if the node usage has no big change.
+	MaxStaleness = 5 * time.Minute
+	// UsageDiffThreshold is the ratio of the node usage change at which the summary is written before MaxStaleness.
+	UsageDiffThreshold = 0.1
+	// TLSCertFile and TLSKeyFile are the serving certificate of the server, which are required since the koordlets
+	// authenticate with their ServiceAccount tokens.
+	TLSCertFile = ""
+	TLSKeyFile  = ""
+	// AllowedServiceAccounts are the ServiceAccounts of the koordlets in the format of <namespace>:<name>.
+	AllowedServiceAccounts = []string{"koordinator-system:koordlet"}
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.StringVar(&BindAddress, "node-metric-report-bind-address", BindAddress, "The address the node metric report server binds to.")
+	pflag.DurationVar(&SyncInterval, "node-metric-report-sync-interval", SyncInterval, "The interval to write the summaries of the node metric reports into NodeMetrics.")
+	pflag.DurationVar(&MaxStaleness, "node-metric-report-max-staleness", MaxStaleness, "The maximum duration a NodeMetric keeps the stale summary if the node usage has no big change.")
+	pflag.Float64Var(&UsageDiffThreshold, "node-metric-report-usage-diff-threshold", UsageDiffThreshold, "The ratio of the node usage change at which the summary is written into NodeMetric before the max staleness.")
+}
+
+var _ manager.LeaderElectionRunnable = &Server{}
+var _ metricreport.NodeMetricReportServiceServer = &Server{}
+
+// Server receives the node metric reports streamed from the koordlets, and writes the compact summaries into the
+// NodeMetrics only when the node usages change significantly or the summaries become stale, instead of each koordlet
+// updating its NodeMetric periodically.
+type Server struct {
+	metricreport.UnimplementedNodeMetricReportServiceServer
+
+	client        client.Client
+	bindAddress   string
+	tlsCertFile   string
+	tlsKeyFile    string
+	authenticator reportAuthenticator
+
+	lock  sync.Mutex
+	nodes map[string]*nodeReport
+}
+
+type nodeReport struct {
+	// latest is the summary of the latest received report
+	latest *slov1alpha1.NodeMetricStatus
+	// synced is the summary written into the NodeMetric at the syncTime
+	synced   *slov1alpha1.NodeMetricStatus
+	syncTime time.Time
+}
+
+func NewServer(c client.Client, bindAddress string) *Server {
+	return &Server{
+		client:        c,
+		bindAddress:   bindAddress,
+		tlsCertFile:   TLSCertFile,
+		tlsKeyFile:    TLSKeyFile,
+		authenticator: newTokenReviewAuthenticator(c, AllowedServiceAccounts),
+		nodes:         map[string]*nodeReport{},
+	}
+}
+
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics/status,verbs=get;update;patch
+
+// Report receives the reports of the authenticated koordlet, which can only report the metrics of the node it runs on.
+func (s *Server) Report(stream metricreport.NodeMetricReportService_ReportServer) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	authNodeName, err := s.authenticator.authenticate(stream.Context(), md)
+	if err != nil {
+		klog.V(4).Infof("reject the node metric report stream, err: %v", err)
+		return status.Errorf(codes.Unauthenticated, "failed to authenticate, err: %v", err)
+	}
+
+	var received int64
+	for {
+		report, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&metricreport.NodeMetricReportResponse{Received: received})
+		}
+		if err != nil {
+			return err
+		}
+		received++
+		if report.NodeName == "" || report.Status == nil {
+			klog.V(5).Infof("skip the invalid node metric report, node %q", report.NodeName)
+			continue
+		}
+		if report.NodeName != authNodeName {
+			klog.Warningf("reject the node metric report of node %s from the koordlet on node %s",
+				report.NodeName, authNodeName)
+			return status.Errorf(codes.PermissionDenied, "not allowed to report the metrics of node %s", report.NodeName)
+		}
+		s.updateReport(report.NodeName, compactStatus(report.Status))
+	}
+}
+
+func (s *Server) updateReport(nodeName string, summary *slov1alpha1.NodeMetricStatus) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	r, ok := s.nodes[nodeName]
+	if !ok {
+		r = &nodeReport{}
+		s.nodes[nodeName] = r
+	}
+	r.latest = summary
+}
+
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.bindAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s, err: %w", s.bindAddress, err)
+	}
+	creds, err := credentials.NewServerTLSFromFile(s.tlsCertFile, s.tlsKeyFile)
+	if err != nil {
+		_ = listener.Close()
+		return fmt.Errorf("failed to load tls certificate, err: %w", err)
+	}
+	grpcServer := grpc.NewServer(grpc.Creds(creds), metricreport.ForceServerCodec())
+	metricreport.RegisterNodeMetricReportServiceServer(grpcServer, s)
+	go func() {
+		<-ctx.Done()
+		// the report streams are long-lived, so stop the server without waiting for them
+		grpcServer.Stop()
+	}()
+	go wait.UntilWithContext(ctx, s.sync, SyncInterval)
+
+	klog.Infof("node metric report server listens on %s", s.bindAddress)
+	return grpcServer.Serve(listener)
+}
+
+// NeedLeaderElection makes only the leader receive the reports and write the NodeMetrics, and the koordlets
+// reconnect to the new leader via the service after the failover.
+func (s *Server) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *Server) sync(ctx context.Context) {
+	now := time.Now()
+	summaries := s.pendingSummaries(now)
+	updated := 0
+	for nodeName, summary := range summaries {
+		err := s.updateNodeMetric(ctx, nodeName, summary)
+		if errors.IsNotFound(err) {
+			// the NodeMetric is created by the nodemetric controller, and the node may have been deleted
+			klog.V(4).Infof("nodeMetric %s not found, drop its reports", nodeName)
+			s.deleteReport(nodeName, summary)
+			continue
+		} else if err != nil {
+			klog.Warningf("failed to update nodeMetric %s status, err: %v", nodeName, err)
+			continue
+		}
+		s.markSynced(nodeName, summary, now)
+		updated++
+	}
+	klog.V(4).Infof("node metric report server updated %d nodeMetrics, %d pending", updated, len(summaries))
+}
+
+// pendingSummaries returns the latest summaries of the nodes which need to be written.
+func (s *Server) pendingSummaries(now time.Time) map[string]*slov1alpha1.NodeMetricStatus {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	summaries := map[string]*slov1alpha1.NodeMetricStatus{}
+	for nodeName, r := range s.nodes {
+		if needSync(r, now) {
+			summaries[nodeName] = r.latest
+		}
+	}
+	return summaries
+}
+
+func (s *Server) markSynced(nodeName string, summary *slov1alpha1.NodeMetricStatus, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if r, ok := s.nodes[nodeName]; ok {
+		r.synced = summary
+		r.syncTime = now
+	}
+}
+
+func (s *Server) deleteReport(nodeName string, summary *slov1alpha1.NodeMetricStatus) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// keep the report if a newer one arrives during the update
+	if r, ok := s.nodes[nodeName]; ok && r.latest == summary {
+		delete(s.nodes, nodeName)
+	}
+}
+
+func (s *Server) updateNodeMetric(ctx context.Context, nodeName string, summary *slov1alpha1.NodeMetricStatus) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		nodeMetric := &slov1alpha1.NodeMetric{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: nodeName}, nodeMetric); err != nil {
+			return err
+		}
+		nodeMetric.Status = *summary.DeepCopy()
+		return s.client.Status().Update(ctx, nodeMetric)
+	})
+}
+
+func needSync(r *nodeReport, now time.Time) bool {
+	if r.latest == nil || r.latest == r.synced {
+		return false
+	}
+	if r.synced == nil || now.Sub(r.syncTime) >= MaxStaleness {
+		return true
+	}
+	// the consumers like the scheduler need to know the new pods soon
+	if len(r.latest.PodsMetric) != len(r.synced.PodsMetric) {
+		return true
+	}
+	oldUsage, newUsage := nodeUsage(r.synced), nodeUsage(r.latest)
+	return util.IsResourceDiff(oldUsage, newUsage, corev1.ResourceCPU, UsageDiffThreshold) ||
+		util.IsResourceDiff(oldUsage, newUsage, corev1.ResourceMemory, UsageDiffThreshold)
+}
+
+func nodeUsage(status *slov1alpha1.NodeMetricStatus) corev1.ResourceList {
+	if status.NodeMetric == nil {
+		return nil
+	}
+	return status.NodeMetric.NodeUsage.ResourceList
+}
+
+// compactStatus drops the pod metrics without any usage, which carry nothing to the consumers.
+func compactStatus(status *slov1alpha1.NodeMetricStatus) *slov1alpha1.NodeMetricStatus {
+	summary := status.DeepCopy()
+	if len(summary.PodsMetric) <= 0 {
+		return summary
+	}
+	podsMetric := make([]*slov1alpha1.PodMetricInfo, 0, len(summary.PodsMetric))
+	for _, podMetric := range summary.PodsMetric {
+		if podMetric == nil || (len(podMetric.PodUsage.ResourceList) <= 0 && len(podMetric.PodUsage.Devices) <= 0) {
+			continue
+		}
+		podsMetric = append(podsMetric, podMetric)
+	}
+	summary.PodsMetric = podsMetric
+	return summary
+}
+
+// Add registers the node metric report server which runs on the leader.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.NodeMetricReportServer) {
+		klog.V(4).Infof("feature %s is disabled, skip the node metric report server", features.NodeMetricReportServer)
+		return nil
+	}
+	if TLSCertFile == "" || TLSKeyFile == "" {
+		return fmt.Errorf("tls cert and key files are required by the node metric report server")
+	}
+	return mgr.Add(NewServer(mgr.GetClient(), BindAddress))
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodemetricreport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/metricreport"
+)
+
+func newTestStatus(cpuMilli int64, pods ...string) *slov1alpha1.NodeMetricStatus {
+	status := &slov1alpha1.NodeMetricStatus{
+		NodeMetric: &slov1alpha1.NodeMetricInfo{
+			NodeUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewMilliQuantity(cpuMilli, resource.DecimalSI),
+					corev1.ResourceMemory: *resource.NewQuantity(4<<30, resource.BinarySI),
+				},
+			},
+		},
+	}
+	for _, pod := range pods {
+		status.PodsMetric = append(status.PodsMetric, &slov1alpha1.PodMetricInfo{
+			Namespace: "default",
+			Name:      pod,
+			PodUsage: slov1alpha1.ResourceMap{
+				ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU: *resource.NewMilliQuantity(500, resource.DecimalSI),
+				},
+			},
+		})
+	}
+	return status
+}
+
+type fakeAuthenticator struct {
+	nodeName string
+	err      error
+}
+
+func (f *fakeAuthenticator) authenticate(ctx context.Context, md map[string][]string) (string, error) {
+	return f.nodeName, f.err
+}
+
+func TestServer(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithStatusSubresource(&slov1alpha1.NodeMetric{}).
+		WithObjects(&slov1alpha1.NodeMetric{ObjectMeta: metav1.ObjectMeta{Name: "node-0"}}).Build()
+	s := NewServer(fakeClient, "")
+	s.authenticator = &fakeAuthenticator{nodeName: "node-0"}
+
+	listener := bufconn.Listen(1024 * 1024)
+	grpcServer := grpc.NewServer(metricreport.ForceServerCodec())
+	metricreport.RegisterNodeMetricReportServiceServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	defer grpcServer.Stop()
+	conn, err := grpc.DialContext(context.TODO(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := metricreport.NewNodeMetricReportServiceClient(conn).Report(context.TODO())
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&metricreport.NodeMetricReport{NodeName: "node-0", Status: newTestStatus(2000, "pod-0")}))
+	assert.NoError(t, stream.Send(&metricreport.NodeMetricReport{NodeName: "node-0", Status: newTestStatus(2100, "pod-0")}))
+	// the invalid report is ignored
+	assert.NoError(t, stream.Send(&metricreport.NodeMetricReport{NodeName: "node-0"}))
+	resp, err := stream.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Received)
+
+	// the koordlet cannot report the metrics of the other nodes
+	stream, err = metricreport.NewNodeMetricReportServiceClient(conn).Report(context.TODO())
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(&metricreport.NodeMetricReport{NodeName: "node-1", Status: newTestStatus(2000)}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	// the unauthenticated stream is rejected
+	s.authenticator = &fakeAuthenticator{err: fmt.Errorf("expected error")}
+	stream, err = metricreport.NewNodeMetricReportServiceClient(conn).Report(context.TODO())
+	assert.NoError(t, err)
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the node without NodeMetric is dropped at the sync
+	s.updateReport("node-1", newTestStatus(2000))
+	ctx := context.TODO()
+	s.sync(ctx)
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "node-0"}, nodeMetric))
+	gotCPU := nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[corev1.ResourceCPU]
+	assert.Equal(t, int64(2100), gotCPU.MilliValue())
+	assert.Len(t, nodeMetric.Status.PodsMetric, 1)
+	assert.Len(t, s.nodes, 1)
+	assert.Contains(t, s.nodes, "node-0")
+
+	// the small usage change is not written until the summary becomes stale
+	s.updateReport("node-0", newTestStatus(2150, "pod-0"))
+	s.sync(ctx)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "node-0"}, nodeMetric))
+	gotCPU = nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[corev1.ResourceCPU]
+	assert.Equal(t, int64(2100), gotCPU.MilliValue())
+
+	s.nodes["node-0"].syncTime = time.Now().Add(-MaxStaleness)
+	s.sync(ctx)
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "node-0"}, nodeMetric))
+	gotCPU = nodeMetric.Status.NodeMetric.NodeUsage.ResourceList[corev1.ResourceCPU]
+	assert.Equal(t, int64(2150), gotCPU.MilliValue())
+}
+
+func Test_needSync(t *testing.T) {
+	now := time.Now()
+	synced := newTestStatus(2000, "pod-0")
+	tests := []struct {
+		name string
+		arg  *nodeReport
+		want bool
+	}{
+		{
+			name: "no report",
+			arg:  &nodeReport{},
+			want: false,
+		},
+		{
+			name: "latest report already synced",
+			arg:  &nodeReport{latest: synced, synced: synced, syncTime: now.Add(-2 * MaxStaleness)},
+			want: false,
+		},
+		{
+			name: "never synced",
+			arg:  &nodeReport{latest: synced},
+			want: true,
+		},
+		{
+			name: "small usage change",
+			arg:  &nodeReport{latest: newTestStatus(2100, "pod-0"), synced: synced, syncTime: now},
+			want: false,
+		},
+		{
+			name: "small usage change but stale",
+			arg:  &nodeReport{latest: newTestStatus(2100, "pod-0"), synced: synced, syncTime: now.Add(-MaxStaleness)},
+			want: true,
+		},
+		{
+			name: "big usage change",
+			arg:  &nodeReport{latest: newTestStatus(3000, "pod-0"), synced: synced, syncTime: now},
+			want: true,
+		},
+		{
+			name: "pods changed",
+			arg:  &nodeReport{latest: newTestStatus(2000, "pod-0", "pod-1"), synced: synced, syncTime: now},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, needSync(tt.arg, now))
+		})
+	}
+}
+
+func Test_compactStatus(t *testing.T) {
+	status := newTestStatus(2000, "pod-0")
+	status.PodsMetric = append(status.PodsMetric, nil, &slov1alpha1.PodMetricInfo{Namespace: "default", Name: "pod-1"})
+	got := compactStatus(status)
+	assert.Len(t, got.PodsMetric, 1)
+	assert.Equal(t, "pod-0", got.PodsMetric[0].Name)
+	assert.Len(t, status.PodsMetric, 3)
+
+	got = compactStatus(newTestStatus(2000))
+	assert.Nil(t, got.PodsMetric)
+}
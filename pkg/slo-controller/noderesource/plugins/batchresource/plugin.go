@@ -64,6 +64,7 @@ func (p *Plugin) Name() string {
 }
 
 // +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
 
 func (p *Plugin) Setup(opt *framework.Option) error {
 	client = opt.Client
@@ -184,6 +185,11 @@ func (p *Plugin) Calculate(strategy *configuration.ColocationStrategy, node *cor
 
 func (p *Plugin) calculate(strategy *configuration.ColocationStrategy, node *corev1.Node, podList *corev1.PodList,
 	resourceMetrics *framework.ResourceMetrics) ([]framework.ResourceItem, error) {
+	// learn the pod lifetimes for dampening the short-lived pods
+	if strategy.ShortLivedPodThresholdSeconds != nil && *strategy.ShortLivedPodThresholdSeconds > 0 {
+		lifetimeTracker.Observe(podList)
+	}
+
 	// calculate node-level batch resources
 	batchAllocatable, cpuMsg, memMsg := p.calculateOnNode(strategy, node, podList, resourceMetrics)
 
@@ -244,9 +250,12 @@ func (p *Plugin) calculateOnNode(strategy *configuration.ColocationStrategy, nod
 		podsAllUsed = quotav1.Add(podsAllUsed, podUsage)
 	}
 
+	// podsFinished are the finished pods whose metrics may still be reported
+	podsFinished := make(map[string]*corev1.Pod)
 	for i := range podList.Items {
 		pod := &podList.Items[i]
 		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			podsFinished[util.GetPodKey(pod)] = pod
 			continue
 		}
 
@@ -264,16 +273,26 @@ func (p *Plugin) calculateOnNode(strategy *configuration.ColocationStrategy, nod
 			continue
 		}
 
+		// dampen the short-lived pods to avoid the oscillation
+		accountingRatio := getShortLivedPodAccountingRatio(strategy, pod)
+		if accountingRatio < 1 {
+			podRequest = resutil.MultiplyResourceList(podRequest, accountingRatio)
+		}
+
 		podsHPRequest = quotav1.Add(podsHPRequest, podRequest)
 		if !hasMetric {
 			podsHPUsed = quotav1.Add(podsHPUsed, podRequest)
-		} else if qos := extension.GetPodQoSClassWithDefault(pod); qos == extension.QoSLSE {
+			continue
+		}
+		podUsed := resutil.GetPodMetricUsage(podMetric)
+		if accountingRatio < 1 {
+			podUsed = resutil.MultiplyResourceList(podUsed, accountingRatio)
+		}
+		if qos := extension.GetPodQoSClassWithDefault(pod); qos == extension.QoSLSE {
 			// NOTE: Currently qos=LSE pods does not reclaim CPU resource.
-			podUsed := resutil.GetPodMetricUsage(podMetric)
 			podsHPUsed = quotav1.Add(podsHPUsed, resutil.MixResourceListCPUAndMemory(podRequest, podUsed))
 			podsHPMaxUsedReq = quotav1.Add(podsHPMaxUsedReq, quotav1.Max(podRequest, podUsed))
 		} else {
-			podUsed := resutil.GetPodMetricUsage(podMetric)
 			podsHPUsed = quotav1.Add(podsHPUsed, podUsed)
 			podsHPMaxUsedReq = quotav1.Add(podsHPMaxUsedReq, quotav1.Max(podRequest, podUsed))
 		}
@@ -282,11 +301,17 @@ func (p *Plugin) calculateOnNode(strategy *configuration.ColocationStrategy, nod
 	hostAppHPUsed := resutil.GetHostAppHPUsed(resourceMetrics, extension.PriorityBatch)
 	// For the pods reported metrics but not shown in current list, count them according to the metric priority.
 	podsDanglingUsed := util.NewZeroResourceList()
-	for _, podMetric := range podMetricDanglingMap {
+	for podKey, podMetric := range podMetricDanglingMap {
 		if priority := podMetric.Priority; priority == extension.PriorityBatch || priority == extension.PriorityFree {
 			continue
 		}
-		podsDanglingUsed = quotav1.Add(podsDanglingUsed, resutil.GetPodMetricUsage(podMetric))
+		podUsed := resutil.GetPodMetricUsage(podMetric)
+		if pod, ok := podsFinished[podKey]; ok {
+			if accountingRatio := getShortLivedPodAccountingRatio(strategy, pod); accountingRatio < 1 {
+				podUsed = resutil.MultiplyResourceList(podUsed, accountingRatio)
+			}
+		}
+		podsDanglingUsed = quotav1.Add(podsDanglingUsed, podUsed)
 	}
 	podsHPUsed = quotav1.Add(podsHPUsed, podsDanglingUsed)
 	podsHPMaxUsedReq = quotav1.Add(podsHPMaxUsedReq, podsDanglingUsed)
@@ -384,9 +409,11 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 		podMetricMap[podKey] = podMetric
 		podMetricUnknownMap[podKey] = podMetric
 	}
+	podsFinished := make(map[string]*corev1.Pod)
 	for i := range podList.Items {
 		pod := &podList.Items[i]
 		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodPending {
+			podsFinished[util.GetPodKey(pod)] = pod
 			continue
 		}
 
@@ -394,11 +421,18 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 		podKey := util.GetPodKey(pod)
 		podMetric, hasMetric := podMetricMap[podKey]
 		podRequest := util.GetPodRequest(pod, corev1.ResourceCPU, corev1.ResourceMemory)
+		accountingRatio := getShortLivedPodAccountingRatio(strategy, pod)
+		if accountingRatio < 1 {
+			podRequest = resutil.MultiplyResourceList(podRequest, accountingRatio)
+		}
 		var podUsage corev1.ResourceList
 		var podZoneRequests, podZoneUsages []corev1.ResourceList
 		if hasMetric {
 			delete(podMetricUnknownMap, podKey)
 			podUsage = resutil.GetPodMetricUsage(podMetric)
+			if accountingRatio < 1 {
+				podUsage = resutil.MultiplyResourceList(podUsage, accountingRatio)
+			}
 			podZoneRequests, podZoneUsages = resutil.GetPodNUMARequestAndUsage(pod, podRequest, podUsage, zoneNum)
 		} else {
 			podUsage = podRequest
@@ -429,11 +463,17 @@ func (p *Plugin) calculateOnNUMALevel(strategy *configuration.ColocationStrategy
 	}
 
 	// For the pods reported metrics but not shown in current list, count them according to the metric priority.
-	for _, podMetric := range podMetricUnknownMap {
+	for podKey, podMetric := range podMetricUnknownMap {
 		if priority := podMetric.Priority; priority == extension.PriorityBatch || priority == extension.PriorityFree {
 			continue
 		}
-		podNUMAUsage := resutil.GetPodUnknownNUMAUsage(resutil.GetPodMetricUsage(podMetric), zoneNum)
+		podUsage := resutil.GetPodMetricUsage(podMetric)
+		if pod, ok := podsFinished[podKey]; ok {
+			if accountingRatio := getShortLivedPodAccountingRatio(strategy, pod); accountingRatio < 1 {
+				podUsage = resutil.MultiplyResourceList(podUsage, accountingRatio)
+			}
+		}
+		podNUMAUsage := resutil.GetPodUnknownNUMAUsage(podUsage, zoneNum)
 		podsUnknownUsed = resutil.AddZoneResourceList(podsUnknownUsed, podNUMAUsage, zoneNum)
 	}
 	podsHPZoneUsed = resutil.AddZoneResourceList(podsHPZoneUsed, podsUnknownUsed, zoneNum)
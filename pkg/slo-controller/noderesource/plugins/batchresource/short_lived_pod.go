@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchresource
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+const (
+	// workloadLifetimeMinSamples is the minimum number of the finished pods observed before a workload is
+	// recognized as short-lived.
+	workloadLifetimeMinSamples = 3
+	// workloadLifetimeDecay is the weight of the history in the moving average of the pod lifetimes.
+	workloadLifetimeDecay = 0.7
+	// workloadLifetimeExpiration is the duration after which the lifetime history of the workload is dropped if no
+	// pod of it finishes.
+	workloadLifetimeExpiration = 24 * time.Hour
+)
+
+var lifetimeTracker = newPodLifetimeTracker()
+
+type workloadLifetime struct {
+	avgSeconds   float64
+	samples      int
+	lastFinished time.Time
+	// observedPods records the finish time of the counted pods, since the pods of a workload finish on different
+	// nodes and are observed in any order.
+	observedPods map[types.UID]time.Time
+}
+
+// podLifetimeTracker learns the lifetimes of the workloads from the start and finish time of their pods, so the
+// running pods of the workloads which historically finish in a short time can be recognized as short-lived.
+// The workload of a pod is identified by its controller owner since the pods of a workload share the same pattern,
+// and the pods of the Jobs created by a CronJob are identified by the CronJob.
+type podLifetimeTracker struct {
+	lock      sync.Mutex
+	workloads map[string]*workloadLifetime
+}
+
+func newPodLifetimeTracker() *podLifetimeTracker {
+	return &podLifetimeTracker{
+		workloads: map[string]*workloadLifetime{},
+	}
+}
+
+// Observe records the lifetimes of the finished pods in the pod list.
+// Each pod is counted once, and the pods finished before the history expiration are ignored.
+func (t *podLifetimeTracker) Observe(podList *corev1.PodList) {
+	type finishedPod struct {
+		workload   string
+		uid        types.UID
+		finishedAt time.Time
+		lifetime   time.Duration
+	}
+	now := Clock.Now()
+	var finishedPods []finishedPod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		finishedAt, lifetime, ok := getPodLifetime(pod)
+		if !ok || now.Sub(finishedAt) > workloadLifetimeExpiration {
+			continue
+		}
+		workload := getPodWorkloadKey(pod)
+		if workload == "" {
+			continue
+		}
+		finishedPods = append(finishedPods, finishedPod{workload: workload, uid: pod.UID, finishedAt: finishedAt, lifetime: lifetime})
+	}
+	sort.Slice(finishedPods, func(i, j int) bool {
+		return finishedPods[i].finishedAt.Before(finishedPods[j].finishedAt)
+	})
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	for _, p := range finishedPods {
+		w, ok := t.workloads[p.workload]
+		if !ok {
+			w = &workloadLifetime{observedPods: map[types.UID]time.Time{}}
+			t.workloads[p.workload] = w
+		}
+		if _, observed := w.observedPods[p.uid]; observed {
+			continue
+		}
+		w.observedPods[p.uid] = p.finishedAt
+		if w.samples <= 0 {
+			w.avgSeconds = p.lifetime.Seconds()
+		} else {
+			w.avgSeconds = w.avgSeconds*workloadLifetimeDecay + p.lifetime.Seconds()*(1-workloadLifetimeDecay)
+		}
+		w.samples++
+		if p.finishedAt.After(w.lastFinished) {
+			w.lastFinished = p.finishedAt
+		}
+	}
+
+	for workload, w := range t.workloads {
+		if now.Sub(w.lastFinished) > workloadLifetimeExpiration {
+			delete(t.workloads, workload)
+			continue
+		}
+		for uid, finishedAt := range w.observedPods {
+			if now.Sub(finishedAt) > workloadLifetimeExpiration {
+				delete(w.observedPods, uid)
+			}
+		}
+	}
+}
+
+// IsShortLived checks if the pod belongs to a workload whose average pod lifetime is shorter than the threshold.
+func (t *podLifetimeTracker) IsShortLived(pod *corev1.Pod, threshold time.Duration) bool {
+	workload := getPodWorkloadKey(pod)
+	if workload == "" {
+		return false
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	w, ok := t.workloads[workload]
+	if !ok || w.samples < workloadLifetimeMinSamples {
+		return false
+	}
+	return w.avgSeconds < threshold.Seconds()
+}
+
+// getShortLivedPodAccountingRatio returns the ratio of the pod resources to count in the batch resource calculation.
+func getShortLivedPodAccountingRatio(strategy *configuration.ColocationStrategy, pod *corev1.Pod) float64 {
+	if strategy == nil || strategy.ShortLivedPodThresholdSeconds == nil || *strategy.ShortLivedPodThresholdSeconds <= 0 {
+		return 1
+	}
+	if !lifetimeTracker.IsShortLived(pod, time.Duration(*strategy.ShortLivedPodThresholdSeconds)*time.Second) {
+		return 1
+	}
+	var percent int64
+	if strategy.ShortLivedPodAccountingPercent != nil {
+		percent = *strategy.ShortLivedPodAccountingPercent
+	}
+	klog.V(6).InfoS("batch resource dampens short-lived pod", "pod", util.GetPodKey(pod), "percent", percent)
+	return float64(percent) / 100
+}
+
+func getPodWorkloadKey(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return ""
+	}
+	if owner.Kind == "Job" {
+		if cronJob := getJobCronJobOwner(pod.Namespace, owner.Name); cronJob != nil {
+			owner = cronJob
+		}
+	}
+	return pod.Namespace + "/" + owner.Kind + "/" + owner.Name
+}
+
+// getJobCronJobOwner returns the CronJob owner of the Job, or nil if the Job is not created by a CronJob.
+func getJobCronJobOwner(namespace, name string) *metav1.OwnerReference {
+	if client == nil {
+		return nil
+	}
+	job := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+	}
+	if err := client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: name}, job); err != nil {
+		klog.V(6).InfoS("failed to get job for the pod lifetime", "job", namespace+"/"+name, "err", err)
+		return nil
+	}
+	if jobOwner := metav1.GetControllerOf(job); jobOwner != nil && jobOwner.Kind == "CronJob" {
+		return jobOwner
+	}
+	return nil
+}
+
+// getPodLifetime returns the finish time and the lifetime of a finished pod.
+func getPodLifetime(pod *corev1.Pod) (time.Time, time.Duration, bool) {
+	if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+		return time.Time{}, 0, false
+	}
+	if pod.Status.StartTime == nil {
+		return time.Time{}, 0, false
+	}
+	var finishedAt time.Time
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if terminated := containerStatus.State.Terminated; terminated != nil && terminated.FinishedAt.Time.After(finishedAt) {
+			finishedAt = terminated.FinishedAt.Time
+		}
+	}
+	if finishedAt.IsZero() || finishedAt.Before(pod.Status.StartTime.Time) {
+		return time.Time{}, 0, false
+	}
+	return finishedAt, finishedAt.Sub(pod.Status.StartTime.Time), true
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package batchresource
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+	"github.com/koordinator-sh/koordinator/pkg/util/testutil"
+)
+
+func makeJobPod(name, job string, phase corev1.PodPhase, startTime time.Time, lifetime time.Duration) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "test",
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				extension.LabelPodQoS: string(extension.QoSLS),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "batch/v1",
+					Kind:       "Job",
+					Name:       job,
+					Controller: pointer.Bool(true),
+				},
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node1",
+			Containers: []corev1.Container{
+				{
+					Resources: makeResourceReq("20", "20G"),
+				},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:     phase,
+			StartTime: &metav1.Time{Time: startTime},
+		},
+	}
+	if phase == corev1.PodSucceeded || phase == corev1.PodFailed {
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{
+			{
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{
+						FinishedAt: metav1.Time{Time: startTime.Add(lifetime)},
+					},
+				},
+			},
+		}
+	}
+	return pod
+}
+
+func Test_podLifetimeTracker(t *testing.T) {
+	now := time.Now()
+	oldClock := Clock
+	fakeClock := fakeclock.NewFakeClock(now)
+	Clock = fakeClock
+	defer func() {
+		Clock = oldClock
+	}()
+
+	tracker := newPodLifetimeTracker()
+	runningPod := makeJobPod("short-job-running", "short-job", corev1.PodRunning, now, 0)
+	longRunningPod := makeJobPod("long-job-running", "long-job", corev1.PodRunning, now, 0)
+	noOwnerPod := makeJobPod("no-owner", "short-job", corev1.PodSucceeded, now.Add(-time.Minute), 10*time.Second)
+	noOwnerPod.OwnerReferences = nil
+
+	// not enough samples
+	tracker.Observe(&corev1.PodList{Items: []corev1.Pod{
+		makeJobPod("short-job-0", "short-job", corev1.PodSucceeded, now.Add(-time.Minute), 10*time.Second),
+		makeJobPod("long-job-0", "long-job", corev1.PodSucceeded, now.Add(-time.Hour), 30*time.Minute),
+		noOwnerPod,
+		runningPod,
+	}})
+	assert.False(t, tracker.IsShortLived(&runningPod, time.Minute))
+	assert.False(t, tracker.IsShortLived(&noOwnerPod, time.Minute))
+
+	// the observed pods are not counted again
+	tracker.Observe(&corev1.PodList{Items: []corev1.Pod{
+		makeJobPod("short-job-0", "short-job", corev1.PodSucceeded, now.Add(-time.Minute), 10*time.Second),
+		makeJobPod("short-job-1", "short-job", corev1.PodFailed, now.Add(-50*time.Second), 20*time.Second),
+	}})
+	assert.False(t, tracker.IsShortLived(&runningPod, time.Minute))
+	assert.Equal(t, 2, tracker.workloads["test/Job/short-job"].samples)
+
+	tracker.Observe(&corev1.PodList{Items: []corev1.Pod{
+		makeJobPod("short-job-2", "short-job", corev1.PodSucceeded, now.Add(-40*time.Second), 30*time.Second),
+		makeJobPod("long-job-1", "long-job", corev1.PodSucceeded, now.Add(-30*time.Minute), 20*time.Minute),
+		makeJobPod("long-job-2", "long-job", corev1.PodSucceeded, now.Add(-20*time.Minute), 15*time.Minute),
+	}})
+	assert.True(t, tracker.IsShortLived(&runningPod, time.Minute))
+	assert.False(t, tracker.IsShortLived(&runningPod, 10*time.Second))
+	assert.False(t, tracker.IsShortLived(&longRunningPod, time.Minute))
+	assert.True(t, tracker.IsShortLived(&longRunningPod, time.Hour))
+
+	// the pod finished earlier on another node is counted
+	tracker.Observe(&corev1.PodList{Items: []corev1.Pod{
+		makeJobPod("short-job-3", "short-job", corev1.PodSucceeded, now.Add(-2*time.Minute), 10*time.Second),
+	}})
+	assert.Equal(t, 4, tracker.workloads["test/Job/short-job"].samples)
+
+	// expire the history
+	fakeClock.SetTime(now.Add(workloadLifetimeExpiration + time.Minute))
+	tracker.Observe(&corev1.PodList{})
+	assert.False(t, tracker.IsShortLived(&runningPod, time.Minute))
+	assert.Equal(t, 0, len(tracker.workloads))
+}
+
+func Test_podLifetimeTrackerWithCronJob(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(testScheme))
+	var jobs []ctrlclient.Object
+	for i := 0; i < workloadLifetimeMinSamples+1; i++ {
+		jobs = append(jobs, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("cron-job-%d", i),
+				Namespace: "test",
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: "batch/v1",
+						Kind:       "CronJob",
+						Name:       "cron",
+						Controller: pointer.Bool(true),
+					},
+				},
+			},
+		})
+	}
+	oldClient := client
+	client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(jobs...).Build()
+	defer func() {
+		client = oldClient
+	}()
+
+	now := time.Now()
+	tracker := newPodLifetimeTracker()
+	podList := &corev1.PodList{}
+	for i := 0; i < workloadLifetimeMinSamples; i++ {
+		podList.Items = append(podList.Items, makeJobPod(fmt.Sprintf("cron-job-%d-pod", i), fmt.Sprintf("cron-job-%d", i),
+			corev1.PodSucceeded, now.Add(-time.Duration(5-i)*time.Minute), 10*time.Second))
+	}
+	tracker.Observe(podList)
+	runningPod := makeJobPod("cron-job-3-pod", "cron-job-3", corev1.PodRunning, now, 0)
+	assert.True(t, tracker.IsShortLived(&runningPod, time.Minute))
+	assert.Equal(t, workloadLifetimeMinSamples, tracker.workloads["test/CronJob/cron"].samples)
+}
+
+func TestPluginCalculateWithShortLivedPods(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(testScheme)
+	assert.NoError(t, err)
+	err = slov1alpha1.AddToScheme(testScheme)
+	assert.NoError(t, err)
+
+	now := time.Now()
+	// podA is a running pod of the short job, and podC is a finished pod of the short job whose metric is still reported.
+	// podB is not in the pod list, so its metric is counted as dangling.
+	podList := &corev1.PodList{
+		Items: []corev1.Pod{
+			makeJobPod("podA", "short-job", corev1.PodRunning, now, 0),
+			makeJobPod("podC", "short-job", corev1.PodSucceeded, now.Add(-30*time.Second), 20*time.Second),
+		},
+	}
+	for i := 0; i < 3; i++ {
+		podList.Items = append(podList.Items, makeJobPod(fmt.Sprintf("short-job-%d", i), "short-job",
+			corev1.PodSucceeded, now.Add(-time.Duration(5-i)*time.Minute), 10*time.Second))
+	}
+	tests := []struct {
+		name     string
+		strategy *configuration.ColocationStrategy
+		want     []framework.ResourceItem
+	}{
+		{
+			name: "short-lived pods are accounted if disabled",
+			strategy: &configuration.ColocationStrategy{
+				Enable:                        pointer.Bool(true),
+				CPUReclaimThresholdPercent:    pointer.Int64(65),
+				MemoryReclaimThresholdPercent: pointer.Int64(65),
+				DegradeTimeMinutes:            pointer.Int64(15),
+				UpdateTimeThresholdSeconds:    pointer.Int64(300),
+				ResourceDiffThreshold:         pointer.Float64(0.1),
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(15000, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:15000 = nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPUsed:43000",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(23, 9),
+					Message:  "batchAllocatable[Mem(GB)]:23 = nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPUsed:43",
+				},
+			},
+		},
+		{
+			name: "exclude short-lived pods",
+			strategy: &configuration.ColocationStrategy{
+				Enable:                        pointer.Bool(true),
+				CPUReclaimThresholdPercent:    pointer.Int64(65),
+				MemoryReclaimThresholdPercent: pointer.Int64(65),
+				DegradeTimeMinutes:            pointer.Int64(15),
+				UpdateTimeThresholdSeconds:    pointer.Int64(300),
+				ResourceDiffThreshold:         pointer.Float64(0.1),
+				ShortLivedPodThresholdSeconds: pointer.Int64(60),
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(48000, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:48000 = nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPUsed:10000",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(56, 9),
+					Message:  "batchAllocatable[Mem(GB)]:56 = nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPUsed:10",
+				},
+			},
+		},
+		{
+			name: "dampen short-lived pods",
+			strategy: &configuration.ColocationStrategy{
+				Enable:                         pointer.Bool(true),
+				CPUReclaimThresholdPercent:     pointer.Int64(65),
+				MemoryReclaimThresholdPercent:  pointer.Int64(65),
+				DegradeTimeMinutes:             pointer.Int64(15),
+				UpdateTimeThresholdSeconds:     pointer.Int64(300),
+				ResourceDiffThreshold:          pointer.Float64(0.1),
+				ShortLivedPodThresholdSeconds:  pointer.Int64(60),
+				ShortLivedPodAccountingPercent: pointer.Int64(50),
+			},
+			want: []framework.ResourceItem{
+				{
+					Name:     extension.BatchCPU,
+					Quantity: resource.NewQuantity(31500, resource.DecimalSI),
+					Message:  "batchAllocatable[CPU(Milli-Core)]:31500 = nodeCapacity:100000 - nodeSafetyMargin:35000 - systemUsageOrNodeReserved:7000 - podHPUsed:26500",
+				},
+				{
+					Name:     extension.BatchMemory,
+					Quantity: resource.NewScaledQuantity(39500, 6),
+					Message:  "batchAllocatable[Mem(GB)]:40 = nodeCapacity:120 - nodeSafetyMargin:42 - systemUsage:12 - podHPUsed:27",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer testPluginCleanup()
+			oldTracker, oldClock := lifetimeTracker, Clock
+			lifetimeTracker, Clock = newPodLifetimeTracker(), clock.RealClock{}
+			defer func() {
+				lifetimeTracker, Clock = oldTracker, oldClock
+			}()
+			p := &Plugin{}
+			err = p.Setup(&framework.Option{
+				Scheme:   testScheme,
+				Client:   fake.NewClientBuilder().WithScheme(testScheme).Build(),
+				Builder:  builder.ControllerManagedBy(&testutil.FakeManager{}),
+				Recorder: &record.FakeRecorder{},
+			})
+			assert.NoError(t, err)
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-node1",
+				},
+				Status: makeNodeStat("100", "120G"),
+			}
+			got, gotErr := p.Calculate(tt.strategy, node, podList, getTestResourceMetrics())
+			assert.NoError(t, gotErr)
+			testingCorrectResourceItems(t, tt.want, got)
+		})
+	}
+}
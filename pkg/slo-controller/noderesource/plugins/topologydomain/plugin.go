@@ -0,0 +1,127 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologydomain
+
+import (
+	"context"
+	"fmt"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+)
+
+const PluginName = "TopologyDomain"
+
+// domainLabels are the node labels of the topology domains published by the plugin.
+var domainLabels = []string{extension.LabelNodeCacheDomain, extension.LabelNodeNUMADomain}
+
+var client ctrlclient.Client
+
+// Plugin publishes the topology domains reported by the koordlet in the NRT as the node labels, so the pods can be
+// spread over the nodes of the same cache domain or NUMA domain.
+type Plugin struct{}
+
+func (p *Plugin) Name() string {
+	return PluginName
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch
+
+func (p *Plugin) Setup(opt *framework.Option) error {
+	client = opt.Client
+
+	if err := topologyv1alpha1.AddToScheme(opt.Scheme); err != nil {
+		return fmt.Errorf("failed to add scheme for NodeResourceTopology, err: %w", err)
+	}
+	if err := topologyv1alpha1.AddToScheme(clientgoscheme.Scheme); err != nil {
+		return fmt.Errorf("failed to add client go scheme for NodeResourceTopology, err: %w", err)
+	}
+	opt.Builder = opt.Builder.Watches(&topologyv1alpha1.NodeResourceTopology{}, &nrtHandler{})
+
+	return nil
+}
+
+// NeedSyncMeta checks if the node labels of the topology domains to update are different from the current.
+func (p *Plugin) NeedSyncMeta(_ *configuration.ColocationStrategy, oldNode, newNode *corev1.Node) (bool, string) {
+	for _, label := range domainLabels {
+		if oldNode.Labels[label] != newNode.Labels[label] {
+			return true, fmt.Sprintf("label %s changed", label)
+		}
+	}
+	return false, "topology domains unchanged"
+}
+
+// Prepare sets the node labels of the topology domains, and removes the labels of the unknown domains.
+func (p *Plugin) Prepare(_ *configuration.ColocationStrategy, node *corev1.Node, nr *framework.NodeResource) error {
+	for _, label := range domainLabels {
+		value, ok := nr.Labels[label]
+		if !ok {
+			continue
+		}
+		if value == "" {
+			delete(node.Labels, label)
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[label] = value
+	}
+	klog.V(6).Infof("prepare node topology domains to set, node %s, labels %v", node.Name, nr.Labels)
+	return nil
+}
+
+func (p *Plugin) Reset(node *corev1.Node, message string) []framework.ResourceItem {
+	return nil
+}
+
+// Calculate retrieves the topology domains from the NRT. The node labels are kept unchanged if the NRT or the
+// topology domains are missing, e.g. the koordlet is not upgraded yet.
+func (p *Plugin) Calculate(_ *configuration.ColocationStrategy, node *corev1.Node, _ *corev1.PodList, _ *framework.ResourceMetrics) ([]framework.ResourceItem, error) {
+	nrt := &topologyv1alpha1.NodeResourceTopology{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: node.Name}, nrt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NodeResourceTopology in topology domain calculation, err: %w", err)
+	}
+	domains, err := extension.GetNodeTopologyDomains(nrt.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse topology domains in topology domain calculation, err: %w", err)
+	}
+	if domains == nil {
+		klog.V(6).Infof("skip calculating topology domains since it is not reported, node %s", node.Name)
+		return nil, nil
+	}
+
+	return []framework.ResourceItem{
+		{
+			Name: PluginName,
+			Labels: map[string]string{
+				extension.LabelNodeCacheDomain: domains.CacheDomain,
+				extension.LabelNodeNUMADomain:  domains.NUMADomain,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package topologydomain
+
+import (
+	"testing"
+
+	topov1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/framework"
+	"github.com/koordinator-sh/koordinator/pkg/util/testutil"
+)
+
+func TestPlugin(t *testing.T) {
+	defer func() { client = nil }()
+	p := &Plugin{}
+	assert.Equal(t, PluginName, p.Name())
+	testScheme := runtime.NewScheme()
+	testOpt := &framework.Option{
+		Scheme:   testScheme,
+		Client:   fake.NewClientBuilder().WithScheme(testScheme).Build(),
+		Builder:  builder.ControllerManagedBy(&testutil.FakeManager{}),
+		Recorder: &record.FakeRecorder{},
+	}
+	assert.NoError(t, p.Setup(testOpt))
+	assert.Nil(t, p.Reset(nil, ""))
+}
+
+func TestPluginCalculateAndPrepare(t *testing.T) {
+	defer func() { client = nil }()
+	testScheme := runtime.NewScheme()
+	assert.NoError(t, topov1alpha1.AddToScheme(testScheme))
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{extension.LabelNodeCacheDomain: "stale"},
+		},
+	}
+	nrt := &topov1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				extension.AnnotationNodeTopologyDomains: `{"numaDomain":"2numa-48cpus"}`,
+			},
+		},
+	}
+	p := &Plugin{}
+
+	// the NRT is missing
+	client = fake.NewClientBuilder().WithScheme(testScheme).Build()
+	got, err := p.Calculate(nil, node, nil, nil)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+
+	// the topology domains are not reported
+	client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(&topov1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+	}).Build()
+	got, err = p.Calculate(nil, node, nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+	newNode := node.DeepCopy()
+	assert.NoError(t, p.Prepare(nil, newNode, framework.NewNodeResource(got...)))
+	assert.Equal(t, node.Labels, newNode.Labels)
+
+	// the unknown cache domain is removed
+	client = fake.NewClientBuilder().WithScheme(testScheme).WithObjects(nrt).Build()
+	got, err = p.Calculate(nil, node, nil, nil)
+	assert.NoError(t, err)
+	newNode = node.DeepCopy()
+	assert.NoError(t, p.Prepare(nil, newNode, framework.NewNodeResource(got...)))
+	assert.Equal(t, map[string]string{extension.LabelNodeNUMADomain: "2numa-48cpus"}, newNode.Labels)
+
+	needSync, _ := p.NeedSyncMeta(nil, node, newNode)
+	assert.True(t, needSync)
+	needSync, _ = p.NeedSyncMeta(nil, newNode, newNode.DeepCopy())
+	assert.False(t, needSync)
+}
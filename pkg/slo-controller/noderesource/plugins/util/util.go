@@ -301,6 +301,14 @@ func DivideResourceList(rl corev1.ResourceList, divisor float64) corev1.Resource
 	return divided
 }
 
+func MultiplyResourceList(rl corev1.ResourceList, ratio float64) corev1.ResourceList {
+	multiplied := corev1.ResourceList{}
+	for resourceName, q := range rl {
+		multiplied[resourceName] = util.MultiplyMilliQuant(q, ratio)
+	}
+	return multiplied
+}
+
 func zoneResourceListHandler(a, b []corev1.ResourceList, zoneNum int,
 	handleFn func(a corev1.ResourceList, b corev1.ResourceList) corev1.ResourceList) []corev1.ResourceList {
 	// assert len(a) == len(b) == zoneNum
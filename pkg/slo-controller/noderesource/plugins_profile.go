@@ -24,6 +24,7 @@ import (
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/midresource"
 	rdmadeviceresource "github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/rdmadevicereource"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/resourceamplification"
+	"github.com/koordinator-sh/koordinator/pkg/slo-controller/noderesource/plugins/topologydomain"
 )
 
 // NOTE: functions in this file can be overwritten for extension
@@ -36,6 +37,7 @@ func init() {
 	addPluginOption(&resourceamplification.Plugin{}, true)
 	addPluginOption(&gpudeviceresource.Plugin{}, true)
 	addPluginOption(&rdmadeviceresource.Plugin{}, true)
+	addPluginOption(&topologydomain.Plugin{}, true)
 }
 
 func addPlugins(filter framework.FilterFn) {
@@ -56,6 +58,7 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&topologydomain.Plugin{},
 	}
 	// NodePreUpdatePlugin implements node resource pre-updating.
 	nodePreUpdatePlugins = []framework.NodePreUpdatePlugin{
@@ -69,6 +72,7 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&topologydomain.Plugin{},
 	}
 	// NodeSyncPlugin implements the check of resource updating.
 	nodeStatusCheckPlugins = []framework.NodeStatusCheckPlugin{
@@ -82,6 +86,7 @@ var (
 		&cpunormalization.Plugin{},
 		&resourceamplification.Plugin{},
 		&gpudeviceresource.Plugin{},
+		&topologydomain.Plugin{},
 	}
 	// ResourceCalculatePlugin implements resource counting and overcommitment algorithms.
 	resourceCalculatePlugins = []framework.ResourceCalculatePlugin{
@@ -91,5 +96,6 @@ var (
 		&batchresource.Plugin{},
 		&gpudeviceresource.Plugin{},
 		&rdmadeviceresource.Plugin{},
+		&topologydomain.Plugin{},
 	}
 )
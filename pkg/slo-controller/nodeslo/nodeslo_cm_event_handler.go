@@ -45,12 +45,13 @@ type SLOCfgCache interface {
 }
 
 type SLOCfg struct {
-	ThresholdCfgMerged   configuration.ResourceThresholdCfg `json:"thresholdCfgMerged,omitempty"`
-	ResourceQOSCfgMerged configuration.ResourceQOSCfg       `json:"resourceQOSCfgMerged,omitempty"`
-	CPUBurstCfgMerged    configuration.CPUBurstCfg          `json:"cpuBurstCfgMerged,omitempty"`
-	SystemCfgMerged      configuration.SystemCfg            `json:"systemCfgMerged,omitempty"`
-	HostAppCfgMerged     configuration.HostApplicationCfg   `json:"hostAppCfgMerged,omitempty"`
-	ExtensionCfgMerged   configuration.ExtensionCfgMap      `json:"extensionCfgMerged,omitempty"` // for third-party extension
+	ThresholdCfgMerged       configuration.ResourceThresholdCfg `json:"thresholdCfgMerged,omitempty"`
+	ResourceQOSCfgMerged     configuration.ResourceQOSCfg       `json:"resourceQOSCfgMerged,omitempty"`
+	CPUBurstCfgMerged        configuration.CPUBurstCfg          `json:"cpuBurstCfgMerged,omitempty"`
+	SystemCfgMerged          configuration.SystemCfg            `json:"systemCfgMerged,omitempty"`
+	HostAppCfgMerged         configuration.HostApplicationCfg   `json:"hostAppCfgMerged,omitempty"`
+	CgroupExclusionCfgMerged configuration.CgroupExclusionCfg   `json:"cgroupExclusionCfgMerged,omitempty"`
+	ExtensionCfgMerged       configuration.ExtensionCfgMap      `json:"extensionCfgMerged,omitempty"` // for third-party extension
 }
 
 func (in *SLOCfg) DeepCopy() *SLOCfg {
@@ -61,6 +62,7 @@ func (in *SLOCfg) DeepCopy() *SLOCfg {
 	out.SystemCfgMerged = *in.SystemCfgMerged.DeepCopy()
 	out.ExtensionCfgMerged = *in.ExtensionCfgMerged.DeepCopy()
 	out.HostAppCfgMerged = *in.HostAppCfgMerged.DeepCopy()
+	out.CgroupExclusionCfgMerged = *in.CgroupExclusionCfgMerged.DeepCopy()
 	return out
 }
 
@@ -73,12 +75,13 @@ type sLOCfgCache struct {
 
 func DefaultSLOCfg() SLOCfg {
 	return SLOCfg{
-		ThresholdCfgMerged:   configuration.ResourceThresholdCfg{ClusterStrategy: sloconfig.DefaultResourceThresholdStrategy()},
-		ResourceQOSCfgMerged: configuration.ResourceQOSCfg{ClusterStrategy: &slov1alpha1.ResourceQOSStrategy{}},
-		CPUBurstCfgMerged:    configuration.CPUBurstCfg{ClusterStrategy: sloconfig.DefaultCPUBurstStrategy()},
-		SystemCfgMerged:      configuration.SystemCfg{ClusterStrategy: sloconfig.DefaultSystemStrategy()},
-		HostAppCfgMerged:     configuration.HostApplicationCfg{},
-		ExtensionCfgMerged:   *getDefaultExtensionCfg(),
+		ThresholdCfgMerged:       configuration.ResourceThresholdCfg{ClusterStrategy: sloconfig.DefaultResourceThresholdStrategy()},
+		ResourceQOSCfgMerged:     configuration.ResourceQOSCfg{ClusterStrategy: &slov1alpha1.ResourceQOSStrategy{}},
+		CPUBurstCfgMerged:        configuration.CPUBurstCfg{ClusterStrategy: sloconfig.DefaultCPUBurstStrategy()},
+		SystemCfgMerged:          configuration.SystemCfg{ClusterStrategy: sloconfig.DefaultSystemStrategy()},
+		HostAppCfgMerged:         configuration.HostApplicationCfg{},
+		CgroupExclusionCfgMerged: configuration.CgroupExclusionCfg{},
+		ExtensionCfgMerged:       *getDefaultExtensionCfg(),
 	}
 }
 
@@ -151,6 +154,11 @@ func (p *SLOCfgHandlerForConfigMapEvent) syncConfig(configMap *corev1.ConfigMap)
 		klog.V(5).Infof("failed to get HostApplicationCfg, err: %s", err)
 		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal HostApplicationCfg, err: %s", err)
 	}
+	newSLOCfg.CgroupExclusionCfgMerged, err = calculateCgroupExclusionConfigMerged(oldSLOCfgCopy.CgroupExclusionCfgMerged, configMap)
+	if err != nil {
+		klog.V(5).Infof("failed to get CgroupExclusionCfg, err: %s", err)
+		p.recorder.Eventf(configMap, "Warning", config.ReasonSLOConfigUnmarshalFailed, "failed to unmarshal CgroupExclusionCfg, err: %s", err)
+	}
 	newSLOCfg.ExtensionCfgMerged = calculateExtensionsCfgMerged(oldSLOCfgCopy.ExtensionCfgMerged, configMap, p.recorder)
 	return p.updateCacheIfChanged(newSLOCfg)
 }
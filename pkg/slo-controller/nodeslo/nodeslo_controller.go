@@ -32,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/metrics"
 	"github.com/koordinator-sh/koordinator/pkg/slo-controller/nodemetric"
@@ -114,6 +115,14 @@ func (r *NodeSLOReconciler) getNodeSLOSpec(node *corev1.Node, oldSpec *slov1alph
 		metrics.RecordNodeSLOSpecParseCount(true, "getHostApplicationConfig")
 	}
 
+	nodeSLOSpec.CgroupExclusionRules, err = getCgroupExclusionConfig(node, &sloCfg.CgroupExclusionCfgMerged)
+	if err != nil {
+		metrics.RecordNodeSLOSpecParseCount(false, "getCgroupExclusionConfig")
+		klog.Warningf("getCgroupExclusionConfig(): failed to get cgroupExclusionConfig spec for node %s,error: %v", node.Name, err)
+	} else {
+		metrics.RecordNodeSLOSpecParseCount(true, "getCgroupExclusionConfig")
+	}
+
 	nodeSLOSpec.Extensions = getExtensionsConfigSpec(node, oldSpec, &sloCfg.ExtensionCfgMerged)
 
 	return nodeSLOSpec, nil
@@ -165,6 +174,11 @@ func (r *NodeSLOReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 		nodeSLOExist = false
 	}
+	if nodeSLOExist && apiext.IsNodeSLOSource(nodeSLO.Labels) {
+		// the NodeSLO sources are managed by the users and composed by koordlet
+		klog.V(5).Infof("skip reconciling nodeSLO %v since it is a NodeSLO source", nodeSLOName)
+		return ctrl.Result{}, nil
+	}
 
 	// NodeSLO lifecycle management
 	if !nodeExist && !nodeSLOExist {
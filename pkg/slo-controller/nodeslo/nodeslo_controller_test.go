@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/koordinator-sh/koordinator/apis/configuration"
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
 )
@@ -402,4 +403,22 @@ func TestNodeSLOReconciler_Reconcile(t *testing.T) {
 	if !errors.IsNotFound(err) {
 		t.Errorf("the testing NodeSLO should not exist after the Node is deleted, err: %s", err)
 	}
+
+	// keep the NodeSLO sources which have no corresponding node
+	testingSourceNodeSLO := &slov1alpha1.NodeSLO{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-cluster-source",
+			Labels: map[string]string{
+				apiext.LabelNodeSLOSource: string(apiext.NodeSLOSourceCluster),
+			},
+		},
+	}
+	err = r.Client.Create(context.TODO(), testingSourceNodeSLO)
+	assert.NoError(t, err)
+	sourceReq := ctrl.Request{NamespacedName: types.NamespacedName{Name: testingSourceNodeSLO.Name}}
+	_, err = r.Reconcile(context.TODO(), sourceReq)
+	assert.NoError(t, err)
+	nodeSLO = &slov1alpha1.NodeSLO{}
+	err = r.Client.Get(context.TODO(), sourceReq.NamespacedName, nodeSLO)
+	assert.NoError(t, err)
 }
@@ -139,6 +139,32 @@ func getHostApplicationConfig(node *corev1.Node, cfg *configuration.HostApplicat
 	return out, nil
 }
 
+func getCgroupExclusionConfig(node *corev1.Node, cfg *configuration.CgroupExclusionCfg) ([]slov1alpha1.CgroupExclusionRule, error) {
+	nodeLabels := labels.Set(node.Labels)
+	for _, nodeCfg := range cfg.NodeConfigs {
+		selector, err := metav1.LabelSelectorAsSelector(nodeCfg.NodeSelector)
+		if err != nil {
+			klog.Errorf("failed to parse node selector %v for CgroupExclusionCfg, error: %v", nodeCfg.NodeSelector.String(), err)
+			continue
+		}
+		if selector.Matches(nodeLabels) {
+			return copyCgroupExclusionRules(nodeCfg.Rules), nil
+		}
+	}
+	return copyCgroupExclusionRules(cfg.Rules), nil
+}
+
+func copyCgroupExclusionRules(rules []slov1alpha1.CgroupExclusionRule) []slov1alpha1.CgroupExclusionRule {
+	if len(rules) <= 0 {
+		return nil
+	}
+	out := make([]slov1alpha1.CgroupExclusionRule, len(rules))
+	for i := range rules {
+		rules[i].DeepCopyInto(&out[i])
+	}
+	return out
+}
+
 func calculateResourceThresholdCfgMerged(oldCfg configuration.ResourceThresholdCfg, configMap *corev1.ConfigMap) (configuration.ResourceThresholdCfg, error) {
 	cfgStr, ok := configMap.Data[configuration.ResourceThresholdConfigKey]
 	if !ok {
@@ -294,3 +320,17 @@ func calculateHostAppConfigMerged(oldCfg configuration.HostApplicationCfg, confi
 	}
 	return mergedCfg, nil
 }
+
+func calculateCgroupExclusionConfigMerged(oldCfg configuration.CgroupExclusionCfg, configMap *corev1.ConfigMap) (configuration.CgroupExclusionCfg, error) {
+	cfgStr, ok := configMap.Data[configuration.CgroupExclusionConfigKey]
+	if !ok {
+		return configuration.CgroupExclusionCfg{}, nil
+	}
+
+	mergedCfg := configuration.CgroupExclusionCfg{}
+	if err := json.Unmarshal([]byte(cfgStr), &mergedCfg); err != nil {
+		klog.Warningf("failed to unmarshal config %s, error: %v", configuration.CgroupExclusionConfigKey, err)
+		return oldCfg, err
+	}
+	return mergedCfg, nil
+}
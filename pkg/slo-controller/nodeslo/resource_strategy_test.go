@@ -1323,3 +1323,75 @@ func Test_calculateHostAppConfigMerged(t *testing.T) {
 		})
 	}
 }
+
+func Test_getCgroupExclusionConfig(t *testing.T) {
+	testCfg := &configuration.CgroupExclusionCfg{
+		Rules: []slov1alpha1.CgroupExclusionRule{
+			{
+				Name:       "exclude-kube-system",
+				Namespaces: []string{"kube-system"},
+			},
+		},
+		NodeConfigs: []configuration.NodeCgroupExclusionCfg{
+			{
+				NodeCfgProfile: configuration.NodeCfgProfile{
+					NodeSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"test-node-key": "test-node-value-A",
+						},
+					},
+				},
+				Rules: []slov1alpha1.CgroupExclusionRule{
+					{
+						Name:            "exclude-agent",
+						CgroupPathRegex: ".*agent.*",
+					},
+				},
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		node *corev1.Node
+		cfg  *configuration.CgroupExclusionCfg
+		want []slov1alpha1.CgroupExclusionRule
+	}{
+		{
+			name: "empty config",
+			node: &corev1.Node{},
+			cfg:  &configuration.CgroupExclusionCfg{},
+			want: nil,
+		},
+		{
+			name: "use cluster rules",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"test-node-key": "test-node-value-B",
+					},
+				},
+			},
+			cfg:  testCfg,
+			want: testCfg.Rules,
+		},
+		{
+			name: "use node rules",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"test-node-key": "test-node-value-A",
+					},
+				},
+			},
+			cfg:  testCfg,
+			want: testCfg.NodeConfigs[0].Rules,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getCgroupExclusionConfig(tt.node, tt.cfg)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeusageforecast
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Forecaster forecasts the node usage in a short horizon by the least squares linear regression over the recent
+// usage samples of the NodeMetrics, which tells whether a load spike is a rising trend or a transient one.
+type Forecaster struct {
+	lock          sync.Mutex
+	horizon       time.Duration
+	historyWindow time.Duration
+	minSamples    int
+	histories     map[string][]usageSample
+}
+
+type usageSample struct {
+	timestamp time.Time
+	// usage is the milli value of the resources
+	usage map[corev1.ResourceName]int64
+}
+
+func NewForecaster(horizon, historyWindow time.Duration, minSamples int) *Forecaster {
+	return &Forecaster{
+		horizon:       horizon,
+		historyWindow: historyWindow,
+		minSamples:    minSamples,
+		histories:     map[string][]usageSample{},
+	}
+}
+
+// Record saves the usage sample of the key, the sample is ignored if it is not newer than the last one,
+// e.g. the NodeMetric is not updated since the last reconciliation.
+func (f *Forecaster) Record(key string, timestamp time.Time, usage corev1.ResourceList) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	history := f.histories[key]
+	if len(history) > 0 && !timestamp.After(history[len(history)-1].timestamp) {
+		return
+	}
+	sample := usageSample{timestamp: timestamp, usage: make(map[corev1.ResourceName]int64, len(usage))}
+	for resourceName, quantity := range usage {
+		sample.usage[resourceName] = quantity.MilliValue()
+	}
+	history = append(history, sample)
+	f.histories[key] = trimSamples(history, timestamp.Add(-f.historyWindow))
+}
+
+// Delete removes the history of the key.
+func (f *Forecaster) Delete(key string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.histories, key)
+}
+
+// GC removes the histories which have no sample in the history window.
+func (f *Forecaster) GC(now time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	for key, history := range f.histories {
+		if history = trimSamples(history, now.Add(-f.historyWindow)); len(history) == 0 {
+			delete(f.histories, key)
+		} else {
+			f.histories[key] = history
+		}
+	}
+}
+
+// Forecast returns the usage of the key after the horizon since the given time.
+// It returns false if the samples are insufficient.
+func (f *Forecaster) Forecast(key string, now time.Time) (corev1.ResourceList, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	history := trimSamples(f.histories[key], now.Add(-f.historyWindow))
+	if len(history) < f.minSamples || len(history) <= 0 {
+		return nil, false
+	}
+
+	target := now.Add(f.horizon).Sub(history[0].timestamp).Seconds()
+	forecasted := corev1.ResourceList{}
+	for resourceName := range history[len(history)-1].usage {
+		var n, sumX, sumY, sumXY, sumXX float64
+		for _, sample := range history {
+			y, ok := sample.usage[resourceName]
+			if !ok {
+				continue
+			}
+			x := sample.timestamp.Sub(history[0].timestamp).Seconds()
+			n++
+			sumX += x
+			sumY += float64(y)
+			sumXY += x * float64(y)
+			sumXX += x * x
+		}
+		if n < float64(f.minSamples) {
+			continue
+		}
+		value := sumY / n
+		if denominator := n*sumXX - sumX*sumX; denominator != 0 {
+			slope := (n*sumXY - sumX*sumY) / denominator
+			value = (sumY-slope*sumX)/n + slope*target
+		}
+		if value < 0 {
+			value = 0
+		}
+		forecasted[resourceName] = *resource.NewMilliQuantity(int64(value), resource.DecimalSI)
+	}
+	return forecasted, true
+}
+
+// Horizon returns how far ahead the usage is forecasted.
+func (f *Forecaster) Horizon() time.Duration {
+	return f.horizon
+}
+
+// trimSamples drops the samples before the start time, the samples are sorted by the timestamp.
+func trimSamples(history []usageSample, start time.Time) []usageSample {
+	i := 0
+	for i < len(history) && history[i].timestamp.Before(start) {
+		i++
+	}
+	return history[i:]
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeusageforecast
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func cpuUsage(milliCPU int64) corev1.ResourceList {
+	return corev1.ResourceList{
+		corev1.ResourceCPU: *resource.NewMilliQuantity(milliCPU, resource.DecimalSI),
+	}
+}
+
+func TestForecaster(t *testing.T) {
+	now := time.Now()
+	forecaster := NewForecaster(5*time.Minute, 15*time.Minute, 3)
+	forecaster.Record("node-1", now.Add(-2*time.Minute), cpuUsage(4000))
+	forecaster.Record("node-1", now.Add(-1*time.Minute), cpuUsage(5000))
+	_, ok := forecaster.Forecast("node-1", now)
+	assert.False(t, ok, "insufficient samples")
+
+	// the sample which is not newer than the last one is ignored
+	forecaster.Record("node-1", now.Add(-1*time.Minute), cpuUsage(9000))
+	_, ok = forecaster.Forecast("node-1", now)
+	assert.False(t, ok, "insufficient samples")
+
+	forecaster.Record("node-1", now, cpuUsage(6000))
+	forecasted, ok := forecaster.Forecast("node-1", now)
+	assert.True(t, ok)
+	got := forecasted[corev1.ResourceCPU]
+	assert.Equal(t, int64(11000), got.MilliValue())
+
+	// falling trend is clamped at zero
+	forecaster.Record("node-2", now.Add(-2*time.Minute), cpuUsage(6000))
+	forecaster.Record("node-2", now.Add(-1*time.Minute), cpuUsage(3000))
+	forecaster.Record("node-2", now, cpuUsage(0))
+	forecasted, ok = forecaster.Forecast("node-2", now)
+	assert.True(t, ok)
+	got = forecasted[corev1.ResourceCPU]
+	assert.Equal(t, int64(0), got.MilliValue())
+
+	forecaster.Delete("node-2")
+	assert.Len(t, forecaster.histories, 1)
+
+	// the samples out of the history window are dropped
+	forecaster.GC(now.Add(16 * time.Minute))
+	assert.Len(t, forecaster.histories, 0)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeusageforecast
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "nodeusageforecast"
+
+var (
+	// Horizon indicates how far ahead the node usage is forecasted.
+	Horizon = 5 * time.Minute
+	// HistoryWindow indicates the time window of the recent NodeMetric samples used to forecast.
+	HistoryWindow = 15 * time.Minute
+	// MinSamples indicates the minimum number of the samples to forecast.
+	MinSamples = 3
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.DurationVar(&Horizon, "node-usage-forecast-horizon", Horizon, "How far ahead the node usage is forecasted from the recent NodeMetrics.")
+	pflag.DurationVar(&HistoryWindow, "node-usage-forecast-history-window", HistoryWindow, "The time window of the recent NodeMetric samples used to forecast the node usage.")
+	pflag.IntVar(&MinSamples, "node-usage-forecast-min-samples", MinSamples, "The minimum number of the NodeMetric samples to forecast the node usage.")
+}
+
+// NodeMetricReconciler forecasts the node usages from the recent NodeMetrics, and annotates the NodeMetrics with the
+// forecasted usages, so the koord-descheduler can tell whether a load spike is expected to self-resolve.
+type NodeMetricReconciler struct {
+	client.Client
+	nodeForecaster *Forecaster
+	prodForecaster *Forecaster
+
+	lock       sync.Mutex
+	lastGCTime time.Time
+}
+
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodemetrics,verbs=get;list;watch;patch
+
+func (r *NodeMetricReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	nodeMetric := &slov1alpha1.NodeMetric{}
+	if err := r.Client.Get(ctx, req.NamespacedName, nodeMetric); err != nil {
+		if errors.IsNotFound(err) {
+			r.nodeForecaster.Delete(req.Name)
+			r.prodForecaster.Delete(req.Name)
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get nodeMetric %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	if nodeMetric.Status.UpdateTime == nil || nodeMetric.Status.NodeMetric == nil {
+		return ctrl.Result{}, nil
+	}
+
+	now := time.Now()
+	r.lock.Lock()
+	if now.Sub(r.lastGCTime) > time.Hour {
+		r.nodeForecaster.GC(now)
+		r.prodForecaster.GC(now)
+		r.lastGCTime = now
+	}
+	r.lock.Unlock()
+
+	// the samples of the report which has been recorded are ignored
+	updateTime := nodeMetric.Status.UpdateTime.Time
+	r.nodeForecaster.Record(req.Name, updateTime, nodeMetric.Status.NodeMetric.NodeUsage.ResourceList)
+	r.prodForecaster.Record(req.Name, updateTime, getProdUsage(nodeMetric))
+
+	// forecast since the update time, so the forecast of the same report is stable
+	nodeUsage, nodeOK := r.nodeForecaster.Forecast(req.Name, updateTime)
+	prodUsage, prodOK := r.prodForecaster.Forecast(req.Name, updateTime)
+	if !nodeOK && !prodOK {
+		klog.V(6).Infof("samples of nodeMetric %s are insufficient to forecast", req.Name)
+		return ctrl.Result{}, nil
+	}
+	forecast := &apiext.NodeUsageForecast{
+		// truncate to the precision of the serialized time
+		ForecastTime: metav1.NewTime(updateTime.Add(r.nodeForecaster.Horizon()).Truncate(time.Second)),
+		NodeUsage:    nodeUsage,
+		ProdUsage:    prodUsage,
+	}
+	if err := r.annotateNodeMetric(ctx, nodeMetric, forecast); err != nil {
+		klog.Errorf("failed to annotate usage forecast of nodeMetric %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// annotateNodeMetric patches the forecast on the NodeMetric if it is changed.
+func (r *NodeMetricReconciler) annotateNodeMetric(ctx context.Context, nodeMetric *slov1alpha1.NodeMetric, forecast *apiext.NodeUsageForecast) error {
+	if lastForecast, err := apiext.GetNodeUsageForecast(nodeMetric.Annotations); err == nil && lastForecast != nil &&
+		isForecastEqual(lastForecast, forecast) {
+		return nil
+	}
+
+	obj := &slov1alpha1.NodeMetric{ObjectMeta: metav1.ObjectMeta{Name: nodeMetric.Name}}
+	if err := apiext.SetNodeUsageForecast(obj, forecast); err != nil {
+		return err
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": obj.Annotations,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.Client.Patch(ctx, obj, client.RawPatch(types.MergePatchType, data)); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	klog.V(5).Infof("annotate nodeMetric %s with usage forecast %+v", nodeMetric.Name, *forecast)
+	return nil
+}
+
+// getProdUsage returns the total usage of the Prod pods in the NodeMetric.
+func getProdUsage(nodeMetric *slov1alpha1.NodeMetric) corev1.ResourceList {
+	prodUsage := corev1.ResourceList{}
+	for _, podMetric := range nodeMetric.Status.PodsMetric {
+		if podMetric == nil || podMetric.Priority != apiext.PriorityProd {
+			continue
+		}
+		for resourceName, quantity := range podMetric.PodUsage.ResourceList {
+			used := prodUsage[resourceName]
+			used.Add(quantity)
+			prodUsage[resourceName] = used
+		}
+	}
+	return prodUsage
+}
+
+// isForecastEqual compares the forecasts by the values, since the formats of the quantities can differ after the
+// serialization.
+func isForecastEqual(a, b *apiext.NodeUsageForecast) bool {
+	if !a.ForecastTime.Equal(&b.ForecastTime) {
+		return false
+	}
+	return isResourceListEqual(a.NodeUsage, b.NodeUsage) && isResourceListEqual(a.ProdUsage, b.ProdUsage)
+}
+
+func isResourceListEqual(a, b corev1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for resourceName, quantity := range a {
+		other, ok := b[resourceName]
+		if !ok || quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Add creates the controller which forecasts the node usages.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.NodeUsageForecast) {
+		klog.V(4).Infof("feature %s is disabled, skip the node usage forecast controller", features.NodeUsageForecast)
+		return nil
+	}
+	r := &NodeMetricReconciler{
+		Client:         mgr.GetClient(),
+		nodeForecaster: NewForecaster(Horizon, HistoryWindow, MinSamples),
+		prodForecaster: NewForecaster(Horizon, HistoryWindow, MinSamples),
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&slov1alpha1.NodeMetric{}).
+		Named(Name).
+		Complete(r)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeusageforecast
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func TestNodeUsageForecastReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, slov1alpha1.AddToScheme(scheme))
+
+	nodeMetric := &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodeMetric).WithStatusSubresource(nodeMetric).Build()
+	r := &NodeMetricReconciler{
+		Client:         c,
+		nodeForecaster: NewForecaster(5*time.Minute, 15*time.Minute, 3),
+		prodForecaster: NewForecaster(5*time.Minute, 15*time.Minute, 3),
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-node"}}
+
+	// the NodeMetric is not reported
+	_, err := r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+
+	updateTime := time.Now().Truncate(time.Second)
+	for i, cpu := range []string{"4", "5", "6"} {
+		if i > 0 {
+			updateTime = updateTime.Add(time.Minute)
+		}
+		assert.NoError(t, c.Get(context.TODO(), req.NamespacedName, nodeMetric))
+		nodeMetric.Status = slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{Time: updateTime},
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse(cpu),
+				}},
+			},
+			PodsMetric: []*slov1alpha1.PodMetricInfo{
+				{
+					Name:     "prod-pod",
+					Priority: apiext.PriorityProd,
+					PodUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("1"),
+					}},
+				},
+				{
+					Name:     "batch-pod",
+					Priority: apiext.PriorityBatch,
+					PodUsage: slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse(cpu),
+					}},
+				},
+			},
+		}
+		assert.NoError(t, c.Status().Update(context.TODO(), nodeMetric))
+		_, err = r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+		// the report has been recorded
+		_, err = r.Reconcile(context.TODO(), req)
+		assert.NoError(t, err)
+	}
+
+	got := &slov1alpha1.NodeMetric{}
+	assert.NoError(t, c.Get(context.TODO(), req.NamespacedName, got))
+	forecast, err := apiext.GetNodeUsageForecast(got.Annotations)
+	assert.NoError(t, err)
+	assert.NotNil(t, forecast)
+	assert.True(t, forecast.ForecastTime.Time.Equal(updateTime.Add(5*time.Minute)))
+	nodeCPU := forecast.NodeUsage[corev1.ResourceCPU]
+	assert.Equal(t, int64(11000), nodeCPU.MilliValue())
+	prodCPU := forecast.ProdUsage[corev1.ResourceCPU]
+	assert.Equal(t, int64(1000), prodCPU.MilliValue())
+
+	// the histories are removed with the NodeMetric
+	assert.NoError(t, c.Delete(context.TODO(), got))
+	_, err = r.Reconcile(context.TODO(), req)
+	assert.NoError(t, err)
+	assert.Len(t, r.nodeForecaster.histories, 0)
+	assert.Len(t, r.prodForecaster.histories, 0)
+}
+
+func Test_isForecastEqual(t *testing.T) {
+	forecastTime := metav1.NewTime(time.Now().Truncate(time.Second))
+	a := &apiext.NodeUsageForecast{
+		ForecastTime: forecastTime,
+		NodeUsage:    corev1.ResourceList{corev1.ResourceCPU: *resource.NewMilliQuantity(2000, resource.DecimalSI)},
+	}
+	b := &apiext.NodeUsageForecast{
+		ForecastTime: forecastTime,
+		NodeUsage:    corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+	}
+	assert.True(t, isForecastEqual(a, b))
+	b.ProdUsage = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}
+	assert.False(t, isForecastEqual(a, b))
+	b.ProdUsage = nil
+	b.ForecastTime = metav1.NewTime(forecastTime.Add(time.Minute))
+	assert.False(t, isForecastEqual(a, b))
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeworkloadmix
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// workloadMix is the cpu requests in milli-cores of the prod and batch workloads on a node.
+type workloadMix struct {
+	prodMilliCPU  int64
+	batchMilliCPU int64
+}
+
+// getWorkloadMix sums the cpu requests of the running pods by the priority classes. The batch and free pods are
+// counted by the batch-cpu requests, and the other pods are counted by the cpu requests.
+func getWorkloadMix(pods []corev1.Pod) workloadMix {
+	mix := workloadMix{}
+	for i := range pods {
+		pod := &pods[i]
+		if util.IsPodTerminated(pod) {
+			continue
+		}
+		switch apiext.GetPodPriorityClassWithDefault(pod) {
+		case apiext.PriorityBatch, apiext.PriorityFree:
+			request := util.GetPodRequest(pod, apiext.BatchCPU)
+			mix.batchMilliCPU += request.Name(apiext.BatchCPU, resource.DecimalSI).Value()
+		default:
+			request := util.GetPodRequest(pod, corev1.ResourceCPU)
+			mix.prodMilliCPU += request.Cpu().MilliValue()
+		}
+	}
+	return mix
+}
+
+// batchPercent returns the percentage of the batch requests in all requests, or -1 if there is no workload.
+func (m workloadMix) batchPercent() int64 {
+	total := m.prodMilliCPU + m.batchMilliCPU
+	if total <= 0 {
+		return -1
+	}
+	return m.batchMilliCPU * 100 / total
+}
+
+// classify returns the workload mix of the node by the batch percentage. To avoid flapping the labels, the node
+// keeps the current class until the percentage crosses the threshold by the hysteresis margin.
+func classify(batchPercent int64, current apiext.NodeWorkloadMix, prodOnlyMax, batchHeavyMin, hysteresis int64) apiext.NodeWorkloadMix {
+	switch current {
+	case apiext.NodeWorkloadMixProdOnly:
+		prodOnlyMax += hysteresis
+	case apiext.NodeWorkloadMixBalanced:
+		prodOnlyMax -= hysteresis
+		batchHeavyMin += hysteresis
+	case apiext.NodeWorkloadMixBatchHeavy:
+		batchHeavyMin -= hysteresis
+	}
+	if batchPercent <= prodOnlyMax {
+		return apiext.NodeWorkloadMixProdOnly
+	}
+	if batchPercent >= batchHeavyMin {
+		return apiext.NodeWorkloadMixBatchHeavy
+	}
+	return apiext.NodeWorkloadMixBalanced
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeworkloadmix
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func newTestPod(name, nodeName string, qos apiext.QoSClass, resourceName corev1.ResourceName, quantity resource.Quantity) *corev1.Pod {
+	pod := &corev1.Pod{}
+	pod.Namespace = "default"
+	pod.Name = name
+	pod.Labels = map[string]string{apiext.LabelPodQoS: string(qos)}
+	pod.Spec.NodeName = nodeName
+	pod.Spec.Containers = []corev1.Container{
+		{
+			Name: "main",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					resourceName: quantity,
+				},
+			},
+		},
+	}
+	pod.Status.Phase = corev1.PodRunning
+	return pod
+}
+
+func Test_getWorkloadMix(t *testing.T) {
+	lsPod := newTestPod("ls-pod", "node-0", apiext.QoSLS, corev1.ResourceCPU, resource.MustParse("3"))
+	bePod := newTestPod("be-pod", "node-0", apiext.QoSBE, apiext.BatchCPU, *resource.NewQuantity(1000, resource.DecimalSI))
+	completedPod := newTestPod("completed-pod", "node-0", apiext.QoSBE, apiext.BatchCPU, *resource.NewQuantity(4000, resource.DecimalSI))
+	completedPod.Status.Phase = corev1.PodSucceeded
+
+	mix := getWorkloadMix([]corev1.Pod{*lsPod, *bePod, *completedPod})
+	assert.Equal(t, workloadMix{prodMilliCPU: 3000, batchMilliCPU: 1000}, mix)
+	assert.Equal(t, int64(25), mix.batchPercent())
+	assert.Equal(t, int64(-1), getWorkloadMix(nil).batchPercent())
+}
+
+func Test_classify(t *testing.T) {
+	tests := []struct {
+		name         string
+		batchPercent int64
+		current      apiext.NodeWorkloadMix
+		want         apiext.NodeWorkloadMix
+	}{
+		{
+			name:         "new prod-only node",
+			batchPercent: 10,
+			want:         apiext.NodeWorkloadMixProdOnly,
+		},
+		{
+			name:         "new balanced node",
+			batchPercent: 30,
+			want:         apiext.NodeWorkloadMixBalanced,
+		},
+		{
+			name:         "new batch-heavy node",
+			batchPercent: 60,
+			want:         apiext.NodeWorkloadMixBatchHeavy,
+		},
+		{
+			name:         "prod-only node keeps in the margin",
+			batchPercent: 15,
+			current:      apiext.NodeWorkloadMixProdOnly,
+			want:         apiext.NodeWorkloadMixProdOnly,
+		},
+		{
+			name:         "prod-only node becomes balanced",
+			batchPercent: 16,
+			current:      apiext.NodeWorkloadMixProdOnly,
+			want:         apiext.NodeWorkloadMixBalanced,
+		},
+		{
+			name:         "balanced node keeps in the margin",
+			batchPercent: 64,
+			current:      apiext.NodeWorkloadMixBalanced,
+			want:         apiext.NodeWorkloadMixBalanced,
+		},
+		{
+			name:         "balanced node becomes prod-only",
+			batchPercent: 5,
+			current:      apiext.NodeWorkloadMixBalanced,
+			want:         apiext.NodeWorkloadMixProdOnly,
+		},
+		{
+			name:         "batch-heavy node keeps in the margin",
+			batchPercent: 55,
+			current:      apiext.NodeWorkloadMixBatchHeavy,
+			want:         apiext.NodeWorkloadMixBatchHeavy,
+		},
+		{
+			name:         "batch-heavy node becomes balanced",
+			batchPercent: 54,
+			current:      apiext.NodeWorkloadMixBatchHeavy,
+			want:         apiext.NodeWorkloadMixBalanced,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classify(tt.batchPercent, tt.current, 10, 60, 5))
+		})
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeworkloadmix
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+const Name = "nodeworkloadmix"
+
+var (
+	// SyncInterval is the minimum interval between two classifications of a node.
+	SyncInterval = 5 * time.Minute
+	// ProdOnlyMaxBatchPercent is the maximum percentage of the batch cpu requests for a node to be prod-only.
+	ProdOnlyMaxBatchPercent = 10
+	// BatchHeavyMinBatchPercent is the minimum percentage of the batch cpu requests for a node to be batch-heavy.
+	BatchHeavyMinBatchPercent = 60
+	// HysteresisPercent is the margin of the batch percentage for a node to leave the current class.
+	HysteresisPercent = 5
+	// SyncPoolLabel indicates whether to set the colocation pool label of the nodes to the workload mix as well.
+	SyncPoolLabel = false
+)
+
+func InitFlags(fs *flag.FlagSet) {
+	pflag.DurationVar(&SyncInterval, "node-workload-mix-sync-interval", SyncInterval, "The minimum interval to classify the workload mix of a node.")
+	pflag.IntVar(&ProdOnlyMaxBatchPercent, "node-workload-mix-prod-only-max-batch-percent", ProdOnlyMaxBatchPercent, "The maximum percentage of the batch cpu requests for a node to be classified as prod-only.")
+	pflag.IntVar(&BatchHeavyMinBatchPercent, "node-workload-mix-batch-heavy-min-batch-percent", BatchHeavyMinBatchPercent, "The minimum percentage of the batch cpu requests for a node to be classified as batch-heavy.")
+	pflag.IntVar(&HysteresisPercent, "node-workload-mix-hysteresis-percent", HysteresisPercent, "The margin of the batch percentage for a node to leave the current workload mix.")
+	pflag.BoolVar(&SyncPoolLabel, "node-workload-mix-sync-pool-label", SyncPoolLabel, "Whether to set the colocation pool label of the nodes to the classified workload mix.")
+}
+
+// Reconciler classifies the nodes into the colocation profiles by the workload mix of the pods, and labels the
+// nodes, so the matching NodeSLO templates and the scheduler pools selecting the labels are applied automatically.
+type Reconciler struct {
+	client.Client
+
+	lock sync.Mutex
+	// lastSyncTimes records the time of the last classification of the nodes
+	lastSyncTimes map[string]time.Time
+}
+
+// +kubebuilder:rbac:groups=core,resources=nodes,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	node := &corev1.Node{}
+	if err := r.Client.Get(ctx, req.NamespacedName, node); err != nil {
+		if errors.IsNotFound(err) {
+			r.lock.Lock()
+			delete(r.lastSyncTimes, req.Name)
+			r.lock.Unlock()
+			return ctrl.Result{}, nil
+		}
+		klog.Errorf("failed to get node %s, err: %v", req.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	now := time.Now()
+	r.lock.Lock()
+	lastSyncTime, ok := r.lastSyncTimes[node.Name]
+	r.lock.Unlock()
+	if elapsed := now.Sub(lastSyncTime); ok && elapsed < SyncInterval {
+		return ctrl.Result{RequeueAfter: SyncInterval - elapsed}, nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node.Name),
+	}); err != nil {
+		klog.Errorf("failed to list pods of node %s, err: %v", node.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+	mix := getWorkloadMix(podList.Items)
+	batchPercent := mix.batchPercent()
+	if batchPercent < 0 {
+		// keep the labels of the idle node until the workloads come
+		klog.V(5).Infof("skip classifying node %s since there is no workload", node.Name)
+		return ctrl.Result{}, nil
+	}
+	current := apiext.NodeWorkloadMix(node.Labels[apiext.LabelNodeWorkloadMix])
+	expected := classify(batchPercent, current, int64(ProdOnlyMaxBatchPercent), int64(BatchHeavyMinBatchPercent), int64(HysteresisPercent))
+	if err := r.updateNodeLabels(ctx, node, expected); err != nil {
+		klog.Errorf("failed to update workload mix labels of node %s, err: %v", node.Name, err)
+		return ctrl.Result{Requeue: true}, err
+	}
+
+	r.lock.Lock()
+	r.lastSyncTimes[node.Name] = now
+	r.lock.Unlock()
+	klog.V(5).Infof("classify node %s as %s, batch percent %d", node.Name, expected, batchPercent)
+	return ctrl.Result{}, nil
+}
+
+// updateNodeLabels patches the workload mix labels of the node if they are changed.
+func (r *Reconciler) updateNodeLabels(ctx context.Context, node *corev1.Node, mix apiext.NodeWorkloadMix) error {
+	labels := map[string]string{}
+	if node.Labels[apiext.LabelNodeWorkloadMix] != string(mix) {
+		labels[apiext.LabelNodeWorkloadMix] = string(mix)
+	}
+	if SyncPoolLabel && node.Labels[apiext.LabelNodeColocationPool] != string(mix) {
+		labels[apiext.LabelNodeColocationPool] = string(mix)
+	}
+	if len(labels) <= 0 {
+		return nil
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": labels,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err = r.Client.Patch(ctx, node, client.RawPatch(types.MergePatchType, data)); err != nil {
+		return err
+	}
+	klog.V(4).Infof("label node %s with workload mix %s", node.Name, mix)
+	return nil
+}
+
+// Add creates the controller which classifies the workload mixes of the nodes.
+func Add(mgr ctrl.Manager) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.NodeWorkloadMixClassifier) {
+		klog.V(4).Infof("feature %s is disabled, skip the node workload mix controller", features.NodeWorkloadMixClassifier)
+		return nil
+	}
+	reconciler := &Reconciler{
+		Client:        mgr.GetClient(),
+		lastSyncTimes: map[string]time.Time{},
+	}
+	// the pod events trigger the classification of their nodes, which is limited by the sync interval
+	enqueueNode := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok || pod.Spec.NodeName == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: pod.Spec.NodeName}}}
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(Name).
+		For(&corev1.Node{}, builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&corev1.Pod{}, enqueueNode).
+		Complete(reconciler)
+}
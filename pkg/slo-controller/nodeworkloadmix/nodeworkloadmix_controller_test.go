@@ -0,0 +1,102 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodeworkloadmix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestNodeWorkloadMixReconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	node0 := &corev1.Node{}
+	node0.Name = "node-0"
+	node1 := &corev1.Node{}
+	node1.Name = "node-1"
+	lsPod := newTestPod("ls-pod", "node-0", apiext.QoSLS, corev1.ResourceCPU, resource.MustParse("4"))
+	bePod := newTestPod("be-pod", "node-0", apiext.QoSBE, apiext.BatchCPU, *resource.NewQuantity(4000, resource.DecimalSI))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Pod).Spec.NodeName}
+		}).
+		WithObjects(node0, node1, lsPod, bePod).Build()
+	r := &Reconciler{
+		Client:        fakeClient,
+		lastSyncTimes: map[string]time.Time{},
+	}
+	ctx := context.TODO()
+
+	oldSyncPoolLabel := SyncPoolLabel
+	SyncPoolLabel = true
+	defer func() {
+		SyncPoolLabel = oldSyncPoolLabel
+	}()
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-0"}}
+	result, err := r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	gotNode := &corev1.Node{}
+	assert.NoError(t, fakeClient.Get(ctx, req.NamespacedName, gotNode))
+	assert.Equal(t, string(apiext.NodeWorkloadMixBalanced), gotNode.Labels[apiext.LabelNodeWorkloadMix])
+	assert.Equal(t, string(apiext.NodeWorkloadMixBalanced), gotNode.Labels[apiext.LabelNodeColocationPool])
+
+	// rate limited
+	assert.NoError(t, fakeClient.Delete(ctx, lsPod))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, result.RequeueAfter > 0)
+
+	// reclassify after the sync interval
+	r.lastSyncTimes["node-0"] = time.Now().Add(-SyncInterval)
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(ctx, req.NamespacedName, gotNode))
+	assert.Equal(t, string(apiext.NodeWorkloadMixBatchHeavy), gotNode.Labels[apiext.LabelNodeWorkloadMix])
+
+	// the idle node is not labeled
+	req1 := ctrl.Request{NamespacedName: types.NamespacedName{Name: "node-1"}}
+	result, err = r.Reconcile(ctx, req1)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	assert.NoError(t, fakeClient.Get(ctx, req1.NamespacedName, gotNode))
+	assert.Nil(t, gotNode.Labels)
+
+	// the deleted node is cleaned up
+	assert.NoError(t, fakeClient.Delete(ctx, node0))
+	result, err = r.Reconcile(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+	_, ok := r.lastSyncTimes["node-0"]
+	assert.False(t, ok)
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictionbudget
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	sloinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions/slo/v1alpha1"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+)
+
+var timeNow = time.Now
+
+// Admitter checks the evictions against the ClusterEvictionBudgets. It is shared by the evictors of the koordinator
+// components, so the evictions decided independently on the nodes are capped by the pool.
+type Admitter interface {
+	// Admit returns whether the eviction of the pod from the node is admitted by all budgets selecting the node, and
+	// the reason if it is not. The admitted eviction is recorded in the budgets before the pod is evicted, so it
+	// consumes the budgets even if the eviction fails later. Admitting the same pod again does not consume more.
+	// The eviction is recorded in either all or none of the budgets.
+	Admit(ctx context.Context, pod *corev1.Pod, node *corev1.Node, evictor string) (bool, string, error)
+}
+
+type admitter struct {
+	client    koordclientset.Interface
+	lister    slolisters.ClusterEvictionBudgetLister
+	hasSynced cache.InformerSynced
+}
+
+// NewAdmitter returns the admitter which checks the budgets from the informer, and records the evictions with the
+// client. The informer should be started by the caller.
+func NewAdmitter(client koordclientset.Interface, informer sloinformers.ClusterEvictionBudgetInformer) Admitter {
+	return &admitter{
+		client:    client,
+		lister:    informer.Lister(),
+		hasSynced: informer.Informer().HasSynced,
+	}
+}
+
+func (a *admitter) Admit(ctx context.Context, pod *corev1.Pod, node *corev1.Node, evictor string) (bool, string, error) {
+	if !a.hasSynced() {
+		return false, "", fmt.Errorf("cluster eviction budgets are not synced")
+	}
+	budgets, err := a.lister.List(labels.Everything())
+	if err != nil {
+		return false, "", fmt.Errorf("failed to list cluster eviction budgets, err: %w", err)
+	}
+
+	now := timeNow()
+	var matched []string
+	// check all budgets before recording, so a denied eviction does not consume the other budgets
+	for _, budget := range budgets {
+		selected, err := IsNodeSelected(budget, node)
+		if err != nil {
+			klog.V(4).Infof("skip cluster eviction budget %s, invalid node selector, err: %v", budget.Name, err)
+			continue
+		}
+		if !selected {
+			continue
+		}
+		if !isAllowed(budget, pod, now) {
+			return false, getExhaustedReason(budget), nil
+		}
+		matched = append(matched, budget.Name)
+	}
+
+	// the lister can be stale, so the budgets are re-checked on recording in the order of the names, and the recorded
+	// ones are rolled back if any of the budgets denies
+	sort.Strings(matched)
+	var recorded []string
+	for _, name := range matched {
+		admitted, added, reason, err := a.record(ctx, name, pod, evictor)
+		if err == nil && admitted {
+			if added {
+				recorded = append(recorded, name)
+			}
+			continue
+		}
+		a.rollback(ctx, recorded, pod)
+		if err != nil {
+			return false, "", err
+		}
+		return false, reason, nil
+	}
+	return true, "", nil
+}
+
+// record appends the eviction to the status of the budget. It re-checks the latest budget to avoid exceeding the
+// limit when the evictors update the budget concurrently. It returns whether the eviction is admitted and whether
+// the eviction is added by this call.
+func (a *admitter) record(ctx context.Context, name string, pod *corev1.Pod, evictor string) (bool, bool, string, error) {
+	admitted, added, reason := false, false, ""
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		admitted, added, reason = false, false, ""
+		budget, err := a.client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			admitted = true
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		now := timeNow()
+		if hasEviction(getEvictionsInWindow(budget, now), pod) {
+			admitted = true
+			return nil
+		}
+		if !isAllowed(budget, pod, now) {
+			reason = getExhaustedReason(budget)
+			return nil
+		}
+
+		budget.Status.Evictions = append(budget.Status.Evictions, slov1alpha1.EvictionRecord{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+			NodeName:  pod.Spec.NodeName,
+			Evictor:   evictor,
+			Time:      metav1.NewTime(now),
+		})
+		budget.Status = NewStatus(budget, now)
+		if _, err = a.client.SloV1alpha1().ClusterEvictionBudgets().UpdateStatus(ctx, budget, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		admitted, added = true, true
+		return nil
+	})
+	if err != nil {
+		return false, false, "", fmt.Errorf("failed to record eviction in cluster eviction budget %s, err: %w", name, err)
+	}
+	return admitted, added, reason, nil
+}
+
+// rollback removes the eviction of the pod from the budgets. The failures are only logged, and the records expire
+// after the windows of the budgets.
+func (a *admitter) rollback(ctx context.Context, names []string, pod *corev1.Pod) {
+	for _, name := range names {
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			budget, err := a.client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			evictions := make([]slov1alpha1.EvictionRecord, 0, len(budget.Status.Evictions))
+			for _, e := range budget.Status.Evictions {
+				if e.UID == pod.UID && e.Namespace == pod.Namespace && e.Name == pod.Name {
+					continue
+				}
+				evictions = append(evictions, e)
+			}
+			if len(evictions) == len(budget.Status.Evictions) {
+				return nil
+			}
+			budget.Status.Evictions = evictions
+			budget.Status = NewStatus(budget, timeNow())
+			_, err = a.client.SloV1alpha1().ClusterEvictionBudgets().UpdateStatus(ctx, budget, metav1.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			klog.Warningf("failed to roll back eviction of pod %s/%s in cluster eviction budget %s, err: %v",
+				pod.Namespace, pod.Name, name, err)
+		}
+	}
+}
+
+// IsNodeSelected returns whether the budget applies to the node. A nil node is only selected by the empty selector.
+func IsNodeSelected(budget *slov1alpha1.ClusterEvictionBudget, node *corev1.Node) (bool, error) {
+	if budget.Spec.NodeSelector == nil {
+		return true, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(budget.Spec.NodeSelector)
+	if err != nil {
+		return false, err
+	}
+	var nodeLabels labels.Set
+	if node != nil {
+		nodeLabels = node.Labels
+	}
+	return selector.Matches(nodeLabels), nil
+}
+
+// NewStatus returns the status of the budget with the evictions out of the window pruned.
+func NewStatus(budget *slov1alpha1.ClusterEvictionBudget, now time.Time) slov1alpha1.ClusterEvictionBudgetStatus {
+	evictions := getEvictionsInWindow(budget, now)
+	remaining := budget.Spec.MaxEvictions - int32(len(evictions))
+	if remaining < 0 {
+		remaining = 0
+	}
+	nowTime := metav1.NewTime(now)
+	return slov1alpha1.ClusterEvictionBudgetStatus{
+		Evictions:          evictions,
+		CurrentEvictions:   int32(len(evictions)),
+		RemainingEvictions: remaining,
+		UpdateTime:         &nowTime,
+	}
+}
+
+// GetNextExpireDuration returns the duration after which the oldest eviction in the window expires.
+// It returns zero if there is no eviction in the window.
+func GetNextExpireDuration(budget *slov1alpha1.ClusterEvictionBudget, now time.Time) time.Duration {
+	evictions := getEvictionsInWindow(budget, now)
+	if len(evictions) <= 0 {
+		return 0
+	}
+	oldest := evictions[0].Time.Time
+	for _, e := range evictions[1:] {
+		if e.Time.Time.Before(oldest) {
+			oldest = e.Time.Time
+		}
+	}
+	return oldest.Add(budget.Spec.Window.Duration).Sub(now)
+}
+
+func getEvictionsInWindow(budget *slov1alpha1.ClusterEvictionBudget, now time.Time) []slov1alpha1.EvictionRecord {
+	windowStart := now.Add(-budget.Spec.Window.Duration)
+	var evictions []slov1alpha1.EvictionRecord
+	for _, e := range budget.Status.Evictions {
+		if e.Time.Time.After(windowStart) {
+			evictions = append(evictions, e)
+		}
+	}
+	return evictions
+}
+
+func isAllowed(budget *slov1alpha1.ClusterEvictionBudget, pod *corev1.Pod, now time.Time) bool {
+	evictions := getEvictionsInWindow(budget, now)
+	if hasEviction(evictions, pod) {
+		return true
+	}
+	return int32(len(evictions)) < budget.Spec.MaxEvictions
+}
+
+func hasEviction(evictions []slov1alpha1.EvictionRecord, pod *corev1.Pod) bool {
+	for _, e := range evictions {
+		if e.UID == pod.UID && e.Namespace == pod.Namespace && e.Name == pod.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func getExhaustedReason(budget *slov1alpha1.ClusterEvictionBudget) string {
+	return fmt.Sprintf("cluster eviction budget %s is exhausted, max %d evictions in %v",
+		budget.Name, budget.Spec.MaxEvictions, budget.Spec.Window.Duration)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package evictionbudget
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+	koordinformers "github.com/koordinator-sh/koordinator/pkg/client/informers/externalversions"
+	slolisters "github.com/koordinator-sh/koordinator/pkg/client/listers/slo/v1alpha1"
+)
+
+func newTestPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name,
+			UID:       types.UID("uid-" + name),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "test-node",
+		},
+	}
+}
+
+func newTestBudget(name string, maxEvictions int32, selector *metav1.LabelSelector) *slov1alpha1.ClusterEvictionBudget {
+	return &slov1alpha1.ClusterEvictionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: slov1alpha1.ClusterEvictionBudgetSpec{
+			NodeSelector: selector,
+			MaxEvictions: maxEvictions,
+			Window:       metav1.Duration{Duration: 10 * time.Minute},
+		},
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	now := time.Now()
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return now }
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test-node",
+			Labels: map[string]string{"pool": "pool-a"},
+		},
+	}
+	poolA := newTestBudget("pool-a", 2, &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "pool-a"}})
+	poolB := newTestBudget("pool-b", 0, &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "pool-b"}})
+	cluster := newTestBudget("cluster", 3, nil)
+	client := koordfake.NewSimpleClientset(poolA, poolB, cluster)
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory := koordinformers.NewSharedInformerFactory(client, 0)
+	a := NewAdmitter(client, factory.Slo().V1alpha1().ClusterEvictionBudgets())
+	ctx := context.TODO()
+
+	// the budgets are not synced
+	_, _, err := a.Admit(ctx, newTestPod("pod-1"), node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.Error(t, err)
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	admitted, _, err := a.Admit(ctx, newTestPod("pod-1"), node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	// admit the same pod again does not consume the budget
+	admitted, _, err = a.Admit(ctx, newTestPod("pod-1"), node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	admitted, _, err = a.Admit(ctx, newTestPod("pod-2"), node, slov1alpha1.EvictionBudgetEvictorDescheduler)
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+
+	got, err := client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "pool-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Status.CurrentEvictions)
+	assert.Equal(t, int32(0), got.Status.RemainingEvictions)
+	assert.Equal(t, slov1alpha1.EvictionBudgetEvictorDescheduler, got.Status.Evictions[1].Evictor)
+
+	// pool-a is exhausted, and the cluster budget is not consumed
+	admitted, reason, err := a.Admit(ctx, newTestPod("pod-3"), node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+	assert.Contains(t, reason, "pool-a")
+	got, err = client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "cluster", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), got.Status.CurrentEvictions)
+	got, err = client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "pool-b", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), got.Status.CurrentEvictions)
+
+	// the evictions expire after the window
+	now = now.Add(10 * time.Minute)
+	admitted, _, err = a.Admit(ctx, newTestPod("pod-3"), node, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.NoError(t, err)
+	assert.True(t, admitted)
+	got, err = client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "pool-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), got.Status.CurrentEvictions)
+	assert.Equal(t, "pod-3", got.Status.Evictions[0].Name)
+}
+
+func TestNewStatus(t *testing.T) {
+	now := time.Now()
+	budget := newTestBudget("test", 2, nil)
+	budget.Status.Evictions = []slov1alpha1.EvictionRecord{
+		{Name: "pod-1", Time: metav1.NewTime(now.Add(-20 * time.Minute))},
+		{Name: "pod-2", Time: metav1.NewTime(now.Add(-5 * time.Minute))},
+	}
+	status := NewStatus(budget, now)
+	assert.Equal(t, []slov1alpha1.EvictionRecord{budget.Status.Evictions[1]}, status.Evictions)
+	assert.Equal(t, int32(1), status.CurrentEvictions)
+	assert.Equal(t, int32(1), status.RemainingEvictions)
+	assert.Equal(t, 5*time.Minute, GetNextExpireDuration(budget, now))
+	assert.Equal(t, time.Duration(0), GetNextExpireDuration(newTestBudget("empty", 1, nil), now))
+}
+
+func TestAdmitRollback(t *testing.T) {
+	now := time.Now()
+	oldTimeNow := timeNow
+	defer func() { timeNow = oldTimeNow }()
+	timeNow = func() time.Time { return now }
+
+	poolA := newTestBudget("pool-a", 1, nil)
+	cluster := newTestBudget("cluster", 3, nil)
+	// the lister is stale, pool-a is exhausted in the latest
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	assert.NoError(t, indexer.Add(poolA.DeepCopy()))
+	assert.NoError(t, indexer.Add(cluster.DeepCopy()))
+	poolA.Status.Evictions = []slov1alpha1.EvictionRecord{
+		{Namespace: "default", Name: "pod-0", UID: "uid-pod-0", Time: metav1.NewTime(now)},
+	}
+	client := koordfake.NewSimpleClientset(poolA, cluster)
+	a := &admitter{
+		client:    client,
+		lister:    slolisters.NewClusterEvictionBudgetLister(indexer),
+		hasSynced: func() bool { return true },
+	}
+	ctx := context.TODO()
+
+	admitted, reason, err := a.Admit(ctx, newTestPod("pod-1"), nil, slov1alpha1.EvictionBudgetEvictorKoordlet)
+	assert.NoError(t, err)
+	assert.False(t, admitted)
+	assert.Contains(t, reason, "pool-a")
+	// the recorded eviction in the cluster budget is rolled back
+	got, err := client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "cluster", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(got.Status.Evictions))
+	got, err = client.SloV1alpha1().ClusterEvictionBudgets().Get(ctx, "pool-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(got.Status.Evictions))
+}
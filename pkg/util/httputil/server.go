@@ -0,0 +1,79 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package httputil
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RunnableServer is an HTTP server which can be added into the controller-runtime manager.
+// It does not need the leader election since every replica could serve the requests from the cache.
+type RunnableServer struct {
+	name        string
+	bindAddress string
+	certDir     string
+	handler     http.Handler
+}
+
+// NewRunnableServer creates a RunnableServer. It serves https with tls.crt and tls.key in the certDir,
+// or serves plain http if the certDir is empty.
+func NewRunnableServer(name, bindAddress, certDir string, handler http.Handler) *RunnableServer {
+	return &RunnableServer{
+		name:        name,
+		bindAddress: bindAddress,
+		certDir:     certDir,
+		handler:     handler,
+	}
+}
+
+func (s *RunnableServer) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *RunnableServer) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.bindAddress,
+		Handler:           s.handler,
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			klog.Warningf("failed to shutdown the %s server, err: %v", s.name, err)
+		}
+	}()
+
+	klog.Infof("starting the %s server on %s", s.name, s.bindAddress)
+	var err error
+	if s.certDir != "" {
+		err = srv.ListenAndServeTLS(filepath.Join(s.certDir, "tls.crt"), filepath.Join(s.certDir, "tls.key"))
+	} else {
+		err = srv.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
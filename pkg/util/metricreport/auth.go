@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricreport
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// ServiceAccountTokenFile is the projected token of the ServiceAccount mounted in the koordlet pod, which is
+	// bound to the pod and rotated by the kubelet.
+	ServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	authorizationKey = "authorization"
+	bearerPrefix     = "Bearer "
+)
+
+var _ credentials.PerRPCCredentials = &tokenFileCredentials{}
+
+// tokenFileCredentials attaches the token read from the file to each call. The file is read at every call since the
+// token is rotated.
+type tokenFileCredentials struct {
+	path string
+}
+
+// NewTokenFileCredentials returns the credentials which authenticate the calls with the bearer token in the file.
+func NewTokenFileCredentials(path string) credentials.PerRPCCredentials {
+	return &tokenFileCredentials{path: path}
+}
+
+func (c *tokenFileCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	content, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file %s, err: %w", c.path, err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return nil, fmt.Errorf("token file %s is empty", c.path)
+	}
+	return map[string]string{authorizationKey: bearerPrefix + token}, nil
+}
+
+// RequireTransportSecurity forbids sending the token in plaintext.
+func (c *tokenFileCredentials) RequireTransportSecurity() bool {
+	return true
+}
+
+// GetBearerToken returns the bearer token in the metadata of an incoming call.
+func GetBearerToken(md map[string][]string) (string, bool) {
+	for _, v := range md[authorizationKey] {
+		if strings.HasPrefix(v, bearerPrefix) {
+			if token := strings.TrimSpace(strings.TrimPrefix(v, bearerPrefix)); token != "" {
+				return token, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricreport
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestTokenFileCredentials(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	c := NewTokenFileCredentials(tokenFile)
+	assert.True(t, c.RequireTransportSecurity())
+	_, err := c.GetRequestMetadata(context.TODO())
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("token-0\n"), 0600))
+	got, err := c.GetRequestMetadata(context.TODO())
+	assert.NoError(t, err)
+	token, ok := GetBearerToken(metadata.New(got))
+	assert.True(t, ok)
+	assert.Equal(t, "token-0", token)
+
+	// the rotated token is read at the next call
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("token-1"), 0600))
+	got, err = c.GetRequestMetadata(context.TODO())
+	assert.NoError(t, err)
+	token, ok = GetBearerToken(metadata.New(got))
+	assert.True(t, ok)
+	assert.Equal(t, "token-1", token)
+}
+
+func TestGetBearerToken(t *testing.T) {
+	_, ok := GetBearerToken(metadata.New(nil))
+	assert.False(t, ok)
+	_, ok = GetBearerToken(metadata.Pairs(authorizationKey, "Basic xxx"))
+	assert.False(t, ok)
+	_, ok = GetBearerToken(metadata.Pairs(authorizationKey, bearerPrefix))
+	assert.False(t, ok)
+}
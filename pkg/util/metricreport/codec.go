@@ -0,0 +1,58 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricreport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/grpc"
+)
+
+// CodecName is the content-subtype of the NodeMetric report calls. The reports embed the NodeMetricStatus API type
+// which is defined for the JSON serialization, so the messages are encoded in JSON instead of the protobuf.
+// The codec is forced per call and per server rather than registered globally, so the other gRPC users in the same
+// binary keep their own codecs of the subtype.
+const CodecName = "json"
+
+// ForceCodec returns the call option to encode the messages with the JSON codec.
+func ForceCodec() grpc.CallOption {
+	return grpc.ForceCodec(jsonCodec{})
+}
+
+// ForceServerCodec returns the server option to decode the reports with the JSON codec. The server should only
+// serve the services of the JSON codec.
+func ForceServerCodec() grpc.ServerOption {
+	return grpc.ForceServerCodec(jsonCodec{})
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal %T, err: %w", v, err)
+	}
+	return nil
+}
+
+func (jsonCodec) Name() string {
+	return CodecName
+}
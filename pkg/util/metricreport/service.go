@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricreport
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+const (
+	ServiceName      = "koordinator.slo.v1alpha1.NodeMetricReportService"
+	ReportStreamName = "Report"
+	ReportMethod     = "/" + ServiceName + "/" + ReportStreamName
+)
+
+// NodeMetricReport is a metric report of a node sent by the koordlet.
+type NodeMetricReport struct {
+	NodeName string                        `json:"nodeName"`
+	Status   *slov1alpha1.NodeMetricStatus `json:"status,omitempty"`
+}
+
+// NodeMetricReportResponse is returned when the report stream is closed.
+type NodeMetricReportResponse struct {
+	Received int64 `json:"received"`
+}
+
+// NodeMetricReportServiceClient is the client API for the NodeMetricReportService.
+type NodeMetricReportServiceClient interface {
+	// Report opens a stream to send the metric reports of a node continuously.
+	Report(ctx context.Context, opts ...grpc.CallOption) (NodeMetricReportService_ReportClient, error)
+}
+
+type NodeMetricReportService_ReportClient interface {
+	Send(*NodeMetricReport) error
+	CloseAndRecv() (*NodeMetricReportResponse, error)
+	grpc.ClientStream
+}
+
+type nodeMetricReportServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNodeMetricReportServiceClient(cc grpc.ClientConnInterface) NodeMetricReportServiceClient {
+	return &nodeMetricReportServiceClient{cc}
+}
+
+func (c *nodeMetricReportServiceClient) Report(ctx context.Context, opts ...grpc.CallOption) (NodeMetricReportService_ReportClient, error) {
+	opts = append([]grpc.CallOption{ForceCodec()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NodeMetricReportService_ServiceDesc.Streams[0], ReportMethod, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeMetricReportServiceReportClient{stream}, nil
+}
+
+type nodeMetricReportServiceReportClient struct {
+	grpc.ClientStream
+}
+
+func (x *nodeMetricReportServiceReportClient) Send(m *NodeMetricReport) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *nodeMetricReportServiceReportClient) CloseAndRecv() (*NodeMetricReportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(NodeMetricReportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// NodeMetricReportServiceServer is the server API for the NodeMetricReportService.
+type NodeMetricReportServiceServer interface {
+	Report(NodeMetricReportService_ReportServer) error
+}
+
+// UnimplementedNodeMetricReportServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedNodeMetricReportServiceServer struct{}
+
+func (UnimplementedNodeMetricReportServiceServer) Report(NodeMetricReportService_ReportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Report not implemented")
+}
+
+type NodeMetricReportService_ReportServer interface {
+	SendAndClose(*NodeMetricReportResponse) error
+	Recv() (*NodeMetricReport, error)
+	grpc.ServerStream
+}
+
+type nodeMetricReportServiceReportServer struct {
+	grpc.ServerStream
+}
+
+func (x *nodeMetricReportServiceReportServer) SendAndClose(m *NodeMetricReportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *nodeMetricReportServiceReportServer) Recv() (*NodeMetricReport, error) {
+	m := new(NodeMetricReport)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func RegisterNodeMetricReportServiceServer(s grpc.ServiceRegistrar, srv NodeMetricReportServiceServer) {
+	s.RegisterService(&NodeMetricReportService_ServiceDesc, srv)
+}
+
+func _NodeMetricReportService_Report_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(NodeMetricReportServiceServer).Report(&nodeMetricReportServiceReportServer{stream})
+}
+
+// NodeMetricReportService_ServiceDesc is the grpc.ServiceDesc for the NodeMetricReportService.
+var NodeMetricReportService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*NodeMetricReportServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    ReportStreamName,
+			Handler:       _NodeMetricReportService_Report_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/util/metricreport/service.go",
+}
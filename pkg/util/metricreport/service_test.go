@@ -0,0 +1,96 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricreport
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+type fakeServer struct {
+	UnimplementedNodeMetricReportServiceServer
+	reports []*NodeMetricReport
+}
+
+func (s *fakeServer) Report(stream NodeMetricReportService_ReportServer) error {
+	for {
+		report, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&NodeMetricReportResponse{Received: int64(len(s.reports))})
+		}
+		if err != nil {
+			return err
+		}
+		s.reports = append(s.reports, report)
+	}
+}
+
+func TestNodeMetricReportService(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(ForceServerCodec())
+	fakeSrv := &fakeServer{}
+	RegisterNodeMetricReportServiceServer(server, fakeSrv)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.DialContext(context.TODO(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := NewNodeMetricReportServiceClient(conn).Report(context.TODO())
+	assert.NoError(t, err)
+	report := &NodeMetricReport{
+		NodeName: "test-node",
+		Status: &slov1alpha1.NodeMetricStatus{
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage: slov1alpha1.ResourceMap{
+					ResourceList: corev1.ResourceList{
+						corev1.ResourceCPU: resource.MustParse("2"),
+					},
+				},
+			},
+		},
+	}
+	assert.NoError(t, stream.Send(report))
+	assert.NoError(t, stream.Send(&NodeMetricReport{NodeName: "test-node"}))
+	resp, err := stream.CloseAndRecv()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Received)
+
+	assert.Len(t, fakeSrv.reports, 2)
+	assert.Equal(t, "test-node", fakeSrv.reports[0].NodeName)
+	gotCPU := fakeSrv.reports[0].Status.NodeMetric.NodeUsage.ResourceList[corev1.ResourceCPU]
+	assert.Equal(t, int64(2000), gotCPU.MilliValue())
+	assert.Nil(t, fakeSrv.reports[1].Status)
+}
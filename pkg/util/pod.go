@@ -18,11 +18,16 @@ package util
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
 )
 
 func GetEmptyPodExtendedResources() *apiext.ExtendedResourceSpec {
@@ -44,7 +49,7 @@ func GetPodTargetExtendedResources(pod *corev1.Pod, resourceNames ...corev1.Reso
 
 	extendedResources := GetEmptyPodExtendedResources()
 
-	// TODO: count init containers and pod overhead
+	// TODO: count pod overhead
 	for i := range pod.Spec.Containers {
 		container := &pod.Spec.Containers[i]
 		r := GetContainerTargetExtendedResources(container, resourceNames...)
@@ -53,8 +58,19 @@ func GetPodTargetExtendedResources(pod *corev1.Pod, resourceNames ...corev1.Reso
 		}
 		extendedResources.Containers[container.Name] = *r
 	}
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		r := GetContainerTargetExtendedResources(container, resourceNames...)
+		if r == nil {
+			continue
+		}
+		if extendedResources.InitContainers == nil {
+			extendedResources.InitContainers = map[string]apiext.ExtendedResourceContainerSpec{}
+		}
+		extendedResources.InitContainers[container.Name] = *r
+	}
 
-	if len(extendedResources.Containers) <= 0 {
+	if len(extendedResources.Containers) <= 0 && len(extendedResources.InitContainers) <= 0 {
 		return nil
 	}
 
@@ -83,3 +99,71 @@ func GetCPUSetFromPod(podAnnotations map[string]string) (string, error) {
 	}
 	return podAlloc.CPUSet, nil
 }
+
+// GetCPUSetMemsFromPod returns the NUMA nodes allocated to the pod in the Linux list format, e.g. "0-1".
+// It returns empty if the pod is not constrained to any NUMA node.
+func GetCPUSetMemsFromPod(podAnnotations map[string]string) (string, error) {
+	if podAnnotations == nil {
+		return "", nil
+	}
+	podAlloc, err := apiext.GetResourceStatus(podAnnotations)
+	if err != nil {
+		return "", err
+	}
+	if len(podAlloc.NUMANodeResources) == 0 {
+		return "", nil
+	}
+	numaNodes := make([]int, 0, len(podAlloc.NUMANodeResources))
+	for _, numaNode := range podAlloc.NUMANodeResources {
+		numaNodes = append(numaNodes, int(numaNode.Node))
+	}
+	return cpuset.NewCPUSet(numaNodes...).String(), nil
+}
+
+const (
+	// WorkloadKindPod is the workload kind of the pods without any controller.
+	WorkloadKindPod = "Pod"
+)
+
+// cronJobScheduledJobNameRegexp matches the names of the jobs created by the CronJob, which are <cronjob>-<minutes>.
+var cronJobScheduledJobNameRegexp = regexp.MustCompile(`^.+-[0-9]{8,}$`)
+
+// GetPodWorkloadKind returns the kind of the top-level workload of the pod only according to the pod metadata.
+// The ReplicaSets of Deployments and the Jobs of CronJobs are recognized by the labels and the names they generated.
+func GetPodWorkloadKind(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return WorkloadKindPod
+	}
+	switch owner.Kind {
+	case "ReplicaSet":
+		if _, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok {
+			return "Deployment"
+		}
+	case "Job":
+		if cronJobScheduledJobNameRegexp.MatchString(owner.Name) {
+			return "CronJob"
+		}
+	}
+	return owner.Kind
+}
+
+// GetPodWorkloadName returns the name of the top-level workload of the pod in the same way as GetPodWorkloadKind.
+// It returns the pod name for the pods without any controller.
+func GetPodWorkloadName(pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return pod.Name
+	}
+	switch owner.Kind {
+	case "ReplicaSet":
+		if hash, ok := pod.Labels[appsv1.DefaultDeploymentUniqueLabelKey]; ok && hash != "" {
+			return strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+	case "Job":
+		if cronJobScheduledJobNameRegexp.MatchString(owner.Name) {
+			return owner.Name[:strings.LastIndex(owner.Name, "-")]
+		}
+	}
+	return owner.Name
+}
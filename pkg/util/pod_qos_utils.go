@@ -18,9 +18,13 @@ package util
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 // NOTE: functions in this file can be overwritten for extension
@@ -42,6 +46,40 @@ func IsPodCPUBurstable(pod *corev1.Pod) bool {
 	return qosClass != apiext.QoSLSR && qosClass != apiext.QoSLSE && qosClass != apiext.QoSBE
 }
 
+// IsPodCPUBurstAllowedByWorkload checks if cpu burst is allowed for the pod by the workload policy.
+// The pod is denied if the label selectors of the policy are invalid.
+func IsPodCPUBurstAllowedByWorkload(pod *corev1.Pod, policy *slov1alpha1.CPUBurstWorkloadPolicy) bool {
+	return IsPodCPUBurstAllowedByWorkloadKind(pod, GetPodWorkloadKind(pod), policy)
+}
+
+// IsPodCPUBurstAllowedByWorkloadKind checks if cpu burst is allowed for the pod of the workload kind by the workload
+// policy, where the kind is resolved by the caller.
+func IsPodCPUBurstAllowedByWorkloadKind(pod *corev1.Pod, kind string, policy *slov1alpha1.CPUBurstWorkloadPolicy) bool {
+	if policy == nil {
+		return true
+	}
+	if len(policy.AllowedKinds) > 0 && !sets.NewString(policy.AllowedKinds...).Has(kind) {
+		return false
+	}
+	if sets.NewString(policy.DeniedKinds...).Has(kind) {
+		return false
+	}
+	podLabels := labels.Set(pod.Labels)
+	if policy.AllowedSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.AllowedSelector)
+		if err != nil || !selector.Matches(podLabels) {
+			return false
+		}
+	}
+	if policy.DeniedSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.DeniedSelector)
+		if err != nil || selector.Matches(podLabels) {
+			return false
+		}
+	}
+	return true
+}
+
 // GetKubeQosClass gets the Kubernetes QOSClass for the pod.
 // DEPRECATED: use extension.GetKubeQosClass instead.
 func GetKubeQosClass(pod *corev1.Pod) corev1.PodQOSClass {
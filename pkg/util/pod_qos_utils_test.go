@@ -22,8 +22,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
 )
 
 func Test_IsPodCfsQuotaNeedUnset(t *testing.T) {
@@ -134,3 +137,104 @@ func Test_GetKubeQosClass(t *testing.T) {
 		})
 	}
 }
+
+func Test_IsPodCPUBurstAllowedByWorkload(t *testing.T) {
+	newPod := func(ownerKind, ownerName string, podLabels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-pod",
+				Labels: podLabels,
+			},
+		}
+		if ownerKind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, Controller: pointer.Bool(true)},
+			}
+		}
+		return pod
+	}
+	deploymentPod := newPod("ReplicaSet", "test-deploy-5d4f8b9c7", map[string]string{"pod-template-hash": "5d4f8b9c7", "app": "web"})
+	cronJobPod := newPod("Job", "test-cronjob-28291740", map[string]string{"app": "batch"})
+	tests := []struct {
+		name   string
+		pod    *corev1.Pod
+		policy *slov1alpha1.CPUBurstWorkloadPolicy
+		want   bool
+	}{
+		{
+			name: "no policy",
+			pod:  cronJobPod,
+			want: true,
+		},
+		{
+			name:   "allow deployment",
+			pod:    deploymentPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{AllowedKinds: []string{"Deployment"}},
+			want:   true,
+		},
+		{
+			name:   "not in allowed kinds",
+			pod:    newPod("ReplicaSet", "test-rs", nil),
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{AllowedKinds: []string{"Deployment"}},
+			want:   false,
+		},
+		{
+			name:   "deny cronjob",
+			pod:    cronJobPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{DeniedKinds: []string{"CronJob"}},
+			want:   false,
+		},
+		{
+			name:   "job not created by cronjob",
+			pod:    newPod("Job", "test-job", nil),
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{DeniedKinds: []string{"CronJob"}},
+			want:   true,
+		},
+		{
+			name:   "bare pod",
+			pod:    newPod("", "", nil),
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{DeniedKinds: []string{WorkloadKindPod}},
+			want:   false,
+		},
+		{
+			name: "match allowed selector",
+			pod:  deploymentPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{
+				AllowedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: true,
+		},
+		{
+			name: "not match allowed selector",
+			pod:  cronJobPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{
+				AllowedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: false,
+		},
+		{
+			name: "match denied selector",
+			pod:  deploymentPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{
+				AllowedKinds:   []string{"Deployment"},
+				DeniedSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			},
+			want: false,
+		},
+		{
+			name: "invalid selector",
+			pod:  deploymentPod,
+			policy: &slov1alpha1.CPUBurstWorkloadPolicy{
+				DeniedSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "app", Operator: "invalid"}},
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsPodCPUBurstAllowedByWorkload(tt.pod, tt.policy))
+		})
+	}
+}
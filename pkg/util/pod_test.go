@@ -20,6 +20,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	apiext "github.com/koordinator-sh/koordinator/apis/extension"
 )
@@ -59,3 +62,101 @@ func Test_GetCPUSetFromPod(t *testing.T) {
 		})
 	}
 }
+
+func Test_GetCPUSetMemsFromPod(t *testing.T) {
+	tests := []struct {
+		name     string
+		podAlloc *apiext.ResourceStatus
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "pod without numa nodes",
+			podAlloc: &apiext.ResourceStatus{
+				CPUSet: "2-4",
+			},
+			want: "",
+		},
+		{
+			name: "get cpuset mems from annotation",
+			podAlloc: &apiext.ResourceStatus{
+				CPUSet: "2-4",
+				NUMANodeResources: []apiext.NUMANodeResource{
+					{Node: 1},
+					{Node: 0},
+				},
+			},
+			want: "0-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podAnnotations := map[string]string{
+				apiext.AnnotationResourceStatus: DumpJSON(tt.podAlloc),
+			}
+			got, err := GetCPUSetMemsFromPod(podAnnotations)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_GetPodWorkloadName(t *testing.T) {
+	newPod := func(ownerKind, ownerName string, podLabels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "test-pod",
+				Labels: podLabels,
+			},
+		}
+		if ownerKind != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{
+				{Kind: ownerKind, Name: ownerName, Controller: pointer.Bool(true)},
+			}
+		}
+		return pod
+	}
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		wantKind string
+		want     string
+	}{
+		{
+			name:     "deployment",
+			pod:      newPod("ReplicaSet", "test-deploy-5d4f8b9c7", map[string]string{"pod-template-hash": "5d4f8b9c7"}),
+			wantKind: "Deployment",
+			want:     "test-deploy",
+		},
+		{
+			name:     "replicaset",
+			pod:      newPod("ReplicaSet", "test-rs", nil),
+			wantKind: "ReplicaSet",
+			want:     "test-rs",
+		},
+		{
+			name:     "cronjob",
+			pod:      newPod("Job", "test-cronjob-28291740", nil),
+			wantKind: "CronJob",
+			want:     "test-cronjob",
+		},
+		{
+			name:     "job",
+			pod:      newPod("Job", "test-job", nil),
+			wantKind: "Job",
+			want:     "test-job",
+		},
+		{
+			name:     "bare pod",
+			pod:      newPod("", "", nil),
+			wantKind: WorkloadKindPod,
+			want:     "test-pod",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantKind, GetPodWorkloadKind(tt.pod))
+			assert.Equal(t, tt.want, GetPodWorkloadName(tt.pod))
+		})
+	}
+}
@@ -660,3 +660,47 @@ func DoNotScheduleTaintsFilter(t *corev1.Taint) bool {
 	// PodToleratesNodeTaints is only interested in NoSchedule and NoExecute taints.
 	return t.Effect == corev1.TaintEffectNoSchedule || t.Effect == corev1.TaintEffectNoExecute
 }
+
+// NewImagePrePull returns the ImagePrePull pulling the images of the reservation template on the reserved node.
+// It returns nil if the reservation is not assigned or has no image to pull.
+func NewImagePrePull(r *schedulingv1alpha1.Reservation) *schedulingv1alpha1.ImagePrePull {
+	nodeName := GetReservationNodeName(r)
+	if nodeName == "" || r.Spec.Template == nil {
+		return nil
+	}
+	var images []string
+	seen := map[string]struct{}{}
+	for _, containers := range [][]corev1.Container{r.Spec.Template.Spec.InitContainers, r.Spec.Template.Spec.Containers} {
+		for _, c := range containers {
+			if _, ok := seen[c.Image]; ok || c.Image == "" {
+				continue
+			}
+			seen[c.Image] = struct{}{}
+			images = append(images, c.Image)
+		}
+	}
+	if len(images) <= 0 {
+		return nil
+	}
+	return &schedulingv1alpha1.ImagePrePull{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: r.Name,
+			Labels: map[string]string{
+				schedulingv1alpha1.LabelImagePrePullNodeName: nodeName,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(r, schedulingv1alpha1.SchemeGroupVersion.WithKind("Reservation")),
+			},
+		},
+		Spec: schedulingv1alpha1.ImagePrePullSpec{
+			NodeName: nodeName,
+			Images:   images,
+			ReservationRef: &corev1.ObjectReference{
+				APIVersion: schedulingv1alpha1.SchemeGroupVersion.String(),
+				Kind:       "Reservation",
+				Name:       r.Name,
+				UID:        r.UID,
+			},
+		},
+	}
+}
@@ -1047,3 +1047,39 @@ func TestNewReservationReason(t *testing.T) {
 		assert.True(t, IsReservationReason(got))
 	})
 }
+
+func TestNewImagePrePull(t *testing.T) {
+	r := &schedulingv1alpha1.Reservation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "reservation-1",
+			UID:  "reservation-uid-1",
+		},
+		Spec: schedulingv1alpha1.ReservationSpec{
+			Template: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{{Name: "init", Image: "busybox:latest"}},
+					Containers: []corev1.Container{
+						{Name: "main", Image: "nginx:1.25"},
+						{Name: "sidecar", Image: "busybox:latest"},
+					},
+				},
+			},
+		},
+	}
+	assert.Nil(t, NewImagePrePull(r), "unassigned reservation")
+
+	r.Status.NodeName = "test-node"
+	got := NewImagePrePull(r)
+	assert.NotNil(t, got)
+	assert.Equal(t, "reservation-1", got.Name)
+	assert.Equal(t, "test-node", got.Labels[schedulingv1alpha1.LabelImagePrePullNodeName])
+	assert.Equal(t, "test-node", got.Spec.NodeName)
+	assert.Equal(t, []string{"busybox:latest", "nginx:1.25"}, got.Spec.Images)
+	assert.Equal(t, r.UID, got.Spec.ReservationRef.UID)
+	assert.Len(t, got.OwnerReferences, 1)
+	assert.Equal(t, "Reservation", got.OwnerReferences[0].Kind)
+
+	r.Spec.Template.Spec.InitContainers = nil
+	r.Spec.Template.Spec.Containers = []corev1.Container{{Name: "main"}}
+	assert.Nil(t, NewImagePrePull(r), "no image")
+}
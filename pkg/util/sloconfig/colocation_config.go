@@ -88,7 +88,9 @@ func IsColocationStrategyValid(strategy *configuration.ColocationStrategy) bool
 		(strategy.MetricMemoryCollectPolicy == nil || len(*strategy.MetricMemoryCollectPolicy) > 0) &&
 		(strategy.MidCPUThresholdPercent == nil || (*strategy.MidCPUThresholdPercent >= 0 && *strategy.MidCPUThresholdPercent <= 100)) &&
 		(strategy.MidMemoryThresholdPercent == nil || (*strategy.MidMemoryThresholdPercent >= 0 && *strategy.MidMemoryThresholdPercent <= 100)) &&
-		(strategy.MidUnallocatedPercent == nil || (*strategy.MidUnallocatedPercent >= 0 && *strategy.MidUnallocatedPercent <= 100))
+		(strategy.MidUnallocatedPercent == nil || (*strategy.MidUnallocatedPercent >= 0 && *strategy.MidUnallocatedPercent <= 100)) &&
+		(strategy.ShortLivedPodThresholdSeconds == nil || *strategy.ShortLivedPodThresholdSeconds >= 0) &&
+		(strategy.ShortLivedPodAccountingPercent == nil || (*strategy.ShortLivedPodAccountingPercent >= 0 && *strategy.ShortLivedPodAccountingPercent <= 100))
 }
 
 func IsNodeColocationCfgValid(nodeCfg *configuration.NodeColocationCfg) bool {
@@ -565,6 +565,17 @@ func Test_IsColocationStrategyValid(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "invalid short-lived pod accounting percent",
+			args: args{
+				strategy: &configuration.ColocationStrategy{
+					Enable:                         pointer.Bool(true),
+					ShortLivedPodThresholdSeconds:  pointer.Int64(60),
+					ShortLivedPodAccountingPercent: pointer.Int64(120),
+				},
+			},
+			want: false,
+		},
 		{
 			name: "partial strategy is valid 1",
 			args: args{
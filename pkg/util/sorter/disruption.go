@@ -0,0 +1,85 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sorter
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1listers "k8s.io/client-go/listers/policy/v1"
+)
+
+// DisruptionBudgetGetter returns the remaining disruptions allowed of the owner of the pod.
+// It returns false if the pod is not covered by any disruption budget.
+type DisruptionBudgetGetter func(pod *corev1.Pod) (int32, bool)
+
+var (
+	disruptionBudgetLock   sync.RWMutex
+	disruptionBudgetGetter DisruptionBudgetGetter
+)
+
+// SetDisruptionBudgetGetter sets the getter used by DisruptionBudget. The victims are not ordered by the
+// disruption budgets if the getter is nil.
+func SetDisruptionBudgetGetter(getter DisruptionBudgetGetter) {
+	disruptionBudgetLock.Lock()
+	defer disruptionBudgetLock.Unlock()
+	disruptionBudgetGetter = getter
+}
+
+func getDisruptionBudget(pod *corev1.Pod) (int32, bool) {
+	disruptionBudgetLock.RLock()
+	defer disruptionBudgetLock.RUnlock()
+	if disruptionBudgetGetter == nil {
+		return 0, false
+	}
+	return disruptionBudgetGetter(pod)
+}
+
+// DisruptionBudget compares the pods by the remaining disruptions allowed of their owners, where the pods without
+// any disruption budget are placed first and the pods with more disruptions allowed are placed before the fewer.
+func DisruptionBudget(p1, p2 *corev1.Pod) int {
+	allowed1, found1 := getDisruptionBudget(p1)
+	allowed2, found2 := getDisruptionBudget(p2)
+	if !found1 || !found2 {
+		return cmpBool(!found1, !found2)
+	}
+	if allowed1 == allowed2 {
+		return 0
+	}
+	if allowed1 > allowed2 {
+		return -1
+	}
+	return 1
+}
+
+// NewPDBDisruptionBudgetGetter returns a DisruptionBudgetGetter by the PodDisruptionBudgets, which returns the minimum
+// disruptions allowed of the PodDisruptionBudgets matching the pod.
+func NewPDBDisruptionBudgetGetter(lister policyv1listers.PodDisruptionBudgetLister) DisruptionBudgetGetter {
+	return func(pod *corev1.Pod) (int32, bool) {
+		pdbs, err := lister.GetPodPodDisruptionBudgets(pod)
+		if err != nil || len(pdbs) <= 0 {
+			return 0, false
+		}
+		allowed := pdbs[0].Status.DisruptionsAllowed
+		for _, pdb := range pdbs[1:] {
+			if pdb.Status.DisruptionsAllowed < allowed {
+				allowed = pdb.Status.DisruptionsAllowed
+			}
+		}
+		return allowed, true
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sorter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1listers "k8s.io/client-go/listers/policy/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestDisruptionBudget(t *testing.T) {
+	creationTime := time.Now()
+	withApp := func(app string) podDecoratorFn {
+		return func(pod *corev1.Pod) {
+			pod.Labels["app"] = app
+		}
+	}
+	makePDB := func(name, app string, disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": app},
+				},
+			},
+			Status: policyv1.PodDisruptionBudgetStatus{
+				DisruptionsAllowed: disruptionsAllowed,
+			},
+		}
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	assert.NoError(t, indexer.Add(makePDB("pdb-1", "app-1", 1)))
+	assert.NoError(t, indexer.Add(makePDB("pdb-2", "app-2", 3)))
+	assert.NoError(t, indexer.Add(makePDB("pdb-3", "app-2", 2)))
+
+	// the pods are otherwise equal except the disruption budgets
+	newPods := func() []*corev1.Pod {
+		return []*corev1.Pod{
+			makePod("test-1", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withApp("app-3")),
+			makePod("test-2", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withApp("app-2")),
+			makePod("test-3", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withApp("app-1")),
+		}
+	}
+	getNames := func(pods []*corev1.Pod) []string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	getter := NewPDBDisruptionBudgetGetter(policyv1listers.NewPodDisruptionBudgetLister(indexer))
+	pods := newPods()
+	allowed, found := getter(pods[1])
+	assert.True(t, found)
+	assert.Equal(t, int32(2), allowed)
+	_, found = getter(pods[0])
+	assert.False(t, found)
+
+	// no disruption budget is considered without the getter
+	pods = newPods()
+	VictimSorter().Sort(pods)
+	assert.Equal(t, []string{"test-3", "test-2", "test-1"}, getNames(pods))
+
+	// the pods without budget are placed first, then the pods with more disruptions allowed
+	SetDisruptionBudgetGetter(getter)
+	defer SetDisruptionBudgetGetter(nil)
+
+	pods = newPods()
+	VictimSorter().Sort(pods)
+	assert.Equal(t, []string{"test-1", "test-2", "test-3"}, getNames(pods))
+
+	pods = newPods()
+	PodSorter().Sort(pods)
+	assert.Equal(t, []string{"test-1", "test-2", "test-3"}, getNames(pods))
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package sorter
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/types"
@@ -64,6 +66,14 @@ func Priority(p1, p2 *corev1.Pod) int {
 	return -1
 }
 
+// SpecifiedPriority compares pods by Priority only if both pods have specified the priority
+func SpecifiedPriority(p1, p2 *corev1.Pod) int {
+	if p1.Spec.Priority == nil || p2.Spec.Priority == nil {
+		return 0
+	}
+	return Priority(p1, p2)
+}
+
 // KubernetesQoSClass compares pods by Kubernetes QosClass
 func KubernetesQoSClass(p1, p2 *corev1.Pod) int {
 	qos1 := k8sQoSClassOrder[util.GetKubeQosClass(p1)]
@@ -125,6 +135,17 @@ func PodUsage(resourcesThatExceedThresholds map[corev1.ResourceName]resource.Qua
 	}
 }
 
+// PodNameReversed compares the pods by the name in the reverse order
+func PodNameReversed(p1, p2 *corev1.Pod) int {
+	if p1.Name == p2.Name {
+		return 0
+	}
+	if p1.Name > p2.Name {
+		return -1
+	}
+	return 1
+}
+
 // PodCreationTimestamp compares the pods by the creation timestamp
 func PodCreationTimestamp(p1, p2 *corev1.Pod) int {
 	if p1.CreationTimestamp.Equal(&p2.CreationTimestamp) {
@@ -160,16 +181,38 @@ func EvictionCost(p1, p2 *corev1.Pod) int {
 	return -1
 }
 
+var timeNow = time.Now
+
+// CompletionProtection compares the pods by the run-to-completion protection, and the pods inside the protection
+// window are sorted after the others. The defaultWindow is used for the pods which declare no window of their own.
+func CompletionProtection(defaultWindow time.Duration) CompareFn {
+	return func(p1, p2 *corev1.Pod) int {
+		now := timeNow()
+		protected1 := extension.IsPodCompletionProtected(p1, defaultWindow, now)
+		protected2 := extension.IsPodCompletionProtected(p2, defaultWindow, now)
+		if protected1 == protected2 {
+			return 0
+		}
+		if protected1 {
+			return 1
+		}
+		return -1
+	}
+}
+
 func PodSorter(cmp ...CompareFn) *MultiSorter {
 	comparators := []CompareFn{
 		KoordinatorPriorityClass,
 		Priority,
 		KubernetesQoSClass,
 		KoordinatorQoSClass,
+		CompletionProtection(0),
+		DisruptionBudget,
 		PodDeletionCost,
 		EvictionCost,
 	}
 	comparators = append(comparators, cmp...)
+	comparators = append(comparators, VictimRuleCompareFns()...)
 	comparators = append(comparators, PodCreationTimestamp)
 	return OrderedBy(comparators...)
 }
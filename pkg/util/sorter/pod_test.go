@@ -198,3 +198,43 @@ func TestSortPods2(t *testing.T) {
 	}
 	assert.Equal(t, expectedPodsOrder, podsOrder)
 }
+
+func TestCompletionProtection(t *testing.T) {
+	now := time.Now()
+	defer func() { timeNow = time.Now }()
+	timeNow = func() time.Time { return now }
+
+	withCompletionTime := func(d time.Duration) podDecoratorFn {
+		return func(pod *corev1.Pod) {
+			pod.Annotations[extension.AnnotationExpectedCompletionTime] = now.Add(d).Format(time.RFC3339)
+		}
+	}
+	withNearCompletion := func(pod *corev1.Pod) {
+		pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+			Type:   extension.PodConditionNearCompletion,
+			Status: corev1.ConditionTrue,
+		})
+	}
+	pods := []*corev1.Pod{
+		makePod("test-1", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, now, withNearCompletion),
+		makePod("test-2", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, now, withCompletionTime(5*time.Minute)),
+		makePod("test-3", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, now, withCompletionTime(time.Hour)),
+		makePod("test-4", extension.PriorityBatchValueMin, extension.QoSBE, corev1.PodQOSBestEffort, now),
+	}
+	OrderedBy(CompletionProtection(10*time.Minute), Reverse(PodNameReversed)).Sort(pods)
+	expectedPodsOrder := []string{"test-3", "test-4", "test-1", "test-2"}
+	var podsOrder []string
+	for _, v := range pods {
+		podsOrder = append(podsOrder, v.Name)
+	}
+	assert.Equal(t, expectedPodsOrder, podsOrder)
+
+	// without the default window, only the pods reporting near completion are protected
+	OrderedBy(CompletionProtection(0), Reverse(PodNameReversed)).Sort(pods)
+	expectedPodsOrder = []string{"test-2", "test-3", "test-4", "test-1"}
+	podsOrder = nil
+	for _, v := range pods {
+		podsOrder = append(podsOrder, v.Name)
+	}
+	assert.Equal(t, expectedPodsOrder, podsOrder)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sorter
+
+import (
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// VictimFilterFn returns a non-nil error if the pod must never be chosen as an eviction victim.
+type VictimFilterFn func(pod *corev1.Pod) error
+
+// VictimRule is a custom rule shared by the koordlet evictors and the descheduler.
+// Filter excludes pods from the victims, Compare orders the remaining victims.
+// Either of them can be nil.
+type VictimRule struct {
+	Name    string
+	Filter  VictimFilterFn
+	Compare CompareFn
+}
+
+var (
+	victimRulesLock sync.RWMutex
+	victimRules     []*VictimRule
+)
+
+// RegisterVictimRule registers a custom victim rule. The rules are applied in the registration order.
+func RegisterVictimRule(rule *VictimRule) error {
+	if rule == nil || rule.Name == "" {
+		return fmt.Errorf("victim rule must have a name")
+	}
+	victimRulesLock.Lock()
+	defer victimRulesLock.Unlock()
+	for _, r := range victimRules {
+		if r.Name == rule.Name {
+			return fmt.Errorf("victim rule %s is conflict since already registered", rule.Name)
+		}
+	}
+	victimRules = append(victimRules, rule)
+	klog.V(1).Infof("victim rule %s is registered", rule.Name)
+	return nil
+}
+
+// UnregisterVictimRule removes the victim rule with the given name.
+func UnregisterVictimRule(name string) {
+	victimRulesLock.Lock()
+	defer victimRulesLock.Unlock()
+	for i, r := range victimRules {
+		if r.Name == name {
+			victimRules = append(victimRules[:i], victimRules[i+1:]...)
+			return
+		}
+	}
+}
+
+// VictimRuleCompareFns returns the CompareFns of the registered victim rules.
+func VictimRuleCompareFns() []CompareFn {
+	victimRulesLock.RLock()
+	defer victimRulesLock.RUnlock()
+	var fns []CompareFn
+	for _, r := range victimRules {
+		if r.Compare != nil {
+			fns = append(fns, r.Compare)
+		}
+	}
+	return fns
+}
+
+// CheckVictim returns an error if any registered victim rule refuses to evict the pod.
+func CheckVictim(pod *corev1.Pod) error {
+	victimRulesLock.RLock()
+	defer victimRulesLock.RUnlock()
+	for _, r := range victimRules {
+		if r.Filter == nil {
+			continue
+		}
+		if err := r.Filter(pod); err != nil {
+			return fmt.Errorf("victim rule %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+// FilterVictims returns the pods allowed to be evicted by the registered victim rules.
+func FilterVictims(pods []*corev1.Pod) []*corev1.Pod {
+	var victims []*corev1.Pod
+	for _, pod := range pods {
+		if err := CheckVictim(pod); err != nil {
+			klog.V(5).Infof("skip victim pod %s, reason: %v", klog.KObj(pod), err)
+			continue
+		}
+		victims = append(victims, pod)
+	}
+	return victims
+}
+
+// VictimSorter returns a MultiSorter which orders the victims by the given comparators, then by the disruption
+// budgets and the registered victim rules, and finally by the name in the reverse order.
+func VictimSorter(cmp ...CompareFn) *MultiSorter {
+	comparators := append([]CompareFn{}, cmp...)
+	comparators = append(comparators, DisruptionBudget)
+	comparators = append(comparators, VictimRuleCompareFns()...)
+	comparators = append(comparators, PodNameReversed)
+	return OrderedBy(comparators...)
+}
+
+// LabelValueFilter refuses to evict the pods whose label key has the given value,
+// e.g. LabelValueFilter("stateful", "true") never evicts the pods labeled stateful=true.
+func LabelValueFilter(key, value string) VictimFilterFn {
+	return func(pod *corev1.Pod) error {
+		if v, ok := pod.Labels[key]; ok && v == value {
+			return fmt.Errorf("pod has label %s=%s", key, value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sorter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestVictimRules(t *testing.T) {
+	creationTime := time.Now()
+	withLabel := func(key, value string) podDecoratorFn {
+		return func(pod *corev1.Pod) {
+			pod.Labels[key] = value
+		}
+	}
+	pods := []*corev1.Pod{
+		makePod("test-1", 0, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withLabel("stateful", "true")),
+		makePod("test-2", 0, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withLabel("tier", "2")),
+		makePod("test-3", 0, extension.QoSBE, corev1.PodQOSBestEffort, creationTime, withLabel("tier", "1")),
+	}
+
+	assert.Error(t, RegisterVictimRule(&VictimRule{}))
+	assert.NoError(t, RegisterVictimRule(&VictimRule{
+		Name:   "stateful",
+		Filter: LabelValueFilter("stateful", "true"),
+	}))
+	assert.NoError(t, RegisterVictimRule(&VictimRule{
+		Name: "tier",
+		Compare: func(p1, p2 *corev1.Pod) int {
+			if p1.Labels["tier"] == p2.Labels["tier"] {
+				return 0
+			}
+			if p1.Labels["tier"] < p2.Labels["tier"] {
+				return -1
+			}
+			return 1
+		},
+	}))
+	assert.Error(t, RegisterVictimRule(&VictimRule{Name: "stateful"}))
+	defer UnregisterVictimRule("stateful")
+	defer UnregisterVictimRule("tier")
+
+	assert.Error(t, CheckVictim(pods[0]))
+	assert.NoError(t, CheckVictim(pods[1]))
+
+	victims := FilterVictims(pods)
+	PodSorter().Sort(victims)
+	var names []string
+	for _, v := range victims {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{"test-3", "test-2"}, names)
+
+	UnregisterVictimRule("stateful")
+	assert.NoError(t, CheckVictim(pods[0]))
+	assert.Len(t, VictimRuleCompareFns(), 1)
+}
@@ -0,0 +1,20 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakekoordlet simulates the koordlet of the nodes for the integration tests of the scheduler and the
+// descheduler. A FakeKoordlet publishes the NodeMetric, Device and NodeResourceTopology of a node like the koordlet
+// does, and the reported usages can change over time according to the given UsageFunc.
+package fakekoordlet
@@ -0,0 +1,538 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakekoordlet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	topologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/pointer"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	koordclientset "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/cpuset"
+)
+
+const (
+	defaultReportInterval = 60 * time.Second
+)
+
+var (
+	aggregationTypes = []apiext.AggregationType{apiext.AVG, apiext.P50, apiext.P90, apiext.P95, apiext.P99}
+	percentiles      = map[apiext.AggregationType]float64{apiext.P50: 0.5, apiext.P90: 0.9, apiext.P95: 0.95, apiext.P99: 0.99}
+)
+
+// FakeKoordlet publishes the NodeMetric, Device and NodeResourceTopology of a node. The clients can be either the
+// fake clientsets or the ones connected to a real apiserver.
+type FakeKoordlet struct {
+	lock sync.Mutex
+
+	nodeName       string
+	koordClient    koordclientset.Interface
+	topologyClient topologyclientset.Interface
+	clock          clock.PassiveClock
+
+	topology           cpuTopologySpec
+	memoryPerNUMANode  resource.Quantity
+	gpus               []*gpuSpec
+	reportInterval     time.Duration
+	aggregateDurations []time.Duration
+
+	nodeUsage UsageFunc
+	podUsages map[types.NamespacedName]UsageFunc
+	// samples are the history of the node usages for the aggregated usages
+	samples []usageSample
+}
+
+type cpuTopologySpec struct {
+	sockets          int
+	nodesPerSocket   int
+	coresPerNUMANode int
+	threadsPerCore   int
+}
+
+func (t cpuTopologySpec) numaNodes() int {
+	return t.sockets * t.nodesPerSocket
+}
+
+func (t cpuTopologySpec) cpus() int {
+	return t.numaNodes() * t.coresPerNUMANode * t.threadsPerCore
+}
+
+type gpuSpec struct {
+	minor   int32
+	node    int32
+	memory  resource.Quantity
+	healthy bool
+}
+
+type usageSample struct {
+	time  time.Time
+	usage corev1.ResourceList
+}
+
+type Option func(k *FakeKoordlet)
+
+// WithClock sets the clock to get the report time and to evaluate the UsageFunc.
+func WithClock(c clock.PassiveClock) Option {
+	return func(k *FakeKoordlet) {
+		k.clock = c
+	}
+}
+
+// WithCPUTopology sets the cpu topology of the node. The cpu ids are numbered like the linux, where the hyper-threads
+// of a core are the cpus of the same id modulo the number of the cores.
+func WithCPUTopology(sockets, nodesPerSocket, coresPerNUMANode, threadsPerCore int) Option {
+	return func(k *FakeKoordlet) {
+		k.topology = cpuTopologySpec{
+			sockets:          sockets,
+			nodesPerSocket:   nodesPerSocket,
+			coresPerNUMANode: coresPerNUMANode,
+			threadsPerCore:   threadsPerCore,
+		}
+	}
+}
+
+// WithNUMAMemory sets the memory capacity of each NUMA node.
+func WithNUMAMemory(memory resource.Quantity) Option {
+	return func(k *FakeKoordlet) {
+		k.memoryPerNUMANode = memory
+	}
+}
+
+// WithGPUs adds the GPUs with the given memory, which are distributed to the NUMA nodes in turn.
+// NOTE: It should be set after the cpu topology.
+func WithGPUs(count int, memory resource.Quantity) Option {
+	return func(k *FakeKoordlet) {
+		k.gpus = nil
+		for i := 0; i < count; i++ {
+			k.gpus = append(k.gpus, &gpuSpec{
+				minor:   int32(i),
+				node:    int32(i % k.topology.numaNodes()),
+				memory:  memory,
+				healthy: true,
+			})
+		}
+	}
+}
+
+// WithNodeUsage sets the usage of the node.
+func WithNodeUsage(fn UsageFunc) Option {
+	return func(k *FakeKoordlet) {
+		k.nodeUsage = fn
+	}
+}
+
+// WithReportInterval sets the interval to publish in Run, which is also reported in the NodeMetric.
+func WithReportInterval(interval time.Duration) Option {
+	return func(k *FakeKoordlet) {
+		k.reportInterval = interval
+	}
+}
+
+// WithAggregateDurations enables the aggregated node usages in the given durations, which are calculated with the
+// node usages sampled in each sync.
+func WithAggregateDurations(durations ...time.Duration) Option {
+	return func(k *FakeKoordlet) {
+		k.aggregateDurations = durations
+	}
+}
+
+// New creates a fake koordlet of the node. By default, the node has 1 socket, 1 NUMA node, 4 cores with 2 threads,
+// 8Gi memory and no usage.
+func New(nodeName string, koordClient koordclientset.Interface, topologyClient topologyclientset.Interface, opts ...Option) *FakeKoordlet {
+	k := &FakeKoordlet{
+		nodeName:       nodeName,
+		koordClient:    koordClient,
+		topologyClient: topologyClient,
+		clock:          clock.RealClock{},
+		topology: cpuTopologySpec{
+			sockets:          1,
+			nodesPerSocket:   1,
+			coresPerNUMANode: 4,
+			threadsPerCore:   2,
+		},
+		memoryPerNUMANode: resource.MustParse("8Gi"),
+		reportInterval:    defaultReportInterval,
+		nodeUsage:         ConstantUsage(corev1.ResourceList{}),
+		podUsages:         map[types.NamespacedName]UsageFunc{},
+	}
+	for _, opt := range opts {
+		opt(k)
+	}
+	return k
+}
+
+// SetNodeUsage changes the usage of the node.
+func (k *FakeKoordlet) SetNodeUsage(fn UsageFunc) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.nodeUsage = fn
+}
+
+// SetPodUsage sets the usage of the pod running on the node.
+func (k *FakeKoordlet) SetPodUsage(namespace, name string, fn UsageFunc) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.podUsages[types.NamespacedName{Namespace: namespace, Name: name}] = fn
+}
+
+// DeletePodUsage removes the pod from the NodeMetric, e.g. the pod is terminated.
+func (k *FakeKoordlet) DeletePodUsage(namespace, name string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	delete(k.podUsages, types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// SetGPUHealth marks the GPU of the minor as healthy or not.
+func (k *FakeKoordlet) SetGPUHealth(minor int32, healthy bool) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	for _, gpu := range k.gpus {
+		if gpu.minor == minor {
+			gpu.healthy = healthy
+		}
+	}
+}
+
+// Run publishes the objects of the node every report interval until the context is done.
+func (k *FakeKoordlet) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := k.Sync(ctx); err != nil {
+			klog.Errorf("failed to sync fake koordlet of node %s, err: %v", k.nodeName, err)
+		}
+	}, k.reportInterval)
+}
+
+// Sync publishes the NodeResourceTopology, Device and NodeMetric of the node once.
+func (k *FakeKoordlet) Sync(ctx context.Context) error {
+	if err := k.syncNodeResourceTopology(ctx); err != nil {
+		return fmt.Errorf("failed to sync NodeResourceTopology, err: %w", err)
+	}
+	if err := k.syncDevice(ctx); err != nil {
+		return fmt.Errorf("failed to sync Device, err: %w", err)
+	}
+	if err := k.syncNodeMetric(ctx); err != nil {
+		return fmt.Errorf("failed to sync NodeMetric, err: %w", err)
+	}
+	return nil
+}
+
+func (k *FakeKoordlet) syncNodeResourceTopology(ctx context.Context) error {
+	nrt, err := k.NodeResourceTopology()
+	if err != nil {
+		return err
+	}
+	client := k.topologyClient.TopologyV1alpha1().NodeResourceTopologies()
+	old, err := client.Get(ctx, k.nodeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = client.Create(ctx, nrt, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	nrt.ResourceVersion = old.ResourceVersion
+	_, err = client.Update(ctx, nrt, metav1.UpdateOptions{})
+	return err
+}
+
+func (k *FakeKoordlet) syncDevice(ctx context.Context) error {
+	device := k.Device()
+	if device == nil {
+		return nil
+	}
+	client := k.koordClient.SchedulingV1alpha1().Devices()
+	old, err := client.Get(ctx, k.nodeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = client.Create(ctx, device, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	device.ResourceVersion = old.ResourceVersion
+	_, err = client.Update(ctx, device, metav1.UpdateOptions{})
+	return err
+}
+
+func (k *FakeKoordlet) syncNodeMetric(ctx context.Context) error {
+	nodeMetric := k.NodeMetric()
+	client := k.koordClient.SloV1alpha1().NodeMetrics()
+	old, err := client.Get(ctx, k.nodeName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// the status is ignored in the creation when the status subresource is enabled
+		old, err = client.Create(ctx, nodeMetric.DeepCopy(), metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	updated := old.DeepCopy()
+	updated.Status = nodeMetric.Status
+	_, err = client.UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// NodeResourceTopology builds the NodeResourceTopology of the node with the cpu topology and the NUMA zones.
+func (k *FakeKoordlet) NodeResourceTopology() (*topologyv1alpha1.NodeResourceTopology, error) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	cpuTopology, sharePools := k.buildCPUTopology()
+	cpuTopologyData, err := json.Marshal(cpuTopology)
+	if err != nil {
+		return nil, err
+	}
+	sharePoolsData, err := json.Marshal(sharePools)
+	if err != nil {
+		return nil, err
+	}
+
+	cpusPerNUMANode := int64(k.topology.coresPerNUMANode * k.topology.threadsPerCore)
+	zoneResourceList := map[string]corev1.ResourceList{}
+	for i := 0; i < k.topology.numaNodes(); i++ {
+		zoneResourceList[util.GenNodeZoneName(i)] = corev1.ResourceList{
+			corev1.ResourceCPU:    *resource.NewQuantity(cpusPerNUMANode, resource.DecimalSI),
+			corev1.ResourceMemory: k.memoryPerNUMANode.DeepCopy(),
+		}
+	}
+
+	return &topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: k.nodeName,
+			Labels: map[string]string{
+				apiext.LabelManagedBy: "Koordinator",
+			},
+			Annotations: map[string]string{
+				apiext.AnnotationNodeCPUTopology:    string(cpuTopologyData),
+				apiext.AnnotationNodeCPUSharedPools: string(sharePoolsData),
+			},
+		},
+		TopologyPolicies: []string{string(topologyv1alpha1.None)},
+		Zones:            util.ZoneResourceListToZoneList(zoneResourceList),
+	}, nil
+}
+
+func (k *FakeKoordlet) buildCPUTopology() (*apiext.CPUTopology, []apiext.CPUSharedPool) {
+	t := k.topology
+	totalCores := t.numaNodes() * t.coresPerNUMANode
+	cpuTopology := &apiext.CPUTopology{}
+	var sharePools []apiext.CPUSharedPool
+	for socket := 0; socket < t.sockets; socket++ {
+		for n := 0; n < t.nodesPerSocket; n++ {
+			node := socket*t.nodesPerSocket + n
+			var cpus []int
+			for c := 0; c < t.coresPerNUMANode; c++ {
+				core := node*t.coresPerNUMANode + c
+				for thread := 0; thread < t.threadsPerCore; thread++ {
+					cpus = append(cpus, thread*totalCores+core)
+				}
+			}
+			sort.Ints(cpus)
+			for _, cpu := range cpus {
+				cpuTopology.Detail = append(cpuTopology.Detail, apiext.CPUInfo{
+					ID:     int32(cpu),
+					Core:   int32(cpu % totalCores),
+					Socket: int32(socket),
+					Node:   int32(node),
+				})
+			}
+			sharePools = append(sharePools, apiext.CPUSharedPool{
+				Socket: int32(socket),
+				Node:   int32(node),
+				CPUSet: cpuset.NewCPUSet(cpus...).String(),
+			})
+		}
+	}
+	sort.Slice(cpuTopology.Detail, func(i, j int) bool {
+		return cpuTopology.Detail[i].ID < cpuTopology.Detail[j].ID
+	})
+	return cpuTopology, sharePools
+}
+
+// Device builds the Device of the node. It returns nil if the node has no device.
+func (k *FakeKoordlet) Device() *schedulingv1alpha1.Device {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if len(k.gpus) <= 0 {
+		return nil
+	}
+
+	device := &schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: k.nodeName,
+		},
+	}
+	for _, gpu := range k.gpus {
+		device.Spec.Devices = append(device.Spec.Devices, schedulingv1alpha1.DeviceInfo{
+			UUID:   fmt.Sprintf("GPU-%s-%d", k.nodeName, gpu.minor),
+			Minor:  pointer.Int32(gpu.minor),
+			Type:   schedulingv1alpha1.GPU,
+			Health: gpu.healthy,
+			Resources: corev1.ResourceList{
+				apiext.ResourceGPUCore:        *resource.NewQuantity(100, resource.DecimalSI),
+				apiext.ResourceGPUMemory:      gpu.memory.DeepCopy(),
+				apiext.ResourceGPUMemoryRatio: *resource.NewQuantity(100, resource.DecimalSI),
+			},
+			Topology: &schedulingv1alpha1.DeviceTopology{
+				SocketID: -1,
+				NodeID:   gpu.node,
+				PCIEID:   fmt.Sprintf("pci%d", gpu.node),
+				BusID:    fmt.Sprintf("0000:%02x:00.0", gpu.minor+1),
+			},
+		})
+	}
+	return device
+}
+
+// NodeMetric builds the NodeMetric of the node with the usages at the current time. The node usage is also sampled
+// for the aggregated usages.
+func (k *FakeKoordlet) NodeMetric() *slov1alpha1.NodeMetric {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	now := k.clock.Now()
+	nodeUsage := k.nodeUsage(now)
+	k.addSample(now, nodeUsage)
+
+	systemUsage := nodeUsage.DeepCopy()
+	podsMetric := make([]*slov1alpha1.PodMetricInfo, 0, len(k.podUsages))
+	for key, fn := range k.podUsages {
+		podUsage := fn(now)
+		podsMetric = append(podsMetric, &slov1alpha1.PodMetricInfo{
+			Namespace: key.Namespace,
+			Name:      key.Name,
+			PodUsage:  slov1alpha1.ResourceMap{ResourceList: podUsage},
+		})
+		for name, q := range podUsage {
+			if used, ok := systemUsage[name]; ok {
+				used.Sub(q)
+				systemUsage[name] = used
+			}
+		}
+	}
+	sort.Slice(podsMetric, func(i, j int) bool {
+		if podsMetric[i].Namespace != podsMetric[j].Namespace {
+			return podsMetric[i].Namespace < podsMetric[j].Namespace
+		}
+		return podsMetric[i].Name < podsMetric[j].Name
+	})
+	for name, q := range systemUsage {
+		if q.Sign() < 0 {
+			systemUsage[name] = newQuantity(name, 0)
+		}
+	}
+
+	return &slov1alpha1.NodeMetric{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: k.nodeName,
+		},
+		Spec: slov1alpha1.NodeMetricSpec{
+			CollectPolicy: &slov1alpha1.NodeMetricCollectPolicy{
+				ReportIntervalSeconds: pointer.Int64(int64(k.reportInterval.Seconds())),
+			},
+		},
+		Status: slov1alpha1.NodeMetricStatus{
+			UpdateTime: &metav1.Time{Time: now},
+			NodeMetric: &slov1alpha1.NodeMetricInfo{
+				NodeUsage:            slov1alpha1.ResourceMap{ResourceList: nodeUsage},
+				SystemUsage:          slov1alpha1.ResourceMap{ResourceList: systemUsage},
+				AggregatedNodeUsages: k.aggregateUsages(now),
+			},
+			PodsMetric: podsMetric,
+		},
+	}
+}
+
+func (k *FakeKoordlet) addSample(now time.Time, usage corev1.ResourceList) {
+	if len(k.aggregateDurations) <= 0 {
+		return
+	}
+	k.samples = append(k.samples, usageSample{time: now, usage: usage.DeepCopy()})
+	var maxDuration time.Duration
+	for _, d := range k.aggregateDurations {
+		if d > maxDuration {
+			maxDuration = d
+		}
+	}
+	// drop the samples out of all durations
+	idx := 0
+	for idx < len(k.samples) && now.Sub(k.samples[idx].time) > maxDuration {
+		idx++
+	}
+	k.samples = k.samples[idx:]
+}
+
+func (k *FakeKoordlet) aggregateUsages(now time.Time) []slov1alpha1.AggregatedUsage {
+	var aggregatedUsages []slov1alpha1.AggregatedUsage
+	for _, d := range k.aggregateDurations {
+		values := map[corev1.ResourceName][]int64{}
+		for _, sample := range k.samples {
+			if now.Sub(sample.time) > d {
+				continue
+			}
+			for name, q := range sample.usage {
+				values[name] = append(values[name], q.MilliValue())
+			}
+		}
+		if len(values) <= 0 {
+			continue
+		}
+		usage := map[apiext.AggregationType]slov1alpha1.ResourceMap{}
+		for _, aggregationType := range aggregationTypes {
+			usage[aggregationType] = slov1alpha1.ResourceMap{ResourceList: corev1.ResourceList{}}
+		}
+		for name, v := range values {
+			sort.Slice(v, func(i, j int) bool { return v[i] < v[j] })
+			var sum int64
+			for _, value := range v {
+				sum += value
+			}
+			usage[apiext.AVG].ResourceList[name] = newQuantity(name, sum/int64(len(v)))
+			for aggregationType, p := range percentiles {
+				idx := int(math.Ceil(p*float64(len(v)))) - 1
+				if idx < 0 {
+					idx = 0
+				}
+				usage[aggregationType].ResourceList[name] = newQuantity(name, v[idx])
+			}
+		}
+		aggregatedUsages = append(aggregatedUsages, slov1alpha1.AggregatedUsage{
+			Usage:    usage,
+			Duration: metav1.Duration{Duration: d},
+		})
+	}
+	return aggregatedUsages
+}
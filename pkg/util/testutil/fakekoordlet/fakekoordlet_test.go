@@ -0,0 +1,153 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakekoordlet
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	faketopologyclientset "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/generated/clientset/versioned/fake"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	apiext "github.com/koordinator-sh/koordinator/apis/extension"
+	koordfake "github.com/koordinator-sh/koordinator/pkg/client/clientset/versioned/fake"
+)
+
+func TestFakeKoordlet_Sync(t *testing.T) {
+	ctx := context.TODO()
+	koordClient := koordfake.NewSimpleClientset()
+	topologyClient := faketopologyclientset.NewSimpleClientset()
+	fakeClock := clocktesting.NewFakeClock(time.Unix(1700000000, 0))
+	k := New("test-node", koordClient, topologyClient,
+		WithClock(fakeClock),
+		WithCPUTopology(2, 1, 2, 2),
+		WithNUMAMemory(resource.MustParse("16Gi")),
+		WithGPUs(2, resource.MustParse("80Gi")),
+		WithNodeUsage(ConstantUsage(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		})),
+		WithReportInterval(30*time.Second))
+	k.SetPodUsage("default", "test-pod", ConstantUsage(corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("1"),
+		corev1.ResourceMemory: resource.MustParse("2Gi"),
+	}))
+
+	assert.NoError(t, k.Sync(ctx))
+
+	nrt, err := topologyClient.TopologyV1alpha1().NodeResourceTopologies().Get(ctx, "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, nrt.Zones, 2)
+	cpuTopology, err := apiext.GetCPUTopology(nrt.Annotations)
+	assert.NoError(t, err)
+	assert.Len(t, cpuTopology.Detail, 8)
+	// the hyper-threads of core 0 are cpu 0 and cpu 4
+	assert.Equal(t, apiext.CPUInfo{ID: 4, Core: 0, Socket: 0, Node: 0}, cpuTopology.Detail[4])
+	sharePools, err := apiext.GetNodeCPUSharePools(nrt.Annotations)
+	assert.NoError(t, err)
+	assert.Equal(t, []apiext.CPUSharedPool{
+		{Socket: 0, Node: 0, CPUSet: "0-1,4-5"},
+		{Socket: 1, Node: 1, CPUSet: "2-3,6-7"},
+	}, sharePools)
+
+	device, err := koordClient.SchedulingV1alpha1().Devices().Get(ctx, "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, device.Spec.Devices, 2)
+	assert.Equal(t, int32(1), device.Spec.Devices[1].Topology.NodeID)
+
+	nodeMetric, err := koordClient.SloV1alpha1().NodeMetrics().Get(ctx, "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(30), *nodeMetric.Spec.CollectPolicy.ReportIntervalSeconds)
+	assert.Equal(t, fakeClock.Now().Unix(), nodeMetric.Status.UpdateTime.Unix())
+	assert.Equal(t, int64(4000), nodeMetric.Status.NodeMetric.NodeUsage.Cpu().MilliValue())
+	assert.Equal(t, int64(3000), nodeMetric.Status.NodeMetric.SystemUsage.Cpu().MilliValue())
+	assert.Len(t, nodeMetric.Status.PodsMetric, 1)
+
+	// the updates are published in the next sync
+	fakeClock.Step(30 * time.Second)
+	k.SetGPUHealth(1, false)
+	k.DeletePodUsage("default", "test-pod")
+	assert.NoError(t, k.Sync(ctx))
+	device, err = koordClient.SchedulingV1alpha1().Devices().Get(ctx, "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.False(t, device.Spec.Devices[1].Health)
+	nodeMetric, err = koordClient.SloV1alpha1().NodeMetrics().Get(ctx, "test-node", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, fakeClock.Now().Unix(), nodeMetric.Status.UpdateTime.Unix())
+	assert.Len(t, nodeMetric.Status.PodsMetric, 0)
+}
+
+func TestFakeKoordlet_AggregatedUsages(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	fakeClock := clocktesting.NewFakeClock(start)
+	steps := []corev1.ResourceList{
+		{corev1.ResourceCPU: resource.MustParse("1")},
+		{corev1.ResourceCPU: resource.MustParse("2")},
+		{corev1.ResourceCPU: resource.MustParse("3")},
+		{corev1.ResourceCPU: resource.MustParse("6")},
+	}
+	k := New("test-node", nil, nil,
+		WithClock(fakeClock),
+		WithNodeUsage(StepUsage(start, time.Minute, steps...)),
+		WithAggregateDurations(2*time.Minute, 5*time.Minute))
+
+	var nodeUsages []int64
+	for i := 0; i < len(steps); i++ {
+		nodeMetric := k.NodeMetric()
+		nodeUsages = append(nodeUsages, nodeMetric.Status.NodeMetric.NodeUsage.Cpu().MilliValue())
+		if i == len(steps)-1 {
+			usages := nodeMetric.Status.NodeMetric.AggregatedNodeUsages
+			assert.Len(t, usages, 2)
+			// the last 2 minutes have 3 samples: 2, 3, 6
+			assert.Equal(t, 2*time.Minute, usages[0].Duration.Duration)
+			assert.Equal(t, int64(3666), cpuOf(usages[0].Usage[apiext.AVG].ResourceList))
+			assert.Equal(t, int64(3000), cpuOf(usages[0].Usage[apiext.P50].ResourceList))
+			assert.Equal(t, int64(6000), cpuOf(usages[0].Usage[apiext.P95].ResourceList))
+			assert.Equal(t, int64(3000), cpuOf(usages[1].Usage[apiext.AVG].ResourceList))
+		}
+		fakeClock.Step(time.Minute)
+	}
+	assert.Equal(t, []int64{1000, 2000, 3000, 6000}, nodeUsages)
+}
+
+func TestSineUsage(t *testing.T) {
+	fn := SineUsage(corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse("4"),
+		corev1.ResourceMemory: resource.MustParse("4Gi"),
+	}, corev1.ResourceList{
+		corev1.ResourceCPU: resource.MustParse("8"),
+	}, 4*time.Minute)
+	now := time.Unix(0, 0)
+	assert.Equal(t, int64(4000), cpuOf(fn(now)))
+	assert.Equal(t, int64(12000), cpuOf(fn(now.Add(time.Minute))))
+	// the usage is not less than zero
+	assert.Equal(t, int64(0), cpuOf(fn(now.Add(3*time.Minute))))
+	assert.Equal(t, int64(4*1024*1024*1024), memoryOf(fn(now.Add(time.Minute))))
+}
+
+func cpuOf(r corev1.ResourceList) int64 {
+	return r.Cpu().MilliValue()
+}
+
+func memoryOf(r corev1.ResourceList) int64 {
+	return r.Memory().Value()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakekoordlet
+
+import (
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// UsageFunc returns the resource usage at the given time.
+type UsageFunc func(now time.Time) corev1.ResourceList
+
+// ConstantUsage always returns the given usage.
+func ConstantUsage(usage corev1.ResourceList) UsageFunc {
+	return func(now time.Time) corev1.ResourceList {
+		return usage.DeepCopy()
+	}
+}
+
+// SineUsage fluctuates around the base usage by the amplitude in the given period, which simulates the diurnal
+// workloads. The usage is not less than zero.
+func SineUsage(base, amplitude corev1.ResourceList, period time.Duration) UsageFunc {
+	return func(now time.Time) corev1.ResourceList {
+		factor := 0.0
+		if period > 0 {
+			factor = math.Sin(2 * math.Pi * float64(now.UnixNano()%int64(period)) / float64(period))
+		}
+		usage := corev1.ResourceList{}
+		for name, q := range base {
+			a := amplitude[name]
+			v := float64(q.MilliValue()) + factor*float64(a.MilliValue())
+			usage[name] = newQuantity(name, int64(math.Max(v, 0)))
+		}
+		return usage
+	}
+}
+
+// StepUsage returns the usages in the order of steps, and each of them lasts for the given duration from the start.
+// The last usage is kept after all steps are passed.
+func StepUsage(start time.Time, duration time.Duration, steps ...corev1.ResourceList) UsageFunc {
+	return func(now time.Time) corev1.ResourceList {
+		if len(steps) <= 0 {
+			return corev1.ResourceList{}
+		}
+		idx := 0
+		if duration > 0 && now.After(start) {
+			idx = int(now.Sub(start) / duration)
+		}
+		if idx >= len(steps) {
+			idx = len(steps) - 1
+		}
+		return steps[idx].DeepCopy()
+	}
+}
+
+func newQuantity(name corev1.ResourceName, milliValue int64) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewMilliQuantity(milliValue, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(milliValue/1000, resource.BinarySI)
+}
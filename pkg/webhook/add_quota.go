@@ -33,4 +33,5 @@ func init() {
 	})
 
 	RegisterDebugAPIProvider("/elasticQuota", &validating.ElasticQuotaValidatingHandler{})
+	RegisterDebugAPIProvider("/elasticQuota/rebuild", &validating.QuotaTopologyRebuildHandler{})
 }
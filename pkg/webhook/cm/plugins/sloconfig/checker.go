@@ -75,6 +75,11 @@ func (c *CommonChecker) InitStatus() string {
 }
 
 func (c *CommonChecker) CheckByValidator(config interface{}) error {
+	return CheckByValidator(config)
+}
+
+// CheckByValidator checks the config with the validator tags of its fields.
+func CheckByValidator(config interface{}) error {
 	info, err := sloconfig.GetValidatorInstance().StructWithTrans(config)
 	if err != nil {
 		return err
@@ -74,7 +74,16 @@ func (c *ResourceQOSChecker) ConfigParamValid() error {
 		return err
 	}
 
-	return CheckNetQosCfg(c.cfg, c.sysCfg, field.NewPath("ResourceQOSCfg"))
+	fldPath := field.NewPath("ResourceQOSCfg")
+	if err := CheckResourceQOSStrategy(c.cfg.ClusterStrategy, fldPath.Child("clusterStrategy")); err != nil {
+		return err
+	}
+	for i, nodeStrategy := range c.cfg.NodeStrategies {
+		if err := CheckResourceQOSStrategy(nodeStrategy.ResourceQOSStrategy, fldPath.Child("nodeStrategies").Index(i)); err != nil {
+			return err
+		}
+	}
+	return CheckNetQosCfg(c.cfg, c.sysCfg, fldPath)
 }
 
 func CheckNetQosCfg(cfg *configuration.ResourceQOSCfg, sysCfg *configuration.SystemCfg, fldPath *field.Path) error {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/klog/v2"
 
 	"github.com/koordinator-sh/koordinator/apis/configuration"
@@ -47,7 +48,19 @@ func NewResourceThresholdChecker(oldConfig, newConfig *corev1.ConfigMap, needUnm
 }
 
 func (c *ResourceThresholdChecker) ConfigParamValid() error {
-	return c.CheckByValidator(c.cfg)
+	if err := c.CheckByValidator(c.cfg); err != nil {
+		return err
+	}
+	fldPath := field.NewPath("ResourceThresholdCfg")
+	if err := CheckResourceThresholdStrategy(c.cfg.ClusterStrategy, fldPath.Child("clusterStrategy")); err != nil {
+		return err
+	}
+	for i, nodeStrategy := range c.cfg.NodeStrategies {
+		if err := CheckResourceThresholdStrategy(nodeStrategy.ResourceThresholdStrategy, fldPath.Child("nodeStrategies").Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (c *ResourceThresholdChecker) initConfig() error {
@@ -0,0 +1,167 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sloconfig
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+// The checks below cover the semantics which cannot be expressed by the validator tags,
+// and the configs failing them are ignored by koordlet at runtime.
+
+// CheckResourceThresholdStrategy checks if the thresholds of the strategy are ordered.
+func CheckResourceThresholdStrategy(strategy *v1alpha1.ResourceThresholdStrategy, fldPath *field.Path) error {
+	if strategy == nil {
+		return nil
+	}
+	if strategy.CPUSuppressMinPercent != nil && strategy.CPUSuppressThresholdPercent != nil &&
+		*strategy.CPUSuppressMinPercent > *strategy.CPUSuppressThresholdPercent {
+		return buildParamInvalidError(fmt.Errorf("%s must not be larger than %s, min %d, threshold %d",
+			fldPath.Child("cpuSuppressMinPercent"), fldPath.Child("cpuSuppressThresholdPercent"),
+			*strategy.CPUSuppressMinPercent, *strategy.CPUSuppressThresholdPercent))
+	}
+	return nil
+}
+
+// CheckResourceQOSStrategy checks if the resctrl configs of the strategy can be applied by koordlet.
+func CheckResourceQOSStrategy(strategy *v1alpha1.ResourceQOSStrategy, fldPath *field.Path) error {
+	if strategy == nil {
+		return nil
+	}
+	for _, class := range getResctrlQOSClasses(strategy) {
+		name, resourceQOS := class.name, class.resourceQOS
+		if resourceQOS == nil || resourceQOS.ResctrlQOS == nil {
+			continue
+		}
+		if err := checkMBAPercent(resourceQOS.ResctrlQOS.MBAPercent, fldPath.Child(name, "resctrlQOS", "mbaPercent")); err != nil {
+			return err
+		}
+	}
+
+	tierNames := sets.NewString()
+	for i, tier := range strategy.ResctrlMBATiers {
+		tierPath := fldPath.Child("resctrlMBATiers").Index(i)
+		if len(tier.Name) <= 0 || strings.Contains(tier.Name, "/") {
+			return buildParamInvalidError(fmt.Errorf("%s is invalid, name %q", tierPath.Child("name"), tier.Name))
+		}
+		if tierNames.Has(tier.Name) {
+			return buildParamInvalidError(fmt.Errorf("%s is duplicated, name %q", tierPath.Child("name"), tier.Name))
+		}
+		tierNames.Insert(tier.Name)
+		if err := checkMBAPercent(tier.MBAPercent, tierPath.Child("mbaPercent")); err != nil {
+			return err
+		}
+	}
+
+	if adaptive := strategy.ResctrlMBAAdaptive; adaptive != nil && adaptive.MinMBAPercent != nil &&
+		adaptive.MaxMBAPercent != nil && *adaptive.MinMBAPercent > *adaptive.MaxMBAPercent {
+		adaptivePath := fldPath.Child("resctrlMBAAdaptive")
+		return buildParamInvalidError(fmt.Errorf("%s must not be larger than %s, min %d, max %d",
+			adaptivePath.Child("minMBAPercent"), adaptivePath.Child("maxMBAPercent"),
+			*adaptive.MinMBAPercent, *adaptive.MaxMBAPercent))
+	}
+
+	subPoolNames := sets.NewString()
+	var subPoolPercent int64
+	for i, subPool := range strategy.CPUSharedSubPools {
+		subPoolPath := fldPath.Child("cpuSharedSubPools").Index(i)
+		if len(subPool.Name) <= 0 || subPoolNames.Has(subPool.Name) {
+			return buildParamInvalidError(fmt.Errorf("%s is invalid or duplicated, name %q", subPoolPath.Child("name"), subPool.Name))
+		}
+		subPoolNames.Insert(subPool.Name)
+		if subPool.MinPriority > subPool.MaxPriority {
+			return buildParamInvalidError(fmt.Errorf("%s must not be larger than %s, min %d, max %d",
+				subPoolPath.Child("minPriority"), subPoolPath.Child("maxPriority"), subPool.MinPriority, subPool.MaxPriority))
+		}
+		subPoolPercent += subPool.CPUPercent
+	}
+	if subPoolPercent > 100 {
+		return buildParamInvalidError(fmt.Errorf("the sum of %s must not be larger than 100, current %d",
+			fldPath.Child("cpuSharedSubPools").Child("cpuPercent"), subPoolPercent))
+	}
+	return nil
+}
+
+type resctrlQOSClass struct {
+	name        string
+	resourceQOS *v1alpha1.ResourceQOS
+}
+
+// getResctrlQOSClasses returns the QoS classes which have the resctrl groups on the node.
+func getResctrlQOSClasses(strategy *v1alpha1.ResourceQOSStrategy) []resctrlQOSClass {
+	return []resctrlQOSClass{
+		{name: "lsrClass", resourceQOS: strategy.LSRClass},
+		{name: "lsClass", resourceQOS: strategy.LSClass},
+		{name: "beClass", resourceQOS: strategy.BEClass},
+	}
+}
+
+func checkMBAPercent(mbaPercent *int64, fldPath *field.Path) error {
+	if mbaPercent != nil && (*mbaPercent <= 0 || *mbaPercent > 100) {
+		return buildParamInvalidError(fmt.Errorf("%s must be in [1, 100], current %d", fldPath, *mbaPercent))
+	}
+	return nil
+}
+
+// CheckResctrlQOSForCatL3Cbm checks if the LLC ranges of the strategy are valid for the L3 cache bit mask reported
+// by the node, i.e. each range must own at least one cache way.
+func CheckResctrlQOSForCatL3Cbm(strategy *v1alpha1.ResourceQOSStrategy, cbmStr string, fldPath *field.Path) error {
+	if strategy == nil || len(cbmStr) <= 0 {
+		return nil
+	}
+	cbm, err := strconv.ParseUint(cbmStr, 16, 32)
+	if err != nil || bits.OnesCount64(cbm+1) != 1 {
+		// the cbm of the node is unknown, skip the check
+		return nil
+	}
+	ways := float64(bits.Len64(cbm))
+
+	for _, class := range getResctrlQOSClasses(strategy) {
+		name, resourceQOS := class.name, class.resourceQOS
+		if resourceQOS == nil || resourceQOS.ResctrlQOS == nil {
+			continue
+		}
+		resctrlQOS := resourceQOS.ResctrlQOS
+		// the code range is only used in the CDP mode
+		for _, r := range [][2]*int64{
+			{resctrlQOS.CATRangeStartPercent, resctrlQOS.CATRangeEndPercent},
+			{resctrlQOS.CATCodeRangeStartPercent, resctrlQOS.CATCodeRangeEndPercent},
+		} {
+			if r[0] == nil || r[1] == nil {
+				continue
+			}
+			start, end := *r[0], *r[1]
+			// keep consistent with the l3 mask calculation of koordlet
+			startWay := math.Ceil(ways * float64(start) / 100)
+			endWay := math.Ceil(ways * float64(end) / 100)
+			if endWay <= startWay {
+				return buildParamInvalidError(fmt.Errorf("%s owns no cache way of the l3 cbm %s, start %d, end %d",
+					fldPath.Child(name, "resctrlQOS"), cbmStr, start, end))
+			}
+		}
+	}
+	return nil
+}
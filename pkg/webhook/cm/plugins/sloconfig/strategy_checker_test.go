@@ -0,0 +1,206 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sloconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	"github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func Test_CheckResourceQOSStrategy(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy *v1alpha1.ResourceQOSStrategy
+		wantErr  bool
+	}{
+		{
+			name:     "nil strategy",
+			strategy: nil,
+		},
+		{
+			name: "valid mba",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				LSClass: &v1alpha1.ResourceQOS{
+					ResctrlQOS: &v1alpha1.ResctrlQOSCfg{ResctrlQOS: v1alpha1.ResctrlQOS{MBAPercent: pointer.Int64(100)}},
+				},
+				ResctrlMBATiers: []v1alpha1.ResctrlMBATier{
+					{Name: "gold", MBAPercent: pointer.Int64(80)},
+					{Name: "silver", MBAPercent: pointer.Int64(50)},
+				},
+			},
+		},
+		{
+			name: "zero mba is ignored by koordlet",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				BEClass: &v1alpha1.ResourceQOS{
+					ResctrlQOS: &v1alpha1.ResctrlQOSCfg{ResctrlQOS: v1alpha1.ResctrlQOS{MBAPercent: pointer.Int64(0)}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid tier name",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				ResctrlMBATiers: []v1alpha1.ResctrlMBATier{
+					{Name: "gold/a", MBAPercent: pointer.Int64(80)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicated tier name",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				ResctrlMBATiers: []v1alpha1.ResctrlMBATier{
+					{Name: "gold", MBAPercent: pointer.Int64(80)},
+					{Name: "gold", MBAPercent: pointer.Int64(50)},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid adaptive mba",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				ResctrlMBAAdaptive: &v1alpha1.ResctrlMBAAdaptiveStrategy{
+					Enable:                      pointer.Bool(true),
+					LSMemoryBandwidthTargetMBps: pointer.Int64(10000),
+					MinMBAPercent:               pointer.Int64(20),
+					MaxMBAPercent:               pointer.Int64(100),
+				},
+			},
+		},
+		{
+			name: "adaptive mba min larger than max",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				ResctrlMBAAdaptive: &v1alpha1.ResctrlMBAAdaptiveStrategy{
+					Enable:                      pointer.Bool(true),
+					LSMemoryBandwidthTargetMBps: pointer.Int64(10000),
+					MinMBAPercent:               pointer.Int64(60),
+					MaxMBAPercent:               pointer.Int64(40),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid cpu shared sub-pools",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				CPUSharedSubPools: []v1alpha1.CPUSharedSubPool{
+					{Name: "ls-high", MinPriority: 9500, MaxPriority: 9999, CPUPercent: 40},
+					{Name: "ls-low", MinPriority: 9000, MaxPriority: 9499, CPUPercent: 60},
+				},
+			},
+		},
+		{
+			name: "duplicated cpu shared sub-pool name",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				CPUSharedSubPools: []v1alpha1.CPUSharedSubPool{
+					{Name: "ls-high", MinPriority: 9500, MaxPriority: 9999, CPUPercent: 40},
+					{Name: "ls-high", MinPriority: 9000, MaxPriority: 9499, CPUPercent: 40},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cpu shared sub-pool min priority larger than max",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				CPUSharedSubPools: []v1alpha1.CPUSharedSubPool{
+					{Name: "ls-high", MinPriority: 9999, MaxPriority: 9500, CPUPercent: 40},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cpu shared sub-pools exceed the shared pool",
+			strategy: &v1alpha1.ResourceQOSStrategy{
+				CPUSharedSubPools: []v1alpha1.CPUSharedSubPool{
+					{Name: "ls-high", MinPriority: 9500, MaxPriority: 9999, CPUPercent: 60},
+					{Name: "ls-low", MinPriority: 9000, MaxPriority: 9499, CPUPercent: 60},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResourceQOSStrategy(tt.strategy, field.NewPath("test"))
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func Test_CheckResctrlQOSForCatL3Cbm(t *testing.T) {
+	newStrategy := func(start, end int64) *v1alpha1.ResourceQOSStrategy {
+		return &v1alpha1.ResourceQOSStrategy{
+			BEClass: &v1alpha1.ResourceQOS{
+				ResctrlQOS: &v1alpha1.ResctrlQOSCfg{ResctrlQOS: v1alpha1.ResctrlQOS{
+					CATRangeStartPercent: pointer.Int64(start),
+					CATRangeEndPercent:   pointer.Int64(end),
+				}},
+			},
+		}
+	}
+	tests := []struct {
+		name     string
+		strategy *v1alpha1.ResourceQOSStrategy
+		cbm      string
+		wantErr  bool
+	}{
+		{
+			name:     "unknown cbm",
+			strategy: newStrategy(10, 20),
+			cbm:      "",
+		},
+		{
+			name:     "invalid cbm",
+			strategy: newStrategy(10, 20),
+			cbm:      "f0",
+		},
+		{
+			name:     "range owns cache ways",
+			strategy: newStrategy(0, 30),
+			cbm:      "7ff",
+		},
+		{
+			name:     "range owns no cache way",
+			strategy: newStrategy(10, 20),
+			cbm:      "f",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResctrlQOSForCatL3Cbm(tt.strategy, tt.cbm, field.NewPath("test"))
+			assert.Equal(t, tt.wantErr, err != nil, err)
+		})
+	}
+}
+
+func Test_CheckResourceThresholdStrategy(t *testing.T) {
+	assert.NoError(t, CheckResourceThresholdStrategy(nil, field.NewPath("test")))
+	assert.NoError(t, CheckResourceThresholdStrategy(&v1alpha1.ResourceThresholdStrategy{
+		CPUSuppressThresholdPercent: pointer.Int64(65),
+		CPUSuppressMinPercent:       pointer.Int64(30),
+	}, field.NewPath("test")))
+	assert.Error(t, CheckResourceThresholdStrategy(&v1alpha1.ResourceThresholdStrategy{
+		CPUSuppressThresholdPercent: pointer.Int64(30),
+		CPUSuppressMinPercent:       pointer.Int64(65),
+	}, field.NewPath("test")))
+}
@@ -0,0 +1,295 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/metrics"
+	webhookutil "github.com/koordinator-sh/koordinator/pkg/webhook/util"
+)
+
+const (
+	validatorName = "DeviceValidator"
+
+	// mastersGroup is allowed to write the Device objects for the emergency fix
+	mastersGroup = "system:masters"
+)
+
+// +kubebuilder:rbac:groups=scheduling.koordinator.sh,resources=devices,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+
+type DeviceValidatingHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+
+	// writers are the users allowed to create or update the Device objects
+	writers sets.Set[string]
+}
+
+func NewDeviceValidatingHandler(c client.Client, d *admission.Decoder) *DeviceValidatingHandler {
+	handler := &DeviceValidatingHandler{
+		Client:  c,
+		Decoder: d,
+		writers: sets.New[string](webhookutil.GetDeviceWriters()...),
+	}
+	return handler
+}
+
+var _ admission.Handler = &DeviceValidatingHandler{}
+
+func ShouldIgnoreIfNotDevice(req admission.Request) bool {
+	// Ignore all calls to sub resources or resources other than devices.
+	if len(req.AdmissionRequest.SubResource) != 0 ||
+		req.AdmissionRequest.Resource.Resource != "devices" {
+		return true
+	}
+	return false
+}
+
+// Handle handles admission requests.
+func (h *DeviceValidatingHandler) Handle(ctx context.Context, req admission.Request) (resp admission.Response) {
+	klog.V(3).Infof("enter validating handler,type:%v,name:%v,user:%s", req.Kind, req.Name, req.UserInfo.Username)
+	if ShouldIgnoreIfNotDevice(req) || req.Operation == admissionv1.Delete {
+		return admission.ValidationResponse(true, "")
+	}
+
+	if !h.isAllowedWriter(req) {
+		klog.Warningf("Webhook reject %s device %s, user %s is not allowed", req.Operation, req.Name, req.UserInfo.Username)
+		return admission.ValidationResponse(false, fmt.Sprintf("user %s is not allowed to write devices", req.UserInfo.Username))
+	}
+
+	obj := &schedulingv1alpha1.Device{}
+	if err := h.Decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	defer func() {
+		if !resp.Allowed {
+			klog.Warningf("Webhook finish validating info device %s, allowed: %v, result: %v",
+				obj.Name, resp.Allowed, util.DumpJSON(resp.Result))
+		}
+	}()
+
+	start := time.Now()
+	err := h.validate(ctx, obj)
+	metrics.RecordWebhookDurationMilliseconds(metrics.ValidatingWebhook,
+		metrics.Device, string(req.Operation), err, validatorName, time.Since(start).Seconds())
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+func (h *DeviceValidatingHandler) isAllowedWriter(req admission.Request) bool {
+	if h.writers.Has(req.UserInfo.Username) {
+		return true
+	}
+	for _, group := range req.UserInfo.Groups {
+		if group == mastersGroup {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the devices are consistent, and the resources of the devices are not less than the allocated.
+// The removed devices are not checked since they can be lost on the node.
+func (h *DeviceValidatingHandler) validate(ctx context.Context, device *schedulingv1alpha1.Device) error {
+	fldPath := field.NewPath("spec", "devices")
+	if errs := validateDevices(device.Spec.Devices, fldPath); len(errs) > 0 {
+		return errs.ToAggregate()
+	}
+
+	allocated, err := h.getAllocatedResources(ctx, device.Name)
+	if err != nil {
+		return err
+	}
+	return validateAllocatedResources(device.Spec.Devices, allocated, fldPath).ToAggregate()
+}
+
+type deviceKey struct {
+	deviceType schedulingv1alpha1.DeviceType
+	minor      int32
+}
+
+func validateDevices(devices []schedulingv1alpha1.DeviceInfo, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	minors := map[deviceKey]int{}
+	busIDs := map[schedulingv1alpha1.DeviceType]map[string]int{}
+	// the numa node and the pcie switch belong to the only one upper level
+	numaSockets := map[int32]int32{}
+	pcieNUMAs := map[string]int32{}
+	for i := range devices {
+		d := &devices[i]
+		idxPath := fldPath.Index(i)
+		if d.Minor != nil {
+			key := deviceKey{deviceType: d.Type, minor: *d.Minor}
+			if *d.Minor < 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("minor"), *d.Minor, "must be non-negative"))
+			} else if j, ok := minors[key]; ok {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("minor"), fmt.Sprintf("%d, same as devices[%d]", *d.Minor, j)))
+			} else {
+				minors[key] = i
+			}
+		}
+		allErrs = append(allErrs, validateVFMinors(d.VFGroups, idxPath.Child("vfGroups"))...)
+
+		topology := d.Topology
+		if topology == nil {
+			continue
+		}
+		topologyPath := idxPath.Child("topology")
+		if topology.SocketID < -1 {
+			allErrs = append(allErrs, field.Invalid(topologyPath.Child("socketID"), topology.SocketID, "must be no less than -1"))
+		}
+		if topology.NodeID < -1 {
+			allErrs = append(allErrs, field.Invalid(topologyPath.Child("nodeID"), topology.NodeID, "must be no less than -1"))
+		}
+		if topology.BusID != "" {
+			if busIDs[d.Type] == nil {
+				busIDs[d.Type] = map[string]int{}
+			}
+			if j, ok := busIDs[d.Type][topology.BusID]; ok {
+				allErrs = append(allErrs, field.Duplicate(topologyPath.Child("busID"), fmt.Sprintf("%s, same as devices[%d]", topology.BusID, j)))
+			} else {
+				busIDs[d.Type][topology.BusID] = i
+			}
+		}
+		if topology.NodeID >= 0 && topology.SocketID >= 0 {
+			if socketID, ok := numaSockets[topology.NodeID]; ok && socketID != topology.SocketID {
+				allErrs = append(allErrs, field.Invalid(topologyPath.Child("socketID"), topology.SocketID,
+					fmt.Sprintf("numa node %d belongs to socket %d", topology.NodeID, socketID)))
+			} else {
+				numaSockets[topology.NodeID] = topology.SocketID
+			}
+		}
+		if topology.PCIEID != "" && topology.NodeID >= 0 {
+			if nodeID, ok := pcieNUMAs[topology.PCIEID]; ok && nodeID != topology.NodeID {
+				allErrs = append(allErrs, field.Invalid(topologyPath.Child("nodeID"), topology.NodeID,
+					fmt.Sprintf("pcie %s belongs to numa node %d", topology.PCIEID, nodeID)))
+			} else {
+				pcieNUMAs[topology.PCIEID] = topology.NodeID
+			}
+		}
+	}
+	return allErrs
+}
+
+func validateVFMinors(groups []schedulingv1alpha1.VirtualFunctionGroup, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	minors := sets.New[int32]()
+	for i, group := range groups {
+		for j, vf := range group.VFs {
+			if minors.Has(vf.Minor) {
+				allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("vfs").Index(j).Child("minor"), vf.Minor))
+				continue
+			}
+			minors.Insert(vf.Minor)
+		}
+	}
+	return allErrs
+}
+
+func validateAllocatedResources(devices []schedulingv1alpha1.DeviceInfo, allocated map[deviceKey]corev1.ResourceList, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for i := range devices {
+		d := &devices[i]
+		if d.Minor == nil {
+			continue
+		}
+		for resourceName, used := range allocated[deviceKey{deviceType: d.Type, minor: *d.Minor}] {
+			total := d.Resources[resourceName]
+			if total.Cmp(used) < 0 {
+				allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("resources").Key(string(resourceName)), total.String(),
+					fmt.Sprintf("less than the allocated %s", used.String())))
+			}
+		}
+	}
+	return allErrs
+}
+
+// getAllocatedResources returns the device resources allocated to the pods on the node.
+func (h *DeviceValidatingHandler) getAllocatedResources(ctx context.Context, nodeName string) (map[deviceKey]corev1.ResourceList, error) {
+	podList := &corev1.PodList{}
+	if err := h.Client.List(ctx, podList, &client.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list pods on node %s, err: %w", nodeName, err)
+	}
+	allocated := map[deviceKey]corev1.ResourceList{}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if util.IsPodTerminated(pod) {
+			continue
+		}
+		allocations, err := extension.GetDeviceAllocations(pod.Annotations)
+		if err != nil {
+			klog.V(4).Infof("failed to get device allocations of pod %s/%s, err: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for deviceType, deviceAllocations := range allocations {
+			for _, allocation := range deviceAllocations {
+				key := deviceKey{deviceType: deviceType, minor: allocation.Minor}
+				used := allocated[key]
+				if used == nil {
+					used = corev1.ResourceList{}
+					allocated[key] = used
+				}
+				for resourceName, quantity := range allocation.Resources {
+					sum := used[resourceName]
+					sum.Add(quantity)
+					used[resourceName] = sum
+				}
+			}
+		}
+	}
+	return allocated, nil
+}
+
+// var _ inject.Client = &DeviceValidatingHandler{}
+
+// InjectClient injects the client into the ValidatingHandler
+func (h *DeviceValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+// var _ admission.DecoderInjector = &DeviceValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ValidatingHandler
+func (h *DeviceValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
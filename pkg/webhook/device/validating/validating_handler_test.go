@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	schedulingv1alpha1 "github.com/koordinator-sh/koordinator/apis/scheduling/v1alpha1"
+)
+
+const testKoordletUser = "system:serviceaccount:koordinator-system:koordlet"
+
+func makeTestHandler(pods ...*corev1.Pod) *DeviceValidatingHandler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = schedulingv1alpha1.AddToScheme(scheme)
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithIndex(&corev1.Pod{}, "spec.nodeName", func(obj client.Object) []string {
+		return []string{obj.(*corev1.Pod).Spec.NodeName}
+	})
+	for _, pod := range pods {
+		builder = builder.WithObjects(pod)
+	}
+	return NewDeviceValidatingHandler(builder.Build(), admission.NewDecoder(scheme))
+}
+
+func makeDeviceRequest(t *testing.T, device *schedulingv1alpha1.Device, userInfo authenticationv1.UserInfo) admission.Request {
+	raw, err := json.Marshal(device)
+	assert.NoError(t, err)
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Resource: metav1.GroupVersionResource{
+				Group:    schedulingv1alpha1.GroupVersion.Group,
+				Version:  schedulingv1alpha1.GroupVersion.Version,
+				Resource: "devices",
+			},
+			Name:      device.Name,
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: raw},
+			UserInfo:  userInfo,
+		},
+	}
+}
+
+func makeGPU(minor int32, nodeID int32, pcie, busID string) schedulingv1alpha1.DeviceInfo {
+	return schedulingv1alpha1.DeviceInfo{
+		Type:   schedulingv1alpha1.GPU,
+		Minor:  pointer.Int32(minor),
+		Health: true,
+		Resources: corev1.ResourceList{
+			extension.ResourceGPUCore:   resource.MustParse("100"),
+			extension.ResourceGPUMemory: resource.MustParse("16Gi"),
+		},
+		Topology: &schedulingv1alpha1.DeviceTopology{
+			SocketID: 0,
+			NodeID:   nodeID,
+			PCIEID:   pcie,
+			BusID:    busID,
+		},
+	}
+}
+
+func makeDevice(devices ...schedulingv1alpha1.DeviceInfo) *schedulingv1alpha1.Device {
+	return &schedulingv1alpha1.Device{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Spec:       schedulingv1alpha1.DeviceSpec{Devices: devices},
+	}
+}
+
+func TestDeviceValidatingHandler_Handle(t *testing.T) {
+	allocatedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-pod",
+			Annotations: map[string]string{
+				extension.AnnotationDeviceAllocated: `{"gpu":[{"minor":1,"resources":{"koordinator.sh/gpu-core":"50","koordinator.sh/gpu-memory":"8Gi"}}]}`,
+			},
+		},
+		Spec: corev1.PodSpec{NodeName: "test-node"},
+	}
+	handler := makeTestHandler(allocatedPod)
+	koordlet := authenticationv1.UserInfo{Username: testKoordletUser}
+
+	shrunkGPU := makeGPU(1, 0, "pci0", "0000:00:09.0")
+	shrunkGPU.Resources[extension.ResourceGPUMemory] = resource.MustParse("4Gi")
+
+	testCases := []struct {
+		name     string
+		device   *schedulingv1alpha1.Device
+		userInfo authenticationv1.UserInfo
+		allowed  bool
+	}{
+		{
+			name:     "valid devices",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0"), makeGPU(1, 0, "pci0", "0000:00:09.0")),
+			userInfo: koordlet,
+			allowed:  true,
+		},
+		{
+			name:     "user not allowed",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0")),
+			userInfo: authenticationv1.UserInfo{Username: "system:serviceaccount:default:foo"},
+			allowed:  false,
+		},
+		{
+			name:     "masters allowed",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0")),
+			userInfo: authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters"}},
+			allowed:  true,
+		},
+		{
+			name:     "duplicate minors",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0"), makeGPU(0, 0, "pci0", "0000:00:09.0")),
+			userInfo: koordlet,
+			allowed:  false,
+		},
+		{
+			name:     "pcie in different numa nodes",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0"), makeGPU(1, 1, "pci0", "0000:00:09.0")),
+			userInfo: koordlet,
+			allowed:  false,
+		},
+		{
+			name:     "capacity less than allocated",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0"), shrunkGPU),
+			userInfo: koordlet,
+			allowed:  false,
+		},
+		{
+			name:     "allocated device removed",
+			device:   makeDevice(makeGPU(0, 0, "pci0", "0000:00:08.0")),
+			userInfo: koordlet,
+			allowed:  true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := handler.Handle(context.TODO(), makeDeviceRequest(t, tc.device, tc.userInfo))
+			assert.Equal(t, tc.allowed, resp.Allowed, resp.Result)
+		})
+	}
+}
+
+func TestValidateDevices(t *testing.T) {
+	socket1 := makeGPU(1, 0, "pci1", "0000:00:09.0")
+	socket1.Topology.SocketID = 1
+	unknownSocket := makeGPU(2, 0, "pci2", "0000:00:0a.0")
+	unknownSocket.Topology.SocketID = -1
+	rdma := schedulingv1alpha1.DeviceInfo{
+		Type:  schedulingv1alpha1.RDMA,
+		Minor: pointer.Int32(0),
+		VFGroups: []schedulingv1alpha1.VirtualFunctionGroup{
+			{VFs: []schedulingv1alpha1.VirtualFunction{{Minor: 0}, {Minor: 1}}},
+			{VFs: []schedulingv1alpha1.VirtualFunction{{Minor: 1}}},
+		},
+	}
+
+	// the numa node belongs to different sockets
+	errs := validateDevices([]schedulingv1alpha1.DeviceInfo{makeGPU(0, 0, "pci0", "0000:00:08.0"), socket1}, nil)
+	assert.Len(t, errs, 1)
+	// the unknown socket is not checked, and the minors are unique per device type
+	errs = validateDevices([]schedulingv1alpha1.DeviceInfo{makeGPU(0, 0, "pci0", "0000:00:08.0"), unknownSocket, rdma}, nil)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Field, "vfGroups[1].vfs[0].minor")
+}
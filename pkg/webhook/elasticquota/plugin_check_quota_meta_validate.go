@@ -24,6 +24,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	clientcache "k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -132,6 +133,19 @@ func (c *QuotaMetaChecker) GetQuotaInfo(name, namespace string) *QuotaInfo {
 	return c.QuotaTopo.getQuotaInfo(name, namespace)
 }
 
+// RebuildQuotaTopology rebuilds the quota topology from the cache immediately.
+func (c *QuotaMetaChecker) RebuildQuotaTopology() (*QuotaTopologyRebuildResult, error) {
+	if c.QuotaTopo == nil {
+		return nil, fmt.Errorf("quota topology is not initialized")
+	}
+	return c.QuotaTopo.Rebuild()
+}
+
+// RebuildQuotaTopology rebuilds the topology of the shared QuotaMetaChecker.
+func RebuildQuotaTopology() (*QuotaTopologyRebuildResult, error) {
+	return quotaMetaCheck.RebuildQuotaTopology()
+}
+
 func (c *QuotaMetaChecker) InjectInformer(elasticQuotaInformer cache.Informer) {
 	c.QuotaInformer = elasticQuotaInformer
 }
@@ -152,5 +166,9 @@ func NewQuotaInformer(cache cache.Cache, qt *quotaTopology) (cache.Informer, err
 		UpdateFunc: qt.OnQuotaUpdate,
 		DeleteFunc: qt.OnQuotaDelete,
 	})
-	return quotaInformer, err
+	if err != nil {
+		return nil, err
+	}
+	go qt.startPeriodicRebuild(quotaInformer.HasSynced, wait.NeverStop)
+	return quotaInformer, nil
 }
@@ -21,6 +21,8 @@ import (
 
 	"k8s.io/klog/v2"
 
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
 	"github.com/koordinator-sh/koordinator/apis/extension"
 )
 
@@ -34,10 +36,15 @@ func (qt *quotaTopology) OnQuotaAdd(obj interface{}) {
 		return
 	}
 
-	quotaInfo := NewQuotaInfoFromQuota(quota)
 	qt.lock.Lock()
 	defer qt.lock.Unlock()
 
+	qt.addQuotaNoLock(quota)
+	klog.V(5).Infof("OnQuotaAdd success: %v.%v", quota.Namespace, quota.Name)
+}
+
+func (qt *quotaTopology) addQuotaNoLock(quota *v1alpha1.ElasticQuota) {
+	quotaInfo := NewQuotaInfoFromQuota(quota)
 	qt.quotaInfoMap[quotaInfo.Name] = quotaInfo
 	if qt.quotaHierarchyInfo[quotaInfo.Name] == nil {
 		qt.quotaHierarchyInfo[quotaInfo.Name] = make(map[string]struct{})
@@ -51,8 +58,6 @@ func (qt *quotaTopology) OnQuotaAdd(obj interface{}) {
 	for _, ns := range namespaces {
 		qt.namespaceToQuotaMap[ns] = quota.Name
 	}
-
-	klog.V(5).Infof("OnQuotaAdd success: %v.%v", quota.Namespace, quota.Name)
 }
 
 func (qt *quotaTopology) OnQuotaUpdate(oldObj, newObj interface{}) {
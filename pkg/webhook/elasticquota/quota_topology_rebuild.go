@@ -0,0 +1,207 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientcache "k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	utilclient "github.com/koordinator-sh/koordinator/pkg/util/client"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/metrics"
+)
+
+// QuotaTopologyRebuildInterval is the interval to rebuild the quota topology from the informer cache.
+// The topology is maintained by the quota events, which can drift from the API server after the webhook restarts
+// or some events are missed. Set to zero to disable the periodic rebuild.
+var QuotaTopologyRebuildInterval = 5 * time.Minute
+
+func init() {
+	flag.DurationVar(&QuotaTopologyRebuildInterval, "quota-topology-rebuild-interval", QuotaTopologyRebuildInterval,
+		"The interval to rebuild the elastic quota topology of the webhook from the informer cache. Zero means disabled.")
+}
+
+// QuotaTopologyRebuildResult describes a rebuild of the quota topology.
+type QuotaTopologyRebuildResult struct {
+	// Drifted indicates whether the topology before the rebuild is inconsistent with the informer cache.
+	Drifted bool `json:"drifted"`
+	// DriftedQuotas are the quotas whose topology information was inconsistent.
+	DriftedQuotas []string `json:"driftedQuotas,omitempty"`
+	OldHash       string   `json:"oldHash"`
+	NewHash       string   `json:"newHash"`
+}
+
+func (qt *quotaTopology) startPeriodicRebuild(hasSynced clientcache.InformerSynced, stopCh <-chan struct{}) {
+	if QuotaTopologyRebuildInterval <= 0 {
+		return
+	}
+	if !clientcache.WaitForCacheSync(stopCh, hasSynced) {
+		klog.Errorf("failed to wait for elastic quota informer synced, skip rebuilding quota topology")
+		return
+	}
+	wait.Until(func() {
+		if _, err := qt.Rebuild(); err != nil {
+			klog.Errorf("failed to rebuild quota topology, err: %v", err)
+		}
+	}, QuotaTopologyRebuildInterval, stopCh)
+}
+
+// Rebuild lists all quotas from the cache and rebuilds the quota topology.
+// The rebuilt topology replaces the current one only if their integrity hashes are different.
+func (qt *quotaTopology) Rebuild() (*QuotaTopologyRebuildResult, error) {
+	if qt.client == nil {
+		return nil, fmt.Errorf("quota topology client is nil")
+	}
+	quotaList := &v1alpha1.ElasticQuotaList{}
+	if err := qt.client.List(context.TODO(), quotaList, utilclient.DisableDeepCopy); err != nil {
+		metrics.RecordQuotaTopologyRebuild(metrics.QuotaTopologyRebuildFailed, 0)
+		return nil, fmt.Errorf("failed to list quotas, err: %v", err)
+	}
+	quotas := make([]*v1alpha1.ElasticQuota, 0, len(quotaList.Items))
+	for i := range quotaList.Items {
+		quotas = append(quotas, &quotaList.Items[i])
+	}
+	result := qt.rebuildFromQuotas(quotas)
+	if result.Drifted {
+		metrics.RecordQuotaTopologyRebuild(metrics.QuotaTopologyRebuildDrifted, len(result.DriftedQuotas))
+		klog.Warningf("quota topology drifted from the cache and is rebuilt, drifted quotas %v, hash %s -> %s",
+			result.DriftedQuotas, result.OldHash, result.NewHash)
+	} else {
+		metrics.RecordQuotaTopologyRebuild(metrics.QuotaTopologyRebuildConsistent, 0)
+		klog.V(5).Infof("quota topology is consistent with the cache, hash %s", result.OldHash)
+	}
+	return result, nil
+}
+
+func (qt *quotaTopology) rebuildFromQuotas(quotas []*v1alpha1.ElasticQuota) *QuotaTopologyRebuildResult {
+	rebuilt := NewQuotaTopology(qt.client)
+	for _, quota := range quotas {
+		rebuilt.addQuotaNoLock(quota)
+	}
+	newHashes := rebuilt.quotaHashesNoLock()
+
+	qt.lock.Lock()
+	defer qt.lock.Unlock()
+
+	oldHashes := qt.quotaHashesNoLock()
+	result := &QuotaTopologyRebuildResult{
+		OldHash: integrityHash(oldHashes),
+		NewHash: integrityHash(newHashes),
+	}
+	if result.OldHash == result.NewHash {
+		return result
+	}
+
+	names := sets.NewString()
+	for name, hash := range oldHashes {
+		if newHashes[name] != hash {
+			names.Insert(name)
+		}
+	}
+	for name := range newHashes {
+		if _, ok := oldHashes[name]; !ok {
+			names.Insert(name)
+		}
+	}
+	result.Drifted = true
+	result.DriftedQuotas = names.List()
+
+	qt.quotaInfoMap = rebuilt.quotaInfoMap
+	qt.quotaHierarchyInfo = rebuilt.quotaHierarchyInfo
+	qt.namespaceToQuotaMap = rebuilt.namespaceToQuotaMap
+	return result
+}
+
+// quotaTopologyItem is the canonical form of a quota in the topology.
+type quotaTopologyItem struct {
+	Info       *QuotaInfo `json:"info,omitempty"`
+	Children   []string   `json:"children,omitempty"`
+	Namespaces []string   `json:"namespaces,omitempty"`
+}
+
+// quotaHashesNoLock returns the hash of each quota's topology information, including the quota info, the children
+// and the bound namespaces.
+func (qt *quotaTopology) quotaHashesNoLock() map[string]string {
+	items := map[string]*quotaTopologyItem{}
+	getItem := func(name string) *quotaTopologyItem {
+		item, ok := items[name]
+		if !ok {
+			item = &quotaTopologyItem{}
+			items[name] = item
+		}
+		return item
+	}
+	for name, info := range qt.quotaInfoMap {
+		getItem(name).Info = info
+	}
+	for name, children := range qt.quotaHierarchyInfo {
+		// skip the empty hierarchy placeholders, e.g. the root quota without children
+		if _, ok := qt.quotaInfoMap[name]; !ok && len(children) <= 0 {
+			continue
+		}
+		item := getItem(name)
+		for child := range children {
+			item.Children = append(item.Children, child)
+		}
+		sort.Strings(item.Children)
+	}
+	for namespace, name := range qt.namespaceToQuotaMap {
+		item := getItem(name)
+		item.Namespaces = append(item.Namespaces, namespace)
+	}
+
+	hashes := make(map[string]string, len(items))
+	for name, item := range items {
+		sort.Strings(item.Namespaces)
+		data, err := json.Marshal(item)
+		if err != nil {
+			klog.Errorf("failed to marshal quota topology item %s, err: %v", name, err)
+			continue
+		}
+		sum := sha256.Sum256(data)
+		hashes[name] = hex.EncodeToString(sum[:])
+	}
+	return hashes
+}
+
+func integrityHash(quotaHashes map[string]string) string {
+	names := make([]string, 0, len(quotaHashes))
+	for name := range quotaHashes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{':'})
+		h.Write([]byte(quotaHashes[name]))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package elasticquota
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/koordinator-sh/koordinator/apis/thirdparty/scheduler-plugins/pkg/apis/scheduling/v1alpha1"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestQuotaTopologyRebuild(t *testing.T) {
+	client := fake.NewClientBuilder().Build()
+	v1alpha1.AddToScheme(client.Scheme())
+	topology := NewQuotaTopology(client)
+
+	parentQuota := MakeQuota("parent-quota").Namespace("kube-system").Max(MakeResourceList().CPU(120).Mem(1048576).Obj()).
+		Min(MakeResourceList().CPU(120).Mem(1048576).Obj()).IsParent(true).Obj()
+	childQuota := MakeQuota("child-quota").Namespace("kube-system").Max(MakeResourceList().CPU(60).Mem(1048576).Obj()).
+		Min(MakeResourceList().CPU(60).Mem(1048576).Obj()).ParentName(parentQuota.Name).Annotations(
+		map[string]string{extension.AnnotationQuotaNamespaces: `["namespace1"]`},
+	).Obj()
+	for _, quota := range []*v1alpha1.ElasticQuota{parentQuota, childQuota} {
+		assert.NoError(t, client.Create(context.TODO(), quota))
+		topology.OnQuotaAdd(quota)
+	}
+
+	// consistent with the cache
+	result, err := topology.Rebuild()
+	assert.NoError(t, err)
+	assert.False(t, result.Drifted)
+	assert.Equal(t, result.OldHash, result.NewHash)
+	assert.Empty(t, result.DriftedQuotas)
+	hash := result.OldHash
+
+	// missed the delete event of the child quota and the add event of another quota
+	otherQuota := MakeQuota("other-quota").Namespace("kube-system").Max(MakeResourceList().CPU(10).Mem(1048576).Obj()).
+		Min(MakeResourceList().CPU(10).Mem(1048576).Obj()).Obj()
+	assert.NoError(t, client.Delete(context.TODO(), childQuota))
+	assert.NoError(t, client.Create(context.TODO(), otherQuota))
+	result, err = topology.Rebuild()
+	assert.NoError(t, err)
+	assert.True(t, result.Drifted)
+	assert.Equal(t, hash, result.OldHash)
+	assert.NotEqual(t, result.OldHash, result.NewHash)
+	assert.Equal(t, []string{childQuota.Name, extension.RootQuotaName, otherQuota.Name, parentQuota.Name}, result.DriftedQuotas)
+
+	assert.Nil(t, topology.getQuotaInfo(childQuota.Name, ""))
+	assert.Nil(t, topology.getQuotaInfo("", "namespace1"))
+	assert.NotNil(t, topology.getQuotaInfo(otherQuota.Name, ""))
+	assert.Equal(t, map[string]struct{}{}, topology.quotaHierarchyInfo[parentQuota.Name])
+	assert.Equal(t, map[string]struct{}{parentQuota.Name: {}, otherQuota.Name: {}}, topology.quotaHierarchyInfo[extension.RootQuotaName])
+
+	// rebuild again and keep consistent
+	result, err = topology.Rebuild()
+	assert.NoError(t, err)
+	assert.False(t, result.Drifted)
+
+	// missed the update event of the quota max
+	newOtherQuota := otherQuota.DeepCopy()
+	newOtherQuota.Spec.Max = MakeResourceList().CPU(20).Mem(1048576).Obj()
+	assert.NoError(t, client.Update(context.TODO(), newOtherQuota))
+	result, err = topology.Rebuild()
+	assert.NoError(t, err)
+	assert.True(t, result.Drifted)
+	assert.Equal(t, []string{otherQuota.Name}, result.DriftedQuotas)
+	assert.Equal(t, int64(20), topology.getQuotaInfo(otherQuota.Name, "").CalculateInfo.Max.Cpu().Value())
+}
+
+func TestQuotaTopologyRebuildWithoutClient(t *testing.T) {
+	topology := newFakeQuotaTopology()
+	result, err := topology.Rebuild()
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
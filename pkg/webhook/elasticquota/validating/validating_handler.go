@@ -137,3 +137,26 @@ func (h *ElasticQuotaValidatingHandler) ServeHTTP(w http.ResponseWriter, r *http
 	w.WriteHeader(200)
 	w.Write(allQuotaTopologySummaryJson)
 }
+
+// QuotaTopologyRebuildHandler forces a rebuild of the quota topology from the cache.
+type QuotaTopologyRebuildHandler struct{}
+
+var _ http.Handler = &QuotaTopologyRebuildHandler{}
+
+func (h *QuotaTopologyRebuildHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	result, err := elasticquota.RebuildQuotaTopology()
+	if err != nil {
+		klog.Errorf("failed to force rebuilding quota topology, err: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	resultJson, _ := json.Marshal(result)
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(resultJson)
+}
@@ -30,8 +30,25 @@ var (
 		},
 		[]string{ElasticQuotaNameKey, ResourceNameKey},
 	)
+	quotaTopologyRebuild = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Subsystem: KoordManagerWebhookSubsystem,
+			Name:      "quota_topology_rebuild_total",
+			Help:      "The number of the quota topology rebuilds by the result",
+		},
+		[]string{RebuildResultKey},
+	)
+	quotaTopologyDriftedQuotas = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Subsystem: KoordManagerWebhookSubsystem,
+			Name:      "quota_topology_drifted_quotas",
+			Help:      "The number of the quotas whose topology drifted from the cache in the last rebuild",
+		},
+	)
 	ElasticQuotaCollector = []prometheus.Collector{
 		quotaSharedWeight,
+		quotaTopologyRebuild,
+		quotaTopologyDriftedQuotas,
 	}
 )
 
@@ -40,3 +57,10 @@ func RecordQuotaSharedWeight(quotaName string, max v1.ResourceList) {
 		quotaSharedWeight.WithLabelValues(quotaName, string(k)).Set(float64(v.Value()))
 	}
 }
+
+func RecordQuotaTopologyRebuild(result string, driftedQuotas int) {
+	quotaTopologyRebuild.WithLabelValues(result).Inc()
+	if result != QuotaTopologyRebuildFailed {
+		quotaTopologyDriftedQuotas.Set(float64(driftedQuotas))
+	}
+}
@@ -30,7 +30,14 @@ const (
 	MutatingWebhook              = "mutate"
 	ValidatingWebhook            = "validate"
 	ConfigMap                    = "configmap"
+	Device                       = "device"
 	ElasticQuota                 = "elasticquota"
 	Node                         = "node"
+	NodeSLO                      = "nodeslo"
 	Pod                          = "pod"
+
+	RebuildResultKey               = "result"
+	QuotaTopologyRebuildConsistent = "consistent"
+	QuotaTopologyRebuildDrifted    = "drifted"
+	QuotaTopologyRebuildFailed     = "failed"
 )
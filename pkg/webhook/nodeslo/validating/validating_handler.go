@@ -0,0 +1,154 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	cmsloconfig "github.com/koordinator-sh/koordinator/pkg/webhook/cm/plugins/sloconfig"
+	"github.com/koordinator-sh/koordinator/pkg/webhook/metrics"
+)
+
+const validatorName = "NodeSLOValidator"
+
+// +kubebuilder:rbac:groups=slo.koordinator.sh,resources=nodeslos,verbs=get;list;watch
+// +kubebuilder:rbac:groups=topology.node.k8s.io,resources=noderesourcetopologies,verbs=get;list;watch
+
+type NodeSLOValidatingHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder *admission.Decoder
+}
+
+func NewNodeSLOValidatingHandler(c client.Client, d *admission.Decoder) *NodeSLOValidatingHandler {
+	handler := &NodeSLOValidatingHandler{
+		Client:  c,
+		Decoder: d,
+	}
+	return handler
+}
+
+var _ admission.Handler = &NodeSLOValidatingHandler{}
+
+func ShouldIgnoreIfNotNodeSLO(req admission.Request) bool {
+	// Ignore all calls to sub resources or resources other than nodeslos.
+	if len(req.AdmissionRequest.SubResource) != 0 ||
+		req.AdmissionRequest.Resource.Resource != "nodeslos" {
+		return true
+	}
+	return false
+}
+
+// Handle handles admission requests.
+func (h *NodeSLOValidatingHandler) Handle(ctx context.Context, req admission.Request) (resp admission.Response) {
+	klog.V(3).Infof("enter validating handler,type:%v,name:%v,user:%s", req.Kind, req.Name, req.UserInfo.Username)
+	if ShouldIgnoreIfNotNodeSLO(req) || req.Operation == admissionv1.Delete {
+		return admission.ValidationResponse(true, "")
+	}
+
+	obj := &slov1alpha1.NodeSLO{}
+	if err := h.Decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	defer func() {
+		if !resp.Allowed {
+			klog.Warningf("Webhook finish validating info nodeSLO %s, allowed: %v, result: %v",
+				obj.Name, resp.Allowed, util.DumpJSON(resp.Result))
+		}
+	}()
+
+	start := time.Now()
+	err := h.validate(ctx, obj)
+	metrics.RecordWebhookDurationMilliseconds(metrics.ValidatingWebhook,
+		metrics.NodeSLO, string(req.Operation), err, validatorName, time.Since(start).Seconds())
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+// validate checks the parameters of the NodeSLO in the same way as the slo-controller configmap,
+// and additionally checks the resctrl configs against the hardware info reported by the node.
+func (h *NodeSLOValidatingHandler) validate(ctx context.Context, nodeSLO *slov1alpha1.NodeSLO) error {
+	if err := cmsloconfig.CheckByValidator(&nodeSLO.Spec); err != nil {
+		return err
+	}
+
+	fldPath := field.NewPath("spec")
+	if err := cmsloconfig.CheckResourceThresholdStrategy(nodeSLO.Spec.ResourceUsedThresholdWithBE,
+		fldPath.Child("resourceUsedThresholdWithBE")); err != nil {
+		return err
+	}
+	if err := cmsloconfig.CheckResourceQOSStrategy(nodeSLO.Spec.ResourceQOSStrategy,
+		fldPath.Child("resourceQOSStrategy")); err != nil {
+		return err
+	}
+
+	if nodeSLO.Spec.ResourceQOSStrategy == nil {
+		return nil
+	}
+	cbm := h.getCatL3CbmMask(ctx, nodeSLO.Name)
+	return cmsloconfig.CheckResctrlQOSForCatL3Cbm(nodeSLO.Spec.ResourceQOSStrategy, cbm,
+		fldPath.Child("resourceQOSStrategy"))
+}
+
+// getCatL3CbmMask returns the l3 cbm of the node reported in the NodeResourceTopology,
+// or empty if it is not reported yet.
+func (h *NodeSLOValidatingHandler) getCatL3CbmMask(ctx context.Context, nodeName string) string {
+	nrt := &topologyv1alpha1.NodeResourceTopology{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: nodeName}, nrt); err != nil {
+		klog.V(5).Infof("failed to get NodeResourceTopology %s, skip checking resctrl for hardware, err: %v", nodeName, err)
+		return ""
+	}
+	basicInfo, err := extension.GetCPUBasicInfo(nrt.Annotations)
+	if err != nil || basicInfo == nil {
+		klog.V(5).Infof("failed to get cpu basic info of node %s, skip checking resctrl for hardware, err: %v", nodeName, err)
+		return ""
+	}
+	return basicInfo.CatL3CbmMask
+}
+
+// var _ inject.Client = &NodeSLOValidatingHandler{}
+
+// InjectClient injects the client into the ValidatingHandler
+func (h *NodeSLOValidatingHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+// var _ admission.DecoderInjector = &NodeSLOValidatingHandler{}
+
+// InjectDecoder injects the decoder into the ValidatingHandler
+func (h *NodeSLOValidatingHandler) InjectDecoder(d *admission.Decoder) error {
+	h.Decoder = d
+	return nil
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+)
+
+func makeTestHandler() *NodeSLOValidatingHandler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = slov1alpha1.AddToScheme(scheme)
+	_ = topologyv1alpha1.AddToScheme(scheme)
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&topologyv1alpha1.NodeResourceTopology{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Annotations: map[string]string{
+				// 4 cache ways
+				extension.AnnotationCPUBasicInfo: `{"catL3CbmMask":"f"}`,
+			},
+		},
+	}).Build()
+	return NewNodeSLOValidatingHandler(client, admission.NewDecoder(scheme))
+}
+
+func gvr(resource string) metav1.GroupVersionResource {
+	return metav1.GroupVersionResource{
+		Group:    slov1alpha1.GroupVersion.Group,
+		Version:  slov1alpha1.GroupVersion.Version,
+		Resource: resource,
+	}
+}
+
+func makeNodeSLORequest(t *testing.T, nodeSLO *slov1alpha1.NodeSLO) admission.Request {
+	raw, err := json.Marshal(nodeSLO)
+	assert.NoError(t, err)
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Resource:  gvr("nodeslos"),
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func TestNodeSLOValidatingHandler_Handle(t *testing.T) {
+	handler := makeTestHandler()
+	ctx := context.Background()
+
+	testCases := []struct {
+		name    string
+		request admission.Request
+		allowed bool
+		code    int32
+	}{
+		{
+			name: "not a nodeslo",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("nodemetrics"),
+					Operation: admissionv1.Create,
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "nodeslo with empty object",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Resource:  gvr("nodeslos"),
+					Operation: admissionv1.Create,
+					Object:    runtime.RawExtension{},
+				},
+			},
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name: "valid nodeslo",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+						CPUSuppressThresholdPercent: pointer.Int64(65),
+						CPUSuppressMinPercent:       pointer.Int64(30),
+					},
+					ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+						BEClass: &slov1alpha1.ResourceQOS{
+							ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+								ResctrlQOS: slov1alpha1.ResctrlQOS{
+									CATRangeStartPercent: pointer.Int64(0),
+									CATRangeEndPercent:   pointer.Int64(30),
+									MBAPercent:           pointer.Int64(50),
+								},
+							},
+						},
+					},
+				},
+			}),
+			allowed: true,
+		},
+		{
+			name: "percentage out of range",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+						CPUSuppressThresholdPercent: pointer.Int64(120),
+					},
+				},
+			}),
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name: "thresholds not ordered",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceUsedThresholdWithBE: &slov1alpha1.ResourceThresholdStrategy{
+						CPUSuppressThresholdPercent: pointer.Int64(30),
+						CPUSuppressMinPercent:       pointer.Int64(65),
+					},
+				},
+			}),
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name: "llc range owns no cache way of the node",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+						BEClass: &slov1alpha1.ResourceQOS{
+							ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+								ResctrlQOS: slov1alpha1.ResctrlQOS{
+									CATRangeStartPercent: pointer.Int64(10),
+									CATRangeEndPercent:   pointer.Int64(20),
+								},
+							},
+						},
+					},
+				},
+			}),
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+		{
+			name: "llc range not checked without hardware info",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "unknown-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+						BEClass: &slov1alpha1.ResourceQOS{
+							ResctrlQOS: &slov1alpha1.ResctrlQOSCfg{
+								ResctrlQOS: slov1alpha1.ResctrlQOS{
+									CATRangeStartPercent: pointer.Int64(10),
+									CATRangeEndPercent:   pointer.Int64(20),
+								},
+							},
+						},
+					},
+				},
+			}),
+			allowed: true,
+		},
+		{
+			name: "duplicated mba tiers",
+			request: makeNodeSLORequest(t, &slov1alpha1.NodeSLO{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: slov1alpha1.NodeSLOSpec{
+					ResourceQOSStrategy: &slov1alpha1.ResourceQOSStrategy{
+						ResctrlMBATiers: []slov1alpha1.ResctrlMBATier{
+							{Name: "gold", MBAPercent: pointer.Int64(80)},
+							{Name: "gold", MBAPercent: pointer.Int64(50)},
+						},
+					},
+				},
+			}),
+			allowed: false,
+			code:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := handler.Handle(ctx, tc.request)
+			assert.Equal(t, tc.allowed, resp.Allowed, resp.Result)
+			if !tc.allowed {
+				assert.Equal(t, tc.code, resp.Result.Code)
+			}
+		})
+	}
+}
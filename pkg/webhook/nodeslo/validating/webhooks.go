@@ -0,0 +1,55 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	topologyv1alpha1 "github.com/k8stopologyawareschedwg/noderesourcetopology-api/pkg/apis/topology/v1alpha1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/pkg/webhook/util/framework"
+)
+
+// +kubebuilder:webhook:path=/validate-nodeslo,mutating=false,failurePolicy=fail,sideEffects=None,groups=slo.koordinator.sh,resources=nodeslos,verbs=create;update,versions=v1alpha1,name=vnodeslo.koordinator.sh,admissionReviewVersions=v1;v1beta1
+
+var (
+	// HandlerBuilderMap contains admission webhook handlers builder
+	HandlerBuilderMap = map[string]framework.HandlerBuilder{
+		"validate-nodeslo": &nodeSLOValidateBuilder{},
+	}
+)
+
+var _ framework.HandlerBuilder = &nodeSLOValidateBuilder{}
+
+type nodeSLOValidateBuilder struct {
+	mgr manager.Manager
+}
+
+func (b *nodeSLOValidateBuilder) WithControllerManager(mgr ctrl.Manager) framework.HandlerBuilder {
+	b.mgr = mgr
+	return b
+}
+
+func (b *nodeSLOValidateBuilder) Build() admission.Handler {
+	// the NodeResourceTopology is required to get the cpu basic info reported by koordlet
+	if err := topologyv1alpha1.AddToScheme(b.mgr.GetScheme()); err != nil {
+		klog.Warningf("failed to add NodeResourceTopology to scheme, err: %v", err)
+	}
+	return NewNodeSLOValidatingHandler(b.mgr.GetClient(), admission.NewDecoder(b.mgr.GetScheme()))
+}
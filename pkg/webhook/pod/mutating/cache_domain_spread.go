@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"fmt"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	utilfeature "github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+// cacheDomainSpreadIgnoredLabels are the labels differing between the replicas of a workload, which are excluded
+// from the label selector of the injected constraints.
+var cacheDomainSpreadIgnoredLabels = []string{
+	appsv1.DefaultDeploymentUniqueLabelKey,
+	appsv1.ControllerRevisionHashLabelKey,
+	appsv1.StatefulSetPodNameLabel,
+	appsv1.PodIndexLabel,
+}
+
+// cacheDomainSpreadMutatingPod injects the topology spread constraints over the cache domain labels of the nodes
+// for the pods opting in, so that the replicas of a workload are spread across the L3 cache domains.
+func (h *PodMutatingHandler) cacheDomainSpreadMutatingPod(ctx context.Context, req admission.Request, pod *corev1.Pod) error {
+	if req.Operation != admissionv1.Create {
+		return nil
+	}
+
+	if !utilfeature.DefaultFeatureGate.Enabled(features.CacheDomainTopologySpread) {
+		return nil
+	}
+
+	spec, err := extension.GetCacheDomainSpreadSpec(pod.Annotations)
+	if err != nil {
+		return fmt.Errorf("invalid annotation %s, err: %w", extension.AnnotationCacheDomainSpread, err)
+	}
+	if spec == nil {
+		return nil
+	}
+
+	matchLabels := make(map[string]string, len(pod.Labels))
+	for k, v := range pod.Labels {
+		matchLabels[k] = v
+	}
+	for _, k := range cacheDomainSpreadIgnoredLabels {
+		delete(matchLabels, k)
+	}
+	if len(matchLabels) == 0 {
+		klog.V(4).Infof("skip injecting cache domain spread for pod %s/%s since it has no labels to select the replicas",
+			pod.Namespace, pod.Name)
+		return nil
+	}
+
+	for _, topologyKey := range spec.TopologyKeys {
+		if hasTopologySpreadConstraint(pod, topologyKey) {
+			continue
+		}
+		pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           spec.MaxSkew,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: spec.WhenUnsatisfiable,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: matchLabels,
+			},
+		})
+	}
+	return nil
+}
+
+func hasTopologySpreadConstraint(pod *corev1.Pod, topologyKey string) bool {
+	for _, constraint := range pod.Spec.TopologySpreadConstraints {
+		if constraint.TopologyKey == topologyKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/features"
+	"github.com/koordinator-sh/koordinator/pkg/util/feature"
+)
+
+func TestCacheDomainSpreadMutatingPod(t *testing.T) {
+	defer feature.SetFeatureGateDuringTest(t, feature.DefaultMutableFeatureGate, features.CacheDomainTopologySpread, true)()
+
+	existingConstraint := corev1.TopologySpreadConstraint{
+		MaxSkew:           2,
+		TopologyKey:       extension.LabelNodeNUMADomain,
+		WhenUnsatisfiable: corev1.DoNotSchedule,
+	}
+	tests := []struct {
+		name            string
+		operation       admissionv1.Operation
+		annotations     map[string]string
+		labels          map[string]string
+		constraints     []corev1.TopologySpreadConstraint
+		wantConstraints []corev1.TopologySpreadConstraint
+		wantErr         bool
+	}{
+		{
+			name:      "pod not opting in",
+			operation: admissionv1.Create,
+			labels:    map[string]string{"app": "test"},
+		},
+		{
+			name:        "skip update",
+			operation:   admissionv1.Update,
+			annotations: map[string]string{extension.AnnotationCacheDomainSpread: "{}"},
+			labels:      map[string]string{"app": "test"},
+		},
+		{
+			name:        "inject default constraint",
+			operation:   admissionv1.Create,
+			annotations: map[string]string{extension.AnnotationCacheDomainSpread: "{}"},
+			labels:      map[string]string{"app": "test", "pod-template-hash": "abcde"},
+			wantConstraints: []corev1.TopologySpreadConstraint{
+				{
+					MaxSkew:           1,
+					TopologyKey:       extension.LabelNodeCacheDomain,
+					WhenUnsatisfiable: corev1.ScheduleAnyway,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				},
+			},
+		},
+		{
+			name:      "inject constraints with custom spec",
+			operation: admissionv1.Create,
+			annotations: map[string]string{
+				extension.AnnotationCacheDomainSpread: `{"topologyKeys":["node.koordinator.sh/cache-domain","node.koordinator.sh/numa-domain"],"maxSkew":2,"whenUnsatisfiable":"DoNotSchedule"}`,
+			},
+			labels:      map[string]string{"app": "test"},
+			constraints: []corev1.TopologySpreadConstraint{existingConstraint},
+			wantConstraints: []corev1.TopologySpreadConstraint{
+				existingConstraint,
+				{
+					MaxSkew:           2,
+					TopologyKey:       extension.LabelNodeCacheDomain,
+					WhenUnsatisfiable: corev1.DoNotSchedule,
+					LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				},
+			},
+		},
+		{
+			name:        "skip pod without labels",
+			operation:   admissionv1.Create,
+			annotations: map[string]string{extension.AnnotationCacheDomainSpread: "{}"},
+			labels:      map[string]string{"pod-template-hash": "abcde"},
+		},
+		{
+			name:        "invalid annotation",
+			operation:   admissionv1.Create,
+			annotations: map[string]string{extension.AnnotationCacheDomainSpread: "invalid"},
+			labels:      map[string]string{"app": "test"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &PodMutatingHandler{
+				Client:  fake.NewClientBuilder().Build(),
+				Decoder: admission.NewDecoder(scheme.Scheme),
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   "default",
+					Name:        "test-pod",
+					Labels:      tt.labels,
+					Annotations: tt.annotations,
+				},
+				Spec: corev1.PodSpec{
+					TopologySpreadConstraints: tt.constraints,
+				},
+			}
+			req := newAdmission(tt.operation, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			err := handler.cacheDomainSpreadMutatingPod(context.TODO(), req, pod)
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantConstraints, pod.Spec.TopologySpreadConstraints)
+		})
+	}
+}
@@ -86,6 +86,7 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 		return nil
 	}
 	skipUpdateResourceFromProfile := false
+	applyCPUAllocationHint := false
 	for _, profile := range matchedProfiles {
 		if extension.ShouldSkipUpdateResource(profile) {
 			skipUpdateResourceFromProfile = true
@@ -103,6 +104,12 @@ func (h *PodMutatingHandler) clusterColocationProfileMutatingPod(ctx context.Con
 			return err
 		}
 		klog.V(4).Infof("mutate Pod %s/%s by clusterColocationProfile %s", pod.Namespace, pod.Name, profile.Name)
+		if extension.ShouldApplyCPUAllocationHint(profile) {
+			applyCPUAllocationHint = true
+		}
+	}
+	if applyCPUAllocationHint {
+		h.cpuAllocationHintMutatingPod(ctx, pod)
 	}
 	if skipUpdateResourceFromProfile || utilfeature.DefaultFeatureGate.Enabled(features.ColocationProfileSkipMutatingResources) {
 		return nil
@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	"github.com/koordinator-sh/koordinator/apis/extension"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+)
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch
+
+// cpuAllocationHintMutatingPod mutates the LS pod to LSR with the exclusive cpus if it is recommended by the CPU
+// allocation hint of its workload. Only the single-container pods are mutated since the exclusive cpus cannot be
+// divided among the containers without the knowledge of the workload.
+// The pod is kept unchanged if the hint is unavailable, so the pod creation is never blocked by the hint.
+// The original cpu request is recorded on the mutated pod, so the learner compares its usages with the request of the
+// workload instead of the pinned exclusive cpus.
+func (h *PodMutatingHandler) cpuAllocationHintMutatingPod(ctx context.Context, pod *corev1.Pod) {
+	if extension.GetPodQoSClassRaw(pod) != extension.QoSLS || len(pod.Spec.Containers) != 1 {
+		return
+	}
+	kind := util.GetPodWorkloadKind(pod)
+	if kind != "Deployment" && kind != "StatefulSet" {
+		return
+	}
+	workload := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "apps/v1",
+			Kind:       kind,
+		},
+	}
+	name := util.GetPodWorkloadName(pod)
+	if err := h.Client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: name}, workload); err != nil {
+		klog.V(4).Infof("failed to get workload %s/%s/%s of pod %s/%s, err: %v", pod.Namespace, kind, name, pod.Namespace, pod.Name, err)
+		return
+	}
+	hint, err := extension.GetCPUAllocationHint(workload.Annotations)
+	if err != nil {
+		klog.V(4).Infof("failed to parse CPU allocation hint of workload %s/%s/%s, err: %v", pod.Namespace, kind, name, err)
+		return
+	}
+	if hint == nil || hint.QoSClass != extension.QoSLSR || hint.CPUs <= 0 {
+		return
+	}
+
+	cpus := *resource.NewQuantity(hint.CPUs, resource.DecimalSI)
+	container := &pod.Spec.Containers[0]
+	if container.Resources.Requests == nil {
+		container.Resources.Requests = corev1.ResourceList{}
+	}
+	if originalRequest, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[extension.AnnotationCPUAllocationHintOriginalCPURequest] = originalRequest.String()
+	}
+	if container.Resources.Limits == nil {
+		container.Resources.Limits = corev1.ResourceList{}
+	}
+	container.Resources.Requests[corev1.ResourceCPU] = cpus
+	container.Resources.Limits[corev1.ResourceCPU] = cpus
+	if pod.Labels == nil {
+		pod.Labels = map[string]string{}
+	}
+	pod.Labels[extension.LabelPodQoS] = string(extension.QoSLSR)
+	klog.V(4).Infof("mutate Pod %s/%s to LSR with %d cpus by CPU allocation hint of workload %s/%s/%s",
+		pod.Namespace, pod.Name, hint.CPUs, pod.Namespace, kind, name)
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1alpha1 "github.com/koordinator-sh/koordinator/apis/config/v1alpha1"
+	"github.com/koordinator-sh/koordinator/apis/extension"
+)
+
+func TestCPUAllocationHintMutatingPod(t *testing.T) {
+	newPod := func(containers int) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "nginx-5d8f7c-abcde",
+				Labels: map[string]string{
+					"app":                                  "nginx",
+					appsv1.DefaultDeploymentUniqueLabelKey: "5d8f7c",
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "nginx-5d8f7c", Controller: pointer.Bool(true)},
+				},
+			},
+		}
+		for i := 0; i < containers; i++ {
+			pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1500m")},
+				},
+			})
+		}
+		return pod
+	}
+	newDeployment := func(hint *extension.CPUAllocationHint) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+		}
+		assert.NoError(t, extension.SetCPUAllocationHint(deployment, hint))
+		return deployment
+	}
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		deployment *appsv1.Deployment
+		applyHint  bool
+		wantQoS    extension.QoSClass
+		wantCPU    string
+	}{
+		{
+			name:       "mutate to LSR by the hint",
+			pod:        newPod(1),
+			deployment: newDeployment(&extension.CPUAllocationHint{QoSClass: extension.QoSLSR, CPUs: 2}),
+			applyHint:  true,
+			wantQoS:    extension.QoSLSR,
+			wantCPU:    "2",
+		},
+		{
+			name:       "profile not opt in",
+			pod:        newPod(1),
+			deployment: newDeployment(&extension.CPUAllocationHint{QoSClass: extension.QoSLSR, CPUs: 2}),
+			wantQoS:    extension.QoSLS,
+			wantCPU:    "1500m",
+		},
+		{
+			name:       "hint to stay LS",
+			pod:        newPod(1),
+			deployment: newDeployment(&extension.CPUAllocationHint{QoSClass: extension.QoSLS}),
+			applyHint:  true,
+			wantQoS:    extension.QoSLS,
+			wantCPU:    "1500m",
+		},
+		{
+			name:       "skip multi-container pod",
+			pod:        newPod(2),
+			deployment: newDeployment(&extension.CPUAllocationHint{QoSClass: extension.QoSLSR, CPUs: 2}),
+			applyHint:  true,
+			wantQoS:    extension.QoSLS,
+			wantCPU:    "1500m",
+		},
+		{
+			name:      "workload not found",
+			pod:       newPod(1),
+			applyHint: true,
+			wantQoS:   extension.QoSLS,
+			wantCPU:   "1500m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := &configv1alpha1.ClusterColocationProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-profile"},
+				Spec: configv1alpha1.ClusterColocationProfileSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "nginx"}},
+					QoSClass: string(extension.QoSLS),
+				},
+			}
+			if tt.applyHint {
+				profile.Annotations = map[string]string{extension.AnnotationApplyCPUAllocationHint: "true"}
+			}
+			builder := fake.NewClientBuilder().WithObjects(profile)
+			if tt.deployment != nil {
+				builder.WithObjects(tt.deployment)
+			}
+			handler := &PodMutatingHandler{
+				Client:  builder.Build(),
+				Decoder: admission.NewDecoder(scheme.Scheme),
+			}
+
+			req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			err := handler.clusterColocationProfileMutatingPod(context.TODO(), req, tt.pod)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantQoS, extension.GetPodQoSClassRaw(tt.pod))
+			wantCPU := resource.MustParse(tt.wantCPU)
+			assert.True(t, wantCPU.Equal(tt.pod.Spec.Containers[0].Resources.Requests[corev1.ResourceCPU]))
+			originalRequest, mutated := extension.GetCPUAllocationHintOriginalCPURequest(tt.pod.Annotations)
+			assert.Equal(t, tt.wantQoS == extension.QoSLSR, mutated)
+			if mutated {
+				assert.Equal(t, "1500m", originalRequest.String())
+			}
+		})
+	}
+}
@@ -42,23 +42,37 @@ func (h *PodMutatingHandler) mutateByExtendedResources(pod *corev1.Pod) error {
 	extendedResourceSpec := &extension.ExtendedResourceSpec{}
 	containersSpec := map[string]extension.ExtendedResourceContainerSpec{}
 
-	// TODO: count init containers and pod overhead
+	initContainersSpec := map[string]extension.ExtendedResourceContainerSpec{}
+	resourceNames := []corev1.ResourceName{
+		extension.BatchCPU,
+		extension.BatchMemory,
+	}
+
+	// TODO: count pod overhead
 	for i := range pod.Spec.Containers {
 		container := &pod.Spec.Containers[i]
-		r := getContainerExtendedResourcesRequirement(container, []corev1.ResourceName{
-			extension.BatchCPU,
-			extension.BatchMemory,
-		})
+		r := getContainerExtendedResourcesRequirement(container, resourceNames)
 		if r == nil {
 			continue
 		}
 		containersSpec[container.Name] = *r
 	}
+	for i := range pod.Spec.InitContainers {
+		container := &pod.Spec.InitContainers[i]
+		r := getContainerExtendedResourcesRequirement(container, resourceNames)
+		if r == nil {
+			continue
+		}
+		initContainersSpec[container.Name] = *r
+	}
 
 	// no requirement of specified extended resources
 	if len(containersSpec) > 0 {
 		extendedResourceSpec.Containers = containersSpec
 	}
+	if len(initContainersSpec) > 0 {
+		extendedResourceSpec.InitContainers = initContainersSpec
+	}
 
 	// compare annotation values
 	spec, err := extension.GetExtendedResourceSpec(pod.Annotations)
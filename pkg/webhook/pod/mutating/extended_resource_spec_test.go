@@ -55,6 +55,24 @@ func TestExtendedResourceSpecMutatingPod(t *testing.T) {
 			},
 		},
 		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name: "test-init-container-a",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							extension.BatchCPU:    resource.MustParse("2000"),
+							extension.BatchMemory: resource.MustParse("1Gi"),
+						},
+						Requests: corev1.ResourceList{
+							extension.BatchCPU:    resource.MustParse("2000"),
+							extension.BatchMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				{
+					Name: "test-init-container-b",
+				},
+			},
 			Containers: []corev1.Container{
 				{
 					Name: "test-container-a",
@@ -91,6 +109,18 @@ func TestExtendedResourceSpecMutatingPod(t *testing.T) {
 				},
 			},
 		},
+		InitContainers: map[string]extension.ExtendedResourceContainerSpec{
+			"test-init-container-a": {
+				Limits: corev1.ResourceList{
+					extension.BatchCPU:    resource.MustParse("2000"),
+					extension.BatchMemory: resource.MustParse("1Gi"),
+				},
+				Requests: corev1.ResourceList{
+					extension.BatchCPU:    resource.MustParse("2000"),
+					extension.BatchMemory: resource.MustParse("1Gi"),
+				},
+			},
+		},
 	}
 	testExtendedResourceSpecBytes, err := json.Marshal(testExtendedResourceSpec)
 	assert.NoError(err)
@@ -113,6 +143,24 @@ func TestExtendedResourceSpecMutatingPod(t *testing.T) {
 			},
 		},
 		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name: "test-init-container-a",
+					Resources: corev1.ResourceRequirements{
+						Limits: corev1.ResourceList{
+							extension.BatchCPU:    *resource.NewQuantity(2000, resource.DecimalSI),
+							extension.BatchMemory: resource.MustParse("1Gi"),
+						},
+						Requests: corev1.ResourceList{
+							extension.BatchCPU:    *resource.NewQuantity(2000, resource.DecimalSI),
+							extension.BatchMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+				{
+					Name: "test-init-container-b",
+				},
+			},
 			Containers: []corev1.Container{
 				{
 					Name: "test-container-a",
@@ -37,6 +37,7 @@ const (
 	ExtendedResourceSpec     = "ExtendedResourceSpec"
 	MultiQuotaTree           = "MultiQuotaTree"
 	DeviceResourceSpec       = "DeviceResourceSpec"
+	CacheDomainSpread        = "CacheDomainSpread"
 )
 
 // PodMutatingHandler handles Pod
@@ -151,6 +152,16 @@ func (h *PodMutatingHandler) handleCreate(ctx context.Context, req admission.Req
 	metrics.RecordWebhookDurationMilliseconds(metrics.MutatingWebhook,
 		metrics.Pod, string(req.Operation), nil, DeviceResourceSpec, time.Since(start).Seconds())
 
+	start = time.Now()
+	if err := h.cacheDomainSpreadMutatingPod(ctx, req, obj); err != nil {
+		klog.Errorf("Failed to mutating Pod %s/%s by CacheDomainSpread, err: %v", obj.Namespace, obj.Name, err)
+		metrics.RecordWebhookDurationMilliseconds(metrics.MutatingWebhook,
+			metrics.Pod, string(req.Operation), err, CacheDomainSpread, time.Since(start).Seconds())
+		return err
+	}
+	metrics.RecordWebhookDurationMilliseconds(metrics.MutatingWebhook,
+		metrics.Pod, string(req.Operation), nil, CacheDomainSpread, time.Since(start).Seconds())
+
 	return nil
 }
 
@@ -37,6 +37,7 @@ const (
 	ClusterColocationProfile = "ClusterColocationProfile"
 	EvaluateQuota            = "EvaluateQuota"
 	DeviceResource           = "DeviceResource"
+	CPUBurst                 = "CPUBurst"
 )
 
 // PodValidatingHandler handles Pod
@@ -118,6 +119,14 @@ func (h *PodValidatingHandler) validatingPodFn(ctx context.Context, req admissio
 		return false, reason, err
 	}
 
+	start = time.Now()
+	allowed, reason, err = h.cpuBurstValidatingPod(ctx, req)
+	metrics.RecordWebhookDurationMilliseconds(metrics.ValidatingWebhook,
+		metrics.Pod, string(req.Operation), err, CPUBurst, time.Since(start).Seconds())
+	if err != nil {
+		return false, reason, err
+	}
+
 	return
 }
 
@@ -0,0 +1,136 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+
+// cpuBurstValidatingPod forbids the pods to enable cpu burst by annotation if their workloads are denied by the
+// workload policy of the cluster cpu burst strategy, which is also enforced by the koordlet.
+// The update which keeps the cpu burst annotation unchanged is allowed, so the pods admitted before the policy
+// changes are not blocked from the other updates.
+func (h *PodValidatingHandler) cpuBurstValidatingPod(ctx context.Context, req admission.Request) (bool, string, error) {
+	pod := &corev1.Pod{}
+	switch req.Operation {
+	case admissionv1.Create:
+		if err := h.Decoder.DecodeRaw(req.Object, pod); err != nil {
+			return false, "", err
+		}
+	case admissionv1.Update:
+		oldPod := &corev1.Pod{}
+		if err := h.Decoder.DecodeRaw(req.OldObject, oldPod); err != nil {
+			return false, "", err
+		}
+		if err := h.Decoder.DecodeRaw(req.Object, pod); err != nil {
+			return false, "", err
+		}
+		if oldPod.Annotations[slov1alpha1.AnnotationPodCPUBurst] == pod.Annotations[slov1alpha1.AnnotationPodCPUBurst] {
+			return true, "", nil
+		}
+	default:
+		return true, "", nil
+	}
+
+	burstCfg, err := slov1alpha1.GetPodCPUBurstConfig(pod)
+	if err != nil || burstCfg == nil || burstCfg.Policy == "" || burstCfg.Policy == slov1alpha1.CPUBurstNone {
+		return true, "", nil
+	}
+	policy := h.getCPUBurstWorkloadPolicy(ctx)
+	if policy == nil {
+		return true, "", nil
+	}
+	kind := h.getPodWorkloadKind(ctx, pod)
+	if util.IsPodCPUBurstAllowedByWorkloadKind(pod, kind, policy) {
+		return true, "", nil
+	}
+
+	allErrs := field.ErrorList{
+		field.Forbidden(field.NewPath("annotations", slov1alpha1.AnnotationPodCPUBurst),
+			"cpu burst is denied for the workload kind "+kind),
+	}
+	err = allErrs.ToAggregate()
+	return false, err.Error(), err
+}
+
+// getPodWorkloadKind returns the workload kind of the pod, where the CronJob of a Job is resolved by the
+// ownerReferences of the Job. It returns the kind of the pod owner if the Job cannot be got.
+func (h *PodValidatingHandler) getPodWorkloadKind(ctx context.Context, pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "Job" {
+		return util.GetPodWorkloadKind(pod)
+	}
+	job := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+		},
+	}
+	if err := h.Client.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, job); err != nil {
+		klog.V(4).Infof("failed to get job %s/%s of pod %s/%s, err: %v", pod.Namespace, owner.Name, pod.Namespace, pod.Name, err)
+		return owner.Kind
+	}
+	if jobOwner := metav1.GetControllerOf(job); jobOwner != nil && jobOwner.Kind == "CronJob" {
+		return jobOwner.Kind
+	}
+	return owner.Kind
+}
+
+func (h *PodValidatingHandler) getCPUBurstWorkloadPolicy(ctx context.Context) *slov1alpha1.CPUBurstWorkloadPolicy {
+	configMap := &corev1.ConfigMap{}
+	err := h.Client.Get(ctx, types.NamespacedName{
+		Namespace: sloconfig.ConfigNameSpace,
+		Name:      sloconfig.SLOCtrlConfigMap,
+	}, configMap)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("failed to get %s config, err: %v", sloconfig.SLOCtrlConfigMap, err)
+		}
+		return nil
+	}
+	cfgStr, ok := configMap.Data[configuration.CPUBurstConfigKey]
+	if !ok {
+		return nil
+	}
+	cfg := &configuration.CPUBurstCfg{}
+	if err = json.Unmarshal([]byte(cfgStr), cfg); err != nil {
+		klog.Errorf("failed to unmarshal %s config, err: %v", configuration.CPUBurstConfigKey, err)
+		return nil
+	}
+	// the node of the pod is unknown at the admission, so only the cluster strategy takes effect
+	if cfg.ClusterStrategy == nil {
+		return nil
+	}
+	return cfg.ClusterStrategy.WorkloadPolicy
+}
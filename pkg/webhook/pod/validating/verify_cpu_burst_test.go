@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Koordinator Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/koordinator-sh/koordinator/apis/configuration"
+	slov1alpha1 "github.com/koordinator-sh/koordinator/apis/slo/v1alpha1"
+	"github.com/koordinator-sh/koordinator/pkg/util"
+	"github.com/koordinator-sh/koordinator/pkg/util/sloconfig"
+)
+
+func TestCPUBurstValidatingPod(t *testing.T) {
+	denyCronJobConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sloconfig.ConfigNameSpace,
+			Name:      sloconfig.SLOCtrlConfigMap,
+		},
+		Data: map[string]string{
+			configuration.CPUBurstConfigKey: `{"clusterStrategy":{"policy":"auto","workloadPolicy":{"deniedKinds":["CronJob"]}}}`,
+		},
+	}
+	cronJobJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-cronjob-28291740",
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "batch/v1", Kind: "CronJob", Name: "test-cronjob", Controller: pointer.Bool(true)},
+			},
+		},
+	}
+	// the job is not created by a CronJob although its name looks like
+	plainJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-job-28291740",
+		},
+	}
+	newPod := func(ownerKind, ownerName, burstAnnotation string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "test-pod",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: ownerKind, Name: ownerName, Controller: pointer.Bool(true)},
+				},
+			},
+		}
+		if burstAnnotation != "" {
+			pod.Annotations = map[string]string{slov1alpha1.AnnotationPodCPUBurst: burstAnnotation}
+		}
+		return pod
+	}
+	tests := []struct {
+		name        string
+		configMap   *corev1.ConfigMap
+		operation   admissionv1.Operation
+		oldPod      *corev1.Pod
+		pod         *corev1.Pod
+		wantAllowed bool
+		wantErr     bool
+	}{
+		{
+			name:        "no config",
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "test-cronjob-28291740", `{"policy":"auto"}`),
+			wantAllowed: true,
+		},
+		{
+			name:        "pod without burst annotation",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "test-cronjob-28291740", ""),
+			wantAllowed: true,
+		},
+		{
+			name:        "pod disables burst",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "test-cronjob-28291740", `{"policy":"none"}`),
+			wantAllowed: true,
+		},
+		{
+			name:        "allowed workload",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("StatefulSet", "test-sts", `{"policy":"auto"}`),
+			wantAllowed: true,
+		},
+		{
+			name:        "denied workload",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "test-cronjob-28291740", `{"policy":"cpuBurstOnly"}`),
+			wantAllowed: false,
+			wantErr:     true,
+		},
+		{
+			name:        "job not owned by cronjob",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "test-job-28291740", `{"policy":"auto"}`),
+			wantAllowed: true,
+		},
+		{
+			name:        "job not found",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Create,
+			pod:         newPod("Job", "unknown-job-28291740", `{"policy":"auto"}`),
+			wantAllowed: true,
+		},
+		{
+			name:        "denied workload on update",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Update,
+			oldPod:      newPod("Job", "test-cronjob-28291740", ""),
+			pod:         newPod("Job", "test-cronjob-28291740", `{"policy":"auto"}`),
+			wantAllowed: false,
+			wantErr:     true,
+		},
+		{
+			name:        "update keeps the burst annotation",
+			configMap:   denyCronJobConfigMap,
+			operation:   admissionv1.Update,
+			oldPod:      newPod("Job", "test-cronjob-28291740", `{"policy":"auto"}`),
+			pod:         newPod("Job", "test-cronjob-28291740", `{"policy":"auto"}`),
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithObjects(cronJobJob, plainJob)
+			if tt.configMap != nil {
+				builder = builder.WithObjects(tt.configMap)
+			}
+			h := &PodValidatingHandler{
+				Client:  builder.Build(),
+				Decoder: admission.NewDecoder(scheme.Scheme),
+			}
+			objRawExt := runtime.RawExtension{
+				Raw: []byte(util.DumpJSON(tt.pod)),
+			}
+			oldObjRawExt := objRawExt
+			if tt.oldPod != nil {
+				oldObjRawExt = runtime.RawExtension{
+					Raw: []byte(util.DumpJSON(tt.oldPod)),
+				}
+			}
+			req := newAdmissionRequest(tt.operation, objRawExt, oldObjRawExt, "pods")
+			gotAllowed, _, err := h.cpuBurstValidatingPod(context.TODO(), admission.Request{AdmissionRequest: req})
+			assert.Equal(t, tt.wantErr, err != nil, err)
+			assert.Equal(t, tt.wantAllowed, gotAllowed)
+		})
+	}
+}
@@ -19,7 +19,9 @@ package util
 import (
 	"os"
 	"strconv"
+	"strings"
 
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
 	"k8s.io/klog/v2"
 )
 
@@ -84,3 +86,16 @@ func GetCertDir() string {
 func GetCertWriter() string {
 	return os.Getenv("WEBHOOK_CERT_WRITER")
 }
+
+// GetDeviceWriters returns the users allowed to create or update the Device objects, which are the service accounts of
+// koordlet by default. The users are separated by commas in the env, e.g. system:serviceaccount:kube-system:koordlet.
+func GetDeviceWriters() []string {
+	if writers := os.Getenv("DEVICE_WRITERS"); len(writers) > 0 {
+		return strings.Split(writers, ",")
+	}
+	ns := GetNamespace()
+	return []string{
+		serviceaccount.MakeUsername(ns, "koordlet"),
+		serviceaccount.MakeUsername(ns, "koord-koordlet"),
+	}
+}